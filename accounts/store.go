@@ -0,0 +1,130 @@
+package accounts
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+var (
+	// invoiceAccountBucket is a root-level bucket that attributes invoices
+	// to accounts. The keys are the payment hashes of the invoices and the
+	// values are the names of the accounts.
+	invoiceAccountBucket = []byte("invoice-account-bucket")
+
+	// paymentAccountBucket is a root-level bucket that attributes
+	// payments to accounts. The keys are the payment hashes of the
+	// payments and the values are the names of the accounts.
+	paymentAccountBucket = []byte("payment-account-bucket")
+
+	// invoiceCreatorBucket is a root-level bucket that attributes invoices
+	// to the macaroons that created them. The keys are the payment hashes
+	// of the invoices and the values are the root key IDs of the
+	// macaroons.
+	invoiceCreatorBucket = []byte("invoice-creator-bucket")
+)
+
+// Store attributes invoices and payments to the wallet accounts that created
+// them. Invoices and payments are attributed separately, as paying an invoice
+// of the node itself gives both the same payment hash. Invoices are also
+// attributed to the root key ID of the macaroon that created them, whether or
+// not the macaroon is bound to an account.
+type Store struct {
+	backend kvdb.Backend
+}
+
+// NewStore creates a new attribution store on top of the given database
+// backend.
+func NewStore(db kvdb.Backend) (*Store, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(invoiceAccountBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(paymentAccountBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(invoiceCreatorBucket)
+
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{backend: db}, nil
+}
+
+// AttributeInvoice attributes the invoice with the given payment hash to the
+// given account.
+func (s *Store) AttributeInvoice(hash lntypes.Hash, account string) error {
+	return s.attribute(invoiceAccountBucket, hash, account)
+}
+
+// InvoiceAccount returns the account the invoice with the given payment hash
+// is attributed to, or an empty string if it isn't attributed to any.
+func (s *Store) InvoiceAccount(hash lntypes.Hash) (string, error) {
+	return s.account(invoiceAccountBucket, hash)
+}
+
+// AttributePayment attributes the payment with the given payment hash to the
+// given account.
+func (s *Store) AttributePayment(hash lntypes.Hash, account string) error {
+	return s.attribute(paymentAccountBucket, hash, account)
+}
+
+// PaymentAccount returns the account the payment with the given payment hash
+// is attributed to, or an empty string if it isn't attributed to any.
+func (s *Store) PaymentAccount(hash lntypes.Hash) (string, error) {
+	return s.account(paymentAccountBucket, hash)
+}
+
+// AttributeInvoiceCreator attributes the invoice with the given payment hash
+// to the macaroon root key ID that created it.
+func (s *Store) AttributeInvoiceCreator(hash lntypes.Hash,
+	rootKeyID string) error {
+
+	return s.attribute(invoiceCreatorBucket, hash, rootKeyID)
+}
+
+// InvoiceCreator returns the macaroon root key ID that created the invoice
+// with the given payment hash, or an empty string if it isn't attributed to
+// any.
+func (s *Store) InvoiceCreator(hash lntypes.Hash) (string, error) {
+	return s.account(invoiceCreatorBucket, hash)
+}
+
+// attribute stores the account of the given hash in the given bucket.
+func (s *Store) attribute(bucket []byte, hash lntypes.Hash,
+	account string) error {
+
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		b := tx.ReadWriteBucket(bucket)
+		if b == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		return b.Put(hash[:], []byte(account))
+	}, func() {})
+}
+
+// account fetches the account of the given hash from the given bucket.
+func (s *Store) account(bucket []byte, hash lntypes.Hash) (string, error) {
+	var account string
+	err := kvdb.View(s.backend, func(tx kvdb.RTx) error {
+		b := tx.ReadBucket(bucket)
+		if b == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		account = string(b.Get(hash[:]))
+
+		return nil
+	}, func() {
+		account = ""
+	})
+
+	return account, err
+}
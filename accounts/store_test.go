@@ -0,0 +1,81 @@
+package accounts
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStoreAttribution tests that invoices and payments are attributed to
+// accounts independently of each other.
+func TestStoreAttribution(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	store, err := NewStore(db)
+	require.NoError(t, err)
+
+	hash := lntypes.Hash{1, 2, 3}
+
+	// Nothing is attributed yet.
+	account, err := store.InvoiceAccount(hash)
+	require.NoError(t, err)
+	require.Empty(t, account)
+
+	account, err = store.PaymentAccount(hash)
+	require.NoError(t, err)
+	require.Empty(t, account)
+
+	// Attributing the invoice doesn't attribute the payment with the same
+	// hash.
+	require.NoError(t, store.AttributeInvoice(hash, "alice"))
+
+	account, err = store.InvoiceAccount(hash)
+	require.NoError(t, err)
+	require.Equal(t, "alice", account)
+
+	account, err = store.PaymentAccount(hash)
+	require.NoError(t, err)
+	require.Empty(t, account)
+
+	require.NoError(t, store.AttributePayment(hash, "bob"))
+
+	account, err = store.PaymentAccount(hash)
+	require.NoError(t, err)
+	require.Equal(t, "bob", account)
+
+	// The creator of the invoice is attributed independently of its
+	// account.
+	creator, err := store.InvoiceCreator(hash)
+	require.NoError(t, err)
+	require.Empty(t, creator)
+
+	require.NoError(t, store.AttributeInvoiceCreator(hash, "1"))
+
+	creator, err = store.InvoiceCreator(hash)
+	require.NoError(t, err)
+	require.Equal(t, "1", creator)
+
+	// The store picks up the attributions when it is created again.
+	store, err = NewStore(db)
+	require.NoError(t, err)
+
+	account, err = store.InvoiceAccount(hash)
+	require.NoError(t, err)
+	require.Equal(t, "alice", account)
+
+	creator, err = store.InvoiceCreator(hash)
+	require.NoError(t, err)
+	require.Equal(t, "1", creator)
+}
@@ -0,0 +1,175 @@
+// Package anchorproof creates and verifies proofs that tie the anchor outputs
+// of a channel to the node that owns them. The script of the anchor output a
+// node can spend only commits to its funding key of the channel, so every
+// anchor output on-chain paying to that script belongs to the channel. A
+// proof is signed by both the node key and the funding key, which allows
+// auditors to attribute on-chain activity to channels of the node without
+// access to its database.
+package anchorproof
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+)
+
+// signedMsgPrefix is prepended to the message of a proof before it is signed,
+// matching the messages signed by the SignMessage RPC, so the node signature
+// can be verified with the VerifyMessage RPC as well.
+var signedMsgPrefix = []byte("Lightning Signed Message:")
+
+var (
+	// ErrInvalidNodeSignature is returned if the node signature of a
+	// proof doesn't match the message or the node key.
+	ErrInvalidNodeSignature = errors.New("invalid node signature")
+
+	// ErrInvalidFundingSignature is returned if the funding key signature
+	// of a proof doesn't match the message or the funding key.
+	ErrInvalidFundingSignature = errors.New("invalid funding key " +
+		"signature")
+
+	// ErrAnchorScriptMismatch is returned if the anchor script of a proof
+	// doesn't commit to its funding key.
+	ErrAnchorScriptMismatch = errors.New("anchor script doesn't " +
+		"commit to the funding key")
+)
+
+// Signer signs the message of a proof with the node key and the funding key
+// of the channel. Both must sign the double SHA-256 of the given message.
+type Signer interface {
+	// SignNodeMessage returns the compact signature of the node key over
+	// the message.
+	SignNodeMessage(msg []byte) ([]byte, error)
+
+	// SignFundingMessage returns the signature of the funding key over
+	// the message.
+	SignFundingMessage(msg []byte) (*ecdsa.Signature, error)
+}
+
+// Proof ties the anchor outputs of a channel to the node owning them.
+type Proof struct {
+	// NodeKey is the identity key of the node.
+	NodeKey *btcec.PublicKey
+
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint wire.OutPoint
+
+	// FundingKey is the funding key of the node in the channel, which the
+	// anchor script commits to.
+	FundingKey *btcec.PublicKey
+
+	// AnchorScript is the output script of the anchor outputs the node
+	// can spend.
+	AnchorScript []byte
+
+	// ClosingTxid is the transaction that closed the channel, if it is
+	// closed.
+	ClosingTxid *chainhash.Hash
+
+	// NodeSignature is the compact signature of the node key over the
+	// message of the proof.
+	NodeSignature []byte
+
+	// FundingSignature is the DER encoded signature of the funding key
+	// over the message of the proof.
+	FundingSignature []byte
+}
+
+// AnchorScript returns the output script of the anchor that the owner of the
+// funding key can spend.
+func AnchorScript(fundingKey *btcec.PublicKey) ([]byte, error) {
+	script, err := input.CommitScriptAnchor(fundingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return input.WitnessScriptHash(script)
+}
+
+// Message returns the message the proof is signed over, without the prefix
+// added for signing.
+func (p *Proof) Message() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "anchor ownership proof\n")
+	fmt.Fprintf(&b, "node: %x\n", p.NodeKey.SerializeCompressed())
+	fmt.Fprintf(&b, "channel point: %v\n", p.ChannelPoint)
+	fmt.Fprintf(&b, "funding key: %x\n", p.FundingKey.SerializeCompressed())
+	fmt.Fprintf(&b, "anchor script: %x\n", p.AnchorScript)
+	if p.ClosingTxid != nil {
+		fmt.Fprintf(&b, "closing txid: %v\n", p.ClosingTxid)
+	}
+
+	return b.Bytes()
+}
+
+// signedMessage returns the message that is actually signed.
+func (p *Proof) signedMessage() []byte {
+	return append(append([]byte{}, signedMsgPrefix...), p.Message()...)
+}
+
+// New creates a signed proof for the anchor outputs of the channel with the
+// given funding key.
+func New(signer Signer, nodeKey *btcec.PublicKey, chanPoint wire.OutPoint,
+	fundingKey *btcec.PublicKey, closingTxid *chainhash.Hash) (*Proof,
+	error) {
+
+	anchorScript, err := AnchorScript(fundingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &Proof{
+		NodeKey:      nodeKey,
+		ChannelPoint: chanPoint,
+		FundingKey:   fundingKey,
+		AnchorScript: anchorScript,
+		ClosingTxid:  closingTxid,
+	}
+
+	msg := proof.signedMessage()
+	proof.NodeSignature, err = signer.SignNodeMessage(msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign with node key: %w", err)
+	}
+
+	fundingSig, err := signer.SignFundingMessage(msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign with funding key: %w",
+			err)
+	}
+	proof.FundingSignature = fundingSig.Serialize()
+
+	return proof, nil
+}
+
+// Verify checks that the anchor script commits to the funding key, and that
+// the proof is signed by both the node key and the funding key.
+func (p *Proof) Verify() error {
+	anchorScript, err := AnchorScript(p.FundingKey)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(anchorScript, p.AnchorScript) {
+		return ErrAnchorScriptMismatch
+	}
+
+	digest := chainhash.DoubleHashB(p.signedMessage())
+
+	nodeKey, _, err := ecdsa.RecoverCompact(p.NodeSignature, digest)
+	if err != nil || !nodeKey.IsEqual(p.NodeKey) {
+		return ErrInvalidNodeSignature
+	}
+
+	fundingSig, err := ecdsa.ParseDERSignature(p.FundingSignature)
+	if err != nil || !fundingSig.Verify(digest, p.FundingKey) {
+		return ErrInvalidFundingSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package anchorproof
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// keySigner signs proofs with fixed private keys.
+type keySigner struct {
+	nodeKey    *btcec.PrivateKey
+	fundingKey *btcec.PrivateKey
+}
+
+func (s *keySigner) SignNodeMessage(msg []byte) ([]byte, error) {
+	return ecdsa.SignCompact(s.nodeKey, chainhash.DoubleHashB(msg), true)
+}
+
+func (s *keySigner) SignFundingMessage(msg []byte) (*ecdsa.Signature,
+	error) {
+
+	return ecdsa.Sign(s.fundingKey, chainhash.DoubleHashB(msg)), nil
+}
+
+// TestProof tests that proofs verify, and that tampering with them is
+// detected.
+func TestProof(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fundingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	signer := &keySigner{nodeKey: nodeKey, fundingKey: fundingKey}
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 1}
+	closingTxid := chainhash.Hash{2}
+
+	newProof := func() *Proof {
+		proof, err := New(
+			signer, nodeKey.PubKey(), chanPoint,
+			fundingKey.PubKey(), &closingTxid,
+		)
+		require.NoError(t, err)
+
+		return proof
+	}
+
+	proof := newProof()
+	require.NoError(t, proof.Verify())
+	require.Contains(t, string(proof.Message()), closingTxid.String())
+
+	expectedScript, err := AnchorScript(fundingKey.PubKey())
+	require.NoError(t, err)
+	require.Equal(t, expectedScript, proof.AnchorScript)
+
+	// A proof for another channel doesn't verify.
+	proof = newProof()
+	proof.ChannelPoint.Index = 2
+	require.ErrorIs(t, proof.Verify(), ErrInvalidNodeSignature)
+
+	// A proof claiming another node doesn't verify.
+	proof = newProof()
+	proof.NodeKey = otherKey.PubKey()
+	require.ErrorIs(t, proof.Verify(), ErrInvalidNodeSignature)
+
+	// An anchor script not committing to the funding key is detected.
+	proof = newProof()
+	proof.AnchorScript, err = AnchorScript(otherKey.PubKey())
+	require.NoError(t, err)
+	require.ErrorIs(t, proof.Verify(), ErrAnchorScriptMismatch)
+
+	// A proof not signed by the funding key doesn't verify.
+	proof, err = New(
+		&keySigner{nodeKey: nodeKey, fundingKey: otherKey},
+		nodeKey.PubKey(), chanPoint, fundingKey.PubKey(), nil,
+	)
+	require.NoError(t, err)
+	require.NotContains(t, string(proof.Message()), "closing txid")
+	require.ErrorIs(t, proof.Verify(), ErrInvalidFundingSignature)
+}
@@ -0,0 +1,428 @@
+// Package anchorreserve tracks whether the wallet can pay for the fee bumps
+// needed to force close the anchor channels, and alerts if it can't.
+package anchorreserve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// AlertInsufficient is the alert event sent when the wallet can no
+	// longer cover a force close fee bump.
+	AlertInsufficient = "insufficient"
+
+	// AlertRestored is the alert event sent when the wallet can cover a
+	// force close fee bump again.
+	AlertRestored = "restored"
+)
+
+// Status is the result of a single anchor reserve check.
+type Status struct {
+	// NumAnchorChans is the number of anchor channels the wallet policy
+	// reserves value for.
+	NumAnchorChans int `json:"num_anchor_chans"`
+
+	// RequiredReserve is the value the wallet policy keeps in the wallet
+	// for the anchor channels.
+	RequiredReserve btcutil.Amount `json:"required_reserve_sat"`
+
+	// FeeRate is the fee rate the fee bumps were priced at.
+	FeeRate chainfee.SatPerKWeight `json:"fee_rate_sat_per_kw"`
+
+	// FeeBumpCost is the fee needed to get the most expensive commitment
+	// confirmed at FeeRate by spending its anchor.
+	FeeBumpCost btcutil.Amount `json:"fee_bump_cost_sat"`
+
+	// TotalFeeBumpCost is the fee needed to get all commitments confirmed
+	// at FeeRate, in case all anchor channels are force closed at once.
+	TotalFeeBumpCost btcutil.Amount `json:"total_fee_bump_cost_sat"`
+
+	// Balance is the balance of the default wallet account, including
+	// unconfirmed outputs.
+	Balance btcutil.Amount `json:"balance_sat"`
+
+	// NumConfirmedUtxos is the number of confirmed UTXOs in the default
+	// wallet account.
+	NumConfirmedUtxos int `json:"num_confirmed_utxos"`
+
+	// LargestUtxo is the value of the largest confirmed UTXO in the
+	// default wallet account.
+	LargestUtxo btcutil.Amount `json:"largest_utxo_sat"`
+}
+
+// Sufficient returns true if the wallet covers both the reserve required by
+// the wallet policy and the most expensive force close fee bump.
+func (s *Status) Sufficient() bool {
+	return s.Balance >= s.RequiredReserve && s.Balance >= s.FeeBumpCost
+}
+
+// Alert is the JSON body posted to the alert webhook.
+type Alert struct {
+	// Event is either AlertInsufficient or AlertRestored.
+	Event string `json:"event"`
+
+	// Status is the status of the check that raised the alert.
+	Status *Status `json:"status"`
+}
+
+// Config contains the dependencies of the anchor reserve monitor.
+type Config struct {
+	// FetchChannels returns all open channels.
+	FetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	// NumAnchorChans returns the number of anchor channels the wallet
+	// policy reserves value for.
+	NumAnchorChans func() (int, error)
+
+	// RequiredReserve returns the value the wallet policy keeps in the
+	// wallet for the given number of anchor channels.
+	RequiredReserve func(numAnchorChans uint32) btcutil.Amount
+
+	// ListUnspent returns the UTXOs of the default wallet account,
+	// including unconfirmed ones.
+	ListUnspent func() ([]*lnwallet.Utxo, error)
+
+	// FeeEstimator is used to price the fee bumps.
+	FeeEstimator chainfee.Estimator
+
+	// ConfTarget is the confirmation target the fee bumps are priced
+	// for.
+	ConfTarget uint32
+
+	// Ticker triggers the periodic checks.
+	Ticker ticker.Ticker
+
+	// Consolidate sweeps all confirmed UTXOs of the default wallet account
+	// into a single output at the given fee rate and returns the txid of
+	// the consolidation. If nil, UTXOs are never consolidated.
+	Consolidate func(feeRate chainfee.SatPerKWeight) (chainhash.Hash,
+		error)
+
+	// MaxConsolidationFeeRate is the maximum fee rate UTXOs are
+	// consolidated at.
+	MaxConsolidationFeeRate chainfee.SatPerKWeight
+
+	// WebhookURL is the URL alerts are posted to. If empty, alerts are
+	// only logged and recorded as metrics.
+	WebhookURL string
+
+	// WebhookTimeout is the time the webhook may take to respond.
+	WebhookTimeout time.Duration
+}
+
+// Monitor periodically checks whether the wallet can cover the fee bumps
+// needed to force close the anchor channels.
+type Monitor struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// status is the status of the last check.
+	status atomic.Pointer[Status]
+
+	// sufficient is the outcome of the last check, nil before the first
+	// check.
+	sufficient *bool
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewMonitor creates a new anchor reserve monitor.
+func NewMonitor(cfg *Config) *Monitor {
+	return &Monitor{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts the periodic checks.
+func (m *Monitor) Start() error {
+	m.started.Do(func() {
+		log.Info("Anchor reserve monitor starting")
+
+		m.cfg.Ticker.Resume()
+
+		m.wg.Add(1)
+		go m.run()
+	})
+
+	return nil
+}
+
+// Stop stops the periodic checks.
+func (m *Monitor) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Anchor reserve monitor shutting down...")
+		defer log.Debug("Anchor reserve monitor shutdown complete")
+
+		close(m.quit)
+		m.wg.Wait()
+
+		m.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// Status returns the status of the last check, or nil if no check completed
+// yet.
+func (m *Monitor) Status() *Status {
+	return m.status.Load()
+}
+
+// run checks the reserve on start up and on every tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Monitor) run() {
+	defer m.wg.Done()
+
+	for {
+		if err := m.checkReserve(); err != nil {
+			log.Errorf("Unable to check anchor reserve: %v", err)
+		}
+
+		select {
+		case <-m.cfg.Ticker.Ticks():
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// checkReserve runs a single check, raises alerts if the outcome changed and
+// consolidates UTXOs if needed.
+func (m *Monitor) checkReserve() error {
+	status, err := m.fetchStatus()
+	if err != nil {
+		return err
+	}
+	m.status.Store(status)
+
+	metrics.SetAnchorReserve(
+		int64(status.RequiredReserve), int64(status.FeeBumpCost),
+		int64(status.Balance),
+	)
+
+	sufficient := status.Sufficient()
+	switch {
+	case !sufficient:
+		log.Warnf("Wallet balance of %v can't cover the anchor "+
+			"reserve: required_reserve=%v, fee_bump_cost=%v at "+
+			"%v", status.Balance, status.RequiredReserve,
+			status.FeeBumpCost, status.FeeRate)
+
+		if m.sufficient == nil || *m.sufficient {
+			metrics.IncAnchorReserveAlerts()
+			m.sendAlert(AlertInsufficient, status)
+		}
+
+	case m.sufficient != nil && !*m.sufficient:
+		log.Infof("Wallet balance of %v covers the anchor reserve "+
+			"again", status.Balance)
+
+		m.sendAlert(AlertRestored, status)
+
+	default:
+		log.Debugf("Anchor reserve covered: balance=%v, "+
+			"required_reserve=%v, fee_bump_cost=%v",
+			status.Balance, status.RequiredReserve,
+			status.FeeBumpCost)
+	}
+	m.sufficient = &sufficient
+
+	m.maybeConsolidate(status)
+
+	return nil
+}
+
+// fetchStatus gathers the current status of the anchor reserve.
+func (m *Monitor) fetchStatus() (*Status, error) {
+	numAnchorChans, err := m.cfg.NumAnchorChans()
+	if err != nil {
+		return nil, fmt.Errorf("unable to count anchor channels: %w",
+			err)
+	}
+
+	feeRate, err := m.cfg.FeeEstimator.EstimateFeePerKW(
+		m.cfg.ConfTarget,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate fee rate: %w", err)
+	}
+
+	status := &Status{
+		NumAnchorChans: numAnchorChans,
+		RequiredReserve: m.cfg.RequiredReserve(
+			uint32(numAnchorChans),
+		),
+		FeeRate: feeRate,
+	}
+
+	channels, err := m.cfg.FetchChannels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channels: %w", err)
+	}
+	for _, channel := range channels {
+		if !channel.ChanType.HasAnchors() {
+			continue
+		}
+
+		cost := FeeBumpCost(channel, feeRate)
+		status.TotalFeeBumpCost += cost
+		if cost > status.FeeBumpCost {
+			status.FeeBumpCost = cost
+		}
+	}
+
+	utxos, err := m.cfg.ListUnspent()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list unspent outputs: %w",
+			err)
+	}
+	for _, utxo := range utxos {
+		status.Balance += utxo.Value
+
+		if utxo.Confirmations == 0 {
+			continue
+		}
+
+		status.NumConfirmedUtxos++
+		if utxo.Value > status.LargestUtxo {
+			status.LargestUtxo = utxo.Value
+		}
+	}
+
+	return status, nil
+}
+
+// maybeConsolidate consolidates the confirmed UTXOs if no UTXO alone can pay
+// for the most expensive fee bump but their sum can, and fees are low enough.
+func (m *Monitor) maybeConsolidate(status *Status) {
+	switch {
+	case m.cfg.Consolidate == nil:
+		return
+
+	case status.NumConfirmedUtxos < 2:
+		return
+
+	case status.LargestUtxo >= status.FeeBumpCost:
+		return
+
+	case status.Balance < status.FeeBumpCost:
+		return
+
+	case status.FeeRate > m.cfg.MaxConsolidationFeeRate:
+		log.Infof("Postponing consolidation of %d UTXOs, fee rate %v "+
+			"is above %v", status.NumConfirmedUtxos,
+			status.FeeRate, m.cfg.MaxConsolidationFeeRate)
+
+		return
+	}
+
+	txid, err := m.cfg.Consolidate(status.FeeRate)
+	if err != nil {
+		log.Errorf("Unable to consolidate %d UTXOs: %v",
+			status.NumConfirmedUtxos, err)
+
+		return
+	}
+
+	log.Infof("Consolidated %d UTXOs to cover a fee bump of %v in "+
+		"txid=%v", status.NumConfirmedUtxos, status.FeeBumpCost, txid)
+}
+
+// sendAlert posts an alert to the webhook, if one is configured.
+func (m *Monitor) sendAlert(event string, status *Status) {
+	if m.cfg.WebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(&Alert{
+		Event:  event,
+		Status: status,
+	})
+	if err != nil {
+		log.Errorf("Unable to encode alert: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), m.cfg.WebhookTimeout,
+	)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, m.cfg.WebhookURL, bytes.NewReader(body),
+	)
+	if err != nil {
+		log.Errorf("Unable to create alert request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Errorf("Unable to post %v alert: %v", event, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		log.Errorf("Alert webhook returned status %v", resp.Status)
+	}
+}
+
+// FeeBumpCost returns the fee we need to pay to get the current local
+// commitment of the given anchor channel confirmed at the given fee rate, by
+// spending its anchor together with a wallet input. The fee the commitment
+// itself pays is taken into account.
+func FeeBumpCost(channel *channeldb.OpenChannel,
+	feeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	commit := channel.LocalCommitment
+
+	commitWeight := lntypes.WeightUnit(input.AnchorCommitWeight)
+	anchorWitnessSize := lntypes.WeightUnit(input.AnchorWitnessSize)
+	if channel.ChanType.IsTaproot() {
+		commitWeight = input.TaprootCommitWeight
+		anchorWitnessSize = input.TaprootAnchorWitnessSize
+	}
+	commitWeight += lntypes.WeightUnit(
+		input.HTLCWeight * len(commit.Htlcs),
+	)
+
+	// The child spends the anchor and a wallet input, and pays the change
+	// back to the wallet.
+	var child input.TxWeightEstimator
+	child.AddWitnessInput(anchorWitnessSize)
+	child.AddTaprootKeySpendInput(txscript.SigHashDefault)
+	child.AddP2TROutput()
+
+	packageFee := feeRate.FeeForWeight(commitWeight + child.Weight())
+	if packageFee <= commit.CommitFee {
+		return 0
+	}
+
+	return packageFee - commit.CommitFee
+}
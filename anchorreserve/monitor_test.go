@@ -0,0 +1,228 @@
+package anchorreserve
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+// testWallet is a wallet whose UTXOs can be changed while the monitor runs.
+type testWallet struct {
+	mu    sync.Mutex
+	utxos []*lnwallet.Utxo
+}
+
+func (w *testWallet) setUtxos(values ...btcutil.Amount) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.utxos = nil
+	for _, value := range values {
+		w.utxos = append(w.utxos, &lnwallet.Utxo{
+			Value:         value,
+			Confirmations: 6,
+		})
+	}
+}
+
+func (w *testWallet) listUnspent() ([]*lnwallet.Utxo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.utxos, nil
+}
+
+// newTestMonitor creates a monitor for a single anchor channel whose fee
+// bumps are priced at the given fee rate.
+func newTestMonitor(wallet *testWallet,
+	feeRate chainfee.SatPerKWeight) (*Monitor, *ticker.Force) {
+
+	channel := &channeldb.OpenChannel{
+		ChanType: channeldb.AnchorOutputsBit |
+			channeldb.SingleFunderTweaklessBit,
+	}
+
+	forceTicker := ticker.NewForce(time.Hour)
+	monitor := NewMonitor(&Config{
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			return []*channeldb.OpenChannel{channel}, nil
+		},
+		NumAnchorChans: func() (int, error) {
+			return 1, nil
+		},
+		RequiredReserve: func(n uint32) btcutil.Amount {
+			reserve := lnwallet.AnchorChanReservedValue
+			return btcutil.Amount(n) * reserve
+		},
+		ListUnspent:             wallet.listUnspent,
+		FeeEstimator:            chainfee.NewStaticEstimator(feeRate, 0),
+		ConfTarget:              6,
+		Ticker:                  forceTicker,
+		MaxConsolidationFeeRate: feeRate,
+		WebhookTimeout:          testTimeout,
+	})
+
+	return monitor, forceTicker
+}
+
+// TestFeeBumpCost tests that the fee bump cost grows with the number of HTLCs
+// and takes the fee paid by the commitment into account.
+func TestFeeBumpCost(t *testing.T) {
+	t.Parallel()
+
+	channel := &channeldb.OpenChannel{
+		ChanType: channeldb.AnchorOutputsBit,
+	}
+	feeRate := chainfee.SatPerKWeight(10_000)
+
+	cost := FeeBumpCost(channel, feeRate)
+	require.Positive(t, cost)
+
+	channel.LocalCommitment.Htlcs = make([]channeldb.HTLC, 3)
+	require.Greater(t, FeeBumpCost(channel, feeRate), cost)
+
+	channel.LocalCommitment.CommitFee = 1_000_000
+	require.Zero(t, FeeBumpCost(channel, feeRate))
+}
+
+// TestMonitorAlerts tests that an alert is posted once when the wallet can no
+// longer cover the reserve, and once when it can again.
+func TestMonitorAlerts(t *testing.T) {
+	t.Parallel()
+
+	alerts := make(chan *Alert, 10)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			alert := &Alert{}
+			err := json.NewDecoder(r.Body).Decode(alert)
+			require.NoError(t, err)
+
+			alerts <- alert
+		},
+	))
+	t.Cleanup(server.Close)
+
+	wallet := &testWallet{}
+	wallet.setUtxos(1_000)
+
+	monitor, forceTicker := newTestMonitor(wallet, 2_500)
+	monitor.cfg.WebhookURL = server.URL
+
+	require.NoError(t, monitor.Start())
+	t.Cleanup(func() {
+		require.NoError(t, monitor.Stop())
+	})
+
+	receiveAlert := func(event string) {
+		t.Helper()
+
+		select {
+		case alert := <-alerts:
+			require.Equal(t, event, alert.Event)
+
+		case <-time.After(testTimeout):
+			t.Fatalf("no %v alert received", event)
+		}
+	}
+	tick := func() {
+		t.Helper()
+
+		select {
+		case forceTicker.Force <- time.Now():
+		case <-time.After(testTimeout):
+			t.Fatalf("tick not consumed")
+		}
+	}
+
+	// The initial check finds the reserve insufficient.
+	receiveAlert(AlertInsufficient)
+	status := monitor.Status()
+	require.False(t, status.Sufficient())
+	require.Equal(t, lnwallet.AnchorChanReservedValue,
+		status.RequiredReserve)
+
+	// A second insufficient check doesn't repeat the alert.
+	tick()
+	tick()
+	select {
+	case alert := <-alerts:
+		t.Fatalf("unexpected alert: %v", alert.Event)
+	default:
+	}
+
+	// Once the wallet is funded, the reserve is restored.
+	wallet.setUtxos(1_000, 50_000)
+	tick()
+	receiveAlert(AlertRestored)
+}
+
+// TestMonitorConsolidate tests that UTXOs are consolidated only if no UTXO
+// alone covers the fee bump and fees are low enough.
+func TestMonitorConsolidate(t *testing.T) {
+	t.Parallel()
+
+	wallet := &testWallet{}
+	wallet.setUtxos(200_000)
+
+	monitor, forceTicker := newTestMonitor(wallet, 2_500)
+
+	consolidations := make(chan chainfee.SatPerKWeight, 10)
+	monitor.cfg.Consolidate = func(
+		feeRate chainfee.SatPerKWeight) (chainhash.Hash, error) {
+
+		consolidations <- feeRate
+		wallet.setUtxos()
+
+		return chainhash.Hash{}, nil
+	}
+
+	require.NoError(t, monitor.Start())
+	t.Cleanup(func() {
+		require.NoError(t, monitor.Stop())
+	})
+
+	tick := func() {
+		t.Helper()
+
+		select {
+		case forceTicker.Force <- time.Now():
+		case <-time.After(testTimeout):
+			t.Fatalf("tick not consumed")
+		}
+	}
+
+	// A single large UTXO covers the fee bump.
+	tick()
+	require.Empty(t, consolidations)
+
+	// Many small UTXOs that only cover it together are consolidated.
+	status := monitor.Status()
+	require.NotNil(t, status)
+	small := status.FeeBumpCost / 4
+	wallet.setUtxos(small, small, small, small, small, small, small,
+		small, small, small, small, small, small, small, small, small,
+		small, small, small, small, small, small, small, small, small)
+
+	tick()
+	tick()
+	select {
+	case feeRate := <-consolidations:
+		require.Equal(t, chainfee.SatPerKWeight(2_500), feeRate)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("UTXOs not consolidated")
+	}
+}
@@ -0,0 +1,274 @@
+package asyncpay
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/onionmessage"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// HeldHTLCAvailableType is the onion message content type that the
+	// sender of an async payment uses to tell the recipient that a
+	// payment is waiting for it.
+	HeldHTLCAvailableType tlv.Type = 72
+
+	// ReleaseHeldHTLCType is the onion message content type that the
+	// recipient of an async payment uses to tell the sender that it is
+	// online and the payment can be sent.
+	ReleaseHeldHTLCType tlv.Type = 74
+
+	// DefaultHoldTTL is the default time a payment is held for its
+	// recipient.
+	DefaultHoldTTL = 24 * time.Hour
+)
+
+var (
+	// ErrUnknownHold is returned when a release arrives for a payment
+	// that isn't held, for example because it expired.
+	ErrUnknownHold = errors.New("unknown held payment")
+
+	// ErrNoReplyPath is returned when a held_htlc_available message
+	// arrives without a path to reply to.
+	ErrNoReplyPath = errors.New("held htlc available without reply path")
+)
+
+// Messenger sends onion messages and dispatches the ones we receive.
+type Messenger interface {
+	// RegisterHandler registers a handler for messages that carry a
+	// record of the given content type.
+	RegisterHandler(typ tlv.Type, handler onionmessage.Handler) error
+
+	// SendMessage sends an onion message with the given contents over
+	// the given blinded path.
+	SendMessage(path, replyPath *sphinx.BlindedPath,
+		contents map[tlv.Type][]byte) error
+}
+
+// Config holds the dependencies of the Manager.
+type Config struct {
+	// Messenger is used to exchange the async payment messages.
+	Messenger Messenger
+
+	// BuildReplyPath builds a blinded path to our node that carries the
+	// given path ID.
+	BuildReplyPath func(pathID []byte) (*sphinx.BlindedPath, error)
+
+	// HoldTTL is the time a payment is held for its recipient. Payments
+	// that aren't released within it are dropped.
+	HoldTTL time.Duration
+
+	// Clock is used to expire held payments.
+	Clock clock.Clock
+}
+
+// heldPayment is a payment that waits for its recipient to come online.
+type heldPayment struct {
+	// release sends the payment.
+	release func()
+
+	// expiry is the time after which the payment is dropped.
+	expiry time.Time
+}
+
+// Manager implements the async payments protocol, which lets a payment to an
+// often-offline recipient wait until the recipient comes online.
+//
+// As a sender, the Manager holds a payment and tells the recipient about it
+// with a held_htlc_available onion message. The recipient's LSP holds that
+// message until the recipient comes online, which then answers with a
+// release_held_htlc message over the reply path, at which point the payment
+// is sent. As a recipient, the Manager releases every payment it is told
+// about, since receiving the message means that we are online.
+//
+// NOTE: Held payments are only kept in memory. They are dropped on restart,
+// and a release that arrives afterwards is rejected as unknown, so the payment
+// is never sent.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// held are the held payments by their path ID.
+	held map[string]*heldPayment
+
+	// mu guards held and makes sure no release is started once quit is
+	// closed.
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a new async payments manager.
+func NewManager(cfg *Config) *Manager {
+	return &Manager{
+		cfg:  cfg,
+		held: make(map[string]*heldPayment),
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts the manager and registers the handlers of the async payment
+// messages.
+func (m *Manager) Start() error {
+	var err error
+	m.started.Do(func() {
+		log.Info("Async payments manager starting")
+
+		err = m.cfg.Messenger.RegisterHandler(
+			HeldHTLCAvailableType, m.handleHeldHTLCAvailable,
+		)
+		if err != nil {
+			return
+		}
+
+		err = m.cfg.Messenger.RegisterHandler(
+			ReleaseHeldHTLCType, m.handleReleaseHeldHTLC,
+		)
+	})
+
+	return err
+}
+
+// Stop stops the manager and drops all held payments. It waits for the
+// payments that were released already to be handed to the router.
+func (m *Manager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Async payments manager shutting down...")
+		defer log.Debug("Async payments manager shutdown complete")
+
+		m.mu.Lock()
+		close(m.quit)
+		m.held = make(map[string]*heldPayment)
+		m.mu.Unlock()
+
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// HoldPayment holds a payment until the recipient that can be reached over
+// the given blinded path comes online. The release closure is called to send
+// the payment once the recipient released it.
+func (m *Manager) HoldPayment(recipientPath *sphinx.BlindedPath,
+	release func()) error {
+
+	pathID, err := onionmessage.NewPathID()
+	if err != nil {
+		return err
+	}
+
+	replyPath, err := m.cfg.BuildReplyPath(pathID)
+	if err != nil {
+		return fmt.Errorf("unable to build reply path: %w", err)
+	}
+
+	m.mu.Lock()
+	m.pruneExpired()
+	m.held[string(pathID)] = &heldPayment{
+		release: release,
+		expiry:  m.cfg.Clock.Now().Add(m.cfg.HoldTTL),
+	}
+	m.mu.Unlock()
+
+	err = m.cfg.Messenger.SendMessage(
+		recipientPath, replyPath, map[tlv.Type][]byte{
+			HeldHTLCAvailableType: {},
+		},
+	)
+	if err != nil {
+		m.mu.Lock()
+		delete(m.held, string(pathID))
+		m.mu.Unlock()
+
+		return fmt.Errorf("unable to send held htlc available: %w",
+			err)
+	}
+
+	log.Debugf("Holding payment for path ID %x", pathID)
+
+	return nil
+}
+
+// NumHeld returns the number of payments that are currently held.
+func (m *Manager) NumHeld() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pruneExpired()
+
+	return len(m.held)
+}
+
+// handleHeldHTLCAvailable releases a payment that a sender holds for us.
+func (m *Manager) handleHeldHTLCAvailable(msg *onionmessage.Message) error {
+	if msg.ReplyPath == nil {
+		return ErrNoReplyPath
+	}
+
+	log.Debugf("Releasing payment held for us")
+
+	return m.cfg.Messenger.SendMessage(
+		msg.ReplyPath, nil, map[tlv.Type][]byte{
+			ReleaseHeldHTLCType: {},
+		},
+	)
+}
+
+// handleReleaseHeldHTLC sends the payment that the recipient released.
+func (m *Manager) handleReleaseHeldHTLC(msg *onionmessage.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// All held payments are dropped once we're shutting down.
+	select {
+	case <-m.quit:
+		return fmt.Errorf("%w: path ID %x", ErrUnknownHold, msg.PathID)
+	default:
+	}
+
+	m.pruneExpired()
+	payment, ok := m.held[string(msg.PathID)]
+	delete(m.held, string(msg.PathID))
+
+	if !ok {
+		return fmt.Errorf("%w: path ID %x", ErrUnknownHold, msg.PathID)
+	}
+
+	log.Infof("Recipient released payment for path ID %x", msg.PathID)
+
+	// Sending the payment may take a while, so don't block the delivery
+	// of onion messages on it. The wait group is added to while holding
+	// the mutex, so Stop can't miss the release.
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		payment.release()
+	}()
+
+	return nil
+}
+
+// pruneExpired removes the held payments that expired.
+//
+// NOTE: The caller must hold the mutex.
+func (m *Manager) pruneExpired() {
+	now := m.cfg.Clock.Now()
+	for pathID, payment := range m.held {
+		if now.After(payment.expiry) {
+			log.Debugf("Dropping expired held payment for path "+
+				"ID %x", pathID)
+
+			delete(m.held, pathID)
+		}
+	}
+}
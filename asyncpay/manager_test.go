@@ -0,0 +1,289 @@
+package asyncpay
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionmessage"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode is a node of the test network.
+type testNode struct {
+	key       *btcec.PublicKey
+	messenger *onionmessage.Messenger
+	manager   *Manager
+}
+
+// testNetwork is a set of nodes that deliver onion messages to each other
+// directly. The sender is node 0, its peer node 1 is the LSP of the recipient
+// node 2.
+type testNetwork struct {
+	clock *clock.TestClock
+	nodes []*testNode
+
+	mu      sync.Mutex
+	offline map[route.Vertex]bool
+}
+
+// newTestNetwork creates the test network and starts the managers of all
+// nodes.
+func newTestNetwork(t *testing.T) *testNetwork {
+	n := &testNetwork{
+		clock:   clock.NewTestClock(time.Unix(1, 0)),
+		offline: make(map[route.Vertex]bool),
+	}
+
+	byKey := make(map[route.Vertex]*testNode)
+	for i := 0; i < 3; i++ {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		node := &testNode{
+			key: priv.PubKey(),
+		}
+		self := route.NewVertex(priv.PubKey())
+
+		node.messenger = onionmessage.New(&onionmessage.Config{
+			NodeKey:     &sphinx.PrivKeyECDH{PrivKey: priv},
+			ChainParams: &chaincfg.RegressionNetParams,
+			SendToPeer: func(to route.Vertex,
+				msg *lnwire.OnionMessage) error {
+
+				n.mu.Lock()
+				offline := n.offline[to]
+				n.mu.Unlock()
+				if offline {
+					return onionmessage.ErrPeerOffline
+				}
+
+				return byKey[to].messenger.HandleOnionMessage(
+					self, msg,
+				)
+			},
+			HoldForPeer: func(route.Vertex) bool {
+				return true
+			},
+			Clock: n.clock,
+		})
+
+		byKey[self] = node
+		n.nodes = append(n.nodes, node)
+	}
+
+	for i, node := range n.nodes {
+		node.manager = NewManager(&Config{
+			Messenger: node.messenger,
+			BuildReplyPath: n.replyPathBuilder(
+				t, n.nodes[1].key, node.key,
+			),
+			HoldTTL: time.Hour,
+			Clock:   n.clock,
+		})
+		require.NoError(t, node.manager.Start(), "node %d", i)
+	}
+
+	return n
+}
+
+// replyPathBuilder returns a BuildReplyPath function that builds paths to the
+// given node through the given introduction node.
+func (n *testNetwork) replyPathBuilder(t *testing.T, intro,
+	node *btcec.PublicKey) func([]byte) (*sphinx.BlindedPath, error) {
+
+	return func(pathID []byte) (*sphinx.BlindedPath, error) {
+		sessionKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return onionmessage.BuildBlindedPath(
+			sessionKey, []*btcec.PublicKey{intro, node}, pathID,
+		)
+	}
+}
+
+// setOffline marks the node with the given index as offline or online.
+func (n *testNetwork) setOffline(i int, offline bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.offline[route.NewVertex(n.nodes[i].key)] = offline
+}
+
+// recipientPath returns a blinded path to the recipient through its LSP.
+func (n *testNetwork) recipientPath(t *testing.T) *sphinx.BlindedPath {
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	path, err := onionmessage.BuildBlindedPath(
+		sessionKey, []*btcec.PublicKey{n.nodes[1].key, n.nodes[2].key},
+		nil,
+	)
+	require.NoError(t, err)
+
+	return path
+}
+
+// TestAsyncPayment asserts that a held payment is released once the offline
+// recipient comes back online.
+func TestAsyncPayment(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t)
+	sender := n.nodes[0].manager
+
+	// The recipient is offline, so the held_htlc_available message waits
+	// at its LSP.
+	n.setOffline(2, true)
+
+	released := make(chan struct{})
+	err := sender.HoldPayment(n.recipientPath(t), func() {
+		close(released)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, sender.NumHeld())
+
+	select {
+	case <-released:
+		t.Fatal("payment released while recipient offline")
+	default:
+	}
+
+	// Once the recipient is back online, its LSP delivers the message and
+	// the recipient releases the payment.
+	n.setOffline(2, false)
+	n.nodes[1].messenger.PeerOnline(route.NewVertex(n.nodes[2].key))
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("payment not released")
+	}
+	require.Zero(t, sender.NumHeld())
+}
+
+// TestAsyncPaymentExpiry asserts that a payment that isn't released within the
+// hold TTL is dropped.
+func TestAsyncPaymentExpiry(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t)
+	sender := n.nodes[0].manager
+
+	n.setOffline(2, true)
+
+	var released bool
+	err := sender.HoldPayment(n.recipientPath(t), func() {
+		released = true
+	})
+	require.NoError(t, err)
+
+	n.clock.SetTime(n.clock.Now().Add(2 * time.Hour))
+	require.Zero(t, sender.NumHeld())
+
+	// The release that arrives late is ignored.
+	n.setOffline(2, false)
+	n.nodes[1].messenger.PeerOnline(route.NewVertex(n.nodes[2].key))
+	require.False(t, released)
+}
+
+// TestHoldPaymentSendFailure asserts that a payment isn't held if the
+// held_htlc_available message can't be sent.
+func TestHoldPaymentSendFailure(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t)
+	sender := n.nodes[0].manager
+
+	// The LSP of the recipient is offline and doesn't hold messages for
+	// the sender.
+	n.setOffline(1, true)
+
+	err := sender.HoldPayment(n.recipientPath(t), func() {})
+	require.ErrorIs(t, err, onionmessage.ErrPeerOffline)
+	require.Zero(t, sender.NumHeld())
+}
+
+// TestStopWaitsForRelease asserts that Stop waits for a released payment to be
+// handed off, and that releases arriving after Stop are ignored.
+func TestStopWaitsForRelease(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t)
+	sender := n.nodes[0].manager
+
+	n.setOffline(2, true)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	err := sender.HoldPayment(n.recipientPath(t), func() {
+		close(started)
+		<-unblock
+	})
+	require.NoError(t, err)
+
+	n.setOffline(2, false)
+	n.nodes[1].messenger.PeerOnline(route.NewVertex(n.nodes[2].key))
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("payment not released")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		require.NoError(t, sender.Stop())
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("stopped before release returned")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(unblock)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("stop didn't return")
+	}
+}
+
+// TestStopDropsHeldPayments asserts that payments held when the manager stops
+// are never sent.
+func TestStopDropsHeldPayments(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t)
+	sender := n.nodes[0].manager
+
+	n.setOffline(2, true)
+
+	released := make(chan struct{})
+	err := sender.HoldPayment(n.recipientPath(t), func() {
+		close(released)
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Stop())
+	require.Zero(t, sender.NumHeld())
+
+	// The release that arrives after the manager stopped is ignored.
+	n.setOffline(2, false)
+	n.nodes[1].messenger.PeerOnline(route.NewVertex(n.nodes[2].key))
+
+	select {
+	case <-released:
+		t.Fatal("payment released after stop")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
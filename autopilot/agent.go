@@ -58,6 +58,12 @@ type Config struct {
 	// when opening channels.
 	Constraints AgentConstraints
 
+	// MaxPendingCloses is the maximum number of channels recommended for
+	// closing by an external agent that can be closing at the same time.
+	// If zero, the channel closes recommended by external agents are
+	// ignored.
+	MaxPendingCloses uint16
+
 	// TODO(roasbeef): add additional signals from fee rates and revenue of
 	// currently opened channels
 }
@@ -145,6 +151,17 @@ type Agent struct {
 	// will be sent.
 	heuristicUpdates chan *heuristicUpdate
 
+	// externalAgentUpdates is a channel where updates about the
+	// registration of an external agent will be sent. This channel will
+	// be buffered to ensure we have at most one pending update of this
+	// type to handle at a given time.
+	externalAgentUpdates chan *externalAgentUpdate
+
+	// externalAgent is the external agent that makes the decisions of
+	// the agent instead of its heuristic, if one is registered.
+	externalAgent    ExternalAgent
+	externalAgentMtx sync.Mutex
+
 	// totalBalance is the total number of satoshis the backing wallet is
 	// known to control at any given instance. This value will be updated
 	// when the agent receives external balance update signals.
@@ -164,7 +181,12 @@ type Agent struct {
 	// This state is required as otherwise, we may go over our allotted
 	// channel limit, or open multiple channels to the same node.
 	pendingOpens map[NodeID]LocalChannel
-	pendingMtx   sync.Mutex
+
+	// pendingCloses tracks the channels that we've requested to be closed
+	// on the recommendation of an external agent, but haven't yet been
+	// confirmed as being fully closed.
+	pendingCloses map[lnwire.ShortChannelID]struct{}
+	pendingMtx    sync.Mutex
 
 	quit chan struct{}
 	wg   sync.WaitGroup
@@ -188,6 +210,9 @@ func New(cfg Config, initialState []LocalChannel) (*Agent, error) {
 		failedNodes:        make(map[NodeID]struct{}),
 		pendingConns:       make(map[NodeID]struct{}),
 		pendingOpens:       make(map[NodeID]LocalChannel),
+
+		externalAgentUpdates: make(chan *externalAgentUpdate, 1),
+		pendingCloses:        make(map[lnwire.ShortChannelID]struct{}),
 	}
 
 	for _, c := range initialState {
@@ -461,6 +486,12 @@ func (a *Agent) controller() {
 				}
 				a.chanStateMtx.Unlock()
 
+				a.pendingMtx.Lock()
+				for _, closedChan := range update.closedChans {
+					delete(a.pendingCloses, closedChan)
+				}
+				a.pendingMtx.Unlock()
+
 				updateBalance()
 			}
 
@@ -498,6 +529,12 @@ func (a *Agent) controller() {
 			log.Debugf("Heuristic %v updated, assessing need for "+
 				"more channels", upd.heuristic.Name())
 
+		// An external agent has been registered or unregistered, so
+		// we'll consult the new decision maker.
+		case <-a.externalAgentUpdates:
+			log.Debugf("External agent updated, assessing need " +
+				"for channel changes")
+
 		// The agent has been signalled to exit, so we'll bail out
 		// immediately.
 		case <-a.quit:
@@ -524,6 +561,24 @@ func (a *Agent) controller() {
 		availableFunds, numChans := a.cfg.Constraints.ChannelBudget(
 			totalChans, a.totalBalance,
 		)
+
+		// If an external agent is registered, it decides which
+		// channels to open and close within our budget instead of our
+		// heuristic. As it may also recommend channels to close, we
+		// consult it even if we can't open any more channels.
+		if externalAgent := a.getExternalAgent(); externalAgent != nil {
+			err := a.executeRecommendations(
+				externalAgent, availableFunds, numChans,
+				totalChans,
+			)
+			if err != nil {
+				log.Errorf("Unable to execute recommendations "+
+					"of external agent: %v", err)
+			}
+
+			continue
+		}
+
 		switch {
 		case numChans == 0:
 			continue
@@ -121,8 +121,9 @@ type openChanIntent struct {
 }
 
 type mockChanController struct {
-	openChanSignals chan openChanIntent
-	private         bool
+	openChanSignals  chan openChanIntent
+	closeChanSignals chan wire.OutPoint
+	private          bool
 }
 
 func (m *mockChanController) OpenChannel(target *btcec.PublicKey,
@@ -138,6 +139,8 @@ func (m *mockChanController) OpenChannel(target *btcec.PublicKey,
 }
 
 func (m *mockChanController) CloseChannel(chanPoint *wire.OutPoint) error {
+	m.closeChanSignals <- *chanPoint
+
 	return nil
 }
 
@@ -176,7 +179,8 @@ func setup(t *testing.T, initialChans []LocalChannel) *testContext {
 	}
 
 	chanController := &mockChanController{
-		openChanSignals: make(chan openChanIntent, 10),
+		openChanSignals:  make(chan openChanIntent, 10),
+		closeChanSignals: make(chan wire.OutPoint, 10),
 	}
 	memGraph, _ := newMemChanGraph(t)
 
@@ -210,8 +214,9 @@ func setup(t *testing.T, initialChans []LocalChannel) *testContext {
 		DisconnectPeer: func(*btcec.PublicKey) error {
 			return nil
 		},
-		Graph:       memGraph,
-		Constraints: constraints,
+		Graph:            memGraph,
+		Constraints:      constraints,
+		MaxPendingCloses: 1,
 	}
 
 	agent, err := New(testCfg, initialChans)
@@ -0,0 +1,260 @@
+package autopilot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/davecgh/go-spew/spew"
+)
+
+// ErrExternalAgentRegistered is returned when registering an external agent
+// while another one is registered already.
+var ErrExternalAgentRegistered = errors.New("an external agent is already " +
+	"registered")
+
+// AgentState is the state of the node the autopilot agent hands to an external
+// agent to base its recommendations on.
+type AgentState struct {
+	// Graph is the current channel graph.
+	Graph ChannelGraph
+
+	// Channels are the channels of the node, including the ones the agent
+	// is currently opening.
+	Channels []LocalChannel
+
+	// WalletBalance is the confirmed balance of the backing wallet.
+	WalletBalance btcutil.Amount
+
+	// AvailableFunds is the amount the agent may commit to new channels
+	// while staying within its constraints.
+	AvailableFunds btcutil.Amount
+
+	// NumChans is the number of additional channels the agent may open
+	// while staying within its constraints.
+	NumChans uint32
+}
+
+// Recommendations are the channel changes recommended by an external agent.
+type Recommendations struct {
+	// Opens are the channels the external agent recommends to open. The
+	// agent opens them in the given order as long as they fit its budget,
+	// reducing the size of channels that exceed it.
+	Opens []AttachmentDirective
+
+	// Closes are the funding outpoints of the channels the external agent
+	// recommends to close cooperatively.
+	Closes []wire.OutPoint
+}
+
+// ExternalAgent is implemented by decision makers outside of lnd that drive
+// the autopilot agent in place of its attachment heuristic. Whenever the agent
+// assesses its state, it hands it to the external agent and executes the
+// returned recommendations within its constraints.
+type ExternalAgent interface {
+	// Recommend returns the channel changes the external agent recommends
+	// given the current state of the node.
+	//
+	// NOTE: This is called from the control loop of the agent, so it
+	// should return promptly.
+	Recommend(state *AgentState) (*Recommendations, error)
+}
+
+// externalAgentUpdate is an update sent when an external agent has been
+// registered or unregistered, and prompts the agent to make a new assessment.
+type externalAgentUpdate struct{}
+
+// SetExternalAgent registers the external agent that makes the decisions of
+// the agent instead of its heuristic. Passing nil restores the heuristic.
+func (a *Agent) SetExternalAgent(externalAgent ExternalAgent) {
+	a.externalAgentMtx.Lock()
+	a.externalAgent = externalAgent
+	a.externalAgentMtx.Unlock()
+
+	select {
+	case a.externalAgentUpdates <- &externalAgentUpdate{}:
+	default:
+	}
+}
+
+// getExternalAgent returns the registered external agent, if any.
+func (a *Agent) getExternalAgent() ExternalAgent {
+	a.externalAgentMtx.Lock()
+	defer a.externalAgentMtx.Unlock()
+
+	return a.externalAgent
+}
+
+// executeRecommendations hands the current state of the node to the external
+// agent, and executes its recommendations within the given budget.
+func (a *Agent) executeRecommendations(externalAgent ExternalAgent,
+	availableFunds btcutil.Amount, numChans uint32,
+	totalChans []LocalChannel) error {
+
+	recs, err := externalAgent.Recommend(&AgentState{
+		Graph:          a.cfg.Graph,
+		Channels:       totalChans,
+		WalletBalance:  a.totalBalance,
+		AvailableFunds: availableFunds,
+		NumChans:       numChans,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to get recommendations: %w", err)
+	}
+
+	log.Debugf("External agent recommended %d channel opens and %d "+
+		"channel closes", len(recs.Opens), len(recs.Closes))
+
+	a.executeOpens(recs.Opens, availableFunds, numChans)
+	a.executeCloses(recs.Closes)
+
+	return nil
+}
+
+// executeOpens attempts to open the recommended channels that fit the given
+// budget, skipping the nodes we already have or are opening channels with.
+func (a *Agent) executeOpens(opens []AttachmentDirective,
+	availableFunds btcutil.Amount, numChans uint32) {
+
+	a.chanStateMtx.Lock()
+	connectedNodes := a.chanState.ConnectedNodes()
+	a.chanStateMtx.Unlock()
+
+	a.pendingMtx.Lock()
+	defer a.pendingMtx.Unlock()
+
+	if uint16(len(a.pendingOpens)) >= a.cfg.Constraints.MaxPendingOpens() {
+		log.Debugf("Reached cap of %v pending channel opens, will "+
+			"retry after success/failure",
+			a.cfg.Constraints.MaxPendingOpens())
+		return
+	}
+
+	nodesToSkip := mergeNodeMaps(a.pendingOpens,
+		a.pendingConns, connectedNodes, a.failedNodes,
+	)
+	nodesToSkip[NewNodeID(a.cfg.Self)] = struct{}{}
+
+	for _, directive := range opens {
+		if numChans == 0 {
+			log.Debugf("No budget left to open recommended " +
+				"channels")
+			return
+		}
+
+		nodeID := directive.NodeID
+		if _, ok := nodesToSkip[nodeID]; ok {
+			log.Debugf("Skipping recommended node %x", nodeID[:])
+			continue
+		}
+
+		// We'll reduce the size of channels that exceed our budget,
+		// but won't open channels below our minimum size.
+		chanAmt := min(
+			directive.ChanAmt, a.cfg.Constraints.MaxChanSize(),
+			availableFunds,
+		)
+		if chanAmt < a.cfg.Constraints.MinChanSize() {
+			log.Debugf("Skipping recommended channel to %x of "+
+				"%v, as the min channel size is %v",
+				nodeID[:], chanAmt,
+				a.cfg.Constraints.MinChanSize())
+			continue
+		}
+
+		availableFunds -= chanAmt
+		numChans--
+
+		directive.ChanAmt = chanAmt
+		nodesToSkip[nodeID] = struct{}{}
+		a.pendingConns[nodeID] = struct{}{}
+
+		log.Infof("Executing recommended channel open: %v",
+			spew.Sdump(directive))
+
+		a.wg.Add(1)
+		go a.executeDirective(directive)
+	}
+}
+
+// executeCloses attempts to close the recommended channels, as long as the
+// number of pending closes stays within our limit.
+func (a *Agent) executeCloses(closes []wire.OutPoint) {
+	if len(closes) == 0 {
+		return
+	}
+
+	a.chanStateMtx.Lock()
+	chans := make(map[wire.OutPoint]LocalChannel, len(a.chanState))
+	for _, channel := range a.chanState {
+		chans[channel.ChanPoint] = channel
+	}
+	a.chanStateMtx.Unlock()
+
+	a.pendingMtx.Lock()
+	defer a.pendingMtx.Unlock()
+
+	for _, chanPoint := range closes {
+		channel, ok := chans[chanPoint]
+		if !ok {
+			log.Warnf("Skipping recommended close of unknown "+
+				"channel %v", chanPoint)
+			continue
+		}
+
+		if _, ok := a.pendingCloses[channel.ChanID]; ok {
+			continue
+		}
+
+		if uint16(len(a.pendingCloses)) >= a.cfg.MaxPendingCloses {
+			log.Debugf("Reached cap of %v pending channel "+
+				"closes, will retry after success/failure",
+				a.cfg.MaxPendingCloses)
+			return
+		}
+
+		a.pendingCloses[channel.ChanID] = struct{}{}
+
+		log.Infof("Executing recommended close of channel %v",
+			chanPoint)
+
+		a.wg.Add(1)
+		go a.executeClose(channel)
+	}
+}
+
+// executeClose attempts to cooperatively close the given channel.
+//
+// NOTE: MUST be run as a goroutine.
+func (a *Agent) executeClose(channel LocalChannel) {
+	defer a.wg.Done()
+
+	// To ensure a call to CloseChannel doesn't block the agent from
+	// shutting down, we'll launch it in a non-waitgrouped goroutine, that
+	// will signal when a result is returned.
+	errChan := make(chan error, 1)
+	go func() {
+		chanPoint := channel.ChanPoint
+		errChan <- a.cfg.ChanController.CloseChannel(&chanPoint)
+	}()
+
+	var err error
+	select {
+	case err = <-errChan:
+	case <-a.quit:
+		return
+	}
+
+	if err == nil {
+		return
+	}
+
+	log.Warnf("Unable to close channel %v: %v", channel.ChanPoint, err)
+
+	// As the attempt failed, we'll clear the channel from the set of
+	// pending closes, so it can be recommended again.
+	a.pendingMtx.Lock()
+	delete(a.pendingCloses, channel.ChanID)
+	a.pendingMtx.Unlock()
+}
@@ -0,0 +1,184 @@
+package autopilot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// mockExternalAgent is an external agent that hands the states it receives to
+// the test, and returns the recommendations given by the test.
+type mockExternalAgent struct {
+	states chan *AgentState
+	recs   chan *Recommendations
+	quit   chan struct{}
+}
+
+func (m *mockExternalAgent) Recommend(state *AgentState) (*Recommendations,
+	error) {
+
+	select {
+	case m.states <- state:
+	case <-m.quit:
+		return nil, errors.New("exiting")
+	}
+
+	select {
+	case recs := <-m.recs:
+		return recs, nil
+	case <-m.quit:
+		return nil, errors.New("exiting")
+	}
+}
+
+var _ ExternalAgent = (*mockExternalAgent)(nil)
+
+// respondRecommendations consumes the state handed to the external agent and
+// responds with the given recommendations.
+func respondRecommendations(t *testing.T, externalAgent *mockExternalAgent,
+	recs *Recommendations) *AgentState {
+
+	t.Helper()
+
+	var state *AgentState
+	select {
+	case state = <-externalAgent.states:
+	case <-time.After(time.Second * 3):
+		t.Fatalf("external agent wasn't queried in time")
+	}
+
+	select {
+	case externalAgent.recs <- recs:
+	case <-time.After(time.Second * 3):
+		t.Fatalf("recommendations weren't sent in time")
+	}
+
+	return state
+}
+
+// TestAgentExternalAgent tests that the recommendations of a registered
+// external agent are executed within the budget of the agent.
+func TestAgentExternalAgent(t *testing.T) {
+	t.Parallel()
+
+	localChan := LocalChannel{
+		ChanID:    lnwire.NewShortChanIDFromInt(1),
+		Balance:   btcutil.SatoshiPerBitcoin,
+		ChanPoint: wire.OutPoint{Index: 1},
+	}
+	testCtx := setup(t, []LocalChannel{localChan})
+	chanController := testCtx.chanController.(*mockChanController)
+
+	pub1, err := testCtx.graph.addRandNode()
+	require.NoError(t, err)
+	pub2, err := testCtx.graph.addRandNode()
+	require.NoError(t, err)
+
+	// We'll register an external agent, which the agent will consult
+	// instead of its heuristic. Both its initial check and the
+	// registration trigger the agent to consult it, so we'll send an
+	// initial "no" response to advance the agent past one of them.
+	externalAgent := &mockExternalAgent{
+		states: make(chan *AgentState),
+		recs:   make(chan *Recommendations),
+		quit:   testCtx.quit,
+	}
+	testCtx.agent.SetExternalAgent(externalAgent)
+
+	respondMoreChans(t, testCtx, moreChansResp{0, 0})
+	respondRecommendations(t, externalAgent, &Recommendations{})
+
+	// The agent should hand its current state and budget to the external
+	// agent.
+	respondMoreChans(t, testCtx, moreChansResp{
+		numMore: 1,
+		amt:     2 * btcutil.SatoshiPerBitcoin,
+	})
+
+	// The external agent recommends opening channels to ourselves and to
+	// two nodes, although the budget only allows a single one, and to
+	// close a known and an unknown channel.
+	state := respondRecommendations(t, externalAgent, &Recommendations{
+		Opens: []AttachmentDirective{
+			{
+				NodeID:  NewNodeID(testCtx.agent.cfg.Self),
+				ChanAmt: btcutil.SatoshiPerBitcoin,
+			},
+			{
+				NodeID:  NewNodeID(pub1),
+				ChanAmt: 5 * btcutil.SatoshiPerBitcoin,
+			},
+			{
+				NodeID:  NewNodeID(pub2),
+				ChanAmt: btcutil.SatoshiPerBitcoin,
+			},
+		},
+		Closes: []wire.OutPoint{
+			localChan.ChanPoint,
+			{Index: 2},
+		},
+	})
+	require.Equal(t, []LocalChannel{localChan}, state.Channels)
+	require.EqualValues(t, 2*btcutil.SatoshiPerBitcoin, state.AvailableFunds)
+	require.EqualValues(t, 1, state.NumChans)
+
+	// Only the channel to the first node should be opened, reduced to the
+	// max channel size.
+	select {
+	case intent := <-chanController.openChanSignals:
+		require.Equal(t, pub1, intent.target)
+		require.Equal(
+			t, testCtx.constraints.MaxChanSize(), intent.amt,
+		)
+
+	case <-time.After(time.Second * 3):
+		t.Fatalf("channel not opened in time")
+	}
+
+	select {
+	case chanPoint := <-chanController.closeChanSignals:
+		require.Equal(t, localChan.ChanPoint, chanPoint)
+
+	case <-time.After(time.Second * 3):
+		t.Fatalf("channel not closed in time")
+	}
+
+	select {
+	case intent := <-chanController.openChanSignals:
+		t.Fatalf("unexpected channel open to %x",
+			intent.target.SerializeCompressed())
+
+	case chanPoint := <-chanController.closeChanSignals:
+		t.Fatalf("unexpected channel close of %v", chanPoint)
+
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// A channel pending close isn't closed again when recommended once
+	// more.
+	testCtx.agent.OnHeuristicUpdate(testCtx.heuristic)
+	respondMoreChans(t, testCtx, moreChansResp{0, 0})
+	respondRecommendations(t, externalAgent, &Recommendations{
+		Closes: []wire.OutPoint{localChan.ChanPoint},
+	})
+
+	select {
+	case chanPoint := <-chanController.closeChanSignals:
+		t.Fatalf("unexpected channel close of %v", chanPoint)
+
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// Once unregistered, the agent consults its heuristic again.
+	testCtx.agent.SetExternalAgent(nil)
+	respondMoreChans(t, testCtx, moreChansResp{
+		numMore: 1,
+		amt:     btcutil.SatoshiPerBitcoin,
+	})
+	respondNodeScores(t, testCtx, map[NodeID]*NodeScore{})
+}
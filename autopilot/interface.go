@@ -50,6 +50,10 @@ type LocalChannel struct {
 	// Node is the peer that this channel has been established with.
 	Node NodeID
 
+	// ChanPoint is the funding outpoint of the channel. It is unset for
+	// channels pending open by the agent.
+	ChanPoint wire.OutPoint
+
 	// TODO(roasbeef): also add other traits?
 	//  * fee, timelock, etc
 }
@@ -53,6 +53,14 @@ type Manager struct {
 	// disabled.
 	pilot *Agent
 
+	// externalAgent is the external agent that drives the autopilot agent
+	// instead of its heuristic, if one is registered.
+	externalAgent ExternalAgent
+
+	// externalAgentID identifies the registration of the current external
+	// agent, so outdated registrations can't unregister it.
+	externalAgentID uint64
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 	sync.Mutex
@@ -119,6 +127,8 @@ func (m *Manager) StartAgent() error {
 		return err
 	}
 
+	pilot.SetExternalAgent(m.externalAgent)
+
 	if err := pilot.Start(); err != nil {
 		return err
 	}
@@ -265,6 +275,73 @@ func (m *Manager) StopAgent() error {
 	return nil
 }
 
+// RegisterExternalAgent registers an external agent that decides which
+// channels the autopilot agent opens and closes instead of its heuristic. Only
+// a single external agent can be registered at a time. The returned function
+// unregisters the agent again, restoring the heuristic.
+func (m *Manager) RegisterExternalAgent(externalAgent ExternalAgent) (func(),
+	error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.externalAgent != nil {
+		return nil, ErrExternalAgentRegistered
+	}
+
+	m.externalAgentID++
+	id := m.externalAgentID
+
+	m.setExternalAgent(externalAgent)
+
+	log.Infof("Registered external autopilot agent")
+
+	unregister := func() {
+		m.Lock()
+		defer m.Unlock()
+
+		if m.externalAgentID != id || m.externalAgent == nil {
+			return
+		}
+
+		m.setExternalAgent(nil)
+
+		log.Infof("Unregistered external autopilot agent")
+	}
+
+	return unregister, nil
+}
+
+// setExternalAgent sets the external agent of the Manager and its active
+// autopilot agent.
+//
+// NOTE: Must be called with the manager's lock.
+func (m *Manager) setExternalAgent(externalAgent ExternalAgent) {
+	m.externalAgent = externalAgent
+	if m.pilot != nil {
+		m.pilot.SetExternalAgent(externalAgent)
+	}
+}
+
+// SetPilotConfig replaces the config autopilot agents are created from. If an
+// agent is active, it is restarted with the new config.
+func (m *Manager) SetPilotConfig(cfg *Config) error {
+	active := m.IsActive()
+	if err := m.StopAgent(); err != nil {
+		return err
+	}
+
+	m.Lock()
+	m.cfg.PilotCfg = cfg
+	m.Unlock()
+
+	if !active {
+		return nil
+	}
+
+	return m.StartAgent()
+}
+
 // QueryHeuristics queries the available autopilot heuristics for node scores.
 func (m *Manager) QueryHeuristics(nodes []NodeID, localState bool) (
 	HeuristicScores, error) {
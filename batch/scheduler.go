@@ -11,11 +11,13 @@ import (
 // horizon. When the first request is received, a TimeScheduler waits a
 // configurable duration for other concurrent requests to join the batch. Once
 // this time has elapsed, the batch is closed and executed. Subsequent requests
-// are then added to a new batch which undergoes the same process.
+// are then added to a new batch which undergoes the same process. A batch that
+// reaches the maximum size is closed and executed right away.
 type TimeScheduler struct {
 	db       kvdb.Backend
 	locker   sync.Locker
 	duration time.Duration
+	maxSize  int
 
 	mu sync.Mutex
 	b  *batch
@@ -25,14 +27,17 @@ type TimeScheduler struct {
 // which to schedule batches. If the operation needs to modify a higher-level
 // cache, the cache's lock should be provided to so that external consistency
 // can be maintained, as successful db operations will cause a request's
-// OnCommit method to be executed while holding this lock.
+// OnCommit method to be executed while holding this lock. If maxSize is
+// positive, batches are executed as soon as they hold that many requests,
+// which bounds the size of their transactions.
 func NewTimeScheduler(db kvdb.Backend, locker sync.Locker,
-	duration time.Duration) *TimeScheduler {
+	duration time.Duration, maxSize int) *TimeScheduler {
 
 	return &TimeScheduler{
 		db:       db,
 		locker:   locker,
 		duration: duration,
+		maxSize:  maxSize,
 	}
 }
 
@@ -62,8 +67,15 @@ func (s *TimeScheduler) Execute(r *Request) error {
 	s.b.reqs = append(s.b.reqs, &req)
 
 	// If this is a non-lazy request, we'll execute the batch immediately.
-	if !r.lazy {
+	// The same goes for a full batch, which we also close so that the
+	// next request starts a new one.
+	switch {
+	case !r.lazy:
 		go s.b.trigger()
+
+	case s.maxSize > 0 && len(s.b.reqs) >= s.maxSize:
+		go s.b.trigger()
+		s.b = nil
 	}
 
 	s.mu.Unlock()
@@ -0,0 +1,59 @@
+package batch
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTimeSchedulerMaxSize tests that batches of lazy requests are committed
+// without waiting for the batch duration once they reach the maximum size.
+func TestTimeSchedulerMaxSize(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "batch")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	const maxSize = 3
+	scheduler := NewTimeScheduler(db, nil, time.Hour, maxSize)
+
+	// As the batch duration is far longer than the test, the requests can
+	// only complete if their batches are executed once full.
+	var wg sync.WaitGroup
+	for i := 0; i < 2*maxSize; i++ {
+		req := &Request{
+			Update: func(tx kvdb.RwTx) error {
+				_, err := tx.CreateTopLevelBucket(
+					[]byte("bucket"),
+				)
+
+				return err
+			},
+		}
+		LazyAdd()(req)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			require.NoError(t, scheduler.Execute(req))
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("batches weren't committed")
+	}
+
+}
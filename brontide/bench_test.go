@@ -30,8 +30,9 @@ func BenchmarkReadHeaderAndBody(t *testing.B) {
 	err = noiseRemoteConn.WriteMessage(msg)
 	require.NoError(t, err, "unable to write encrypted message: %v", err)
 
-	cipherHeader := noiseRemoteConn.noise.nextHeaderSend
-	cipherMsg := noiseRemoteConn.noise.nextBodySend
+	pendingSend := noiseRemoteConn.noise.pendingSend
+	cipherHeader := pendingSend[:encHeaderSize]
+	cipherMsg := pendingSend[encHeaderSize:]
 
 	var (
 		benchErr error
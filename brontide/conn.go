@@ -30,10 +30,74 @@ type Conn struct {
 // A compile-time assertion to ensure that Conn meets the net.Conn interface.
 var _ net.Conn = (*Conn)(nil)
 
+// HandshakeStage is a stage of establishing a connection with Dial.
+type HandshakeStage uint8
+
+const (
+	// StageConnect is the stage of establishing the underlying TCP
+	// connection, directly or through the Tor proxy.
+	StageConnect HandshakeStage = iota
+
+	// StageActOne is the stage of sending the first act of the handshake.
+	StageActOne
+
+	// StageActTwo is the stage of receiving and verifying the second act
+	// of the handshake. The remote peer closes the connection or doesn't
+	// respond in this stage if the first act wasn't meant for its public
+	// key.
+	StageActTwo
+
+	// StageActThree is the stage of sending the final act of the
+	// handshake.
+	StageActThree
+)
+
+// String returns a human-readable name of the stage.
+func (s HandshakeStage) String() string {
+	switch s {
+	case StageConnect:
+		return "connect"
+
+	case StageActOne:
+		return "act_one"
+
+	case StageActTwo:
+		return "act_two"
+
+	case StageActThree:
+		return "act_three"
+
+	default:
+		return "unknown"
+	}
+}
+
+// HandshakeError is the error returned by Dial, recording the stage of the
+// connection attempt that failed. The error message is the one of the
+// underlying error.
+type HandshakeError struct {
+	// Stage is the stage that failed.
+	Stage HandshakeStage
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the message of the underlying error.
+func (e *HandshakeError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *HandshakeError) Unwrap() error {
+	return e.Err
+}
+
 // Dial attempts to establish an encrypted+authenticated connection with the
 // remote peer located at address which has remotePub as its long-term static
 // public key. In the case of a handshake failure, the connection is closed and
-// a non-nil error is returned.
+// a non-nil error is returned. The error is a *HandshakeError recording the
+// stage that failed.
 func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 	timeout time.Duration, dialer tor.DialFunc) (*Conn, error) {
 
@@ -42,7 +106,7 @@ func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 	var err error
 	conn, err = dialer("tcp", ipAddr, timeout)
 	if err != nil {
-		return nil, err
+		return nil, &HandshakeError{Stage: StageConnect, Err: err}
 	}
 
 	b := &Conn{
@@ -50,15 +114,19 @@ func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 		noise: NewBrontideMachine(true, local, netAddr.IdentityKey),
 	}
 
+	// fail closes the connection and returns the error of the stage.
+	fail := func(stage HandshakeStage, err error) (*Conn, error) {
+		b.conn.Close()
+		return nil, &HandshakeError{Stage: stage, Err: err}
+	}
+
 	// Initiate the handshake by sending the first act to the receiver.
 	actOne, err := b.noise.GenActOne()
 	if err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActOne, err)
 	}
 	if _, err := conn.Write(actOne[:]); err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActOne, err)
 	}
 
 	// We'll ensure that we get ActTwo from the remote peer in a timely
@@ -66,8 +134,7 @@ func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 	// we'll kill the connection.
 	err = conn.SetReadDeadline(time.Now().Add(handshakeReadTimeout))
 	if err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActTwo, err)
 	}
 
 	// If the first act was successful (we know that address is actually
@@ -76,32 +143,27 @@ func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 	// secrecy.
 	var actTwo [ActTwoSize]byte
 	if _, err := io.ReadFull(conn, actTwo[:]); err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActTwo, err)
 	}
 	if err := b.noise.RecvActTwo(actTwo); err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActTwo, err)
 	}
 
 	// Finally, complete the handshake by sending over our encrypted static
 	// key and execute the final ECDH operation.
 	actThree, err := b.noise.GenActThree()
 	if err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActThree, err)
 	}
 	if _, err := conn.Write(actThree[:]); err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActThree, err)
 	}
 
 	// We'll reset the deadline as it's no longer critical beyond the
 	// initial handshake.
 	err = conn.SetReadDeadline(time.Time{})
 	if err != nil {
-		b.conn.Close()
-		return nil, err
+		return fail(StageActThree, err)
 	}
 
 	return b, nil
@@ -217,12 +279,12 @@ func (c *Conn) WriteMessage(b []byte) error {
 	return c.noise.WriteMessage(b)
 }
 
-// Flush attempts to write a message buffered using WriteMessage to the
+// Flush attempts to write all messages buffered using WriteMessage to the
 // underlying connection. If no buffered message exists, this will result in a
 // NOP. Otherwise, it will continue to write the remaining bytes, picking up
 // where the byte stream left off in the event of a partial write. The number of
-// bytes returned reflects the number of plaintext bytes in the payload, and
-// does not account for the overhead of the header or MACs.
+// bytes returned reflects the number of plaintext bytes in the payloads, and
+// does not account for the overhead of the headers or MACs.
 //
 // NOTE: It is safe to call this method again iff a timeout error is returned.
 func (c *Conn) Flush() (int, error) {
@@ -54,6 +54,9 @@ var (
 
 	// ErrMessageNotFlushed signals that the connection cannot accept a new
 	// message because the prior message has not been fully flushed.
+	//
+	// Deprecated: Multiple messages can now be buffered before they are
+	// flushed, so this error is no longer returned.
 	ErrMessageNotFlushed = errors.New("prior message not flushed")
 
 	// lightningPrologue is the noise prologue that is used to initialize
@@ -374,15 +377,24 @@ type Machine struct {
 	// (of the next ciphertext), followed by a 16 byte MAC.
 	nextCipherHeader [encHeaderSize]byte
 
-	// nextHeaderSend holds a reference to the remaining header bytes to
-	// write out for a pending message. This allows us to tolerate timeout
-	// errors that cause partial writes.
-	nextHeaderSend []byte
+	// pendingSend holds the remaining ciphertext bytes to write out for
+	// all pending messages. This allows us to tolerate timeout errors that
+	// cause partial writes.
+	pendingSend []byte
+
+	// pendingFrames tracks the remaining header and body bytes of each
+	// pending message within pendingSend.
+	pendingFrames []pendingFrame
+}
+
+// pendingFrame tracks the number of bytes of a buffered message that still
+// have to be written out.
+type pendingFrame struct {
+	// header is the number of remaining header bytes.
+	header int
 
-	// nextHeaderBody holds a reference to the remaining body bytes to write
-	// out for a pending message. This allows us to tolerate timeout errors
-	// that cause partial writes.
-	nextBodySend []byte
+	// body is the number of remaining body bytes, including the MAC.
+	body int
 }
 
 // NewBrontideMachine creates a new instance of the brontide state-machine. If
@@ -717,7 +729,9 @@ func (b *Machine) split() {
 
 // WriteMessage encrypts and buffers the next message p. The ciphertext of the
 // message is prepended with an encrypt+auth'd length which must be used as the
-// AD to the AEAD construction when being decrypted by the other side.
+// AD to the AEAD construction when being decrypted by the other side. Multiple
+// messages can be buffered before they are flushed, in which case they are
+// written out together by the next call to Flush.
 //
 // NOTE: This DOES NOT write the message to the wire, it should be followed by a
 // call to Flush to ensure the message is written.
@@ -729,13 +743,6 @@ func (b *Machine) WriteMessage(p []byte) error {
 		return ErrMaxMessageLengthExceeded
 	}
 
-	// If a prior message was written but it hasn't been fully flushed,
-	// return an error as we only support buffering of one message at a
-	// time.
-	if len(b.nextHeaderSend) > 0 || len(b.nextBodySend) > 0 {
-		return ErrMessageNotFlushed
-	}
-
 	// The full length of the packet is only the packet length, and does
 	// NOT include the MAC.
 	fullLength := uint16(len(p))
@@ -744,87 +751,81 @@ func (b *Machine) WriteMessage(p []byte) error {
 	binary.BigEndian.PutUint16(pktLen[:], fullLength)
 
 	// First, generate the encrypted+MAC'd length prefix for the packet.
-	b.nextHeaderSend = b.sendCipher.Encrypt(nil, nil, pktLen[:])
+	// Both the header and the packet itself are appended to the
+	// ciphertext of any messages that haven't been flushed yet.
+	b.pendingSend = b.sendCipher.Encrypt(nil, b.pendingSend, pktLen[:])
 
 	// Finally, generate the encrypted packet itself.
-	b.nextBodySend = b.sendCipher.Encrypt(nil, nil, p)
+	b.pendingSend = b.sendCipher.Encrypt(nil, b.pendingSend, p)
+
+	b.pendingFrames = append(b.pendingFrames, pendingFrame{
+		header: encHeaderSize,
+		body:   len(p) + macSize,
+	})
 
 	return nil
 }
 
-// Flush attempts to write a message buffered using WriteMessage to the provided
-// io.Writer. If no buffered message exists, this will result in a NOP.
-// Otherwise, it will continue to write the remaining bytes, picking up where
-// the byte stream left off in the event of a partial write. The number of bytes
-// returned reflects the number of plaintext bytes in the payload, and does not
-// account for the overhead of the header or MACs.
+// Flush attempts to write all messages buffered using WriteMessage to the
+// provided io.Writer in a single write. If no buffered message exists, this
+// will result in a NOP. Otherwise, it will continue to write the remaining
+// bytes, picking up where the byte stream left off in the event of a partial
+// write. The number of bytes returned reflects the number of plaintext bytes
+// in the payloads, and does not account for the overhead of the headers or
+// MACs.
 //
 // NOTE: It is safe to call this method again iff a timeout error is returned.
 func (b *Machine) Flush(w io.Writer) (int, error) {
-	// First, write out the pending header bytes, if any exist. Any header
-	// bytes written will not count towards the total amount flushed.
-	if len(b.nextHeaderSend) > 0 {
-		// Write any remaining header bytes and shift the slice to point
-		// to the next segment of unwritten bytes. If an error is
-		// encountered, we can continue to write the header from where
-		// we left off on a subsequent call to Flush.
-		n, err := w.Write(b.nextHeaderSend)
-		b.nextHeaderSend = b.nextHeaderSend[n:]
-		if err != nil {
-			return 0, err
-		}
+	if len(b.pendingSend) == 0 {
+		return 0, nil
 	}
 
-	// Next, write the pending body bytes, if any exist. Only the number of
-	// bytes written that correspond to the ciphertext will be included in
-	// the total bytes written, bytes written as part of the MAC will not be
-	// counted.
+	// Write out all pending bytes and shift the slice to point to the
+	// next segment of unwritten bytes. If an error is encountered, we can
+	// continue to write from where we left off on a subsequent call to
+	// Flush.
+	n, err := w.Write(b.pendingSend)
+	b.pendingSend = b.pendingSend[n:]
+
+	// Walk through the frames covered by the write to determine the
+	// number of plaintext bytes that were written. Header bytes don't
+	// count towards the total amount flushed.
 	var nn int
-	if len(b.nextBodySend) > 0 {
-		// Write out all bytes excluding the mac and shift the body
-		// slice depending on the number of actual bytes written.
-		n, err := w.Write(b.nextBodySend)
-		b.nextBodySend = b.nextBodySend[n:]
+	for n > 0 && len(b.pendingFrames) > 0 {
+		frame := &b.pendingFrames[0]
+
+		headerWritten := min(n, frame.header)
+		frame.header -= headerWritten
+		n -= headerWritten
 
 		// If we partially or fully wrote any of the body's MAC, we'll
-		// subtract that contribution from the total amount flushed to
-		// preserve the abstraction of returning the number of plaintext
-		// bytes written by the connection.
-		//
-		// There are three possible scenarios we must handle to ensure
-		// the returned value is correct. In the first case, the write
-		// straddles both payload and MAC bytes, and we must subtract
-		// the number of MAC bytes written from n. In the second, only
-		// payload bytes are written, thus we can return n unmodified.
-		// The final scenario pertains to the case where only MAC bytes
-		// are written, none of which count towards the total.
+		// only count the payload bytes written to preserve the
+		// abstraction of returning the number of plaintext bytes
+		// written by the connection.
 		//
 		//                 |-----------Payload------------|----MAC----|
 		// Straddle:       S---------------------------------E--------0
 		// Payload-only:   S------------------------E-----------------0
 		// MAC-only:                                        S-------E-0
-		start, end := n+len(b.nextBodySend), len(b.nextBodySend)
-		switch {
-
-		// Straddles payload and MAC bytes, subtract number of MAC bytes
-		// written from the actual number written.
-		case start > macSize && end <= macSize:
-			nn = n - (macSize - end)
-
-		// Only payload bytes are written, return n directly.
-		case start > macSize && end > macSize:
-			nn = n
-
-		// Only MAC bytes are written, return 0 bytes written.
-		default:
+		bodyWritten := min(n, frame.body)
+		start, end := frame.body, frame.body-bodyWritten
+		nn += max(start-macSize, 0) - max(end-macSize, 0)
+		frame.body = end
+		n -= bodyWritten
+
+		if frame.header == 0 && frame.body == 0 {
+			b.pendingFrames = b.pendingFrames[1:]
 		}
+	}
 
-		if err != nil {
-			return nn, err
-		}
+	// Release the buffer once everything has been written, so we don't
+	// hold on to the memory of a large message.
+	if len(b.pendingSend) == 0 {
+		b.pendingSend = nil
+		b.pendingFrames = nil
 	}
 
-	return nn, nil
+	return nn, err
 }
 
 // ReadMessage attempts to read the next message from the passed io.Reader. In
@@ -662,6 +662,42 @@ func TestFlush(t *testing.T) {
 	})
 }
 
+// TestFlushMultiple asserts that multiple buffered messages are flushed
+// together, and that the number of plaintext bytes is reported correctly if
+// the write stops in the middle of them.
+func TestFlushMultiple(t *testing.T) {
+	var (
+		w bytes.Buffer
+		b Machine
+	)
+	b.split()
+
+	const numMsgs = 3
+	payload := make([]byte, payloadSize)
+	for i := 0; i < numMsgs; i++ {
+		require.NoError(t, b.WriteMessage(payload))
+	}
+
+	// Write the first message and the header and half of the payload of
+	// the second message.
+	frameSize := encHeaderSize + payloadSize + macSize
+	errAfter := int64(frameSize + encHeaderSize + payloadSize/2)
+	assertFlush(
+		t, &b, &w, errAfter, payloadSize+payloadSize/2,
+		iotest.ErrTimeout,
+	)
+
+	// Write the remainder in one go.
+	assertFlush(
+		t, &b, &w, -1, numMsgs*payloadSize-payloadSize-payloadSize/2,
+		nil,
+	)
+	require.Equal(t, numMsgs*frameSize, w.Len())
+
+	// Nothing is left to be flushed.
+	assertFlush(t, &b, &w, 0, 0, nil)
+}
+
 // testFlush buffers a message on the Machine, then flushes it to the io.Writer
 // in chunks. Once complete, a final call to flush is made to assert that Write
 // is not called again.
@@ -700,3 +736,39 @@ func assertFlush(t *testing.T, b *Machine, w io.Writer, n int64, expN int,
 		t.Fatalf("expected n: %d, got: %d", expN, nn)
 	}
 }
+
+// TestDialHandshakeStage tests that Dial reports the stage of the connection
+// attempt that failed.
+func TestDialHandshakeStage(t *testing.T) {
+	t.Parallel()
+
+	listener, netAddr, err := makeListener()
+	require.NoError(t, err)
+	defer listener.Close()
+
+	localPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	localKeyECDH := &keychain.PrivKeyECDH{PrivKey: localPriv}
+
+	// A listener with a different key than expected closes the connection
+	// after the first act, so the second act fails.
+	wrongAddr := &lnwire.NetAddress{
+		IdentityKey: localPriv.PubKey(),
+		Address:     netAddr.Address,
+	}
+	_, err = Dial(
+		localKeyECDH, wrongAddr, tor.DefaultConnTimeout,
+		net.DialTimeout,
+	)
+	var hsErr *HandshakeError
+	require.ErrorAs(t, err, &hsErr)
+	require.Equal(t, StageActTwo, hsErr.Stage)
+
+	// Once the listener is closed, the TCP connection fails.
+	require.NoError(t, listener.Close())
+	_, err = Dial(
+		localKeyECDH, netAddr, tor.DefaultConnTimeout, net.DialTimeout,
+	)
+	require.ErrorAs(t, err, &hsErr)
+	require.Equal(t, StageConnect, hsErr.Stage)
+}
@@ -0,0 +1,43 @@
+package lnd
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// newChainInfo combines the sync state of the chain backend with the best
+// block the node has synced to.
+func newChainInfo(backend *chainreg.BackendInfo, bestHash string,
+	bestHeight uint32, bestTime, now time.Time,
+	synced bool) *lnrpc.GetChainInfoResponse {
+
+	info := &lnrpc.GetChainInfoResponse{
+		Backend:              backend.Backend,
+		BlockHeight:          bestHeight,
+		BlockHash:            bestHash,
+		BackendBlockHeight:   backend.BlockHeight,
+		HeaderHeight:         backend.HeaderHeight,
+		FilterHeaderHeight:   backend.FilterHeaderHeight,
+		VerificationProgress: backend.VerificationProgress,
+		BestBlockTimestamp:   bestTime.Unix(),
+		SyncedToChain:        synced,
+	}
+
+	// The backend may still be catching up on the headers, in which case
+	// the lag is measured against the headers it already knows about.
+	tip := backend.HeaderHeight
+	if backend.BlockHeight > tip {
+		tip = backend.BlockHeight
+	}
+	if tip > bestHeight {
+		info.BlockLag = tip - bestHeight
+	}
+
+	if now.After(bestTime) {
+		info.BestBlockAgeSec = int64(now.Sub(bestTime).Seconds())
+	}
+
+	return info
+}
@@ -0,0 +1,99 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewChainInfo tests that the block lag and the best block age are
+// derived correctly from the sync state of the backend and the node.
+func TestNewChainInfo(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	bestTime := now.Add(-30 * time.Minute)
+
+	testCases := []struct {
+		name     string
+		backend  *chainreg.BackendInfo
+		height   uint32
+		bestTime time.Time
+		lag      uint32
+		age      time.Duration
+	}{{
+		name: "synced",
+		backend: &chainreg.BackendInfo{
+			Backend:      "bitcoind",
+			BlockHeight:  100,
+			HeaderHeight: 100,
+		},
+		height:   100,
+		bestTime: bestTime,
+		lag:      0,
+		age:      30 * time.Minute,
+	}, {
+		name: "backend still validating blocks",
+		backend: &chainreg.BackendInfo{
+			Backend:      "bitcoind",
+			BlockHeight:  90,
+			HeaderHeight: 120,
+		},
+		height:   90,
+		bestTime: bestTime,
+		lag:      30,
+		age:      30 * time.Minute,
+	}, {
+		name: "neutrino filter headers behind",
+		backend: &chainreg.BackendInfo{
+			Backend:            "neutrino",
+			BlockHeight:        95,
+			HeaderHeight:       100,
+			FilterHeaderHeight: 95,
+		},
+		height:   95,
+		bestTime: bestTime,
+		lag:      5,
+		age:      30 * time.Minute,
+	}, {
+		name: "node ahead of backend",
+		backend: &chainreg.BackendInfo{
+			Backend:      "btcd",
+			BlockHeight:  99,
+			HeaderHeight: 99,
+		},
+		height:   100,
+		bestTime: now.Add(time.Minute),
+		lag:      0,
+		age:      0,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := newChainInfo(
+				tc.backend, "hash", tc.height, tc.bestTime,
+				now, tc.lag == 0,
+			)
+
+			require.Equal(t, tc.backend.Backend, info.Backend)
+			require.Equal(t, tc.height, info.BlockHeight)
+			require.Equal(
+				t, tc.backend.BlockHeight,
+				info.BackendBlockHeight,
+			)
+			require.Equal(t, tc.lag, info.BlockLag)
+			require.EqualValues(
+				t, tc.age.Seconds(), info.BestBlockAgeSec,
+			)
+			require.Equal(
+				t, tc.bestTime.Unix(), info.BestBlockTimestamp,
+			)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package chainreg
+
+import (
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/lightninglabs/neutrino"
+)
+
+// BackendInfo describes how far the chain backend has synced the chain.
+type BackendInfo struct {
+	// Backend is the type of the chain backend.
+	Backend string
+
+	// BlockHeight is the height of the best block the backend has fully
+	// processed.
+	BlockHeight uint32
+
+	// HeaderHeight is the height of the best block header the backend
+	// knows about.
+	HeaderHeight uint32
+
+	// FilterHeaderHeight is the height of the best compact filter header
+	// the backend knows about. It is only set for neutrino.
+	FilterHeaderHeight uint32
+
+	// VerificationProgress is the estimated fraction of the chain the
+	// backend has verified, between 0 and 1. It is zero if the backend
+	// doesn't report it.
+	VerificationProgress float64
+}
+
+// neutrinoBackendInfo returns the sync state of the neutrino light client.
+// Neutrino only considers a block synced once it has both its header and its
+// filter header, so the block height is the lower of the two.
+func neutrinoBackendInfo(cs *neutrino.ChainService) (*BackendInfo, error) {
+	_, headerHeight, err := cs.BlockHeaders.ChainTip()
+	if err != nil {
+		return nil, err
+	}
+
+	_, filterHeight, err := cs.RegFilterHeaders.ChainTip()
+	if err != nil {
+		return nil, err
+	}
+
+	blockHeight := headerHeight
+	if filterHeight < blockHeight {
+		blockHeight = filterHeight
+	}
+
+	return &BackendInfo{
+		Backend:            "neutrino",
+		BlockHeight:        blockHeight,
+		HeaderHeight:       headerHeight,
+		FilterHeaderHeight: filterHeight,
+	}, nil
+}
+
+// rpcBackendInfo returns the sync state of a full node backend that supports
+// the getblockchaininfo RPC.
+func rpcBackendInfo(backend string,
+	client *rpcclient.Client) (*BackendInfo, error) {
+
+	info, err := client.GetBlockChainInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendInfo{
+		Backend:              backend,
+		BlockHeight:          uint32(info.Blocks),
+		HeaderHeight:         uint32(info.Headers),
+		VerificationProgress: info.VerificationProgress,
+	}, nil
+}
@@ -45,6 +45,27 @@ var BitcoinSigNetParams = BitcoinNetParams{
 	CoinType: keychain.CoinTypeTestnet,
 }
 
+// CustomSigNetParams returns the parameters of a custom signet network defined
+// by the given block challenge and seed nodes. If a genesis block is given, it
+// replaces the default signet genesis block. This allows private signets to
+// use a chain hash that is distinct from the global default signet, which is
+// needed to keep their gossip, invoices and watchtower sessions apart.
+func CustomSigNetParams(challenge []byte, seeds []bitcoinCfg.DNSSeed,
+	genesis *bitcoinWire.MsgBlock) BitcoinNetParams {
+
+	chainParams := bitcoinCfg.CustomSignetParams(challenge, seeds)
+	if genesis != nil {
+		genesisHash := genesis.BlockHash()
+		chainParams.GenesisBlock = genesis
+		chainParams.GenesisHash = &genesisHash
+	}
+
+	params := BitcoinSigNetParams
+	params.Params = &chainParams
+
+	return params
+}
+
 // BitcoinRegTestNetParams contains parameters specific to a local bitcoin
 // regtest network.
 var BitcoinRegTestNetParams = BitcoinNetParams{
@@ -0,0 +1,45 @@
+package chainreg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCustomSigNetParams tests that the parameters of a custom signet use the
+// given challenge and genesis block without modifying the default signet
+// parameters.
+func TestCustomSigNetParams(t *testing.T) {
+	t.Parallel()
+
+	challenge := []byte{0x51}
+	seeds := []chaincfg.DNSSeed{{Host: "seed.example.com"}}
+
+	// Without a genesis block, the custom signet shares the chain hash of
+	// the default signet but uses its own network magic.
+	params := CustomSigNetParams(challenge, seeds, nil)
+	require.Equal(t, chaincfg.SigNetParams.GenesisHash, params.GenesisHash)
+	require.NotEqual(t, chaincfg.SigNetParams.Net, params.Net)
+	require.Equal(t, seeds, params.DNSSeeds)
+	require.Equal(t, BitcoinSigNetParams.RPCPort, params.RPCPort)
+	require.Equal(t, BitcoinSigNetParams.CoinType, params.CoinType)
+
+	// With a custom genesis block, the chain hash is the hash of that
+	// block.
+	genesis := *chaincfg.SigNetParams.GenesisBlock
+	genesis.Header.Timestamp = time.Unix(1700000000, 0)
+	genesisHash := genesis.BlockHash()
+
+	params = CustomSigNetParams(challenge, seeds, &genesis)
+	require.Equal(t, &genesisHash, params.GenesisHash)
+	require.Equal(t, &genesis, params.GenesisBlock)
+
+	// The default signet parameters must be left untouched.
+	require.Equal(
+		t, chaincfg.SigNetParams.GenesisHash,
+		BitcoinSigNetParams.GenesisHash,
+	)
+	require.Equal(t, chaincfg.SigNetParams.Net, BitcoinSigNetParams.Net)
+}
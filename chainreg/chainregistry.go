@@ -89,6 +89,10 @@ type Config struct {
 	// TCP connections to Bitcoin peers in the event of a pruned block being
 	// requested.
 	Dialer chain.Dialer
+
+	// FeeDialer is a function closure that will be used to establish the
+	// connections to the web fee estimator. This field is optional.
+	FeeDialer func(network, addr string) (net.Conn, error)
 }
 
 const (
@@ -146,6 +150,10 @@ type PartialChainControl struct {
 	// node.
 	HealthCheck func() error
 
+	// BackendInfo is a function which returns how far the chain backend
+	// has synced the chain.
+	BackendInfo func() (*BackendInfo, error)
+
 	// FeeEstimator is used to estimate an optimal fee for transactions
 	// important to us.
 	FeeEstimator chainfee.Estimator
@@ -222,6 +230,11 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			BaseFee:       cfg.Bitcoin.BaseFee,
 			FeeRate:       cfg.Bitcoin.FeeRate,
 			TimeLockDelta: cfg.Bitcoin.TimeLockDelta,
+
+			InboundFee: models.InboundFee{
+				Base: cfg.Bitcoin.InboundBaseFee,
+				Rate: cfg.Bitcoin.InboundFeeRate,
+			},
 		},
 		MinHtlcIn: cfg.Bitcoin.MinHTLCIn,
 		FeeEstimator: chainfee.NewStaticEstimator(
@@ -284,6 +297,9 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			_, _, err := cc.ChainSource.GetBestBlock()
 			return err
 		}
+		cc.BackendInfo = func() (*BackendInfo, error) {
+			return neutrinoBackendInfo(cfg.NeutrinoCS)
+		}
 
 	case "bitcoind":
 		bitcoindMode := cfg.BitcoindMode
@@ -527,6 +543,9 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			// number of outbound peers.
 			return checkOutboundPeers(chainConn)
 		}
+		cc.BackendInfo = func() (*BackendInfo, error) {
+			return rpcBackendInfo("bitcoind", chainConn)
+		}
 
 	case "btcd":
 		// Otherwise, we'll be speaking directly via RPC to a node.
@@ -650,6 +669,9 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			// number of outbound peers.
 			return checkOutboundPeers(chainRPC.Client)
 		}
+		cc.BackendInfo = func() (*BackendInfo, error) {
+			return rpcBackendInfo("btcd", chainConn)
+		}
 
 		// If we're not in simnet or regtest mode, then we'll attempt
 		// to use a proper fee estimator for testnet.
@@ -683,6 +705,11 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		cc.HealthCheck = func() error {
 			return nil
 		}
+		cc.BackendInfo = func() (*BackendInfo, error) {
+			return &BackendInfo{
+				Backend: "nochainbackend",
+			}, nil
+		}
 
 	default:
 		return nil, nil, fmt.Errorf("unknown node type: %s",
@@ -715,7 +742,8 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 
 		cc.FeeEstimator, err = chainfee.NewWebAPIEstimator(
 			chainfee.SparseConfFeeSource{
-				URL: cfg.Fee.URL,
+				URL:  cfg.Fee.URL,
+				Dial: cfg.FeeDialer,
 			},
 			!cacheFees,
 			cfg.Fee.MinUpdateTimeout,
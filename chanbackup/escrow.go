@@ -0,0 +1,242 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// EscrowBundleVersion denotes the version of the escrow bundle.
+type EscrowBundleVersion byte
+
+const (
+	// DefaultEscrowBundleVersion is the default version of the escrow
+	// bundle. The serialized format for this version is simply: version ||
+	// nodeIDHash || packedMulti.
+	DefaultEscrowBundleVersion = 0
+
+	// maxEscrowBundleSize is the largest escrow bundle we'll fetch from a
+	// remote endpoint.
+	maxEscrowBundleSize = 10 * 1024 * 1024
+)
+
+// EscrowBundle is the static channel backup of a node as it is stored with a
+// remote escrow endpoint. Next to the packed multi-channel backup, which is
+// encrypted with a key derived from the node's seed, it holds the hash of the
+// node's identity key. This allows checking that a seed restores the node the
+// bundle belongs to without a passphrase and without revealing the node's
+// identity to the endpoint.
+type EscrowBundle struct {
+	// Version is the version of the bundle.
+	Version EscrowBundleVersion
+
+	// NodeIDHash is the sha256 hash of the compressed identity public key
+	// of the node that created the bundle.
+	NodeIDHash [sha256.Size]byte
+
+	// Multi is the packed multi-channel backup of the node.
+	Multi PackedMulti
+}
+
+// NewEscrowBundle creates a new escrow bundle of the given packed multi backup
+// for the node with the given identity key.
+func NewEscrowBundle(nodeID *btcec.PublicKey,
+	multi PackedMulti) *EscrowBundle {
+
+	return &EscrowBundle{
+		Version:    DefaultEscrowBundleVersion,
+		NodeIDHash: sha256.Sum256(nodeID.SerializeCompressed()),
+		Multi:      multi,
+	}
+}
+
+// MatchesNode returns true if the bundle was created by the node with the
+// given identity key.
+func (b *EscrowBundle) MatchesNode(nodeID *btcec.PublicKey) bool {
+	return b.NodeIDHash == sha256.Sum256(nodeID.SerializeCompressed())
+}
+
+// Encode writes the serialized bundle to the given writer.
+func (b *EscrowBundle) Encode(w io.Writer) error {
+	if b.Version != DefaultEscrowBundleVersion {
+		return fmt.Errorf("unable to encode unknown escrow bundle "+
+			"version %v", b.Version)
+	}
+
+	if _, err := w.Write([]byte{byte(b.Version)}); err != nil {
+		return err
+	}
+	if _, err := w.Write(b.NodeIDHash[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(b.Multi)
+
+	return err
+}
+
+// DecodeEscrowBundle reads a serialized bundle from the given reader.
+func DecodeEscrowBundle(r io.Reader) (*EscrowBundle, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, err
+	}
+
+	b := &EscrowBundle{
+		Version: EscrowBundleVersion(version[0]),
+	}
+	if b.Version != DefaultEscrowBundleVersion {
+		return nil, fmt.Errorf("unable to decode unknown escrow "+
+			"bundle version %v", b.Version)
+	}
+
+	if _, err := io.ReadFull(r, b.NodeIDHash[:]); err != nil {
+		return nil, err
+	}
+
+	multi, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(multi) < NilMultiSizePacked {
+		return nil, fmt.Errorf("escrow bundle holds no multi backup")
+	}
+	b.Multi = multi
+
+	return b, nil
+}
+
+// EscrowEndpoint is a remote HTTP endpoint escrow bundles are stored with. The
+// bundle is uploaded with a PUT request to the endpoint's URL and retrieved
+// with a GET request to the same URL.
+type EscrowEndpoint struct {
+	// URL is the URL of the endpoint. Credentials can be passed as part
+	// of the URL.
+	URL string
+
+	// Timeout is the time a single request to the endpoint may take.
+	Timeout time.Duration
+}
+
+// Push uploads the given bundle to the endpoint, replacing the previous one.
+func (e *EscrowEndpoint) Push(ctx context.Context, b *EscrowBundle) error {
+	var buf bytes.Buffer
+	if err := b.Encode(&buf); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPut, e.URL, &buf,
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("escrow endpoint returned status %v",
+			resp.Status)
+	}
+
+	return nil
+}
+
+// Fetch retrieves the current bundle from the endpoint.
+func (e *EscrowEndpoint) Fetch(ctx context.Context) (*EscrowBundle, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, e.URL, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("escrow endpoint returned status %v",
+			resp.Status)
+	}
+
+	return DecodeEscrowBundle(
+		io.LimitReader(resp.Body, maxEscrowBundleSize),
+	)
+}
+
+// EscrowSwapper is a Swapper that pushes every new multi backup to an escrow
+// endpoint after it was stored by the wrapped Swapper. A failed push doesn't
+// fail the update, as the backup is still stored locally. The next update
+// pushes the complete backup again.
+type EscrowSwapper struct {
+	Swapper
+
+	nodeID   *btcec.PublicKey
+	endpoint *EscrowEndpoint
+}
+
+// A compile-time assertion to ensure that EscrowSwapper meets the Swapper
+// interface.
+var _ Swapper = (*EscrowSwapper)(nil)
+
+// NewEscrowSwapper creates a new EscrowSwapper that pushes the backups of the
+// node with the given identity key to the endpoint.
+func NewEscrowSwapper(swapper Swapper, nodeID *btcec.PublicKey,
+	endpoint *EscrowEndpoint) *EscrowSwapper {
+
+	return &EscrowSwapper{
+		Swapper:  swapper,
+		nodeID:   nodeID,
+		endpoint: endpoint,
+	}
+}
+
+// UpdateAndSwap stores the new backup with the wrapped Swapper, then pushes
+// it to the escrow endpoint.
+//
+// NOTE: This is part of the Swapper interface.
+func (e *EscrowSwapper) UpdateAndSwap(newBackup PackedMulti) error {
+	if err := e.Swapper.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	bundle := NewEscrowBundle(e.nodeID, newBackup)
+	err := e.endpoint.Push(context.Background(), bundle)
+	if err != nil {
+		log.Errorf("Unable to push backup to escrow endpoint: %v", err)
+		return nil
+	}
+
+	log.Infof("Pushed backup to escrow endpoint")
+
+	return nil
+}
+
+// ExtractMulti obtains the current backup from the wrapped Swapper.
+//
+// NOTE: This is part of the Swapper interface.
+func (e *EscrowSwapper) ExtractMulti(keyRing keychain.KeyRing) (*Multi,
+	error) {
+
+	return e.Swapper.ExtractMulti(keyRing)
+}
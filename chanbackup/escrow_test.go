@@ -0,0 +1,147 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEscrowServer is an escrow endpoint that stores the last bundle that
+// was pushed to it.
+type mockEscrowServer struct {
+	mu     sync.Mutex
+	bundle []byte
+	fail   bool
+}
+
+func (m *mockEscrowServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		bundle, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.bundle = bundle
+
+	case http.MethodGet:
+		if m.bundle == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(m.bundle)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// TestEscrowBundleEncodeDecode tests that an escrow bundle can be encoded and
+// decoded again, and that it is matched to the node that created it.
+func TestEscrowBundleEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	multi, err := makeFakePackedMulti()
+	require.NoError(t, err)
+
+	bundle := NewEscrowBundle(nodeKey.PubKey(), multi)
+
+	var b bytes.Buffer
+	require.NoError(t, bundle.Encode(&b))
+
+	decoded, err := DecodeEscrowBundle(&b)
+	require.NoError(t, err)
+	require.Equal(t, bundle, decoded)
+
+	require.True(t, decoded.MatchesNode(nodeKey.PubKey()))
+	require.False(t, decoded.MatchesNode(otherKey.PubKey()))
+
+	// Bundles of an unknown version or without a multi backup are
+	// rejected.
+	_, err = DecodeEscrowBundle(bytes.NewReader([]byte{1}))
+	require.Error(t, err)
+
+	b.Reset()
+	bundle.Multi = nil
+	require.NoError(t, bundle.Encode(&b))
+	_, err = DecodeEscrowBundle(&b)
+	require.Error(t, err)
+}
+
+// TestEscrowSwapper tests that the escrow swapper pushes new backups to the
+// escrow endpoint after storing them locally, and that a failed push doesn't
+// fail the local update.
+func TestEscrowSwapper(t *testing.T) {
+	t.Parallel()
+
+	server := &mockEscrowServer{}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	endpoint := &EscrowEndpoint{
+		URL:     httpServer.URL,
+		Timeout: time.Second,
+	}
+	backupPath := filepath.Join(t.TempDir(), DefaultBackupFileName)
+	swapper := NewEscrowSwapper(
+		NewMultiFile(backupPath), nodeKey.PubKey(), endpoint,
+	)
+
+	// Nothing was pushed yet, so fetching fails.
+	_, err = endpoint.Fetch(context.Background())
+	require.Error(t, err)
+
+	multi, err := makeFakePackedMulti()
+	require.NoError(t, err)
+	require.NoError(t, swapper.UpdateAndSwap(multi))
+	assertBackupMatches(t, backupPath, multi)
+
+	bundle, err := endpoint.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, multi, bundle.Multi)
+	require.True(t, bundle.MatchesNode(nodeKey.PubKey()))
+
+	// If the endpoint fails, the backup is still stored locally and the
+	// endpoint keeps the previous bundle.
+	server.mu.Lock()
+	server.fail = true
+	server.mu.Unlock()
+
+	newMulti, err := makeFakePackedMulti()
+	require.NoError(t, err)
+	require.NoError(t, swapper.UpdateAndSwap(newMulti))
+	assertBackupMatches(t, backupPath, newMulti)
+
+	server.mu.Lock()
+	server.fail = false
+	server.mu.Unlock()
+
+	bundle, err = endpoint.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, multi, bundle.Multi)
+}
@@ -0,0 +1,173 @@
+package lnd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+)
+
+// errChannelNotFrozen is returned when unfreezing a channel that isn't in
+// maintenance mode.
+var errChannelNotFrozen = errors.New("channel is not frozen")
+
+// channelFreezer puts channels into maintenance mode, in which they don't
+// forward HTLCs in either direction and are announced as disabled, while
+// staying open. This is useful before a planned downtime of the peer. Frozen
+// channels can be unfrozen automatically once a given duration elapsed.
+//
+// NOTE: Frozen channels are unfrozen when lnd restarts.
+type channelFreezer struct {
+	// freezeLink stops the link of the given channel from forwarding new
+	// HTLCs.
+	freezeLink func(lnwire.ChannelID)
+
+	// unfreezeLink allows the link of the given channel to forward new
+	// HTLCs again.
+	unfreezeLink func(lnwire.ChannelID)
+
+	// disableChannel announces the given channel as manually disabled.
+	disableChannel func(wire.OutPoint) error
+
+	// enableChannel restores the automatic status management of the given
+	// channel and announces it as enabled if it's active.
+	enableChannel func(wire.OutPoint) error
+
+	// frozen maps the frozen channels to the timers that unfreeze them,
+	// which are nil for channels that aren't unfrozen automatically.
+	frozen map[wire.OutPoint]*time.Timer
+
+	mu sync.Mutex
+}
+
+// newChannelFreezer creates a new channel freezer that uses the given switch
+// and channel status manager to freeze channels.
+func newChannelFreezer(freezeLink, unfreezeLink func(lnwire.ChannelID),
+	chanStatusMgr *netann.ChanStatusManager) *channelFreezer {
+
+	return &channelFreezer{
+		freezeLink:   freezeLink,
+		unfreezeLink: unfreezeLink,
+		disableChannel: func(chanPoint wire.OutPoint) error {
+			return chanStatusMgr.RequestDisable(chanPoint, true)
+		},
+		enableChannel: func(chanPoint wire.OutPoint) error {
+			err := chanStatusMgr.RequestAuto(chanPoint)
+			if err != nil {
+				return err
+			}
+
+			// If the peer is offline, the channel will be enabled
+			// once it reconnects.
+			err = chanStatusMgr.RequestEnable(chanPoint, false)
+			if errors.Is(err, netann.ErrEnableInactiveChan) {
+				return nil
+			}
+
+			return err
+		},
+		frozen: make(map[wire.OutPoint]*time.Timer),
+	}
+}
+
+// Freeze puts the given channel into maintenance mode. If duration is
+// non-zero, the channel is unfrozen automatically once it elapsed. Freezing a
+// frozen channel replaces its unfreeze timer.
+func (c *channelFreezer) Freeze(chanPoint wire.OutPoint,
+	duration time.Duration) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.disableChannel(chanPoint); err != nil {
+		return fmt.Errorf("unable to disable channel %v: %w",
+			chanPoint, err)
+	}
+
+	c.freezeLink(lnwire.NewChanIDFromOutPoint(chanPoint))
+
+	if timer := c.frozen[chanPoint]; timer != nil {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	if duration > 0 {
+		timer = time.AfterFunc(duration, func() {
+			c.unfreezeExpired(chanPoint, timer)
+		})
+	}
+	c.frozen[chanPoint] = timer
+
+	ltndLog.Infof("Froze ChannelPoint(%v) for %v", chanPoint, duration)
+
+	return nil
+}
+
+// Unfreeze takes the given channel out of maintenance mode.
+func (c *channelFreezer) Unfreeze(chanPoint wire.OutPoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer, ok := c.frozen[chanPoint]
+	if !ok {
+		return errChannelNotFrozen
+	}
+
+	if timer != nil {
+		timer.Stop()
+	}
+
+	return c.unfreeze(chanPoint)
+}
+
+// unfreezeExpired unfreezes the given channel once its unfreeze timer fired,
+// unless the channel was unfrozen or frozen again in the meantime.
+func (c *channelFreezer) unfreezeExpired(chanPoint wire.OutPoint,
+	timer *time.Timer) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.frozen[chanPoint] != timer {
+		return
+	}
+
+	if err := c.unfreeze(chanPoint); err != nil {
+		ltndLog.Errorf("Unable to unfreeze ChannelPoint(%v): %v",
+			chanPoint, err)
+	}
+}
+
+// unfreeze takes the given channel out of maintenance mode.
+//
+// NOTE: This MUST be called with the mutex held.
+func (c *channelFreezer) unfreeze(chanPoint wire.OutPoint) error {
+	delete(c.frozen, chanPoint)
+
+	c.unfreezeLink(lnwire.NewChanIDFromOutPoint(chanPoint))
+
+	if err := c.enableChannel(chanPoint); err != nil {
+		return fmt.Errorf("unable to enable channel %v: %w",
+			chanPoint, err)
+	}
+
+	ltndLog.Infof("Unfroze ChannelPoint(%v)", chanPoint)
+
+	return nil
+}
+
+// Stop stops the timers that unfreeze the frozen channels.
+func (c *channelFreezer) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, timer := range c.frozen {
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package lnd
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFrozenChannels records the channels frozen by a channel freezer.
+type mockFrozenChannels struct {
+	mu       sync.Mutex
+	links    map[lnwire.ChannelID]bool
+	disabled map[wire.OutPoint]bool
+}
+
+func (m *mockFrozenChannels) setLink(chanID lnwire.ChannelID, frozen bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.links[chanID] = frozen
+}
+
+func (m *mockFrozenChannels) setDisabled(chanPoint wire.OutPoint,
+	disabled bool) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.disabled[chanPoint] = disabled
+
+	return nil
+}
+
+// isFrozen returns true if both the link and the status of the channel are
+// frozen.
+func (m *mockFrozenChannels) isFrozen(chanPoint wire.OutPoint) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+
+	return m.links[chanID] && m.disabled[chanPoint]
+}
+
+// TestChannelFreezer tests that channels can be frozen and unfrozen, both
+// manually and automatically.
+func TestChannelFreezer(t *testing.T) {
+	t.Parallel()
+
+	channels := &mockFrozenChannels{
+		links:    make(map[lnwire.ChannelID]bool),
+		disabled: make(map[wire.OutPoint]bool),
+	}
+	freezer := &channelFreezer{
+		freezeLink: func(chanID lnwire.ChannelID) {
+			channels.setLink(chanID, true)
+		},
+		unfreezeLink: func(chanID lnwire.ChannelID) {
+			channels.setLink(chanID, false)
+		},
+		disableChannel: func(chanPoint wire.OutPoint) error {
+			return channels.setDisabled(chanPoint, true)
+		},
+		enableChannel: func(chanPoint wire.OutPoint) error {
+			return channels.setDisabled(chanPoint, false)
+		},
+		frozen: make(map[wire.OutPoint]*time.Timer),
+	}
+	t.Cleanup(freezer.Stop)
+
+	chanPoint := wire.OutPoint{Index: 1}
+
+	// A channel that isn't frozen can't be unfrozen.
+	require.ErrorIs(t, freezer.Unfreeze(chanPoint), errChannelNotFrozen)
+
+	// A channel frozen without a duration stays frozen until it's
+	// unfrozen manually.
+	require.NoError(t, freezer.Freeze(chanPoint, 0))
+	require.True(t, channels.isFrozen(chanPoint))
+
+	require.NoError(t, freezer.Unfreeze(chanPoint))
+	require.False(t, channels.isFrozen(chanPoint))
+
+	// Freezing a frozen channel again replaces its unfreeze timer, so the
+	// channel stays frozen after the first duration elapsed.
+	require.NoError(t, freezer.Freeze(chanPoint, 50*time.Millisecond))
+	require.NoError(t, freezer.Freeze(chanPoint, time.Hour))
+	time.Sleep(100 * time.Millisecond)
+	require.True(t, channels.isFrozen(chanPoint))
+
+	// Once the duration elapsed, the channel is unfrozen automatically.
+	require.NoError(t, freezer.Freeze(chanPoint, 50*time.Millisecond))
+	require.Eventually(t, func() bool {
+		return !channels.isFrozen(chanPoint)
+	}, time.Second, 10*time.Millisecond)
+
+	require.ErrorIs(t, freezer.Unfreeze(chanPoint), errChannelNotFrozen)
+}
@@ -3032,11 +3032,24 @@ func (c *OpenChannel) AdvanceCommitChainTail(fwdPkg *FwdPkg,
 			return err
 		}
 
+		// If compaction is enabled, the HTLC entries of the log are
+		// shared with the other logs of the channel.
+		var htlcBucket kvdb.RwBucket
+		if c.Db.parent.compactRevLog {
+			htlcBucket, err = chanBucket.CreateBucketIfNotExists(
+				revocationLogHtlcBucket,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		// With the commitment pointer swapped, we can now add the
 		// revoked (prior) state to the revocation log.
 		err = putRevocationLog(
-			logBucket, &c.RemoteCommitment, ourOutputIndex,
-			theirOutputIndex, c.Db.parent.noRevLogAmtData,
+			logBucket, htlcBucket, &c.RemoteCommitment,
+			ourOutputIndex, theirOutputIndex,
+			c.Db.parent.noRevLogAmtData,
 		)
 		if err != nil {
 			return err
@@ -3817,7 +3830,11 @@ func putChannelCloseSummary(tx kvdb.RwTx, chanID []byte,
 		return err
 	}
 
-	return closedChanBucket.Put(chanID, b.Bytes())
+	if err := closedChanBucket.Put(chanID, b.Bytes()); err != nil {
+		return err
+	}
+
+	return putClosedChanIndexes(tx, chanID, summary)
 }
 
 func serializeChannelCloseSummary(w io.Writer, cs *ChannelCloseSummary) error {
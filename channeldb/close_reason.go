@@ -0,0 +1,263 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// closeReasonBucket is a top level bucket that stores why a channel
+	// was closed, keyed by its channel point.
+	closeReasonBucket = []byte("close-reason-bucket")
+
+	// ErrNoCloseReason is returned when no close reason was recorded for
+	// a channel.
+	ErrNoCloseReason = errors.New("no close reason recorded for channel")
+)
+
+const (
+	closeInitiatorType tlv.Type = 0
+	closeReasonType    tlv.Type = 1
+	closeDetailsType   tlv.Type = 2
+	closeTimestampType tlv.Type = 3
+)
+
+// CloseInitiator is the party that initiated the close of a channel.
+type CloseInitiator uint8
+
+const (
+	// CloseInitiatorUnknown is used when we don't know who initiated the
+	// close.
+	CloseInitiatorUnknown CloseInitiator = 0
+
+	// CloseInitiatorLocal is used when we initiated the close.
+	CloseInitiatorLocal CloseInitiator = 1
+
+	// CloseInitiatorRemote is used when the remote party initiated the
+	// close.
+	CloseInitiatorRemote CloseInitiator = 2
+)
+
+// String returns a human readable string for the close initiator.
+func (c CloseInitiator) String() string {
+	switch c {
+	case CloseInitiatorLocal:
+		return "local"
+
+	case CloseInitiatorRemote:
+		return "remote"
+
+	default:
+		return "unknown"
+	}
+}
+
+// CloseReason describes why a channel was closed.
+type CloseReason uint8
+
+const (
+	// CloseReasonUnknown is used when the reason of the close is unknown,
+	// for example for channels that were closed before close reasons were
+	// recorded.
+	CloseReasonUnknown CloseReason = 0
+
+	// CloseReasonUserRequest is used when the channel was closed or
+	// abandoned on request of the user.
+	CloseReasonUserRequest CloseReason = 1
+
+	// CloseReasonPeerRequest is used when the remote party requested a
+	// cooperative close.
+	CloseReasonPeerRequest CloseReason = 2
+
+	// CloseReasonRemoteForceClose is used when the remote party published
+	// its commitment without a reason known to us.
+	CloseReasonRemoteForceClose CloseReason = 3
+
+	// CloseReasonBreach is used when the remote party published a revoked
+	// commitment.
+	CloseReasonBreach CloseReason = 4
+
+	// CloseReasonHtlcExpiry is used when we went on chain because an HTLC
+	// was about to expire without being resolved off chain.
+	CloseReasonHtlcExpiry CloseReason = 5
+
+	// CloseReasonPeerBehavior is used when we force closed the channel
+	// because the remote party misbehaved, for example by violating the
+	// protocol or sending us an error.
+	CloseReasonPeerBehavior CloseReason = 6
+
+	// CloseReasonFundingCanceled is used when the channel never confirmed
+	// and its funding flow was canceled.
+	CloseReasonFundingCanceled CloseReason = 7
+)
+
+// String returns a human readable string for the close reason.
+func (c CloseReason) String() string {
+	switch c {
+	case CloseReasonUserRequest:
+		return "user request"
+
+	case CloseReasonPeerRequest:
+		return "peer request"
+
+	case CloseReasonRemoteForceClose:
+		return "remote force close"
+
+	case CloseReasonBreach:
+		return "breach"
+
+	case CloseReasonHtlcExpiry:
+		return "htlc expiry"
+
+	case CloseReasonPeerBehavior:
+		return "peer behavior"
+
+	case CloseReasonFundingCanceled:
+		return "funding canceled"
+
+	default:
+		return "unknown"
+	}
+}
+
+// ChannelCloseReason records who initiated the close of a channel and why.
+type ChannelCloseReason struct {
+	// Initiator is the party that initiated the close.
+	Initiator CloseInitiator
+
+	// Reason is the reason the channel was closed for.
+	Reason CloseReason
+
+	// Details holds optional human readable details about the reason,
+	// for example the error that caused a force close.
+	Details string
+
+	// Timestamp is the time the reason was recorded at.
+	Timestamp time.Time
+}
+
+// PutCloseReason records why the given channel is being closed. Only the first
+// reason recorded for a channel is kept, as later events, like the
+// confirmation of the commitment we broadcast, are a consequence of it. A
+// breach is the exception, it always replaces an earlier reason as the close
+// attempt it interrupted never completed.
+func (c *ChannelStateDB) PutCloseReason(chanPoint *wire.OutPoint,
+	reason *ChannelCloseReason) error {
+
+	var key bytes.Buffer
+	if err := writeOutpoint(&key, chanPoint); err != nil {
+		return err
+	}
+
+	var value bytes.Buffer
+	if err := serializeCloseReason(&value, reason); err != nil {
+		return err
+	}
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(closeReasonBucket)
+		if err != nil {
+			return err
+		}
+
+		if bucket.Get(key.Bytes()) != nil &&
+			reason.Reason != CloseReasonBreach {
+
+			return nil
+		}
+
+		return bucket.Put(key.Bytes(), value.Bytes())
+	}, func() {})
+}
+
+// FetchCloseReason returns the close reason recorded for the given channel. If
+// no reason was recorded, ErrNoCloseReason is returned.
+func (c *ChannelStateDB) FetchCloseReason(
+	chanPoint *wire.OutPoint) (*ChannelCloseReason, error) {
+
+	var key bytes.Buffer
+	if err := writeOutpoint(&key, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var reason *ChannelCloseReason
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(closeReasonBucket)
+		if bucket == nil {
+			return ErrNoCloseReason
+		}
+
+		value := bucket.Get(key.Bytes())
+		if value == nil {
+			return ErrNoCloseReason
+		}
+
+		var err error
+		reason, err = deserializeCloseReason(bytes.NewReader(value))
+
+		return err
+	}, func() {
+		reason = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reason, nil
+}
+
+// serializeCloseReason serializes a close reason as a TLV stream.
+func serializeCloseReason(w io.Writer, reason *ChannelCloseReason) error {
+	initiator := uint8(reason.Initiator)
+	closeReason := uint8(reason.Reason)
+	details := []byte(reason.Details)
+	timestamp := uint64(reason.Timestamp.Unix())
+
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(closeInitiatorType, &initiator),
+		tlv.MakePrimitiveRecord(closeReasonType, &closeReason),
+		tlv.MakePrimitiveRecord(closeDetailsType, &details),
+		tlv.MakePrimitiveRecord(closeTimestampType, &timestamp),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// deserializeCloseReason deserializes a close reason from a TLV stream.
+func deserializeCloseReason(r io.Reader) (*ChannelCloseReason, error) {
+	var (
+		initiator, closeReason uint8
+		details                []byte
+		timestamp              uint64
+	)
+
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(closeInitiatorType, &initiator),
+		tlv.MakePrimitiveRecord(closeReasonType, &closeReason),
+		tlv.MakePrimitiveRecord(closeDetailsType, &details),
+		tlv.MakePrimitiveRecord(closeTimestampType, &timestamp),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tlvStream.Decode(r); err != nil {
+		return nil, err
+	}
+
+	return &ChannelCloseReason{
+		Initiator: CloseInitiator(initiator),
+		Reason:    CloseReason(closeReason),
+		Details:   string(details),
+		Timestamp: time.Unix(int64(timestamp), 0),
+	}, nil
+}
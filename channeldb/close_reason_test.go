@@ -0,0 +1,61 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPersistCloseReason tests that the first close reason recorded for a
+// channel is persisted and that later reasons other than a breach are
+// ignored.
+func TestPersistCloseReason(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+	cdb := db.ChannelStateDB()
+
+	_, err = cdb.FetchCloseReason(&testChanPoint1)
+	require.ErrorIs(t, err, ErrNoCloseReason)
+
+	reason := &ChannelCloseReason{
+		Initiator: CloseInitiatorLocal,
+		Reason:    CloseReasonHtlcExpiry,
+		Details:   "outgoing htlc 3 expires at height 800000",
+		Timestamp: time.Unix(1700000000, 0),
+	}
+	require.NoError(t, cdb.PutCloseReason(&testChanPoint1, reason))
+
+	// The confirmation of our commitment must not replace the reason we
+	// broadcast it for.
+	require.NoError(t, cdb.PutCloseReason(
+		&testChanPoint1, &ChannelCloseReason{
+			Initiator: CloseInitiatorRemote,
+			Reason:    CloseReasonRemoteForceClose,
+			Timestamp: time.Unix(1700000100, 0),
+		},
+	))
+
+	fetched, err := cdb.FetchCloseReason(&testChanPoint1)
+	require.NoError(t, err)
+	require.Equal(t, reason, fetched)
+
+	// A breach replaces the reason of the interrupted close attempt.
+	breach := &ChannelCloseReason{
+		Initiator: CloseInitiatorRemote,
+		Reason:    CloseReasonBreach,
+		Timestamp: time.Unix(1700000200, 0),
+	}
+	require.NoError(t, cdb.PutCloseReason(&testChanPoint1, breach))
+
+	fetched, err = cdb.FetchCloseReason(&testChanPoint1)
+	require.NoError(t, err)
+	require.Equal(t, breach, fetched)
+
+	otherChanPoint := testChanPoint1
+	otherChanPoint.Index++
+	_, err = cdb.FetchCloseReason(&otherChanPoint)
+	require.ErrorIs(t, err, ErrNoCloseReason)
+}
@@ -0,0 +1,492 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// closedChanIndexBucket is a top level bucket that holds the indexes
+	// over the close summaries in the closedChannelBucket.
+	closedChanIndexBucket = []byte("closed-chan-index-bucket")
+
+	// closeHeightIndexBucket is a sub-bucket of the closedChanIndexBucket
+	// that indexes the close summaries by their close height. The keys are
+	// the big endian close height followed by the channel point, the
+	// values are empty.
+	closeHeightIndexBucket = []byte("close-height-index")
+
+	// pendingCloseIndexBucket is a sub-bucket of the
+	// closedChanIndexBucket that holds the channel points of all close
+	// summaries of channels that aren't fully closed yet.
+	pendingCloseIndexBucket = []byte("pending-close-index")
+
+	// closedChanIndexCompleteKey is set in the closedChanIndexBucket once
+	// the close summaries stored before the indexes were added have been
+	// indexed.
+	closedChanIndexCompleteKey = []byte("index-complete")
+
+	// ErrInvalidCursor is returned if a cursor passed to
+	// FetchClosedChannelsPage wasn't returned by a previous query.
+	ErrInvalidCursor = errors.New("invalid closed channel cursor")
+)
+
+const (
+	// closeHeightIndexKeyLen is the length of a key of the close height
+	// index, consisting of the close height and the channel point.
+	closeHeightIndexKeyLen = 4 + 32 + 4
+)
+
+// ClosedChannelQuery describes a page of close summaries to fetch with
+// FetchClosedChannelsPage. The close summaries are ordered by their close
+// height and channel point.
+type ClosedChannelQuery struct {
+	// Cursor is the cursor of the previous page. If set, the page starts
+	// after the last close summary of the previous page.
+	Cursor []byte
+
+	// MaxChannels is the maximum number of close summaries to return. If
+	// zero, all matching close summaries are returned.
+	MaxChannels uint32
+
+	// PendingOnly restricts the query to channels that aren't fully
+	// closed yet.
+	PendingOnly bool
+
+	// ExcludePending restricts the query to channels that are fully
+	// closed.
+	ExcludePending bool
+
+	// CloseTypes restricts the query to channels closed in one of the
+	// given ways. If empty, channels are returned regardless of how they
+	// were closed.
+	CloseTypes []ClosureType
+
+	// MinCloseHeight is the lowest close height to return channels for.
+	MinCloseHeight uint32
+
+	// MaxCloseHeight is the highest close height to return channels for.
+	// If zero, there is no upper bound.
+	MaxCloseHeight uint32
+
+	// RemotePub restricts the query to channels with the given peer.
+	RemotePub *btcec.PublicKey
+}
+
+// matches returns true if the given close summary matches the filters of the
+// query.
+func (q *ClosedChannelQuery) matches(summary *ChannelCloseSummary) bool {
+	switch {
+	case q.PendingOnly && !summary.IsPending:
+		return false
+
+	case q.ExcludePending && summary.IsPending:
+		return false
+
+	case summary.CloseHeight < q.MinCloseHeight:
+		return false
+
+	case q.MaxCloseHeight != 0 && summary.CloseHeight > q.MaxCloseHeight:
+		return false
+
+	case q.RemotePub != nil && !q.RemotePub.IsEqual(summary.RemotePub):
+		return false
+	}
+
+	if len(q.CloseTypes) == 0 {
+		return true
+	}
+	for _, closeType := range q.CloseTypes {
+		if summary.CloseType == closeType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClosedChannelPage is a page of close summaries returned by
+// FetchClosedChannelsPage.
+type ClosedChannelPage struct {
+	// Channels are the close summaries of the page.
+	Channels []*ChannelCloseSummary
+
+	// NextCursor is the cursor to pass with the query of the next page.
+	// It is nil if there are no more matching close summaries.
+	NextCursor []byte
+}
+
+// closeHeightIndexKey returns the key of the given close summary in the close
+// height index.
+func closeHeightIndexKey(summary *ChannelCloseSummary) ([]byte, error) {
+	var key bytes.Buffer
+	var height [4]byte
+	binary.BigEndian.PutUint32(height[:], summary.CloseHeight)
+	key.Write(height[:])
+
+	if err := writeOutpoint(&key, &summary.ChanPoint); err != nil {
+		return nil, err
+	}
+
+	return key.Bytes(), nil
+}
+
+// putClosedChanIndexes adds the given close summary, which is stored under the
+// given channel point key, to the closed channel indexes.
+func putClosedChanIndexes(tx kvdb.RwTx, chanKey []byte,
+	summary *ChannelCloseSummary) error {
+
+	indexBucket, err := tx.CreateTopLevelBucket(closedChanIndexBucket)
+	if err != nil {
+		return err
+	}
+	heightIndex, err := indexBucket.CreateBucketIfNotExists(
+		closeHeightIndexBucket,
+	)
+	if err != nil {
+		return err
+	}
+	pendingIndex, err := indexBucket.CreateBucketIfNotExists(
+		pendingCloseIndexBucket,
+	)
+	if err != nil {
+		return err
+	}
+
+	heightKey, err := closeHeightIndexKey(summary)
+	if err != nil {
+		return err
+	}
+	if err := heightIndex.Put(heightKey, []byte{}); err != nil {
+		return err
+	}
+
+	if summary.IsPending {
+		return pendingIndex.Put(chanKey, []byte{})
+	}
+
+	return pendingIndex.Delete(chanKey)
+}
+
+// indexClosedChannels brings the closed channel indexes in line with the
+// close summaries. The indexes are kept up to date whenever a close summary is
+// written, but versions of lnd without the indexes, including ones the node
+// was downgraded to in the meantime, write close summaries without indexing
+// them. So the indexes are validated against the close summaries on every
+// startup, and missing or stale entries are added or removed.
+func (c *ChannelStateDB) indexClosedChannels() error {
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		indexBucket, err := tx.CreateTopLevelBucket(
+			closedChanIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+		heightIndex, err := indexBucket.CreateBucketIfNotExists(
+			closeHeightIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+		pendingIndex, err := indexBucket.CreateBucketIfNotExists(
+			pendingCloseIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Collect the index entries of all close summaries.
+		heightKeys := make(map[string]struct{})
+		pendingKeys := make(map[string]struct{})
+		closeBucket := tx.ReadBucket(closedChannelBucket)
+		if closeBucket != nil {
+			err := closeBucket.ForEach(func(k, v []byte) error {
+				summary, err := deserializeCloseChannelSummary(
+					bytes.NewReader(v),
+				)
+				if err != nil {
+					return err
+				}
+
+				heightKey, err := closeHeightIndexKey(summary)
+				if err != nil {
+					return err
+				}
+				heightKeys[string(heightKey)] = struct{}{}
+
+				if summary.IsPending {
+					pendingKeys[string(k)] = struct{}{}
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		numRemoved, err := syncIndexBucket(heightIndex, heightKeys)
+		if err != nil {
+			return err
+		}
+		numPendingRemoved, err := syncIndexBucket(
+			pendingIndex, pendingKeys,
+		)
+		if err != nil {
+			return err
+		}
+		numRemoved += numPendingRemoved
+
+		if numRemoved > 0 {
+			log.Infof("Removed %d stale closed channel index "+
+				"entries", numRemoved)
+		}
+
+		if indexBucket.Get(closedChanIndexCompleteKey) != nil {
+			return nil
+		}
+
+		return indexBucket.Put(closedChanIndexCompleteKey, []byte{1})
+	}, func() {})
+}
+
+// syncIndexBucket makes the given index bucket contain exactly the given keys
+// with empty values, and returns the number of stale keys that were removed.
+func syncIndexBucket(bucket kvdb.RwBucket,
+	keys map[string]struct{}) (int, error) {
+
+	var staleKeys [][]byte
+	err := bucket.ForEach(func(k, _ []byte) error {
+		if _, ok := keys[string(k)]; !ok {
+			staleKeys = append(staleKeys, append([]byte{}, k...))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, k := range staleKeys {
+		if err := bucket.Delete(k); err != nil {
+			return 0, err
+		}
+	}
+
+	for k := range keys {
+		if bucket.Get([]byte(k)) != nil {
+			continue
+		}
+
+		if err := bucket.Put([]byte(k), []byte{}); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(staleKeys), nil
+}
+
+// fetchPendingCloseSummaries returns the close summaries of all channels that
+// aren't fully closed yet, using the pending close index. It returns false if
+// the index isn't available.
+func fetchPendingCloseSummaries(tx kvdb.RTx) ([]*ChannelCloseSummary, bool,
+	error) {
+
+	indexBucket := tx.ReadBucket(closedChanIndexBucket)
+	if indexBucket == nil ||
+		indexBucket.Get(closedChanIndexCompleteKey) == nil {
+
+		return nil, false, nil
+	}
+
+	pendingIndex := indexBucket.NestedReadBucket(pendingCloseIndexBucket)
+	closeBucket := tx.ReadBucket(closedChannelBucket)
+	if pendingIndex == nil || closeBucket == nil {
+		return nil, true, nil
+	}
+
+	var summaries []*ChannelCloseSummary
+	err := pendingIndex.ForEach(func(chanKey, _ []byte) error {
+		summaryBytes := closeBucket.Get(chanKey)
+		if summaryBytes == nil {
+			return nil
+		}
+
+		summary, err := deserializeCloseChannelSummary(
+			bytes.NewReader(summaryBytes),
+		)
+		if err != nil {
+			return err
+		}
+
+		summaries = append(summaries, summary)
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return summaries, true, nil
+}
+
+// FetchClosedChannelsPage returns a page of the close summaries matching the
+// given query, ordered by their close height. Unlike FetchClosedChannels, it
+// only reads the close summaries within the queried range of close heights,
+// and only those of channels pending close if the query is restricted to
+// them.
+func (c *ChannelStateDB) FetchClosedChannelsPage(
+	query *ClosedChannelQuery) (*ClosedChannelPage, error) {
+
+	if len(query.Cursor) != 0 &&
+		len(query.Cursor) != closeHeightIndexKeyLen {
+
+		return nil, ErrInvalidCursor
+	}
+
+	// The page is complete once it holds the maximum number of channels.
+	// We fetch one more channel to know whether there is a next page.
+	var page *ClosedChannelPage
+	isFull := func() bool {
+		return query.MaxChannels != 0 &&
+			uint32(len(page.Channels)) > query.MaxChannels
+	}
+
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		closeBucket := tx.ReadBucket(closedChannelBucket)
+		indexBucket := tx.ReadBucket(closedChanIndexBucket)
+		if closeBucket == nil || indexBucket == nil {
+			return nil
+		}
+
+		// Channels pending close are usually few, so we read them
+		// from the pending close index and order them in memory.
+		if query.PendingOnly {
+			summaries, ok, err := fetchPendingCloseSummaries(tx)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return pendingClosedChannelsPage(
+					query, summaries, page, isFull,
+				)
+			}
+		}
+
+		heightIndex := indexBucket.NestedReadBucket(
+			closeHeightIndexBucket,
+		)
+		if heightIndex == nil {
+			return nil
+		}
+
+		// Start after the cursor, or at the lowest queried close
+		// height.
+		start := make([]byte, 4)
+		binary.BigEndian.PutUint32(start, query.MinCloseHeight)
+		if bytes.Compare(query.Cursor, start) > 0 {
+			start = query.Cursor
+		}
+
+		cursor := heightIndex.ReadCursor()
+		for k, _ := cursor.Seek(start); k != nil; k, _ = cursor.Next() {
+			if len(k) != closeHeightIndexKeyLen ||
+				bytes.Equal(k, query.Cursor) {
+
+				continue
+			}
+
+			height := binary.BigEndian.Uint32(k[:4])
+			if query.MaxCloseHeight != 0 &&
+				height > query.MaxCloseHeight {
+
+				break
+			}
+
+			summaryBytes := closeBucket.Get(k[4:])
+			if summaryBytes == nil {
+				continue
+			}
+			summary, err := deserializeCloseChannelSummary(
+				bytes.NewReader(summaryBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			// Skip stale entries of close summaries that were
+			// rewritten with a different close height.
+			if summary.CloseHeight != height ||
+				!query.matches(summary) {
+
+				continue
+			}
+
+			page.Channels = append(page.Channels, summary)
+			if isFull() {
+				break
+			}
+		}
+
+		return nil
+	}, func() {
+		page = &ClosedChannelPage{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// If we fetched an additional channel, there is a next page that
+	// starts after the last channel of this page.
+	if isFull() {
+		page.Channels = page.Channels[:query.MaxChannels]
+
+		last := page.Channels[len(page.Channels)-1]
+		page.NextCursor, err = closeHeightIndexKey(last)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// pendingClosedChannelsPage adds the given close summaries of channels
+// pending close that match the query and follow its cursor to the page.
+func pendingClosedChannelsPage(query *ClosedChannelQuery,
+	summaries []*ChannelCloseSummary, page *ClosedChannelPage,
+	isFull func() bool) error {
+
+	keys := make(map[*ChannelCloseSummary][]byte, len(summaries))
+	for _, summary := range summaries {
+		key, err := closeHeightIndexKey(summary)
+		if err != nil {
+			return err
+		}
+		keys[summary] = key
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return bytes.Compare(keys[summaries[i]], keys[summaries[j]]) < 0
+	})
+
+	for _, summary := range summaries {
+		if bytes.Compare(keys[summary], query.Cursor) <= 0 ||
+			!query.matches(summary) {
+
+			continue
+		}
+
+		page.Channels = append(page.Channels, summary)
+		if isFull() {
+			break
+		}
+	}
+
+	return nil
+}
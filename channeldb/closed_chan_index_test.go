@@ -0,0 +1,291 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFetchClosedChannelsPage tests that close summaries are paged by their
+// close height and filtered according to the query.
+func TestFetchClosedChannelsPage(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+	cdb := fullDB.ChannelStateDB()
+
+	privKey1, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	privKey2, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	peer1, peer2 := privKey1.PubKey(), privKey2.PubKey()
+
+	// Store the close summaries in reverse order of their close heights,
+	// to make sure they are ordered by the index. Every third channel is
+	// still pending close, every other channel is with the second peer.
+	const numChans = 10
+	putSummary := func(summary *ChannelCloseSummary) {
+		err := kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+			var key bytes.Buffer
+			err := writeOutpoint(&key, &summary.ChanPoint)
+			if err != nil {
+				return err
+			}
+
+			return putChannelCloseSummary(
+				tx, key.Bytes(), summary, &OpenChannel{},
+			)
+		}, func() {})
+		require.NoError(t, err)
+	}
+	for i := uint32(numChans); i > 0; i-- {
+		summary := &ChannelCloseSummary{
+			ChanPoint:   wire.OutPoint{Index: i},
+			CloseHeight: 100 + i,
+			RemotePub:   peer1,
+			CloseType:   CooperativeClose,
+			IsPending:   i%3 == 0,
+		}
+		if i%2 == 0 {
+			summary.RemotePub = peer2
+			summary.CloseType = LocalForceClose
+		}
+		putSummary(summary)
+	}
+
+	// fetchAll fetches all pages of the given query, and returns the
+	// output indexes of the channel points.
+	fetchAll := func(query ClosedChannelQuery) []uint32 {
+		var indexes []uint32
+		for {
+			page, err := cdb.FetchClosedChannelsPage(&query)
+			require.NoError(t, err)
+
+			for _, summary := range page.Channels {
+				index := summary.ChanPoint.Index
+				indexes = append(indexes, index)
+			}
+			if query.MaxChannels != 0 {
+				require.LessOrEqual(
+					t, len(page.Channels),
+					int(query.MaxChannels),
+				)
+			}
+
+			if page.NextCursor == nil {
+				return indexes
+			}
+			query.Cursor = page.NextCursor
+		}
+	}
+
+	require.Equal(t, []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, fetchAll(
+		ClosedChannelQuery{MaxChannels: 3},
+	))
+	require.Equal(t, []uint32{3, 6, 9}, fetchAll(ClosedChannelQuery{
+		MaxChannels: 2,
+		PendingOnly: true,
+	}))
+	require.Equal(t, []uint32{1, 2, 4, 5, 7, 8, 10}, fetchAll(
+		ClosedChannelQuery{ExcludePending: true},
+	))
+	require.Equal(t, []uint32{2, 4, 6, 8, 10}, fetchAll(ClosedChannelQuery{
+		MaxChannels: 1,
+		CloseTypes:  []ClosureType{LocalForceClose},
+	}))
+	require.Equal(t, []uint32{3, 5, 7}, fetchAll(ClosedChannelQuery{
+		MaxChannels:    1,
+		MinCloseHeight: 103,
+		MaxCloseHeight: 107,
+		RemotePub:      peer1,
+	}))
+
+	// Channels that are fully closed are removed from the pending close
+	// index.
+	require.NoError(t, cdb.MarkChanFullyClosed(&wire.OutPoint{Index: 6}))
+	require.Equal(t, []uint32{3, 9}, fetchAll(
+		ClosedChannelQuery{PendingOnly: true},
+	))
+
+	pending, err := cdb.FetchClosedChannels(true)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	_, err = cdb.FetchClosedChannelsPage(&ClosedChannelQuery{
+		Cursor: []byte{1},
+	})
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	// Close summaries stored before the indexes existed are indexed once.
+	err = kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+		return tx.DeleteTopLevelBucket(closedChanIndexBucket)
+	}, func() {})
+	require.NoError(t, err)
+	require.Empty(t, fetchAll(ClosedChannelQuery{}))
+
+	require.NoError(t, cdb.indexClosedChannels())
+	require.Equal(t, []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, fetchAll(
+		ClosedChannelQuery{},
+	))
+	require.Equal(t, []uint32{3, 9}, fetchAll(
+		ClosedChannelQuery{PendingOnly: true},
+	))
+}
+
+// TestIndexClosedChannels tests that abandoned channels are indexed, and that
+// the indexes are brought in line with close summaries that were written,
+// rewritten or deleted without updating them, as a downgraded node does.
+func TestIndexClosedChannels(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+	cdb := fullDB.ChannelStateDB()
+
+	// Abandoned channels are indexed as fully closed.
+	chanState := createTestChannel(t, cdb)
+	abandonedPoint := chanState.FundingOutpoint
+	require.NoError(t, cdb.AbandonChannel(&abandonedPoint, 50))
+
+	page, err := cdb.FetchClosedChannelsPage(&ClosedChannelQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Channels, 1)
+	require.Equal(t, Abandoned, page.Channels[0].CloseType)
+	require.Equal(t, abandonedPoint, page.Channels[0].ChanPoint)
+
+	page, err = cdb.FetchClosedChannelsPage(&ClosedChannelQuery{
+		PendingOnly: true,
+	})
+	require.NoError(t, err)
+	require.Empty(t, page.Channels)
+
+	// putRawSummary writes a close summary without updating the indexes,
+	// and deleteRawSummary deletes one, as older versions of lnd do.
+	putRawSummary := func(summary *ChannelCloseSummary) {
+		err := kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+			var key, value bytes.Buffer
+			err := writeOutpoint(&key, &summary.ChanPoint)
+			if err != nil {
+				return err
+			}
+			err = serializeChannelCloseSummary(&value, summary)
+			if err != nil {
+				return err
+			}
+
+			closeBucket := tx.ReadWriteBucket(closedChannelBucket)
+
+			return closeBucket.Put(key.Bytes(), value.Bytes())
+		}, func() {})
+		require.NoError(t, err)
+	}
+	deleteRawSummary := func(chanPoint wire.OutPoint) {
+		err := kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+			var key bytes.Buffer
+			err := writeOutpoint(&key, &chanPoint)
+			if err != nil {
+				return err
+			}
+
+			closeBucket := tx.ReadWriteBucket(closedChannelBucket)
+
+			return closeBucket.Delete(key.Bytes())
+		}, func() {})
+		require.NoError(t, err)
+	}
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	newSummary := func(index, height uint32,
+		pending bool) *ChannelCloseSummary {
+
+		return &ChannelCloseSummary{
+			ChanPoint:   wire.OutPoint{Index: index},
+			CloseHeight: height,
+			RemotePub:   privKey.PubKey(),
+			IsPending:   pending,
+		}
+	}
+
+	// Store three pending summaries through the indexed path.
+	err = kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+		for i := uint32(1); i <= 3; i++ {
+			summary := newSummary(i, 100+i, true)
+
+			var key bytes.Buffer
+			err := writeOutpoint(&key, &summary.ChanPoint)
+			if err != nil {
+				return err
+			}
+
+			err = putChannelCloseSummary(
+				tx, key.Bytes(), summary, &OpenChannel{},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// While downgraded, a new channel is closed, the first channel is
+	// fully closed, the second one is rewritten with another close height
+	// and the abandoned one is deleted.
+	putRawSummary(newSummary(4, 104, true))
+	putRawSummary(newSummary(1, 101, false))
+	putRawSummary(newSummary(2, 110, true))
+	deleteRawSummary(abandonedPoint)
+
+	// The stale entries of the rewritten channel are skipped, but the new
+	// channel is missing until the indexes are validated.
+	fetchIndexes := func(query *ClosedChannelQuery) []uint32 {
+		page, err := cdb.FetchClosedChannelsPage(query)
+		require.NoError(t, err)
+
+		var indexes []uint32
+		for _, summary := range page.Channels {
+			indexes = append(indexes, summary.ChanPoint.Index)
+		}
+
+		return indexes
+	}
+	require.Equal(t, []uint32{1, 3}, fetchIndexes(&ClosedChannelQuery{}))
+
+	// Once validated on startup, the indexes match the close summaries.
+	require.NoError(t, cdb.indexClosedChannels())
+	require.Equal(
+		t, []uint32{1, 3, 4, 2}, fetchIndexes(&ClosedChannelQuery{}),
+	)
+	require.Equal(t, []uint32{3, 4, 2}, fetchIndexes(&ClosedChannelQuery{
+		PendingOnly: true,
+	}))
+
+	// No stale entries are left behind.
+	err = kvdb.View(cdb.backend, func(tx kvdb.RTx) error {
+		indexBucket := tx.ReadBucket(closedChanIndexBucket)
+
+		for bucket, numKeys := range map[string]int{
+			string(closeHeightIndexBucket):  4,
+			string(pendingCloseIndexBucket): 3,
+		} {
+			var numFound int
+			err := indexBucket.NestedReadBucket([]byte(bucket)).
+				ForEach(func(_, _ []byte) error {
+					numFound++
+					return nil
+				})
+			require.NoError(t, err)
+			require.Equal(t, numKeys, numFound, bucket)
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
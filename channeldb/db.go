@@ -36,6 +36,10 @@ import (
 
 const (
 	dbName = "channel.db"
+
+	// compactRevLogVersion is the mandatory database version that marks a
+	// database which may contain compacted revocation logs.
+	compactRevLogVersion = 32
 )
 
 var (
@@ -286,6 +290,17 @@ var (
 			number:    31,
 			migration: migration31.DeleteLastPublishedTxTLB,
 		},
+		{
+			// Compacted revocation logs store their HTLCs as
+			// references to shared HTLC entries, which older
+			// versions would silently decode as logs without
+			// HTLCs. This version doesn't migrate any data, it
+			// only prevents reverting to such a version. It's
+			// only applied once compaction is enabled, see
+			// filterVersions.
+			number:    compactRevLogVersion,
+			migration: nil,
+		},
 	}
 
 	// optionalVersions stores all optional migrations that are applied
@@ -334,6 +349,10 @@ type DB struct {
 	// noRevLogAmtData if true, means that commitment transaction amount
 	// data should not be stored in the revocation log.
 	noRevLogAmtData bool
+
+	// compactRevLog if true, means that the HTLC entries of new revocation
+	// logs are shared between the logs of a channel.
+	compactRevLog bool
 }
 
 // Open opens or creates channeldb. Any necessary schemas migrations due
@@ -375,11 +394,18 @@ func CreateWithBackend(backend kvdb.Backend,
 	}
 
 	if !opts.NoMigration {
-		if err := initChannelDB(backend); err != nil {
+		err := initChannelDB(backend, opts.CompactRevLog)
+		if err != nil {
 			return nil, err
 		}
 	}
 
+	// All writes go through the export fence, so that they can be
+	// rejected once the database was exported for migration.
+	backend = &exportFence{
+		Backend: backend,
+	}
+
 	chanDB := &DB{
 		Backend: backend,
 		channelStateDB: &ChannelStateDB{
@@ -393,6 +419,7 @@ func CreateWithBackend(backend kvdb.Backend,
 		keepFailedPaymentAttempts: opts.keepFailedPaymentAttempts,
 		storeFinalHtlcResolutions: opts.storeFinalHtlcResolutions,
 		noRevLogAmtData:           opts.NoRevLogAmtData,
+		compactRevLog:             opts.CompactRevLog,
 	}
 
 	// Set the parent pointer (only used in tests).
@@ -401,8 +428,9 @@ func CreateWithBackend(backend kvdb.Backend,
 	var err error
 	chanDB.graph, err = NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
-		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		opts.UseGraphCache, opts.NoMigration,
+		opts.BatchCommitInterval, opts.MaxBatchSize,
+		opts.PreAllocCacheNumNodes, opts.UseGraphCache,
+		opts.NoMigration,
 	)
 	if err != nil {
 		return nil, err
@@ -421,6 +449,14 @@ func CreateWithBackend(backend kvdb.Backend,
 			backend.Close()
 			return nil, err
 		}
+
+		// Index the close summaries that were stored without
+		// updating the closed channel indexes.
+		err := chanDB.channelStateDB.indexClosedChannels()
+		if err != nil {
+			backend.Close()
+			return nil, err
+		}
 	}
 
 	return chanDB, nil
@@ -447,6 +483,7 @@ var dbTopLevelBuckets = [][]byte{
 	outpointBucket,
 	chanIDBucket,
 	historicalChannelBucket,
+	closeReasonBucket,
 }
 
 // Wipe completely deletes all saved state within all used buckets within the
@@ -466,14 +503,14 @@ func (d *DB) Wipe() error {
 		return err
 	}
 
-	return initChannelDB(d.Backend)
+	return initChannelDB(d.Backend, d.compactRevLog)
 }
 
 // initChannelDB creates and initializes a fresh version of channeldb. In the
 // case that the target path has not yet been created or doesn't yet exist, then
 // the path is created. Additionally, all required top-level buckets used within
 // the database are created.
-func initChannelDB(db kvdb.Backend) error {
+func initChannelDB(db kvdb.Backend, compactRevLog bool) error {
 	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
 		// Check if DB was marked as inactive with a tomb stone.
 		if err := EnsureNoTombstone(tx); err != nil {
@@ -493,7 +530,8 @@ func initChannelDB(db kvdb.Backend) error {
 			}
 		}
 
-		meta.DbVersionNumber = getLatestDBVersion(dbVersions)
+		versions := filterVersions(dbVersions, 0, compactRevLog)
+		meta.DbVersionNumber = getLatestDBVersion(versions)
 		return putMeta(meta, tx)
 	}, func() {})
 	if err != nil {
@@ -1035,6 +1073,19 @@ func (c *ChannelStateDB) FetchClosedChannels(pendingOnly bool) (
 			return ErrNoClosedChannels
 		}
 
+		// If only pending channels are requested, we avoid reading
+		// all close summaries by using the pending close index.
+		if pendingOnly {
+			summaries, ok, err := fetchPendingCloseSummaries(tx)
+			if err != nil {
+				return err
+			}
+			if ok {
+				chanSummaries = summaries
+				return nil
+			}
+		}
+
 		return closeBucket.ForEach(func(chanID []byte, summaryBytes []byte) error {
 			summaryReader := bytes.NewReader(summaryBytes)
 			chanSummary, err := deserializeCloseChannelSummary(summaryReader)
@@ -1203,6 +1254,11 @@ func (c *ChannelStateDB) MarkChanFullyClosed(chanPoint *wire.OutPoint) error {
 			return err
 		}
 
+		err = putClosedChanIndexes(tx, chanID, chanSummary)
+		if err != nil {
+			return err
+		}
+
 		// Now that the channel is closed, we'll check if we have any
 		// other open channels with this peer. If we don't we'll
 		// garbage collect it to ensure we don't establish persistent
@@ -1498,6 +1554,9 @@ func (d *DB) syncVersions(versions []mandatoryVersion) error {
 		}
 	}
 
+	versions = filterVersions(
+		versions, meta.DbVersionNumber, d.compactRevLog,
+	)
 	latestVersion := getLatestDBVersion(versions)
 	log.Infof("Checking for schema update: latest_version=%v, "+
 		"db_version=%v", latestVersion, meta.DbVersionNumber)
@@ -1561,8 +1620,6 @@ func (d *DB) syncVersions(versions []mandatoryVersion) error {
 
 // applyOptionalVersions takes a config to determine whether the optional
 // migrations will be applied.
-//
-// NOTE: only support the prune_revocation_log optional migration atm.
 func (d *DB) applyOptionalVersions(cfg OptionalMiragtionConfig) error {
 	// TODO(yy): need to design the db to support dry run for optional
 	// migrations.
@@ -1586,19 +1643,9 @@ func (d *DB) applyOptionalVersions(cfg OptionalMiragtionConfig) error {
 	log.Infof("Checking for optional update: prune_revocation_log=%v, "+
 		"db_version=%s", cfg.PruneRevocationLog, om)
 
-	// Exit early if the optional migration is not specified.
-	if !cfg.PruneRevocationLog {
-		return nil
-	}
-
-	// Exit early if the optional migration has already been applied.
-	if _, ok := om.Versions[0]; ok {
-		return nil
-	}
-
-	// Get the optional version.
-	version := optionalVersions[0]
-	log.Infof("Performing database optional migration: %s", version.name)
+	// The optional migrations that are enabled by the config, indexed by
+	// their optional version.
+	enabled := []bool{cfg.PruneRevocationLog}
 
 	migrationCfg := &MigrationConfigImpl{
 		migration30.MigrateRevLogConfigImpl{
@@ -1606,23 +1653,38 @@ func (d *DB) applyOptionalVersions(cfg OptionalMiragtionConfig) error {
 		},
 	}
 
-	// Migrate the data.
-	if err := version.migration(d, migrationCfg); err != nil {
-		log.Errorf("Unable to apply optional migration: %s, error: %v",
-			version.name, err)
-		return err
-	}
+	for i, version := range optionalVersions {
+		// Skip the optional migration if it's not specified or if it
+		// has already been applied.
+		if i >= len(enabled) || !enabled[i] {
+			continue
+		}
+		if _, ok := om.Versions[uint64(i)]; ok {
+			continue
+		}
 
-	// Update the optional meta. Notice that unlike the mandatory db
-	// migrations where we perform the migration and updating meta in a
-	// single db transaction, we use different transactions here. Even when
-	// the following update is failed, we should be fine here as we would
-	// re-run the optional migration again, which is a noop, during next
-	// startup.
-	om.Versions[0] = version.name
-	if err := d.putOptionalMeta(om); err != nil {
-		log.Errorf("Unable to update optional meta: %v", err)
-		return err
+		log.Infof("Performing database optional migration: %s",
+			version.name)
+
+		// Migrate the data.
+		if err := version.migration(d, migrationCfg); err != nil {
+			log.Errorf("Unable to apply optional migration: %s, "+
+				"error: %v", version.name, err)
+			return err
+		}
+
+		// Update the optional meta. Notice that unlike the mandatory
+		// db migrations where we perform the migration and updating
+		// meta in a single db transaction, we use different
+		// transactions here. Even when the following update is
+		// failed, we should be fine here as we would re-run the
+		// optional migration again, which is a noop, during next
+		// startup.
+		om.Versions[uint64(i)] = version.name
+		if err := d.putOptionalMeta(om); err != nil {
+			log.Errorf("Unable to update optional meta: %v", err)
+			return err
+		}
 	}
 
 	return nil
@@ -1649,6 +1711,30 @@ func getLatestDBVersion(versions []mandatoryVersion) uint32 {
 	return versions[len(versions)-1].number
 }
 
+// filterVersions returns the given mandatory versions without the version
+// that marks compacted revocation logs, unless revocation log compaction is
+// enabled or the database is already at or past that version. This way older
+// versions of lnd can still open the database as long as it may not contain
+// any compacted revocation logs.
+func filterVersions(versions []mandatoryVersion, dbVersion uint32,
+	compactRevLog bool) []mandatoryVersion {
+
+	if compactRevLog || dbVersion >= compactRevLogVersion {
+		return versions
+	}
+
+	filtered := make([]mandatoryVersion, 0, len(versions))
+	for _, v := range versions {
+		if v.number == compactRevLogVersion {
+			continue
+		}
+
+		filtered = append(filtered, v)
+	}
+
+	return filtered
+}
+
 // getMigrationsToApply retrieves the migration function that should be
 // applied to the database.
 func getMigrationsToApply(versions []mandatoryVersion,
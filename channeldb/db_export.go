@@ -0,0 +1,520 @@
+package channeldb
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+const (
+	// exportVersion is the version of the export format.
+	exportVersion = 1
+
+	// exportRecordBucket marks the start of a bucket. It's followed by the
+	// bucket's key and sequence number.
+	exportRecordBucket byte = 'b'
+
+	// exportRecordBucketEnd marks the end of the current bucket.
+	exportRecordBucketEnd byte = 'e'
+
+	// exportRecordValue is a key/value pair of the current bucket.
+	exportRecordValue byte = 'v'
+
+	// importBatchSize is the maximum number of records that are imported
+	// within a single transaction. This keeps the transactions small
+	// enough for remote database backends.
+	importBatchSize = 10_000
+
+	// maxExportFieldSize is the maximum size of a single key or value in
+	// an export.
+	maxExportFieldSize = 1 << 30
+)
+
+var (
+	// exportMagic is written at the start of every export.
+	exportMagic = []byte("lnd-channeldb-export")
+
+	// ErrDBExported is returned when a write to the database is attempted
+	// after it was exported for the migration to a different database
+	// backend.
+	ErrDBExported = errors.New("channel db was exported for migration " +
+		"and can no longer be written to")
+
+	// ErrInvalidExport is returned when an export can't be imported
+	// because it's corrupted or has an unknown format.
+	ErrInvalidExport = errors.New("invalid channel db export")
+
+	// ErrImportTargetNotEmpty is returned when an export is imported into
+	// a database that already contains data.
+	ErrImportTargetNotEmpty = errors.New("channel db import target is " +
+		"not empty")
+)
+
+// exportFence is a database backend that rejects all writes once the
+// database was exported. This makes sure that the state of a node can't
+// change anymore after it was exported, so the node that the export is
+// imported into is the only one that can advance it.
+type exportFence struct {
+	kvdb.Backend
+
+	// fenced is set once the database was exported.
+	fenced atomic.Bool
+}
+
+// BeginReadWriteTx opens a database read+write transaction. An error is
+// returned if the database was exported.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (f *exportFence) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	tx, err := f.Backend.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+
+	// We only check the fence once the transaction was opened, so that
+	// transactions that waited for the export to finish are rejected too.
+	if f.fenced.Load() {
+		_ = tx.Rollback()
+
+		return nil, ErrDBExported
+	}
+
+	return tx, nil
+}
+
+// Update opens a database read+write transaction and executes the function f
+// with the transaction passed as a parameter. An error is returned if the
+// database was exported.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (f *exportFence) Update(fn func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	return f.Backend.Update(func(tx walletdb.ReadWriteTx) error {
+		if f.fenced.Load() {
+			return ErrDBExported
+		}
+
+		return fn(tx)
+	}, reset)
+}
+
+// ExportForMigration writes a complete copy of the database to w, for it to
+// be imported into a database of a different backend with
+// ImportForMigration. In the same transaction, the database is marked with a
+// tombstone, so it can't be used again after the export. All writes to the
+// database are rejected from then on, so the node should be shut down right
+// after the export. If w implements a Sync method, for example if it's a
+// file, it's called before the tombstone is committed.
+func (d *DB) ExportForMigration(w io.Writer) error {
+	fence, ok := d.Backend.(*exportFence)
+	if !ok {
+		return fmt.Errorf("channel db doesn't support exports")
+	}
+
+	// We use a manual transaction rather than an update, since the export
+	// can't be retried once it was written.
+	tx, err := fence.Backend.BeginReadWriteTx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := EnsureNoTombstone(tx); err != nil {
+		return err
+	}
+
+	if err := exportDB(tx, w); err != nil {
+		return fmt.Errorf("unable to export channel db: %w", err)
+	}
+
+	if syncer, ok := w.(interface{ Sync() error }); ok {
+		if err := syncer.Sync(); err != nil {
+			return fmt.Errorf("unable to sync export: %w", err)
+		}
+	}
+
+	tombstone := fmt.Sprintf("exported for migration at %v",
+		time.Now().UTC().Format(time.RFC3339))
+	if err := AddMarker(tx, TombstoneKey, []byte(tombstone)); err != nil {
+		return err
+	}
+
+	// We fence the database before the export is committed, so that no
+	// other transaction can be committed in between.
+	fence.fenced.Store(true)
+
+	err = tx.Commit()
+	tx = nil
+	if err != nil {
+		fence.fenced.Store(false)
+
+		return err
+	}
+
+	log.Infof("Channel db exported for migration, all writes to it are " +
+		"rejected from now on")
+
+	return nil
+}
+
+// exportDB writes all buckets of the given transaction to w, followed by a
+// checksum of the export.
+func exportDB(tx kvdb.RwTx, w io.Writer) error {
+	checksum := sha256.New()
+	bw := bufio.NewWriter(io.MultiWriter(w, checksum))
+
+	if _, err := bw.Write(exportMagic); err != nil {
+		return err
+	}
+	if err := bw.WriteByte(exportVersion); err != nil {
+		return err
+	}
+
+	err := tx.ForEachBucket(func(key []byte) error {
+		return exportBucket(bw, key, tx.ReadWriteBucket(key))
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	_, err = w.Write(checksum.Sum(nil))
+
+	return err
+}
+
+// exportBucket writes the given bucket and all of its nested buckets to w.
+func exportBucket(w *bufio.Writer, key []byte, bucket kvdb.RwBucket) error {
+	if bucket == nil {
+		return fmt.Errorf("bucket %x not found", key)
+	}
+
+	if err := w.WriteByte(exportRecordBucket); err != nil {
+		return err
+	}
+	if err := writeExportField(w, key); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, bucket.Sequence()); err != nil {
+		return err
+	}
+
+	err := bucket.ForEach(func(k, v []byte) error {
+		// A nil value denotes a nested bucket.
+		if v == nil {
+			nested := bucket.NestedReadWriteBucket(k)
+			return exportBucket(w, k, nested)
+		}
+
+		if err := w.WriteByte(exportRecordValue); err != nil {
+			return err
+		}
+		if err := writeExportField(w, k); err != nil {
+			return err
+		}
+
+		return writeExportField(w, v)
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.WriteByte(exportRecordBucketEnd)
+}
+
+// writeUvarint writes the given value as an unsigned varint to w.
+func writeUvarint(w io.Writer, value uint64) error {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], value)
+	_, err := w.Write(b[:n])
+
+	return err
+}
+
+// writeExportField writes the given field to w, prefixed by its length.
+func writeExportField(w io.Writer, field []byte) error {
+	if err := writeUvarint(w, uint64(len(field))); err != nil {
+		return err
+	}
+	_, err := w.Write(field)
+
+	return err
+}
+
+// readExportField reads a field written by writeExportField from r.
+func readExportField(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxExportFieldSize {
+		return nil, fmt.Errorf("%w: field of %d bytes exceeds "+
+			"maximum", ErrInvalidExport, size)
+	}
+
+	field := make([]byte, size)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// exportRecord is a single record of an export.
+type exportRecord struct {
+	recordType byte
+	key        []byte
+	value      []byte
+	sequence   uint64
+}
+
+// readExportRecords reads up to the given number of records from r. Fewer
+// records are only returned once the end of r is reached.
+func readExportRecords(r *bufio.Reader, num int) ([]exportRecord, error) {
+	records := make([]exportRecord, 0, num)
+	for len(records) < num {
+		recordType, err := r.ReadByte()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := exportRecord{
+			recordType: recordType,
+		}
+		switch recordType {
+		case exportRecordBucket:
+			record.key, err = readExportField(r)
+			if err != nil {
+				return nil, err
+			}
+
+			record.sequence, err = binary.ReadUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+
+		case exportRecordBucketEnd:
+
+		case exportRecordValue:
+			record.key, err = readExportField(r)
+			if err != nil {
+				return nil, err
+			}
+
+			record.value, err = readExportField(r)
+			if err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("%w: unknown record type %d",
+				ErrInvalidExport, recordType)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// ImportForMigration imports an export created by ExportForMigration into the
+// given database backend, which must not contain any data yet. The checksum
+// of the export is verified before anything is written.
+func ImportForMigration(db kvdb.Backend, r io.ReadSeeker) error {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	headerSize := int64(len(exportMagic) + 1)
+	if size < headerSize+sha256.Size {
+		return fmt.Errorf("%w: export too short", ErrInvalidExport)
+	}
+	dataSize := size - sha256.Size
+
+	// Verify the checksum first, so that we don't import corrupted data.
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	checksum := sha256.New()
+	if _, err := io.CopyN(checksum, r, dataSize); err != nil {
+		return err
+	}
+	expectedChecksum := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(r, expectedChecksum); err != nil {
+		return err
+	}
+	if !bytes.Equal(checksum.Sum(nil), expectedChecksum) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidExport)
+	}
+
+	// Make sure that we don't mix the export with existing data.
+	err = kvdb.View(db, func(tx kvdb.RTx) error {
+		return tx.ForEachBucket(func(_ []byte) error {
+			return ErrImportTargetNotEmpty
+		})
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	br := bufio.NewReader(io.LimitReader(r, dataSize))
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return err
+	}
+	if !bytes.Equal(header[:len(exportMagic)], exportMagic) {
+		return fmt.Errorf("%w: unknown format", ErrInvalidExport)
+	}
+	if header[len(exportMagic)] != exportVersion {
+		return fmt.Errorf("%w: unknown version %d", ErrInvalidExport,
+			header[len(exportMagic)])
+	}
+
+	// We import the records in batches. Since every batch is imported in
+	// a new transaction, we keep track of the path to the current bucket
+	// between them.
+	var (
+		path     [][]byte
+		imported int
+	)
+	for {
+		records, err := readExportRecords(br, importBatchSize)
+		if err != nil {
+			return fmt.Errorf("unable to read export: %w", err)
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		var newPath [][]byte
+		err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+			var err error
+			newPath, err = importRecords(tx, path, records)
+
+			return err
+		}, func() {
+			newPath = nil
+		})
+		if err != nil {
+			return fmt.Errorf("unable to import records: %w", err)
+		}
+
+		path = newPath
+		imported += len(records)
+
+		log.Debugf("Imported %d records into channel db", imported)
+	}
+
+	if len(path) != 0 {
+		return fmt.Errorf("%w: unterminated bucket", ErrInvalidExport)
+	}
+
+	log.Infof("Imported %d records into channel db", imported)
+
+	return nil
+}
+
+// importRecords imports the given records within the given transaction,
+// starting in the bucket with the given path. The path to the bucket of the
+// last record is returned.
+func importRecords(tx kvdb.RwTx, path [][]byte,
+	records []exportRecord) ([][]byte, error) {
+
+	path = append([][]byte(nil), path...)
+
+	// openPath opens the bucket with the current path, which is nil for
+	// the root of the database.
+	openPath := func() kvdb.RwBucket {
+		if len(path) == 0 {
+			return nil
+		}
+
+		bucket := tx.ReadWriteBucket(path[0])
+		for _, key := range path[1:] {
+			if bucket == nil {
+				return nil
+			}
+			bucket = bucket.NestedReadWriteBucket(key)
+		}
+
+		return bucket
+	}
+
+	bucket := openPath()
+	for _, record := range records {
+		switch record.recordType {
+		case exportRecordBucket:
+			var (
+				nested kvdb.RwBucket
+				err    error
+			)
+			if bucket == nil {
+				nested, err = tx.CreateTopLevelBucket(
+					record.key,
+				)
+			} else {
+				nested, err = bucket.CreateBucket(record.key)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if record.sequence != 0 {
+				err := nested.SetSequence(record.sequence)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			path = append(path, record.key)
+			bucket = nested
+
+		case exportRecordBucketEnd:
+			if len(path) == 0 {
+				return nil, fmt.Errorf("%w: unexpected bucket "+
+					"end", ErrInvalidExport)
+			}
+
+			path = path[:len(path)-1]
+			bucket = openPath()
+
+		case exportRecordValue:
+			if bucket == nil {
+				return nil, fmt.Errorf("%w: value outside of "+
+					"bucket", ErrInvalidExport)
+			}
+
+			err := bucket.Put(record.key, record.value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return path, nil
+}
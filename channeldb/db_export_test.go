@@ -0,0 +1,162 @@
+package channeldb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportImportForMigration tests that a channel db can be exported and
+// imported into a fresh database, and that the exported database is fenced
+// off.
+func TestExportImportForMigration(t *testing.T) {
+	t.Parallel()
+
+	cdb, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	// Create a channel and a nested bucket with a sequence number, which
+	// both need to survive the migration.
+	channel := createTestChannel(
+		t, cdb.ChannelStateDB(), openChannelOption(),
+	)
+
+	var (
+		bucketKey = []byte("export-test")
+		nestedKey = []byte("nested")
+	)
+	err = kvdb.Update(cdb, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+
+		nested, err := bucket.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		if err := nested.SetSequence(42); err != nil {
+			return err
+		}
+
+		return nested.Put([]byte("key"), []byte("value"))
+	}, func() {})
+	require.NoError(t, err)
+
+	exportPath := filepath.Join(t.TempDir(), "channel.db.export")
+	exportFile, err := os.Create(exportPath)
+	require.NoError(t, err)
+	require.NoError(t, cdb.ExportForMigration(exportFile))
+	require.NoError(t, exportFile.Close())
+
+	// Once exported, the database can't be written to anymore, and it
+	// can't be exported a second time.
+	err = kvdb.Update(cdb, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket([]byte("other"))
+		return err
+	}, func() {})
+	require.ErrorIs(t, err, ErrDBExported)
+
+	_, err = cdb.BeginReadWriteTx()
+	require.ErrorIs(t, err, ErrDBExported)
+
+	err = channel.MarkBorked()
+	require.ErrorIs(t, err, ErrDBExported)
+
+	require.ErrorContains(
+		t, cdb.ExportForMigration(&os.File{}), "tombstone",
+	)
+
+	// The tombstone also keeps the database from being opened again.
+	_, err = CreateWithBackend(cdb.Backend.(*exportFence).Backend)
+	require.ErrorContains(t, err, "tombstone")
+
+	// Import the export into a fresh database.
+	backend, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "cdb")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	exportFile, err = os.Open(exportPath)
+	require.NoError(t, err)
+	defer exportFile.Close()
+
+	require.NoError(t, ImportForMigration(backend, exportFile))
+
+	// A second import isn't possible since the database isn't empty
+	// anymore.
+	require.ErrorIs(
+		t, ImportForMigration(backend, exportFile),
+		ErrImportTargetNotEmpty,
+	)
+
+	// The imported database contains all the data, but no tombstone.
+	importedDB, err := CreateWithBackend(backend)
+	require.NoError(t, err)
+
+	channels, err := importedDB.ChannelStateDB().FetchAllChannels()
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.Equal(t, channel.FundingOutpoint, channels[0].FundingOutpoint)
+
+	err = kvdb.View(importedDB, func(tx kvdb.RTx) error {
+		nested := tx.ReadBucket(bucketKey).NestedReadBucket(nestedKey)
+		require.Equal(t, []byte("value"), nested.Get([]byte("key")))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	err = kvdb.Update(importedDB, func(tx kvdb.RwTx) error {
+		nested := tx.ReadWriteBucket(bucketKey).NestedReadWriteBucket(
+			nestedKey,
+		)
+		require.EqualValues(t, 42, nested.Sequence())
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestImportForMigrationInvalid tests that corrupted exports are rejected
+// before anything is imported.
+func TestImportForMigrationInvalid(t *testing.T) {
+	t.Parallel()
+
+	cdb, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	exportPath := filepath.Join(t.TempDir(), "channel.db.export")
+	exportFile, err := os.Create(exportPath)
+	require.NoError(t, err)
+	require.NoError(t, cdb.ExportForMigration(exportFile))
+	require.NoError(t, exportFile.Close())
+
+	export, err := os.ReadFile(exportPath)
+	require.NoError(t, err)
+
+	// Flip a bit in the middle of the export.
+	export[len(export)/2] ^= 1
+	require.NoError(t, os.WriteFile(exportPath, export, 0600))
+
+	backend, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "cdb")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	exportFile, err = os.Open(exportPath)
+	require.NoError(t, err)
+	defer exportFile.Close()
+
+	err = ImportForMigration(backend, exportFile)
+	require.ErrorIs(t, err, ErrInvalidExport)
+
+	// Nothing was imported.
+	err = kvdb.View(backend, func(tx kvdb.RTx) error {
+		return tx.ForEachBucket(func(_ []byte) error {
+			return ErrImportTargetNotEmpty
+		})
+	}, func() {})
+	require.NoError(t, err)
+}
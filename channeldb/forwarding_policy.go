@@ -23,12 +23,18 @@ func (c *ChannelStateDB) SaveInitialForwardingPolicy(chanID lnwire.ChannelID,
 	chanIDCopy := make([]byte, 32)
 	copy(chanIDCopy, chanID[:])
 
-	scratch := make([]byte, 36)
+	scratch := make([]byte, 44)
 	byteOrder.PutUint64(scratch[:8], uint64(forwardingPolicy.MinHTLCOut))
 	byteOrder.PutUint64(scratch[8:16], uint64(forwardingPolicy.MaxHTLC))
 	byteOrder.PutUint64(scratch[16:24], uint64(forwardingPolicy.BaseFee))
 	byteOrder.PutUint64(scratch[24:32], uint64(forwardingPolicy.FeeRate))
-	byteOrder.PutUint32(scratch[32:], forwardingPolicy.TimeLockDelta)
+	byteOrder.PutUint32(scratch[32:36], forwardingPolicy.TimeLockDelta)
+	byteOrder.PutUint32(
+		scratch[36:40], uint32(forwardingPolicy.InboundFee.Base),
+	)
+	byteOrder.PutUint32(
+		scratch[40:], uint32(forwardingPolicy.InboundFee.Rate),
+	)
 
 	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
 		bucket, err := tx.CreateTopLevelBucket(
@@ -82,6 +88,15 @@ func (c *ChannelStateDB) GetInitialForwardingPolicy(
 			TimeLockDelta: byteOrder.Uint32(stateBytes[32:36]),
 		}
 
+		// Policies stored before inbound fees were added don't have
+		// an inbound fee.
+		if len(stateBytes) >= 44 {
+			forwardingPolicy.InboundFee = models.InboundFee{
+				Base: int32(byteOrder.Uint32(stateBytes[36:40])),
+				Rate: int32(byteOrder.Uint32(stateBytes[40:44])),
+			}
+		}
+
 		return nil
 	}, func() {
 		forwardingPolicy = nil
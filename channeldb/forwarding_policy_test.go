@@ -0,0 +1,62 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInitialForwardingPolicy tests that the initial forwarding policy of a
+// channel, including its inbound fee, is stored and fetched correctly, and
+// that policies stored without an inbound fee can still be read.
+func TestInitialForwardingPolicy(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+	cdb := fullDB.ChannelStateDB()
+
+	chanID := lnwire.ChannelID{1}
+	policy := &models.ForwardingPolicy{
+		MinHTLCOut:    1,
+		MaxHTLC:       2,
+		BaseFee:       3,
+		FeeRate:       4,
+		TimeLockDelta: 5,
+		InboundFee: models.InboundFee{
+			Base: -6,
+			Rate: -7,
+		},
+	}
+
+	require.NoError(t, cdb.SaveInitialForwardingPolicy(chanID, policy))
+
+	stored, err := cdb.GetInitialForwardingPolicy(chanID)
+	require.NoError(t, err)
+	require.Equal(t, policy, stored)
+
+	// Policies stored before inbound fees were added lack the last eight
+	// bytes, and are read with a zero inbound fee.
+	err = kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(
+			initialChannelForwardingPolicyBucket,
+		)
+		value := bucket.Get(chanID[:])
+
+		return bucket.Put(chanID[:], value[:36])
+	}, func() {})
+	require.NoError(t, err)
+
+	stored, err = cdb.GetInitialForwardingPolicy(chanID)
+	require.NoError(t, err)
+
+	policy.InboundFee = models.InboundFee{}
+	require.Equal(t, policy, stored)
+
+	require.NoError(t, cdb.DeleteInitialForwardingPolicy(chanID))
+	_, err = cdb.GetInitialForwardingPolicy(chanID)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+}
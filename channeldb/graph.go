@@ -16,6 +16,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -191,8 +192,9 @@ type ChannelGraph struct {
 // NewChannelGraph allocates a new ChannelGraph backed by a DB instance. The
 // returned instance has its own unique reject cache and channel cache.
 func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
-	batchCommitInterval time.Duration, preAllocCacheNumNodes int,
-	useGraphCache, noMigrations bool) (*ChannelGraph, error) {
+	batchCommitInterval time.Duration, maxBatchSize,
+	preAllocCacheNumNodes int, useGraphCache,
+	noMigrations bool) (*ChannelGraph, error) {
 
 	if !noMigrations {
 		if err := initChannelGraph(db); err != nil {
@@ -206,10 +208,10 @@ func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
 		chanCache:   newChannelCache(chanCacheSize),
 	}
 	g.chanScheduler = batch.NewTimeScheduler(
-		db, &g.cacheMu, batchCommitInterval,
+		db, &g.cacheMu, batchCommitInterval, maxBatchSize,
 	)
 	g.nodeScheduler = batch.NewTimeScheduler(
-		db, nil, batchCommitInterval,
+		db, nil, batchCommitInterval, maxBatchSize,
 	)
 
 	// The graph cache can be turned off (e.g. for mobile users) for a
@@ -220,6 +222,10 @@ func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
 		log.Debugf("Populating in-memory channel graph, this might " +
 			"take a while...")
 
+		// The nodes and the channels are read one after the other, as
+		// both read the graph buckets of the same database and fill
+		// the same cache, so reading them concurrently only makes
+		// them contend with each other.
 		err := g.ForEachNodeCacheable(
 			func(tx kvdb.RTx, node GraphCacheNode) error {
 				g.graphCache.AddNodeFeatures(node)
@@ -1279,6 +1285,11 @@ const (
 // prune the graph is stored so callers can ensure the graph is fully in sync
 // with the current UTXO state. A slice of channels that have been closed by
 // the target block are returned if the function succeeds without error.
+//
+// All channels closed by the block, the update of the prune log and the
+// removal of their nodes that are left without channels happen within a single
+// database transaction. The in-memory caches are only updated once the
+// transaction committed.
 func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 	blockHash *chainhash.Hash, blockHeight uint32) (
 	[]*models.ChannelEdgeInfo, error) {
@@ -1286,7 +1297,10 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 	c.cacheMu.Lock()
 	defer c.cacheMu.Unlock()
 
-	var chansClosed []*models.ChannelEdgeInfo
+	var (
+		chansClosed []*models.ChannelEdgeInfo
+		nodesPruned []route.Vertex
+	)
 
 	err := kvdb.Update(c.db, func(tx kvdb.RwTx) error {
 		// First grab the edges bucket which houses the information
@@ -1333,27 +1347,18 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 				continue
 			}
 
-			// However, if it does, then we'll read out the full
-			// version so we can add it to the set of deleted
-			// channels.
-			edgeInfo, err := fetchChanEdgeInfo(edgeIndex, chanID)
-			if err != nil {
-				return err
-			}
-
-			// Attempt to delete the channel, an ErrEdgeNotFound
-			// will be returned if that outpoint isn't known to be
-			// a channel. If no error is returned, then a channel
-			// was successfully pruned.
-			err = c.delChannelEdgeUnsafe(
+			// However, if it does, then we'll delete it and add
+			// it to the set of deleted channels. The graph cache
+			// is updated once the transaction committed.
+			edgeInfo, err := delChannelEdge(
 				edges, edgeIndex, chanIndex, zombieIndex,
 				chanID, false, false,
 			)
-			if err != nil && !errors.Is(err, ErrEdgeNotFound) {
+			if err != nil {
 				return err
 			}
 
-			chansClosed = append(chansClosed, &edgeInfo)
+			chansClosed = append(chansClosed, edgeInfo)
 		}
 
 		metaBucket, err := tx.CreateTopLevelBucket(graphMetaBucket)
@@ -1381,11 +1386,17 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 		}
 
 		// Now that the graph has been pruned, we'll also attempt to
-		// prune any nodes that have had a channel closed within the
-		// latest block.
-		return c.pruneGraphNodes(nodes, edgeIndex)
+		// prune the nodes that have had a channel closed within the
+		// latest block. Only those nodes can have lost their last
+		// channel, so there is no need to scan the whole graph.
+		nodesPruned, err = c.pruneClosedChanNodes(
+			nodes, edges, chansClosed,
+		)
+
+		return err
 	}, func() {
 		chansClosed = nil
+		nodesPruned = nil
 	})
 	if err != nil {
 		return nil, err
@@ -1397,6 +1408,16 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 	}
 
 	if c.graphCache != nil {
+		for _, channel := range chansClosed {
+			c.graphCache.RemoveChannel(
+				channel.NodeKey1Bytes, channel.NodeKey2Bytes,
+				channel.ChannelID,
+			)
+		}
+		for _, node := range nodesPruned {
+			c.graphCache.RemoveNode(node)
+		}
+
 		log.Debugf("Pruned graph, cache now has %s",
 			c.graphCache.Stats())
 	}
@@ -1404,6 +1425,62 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 	return chansClosed, nil
 }
 
+// pruneClosedChanNodes removes the nodes of the given closed channels that
+// are left without any channels, and returns the removed nodes. The source
+// node is never removed.
+func (c *ChannelGraph) pruneClosedChanNodes(nodes, edges kvdb.RwBucket,
+	chansClosed []*models.ChannelEdgeInfo) ([]route.Vertex, error) {
+
+	if len(chansClosed) == 0 {
+		return nil, nil
+	}
+
+	sourceNode, err := c.sourceNode(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[route.Vertex]struct{})
+	for _, channel := range chansClosed {
+		candidates[channel.NodeKey1Bytes] = struct{}{}
+		candidates[channel.NodeKey2Bytes] = struct{}{}
+	}
+	delete(candidates, sourceNode.PubKeyBytes)
+
+	var nodesPruned []route.Vertex
+	for node := range candidates {
+		// Every channel of a node has an entry keyed by the node's
+		// public key followed by the channel ID in the edges bucket,
+		// so a node without such an entry has no channels left.
+		cursor := edges.ReadWriteCursor()
+		edgeKey, _ := cursor.Seek(node[:])
+		if edgeKey != nil && bytes.HasPrefix(edgeKey, node[:]) {
+			continue
+		}
+
+		err := c.deleteLightningNode(nodes, node[:])
+		switch {
+		case errors.Is(err, ErrGraphNodeNotFound):
+			continue
+
+		case err != nil:
+			return nil, err
+		}
+
+		log.Infof("Pruned unconnected node %x from channel graph",
+			node[:])
+
+		nodesPruned = append(nodesPruned, node)
+	}
+
+	if len(nodesPruned) > 0 {
+		log.Infof("Pruned %v unconnected nodes from the channel graph",
+			len(nodesPruned))
+	}
+
+	return nodesPruned, nil
+}
+
 // PruneGraphNodes is a garbage collection method which attempts to prune out
 // any nodes from the channel graph that are currently unconnected. This ensure
 // that we only maintain a graph of reachable nodes. In the event that a pruned
@@ -2097,6 +2174,257 @@ func (c *ChannelGraph) NodeUpdatesInHorizon(startTime,
 	return nodesInHorizon, nil
 }
 
+// ForEachChanUpdateInHorizon calls the callback with batches of the known
+// channel edges which have at least one edge that has an update timestamp
+// within the specified horizon. Each batch is read from the edge update index
+// in its own transaction, resuming where the previous batch ended, so that
+// the set of edges is never held in memory at once and the callback can take
+// its time without holding a transaction open. Unlike ChanUpdatesInHorizon,
+// the nodes of the edges aren't fetched.
+func (c *ChannelGraph) ForEachChanUpdateInHorizon(startTime, endTime time.Time,
+	batchSize int, cb func([]ChannelEdge) error) error {
+
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid batch size: %v", batchSize)
+	}
+
+	var startTimeBytes, endTimeBytes [8 + 8]byte
+	byteOrder.PutUint64(startTimeBytes[:8], uint64(startTime.Unix()))
+	byteOrder.PutUint64(endTimeBytes[:8], uint64(endTime.Unix()))
+	endKey := endTimeBytes[:]
+
+	// A channel is in the index once for each of its policies, so we
+	// track the channels we've passed to the callback to not pass them
+	// twice.
+	edgesSeen := make(map[uint64]struct{})
+	resumeKey := startTimeBytes[:]
+
+	for {
+		var (
+			batch     []ChannelEdge
+			batchSeen map[uint64]struct{}
+			nextKey   []byte
+		)
+
+		c.cacheMu.RLock()
+		err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+			edges := tx.ReadBucket(edgeBucket)
+			if edges == nil {
+				return ErrGraphNoEdgesFound
+			}
+			edgeIndex := edges.NestedReadBucket(edgeIndexBucket)
+			if edgeIndex == nil {
+				return ErrGraphNoEdgesFound
+			}
+			edgeUpdateIndex := edges.NestedReadBucket(
+				edgeUpdateIndexBucket,
+			)
+			if edgeUpdateIndex == nil {
+				return ErrGraphNoEdgesFound
+			}
+
+			cursor := edgeUpdateIndex.ReadCursor()
+			indexKey, _ := cursor.Seek(resumeKey)
+			for ; indexKey != nil; indexKey, _ = cursor.Next() {
+				if bytes.Compare(indexKey, endKey) > 0 {
+					break
+				}
+
+				// Stop at the first entry that doesn't fit
+				// into this batch, the next batch starts with
+				// it.
+				if len(batch) == batchSize {
+					nextKey = bytes.Clone(indexKey)
+					return nil
+				}
+
+				chanID := indexKey[8:]
+				chanIDInt := byteOrder.Uint64(chanID)
+				if _, ok := edgesSeen[chanIDInt]; ok {
+					continue
+				}
+				if _, ok := batchSeen[chanIDInt]; ok {
+					continue
+				}
+				batchSeen[chanIDInt] = struct{}{}
+
+				channel, ok := c.chanCache.get(chanIDInt)
+				if ok {
+					batch = append(batch, channel)
+					continue
+				}
+
+				edgeInfo, err := fetchChanEdgeInfo(
+					edgeIndex, chanID,
+				)
+				if err != nil {
+					return fmt.Errorf("unable to fetch "+
+						"info for edge with "+
+						"chan_id=%v: %w", chanIDInt,
+						err)
+				}
+
+				edge1, edge2, err := fetchChanEdgePolicies(
+					edgeIndex, edges, chanID,
+				)
+				if err != nil {
+					return fmt.Errorf("unable to fetch "+
+						"policies for edge with "+
+						"chan_id=%v: %w", chanIDInt,
+						err)
+				}
+
+				batch = append(batch, ChannelEdge{
+					Info:    &edgeInfo,
+					Policy1: edge1,
+					Policy2: edge2,
+				})
+			}
+
+			return nil
+		}, func() {
+			batch = nil
+			batchSeen = make(map[uint64]struct{})
+			nextKey = nil
+		})
+		c.cacheMu.RUnlock()
+
+		switch {
+		case errors.Is(err, ErrGraphNoEdgesFound):
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		for chanID := range batchSeen {
+			edgesSeen[chanID] = struct{}{}
+		}
+
+		if len(batch) > 0 {
+			if err := cb(batch); err != nil {
+				return err
+			}
+		}
+
+		if nextKey == nil {
+			return nil
+		}
+		resumeKey = nextKey
+	}
+}
+
+// ForEachNodeUpdateInHorizon calls the callback with batches of the known
+// lightning nodes which have an update timestamp within the passed range. If
+// publicOnly is set, nodes that aren't publicly advertised are skipped. Each
+// batch is read from the node update index in its own transaction, resuming
+// where the previous batch ended, so that the set of nodes is never held in
+// memory at once.
+func (c *ChannelGraph) ForEachNodeUpdateInHorizon(startTime, endTime time.Time,
+	publicOnly bool, batchSize int, cb func([]LightningNode) error) error {
+
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid batch size: %v", batchSize)
+	}
+
+	var startTimeBytes, endTimeBytes [8 + 33]byte
+	byteOrder.PutUint64(startTimeBytes[:8], uint64(startTime.Unix()))
+	byteOrder.PutUint64(endTimeBytes[:8], uint64(endTime.Unix()))
+	endKey := endTimeBytes[:]
+
+	resumeKey := startTimeBytes[:]
+
+	for {
+		var (
+			batch   []LightningNode
+			nextKey []byte
+		)
+
+		err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+			nodes := tx.ReadBucket(nodeBucket)
+			if nodes == nil {
+				return ErrGraphNodesNotFound
+			}
+
+			nodeUpdateIndex := nodes.NestedReadBucket(
+				nodeUpdateIndexBucket,
+			)
+			if nodeUpdateIndex == nil {
+				return ErrGraphNodesNotFound
+			}
+
+			sourcePubKey := nodes.Get(sourceKey)
+			if publicOnly && sourcePubKey == nil {
+				return ErrSourceNodeNotSet
+			}
+
+			cursor := nodeUpdateIndex.ReadCursor()
+			indexKey, _ := cursor.Seek(resumeKey)
+			for ; indexKey != nil; indexKey, _ = cursor.Next() {
+				if bytes.Compare(indexKey, endKey) > 0 {
+					break
+				}
+
+				// Stop at the first entry that doesn't fit
+				// into this batch, the next batch starts with
+				// it.
+				if len(batch) == batchSize {
+					nextKey = bytes.Clone(indexKey)
+					return nil
+				}
+
+				node, err := fetchLightningNode(
+					nodes, indexKey[8:],
+				)
+				if err != nil {
+					return err
+				}
+
+				// The public check is done in the same
+				// transaction, instead of opening one per
+				// node.
+				if publicOnly {
+					isPublic, err := c.isPublic(
+						tx, node.PubKeyBytes,
+						sourcePubKey,
+					)
+					if err != nil {
+						return err
+					}
+					if !isPublic {
+						continue
+					}
+				}
+
+				batch = append(batch, node)
+			}
+
+			return nil
+		}, func() {
+			batch = nil
+			nextKey = nil
+		})
+		switch {
+		case errors.Is(err, ErrGraphNodesNotFound):
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		if len(batch) > 0 {
+			if err := cb(batch); err != nil {
+				return err
+			}
+		}
+
+		if nextKey == nil {
+			return nil
+		}
+		resumeKey = nextKey
+	}
+}
+
 // FilterKnownChanIDs takes a set of channel IDs and return the subset of chan
 // ID's that we don't know and are not known zombies of the passed set. In other
 // words, we perform a set difference of our set of chan ID's and the ones
@@ -2530,17 +2858,38 @@ func (c *ChannelGraph) delChannelEdgeUnsafe(edges, edgeIndex, chanIndex,
 		)
 	}
 
+	_, err = delChannelEdge(
+		edges, edgeIndex, chanIndex, zombieIndex, chanID, isZombie,
+		strictZombie,
+	)
+
+	return err
+}
+
+// delChannelEdge deletes any policy info and edge info for the channel with
+// the given chanID from the DB and, if isZombie is true, adds an entry for
+// this channel in the zombie index. It returns the info of the deleted
+// channel.
+func delChannelEdge(edges, edgeIndex, chanIndex, zombieIndex kvdb.RwBucket,
+	chanID []byte, isZombie, strictZombie bool) (*models.ChannelEdgeInfo,
+	error) {
+
+	edgeInfo, err := fetchChanEdgeInfo(edgeIndex, chanID)
+	if err != nil {
+		return nil, err
+	}
+
 	// We'll also remove the entry in the edge update index bucket before
 	// we delete the edges themselves so we can access their last update
 	// times.
 	cid := byteOrder.Uint64(chanID)
 	edge1, edge2, err := fetchChanEdgePolicies(edgeIndex, edges, chanID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = delEdgeUpdateIndexEntry(edges, cid, edge1, edge2)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// The edge key is of the format pubKey || chanID. First we construct
@@ -2554,13 +2903,13 @@ func (c *ChannelGraph) delChannelEdgeUnsafe(edges, edgeIndex, chanIndex,
 	copy(edgeKey[:33], edgeInfo.NodeKey1Bytes[:])
 	if edges.Get(edgeKey[:]) != nil {
 		if err := edges.Delete(edgeKey[:]); err != nil {
-			return err
+			return nil, err
 		}
 	}
 	copy(edgeKey[:33], edgeInfo.NodeKey2Bytes[:])
 	if edges.Get(edgeKey[:]) != nil {
 		if err := edges.Delete(edgeKey[:]); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -2572,21 +2921,21 @@ func (c *ChannelGraph) delChannelEdgeUnsafe(edges, edgeIndex, chanIndex,
 	// With the edge data deleted, we can purge the information from the two
 	// edge indexes.
 	if err := edgeIndex.Delete(chanID); err != nil {
-		return err
+		return nil, err
 	}
 	var b bytes.Buffer
 	if err := writeOutpoint(&b, &edgeInfo.ChannelPoint); err != nil {
-		return err
+		return nil, err
 	}
 	if err := chanIndex.Delete(b.Bytes()); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Finally, we'll mark the edge as a zombie within our index if it's
 	// being removed due to the channel becoming a zombie. We do this to
 	// ensure we don't store unnecessary data for spent channels.
 	if !isZombie {
-		return nil
+		return &edgeInfo, nil
 	}
 
 	nodeKey1, nodeKey2 := edgeInfo.NodeKey1Bytes, edgeInfo.NodeKey2Bytes
@@ -2594,9 +2943,14 @@ func (c *ChannelGraph) delChannelEdgeUnsafe(edges, edgeIndex, chanIndex,
 		nodeKey1, nodeKey2 = makeZombiePubkeys(&edgeInfo, edge1, edge2)
 	}
 
-	return markEdgeZombie(
+	err = markEdgeZombie(
 		zombieIndex, byteOrder.Uint64(chanID), nodeKey1, nodeKey2,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &edgeInfo, nil
 }
 
 // makeZombiePubkeys derives the node pubkeys to store in the zombie index for a
@@ -4119,7 +4473,15 @@ func putChanEdgeInfo(edgeIndex kvdb.RwBucket,
 
 	authProof := edgeInfo.AuthProof
 	var nodeSig1, nodeSig2, bitcoinSig1, bitcoinSig2 []byte
-	if authProof != nil {
+	switch {
+	// The Schnorr signature of a taproot channel is stored in place of the
+	// first node signature, with the other signatures left empty. Older
+	// versions will read this as an empty proof and treat the channel as
+	// unannounced.
+	case authProof != nil && authProof.IsTaproot():
+		nodeSig1 = authProof.SchnorrSigBytes
+
+	case authProof != nil:
 		nodeSig1 = authProof.NodeSig1Bytes
 		nodeSig2 = authProof.NodeSig2Bytes
 		bitcoinSig1 = authProof.BitcoinSig1Bytes
@@ -4218,6 +4580,18 @@ func deserializeChanEdgeInfo(r io.Reader) (models.ChannelEdgeInfo, error) {
 		return models.ChannelEdgeInfo{}, err
 	}
 
+	// A proof that only has the first signature set holds the Schnorr
+	// signature of a taproot channel.
+	if len(proof.NodeSig1Bytes) == schnorr.SignatureSize &&
+		len(proof.NodeSig2Bytes) == 0 &&
+		len(proof.BitcoinSig1Bytes) == 0 &&
+		len(proof.BitcoinSig2Bytes) == 0 {
+
+		proof = &models.ChannelAuthProof{
+			SchnorrSigBytes: proof.NodeSig1Bytes,
+		}
+	}
+
 	if !proof.IsEmpty() {
 		edgeInfo.AuthProof = proof
 	}
@@ -1,6 +1,7 @@
 package channeldb
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 
@@ -83,11 +84,90 @@ func (c *DirectedChannel) DeepCopy() *DirectedChannel {
 	return &channelCopy
 }
 
+// cachedFeatures is a feature vector that is shared by all nodes in the cache
+// that announced the same features. Most nodes of the network run one of a few
+// implementations and versions, so interning their features saves an
+// allocation per node.
+type cachedFeatures struct {
+	// key is the serialized feature vector the features are interned by.
+	key string
+
+	// features is the shared feature vector.
+	features *lnwire.FeatureVector
+
+	// refs is the number of nodes that share the features.
+	refs int
+}
+
+// cachedNode is a node of the graph cache. Nodes are referenced by their index
+// in the cache instead of their public key to keep the channels compact.
+type cachedNode struct {
+	// pubKey is the public key of the node.
+	pubKey route.Vertex
+
+	// features are the features of the node, or nil if they aren't known.
+	features *cachedFeatures
+
+	// channels are the indexes of the channels of the node. It is nil if
+	// no channel was ever added for the node.
+	channels []uint32
+}
+
+// cachedPolicy is the part of a channel edge policy that is needed for path
+// finding.
+type cachedPolicy struct {
+	messageFlags  lnwire.ChanUpdateMsgFlags
+	channelFlags  lnwire.ChanUpdateChanFlags
+	timeLockDelta uint16
+	minHTLC       lnwire.MilliSatoshi
+	maxHTLC       lnwire.MilliSatoshi
+	feeBase       lnwire.MilliSatoshi
+	feeRate       lnwire.MilliSatoshi
+}
+
+// cachedChannel is a channel of the graph cache. All fields that depend on the
+// direction are indexed by 0 for node 1 and by 1 for node 2 of the channel.
+type cachedChannel struct {
+	// channelID is the unique identifier of the channel.
+	channelID uint64
+
+	// capacity is the announced capacity of the channel.
+	capacity btcutil.Amount
+
+	// nodes are the indexes of the two nodes of the channel.
+	nodes [2]uint32
+
+	// policySet indicates whether the outgoing policy of the node is set.
+	policySet [2]bool
+
+	// policies are the outgoing policies of the nodes.
+	policies [2]cachedPolicy
+
+	// inboundFees are the inbound fees of the nodes.
+	inboundFees [2]lnwire.Fee
+}
+
 // GraphCache is a type that holds a minimal set of information of the public
-// channel graph that can be used for pathfinding.
+// channel graph that can be used for pathfinding. To keep the memory footprint
+// and the pressure on the garbage collector low on large graphs, nodes and
+// channels are packed into slices and reference each other by index, rather
+// than by public key and pointer.
 type GraphCache struct {
-	nodeChannels map[route.Vertex]map[uint64]*DirectedChannel
-	nodeFeatures map[route.Vertex]*lnwire.FeatureVector
+	// nodes are all nodes of the cache. Slots of removed nodes are reused
+	// through freeNodes.
+	nodes     []cachedNode
+	nodeIndex map[route.Vertex]uint32
+	freeNodes []uint32
+
+	// channels are all channels of the cache. Slots of removed channels
+	// are reused through freeChannels.
+	channels     []cachedChannel
+	channelIndex map[uint64]uint32
+	freeChannels []uint32
+
+	// features are the distinct feature vectors of the nodes, by their
+	// serialization.
+	features map[string]*cachedFeatures
 
 	mtx sync.RWMutex
 }
@@ -95,17 +175,15 @@ type GraphCache struct {
 // NewGraphCache creates a new graphCache.
 func NewGraphCache(preAllocNumNodes int) *GraphCache {
 	return &GraphCache{
-		nodeChannels: make(
-			map[route.Vertex]map[uint64]*DirectedChannel,
-			// A channel connects two nodes, so we can look it up
-			// from both sides, meaning we get double the number of
-			// entries.
-			preAllocNumNodes*2,
-		),
-		nodeFeatures: make(
-			map[route.Vertex]*lnwire.FeatureVector,
-			preAllocNumNodes,
+		nodes:     make([]cachedNode, 0, preAllocNumNodes),
+		nodeIndex: make(map[route.Vertex]uint32, preAllocNumNodes),
+		channels: make(
+			// There are about five times as many channels as
+			// nodes in the public graph.
+			[]cachedChannel, 0, preAllocNumNodes*5,
 		),
+		channelIndex: make(map[uint64]uint32, preAllocNumNodes*5),
+		features:     make(map[string]*cachedFeatures),
 	}
 }
 
@@ -114,26 +192,104 @@ func (c *GraphCache) Stats() string {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
 
-	numChannels := 0
-	for node := range c.nodeChannels {
-		numChannels += len(c.nodeChannels[node])
+	numNodeFeatures, numNodes := 0, 0
+	for _, idx := range c.nodeIndex {
+		node := &c.nodes[idx]
+		if node.features != nil {
+			numNodeFeatures++
+		}
+		if node.channels != nil {
+			numNodes++
+		}
 	}
+
+	// Every channel can be looked up from both sides.
 	return fmt.Sprintf("num_node_features=%d, num_nodes=%d, "+
-		"num_channels=%d", len(c.nodeFeatures), len(c.nodeChannels),
-		numChannels)
+		"num_channels=%d, num_distinct_features=%d", numNodeFeatures,
+		numNodes, len(c.channelIndex)*2, len(c.features))
+}
+
+// internNode returns the index of the node with the given public key, adding
+// the node if it isn't known yet.
+//
+// NOTE: The write lock must be held when calling this method.
+func (c *GraphCache) internNode(pubKey route.Vertex) uint32 {
+	if idx, ok := c.nodeIndex[pubKey]; ok {
+		return idx
+	}
+
+	var idx uint32
+	if n := len(c.freeNodes); n > 0 {
+		idx = c.freeNodes[n-1]
+		c.freeNodes = c.freeNodes[:n-1]
+		c.nodes[idx] = cachedNode{pubKey: pubKey}
+	} else {
+		idx = uint32(len(c.nodes))
+		c.nodes = append(c.nodes, cachedNode{pubKey: pubKey})
+	}
+	c.nodeIndex[pubKey] = idx
+
+	return idx
+}
+
+// internFeatures returns the shared instance of the given features, adding them
+// if no node with the same features is known yet.
+//
+// NOTE: The write lock must be held when calling this method.
+func (c *GraphCache) internFeatures(
+	features *lnwire.FeatureVector) *cachedFeatures {
+
+	// A nil feature vector is interned as well, as it has a different
+	// meaning than an empty one.
+	var key string
+	if features != nil && features.RawFeatureVector != nil {
+		var b bytes.Buffer
+		if err := features.Encode(&b); err != nil {
+			// Features that can't be serialized aren't shared.
+			return &cachedFeatures{features: features, refs: 1}
+		}
+		key = b.String()
+	}
+
+	shared, ok := c.features[key]
+	if !ok {
+		shared = &cachedFeatures{key: key, features: features}
+		c.features[key] = shared
+	}
+	shared.refs++
+
+	return shared
+}
+
+// releaseFeatures releases a reference to the given shared features.
+//
+// NOTE: The write lock must be held when calling this method.
+func (c *GraphCache) releaseFeatures(features *cachedFeatures) {
+	if features == nil {
+		return
+	}
+
+	features.refs--
+	if features.refs == 0 && c.features[features.key] == features {
+		delete(c.features, features.key)
+	}
 }
 
 // AddNodeFeatures adds a graph node and its features to the cache.
 func (c *GraphCache) AddNodeFeatures(node GraphCacheNode) {
 	nodePubKey := node.PubKey()
+	features := node.Features()
 
 	// Only hold the lock for a short time. The `ForEachChannel()` below is
 	// possibly slow as it has to go to the backend, so we can unlock
 	// between the calls. And the AddChannel() method will acquire its own
 	// lock anyway.
 	c.mtx.Lock()
-	c.nodeFeatures[nodePubKey] = node.Features()
-	c.mtx.Unlock()
+	defer c.mtx.Unlock()
+
+	cached := &c.nodes[c.internNode(nodePubKey)]
+	c.releaseFeatures(cached.features)
+	cached.features = c.internFeatures(features)
 }
 
 // AddNode adds a graph node, including all the (directed) channels of that
@@ -170,20 +326,9 @@ func (c *GraphCache) AddChannel(info *models.ChannelEdgeInfo,
 		return
 	}
 
-	// Create the edge entry for both nodes.
+	// Create the channel, or reset it if it already exists.
 	c.mtx.Lock()
-	c.updateOrAddEdge(info.NodeKey1Bytes, &DirectedChannel{
-		ChannelID: info.ChannelID,
-		IsNode1:   true,
-		OtherNode: info.NodeKey2Bytes,
-		Capacity:  info.Capacity,
-	})
-	c.updateOrAddEdge(info.NodeKey2Bytes, &DirectedChannel{
-		ChannelID: info.ChannelID,
-		IsNode1:   false,
-		OtherNode: info.NodeKey1Bytes,
-		Capacity:  info.Capacity,
-	})
+	c.addChannel(info)
 	c.mtx.Unlock()
 
 	// The policy's node is always the to_node. So if policy 1 has to_node
@@ -206,20 +351,40 @@ func (c *GraphCache) AddChannel(info *models.ChannelEdgeInfo,
 	}
 }
 
-// updateOrAddEdge makes sure the edge information for a node is either updated
-// if it already exists or is added to that node's list of channels.
-func (c *GraphCache) updateOrAddEdge(node route.Vertex, edge *DirectedChannel) {
-	if len(c.nodeChannels[node]) == 0 {
-		c.nodeChannels[node] = make(map[uint64]*DirectedChannel)
+// addChannel adds the given channel without any policies, replacing the
+// channel if it already exists.
+//
+// NOTE: The write lock must be held when calling this method.
+func (c *GraphCache) addChannel(info *models.ChannelEdgeInfo) {
+	c.removeChannel(info.ChannelID)
+
+	node1 := c.internNode(info.NodeKey1Bytes)
+	node2 := c.internNode(info.NodeKey2Bytes)
+	channel := cachedChannel{
+		channelID: info.ChannelID,
+		capacity:  info.Capacity,
+		nodes:     [2]uint32{node1, node2},
+	}
+
+	var idx uint32
+	if n := len(c.freeChannels); n > 0 {
+		idx = c.freeChannels[n-1]
+		c.freeChannels = c.freeChannels[:n-1]
+		c.channels[idx] = channel
+	} else {
+		idx = uint32(len(c.channels))
+		c.channels = append(c.channels, channel)
 	}
+	c.channelIndex[info.ChannelID] = idx
 
-	c.nodeChannels[node][edge.ChannelID] = edge
+	c.nodes[node1].channels = append(c.nodes[node1].channels, idx)
+	c.nodes[node2].channels = append(c.nodes[node2].channels, idx)
 }
 
 // UpdatePolicy updates a single policy on both the from and to node. The order
 // of the from and to node is not strictly important. But we assume that a
-// channel edge was added beforehand so that the directed channel struct already
-// exists in the cache.
+// channel edge was added beforehand so that the channel already exists in the
+// cache.
 func (c *GraphCache) UpdatePolicy(policy *models.ChannelEdgePolicy, fromNode,
 	toNode route.Vertex, edge1 bool) {
 
@@ -234,40 +399,31 @@ func (c *GraphCache) UpdatePolicy(policy *models.ChannelEdgePolicy, fromNode,
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	updatePolicy := func(nodeKey route.Vertex) {
-		if len(c.nodeChannels[nodeKey]) == 0 {
-			return
-		}
-
-		channel, ok := c.nodeChannels[nodeKey][policy.ChannelID]
-		if !ok {
-			return
-		}
+	idx, ok := c.channelIndex[policy.ChannelID]
+	if !ok {
+		return
+	}
 
-		// Edge 1 is defined as the policy for the direction of node1 to
-		// node2.
-		switch {
-		// This is node 1, and it is edge 1, so this is the outgoing
-		// policy for node 1.
-		case channel.IsNode1 && edge1:
-			channel.OutPolicySet = true
-			channel.InboundFee = inboundFee
-
-		// This is node 2, and it is edge 2, so this is the outgoing
-		// policy for node 2.
-		case !channel.IsNode1 && !edge1:
-			channel.OutPolicySet = true
-			channel.InboundFee = inboundFee
-
-		// The other two cases left mean it's the inbound policy for the
-		// node.
-		default:
-			channel.InPolicy = models.NewCachedPolicy(policy)
-		}
+	// Edge 1 is defined as the policy for the direction of node1 to node2,
+	// so it is the outgoing policy of node 1 and the incoming policy of
+	// node 2.
+	dir := 1
+	if edge1 {
+		dir = 0
 	}
 
-	updatePolicy(fromNode)
-	updatePolicy(toNode)
+	channel := &c.channels[idx]
+	channel.policySet[dir] = true
+	channel.inboundFees[dir] = inboundFee
+	channel.policies[dir] = cachedPolicy{
+		messageFlags:  policy.MessageFlags,
+		channelFlags:  policy.ChannelFlags,
+		timeLockDelta: policy.TimeLockDelta,
+		minHTLC:       policy.MinHTLC,
+		maxHTLC:       policy.MaxHTLC,
+		feeBase:       policy.FeeBaseMSat,
+		feeRate:       policy.FeeProportionalMillionths,
+	}
 }
 
 // RemoveNode completely removes a node and all its channels (including the
@@ -276,15 +432,23 @@ func (c *GraphCache) RemoveNode(node route.Vertex) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	delete(c.nodeFeatures, node)
+	idx, ok := c.nodeIndex[node]
+	if !ok {
+		return
+	}
 
-	// First remove all channels from the other nodes' lists.
-	for _, channel := range c.nodeChannels[node] {
-		c.removeChannelIfFound(channel.OtherNode, channel.ChannelID)
+	// First remove all channels from the other nodes' lists. We iterate
+	// over a copy, as removing a channel modifies the list.
+	channels := append([]uint32(nil), c.nodes[idx].channels...)
+	for _, chanIdx := range channels {
+		c.removeChannel(c.channels[chanIdx].channelID)
 	}
 
 	// Then remove our whole node completely.
-	delete(c.nodeChannels, node)
+	c.releaseFeatures(c.nodes[idx].features)
+	c.nodes[idx] = cachedNode{}
+	c.freeNodes = append(c.freeNodes, idx)
+	delete(c.nodeIndex, node)
 }
 
 // RemoveChannel removes a single channel between two nodes.
@@ -293,17 +457,38 @@ func (c *GraphCache) RemoveChannel(node1, node2 route.Vertex, chanID uint64) {
 	defer c.mtx.Unlock()
 
 	// Remove that one channel from both sides.
-	c.removeChannelIfFound(node1, chanID)
-	c.removeChannelIfFound(node2, chanID)
+	c.removeChannel(chanID)
 }
 
-// removeChannelIfFound removes a single channel from one side.
-func (c *GraphCache) removeChannelIfFound(node route.Vertex, chanID uint64) {
-	if len(c.nodeChannels[node]) == 0 {
+// removeChannel removes a single channel from both sides, if it exists.
+//
+// NOTE: The write lock must be held when calling this method.
+func (c *GraphCache) removeChannel(chanID uint64) {
+	idx, ok := c.channelIndex[chanID]
+	if !ok {
 		return
 	}
 
-	delete(c.nodeChannels[node], chanID)
+	for _, nodeIdx := range c.channels[idx].nodes {
+		node := &c.nodes[nodeIdx]
+		for i, chanIdx := range node.channels {
+			if chanIdx != idx {
+				continue
+			}
+
+			// The order of the channels doesn't matter, so we
+			// move the last one into the gap.
+			last := len(node.channels) - 1
+			node.channels[i] = node.channels[last]
+			node.channels = node.channels[:last]
+
+			break
+		}
+	}
+
+	c.channels[idx] = cachedChannel{}
+	c.freeChannels = append(c.freeChannels, idx)
+	delete(c.channelIndex, chanID)
 }
 
 // UpdateChannel updates the channel edge information for a specific edge. We
@@ -313,25 +498,65 @@ func (c *GraphCache) UpdateChannel(info *models.ChannelEdgeInfo) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	if len(c.nodeChannels[info.NodeKey1Bytes]) == 0 ||
-		len(c.nodeChannels[info.NodeKey2Bytes]) == 0 {
+	idx, ok := c.channelIndex[info.ChannelID]
+	if !ok {
+		return
+	}
 
+	// We only expect to be called when the channel is already known, so
+	// the nodes of the channel can't change.
+	channel := &c.channels[idx]
+	if c.nodes[channel.nodes[0]].pubKey != info.NodeKey1Bytes ||
+		c.nodes[channel.nodes[1]].pubKey != info.NodeKey2Bytes {
+
+		return
+	}
+
+	channel.capacity = info.Capacity
+}
+
+// directedChannel returns the given side of the channel as a directed channel.
+// The incoming policy is written to the given policy if it is set.
+//
+// NOTE: The read lock must be held when calling this method.
+func (c *GraphCache) directedChannel(channel *cachedChannel, side int,
+	directed *DirectedChannel, inPolicy *models.CachedEdgePolicy) {
+
+	other := 1 - side
+	*directed = DirectedChannel{
+		ChannelID:    channel.channelID,
+		IsNode1:      side == 0,
+		OtherNode:    c.nodes[channel.nodes[other]].pubKey,
+		Capacity:     channel.capacity,
+		OutPolicySet: channel.policySet[side],
+		InboundFee:   channel.inboundFees[side],
+	}
+
+	if !channel.policySet[other] {
 		return
 	}
 
-	channel, ok := c.nodeChannels[info.NodeKey1Bytes][info.ChannelID]
-	if ok {
-		// We only expect to be called when the channel is already
-		// known.
-		channel.Capacity = info.Capacity
-		channel.OtherNode = info.NodeKey2Bytes
+	policy := &channel.policies[other]
+	*inPolicy = models.CachedEdgePolicy{
+		ChannelID:                 channel.channelID,
+		MessageFlags:              policy.messageFlags,
+		ChannelFlags:              policy.channelFlags,
+		TimeLockDelta:             policy.timeLockDelta,
+		MinHTLC:                   policy.minHTLC,
+		MaxHTLC:                   policy.maxHTLC,
+		FeeBaseMSat:               policy.feeBase,
+		FeeProportionalMillionths: policy.feeRate,
 	}
+	directed.InPolicy = inPolicy
+}
 
-	channel, ok = c.nodeChannels[info.NodeKey2Bytes][info.ChannelID]
-	if ok {
-		channel.Capacity = info.Capacity
-		channel.OtherNode = info.NodeKey1Bytes
+// side returns the side of the channel the given node is on.
+func (c *cachedChannel) side(nodeIdx uint32) int {
+	if c.nodes[0] == nodeIdx {
+		return 0
 	}
+
+	return 1
 }
 
 // getChannels returns a copy of the passed node's channels or nil if there
@@ -340,46 +565,75 @@ func (c *GraphCache) getChannels(node route.Vertex) []*DirectedChannel {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
 
-	channels, ok := c.nodeChannels[node]
-	if !ok {
+	idx, ok := c.nodeIndex[node]
+	if !ok || c.nodes[idx].channels == nil {
 		return nil
 	}
-
-	features, ok := c.nodeFeatures[node]
-	if !ok {
-		// If the features were set to nil explicitly, that's fine here.
-		// The router will overwrite the features of the destination
-		// node with those found in the invoice if necessary. But if we
-		// didn't yet get a node announcement we want to mimic the
-		// behavior of the old DB based code that would always set an
-		// empty feature vector instead of leaving it nil.
-		features = lnwire.EmptyFeatureVector()
+	cached := &c.nodes[idx]
+
+	// If the features were set to nil explicitly, that's fine here. The
+	// router will overwrite the features of the destination node with
+	// those found in the invoice if necessary. But if we didn't yet get a
+	// node announcement we want to mimic the behavior of the old DB based
+	// code that would always set an empty feature vector instead of
+	// leaving it nil.
+	features := lnwire.EmptyFeatureVector()
+	if cached.features != nil {
+		features = cached.features.features
 	}
 
 	toNodeCallback := func() route.Vertex {
 		return node
 	}
 
-	i := 0
-	channelsCopy := make([]*DirectedChannel, len(channels))
-	for _, channel := range channels {
-		// We need to copy the channel and policy to avoid it being
-		// updated in the cache if the path finding algorithm sets
-		// fields on it (currently only the ToNodeFeatures of the
-		// policy).
-		channelCopy := channel.DeepCopy()
-		if channelCopy.InPolicy != nil {
-			channelCopy.InPolicy.ToNodePubKey = toNodeCallback
-			channelCopy.InPolicy.ToNodeFeatures = features
+	// The copies are allocated in bulk to reduce the number of allocations
+	// during path finding. Path finding may set fields on them (currently
+	// only the ToNodeFeatures of the policy), which mustn't affect the
+	// cache.
+	numChannels := len(cached.channels)
+	directed := make([]DirectedChannel, numChannels)
+	inPolicies := make([]models.CachedEdgePolicy, numChannels)
+	channelsCopy := make([]*DirectedChannel, numChannels)
+	for i, chanIdx := range cached.channels {
+		channel := &c.channels[chanIdx]
+		c.directedChannel(
+			channel, channel.side(idx), &directed[i],
+			&inPolicies[i],
+		)
+		if directed[i].InPolicy != nil {
+			directed[i].InPolicy.ToNodePubKey = toNodeCallback
+			directed[i].InPolicy.ToNodeFeatures = features
 		}
 
-		channelsCopy[i] = channelCopy
-		i++
+		channelsCopy[i] = &directed[i]
 	}
 
 	return channelsCopy
 }
 
+// nodeChannels returns the channels of the node with the given index as
+// directed channels, by their channel ID.
+//
+// NOTE: The read lock must be held when calling this method.
+func (c *GraphCache) nodeChannels(nodeIdx uint32) map[uint64]*DirectedChannel {
+	node := &c.nodes[nodeIdx]
+	channels := make(map[uint64]*DirectedChannel, len(node.channels))
+	for _, chanIdx := range node.channels {
+		channel := &c.channels[chanIdx]
+
+		var (
+			directed DirectedChannel
+			inPolicy models.CachedEdgePolicy
+		)
+		c.directedChannel(
+			channel, channel.side(nodeIdx), &directed, &inPolicy,
+		)
+		channels[channel.channelID] = &directed
+	}
+
+	return channels
+}
+
 // ForEachChannel invokes the given callback for each channel of the given node.
 func (c *GraphCache) ForEachChannel(node route.Vertex,
 	cb func(channel *DirectedChannel) error) error {
@@ -413,11 +667,17 @@ func (c *GraphCache) ForEachNode(cb func(node route.Vertex,
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
 
-	for node, channels := range c.nodeChannels {
-		// We don't make a copy here since this is a read-only RPC
-		// call. We also don't need the node features either for this
-		// call.
-		if err := cb(node, channels); err != nil {
+	for idx := range c.nodes {
+		cached := &c.nodes[idx]
+		if cached.channels == nil {
+			continue
+		}
+
+		// As the channels aren't stored as directed channels, we
+		// create them for the callback. We don't need the node
+		// features for this call.
+		channels := c.nodeChannels(uint32(idx))
+		if err := cb(cached.pubKey, channels); err != nil {
 			return err
 		}
 	}
@@ -431,12 +691,14 @@ func (c *GraphCache) GetFeatures(node route.Vertex) *lnwire.FeatureVector {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
 
-	features, ok := c.nodeFeatures[node]
-	if !ok || features == nil {
+	idx, ok := c.nodeIndex[node]
+	if !ok || c.nodes[idx].features == nil ||
+		c.nodes[idx].features.features == nil {
+
 		// The router expects the features to never be nil, so we return
 		// an empty feature set instead.
 		return lnwire.EmptyFeatureVector()
 	}
 
-	return features
+	return c.nodes[idx].features.features
 }
@@ -58,6 +58,38 @@ func (n *node) ForEachChannel(tx kvdb.RTx,
 	return nil
 }
 
+// cachedNodeChannels returns the channels of each node of the cache, as they
+// are stored in the cache.
+func cachedNodeChannels(
+	c *GraphCache) map[route.Vertex]map[uint64]*DirectedChannel {
+
+	nodeChannels := make(map[route.Vertex]map[uint64]*DirectedChannel)
+	for idx := range c.nodes {
+		node := &c.nodes[idx]
+		if node.channels == nil {
+			continue
+		}
+
+		nodeChannels[node.pubKey] = c.nodeChannels(uint32(idx))
+	}
+
+	return nodeChannels
+}
+
+// cachedNodeFeatures returns the features of each node of the cache that are
+// known.
+func cachedNodeFeatures(c *GraphCache) map[route.Vertex]*lnwire.FeatureVector {
+	nodeFeatures := make(map[route.Vertex]*lnwire.FeatureVector)
+	for _, idx := range c.nodeIndex {
+		node := &c.nodes[idx]
+		if node.features != nil {
+			nodeFeatures[node.pubKey] = node.features.features
+		}
+	}
+
+	return nodeFeatures
+}
+
 // TestGraphCacheAddNode tests that a channel going from node A to node B can be
 // cached correctly, independent of the direction we add the channel as.
 func TestGraphCacheAddNode(t *testing.T) {
@@ -171,3 +203,69 @@ func assertCachedPolicyEqual(t *testing.T, original *models.ChannelEdgePolicy,
 		t, route.Vertex(original.ToNode), cached.ToNodePubKey(),
 	)
 }
+
+// TestGraphCacheCompaction tests that the graph cache shares the features of
+// nodes, and reuses the slots of removed nodes and channels.
+func TestGraphCacheCompaction(t *testing.T) {
+	t.Parallel()
+
+	features := func() *lnwire.FeatureVector {
+		raw := lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadOptional,
+		)
+
+		return lnwire.NewFeatureVector(raw, lnwire.Features)
+	}
+	pubKey3 := route.Vertex{3}
+
+	cache := NewGraphCache(10)
+	cache.AddNodeFeatures(&node{pubKey: pubKey1, features: features()})
+	cache.AddNodeFeatures(&node{pubKey: pubKey2, features: features()})
+	cache.AddNodeFeatures(&node{pubKey: pubKey3})
+
+	// Nodes with the same features share them, a node that announced nil
+	// features keeps them nil.
+	require.Len(t, cache.features, 2)
+	require.Same(t, cache.GetFeatures(pubKey1), cache.GetFeatures(pubKey2))
+	require.Nil(t, cachedNodeFeatures(cache)[pubKey3])
+	require.Equal(
+		t, lnwire.EmptyFeatureVector(), cache.GetFeatures(pubKey3),
+	)
+
+	addChannel := func(chanID uint64, node1, node2 route.Vertex) {
+		cache.AddChannel(&models.ChannelEdgeInfo{
+			ChannelID:     chanID,
+			NodeKey1Bytes: node1,
+			NodeKey2Bytes: node2,
+			Capacity:      1000,
+		}, nil, nil)
+	}
+	addChannel(1, pubKey1, pubKey2)
+	addChannel(2, pubKey1, pubKey3)
+	addChannel(3, pubKey2, pubKey3)
+	require.Len(t, cache.channels, 3)
+
+	// Removing a node removes its channels from its peers and frees their
+	// slots, which are reused for new channels.
+	cache.RemoveNode(pubKey3)
+	require.Len(t, cache.features, 1)
+	require.Len(t, cache.channelIndex, 1)
+	require.NotContains(t, cachedNodeChannels(cache), pubKey3)
+	require.Len(t, cachedNodeChannels(cache)[pubKey1], 1)
+	require.Len(t, cachedNodeChannels(cache)[pubKey2], 1)
+
+	addChannel(4, pubKey1, pubKey2)
+	addChannel(5, pubKey2, pubKey1)
+	require.Len(t, cache.channels, 3)
+	require.Len(t, cachedNodeChannels(cache)[pubKey1], 3)
+
+	// Once all nodes with the same features are removed, the features are
+	// released.
+	cache.RemoveNode(pubKey1)
+	cache.RemoveNode(pubKey2)
+	require.Empty(t, cache.features)
+	require.Empty(t, cache.channelIndex)
+	require.Empty(t, cache.nodeIndex)
+	require.Len(t, cache.freeNodes, 3)
+	require.Len(t, cache.freeChannels, 3)
+}
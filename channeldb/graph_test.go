@@ -18,6 +18,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -69,8 +70,8 @@ func MakeTestGraph(t testing.TB, modifiers ...OptionModifier) (*ChannelGraph, er
 
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
-		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.BatchCommitInterval, opts.MaxBatchSize,
+		opts.PreAllocCacheNumNodes, true, false,
 	)
 	if err != nil {
 		backendCleanup()
@@ -385,6 +386,51 @@ func TestEdgeInsertionDeletion(t *testing.T) {
 	}
 }
 
+// TestTaprootEdgeProof tests that the Schnorr signature of a channel announced
+// with a ChannelAnnouncement2 is stored and read back as a taproot proof.
+func TestTaprootEdgeProof(t *testing.T) {
+	t.Parallel()
+
+	graph, err := MakeTestGraph(t)
+	require.NoError(t, err, "unable to make test database")
+
+	node1, err := createTestVertex(graph.db)
+	require.NoError(t, err, "unable to create test node")
+	node2, err := createTestVertex(graph.db)
+	require.NoError(t, err, "unable to create test node")
+
+	edgeInfo, scid := createEdge(100, 1, 0, 0, node1, node2)
+	edgeInfo.AuthProof = &models.ChannelAuthProof{
+		SchnorrSigBytes: bytes.Repeat([]byte{1}, schnorr.SignatureSize),
+	}
+	require.NoError(t, graph.AddChannelEdge(&edgeInfo))
+
+	dbEdgeInfo, _, _, err := graph.FetchChannelEdgesByID(scid.ToUint64())
+	require.NoError(t, err)
+	require.True(t, dbEdgeInfo.AuthProof.IsTaproot())
+	assertEdgeInfoEqual(t, dbEdgeInfo, &edgeInfo)
+
+	// A channel without a proof can be given a taproot proof once the
+	// announcement signatures have been exchanged.
+	edgeInfo2, scid2 := createEdge(100, 2, 0, 1, node1, node2)
+	edgeInfo2.AuthProof = nil
+	require.NoError(t, graph.AddChannelEdge(&edgeInfo2))
+
+	dbEdgeInfo, _, _, err = graph.FetchChannelEdgesByID(scid2.ToUint64())
+	require.NoError(t, err)
+	require.Nil(t, dbEdgeInfo.AuthProof)
+
+	proof := &models.ChannelAuthProof{
+		SchnorrSigBytes: bytes.Repeat([]byte{2}, schnorr.SignatureSize),
+	}
+	dbEdgeInfo.AuthProof = proof
+	require.NoError(t, graph.UpdateChannelEdge(dbEdgeInfo))
+
+	dbEdgeInfo, _, _, err = graph.FetchChannelEdgesByID(scid2.ToUint64())
+	require.NoError(t, err)
+	require.Equal(t, proof, dbEdgeInfo.AuthProof)
+}
+
 func createEdge(height, txIndex uint32, txPosition uint16, outPointIndex uint32,
 	node1, node2 *LightningNode) (models.ChannelEdgeInfo,
 	lnwire.ShortChannelID) {
@@ -601,6 +647,11 @@ func assertEdgeInfoEqual(t *testing.T, e1 *models.ChannelEdgeInfo,
 	if !bytes.Equal(e1.AuthProof.BitcoinSig2Bytes, e2.AuthProof.BitcoinSig2Bytes) {
 		t.Fatalf("bitcoinsig2 doesn't match")
 	}
+	if !bytes.Equal(
+		e1.AuthProof.SchnorrSigBytes, e2.AuthProof.SchnorrSigBytes,
+	) {
+		t.Fatalf("schnorrsig doesn't match")
+	}
 
 	if e1.ChannelPoint != e2.ChannelPoint {
 		t.Fatalf("channel point match: %v vs %v", e1.ChannelPoint,
@@ -723,7 +774,7 @@ func TestEdgeInfoUpdates(t *testing.T) {
 	if err := graph.UpdateEdgePolicy(edge1); err != ErrEdgeNotFound {
 		t.Fatalf("expected ErrEdgeNotFound, got: %v", err)
 	}
-	require.Len(t, graph.graphCache.nodeChannels, 0)
+	require.Len(t, cachedNodeChannels(graph.graphCache), 0)
 
 	// Add the edge info.
 	if err := graph.AddChannelEdge(edgeInfo); err != nil {
@@ -794,9 +845,8 @@ func assertNodeInCache(t *testing.T, g *ChannelGraph, n *LightningNode,
 	expectedFeatures *lnwire.FeatureVector) {
 
 	// Let's check the internal view first.
-	require.Equal(
-		t, expectedFeatures, g.graphCache.nodeFeatures[n.PubKeyBytes],
-	)
+	nodeFeatures := cachedNodeFeatures(g.graphCache)
+	require.Equal(t, expectedFeatures, nodeFeatures[n.PubKeyBytes])
 
 	// The external view should reflect this as well. Except when we expect
 	// the features to be nil internally, we return an empty feature vector
@@ -809,10 +859,10 @@ func assertNodeInCache(t *testing.T, g *ChannelGraph, n *LightningNode,
 }
 
 func assertNodeNotInCache(t *testing.T, g *ChannelGraph, n route.Vertex) {
-	_, ok := g.graphCache.nodeFeatures[n]
+	_, ok := cachedNodeFeatures(g.graphCache)[n]
 	require.False(t, ok)
 
-	_, ok = g.graphCache.nodeChannels[n]
+	_, ok = cachedNodeChannels(g.graphCache)[n]
 	require.False(t, ok)
 
 	// We should get the default features for this node.
@@ -824,8 +874,9 @@ func assertEdgeWithNoPoliciesInCache(t *testing.T, g *ChannelGraph,
 	e *models.ChannelEdgeInfo) {
 
 	// Let's check the internal view first.
-	require.NotEmpty(t, g.graphCache.nodeChannels[e.NodeKey1Bytes])
-	require.NotEmpty(t, g.graphCache.nodeChannels[e.NodeKey2Bytes])
+	nodeChannels := cachedNodeChannels(g.graphCache)
+	require.NotEmpty(t, nodeChannels[e.NodeKey1Bytes])
+	require.NotEmpty(t, nodeChannels[e.NodeKey2Bytes])
 
 	expectedNode1Channel := &DirectedChannel{
 		ChannelID:    e.ChannelID,
@@ -835,12 +886,10 @@ func assertEdgeWithNoPoliciesInCache(t *testing.T, g *ChannelGraph,
 		OutPolicySet: false,
 		InPolicy:     nil,
 	}
-	require.Contains(
-		t, g.graphCache.nodeChannels[e.NodeKey1Bytes], e.ChannelID,
-	)
+	require.Contains(t, nodeChannels[e.NodeKey1Bytes], e.ChannelID)
 	require.Equal(
 		t, expectedNode1Channel,
-		g.graphCache.nodeChannels[e.NodeKey1Bytes][e.ChannelID],
+		nodeChannels[e.NodeKey1Bytes][e.ChannelID],
 	)
 
 	expectedNode2Channel := &DirectedChannel{
@@ -851,12 +900,10 @@ func assertEdgeWithNoPoliciesInCache(t *testing.T, g *ChannelGraph,
 		OutPolicySet: false,
 		InPolicy:     nil,
 	}
-	require.Contains(
-		t, g.graphCache.nodeChannels[e.NodeKey2Bytes], e.ChannelID,
-	)
+	require.Contains(t, nodeChannels[e.NodeKey2Bytes], e.ChannelID)
 	require.Equal(
 		t, expectedNode2Channel,
-		g.graphCache.nodeChannels[e.NodeKey2Bytes][e.ChannelID],
+		nodeChannels[e.NodeKey2Bytes][e.ChannelID],
 	)
 
 	// The external view should reflect this as well.
@@ -891,7 +938,7 @@ func assertEdgeWithNoPoliciesInCache(t *testing.T, g *ChannelGraph,
 func assertNoEdge(t *testing.T, g *ChannelGraph, chanID uint64) {
 	// Make sure no channel in the cache has the given channel ID. If there
 	// are no channels at all, that is fine as well.
-	for _, channels := range g.graphCache.nodeChannels {
+	for _, channels := range cachedNodeChannels(g.graphCache) {
 		for _, channel := range channels {
 			require.NotEqual(t, channel.ChannelID, chanID)
 		}
@@ -902,7 +949,8 @@ func assertEdgeWithPolicyInCache(t *testing.T, g *ChannelGraph,
 	e *models.ChannelEdgeInfo, p *models.ChannelEdgePolicy, policy1 bool) {
 
 	// Check the internal state first.
-	c1, ok := g.graphCache.nodeChannels[e.NodeKey1Bytes][e.ChannelID]
+	nodeChannels := cachedNodeChannels(g.graphCache)
+	c1, ok := nodeChannels[e.NodeKey1Bytes][e.ChannelID]
 	require.True(t, ok)
 
 	if policy1 {
@@ -915,7 +963,7 @@ func assertEdgeWithPolicyInCache(t *testing.T, g *ChannelGraph,
 		)
 	}
 
-	c2, ok := g.graphCache.nodeChannels[e.NodeKey2Bytes][e.ChannelID]
+	c2, ok := nodeChannels[e.NodeKey2Bytes][e.ChannelID]
 	require.True(t, ok)
 
 	if policy1 {
@@ -1919,6 +1967,195 @@ func TestNodeUpdatesInHorizon(t *testing.T) {
 	}
 }
 
+// TestForEachChanUpdateInHorizon tests that the channels with updates within a
+// time horizon are passed in batches, each channel exactly once and in the
+// order of the update index.
+func TestForEachChanUpdateInHorizon(t *testing.T) {
+	t.Parallel()
+
+	graph, err := MakeTestGraph(t)
+	require.NoError(t, err, "unable to make test database")
+
+	// Iterating over an empty graph doesn't call the callback.
+	err = graph.ForEachChanUpdateInHorizon(
+		time.Unix(0, 0), time.Unix(9999, 0), 3,
+		func([]ChannelEdge) error {
+			return errors.New("unexpected batch")
+		},
+	)
+	require.NoError(t, err)
+
+	node1, err := createTestVertex(graph.db)
+	require.NoError(t, err)
+	require.NoError(t, graph.AddLightningNode(node1))
+
+	node2, err := createTestVertex(graph.db)
+	require.NoError(t, err)
+	require.NoError(t, graph.AddLightningNode(node2))
+
+	// We'll create 10 channels with both of their policies updated one
+	// second after each other, so that each channel is in the update
+	// index twice.
+	const numChans = 10
+	startTime := time.Unix(1234, 0)
+	updateTime := startTime
+	chanIDs := make([]uint64, 0, numChans)
+	for i := 0; i < numChans; i++ {
+		channel, chanID := createEdge(
+			uint32(i*10), 0, 0, 0, node1, node2,
+		)
+		require.NoError(t, graph.AddChannelEdge(&channel))
+
+		for flags := lnwire.ChanUpdateChanFlags(0); flags < 2; flags++ {
+			edge := newEdgePolicy(
+				chanID.ToUint64(), graph.db, updateTime.Unix(),
+			)
+			edge.ChannelFlags = flags
+			edge.SigBytes = testSig.Serialize()
+			edge.ToNode = node2.PubKeyBytes
+			if flags == 1 {
+				edge.ToNode = node1.PubKeyBytes
+			}
+			require.NoError(t, graph.UpdateEdgePolicy(edge))
+
+			updateTime = updateTime.Add(time.Second)
+		}
+
+		chanIDs = append(chanIDs, chanID.ToUint64())
+	}
+
+	queryHorizon := func(start, end time.Time) ([]uint64, []int) {
+		var (
+			ids        []uint64
+			batchSizes []int
+		)
+		err := graph.ForEachChanUpdateInHorizon(
+			start, end, 3, func(batch []ChannelEdge) error {
+				batchSizes = append(batchSizes, len(batch))
+				for _, channel := range batch {
+					require.NotNil(t, channel.Policy1)
+					require.NotNil(t, channel.Policy2)
+
+					ids = append(
+						ids, channel.Info.ChannelID,
+					)
+				}
+
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		return ids, batchSizes
+	}
+
+	// The full range is passed in batches of three channels, even though
+	// each channel has two entries in the index.
+	ids, batchSizes := queryHorizon(startTime, updateTime)
+	require.Equal(t, chanIDs, ids)
+	require.Equal(t, []int{3, 3, 3, 1}, batchSizes)
+
+	// A horizon that starts at the second policy of the first channel and
+	// ends before the second policy of the last channel still includes
+	// both.
+	ids, _ = queryHorizon(
+		startTime.Add(time.Second), updateTime.Add(-time.Second),
+	)
+	require.Equal(t, chanIDs, ids)
+
+	// A horizon that excludes the first channel entirely.
+	ids, _ = queryHorizon(startTime.Add(2*time.Second), updateTime)
+	require.Equal(t, chanIDs[1:], ids)
+
+	// An error of the callback stops the iteration.
+	errStop := errors.New("stop")
+	var numBatches int
+	err = graph.ForEachChanUpdateInHorizon(
+		startTime, updateTime, 3, func([]ChannelEdge) error {
+			numBatches++
+			return errStop
+		},
+	)
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, numBatches)
+
+	// A batch size that isn't positive is rejected.
+	err = graph.ForEachChanUpdateInHorizon(
+		startTime, updateTime, 0, func([]ChannelEdge) error {
+			return nil
+		},
+	)
+	require.Error(t, err)
+}
+
+// TestForEachNodeUpdateInHorizon tests that the nodes with updates within a
+// time horizon are passed in batches, optionally skipping nodes that aren't
+// publicly advertised.
+func TestForEachNodeUpdateInHorizon(t *testing.T) {
+	t.Parallel()
+
+	graph, err := MakeTestGraph(t)
+	require.NoError(t, err, "unable to make test database")
+
+	startTime := time.Unix(1234, 0)
+	updateTime := startTime
+
+	// We'll create a source node and three other nodes, with update
+	// timestamps one second after each other.
+	nodes := make([]*LightningNode, 0, 4)
+	for i := 0; i < 4; i++ {
+		node, err := createTestVertex(graph.db)
+		require.NoError(t, err)
+
+		node.LastUpdate = updateTime
+		updateTime = updateTime.Add(time.Second)
+
+		require.NoError(t, graph.AddLightningNode(node))
+		nodes = append(nodes, node)
+	}
+	require.NoError(t, graph.SetSourceNode(nodes[0]))
+
+	// Only the two nodes with a channel between them are public, the
+	// source node and the node without channels aren't.
+	channel, _ := createEdge(100, 0, 0, 0, nodes[1], nodes[2])
+	require.NoError(t, graph.AddChannelEdge(&channel))
+
+	queryHorizon := func(publicOnly bool) ([][33]byte, []int) {
+		var (
+			pubKeys    [][33]byte
+			batchSizes []int
+		)
+		err := graph.ForEachNodeUpdateInHorizon(
+			startTime, updateTime, publicOnly, 2,
+			func(batch []LightningNode) error {
+				batchSizes = append(batchSizes, len(batch))
+				for _, node := range batch {
+					pubKeys = append(
+						pubKeys, node.PubKeyBytes,
+					)
+				}
+
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		return pubKeys, batchSizes
+	}
+
+	pubKeys, batchSizes := queryHorizon(false)
+	require.Equal(t, [][33]byte{
+		nodes[0].PubKeyBytes, nodes[1].PubKeyBytes,
+		nodes[2].PubKeyBytes, nodes[3].PubKeyBytes,
+	}, pubKeys)
+	require.Equal(t, []int{2, 2}, batchSizes)
+
+	pubKeys, _ = queryHorizon(true)
+	require.Equal(t, [][33]byte{
+		nodes[1].PubKeyBytes, nodes[2].PubKeyBytes,
+	}, pubKeys)
+}
+
 // TestFilterKnownChanIDs tests that we're able to properly perform the set
 // differences of an incoming set of channel ID's, and those that we already
 // know of on disk.
@@ -3020,6 +3257,65 @@ func TestPruneGraphNodes(t *testing.T) {
 	}
 }
 
+// TestPruneGraphClosedChanNodes tests that pruning the graph for a block only
+// removes the nodes of the closed channels that are left without channels,
+// and that the graph cache reflects the pruned channels and nodes.
+func TestPruneGraphClosedChanNodes(t *testing.T) {
+	t.Parallel()
+
+	graph, err := MakeTestGraph(t)
+	require.NoError(t, err, "unable to make test database")
+
+	sourceNode, err := createTestVertex(graph.db)
+	require.NoError(t, err, "unable to create source node")
+	require.NoError(t, graph.SetSourceNode(sourceNode))
+
+	// We'll create four nodes. Node 1 has channels to both node 2 and
+	// node 3, while node 4 has no channels at all.
+	nodes := make([]*LightningNode, 4)
+	for i := range nodes {
+		nodes[i], err = createTestVertex(graph.db)
+		require.NoError(t, err, "unable to create test node")
+		require.NoError(t, graph.AddLightningNode(nodes[i]))
+	}
+
+	edge12, _ := createEdge(100, 0, 0, 0, nodes[0], nodes[1])
+	require.NoError(t, graph.AddChannelEdge(&edge12))
+	edge13, _ := createEdge(100, 1, 0, 1, nodes[0], nodes[2])
+	require.NoError(t, graph.AddChannelEdge(&edge13))
+
+	// Closing the channel between node 1 and node 2 in a block should
+	// only remove node 2, as node 1 still has a channel to node 3.
+	blockHash := chainhash.Hash(sha256.Sum256([]byte{1}))
+	prunedChans, err := graph.PruneGraph(
+		[]*wire.OutPoint{&edge12.ChannelPoint}, &blockHash, 1,
+	)
+	require.NoError(t, err, "unable to prune graph")
+	require.Len(t, prunedChans, 1)
+	require.Equal(t, edge12.ChannelID, prunedChans[0].ChannelID)
+
+	_, err = graph.FetchLightningNode(nil, nodes[1].PubKeyBytes)
+	require.ErrorIs(t, err, ErrGraphNodeNotFound)
+	assertNodeNotInCache(t, graph, nodes[1].PubKeyBytes)
+
+	_, err = graph.FetchLightningNode(nil, nodes[0].PubKeyBytes)
+	require.NoError(t, err)
+	nodeChannels := cachedNodeChannels(graph.graphCache)
+	require.Len(t, nodeChannels[nodes[0].PubKeyBytes], 1)
+	require.Contains(
+		t, nodeChannels[nodes[0].PubKeyBytes], edge13.ChannelID,
+	)
+
+	// Node 4 wasn't part of a closed channel, so it is left for the full
+	// prune of the graph nodes.
+	assertNumNodes(t, graph, 4)
+	_, err = graph.FetchLightningNode(nil, nodes[3].PubKeyBytes)
+	require.NoError(t, err)
+
+	require.NoError(t, graph.PruneGraphNodes())
+	assertNumNodes(t, graph, 3)
+}
+
 // TestAddChannelEdgeShellNodes tests that when we attempt to add a ChannelEdge
 // to the graph, one or both of the nodes the edge involves aren't found in the
 // database, then shell edges are created for each node if needed.
@@ -3909,6 +4205,30 @@ func BenchmarkForEachChannel(b *testing.B) {
 	}
 }
 
+// BenchmarkNewChannelGraph measures the time it takes to open the channel
+// graph and populate the graph cache.
+func BenchmarkNewChannelGraph(b *testing.B) {
+	graph, err := MakeTestGraph(b)
+	require.Nil(b, err)
+
+	const numNodes = 100
+	const numChannels = 4
+	_, _ = fillTestGraph(b, graph, numNodes, numChannels)
+
+	opts := DefaultOptions()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := NewChannelGraph(
+			graph.db, opts.RejectCacheSize, opts.ChannelCacheSize,
+			opts.BatchCommitInterval, opts.MaxBatchSize,
+			opts.PreAllocCacheNumNodes, true, true,
+		)
+		require.NoError(b, err)
+	}
+}
+
 // TestGraphCacheForEachNodeChannel tests that the ForEachNodeDirectedChannel
 // method works as expected, and is able to handle nil self edges.
 func TestGraphCacheForEachNodeChannel(t *testing.T) {
@@ -4002,8 +4322,8 @@ func TestGraphLoading(t *testing.T) {
 	opts := DefaultOptions()
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
-		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.BatchCommitInterval, opts.MaxBatchSize,
+		opts.PreAllocCacheNumNodes, true, false,
 	)
 	require.NoError(t, err)
 
@@ -4016,19 +4336,19 @@ func TestGraphLoading(t *testing.T) {
 	// populated.
 	graphReloaded, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
-		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.BatchCommitInterval, opts.MaxBatchSize,
+		opts.PreAllocCacheNumNodes, true, false,
 	)
 	require.NoError(t, err)
 
 	// Assert that the cache content is identical.
 	require.Equal(
-		t, graph.graphCache.nodeChannels,
-		graphReloaded.graphCache.nodeChannels,
+		t, cachedNodeChannels(graph.graphCache),
+		cachedNodeChannels(graphReloaded.graphCache),
 	)
 
 	require.Equal(
-		t, graph.graphCache.nodeFeatures,
-		graphReloaded.graphCache.nodeFeatures,
+		t, cachedNodeFeatures(graph.graphCache),
+		cachedNodeFeatures(graphReloaded.graphCache),
 	)
 }
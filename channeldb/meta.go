@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/tlv"
@@ -105,14 +107,24 @@ type OptionalMeta struct {
 }
 
 func (om *OptionalMeta) String() string {
-	s := ""
-	for index, name := range om.Versions {
-		s += fmt.Sprintf("%d: %s", index, name)
+	indexes := make([]uint64, 0, len(om.Versions))
+	for index := range om.Versions {
+		indexes = append(indexes, index)
 	}
-	if s == "" {
-		s = "empty"
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i] < indexes[j]
+	})
+
+	versions := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		name := om.Versions[index]
+		versions = append(versions, fmt.Sprintf("%d: %s", index, name))
+	}
+	if len(versions) == 0 {
+		return "empty"
 	}
-	return s
+
+	return strings.Join(versions, ", ")
 }
 
 // fetchOptionalMeta reads the optional meta from the database.
@@ -146,7 +158,14 @@ func (d *DB) fetchOptionalMeta() (*OptionalMeta, error) {
 			if err != nil {
 				return err
 			}
-			om.Versions[version] = optionalVersions[i].name
+
+			// Versions unknown to this binary, e.g. ones written
+			// by a newer version, are kept without a name.
+			var name string
+			if version < uint64(len(optionalVersions)) {
+				name = optionalVersions[version].name
+			}
+			om.Versions[version] = name
 		}
 
 		return nil
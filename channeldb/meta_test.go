@@ -95,7 +95,10 @@ func TestVersionFetchPut(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if meta.DbVersionNumber != getLatestDBVersion(dbVersions) {
+	// A fresh database without compacted revocation logs isn't bumped to
+	// the version marking them.
+	latest := getLatestDBVersion(filterVersions(dbVersions, 0, false))
+	if meta.DbVersionNumber != latest {
 		t.Fatal("initialization of meta information wasn't performed")
 	}
 
@@ -574,6 +577,65 @@ func TestApplyOptionalVersions(t *testing.T) {
 	require.Equal(t, 1, migrateCount, "expected no migration")
 }
 
+// TestSyncVersionsCompactRevLog checks that the database is only bumped to the
+// version marking compacted revocation logs once compaction is enabled, and
+// that older versions refuse to open it afterwards.
+func TestSyncVersionsCompactRevLog(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	assertVersion := func(expected uint32) {
+		t.Helper()
+
+		meta, err := db.FetchMeta()
+		require.NoError(t, err)
+		require.Equal(t, expected, meta.DbVersionNumber)
+	}
+
+	// Without compaction, the database stays on the previous version.
+	assertVersion(compactRevLogVersion - 1)
+
+	// Enabling compaction bumps the version.
+	db.compactRevLog = true
+	require.NoError(t, db.syncVersions(dbVersions))
+	assertVersion(compactRevLogVersion)
+
+	// The version is kept when compaction is disabled again, as the
+	// database may still contain compacted logs.
+	db.compactRevLog = false
+	require.NoError(t, db.syncVersions(dbVersions))
+	assertVersion(compactRevLogVersion)
+
+	// Versions that don't know about compacted logs refuse to open the
+	// database.
+	err = db.syncVersions(dbVersions[:len(dbVersions)-1])
+	require.ErrorIs(t, err, ErrDBReversion)
+}
+
+// TestFetchOptionalMetaUnknownVersion checks that optional versions unknown to
+// this version, e.g. ones written by a newer version, are kept when reading
+// the optional meta.
+func TestFetchOptionalMetaUnknownVersion(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	om := &OptionalMeta{
+		Versions: map[uint64]string{
+			0:                             optionalVersions[0].name,
+			uint64(len(optionalVersions)): "",
+		},
+	}
+	require.NoError(t, db.putOptionalMeta(om))
+
+	om1, err := db.fetchOptionalMeta()
+	require.NoError(t, err)
+	require.Equal(t, om, om1)
+}
+
 // TestFetchMeta tests that the FetchMeta returns the latest DB version for a
 // freshly created DB instance.
 func TestFetchMeta(t *testing.T) {
@@ -590,7 +652,10 @@ func TestFetchMeta(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	require.Equal(t, LatestDBVersion(), meta.DbVersionNumber)
+	require.Equal(
+		t, getLatestDBVersion(filterVersions(dbVersions, 0, false)),
+		meta.DbVersionNumber,
+	)
 }
 
 // TestMarkerAndTombstone tests that markers like a tombstone can be added to a
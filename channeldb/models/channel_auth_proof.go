@@ -37,6 +37,19 @@ type ChannelAuthProof struct {
 	// BitcoinSig2Bytes are the raw bytes of the second bitcoin signature
 	// encoded in DER format.
 	BitcoinSig2Bytes []byte
+
+	// SchnorrSigBytes are the raw bytes of the Schnorr signature of a
+	// channel announced with a ChannelAnnouncement2 message. The signature
+	// is created by the MuSig2 aggregate of the node and bitcoin keys of
+	// both nodes. If this is set, then the four signatures above are
+	// empty.
+	SchnorrSigBytes []byte
+}
+
+// IsTaproot returns true if the proof is the signature of a
+// ChannelAnnouncement2 message.
+func (c *ChannelAuthProof) IsTaproot() bool {
+	return len(c.SchnorrSigBytes) != 0
 }
 
 // Node1Sig is the signature using the identity key of the node that is first
@@ -124,6 +137,10 @@ func (c *ChannelAuthProof) BitcoinSig2() (*ecdsa.Signature, error) {
 // IsEmpty check is the authentication proof is empty Proof is empty if at
 // least one of the signatures are equal to nil.
 func (c *ChannelAuthProof) IsEmpty() bool {
+	if c.IsTaproot() {
+		return false
+	}
+
 	return len(c.NodeSig1Bytes) == 0 ||
 		len(c.NodeSig2Bytes) == 0 ||
 		len(c.BitcoinSig1Bytes) == 0 ||
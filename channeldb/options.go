@@ -23,6 +23,10 @@ const (
 	// September 2021, there currently are 14k nodes in a strictly pruned
 	// graph, so we choose a number that is slightly higher.
 	DefaultPreAllocCacheNumNodes = 15000
+
+	// DefaultMaxBatchSize is the default maximum number of graph updates
+	// the batch schedulers commit in one transaction.
+	DefaultMaxBatchSize = 1000
 )
 
 // OptionalMiragtionConfig defines the flags used to signal whether a
@@ -50,6 +54,11 @@ type Options struct {
 	// wait before attempting to commit a pending set of updates.
 	BatchCommitInterval time.Duration
 
+	// MaxBatchSize is the maximum number of updates the batch schedulers
+	// commit in one transaction. A batch is committed before the commit
+	// interval elapsed once it reaches this size.
+	MaxBatchSize int
+
 	// PreAllocCacheNumNodes is the number of nodes we expect to be in the
 	// graph cache, so we can pre-allocate the map accordingly.
 	PreAllocCacheNumNodes int
@@ -68,6 +77,11 @@ type Options struct {
 	// not be stored in the revocation log.
 	NoRevLogAmtData bool
 
+	// CompactRevLog when set to true, indicates that the HTLC entries of
+	// new revocation logs should be stored once per channel and only be
+	// referenced by the logs.
+	CompactRevLog bool
+
 	// clock is the time source used by the database.
 	clock clock.Clock
 
@@ -96,6 +110,7 @@ func DefaultOptions() Options {
 		OptionalMiragtionConfig: OptionalMiragtionConfig{},
 		RejectCacheSize:         DefaultRejectCacheSize,
 		ChannelCacheSize:        DefaultChannelCacheSize,
+		MaxBatchSize:            DefaultMaxBatchSize,
 		PreAllocCacheNumNodes:   DefaultPreAllocCacheNumNodes,
 		UseGraphCache:           true,
 		NoMigration:             false,
@@ -142,6 +157,15 @@ func OptionNoRevLogAmtData(noAmtData bool) OptionModifier {
 	}
 }
 
+// OptionCompactRevLog sets the CompactRevLog option to the given value. If it
+// is set to true then the HTLC entries of new revocation logs are shared
+// between the logs of a channel.
+func OptionCompactRevLog(compact bool) OptionModifier {
+	return func(o *Options) {
+		o.CompactRevLog = compact
+	}
+}
+
 // OptionSetSyncFreelist allows the database to sync its freelist.
 func OptionSetSyncFreelist(b bool) OptionModifier {
 	return func(o *Options) {
@@ -172,6 +196,14 @@ func OptionSetBatchCommitInterval(interval time.Duration) OptionModifier {
 	}
 }
 
+// OptionSetMaxBatchSize sets the maximum number of updates the internal batch
+// schedulers commit in one transaction.
+func OptionSetMaxBatchSize(size int) OptionModifier {
+	return func(o *Options) {
+		o.MaxBatchSize = size
+	}
+}
+
 // OptionNoMigration allows the database to be opened in read only mode by
 // disabling migrations.
 func OptionNoMigration(b bool) OptionModifier {
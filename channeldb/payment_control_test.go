@@ -561,6 +561,46 @@ func TestPaymentControlDeletePayments(t *testing.T) {
 	assertPayments(t, db, payments[2:])
 }
 
+// TestFetchHtlcAttemptIDs tests that the IDs of the HTLC attempts of all
+// payments are returned, together with whether they are in flight.
+func TestFetchHtlcAttemptIDs(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to init db")
+
+	attempts, err := db.FetchHtlcAttemptIDs()
+	require.NoError(t, err)
+	require.Empty(t, attempts)
+
+	// Each payment has a failed attempt, followed by an attempt according
+	// to its status.
+	payments := []*payment{
+		{status: StatusFailed},
+		{status: StatusSucceeded},
+		{status: StatusInFlight},
+	}
+	createTestPayments(t, NewPaymentControl(db), payments)
+
+	attempts, err = db.FetchHtlcAttemptIDs()
+	require.NoError(t, err)
+	require.Equal(t, map[uint64]bool{
+		0: false, 1: false,
+		2: false, 3: false,
+		4: false, 5: true,
+	}, attempts)
+
+	// The attempts of deleted payments are no longer returned.
+	require.NoError(t, db.DeletePayments(true, false))
+
+	attempts, err = db.FetchHtlcAttemptIDs()
+	require.NoError(t, err)
+	require.Equal(t, map[uint64]bool{
+		2: false, 3: false,
+		4: false, 5: true,
+	}, attempts)
+}
+
 // TestPaymentControlDeleteSinglePayment tests that DeletePayment correctly
 // deletes information about a completed payment from the database.
 func TestPaymentControlDeleteSinglePayment(t *testing.T) {
@@ -257,6 +257,75 @@ func (d *DB) FetchPayments() ([]*MPPayment, error) {
 	return payments, nil
 }
 
+// FetchHtlcAttemptIDs returns the IDs of the HTLC attempts of all payments in
+// the DB, mapped to whether the attempt is still in flight. Attempts of legacy
+// duplicate payments aren't included.
+func (d *DB) FetchHtlcAttemptIDs() (map[uint64]bool, error) {
+	attempts := make(map[uint64]bool)
+
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		paymentsBucket := tx.ReadBucket(paymentsRootBucket)
+		if paymentsBucket == nil {
+			return nil
+		}
+
+		return paymentsBucket.ForEach(func(k, _ []byte) error {
+			bucket := paymentsBucket.NestedReadBucket(k)
+			if bucket == nil {
+				// We only expect sub-buckets to be found in
+				// this top-level bucket.
+				return fmt.Errorf("non bucket element in " +
+					"payments bucket")
+			}
+
+			htlcsBucket := bucket.NestedReadBucket(
+				paymentHtlcsBucket,
+			)
+			if htlcsBucket == nil {
+				return nil
+			}
+
+			// An attempt is in flight until its settle or fail
+			// info is stored, so an attempt is resolved if either
+			// key is found.
+			resolved := make(map[uint64]bool)
+			err := htlcsBucket.ForEach(func(k, _ []byte) error {
+				aid := byteOrder.Uint64(k[len(k)-8:])
+
+				switch {
+				case bytes.HasPrefix(k, htlcSettleInfoKey),
+					bytes.HasPrefix(k, htlcFailInfoKey):
+
+					resolved[aid] = true
+
+				case bytes.HasPrefix(k, htlcAttemptInfoKey):
+					if _, ok := resolved[aid]; !ok {
+						resolved[aid] = false
+					}
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for aid, isResolved := range resolved {
+				attempts[aid] = !isResolved
+			}
+
+			return nil
+		})
+	}, func() {
+		attempts = make(map[uint64]bool)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
 func fetchCreationInfo(bucket kvdb.RBucket) (*PaymentCreationInfo, error) {
 	b := bucket.Get(paymentCreationInfoKey)
 	if b == nil {
@@ -20,6 +20,7 @@ var (
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
+	//      |        |--peer-storage-key: <blob>
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
@@ -29,12 +30,21 @@ var (
 	// the timestamp of a peer's last flap count and its all time flap
 	// count.
 	flapCountKey = []byte("flap-count")
+
+	// peerStorageKey is a key used in the peer pubkey sub-bucket that
+	// stores the latest blob that the peer asked us to store on its
+	// behalf.
+	peerStorageKey = []byte("peer-storage")
 )
 
 var (
 	// ErrNoPeerBucket is returned when we try to read entries for a peer
 	// that is not tracked.
 	ErrNoPeerBucket = errors.New("peer bucket not found")
+
+	// ErrNoPeerStorage is returned when we try to read the storage blob of
+	// a peer that hasn't asked us to store one.
+	ErrNoPeerStorage = errors.New("no peer storage blob found")
 )
 
 // FlapCount contains information about a peer's flap count.
@@ -121,3 +131,91 @@ func (d *DB) ReadFlapCount(pubkey route.Vertex) (*FlapCount, error) {
 
 	return &flapCount, nil
 }
+
+// WritePeerStorage stores the given blob on behalf of a peer, creating a bucket
+// for the peer's pubkey if necessary. Note that this function overwrites any
+// blob that was previously stored for the peer.
+func (d *DB) WritePeerStorage(pubkey route.Vertex, blob []byte) error {
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		peers := tx.ReadWriteBucket(peersBucket)
+
+		peerBucket, err := peers.CreateBucketIfNotExists(pubkey[:])
+		if err != nil {
+			return err
+		}
+
+		return peerBucket.Put(peerStorageKey, blob)
+	}, func() {})
+}
+
+// ReadPeerStorage returns the latest blob that we have stored on behalf of the
+// given peer, failing if the peer is not found or we do not have a blob stored.
+func (d *DB) ReadPeerStorage(pubkey route.Vertex) ([]byte, error) {
+	var blob []byte
+
+	if err := kvdb.View(d, func(tx kvdb.RTx) error {
+		peers := tx.ReadBucket(peersBucket)
+
+		peerBucket := peers.NestedReadBucket(pubkey[:])
+		if peerBucket == nil {
+			return ErrNoPeerBucket
+		}
+
+		blobBytes := peerBucket.Get(peerStorageKey)
+		if blobBytes == nil {
+			return ErrNoPeerStorage
+		}
+
+		// The returned bytes are only valid for the lifetime of the
+		// transaction, so we copy them.
+		blob = make([]byte, len(blobBytes))
+		copy(blob, blobBytes)
+
+		return nil
+	}, func() {
+		blob = nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// FetchPeerStorageBlobs returns all the blobs that we currently store on behalf
+// of our peers, keyed by the peer's pubkey.
+func (d *DB) FetchPeerStorageBlobs() (map[route.Vertex][]byte, error) {
+	var blobs map[route.Vertex][]byte
+
+	if err := kvdb.View(d, func(tx kvdb.RTx) error {
+		peers := tx.ReadBucket(peersBucket)
+
+		return peers.ForEach(func(k, _ []byte) error {
+			peerBucket := peers.NestedReadBucket(k)
+			if peerBucket == nil {
+				return nil
+			}
+
+			blobBytes := peerBucket.Get(peerStorageKey)
+			if blobBytes == nil {
+				return nil
+			}
+
+			pubkey, err := route.NewVertexFromBytes(k)
+			if err != nil {
+				return err
+			}
+
+			blob := make([]byte, len(blobBytes))
+			copy(blob, blobBytes)
+			blobs[pubkey] = blob
+
+			return nil
+		})
+	}, func() {
+		blobs = make(map[route.Vertex][]byte)
+	}); err != nil {
+		return nil, err
+	}
+
+	return blobs, nil
+}
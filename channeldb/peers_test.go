@@ -47,3 +47,46 @@ func TestFlapCount(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, peer2FlapCount, count)
 }
+
+// TestPeerStorage tests lookup and writing of peer storage blobs to disk.
+func TestPeerStorage(t *testing.T) {
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	// Try to read the blob of a peer that we have no records for.
+	_, err = db.ReadPeerStorage(testPub)
+	require.Equal(t, ErrNoPeerBucket, err)
+
+	// Once the peer has a bucket but no blob, we expect a different
+	// error.
+	err = db.WriteFlapCounts(map[route.Vertex]*FlapCount{
+		testPub: {Count: 1},
+	})
+	require.NoError(t, err)
+
+	_, err = db.ReadPeerStorage(testPub)
+	require.Equal(t, ErrNoPeerStorage, err)
+
+	blobs, err := db.FetchPeerStorageBlobs()
+	require.NoError(t, err)
+	require.Empty(t, blobs)
+
+	// Store a blob for two peers and read them back.
+	testPub2 := route.Vertex{2, 2, 2}
+	require.NoError(t, db.WritePeerStorage(testPub, []byte{1, 2, 3}))
+	require.NoError(t, db.WritePeerStorage(testPub2, []byte{4, 5}))
+
+	blob, err := db.ReadPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, blob)
+
+	// Overwrite the blob of the first peer.
+	require.NoError(t, db.WritePeerStorage(testPub, []byte{6}))
+
+	blobs, err = db.FetchPeerStorageBlobs()
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex][]byte{
+		testPub:  {6},
+		testPub2: {4, 5},
+	}, blobs)
+}
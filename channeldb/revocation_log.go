@@ -2,7 +2,9 @@ package channeldb
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io"
 	"math"
 
@@ -26,6 +28,16 @@ const (
 	revLogCommitTxHashType     tlv.Type = 2
 	revLogOurBalanceType       tlv.Type = 3
 	revLogTheirBalanceType     tlv.Type = 4
+	revLogHtlcRefsType         tlv.Type = 6
+
+	// htlcEntryKeySize is the size of the keys of the HTLC entries that
+	// are shared by compacted revocation logs.
+	htlcEntryKeySize = 8
+
+	// htlcEntryRefSize is the serialized size of a reference to a shared
+	// HTLC entry, which is the entry's key followed by the output index of
+	// the HTLC in the particular commitment.
+	htlcEntryRefSize = htlcEntryKeySize + 2
 )
 
 var (
@@ -45,6 +57,14 @@ var (
 	// attempting a non-cooperative channel closure.
 	revocationLogBucket = []byte("revocation-log")
 
+	// revocationLogHtlcBucket is a sub-bucket under openChannelBucket. It
+	// stores the HTLC entries of compacted revocation logs. An HTLC is
+	// usually part of many consecutive commitments, so instead of storing
+	// the full HTLC entry in every revocation log, compacted logs only
+	// reference the shared entry along with the output index of the HTLC
+	// in that particular commitment.
+	revocationLogHtlcBucket = []byte("revocation-log-htlcs")
+
 	// ErrLogEntryNotFound is returned when we cannot find a log entry at
 	// the height requested in the revocation log.
 	ErrLogEntryNotFound = errors.New("log entry not found")
@@ -52,6 +72,10 @@ var (
 	// ErrOutputIndexTooBig is returned when the output index is greater
 	// than uint16.
 	ErrOutputIndexTooBig = errors.New("output index is over uint16")
+
+	// ErrHTLCEntryNotFound is returned when a compacted revocation log
+	// references an HTLC entry that cannot be found.
+	ErrHTLCEntryNotFound = errors.New("htlc entry not found")
 )
 
 // HTLCEntry specifies the minimal info needed to be stored on disk for ALL the
@@ -229,9 +253,11 @@ type RevocationLog struct {
 // putRevocationLog uses the fields `CommitTx` and `Htlcs` from a
 // ChannelCommitment to construct a revocation log entry and saves them to
 // disk. It also saves our output index and their output index, which are
-// useful when creating breach retribution.
-func putRevocationLog(bucket kvdb.RwBucket, commit *ChannelCommitment,
-	ourOutputIndex, theirOutputIndex uint32, noAmtData bool) error {
+// useful when creating breach retribution. If an HTLC bucket is given, the
+// revocation log is compacted by sharing its HTLC entries through that bucket.
+func putRevocationLog(bucket, htlcBucket kvdb.RwBucket,
+	commit *ChannelCommitment, ourOutputIndex, theirOutputIndex uint32,
+	noAmtData bool) error {
 
 	// Sanity check that the output indexes can be safely converted.
 	if ourOutputIndex > math.MaxUint16 {
@@ -276,7 +302,7 @@ func putRevocationLog(bucket kvdb.RwBucket, commit *ChannelCommitment,
 	}
 
 	var b bytes.Buffer
-	err := serializeRevocationLog(&b, rl)
+	err := serializeRevocationLog(&b, rl, htlcBucket)
 	if err != nil {
 		return err
 	}
@@ -286,8 +312,9 @@ func putRevocationLog(bucket kvdb.RwBucket, commit *ChannelCommitment,
 }
 
 // fetchRevocationLog queries the revocation log bucket to find an log entry.
-// Return an error if not found.
-func fetchRevocationLog(log kvdb.RBucket,
+// The HTLC bucket is used to look up the HTLC entries of compacted logs and
+// may be nil if the channel has none. Return an error if not found.
+func fetchRevocationLog(log, htlcBucket kvdb.RBucket,
 	updateNum uint64) (RevocationLog, error) {
 
 	logEntrykey := makeLogKey(updateNum)
@@ -298,12 +325,185 @@ func fetchRevocationLog(log kvdb.RBucket,
 
 	commitReader := bytes.NewReader(commitBytes)
 
-	return deserializeRevocationLog(commitReader)
+	return deserializeRevocationLog(commitReader, htlcBucket)
+}
+
+// htlcEntryRef references an HTLC entry that is shared by compacted
+// revocation logs.
+type htlcEntryRef struct {
+	// key is the key of the shared entry in the HTLC bucket.
+	key [htlcEntryKeySize]byte
+
+	// outputIndex is the output index of the HTLC in the commitment of
+	// the revocation log that holds the reference.
+	outputIndex uint16
+}
+
+// htlcEntryKey returns the key of an HTLC entry in the HTLC bucket. It commits
+// to all fields of the entry except for the output index, which is the only
+// field that changes between the commitments that include the HTLC.
+func htlcEntryKey(htlc *HTLCEntry) [htlcEntryKeySize]byte {
+	var b [32 + 4 + 1 + 8]byte
+	copy(b[:32], htlc.RHash[:])
+	byteOrder.PutUint32(b[32:36], htlc.RefundTimeout)
+	if htlc.Incoming {
+		b[36] = 1
+	}
+	byteOrder.PutUint64(b[37:], uint64(htlc.Amt))
+
+	var key [htlcEntryKeySize]byte
+	hash := sha256.Sum256(b[:])
+	copy(key[:], hash[:])
+
+	return key
+}
+
+// putHTLCEntries stores the given HTLC entries in the HTLC bucket, unless
+// they are stored there already, and returns the references to them. In the
+// unlikely case that the key of an entry collides with a different entry, the
+// entry is returned to be stored inline with the revocation log instead.
+func putHTLCEntries(htlcBucket kvdb.RwBucket, htlcs []*HTLCEntry) (
+	[]htlcEntryRef, []*HTLCEntry, error) {
+
+	var (
+		refs   []htlcEntryRef
+		inline []*HTLCEntry
+	)
+	for _, htlc := range htlcs {
+		// The shared entry doesn't carry the output index, as it
+		// differs between commitments.
+		shared := *htlc
+		shared.OutputIndex = 0
+
+		var b bytes.Buffer
+		err := serializeHTLCEntries(&b, []*HTLCEntry{&shared})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := htlcEntryKey(htlc)
+		existing := htlcBucket.Get(key[:])
+		switch {
+		case existing == nil:
+			err := htlcBucket.Put(key[:], b.Bytes())
+			if err != nil {
+				return nil, nil, err
+			}
+
+		case !bytes.Equal(existing, b.Bytes()):
+			inline = append(inline, htlc)
+			continue
+		}
+
+		refs = append(refs, htlcEntryRef{
+			key:         key,
+			outputIndex: htlc.OutputIndex,
+		})
+	}
+
+	return refs, inline, nil
+}
+
+// fetchHTLCEntries looks up the HTLC entries of the given references in the
+// HTLC bucket.
+func fetchHTLCEntries(htlcBucket kvdb.RBucket,
+	refs []htlcEntryRef) ([]*HTLCEntry, error) {
+
+	if len(refs) > 0 && htlcBucket == nil {
+		return nil, ErrHTLCEntryNotFound
+	}
+
+	htlcs := make([]*HTLCEntry, 0, len(refs))
+	for _, ref := range refs {
+		entryBytes := htlcBucket.Get(ref.key[:])
+		if entryBytes == nil {
+			return nil, ErrHTLCEntryNotFound
+		}
+
+		entries, err := deserializeHTLCEntries(
+			bytes.NewReader(entryBytes),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) != 1 {
+			return nil, fmt.Errorf("expected a single htlc "+
+				"entry, got %d", len(entries))
+		}
+
+		entry := entries[0]
+		entry.OutputIndex = ref.outputIndex
+		htlcs = append(htlcs, entry)
+	}
+
+	return htlcs, nil
+}
+
+// encodeHTLCEntryRefs is the tlv encoder for a list of HTLC entry references.
+func encodeHTLCEntryRefs(w io.Writer, val interface{}, _ *[8]byte) error {
+	refs, ok := val.(*[]htlcEntryRef)
+	if !ok {
+		return tlv.NewTypeForEncodingErr(val, "[]htlcEntryRef")
+	}
+
+	for _, ref := range *refs {
+		var b [htlcEntryRefSize]byte
+		copy(b[:htlcEntryKeySize], ref.key[:])
+		byteOrder.PutUint16(b[htlcEntryKeySize:], ref.outputIndex)
+
+		if _, err := w.Write(b[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeHTLCEntryRefs is the tlv decoder for a list of HTLC entry references.
+func decodeHTLCEntryRefs(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	refs, ok := val.(*[]htlcEntryRef)
+	if !ok || l%htlcEntryRefSize != 0 {
+		return tlv.NewTypeForDecodingErr(
+			val, "[]htlcEntryRef", l, l-l%htlcEntryRefSize,
+		)
+	}
+
+	for i := uint64(0); i < l/htlcEntryRefSize; i++ {
+		var b [htlcEntryRefSize]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+
+		var ref htlcEntryRef
+		copy(ref.key[:], b[:htlcEntryKeySize])
+		ref.outputIndex = byteOrder.Uint16(b[htlcEntryKeySize:])
+		*refs = append(*refs, ref)
+	}
+
+	return nil
 }
 
 // serializeRevocationLog serializes a RevocationLog record based on tlv
-// format.
-func serializeRevocationLog(w io.Writer, rl *RevocationLog) error {
+// format. If an HTLC bucket is given, the HTLC entries of the log are stored
+// in that bucket and the log only references them.
+func serializeRevocationLog(w io.Writer, rl *RevocationLog,
+	htlcBucket kvdb.RwBucket) error {
+
+	// Compact the HTLC entries if requested.
+	var (
+		refs  []htlcEntryRef
+		htlcs = rl.HTLCEntries
+	)
+	if htlcBucket != nil {
+		var err error
+		refs, htlcs, err = putHTLCEntries(htlcBucket, rl.HTLCEntries)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Add the tlv records for all non-optional fields.
 	records := []tlv.Record{
 		tlv.MakePrimitiveRecord(
@@ -332,6 +532,14 @@ func serializeRevocationLog(w io.Writer, rl *RevocationLog) error {
 		))
 	}
 
+	if len(refs) > 0 {
+		records = append(records, tlv.MakeDynamicRecord(
+			revLogHtlcRefsType, &refs, func() uint64 {
+				return uint64(len(refs) * htlcEntryRefSize)
+			}, encodeHTLCEntryRefs, decodeHTLCEntryRefs,
+		))
+	}
+
 	// Create the tlv stream.
 	tlvStream, err := tlv.NewStream(records...)
 	if err != nil {
@@ -343,8 +551,8 @@ func serializeRevocationLog(w io.Writer, rl *RevocationLog) error {
 		return err
 	}
 
-	// Write the HTLCs.
-	return serializeHTLCEntries(w, rl.HTLCEntries)
+	// Write the HTLCs that are stored inline.
+	return serializeHTLCEntries(w, htlcs)
 }
 
 // serializeHTLCEntries serializes a list of HTLCEntry records based on tlv
@@ -375,11 +583,40 @@ func serializeHTLCEntries(w io.Writer, htlcs []*HTLCEntry) error {
 }
 
 // deserializeRevocationLog deserializes a RevocationLog based on tlv format.
-func deserializeRevocationLog(r io.Reader) (RevocationLog, error) {
+// The HTLC entries of compacted logs are looked up in the given HTLC bucket.
+func deserializeRevocationLog(r io.Reader,
+	htlcBucket kvdb.RBucket) (RevocationLog, error) {
+
+	rl, refs, err := decodeRevocationLog(r)
+	if err != nil {
+		return rl, err
+	}
+
+	// Look up the HTLC entries that are referenced by the log.
+	htlcs, err := fetchHTLCEntries(htlcBucket, refs)
+	if err != nil {
+		return rl, err
+	}
+
+	if len(htlcs) > 0 {
+		rl.HTLCEntries = append(htlcs, rl.HTLCEntries...)
+	}
+
+	return rl, nil
+}
+
+// decodeRevocationLog decodes a RevocationLog based on tlv format. Only the
+// HTLC entries that are stored inline are added to the log, while the
+// references to the shared HTLC entries of compacted logs are returned
+// separately.
+func decodeRevocationLog(r io.Reader) (RevocationLog, []htlcEntryRef,
+	error) {
+
 	var (
 		rl           RevocationLog
 		ourBalance   uint64
 		theirBalance uint64
+		refs         []htlcEntryRef
 	)
 
 	// Create the tlv stream.
@@ -397,15 +634,19 @@ func deserializeRevocationLog(r io.Reader) (RevocationLog, error) {
 		tlv.MakeBigSizeRecord(
 			revLogTheirBalanceType, &theirBalance,
 		),
+		tlv.MakeDynamicRecord(
+			revLogHtlcRefsType, &refs, nil, encodeHTLCEntryRefs,
+			decodeHTLCEntryRefs,
+		),
 	)
 	if err != nil {
-		return rl, err
+		return rl, nil, err
 	}
 
 	// Read the tlv stream.
 	parsedTypes, err := readTlvStream(r, tlvStream)
 	if err != nil {
-		return rl, err
+		return rl, nil, err
 	}
 
 	if t, ok := parsedTypes[revLogOurBalanceType]; ok && t == nil {
@@ -421,7 +662,7 @@ func deserializeRevocationLog(r io.Reader) (RevocationLog, error) {
 	// Read the HTLC entries.
 	rl.HTLCEntries, err = deserializeHTLCEntries(r)
 
-	return rl, err
+	return rl, refs, err
 }
 
 // deserializeHTLCEntries deserializes a list of HTLC entries based on tlv
@@ -535,7 +776,10 @@ func fetchRevocationLogCompatible(chanBucket kvdb.RBucket,
 	// Look into the new bucket first.
 	logBucket := chanBucket.NestedReadBucket(revocationLogBucket)
 	if logBucket != nil {
-		rl, err := fetchRevocationLog(logBucket, updateNum)
+		htlcBucket := chanBucket.NestedReadBucket(
+			revocationLogHtlcBucket,
+		)
+		rl, err := fetchRevocationLog(logBucket, htlcBucket, updateNum)
 		// We've found the record, no need to visit the old bucket.
 		if err == nil {
 			return &rl, nil, nil
@@ -586,7 +830,8 @@ func fetchLogBucket(chanBucket kvdb.RBucket) (kvdb.RBucket, error) {
 	return logBucket, nil
 }
 
-// deleteLogBucket deletes the both the new and old revocation log buckets.
+// deleteLogBucket deletes the both the new and old revocation log buckets, as
+// well as the bucket of shared HTLC entries.
 func deleteLogBucket(chanBucket kvdb.RwBucket) error {
 	// Check if the bucket exists and delete it.
 	logBucket := chanBucket.NestedReadWriteBucket(
@@ -599,6 +844,16 @@ func deleteLogBucket(chanBucket kvdb.RwBucket) error {
 		}
 	}
 
+	htlcBucket := chanBucket.NestedReadWriteBucket(
+		revocationLogHtlcBucket,
+	)
+	if htlcBucket != nil {
+		err := chanBucket.DeleteNestedBucket(revocationLogHtlcBucket)
+		if err != nil {
+			return err
+		}
+	}
+
 	// We also check whether the old revocation log bucket exists
 	// and delete it if so.
 	oldLogBucket := chanBucket.NestedReadWriteBucket(
@@ -615,3 +870,151 @@ func deleteLogBucket(chanBucket kvdb.RwBucket) error {
 
 	return nil
 }
+
+// revLogCompactBatchSize is the maximum number of revocation logs that are
+// compacted within a single database transaction, which keeps the
+// transactions short enough to compact the logs while the node is running.
+const revLogCompactBatchSize = 1000
+
+// CompactRevocationLogs compacts the revocation logs of all channels that
+// were written without sharing their HTLC entries. The logs are processed in
+// small batches, each in its own database transaction, so this can run in
+// the background while the channels keep operating. Logs that are already
+// compacted are left as they are, which makes the compaction safe to
+// interrupt and resume. The number of compacted logs is returned once all
+// logs have been visited or the quit channel is closed.
+func (c *ChannelStateDB) CompactRevocationLogs(
+	quit <-chan struct{}) (uint64, error) {
+
+	channels, err := c.FetchAllChannels()
+	if err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	for _, channel := range channels {
+		var (
+			next uint64
+			done bool
+		)
+		for !done {
+			select {
+			case <-quit:
+				return total, nil
+			default:
+			}
+
+			var compacted uint64
+			err := kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+				var err error
+				next, done, compacted, err = compactRevLogBatch(
+					tx, channel, next,
+				)
+
+				return err
+			}, func() {
+				compacted = 0
+			})
+			if err != nil {
+				return total, fmt.Errorf("unable to compact "+
+					"revocation log of channel %v: %w",
+					channel.FundingOutpoint, err)
+			}
+
+			total += compacted
+		}
+	}
+
+	return total, nil
+}
+
+// compactRevLogBatch compacts a batch of revocation logs of the given channel,
+// starting at the given commit height. It returns the commit height to
+// continue at and whether all logs of the channel have been visited, along
+// with the number of logs that were compacted.
+func compactRevLogBatch(tx kvdb.RwTx, channel *OpenChannel,
+	start uint64) (uint64, bool, uint64, error) {
+
+	chanBucket, err := fetchChanBucketRw(
+		tx, channel.IdentityPub, &channel.FundingOutpoint,
+		channel.ChainHash,
+	)
+	switch {
+	// The channel may have been closed in the meantime, in which case
+	// its revocation log is gone.
+	case errors.Is(err, ErrNoChanDBExists),
+		errors.Is(err, ErrNoActiveChannels),
+		errors.Is(err, ErrChannelNotFound):
+
+		return 0, true, 0, nil
+
+	case err != nil:
+		return 0, false, 0, err
+	}
+
+	logBucket := chanBucket.NestedReadWriteBucket(revocationLogBucket)
+	if logBucket == nil {
+		return 0, true, 0, nil
+	}
+
+	htlcBucket, err := chanBucket.CreateBucketIfNotExists(
+		revocationLogHtlcBucket,
+	)
+	if err != nil {
+		return 0, false, 0, err
+	}
+
+	// Collect the logs of this batch that still store their HTLC entries
+	// inline. They are only rewritten after the iteration, as modifying
+	// the bucket invalidates the cursor.
+	var (
+		logs    = make(map[uint64]*RevocationLog)
+		visited int
+		done    = true
+		next    uint64
+	)
+	startKey := makeLogKey(start)
+	cursor := logBucket.ReadWriteCursor()
+	for k, v := cursor.Seek(startKey[:]); k != nil; k, v = cursor.Next() {
+		height := byteOrder.Uint64(k)
+		if visited == revLogCompactBatchSize {
+			next, done = height, false
+			break
+		}
+		visited++
+
+		rl, refs, err := decodeRevocationLog(bytes.NewReader(v))
+		if err != nil {
+			return 0, false, 0, err
+		}
+
+		// Logs that don't store any HTLC entries inline have nothing
+		// to compact.
+		if len(rl.HTLCEntries) == 0 {
+			continue
+		}
+
+		htlcs, err := fetchHTLCEntries(htlcBucket, refs)
+		if err != nil {
+			return 0, false, 0, err
+		}
+		rl.HTLCEntries = append(htlcs, rl.HTLCEntries...)
+
+		logs[height] = &rl
+	}
+
+	for height, rl := range logs {
+		var b bytes.Buffer
+		err := serializeRevocationLog(&b, rl, htlcBucket)
+		if err != nil {
+			return 0, false, 0, err
+		}
+
+		logKey := makeLogKey(height)
+		if err := logBucket.Put(logKey[:], b.Bytes()); err != nil {
+			return 0, false, 0, err
+		}
+	}
+
+	return next, done, uint64(len(logs)), nil
+}
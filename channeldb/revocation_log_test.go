@@ -2,6 +2,7 @@ package channeldb
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"math"
 	"math/rand"
@@ -285,7 +286,7 @@ func testSerializeRevocationLog(t *testing.T, rl *RevocationLog,
 
 	// Write the tlv stream.
 	buf := bytes.NewBuffer([]byte{})
-	err := serializeRevocationLog(buf, rl)
+	err := serializeRevocationLog(buf, rl, nil)
 	require.NoError(t, err)
 
 	// Check the expected bytes on the body of the revocation log.
@@ -301,7 +302,7 @@ func testDerializeRevocationLog(t *testing.T, revLog *RevocationLog,
 
 	// Read the tlv stream.
 	buf := bytes.NewBuffer(revLogBytes)
-	rl, err := deserializeRevocationLog(buf)
+	rl, err := deserializeRevocationLog(buf, nil)
 	require.NoError(t, err)
 
 	// Check the bytes are read as expected.
@@ -521,8 +522,8 @@ func TestPutRevocationLog(t *testing.T) {
 
 			// Save the log.
 			err = putRevocationLog(
-				bucket, &tc.commit, tc.ourIndex, tc.theirIndex,
-				tc.noAmtData,
+				bucket, nil, &tc.commit, tc.ourIndex,
+				tc.theirIndex, tc.noAmtData,
 			)
 			if err != nil {
 				return RevocationLog{}, err
@@ -530,7 +531,7 @@ func TestPutRevocationLog(t *testing.T) {
 
 			// Read the saved log.
 			return fetchRevocationLog(
-				bucket, tc.commit.CommitHeight,
+				bucket, nil, tc.commit.CommitHeight,
 			)
 		}
 
@@ -630,7 +631,8 @@ func TestFetchRevocationLogCompatible(t *testing.T) {
 				require.NoError(t, err)
 
 				err = putRevocationLog(
-					lb, &testChannelCommit, 0, 1, false,
+					lb, nil, &testChannelCommit, 0, 1,
+					false,
 				)
 				require.NoError(t, err)
 			}
@@ -706,3 +708,226 @@ func createTestRevocatoinLogBuckets(tx kvdb.RwTx) (kvdb.RwBucket,
 
 	return chanBucket, logBucket, nil
 }
+
+// TestPutCompactRevocationLog checks that compacted revocation logs share
+// their HTLC entries while preserving the output index of each commitment.
+func TestPutCompactRevocationLog(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	backend := fullDB.ChannelStateDB().backend
+
+	// Create a second commitment that includes the same HTLC at a
+	// different output index.
+	nextCommit := testChannelCommit
+	nextCommit.CommitHeight++
+	nextCommit.Htlcs = []HTLC{testChannelCommit.Htlcs[0]}
+	nextCommit.Htlcs[0].OutputIndex++
+
+	err = kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		chanBucket, logBucket, err := createTestRevocatoinLogBuckets(tx)
+		require.NoError(t, err)
+
+		htlcBucket, err := chanBucket.CreateBucket(
+			revocationLogHtlcBucket,
+		)
+		require.NoError(t, err)
+
+		for _, commit := range []*ChannelCommitment{
+			&testChannelCommit, &nextCommit,
+		} {
+			err := putRevocationLog(
+				logBucket, htlcBucket, commit, 0, 1, false,
+			)
+			require.NoError(t, err)
+		}
+
+		// Both logs should reference a single shared entry.
+		var numEntries int
+		err = htlcBucket.ForEach(func(_, _ []byte) error {
+			numEntries++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, numEntries)
+
+		// The first log is read back unchanged.
+		rl, err := fetchRevocationLog(
+			logBucket, htlcBucket, testChannelCommit.CommitHeight,
+		)
+		require.NoError(t, err)
+		require.Equal(t, testRevocationLogWithAmts, rl)
+
+		// The second log has its own output index.
+		rl, err = fetchRevocationLog(
+			logBucket, htlcBucket, nextCommit.CommitHeight,
+		)
+		require.NoError(t, err)
+		require.Len(t, rl.HTLCEntries, 1)
+		require.Equal(t, testHTLCEntry.OutputIndex+1,
+			rl.HTLCEntries[0].OutputIndex)
+
+		// Without the HTLC bucket the logs can't be read.
+		_, err = fetchRevocationLog(
+			logBucket, nil, nextCommit.CommitHeight,
+		)
+		require.ErrorIs(t, err, ErrHTLCEntryNotFound)
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestFetchLegacyAndCompactRevocationLogs checks that a channel with both
+// legacy and compacted revocation logs can be read regardless of whether the
+// database was opened with compaction enabled.
+func TestFetchLegacyAndCompactRevocationLogs(t *testing.T) {
+	t.Parallel()
+
+	for _, compact := range []bool{false, true} {
+		compact := compact
+
+		t.Run(fmt.Sprintf("compact=%v", compact), func(t *testing.T) {
+			t.Parallel()
+
+			testFetchLegacyAndCompactRevocationLogs(t, compact)
+		})
+	}
+}
+
+func testFetchLegacyAndCompactRevocationLogs(t *testing.T, compact bool) {
+	fullDB, err := MakeTestDB(t, OptionCompactRevLog(compact))
+	require.NoError(t, err)
+
+	backend := fullDB.ChannelStateDB().backend
+
+	// Write a legacy log, followed by a compacted one.
+	nextCommit := testChannelCommit
+	nextCommit.CommitHeight++
+
+	err = kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		chanBucket, logBucket, err := createTestRevocatoinLogBuckets(tx)
+		require.NoError(t, err)
+
+		err = putRevocationLog(
+			logBucket, nil, &testChannelCommit, 0, 1, false,
+		)
+		require.NoError(t, err)
+
+		htlcBucket, err := chanBucket.CreateBucket(
+			revocationLogHtlcBucket,
+		)
+		require.NoError(t, err)
+
+		return putRevocationLog(
+			logBucket, htlcBucket, &nextCommit, 0, 1, false,
+		)
+	}, func() {})
+	require.NoError(t, err)
+
+	// Both logs are read back unchanged.
+	err = kvdb.View(backend, func(tx kvdb.RTx) error {
+		chanBucket := tx.ReadBucket(openChannelBucket)
+
+		for _, commit := range []*ChannelCommitment{
+			&testChannelCommit, &nextCommit,
+		} {
+			rl, _, err := fetchRevocationLogCompatible(
+				chanBucket, commit.CommitHeight,
+			)
+			require.NoError(t, err)
+			require.Equal(
+				t, testRevocationLogWithAmts.HTLCEntries,
+				rl.HTLCEntries,
+			)
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestCompactRevocationLogs checks that the revocation logs of existing
+// channels are compacted and can still be read afterwards.
+func TestCompactRevocationLogs(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	cdb := fullDB.ChannelStateDB()
+	channel := createTestChannel(t, cdb, openChannelOption())
+
+	// Write more logs than fit into a single batch, none of which is
+	// compacted.
+	const numLogs = revLogCompactBatchSize + 1
+	err = kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, channel.IdentityPub, &channel.FundingOutpoint,
+			channel.ChainHash,
+		)
+		require.NoError(t, err)
+
+		logBucket, err := chanBucket.CreateBucketIfNotExists(
+			revocationLogBucket,
+		)
+		require.NoError(t, err)
+
+		for i := uint64(0); i < numLogs; i++ {
+			commit := testChannelCommit
+			commit.CommitHeight = i
+
+			err := putRevocationLog(
+				logBucket, nil, &commit, 0, 1, false,
+			)
+			require.NoError(t, err)
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	compacted, err := cdb.CompactRevocationLogs(make(chan struct{}))
+	require.NoError(t, err)
+	require.EqualValues(t, numLogs, compacted)
+
+	// A second run has nothing left to compact.
+	compacted, err = cdb.CompactRevocationLogs(make(chan struct{}))
+	require.NoError(t, err)
+	require.Zero(t, compacted)
+
+	// All logs are still readable and reference the shared entry.
+	err = kvdb.View(cdb.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, channel.IdentityPub, &channel.FundingOutpoint,
+			channel.ChainHash,
+		)
+		require.NoError(t, err)
+
+		logBucket := chanBucket.NestedReadBucket(revocationLogBucket)
+		err = logBucket.ForEach(func(_, v []byte) error {
+			rl, refs, err := decodeRevocationLog(
+				bytes.NewReader(v),
+			)
+			require.NoError(t, err)
+			require.Empty(t, rl.HTLCEntries)
+			require.Len(t, refs, 1)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		for i := uint64(0); i < numLogs; i++ {
+			rl, _, err := fetchRevocationLogCompatible(
+				chanBucket, i,
+			)
+			require.NoError(t, err)
+			require.Equal(t, testRevocationLogWithAmts, *rl)
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
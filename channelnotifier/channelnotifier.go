@@ -5,6 +5,8 @@ import (
 
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/subscribe"
 )
 
@@ -80,6 +82,79 @@ type FullyResolvedChannelEvent struct {
 	ChannelPoint *wire.OutPoint
 }
 
+// DrainReason describes why new HTLCs are no longer forwarded through a
+// channel.
+type DrainReason uint8
+
+const (
+	// DrainReasonShutdown is used when the peer sent a Shutdown message to
+	// cooperatively close the channel.
+	DrainReasonShutdown DrainReason = iota
+
+	// DrainReasonFlapping is used when the peer disconnected repeatedly,
+	// so the channel is likely to be closed or stuck with HTLCs.
+	DrainReasonFlapping
+)
+
+// String returns a human readable version of the drain reason.
+func (r DrainReason) String() string {
+	switch r {
+	case DrainReasonShutdown:
+		return "shutdown"
+
+	case DrainReasonFlapping:
+		return "flapping"
+
+	default:
+		return "unknown"
+	}
+}
+
+// ChannelDrainingEvent represents a new event where new HTLCs are no longer
+// forwarded through a channel, so its outstanding HTLCs can be resolved.
+type ChannelDrainingEvent struct {
+	// ChannelPoint is the channel point for the draining channel.
+	ChannelPoint *wire.OutPoint
+
+	// Reason is the reason the channel is drained.
+	Reason DrainReason
+}
+
+// ChannelDrainedEvent represents a new event where a draining channel has no
+// more outstanding HTLCs.
+type ChannelDrainedEvent struct {
+	// ChannelPoint is the channel point for the drained channel.
+	ChannelPoint *wire.OutPoint
+}
+
+// ChannelForwardsResumedEvent represents a new event where new HTLCs are
+// forwarded through a draining channel again, as its peer became stable.
+type ChannelForwardsResumedEvent struct {
+	// ChannelPoint is the channel point for the resumed channel.
+	ChannelPoint *wire.OutPoint
+}
+
+// ChannelFailureEvent represents a new event where a channel failed, either
+// because we failed its link or because the peer sent an error or a warning
+// for it.
+type ChannelFailureEvent struct {
+	// ChannelPoint is the channel point for the failed channel.
+	ChannelPoint *wire.OutPoint
+
+	// Remote is true if the peer sent the error or warning.
+	Remote bool
+
+	// Warning is true if the failure is a warning, which doesn't fail the
+	// channel altogether.
+	Warning bool
+
+	// Code is the machine-readable reason of the failure, if known.
+	Code fn.Option[lnwire.ErrorCode]
+
+	// Message is the human-readable description of the failure.
+	Message string
+}
+
 // New creates a new channel notifier. The ChannelNotifier gets channel
 // events from peers and from the chain arbitrator, and dispatches them to
 // its clients.
@@ -219,3 +294,43 @@ func (c *ChannelNotifier) NotifyInactiveChannelEvent(chanPoint wire.OutPoint) {
 		log.Warnf("Unable to send inactive channel update: %v", err)
 	}
 }
+
+// NotifyChannelDrainingEvent notifies the channelEventNotifier goroutine that
+// new HTLCs are no longer forwarded through a channel.
+func (c *ChannelNotifier) NotifyChannelDrainingEvent(chanPoint wire.OutPoint,
+	reason DrainReason) {
+
+	event := ChannelDrainingEvent{ChannelPoint: &chanPoint, Reason: reason}
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send channel draining update: %v", err)
+	}
+}
+
+// NotifyChannelDrainedEvent notifies the channelEventNotifier goroutine that a
+// draining channel has no more outstanding HTLCs.
+func (c *ChannelNotifier) NotifyChannelDrainedEvent(chanPoint wire.OutPoint) {
+	event := ChannelDrainedEvent{ChannelPoint: &chanPoint}
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send channel drained update: %v", err)
+	}
+}
+
+// NotifyChannelForwardsResumedEvent notifies the channelEventNotifier
+// goroutine that new HTLCs are forwarded through a draining channel again.
+func (c *ChannelNotifier) NotifyChannelForwardsResumedEvent(
+	chanPoint wire.OutPoint) {
+
+	event := ChannelForwardsResumedEvent{ChannelPoint: &chanPoint}
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send channel forwards resumed update: %v",
+			err)
+	}
+}
+
+// NotifyChannelFailureEvent notifies the channelEventNotifier goroutine that a
+// channel failed.
+func (c *ChannelNotifier) NotifyChannelFailureEvent(event ChannelFailureEvent) {
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send channel failure update: %v", err)
+	}
+}
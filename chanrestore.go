@@ -1,6 +1,7 @@
 package lnd
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"net"
@@ -12,6 +13,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/shachain"
 )
@@ -101,6 +103,19 @@ func (c *chanDBRestorer) openChannelShell(backup chanbackup.Single) (
 		privKey, err := c.secretKeys.DerivePrivKey(
 			backup.ShaChainRootDesc,
 		)
+		// With remote signing, the private key isn't available
+		// locally, so we'll give a hint where the channel can be
+		// restored instead.
+		remoteSigning := errors.Is(
+			err, rpcwallet.ErrRemoteSigningPrivateKeyNotAvailable,
+		)
+		if remoteSigning {
+			return nil, fmt.Errorf("channel %v uses the legacy "+
+				"revocation root format, which requires the "+
+				"private key and can only be restored without "+
+				"remote signing: %w", backup.FundingOutpoint,
+				err)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("could not derive private key "+
 				"for legacy channel revocation root format: "+
@@ -0,0 +1,302 @@
+// Package cltvguard watches the blocks delivered by the chain backend and
+// requires larger CLTV deltas for forwards while block production is
+// irregular or the backend lags behind the chain.
+package cltvguard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+// Condition is an irregularity of the chain that engages the guard.
+type Condition string
+
+const (
+	// ConditionBlockStorm holds when many blocks arrived within a short
+	// window. Blocks arriving in quick succession leave the htlcs we
+	// forward less time to be resolved off-chain than expected.
+	ConditionBlockStorm Condition = "block_storm"
+
+	// ConditionStaleTip holds when the timestamp of the best block is
+	// long in the past, either because no blocks were found or because
+	// the chain backend lags behind. Once it catches up, many blocks will
+	// arrive at once.
+	ConditionStaleTip Condition = "stale_tip"
+)
+
+// Config contains the dependencies and parameters of the guard.
+type Config struct {
+	// RegisterBlockEpochNtfn registers for notifications of new blocks.
+	RegisterBlockEpochNtfn func(*chainntnfs.BlockEpoch) (
+		*chainntnfs.BlockEpochEvent, error)
+
+	// Clock is used to time the arrival of blocks and the age of the
+	// best block.
+	Clock clock.Clock
+
+	// Ticker triggers the periodic checks of the age of the best block.
+	Ticker ticker.Ticker
+
+	// StormBlocks is the number of blocks that must arrive within the
+	// StormWindow to detect a block storm. Zero disables the detection.
+	StormBlocks int
+
+	// StormWindow is the window block storms are detected in.
+	StormWindow time.Duration
+
+	// MaxTipAge is the age of the timestamp of the best block above which
+	// the tip is stale. Zero disables the detection.
+	MaxTipAge time.Duration
+
+	// ExtraDelta is the number of blocks added to the CLTV deltas we
+	// require for forwards while the guard is engaged.
+	ExtraDelta uint32
+
+	// HoldTime is the time the guard stays engaged after the last
+	// condition cleared.
+	HoldTime time.Duration
+}
+
+// Status describes the state of the guard.
+type Status struct {
+	// Engaged is true if the guard currently requires the extra delta.
+	Engaged bool `json:"engaged"`
+
+	// Conditions are the conditions that currently hold. The guard may
+	// still be engaged while none hold, until the hold time passed.
+	Conditions []Condition `json:"conditions"`
+
+	// ExtraDelta is the number of blocks currently added to the CLTV
+	// deltas we require.
+	ExtraDelta uint32 `json:"extra_delta"`
+
+	// Since is the time the guard got engaged, if it is.
+	Since time.Time `json:"since,omitempty"`
+
+	// BestHeight is the height of the best block.
+	BestHeight int32 `json:"best_height"`
+
+	// TipAge is the age of the timestamp of the best block.
+	TipAge time.Duration `json:"tip_age"`
+
+	// RecentBlocks is the number of blocks that arrived within the storm
+	// window.
+	RecentBlocks int `json:"recent_blocks"`
+}
+
+// Guard detects irregular block production and a lagging chain backend, and
+// raises the CLTV deltas required for forwards while they are detected.
+type Guard struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// arrivals are the arrival times of the blocks within the storm
+	// window.
+	arrivals []time.Time
+
+	// bestHeight and tipTime describe the best block.
+	bestHeight int32
+	tipTime    time.Time
+
+	// engagedSince is the time the guard got engaged, zero if it isn't.
+	engagedSince time.Time
+
+	// lastTriggered is the last time any condition held.
+	lastTriggered time.Time
+
+	// conditions are the conditions found by the last check.
+	conditions []Condition
+
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new guard.
+func New(cfg *Config) *Guard {
+	return &Guard{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start registers for new blocks and starts the periodic checks.
+func (g *Guard) Start() error {
+	var startErr error
+	g.started.Do(func() {
+		log.Info("CLTV guard starting")
+
+		blockEpochs, err := g.cfg.RegisterBlockEpochNtfn(nil)
+		if err != nil {
+			startErr = fmt.Errorf("unable to register for block "+
+				"epochs: %w", err)
+
+			return
+		}
+
+		g.cfg.Ticker.Resume()
+
+		g.wg.Add(1)
+		go g.run(blockEpochs)
+	})
+
+	return startErr
+}
+
+// Stop stops the guard.
+func (g *Guard) Stop() error {
+	g.stopped.Do(func() {
+		log.Info("CLTV guard shutting down...")
+		defer log.Debug("CLTV guard shutdown complete")
+
+		close(g.quit)
+		g.wg.Wait()
+
+		g.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// ExtraDelta returns the number of blocks to add to the CLTV deltas required
+// for forwards, which is zero unless the guard is engaged.
+func (g *Guard) ExtraDelta() uint32 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.engagedSince.IsZero() {
+		return 0
+	}
+
+	return g.cfg.ExtraDelta
+}
+
+// Status returns the current state of the guard.
+func (g *Guard) Status() *Status {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.cfg.Clock.Now()
+	status := &Status{
+		Engaged:      !g.engagedSince.IsZero(),
+		Conditions:   append([]Condition{}, g.conditions...),
+		Since:        g.engagedSince,
+		BestHeight:   g.bestHeight,
+		RecentBlocks: g.recentBlocks(now),
+	}
+	if status.Engaged {
+		status.ExtraDelta = g.cfg.ExtraDelta
+	}
+	if !g.tipTime.IsZero() {
+		status.TipAge = now.Sub(g.tipTime)
+	}
+
+	return status
+}
+
+// run processes new blocks and checks the conditions on every block and tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (g *Guard) run(blockEpochs *chainntnfs.BlockEpochEvent) {
+	defer g.wg.Done()
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				log.Warn("Block epoch stream closed")
+				return
+			}
+
+			g.blockConnected(epoch)
+
+		case <-g.cfg.Ticker.Ticks():
+			g.check()
+
+		case <-g.quit:
+			return
+		}
+	}
+}
+
+// blockConnected records the arrival of a new block and checks the
+// conditions.
+func (g *Guard) blockConnected(epoch *chainntnfs.BlockEpoch) {
+	g.mu.Lock()
+	g.arrivals = append(g.arrivals, g.cfg.Clock.Now())
+	g.bestHeight = epoch.Height
+	if epoch.BlockHeader != nil {
+		g.tipTime = epoch.BlockHeader.Timestamp
+	}
+	g.mu.Unlock()
+
+	g.check()
+}
+
+// check evaluates the conditions, and engages or releases the guard.
+func (g *Guard) check() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.cfg.Clock.Now()
+
+	// Drop the arrivals that fell out of the storm window.
+	cutoff := now.Add(-g.cfg.StormWindow)
+	for len(g.arrivals) > 0 && !g.arrivals[0].After(cutoff) {
+		g.arrivals = g.arrivals[1:]
+	}
+
+	var conditions []Condition
+	if g.cfg.StormBlocks > 0 && len(g.arrivals) >= g.cfg.StormBlocks {
+		conditions = append(conditions, ConditionBlockStorm)
+	}
+	if g.cfg.MaxTipAge > 0 && !g.tipTime.IsZero() &&
+		now.Sub(g.tipTime) > g.cfg.MaxTipAge {
+
+		conditions = append(conditions, ConditionStaleTip)
+	}
+	g.conditions = conditions
+
+	switch {
+	case len(conditions) > 0:
+		g.lastTriggered = now
+		if g.engagedSince.IsZero() {
+			g.engagedSince = now
+			log.Warnf("CLTV guard engaged at height %v, requiring "+
+				"%v extra blocks of CLTV delta: %v",
+				g.bestHeight, g.cfg.ExtraDelta, conditions)
+		}
+
+	case !g.engagedSince.IsZero() &&
+		now.Sub(g.lastTriggered) >= g.cfg.HoldTime:
+
+		g.engagedSince = time.Time{}
+		log.Infof("CLTV guard released at height %v", g.bestHeight)
+	}
+}
+
+// recentBlocks returns the number of blocks that arrived within the storm
+// window.
+//
+// NOTE: The mutex of the guard must be held.
+func (g *Guard) recentBlocks(now time.Time) int {
+	cutoff := now.Add(-g.cfg.StormWindow)
+
+	var count int
+	for _, arrival := range g.arrivals {
+		if arrival.After(cutoff) {
+			count++
+		}
+	}
+
+	return count
+}
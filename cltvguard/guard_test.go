@@ -0,0 +1,157 @@
+package cltvguard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+// newEpoch returns a block epoch at the given height with the given block
+// timestamp.
+func newEpoch(height int32, timestamp time.Time) *chainntnfs.BlockEpoch {
+	return &chainntnfs.BlockEpoch{
+		Height:      height,
+		BlockHeader: &wire.BlockHeader{Timestamp: timestamp},
+	}
+}
+
+// TestGuardConditions tests that the guard engages on block storms and stale
+// tips, and releases once the hold time passed without any condition.
+func TestGuardConditions(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Unix(1_000_000, 0))
+	guard := New(&Config{
+		Clock:       testClock,
+		Ticker:      ticker.NewForce(time.Hour),
+		StormBlocks: 3,
+		StormWindow: 10 * time.Minute,
+		MaxTipAge:   time.Hour,
+		ExtraDelta:  24,
+		HoldTime:    30 * time.Minute,
+	})
+
+	// advance moves the clock forward by the given duration.
+	advance := func(d time.Duration) time.Time {
+		testClock.SetTime(testClock.Now().Add(d))
+		return testClock.Now()
+	}
+
+	// Blocks arriving at regular intervals don't engage the guard.
+	height := int32(100)
+	for i := 0; i < 3; i++ {
+		height++
+		guard.blockConnected(newEpoch(height, advance(10*time.Minute)))
+	}
+	require.Zero(t, guard.ExtraDelta())
+
+	status := guard.Status()
+	require.False(t, status.Engaged)
+	require.Empty(t, status.Conditions)
+	require.Equal(t, height, status.BestHeight)
+	require.Equal(t, 1, status.RecentBlocks)
+
+	// Three blocks within the storm window engage the guard.
+	stormStart := advance(10 * time.Minute)
+	for i := 0; i < 2; i++ {
+		height++
+		guard.blockConnected(newEpoch(height, advance(time.Minute)))
+	}
+	require.Zero(t, guard.ExtraDelta())
+
+	height++
+	guard.blockConnected(newEpoch(height, advance(time.Minute)))
+	require.EqualValues(t, 24, guard.ExtraDelta())
+
+	status = guard.Status()
+	require.True(t, status.Engaged)
+	require.Equal(t, []Condition{ConditionBlockStorm}, status.Conditions)
+	require.EqualValues(t, 24, status.ExtraDelta)
+	require.Equal(t, stormStart.Add(3*time.Minute), status.Since)
+	require.Equal(t, 3, status.RecentBlocks)
+
+	// Once the storm passed, the guard stays engaged for the hold time.
+	advance(10 * time.Minute)
+	guard.check()
+	status = guard.Status()
+	require.True(t, status.Engaged)
+	require.Empty(t, status.Conditions)
+
+	advance(20 * time.Minute)
+	guard.check()
+	require.Zero(t, guard.ExtraDelta())
+	require.False(t, guard.Status().Engaged)
+
+	// If no block arrives for too long, the tip is stale and the guard
+	// engages again.
+	advance(31 * time.Minute)
+	guard.check()
+	require.EqualValues(t, 24, guard.ExtraDelta())
+
+	status = guard.Status()
+	require.Equal(t, []Condition{ConditionStaleTip}, status.Conditions)
+	require.Greater(t, status.TipAge, time.Hour)
+
+	// A block that is old on arrival, as delivered by a lagging backend,
+	// keeps the tip stale.
+	height++
+	tipTime := testClock.Now().Add(-2 * time.Hour)
+	guard.blockConnected(newEpoch(height, tipTime))
+	require.Equal(
+		t, []Condition{ConditionStaleTip}, guard.Status().Conditions,
+	)
+
+	// Once the backend caught up, the tip is fresh again.
+	height++
+	guard.blockConnected(newEpoch(height, testClock.Now()))
+	require.Empty(t, guard.Status().Conditions)
+	require.EqualValues(t, 24, guard.ExtraDelta())
+}
+
+// TestGuardStartStop tests that the guard processes the blocks it is notified
+// of once started.
+func TestGuardStartStop(t *testing.T) {
+	t.Parallel()
+
+	epochs := make(chan *chainntnfs.BlockEpoch)
+	testClock := clock.NewTestClock(time.Unix(1_000_000, 0))
+	guard := New(&Config{
+		RegisterBlockEpochNtfn: func(*chainntnfs.BlockEpoch) (
+			*chainntnfs.BlockEpochEvent, error) {
+
+			return &chainntnfs.BlockEpochEvent{
+				Epochs: epochs,
+				Cancel: func() {},
+			}, nil
+		},
+		Clock:       testClock,
+		Ticker:      ticker.NewForce(time.Hour),
+		StormBlocks: 2,
+		StormWindow: time.Minute,
+		ExtraDelta:  10,
+	})
+
+	require.NoError(t, guard.Start())
+	t.Cleanup(func() {
+		require.NoError(t, guard.Stop())
+	})
+
+	for height := int32(1); height <= 2; height++ {
+		select {
+		case epochs <- newEpoch(height, testClock.Now()):
+		case <-time.After(testTimeout):
+			t.Fatalf("block epoch not consumed")
+		}
+	}
+
+	require.Eventually(t, func() bool {
+		return guard.ExtraDelta() == 10
+	}, testTimeout, 10*time.Millisecond)
+}
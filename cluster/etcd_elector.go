@@ -5,6 +5,7 @@ package cluster
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/lightningnetwork/lnd/kvdb/etcd"
@@ -113,3 +114,40 @@ func (e *etcdLeaderElector) Campaign(ctx context.Context) error {
 func (e *etcdLeaderElector) Resign() error {
 	return e.election.Resign(context.Background())
 }
+
+// IsLeader returns true if the caller currently holds the leader role.
+func (e *etcdLeaderElector) IsLeader(ctx context.Context) (bool, error) {
+	// Without a leader key we never campaigned or already resigned.
+	key := e.election.Key()
+	if key == "" {
+		return false, nil
+	}
+
+	resp, err := e.election.Leader(ctx)
+	switch {
+	case errors.Is(err, concurrency.ErrElectionNoLeader):
+		return false, nil
+
+	case err != nil:
+		return false, err
+	}
+
+	return string(resp.Kvs[0].Key) == key, nil
+}
+
+// FencingToken returns the fencing token of the caller's leadership term,
+// which is the creation revision of its leader key. Etcd revisions are
+// strictly increasing, so every new term has a higher token.
+func (e *etcdLeaderElector) FencingToken() (uint64, error) {
+	rev := e.election.Rev()
+	if e.election.Key() == "" || rev <= 0 {
+		return 0, ErrNotLeader
+	}
+
+	return uint64(rev), nil
+}
+
+// Done returns a channel that is closed once the election session expired.
+func (e *etcdLeaderElector) Done() <-chan struct{} {
+	return e.session.Done()
+}
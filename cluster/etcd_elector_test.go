@@ -87,13 +87,32 @@ func TestEtcdElector(t *testing.T) {
 	tmp := <-ch
 	first, err := tmp.Leader(ctxb)
 	require.NoError(t, err)
+
+	isLeader, err := tmp.IsLeader(ctxb)
+	require.NoError(t, err)
+	require.True(t, isLeader)
+
+	firstToken, err := tmp.FencingToken()
+	require.NoError(t, err)
 	require.NoError(t, tmp.Resign())
 
 	tmp = <-ch
 	second, err := tmp.Leader(ctxb)
 	require.NoError(t, err)
+
+	state, err := GetLeadershipState(ctxb, tmp, second)
+	require.NoError(t, err)
+	require.True(t, state.IsLeader)
+	require.Equal(t, second, state.Leader)
+
+	// The fencing token of the second term must be higher.
+	require.Greater(t, state.FencingToken, firstToken)
 	require.NoError(t, tmp.Resign())
 
+	isLeader, err = tmp.IsLeader(ctxb)
+	require.NoError(t, err)
+	require.False(t, isLeader)
+
 	require.Contains(t, []string{id1, id2}, first)
 	require.Contains(t, []string{id1, id2}, second)
 	require.NotEqual(t, first, second)
@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"context"
+	"errors"
 )
 
 const (
@@ -10,6 +11,10 @@ const (
 	EtcdLeaderElector = "etcd"
 )
 
+// ErrNotLeader is returned when an operation requires the caller to be the
+// leader, but it isn't.
+var ErrNotLeader = errors.New("not the leader")
+
 // LeaderElector is a general interface implementing basic leader elections
 // in a clustered environment.
 type LeaderElector interface {
@@ -23,4 +28,68 @@ type LeaderElector interface {
 
 	// Leader returns the leader value for the current election.
 	Leader(ctx context.Context) (string, error)
+
+	// IsLeader returns true if the caller currently holds the leader role.
+	IsLeader(ctx context.Context) (bool, error)
+
+	// FencingToken returns the fencing token of the caller's leadership
+	// term. Tokens strictly increase with every new term, which allows
+	// the storage to reject writes of a previous leader that isn't aware
+	// yet that it was replaced. It must only be called after Campaign
+	// returned successfully.
+	FencingToken() (uint64, error)
+
+	// Done returns a channel that is closed once the caller's election
+	// session expired, for example because the connection to the election
+	// governor was lost. Once that happens, the caller may no longer be
+	// the leader and must stop acting as such.
+	Done() <-chan struct{}
+}
+
+// LeadershipState describes the state of the leader election from the
+// perspective of a cluster member.
+type LeadershipState struct {
+	// ID is the identifier of the cluster member.
+	ID string
+
+	// Leader is the identifier of the current leader.
+	Leader string
+
+	// IsLeader is true if the member is the current leader.
+	IsLeader bool
+
+	// FencingToken is the fencing token of the member's leadership term.
+	// It is zero if the member isn't the leader.
+	FencingToken uint64
+}
+
+// GetLeadershipState returns the leadership state of the cluster member with
+// the given identifier.
+func GetLeadershipState(ctx context.Context, elector LeaderElector,
+	id string) (*LeadershipState, error) {
+
+	leader, err := elector.Leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	isLeader, err := elector.IsLeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &LeadershipState{
+		ID:       id,
+		Leader:   leader,
+		IsLeader: isLeader,
+	}
+
+	if isLeader {
+		state.FencingToken, err = elector.FencingToken()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var bumpCloseFeeCommand = cli.Command{
+	Name:     "bumpclosefee",
+	Category: "Channels",
+	Usage:    "Bump the fee of a pending cooperative close.",
+	Description: `
+	Bump the fee of the RBF cooperative close transaction of a channel we
+	closed, by proposing a replacement closing transaction to the peer.
+	This requires the rbf-coop-close protocol option to be set on both
+	peers, and the peer to be connected.
+
+	If no fee rate is given, the fee rate of the current closing
+	transaction is increased by the configured coopcloserbf.feerate-increase
+	percentage.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of " +
+				"the funding transaction",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel whose close to bump. Takes the " +
+				"form of: txid:output_index",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) the fee rate in sat/vbyte of the " +
+				"replacement closing transaction",
+		},
+	},
+	Action: actionDecorator(bumpCloseFee),
+}
+
+func bumpCloseFee(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "bumpclosefee")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.BumpCoopCloseFeeRequest{
+		ChannelPoint: channelPoint,
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	}
+
+	resp, err := client.BumpCoopCloseFee(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -41,6 +41,34 @@ func getDebugInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var getEffectiveConfigCommand = cli.Command{
+	Name:     "geteffectiveconfig",
+	Category: "Debug",
+	Usage: "Returns the configuration the active daemon is running " +
+		"with.",
+	Description: `
+	Returns the value of every config option of the active daemon, after
+	defaults were applied and the config was validated. Secrets are
+	redacted. Deprecated options that are set are listed separately.`,
+	Action: actionDecorator(getEffectiveConfig),
+}
+
+func getEffectiveConfig(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetEffectiveConfigRequest{}
+	resp, err := client.GetEffectiveConfig(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 type DebugPackage struct {
 	EphemeralPubKey  string `json:"ephemeral_public_key"`
 	EncryptedPayload string `json:"encrypted_payload"`
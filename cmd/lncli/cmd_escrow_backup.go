@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var fetchEscrowBackupCommand = cli.Command{
+	Name:     "fetchescrowbackup",
+	Category: "Channels",
+	Usage: "Fetch the channel backup escrowed with a remote endpoint, " +
+		"and optionally restore it.",
+	ArgsUsage: "--url=<url> [--multi_file=<path>] [--restore]",
+	Description: `
+	Fetches the escrow bundle that lnd pushes to the endpoint configured
+	with backupescrow.url every time its channel backup changes.
+
+	If --multi_file is set, the multi-channel backup of the bundle is
+	written to the given path, using the same format that lnd does in its
+	channel.backup file.
+
+	If --restore is set, the bundle is first verified to belong to the
+	node lncli is connected to, which must have been restored with the same
+	seed as the node that created the bundle. The channels of the backup
+	are then restored, just like with the restorechanbackup command.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "url",
+			Usage: "the http(s) URL the escrow bundle is fetched from",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "the time the request to the endpoint may take",
+			Value: 30 * time.Second,
+		},
+		cli.StringFlag{
+			Name: "multi_file",
+			Usage: "the path the multi-channel backup of the bundle " +
+				"is written to",
+			TakesFile: true,
+		},
+		cli.BoolFlag{
+			Name: "restore",
+			Usage: "verify the bundle against the connected node and " +
+				"restore its channels",
+		},
+	},
+	Action: actionDecorator(fetchEscrowBackup),
+}
+
+func fetchEscrowBackup(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Show command help if no arguments provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "fetchescrowbackup")
+		return nil
+	}
+
+	if !ctx.IsSet("url") {
+		return errors.New("url must be set")
+	}
+
+	endpoint := &chanbackup.EscrowEndpoint{
+		URL:     ctx.String("url"),
+		Timeout: ctx.Duration("timeout"),
+	}
+	bundle, err := endpoint.Fetch(ctxc)
+	if err != nil {
+		return fmt.Errorf("unable to fetch escrow bundle: %w", err)
+	}
+
+	if ctx.IsSet("multi_file") {
+		err := os.WriteFile(ctx.String("multi_file"), bundle.Multi, 0600)
+		if err != nil {
+			return fmt.Errorf("unable to write multi backup: %w",
+				err)
+		}
+	}
+
+	if ctx.Bool("restore") {
+		client, cleanUp := getClient(ctx)
+		defer cleanUp()
+
+		info, err := client.GetInfo(ctxc, &lnrpc.GetInfoRequest{})
+		if err != nil {
+			return err
+		}
+
+		pubKeyBytes, err := hex.DecodeString(info.IdentityPubkey)
+		if err != nil {
+			return err
+		}
+		nodeID, err := btcec.ParsePubKey(pubKeyBytes)
+		if err != nil {
+			return err
+		}
+
+		if !bundle.MatchesNode(nodeID) {
+			return fmt.Errorf("escrow bundle wasn't created by node "+
+				"%v, make sure it was restored with the right "+
+				"seed", info.IdentityPubkey)
+		}
+
+		_, err = client.RestoreChannelBackups(
+			ctxc, &lnrpc.RestoreChanBackupRequest{
+				Backup: &lnrpc.RestoreChanBackupRequest_MultiChanBackup{
+					MultiChanBackup: bundle.Multi,
+				},
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to restore chan backups: %w",
+				err)
+		}
+	}
+
+	printJSON(struct {
+		NodeIDHash string `json:"node_id_hash"`
+		MultiSize  int    `json:"multi_size"`
+		Restored   bool   `json:"restored"`
+	}{
+		NodeIDHash: hex.EncodeToString(bundle.NodeIDHash[:]),
+		MultiSize:  len(bundle.Multi),
+		Restored:   ctx.Bool("restore"),
+	})
+
+	return nil
+}
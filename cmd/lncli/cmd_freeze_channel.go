@@ -0,0 +1,121 @@
+package main
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+// freezeChanPointFlags are the flags that select the channel to freeze or
+// unfreeze.
+var freezeChanPointFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "funding_txid",
+		Usage: "the txid of the channel's funding transaction",
+	},
+	cli.IntFlag{
+		Name: "output_index",
+		Usage: "the output index for the funding output of the " +
+			"funding transaction",
+	},
+	cli.StringFlag{
+		Name: "chan_point",
+		Usage: "the channel to update. Takes the form of: " +
+			"txid:output_index",
+	},
+}
+
+var freezeChannelCommand = cli.Command{
+	Name:     "freezechannel",
+	Category: "Channels",
+	Usage:    "Put a channel into maintenance mode.",
+	Description: `
+	Put a channel into maintenance mode, which is useful before a planned
+	downtime of the peer. The channel stops forwarding HTLCs in both
+	directions and rejects new payments, and is announced as disabled to
+	the network, but stays open. HTLCs in flight are resolved as usual.
+
+	If a duration is given, the channel is unfrozen automatically once it
+	elapsed. Otherwise it stays frozen until the unfreezechannel command is
+	used or lnd is restarted.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: append(freezeChanPointFlags, cli.DurationFlag{
+		Name: "duration",
+		Usage: "the time after which the channel is unfrozen " +
+			"automatically, e.g. 2h",
+	}),
+	Action: actionDecorator(freezeChannel),
+}
+
+func freezeChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "freezechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &routerrpc.FreezeChannelRequest{
+		ChanPoint: channelPoint,
+		DurationSeconds: uint64(
+			ctx.Duration("duration") / time.Second,
+		),
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.FreezeChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var unfreezeChannelCommand = cli.Command{
+	Name:      "unfreezechannel",
+	Category:  "Channels",
+	Usage:     "Take a channel out of maintenance mode.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags:     freezeChanPointFlags,
+	Action:    actionDecorator(unfreezeChannel),
+}
+
+func unfreezeChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "unfreezechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &routerrpc.UnfreezeChannelRequest{
+		ChanPoint: channelPoint,
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.UnfreezeChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
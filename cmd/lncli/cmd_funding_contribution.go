@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var pendingChanIDFlag = cli.StringFlag{
+	Name: "pending_chan_id",
+	Usage: "the hex encoded pending channel ID of the channel that is " +
+		"funded by the contributors",
+}
+
+var contributorIDFlag = cli.StringFlag{
+	Name:  "contributor_id",
+	Usage: "the ID of the contributor",
+}
+
+// parsePendingChanID parses the hex encoded pending channel ID flag.
+func parsePendingChanID(ctx *cli.Context) ([]byte, error) {
+	if !ctx.IsSet(pendingChanIDFlag.Name) {
+		return nil, fmt.Errorf("%s argument missing",
+			pendingChanIDFlag.Name)
+	}
+
+	pendingChanID, err := hex.DecodeString(
+		ctx.String(pendingChanIDFlag.Name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode pending channel ID: "+
+			"%w", err)
+	}
+
+	return pendingChanID, nil
+}
+
+// parseBase64Psbt parses the base64 encoded PSBT of the given flag.
+func parseBase64Psbt(ctx *cli.Context, name string) ([]byte, error) {
+	if !ctx.IsSet(name) {
+		return nil, fmt.Errorf("%s argument missing", name)
+	}
+
+	packet, err := base64.StdEncoding.DecodeString(ctx.String(name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", name, err)
+	}
+
+	return packet, nil
+}
+
+var registerFundingContributorsCommand = cli.Command{
+	Name:     "registerfundingcontributors",
+	Category: "Channels",
+	Usage: "Register the contributors whose inputs fund a PSBT channel " +
+		"funding.",
+	Description: `
+	Start assembling the funding transaction of a channel that is opened
+	with a PSBT funding shim from the inputs of several contributors. The
+	base PSBT must contain the channel funding output as returned by
+	openchannel --psbt. If not all contributors signed their inputs
+	within the timeout, the channel funding is canceled.`,
+	Flags: []cli.Flag{
+		pendingChanIDFlag,
+		cli.StringFlag{
+			Name: "base_psbt",
+			Usage: "the base64 encoded PSBT that contains the " +
+				"channel funding output",
+		},
+		cli.StringSliceFlag{
+			Name: "contributor_id",
+			Usage: "the ID of a contributor, can be specified " +
+				"multiple times",
+		},
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "the time within which all contributors need " +
+				"to sign their inputs",
+			Value: 10 * time.Minute,
+		},
+	},
+	Action: actionDecorator(registerFundingContributors),
+}
+
+func registerFundingContributors(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	pendingChanID, err := parsePendingChanID(ctx)
+	if err != nil {
+		return err
+	}
+
+	basePsbt, err := parseBase64Psbt(ctx, "base_psbt")
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.RegisterFundingContributorsRequest{
+		PendingChanId:  pendingChanID,
+		BasePsbt:       basePsbt,
+		ContributorIds: ctx.StringSlice("contributor_id"),
+		TimeoutSecs:    uint32(ctx.Duration("timeout").Seconds()),
+	}
+
+	resp, err := client.RegisterFundingContributors(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var contributeFundingCommand = cli.Command{
+	Name:     "contributefunding",
+	Category: "Channels",
+	Usage:    "Add the inputs of a contributor to a channel funding.",
+	Description: `
+	Add the inputs and optional change outputs of the given PSBT to the
+	funding transaction on behalf of the given contributor. Once all
+	contributors added their inputs, the funded PSBT can be fetched with
+	fundingcontributionstatus for signing.`,
+	Flags: []cli.Flag{
+		pendingChanIDFlag,
+		contributorIDFlag,
+		cli.StringFlag{
+			Name: "psbt",
+			Usage: "the base64 encoded PSBT with the inputs and " +
+				"change outputs of the contributor",
+		},
+	},
+	Action: actionDecorator(contributeFunding),
+}
+
+func contributeFunding(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	pendingChanID, err := parsePendingChanID(ctx)
+	if err != nil {
+		return err
+	}
+
+	packet, err := parseBase64Psbt(ctx, "psbt")
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.ContributeFundingRequest{
+		PendingChanId: pendingChanID,
+		ContributorId: ctx.String(contributorIDFlag.Name),
+		Psbt:          packet,
+	}
+
+	resp, err := client.ContributeFunding(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var signFundingContributionCommand = cli.Command{
+	Name:     "signfundingcontribution",
+	Category: "Channels",
+	Usage:    "Add the signed inputs of a contributor to a funding.",
+	Description: `
+	Add the signed inputs of the given contributor from the funded PSBT to
+	the funding transaction. Once all contributors signed their inputs,
+	the channel funding is finalized.`,
+	Flags: []cli.Flag{
+		pendingChanIDFlag,
+		contributorIDFlag,
+		cli.StringFlag{
+			Name: "signed_psbt",
+			Usage: "the base64 encoded funded PSBT with all " +
+				"inputs of the contributor finalized",
+		},
+	},
+	Action: actionDecorator(signFundingContribution),
+}
+
+func signFundingContribution(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	pendingChanID, err := parsePendingChanID(ctx)
+	if err != nil {
+		return err
+	}
+
+	signedPsbt, err := parseBase64Psbt(ctx, "signed_psbt")
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.SignFundingContributionRequest{
+		PendingChanId: pendingChanID,
+		ContributorId: ctx.String(contributorIDFlag.Name),
+		SignedPsbt:    signedPsbt,
+	}
+
+	resp, err := client.SignFundingContribution(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var fundingContributionStatusCommand = cli.Command{
+	Name:     "fundingcontributionstatus",
+	Category: "Channels",
+	Usage:    "Show the state of the contributors to a channel funding.",
+	Description: `
+	Print the state of every contributor to the funding transaction of the
+	channel, and the base64 encoded funded PSBT once all of them added
+	their inputs.`,
+	Flags: []cli.Flag{
+		pendingChanIDFlag,
+	},
+	Action: actionDecorator(fundingContributionStatus),
+}
+
+func fundingContributionStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	pendingChanID, err := parsePendingChanID(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.FundingContributionStatusRequest{
+		PendingChanId: pendingChanID,
+	}
+
+	resp, err := client.FundingContributionStatus(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var holdFeeStatusCommand = cli.Command{
+	Name:     "holdfeestatus",
+	Category: "Channels",
+	Usage:    "Display the statistics of the hold fee policy.",
+	Description: `
+	Display the number of forwards checked against the hold fee policy, how
+	many of them had to pay a surcharge or were refused, and the most recent
+	decisions of the policy. In dry-run mode, they show the effect the
+	policy would have if it was enforced.`,
+	Action: actionDecorator(holdFeeStatus),
+}
+
+func holdFeeStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.HoldFeeStatus(ctxc, &lnrpc.HoldFeeStatusRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var simulateHoldFeeCommand = cli.Command{
+	Name:     "simulateholdfee",
+	Category: "Channels",
+	Usage:    "Evaluate a forward against the hold fee policy.",
+	Description: `
+	Show the surcharge the configured hold fee policy requires for a
+	forward of the given amount and CLTV distance, and whether it would be
+	accepted. This works while the policy isn't active, so its parameters
+	can be tuned before enabling it.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  "amt_msat",
+			Usage: "the amount forwarded on the outgoing channel",
+		},
+		cli.Uint64Flag{
+			Name: "fee_msat",
+			Usage: "the fee paid in excess of the regular " +
+				"forwarding fee",
+		},
+		cli.UintFlag{
+			Name: "cltv_distance",
+			Usage: "the number of blocks until the outgoing htlc " +
+				"expires",
+		},
+		cli.UintFlag{
+			Name: "pending_htlcs",
+			Usage: "the number of htlcs already in flight on the " +
+				"outgoing channel",
+		},
+		cli.UintFlag{
+			Name: "max_htlcs",
+			Usage: "the maximum number of htlcs that may be in " +
+				"flight on the outgoing channel",
+			Value: uint(input.MaxHTLCNumber / 2),
+		},
+	},
+	Action: actionDecorator(simulateHoldFee),
+}
+
+func simulateHoldFee(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.SimulateHoldFeeRequest{
+		Htlcs: []*lnrpc.HoldFeeHtlc{{
+			AmtMsat:      ctx.Uint64("amt_msat"),
+			FeeMsat:      ctx.Uint64("fee_msat"),
+			CltvDistance: uint32(ctx.Uint("cltv_distance")),
+			PendingHtlcs: uint32(ctx.Uint("pending_htlcs")),
+			MaxHtlcs:     uint32(ctx.Uint("max_htlcs")),
+		}},
+	}
+	resp, err := client.SimulateHoldFee(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var listJammingIncidentsCommand = cli.Command{
+	Name:     "listjammingincidents",
+	Category: "Channels",
+	Usage:    "List the most recent suspected channel jamming incidents.",
+	Description: `
+	List the most recent slot and liquidity jamming incidents detected in
+	the forwarded htlcs, newest first. Incidents that are mitigated show
+	until when forwards from their incoming channel are refused.`,
+	Action: actionDecorator(listJammingIncidents),
+}
+
+func listJammingIncidents(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListJammingIncidentsRequest{}
+	resp, err := client.ListJammingIncidents(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
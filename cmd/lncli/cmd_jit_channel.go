@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var registerJITChannelCommand = cli.Command{
+	Name:     "registerjitchannel",
+	Category: "Channels",
+	Usage:    "Register a just-in-time channel for a client.",
+	Description: `
+	Assign a new short channel id to a client, which the client puts into
+	the route hints of its invoices. Once an htlc is forwarded to the short
+	channel id, the htlc is held, a zero-conf channel is opened to the
+	client and the htlc is released into it.
+
+	Requires jitchannel.active to be set.`,
+	ArgsUsage: "client_pubkey",
+	Action:    actionDecorator(registerJITChannel),
+}
+
+func registerJITChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "registerjitchannel")
+	}
+
+	client, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode client pubkey: %w", err)
+	}
+
+	req := &routerrpc.RegisterJITChannelRequest{
+		Client: client,
+	}
+
+	routerClient := routerrpc.NewRouterClient(conn)
+	resp, err := routerClient.RegisterJITChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var cancelJITChannelCommand = cli.Command{
+	Name:     "canceljitchannel",
+	Category: "Channels",
+	Usage:    "Cancel a just-in-time channel registration.",
+	Description: `
+	Remove the registration of a just-in-time channel that still waits for
+	its first forward.`,
+	ArgsUsage: "scid",
+	Action:    actionDecorator(cancelJITChannel),
+}
+
+func cancelJITChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "canceljitchannel")
+	}
+
+	scid, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to decode scid: %w", err)
+	}
+
+	req := &routerrpc.CancelJITChannelRequest{
+		Scid: scid,
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.CancelJITChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listJITChannelsCommand = cli.Command{
+	Name:     "listjitchannels",
+	Category: "Channels",
+	Usage:    "List the registered just-in-time channels.",
+	Action:   actionDecorator(listJITChannels),
+}
+
+func listJITChannels(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	req := &routerrpc.ListJITChannelsRequest{}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.ListJITChannels(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
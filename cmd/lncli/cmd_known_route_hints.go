@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var addRouteHintsCommand = cli.Command{
+	Name:     "addroutehints",
+	Category: "Payments",
+	Usage:    "Register route hints for a private destination.",
+	Description: `
+	Register route hints for a private destination we know about out of
+	band, for example a client of our LSP or a partner's private channel.
+	Path finding uses the hints for our outgoing payments until the time to
+	live elapses. Hints registered for the destination before are replaced.
+
+	The route hints are given as a JSON array in the format decodepayreq
+	shows them, e.g.:
+	'[{"hop_hints":[{"node_id":"03...","chan_id":"123",
+	"fee_base_msat":1000,"fee_proportional_millionths":1,
+	"cltv_expiry_delta":40}]}]'
+
+	With the "all" scope, any payment may route through the hinted
+	channels. This should only be used for hints from a trusted source.`,
+	ArgsUsage: "dest route_hints",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest",
+			Usage: "the hex-encoded public key of the private " +
+				"destination",
+		},
+		cli.StringFlag{
+			Name:  "route_hints",
+			Usage: "the route hints to the destination as JSON",
+		},
+		cli.DurationFlag{
+			Name:  "ttl",
+			Usage: "the time the hints are used for",
+			Value: 24 * time.Hour,
+		},
+		cli.StringFlag{
+			Name: "scope",
+			Usage: `the payments that may use the hints, either ` +
+				`"destination" or "all"`,
+			Value: "destination",
+		},
+	},
+	Action: actionDecorator(addRouteHints),
+}
+
+func addRouteHints(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	args := ctx.Args()
+
+	var dest string
+	switch {
+	case ctx.IsSet("dest"):
+		dest = ctx.String("dest")
+
+	case args.Present():
+		dest = args.First()
+		args = args.Tail()
+
+	default:
+		return fmt.Errorf("dest argument missing")
+	}
+
+	destBytes, err := hex.DecodeString(dest)
+	if err != nil {
+		return fmt.Errorf("unable to decode dest: %w", err)
+	}
+
+	var hintsJSON string
+	switch {
+	case ctx.IsSet("route_hints"):
+		hintsJSON = ctx.String("route_hints")
+
+	case args.Present():
+		hintsJSON = args.First()
+
+	default:
+		return fmt.Errorf("route_hints argument missing")
+	}
+
+	// The hints are parsed as part of a known route hint message, so they
+	// use the same JSON format as everywhere else.
+	hints := &routerrpc.KnownRouteHint{}
+	err = lnrpc.ProtoJSONUnmarshalOpts.Unmarshal(
+		[]byte(`{"route_hints":`+hintsJSON+`}`), hints,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to parse route hints: %w", err)
+	}
+
+	var scope routerrpc.RouteHintScope
+	switch ctx.String("scope") {
+	case "destination":
+		scope = routerrpc.RouteHintScope_ROUTE_HINT_SCOPE_DESTINATION
+
+	case "all":
+		scope = routerrpc.RouteHintScope_ROUTE_HINT_SCOPE_ALL
+
+	default:
+		return fmt.Errorf(`scope must be either "destination" or ` +
+			`"all"`)
+	}
+
+	req := &routerrpc.AddKnownRouteHintsRequest{
+		Dest:       destBytes,
+		RouteHints: hints.RouteHints,
+		TtlSeconds: uint64(ctx.Duration("ttl") / time.Second),
+		Scope:      scope,
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.AddKnownRouteHints(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var removeRouteHintsCommand = cli.Command{
+	Name:      "removeroutehints",
+	Category:  "Payments",
+	Usage:     "Remove the route hints registered for a destination.",
+	ArgsUsage: "dest",
+	Action:    actionDecorator(removeRouteHints),
+}
+
+func removeRouteHints(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "removeroutehints")
+	}
+
+	dest, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode dest: %w", err)
+	}
+
+	req := &routerrpc.RemoveKnownRouteHintsRequest{
+		Dest: dest,
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.RemoveKnownRouteHints(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listRouteHintsCommand = cli.Command{
+	Name:     "listroutehints",
+	Category: "Payments",
+	Usage:    "List the registered route hints that haven't expired.",
+	Action:   actionDecorator(listRouteHints),
+}
+
+func listRouteHints(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	req := &routerrpc.ListKnownRouteHintsRequest{}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.ListKnownRouteHints(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
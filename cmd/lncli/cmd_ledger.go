@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var exportLedgerCommand = cli.Command{
+	Name:     "exportledger",
+	Category: "Payments",
+	Usage:    "Export the on-chain and off-chain activity as one ledger.",
+	Description: `
+	Export the transactions of the wallet, settled invoices, succeeded
+	payments and the fees earned by forwards over a time range as one
+	ledger in CSV or JSON. Every entry is a change of the balance: its
+	amount is the change before fees, positive for incoming and negative
+	for outgoing funds, and its fee is the fee paid.
+
+	The start and end times are unix timestamps or negative time ranges,
+	e.g. "-3d". If --start_time isn't provided, then the activity of the
+	last 24 hours is exported. If --end_time isn't provided, then the
+	current time is used.
+
+	Large ranges can be exported in pages by passing the next offset of
+	the previous page as --offset, together with the same --end_time.
+
+	Without --output_file, the ledger is printed to stdout.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "start_time",
+			Usage: "the start of the range as unix timestamp or " +
+				`relative e.g. "-1w"`,
+		},
+		cli.StringFlag{
+			Name: "end_time",
+			Usage: "the end of the range as unix timestamp or " +
+				`relative e.g. "-1w"`,
+		},
+		cli.Uint64Flag{
+			Name:  "offset",
+			Usage: "the number of entries of the range to skip",
+		},
+		cli.UintFlag{
+			Name:  "max_entries",
+			Usage: "the maximum number of entries to export",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "the format of the ledger, either csv or json",
+			Value: "csv",
+		},
+		cli.StringFlag{
+			Name: "output_file",
+			Usage: "the file to write the ledger to, the page " +
+				"information is printed instead",
+		},
+	},
+	Action: actionDecorator(exportLedger),
+}
+
+func exportLedger(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	now := time.Now()
+	startTime := uint64(now.Add(-time.Hour * 24).Unix())
+	if ctx.IsSet("start_time") {
+		var err error
+		startTime, err = parseTime(ctx.String("start_time"), now)
+		if err != nil {
+			return fmt.Errorf("unable to decode start_time: %w",
+				err)
+		}
+	}
+
+	var endTime uint64
+	if ctx.IsSet("end_time") {
+		var err error
+		endTime, err = parseTime(ctx.String("end_time"), now)
+		if err != nil {
+			return fmt.Errorf("unable to decode end_time: %w", err)
+		}
+	}
+
+	var format lnrpc.LedgerFormat
+	switch ctx.String("format") {
+	case "csv":
+		format = lnrpc.LedgerFormat_LEDGER_FORMAT_CSV
+
+	case "json":
+		format = lnrpc.LedgerFormat_LEDGER_FORMAT_JSON
+
+	default:
+		return fmt.Errorf("unknown format %q", ctx.String("format"))
+	}
+
+	req := &lnrpc.ExportLedgerRequest{
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Offset:     ctx.Uint64("offset"),
+		MaxEntries: uint32(ctx.Uint("max_entries")),
+		Format:     format,
+	}
+	resp, err := client.ExportLedger(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.IsSet("output_file") {
+		fmt.Print(string(resp.Ledger))
+		return nil
+	}
+
+	err = os.WriteFile(ctx.String("output_file"), resp.Ledger, 0644)
+	if err != nil {
+		return err
+	}
+
+	resp.Ledger = nil
+	printRespJSON(resp)
+
+	return nil
+}
@@ -38,6 +38,12 @@ var (
 		Usage: "the condition of the custom caveat to add, can be " +
 			"empty if custom caveat doesn't need a value",
 	}
+	macAccountFlag = cli.StringFlag{
+		Name: "account",
+		Usage: "the wallet account the macaroon will be bound to; " +
+			"it can then only use this account and only sees " +
+			"the invoices and payments created with it",
+	}
 )
 
 var bakeMacaroonCommand = cli.Command{
@@ -47,7 +53,8 @@ var bakeMacaroonCommand = cli.Command{
 		"and restrictions.",
 	ArgsUsage: "[--save_to=] [--timeout=] [--ip_address=] " +
 		"[--custom_caveat_name= [--custom_caveat_condition=]] " +
-		"[--root_key_id=] [--allow_external_permissions] " +
+		"[--account=] [--root_key_id=] " +
+		"[--allow_external_permissions] " +
 		"permissions...",
 	Description: `
 	Bake a new macaroon that grants the provided permissions and
@@ -85,6 +92,7 @@ var bakeMacaroonCommand = cli.Command{
 		macIPAddressFlag,
 		macCustomCaveatNameFlag,
 		macCustomCaveatConditionFlag,
+		macAccountFlag,
 		cli.Uint64Flag{
 			Name: "root_key_id",
 			Usage: "the numerical root key ID used to create the " +
@@ -416,7 +424,8 @@ var constrainMacaroonCommand = cli.Command{
 	Category: "Macaroons",
 	Usage:    "Adds one or more restriction(s) to an existing macaroon",
 	ArgsUsage: "[--timeout=] [--ip_address=] [--custom_caveat_name= " +
-		"[--custom_caveat_condition=]] input-macaroon-file " +
+		"[--custom_caveat_condition=]] [--account=] " +
+		"input-macaroon-file " +
 		"constrained-macaroon-file",
 	Description: `
 	Add one or more first-party caveat(s) (a.k.a. constraints/restrictions)
@@ -427,6 +436,7 @@ var constrainMacaroonCommand = cli.Command{
 		macIPAddressFlag,
 		macCustomCaveatNameFlag,
 		macCustomCaveatConditionFlag,
+		macAccountFlag,
 	},
 	Action: actionDecorator(constrainMacaroon),
 }
@@ -548,6 +558,17 @@ func applyMacaroonConstraints(ctx *cli.Context,
 		)
 	}
 
+	if ctx.IsSet(macAccountFlag.Name) {
+		account := ctx.String(macAccountFlag.Name)
+		if account == "" {
+			return nil, fmt.Errorf("invalid account")
+		}
+
+		macConstraints = append(
+			macConstraints, macaroons.AccountConstraint(account),
+		)
+	}
+
 	constrainedMac, err := macaroons.AddConstraints(mac, macConstraints...)
 	if err != nil {
 		return nil, fmt.Errorf("error adding constraints: %w", err)
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var queryNetworkResultsCommand = cli.Command{
+	Name:     "querynetworkresults",
+	Category: "Payments",
+	Usage:    "List the stored results of payment attempts.",
+	Description: `
+	List a page of the results of payment attempts that are kept in the
+	network result store, ordered by attempt id.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "first_attempt_id",
+			Usage: "the attempt id to start listing the results " +
+				"from",
+		},
+		cli.UintFlag{
+			Name: "max_results",
+			Usage: "the maximum number of results to return, 0 " +
+				"returns all results",
+		},
+	},
+	Action: actionDecorator(queryNetworkResults),
+}
+
+func queryNetworkResults(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	req := &routerrpc.QueryNetworkResultsRequest{
+		FirstAttemptId: ctx.Uint64("first_attempt_id"),
+		MaxResults:     uint32(ctx.Uint("max_results")),
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.QueryNetworkResults(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var pruneNetworkResultsCommand = cli.Command{
+	Name:     "prunenetworkresults",
+	Category: "Payments",
+	Usage:    "Prune the stored results of payment attempts.",
+	Description: `
+	Remove the results of resolved payment attempts whose retention passed
+	and of attempts whose payment was deleted, without waiting for the
+	next periodic prune.`,
+	Action: actionDecorator(pruneNetworkResults),
+}
+
+func pruneNetworkResults(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	req := &routerrpc.PruneNetworkResultsRequest{}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.PruneNetworkResults(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -98,7 +98,11 @@ var openChannelCommand = cli.Command{
 	One can also specify a short string memo to record some useful
 	information about the channel using the --memo argument. This is stored
 	locally only, and is purely for reference. It has no bearing on the
-	channel's operation. Max allowed length is 500 characters.`,
+	channel's operation. Max allowed length is 500 characters.
+
+	With the --check flag, the request is only checked and nothing is
+	opened. The result of each check is printed, together with the
+	estimated funding fee and the wallet balance the channel needs.`,
 	ArgsUsage: "node-key local-amt push-amt",
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -159,6 +163,11 @@ var openChannelCommand = cli.Command{
 			Name:  "block",
 			Usage: "block and wait until the channel is fully open",
 		},
+		cli.BoolFlag{
+			Name: "check",
+			Usage: "only check if the channel can be opened, " +
+				"without opening it",
+		},
 		cli.Int64Flag{
 			Name: "conf_target",
 			Usage: "(optional) the number of blocks that the " +
@@ -450,6 +459,17 @@ func openChannel(ctx *cli.Context) error {
 		return fmt.Errorf("unsupported channel type %v", channelType)
 	}
 
+	if ctx.Bool("check") {
+		resp, err := client.CheckOpenChannel(ctxc, req)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(resp)
+
+		return nil
+	}
+
 	// PSBT funding is a more involved, interactive process that is too
 	// large to also fit into this already long function.
 	if ctx.Bool("psbt") {
@@ -46,6 +46,31 @@ var (
 			"to route through for this payment",
 	}
 
+	trampolineNodeFlag = cli.StringFlag{
+		Name: "trampoline_node",
+		Usage: "pubkey of a trampoline node that finds the route " +
+			"to the destination; if set, the payment is sent " +
+			"through it",
+	}
+
+	trampolineBaseFeeFlag = cli.Uint64Flag{
+		Name: "trampoline_base_fee_msat",
+		Usage: "the base fee in msat that the trampoline node " +
+			"charges",
+	}
+
+	trampolineFeeRateFlag = cli.Uint64Flag{
+		Name: "trampoline_fee_rate_ppm",
+		Usage: "the fee rate in ppm that the trampoline node " +
+			"charges",
+	}
+
+	trampolineCltvDeltaFlag = cli.UintFlag{
+		Name: "trampoline_cltv_delta",
+		Usage: "the CLTV delta that the trampoline node " +
+			"requires",
+	}
+
 	dataFlag = cli.StringFlag{
 		Name: "data",
 		Usage: "attach custom data to the payment. The required " +
@@ -193,12 +218,14 @@ func paymentFlags() []cli.Flag {
 			Usage: "will skip payment request confirmation",
 		},
 		cli.BoolFlag{
-			Name:  "allow_self_payment",
-			Usage: "allow sending a circular payment to self",
+			Name: "allow_self_payment",
+			Usage: "send payments to self over a circular " +
+				"route instead of settling them locally",
 		},
 		dataFlag, inflightUpdatesFlag, maxPartsFlag, jsonFlag,
 		maxShardSizeSatFlag, maxShardSizeMsatFlag, ampFlag,
-		timePrefFlag,
+		timePrefFlag, trampolineNodeFlag, trampolineBaseFeeFlag,
+		trampolineFeeRateFlag, trampolineCltvDeltaFlag,
 	}
 }
 
@@ -602,9 +629,39 @@ func sendPaymentRequest(ctx *cli.Context,
 	printJSON := ctx.Bool(jsonFlag.Name)
 	req.NoInflightUpdates = !ctx.Bool(inflightUpdatesFlag.Name) && printJSON
 
-	stream, err := routerClient.SendPaymentV2(ctxc, req)
-	if err != nil {
-		return err
+	var stream routerrpc.Router_SendPaymentV2Client
+	if ctx.IsSet(trampolineNodeFlag.Name) {
+		trampolineNode, err := route.NewVertexFromStr(
+			ctx.String(trampolineNodeFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
+
+		stream, err = routerClient.SendTrampolinePayment(
+			ctxc, &routerrpc.SendTrampolinePaymentRequest{
+				Payment:        req,
+				TrampolineNode: trampolineNode[:],
+				BaseFeeMsat: ctx.Uint64(
+					trampolineBaseFeeFlag.Name,
+				),
+				FeeRatePpm: ctx.Uint64(
+					trampolineFeeRateFlag.Name,
+				),
+				CltvDelta: uint32(ctx.Uint(
+					trampolineCltvDeltaFlag.Name,
+				)),
+			},
+		)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		stream, err = routerClient.SendPaymentV2(ctxc, req)
+		if err != nil {
+			return err
+		}
 	}
 
 	finalState, err := printLivePayment(
@@ -671,6 +728,51 @@ func trackPayment(ctx *cli.Context) error {
 	return err
 }
 
+var cancelPaymentCommand = cli.Command{
+	Name:     "cancelpayment",
+	Category: "Payments",
+	Usage:    "Cancel an in-flight payment.",
+	Description: `
+	Cancel the in-flight payment specified by the hash argument. No new
+	HTLC attempts are made for the payment, and it is failed once the
+	results of the attempts already in flight are known. Use trackpayment
+	to follow the payment until it reaches its final state.
+	`,
+	ArgsUsage: "hash",
+	Action:    actionDecorator(cancelPayment),
+}
+
+func cancelPayment(ctx *cli.Context) error {
+	ctxc := getContext()
+	args := ctx.Args()
+
+	if !args.Present() {
+		return fmt.Errorf("hash argument missing")
+	}
+
+	hash, err := hex.DecodeString(args.First())
+	if err != nil {
+		return err
+	}
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	routerClient := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.CancelPaymentRequest{
+		PaymentHash: hash,
+	}
+	resp, err := routerClient.CancelPayment(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 // printLivePayment receives payment updates from the given stream and either
 // outputs them as json or as a more user-friendly formatted table. The table
 // option uses terminal control codes to rewrite the output. This call
@@ -1126,6 +1228,18 @@ var queryRoutesCommand = cli.Command{
 		blindedBaseFlag,
 		blindedPPMFlag,
 		blindedCLTVFlag,
+		cli.UintFlag{
+			Name: "num_routes",
+			Usage: "(optional) the maximum number of routes to " +
+				"return, each one edge disjoint from the " +
+				"routes before it",
+		},
+		cli.BoolFlag{
+			Name: "node_disjoint",
+			Usage: "if more than one route is returned, return " +
+				"routes that don't share any node rather " +
+				"than any channel",
+		},
 	},
 	Action: actionDecorator(queryRoutes),
 }
@@ -1135,6 +1249,72 @@ func queryRoutes(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
+	req, err := parseQueryRoutesRequest(ctx)
+	if err != nil {
+		return err
+	}
+	req.NumRoutes = uint32(ctx.Uint("num_routes"))
+
+	route, err := client.QueryRoutes(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(route)
+	return nil
+}
+
+var queryRouteFeesCommand = cli.Command{
+	Name:     "queryroutefees",
+	Category: "Payments",
+	Usage:    "Break down the fees of the routes to a destination.",
+	Description: `
+	Queries the channel router for up to num_routes (3 by default) edge
+	disjoint paths to the destination, or node disjoint paths if
+	node_disjoint is set, and breaks down the fees expected along each of
+	them by the nodes that charge them. Next to that, the fees our own
+	policy for the first channel of a route charges for the same amount are
+	returned.`,
+	ArgsUsage: "dest amt",
+	Flags:     queryRoutesCommand.Flags,
+	Action:    actionDecorator(queryRouteFees),
+}
+
+func queryRouteFees(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	query, err := parseQueryRoutesRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	numRoutes := uint32(3)
+	if ctx.IsSet("num_routes") {
+		numRoutes = uint32(ctx.Uint("num_routes"))
+	}
+
+	req := &routerrpc.QueryRouteFeesRequest{
+		Query:     query,
+		NumRoutes: numRoutes,
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.QueryRouteFees(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// parseQueryRoutesRequest builds a query routes request from the arguments
+// and flags of the queryroutes command.
+func parseQueryRoutesRequest(ctx *cli.Context) (*lnrpc.QueryRoutesRequest,
+	error) {
+
 	var (
 		dest string
 		amt  int64
@@ -1156,7 +1336,7 @@ func queryRoutes(ctx *cli.Context) error {
 	case ctx.IsSet(introductionNodeFlag.Name):
 
 	default:
-		return fmt.Errorf("dest argument missing")
+		return nil, fmt.Errorf("dest argument missing")
 	}
 
 	switch {
@@ -1165,16 +1345,16 @@ func queryRoutes(ctx *cli.Context) error {
 	case args.Present():
 		amt, err = strconv.ParseInt(args.First(), 10, 64)
 		if err != nil {
-			return fmt.Errorf("unable to decode amt argument: %w",
-				err)
+			return nil, fmt.Errorf("unable to decode amt "+
+				"argument: %w", err)
 		}
 	default:
-		return fmt.Errorf("amt argument missing")
+		return nil, fmt.Errorf("amt argument missing")
 	}
 
 	feeLimit, err := retrieveFeeLimitLegacy(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pairs := ctx.StringSlice("ignore_pair")
@@ -1182,18 +1362,18 @@ func queryRoutes(ctx *cli.Context) error {
 	for i, pair := range pairs {
 		nodes := strings.Split(pair, ":")
 		if len(nodes) != 2 {
-			return fmt.Errorf("invalid node pair format. " +
+			return nil, fmt.Errorf("invalid node pair format. " +
 				"Expected <node1 pub key>:<node2 pub key>")
 		}
 
 		node1, err := hex.DecodeString(nodes[0])
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		node2, err := hex.DecodeString(nodes[1])
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		ignoredPairs[i] = &lnrpc.NodePair{
@@ -1204,10 +1384,16 @@ func queryRoutes(ctx *cli.Context) error {
 
 	blindedRoutes, err := parseBlindedPaymentParameters(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	routeDiversity := lnrpc.RouteDiversity_ROUTE_DIVERSITY_EDGE_DISJOINT
+	if ctx.Bool("node_disjoint") {
+		routeDiversity =
+			lnrpc.RouteDiversity_ROUTE_DIVERSITY_NODE_DISJOINT
 	}
 
-	req := &lnrpc.QueryRoutesRequest{
+	return &lnrpc.QueryRoutesRequest{
 		PubKey:              dest,
 		Amt:                 amt,
 		FeeLimit:            feeLimit,
@@ -1218,15 +1404,8 @@ func queryRoutes(ctx *cli.Context) error {
 		TimePref:            ctx.Float64(timePrefFlag.Name),
 		IgnoredPairs:        ignoredPairs,
 		BlindedPaymentPaths: blindedRoutes,
-	}
-
-	route, err := client.QueryRoutes(ctxc, req)
-	if err != nil {
-		return err
-	}
-
-	printRespJSON(route)
-	return nil
+		RouteDiversity:      routeDiversity,
+	}, nil
 }
 
 func parseBlindedPaymentParameters(ctx *cli.Context) (
@@ -202,10 +202,10 @@ func newAddress(ctx *cli.Context) error {
 var coinSelectionStrategyFlag = cli.StringFlag{
 	Name: "coin_selection_strategy",
 	Usage: "(optional) the strategy to use for selecting " +
-		"coins. Possible values are 'largest', 'random', or " +
-		"'global-config'. If either 'largest' or 'random' is " +
-		"specified, it will override the globally configured " +
-		"strategy in lnd.conf",
+		"coins. Possible values are 'largest', 'random', " +
+		"'smallest', 'oldest', 'nochange', or 'global-config'. " +
+		"Any value other than 'global-config' overrides the " +
+		"globally configured strategy in lnd.conf",
 	Value: "global-config",
 }
 
@@ -771,6 +771,55 @@ func disconnectPeer(ctx *cli.Context) error {
 	return nil
 }
 
+var diagnoseConnectionCommand = cli.Command{
+	Name:      "diagnoseconnection",
+	Category:  "Peers",
+	Usage:     "Diagnose why a connection to a node fails.",
+	ArgsUsage: "<pubkey>[@host]",
+	Description: `
+	Attempt to connect to a node and report the stage each attempt failed
+	in: resolve, connect, act_one, act_two, act_three or init.
+
+	If no host is given, each address the node advertises in the graph is
+	tried. The connections are closed after the diagnosis. If the node is
+	already connected, only the TCP connection is attempted.
+	`,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "The timeout of each connection attempt. If " +
+				"not set, the global connection timeout is " +
+				"used.",
+		},
+	},
+	Action: actionDecorator(diagnoseConnection),
+}
+
+func diagnoseConnection(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.Args().Present() {
+		return fmt.Errorf("must specify target public key")
+	}
+
+	pubKey, host, _ := strings.Cut(ctx.Args().First(), "@")
+	req := &lnrpc.DiagnoseConnectionRequest{
+		PubKey:      pubKey,
+		Address:     host,
+		TimeoutSecs: uint32(ctx.Duration("timeout").Seconds()),
+	}
+
+	resp, err := client.DiagnoseConnection(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // TODO(roasbeef): also allow short relative channel ID.
 
 var closeChannelCommand = cli.Command{
@@ -1419,6 +1468,54 @@ func walletBalance(ctx *cli.Context) error {
 	return nil
 }
 
+var subscribeWalletBalanceCommand = cli.Command{
+	Name:     "subscribewalletbalance",
+	Category: "Wallet",
+	Usage: "Display the wallet's balance broken down by account " +
+		"and every change to it.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "account",
+			Usage: "(optional) the account for which the balance " +
+				"is shown",
+		},
+		cli.IntFlag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of " +
+				"confirmations of the outputs that count " +
+				"towards the confirmed balance",
+			Value: 1,
+		},
+	},
+	Action: actionDecorator(subscribeWalletBalance),
+}
+
+func subscribeWalletBalance(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.SubscribeWalletBalanceRequest{
+		Account:  ctx.String("account"),
+		MinConfs: int32(ctx.Int("min_confs")),
+	}
+	stream, err := client.SubscribeWalletBalance(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(update)
+	}
+}
+
 var channelBalanceCommand = cli.Command{
 	Name:     "channelbalance",
 	Category: "Channels",
@@ -1442,6 +1539,35 @@ func channelBalance(ctx *cli.Context) error {
 	return nil
 }
 
+var liquidityAlertsCommand = cli.Command{
+	Name:     "liquidityalerts",
+	Category: "Channels",
+	Usage:    "List the liquidity alerts that are currently raised.",
+	Description: `
+	List the alerts the liquidity monitor currently raises for channels,
+	peers or the node as a whole whose liquidity dropped below the
+	configured thresholds, or channels that stayed one-sided for too long.
+
+	Requires liquidityalert.active to be set.`,
+	Action: actionDecorator(liquidityAlerts),
+}
+
+func liquidityAlerts(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.LiquidityAlertsRequest{}
+	resp, err := client.LiquidityAlerts(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var generateManPageCommand = cli.Command{
 	Name: "generatemanpage",
 	Usage: "Generates a man page for lncli and lnd as " +
@@ -1498,6 +1624,40 @@ func getInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var listDeprecationsCommand = cli.Command{
+	Name:     "listdeprecations",
+	Category: "Debug",
+	Usage: "List the deprecated RPC methods and request and response " +
+		"fields.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "method_prefix",
+			Usage: "(optional) only list the methods whose full " +
+				"name starts with this prefix, e.g. " +
+				"/routerrpc.Router/",
+		},
+	},
+	Action: actionDecorator(listDeprecations),
+}
+
+func listDeprecations(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListDeprecationsRequest{
+		MethodPrefix: ctx.String("method_prefix"),
+	}
+	resp, err := client.ListDeprecations(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var getRecoveryInfoCommand = cli.Command{
 	Name:   "getrecoveryinfo",
 	Usage:  "Display information about an ongoing recovery attempt.",
@@ -1519,6 +1679,180 @@ func getRecoveryInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var getChainInfoCommand = cli.Command{
+	Name: "getchaininfo",
+	Usage: "Display how far the chain backend and the node have " +
+		"synced the chain.",
+	Action: actionDecorator(getChainInfo),
+}
+
+func getChainInfo(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetChainInfoRequest{}
+	resp, err := client.GetChainInfo(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var getLeadershipStateCommand = cli.Command{
+	Name: "getleadershipstate",
+	Usage: "Display the leader election state of the node when it runs " +
+		"as part of a cluster.",
+	Action: actionDecorator(getLeadershipState),
+}
+
+func getLeadershipState(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetLeadershipStateRequest{}
+	resp, err := client.GetLeadershipState(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var dbUsageCommand = cli.Command{
+	Name:     "dbusage",
+	Category: "Database",
+	Usage: "Display the disk usage of the bolt database files, broken " +
+		"down by bucket.",
+	Action: actionDecorator(dbUsage),
+}
+
+func dbUsage(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetDBUsageRequest{}
+	resp, err := client.GetDBUsage(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var compactDBCommand = cli.Command{
+	Name:      "compactdb",
+	Category:  "Database",
+	Usage:     "Compact the bolt database files on the next restart.",
+	ArgsUsage: "[name...]",
+	Description: `
+	Schedules the compaction of the given bolt database files, as listed
+	by the dbusage command, on the next restart of the node. All bolt
+	database files are compacted if none are given.`,
+	Action: actionDecorator(compactDB),
+}
+
+func compactDB(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ScheduleDBCompactionRequest{
+		Names: ctx.Args(),
+	}
+	if _, err := client.ScheduleDBCompaction(ctxc, req); err != nil {
+		return err
+	}
+
+	fmt.Println("Compaction scheduled, restart the node to compact")
+	return nil
+}
+
+var cltvGuardStatusCommand = cli.Command{
+	Name: "cltvguardstatus",
+	Usage: "Display the state of the CLTV guard and the chain " +
+		"conditions that engage it.",
+	Description: `
+	Display whether the CLTV guard currently requires an extra CLTV delta
+	for forwards, because blocks arrive in storms or the best block is
+	stale, and how large the extra delta is.
+
+	Requires cltvguard.active to be set.`,
+	Action: actionDecorator(cltvGuardStatus),
+}
+
+func cltvGuardStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.CltvGuardStatusRequest{}
+	resp, err := client.CltvGuardStatus(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var safeModeCommand = cli.Command{
+	Name:  "safemode",
+	Usage: "Display whether the node is in safe mode and why.",
+	Description: `
+	Display whether the node is in safe mode, and the failures of its
+	subsystems that put it into safe mode. In safe mode, the node neither
+	forwards nor initiates new HTLCs, and doesn't open or cooperatively
+	close channels, but it keeps resolving existing state.`,
+	Action: actionDecorator(safeModeStatus),
+}
+
+func safeModeStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.SafeModeStatusRequest{}
+	resp, err := client.SafeModeStatus(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exitSafeModeCommand = cli.Command{
+	Name:  "exitsafemode",
+	Usage: "Take the node out of safe mode.",
+	Description: `
+	Take the node out of safe mode, so that it accepts new HTLCs and
+	channel operations again. Subsystems that still fail put the node back
+	into safe mode once their failure is detected again.`,
+	Action: actionDecorator(exitSafeMode),
+}
+
+func exitSafeMode(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ExitSafeModeRequest{}
+	resp, err := client.ExitSafeMode(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var pendingChannelsCommand = cli.Command{
 	Name:     "pendingchannels",
 	Category: "Channels",
@@ -1717,6 +2051,43 @@ func closedChannels(ctx *cli.Context) error {
 	return nil
 }
 
+var closeHistoryCommand = cli.Command{
+	Name:     "closehistory",
+	Category: "Channels",
+	Usage:    "List who closed each closed channel and why.",
+	Description: `
+	List the close history of all closed channels, ordered by their close
+	height. For each channel, the party that initiated the close and the
+	reason of the close are returned.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "include_pending",
+			Usage: "include channels that are still pending " +
+				"close",
+		},
+	},
+	Action: actionDecorator(closeHistory),
+}
+
+func closeHistory(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ChannelCloseHistoryRequest{
+		IncludePending: ctx.Bool("include_pending"),
+	}
+
+	resp, err := client.ChannelCloseHistory(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var describeGraphCommand = cli.Command{
 	Name:     "describegraph",
 	Category: "Graph",
@@ -1896,6 +2267,102 @@ func getNodeInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var getNodeMetadataCommand = cli.Command{
+	Name:     "getnodemetadata",
+	Category: "Graph",
+	Usage:    "Get the operator information a node advertises.",
+	Description: "Prints out the contact, organization name and URL " +
+		"records of the latest node announcement of a node",
+	ArgsUsage: "pub_key",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "pub_key",
+			Usage: "the 33-byte hex-encoded compressed public of " +
+				"the target node",
+		},
+	},
+	Action: actionDecorator(getNodeMetadata),
+}
+
+func getNodeMetadata(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var pubKey string
+	switch {
+	case ctx.IsSet("pub_key"):
+		pubKey = ctx.String("pub_key")
+	case ctx.Args().Present():
+		pubKey = ctx.Args().First()
+	default:
+		return fmt.Errorf("pub_key argument missing")
+	}
+
+	metadata, err := client.GetNodeMetadata(
+		ctxc, &lnrpc.NodeMetadataRequest{PubKey: pubKey},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(metadata)
+	return nil
+}
+
+var getNodeCapabilitiesCommand = cli.Command{
+	Name:     "getnodecapabilities",
+	Category: "Graph",
+	Usage:    "Get the capabilities a node advertises.",
+	Description: "Prints out whether a node supports onion messages, " +
+		"route blinding, taproot channels and trampoline routing, " +
+		"which matters when selecting introduction nodes for reply " +
+		"paths",
+	ArgsUsage: "pub_key",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "pub_key",
+			Usage: "the 33-byte hex-encoded compressed public of " +
+				"the target node",
+		},
+		cli.BoolFlag{
+			Name: "include_relay_stats",
+			Usage: "if true, the relay statistics measured " +
+				"locally for the node are included",
+		},
+	},
+	Action: actionDecorator(getNodeCapabilities),
+}
+
+func getNodeCapabilities(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var pubKey string
+	switch {
+	case ctx.IsSet("pub_key"):
+		pubKey = ctx.String("pub_key")
+	case ctx.Args().Present():
+		pubKey = ctx.Args().First()
+	default:
+		return fmt.Errorf("pub_key argument missing")
+	}
+
+	req := &lnrpc.NodeCapabilitiesRequest{
+		PubKey:            pubKey,
+		IncludeRelayStats: ctx.Bool("include_relay_stats"),
+	}
+
+	capabilities, err := client.GetNodeCapabilities(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(capabilities)
+	return nil
+}
+
 var getNetworkInfoCommand = cli.Command{
 	Name:     "getnetworkinfo",
 	Category: "Channels",
@@ -1960,6 +2427,34 @@ func debugLevel(ctx *cli.Context) error {
 	return nil
 }
 
+var reloadConfigCommand = cli.Command{
+	Name:  "reloadconfig",
+	Usage: "Reload the config file without a restart.",
+	Description: `
+	Reloads the config file and applies the changed options that can be
+	changed without a restart, such as log levels, fee settings, gossip
+	rate limits and autopilot options. Changes to any other option are
+	rejected and take effect on the next restart.
+
+	This is equivalent to sending SIGHUP to lnd, but reports which of the
+	changed options were applied and which were rejected.`,
+	Action: actionDecorator(reloadConfig),
+}
+
+func reloadConfig(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ReloadConfig(ctxc, &lnrpc.ReloadConfigRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var listChainTxnsCommand = cli.Command{
 	Name:     "listchaintxns",
 	Category: "On-chain",
@@ -2017,6 +2512,45 @@ func listChainTxns(ctx *cli.Context) error {
 	return nil
 }
 
+var addressReuseCommand = cli.Command{
+	Name:     "addressreuse",
+	Category: "On-chain",
+	Usage:    "List the addresses that were paid to more than once.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "account",
+			Usage: "(optional) only consider the transactions of " +
+				"this wallet account",
+		},
+	},
+	Description: `
+	List the output scripts that more than one wallet transaction paid to,
+	in the order they were first paid to. This includes addresses of the
+	wallet that received funds more than once as well as foreign addresses
+	the wallet paid to more than once.
+`,
+	Action: actionDecorator(addressReuse),
+}
+
+func addressReuse(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.AddressReuseReportRequest{
+		Account: ctx.String("account"),
+	}
+
+	resp, err := client.AddressReuseReport(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var stopCommand = cli.Command{
 	Name:  "stop",
 	Usage: "Stop and shutdown the daemon.",
@@ -4,7 +4,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -24,6 +26,7 @@ func devCommands() []cli.Command {
 			ArgsUsage:   "graph-json-file",
 			Action:      actionDecorator(importGraph),
 		},
+		getProfileCommand,
 	}
 }
 
@@ -60,3 +63,80 @@ func importGraph(ctx *cli.Context) error {
 	printRespJSON(res)
 	return nil
 }
+
+var getProfileCommand = cli.Command{
+	Name:     "getprofile",
+	Category: "Development",
+	Usage:    "Capture a profile of the running lnd instance.",
+	Description: `
+	Capture a profile of the given type and write it to the given file.
+	CPU profiles and execution traces are captured for the given duration,
+	all other profiles known to runtime/pprof, like heap or goroutine, are
+	taken as a snapshot right away. The file can be inspected with go tool
+	pprof, or go tool trace for execution traces.
+	`,
+	ArgsUsage: "--type=T --output_file=F [--duration=D]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "type",
+			Usage: "the type of the profile, either cpu, " +
+				"trace or any profile known to " +
+				"runtime/pprof like heap or goroutine",
+		},
+		cli.Uint64Flag{
+			Name: "duration",
+			Usage: "the number of seconds to capture a cpu " +
+				"profile or an execution trace for",
+			Value: 30,
+		},
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the profile to",
+		},
+	},
+	Action: actionDecorator(getProfile),
+}
+
+func getProfile(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getDevClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("type") || !ctx.IsSet("output_file") {
+		return cli.ShowCommandHelp(ctx, "getprofile")
+	}
+
+	stream, err := client.GetProfile(ctxc, &devrpc.GetProfileRequest{
+		ProfileType:     ctx.String("type"),
+		DurationSeconds: uint32(ctx.Uint64("duration")),
+	})
+	if err != nil {
+		return err
+	}
+
+	outFile := lncfg.CleanAndExpandPath(ctx.String("output_file"))
+	f, err := os.Create(outFile)
+	if err != nil {
+		return fmt.Errorf("unable to create output file %v: %w",
+			outFile, err)
+	}
+	defer f.Close()
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := f.Write(chunk.Chunk); err != nil {
+			return fmt.Errorf("unable to write profile: %w", err)
+		}
+	}
+
+	fmt.Printf("Wrote %v profile to %v\n", ctx.String("type"), outFile)
+
+	return nil
+}
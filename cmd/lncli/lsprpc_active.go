@@ -0,0 +1,367 @@
+//go:build lsprpc
+// +build lsprpc
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc/lsprpc"
+	"github.com/urfave/cli"
+)
+
+// lspCommands will return the set of commands to enable for lsprpc builds.
+func lspCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:     "lsp",
+			Category: "LSP",
+			Usage: "Order channels from LSPs and buy " +
+				"just-in-time channels from them",
+			Subcommands: []cli.Command{
+				listProtocolsCommand,
+				lsps1GetInfoCommand,
+				lsps1CreateOrderCommand,
+				lsps1GetOrderCommand,
+				lsps2GetInfoCommand,
+				lsps2BuyCommand,
+				listLSPJITChannelsCommand,
+			},
+		},
+	}
+}
+
+func getLSPClient(ctx *cli.Context) (lsprpc.LSPClient, func()) {
+	conn := getClientConn(ctx, false)
+	cleanUp := func() {
+		conn.Close()
+	}
+	return lsprpc.NewLSPClient(conn), cleanUp
+}
+
+var lspPubkeyFlag = cli.StringFlag{
+	Name:  "lsp",
+	Usage: "the hex encoded public key of the LSP, which must be a peer",
+}
+
+// parseLSPPubkey parses the public key of the LSP from the lsp flag.
+func parseLSPPubkey(ctx *cli.Context) ([]byte, error) {
+	if !ctx.IsSet(lspPubkeyFlag.Name) {
+		return nil, fmt.Errorf("%s argument missing",
+			lspPubkeyFlag.Name)
+	}
+
+	pubKey, err := hex.DecodeString(ctx.String(lspPubkeyFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode lsp pubkey: %w", err)
+	}
+
+	return pubKey, nil
+}
+
+var listProtocolsCommand = cli.Command{
+	Name:   "listprotocols",
+	Usage:  "List the LSP specifications an LSP supports.",
+	Flags:  []cli.Flag{lspPubkeyFlag},
+	Action: actionDecorator(listProtocols),
+}
+
+func listProtocols(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getLSPClient(ctx)
+	defer cleanUp()
+
+	lsp, err := parseLSPPubkey(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListProtocols(ctxc, &lsprpc.ListProtocolsRequest{
+		LspPubkey: lsp,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var lsps1GetInfoCommand = cli.Command{
+	Name:   "lsps1getinfo",
+	Usage:  "Show the channels an LSP sells.",
+	Flags:  []cli.Flag{lspPubkeyFlag},
+	Action: actionDecorator(lsps1GetInfo),
+}
+
+func lsps1GetInfo(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getLSPClient(ctx)
+	defer cleanUp()
+
+	lsp, err := parseLSPPubkey(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.LSPS1GetInfo(ctxc, &lsprpc.LSPS1GetInfoRequest{
+		LspPubkey: lsp,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var lsps1CreateOrderCommand = cli.Command{
+	Name:  "lsps1createorder",
+	Usage: "Order a channel from an LSP.",
+	Description: `
+	Order a channel from an LSP. The returned order holds the invoice or
+	address to pay the LSP with.`,
+	Flags: []cli.Flag{
+		lspPubkeyFlag,
+		cli.Int64Flag{
+			Name:  "lsp_balance_sat",
+			Usage: "the balance on the LSP side of the channel",
+		},
+		cli.Int64Flag{
+			Name: "client_balance_sat",
+			Usage: "the balance on our side of the channel, " +
+				"which is paid for on top of the fees",
+		},
+		cli.Uint64Flag{
+			Name: "required_channel_confirmations",
+			Usage: "the number of confirmations the LSP waits " +
+				"for before the channel can be used",
+		},
+		cli.Uint64Flag{
+			Name: "funding_confirms_within_blocks",
+			Usage: "the number of blocks the funding transaction " +
+				"should confirm in",
+			Value: 6,
+		},
+		cli.Uint64Flag{
+			Name: "channel_expiry_blocks",
+			Usage: "the number of blocks the LSP keeps the " +
+				"channel open for at least",
+		},
+		cli.StringFlag{
+			Name:  "token",
+			Usage: "an optional token the LSP handed out",
+		},
+		cli.StringFlag{
+			Name: "refund_onchain_address",
+			Usage: "the address the LSP refunds on-chain " +
+				"payments to if the order fails",
+		},
+		cli.BoolFlag{
+			Name:  "announce_channel",
+			Usage: "announce the channel to the network",
+		},
+	},
+	Action: actionDecorator(lsps1CreateOrder),
+}
+
+func lsps1CreateOrder(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getLSPClient(ctx)
+	defer cleanUp()
+
+	lsp, err := parseLSPPubkey(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lsprpc.LSPS1CreateOrderRequest{
+		LspPubkey:        lsp,
+		LspBalanceSat:    ctx.Int64("lsp_balance_sat"),
+		ClientBalanceSat: ctx.Int64("client_balance_sat"),
+		RequiredChannelConfirmations: uint32(
+			ctx.Uint64("required_channel_confirmations"),
+		),
+		FundingConfirmsWithinBlocks: uint32(
+			ctx.Uint64("funding_confirms_within_blocks"),
+		),
+		ChannelExpiryBlocks: uint32(
+			ctx.Uint64("channel_expiry_blocks"),
+		),
+		Token:                ctx.String("token"),
+		RefundOnchainAddress: ctx.String("refund_onchain_address"),
+		AnnounceChannel:      ctx.Bool("announce_channel"),
+	}
+
+	resp, err := client.LSPS1CreateOrder(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var lsps1GetOrderCommand = cli.Command{
+	Name:      "lsps1getorder",
+	Usage:     "Show the current state of a channel order.",
+	ArgsUsage: "order_id",
+	Flags:     []cli.Flag{lspPubkeyFlag},
+	Action:    actionDecorator(lsps1GetOrder),
+}
+
+func lsps1GetOrder(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getLSPClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "lsps1getorder")
+	}
+
+	lsp, err := parseLSPPubkey(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.LSPS1GetOrder(ctxc, &lsprpc.LSPS1GetOrderRequest{
+		LspPubkey: lsp,
+		OrderId:   ctx.Args().First(),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var lspTokenFlag = cli.StringFlag{
+	Name:  "token",
+	Usage: "an optional token the LSP handed out",
+}
+
+var lsps2GetInfoCommand = cli.Command{
+	Name:  "lsps2getinfo",
+	Usage: "Show the fees an LSP offers just-in-time channels for.",
+	Flags: []cli.Flag{
+		lspPubkeyFlag,
+		lspTokenFlag,
+	},
+	Action: actionDecorator(lsps2GetInfo),
+}
+
+func lsps2GetInfo(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getLSPClient(ctx)
+	defer cleanUp()
+
+	lsp, err := parseLSPPubkey(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.LSPS2GetInfo(ctxc, &lsprpc.LSPS2GetInfoRequest{
+		LspPubkey: lsp,
+		Token:     ctx.String(lspTokenFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var lsps2BuyCommand = cli.Command{
+	Name:  "lsps2buy",
+	Usage: "Buy a just-in-time channel from an LSP.",
+	Description: `
+	Fetch the opening fee params the LSP currently offers and buy a
+	just-in-time channel with the ones at the given index of the menu. The
+	hop hint of the returned channel needs to be added to the invoice that
+	is paid through it.`,
+	Flags: []cli.Flag{
+		lspPubkeyFlag,
+		lspTokenFlag,
+		cli.UintFlag{
+			Name: "menu_index",
+			Usage: "the index of the opening fee params to buy " +
+				"the channel with, as listed by lsps2getinfo",
+		},
+		cli.Uint64Flag{
+			Name: "payment_size_msat",
+			Usage: "the size of the payment the channel is " +
+				"bought for, if the invoice has a fixed amount",
+		},
+	},
+	Action: actionDecorator(lsps2Buy),
+}
+
+func lsps2Buy(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getLSPClient(ctx)
+	defer cleanUp()
+
+	lsp, err := parseLSPPubkey(ctx)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.LSPS2GetInfo(ctxc, &lsprpc.LSPS2GetInfoRequest{
+		LspPubkey: lsp,
+		Token:     ctx.String(lspTokenFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	menu := info.OpeningFeeParamsMenu
+	idx := ctx.Uint("menu_index")
+	if idx >= uint(len(menu)) {
+		return fmt.Errorf("menu_index %d out of range, the LSP offers "+
+			"%d opening fee params", idx, len(menu))
+	}
+
+	resp, err := client.LSPS2Buy(ctxc, &lsprpc.LSPS2BuyRequest{
+		LspPubkey:        lsp,
+		OpeningFeeParams: menu[idx],
+		PaymentSizeMsat:  ctx.Uint64("payment_size_msat"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listLSPJITChannelsCommand = cli.Command{
+	Name:   "listjitchannels",
+	Usage:  "List the just-in-time channels the LSPs are expected to open.",
+	Action: actionDecorator(listLSPJITChannels),
+}
+
+func listLSPJITChannels(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getLSPClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListJITChannels(
+		ctxc, &lsprpc.ListJITChannelsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
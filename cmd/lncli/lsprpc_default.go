@@ -0,0 +1,11 @@
+//go:build !lsprpc
+// +build !lsprpc
+
+package main
+
+import "github.com/urfave/cli"
+
+// lspCommands will return nil for non-lsprpc builds.
+func lspCommands() []cli.Command {
+	return nil
+}
@@ -451,19 +451,36 @@ func main() {
 		listUnspentCommand,
 		connectCommand,
 		disconnectCommand,
+		diagnoseConnectionCommand,
 		openChannelCommand,
 		batchOpenChannelCommand,
+		registerFundingContributorsCommand,
+		contributeFundingCommand,
+		signFundingContributionCommand,
+		fundingContributionStatusCommand,
 		closeChannelCommand,
+		bumpCloseFeeCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
 		listPeersCommand,
 		walletBalanceCommand,
+		subscribeWalletBalanceCommand,
 		channelBalanceCommand,
+		liquidityAlertsCommand,
 		getInfoCommand,
 		getDebugInfoCommand,
+		getEffectiveConfigCommand,
+		listDeprecationsCommand,
 		encryptDebugPackageCommand,
 		decryptDebugPackageCommand,
 		getRecoveryInfoCommand,
+		getChainInfoCommand,
+		getLeadershipStateCommand,
+		dbUsageCommand,
+		compactDBCommand,
+		cltvGuardStatusCommand,
+		safeModeCommand,
+		exitSafeModeCommand,
 		pendingChannelsCommand,
 		sendPaymentCommand,
 		payInvoiceCommand,
@@ -473,25 +490,35 @@ func main() {
 		listInvoicesCommand,
 		listChannelsCommand,
 		closedChannelsCommand,
+		closeHistoryCommand,
 		listPaymentsCommand,
 		describeGraphCommand,
 		getNodeMetricsCommand,
 		getChanInfoCommand,
 		getNodeInfoCommand,
+		getNodeMetadataCommand,
+		getNodeCapabilitiesCommand,
 		queryRoutesCommand,
 		getNetworkInfoCommand,
 		debugLevelCommand,
+		reloadConfigCommand,
 		decodePayReqCommand,
 		listChainTxnsCommand,
+		addressReuseCommand,
 		stopCommand,
 		signMessageCommand,
 		verifyMessageCommand,
 		feeReportCommand,
 		updateChannelPolicyCommand,
 		forwardingHistoryCommand,
+		exportLedgerCommand,
+		holdFeeStatusCommand,
+		simulateHoldFeeCommand,
+		listJammingIncidentsCommand,
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
 		restoreChanBackupCommand,
+		fetchEscrowBackupCommand,
 		bakeMacaroonCommand,
 		listMacaroonIDsCommand,
 		deleteMacaroonIDCommand,
@@ -499,6 +526,7 @@ func main() {
 		printMacaroonCommand,
 		constrainMacaroonCommand,
 		trackPaymentCommand,
+		cancelPaymentCommand,
 		versionCommand,
 		profileSubCommand,
 		getStateCommand,
@@ -521,6 +549,7 @@ func main() {
 	app.Commands = append(app.Commands, wtclientCommands()...)
 	app.Commands = append(app.Commands, devCommands()...)
 	app.Commands = append(app.Commands, peersCommands()...)
+	app.Commands = append(app.Commands, lspCommands()...)
 	app.Commands = append(app.Commands, chainCommands()...)
 
 	if err := app.Run(os.Args); err != nil {
@@ -587,6 +616,15 @@ func parseCoinSelectionStrategy(ctx *cli.Context) (
 	case "random":
 		return lnrpc.CoinSelectionStrategy_STRATEGY_RANDOM, nil
 
+	case "smallest":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_SMALLEST, nil
+
+	case "oldest":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_OLDEST, nil
+
+	case "nochange":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_NO_CHANGE, nil
+
 	default:
 		return 0, fmt.Errorf("unknown coin selection strategy "+
 			"%v", strategy)
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -22,6 +23,13 @@ func peersCommands() []cli.Command {
 				"network",
 			Subcommands: []cli.Command{
 				updateNodeAnnouncementCommand,
+				updateNodeMetadataCommand,
+				listRejectedPeersCommand,
+				registerInitRecordCommand,
+				unregisterInitRecordCommand,
+				listInitRecordsCommand,
+				peerInitRecordsCommand,
+				listPeerStorageCommand,
 			},
 		},
 	}
@@ -160,3 +168,262 @@ func updateNodeAnnouncement(ctx *cli.Context) error {
 
 	return nil
 }
+
+var updateNodeMetadataCommand = cli.Command{
+	Name:     "updatenodemetadata",
+	Category: "Peers",
+	Usage:    "update the operator information of the node announcement",
+	Description: `
+	Replace the operator information records of the node announcement and
+	broadcast a new node announcement. Fields that aren't set remove their
+	record, so all records that should stay must be passed.`,
+	ArgsUsage: "[--contact=] [--org_name=] [--url=]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "contact",
+			Usage: "the contact of the node operator, e.g. an " +
+				"email address or a Nostr public key",
+		},
+		cli.StringFlag{
+			Name:  "org_name",
+			Usage: "the name of the organization running the node",
+		},
+		cli.StringFlag{
+			Name:  "url",
+			Usage: "the canonical URL of the node or its operator",
+		},
+	},
+	Action: actionDecorator(updateNodeMetadata),
+}
+
+func updateNodeMetadata(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.UpdateNodeMetadata(
+		ctxc, &peersrpc.NodeMetadataUpdateRequest{
+			Contact: ctx.String("contact"),
+			OrgName: ctx.String("org_name"),
+			Url:     ctx.String("url"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listRejectedPeersCommand = cli.Command{
+	Name:     "listrejectedpeers",
+	Category: "Peers",
+	Usage:    "list the peers rejected by the feature policy",
+	Description: `
+	List the peers that were disconnected because the features they
+	signaled in their init message violate the configured feature policy,
+	starting with the most recent rejection.`,
+	Action: actionDecorator(listRejectedPeers),
+}
+
+func listRejectedPeers(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListRejectedPeers(
+		ctxc, &peersrpc.ListRejectedPeersRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var registerInitRecordCommand = cli.Command{
+	Name:     "registerinitrecord",
+	Category: "Peers",
+	Usage:    "add a custom TLV record to the init message",
+	Description: `
+	Add a custom TLV record to the init message sent to peers, replacing the
+	value of an already registered record of the type. The type must be odd
+	and at least 65536. The record is only sent on connections established
+	after it was registered.`,
+	ArgsUsage: "--type= --value=",
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  "type",
+			Usage: "the TLV type of the record",
+		},
+		cli.StringFlag{
+			Name:  "value",
+			Usage: "the hex-encoded value of the record",
+		},
+	},
+	Action: actionDecorator(registerInitRecord),
+}
+
+func registerInitRecord(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("type") {
+		return fmt.Errorf("type must be set")
+	}
+
+	value, err := hex.DecodeString(ctx.String("value"))
+	if err != nil {
+		return fmt.Errorf("unable to decode value: %w", err)
+	}
+
+	resp, err := client.RegisterInitRecord(
+		ctxc, &peersrpc.RegisterInitRecordRequest{
+			Type:  ctx.Uint64("type"),
+			Value: value,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var unregisterInitRecordCommand = cli.Command{
+	Name:      "unregisterinitrecord",
+	Category:  "Peers",
+	Usage:     "remove a custom TLV record from the init message",
+	ArgsUsage: "--type=",
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  "type",
+			Usage: "the TLV type of the record",
+		},
+	},
+	Action: actionDecorator(unregisterInitRecord),
+}
+
+func unregisterInitRecord(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("type") {
+		return fmt.Errorf("type must be set")
+	}
+
+	resp, err := client.UnregisterInitRecord(
+		ctxc, &peersrpc.UnregisterInitRecordRequest{
+			Type: ctx.Uint64("type"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listInitRecordsCommand = cli.Command{
+	Name:     "listinitrecords",
+	Category: "Peers",
+	Usage:    "list the custom TLV records of the init message",
+	Action:   actionDecorator(listInitRecords),
+}
+
+func listInitRecords(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListInitRecords(
+		ctxc, &peersrpc.ListInitRecordsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var peerInitRecordsCommand = cli.Command{
+	Name:      "peerinitrecords",
+	Category:  "Peers",
+	Usage:     "list the TLV records of the init message of a peer",
+	ArgsUsage: "--peer=",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "peer",
+			Usage: "the hex-encoded public key of the peer",
+		},
+	},
+	Action: actionDecorator(peerInitRecords),
+}
+
+func peerInitRecords(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	pubKey, err := hex.DecodeString(ctx.String("peer"))
+	if err != nil {
+		return fmt.Errorf("unable to decode peer: %w", err)
+	}
+
+	resp, err := client.PeerInitRecords(
+		ctxc, &peersrpc.PeerInitRecordsRequest{
+			PubKey: pubKey,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listPeerStorageCommand = cli.Command{
+	Name:     "listpeerstorage",
+	Category: "Peers",
+	Usage:    "list the backup blobs we store on behalf of our peers",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "include_blobs",
+			Usage: "if set, the blobs are shown next to their size",
+		},
+	},
+	Action: actionDecorator(listPeerStorage),
+}
+
+func listPeerStorage(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListPeerStorage(
+		ctxc, &peersrpc.ListPeerStorageRequest{
+			IncludeBlobs: ctx.Bool("include_blobs"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -8,10 +8,21 @@ func routerCommands() []cli.Command {
 		queryMissionControlCommand,
 		importMissionControlCommand,
 		queryProbCommand,
+		queryRouteFeesCommand,
 		resetMissionControlCommand,
 		buildRouteCommand,
 		getCfgCommand,
 		setCfgCommand,
 		updateChanStatusCommand,
+		freezeChannelCommand,
+		unfreezeChannelCommand,
+		addRouteHintsCommand,
+		removeRouteHintsCommand,
+		listRouteHintsCommand,
+		registerJITChannelCommand,
+		cancelJITChannelCommand,
+		listJITChannelsCommand,
+		queryNetworkResultsCommand,
+		pruneNetworkResultsCommand,
 	}
 }
@@ -1297,10 +1297,36 @@ var fundPsbtCommand = cli.Command{
 			Value: defaultUtxoMinConf,
 		},
 		coinSelectionStrategyFlag,
+		psbtVersionFlag,
 	},
 	Action: actionDecorator(fundPsbt),
 }
 
+var psbtVersionFlag = cli.Uint64Flag{
+	Name: "psbt_version",
+	Usage: "(optional) the version to return the PSBT in, either 0 " +
+		"(BIP 174) or 2 (BIP 370). If not set, the PSBT is " +
+		"returned in the same version it was given in",
+}
+
+// parsePsbtVersion parses the PSBT version flag.
+func parsePsbtVersion(ctx *cli.Context) (walletrpc.PsbtVersion, error) {
+	if !ctx.IsSet(psbtVersionFlag.Name) {
+		return walletrpc.PsbtVersion_PSBT_VERSION_UNCHANGED, nil
+	}
+
+	switch version := ctx.Uint64(psbtVersionFlag.Name); version {
+	case 0:
+		return walletrpc.PsbtVersion_PSBT_VERSION_V0, nil
+
+	case 2:
+		return walletrpc.PsbtVersion_PSBT_VERSION_V2, nil
+
+	default:
+		return 0, fmt.Errorf("unknown PSBT version: %v", version)
+	}
+}
+
 func fundPsbt(ctx *cli.Context) error {
 	ctxc := getContext()
 
@@ -1315,12 +1341,18 @@ func fundPsbt(ctx *cli.Context) error {
 		return err
 	}
 
+	psbtVersion, err := parsePsbtVersion(ctx)
+	if err != nil {
+		return err
+	}
+
 	minConfs := int32(ctx.Uint64("min_confs"))
 	req := &walletrpc.FundPsbtRequest{
 		Account:               ctx.String("account"),
 		MinConfs:              minConfs,
 		SpendUnconfirmed:      minConfs == 0,
 		CoinSelectionStrategy: coinSelectionStrategy,
+		PsbtVersion:           psbtVersion,
 	}
 
 	// Parse template flags.
@@ -1496,6 +1528,7 @@ var finalizePsbtCommand = cli.Command{
 			Usage: "(optional) the name of the account to " +
 				"finalize the PSBT with",
 		},
+		psbtVersionFlag,
 	},
 	Action: actionDecorator(finalizePsbt),
 }
@@ -1505,7 +1538,7 @@ func finalizePsbt(ctx *cli.Context) error {
 
 	// Display the command's help message if we do not have the expected
 	// number of arguments/flags.
-	if ctx.NArg() > 1 || ctx.NumFlags() > 2 {
+	if ctx.NArg() > 1 || ctx.NumFlags() > 3 {
 		return cli.ShowCommandHelp(ctx, "finalize")
 	}
 
@@ -1526,9 +1559,14 @@ func finalizePsbt(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	psbtVersion, err := parsePsbtVersion(ctx)
+	if err != nil {
+		return err
+	}
 	req := &walletrpc.FinalizePsbtRequest{
-		FundedPsbt: psbtBytes,
-		Account:    ctx.String("account"),
+		FundedPsbt:  psbtBytes,
+		Account:     ctx.String("account"),
+		PsbtVersion: psbtVersion,
 	}
 
 	walletClient, cleanUp := getWalletClient(ctx)
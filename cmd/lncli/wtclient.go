@@ -27,6 +27,8 @@ func wtclientCommands() []cli.Command {
 				statsCommand,
 				policyCommand,
 				sessionCommands,
+				migrateTowerCommand,
+				migrationStatusCommand,
 			},
 		},
 	}
@@ -418,3 +420,85 @@ func terminateSession(ctx *cli.Context) error {
 
 	return nil
 }
+
+var migrateTowerCommand = cli.Command{
+	Name: "migrate",
+	Usage: "Migrate the backups of a decommissioned watchtower to a new " +
+		"one.",
+	Description: "The new watchtower is added, the old one is " +
+		"deactivated and the backed up states of all open channels " +
+		"are uploaded again. Once the migration is complete, the old " +
+		"watchtower can be removed.",
+	ArgsUsage: "old_pubkey new_pubkey@address",
+	Action:    actionDecorator(migrateTower),
+}
+
+func migrateTower(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if the number of arguments/flags
+	// is not what we expect.
+	if ctx.NArg() != 2 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "migrate")
+	}
+
+	oldPubKey, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	parts := strings.Split(ctx.Args().Get(1), "@")
+	if len(parts) != 2 {
+		return errors.New("expected tower of format pubkey@address")
+	}
+	newPubKey, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	req := &wtclientrpc.MigrateTowerRequest{
+		OldPubkey:  oldPubKey,
+		NewPubkey:  newPubKey,
+		NewAddress: parts[1],
+	}
+	resp, err := client.MigrateTower(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var migrationStatusCommand = cli.Command{
+	Name:   "migration",
+	Usage:  "Display the progress of the last watchtower migration.",
+	Action: actionDecorator(migrationStatus),
+}
+
+func migrationStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if the number of arguments/flags
+	// is not what we expect.
+	if ctx.NArg() > 0 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "migration")
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	req := &wtclientrpc.MigrationStatusRequest{}
+	resp, err := client.MigrationStatus(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
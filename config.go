@@ -5,6 +5,7 @@
 package lnd
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -15,12 +16,14 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/autopilot"
@@ -35,6 +38,7 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/lsprpc"
 	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
@@ -169,6 +173,16 @@ const (
 	defaultRSBackoff  = time.Second * 30
 	defaultRSAttempts = 1
 
+	// Set defaults for a health check which probes the latency and the
+	// replication lag of remote database backends. Although this check is
+	// off by default, we still set the other default values so that the
+	// health check can be easily enabled with sane defaults.
+	defaultRDBInterval   = time.Minute
+	defaultRDBTimeout    = time.Second * 10
+	defaultRDBBackoff    = time.Second * 10
+	defaultRDBAttempts   = 0
+	defaultRDBMaxLatency = time.Second * 2
+
 	// defaultRemoteMaxHtlcs specifies the default limit for maximum
 	// concurrent HTLCs the remote party may add to commitment transactions.
 	// This value can be overridden with --default-remote-max-htlcs.
@@ -207,6 +221,10 @@ const (
 	// used by default to fund transactions.
 	defaultCoinSelectionStrategy = "largest"
 
+	// defaultAddressReusePolicy is the policy that is applied by default
+	// when a transaction would pay to an address that was used before.
+	defaultAddressReusePolicy = "allow"
+
 	// defaultKeepFailedPaymentAttempts is the default setting for whether
 	// to keep failed payments in the database.
 	defaultKeepFailedPaymentAttempts = false
@@ -285,6 +303,8 @@ var (
 type Config struct {
 	ShowVersion bool `short:"V" long:"version" description:"Display version information and exit"`
 
+	CheckConfig bool `long:"check-config" description:"Validate the config file and the command line options, print the effective configuration with secrets redacted and exit"`
+
 	LndDir       string `long:"lnddir" description:"The base directory that contains lnd's data, logs, configuration file, etc. This option overwrites all other directory options."`
 	ConfigFile   string `short:"C" long:"configfile" description:"Path to configuration file"`
 	DataDir      string `short:"b" long:"datadir" description:"The directory to store lnd's data within"`
@@ -329,8 +349,10 @@ type Config struct {
 	DisableListen     bool          `long:"nolisten" description:"Disable listening for incoming peer connections"`
 	DisableRest       bool          `long:"norest" description:"Disable REST API"`
 	DisableRestTLS    bool          `long:"no-rest-tls" description:"Disable TLS for REST connections"`
+	DisableRestGzip   bool          `long:"no-rest-compression" description:"Disable gzip compression of REST responses, even if the client accepts it"`
 	WSPingInterval    time.Duration `long:"ws-ping-interval" description:"The ping interval for REST based WebSocket connections, set to 0 to disable sending ping messages from the server side"`
 	WSPongWait        time.Duration `long:"ws-pong-wait" description:"The time we wait for a pong response message on REST based WebSocket connections before the connection is closed as inactive"`
+	WSResumeTimeout   time.Duration `long:"ws-resume-timeout" description:"How long a resumable REST based WebSocket subscription is kept after its connection dropped, so the client can resume it without missing messages. Set to 0 to disable resumable subscriptions"`
 	NAT               bool          `long:"nat" description:"Toggle NAT traversal support (using either UPnP or NAT-PMP) to automatically advertise your external IP address to the network -- NOTE this does not support devices behind multiple NATs"`
 	AddPeers          []string      `long:"addpeer" description:"Specify peers to connect to first"`
 	MinBackoff        time.Duration `long:"minbackoff" description:"Shortest backoff when reconnecting to persistent peers. Valid time units are {s, m, h}."`
@@ -376,7 +398,9 @@ type Config struct {
 
 	ResetWalletTransactions bool `long:"reset-wallet-transactions" description:"Removes all transaction history from the on-chain wallet on startup, forcing a full chain rescan starting at the wallet's birthday. Implements the same functionality as btcwallet's dropwtxmgr command. Should be set to false after successful execution to avoid rescanning on every restart of lnd."`
 
-	CoinSelectionStrategy string `long:"coin-selection-strategy" description:"The strategy to use for selecting coins for wallet transactions." choice:"largest" choice:"random"`
+	CoinSelectionStrategy string `long:"coin-selection-strategy" description:"The strategy to use for selecting coins for wallet transactions. 'nochange' only selects coins that fund the transaction without a change output and fails otherwise." choice:"largest" choice:"random" choice:"smallest" choice:"oldest" choice:"nochange"`
+
+	AddressReusePolicy string `long:"address-reuse-policy" description:"What to do when SendCoins or SendMany would pay to an address that a wallet transaction already paid to. 'warn' logs a warning, 'refuse' fails the request." choice:"allow" choice:"warn" choice:"refuse"`
 
 	PaymentsExpirationGracePeriod time.Duration `long:"payments-expiration-grace-period" description:"A period to wait before force closing channels with outgoing htlcs that have timed-out and are a result of this node initiated payments."`
 	TrickleDelay                  int           `long:"trickledelay" description:"Time in milliseconds between each release of announcements to the network"`
@@ -427,6 +451,8 @@ type Config struct {
 
 	DryRunMigration bool `long:"dry-run-migration" description:"If true, lnd will abort committing a migration if it would otherwise have been successful. This leaves the database unmodified, and still compatible with the previously active version of lnd."`
 
+	ReadOnly bool `long:"read-only" description:"If true, lnd is started in read-only mode to safely observe a node's data, for example from a dashboard. None of the writes to the databases are persisted, RPC calls that would modify the node's state are rejected and the node neither connects to peers nor acts on-chain. Streaming calls are served if the subsystem sending their events runs in read-only mode, like the wallet, and are rejected with the reason why otherwise. Bolt databases are opened read-only with a shared lock. They can't be in use by an lnd instance that isn't in read-only mode at the same time."`
+
 	net tor.Net
 
 	EnableUpfrontShutdown bool `long:"enable-upfront-shutdown" description:"If true, option upfront shutdown script will be enabled. If peers that we open channels with support this feature, we will automatically set the script to which cooperative closes should be paid out to on channel open. This offers the partial protection of a channel peer disconnecting from us if cooperative close is attempted with a different script."`
@@ -457,6 +483,36 @@ type Config struct {
 
 	Prometheus lncfg.Prometheus `group:"prometheus" namespace:"prometheus"`
 
+	Tracing *lncfg.Tracing `group:"tracing" namespace:"tracing"`
+
+	BackupEscrow *lncfg.BackupEscrow `group:"backupescrow" namespace:"backupescrow"`
+
+	HoldInvoiceRules *lncfg.HoldInvoiceRules `group:"holdinvoicerules" namespace:"holdinvoicerules"`
+
+	LNURL *lncfg.LNURL `group:"lnurl" namespace:"lnurl"`
+
+	NodeMetadata *lncfg.NodeMetadata `group:"nodemetadata" namespace:"nodemetadata"`
+
+	AnchorReserve *lncfg.AnchorReserve `group:"anchorreserve" namespace:"anchorreserve"`
+
+	CoopCloseRbf *lncfg.CoopCloseRbf `group:"coopcloserbf" namespace:"coopcloserbf"`
+
+	LiquidityAlert *lncfg.LiquidityAlert `group:"liquidityalert" namespace:"liquidityalert"`
+
+	CltvGuard *lncfg.CltvGuard `group:"cltvguard" namespace:"cltvguard"`
+
+	HoldFee *lncfg.HoldFee `group:"holdfee" namespace:"holdfee"`
+
+	JamDetect *lncfg.JamDetect `group:"jamdetect" namespace:"jamdetect"`
+
+	JITChannel *lncfg.JITChannel `group:"jitchannel" namespace:"jitchannel"`
+
+	Trampoline *lncfg.Trampoline `group:"trampoline" namespace:"trampoline"`
+
+	SafeMode *lncfg.SafeMode `group:"safemode" namespace:"safemode"`
+
+	FeaturePolicy *lncfg.FeaturePolicy `group:"featurepolicy" namespace:"featurepolicy"`
+
 	WtClient *lncfg.WtClient `group:"wtclient" namespace:"wtclient"`
 
 	Watchtower *lncfg.Watchtower `group:"watchtower" namespace:"watchtower"`
@@ -475,6 +531,8 @@ type Config struct {
 
 	RemoteSigner *lncfg.RemoteSigner `group:"remotesigner" namespace:"remotesigner"`
 
+	HWI *lncfg.HWI `group:"hwi" namespace:"hwi"`
+
 	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
 
 	Htlcswitch *lncfg.Htlcswitch `group:"htlcswitch" namespace:"htlcswitch"`
@@ -496,6 +554,11 @@ type Config struct {
 	// Estimator is used to estimate routing probabilities.
 	Estimator routing.Estimator
 
+	// loadedOptions holds the string value of every option as it was
+	// loaded from the config file and the command line, keyed by the
+	// option's long name including its namespace.
+	loadedOptions map[string]string
+
 	// Dev specifies configs used for integration tests, which is always
 	// empty if not built with `integration` flag.
 	Dev *lncfg.DevConfig `group:"dev" namespace:"dev"`
@@ -532,6 +595,17 @@ type GRPCConfig struct {
 	// useful to keep the underlying HTTP/2 connection open for future
 	// requests.
 	ClientAllowPingWithoutStream bool `long:"client-allow-ping-without-stream" description:"If true, the server allows keepalive pings from the client even when there are no active gRPC streams. This might be useful to keep the underlying HTTP/2 connection open for future requests."`
+
+	// MaxListResults is the maximum number of results a listing RPC like
+	// ListInvoices returns at once.
+	MaxListResults uint64 `long:"max-list-results" description:"The maximum number of results the listing RPCs ListInvoices, ListPayments and ForwardingHistory return at once. Requests for more results, or for all of them, are limited to this number and the remaining results can be paged through with the returned index offsets. Set to 0 to disable the limit."`
+
+	// RejectUnboundedLists rejects listing requests over MaxListResults
+	// instead of limiting them.
+	RejectUnboundedLists bool `long:"reject-unbounded-lists" description:"If true, listing requests for more than max-list-results results, or for all of them, are rejected with an InvalidArgument error instead of being limited."`
+
+	// MaxResponseSize is the maximum size of a response message in bytes.
+	MaxResponseSize uint64 `long:"max-response-size" description:"The maximum size of a gRPC response message in bytes. Calls with larger responses fail with a ResourceExhausted error. Set to 0 to disable the limit."`
 }
 
 // DefaultConfig returns all default values for the Config struct.
@@ -554,6 +628,7 @@ func DefaultConfig() Config {
 		AcceptorTimeout:   defaultAcceptorTimeout,
 		WSPingInterval:    lnrpc.DefaultPingInterval,
 		WSPongWait:        lnrpc.DefaultPongWait,
+		WSResumeTimeout:   lnrpc.DefaultResumeTimeout,
 		Bitcoin: &lncfg.Chain{
 			MinHTLCIn:     chainreg.DefaultBitcoinMinHTLCInMSat,
 			MinHTLCOut:    chainreg.DefaultBitcoinMinHTLCOutMSat,
@@ -595,6 +670,7 @@ func DefaultConfig() Config {
 			SignRPC:   &signrpc.Config{},
 			RouterRPC: routerrpc.DefaultConfig(),
 			PeersRPC:  &peersrpc.Config{},
+			LspRPC:    &lsprpc.Config{},
 		},
 		Autopilot: &lncfg.AutoPilot{
 			MaxChannels:    5,
@@ -622,22 +698,39 @@ func DefaultConfig() Config {
 		NumGraphSyncPeers:             defaultMinPeers,
 		HistoricalSyncInterval:        discovery.DefaultHistoricalSyncInterval,
 		Tor: &lncfg.Tor{
-			SOCKS:   defaultTorSOCKS,
-			DNS:     defaultTorDNS,
-			Control: defaultTorControl,
+			SOCKS:           defaultTorSOCKS,
+			DNS:             defaultTorDNS,
+			IsolationPolicy: lncfg.TorIsolationNone,
+			Control:         defaultTorControl,
 		},
 		net: &tor.ClearNet{},
 		Workers: &lncfg.Workers{
-			Read:  lncfg.DefaultReadWorkers,
-			Write: lncfg.DefaultWriteWorkers,
-			Sig:   lncfg.DefaultSigWorkers,
+			Read:      lncfg.DefaultReadWorkers,
+			Write:     lncfg.DefaultWriteWorkers,
+			Sig:       lncfg.DefaultSigWorkers,
+			GossipSig: lncfg.DefaultGossipSigWorkers,
 		},
 		Caches: &lncfg.Caches{
 			RejectCacheSize:  channeldb.DefaultRejectCacheSize,
 			ChannelCacheSize: channeldb.DefaultChannelCacheSize,
 		},
-		Prometheus: lncfg.DefaultPrometheus(),
-		Watchtower: lncfg.DefaultWatchtowerCfg(defaultTowerDir),
+		Prometheus:       lncfg.DefaultPrometheus(),
+		Tracing:          lncfg.DefaultTracing(),
+		BackupEscrow:     lncfg.DefaultBackupEscrow(),
+		HoldInvoiceRules: lncfg.DefaultHoldInvoiceRules(),
+		LNURL:            lncfg.DefaultLNURL(),
+		NodeMetadata:     &lncfg.NodeMetadata{},
+		AnchorReserve:    lncfg.DefaultAnchorReserve(),
+		CoopCloseRbf:     lncfg.DefaultCoopCloseRbf(),
+		LiquidityAlert:   lncfg.DefaultLiquidityAlert(),
+		CltvGuard:        lncfg.DefaultCltvGuard(),
+		HoldFee:          lncfg.DefaultHoldFee(),
+		JamDetect:        lncfg.DefaultJamDetect(),
+		JITChannel:       lncfg.DefaultJITChannel(),
+		Trampoline:       lncfg.DefaultTrampoline(),
+		SafeMode:         &lncfg.SafeMode{},
+		FeaturePolicy:    &lncfg.FeaturePolicy{},
+		Watchtower:       lncfg.DefaultWatchtowerCfg(defaultTowerDir),
 		HealthChecks: &lncfg.HealthCheckConfig{
 			ChainCheck: &lncfg.CheckConfig{
 				Interval: defaultChainInterval,
@@ -660,11 +753,13 @@ func DefaultConfig() Config {
 				Attempts: defaultTLSAttempts,
 				Backoff:  defaultTLSBackoff,
 			},
-			TorConnection: &lncfg.CheckConfig{
-				Interval: defaultTCInterval,
-				Timeout:  defaultTCTimeout,
-				Attempts: defaultTCAttempts,
-				Backoff:  defaultTCBackoff,
+			TorConnection: &lncfg.TorConnectionCheckConfig{
+				CheckConfig: &lncfg.CheckConfig{
+					Interval: defaultTCInterval,
+					Timeout:  defaultTCTimeout,
+					Attempts: defaultTCAttempts,
+					Backoff:  defaultTCBackoff,
+				},
 			},
 			RemoteSigner: &lncfg.CheckConfig{
 				Interval: defaultRSInterval,
@@ -672,11 +767,23 @@ func DefaultConfig() Config {
 				Attempts: defaultRSAttempts,
 				Backoff:  defaultRSBackoff,
 			},
+			RemoteDB: &lncfg.RemoteDBCheckConfig{
+				MaxLatency: defaultRDBMaxLatency,
+				CheckConfig: &lncfg.CheckConfig{
+					Interval: defaultRDBInterval,
+					Timeout:  defaultRDBTimeout,
+					Attempts: defaultRDBAttempts,
+					Backoff:  defaultRDBBackoff,
+				},
+			},
 		},
 		Gossip: &lncfg.Gossip{
 			MaxChannelUpdateBurst: discovery.DefaultMaxChannelUpdateBurst,
 			ChannelUpdateInterval: discovery.DefaultChannelUpdateInterval,
 			SubBatchDelay:         discovery.DefaultSubBatchDelay,
+
+			MaxLocalChannelUpdateBurst: discovery.DefaultMaxLocalChannelUpdateBurst, //nolint:lll
+			LocalChannelUpdateInterval: discovery.DefaultLocalChannelUpdateInterval, //nolint:lll
 		},
 		Invoices: &lncfg.Invoices{
 			HoldExpiryDelta: lncfg.DefaultHoldInvoiceExpiryDelta,
@@ -694,13 +801,21 @@ func DefaultConfig() Config {
 		PendingCommitInterval:     defaultPendingCommitInterval,
 		ChannelCommitBatchSize:    defaultChannelCommitBatchSize,
 		CoinSelectionStrategy:     defaultCoinSelectionStrategy,
+		AddressReusePolicy:        defaultAddressReusePolicy,
 		KeepFailedPaymentAttempts: defaultKeepFailedPaymentAttempts,
 		RemoteSigner: &lncfg.RemoteSigner{
 			Timeout: lncfg.DefaultRemoteSignerRPCTimeout,
 		},
+		HWI: &lncfg.HWI{
+			Path:    lncfg.DefaultHWIPath,
+			Timeout: lncfg.DefaultHWITimeout,
+		},
 		Sweeper: lncfg.DefaultSweeperConfig(),
 		Htlcswitch: &lncfg.Htlcswitch{
-			MailboxDeliveryTimeout: htlcswitch.DefaultMailboxDeliveryTimeout,
+			MailboxDeliveryTimeout:     htlcswitch.DefaultMailboxDeliveryTimeout,
+			FlapDrainWindow:            lncfg.DefaultFlapDrainWindow,
+			NetworkResultRetention:     htlcswitch.DefaultNetworkResultRetention,
+			NetworkResultPruneInterval: htlcswitch.DefaultNetworkResultPruneInterval,
 		},
 		GRPC: &GRPCConfig{
 			ServerPingTime:    defaultGrpcServerPingTime,
@@ -738,36 +853,16 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		os.Exit(0)
 	}
 
-	// If the config file path has not been modified by the user, then we'll
-	// use the default config file path. However, if the user has modified
-	// their lnddir, then we should assume they intend to use the config
-	// file within it.
-	configFileDir := CleanAndExpandPath(preCfg.LndDir)
-	configFilePath := CleanAndExpandPath(preCfg.ConfigFile)
-	switch {
-	// User specified --lnddir but no --configfile. Update the config file
-	// path to the lnd config directory, but don't require it to exist.
-	case configFileDir != DefaultLndDir &&
-		configFilePath == DefaultConfigFile:
-
-		configFilePath = filepath.Join(
-			configFileDir, lncfg.DefaultConfigFilename,
-		)
-
-	// User did specify an explicit --configfile, so we check that it does
-	// exist under that path to avoid surprises.
-	case configFilePath != DefaultConfigFile:
-		if !lnrpc.FileExists(configFilePath) {
-			return nil, fmt.Errorf("specified config file does "+
-				"not exist in %s", configFilePath)
-		}
+	configFilePath, err := resolveConfigFilePath(&preCfg)
+	if err != nil {
+		return nil, err
 	}
 
 	// Next, load any additional configuration options from the file.
 	var configFileError error
 	cfg := preCfg
 	fileParser := flags.NewParser(&cfg, flags.Default)
-	err := flags.NewIniParser(fileParser).ParseFile(configFilePath)
+	err = flags.NewIniParser(fileParser).ParseFile(configFilePath)
 	if err != nil {
 		// If it's a parsing related error, then we'll return
 		// immediately, otherwise we can proceed as possibly the config
@@ -788,6 +883,13 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		return nil, err
 	}
 
+	// Remember the options as they were loaded, before validation changes
+	// them, so that a config reload can tell which options were changed.
+	loadedOptions, _, err := configToFlatMap(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Make sure everything we just loaded makes sense.
 	cleanCfg, err := ValidateConfig(
 		cfg, interceptor, fileParser, flagParser,
@@ -814,6 +916,20 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		return nil, err
 	}
 
+	// When checking the config, a config file that can't be read is an
+	// error, as it usually means its path was mistyped. Otherwise we print
+	// the effective config and exit.
+	if cleanCfg.CheckConfig {
+		if configFileError != nil {
+			return nil, configFileError
+		}
+
+		if err := printEffectiveConfig(os.Stdout, cleanCfg); err != nil {
+			return nil, err
+		}
+		os.Exit(0)
+	}
+
 	// Warn about missing config file only after all other configuration is
 	// done. This prevents the warning on help messages and invalid options.
 	// Note this should go directly before the return.
@@ -824,9 +940,83 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 	// Finally, log warnings for deprecated config options if they are set.
 	logWarningsForDeprecation(*cleanCfg)
 
+	cleanCfg.loadedOptions = loadedOptions
+
 	return cleanCfg, nil
 }
 
+// sanitizeAutopilotConfig checks the autopilot config for invalid values and
+// clamps the channel sizes to the bounds of the normal channel size
+// constraints.
+func sanitizeAutopilotConfig(cfg *lncfg.AutoPilot) error {
+	// Ensure that the user didn't attempt to specify negative values for
+	// any of the autopilot params.
+	if cfg.MaxChannels < 0 {
+		return fmt.Errorf("autopilot.maxchannels must be non-negative")
+	}
+	if cfg.Allocation < 0 {
+		return fmt.Errorf("autopilot.allocation must be non-negative")
+	}
+	if cfg.MinChannelSize < 0 {
+		return fmt.Errorf("autopilot.minchansize must be non-negative")
+	}
+	if cfg.MaxChannelSize < 0 {
+		return fmt.Errorf("autopilot.maxchansize must be non-negative")
+	}
+	if cfg.MinConfs < 0 {
+		return fmt.Errorf("autopilot.minconfs must be non-negative")
+	}
+	if cfg.ConfTarget < 1 {
+		return fmt.Errorf("autopilot.conftarget must be positive")
+	}
+
+	// Ensure that the specified values for the min and max channel size
+	// are within the bounds of the normal chan size constraints.
+	if cfg.MinChannelSize < int64(funding.MinChanFundingSize) {
+		cfg.MinChannelSize = int64(funding.MinChanFundingSize)
+	}
+	if cfg.MaxChannelSize > int64(MaxFundingAmount) {
+		cfg.MaxChannelSize = int64(MaxFundingAmount)
+	}
+
+	if _, err := validateAtplCfg(cfg); err != nil {
+		return fmt.Errorf("error validating autopilot: %w", err)
+	}
+
+	return nil
+}
+
+// resolveConfigFilePath returns the path of the config file to load given the
+// pre-parsed command line options.
+func resolveConfigFilePath(preCfg *Config) (string, error) {
+	// If the config file path has not been modified by the user, then we'll
+	// use the default config file path. However, if the user has modified
+	// their lnddir, then we should assume they intend to use the config
+	// file within it.
+	configFileDir := CleanAndExpandPath(preCfg.LndDir)
+	configFilePath := CleanAndExpandPath(preCfg.ConfigFile)
+	switch {
+	// User specified --lnddir but no --configfile. Update the config file
+	// path to the lnd config directory, but don't require it to exist.
+	case configFileDir != DefaultLndDir &&
+		configFilePath == DefaultConfigFile:
+
+		configFilePath = filepath.Join(
+			configFileDir, lncfg.DefaultConfigFilename,
+		)
+
+	// User did specify an explicit --configfile, so we check that it does
+	// exist under that path to avoid surprises.
+	case configFilePath != DefaultConfigFile:
+		if !lnrpc.FileExists(configFilePath) {
+			return "", fmt.Errorf("specified config file does "+
+				"not exist in %s", configFilePath)
+		}
+	}
+
+	return configFilePath, nil
+}
+
 // usageError is an error type that signals a problem with the supplied flags.
 type usageError struct {
 	err error
@@ -956,50 +1146,8 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.WalletUnlockPasswordFile,
 	)
 
-	// Ensure that the user didn't attempt to specify negative values for
-	// any of the autopilot params.
-	if cfg.Autopilot.MaxChannels < 0 {
-		str := "autopilot.maxchannels must be non-negative"
-
-		return nil, mkErr(str)
-	}
-	if cfg.Autopilot.Allocation < 0 {
-		str := "autopilot.allocation must be non-negative"
-
-		return nil, mkErr(str)
-	}
-	if cfg.Autopilot.MinChannelSize < 0 {
-		str := "autopilot.minchansize must be non-negative"
-
-		return nil, mkErr(str)
-	}
-	if cfg.Autopilot.MaxChannelSize < 0 {
-		str := "autopilot.maxchansize must be non-negative"
-
-		return nil, mkErr(str)
-	}
-	if cfg.Autopilot.MinConfs < 0 {
-		str := "autopilot.minconfs must be non-negative"
-
-		return nil, mkErr(str)
-	}
-	if cfg.Autopilot.ConfTarget < 1 {
-		str := "autopilot.conftarget must be positive"
-
-		return nil, mkErr(str)
-	}
-
-	// Ensure that the specified values for the min and max channel size
-	// are within the bounds of the normal chan size constraints.
-	if cfg.Autopilot.MinChannelSize < int64(funding.MinChanFundingSize) {
-		cfg.Autopilot.MinChannelSize = int64(funding.MinChanFundingSize)
-	}
-	if cfg.Autopilot.MaxChannelSize > int64(MaxFundingAmount) {
-		cfg.Autopilot.MaxChannelSize = int64(MaxFundingAmount)
-	}
-
-	if _, err := validateAtplCfg(cfg.Autopilot); err != nil {
-		return nil, mkErr("error validating autopilot: %v", err)
+	if err := sanitizeAutopilotConfig(cfg.Autopilot); err != nil {
+		return nil, mkErr("%v", err)
 	}
 
 	// Ensure that --maxchansize is properly handled when set by user.
@@ -1087,6 +1235,12 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	}
 	cfg.Tor.Control = control.String()
 
+	// The tor controller expects the path of a UNIX domain socket to be
+	// prefixed with unix:// to tell it apart from a TCP address.
+	if _, ok := control.(*net.UnixAddr); ok {
+		cfg.Tor.Control = "unix://" + control.String()
+	}
+
 	// Ensure that tor socks host:port is not equal to tor control
 	// host:port. This would lead to lnd not starting up properly.
 	if cfg.Tor.SOCKS == cfg.Tor.Control {
@@ -1138,10 +1292,24 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	// we'll use the Tor proxy specific functions in order to avoid leaking
 	// our real information.
 	if cfg.Tor.Active {
+		isolationPolicy, err := cfg.Tor.ProxyIsolationPolicy()
+		if err != nil {
+			return nil, mkErr("error parsing tor isolation "+
+				"policy: %v", err)
+		}
+
+		if cfg.Tor.StreamIsolation &&
+			isolationPolicy != tor.IsolationNone {
+
+			return nil, mkErr("tor.streamisolation and " +
+				"tor.isolationpolicy are mutually exclusive")
+		}
+
 		cfg.net = &tor.ProxyNet{
 			SOCKS:                       cfg.Tor.SOCKS,
 			DNS:                         cfg.Tor.DNS,
 			StreamIsolation:             cfg.Tor.StreamIsolation,
+			IsolationPolicy:             isolationPolicy,
 			SkipProxyForClearNetTargets: cfg.Tor.SkipProxyForClearNetTargets,
 		}
 	}
@@ -1186,7 +1354,6 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	}
 	if cfg.Bitcoin.SigNet {
 		numNets++
-		cfg.ActiveNetParams = chainreg.BitcoinSigNetParams
 
 		// Let the user overwrite the default signet parameters.
 		// The challenge defines the actual signet network to
@@ -1218,10 +1385,32 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			}
 		}
 
-		chainParams := chaincfg.CustomSignetParams(
-			sigNetChallenge, sigNetSeeds,
+		// A custom signet may also use its own genesis block, which
+		// gives it a chain hash distinct from the default signet.
+		var sigNetGenesis *wire.MsgBlock
+		if cfg.Bitcoin.SigNetGenesis != "" {
+			genesis, err := hex.DecodeString(
+				cfg.Bitcoin.SigNetGenesis,
+			)
+			if err != nil {
+				return nil, mkErr("Invalid "+
+					"signet genesis, hex decode "+
+					"failed: %v", err)
+			}
+
+			sigNetGenesis = &wire.MsgBlock{}
+			err = sigNetGenesis.Deserialize(
+				bytes.NewReader(genesis),
+			)
+			if err != nil {
+				return nil, mkErr("Invalid "+
+					"signet genesis block: %v", err)
+			}
+		}
+
+		cfg.ActiveNetParams = chainreg.CustomSigNetParams(
+			sigNetChallenge, sigNetSeeds, sigNetGenesis,
 		)
-		cfg.ActiveNetParams.Params = &chainParams
 	}
 	if numNets > 1 {
 		str := "The mainnet, testnet, regtest, simnet and signet " +
@@ -1241,7 +1430,44 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr(str)
 	}
 
-	err = cfg.Bitcoin.Validate(minTimeLockDelta, funding.MinBtcRemoteDelay)
+	// The time-compressed CLTV mode scales down the deltas that protect
+	// our funds, so we only allow it on networks without real value.
+	cltvScale := cfg.Dev.GetCLTVScale()
+	if cltvScale > 1 {
+		if !cfg.Bitcoin.SimNet && !cfg.Bitcoin.RegTest {
+			return nil, mkErr("dev.cltvscale can only be used " +
+				"on simnet or regtest")
+		}
+
+		ltndLog.Warnf("Scaling down CLTV and CSV deltas by a "+
+			"factor of %v", cltvScale)
+
+		// Scale down the defaults that weren't overwritten by the
+		// user, so they stay consistent with the htlc deltas.
+		defaultTimeLockDelta := uint32(
+			chainreg.DefaultBitcoinTimeLockDelta,
+		)
+		if cfg.Bitcoin.TimeLockDelta == defaultTimeLockDelta {
+			cfg.Bitcoin.TimeLockDelta = lncfg.ScaleBlocks(
+				defaultTimeLockDelta, cltvScale,
+			)
+		}
+		if cfg.Invoices.HoldExpiryDelta ==
+			lncfg.DefaultHoldInvoiceExpiryDelta {
+
+			cfg.Invoices.HoldExpiryDelta =
+				cfg.cltvDeltas().HoldInvoiceExpiryDelta
+		}
+	}
+
+	err = cfg.Bitcoin.Validate(
+		cfg.minTimeLockDelta(), cfg.minRemoteDelay(),
+	)
+	if err != nil {
+		return nil, mkErr("error validating bitcoin params: %v", err)
+	}
+
+	err = validateInboundFee(&cfg)
 	if err != nil {
 		return nil, mkErr("error validating bitcoin params: %v", err)
 	}
@@ -1503,6 +1729,12 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			"not exist", cfg.WalletUnlockPasswordFile)
 	}
 
+	// Rejecting unbounded listing requests requires a bound.
+	if cfg.GRPC.RejectUnboundedLists && cfg.GRPC.MaxListResults == 0 {
+		return nil, mkErr("grpc.reject-unbounded-lists requires " +
+			"grpc.max-list-results to be set")
+	}
+
 	// For each of the RPC listeners (REST+gRPC), we'll ensure that users
 	// have specified a safe combo for authentication. If not, we'll bail
 	// out with an error. Since we don't allow disabling TLS for gRPC
@@ -1516,6 +1748,11 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	}
 
 	if cfg.DisableRest {
+		// The LNURL server is served by the REST proxy.
+		if cfg.LNURL.Active {
+			return nil, mkErr("lnurl.active requires the REST API")
+		}
+
 		ltndLog.Infof("REST API is disabled!")
 		cfg.RESTListeners = nil
 	} else {
@@ -1656,28 +1893,78 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr("error parsing gossip syncer: %v", err)
 	}
 
+	if cfg.Gossip.LocalChannelUpdateInterval > 0 &&
+		cfg.Gossip.MaxLocalChannelUpdateBurst <= 0 {
+
+		return nil, mkErr("max-local-channel-update-burst must be " +
+			"positive")
+	}
+
 	// Log a warning if our expiry delta is not greater than our incoming
 	// broadcast delta. We do not fail here because this value may be set
 	// to zero to intentionally keep lnd's behavior unchanged from when we
 	// didn't auto-cancel these invoices.
-	if cfg.Invoices.HoldExpiryDelta <= lncfg.DefaultIncomingBroadcastDelta {
+	incomingDelta := cfg.cltvDeltas().IncomingBroadcastDelta
+	if cfg.Invoices.HoldExpiryDelta <= incomingDelta {
 		ltndLog.Warnf("Invoice hold expiry delta: %v <= incoming "+
 			"delta: %v, accepted hold invoices will force close "+
 			"channels if they are not canceled manually",
-			cfg.Invoices.HoldExpiryDelta,
-			lncfg.DefaultIncomingBroadcastDelta)
+			cfg.Invoices.HoldExpiryDelta, incomingDelta)
 	}
 
 	// If the experimental protocol options specify any protocol messages
 	// that we want to handle as custom messages, set them now.
 	customMsg := cfg.ProtocolOptions.CustomMessageOverrides()
 
+	// Onion messages can either be handled by lnd itself or be passed on
+	// to an external application as custom messages, but not both.
+	if cfg.ProtocolOptions.OnionMessages {
+		for _, msgType := range customMsg {
+			if msgType != lnwire.MsgOnionMessage {
+				continue
+			}
+
+			return nil, mkErr("protocol.onion-messages can't be "+
+				"used with custom-message=%d",
+				lnwire.MsgOnionMessage)
+		}
+	}
+
+	if cfg.ProtocolOptions.AsyncPayments &&
+		!cfg.ProtocolOptions.OnionMessages {
+
+		return nil, mkErr("protocol.async-payments requires " +
+			"protocol.onion-messages")
+	}
+
+	if cfg.ProtocolOptions.TaprootGossip &&
+		!cfg.ProtocolOptions.TaprootChans {
+
+		return nil, mkErr("protocol.taproot-gossip requires " +
+			"protocol.simple-taproot-chans")
+	}
+
 	// We can safely set our custom override values during startup because
 	// startup is blocked on config parsing.
 	if err := lnwire.SetCustomOverrides(customMsg); err != nil {
 		return nil, mkErr("custom-message: %v", err)
 	}
 
+	// Writes to the native SQL stores can't be discarded, so they can't be
+	// used in read-only mode. And since no migrations are applied in
+	// read-only mode, a dry run wouldn't do anything either.
+	if cfg.ReadOnly {
+		switch {
+		case cfg.DB.UseNativeSQL:
+			return nil, mkErr("read-only mode can't be used with " +
+				"native SQL")
+
+		case cfg.DryRunMigration:
+			return nil, mkErr("read-only mode can't be used with " +
+				"dry-run-migration")
+		}
+	}
+
 	// Validate the subconfigs for workers, caches, and the tower client.
 	err = lncfg.Validate(
 		cfg.Workers,
@@ -1688,13 +1975,37 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.HealthChecks,
 		cfg.RPCMiddleware,
 		cfg.RemoteSigner,
+		cfg.HWI,
 		cfg.Sweeper,
 		cfg.Htlcswitch,
+		cfg.Tracing,
+		cfg.BackupEscrow,
+		cfg.HoldInvoiceRules,
+		cfg.LNURL,
+		cfg.NodeMetadata,
+		cfg.AnchorReserve,
+		cfg.CoopCloseRbf,
+		cfg.LiquidityAlert,
+		cfg.CltvGuard,
+		cfg.HoldFee,
+		cfg.JamDetect,
+		cfg.JITChannel,
+		cfg.Trampoline,
+		cfg.FeaturePolicy,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Just-in-time channels are opened as zero-conf channels, which
+	// require the zero-conf and the option-scid-alias feature bits.
+	if cfg.JITChannel.Active && (!cfg.ProtocolOptions.ZeroConf() ||
+		!cfg.ProtocolOptions.ScidAlias()) {
+
+		return nil, mkErr("jitchannel.active requires " +
+			"protocol.zero-conf and protocol.option-scid-alias")
+	}
+
 	// Finally, ensure that the user's color is correctly formatted,
 	// otherwise the server will not be able to start after the unlocking
 	// the wallet.
@@ -1716,6 +2027,34 @@ func (c *Config) graphDatabaseDir() string {
 	)
 }
 
+// cltvDeltas returns the htlc deltas to use, which are scaled down if the
+// time-compressed CLTV dev mode is active.
+func (c *Config) cltvDeltas() lncfg.CLTVDeltas {
+	return lncfg.ScaledCLTVDeltas(c.Dev.GetCLTVScale())
+}
+
+// minTimeLockDelta returns the minimum timelock we require for incoming HTLCs
+// on our channels and in our invoices.
+func (c *Config) minTimeLockDelta() uint32 {
+	return lncfg.ScaleBlocks(minTimeLockDelta, c.Dev.GetCLTVScale())
+}
+
+// minRemoteDelay returns the minimum CSV delay we require the remote party to
+// use for its commitment outputs.
+func (c *Config) minRemoteDelay() uint16 {
+	return uint16(lncfg.ScaleBlocks(
+		uint32(funding.MinBtcRemoteDelay), c.Dev.GetCLTVScale(),
+	))
+}
+
+// maxRemoteDelay returns the maximum CSV delay we require the remote party to
+// use for its commitment outputs.
+func (c *Config) maxRemoteDelay() uint16 {
+	return uint16(lncfg.ScaleBlocks(
+		uint32(funding.MaxBtcRemoteDelay), c.Dev.GetCLTVScale(),
+	))
+}
+
 // ImplementationConfig returns the configuration of what actual implementations
 // should be used when creating the main lnd instance.
 func (c *Config) ImplementationConfig(
@@ -2306,6 +2645,39 @@ func configToFlatMap(cfg Config) (map[string]string,
 	return result, deprecated, nil
 }
 
+// printEffectiveConfig writes every option of the given config to the writer
+// in the format of the config file, sorted by name and with secrets redacted.
+// Deprecated options that are set are marked as such.
+func printEffectiveConfig(w io.Writer, cfg *Config) error {
+	flatConfig, deprecated, err := configToFlatMap(*cfg)
+	if err != nil {
+		return fmt.Errorf("error converting config to flat map: %w",
+			err)
+	}
+
+	keys := make([]string, 0, len(flatConfig))
+	for key := range flatConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, ok := deprecated[key]; ok {
+			_, err := fmt.Fprintf(w, "; %s is deprecated\n", key)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "%s=%s\n", key, flatConfig[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // logWarningsForDeprecation logs a warning if a deprecated config option is
 // set.
 func logWarningsForDeprecation(cfg Config) {
@@ -2318,3 +2690,25 @@ func logWarningsForDeprecation(cfg Config) {
 		ltndLog.Warnf("Config '%s' is deprecated, please remove it", k)
 	}
 }
+
+// validateInboundFee checks that the default inbound fee is only positive if
+// positive inbound fees are accepted.
+func validateInboundFee(cfg *Config) error {
+	if cfg.AcceptPositiveInboundFees {
+		return nil
+	}
+
+	if cfg.Bitcoin.InboundBaseFee > 0 {
+		return fmt.Errorf("positive inbound base fee %v requires "+
+			"accept-positive-inbound-fees",
+			cfg.Bitcoin.InboundBaseFee)
+	}
+
+	if cfg.Bitcoin.InboundFeeRate > 0 {
+		return fmt.Errorf("positive inbound fee rate %v requires "+
+			"accept-positive-inbound-fees",
+			cfg.Bitcoin.InboundFeeRate)
+	}
+
+	return nil
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/rpcperms"
@@ -49,6 +50,7 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
@@ -407,7 +409,7 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		}
 		macaroonService, err = macaroons.NewService(
 			rootKeyStore, "lnd", walletInitParams.StatelessInit,
-			macaroons.IPLockChecker,
+			macaroons.IPLockChecker, macaroons.AccountChecker,
 			macaroons.CustomChecker(interceptorChain),
 		)
 		if err != nil {
@@ -560,6 +562,13 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 				"tcp", addr, d.cfg.ConnectionTimeout,
 			)
 		},
+		FeeDialer: func(network, addr string) (net.Conn, error) {
+			feeNet := isolatedNet(d.cfg.net, torClassFeeEstimator)
+
+			return feeNet.Dial(
+				network, addr, d.cfg.ConnectionTimeout,
+			)
+		},
 		BlockCache:         blockCache,
 		WalletUnlockParams: &walletInitParams,
 	}
@@ -600,6 +609,18 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 	case "random":
 		walletConfig.CoinSelectionStrategy = wallet.CoinSelectionRandom
 
+	case "smallest":
+		walletConfig.CoinSelectionStrategy =
+			chanfunding.CoinSelectionSmallest
+
+	case "oldest":
+		walletConfig.CoinSelectionStrategy =
+			chanfunding.CoinSelectionOldest
+
+	case "nochange":
+		walletConfig.CoinSelectionStrategy =
+			chanfunding.CoinSelectionNoChange
+
 	default:
 		return nil, nil, nil, fmt.Errorf("unknown coin selection "+
 			"strategy %v", d.cfg.CoinSelectionStrategy)
@@ -882,6 +903,14 @@ type DatabaseInstances struct {
 	// for native SQL queries for tables that already support it. This may
 	// be nil if the use-native-sql flag was not set.
 	NativeSQLStore *sqldb.BaseDB
+
+	// BoltDBs holds the bolt database files keyed by their namespace
+	// name. This is empty if a different database backend is used.
+	BoltDBs map[string]*lncfg.BoltDB
+
+	// FencedDBs holds the database backends that support fencing tokens,
+	// keyed by their namespace name.
+	FencedDBs map[string]lncfg.FencedBackend
 }
 
 // DefaultDatabaseBuilder is a type that builds the default database backends
@@ -925,7 +954,8 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		ctx, cfg.graphDatabaseDir(), cfg.networkDir, filepath.Join(
 			cfg.Watchtower.TowerDir, BitcoinChainName,
 			lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
-		), cfg.WtClient.Active, cfg.Watchtower.Active, d.logger,
+		), cfg.WtClient.Active, cfg.Watchtower.Active, cfg.ReadOnly,
+		d.logger,
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to obtain database "+
@@ -941,6 +971,8 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		DecayedLogDB:   databaseBackends.DecayedLogDB,
 		WalletDB:       databaseBackends.WalletDB,
 		NativeSQLStore: databaseBackends.NativeSQLStore,
+		BoltDBs:        databaseBackends.BoltDBs,
+		FencedDBs:      databaseBackends.FencedDBs,
 	}
 	cleanUp := func() {
 		// We can just close the returned close functions directly. Even
@@ -953,6 +985,26 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 			}
 		}
 	}
+	// If requested, import a channel DB export of a migrated node before
+	// the channel DB is initialized. This is only possible into one of the
+	// SQL backends, since the export is taken from a bolt-backed node.
+	if importPath := cfg.Dev.GetImportChannelDB(); importPath != "" {
+		err := importChannelDB(
+			cfg.DB.Backend, databaseBackends.ChanStateDB,
+			importPath,
+		)
+		if err != nil {
+			cleanUp()
+
+			err := fmt.Errorf("unable to import channel DB: %w",
+				err)
+			d.logger.Error(err)
+			return nil, nil, err
+		}
+
+		d.logger.Infof("Imported channel DB from %v", importPath)
+	}
+
 	if databaseBackends.Remote {
 		d.logger.Infof("Using remote %v database! Creating "+
 			"graph and channel state DB instances", cfg.DB.Backend)
@@ -969,6 +1021,7 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		channeldb.OptionSetBatchCommitInterval(
 			cfg.DB.BatchCommitInterval,
 		),
+		channeldb.OptionSetMaxBatchSize(cfg.DB.MaxBatchSize),
 		channeldb.OptionDryRunMigration(cfg.DryRunMigration),
 		channeldb.OptionSetUseGraphCache(!cfg.DB.NoGraphCache),
 		channeldb.OptionKeepFailedPaymentAttempts(
@@ -979,6 +1032,11 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		),
 		channeldb.OptionPruneRevocationLog(cfg.DB.PruneRevocation),
 		channeldb.OptionNoRevLogAmtData(cfg.DB.NoRevLogAmtData),
+		channeldb.OptionCompactRevLog(cfg.DB.CompactRevLog),
+
+		// In read-only mode we can't apply any migrations, since
+		// they would never be persisted.
+		channeldb.OptionNoMigration(cfg.ReadOnly),
 	}
 
 	// We want to pre-allocate the channel graph cache according to what we
@@ -991,11 +1049,73 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		)
 	}
 
-	// Otherwise, we'll open two instances, one for the state we only need
-	// locally, and the other for things we want to ensure are replicated.
-	dbs.GraphDB, err = channeldb.CreateWithBackend(
-		databaseBackends.GraphDB, dbOptions...,
-	)
+	// The graph DB and the watchtower DBs don't depend on each other.
+	// With bbolt, each of them is a separate file, so we open them
+	// concurrently. Opening the graph DB applies the pending migrations
+	// and populates the graph cache, which can take minutes on large
+	// nodes. Remote backends serve all DBs from the same database, so
+	// there we open them one after the other to not contend with the
+	// graph DB.
+	var eg errgroup.Group
+	if cfg.DB.Backend != lncfg.BoltBackend {
+		eg.SetLimit(1)
+	}
+	eg.Go(func() error {
+		startGraphTime := time.Now()
+
+		var err error
+		dbs.GraphDB, err = channeldb.CreateWithBackend(
+			databaseBackends.GraphDB, dbOptions...,
+		)
+		switch {
+		case errors.Is(err, channeldb.ErrDryRunMigrationOK):
+			return err
+
+		case err != nil:
+			return fmt.Errorf("unable to open graph DB: %w", err)
+		}
+
+		d.logger.Debugf("Graph DB now open (time_to_open=%v)",
+			time.Since(startGraphTime))
+
+		return nil
+	})
+
+	// Wrap the watchtower client DB.
+	if cfg.WtClient.Active {
+		eg.Go(func() error {
+			var err error
+			dbs.TowerClientDB, err = wtdb.OpenClientDB(
+				databaseBackends.TowerClientDB,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to open %s "+
+					"database: %w", lncfg.NSTowerClientDB,
+					err)
+			}
+
+			return nil
+		})
+	}
+
+	// Wrap the watchtower server DB.
+	if cfg.Watchtower.Active {
+		eg.Go(func() error {
+			var err error
+			dbs.TowerServerDB, err = wtdb.OpenTowerDB(
+				databaseBackends.TowerServerDB,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to open %s "+
+					"database: %w", lncfg.NSTowerServerDB,
+					err)
+			}
+
+			return nil
+		})
+	}
+
+	err = eg.Wait()
 	switch {
 	// Give the DB a chance to dry run the migration. Since we know that
 	// both the channel state and graph DBs are still always behind the same
@@ -1007,7 +1127,6 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	case err != nil:
 		cleanUp()
 
-		err := fmt.Errorf("unable to open graph DB: %w", err)
 		d.logger.Error(err)
 		return nil, nil, err
 	}
@@ -1069,36 +1188,6 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		dbs.InvoiceDB = dbs.GraphDB
 	}
 
-	// Wrap the watchtower client DB and make sure we clean up.
-	if cfg.WtClient.Active {
-		dbs.TowerClientDB, err = wtdb.OpenClientDB(
-			databaseBackends.TowerClientDB,
-		)
-		if err != nil {
-			cleanUp()
-
-			err := fmt.Errorf("unable to open %s database: %w",
-				lncfg.NSTowerClientDB, err)
-			d.logger.Error(err)
-			return nil, nil, err
-		}
-	}
-
-	// Wrap the watchtower server DB and make sure we clean up.
-	if cfg.Watchtower.Active {
-		dbs.TowerServerDB, err = wtdb.OpenTowerDB(
-			databaseBackends.TowerServerDB,
-		)
-		if err != nil {
-			cleanUp()
-
-			err := fmt.Errorf("unable to open %s database: %w",
-				lncfg.NSTowerServerDB, err)
-			d.logger.Error(err)
-			return nil, nil, err
-		}
-	}
-
 	openTime := time.Since(startOpenTime)
 	d.logger.Infof("Database(s) now open (time_to_open=%v)!", openTime)
 
@@ -1515,3 +1604,25 @@ func broadcastErrorMapper(err error) error {
 
 	return returnErr
 }
+
+// importChannelDB imports the channel DB export at the given path into the
+// given, empty channel state DB backend.
+func importChannelDB(dbBackend string, db kvdb.Backend,
+	importPath string) error {
+
+	if dbBackend != lncfg.PostgresBackend &&
+		dbBackend != lncfg.SqliteBackend {
+
+		return fmt.Errorf("channel DB can only be imported into a "+
+			"%v or %v backend, got %v", lncfg.PostgresBackend,
+			lncfg.SqliteBackend, dbBackend)
+	}
+
+	file, err := os.Open(importPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return channeldb.ImportForMigration(db, file)
+}
@@ -0,0 +1,317 @@
+package lnd
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lightningnetwork/lnd/autopilot"
+	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnutils"
+)
+
+// ConfigReloadReport describes the outcome of a config reload. Only options
+// that changed since they were last loaded are listed.
+type ConfigReloadReport struct {
+	// Applied are the changed options that took effect.
+	Applied []string
+
+	// Rejected maps the changed options that didn't take effect to the
+	// reason why. Rejected options are reported again on the next reload
+	// until they take effect.
+	Rejected map[string]string
+}
+
+// reloadableOptions is a set of options that can be changed without a restart.
+// The options of a set are applied together.
+type reloadableOptions struct {
+	// names are the long names of the options, including their namespace.
+	names []string
+
+	// apply makes the values of the options in the given config take
+	// effect.
+	apply func(r *configReloader, cfg *Config) error
+}
+
+// reloadable lists all options that can be changed without a restart.
+var reloadable = []reloadableOptions{
+	{
+		names: []string{"debuglevel"},
+		apply: (*configReloader).applyDebugLevel,
+	},
+	{
+		names: []string{
+			"bitcoin.basefee", "bitcoin.feerate",
+			"bitcoin.inboundbasefee", "bitcoin.inboundfeerate",
+			"bitcoin.timelockdelta",
+		},
+		apply: (*configReloader).applyRoutingPolicy,
+	},
+	{
+		names: []string{
+			"gossip.max-channel-update-burst",
+			"gossip.channel-update-interval",
+		},
+		apply: (*configReloader).applyGossipRateLimit,
+	},
+	{
+		names: []string{
+			"autopilot.active", "autopilot.heuristic",
+			"autopilot.maxchannels", "autopilot.allocation",
+			"autopilot.minchansize", "autopilot.maxchansize",
+			"autopilot.private", "autopilot.minconfs",
+			"autopilot.conftarget",
+		},
+		apply: (*configReloader).applyAutopilot,
+	},
+}
+
+// configReloader reloads the config of a running node and applies the changes
+// to the options that can be changed without a restart.
+type configReloader struct {
+	cfg       *Config
+	server    *server
+	atplMgr   *autopilot.Manager
+	parseArgs []string
+
+	mu sync.Mutex
+}
+
+// newConfigReloader creates a config reloader for the node running with the
+// given config. The command line options are parsed from the given arguments
+// on every reload, just like they were on start up.
+func newConfigReloader(cfg *Config, s *server, atplMgr *autopilot.Manager,
+	args []string) *configReloader {
+
+	return &configReloader{
+		cfg:       cfg,
+		server:    s,
+		atplMgr:   atplMgr,
+		parseArgs: args,
+	}
+}
+
+// Reload parses the config file and the command line options again and
+// applies the options that changed. Changes to options that can't be applied
+// without a restart are rejected and reported.
+func (r *configReloader) Reload() (*ConfigReloadReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newCfg, options, err := parseConfigOptions(r.parseArgs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+
+	changed := changedOptions(r.cfg.loadedOptions, options)
+
+	report := &ConfigReloadReport{
+		Rejected: make(map[string]string),
+	}
+	for _, set := range reloadable {
+		var names []string
+		for _, name := range set.names {
+			if _, ok := changed[name]; ok {
+				names = append(names, name)
+				delete(changed, name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		if err := set.apply(r, newCfg); err != nil {
+			for _, name := range names {
+				report.Rejected[name] = err.Error()
+			}
+
+			continue
+		}
+
+		for _, name := range names {
+			report.Applied = append(report.Applied, name)
+			r.cfg.loadedOptions[name] = options[name]
+		}
+	}
+
+	for name := range changed {
+		report.Rejected[name] = "requires a restart"
+	}
+
+	sort.Strings(report.Applied)
+
+	ltndLog.Infof("Reloaded config, applied=%v, rejected=%v",
+		report.Applied, report.Rejected)
+
+	return report, nil
+}
+
+// applyDebugLevel applies the log levels of the given config.
+func (r *configReloader) applyDebugLevel(cfg *Config) error {
+	err := build.ParseAndSetDebugLevels(cfg.DebugLevel, r.cfg.LogWriter)
+	if err != nil {
+		return err
+	}
+
+	r.cfg.DebugLevel = cfg.DebugLevel
+
+	return nil
+}
+
+// applyRoutingPolicy applies the routing policy new channels are opened with.
+// Channels that are already open keep their policy.
+func (r *configReloader) applyRoutingPolicy(cfg *Config) error {
+	if cfg.Bitcoin.TimeLockDelta < cfg.minTimeLockDelta() {
+		return fmt.Errorf("timelockdelta must be at least %v",
+			cfg.minTimeLockDelta())
+	}
+
+	if err := validateInboundFee(cfg); err != nil {
+		return err
+	}
+
+	s := r.server
+
+	s.mu.Lock()
+	s.cc.RoutingPolicy.BaseFee = cfg.Bitcoin.BaseFee
+	s.cc.RoutingPolicy.FeeRate = cfg.Bitcoin.FeeRate
+	s.cc.RoutingPolicy.InboundFee = models.InboundFee{
+		Base: cfg.Bitcoin.InboundBaseFee,
+		Rate: cfg.Bitcoin.InboundFeeRate,
+	}
+	s.cc.RoutingPolicy.TimeLockDelta = cfg.Bitcoin.TimeLockDelta
+	policy := s.cc.RoutingPolicy
+	s.mu.Unlock()
+
+	s.fundingMgr.SetDefaultRoutingPolicy(policy)
+
+	r.cfg.Bitcoin.BaseFee = cfg.Bitcoin.BaseFee
+	r.cfg.Bitcoin.FeeRate = cfg.Bitcoin.FeeRate
+	r.cfg.Bitcoin.InboundBaseFee = cfg.Bitcoin.InboundBaseFee
+	r.cfg.Bitcoin.InboundFeeRate = cfg.Bitcoin.InboundFeeRate
+	r.cfg.Bitcoin.TimeLockDelta = cfg.Bitcoin.TimeLockDelta
+
+	return nil
+}
+
+// applyGossipRateLimit applies the rate limit of channel updates the gossiper
+// accepts.
+func (r *configReloader) applyGossipRateLimit(cfg *Config) error {
+	if cfg.Gossip.MaxChannelUpdateBurst <= 0 {
+		return fmt.Errorf("max-channel-update-burst must be positive")
+	}
+	if cfg.Gossip.ChannelUpdateInterval <= 0 {
+		return fmt.Errorf("channel-update-interval must be positive")
+	}
+
+	r.server.authGossiper.SetChannelUpdateRateLimit(
+		cfg.Gossip.ChannelUpdateInterval,
+		cfg.Gossip.MaxChannelUpdateBurst,
+	)
+
+	r.cfg.Gossip.MaxChannelUpdateBurst = cfg.Gossip.MaxChannelUpdateBurst
+	r.cfg.Gossip.ChannelUpdateInterval = cfg.Gossip.ChannelUpdateInterval
+
+	return nil
+}
+
+// applyAutopilot restarts the autopilot agent with the given config, or
+// starts or stops it if it was activated or deactivated.
+func (r *configReloader) applyAutopilot(cfg *Config) error {
+	if err := sanitizeAutopilotConfig(cfg.Autopilot); err != nil {
+		return err
+	}
+
+	atplCfg, err := initAutoPilot(
+		r.server, cfg.Autopilot, r.server.cc.MinHtlcIn,
+		r.cfg.ActiveNetParams,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := r.atplMgr.SetPilotConfig(atplCfg.PilotCfg); err != nil {
+		return err
+	}
+
+	switch {
+	case cfg.Autopilot.Active && !r.atplMgr.IsActive():
+		err = r.atplMgr.StartAgent()
+
+	case !cfg.Autopilot.Active && r.atplMgr.IsActive():
+		err = r.atplMgr.StopAgent()
+	}
+	if err != nil {
+		return err
+	}
+
+	r.cfg.Autopilot = cfg.Autopilot
+
+	return nil
+}
+
+// parseConfigOptions parses the config file and the given command line
+// arguments on top of the default config, the same way LoadConfig does. The
+// parsed config isn't validated. It's returned along with its flattened
+// options.
+func parseConfigOptions(args []string) (*Config, map[string]string, error) {
+	// Pre-parse the command line options to pick up an alternative config
+	// file.
+	preCfg := DefaultConfig()
+	if _, err := flags.NewParser(&preCfg, flags.Default).ParseArgs(
+		args,
+	); err != nil {
+		return nil, nil, err
+	}
+
+	configFilePath, err := resolveConfigFilePath(&preCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A missing config file is fine, just like on start up. Parsing
+	// errors are not.
+	cfg := preCfg
+	fileParser := flags.NewParser(&cfg, flags.Default)
+	err = flags.NewIniParser(fileParser).ParseFile(configFilePath)
+	if err != nil && (lnutils.ErrorAs[*flags.IniError](err) ||
+		lnutils.ErrorAs[*flags.Error](err)) {
+
+		return nil, nil, err
+	}
+
+	// The command line options take precedence.
+	flagParser := flags.NewParser(&cfg, flags.Default)
+	if _, err := flagParser.ParseArgs(args); err != nil {
+		return nil, nil, err
+	}
+
+	options, _, err := configToFlatMap(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &cfg, options, nil
+}
+
+// changedOptions returns the names of the options whose values differ between
+// the two sets of option values.
+func changedOptions(oldValues,
+	newValues map[string]string) map[string]struct{} {
+
+	changed := make(map[string]struct{})
+	for name, value := range newValues {
+		if oldValue, ok := oldValues[name]; !ok || oldValue != value {
+			changed[name] = struct{}{}
+		}
+	}
+	for name := range oldValues {
+		if _, ok := newValues[name]; !ok {
+			changed[name] = struct{}{}
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,40 @@
+package lnd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseConfigOptionsChanges tests that parsing an unchanged config again
+// yields no changed options, and that a changed option is detected.
+func TestParseConfigOptionsChanges(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), "lnd.conf")
+	writeConfig := func(content string) {
+		err := os.WriteFile(configFile, []byte(content), 0600)
+		require.NoError(t, err)
+	}
+	args := []string{"--configfile=" + configFile, "--alias=reload"}
+
+	writeConfig("[Application Options]\ndebuglevel=info\n")
+	_, loaded, err := parseConfigOptions(args)
+	require.NoError(t, err)
+
+	_, options, err := parseConfigOptions(args)
+	require.NoError(t, err)
+	require.Empty(t, changedOptions(loaded, options))
+
+	writeConfig("[Application Options]\ndebuglevel=debug\n")
+	cfg, options, err := parseConfigOptions(args)
+	require.NoError(t, err)
+	require.Equal(t, "debug", cfg.DebugLevel)
+	require.Equal(t, "reload", cfg.Alias)
+	require.Equal(
+		t, map[string]struct{}{"debuglevel": {}},
+		changedOptions(loaded, options),
+	)
+}
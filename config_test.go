@@ -1,7 +1,10 @@
 package lnd
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/lightningnetwork/lnd/chainreg"
@@ -53,6 +56,30 @@ func TestConfigToFlatMap(t *testing.T) {
 	require.Equal(t, redactedPassword, result["db.postgres.dsn"])
 }
 
+// TestPrintEffectiveConfig tests that the effective config is printed sorted,
+// redacted and with deprecated options marked.
+func TestPrintEffectiveConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BtcdMode.RPCPass = testPassword
+	cfg.Bitcoin.Active = true
+
+	var b bytes.Buffer
+	require.NoError(t, printEffectiveConfig(&b, &cfg))
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	var keys []string
+	for _, line := range lines {
+		if !strings.HasPrefix(line, ";") {
+			keys = append(keys, strings.SplitN(line, "=", 2)[0])
+		}
+	}
+	require.True(t, sort.StringsAreSorted(keys))
+	require.Contains(t, lines, "btcd.rpcpass="+redactedPassword)
+	require.Contains(t, lines, "; bitcoin.active is deprecated")
+	require.Contains(t, lines, "lnddir="+DefaultLndDir)
+	require.NotContains(t, b.String(), testPassword)
+}
+
 // TestSupplyEnvValue tests that the supplyEnvValue function works as
 // expected on the passed inputs.
 func TestSupplyEnvValue(t *testing.T) {
@@ -115,3 +142,25 @@ func TestSupplyEnvValue(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateInboundFee tests that positive default inbound fees are only
+// accepted if accept-positive-inbound-fees is set.
+func TestValidateInboundFee(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+
+	cfg.Bitcoin.InboundBaseFee = -100
+	cfg.Bitcoin.InboundFeeRate = -10
+	require.NoError(t, validateInboundFee(&cfg))
+
+	cfg.Bitcoin.InboundBaseFee = 100
+	require.ErrorContains(t, validateInboundFee(&cfg), "inbound base fee")
+
+	cfg.Bitcoin.InboundBaseFee = 0
+	cfg.Bitcoin.InboundFeeRate = 10
+	require.ErrorContains(t, validateInboundFee(&cfg), "inbound fee rate")
+
+	cfg.AcceptPositiveInboundFees = true
+	require.NoError(t, validateInboundFee(&cfg))
+}
@@ -0,0 +1,281 @@
+package lnd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/feature"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tor"
+)
+
+const (
+	// TransportClearnet is the transport of IPv4 and IPv6 addresses.
+	TransportClearnet = "clearnet"
+
+	// TransportTor is the transport of onion addresses.
+	TransportTor = "tor"
+
+	// stageResolve is the stage of parsing and resolving an address, which
+	// precedes the stages of brontide.Dial.
+	stageResolve = "resolve"
+
+	// stageInit is the stage of exchanging init messages, which follows
+	// the stages of brontide.Dial.
+	stageInit = "init"
+
+	// initRejectTimeout is how long we wait after exchanging init
+	// messages for the peer to reject our features by sending an error
+	// or closing the connection.
+	initRejectTimeout = 2 * time.Second
+)
+
+// addressTransport returns the transport an address is reached over.
+func addressTransport(addr net.Addr) string {
+	if _, ok := addr.(*tor.OnionAddr); ok {
+		return TransportTor
+	}
+
+	return TransportClearnet
+}
+
+// handshakeFailureDetail returns the likely cause of a failure in the given
+// stage of brontide.Dial.
+func handshakeFailureDetail(stage brontide.HandshakeStage) string {
+	switch stage {
+	case brontide.StageConnect:
+		return "unable to establish a TCP connection, the node is " +
+			"offline, the address is outdated or a firewall " +
+			"blocks the port"
+
+	case brontide.StageActOne:
+		return "the connection was closed before the handshake started"
+
+	case brontide.StageActTwo:
+		return "the node didn't complete the handshake, the " +
+			"address likely belongs to a node with a different " +
+			"public key or the port isn't a Lightning port"
+
+	case brontide.StageActThree:
+		return "the connection was closed at the end of the handshake"
+
+	default:
+		return "the handshake failed"
+	}
+}
+
+// diagnoseInit exchanges init messages over the connection and checks that
+// the features of both sides are compatible.
+func diagnoseInit(conn *brontide.Conn, localInit *lnwire.Init,
+	timeout time.Duration) (string, error) {
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return "unable to exchange init messages", err
+	}
+
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, localInit, 0); err != nil {
+		return "unable to exchange init messages", err
+	}
+	if _, err := conn.Write(b.Bytes()); err != nil {
+		return "unable to send our init message", err
+	}
+
+	// readMsg reads the next message from the connection.
+	readMsg := func() (lnwire.Message, error) {
+		rawMsg, err := conn.ReadNextMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		return lnwire.ReadMessage(bytes.NewReader(rawMsg), 0)
+	}
+
+	msg, err := readMsg()
+	if err != nil {
+		return "no init message received from the node", err
+	}
+
+	var remoteInit *lnwire.Init
+	switch m := msg.(type) {
+	case *lnwire.Init:
+		remoteInit = m
+
+	case *lnwire.Error:
+		return "the node sent an error instead of its init message",
+			errors.New(m.Error())
+
+	case *lnwire.Warning:
+		return "the node sent a warning instead of its init message",
+			errors.New(m.Warning())
+
+	default:
+		return "unexpected message instead of init message",
+			fmt.Errorf("received %v", m.MsgType())
+	}
+
+	// Check the features of the node as we would for a peer.
+	err = remoteInit.Features.Merge(remoteInit.GlobalFeatures)
+	if err != nil {
+		return "the init message of the node is invalid", err
+	}
+	remoteFeatures := lnwire.NewFeatureVector(
+		remoteInit.Features, lnwire.Features,
+	)
+	if err := feature.ValidateRequired(remoteFeatures); err != nil {
+		return "the node requires features we don't support", err
+	}
+	if err := feature.ValidateDeps(remoteFeatures); err != nil {
+		return "the node advertises inconsistent features", err
+	}
+
+	// The node rejects our features by sending an error or by closing
+	// the connection right after receiving our init message. Any other
+	// message or no message at all means it accepted them.
+	err = conn.SetReadDeadline(time.Now().Add(initRejectTimeout))
+	if err != nil {
+		return "unable to exchange init messages", err
+	}
+	msg, err = readMsg()
+	switch {
+	case err != nil:
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return "", nil
+		}
+
+		return "the node closed the connection after receiving " +
+			"our init message, it likely doesn't support our " +
+			"required features", err
+
+	case msg.MsgType() == lnwire.MsgError:
+		return "the node rejected our init message",
+			errors.New(msg.(*lnwire.Error).Error())
+	}
+
+	return "", nil
+}
+
+// diagnoseAddress attempts to connect to the node at the given address and
+// reports the stage the attempt failed in. If handshake is false, only the
+// TCP connection is established.
+func diagnoseAddress(local keychain.SingleKeyECDH, addr *lnwire.NetAddress,
+	localInit *lnwire.Init, timeout time.Duration, dialer tor.DialFunc,
+	handshake bool) *lnrpc.AddressDiagnosis {
+
+	diag := &lnrpc.AddressDiagnosis{
+		Address:   addr.Address.String(),
+		Transport: addressTransport(addr.Address),
+	}
+
+	start := time.Now()
+	defer func() {
+		diag.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	fail := func(stage, detail string, err error) *lnrpc.AddressDiagnosis {
+		diag.FailedStage = stage
+		diag.Detail = detail
+		if err != nil {
+			diag.Error = err.Error()
+		}
+
+		return diag
+	}
+
+	if !handshake {
+		conn, err := dialer("tcp", diag.Address, timeout)
+		if err != nil {
+			return fail(
+				brontide.StageConnect.String(),
+				handshakeFailureDetail(brontide.StageConnect),
+				err,
+			)
+		}
+		conn.Close()
+
+		diag.Reachable = true
+		diag.Detail = "TCP connection established, the handshake was " +
+			"skipped as the node is already connected"
+
+		return diag
+	}
+
+	conn, err := brontide.Dial(local, addr, timeout, dialer)
+	if err != nil {
+		stage := brontide.StageConnect
+		var hsErr *brontide.HandshakeError
+		if errors.As(err, &hsErr) {
+			stage = hsErr.Stage
+		}
+
+		return fail(stage.String(), handshakeFailureDetail(stage), err)
+	}
+	defer conn.Close()
+
+	detail, err := diagnoseInit(conn, localInit, timeout)
+	if err != nil {
+		return fail(stageInit, detail, err)
+	}
+
+	diag.Reachable = true
+	diag.Detail = "handshake completed and init messages exchanged"
+
+	return diag
+}
+
+// diagnoseAddresses diagnoses all addresses concurrently and returns the
+// results in the order of the addresses. Addresses that couldn't be resolved
+// are passed as errors and reported as failed in the resolve stage.
+func diagnoseAddresses(addrs []string, resolve func(string) (net.Addr, error),
+	diagnose func(net.Addr) *lnrpc.AddressDiagnosis,
+	torActive bool) []*lnrpc.AddressDiagnosis {
+
+	results := make([]*lnrpc.AddressDiagnosis, len(addrs))
+
+	var wg sync.WaitGroup
+	for i, addrStr := range addrs {
+		addr, err := resolve(addrStr)
+		if err != nil {
+			results[i] = &lnrpc.AddressDiagnosis{
+				Address:     addrStr,
+				Transport:   TransportClearnet,
+				FailedStage: stageResolve,
+				Error:       err.Error(),
+				Detail:      "unable to resolve the address",
+			}
+
+			continue
+		}
+
+		// Onion addresses can only be reached through Tor.
+		if addressTransport(addr) == TransportTor && !torActive {
+			results[i] = &lnrpc.AddressDiagnosis{
+				Address:     addrStr,
+				Transport:   TransportTor,
+				FailedStage: brontide.StageConnect.String(),
+				Detail: "tor is not active, enable it with " +
+					"tor.active to reach onion addresses",
+			}
+
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, addr net.Addr) {
+			defer wg.Done()
+
+			results[i] = diagnose(addr)
+		}(i, addr)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,197 @@
+package lnd
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/stretchr/testify/require"
+)
+
+// startDiagnosisNode starts a listener that acts as a node, sending an init
+// message with the given features to each connection and then calling
+// respond with the connection after receiving the init message of the
+// dialer.
+func startDiagnosisNode(t *testing.T, features *lnwire.RawFeatureVector,
+	respond func(net.Conn)) *lnwire.NetAddress {
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	listener, err := brontide.NewListener(
+		&keychain.PrivKeyECDH{PrivKey: priv}, "localhost:0",
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			var b bytes.Buffer
+			_, err = lnwire.WriteMessage(&b, lnwire.NewInitMessage(
+				lnwire.NewRawFeatureVector(), features,
+			), 0)
+			if err == nil {
+				_, err = conn.Write(b.Bytes())
+			}
+			if err == nil {
+				_, err = conn.(*brontide.Conn).ReadNextMessage()
+			}
+			if err != nil {
+				conn.Close()
+				continue
+			}
+
+			respond(conn)
+		}
+	}()
+
+	return &lnwire.NetAddress{
+		IdentityKey: priv.PubKey(),
+		Address:     listener.Addr(),
+	}
+}
+
+// TestDiagnoseAddress tests that connection attempts report the stage they
+// failed in.
+func TestDiagnoseAddress(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	local := &keychain.PrivKeyECDH{PrivKey: priv}
+
+	localInit := lnwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(),
+		lnwire.NewRawFeatureVector(lnwire.DataLossProtectRequired),
+	)
+
+	diagnose := func(addr *lnwire.NetAddress,
+		handshake bool) *lnrpc.AddressDiagnosis {
+
+		return diagnoseAddress(
+			local, addr, localInit, 5*time.Second, net.DialTimeout,
+			handshake,
+		)
+	}
+
+	// sendPing sends a ping, which shows that the node accepted our init
+	// message.
+	sendPing := func(conn net.Conn) {
+		var b bytes.Buffer
+		_, err := lnwire.WriteMessage(&b, lnwire.NewPing(0), 0)
+		if err == nil {
+			_, _ = conn.Write(b.Bytes())
+		}
+	}
+
+	// A node that accepts our init message is reachable.
+	addr := startDiagnosisNode(
+		t, lnwire.NewRawFeatureVector(lnwire.DataLossProtectRequired),
+		sendPing,
+	)
+	diag := diagnose(addr, true)
+	require.True(t, diag.Reachable, diag.Detail)
+	require.Empty(t, diag.FailedStage)
+	require.Equal(t, TransportClearnet, diag.Transport)
+
+	// If we are already connected, only the TCP connection is attempted.
+	diag = diagnose(addr, false)
+	require.True(t, diag.Reachable, diag.Detail)
+
+	// An address of a node with a different key fails the handshake.
+	wrongKey := &lnwire.NetAddress{
+		IdentityKey: priv.PubKey(),
+		Address:     addr.Address,
+	}
+	diag = diagnose(wrongKey, true)
+	require.False(t, diag.Reachable)
+	require.Equal(t, brontide.StageActTwo.String(), diag.FailedStage)
+
+	// A node requiring an unknown feature fails the init stage.
+	addr = startDiagnosisNode(
+		t, lnwire.NewRawFeatureVector(lnwire.FeatureBit(1000)),
+		sendPing,
+	)
+	diag = diagnose(addr, true)
+	require.False(t, diag.Reachable)
+	require.Equal(t, stageInit, diag.FailedStage)
+	require.NotEmpty(t, diag.Error)
+
+	// A node that closes the connection after our init message rejected
+	// our features.
+	addr = startDiagnosisNode(
+		t, lnwire.NewRawFeatureVector(), func(conn net.Conn) {
+			conn.Close()
+		},
+	)
+	diag = diagnose(addr, true)
+	require.False(t, diag.Reachable)
+	require.Equal(t, stageInit, diag.FailedStage)
+	require.Contains(t, diag.Detail, "closed the connection")
+}
+
+// TestDiagnoseAddresses tests that addresses which can't be resolved or
+// reached through the active transports are reported without an attempt.
+func TestDiagnoseAddresses(t *testing.T) {
+	t.Parallel()
+
+	onion := &tor.OnionAddr{
+		OnionService: "3g2upl4pq6kufc4m.onion",
+		Port:         9735,
+	}
+	tcp := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9735}
+
+	resolve := func(addr string) (net.Addr, error) {
+		switch addr {
+		case onion.String():
+			return onion, nil
+
+		case tcp.String():
+			return tcp, nil
+
+		default:
+			return nil, errors.New("no such host")
+		}
+	}
+
+	var attempted []net.Addr
+	diagnose := func(addr net.Addr) *lnrpc.AddressDiagnosis {
+		attempted = append(attempted, addr)
+
+		return &lnrpc.AddressDiagnosis{
+			Address:   addr.String(),
+			Transport: addressTransport(addr),
+			Reachable: true,
+		}
+	}
+
+	addrs := []string{"unknown:9735", onion.String(), tcp.String()}
+	results := diagnoseAddresses(addrs, resolve, diagnose, false)
+	require.Len(t, results, 3)
+
+	require.Equal(t, stageResolve, results[0].FailedStage)
+	require.Equal(t, "no such host", results[0].Error)
+
+	require.Equal(t, TransportTor, results[1].Transport)
+	require.Equal(
+		t, brontide.StageConnect.String(), results[1].FailedStage,
+	)
+
+	require.True(t, results[2].Reachable)
+	require.Equal(t, []net.Addr{tcp}, attempted)
+}
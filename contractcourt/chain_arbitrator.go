@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -217,6 +218,10 @@ type ChainArbitratorConfig struct {
 	// meanwhile, turn `PaymentCircuit` into an interface or bring it to a
 	// lower package.
 	QueryIncomingCircuit func(circuit models.CircuitKey) *models.CircuitKey
+
+	// FetchNodeAlias is an optional function that returns the alias of
+	// the given node, which is added to the labels of close transactions.
+	FetchNodeAlias func(*btcec.PublicKey) (string, error)
 }
 
 // ChainArbitrator is a sub-system that oversees the on-chain resolution of all
@@ -373,6 +378,7 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 		ChanPoint:   chanPoint,
 		Channel:     c.getArbChannel(channel),
 		ShortChanID: channel.ShortChanID(),
+		PeerAlias:   c.peerAlias(channel),
 
 		MarkCommitmentBroadcasted: channel.MarkCommitmentBroadcasted,
 		MarkChannelClosed: func(summary *channeldb.ChannelCloseSummary,
@@ -395,6 +401,10 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 				tx, c.cfg.ChainHash, &chanPoint, report,
 			)
 		},
+		PutCloseReason: func(reason *channeldb.ChannelCloseReason) error {
+			chanStateDB := c.chanSource.ChannelStateDB()
+			return chanStateDB.PutCloseReason(&chanPoint, reason)
+		},
 		FetchHistoricalChannel: func() (*channeldb.OpenChannel, error) {
 			chanStateDB := c.chanSource.ChannelStateDB()
 			return chanStateDB.FetchHistoricalChannel(&chanPoint)
@@ -620,6 +630,10 @@ func (c *ChainArbitrator) Start() error {
 					tx, c.cfg.ChainHash, &chanPoint, report,
 				)
 			},
+			PutCloseReason: func(reason *channeldb.ChannelCloseReason) error {
+				chanStateDB := c.chanSource.ChannelStateDB()
+				return chanStateDB.PutCloseReason(&chanPoint, reason)
+			},
 			FetchHistoricalChannel: func() (*channeldb.OpenChannel, error) {
 				chanStateDB := c.chanSource.ChannelStateDB()
 				return chanStateDB.FetchHistoricalChannel(&chanPoint)
@@ -891,6 +905,24 @@ func (c *ChainArbitrator) republishClosingTxs(
 	return nil
 }
 
+// peerAlias returns the alias of the peer of the given channel, or an empty
+// string if it isn't known.
+func (c *ChainArbitrator) peerAlias(channel *channeldb.OpenChannel) string {
+	if c.cfg.FetchNodeAlias == nil {
+		return ""
+	}
+
+	alias, err := c.cfg.FetchNodeAlias(channel.IdentityPub)
+	if err != nil {
+		log.Debugf("Unable to fetch alias of peer %x: %v",
+			channel.IdentityPub.SerializeCompressed(), err)
+
+		return ""
+	}
+
+	return alias
+}
+
 // rebroadcast is a helper method which will republish the unilateral or
 // cooperative close transaction or a channel in a particular state.
 //
@@ -934,8 +966,9 @@ func (c *ChainArbitrator) rebroadcast(channel *channeldb.OpenChannel,
 	log.Infof("Re-publishing %s close tx(%v) for channel %v",
 		kind, closeTx.TxHash(), chanPoint)
 
-	label := labels.MakeLabel(
+	label := labels.MakeChannelLabel(
 		labels.LabelTypeChannelClose, &channel.ShortChannelID,
+		chanPoint, c.peerAlias(channel),
 	)
 	err = c.cfg.PublishTx(closeTx, label)
 	if err != nil && err != lnwallet.ErrDoubleSpend {
@@ -116,6 +116,11 @@ type ChannelArbitratorConfig struct {
 	// to the switch during contract resolution.
 	ShortChanID lnwire.ShortChannelID
 
+	// PeerAlias is the alias of the channel peer, which is added to the
+	// label of our commitment transaction. It is empty if the alias isn't
+	// known.
+	PeerAlias string
+
 	// ChainEvents is an active subscription to the chain watcher for this
 	// channel to be notified of any on-chain activity related to this
 	// channel.
@@ -160,6 +165,10 @@ type ChannelArbitratorConfig struct {
 	PutResolverReport func(tx kvdb.RwTx,
 		report *channeldb.ResolverReport) error
 
+	// PutCloseReason records who initiated the close of the channel and
+	// why. Only the first reason recorded for a channel is kept.
+	PutCloseReason func(reason *channeldb.ChannelCloseReason) error
+
 	// FetchHistoricalChannel retrieves the historical state of a channel.
 	// This is mostly used to supplement the ContractResolvers with
 	// additional information required for proper contract resolution.
@@ -901,6 +910,25 @@ func (t transitionTrigger) String() string {
 	}
 }
 
+// recordCloseReason records who initiated the close of the channel and why.
+// The reason is informational only, so failing to record it doesn't stop the
+// close.
+func (c *ChannelArbitrator) recordCloseReason(
+	initiator channeldb.CloseInitiator, reason channeldb.CloseReason,
+	details string) {
+
+	err := c.cfg.PutCloseReason(&channeldb.ChannelCloseReason{
+		Initiator: initiator,
+		Reason:    reason,
+		Details:   details,
+		Timestamp: c.cfg.Clock.Now(),
+	})
+	if err != nil {
+		log.Errorf("ChannelArbitrator(%v): unable to record close "+
+			"reason: %v", c.cfg.ChanPoint, err)
+	}
+}
+
 // stateStep is a help method that examines our internal state, and attempts
 // the appropriate state transition if necessary. The next state we transition
 // to is returned, Additionally, if the next transition results in a commitment
@@ -972,7 +1000,20 @@ func (c *ChannelArbitrator) stateStep(
 		// next state, as we still need to broadcast the commitment
 		// transaction.
 		case chainTrigger:
-			fallthrough
+			var numHtlcs int
+			for _, htlcs := range chainActions {
+				numHtlcs += len(htlcs)
+			}
+			c.recordCloseReason(
+				channeldb.CloseInitiatorLocal,
+				channeldb.CloseReasonHtlcExpiry,
+				fmt.Sprintf("%d htlc(s) required on-chain "+
+					"action at height %d", numHtlcs,
+					triggerHeight),
+			)
+
+			nextState = StateBroadcastCommit
+
 		case userTrigger:
 			nextState = StateBroadcastCommit
 
@@ -1103,8 +1144,9 @@ func (c *ChannelArbitrator) stateStep(
 
 		// At this point, we'll now broadcast the commitment
 		// transaction itself.
-		label := labels.MakeLabel(
+		label := labels.MakeChannelLabel(
 			labels.LabelTypeChannelClose, &c.cfg.ShortChanID,
+			c.cfg.ChanPoint, c.cfg.PeerAlias,
 		)
 		if err := c.cfg.PublishTx(closeTx, label); err != nil {
 			log.Errorf("ChannelArbitrator(%v): unable to broadcast "+
@@ -2979,6 +3021,11 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 			// case we must manually re-trigger the state
 			// transition into StateContractClosed based on the
 			// close status of the channel.
+			c.recordCloseReason(
+				channeldb.CloseInitiatorRemote,
+				channeldb.CloseReasonRemoteForceClose, "",
+			)
+
 			closeSummary := &uniClosure.ChannelCloseSummary
 			err = c.cfg.MarkChannelClosed(
 				closeSummary,
@@ -3038,6 +3085,11 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 			// The channel is finally marked pending closed here as
 			// the BreachArbitrator and channel arbitrator have
 			// persisted the relevant states.
+			c.recordCloseReason(
+				channeldb.CloseInitiatorRemote,
+				channeldb.CloseReasonBreach, "",
+			)
+
 			closeSummary := &breachInfo.CloseSummary
 			err = c.cfg.MarkChannelClosed(
 				closeSummary,
@@ -214,6 +214,24 @@ type chanArbTestCtx struct {
 	breachResolutionChan chan struct{}
 
 	finalHtlcs map[uint64]bool
+
+	closeReasons chan *channeldb.ChannelCloseReason
+}
+
+// AssertCloseReason asserts that the given close reason is recorded next.
+func (c *chanArbTestCtx) AssertCloseReason(
+	initiator channeldb.CloseInitiator, reason channeldb.CloseReason) {
+
+	c.t.Helper()
+
+	select {
+	case closeReason := <-c.closeReasons:
+		require.Equal(c.t, initiator, closeReason.Initiator)
+		require.Equal(c.t, reason, closeReason.Reason)
+
+	case <-time.After(defaultTimeout):
+		c.t.Fatalf("no close reason recorded")
+	}
 }
 
 func (c *chanArbTestCtx) CleanUp() {
@@ -330,6 +348,9 @@ func createTestChannelArbitrator(t *testing.T, log ArbitratorLog,
 	chanArbCtx := &chanArbTestCtx{
 		breachSubscribed: make(chan struct{}),
 		finalHtlcs:       make(map[uint64]bool),
+		closeReasons: make(
+			chan *channeldb.ChannelCloseReason, 10,
+		),
 	}
 
 	chanPoint := wire.OutPoint{}
@@ -432,6 +453,14 @@ func createTestChannelArbitrator(t *testing.T, log ArbitratorLog,
 
 			return nil
 		},
+		PutCloseReason: func(reason *channeldb.ChannelCloseReason) error {
+			select {
+			case chanArbCtx.closeReasons <- reason:
+			default:
+			}
+
+			return nil
+		},
 		FetchHistoricalChannel: func() (*channeldb.OpenChannel, error) {
 			return &channeldb.OpenChannel{}, nil
 		},
@@ -599,6 +628,12 @@ func TestChannelArbitratorRemoteForceClose(t *testing.T) {
 		StateContractClosed, StateFullyResolved,
 	)
 
+	// The remote party should be recorded as the initiator.
+	chanArbCtx.AssertCloseReason(
+		channeldb.CloseInitiatorRemote,
+		channeldb.CloseReasonRemoteForceClose,
+	)
+
 	// It should also mark the channel as resolved.
 	select {
 	case <-chanArbCtx.resolvedChan:
@@ -788,6 +823,9 @@ func TestChannelArbitratorBreachClose(t *testing.T) {
 
 	// It should transition StateDefault -> StateContractClosed.
 	chanArbCtx.AssertStateTransitions(StateContractClosed)
+	chanArbCtx.AssertCloseReason(
+		channeldb.CloseInitiatorRemote, channeldb.CloseReasonBreach,
+	)
 
 	// We should receive one ResolutionMsg as there was only one outgoing
 	// HTLC at the time of the breach.
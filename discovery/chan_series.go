@@ -25,12 +25,14 @@ type ChannelGraphTimeSeries interface {
 	// the remote node.
 	HighestChanID(chain chainhash.Hash) (*lnwire.ShortChannelID, error)
 
-	// UpdatesInHorizon returns all known channel and node updates with an
-	// update timestamp between the start time and end time. We'll use this
-	// to catch up a remote node to the set of channel updates that they
-	// may have missed out on within the target chain.
-	UpdatesInHorizon(chain chainhash.Hash,
-		startTime time.Time, endTime time.Time) ([]lnwire.Message, error)
+	// UpdatesInHorizon calls the callback with batches of all known
+	// channel and node updates with an update timestamp between the start
+	// time and end time. We'll use this to catch up a remote node to the
+	// set of channel updates that they may have missed out on within the
+	// target chain. An error returned by the callback stops the iteration
+	// and is returned.
+	UpdatesInHorizon(chain chainhash.Hash, startTime time.Time,
+		endTime time.Time, cb func([]lnwire.Message) error) error
 
 	// FilterKnownChanIDs takes a target chain, and a set of channel ID's,
 	// and returns a filtered set of chan ID's. This filtered set of chan
@@ -64,6 +66,10 @@ type ChannelGraphTimeSeries interface {
 		shortChanID lnwire.ShortChannelID) ([]*lnwire.ChannelUpdate, error)
 }
 
+// horizonBatchSize is the number of channels or nodes that are read from the
+// graph at once when serving the updates within a peer's gossip horizon.
+const horizonBatchSize = 500
+
 // ChanSeries is an implementation of the ChannelGraphTimeSeries
 // interface backed by the channeldb ChannelGraph database. We'll provide this
 // implementation to the AuthenticatedGossiper so it can properly use the
@@ -97,26 +103,67 @@ func (c *ChanSeries) HighestChanID(chain chainhash.Hash) (*lnwire.ShortChannelID
 	return &shortChanID, nil
 }
 
-// UpdatesInHorizon returns all known channel and node updates with an update
-// timestamp between the start time and end time. We'll use this to catch up a
-// remote node to the set of channel updates that they may have missed out on
-// within the target chain.
+// UpdatesInHorizon calls the callback with batches of all known channel and
+// node updates with an update timestamp between the start time and end time.
+// The updates are read from the update time indexes of the graph one batch at
+// a time, so serving a wide horizon neither scans the full set of edges nor
+// holds all of its updates in memory. We'll use this to catch up a remote
+// node to the set of channel updates that they may have missed out on within
+// the target chain.
 //
 // NOTE: This is part of the ChannelGraphTimeSeries interface.
-func (c *ChanSeries) UpdatesInHorizon(chain chainhash.Hash,
-	startTime time.Time, endTime time.Time) ([]lnwire.Message, error) {
-
-	var updates []lnwire.Message
+func (c *ChanSeries) UpdatesInHorizon(_ chainhash.Hash, startTime time.Time,
+	endTime time.Time, cb func([]lnwire.Message) error) error {
+
+	// First, we'll send out the channels that have an update that falls
+	// within the specified horizon.
+	err := c.graph.ForEachChanUpdateInHorizon(
+		startTime, endTime, horizonBatchSize,
+		func(channels []channeldb.ChannelEdge) error {
+			updates, err := chanEdgeUpdates(channels)
+			if err != nil {
+				return err
+			}
+			if len(updates) == 0 {
+				return nil
+			}
 
-	// First, we'll query for all the set of channels that have an update
-	// that falls within the specified horizon.
-	chansInHorizon, err := c.graph.ChanUpdatesInHorizon(
-		startTime, endTime,
+			return cb(updates)
+		},
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for _, channel := range chansInHorizon {
+
+	// Next, we'll send out all the node announcements that have an update
+	// within the horizon as well. We send these second to ensure that they
+	// follow any active channels they have. We only forward nodes that are
+	// publicly advertised to prevent leaking information about nodes.
+	return c.graph.ForEachNodeUpdateInHorizon(
+		startTime, endTime, true, horizonBatchSize,
+		func(nodes []channeldb.LightningNode) error {
+			updates := make([]lnwire.Message, 0, len(nodes))
+			for _, node := range nodes {
+				nodeUpdate, err := node.NodeAnnouncement(true)
+				if err != nil {
+					return err
+				}
+
+				updates = append(updates, nodeUpdate)
+			}
+
+			return cb(updates)
+		},
+	)
+}
+
+// chanEdgeUpdates returns the channel announcements and the valid channel
+// updates of the given channels.
+func chanEdgeUpdates(channels []channeldb.ChannelEdge) ([]lnwire.Message,
+	error) {
+
+	updates := make([]lnwire.Message, 0, len(channels)*3)
+	for _, channel := range channels {
 		// If the channel hasn't been fully advertised yet, or is a
 		// private channel, then we'll skip it as we can't construct a
 		// full authentication proof if one is requested.
@@ -124,7 +171,7 @@ func (c *ChanSeries) UpdatesInHorizon(chain chainhash.Hash,
 			continue
 		}
 
-		chanAnn, edge1, edge2, err := netann.CreateChanAnnouncement(
+		chanAnn, edge1, edge2, err := netann.CreateChanAnnouncementMsg(
 			channel.Info.AuthProof, channel.Info, channel.Policy1,
 			channel.Policy2,
 		)
@@ -155,42 +202,6 @@ func (c *ChanSeries) UpdatesInHorizon(chain chainhash.Hash,
 		}
 	}
 
-	// Next, we'll send out all the node announcements that have an update
-	// within the horizon as well. We send these second to ensure that they
-	// follow any active channels they have.
-	nodeAnnsInHorizon, err := c.graph.NodeUpdatesInHorizon(
-		startTime, endTime,
-	)
-	if err != nil {
-		return nil, err
-	}
-	for _, nodeAnn := range nodeAnnsInHorizon {
-		nodeAnn := nodeAnn
-
-		// Ensure we only forward nodes that are publicly advertised to
-		// prevent leaking information about nodes.
-		isNodePublic, err := c.graph.IsPublicNode(nodeAnn.PubKeyBytes)
-		if err != nil {
-			log.Errorf("Unable to determine if node %x is "+
-				"advertised: %v", nodeAnn.PubKeyBytes, err)
-			continue
-		}
-
-		if !isNodePublic {
-			log.Tracef("Skipping forwarding announcement for "+
-				"node %x due to being unadvertised",
-				nodeAnn.PubKeyBytes)
-			continue
-		}
-
-		nodeUpdate, err := nodeAnn.NodeAnnouncement(true)
-		if err != nil {
-			return nil, err
-		}
-
-		updates = append(updates, nodeUpdate)
-	}
-
 	return updates, nil
 }
 
@@ -268,7 +279,7 @@ func (c *ChanSeries) FetchChanAnns(chain chainhash.Hash,
 			continue
 		}
 
-		chanAnn, edge1, edge2, err := netann.CreateChanAnnouncement(
+		chanAnn, edge1, edge2, err := netann.CreateChanAnnouncementMsg(
 			channel.Info.AuthProof, channel.Info, channel.Policy1,
 			channel.Policy2,
 		)
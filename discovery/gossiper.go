@@ -20,12 +20,14 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fault"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
 	"github.com/lightningnetwork/lnd/multimutex"
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/routing"
@@ -311,6 +313,29 @@ type Config struct {
 	// direction.
 	ChannelUpdateInterval time.Duration
 
+	// MaxLocalChannelUpdateBurst specifies the maximum number of our own
+	// updates for a specific channel and direction that we broadcast over
+	// the local channel update interval.
+	MaxLocalChannelUpdateBurst int
+
+	// LocalChannelUpdateInterval specifies the interval we'll use to
+	// determine how often we broadcast another one of our own updates for
+	// a specific channel and direction. Updates exceeding the rate are
+	// held back until it allows broadcasting them. If it isn't positive,
+	// our own updates aren't rate limited.
+	LocalChannelUpdateInterval time.Duration
+
+	// ThrottleStatusUpdates indicates whether our own updates that disable
+	// or enable a channel are rate limited as well. Otherwise they're
+	// broadcast right away, so that the network learns about unusable
+	// channels without delay.
+	ThrottleStatusUpdates bool
+
+	// NumSigWorkers is the maximum number of gossip message signatures
+	// that are verified concurrently. If it isn't positive, the number of
+	// CPUs is used.
+	NumSigWorkers int
+
 	// IsAlias returns true if a given ShortChannelID is an alias for
 	// option_scid_alias channels.
 	IsAlias func(scid lnwire.ShortChannelID) bool
@@ -340,6 +365,10 @@ type Config struct {
 	// updates for a channel and returns true if the channel should be
 	// considered a zombie based on these timestamps.
 	IsStillZombieChannel func(time.Time, time.Time) bool
+
+	// FaultInjector is used in dev builds to drop announcement batches on
+	// demand. It is nil otherwise.
+	FaultInjector *fault.Injector
 }
 
 // processedNetworkMsg is a wrapper around networkMsg and a boolean. It is
@@ -490,6 +519,14 @@ type AuthenticatedGossiper struct {
 	// AuthenticatedGossiper lock.
 	chanUpdateRateLimiter map[uint64][2]*rate.Limiter
 
+	// sigVerifier bounds the number of concurrent signature verifications
+	// of incoming gossip messages and skips the ones already verified.
+	sigVerifier *sigVerifier
+
+	// localUpdateThrottle limits the rate at which our own channel updates
+	// are broadcast.
+	localUpdateThrottle *localUpdateThrottle
+
 	sync.Mutex
 }
 
@@ -512,8 +549,17 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 			maxRejectedUpdates,
 		),
 		chanUpdateRateLimiter: make(map[uint64][2]*rate.Limiter),
+		localUpdateThrottle: newLocalUpdateThrottle(
+			cfg.LocalChannelUpdateInterval,
+			cfg.MaxLocalChannelUpdateBurst,
+			cfg.ThrottleStatusUpdates,
+		),
 	}
 
+	gossiper.sigVerifier = newSigVerifier(
+		cfg.NumSigWorkers, gossiper.quit,
+	)
+
 	gossiper.syncMgr = newSyncManager(&SyncManagerCfg{
 		ChainHash:               cfg.ChainHash,
 		ChanSeries:              cfg.ChanSeries,
@@ -546,6 +592,20 @@ type EdgeWithInfo struct {
 	Edge *models.ChannelEdgePolicy
 }
 
+// SetChannelUpdateRateLimit changes the number of updates for a channel and
+// direction the gossiper accepts over the given interval. The rate limits of
+// all channels are reset, so every channel starts with a full burst.
+func (d *AuthenticatedGossiper) SetChannelUpdateRateLimit(
+	interval time.Duration, burst int) {
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.cfg.ChannelUpdateInterval = interval
+	d.cfg.MaxChannelUpdateBurst = burst
+	d.chanUpdateRateLimiter = make(map[uint64][2]*rate.Limiter)
+}
+
 // PropagateChanPolicyUpdate signals the AuthenticatedGossiper to perform the
 // specified edge updates. Updates are done in two stages: first, the
 // AuthenticatedGossiper ensures the update has been committed by dependent
@@ -816,17 +876,10 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 		}
 
 		// If we've found the message target, then we'll dispatch the
-		// message directly to it.
-		if err := syncer.ApplyGossipFilter(m); err != nil {
-			log.Warnf("Unable to apply gossip filter for peer=%x: "+
-				"%v", peer.PubKey(), err)
-
-			errChan <- err
-			return errChan
-		}
-
-		errChan <- nil
-		return errChan
+		// message directly to it. The backlog within the new horizon
+		// is sent in the background, so we hand the result of sending
+		// it back to the caller.
+		return syncer.ApplyGossipFilter(m)
 
 	// To avoid inserting edges in the graph for our own channels that we
 	// have already closed, we ignore such channel announcements coming
@@ -839,6 +892,19 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 		if bytes.Equal(m.NodeID1[:], ownKey) ||
 			bytes.Equal(m.NodeID2[:], ownKey) {
 
+			log.Warn(ownErr)
+			errChan <- ownErr
+			return errChan
+		}
+
+	case *lnwire.ChannelAnnouncement2:
+		ownKey := d.selfKey.SerializeCompressed()
+		ownErr := fmt.Errorf("ignoring remote ChannelAnnouncement2 " +
+			"for own channel")
+
+		if bytes.Equal(m.NodeID1.Val[:], ownKey) ||
+			bytes.Equal(m.NodeID2.Val[:], ownKey) {
+
 			log.Warn(ownErr)
 			errChan <- ownErr
 			return errChan
@@ -897,6 +963,17 @@ func (d *AuthenticatedGossiper) ProcessLocalAnnouncement(msg lnwire.Message,
 	return nMsg.err
 }
 
+// SendCustomMessageReliably persists the custom message before attempting to
+// send it to the peer with the given public key. If the peer is offline, or we
+// shut down before the message was sent, it'll be sent once the peer
+// reconnects, including across restarts. Queueing a message that is identical
+// to one that hasn't been sent yet has no effect.
+func (d *AuthenticatedGossiper) SendCustomMessageReliably(peerPub [33]byte,
+	msg *lnwire.Custom) error {
+
+	return d.reliableSender.sendMessage(msg, peerPub)
+}
+
 // channelUpdateID is a unique identifier for ChannelUpdate messages, as
 // channel updates can be identified by the (ShortChannelID, ChannelFlags)
 // tuple.
@@ -994,26 +1071,10 @@ func (d *deDupedAnnouncements) addMsg(message networkMsg) {
 
 	// Channel announcements are identified by the short channel id field.
 	case *lnwire.ChannelAnnouncement:
-		deDupKey := msg.ShortChannelID
-		sender := route.NewVertex(message.source)
-
-		mws, ok := d.channelAnnouncements[deDupKey]
-		if !ok {
-			mws = msgWithSenders{
-				msg:     msg,
-				isLocal: !message.isRemote,
-				senders: make(map[route.Vertex]struct{}),
-			}
-			mws.senders[sender] = struct{}{}
+		d.addChanAnnouncement(msg.ShortChannelID, message)
 
-			d.channelAnnouncements[deDupKey] = mws
-
-			return
-		}
-
-		mws.msg = msg
-		mws.senders[sender] = struct{}{}
-		d.channelAnnouncements[deDupKey] = mws
+	case *lnwire.ChannelAnnouncement2:
+		d.addChanAnnouncement(msg.ShortChannelID.Val, message)
 
 	// Channel updates are identified by the (short channel id,
 	// channelflags) tuple.
@@ -1108,6 +1169,32 @@ func (d *deDupedAnnouncements) addMsg(message networkMsg) {
 	}
 }
 
+// addChanAnnouncement adds a new channel announcement of the channel with the
+// given short channel id to the current batch.
+func (d *deDupedAnnouncements) addChanAnnouncement(
+	deDupKey lnwire.ShortChannelID, message networkMsg) {
+
+	sender := route.NewVertex(message.source)
+
+	mws, ok := d.channelAnnouncements[deDupKey]
+	if !ok {
+		mws = msgWithSenders{
+			msg:     message.msg,
+			isLocal: !message.isRemote,
+			senders: make(map[route.Vertex]struct{}),
+		}
+		mws.senders[sender] = struct{}{}
+
+		d.channelAnnouncements[deDupKey] = mws
+
+		return
+	}
+
+	mws.msg = message.msg
+	mws.senders[sender] = struct{}{}
+	d.channelAnnouncements[deDupKey] = mws
+}
+
 // AddMsgs is a helper method to add multiple messages to the announcement
 // batch.
 func (d *deDupedAnnouncements) AddMsgs(msgs ...networkMsg) {
@@ -1445,12 +1532,26 @@ func (d *AuthenticatedGossiper) networkHandler() {
 			// deDupedAnnouncements.
 			announcementBatch := announcements.Emit()
 
+			// Hold back our own channel updates that exceed the
+			// local update rate, and release those that were held
+			// back before if the rate allows it now.
+			throttle := d.localUpdateThrottle
+			announcementBatch.localMsgs = throttle.filter(
+				announcementBatch.localMsgs, time.Now(),
+			)
+
 			// If the current announcements batch is nil, then we
 			// have no further work here.
 			if announcementBatch.isEmpty() {
 				continue
 			}
 
+			// In dev builds, dropping this batch may have been
+			// requested to reproduce propagation issues.
+			if d.cfg.FaultInjector.Trigger(fault.GossipBatch) {
+				continue
+			}
+
 			// At this point, we have the set of local and remote
 			// announcements we want to send out. We'll do the
 			// batching as normal for both, but for local
@@ -1570,6 +1671,9 @@ func (d *AuthenticatedGossiper) isRecentlyRejectedMsg(msg lnwire.Message,
 	case *lnwire.ChannelAnnouncement:
 		scid = m.ShortChannelID.ToUint64()
 
+	case *lnwire.ChannelAnnouncement2:
+		scid = m.ShortChannelID.Val.ToUint64()
+
 	default:
 		return false
 	}
@@ -1860,7 +1964,7 @@ func (d *AuthenticatedGossiper) processRejectedEdge(
 	if err != nil {
 		return nil, err
 	}
-	err = routing.ValidateChannelAnn(chanAnn)
+	err = d.sigVerifier.validateChannelAnn(chanAnn)
 	if err != nil {
 		err := fmt.Errorf("assembled channel announcement proof "+
 			"for shortChanID=%v isn't valid: %v",
@@ -1909,7 +2013,7 @@ func (d *AuthenticatedGossiper) processRejectedEdge(
 func (d *AuthenticatedGossiper) addNode(msg *lnwire.NodeAnnouncement,
 	op ...batch.SchedulerOption) error {
 
-	if err := routing.ValidateNodeAnn(msg); err != nil {
+	if err := d.sigVerifier.validateNodeAnn(msg); err != nil {
 		return fmt.Errorf("unable to validate node announcement: %w",
 			err)
 	}
@@ -2014,6 +2118,11 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 	case *lnwire.ChannelAnnouncement:
 		return d.handleChanAnnouncement(nMsg, msg, schedulerOp)
 
+	// A new taproot channel announcement has arrived, which is handled
+	// just like a legacy channel announcement.
+	case *lnwire.ChannelAnnouncement2:
+		return d.handleChanAnnouncement2(nMsg, msg, schedulerOp)
+
 	// A new authenticated channel edge update has arrived. This indicates
 	// that the directional information for an already known channel has
 	// been updated.
@@ -2063,7 +2172,7 @@ func (d *AuthenticatedGossiper) processZombieUpdate(
 			"with chan_id=%v", msg.ShortChannelID)
 	}
 
-	err := routing.VerifyChannelUpdateSignature(msg, pubKey)
+	err := d.sigVerifier.verifyChannelUpdateSignature(msg, pubKey)
 	if err != nil {
 		return fmt.Errorf("unable to verify channel "+
 			"update signature: %v", err)
@@ -2167,6 +2276,11 @@ func (d *AuthenticatedGossiper) isMsgStale(msg lnwire.Message) bool {
 		timestamp := time.Unix(int64(msg.Timestamp), 0)
 		return p.LastUpdate.After(timestamp)
 
+	// Custom messages only need to be delivered once, so they're stale
+	// as soon as they've been sent.
+	case *lnwire.Custom:
+		return true
+
 	default:
 		// We'll make sure to not mark any unsupported messages as stale
 		// to ensure they are not removed.
@@ -2177,7 +2291,7 @@ func (d *AuthenticatedGossiper) isMsgStale(msg lnwire.Message) bool {
 // updateChannel creates a new fully signed update for the channel, and updates
 // the underlying graph with the new state.
 func (d *AuthenticatedGossiper) updateChannel(info *models.ChannelEdgeInfo,
-	edge *models.ChannelEdgePolicy) (*lnwire.ChannelAnnouncement,
+	edge *models.ChannelEdgePolicy) (lnwire.Message,
 	*lnwire.ChannelUpdate, error) {
 
 	// Parse the unsigned edge into a channel update.
@@ -2213,7 +2327,19 @@ func (d *AuthenticatedGossiper) updateChannel(info *models.ChannelEdgeInfo,
 
 	// We'll also create the original channel announcement so the two can
 	// be broadcast along side each other (if necessary), but only if we
-	// have a full channel announcement for this channel.
+	// have a full channel announcement for this channel. Taproot channels
+	// are announced with a ChannelAnnouncement2.
+	if info.AuthProof != nil && info.AuthProof.IsTaproot() {
+		chanAnn, _, _, err := netann.CreateChanAnnouncement2(
+			info.AuthProof, info, nil, nil,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return chanAnn, chanUpdate, nil
+	}
+
 	var chanAnn *lnwire.ChannelAnnouncement
 	if info.AuthProof != nil {
 		chanID := lnwire.NewShortChanIDFromInt(info.ChannelID)
@@ -2253,6 +2379,11 @@ func (d *AuthenticatedGossiper) updateChannel(info *models.ChannelEdgeInfo,
 		}
 	}
 
+	// Make sure a missing announcement is returned as a nil interface.
+	if chanAnn == nil {
+		return nil, chanUpdate, nil
+	}
+
 	return chanAnn, chanUpdate, err
 }
 
@@ -2456,7 +2587,7 @@ func (d *AuthenticatedGossiper) handleChanAnnouncement(nMsg *networkMsg,
 	// the signatures within the proof as it should be well formed.
 	var proof *models.ChannelAuthProof
 	if nMsg.isRemote {
-		if err := routing.ValidateChannelAnn(ann); err != nil {
+		if err := d.sigVerifier.validateChannelAnn(ann); err != nil {
 			err := fmt.Errorf("unable to validate announcement: "+
 				"%v", err)
 
@@ -2586,7 +2717,243 @@ func (d *AuthenticatedGossiper) handleChanAnnouncement(nMsg *networkMsg,
 
 	// If we earlier received any ChannelUpdates for this channel, we can
 	// now process them, as the channel is added to the graph.
-	shortChanID := ann.ShortChannelID.ToUint64()
+	d.reprocessPrematureUpdates(ann.ShortChannelID.ToUint64())
+
+	// Channel announcement was successfully processed and now it might be
+	// broadcast to other connected nodes if it was an announcement with
+	// proof (remote).
+	var announcements []networkMsg
+
+	if proof != nil {
+		announcements = append(announcements, networkMsg{
+			peer:     nMsg.peer,
+			isRemote: nMsg.isRemote,
+			source:   nMsg.source,
+			msg:      ann,
+		})
+	}
+
+	nMsg.err <- nil
+
+	log.Debugf("Processed ChannelAnnouncement: peer=%v, short_chan_id=%v",
+		nMsg.peer, ann.ShortChannelID.ToUint64())
+
+	return announcements, true
+}
+
+// handleChanAnnouncement2 processes a new ChannelAnnouncement2 that announces
+// a taproot channel. Remote announcements add a new edge to the graph, while
+// the fully signed announcement of one of our own channels adds the proof to
+// the edge we already know of.
+func (d *AuthenticatedGossiper) handleChanAnnouncement2(nMsg *networkMsg,
+	ann *lnwire.ChannelAnnouncement2,
+	ops []batch.SchedulerOption) ([]networkMsg, bool) {
+
+	scid := ann.ShortChannelID.Val
+	log.Debugf("Processing ChannelAnnouncement2: peer=%v, "+
+		"short_chan_id=%v", nMsg.peer, scid.ToUint64())
+
+	// rejectAnn adds the announcement to the reject cache and returns the
+	// given error to the caller.
+	rejectAnn := func(err error) ([]networkMsg, bool) {
+		key := newRejectCacheKey(
+			scid.ToUint64(), sourceToPub(nMsg.source),
+		)
+		_, _ = d.recentRejects.Put(key, &cachedReject{})
+
+		nMsg.err <- err
+		return nil, false
+	}
+
+	// We'll ignore any channel announcements that target any chain other
+	// than the set of chains we know of.
+	if !bytes.Equal(ann.ChainHash.Val[:], d.cfg.ChainHash[:]) {
+		err := fmt.Errorf("ignoring ChannelAnnouncement2 from "+
+			"chain=%v, gossiper on chain=%v",
+			chainhash.Hash(ann.ChainHash.Val), d.cfg.ChainHash)
+		log.Errorf(err.Error())
+
+		return rejectAnn(err)
+	}
+
+	// Just like for the legacy announcements, alias SCIDs are never
+	// accepted from remote peers.
+	if nMsg.isRemote && d.cfg.IsAlias(scid) {
+		err := fmt.Errorf("ignoring remote alias channel=%v", scid)
+		log.Errorf(err.Error())
+
+		return rejectAnn(err)
+	}
+
+	// If the advertised inclusionary block is beyond our knowledge of the
+	// chain tip, then we'll ignore it for now.
+	d.Lock()
+	if nMsg.isRemote && d.isPremature(scid, 0, nMsg) {
+		log.Warnf("Announcement for chan_id=(%v), is premature: "+
+			"advertises height %v, only height %v is known",
+			scid.ToUint64(), scid.BlockHeight, d.bestHeight)
+		d.Unlock()
+		nMsg.err <- nil
+		return nil, false
+	}
+	d.Unlock()
+
+	proof := &models.ChannelAuthProof{
+		SchnorrSigBytes: ann.Signature.RawBytes(),
+	}
+
+	// Our own channels are already known when their announcement has been
+	// assembled, as the edge is added without a proof once the channel is
+	// ready. In that case the announcement only adds the proof to the
+	// edge, while remote announcements of known channels are ignored.
+	if d.cfg.Router.IsKnownEdge(scid) {
+		if nMsg.isRemote {
+			nMsg.err <- nil
+			return nil, true
+		}
+
+		d.channelMtx.Lock(scid.ToUint64())
+		anns, err := d.addChanAnn2Proof(ann, proof)
+		d.channelMtx.Unlock(scid.ToUint64())
+		if err != nil {
+			return rejectAnn(err)
+		}
+
+		nMsg.err <- nil
+		return anns, true
+	}
+
+	if err := d.sigVerifier.validateChannelAnn2(ann); err != nil {
+		err := fmt.Errorf("unable to validate announcement: %w", err)
+		log.Error(err)
+
+		return rejectAnn(err)
+	}
+
+	var featureBuf bytes.Buffer
+	err := ann.Features.Val.RawFeatureVector.Encode(&featureBuf)
+	if err != nil {
+		log.Errorf("unable to encode features: %v", err)
+		nMsg.err <- err
+		return nil, false
+	}
+
+	edge := &models.ChannelEdgeInfo{
+		ChannelID:       scid.ToUint64(),
+		ChainHash:       ann.ChainHash.Val,
+		NodeKey1Bytes:   ann.NodeID1.Val,
+		NodeKey2Bytes:   ann.NodeID2.Val,
+		AuthProof:       proof,
+		Features:        featureBuf.Bytes(),
+		Capacity:        btcutil.Amount(ann.Capacity.Val),
+		ExtraOpaqueData: ann.ExtraOpaqueData,
+	}
+	ann.BitcoinKey1.WhenSomeV(func(key [33]byte) {
+		edge.BitcoinKey1Bytes = key
+	})
+	ann.BitcoinKey2.WhenSomeV(func(key [33]byte) {
+		edge.BitcoinKey2Bytes = key
+	})
+
+	log.Debugf("Adding edge for short_chan_id: %v", scid.ToUint64())
+
+	d.channelMtx.Lock(scid.ToUint64())
+	err = d.cfg.Router.AddEdge(edge, ops...)
+	if err != nil {
+		log.Debugf("Router rejected edge for short_chan_id(%v): %v",
+			scid.ToUint64(), err)
+
+		d.channelMtx.Unlock(scid.ToUint64())
+
+		// The edge may have been added since we checked above.
+		if routing.IsError(err, routing.ErrIgnored) {
+			nMsg.err <- nil
+			return nil, true
+		}
+
+		return rejectAnn(err)
+	}
+	d.channelMtx.Unlock(scid.ToUint64())
+
+	// If we earlier received any ChannelUpdates for this channel, we can
+	// now process them, as the channel is added to the graph.
+	d.reprocessPrematureUpdates(scid.ToUint64())
+
+	nMsg.err <- nil
+
+	log.Debugf("Processed ChannelAnnouncement2: peer=%v, "+
+		"short_chan_id=%v", nMsg.peer, scid.ToUint64())
+
+	return []networkMsg{{
+		peer:     nMsg.peer,
+		isRemote: nMsg.isRemote,
+		source:   nMsg.source,
+		msg:      ann,
+	}}, true
+}
+
+// addChanAnn2Proof adds the proof of a ChannelAnnouncement2 to an edge that is
+// already in the graph without a proof, which is the case for our own channels
+// once their announcement has been assembled. The announcement is re-created
+// from the edge to make sure the proof covers what we know of the channel.
+func (d *AuthenticatedGossiper) addChanAnn2Proof(
+	ann *lnwire.ChannelAnnouncement2,
+	proof *models.ChannelAuthProof) ([]networkMsg, error) {
+
+	scid := ann.ShortChannelID.Val
+	chanInfo, e1, e2, err := d.cfg.Router.GetChannelByID(scid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The edge already has a proof, so there's nothing new to announce.
+	if chanInfo.AuthProof != nil {
+		return nil, nil
+	}
+
+	chanAnn, e1Ann, e2Ann, err := netann.CreateChanAnnouncement2(
+		proof, chanInfo, e1, e2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.sigVerifier.validateChannelAnn2(chanAnn); err != nil {
+		err := fmt.Errorf("assembled channel announcement proof "+
+			"for shortChanID=%v isn't valid: %w", scid, err)
+		log.Error(err)
+		return nil, err
+	}
+
+	if err := d.cfg.Router.AddProof(scid, proof); err != nil {
+		err := fmt.Errorf("unable add proof to shortChanID=%v: %w",
+			scid, err)
+		log.Error(err)
+		return nil, err
+	}
+
+	announcements := []networkMsg{{
+		source: d.selfKey,
+		msg:    chanAnn,
+	}}
+	if e1Ann != nil {
+		announcements = append(announcements, networkMsg{
+			source: d.selfKey,
+			msg:    e1Ann,
+		})
+	}
+	if e2Ann != nil {
+		announcements = append(announcements, networkMsg{
+			source: d.selfKey,
+			msg:    e2Ann,
+		})
+	}
+
+	return announcements, nil
+}
+
+// reprocessPrematureUpdates re-processes the ChannelUpdates that we received
+// for the given channel before it was added to the graph.
+func (d *AuthenticatedGossiper) reprocessPrematureUpdates(shortChanID uint64) {
 	var channelUpdates []*processedNetworkMsg
 
 	earlyChanUpdates, err := d.prematureChannelUpdates.Get(shortChanID)
@@ -2639,27 +3006,6 @@ func (d *AuthenticatedGossiper) handleChanAnnouncement(nMsg *networkMsg,
 			}
 		}(cu.msg)
 	}
-
-	// Channel announcement was successfully processed and now it might be
-	// broadcast to other connected nodes if it was an announcement with
-	// proof (remote).
-	var announcements []networkMsg
-
-	if proof != nil {
-		announcements = append(announcements, networkMsg{
-			peer:     nMsg.peer,
-			isRemote: nMsg.isRemote,
-			source:   nMsg.source,
-			msg:      ann,
-		})
-	}
-
-	nMsg.err <- nil
-
-	log.Debugf("Processed ChannelAnnouncement: peer=%v, short_chan_id=%v",
-		nMsg.peer, ann.ShortChannelID.ToUint64())
-
-	return announcements, true
 }
 
 // handleChanUpdate processes a new channel update.
@@ -2861,7 +3207,9 @@ func (d *AuthenticatedGossiper) handleChanUpdate(nMsg *networkMsg,
 	// Validate the channel announcement with the expected public key and
 	// channel capacity. In the case of an invalid channel update, we'll
 	// return an error to the caller and exit early.
-	err = routing.ValidateChannelUpdateAnn(pubKey, chanInfo.Capacity, upd)
+	err = d.sigVerifier.validateChannelUpdateAnn(
+		pubKey, chanInfo.Capacity, upd,
+	)
 	if err != nil {
 		rErr := fmt.Errorf("unable to validate channel update "+
 			"announcement for short_chan_id=%v: %v",
@@ -2918,6 +3266,9 @@ func (d *AuthenticatedGossiper) handleChanUpdate(nMsg *networkMsg,
 				log.Debugf("Rate limiting update for channel "+
 					"%v from direction %x", shortChanID,
 					pubKey.SerializeCompressed())
+				metrics.IncSuppressedChannelUpdates(
+					metrics.SuppressedRateLimited,
+				)
 				nMsg.err <- nil
 				return nil, false
 			}
@@ -3267,7 +3618,7 @@ func (d *AuthenticatedGossiper) handleAnnSig(nMsg *networkMsg,
 
 	// With all the necessary components assembled validate the full
 	// channel announcement proof.
-	if err := routing.ValidateChannelAnn(chanAnn); err != nil {
+	if err := d.sigVerifier.validateChannelAnn(chanAnn); err != nil {
 		err := fmt.Errorf("channel announcement proof for "+
 			"short_chan_id=%v isn't valid: %v", shortChanID, err)
 
@@ -15,6 +15,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -35,6 +36,7 @@ import (
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/require"
 )
 
@@ -4139,3 +4141,212 @@ func TestFutureMsgCacheEviction(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, 2, item.height, "should be the second item")
 }
+
+// createChannelAnnouncement2 creates a ChannelAnnouncement2 of the channel
+// between the given node keys, signed by the node keys and the test bitcoin
+// keys.
+func createChannelAnnouncement2(t *testing.T, blockHeight uint32,
+	key1, key2 *btcec.PrivateKey) *lnwire.ChannelAnnouncement2 {
+
+	t.Helper()
+
+	nodeKey1, nodeKey2 := key1, key2
+	if bytes.Compare(
+		key1.PubKey().SerializeCompressed(),
+		key2.PubKey().SerializeCompressed(),
+	) == 1 {
+
+		nodeKey1, nodeKey2 = key2, key1
+	}
+
+	ann := lnwire.NewChannelAnnouncement2()
+	ann.ShortChannelID.Val = lnwire.ShortChannelID{
+		BlockHeight: blockHeight,
+	}
+	ann.Capacity.Val = btcutil.SatoshiPerBitcoin
+	copy(ann.NodeID1.Val[:], nodeKey1.PubKey().SerializeCompressed())
+	copy(ann.NodeID2.Val[:], nodeKey2.PubKey().SerializeCompressed())
+
+	var btcKey1, btcKey2 [33]byte
+	copy(btcKey1[:], bitcoinKeyPub1.SerializeCompressed())
+	copy(btcKey2[:], bitcoinKeyPub2.SerializeCompressed())
+	ann.BitcoinKey1 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType12](btcKey1),
+	)
+	ann.BitcoinKey2 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType14](btcKey2),
+	)
+
+	signChannelAnnouncement2(
+		t, ann, nodeKey1, nodeKey2, bitcoinKeyPriv1, bitcoinKeyPriv2,
+	)
+
+	return ann
+}
+
+// signChannelAnnouncement2 signs the ChannelAnnouncement2 with the MuSig2
+// aggregate of the given keys.
+func signChannelAnnouncement2(t *testing.T, ann *lnwire.ChannelAnnouncement2,
+	keys ...*btcec.PrivateKey) {
+
+	t.Helper()
+
+	digest, err := ann.DigestToSign()
+	require.NoError(t, err)
+
+	pubKeys := make([]*btcec.PublicKey, len(keys))
+	nonces := make([]*musig2.Nonces, len(keys))
+	pubNonces := make([][musig2.PubNonceSize]byte, len(keys))
+	for i, key := range keys {
+		pubKeys[i] = key.PubKey()
+		nonces[i], err = musig2.GenNonces(
+			musig2.WithPublicKey(pubKeys[i]),
+		)
+		require.NoError(t, err)
+		pubNonces[i] = nonces[i].PubNonce
+	}
+
+	combinedNonce, err := musig2.AggregateNonces(pubNonces)
+	require.NoError(t, err)
+
+	partialSigs := make([]*musig2.PartialSignature, len(keys))
+	for i, key := range keys {
+		partialSigs[i], err = musig2.Sign(
+			nonces[i].SecNonce, key, combinedNonce, pubKeys,
+			*digest, musig2.WithSortedKeys(),
+		)
+		require.NoError(t, err)
+	}
+
+	sig := musig2.CombineSigs(partialSigs[0].R, partialSigs)
+	ann.Signature, err = lnwire.NewSigFromSchnorrRawSignature(
+		sig.Serialize(),
+	)
+	require.NoError(t, err)
+}
+
+// TestProcessChannelAnnouncement2 tests that remote ChannelAnnouncement2
+// messages add taproot channels to the graph, and that the local announcement
+// of one of our own channels adds the proof to its edge.
+func TestProcessChannelAnnouncement2(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := createTestCtx(t, 2)
+	require.NoError(t, err, "can't create context")
+
+	nodePeer := &mockPeer{remoteKeyPriv1.PubKey(), nil, nil}
+
+	// An announcement that was modified after it was signed must be
+	// rejected.
+	ann := createChannelAnnouncement2(t, 0, remoteKeyPriv1, remoteKeyPriv2)
+	ann.Capacity.Val++
+
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ann, nodePeer):
+	case <-time.After(2 * time.Second):
+		t.Fatal("remote announcement not processed")
+	}
+	require.ErrorContains(t, err, "can't verify channel announcement")
+
+	// A valid announcement adds the channel with its proof to the graph
+	// and is broadcast.
+	ann = createChannelAnnouncement2(t, 1, remoteKeyPriv1, remoteKeyPriv2)
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ann, nodePeer):
+	case <-time.After(2 * time.Second):
+		t.Fatal("remote announcement not processed")
+	}
+	require.NoError(t, err, "can't process remote announcement")
+
+	select {
+	case msg := <-ctx.broadcastedMessage:
+		require.Equal(t, ann, msg.msg)
+	case <-time.After(2 * trickleDelay):
+		t.Fatal("announcement wasn't broadcast")
+	}
+
+	chanInfo, _, _, err := ctx.router.GetChannelByID(ann.ShortChannelID.Val)
+	require.NoError(t, err)
+	require.True(t, chanInfo.AuthProof.IsTaproot())
+	require.Equal(t, ann.Signature.RawBytes(),
+		chanInfo.AuthProof.SchnorrSigBytes)
+	require.EqualValues(t, ann.Capacity.Val, chanInfo.Capacity)
+
+	// A remote announcement of one of our own channels is ignored.
+	ownAnn := createChannelAnnouncement2(t, 2, selfKeyPriv, remoteKeyPriv1)
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ownAnn, nodePeer):
+	case <-time.After(2 * time.Second):
+		t.Fatal("remote announcement not processed")
+	}
+	require.ErrorContains(t, err, "ignoring")
+
+	// Our own channel was added to the graph without a proof when it
+	// became ready. The local announcement adds the proof to the edge.
+	var features bytes.Buffer
+	err = ownAnn.Features.Val.RawFeatureVector.Encode(&features)
+	require.NoError(t, err)
+
+	ownInfo := &models.ChannelEdgeInfo{
+		ChannelID:     ownAnn.ShortChannelID.Val.ToUint64(),
+		NodeKey1Bytes: ownAnn.NodeID1.Val,
+		NodeKey2Bytes: ownAnn.NodeID2.Val,
+		Capacity:      btcutil.Amount(ownAnn.Capacity.Val),
+		Features:      features.Bytes(),
+	}
+	ownAnn.BitcoinKey1.WhenSomeV(func(key [33]byte) {
+		ownInfo.BitcoinKey1Bytes = key
+	})
+	ownAnn.BitcoinKey2.WhenSomeV(func(key [33]byte) {
+		ownInfo.BitcoinKey2Bytes = key
+	})
+	require.NoError(t, ctx.router.AddEdge(ownInfo))
+
+	select {
+	case err = <-ctx.gossiper.ProcessLocalAnnouncement(ownAnn):
+	case <-time.After(2 * time.Second):
+		t.Fatal("local announcement not processed")
+	}
+	require.NoError(t, err, "can't process local announcement")
+
+	select {
+	case msg := <-ctx.broadcastedMessage:
+		require.IsType(t, &lnwire.ChannelAnnouncement2{}, msg.msg)
+	case <-time.After(2 * trickleDelay):
+		t.Fatal("announcement wasn't broadcast")
+	}
+
+	ownChanInfo, _, _, err := ctx.router.GetChannelByID(
+		ownAnn.ShortChannelID.Val,
+	)
+	require.NoError(t, err)
+	require.Equal(t, ownAnn.Signature.RawBytes(),
+		ownChanInfo.AuthProof.SchnorrSigBytes)
+}
+
+// TestFilterTaprootGossip tests that taproot gossip messages are only sent to
+// peers that understand them.
+func TestFilterTaprootGossip(t *testing.T) {
+	t.Parallel()
+
+	msgs := []lnwire.Message{
+		&lnwire.ChannelAnnouncement{},
+		lnwire.NewChannelAnnouncement2(),
+		&lnwire.ChannelUpdate{},
+		&lnwire.ChannelUpdate2{},
+		&lnwire.AnnounceSignatures2{},
+	}
+
+	legacy := lnwire.NewFeatureVector(nil, lnwire.Features)
+	require.Equal(t, []lnwire.Message{msgs[0], msgs[2]},
+		FilterTaprootGossip(legacy, msgs))
+	require.Equal(t, []lnwire.Message{msgs[0], msgs[2]},
+		FilterTaprootGossip(nil, msgs))
+
+	taproot := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.TaprootGossipOptionalStaging,
+		), lnwire.Features,
+	)
+	require.Equal(t, msgs, FilterTaprootGossip(taproot, msgs))
+}
@@ -0,0 +1,182 @@
+package discovery
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultMaxLocalChannelUpdateBurst is the default maximum number of
+	// our own updates for a channel that we broadcast over the local
+	// channel update interval. It matches the number of updates our peers
+	// accept by default, so that our updates aren't rate limited by them.
+	DefaultMaxLocalChannelUpdateBurst = DefaultMaxChannelUpdateBurst
+
+	// DefaultLocalChannelUpdateInterval is the default interval after
+	// which we allow broadcasting another one of our own updates for a
+	// channel once the burst is used up.
+	DefaultLocalChannelUpdateInterval = DefaultChannelUpdateInterval
+)
+
+// throttleKey identifies the direction of a channel that updates are throttled
+// for.
+type throttleKey struct {
+	chanID    uint64
+	direction lnwire.ChanUpdateChanFlags
+}
+
+// localUpdateThrottle limits the rate at which our own channel updates are
+// broadcast to the network. Updates that exceed the rate are held back until
+// the rate allows broadcasting them, and are replaced by newer updates for the
+// same channel in the meantime. Updates that disable or enable a channel are
+// urgent and are broadcast right away, unless status updates are throttled as
+// well.
+//
+// NOTE: The throttle is only used by the gossiper's network handler and is
+// therefore not safe for concurrent use.
+type localUpdateThrottle struct {
+	// interval is the interval after which another update may be
+	// broadcast once the burst is used up. If it isn't positive, updates
+	// aren't throttled.
+	interval time.Duration
+
+	// burst is the maximum number of updates for a channel that are
+	// broadcast in quick succession.
+	burst int
+
+	// throttleStatus indicates whether updates that disable or enable a
+	// channel are throttled like any other update.
+	throttleStatus bool
+
+	// limiters holds the rate limiter of each channel direction.
+	limiters map[throttleKey]*rate.Limiter
+
+	// disabled records whether the last update that was broadcast for a
+	// channel direction disabled it.
+	disabled map[throttleKey]bool
+
+	// pending holds the latest held back update of each channel direction.
+	pending map[throttleKey]msgWithSenders
+}
+
+// newLocalUpdateThrottle creates a throttle for our own channel updates.
+func newLocalUpdateThrottle(interval time.Duration, burst int,
+	throttleStatus bool) *localUpdateThrottle {
+
+	return &localUpdateThrottle{
+		interval:       interval,
+		burst:          burst,
+		throttleStatus: throttleStatus,
+		limiters:       make(map[throttleKey]*rate.Limiter),
+		disabled:       make(map[throttleKey]bool),
+		pending:        make(map[throttleKey]msgWithSenders),
+	}
+}
+
+// filter returns the local messages of a batch that may be broadcast now.
+// Channel updates that exceed the rate are held back, and held back updates
+// of earlier batches are added to the result once the rate allows it.
+func (t *localUpdateThrottle) filter(msgs []msgWithSenders,
+	now time.Time) []msgWithSenders {
+
+	if t.interval <= 0 {
+		return msgs
+	}
+
+	// Updates in this batch replace the held back updates of the same
+	// channel direction, as only the latest update is relevant.
+	inBatch := make(map[throttleKey]struct{})
+	for _, msg := range msgs {
+		if upd, ok := msg.msg.(*lnwire.ChannelUpdate); ok {
+			inBatch[newThrottleKey(upd)] = struct{}{}
+		}
+	}
+
+	allowed := make([]msgWithSenders, 0, len(msgs))
+	for key, msg := range t.pending {
+		if _, ok := inBatch[key]; ok {
+			log.Debugf("Dropping held back update for channel %v "+
+				"superseded by newer update", key.chanID)
+
+			metrics.IncSuppressedChannelUpdates(
+				metrics.SuppressedSuperseded,
+			)
+			delete(t.pending, key)
+
+			continue
+		}
+
+		upd := msg.msg.(*lnwire.ChannelUpdate)
+		if t.allow(key, upd, now) {
+			log.Debugf("Releasing held back update for channel %v",
+				key.chanID)
+
+			allowed = append(allowed, msg)
+			delete(t.pending, key)
+		}
+	}
+
+	for _, msg := range msgs {
+		upd, ok := msg.msg.(*lnwire.ChannelUpdate)
+		if !ok {
+			allowed = append(allowed, msg)
+			continue
+		}
+
+		key := newThrottleKey(upd)
+		if t.allow(key, upd, now) {
+			allowed = append(allowed, msg)
+			continue
+		}
+
+		log.Debugf("Holding back update for channel %v exceeding the "+
+			"local update rate", upd.ShortChannelID)
+
+		metrics.IncSuppressedChannelUpdates(metrics.SuppressedThrottled)
+		t.pending[key] = msg
+	}
+
+	return allowed
+}
+
+// allow returns true if the given update may be broadcast now. If it may, the
+// update is recorded as the last broadcast update of its channel direction.
+func (t *localUpdateThrottle) allow(key throttleKey, upd *lnwire.ChannelUpdate,
+	now time.Time) bool {
+
+	limiter, ok := t.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(t.interval), t.burst)
+		t.limiters[key] = limiter
+	}
+
+	disabled := upd.ChannelFlags&lnwire.ChanUpdateDisabled != 0
+	wasDisabled, known := t.disabled[key]
+	urgent := !t.throttleStatus && known && disabled != wasDisabled
+
+	// Urgent updates still use up the rate if there's any left, so that a
+	// flapping channel doesn't get to broadcast additional policy
+	// updates.
+	if !limiter.AllowN(now, 1) && !urgent {
+		return false
+	}
+
+	if urgent {
+		metrics.IncUrgentChannelUpdates()
+	}
+
+	t.disabled[key] = disabled
+
+	return true
+}
+
+// newThrottleKey returns the throttle key of a channel update.
+func newThrottleKey(upd *lnwire.ChannelUpdate) throttleKey {
+	return throttleKey{
+		chanID:    upd.ShortChannelID.ToUint64(),
+		direction: upd.ChannelFlags & lnwire.ChanUpdateDirection,
+	}
+}
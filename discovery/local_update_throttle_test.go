@@ -0,0 +1,165 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newThrottleTestUpdate creates a local channel update message for the given
+// channel.
+func newThrottleTestUpdate(chanID uint64, timestamp uint32,
+	disabled bool) msgWithSenders {
+
+	upd := &lnwire.ChannelUpdate{
+		ShortChannelID: lnwire.NewShortChanIDFromInt(chanID),
+		Timestamp:      timestamp,
+	}
+	if disabled {
+		upd.ChannelFlags |= lnwire.ChanUpdateDisabled
+	}
+
+	return msgWithSenders{
+		msg:     upd,
+		isLocal: true,
+	}
+}
+
+// requireTimestamps asserts that the given messages are the channel updates
+// with the given timestamps.
+func requireTimestamps(t *testing.T, msgs []msgWithSenders,
+	timestamps ...uint32) {
+
+	t.Helper()
+
+	var actual []uint32
+	for _, msg := range msgs {
+		actual = append(
+			actual, msg.msg.(*lnwire.ChannelUpdate).Timestamp,
+		)
+	}
+	require.ElementsMatch(t, timestamps, actual)
+}
+
+// TestLocalUpdateThrottle tests that our own channel updates that exceed the
+// local update rate are held back until the rate allows broadcasting them.
+func TestLocalUpdateThrottle(t *testing.T) {
+	t.Parallel()
+
+	const interval = time.Minute
+
+	throttle := newLocalUpdateThrottle(interval, 2, false)
+	now := time.Unix(1_000_000, 0)
+
+	// The first two updates of a channel are within the burst, the third
+	// one is held back. Updates of other channels aren't affected.
+	allowed := throttle.filter([]msgWithSenders{
+		newThrottleTestUpdate(1, 1, false),
+		newThrottleTestUpdate(2, 2, false),
+	}, now)
+	requireTimestamps(t, allowed, 1, 2)
+
+	allowed = throttle.filter([]msgWithSenders{
+		newThrottleTestUpdate(1, 3, false),
+	}, now)
+	requireTimestamps(t, allowed, 3)
+
+	allowed = throttle.filter([]msgWithSenders{
+		newThrottleTestUpdate(1, 4, false),
+	}, now)
+	require.Empty(t, allowed)
+	require.Len(t, throttle.pending, 1)
+
+	// A newer update replaces the held back update.
+	allowed = throttle.filter([]msgWithSenders{
+		newThrottleTestUpdate(1, 5, false),
+	}, now)
+	require.Empty(t, allowed)
+	require.Len(t, throttle.pending, 1)
+
+	// Without new updates, the held back update is released once the rate
+	// allows it.
+	allowed = throttle.filter(nil, now.Add(interval/2))
+	require.Empty(t, allowed)
+
+	allowed = throttle.filter(nil, now.Add(interval))
+	requireTimestamps(t, allowed, 5)
+	require.Empty(t, throttle.pending)
+}
+
+// TestLocalUpdateThrottleUrgent tests that updates that disable or enable a
+// channel bypass the local update rate, unless status updates are throttled.
+func TestLocalUpdateThrottleUrgent(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		throttleStatus bool
+		expectUrgent   bool
+	}{
+		{
+			name:         "urgent",
+			expectUrgent: true,
+		},
+		{
+			name:           "throttled",
+			throttleStatus: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			throttle := newLocalUpdateThrottle(
+				time.Minute, 1, tc.throttleStatus,
+			)
+			now := time.Unix(1_000_000, 0)
+
+			allowed := throttle.filter([]msgWithSenders{
+				newThrottleTestUpdate(1, 1, false),
+			}, now)
+			requireTimestamps(t, allowed, 1)
+
+			// A policy update is held back, as the burst is used
+			// up.
+			allowed = throttle.filter([]msgWithSenders{
+				newThrottleTestUpdate(1, 2, false),
+			}, now)
+			require.Empty(t, allowed)
+
+			// Disabling the channel supersedes the held back
+			// update, and is broadcast right away if it's urgent.
+			allowed = throttle.filter([]msgWithSenders{
+				newThrottleTestUpdate(1, 3, true),
+			}, now)
+			if tc.expectUrgent {
+				requireTimestamps(t, allowed, 3)
+				require.Empty(t, throttle.pending)
+			} else {
+				require.Empty(t, allowed)
+				require.Len(t, throttle.pending, 1)
+			}
+		})
+	}
+}
+
+// TestLocalUpdateThrottleDisabled tests that our own updates aren't rate
+// limited if no interval is set.
+func TestLocalUpdateThrottleDisabled(t *testing.T) {
+	t.Parallel()
+
+	throttle := newLocalUpdateThrottle(0, 0, false)
+	now := time.Unix(1_000_000, 0)
+
+	for i := uint32(0); i < 10; i++ {
+		allowed := throttle.filter([]msgWithSenders{
+			newThrottleTestUpdate(1, i, false),
+		}, now)
+		requireTimestamps(t, allowed, i)
+	}
+}
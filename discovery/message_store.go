@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -17,7 +18,7 @@ var (
 	// respective peers.
 	//
 	// maps:
-	//   pubKey (33 bytes) + msgShortChanID (8 bytes) + msgType (2 bytes) -> msg
+	//   pubKey (33 bytes) + msgStoreID (8 bytes) + msgType (2 bytes) -> msg
 	messageStoreBucket = []byte("message-store")
 
 	// ErrUnsupportedMessage is an error returned when we attempt to add a
@@ -31,8 +32,8 @@ var (
 		"corrupted")
 )
 
-// GossipMessageStore is a store responsible for storing gossip messages which
-// we should reliably send to our peers.
+// GossipMessageStore is a store responsible for storing gossip messages, and
+// custom messages, which we should reliably send to our peers.
 type GossipMessageStore interface {
 	// AddMessage adds a message to the store for this peer.
 	AddMessage(lnwire.Message, [33]byte) error
@@ -56,7 +57,7 @@ type GossipMessageStore interface {
 // MessageStore is an implementation of the GossipMessageStore interface backed
 // by a channeldb instance. By design, this store will only keep the latest
 // version of a message (like in the case of multiple ChannelUpdate's) for a
-// channel with a peer.
+// channel with a peer, and a single copy of identical custom messages.
 type MessageStore struct {
 	db kvdb.Backend
 }
@@ -94,16 +95,34 @@ func msgShortChanID(msg lnwire.Message) (lnwire.ShortChannelID, error) {
 	return shortChanID, nil
 }
 
+// msgStoreID retrieves the ID under which the message is stored for a peer,
+// next to its type. Gossip messages are stored under the short channel ID of
+// the channel they apply to, while custom messages are stored under a digest
+// of their data so that queueing the same message twice only stores it once.
+func msgStoreID(msg lnwire.Message) (uint64, error) {
+	if msg, ok := msg.(*lnwire.Custom); ok {
+		digest := sha256.Sum256(msg.Data)
+		return binary.BigEndian.Uint64(digest[:8]), nil
+	}
+
+	shortChanID, err := msgShortChanID(msg)
+	if err != nil {
+		return 0, err
+	}
+
+	return shortChanID.ToUint64(), nil
+}
+
 // messageStoreKey constructs the database key for the message to be stored.
 func messageStoreKey(msg lnwire.Message, peerPubKey [33]byte) ([]byte, error) {
-	shortChanID, err := msgShortChanID(msg)
+	msgID, err := msgStoreID(msg)
 	if err != nil {
 		return nil, err
 	}
 
 	var k [33 + 8 + 2]byte
 	copy(k[:33], peerPubKey[:])
-	binary.BigEndian.PutUint64(k[33:41], shortChanID.ToUint64())
+	binary.BigEndian.PutUint64(k[33:41], msgID)
 	binary.BigEndian.PutUint16(k[41:43], uint16(msg.MsgType()))
 
 	return k[:], nil
@@ -194,8 +213,8 @@ func readMessage(msgBytes []byte) (lnwire.Message, error) {
 	}
 
 	// Check if the message is supported by the store. We can reuse the
-	// check for ShortChannelID as its a dependency on messages stored.
-	if _, err := msgShortChanID(msg); err != nil {
+	// check for the store ID as its a dependency on messages stored.
+	if _, err := msgStoreID(msg); err != nil {
 		return nil, err
 	}
 
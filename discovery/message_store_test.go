@@ -332,3 +332,34 @@ func TestMessageStoreDeleteMessage(t *testing.T) {
 	}
 	assertMsg(newChanUpdate, peer, false)
 }
+
+// TestMessageStoreCustomMessages ensures that custom messages can be stored
+// and deleted, and that identical custom messages are only stored once.
+func TestMessageStoreCustomMessages(t *testing.T) {
+	t.Parallel()
+
+	msgStore := createTestMessageStore(t)
+	peer := randCompressedPubKey(t)
+
+	msg1, err := lnwire.NewCustom(lnwire.CustomTypeStart, []byte{1})
+	require.NoError(t, err)
+	msg2, err := lnwire.NewCustom(lnwire.CustomTypeStart, []byte{2})
+	require.NoError(t, err)
+
+	// Adding the first message twice should only store it once, next to
+	// the second message which has different data.
+	require.NoError(t, msgStore.AddMessage(msg1, peer))
+	require.NoError(t, msgStore.AddMessage(msg1, peer))
+	require.NoError(t, msgStore.AddMessage(msg2, peer))
+
+	peerMsgs, err := msgStore.MessagesForPeer(peer)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []lnwire.Message{msg1, msg2}, peerMsgs)
+
+	// Once deleted, only the second message should remain.
+	require.NoError(t, msgStore.DeleteMessage(msg1, peer))
+
+	peerMsgs, err = msgStore.MessagesForPeer(peer)
+	require.NoError(t, err)
+	require.Equal(t, []lnwire.Message{msg2}, peerMsgs)
+}
@@ -188,8 +188,8 @@ out:
 		case msg := <-peerMgr.msgs:
 			// Retrieve the short channel ID for which this message
 			// applies for logging purposes. The error can be
-			// ignored as the store can only contain messages which
-			// have a ShortChannelID field.
+			// ignored as only custom messages don't have a
+			// ShortChannelID field.
 			shortChanID, _ := msgShortChanID(msg)
 			log.Debugf("Received request to send %v message for "+
 				"channel=%v while peer=%x is offline",
@@ -225,8 +225,8 @@ out:
 	// for them.
 	for _, msg := range pendingMsgs {
 		// Retrieve the short channel ID for which this message applies
-		// for logging purposes. The error can be ignored as the store
-		// can only contain messages which have a ShortChannelID field.
+		// for logging purposes. The error can be ignored as only custom
+		// messages don't have a ShortChannelID field.
 		shortChanID, _ := msgShortChanID(msg)
 
 		// Ensure the peer is still online right before sending the
@@ -252,20 +252,7 @@ out:
 		// check whether it's stale. This guarantees that
 		// AnnounceSignatures are sent at least once if we happen to
 		// already have signatures for both parties.
-		if s.cfg.IsMsgStale(msg) {
-			err := s.cfg.MessageStore.DeleteMessage(msg, peerPubKey)
-			if err != nil {
-				log.Errorf("Unable to remove stale %v message "+
-					"for channel=%v with peer %x: %v",
-					msg.MsgType(), shortChanID, peerPubKey,
-					err)
-				continue
-			}
-
-			log.Debugf("Removed stale %v message for channel=%v "+
-				"with peer=%x", msg.MsgType(), shortChanID,
-				peerPubKey)
-		}
+		s.removeIfStale(msg, peerPubKey)
 	}
 
 	// If all of our messages were stale, then there's no need for this
@@ -296,20 +283,26 @@ out:
 		case msg := <-peerMgr.msgs:
 			// Retrieve the short channel ID for which this message
 			// applies for logging purposes. The error can be
-			// ignored as the store can only contain messages which
-			// have a ShortChannelID field.
+			// ignored as only custom messages don't have a
+			// ShortChannelID field.
 			shortChanID, _ := msgShortChanID(msg)
 
 			if err := peer.SendMessage(false, msg); err != nil {
 				log.Errorf("Unable to send %v message for "+
 					"channel=%v to %x: %v", msg.MsgType(),
 					shortChanID, peerPubKey, err)
+				continue
 			}
 
 			log.Debugf("Successfully sent %v message for "+
 				"channel=%v with peer=%x", msg.MsgType(),
 				shortChanID, peerPubKey)
 
+			// Messages that are stale once sent, like custom
+			// messages, don't need to be resent when the peer
+			// reconnects.
+			s.removeIfStale(msg, peerPubKey)
+
 		case <-offlineChan:
 			goto waitUntilOnline
 
@@ -319,6 +312,31 @@ out:
 	}
 }
 
+// removeIfStale removes the given message, which has been sent to the peer,
+// from the message store if it's seen as stale.
+func (s *reliableSender) removeIfStale(msg lnwire.Message,
+	peerPubKey [33]byte) {
+
+	if !s.cfg.IsMsgStale(msg) {
+		return
+	}
+
+	// The error can be ignored as only custom messages don't have a
+	// ShortChannelID field.
+	shortChanID, _ := msgShortChanID(msg)
+
+	err := s.cfg.MessageStore.DeleteMessage(msg, peerPubKey)
+	if err != nil {
+		log.Errorf("Unable to remove stale %v message for channel=%v "+
+			"with peer %x: %v", msg.MsgType(), shortChanID,
+			peerPubKey, err)
+		return
+	}
+
+	log.Debugf("Removed stale %v message for channel=%v with peer=%x",
+		msg.MsgType(), shortChanID, peerPubKey)
+}
+
 // resendPendingMsgs retrieves and sends all of the messages within the message
 // store that should be reliably sent to their respective peers.
 func (s *reliableSender) resendPendingMsgs() error {
@@ -10,6 +10,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lntest/wait"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
 )
 
 // newTestReliableSender creates a new reliable sender instance used for
@@ -288,3 +289,71 @@ func TestReliableSenderStaleMessages(t *testing.T) {
 
 	assertMsgsSent(t, peer.sentMsgs, msg2)
 }
+
+// TestReliableSenderCustomMessages ensures that custom messages, which are
+// stale once sent, are removed from the store after being sent to an online
+// peer while the messages that still need to be resent remain.
+func TestReliableSenderCustomMessages(t *testing.T) {
+	t.Parallel()
+
+	reliableSender := newTestReliableSender(t)
+
+	// Create a mock peer to send the messages to.
+	pubKey := randPubKey(t)
+	msgsSent := make(chan lnwire.Message)
+	peer := &mockPeer{pubKey, msgsSent, reliableSender.quit}
+
+	// Only custom messages are seen as stale, like in the gossiper.
+	reliableSender.cfg.IsMsgStale = func(msg lnwire.Message) bool {
+		_, ok := msg.(*lnwire.Custom)
+		return ok
+	}
+
+	notifyOnline := make(chan chan<- lnpeer.Peer, 1)
+	reliableSender.cfg.NotifyWhenOnline = func(_ [33]byte,
+		peerChan chan<- lnpeer.Peer) {
+
+		notifyOnline <- peerChan
+	}
+
+	var peerPubKey [33]byte
+	copy(peerPubKey[:], pubKey.SerializeCompressed())
+
+	// We'll start by sending a ChannelUpdate, which isn't stale and keeps
+	// the peerHandler active once the peer is online.
+	chanUpdate := randChannelUpdate()
+	err := reliableSender.sendMessage(chanUpdate, peerPubKey)
+	require.NoError(t, err)
+
+	select {
+	case peerChan := <-notifyOnline:
+		peerChan <- peer
+	case <-time.After(time.Second):
+		t.Fatal("reliable sender did not request online notification")
+	}
+	assertMsgsSent(t, peer.sentMsgs, chanUpdate)
+
+	// Now that the peer is online, a custom message should be sent right
+	// away and then removed from the store.
+	customMsg, err := lnwire.NewCustom(lnwire.CustomTypeStart, []byte{1})
+	require.NoError(t, err)
+	err = reliableSender.sendMessage(customMsg, peerPubKey)
+	require.NoError(t, err)
+	assertMsgsSent(t, peer.sentMsgs, customMsg)
+
+	err = wait.NoError(func() error {
+		msgs, err := reliableSender.cfg.MessageStore.MessagesForPeer(
+			peerPubKey,
+		)
+		if err != nil {
+			return err
+		}
+		if len(msgs) != 1 || msgs[0] != chanUpdate {
+			return fmt.Errorf("expected only the channel update "+
+				"to remain, found %d messages", len(msgs))
+		}
+
+		return nil
+	}, time.Second)
+	require.NoError(t, err)
+}
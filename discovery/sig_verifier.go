@@ -0,0 +1,212 @@
+package discovery
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"runtime"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightninglabs/neutrino/cache/lru"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+const (
+	// maxVerifiedMsgs tracks the max amount of gossip messages with valid
+	// signatures that we'll remember. We'll allocate ~2 MB max to the
+	// cache.
+	maxVerifiedMsgs = 20_000
+)
+
+// cachedVerified is the empty value used to track messages with valid
+// signatures.
+type cachedVerified struct{}
+
+// Size returns the "size" of an entry. We return 1 as we just want to limit
+// the total size.
+func (c *cachedVerified) Size() (uint64, error) {
+	return 1, nil
+}
+
+// sigVerifier verifies the signatures of gossip messages. As every incoming
+// gossip message is handled in its own goroutine, verifying all of them at
+// once during a graph sync makes the verifications compete for the CPU. The
+// sigVerifier therefore bounds the number of concurrent verifications by a
+// fixed number of worker slots. Additionally, it remembers the messages it
+// already verified, so the same announcement received from several peers is
+// only verified once.
+type sigVerifier struct {
+	// workers holds one entry for each verification that is currently
+	// being executed.
+	workers chan struct{}
+
+	// verified contains the digests of the messages whose signatures were
+	// verified successfully.
+	verified *lru.Cache[[32]byte, *cachedVerified]
+
+	quit <-chan struct{}
+}
+
+// newSigVerifier creates a new signature verifier that executes at most
+// numWorkers verifications concurrently. If numWorkers isn't positive, the
+// number of CPUs is used.
+func newSigVerifier(numWorkers int, quit <-chan struct{}) *sigVerifier {
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	return &sigVerifier{
+		workers: make(chan struct{}, numWorkers),
+		verified: lru.NewCache[[32]byte, *cachedVerified](
+			maxVerifiedMsgs,
+		),
+		quit: quit,
+	}
+}
+
+// msgDigest returns the digest of the wire encoding of the given message and
+// the optional public key it is expected to be signed with.
+func msgDigest(msg lnwire.Message, pubKey *btcec.PublicKey) ([32]byte,
+	error) {
+
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+		return [32]byte{}, err
+	}
+	if pubKey != nil {
+		b.Write(pubKey.SerializeCompressed())
+	}
+
+	return sha256.Sum256(b.Bytes()), nil
+}
+
+// verify runs the given verification of the message with the given digest
+// once a worker slot is available, unless the message was already verified
+// before.
+func (s *sigVerifier) verify(digest [32]byte, verifyFn func() error) error {
+	if _, err := s.verified.Get(digest); err == nil {
+		return nil
+	}
+
+	select {
+	case s.workers <- struct{}{}:
+	case <-s.quit:
+		return ErrGossiperShuttingDown
+	}
+	err := verifyFn()
+	<-s.workers
+
+	if err != nil {
+		return err
+	}
+
+	_, _ = s.verified.Put(digest, &cachedVerified{})
+
+	return nil
+}
+
+// validateChannelAnn validates the signatures of the given channel
+// announcement.
+func (s *sigVerifier) validateChannelAnn(a *lnwire.ChannelAnnouncement) error {
+	digest, err := msgDigest(a, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.verify(digest, func() error {
+		return routing.ValidateChannelAnn(a)
+	})
+}
+
+// validateNodeAnn validates the signature of the given node announcement.
+func (s *sigVerifier) validateNodeAnn(a *lnwire.NodeAnnouncement) error {
+	digest, err := msgDigest(a, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.verify(digest, func() error {
+		return routing.ValidateNodeAnn(a)
+	})
+}
+
+// verifyChannelUpdateSignature verifies that the channel update was signed by
+// the given node public key.
+func (s *sigVerifier) verifyChannelUpdateSignature(msg *lnwire.ChannelUpdate,
+	pubKey *btcec.PublicKey) error {
+
+	digest, err := msgDigest(msg, pubKey)
+	if err != nil {
+		return err
+	}
+
+	return s.verify(digest, func() error {
+		return routing.VerifyChannelUpdateSignature(msg, pubKey)
+	})
+}
+
+// validateChannelUpdateAnn validates the fields of the given channel update
+// and verifies that it was signed by the given node public key.
+func (s *sigVerifier) validateChannelUpdateAnn(pubKey *btcec.PublicKey,
+	capacity btcutil.Amount, a *lnwire.ChannelUpdate) error {
+
+	if err := routing.ValidateChannelUpdateFields(capacity, a); err != nil {
+		return err
+	}
+
+	return s.verifyChannelUpdateSignature(a, pubKey)
+}
+
+// validateChannelAnn2 validates the signature of the given taproot channel
+// announcement. The expected funding output is derived from the announced
+// keys, its existence is verified once the edge is added to the graph.
+func (s *sigVerifier) validateChannelAnn2(
+	a *lnwire.ChannelAnnouncement2) error {
+
+	// We only support announcements of channels with a BIP86 funding
+	// output for now, as those are the only ones the router can find on
+	// chain.
+	if a.BitcoinKey1.IsNone() || a.BitcoinKey2.IsNone() {
+		return errors.New("channel announcement without bitcoin " +
+			"keys isn't supported")
+	}
+	if a.MerkleRootHash.IsSome() {
+		return errors.New("channel announcement with merkle root " +
+			"isn't supported")
+	}
+
+	digest, err := msgDigest(a, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.verify(digest, func() error {
+		var (
+			btcKey1, btcKey2 *btcec.PublicKey
+			err1, err2       error
+		)
+		a.BitcoinKey1.WhenSomeV(func(key [33]byte) {
+			btcKey1, err1 = btcec.ParsePubKey(key[:])
+		})
+		a.BitcoinKey2.WhenSomeV(func(key [33]byte) {
+			btcKey2, err2 = btcec.ParsePubKey(key[:])
+		})
+		if err1 != nil {
+			return err1
+		}
+		if err2 != nil {
+			return err2
+		}
+
+		fundingPkScript, err := routing.ChanAnn2FundingPkScript(
+			btcKey1, btcKey2, a.MerkleRootHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		return routing.ValidateChannelAnn2(a, fundingPkScript)
+	})
+}
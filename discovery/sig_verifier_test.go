@@ -0,0 +1,95 @@
+package discovery
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSigVerifier tests that the signature verifier bounds the number of
+// concurrent verifications and only verifies successfully verified messages
+// once.
+func TestSigVerifier(t *testing.T) {
+	t.Parallel()
+
+	const numWorkers = 2
+
+	quit := make(chan struct{})
+	verifier := newSigVerifier(numWorkers, quit)
+
+	// Start more verifications than there are workers and block all of
+	// them, so we can check how many are executed concurrently.
+	var (
+		mtx        sync.Mutex
+		running    int
+		maxRunning int
+		wg         sync.WaitGroup
+	)
+	release := make(chan struct{})
+	for i := 0; i < 2*numWorkers; i++ {
+		wg.Add(1)
+		go func(i byte) {
+			defer wg.Done()
+
+			err := verifier.verify([32]byte{i}, func() error {
+				mtx.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mtx.Unlock()
+
+				<-release
+
+				mtx.Lock()
+				running--
+				mtx.Unlock()
+
+				return nil
+			})
+			require.NoError(t, err)
+		}(byte(i))
+	}
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		return running == numWorkers
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, numWorkers, maxRunning)
+
+	// A message that was verified successfully isn't verified again.
+	var numCalls int
+	verifyFn := func() error {
+		numCalls++
+		return nil
+	}
+	require.NoError(t, verifier.verify([32]byte{0}, verifyFn))
+	require.Zero(t, numCalls)
+
+	// A message with an invalid signature is verified every time.
+	errInvalid := errors.New("invalid signature")
+	invalidFn := func() error {
+		numCalls++
+		return errInvalid
+	}
+	require.ErrorIs(t, verifier.verify([32]byte{9}, invalidFn), errInvalid)
+	require.ErrorIs(t, verifier.verify([32]byte{9}, invalidFn), errInvalid)
+	require.Equal(t, 2, numCalls)
+
+	// Once all workers are busy, pending verifications are aborted on
+	// shutdown.
+	for i := 0; i < numWorkers; i++ {
+		verifier.workers <- struct{}{}
+	}
+	close(quit)
+	err := verifier.verify([32]byte{10}, verifyFn)
+	require.ErrorIs(t, err, ErrGossiperShuttingDown)
+}
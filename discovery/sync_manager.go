@@ -494,9 +494,19 @@ func (m *SyncManager) createGossipSyncer(peer lnpeer.Peer) *GossipSyncer {
 		chunkSize:     encodingTypeToChunkSize[encoding],
 		batchSize:     requestBatchSize,
 		sendToPeer: func(msgs ...lnwire.Message) error {
+			msgs = FilterTaprootGossip(peer.RemoteFeatures(), msgs)
+			if len(msgs) == 0 {
+				return nil
+			}
+
 			return peer.SendMessageLazy(false, msgs...)
 		},
 		sendToPeerSync: func(msgs ...lnwire.Message) error {
+			msgs = FilterTaprootGossip(peer.RemoteFeatures(), msgs)
+			if len(msgs) == 0 {
+				return nil
+			}
+
 			return peer.SendMessageLazy(true, msgs...)
 		},
 		ignoreHistoricalFilters:   m.cfg.IgnoreHistoricalFilters,
@@ -521,6 +531,33 @@ func (m *SyncManager) createGossipSyncer(peer lnpeer.Peer) *GossipSyncer {
 	return s
 }
 
+// FilterTaprootGossip returns the given messages without the taproot gossip
+// messages if the peer with the given features doesn't understand them. As
+// these messages have even types, the peer would disconnect if we sent them.
+func FilterTaprootGossip(features *lnwire.FeatureVector,
+	msgs []lnwire.Message) []lnwire.Message {
+
+	if features != nil &&
+		features.HasFeature(lnwire.TaprootGossipOptionalStaging) {
+
+		return msgs
+	}
+
+	filtered := make([]lnwire.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		switch msg.(type) {
+		case *lnwire.ChannelAnnouncement2, *lnwire.ChannelUpdate2,
+			*lnwire.AnnounceSignatures2:
+
+			continue
+		}
+
+		filtered = append(filtered, msg)
+	}
+
+	return filtered
+}
+
 // removeGossipSyncer removes all internal references to the disconnected peer's
 // GossipSyncer and stops it. In the event of an active GossipSyncer being
 // disconnected, a passive GossipSyncer, if any, will take its place.
@@ -1276,8 +1276,14 @@ func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error
 
 // ApplyGossipFilter applies a gossiper filter sent by the remote node to the
 // state machine. Once applied, we'll ensure that we don't forward any messages
-// to the peer that aren't within the time range of the filter.
-func (g *GossipSyncer) ApplyGossipFilter(filter *lnwire.GossipTimestampRange) error {
+// to the peer that aren't within the time range of the filter. The backlog of
+// messages within the new horizon is sent in the background, and the returned
+// channel receives the result once the backlog has been sent.
+func (g *GossipSyncer) ApplyGossipFilter(
+	filter *lnwire.GossipTimestampRange) chan error {
+
+	errChan := make(chan error, 1)
+
 	g.Lock()
 
 	g.remoteUpdateHorizon = filter
@@ -1292,56 +1298,83 @@ func (g *GossipSyncer) ApplyGossipFilter(filter *lnwire.GossipTimestampRange) er
 	// If requested, don't reply with historical gossip data when the remote
 	// peer sets their gossip timestamp range.
 	if g.cfg.ignoreHistoricalFilters {
-		return nil
-	}
-
-	// Now that the remote peer has applied their filter, we'll query the
-	// database for all the messages that are beyond this filter.
-	newUpdatestoSend, err := g.cfg.channelSeries.UpdatesInHorizon(
-		g.cfg.chainHash, startTime, endTime,
-	)
-	if err != nil {
-		return err
+		errChan <- nil
+		return errChan
 	}
 
 	log.Infof("GossipSyncer(%x): applying new update horizon: start=%v, "+
-		"end=%v, backlog_size=%v", g.cfg.peerPub[:], startTime, endTime,
-		len(newUpdatestoSend))
+		"end=%v", g.cfg.peerPub[:], startTime, endTime)
 
-	// If we don't have any to send, then we can return early.
-	if len(newUpdatestoSend) == 0 {
-		return nil
-	}
+	// Now that the remote peer has applied their filter, we'll launch a
+	// goroutine that sends out the messages that are beyond this filter.
+	// The messages are sent as they're read from the database batch by
+	// batch, so that we don't hold the full backlog in memory.
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		errChan <- g.sendUpdatesInHorizon(startTime, endTime)
+	}()
+
+	return errChan
+}
+
+// sendUpdatesInHorizon sends the remote peer all known updates with a
+// timestamp between the start and end time. The gossip filter semaphore is
+// held while the updates are read from the database and sent, which bounds the
+// number of backlogs that are read concurrently.
+func (g *GossipSyncer) sendUpdatesInHorizon(startTime,
+	endTime time.Time) error {
 
 	select {
 	case <-g.gossipFilterSema:
 	case <-g.quit:
-		return ErrGossipSyncerExiting
+		return nil
 	}
+	defer func() {
+		g.gossipFilterSema <- struct{}{}
+	}()
 
-	// We'll conclude by launching a goroutine to send out any updates.
-	g.wg.Add(1)
-	go func() {
-		defer g.wg.Done()
-		defer func() {
-			g.gossipFilterSema <- struct{}{}
-		}()
+	var numSent int
+	err := g.cfg.channelSeries.UpdatesInHorizon(
+		g.cfg.chainHash, startTime, endTime,
+		func(msgs []lnwire.Message) error {
+			for _, msg := range msgs {
+				err := g.cfg.sendToPeerSync(msg)
+				switch {
+				case err == ErrGossipSyncerExiting:
+					return err
+
+				case err == lnpeer.ErrPeerExiting:
+					return err
+
+				case err != nil:
+					log.Errorf("Unable to send message "+
+						"for peer catch up: %v", err)
+
+				default:
+					numSent++
+				}
+			}
 
-		for _, msg := range newUpdatestoSend {
-			err := g.cfg.sendToPeerSync(msg)
-			switch {
-			case err == ErrGossipSyncerExiting:
-				return
+			return nil
+		},
+	)
+	switch {
+	// If we or the peer are exiting, there's no one left to catch up, so
+	// we don't consider this an error.
+	case err == ErrGossipSyncerExiting:
+		return nil
 
-			case err == lnpeer.ErrPeerExiting:
-				return
+	case err == lnpeer.ErrPeerExiting:
+		return nil
 
-			case err != nil:
-				log.Errorf("Unable to send message for "+
-					"peer catch up: %v", err)
-			}
-		}
-	}()
+	case err != nil:
+		return err
+	}
+
+	log.Debugf("GossipSyncer(%x): sent backlog of %v messages within "+
+		"update horizon", g.cfg.peerPub[:], numSent)
 
 	return nil
 }
@@ -1402,6 +1435,35 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 			(t.After(startTime) && t.Before(endTime))
 	}
 
+	// chanAnnPassesFilter returns whether the announcement of the given
+	// channel passes the filter, which is the case if any of the channel's
+	// updates is within our time range, or if no update is known yet.
+	chanAnnPassesFilter := func(scid lnwire.ShortChannelID) bool {
+		// First, we'll check if the channel updates are in this
+		// message batch.
+		chanUpdates, ok := chanUpdateIndex[scid]
+		if !ok {
+			// If not, we'll attempt to query the database to see
+			// if we know of the updates.
+			chanUpdates, err = g.cfg.channelSeries.FetchChanUpdates(
+				g.cfg.chainHash, scid,
+			)
+			if err != nil {
+				log.Warnf("no channel updates found for "+
+					"short_chan_id=%v", scid)
+				return false
+			}
+		}
+
+		for _, chanUpdate := range chanUpdates {
+			if passesFilter(chanUpdate.Timestamp) {
+				return true
+			}
+		}
+
+		return len(chanUpdates) == 0
+	}
+
 	msgsToSend := make([]lnwire.Message, 0, len(msgs))
 	for _, msg := range msgs {
 		// If the target peer is the peer that sent us this message,
@@ -1417,31 +1479,12 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 		// message if the channel updates for the channel are between
 		// our time range.
 		case *lnwire.ChannelAnnouncement:
-			// First, we'll check if the channel updates are in
-			// this message batch.
-			chanUpdates, ok := chanUpdateIndex[msg.ShortChannelID]
-			if !ok {
-				// If not, we'll attempt to query the database
-				// to see if we know of the updates.
-				chanUpdates, err = g.cfg.channelSeries.FetchChanUpdates(
-					g.cfg.chainHash, msg.ShortChannelID,
-				)
-				if err != nil {
-					log.Warnf("no channel updates found for "+
-						"short_chan_id=%v",
-						msg.ShortChannelID)
-					continue
-				}
-			}
-
-			for _, chanUpdate := range chanUpdates {
-				if passesFilter(chanUpdate.Timestamp) {
-					msgsToSend = append(msgsToSend, msg)
-					break
-				}
+			if chanAnnPassesFilter(msg.ShortChannelID) {
+				msgsToSend = append(msgsToSend, msg)
 			}
 
-			if len(chanUpdates) == 0 {
+		case *lnwire.ChannelAnnouncement2:
+			if chanAnnPassesFilter(msg.ShortChannelID.Val) {
 				msgsToSend = append(msgsToSend, msg)
 			}
 
@@ -41,6 +41,7 @@ type mockChannelGraphTimeSeries struct {
 
 	horizonReq  chan horizonQuery
 	horizonResp chan []lnwire.Message
+	horizonErr  error
 
 	filterReq  chan []channeldb.ChannelUpdateInfo
 	filterResp chan []lnwire.ShortChannelID
@@ -82,13 +83,22 @@ func (m *mockChannelGraphTimeSeries) HighestChanID(chain chainhash.Hash) (*lnwir
 	return &m.highestID, nil
 }
 func (m *mockChannelGraphTimeSeries) UpdatesInHorizon(chain chainhash.Hash,
-	startTime time.Time, endTime time.Time) ([]lnwire.Message, error) {
+	startTime time.Time, endTime time.Time,
+	cb func([]lnwire.Message) error) error {
 
 	m.horizonReq <- horizonQuery{
 		chain, startTime, endTime,
 	}
 
-	return <-m.horizonResp, nil
+	msgs := <-m.horizonResp
+	if m.horizonErr != nil {
+		return m.horizonErr
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	return cb(msgs)
 }
 
 func (m *mockChannelGraphTimeSeries) FilterKnownChanIDs(chain chainhash.Hash,
@@ -507,7 +517,7 @@ func TestGossipSyncerApplyGossipFilter(t *testing.T) {
 	}()
 
 	// We'll now attempt to apply the gossip filter for the remote peer.
-	err := syncer.ApplyGossipFilter(remoteHorizon)
+	err := <-syncer.ApplyGossipFilter(remoteHorizon)
 	require.NoError(t, err, "unable to apply filter")
 
 	// There should be no messages in the message queue as we didn't send
@@ -555,7 +565,7 @@ func TestGossipSyncerApplyGossipFilter(t *testing.T) {
 			errCh <- nil
 		}
 	}()
-	err = syncer.ApplyGossipFilter(remoteHorizon)
+	err = <-syncer.ApplyGossipFilter(remoteHorizon)
 	require.NoError(t, err, "unable to apply filter")
 
 	// We should get back the exact same message.
@@ -581,6 +591,69 @@ func TestGossipSyncerApplyGossipFilter(t *testing.T) {
 	}
 }
 
+// TestGossipSyncerApplyGossipFilterSema tests that the backlog of a gossip
+// filter is only read once the gossip filter semaphore is acquired, and that
+// errors reading the backlog are returned.
+func TestGossipSyncerApplyGossipFilterSema(t *testing.T) {
+	t.Parallel()
+
+	_, syncer, chanSeries := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+
+	// Take all slots of the semaphore, as if other peers were being
+	// caught up.
+	var numSlots int
+	for len(syncer.gossipFilterSema) > 0 {
+		<-syncer.gossipFilterSema
+		numSlots++
+	}
+
+	remoteHorizon := &lnwire.GossipTimestampRange{
+		FirstTimestamp: unixStamp(25000),
+		TimestampRange: uint32(1000),
+	}
+
+	// The backlog isn't read while the semaphore is taken.
+	errChan := syncer.ApplyGossipFilter(remoteHorizon)
+	select {
+	case <-chanSeries.horizonReq:
+		t.Fatalf("backlog read without the semaphore")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Once a slot is released, the backlog is read and the filter is
+	// applied.
+	syncer.gossipFilterSema <- struct{}{}
+	chanSeries.horizonResp <- []lnwire.Message{}
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("gossip filter not applied")
+	}
+	<-chanSeries.horizonReq
+
+	// An error reading the updates in the horizon is returned.
+	chanSeries.horizonErr = errors.New("db error")
+	chanSeries.horizonResp <- nil
+	select {
+	case err := <-syncer.ApplyGossipFilter(remoteHorizon):
+		require.ErrorIs(t, err, chanSeries.horizonErr)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("gossip filter not applied")
+	}
+	<-chanSeries.horizonReq
+
+	// The slot is released again after each backlog.
+	require.Len(t, syncer.gossipFilterSema, 1)
+	require.Positive(t, numSlots)
+}
+
 // TestGossipSyncerQueryChannelRangeWrongChainHash tests that if we receive a
 // channel range query for the wrong chain, then we send back a response with no
 // channels and complete=0.
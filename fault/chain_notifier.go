@@ -0,0 +1,85 @@
+package fault
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+// ErrInjectedChainNotifier is returned by a ChainNotifier wrapped with
+// WrapChainNotifier when a fault is injected.
+var ErrInjectedChainNotifier = errors.New("injected chain notifier failure")
+
+// chainNotifier is a chainntnfs.ChainNotifier that fails notification
+// registrations whenever a fault is triggered at the ChainNotifier point.
+type chainNotifier struct {
+	chainntnfs.ChainNotifier
+
+	injector *Injector
+}
+
+// A compile-time check to ensure chainNotifier implements the
+// chainntnfs.ChainNotifier interface.
+var _ chainntnfs.ChainNotifier = (*chainNotifier)(nil)
+
+// WrapChainNotifier wraps the given chain notifier so that its notification
+// registrations fail when a fault is armed at the ChainNotifier point.
+func WrapChainNotifier(notifier chainntnfs.ChainNotifier,
+	injector *Injector) chainntnfs.ChainNotifier {
+
+	return &chainNotifier{
+		ChainNotifier: notifier,
+		injector:      injector,
+	}
+}
+
+// RegisterConfirmationsNtfn registers an intent to be notified once the
+// transaction reaches the given number of confirmations, unless a fault is
+// injected.
+//
+// NOTE: This is part of the chainntnfs.ChainNotifier interface.
+func (c *chainNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	pkScript []byte, numConfs, heightHint uint32,
+	opts ...chainntnfs.NotifierOption) (*chainntnfs.ConfirmationEvent,
+	error) {
+
+	if c.injector.Trigger(ChainNotifier) {
+		return nil, ErrInjectedChainNotifier
+	}
+
+	return c.ChainNotifier.RegisterConfirmationsNtfn(
+		txid, pkScript, numConfs, heightHint, opts...,
+	)
+}
+
+// RegisterSpendNtfn registers an intent to be notified once the outpoint is
+// spent, unless a fault is injected.
+//
+// NOTE: This is part of the chainntnfs.ChainNotifier interface.
+func (c *chainNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	pkScript []byte, heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	if c.injector.Trigger(ChainNotifier) {
+		return nil, ErrInjectedChainNotifier
+	}
+
+	return c.ChainNotifier.RegisterSpendNtfn(
+		outpoint, pkScript, heightHint,
+	)
+}
+
+// RegisterBlockEpochNtfn registers an intent to be notified of each new block
+// connected to the main chain, unless a fault is injected.
+//
+// NOTE: This is part of the chainntnfs.ChainNotifier interface.
+func (c *chainNotifier) RegisterBlockEpochNtfn(
+	bestBlock *chainntnfs.BlockEpoch) (*chainntnfs.BlockEpochEvent, error) {
+
+	if c.injector.Trigger(ChainNotifier) {
+		return nil, ErrInjectedChainNotifier
+	}
+
+	return c.ChainNotifier.RegisterBlockEpochNtfn(bestBlock)
+}
@@ -0,0 +1,112 @@
+package fault
+
+import (
+	"sync"
+)
+
+// Point identifies a place in lnd where a fault can be injected.
+type Point uint8
+
+const (
+	// ForwardHTLC fails an HTLC that is about to be forwarded by the
+	// switch with a temporary channel failure.
+	ForwardHTLC Point = iota
+
+	// GossipBatch drops a batch of announcements that is about to be
+	// broadcast by the gossiper.
+	GossipBatch
+
+	// ChainNotifier fails a notification registration with the chain
+	// notifier.
+	ChainNotifier
+)
+
+// String returns a human-readable name of the fault injection point.
+func (p Point) String() string {
+	switch p {
+	case ForwardHTLC:
+		return "ForwardHTLC"
+
+	case GossipBatch:
+		return "GossipBatch"
+
+	case ChainNotifier:
+		return "ChainNotifier"
+
+	default:
+		return "Unknown"
+	}
+}
+
+// Injector keeps track of the faults that are armed at each injection point.
+// It's used in dev builds only to reproduce failures that are otherwise hard
+// to trigger. A nil Injector never triggers, so production code can call it
+// unconditionally.
+type Injector struct {
+	// armed holds the number of times a fault is still to be triggered
+	// at each injection point.
+	armed map[Point]uint32
+
+	mu sync.Mutex
+}
+
+// NewInjector creates a new Injector without any armed faults.
+func NewInjector() *Injector {
+	return &Injector{
+		armed: make(map[Point]uint32),
+	}
+}
+
+// Arm makes the next n calls to Trigger for the given point return true. Any
+// previously armed faults at that point are replaced.
+func (i *Injector) Arm(p Point, n uint32) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if n == 0 {
+		delete(i.armed, p)
+		return
+	}
+
+	log.Infof("Armed %d fault(s) at %v", n, p)
+
+	i.armed[p] = n
+}
+
+// Armed returns the number of faults still armed at the given point.
+func (i *Injector) Armed(p Point) uint32 {
+	if i == nil {
+		return 0
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.armed[p]
+}
+
+// Trigger returns true if a fault is armed at the given point, in which case
+// the caller must simulate the failure. Each call consumes one armed fault.
+func (i *Injector) Trigger(p Point) bool {
+	if i == nil {
+		return false
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	n, ok := i.armed[p]
+	if !ok {
+		return false
+	}
+
+	if n <= 1 {
+		delete(i.armed, p)
+	} else {
+		i.armed[p] = n - 1
+	}
+
+	log.Warnf("Triggered injected fault at %v", p)
+
+	return true
+}
@@ -0,0 +1,34 @@
+package fault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestInjector checks that armed faults are triggered exactly as often as they
+// were armed, and only at their own injection point.
+func TestInjector(t *testing.T) {
+	t.Parallel()
+
+	// A nil injector never triggers.
+	var nilInjector *Injector
+	require.False(t, nilInjector.Trigger(ForwardHTLC))
+	require.Zero(t, nilInjector.Armed(ForwardHTLC))
+
+	i := NewInjector()
+	require.False(t, i.Trigger(ForwardHTLC))
+
+	i.Arm(ForwardHTLC, 2)
+	require.EqualValues(t, 2, i.Armed(ForwardHTLC))
+	require.False(t, i.Trigger(GossipBatch))
+
+	require.True(t, i.Trigger(ForwardHTLC))
+	require.True(t, i.Trigger(ForwardHTLC))
+	require.False(t, i.Trigger(ForwardHTLC))
+
+	// Arming zero faults clears the pending ones.
+	i.Arm(ChainNotifier, 3)
+	i.Arm(ChainNotifier, 0)
+	require.False(t, i.Trigger(ChainNotifier))
+}
@@ -72,6 +72,10 @@ var deps = depDesc{
 	lnwire.KeysendOptional: {
 		lnwire.TLVOnionPayloadOptional: {},
 	},
+	lnwire.TrampolineRoutingOptional: {
+		lnwire.TLVOnionPayloadOptional: {},
+		lnwire.PaymentAddrOptional:     {},
+	},
 	lnwire.ZeroConfOptional: {
 		lnwire.ScidAliasOptional: {},
 	},
@@ -79,6 +83,9 @@ var deps = depDesc{
 		lnwire.AnchorsZeroFeeHtlcTxOptional: {},
 		lnwire.ExplicitChannelTypeOptional:  {},
 	},
+	lnwire.TaprootGossipOptionalStaging: {
+		lnwire.SimpleTaprootChannelsOptionalStaging: {},
+	},
 	lnwire.RouteBlindingOptional: {
 		lnwire.TLVOnionPayloadOptional: {},
 	},
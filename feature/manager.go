@@ -39,6 +39,26 @@ type Config struct {
 	// channels.
 	NoTaprootChans bool
 
+	// NoTaprootGossip unsets any bits signaling support for the gossip
+	// messages used to announce taproot channels.
+	NoTaprootGossip bool
+
+	// NoPeerStorage unsets any bits signaling that we offer to store
+	// backup blobs on behalf of our channel peers.
+	NoPeerStorage bool
+
+	// NoRbfCoopClose unsets any bits signaling support for the RBF
+	// cooperative close protocol.
+	NoRbfCoopClose bool
+
+	// NoOnionMessages unsets any bits signaling support for forwarding
+	// onion messages.
+	NoOnionMessages bool
+
+	// NoTrampolineRouting unsets any bits signaling support for relaying
+	// trampoline payments.
+	NoTrampolineRouting bool
+
 	// NoScriptEnforcementLease unsets any bits signaling support for script
 	// enforced leases.
 	NoScriptEnforcementLease bool
@@ -184,6 +204,26 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.SimpleTaprootChannelsOptionalStaging)
 			raw.Unset(lnwire.SimpleTaprootChannelsRequiredStaging)
 		}
+		if cfg.NoTaprootGossip {
+			raw.Unset(lnwire.TaprootGossipOptionalStaging)
+			raw.Unset(lnwire.TaprootGossipRequiredStaging)
+		}
+		if cfg.NoPeerStorage {
+			raw.Unset(lnwire.ProvideStorageOptional)
+			raw.Unset(lnwire.ProvideStorageRequired)
+		}
+		if cfg.NoRbfCoopClose {
+			raw.Unset(lnwire.RbfCoopCloseOptional)
+			raw.Unset(lnwire.RbfCoopCloseRequired)
+		}
+		if cfg.NoOnionMessages {
+			raw.Unset(lnwire.OnionMessagesOptional)
+			raw.Unset(lnwire.OnionMessagesRequired)
+		}
+		if cfg.NoTrampolineRouting {
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
+		}
 		if cfg.NoRouteBlinding {
 			raw.Unset(lnwire.RouteBlindingOptional)
 			raw.Unset(lnwire.RouteBlindingRequired)
@@ -0,0 +1,171 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// maxPolicyRejections is the maximum number of rejections the feature
+	// policy remembers. Once reached, the oldest rejection is forgotten.
+	maxPolicyRejections = 100
+)
+
+// ErrFeaturePolicy signals that a peer's feature vector violates the
+// configured feature policy.
+var ErrFeaturePolicy = errors.New("feature policy violated")
+
+// PolicyRejection records a peer that was rejected because its feature
+// vector violates the feature policy.
+type PolicyRejection struct {
+	// PubKey is the identity key of the rejected peer.
+	PubKey [33]byte
+
+	// Reason describes which part of the policy the peer violated.
+	Reason string
+
+	// Timestamp is the time the peer was last rejected.
+	Timestamp time.Time
+
+	// Count is the number of times the peer was rejected.
+	Count uint32
+}
+
+// Policy enforces the feature bits peers must or must not set in their init
+// message, and remembers the peers it rejected.
+type Policy struct {
+	required  []lnwire.FeatureBit
+	forbidden []lnwire.FeatureBit
+	clock     clock.Clock
+
+	mu         sync.Mutex
+	rejections []*PolicyRejection
+}
+
+// NewPolicy creates a feature policy requiring peers to set one of the
+// required bits' pair, and refusing peers that set any of the forbidden bits'
+// pair.
+func NewPolicy(required, forbidden []lnwire.FeatureBit,
+	clock clock.Clock) *Policy {
+
+	return &Policy{
+		required:  required,
+		forbidden: forbidden,
+		clock:     clock,
+	}
+}
+
+// IsActive returns true if the policy requires or forbids any feature bits.
+func (p *Policy) IsActive() bool {
+	return p != nil && (len(p.required) > 0 || len(p.forbidden) > 0)
+}
+
+// hasFeaturePair returns true if either the required or the optional bit of
+// the given feature is set in the feature vector.
+func hasFeaturePair(fv *lnwire.FeatureVector, bit lnwire.FeatureBit) bool {
+	return fv.IsSet(mandatoryBit(bit)) || fv.IsSet(optionalBit(bit))
+}
+
+// mandatoryBit returns the required bit of the given feature's pair.
+func mandatoryBit(bit lnwire.FeatureBit) lnwire.FeatureBit {
+	return bit &^ 1
+}
+
+// optionalBit returns the optional bit of the given feature's pair.
+func optionalBit(bit lnwire.FeatureBit) lnwire.FeatureBit {
+	return bit | 1
+}
+
+// Check validates the feature vector of the given peer against the policy. If
+// the peer violates it, the rejection is recorded and an error wrapping
+// ErrFeaturePolicy is returned. A nil policy accepts all peers.
+func (p *Policy) Check(pubKey [33]byte, fv *lnwire.FeatureVector) error {
+	if !p.IsActive() {
+		return nil
+	}
+
+	var reason string
+	for _, bit := range p.required {
+		if !hasFeaturePair(fv, bit) {
+			reason = fmt.Sprintf("required feature %v not set",
+				fv.Name(bit))
+
+			break
+		}
+	}
+	for _, bit := range p.forbidden {
+		if reason != "" {
+			break
+		}
+		if hasFeaturePair(fv, bit) {
+			reason = fmt.Sprintf("forbidden feature %v set",
+				fv.Name(bit))
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+
+	p.recordRejection(pubKey, reason)
+
+	return fmt.Errorf("%w: %v", ErrFeaturePolicy, reason)
+}
+
+// recordRejection records that the given peer was rejected for the given
+// reason.
+func (p *Policy) recordRejection(pubKey [33]byte, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.clock.Now()
+
+	// Peers that reconnect are only listed once, with their latest
+	// rejection.
+	for i, rejection := range p.rejections {
+		if rejection.PubKey != pubKey {
+			continue
+		}
+
+		rejection.Reason = reason
+		rejection.Timestamp = now
+		rejection.Count++
+
+		p.rejections = append(p.rejections[:i], p.rejections[i+1:]...)
+		p.rejections = append(p.rejections, rejection)
+
+		return
+	}
+
+	if len(p.rejections) == maxPolicyRejections {
+		p.rejections = p.rejections[1:]
+	}
+	p.rejections = append(p.rejections, &PolicyRejection{
+		PubKey:    pubKey,
+		Reason:    reason,
+		Timestamp: now,
+		Count:     1,
+	})
+}
+
+// Rejections returns the peers rejected by the policy, starting with the most
+// recent rejection.
+func (p *Policy) Rejections() []PolicyRejection {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rejections := make([]PolicyRejection, 0, len(p.rejections))
+	for i := len(p.rejections) - 1; i >= 0; i-- {
+		rejections = append(rejections, *p.rejections[i])
+	}
+
+	return rejections
+}
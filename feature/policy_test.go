@@ -0,0 +1,72 @@
+package feature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPolicyCheck tests that the feature policy rejects peers that don't set
+// a required feature or set a forbidden one, and remembers them.
+func TestPolicyCheck(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	policy := NewPolicy(
+		[]lnwire.FeatureBit{lnwire.AnchorsZeroFeeHtlcTxOptional},
+		[]lnwire.FeatureBit{lnwire.ScidAliasRequired}, testClock,
+	)
+	require.True(t, policy.IsActive())
+
+	newFeatures := func(bits ...lnwire.FeatureBit) *lnwire.FeatureVector {
+		return lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(bits...), lnwire.Features,
+		)
+	}
+	peer1, peer2 := [33]byte{1}, [33]byte{2}
+
+	// Either bit of a required feature satisfies the policy.
+	require.NoError(t, policy.Check(peer1, newFeatures(
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	)))
+	require.NoError(t, policy.Check(peer1, newFeatures(
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+	)))
+	require.Empty(t, policy.Rejections())
+
+	// Peers without the required feature are rejected.
+	err := policy.Check(peer1, newFeatures())
+	require.ErrorIs(t, err, ErrFeaturePolicy)
+
+	// Either bit of a forbidden feature violates the policy.
+	testClock.SetTime(time.Unix(2000, 0))
+	err = policy.Check(peer2, newFeatures(
+		lnwire.AnchorsZeroFeeHtlcTxOptional, lnwire.ScidAliasOptional,
+	))
+	require.ErrorIs(t, err, ErrFeaturePolicy)
+
+	rejections := policy.Rejections()
+	require.Len(t, rejections, 2)
+	require.Equal(t, peer2, rejections[0].PubKey)
+	require.Contains(t, rejections[0].Reason, "forbidden")
+	require.Equal(t, time.Unix(2000, 0), rejections[0].Timestamp)
+	require.Equal(t, peer1, rejections[1].PubKey)
+	require.Contains(t, rejections[1].Reason, "required")
+
+	// A peer that is rejected again is only listed once, as the most
+	// recent rejection.
+	require.Error(t, policy.Check(peer1, newFeatures()))
+	rejections = policy.Rejections()
+	require.Len(t, rejections, 2)
+	require.Equal(t, peer1, rejections[0].PubKey)
+	require.EqualValues(t, 2, rejections[0].Count)
+
+	// A nil policy accepts all peers.
+	var nilPolicy *Policy
+	require.False(t, nilPolicy.IsActive())
+	require.NoError(t, nilPolicy.Check(peer1, newFeatures()))
+	require.Empty(t, nilPolicy.Rejections())
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwallet/psbtv2"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -274,7 +275,7 @@ func (b *Batcher) BatchFund(ctx context.Context,
 		fundingReq.PendingChanID = pendingChanID
 		fundingReq.ChanFunder = chanfunding.NewPsbtAssembler(
 			btcutil.Amount(rpcChannel.LocalFundingAmount), nil,
-			b.cfg.NetParams, false,
+			psbtv2.Version0, b.cfg.NetParams, false,
 		)
 
 		b.channels = append(b.channels, &batchChannel{
@@ -0,0 +1,544 @@
+package funding
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// chanAnn2State holds the state of the MuSig2 signing of the
+// ChannelAnnouncement2 of a taproot channel. Both peers sign the announcement
+// with their node key and their bitcoin key, so each side contributes two
+// nonces and a partial signature that is the sum of the partial signatures of
+// both of its keys.
+type chanAnn2State struct {
+	// channel is the channel that is being announced.
+	channel *channeldb.OpenChannel
+
+	// localNodeNonces and localBtcNonces are the nonces we sign with our
+	// node key and our bitcoin key. They are set to nil once they have
+	// been used to sign the announcement.
+	localNodeNonces *musig2.Nonces
+	localBtcNonces  *musig2.Nonces
+
+	// remoteNodeNonce and remoteBtcNonce are the latest nonces the remote
+	// peer sent us. They are only valid if haveRemoteNonces is set.
+	remoteNodeNonce  lnwire.Musig2Nonce
+	remoteBtcNonce   lnwire.Musig2Nonce
+	haveRemoteNonces bool
+
+	// readyToSign is set once the funding transaction has reached six
+	// confirmations, or if the channel was already announced by us.
+	readyToSign bool
+
+	// ann is the announcement we sign. It is set together with
+	// readyToSign.
+	ann *lnwire.ChannelAnnouncement2
+
+	// localSigs are the partial signatures of our node key and bitcoin
+	// key, which are set once we've signed the announcement.
+	localSigs []*musig2.PartialSignature
+
+	// remoteSig is the latest partial signature the remote peer sent us.
+	remoteSig *lnwire.PartialSig
+
+	// complete is set once the fully signed announcement has been
+	// assembled.
+	complete bool
+
+	// done is closed once the fully signed announcement has been handed
+	// to the gossiper.
+	done chan struct{}
+}
+
+// isTaprootGossipChan returns true if the given channel is a public taproot
+// channel that is announced with a ChannelAnnouncement2, which requires both
+// peers to understand taproot gossip.
+func isTaprootGossipChan(channel *channeldb.OpenChannel,
+	peer lnpeer.Peer) bool {
+
+	public := channel.ChannelFlags&lnwire.FFAnnounceChannel != 0
+
+	return channel.ChanType.IsTaproot() && public && hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.TaprootGossipOptionalStaging,
+	)
+}
+
+// genAnn2Nonces generates a fresh pair of nonces for our node key and bitcoin
+// key.
+func (f *Manager) genAnn2Nonces(state *chanAnn2State) error {
+	nodeNonces, err := musig2.GenNonces(musig2.WithPublicKey(f.cfg.IDKey))
+	if err != nil {
+		return err
+	}
+	btcNonces, err := musig2.GenNonces(musig2.WithPublicKey(
+		state.channel.LocalChanCfg.MultiSigKey.PubKey,
+	))
+	if err != nil {
+		return err
+	}
+
+	state.localNodeNonces = nodeNonces
+	state.localBtcNonces = btcNonces
+
+	return nil
+}
+
+// loadAnn2State returns the announcement signing state of the given channel,
+// creating it if it doesn't exist yet. The returned boolean is true if the
+// state was created, in which case our nonces still need to be sent to the
+// peer.
+//
+// NOTE: The ann2Mtx must be held when calling this method.
+func (f *Manager) loadAnn2State(channel *channeldb.OpenChannel) (
+	*chanAnn2State, bool, error) {
+
+	chanID := lnwire.NewChanIDFromOutPoint(channel.FundingOutpoint)
+	if state, ok := f.ann2States[chanID]; ok {
+		return state, false, nil
+	}
+
+	state := &chanAnn2State{
+		channel: channel,
+		done:    make(chan struct{}),
+	}
+	if err := f.genAnn2Nonces(state); err != nil {
+		return nil, false, err
+	}
+
+	f.ann2States[chanID] = state
+
+	return state, true, nil
+}
+
+// addAnn2Nonces adds our announcement nonces of the given channel to the
+// channel_ready message.
+//
+// NOTE: The ann2Mtx must be held when calling this method.
+func (f *Manager) addAnn2Nonces(state *chanAnn2State,
+	msg *lnwire.ChannelReady) {
+
+	msg.AnnouncementNodeNonce = tlv.SomeRecordT(
+		tlv.NewRecordT[tlv.TlvType0](
+			lnwire.Musig2Nonce(state.localNodeNonces.PubNonce),
+		),
+	)
+	msg.AnnouncementBitcoinNonce = tlv.SomeRecordT(
+		tlv.NewRecordT[tlv.TlvType2](
+			lnwire.Musig2Nonce(state.localBtcNonces.PubNonce),
+		),
+	)
+}
+
+// sendAnn2ChannelReady sends a channel_ready message that carries our current
+// announcement nonces to the peer. All other fields of the message are the
+// same as in the channel_ready we sent before, so the peer can process it in
+// case it didn't receive the original one.
+func (f *Manager) sendAnn2ChannelReady(peer lnpeer.Peer,
+	state *chanAnn2State) error {
+
+	channel := state.channel
+	chanID := lnwire.NewChanIDFromOutPoint(channel.FundingOutpoint)
+
+	secondPoint, err := channel.SecondCommitmentPoint()
+	if err != nil {
+		return fmt.Errorf("unable to fetch second commitment point: "+
+			"%w", err)
+	}
+	channelReadyMsg := lnwire.NewChannelReady(chanID, secondPoint)
+
+	firstVerNonce, err := genFirstStateMusigNonce(channel)
+	if err != nil {
+		return err
+	}
+	channelReadyMsg.NextLocalNonce = lnwire.SomeMusig2Nonce(
+		firstVerNonce.PubNonce,
+	)
+
+	if channel.NegotiatedAliasFeature() {
+		aliases := f.cfg.AliasManager.GetAliases(
+			channel.ShortChannelID,
+		)
+		if len(aliases) != 0 {
+			channelReadyMsg.AliasScid = &aliases[0]
+		}
+	}
+
+	f.ann2Mtx.Lock()
+	f.addAnn2Nonces(state, channelReadyMsg)
+	f.ann2Mtx.Unlock()
+
+	return peer.SendMessage(true, channelReadyMsg)
+}
+
+// newChanAnnouncement2 creates the unsigned ChannelAnnouncement2 of the given
+// taproot channel. It matches the edge that was added to the graph for the
+// channel, so the gossiper can add the proof to it.
+func (f *Manager) newChanAnnouncement2(channel *channeldb.OpenChannel,
+	shortChanID lnwire.ShortChannelID) *lnwire.ChannelAnnouncement2 {
+
+	ann := lnwire.NewChannelAnnouncement2()
+	ann.ChainHash.Val = *f.cfg.Wallet.Cfg.NetParams.GenesisHash
+	ann.Features.Val = *lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.SimpleTaprootChannelsRequiredStaging,
+		), lnwire.Features,
+	)
+	ann.ShortChannelID.Val = shortChanID
+	ann.Capacity.Val = uint64(channel.Capacity)
+
+	var (
+		localKey  = f.cfg.IDKey.SerializeCompressed()
+		remoteKey = channel.IdentityPub.SerializeCompressed()
+		localBtc  = channel.LocalChanCfg.MultiSigKey.PubKey
+		remoteBtc = channel.RemoteChanCfg.MultiSigKey.PubKey
+	)
+
+	// The node with the lexicographically lower identity key is the first
+	// node of the channel.
+	nodeKey1, nodeKey2 := localKey, remoteKey
+	btcKey1, btcKey2 := localBtc, remoteBtc
+	if bytes.Compare(localKey, remoteKey) == 1 {
+		nodeKey1, nodeKey2 = remoteKey, localKey
+		btcKey1, btcKey2 = remoteBtc, localBtc
+	}
+
+	copy(ann.NodeID1.Val[:], nodeKey1)
+	copy(ann.NodeID2.Val[:], nodeKey2)
+
+	var key1, key2 [33]byte
+	copy(key1[:], btcKey1.SerializeCompressed())
+	copy(key2[:], btcKey2.SerializeCompressed())
+	ann.BitcoinKey1 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType12](key1),
+	)
+	ann.BitcoinKey2 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType14](key2),
+	)
+
+	return ann
+}
+
+// signAnn2 signs the announcement of the channel with our node key and our
+// bitcoin key, if we're ready to sign and know the nonces of the peer. The
+// returned AnnounceSignatures2 message is nil if we can't sign yet.
+//
+// NOTE: The ann2Mtx must be held when calling this method.
+func (f *Manager) signAnn2(state *chanAnn2State) (*lnwire.AnnounceSignatures2,
+	error) {
+
+	if !state.readyToSign || !state.haveRemoteNonces ||
+		state.localNodeNonces == nil {
+
+		return nil, nil
+	}
+
+	channel := state.channel
+	digest, err := state.ann.DigestToSign()
+	if err != nil {
+		return nil, err
+	}
+
+	signer := f.cfg.Wallet.Cfg.Signer
+	signingKeys := []*btcec.PublicKey{
+		f.cfg.IDKey, channel.IdentityPub,
+		channel.LocalChanCfg.MultiSigKey.PubKey,
+		channel.RemoteChanCfg.MultiSigKey.PubKey,
+	}
+
+	// sign creates the partial signature of one of our keys, using the
+	// given local nonces and the nonces of all other signing keys.
+	sign := func(keyLoc keychain.KeyLocator, localNonces *musig2.Nonces,
+		otherNonces ...[musig2.PubNonceSize]byte) (
+		*musig2.PartialSignature, error) {
+
+		session, err := signer.MuSig2CreateSession(
+			input.MuSig2Version100RC2, keyLoc, signingKeys,
+			&input.MuSig2Tweaks{}, otherNonces, localNonces,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return signer.MuSig2Sign(session.SessionID, *digest, true)
+	}
+
+	nodeSig, err := sign(
+		f.cfg.IDKeyLoc, state.localNodeNonces,
+		state.localBtcNonces.PubNonce, state.remoteNodeNonce,
+		state.remoteBtcNonce,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign with node key: %w", err)
+	}
+	btcSig, err := sign(
+		channel.LocalChanCfg.MultiSigKey.KeyLocator,
+		state.localBtcNonces, state.localNodeNonces.PubNonce,
+		state.remoteNodeNonce, state.remoteBtcNonce,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign with bitcoin key: %w",
+			err)
+	}
+
+	// Our nonces must never be used again.
+	state.localNodeNonces = nil
+	state.localBtcNonces = nil
+	state.localSigs = []*musig2.PartialSignature{nodeSig, btcSig}
+
+	// We send the sum of both partial signatures, which is all the peer
+	// needs to combine the final signature.
+	var sig btcec.ModNScalar
+	sig.Set(nodeSig.S).Add(btcSig.S)
+
+	msg := &lnwire.AnnounceSignatures2{}
+	msg.ChannelID.Val = lnwire.NewChanIDFromOutPoint(
+		channel.FundingOutpoint,
+	)
+	msg.ShortChannelID.Val = state.ann.ShortChannelID.Val
+	msg.PartialSignature.Val = lnwire.NewPartialSig(sig)
+
+	return msg, nil
+}
+
+// combineAnn2 combines our partial signatures with the one of the peer. If
+// the resulting signature is valid, the complete announcement is sent to the
+// gossiper.
+func (f *Manager) combineAnn2(state *chanAnn2State) error {
+	f.ann2Mtx.Lock()
+	if state.complete || state.localSigs == nil || state.remoteSig == nil {
+		f.ann2Mtx.Unlock()
+		return nil
+	}
+
+	nonce := state.localSigs[0].R
+	remoteSig := musig2.NewPartialSignature(&state.remoteSig.Sig, nonce)
+	sig := musig2.CombineSigs(nonce, []*musig2.PartialSignature{
+		state.localSigs[0], state.localSigs[1], &remoteSig,
+	})
+
+	ann := *state.ann
+	var err error
+	ann.Signature, err = lnwire.NewSigFromSchnorrRawSignature(
+		sig.Serialize(),
+	)
+	if err != nil {
+		f.ann2Mtx.Unlock()
+		return err
+	}
+
+	fundingScript, err := makeFundingScript(state.channel)
+	if err != nil {
+		f.ann2Mtx.Unlock()
+		return err
+	}
+
+	// The signature of the peer may have been created with outdated
+	// nonces, in which case we wait for the next one.
+	if err := routing.ValidateChannelAnn2(&ann, fundingScript); err != nil {
+		state.remoteSig = nil
+		f.ann2Mtx.Unlock()
+
+		return fmt.Errorf("invalid announcement signature: %w", err)
+	}
+
+	state.complete = true
+	f.ann2Mtx.Unlock()
+
+	errChan := f.cfg.SendAnnouncement(&ann)
+	select {
+	case err := <-errChan:
+		if err != nil {
+			if routing.IsError(err, routing.ErrOutdated,
+				routing.ErrIgnored) {
+
+				log.Debugf("Router rejected "+
+					"ChannelAnnouncement2: %v", err)
+			} else {
+				// Allow the announcement to be sent again
+				// once the next signature arrives.
+				f.ann2Mtx.Lock()
+				state.complete = false
+				f.ann2Mtx.Unlock()
+
+				return fmt.Errorf("unable to send channel "+
+					"announcement: %w", err)
+			}
+		}
+
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+
+	close(state.done)
+
+	return nil
+}
+
+// processAnn2Nonces handles the announcement nonces the peer sent us in its
+// channel_ready message. If we already signed the announcement with nonces of
+// the peer that are now outdated, we'll sign again with fresh nonces.
+func (f *Manager) processAnn2Nonces(peer lnpeer.Peer,
+	channel *channeldb.OpenChannel, nodeNonce,
+	btcNonce lnwire.Musig2Nonce) error {
+
+	f.ann2Mtx.Lock()
+	state, resend, err := f.loadAnn2State(channel)
+	if err != nil {
+		f.ann2Mtx.Unlock()
+		return err
+	}
+
+	// If we don't know of the announcement yet, then we're either waiting
+	// for six confirmations, or we announced the channel before. In the
+	// latter case, the peer lost its state and needs our signature again.
+	if state.ann == nil {
+		_, _, err := f.getChannelOpeningState(&channel.FundingOutpoint)
+		if err == channeldb.ErrChannelNotFound {
+			shortChanID := channel.ShortChanID()
+			if channel.IsZeroConf() {
+				shortChanID = channel.ZeroConfRealScid()
+			}
+
+			state.ann = f.newChanAnnouncement2(channel, shortChanID)
+			state.readyToSign = true
+		}
+	}
+
+	changed := !state.haveRemoteNonces ||
+		state.remoteNodeNonce != nodeNonce ||
+		state.remoteBtcNonce != btcNonce
+
+	// If we already used our nonces with the previous nonces of the peer,
+	// we need new ones to sign again.
+	if changed && state.localNodeNonces == nil {
+		if err := f.genAnn2Nonces(state); err != nil {
+			f.ann2Mtx.Unlock()
+			return err
+		}
+
+		state.localSigs = nil
+		resend = true
+	}
+
+	state.remoteNodeNonce = nodeNonce
+	state.remoteBtcNonce = btcNonce
+	state.haveRemoteNonces = true
+
+	sigMsg, err := f.signAnn2(state)
+	f.ann2Mtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Our nonces must reach the peer before our signature does.
+	if resend {
+		if err := f.sendAnn2ChannelReady(peer, state); err != nil {
+			return err
+		}
+	}
+	if sigMsg != nil {
+		if err := peer.SendMessage(true, sigMsg); err != nil {
+			return err
+		}
+	}
+
+	return f.combineAnn2(state)
+}
+
+// handleAnnounceSignatures2 handles the partial signature of the announcement
+// of a taproot channel that the peer sent us.
+func (f *Manager) handleAnnounceSignatures2(peer lnpeer.Peer,
+	msg *lnwire.AnnounceSignatures2) {
+
+	defer f.wg.Done()
+
+	chanID := msg.ChannelID.Val
+
+	f.ann2Mtx.Lock()
+	state, ok := f.ann2States[chanID]
+	if !ok {
+		f.ann2Mtx.Unlock()
+		log.Warnf("Received AnnounceSignatures2 for unknown "+
+			"ChannelID(%v) from peer %x", chanID,
+			peer.IdentityKey().SerializeCompressed())
+
+		return
+	}
+
+	remoteSig := msg.PartialSignature.Val
+	state.remoteSig = &remoteSig
+	f.ann2Mtx.Unlock()
+
+	if err := f.combineAnn2(state); err != nil {
+		log.Errorf("Unable to complete announcement of "+
+			"ChannelID(%v): %v", chanID, err)
+	}
+}
+
+// announceTaprootChannel signs the ChannelAnnouncement2 of the given taproot
+// channel together with the peer. It returns once the complete announcement
+// has been sent to the gossiper.
+func (f *Manager) announceTaprootChannel(channel *channeldb.OpenChannel,
+	shortChanID lnwire.ShortChannelID) error {
+
+	peer, err := f.waitForPeerOnline(channel.IdentityPub)
+	if err != nil {
+		return err
+	}
+	if !isTaprootGossipChan(channel, peer) {
+		return fmt.Errorf("taproot gossip not negotiated with peer %x",
+			peer.IdentityKey().SerializeCompressed())
+	}
+
+	f.ann2Mtx.Lock()
+	state, resend, err := f.loadAnn2State(channel)
+	if err != nil {
+		f.ann2Mtx.Unlock()
+		return err
+	}
+
+	state.ann = f.newChanAnnouncement2(channel, shortChanID)
+	state.readyToSign = true
+
+	sigMsg, err := f.signAnn2(state)
+	f.ann2Mtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if resend {
+		if err := f.sendAnn2ChannelReady(peer, state); err != nil {
+			return err
+		}
+	}
+	if sigMsg != nil {
+		if err := peer.SendMessage(true, sigMsg); err != nil {
+			return err
+		}
+	}
+
+	// The signature of the peer may have arrived before we signed.
+	if err := f.combineAnn2(state); err != nil {
+		log.Debugf("Unable to complete announcement of "+
+			"ChannelPoint(%v): %v", channel.FundingOutpoint, err)
+	}
+
+	select {
+	case <-state.done:
+		return nil
+
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+}
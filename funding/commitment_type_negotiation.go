@@ -365,3 +365,18 @@ func hasFeatures(local, remote *lnwire.FeatureVector,
 	}
 	return true
 }
+
+// NegotiateCommitmentType returns the commitment type a new channel with the
+// desired channel type would use, given the features of both peers. This
+// allows callers to check whether a channel of that type can be opened with a
+// peer before sending the open_channel message. An error is returned if the
+// desired channel type is not supported by both peers.
+func NegotiateCommitmentType(desiredChanType *lnwire.ChannelType, local,
+	remote *lnwire.FeatureVector) (lnwallet.CommitmentType, error) {
+
+	_, commitType, err := negotiateCommitmentType(
+		desiredChanType, local, remote,
+	)
+
+	return commitType, err
+}
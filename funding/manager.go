@@ -32,6 +32,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwallet/psbtv2"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing"
 	"golang.org/x/crypto/salsa20"
@@ -53,6 +54,10 @@ var (
 	// expected TLV.
 	errNoLocalNonce = fmt.Errorf("local nonce not found")
 
+	// errNoAnnNonce is returned when an announcement nonce is not found
+	// in the expected TLV.
+	errNoAnnNonce = fmt.Errorf("announcement nonce not found")
+
 	// errNoPartialSig is returned when a partial sig is not found in the
 	// expected TLV.
 	errNoPartialSig = fmt.Errorf("partial sig not found")
@@ -372,6 +377,11 @@ type Config struct {
 	// overwriting any existing labels.
 	UpdateLabel func(chainhash.Hash, string) error
 
+	// FetchNodeAlias is an optional function that returns the alias of
+	// the given node, which is added to the labels of funding
+	// transactions.
+	FetchNodeAlias func(*btcec.PublicKey) (string, error)
+
 	// FeeEstimator calculates appropriate fee rates based on historical
 	// transaction information.
 	FeeEstimator chainfee.Estimator
@@ -476,6 +486,22 @@ type Config struct {
 	// sub-systems.
 	ReportShortChanID func(wire.OutPoint) error
 
+	// ZeroConfMempoolActivation signals that zero-conf channels we fund
+	// should only become active once their funding transaction was
+	// accepted to the mempool.
+	ZeroConfMempoolActivation bool
+
+	// Mempool is used to watch the mempool for the funding transactions
+	// of zero-conf channels and transactions conflicting with them. It
+	// is nil if the chain backend doesn't support watching the mempool.
+	Mempool chainntnfs.MempoolWatcher
+
+	// BlockOutgoingAdds blocks or unblocks sending HTLCs over the channel
+	// with the given channel point. It is used to stop using zero-conf
+	// channels whose funding transaction conflicts with another
+	// transaction.
+	BlockOutgoingAdds func(chanPoint wire.OutPoint, block bool) error
+
 	// ZombieSweeperInterval is the periodic time interval in which the
 	// zombie sweeper is run.
 	ZombieSweeperInterval time.Duration
@@ -562,6 +588,10 @@ type Manager struct {
 	// was initialized with.
 	cfg *Config
 
+	// policyMtx guards the DefaultRoutingPolicy of the config, which can
+	// be changed while the manager is running.
+	policyMtx sync.RWMutex
+
 	// chanIDKey is a cryptographically random key that's used to generate
 	// temporary channel ID's.
 	chanIDKey [32]byte
@@ -582,6 +612,15 @@ type Manager struct {
 	// TODO(roasbeef): replace w/ generic concurrent map
 	pendingMusigNonces map[lnwire.ChannelID]*musig2.Nonces
 
+	// ann2Mtx guards the ann2States map and the states within it.
+	ann2Mtx sync.Mutex
+
+	// ann2States holds the signing state of the ChannelAnnouncement2 of
+	// the public taproot channels that are announced.
+	//
+	// NOTE: This map is protected by the ann2Mtx above.
+	ann2States map[lnwire.ChannelID]*chanAnn2State
+
 	// activeReservations is a map which houses the state of all pending
 	// funding workflows.
 	activeReservations map[serializedPubKey]pendingChannels
@@ -676,7 +715,8 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		pendingMusigNonces: make(
 			map[lnwire.ChannelID]*musig2.Nonces,
 		),
-		quit: make(chan struct{}),
+		ann2States: make(map[lnwire.ChannelID]*chanAnn2State),
+		quit:       make(chan struct{}),
 	}, nil
 }
 
@@ -738,6 +778,11 @@ func (f *Manager) start() error {
 			// zero-conf channels if we have the funding tx and are
 			// also the initiator.
 			f.rebroadcastFundingTx(channel)
+
+			if f.mempoolActivation(channel) {
+				f.wg.Add(1)
+				go f.watchFundingConflicts(channel)
+			}
 		}
 
 		// We will restart the funding state machine for all channels,
@@ -788,7 +833,7 @@ func (f *Manager) rebroadcastFundingTx(c *channeldb.OpenChannel) {
 
 	// Set a nil short channel ID at this stage because we do not know it
 	// until our funding tx confirms.
-	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+	label := f.openChannelLabel(c, nil)
 
 	err = f.cfg.PublishTransaction(c.FundingTxn, label)
 	if err != nil {
@@ -957,6 +1002,9 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, cid *chanIdentifier,
 	errMsg := &lnwire.Error{
 		ChanID: cid.tempChanID,
 		Data:   msg,
+		Code: lnwire.NewErrorCodeRecord(
+			lnwire.ErrorCodeFundingFailed,
+		),
 	}
 
 	log.Debugf("Sending funding error to peer (%x): %v",
@@ -976,6 +1024,9 @@ func (f *Manager) sendWarning(peer lnpeer.Peer, cid *chanIdentifier,
 	errMsg := &lnwire.Warning{
 		ChanID: cid.tempChanID,
 		Data:   lnwire.WarningData(msg),
+		Code: lnwire.NewErrorCodeRecord(
+			lnwire.ErrorCodeFundingFailed,
+		),
 	}
 
 	log.Debugf("Sending funding warning to peer (%x): %v",
@@ -1018,6 +1069,10 @@ func (f *Manager) reservationCoordinator() {
 				f.wg.Add(1)
 				go f.handleChannelReady(fmsg.peer, msg)
 
+			case *lnwire.AnnounceSignatures2:
+				f.wg.Add(1)
+				go f.handleAnnounceSignatures2(fmsg.peer, msg)
+
 			case *lnwire.Warning:
 				f.handleWarningMsg(fmsg.peer, msg)
 
@@ -1242,6 +1297,17 @@ func (f *Manager) advancePendingChannelState(
 	channel *channeldb.OpenChannel, pendingChanID [32]byte) error {
 
 	if channel.IsZeroConf() {
+		// If enabled, we only activate zero-conf channels we fund once
+		// their funding transaction was accepted to the mempool.
+		mempoolActivation := f.mempoolActivation(channel)
+		if mempoolActivation {
+			err := f.waitForMempoolAcceptance(channel)
+			if err != nil {
+				return fmt.Errorf("error waiting for mempool "+
+					"acceptance of funding tx: %w", err)
+			}
+		}
+
 		// Persist the alias to the alias database.
 		baseScid := channel.ShortChannelID
 		err := f.cfg.AliasManager.AddLocalAlias(
@@ -1285,6 +1351,14 @@ func (f *Manager) advancePendingChannelState(
 			close(discoverySignal)
 		}
 
+		// Until the funding transaction confirms, we watch for
+		// conflicting transactions that would prevent it from ever
+		// confirming.
+		if mempoolActivation {
+			f.wg.Add(1)
+			go f.watchFundingConflicts(channel)
+		}
+
 		return nil
 	}
 
@@ -1597,9 +1671,14 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 
 		return
 
-	// The current variant of taproot channels can only be used with
-	// unadvertised channels for now.
-	case commitType.IsTaproot() && public:
+	// Taproot channels can only be advertised if both sides understand
+	// taproot gossip.
+	case commitType.IsTaproot() && public &&
+		!hasFeatures(
+			peer.LocalFeatures(), peer.RemoteFeatures(),
+			lnwire.TaprootGossipOptionalStaging,
+		):
+
 		err = fmt.Errorf("taproot channel type for public channel")
 		log.Errorf("Cancelling funding flow for public taproot "+
 			"channel %v: %v", cid, err)
@@ -2117,8 +2196,9 @@ func (f *Manager) funderProcessAcceptChannel(peer lnpeer.Peer,
 			f.failFundingFlow(peer, cid, err)
 			return
 		}
-		var buf bytes.Buffer
-		err = packet.Serialize(&buf)
+		rawPsbt, err := psbtv2.Serialize(
+			packet, psbtErr.Intent.PsbtVersion,
+		)
 		if err != nil {
 			log.Errorf("Unable to serialize PSBT for "+
 				"contribution from %x: %v", peerKeyBytes, err)
@@ -2131,7 +2211,7 @@ func (f *Manager) funderProcessAcceptChannel(peer lnpeer.Peer,
 				PsbtFund: &lnrpc.ReadyForPsbtFunding{
 					FundingAddress: addr.EncodeAddress(),
 					FundingAmount:  amt,
-					Psbt:           buf.Bytes(),
+					Psbt:           rawPsbt,
 				},
 			},
 		}
@@ -2666,9 +2746,7 @@ func (f *Manager) funderProcessFundingSigned(peer lnpeer.Peer,
 
 		// Set a nil short channel ID at this stage because we do not
 		// know it until our funding tx confirms.
-		label := labels.MakeLabel(
-			labels.LabelTypeChannelOpen, nil,
-		)
+		label := f.openChannelLabel(completeChan, nil)
 
 		err = f.cfg.PublishTransaction(fundingTx, label)
 		if err != nil {
@@ -3064,9 +3142,7 @@ func (f *Manager) makeLabelForTx(c *channeldb.OpenChannel) {
 			shortChanID = c.ZeroConfRealScid()
 		}
 
-		label := labels.MakeLabel(
-			labels.LabelTypeChannelOpen, &shortChanID,
-		)
+		label := f.openChannelLabel(c, &shortChanID)
 
 		err := f.cfg.UpdateLabel(c.FundingOutpoint.Hash, label)
 		if err != nil {
@@ -3075,6 +3151,28 @@ func (f *Manager) makeLabelForTx(c *channeldb.OpenChannel) {
 	}
 }
 
+// openChannelLabel returns the label of the funding transaction of the given
+// channel, which contains its channel point and the alias of the peer if it is
+// known.
+func (f *Manager) openChannelLabel(c *channeldb.OpenChannel,
+	shortChanID *lnwire.ShortChannelID) string {
+
+	var alias string
+	if f.cfg.FetchNodeAlias != nil {
+		var err error
+		alias, err = f.cfg.FetchNodeAlias(c.IdentityPub)
+		if err != nil {
+			log.Debugf("Unable to fetch alias of peer %x: %v",
+				c.IdentityPub.SerializeCompressed(), err)
+		}
+	}
+
+	return labels.MakeChannelLabel(
+		labels.LabelTypeChannelOpen, shortChanID, c.FundingOutpoint,
+		alias,
+	)
+}
+
 // handleFundingConfirmation marks a channel as open in the database, and set
 // the channelOpeningState markedOpen. In addition it will report the now
 // decided short channel ID to the switch, and close the local discovery signal
@@ -3285,6 +3383,20 @@ func (f *Manager) sendChannelReady(completeChan *channeldb.OpenChannel,
 			}
 		}
 
+		// If the channel is announced with a ChannelAnnouncement2, we
+		// send along the nonces we'll sign the announcement with.
+		if isTaprootGossipChan(completeChan, peer) {
+			f.ann2Mtx.Lock()
+			state, _, err := f.loadAnn2State(completeChan)
+			if err == nil {
+				f.addAnn2Nonces(state, channelReadyMsg)
+			}
+			f.ann2Mtx.Unlock()
+			if err != nil {
+				return err
+			}
+		}
+
 		log.Infof("Peer(%x) is online, sending ChannelReady "+
 			"for ChannelID(%v)", peerKey, chanID)
 
@@ -3358,8 +3470,8 @@ func (f *Manager) extractAnnounceParams(c *channeldb.OpenChannel) (
 
 	// We don't necessarily want to go as low as the remote party allows.
 	// Check it against our default forwarding policy.
-	if fwdMinHTLC < f.cfg.DefaultRoutingPolicy.MinHTLCOut {
-		fwdMinHTLC = f.cfg.DefaultRoutingPolicy.MinHTLCOut
+	if fwdMinHTLC < f.defaultRoutingPolicy().MinHTLCOut {
+		fwdMinHTLC = f.defaultRoutingPolicy().MinHTLCOut
 	}
 
 	// We'll obtain the max HTLC value we can forward in our direction, as
@@ -3582,6 +3694,26 @@ func (f *Manager) annAfterSixConfs(completeChan *channeldb.OpenChannel,
 			}
 		}
 
+		// Taproot channels are announced with a ChannelAnnouncement2
+		// that both peers sign together.
+		if completeChan.ChanType.IsTaproot() {
+			err := f.announceTaprootChannel(
+				completeChan, *shortChanID,
+			)
+			if err != nil {
+				return fmt.Errorf("channel announcement "+
+					"failed: %w", err)
+			}
+
+			nodeAnn, err := f.cfg.CurrentNodeAnnouncement()
+			if err != nil {
+				return fmt.Errorf("can't generate node "+
+					"announcement: %w", err)
+			}
+
+			return f.sendNodeAnnouncement(&nodeAnn)
+		}
+
 		// Create and broadcast the proofs required to make this channel
 		// public and usable for other nodes for routing.
 		err = f.announceChannel(
@@ -3885,6 +4017,28 @@ func (f *Manager) handleChannelReady(peer lnpeer.Peer, //nolint:funlen
 		}
 	}
 
+	// If the peer sent the nonces it signs the announcement of a taproot
+	// channel with, we process them before the duplicate check below, as
+	// they are resent whenever the peer needs to sign again.
+	if isTaprootGossipChan(channel, peer) {
+		nodeNonce, nodeErr := msg.AnnouncementNodeNonce.UnwrapOrErrV(
+			errNoAnnNonce,
+		)
+		btcNonce, btcErr := msg.AnnouncementBitcoinNonce.UnwrapOrErrV(
+			errNoAnnNonce,
+		)
+		if nodeErr == nil && btcErr == nil {
+			err := f.processAnn2Nonces(
+				peer, channel, nodeNonce, btcNonce,
+			)
+			if err != nil {
+				log.Errorf("Unable to process announcement "+
+					"nonces of ChannelID(%v): %v", chanID,
+					err)
+			}
+		}
+	}
+
 	// If the RemoteNextRevocation is non-nil, it means that we have
 	// already processed channelReady for this channel, so ignore. This
 	// check is after the alias logic so we store the peer's most recent
@@ -4203,7 +4357,7 @@ func (f *Manager) newChanAnnouncement(localPubKey,
 		MessageFlags:   msgFlags,
 		ChannelFlags:   chanFlags,
 		TimeLockDelta: uint16(
-			f.cfg.DefaultRoutingPolicy.TimeLockDelta,
+			f.defaultRoutingPolicy().TimeLockDelta,
 		),
 		HtlcMinimumMsat: fwdMinHTLC,
 		HtlcMaximumMsat: fwdMaxHTLC,
@@ -4233,20 +4387,36 @@ func (f *Manager) newChanAnnouncement(localPubKey,
 			ourPolicy.FeeProportionalMillionths,
 		)
 
+		// Keep the inbound fee and any other extra data of our
+		// current policy.
+		chanUpdateAnn.ExtraOpaqueData = ourPolicy.ExtraOpaqueData
+
 	case storedFwdingPolicy != nil:
 		chanUpdateAnn.BaseFee = uint32(storedFwdingPolicy.BaseFee)
 		chanUpdateAnn.FeeRate = uint32(storedFwdingPolicy.FeeRate)
 
+		err := setInboundFee(chanUpdateAnn, storedFwdingPolicy.InboundFee)
+		if err != nil {
+			return nil, err
+		}
+
 	default:
 		log.Infof("No channel forwarding policy specified for channel "+
 			"announcement of ChannelID(%v). "+
 			"Assuming default fee parameters.", chanID)
 		chanUpdateAnn.BaseFee = uint32(
-			f.cfg.DefaultRoutingPolicy.BaseFee,
+			f.defaultRoutingPolicy().BaseFee,
 		)
 		chanUpdateAnn.FeeRate = uint32(
-			f.cfg.DefaultRoutingPolicy.FeeRate,
+			f.defaultRoutingPolicy().FeeRate,
 		)
+
+		err := setInboundFee(
+			chanUpdateAnn, f.defaultRoutingPolicy().InboundFee,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// With the channel update announcement constructed, we'll generate a
@@ -4377,7 +4547,12 @@ func (f *Manager) announceChannel(localIDKey, remoteIDKey *btcec.PublicKey,
 		return err
 	}
 
-	errChan = f.cfg.SendAnnouncement(&nodeAnn)
+	return f.sendNodeAnnouncement(&nodeAnn)
+}
+
+// sendNodeAnnouncement sends our node announcement to the gossiper.
+func (f *Manager) sendNodeAnnouncement(nodeAnn *lnwire.NodeAnnouncement) error {
+	errChan := f.cfg.SendAnnouncement(nodeAnn)
 	select {
 	case err := <-errChan:
 		if err != nil {
@@ -4565,6 +4740,22 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		}
 	}
 
+	// Taproot channels can only be advertised if both sides understand
+	// taproot gossip.
+	if commitType.IsTaproot() && !msg.Private &&
+		!hasFeatures(
+			msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
+			lnwire.TaprootGossipOptionalStaging,
+		) {
+
+		err = fmt.Errorf("taproot channel type for public channel " +
+			"requires taproot gossip")
+		log.Error(err)
+		msg.Err <- err
+
+		return
+	}
+
 	// First, we'll query the fee estimator for a fee that should get the
 	// commitment transaction confirmed by the next few blocks (conf target
 	// of 3). We target the near blocks here to ensure that we'll be able
@@ -5026,6 +5217,42 @@ func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
 	return btcec.NewPublicKey(&tmp.X, &tmp.Y)
 }
 
+// setInboundFee adds the given inbound fee to the extra data of the channel
+// update. A zero inbound fee is omitted, as that's the default.
+func setInboundFee(chanUpdate *lnwire.ChannelUpdate,
+	inboundFee models.InboundFee) error {
+
+	if inboundFee == (models.InboundFee{}) {
+		return nil
+	}
+
+	wireFee := inboundFee.ToWire()
+	err := chanUpdate.ExtraOpaqueData.PackRecords(&wireFee)
+	if err != nil {
+		return errors.Errorf("unable to encode inbound fee: %v", err)
+	}
+
+	return nil
+}
+
+// defaultRoutingPolicy returns the routing policy new channels are opened
+// with.
+func (f *Manager) defaultRoutingPolicy() models.ForwardingPolicy {
+	f.policyMtx.RLock()
+	defer f.policyMtx.RUnlock()
+
+	return f.cfg.DefaultRoutingPolicy
+}
+
+// SetDefaultRoutingPolicy changes the routing policy new channels are opened
+// with. Channels that are already open keep their policy.
+func (f *Manager) SetDefaultRoutingPolicy(policy models.ForwardingPolicy) {
+	f.policyMtx.Lock()
+	defer f.policyMtx.Unlock()
+
+	f.cfg.DefaultRoutingPolicy = policy
+}
+
 // defaultForwardingPolicy returns the default forwarding policy based on the
 // default routing policy and our local channel constraints.
 func (f *Manager) defaultForwardingPolicy(
@@ -5034,9 +5261,10 @@ func (f *Manager) defaultForwardingPolicy(
 	return &models.ForwardingPolicy{
 		MinHTLCOut:    constraints.MinHTLC,
 		MaxHTLC:       constraints.MaxPendingAmount,
-		BaseFee:       f.cfg.DefaultRoutingPolicy.BaseFee,
-		FeeRate:       f.cfg.DefaultRoutingPolicy.FeeRate,
-		TimeLockDelta: f.cfg.DefaultRoutingPolicy.TimeLockDelta,
+		BaseFee:       f.defaultRoutingPolicy().BaseFee,
+		FeeRate:       f.defaultRoutingPolicy().FeeRate,
+		TimeLockDelta: f.defaultRoutingPolicy().TimeLockDelta,
+		InboundFee:    f.defaultRoutingPolicy().InboundFee,
 	}
 }
 
@@ -40,6 +40,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/stretchr/testify/require"
 )
 
@@ -799,8 +800,11 @@ func fundChannel(t *testing.T, alice, bob *testNode, localFundingAmt,
 	}
 
 	// If this is a taproot channel, then we want to force it to be a
-	// private channel, as that's the only channel type supported for now.
-	if isTaprootChanType(chanType) {
+	// private channel, unless both peers understand taproot gossip.
+	taprootGossip := bob.LocalFeatures().HasFeature(
+		lnwire.TaprootGossipOptionalStaging,
+	)
+	if isTaprootChanType(chanType) && !taprootGossip {
 		initReq.Private = true
 	}
 
@@ -983,6 +987,8 @@ func assertFundingMsgSent(t *testing.T, msgChan chan lnwire.Message,
 		sentMsg, ok = msg.(*lnwire.FundingSigned)
 	case "ChannelReady":
 		sentMsg, ok = msg.(*lnwire.ChannelReady)
+	case "AnnounceSignatures2":
+		sentMsg, ok = msg.(*lnwire.AnnounceSignatures2)
 	case "Error":
 		sentMsg, ok = msg.(*lnwire.Error)
 	default:
@@ -1592,6 +1598,145 @@ func TestFundingManagerNormalWorkflow(t *testing.T) {
 	}
 }
 
+// withTaprootGossipSigner makes the signer of the funding manager sign MuSig2
+// sessions of the node key with the private key of the node, which the
+// ChannelAnnouncement2 of a taproot channel is signed with.
+func withTaprootGossipSigner(cfg *Config) {
+	nodeKey := alicePrivKey
+	if cfg.IDKey.IsEqual(bobPrivKey.PubKey()) {
+		nodeKey = bobPrivKey
+	}
+
+	signer, ok := cfg.Wallet.Cfg.Signer.(*mock.SingleSigner)
+	if !ok {
+		return
+	}
+	signer.MusigSessionManager = input.NewMusigSessionManager(
+		func(desc *keychain.KeyDescriptor) (*btcec.PrivateKey, error) {
+			if desc.KeyLocator == cfg.IDKeyLoc {
+				return nodeKey, nil
+			}
+
+			return alicePrivKey, nil
+		},
+	)
+}
+
+// TestFundingManagerTaprootGossip tests that a public taproot channel is
+// announced with a ChannelAnnouncement2 that both peers sign together, if
+// both of them understand taproot gossip.
+func TestFundingManagerTaprootGossip(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t, withTaprootGossipSigner)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	featureBits := []lnwire.FeatureBit{
+		lnwire.ExplicitChannelTypeOptional,
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+		lnwire.SimpleTaprootChannelsOptionalStaging,
+		lnwire.TaprootGossipOptionalStaging,
+	}
+	alice.localFeatures = featureBits
+	alice.remoteFeatures = featureBits
+	bob.localFeatures = featureBits
+	bob.remoteFeatures = featureBits
+
+	chanType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.SimpleTaprootChannelsRequiredStaging,
+	))
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	localAmt := btcutil.Amount(500000)
+	fundingOutPoint, fundingTx := openChannel(
+		t, alice, bob, localAmt, 0, 1, updateChan, true, &chanType,
+	)
+
+	alice.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	bob.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	assertMarkedOpen(t, alice, bob, fundingOutPoint)
+
+	// Both channel_ready messages carry the nonces the announcement is
+	// signed with.
+	channelReadyAlice := assertFundingMsgSent(
+		t, alice.msgChan, "ChannelReady",
+	).(*lnwire.ChannelReady)
+	channelReadyBob := assertFundingMsgSent(
+		t, bob.msgChan, "ChannelReady",
+	).(*lnwire.ChannelReady)
+	for _, msg := range []*lnwire.ChannelReady{
+		channelReadyAlice, channelReadyBob,
+	} {
+		require.True(t, msg.AnnouncementNodeNonce.IsSome())
+		require.True(t, msg.AnnouncementBitcoinNonce.IsSome())
+	}
+
+	assertChannelReadySent(t, alice, bob, fundingOutPoint)
+
+	alice.fundingMgr.ProcessFundingMsg(channelReadyBob, bob)
+	bob.fundingMgr.ProcessFundingMsg(channelReadyAlice, alice)
+
+	assertHandleChannelReady(t, alice, bob)
+	assertChannelAnnouncements(
+		t, alice, bob, localAmt, nil, nil, nil, nil,
+	)
+	assertAddedToRouterGraph(t, alice, bob, fundingOutPoint)
+	waitForOpenUpdate(t, updateChan)
+
+	// Once six confirmations are reached, the peers exchange their partial
+	// signatures of the announcement.
+	alice.mockNotifier.sixConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+	bob.mockNotifier.sixConfChannel <- &chainntnfs.TxConfirmation{
+		Tx: fundingTx,
+	}
+
+	sigsAlice := assertFundingMsgSent(
+		t, alice.msgChan, "AnnounceSignatures2",
+	).(*lnwire.AnnounceSignatures2)
+	sigsBob := assertFundingMsgSent(
+		t, bob.msgChan, "AnnounceSignatures2",
+	).(*lnwire.AnnounceSignatures2)
+
+	alice.fundingMgr.ProcessFundingMsg(sigsBob, bob)
+	bob.fundingMgr.ProcessFundingMsg(sigsAlice, alice)
+
+	// Both peers combine the signatures to the same valid announcement,
+	// followed by their node announcement.
+	var anns []*lnwire.ChannelAnnouncement2
+	for _, node := range []*testNode{alice, bob} {
+		msg, err := lnutils.RecvOrTimeout(
+			node.announceChan, time.Second*5,
+		)
+		require.NoError(t, err)
+		ann := assertType[*lnwire.ChannelAnnouncement2](t, *msg)
+
+		err = routing.ValidateChannelAnn2(
+			ann, fundingTx.TxOut[fundingOutPoint.Index].PkScript,
+		)
+		require.NoError(t, err)
+		require.EqualValues(t, localAmt, ann.Capacity.Val)
+		anns = append(anns, ann)
+
+		msg, err = lnutils.RecvOrTimeout(
+			node.announceChan, time.Second*5,
+		)
+		require.NoError(t, err)
+		assertType[*lnwire.NodeAnnouncement](t, *msg)
+	}
+	require.Equal(t, anns[0], anns[1])
+
+	assertNoChannelState(t, alice, bob, fundingOutPoint)
+}
+
 // TestFundingManagerRejectCSV tests checking of local CSV values against our
 // local CSV limit for incoming and outgoing channels.
 func TestFundingManagerRejectCSV(t *testing.T) {
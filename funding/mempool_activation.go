@@ -0,0 +1,228 @@
+package funding
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// errFundingConflict is returned if a transaction conflicting with the
+// funding transaction of a channel was accepted to the mempool.
+var errFundingConflict = errors.New("conflicting transaction spends " +
+	"funding inputs")
+
+// mempoolActivation returns true if the given zero-conf channel should only
+// become active once its funding transaction was accepted to the mempool.
+// This is only possible for channels we fund ourselves, as we otherwise don't
+// know the inputs of the funding transaction.
+func (f *Manager) mempoolActivation(c *channeldb.OpenChannel) bool {
+	return f.cfg.ZeroConfMempoolActivation && f.cfg.Mempool != nil &&
+		c.IsZeroConf() && c.IsInitiator && c.FundingTxn != nil &&
+		len(c.FundingTxn.TxIn) > 0
+}
+
+// fundingInMempool checks whether the funding transaction of the given
+// channel or a conflicting transaction spending one of its inputs is in the
+// mempool. It returns errFundingConflict in case of a conflict.
+func (f *Manager) fundingInMempool(c *channeldb.OpenChannel) (bool, error) {
+	fundingTxid := c.FundingTxn.TxHash()
+
+	var found bool
+	for _, txIn := range c.FundingTxn.TxIn {
+		spender := f.cfg.Mempool.LookupInputMempoolSpend(
+			txIn.PreviousOutPoint,
+		)
+		if spender.IsNone() {
+			continue
+		}
+
+		spenderTx := spender.UnsafeFromSome()
+		spenderTxid := spenderTx.TxHash()
+		if spenderTxid != fundingTxid {
+			return false, fmt.Errorf("%w: %v spent by %v",
+				errFundingConflict, txIn.PreviousOutPoint,
+				spenderTxid)
+		}
+		found = true
+	}
+
+	return found, nil
+}
+
+// waitForMempoolAcceptance waits until the funding transaction of the given
+// zero-conf channel was accepted to the mempool or confirmed. An error is
+// returned if a conflicting transaction is found instead.
+func (f *Manager) waitForMempoolAcceptance(c *channeldb.OpenChannel) error {
+	txid := c.FundingOutpoint.Hash
+	fundingScript, err := makeFundingScript(c)
+	if err != nil {
+		return fmt.Errorf("unable to create funding script: %w", err)
+	}
+
+	// The funding transaction might already have left the mempool by
+	// getting confirmed, so we also watch for its first confirmation.
+	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
+		&txid, fundingScript, 1, c.BroadcastHeight(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to register for confirmation: %w",
+			err)
+	}
+	defer confNtfn.Cancel()
+
+	for {
+		inMempool, err := f.fundingInMempool(c)
+		if err != nil {
+			return err
+		}
+		if inMempool {
+			log.Infof("Funding tx %v of zero-conf "+
+				"ChannelPoint(%v) accepted to mempool", txid,
+				c.FundingOutpoint)
+
+			return nil
+		}
+
+		select {
+		case _, ok := <-confNtfn.Confirmed:
+			if !ok {
+				return ErrFundingManagerShuttingDown
+			}
+
+			return nil
+
+		case <-time.After(checkPeerChannelReadyInterval):
+
+		case <-f.quit:
+			return ErrFundingManagerShuttingDown
+		}
+	}
+}
+
+// watchFundingConflicts watches the mempool for transactions that conflict
+// with the funding transaction of the given zero-conf channel until the
+// funding transaction confirms. Once a conflict is detected, the channel is
+// downgraded so that it isn't used to send HTLCs anymore, as the funding
+// transaction is unlikely to ever confirm. Should the funding transaction
+// confirm regardless, the channel is upgraded again.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *Manager) watchFundingConflicts(c *channeldb.OpenChannel) {
+	defer f.wg.Done()
+
+	txid := c.FundingOutpoint.Hash
+	fundingScript, err := makeFundingScript(c)
+	if err != nil {
+		log.Errorf("Unable to create funding script for "+
+			"ChannelPoint(%v): %v", c.FundingOutpoint, err)
+		return
+	}
+
+	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
+		&txid, fundingScript, 1, c.BroadcastHeight(),
+	)
+	if err != nil {
+		log.Errorf("Unable to register for confirmation of "+
+			"ChannelPoint(%v): %v", c.FundingOutpoint, err)
+		return
+	}
+	defer confNtfn.Cancel()
+
+	// Subscribe to mempool spends of all funding inputs and merge them
+	// into a single channel.
+	done := make(chan struct{})
+	defer close(done)
+
+	spends := make(chan *chainntnfs.SpendDetail, len(c.FundingTxn.TxIn))
+	for _, txIn := range c.FundingTxn.TxIn {
+		sub, err := f.cfg.Mempool.SubscribeMempoolSpent(
+			txIn.PreviousOutPoint,
+		)
+		if err != nil {
+			log.Errorf("Unable to watch funding input %v of "+
+				"ChannelPoint(%v): %v", txIn.PreviousOutPoint,
+				c.FundingOutpoint, err)
+			return
+		}
+		defer f.cfg.Mempool.CancelMempoolSpendEvent(sub)
+
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+
+			select {
+			case spend, ok := <-sub.Spend:
+				if !ok {
+					return
+				}
+
+				select {
+				case spends <- spend:
+				case <-done:
+				}
+
+			case <-done:
+			}
+		}()
+	}
+
+	// A conflict might have entered the mempool before we subscribed.
+	var downgraded bool
+	if _, err := f.fundingInMempool(c); err != nil {
+		f.downgradeChannel(c, err)
+		downgraded = true
+	}
+
+	for {
+		select {
+		case spend := <-spends:
+			if downgraded || spend.SpenderTxHash == nil ||
+				*spend.SpenderTxHash == txid {
+
+				continue
+			}
+
+			f.downgradeChannel(c, fmt.Errorf("%w: %v spent by %v",
+				errFundingConflict, spend.SpentOutPoint,
+				spend.SpenderTxHash))
+			downgraded = true
+
+		case _, ok := <-confNtfn.Confirmed:
+			if !ok || !downgraded {
+				return
+			}
+
+			log.Infof("Funding tx of downgraded zero-conf "+
+				"ChannelPoint(%v) confirmed, sending HTLCs "+
+				"again", c.FundingOutpoint)
+
+			err := f.cfg.BlockOutgoingAdds(c.FundingOutpoint, false)
+			if err != nil {
+				log.Errorf("Unable to upgrade "+
+					"ChannelPoint(%v): %v",
+					c.FundingOutpoint, err)
+			}
+
+			return
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// downgradeChannel stops the given zero-conf channel from being used to send
+// HTLCs because of the given conflict with its funding transaction.
+func (f *Manager) downgradeChannel(c *channeldb.OpenChannel, conflict error) {
+	log.Warnf("Downgrading zero-conf ChannelPoint(%v), not sending "+
+		"HTLCs until funding tx confirms: %v", c.FundingOutpoint,
+		conflict)
+
+	if err := f.cfg.BlockOutgoingAdds(c.FundingOutpoint, true); err != nil {
+		log.Errorf("Unable to downgrade ChannelPoint(%v): %v",
+			c.FundingOutpoint, err)
+	}
+}
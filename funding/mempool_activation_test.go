@@ -0,0 +1,74 @@
+package funding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFundingInMempool tests that the funding transaction of a zero-conf
+// channel is found in the mempool and that conflicting transactions are
+// detected.
+func TestFundingInMempool(t *testing.T) {
+	t.Parallel()
+
+	input := wire.OutPoint{Index: 1}
+	fundingTx := wire.NewMsgTx(2)
+	fundingTx.AddTxIn(&wire.TxIn{PreviousOutPoint: input})
+	fundingTx.AddTxOut(&wire.TxOut{Value: 100_000})
+
+	conflictTx := wire.NewMsgTx(2)
+	conflictTx.AddTxIn(&wire.TxIn{PreviousOutPoint: input})
+	conflictTx.AddTxOut(&wire.TxOut{Value: 90_000})
+
+	channel := &channeldb.OpenChannel{
+		ChanType:    channeldb.ZeroConfBit,
+		IsInitiator: true,
+		FundingTxn:  fundingTx,
+	}
+
+	mempool := chainntnfs.NewMockMempoolWatcher()
+	f := &Manager{
+		cfg: &Config{
+			ZeroConfMempoolActivation: true,
+			Mempool:                   mempool,
+		},
+	}
+
+	// Only zero-conf channels we fund are activated based on the mempool.
+	require.True(t, f.mempoolActivation(channel))
+
+	channel.IsInitiator = false
+	require.False(t, f.mempoolActivation(channel))
+	channel.IsInitiator = true
+
+	// As long as no transaction spends the funding inputs, the funding
+	// transaction isn't in the mempool.
+	mempool.On("LookupInputMempoolSpend", input).Return(
+		fn.None[wire.MsgTx](),
+	).Once()
+	inMempool, err := f.fundingInMempool(channel)
+	require.NoError(t, err)
+	require.False(t, inMempool)
+
+	// Once the funding transaction spends them, it was accepted.
+	mempool.On("LookupInputMempoolSpend", input).Return(
+		fn.Some(*fundingTx),
+	).Once()
+	inMempool, err = f.fundingInMempool(channel)
+	require.NoError(t, err)
+	require.True(t, inMempool)
+
+	// A different transaction spending them is a conflict.
+	mempool.On("LookupInputMempoolSpend", input).Return(
+		fn.Some(*conflictTx),
+	).Once()
+	_, err = f.fundingInMempool(channel)
+	require.ErrorIs(t, err, errFundingConflict)
+
+	mempool.AssertExpectations(t)
+}
@@ -0,0 +1,289 @@
+package lnd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+)
+
+var (
+	// errNoFundingContributors is returned if no contributors were
+	// registered for a pending channel ID.
+	errNoFundingContributors = errors.New("no funding contributors " +
+		"registered")
+)
+
+// fundingContributors tracks the channel funding transactions that are
+// assembled from the inputs of several contributors, by the pending channel
+// ID of the channel they fund. Once all contributors added their inputs, the
+// funding PSBT is verified against the PSBT funding intent of the channel.
+// Once all contributors signed their inputs, the intent is finalized with the
+// signed funding transaction. If the contributors don't sign in time, the
+// funding intent is canceled.
+type fundingContributors struct {
+	// verify verifies the funded PSBT of the channel with the given
+	// pending channel ID.
+	verify func(pid [32]byte, packet *psbt.Packet) error
+
+	// finalize finalizes the funding of the channel with the given
+	// pending channel ID with the signed PSBT.
+	finalize func(pid [32]byte, packet *psbt.Packet) error
+
+	// cancel cancels the funding intent of the channel with the given
+	// pending channel ID.
+	cancel func(pid [32]byte) error
+
+	clock clock.Clock
+
+	// coordinators are the coordinators of the funding transactions, by
+	// the pending channel ID of the channel they fund.
+	coordinators map[[32]byte]*chanfunding.PsbtCoordinator
+
+	// timers cancel the funding intents once the contributions timed
+	// out.
+	timers map[[32]byte]*time.Timer
+
+	mu sync.Mutex
+}
+
+// newFundingContributors creates a new registry of multi-party funding
+// transactions that advances the PSBT funding intents of the channels with the
+// given functions.
+func newFundingContributors(verify, finalize func([32]byte,
+	*psbt.Packet) error, cancel func([32]byte) error,
+	clock clock.Clock) *fundingContributors {
+
+	return &fundingContributors{
+		verify:       verify,
+		finalize:     finalize,
+		cancel:       cancel,
+		clock:        clock,
+		coordinators: make(map[[32]byte]*chanfunding.PsbtCoordinator),
+		timers:       make(map[[32]byte]*time.Timer),
+	}
+}
+
+// Register starts the assembly of the funding transaction of the channel with
+// the given pending channel ID from the inputs of the given contributors. The
+// base PSBT must contain the channel funding output. All contributors need to
+// sign their inputs within the given timeout.
+func (f *fundingContributors) Register(pid [32]byte, basePsbt *psbt.Packet,
+	contributorIDs []string, timeout time.Duration) error {
+
+	if timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	coordinator, err := chanfunding.NewPsbtCoordinator(
+		basePsbt, contributorIDs, f.clock.Now().Add(timeout),
+	)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.coordinators[pid]; ok {
+		return fmt.Errorf("contributors already registered for "+
+			"pending channel ID %x", pid[:])
+	}
+
+	f.coordinators[pid] = coordinator
+	f.timers[pid] = time.AfterFunc(timeout, func() {
+		f.expire(pid)
+	})
+
+	return nil
+}
+
+// expire cancels the funding intent of the channel with the given pending
+// channel ID if not all contributors signed their inputs in time.
+func (f *fundingContributors) expire(pid [32]byte) {
+	f.mu.Lock()
+	coordinator, ok := f.coordinators[pid]
+	delete(f.coordinators, pid)
+	delete(f.timers, pid)
+	f.mu.Unlock()
+
+	if !ok || !coordinator.Expire() {
+		return
+	}
+
+	ltndLog.Infof("Funding contributions for pending channel ID %x "+
+		"timed out, canceling funding intent", pid[:])
+
+	if err := f.cancel(pid); err != nil {
+		ltndLog.Warnf("Unable to cancel funding intent for pending "+
+			"channel ID %x: %v", pid[:], err)
+	}
+}
+
+// coordinator returns the coordinator of the funding transaction of the
+// channel with the given pending channel ID.
+func (f *fundingContributors) coordinator(
+	pid [32]byte) (*chanfunding.PsbtCoordinator, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	coordinator, ok := f.coordinators[pid]
+	if !ok {
+		return nil, fmt.Errorf("%w for pending channel ID %x",
+			errNoFundingContributors, pid[:])
+	}
+
+	return coordinator, nil
+}
+
+// Contribute adds the inputs of the given contributor to the funding
+// transaction of the channel with the given pending channel ID. Once all
+// contributors added their inputs, the funded PSBT is verified against the
+// funding intent of the channel.
+func (f *fundingContributors) Contribute(pid [32]byte, id string,
+	packet *psbt.Packet) error {
+
+	coordinator, err := f.coordinator(pid)
+	if err != nil {
+		return err
+	}
+
+	allFunded, err := coordinator.AddContribution(id, packet)
+	if err != nil {
+		return err
+	}
+	if !allFunded {
+		return nil
+	}
+
+	funded, err := coordinator.FundedPsbt()
+	if err != nil {
+		return err
+	}
+
+	return f.verify(pid, funded)
+}
+
+// Sign adds the signed inputs of the given contributor to the funding
+// transaction of the channel with the given pending channel ID. Once all
+// contributors signed their inputs, the funding of the channel is finalized.
+func (f *fundingContributors) Sign(pid [32]byte, id string,
+	packet *psbt.Packet) error {
+
+	coordinator, err := f.coordinator(pid)
+	if err != nil {
+		return err
+	}
+
+	allSigned, err := coordinator.AddSignatures(id, packet)
+	if err != nil {
+		return err
+	}
+	if !allSigned {
+		return nil
+	}
+
+	signed, err := coordinator.SignedPsbt()
+	if err != nil {
+		return err
+	}
+	if err := f.finalize(pid, signed); err != nil {
+		return err
+	}
+
+	f.remove(pid)
+
+	return nil
+}
+
+// remove stops tracking the funding transaction of the channel with the given
+// pending channel ID.
+func (f *fundingContributors) remove(pid [32]byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if timer, ok := f.timers[pid]; ok {
+		timer.Stop()
+		delete(f.timers, pid)
+	}
+	delete(f.coordinators, pid)
+}
+
+// Status returns the state of the funding transaction of the channel with the
+// given pending channel ID.
+func (f *fundingContributors) Status(
+	pid [32]byte) (*lnrpc.FundingContributionStatusResponse, error) {
+
+	coordinator, err := f.coordinator(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	contributors := coordinator.Status()
+	status := &lnrpc.FundingContributionStatusResponse{
+		Contributors: make(
+			[]*lnrpc.FundingContributor, 0, len(contributors),
+		),
+		Deadline: coordinator.Deadline().Unix(),
+	}
+	for _, contributor := range contributors {
+		status.Contributors = append(
+			status.Contributors, marshalFundingContributor(
+				contributor,
+			),
+		)
+	}
+
+	// The funded PSBT is only available once all contributors added
+	// their inputs.
+	funded, err := coordinator.FundedPsbt()
+	if err != nil {
+		return status, nil
+	}
+
+	var b bytes.Buffer
+	if err := funded.Serialize(&b); err != nil {
+		return nil, err
+	}
+	status.FundedPsbt = b.Bytes()
+
+	return status, nil
+}
+
+// marshalFundingContributor converts the status of a contributor into its
+// RPC representation.
+func marshalFundingContributor(
+	c chanfunding.ContributorStatus) *lnrpc.FundingContributor {
+
+	rpcContributor := &lnrpc.FundingContributor{
+		Id:     c.ID,
+		Inputs: make([]*lnrpc.OutPoint, 0, len(c.Inputs)),
+	}
+	for i := range c.Inputs {
+		rpcContributor.Inputs = append(
+			rpcContributor.Inputs, lnrpc.MarshalOutPoint(
+				&c.Inputs[i],
+			),
+		)
+	}
+
+	switch c.State {
+	case chanfunding.ContributorPending:
+		rpcContributor.State = lnrpc.FundingContributor_PENDING
+
+	case chanfunding.ContributorFunded:
+		rpcContributor.State = lnrpc.FundingContributor_FUNDED
+
+	case chanfunding.ContributorSigned:
+		rpcContributor.State = lnrpc.FundingContributor_SIGNED
+	}
+
+	return rpcContributor
+}
@@ -0,0 +1,174 @@
+package lnd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serializePsbt returns the binary serialization of the given PSBT.
+func serializePsbt(t *testing.T, packet *psbt.Packet) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	require.NoError(t, packet.Serialize(&b))
+
+	return b.Bytes()
+}
+
+// contributionPsbt creates a PSBT that spends a single input with the given
+// index and sends change back to the contributor.
+func contributionPsbt(t *testing.T, idx uint32) []byte {
+	t.Helper()
+
+	prevOut := wire.OutPoint{Index: idx}
+	packet, err := psbt.New(
+		[]*wire.OutPoint{&prevOut}, []*wire.TxOut{{
+			Value:    1000,
+			PkScript: []byte{byte(idx)},
+		}}, 2, 0, []uint32{wire.MaxTxInSequenceNum},
+	)
+	require.NoError(t, err)
+
+	packet.Inputs[0].WitnessUtxo = &wire.TxOut{
+		Value:    100_000,
+		PkScript: []byte{0x00, byte(idx)},
+	}
+
+	return serializePsbt(t, packet)
+}
+
+// TestFundingContributionRPCs tests that a channel funding transaction can be
+// assembled from the inputs of several contributors through the RPCs.
+func TestFundingContributionRPCs(t *testing.T) {
+	t.Parallel()
+
+	var verified, finalized *psbt.Packet
+	r := &rpcServer{server: &server{
+		fundingContributors: newFundingContributors(
+			func(_ [32]byte, packet *psbt.Packet) error {
+				verified = packet
+				return nil
+			},
+			func(_ [32]byte, packet *psbt.Packet) error {
+				finalized = packet
+				return nil
+			},
+			func([32]byte) error { return nil },
+			clock.NewDefaultClock(),
+		),
+	}}
+	ctx := context.Background()
+	pid := bytes.Repeat([]byte{1}, 32)
+
+	// Nothing is registered for the pending channel ID yet.
+	_, err := r.FundingContributionStatus(
+		ctx, &lnrpc.FundingContributionStatusRequest{
+			PendingChanId: pid,
+		},
+	)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	base, err := psbt.New(
+		nil, []*wire.TxOut{{Value: 150_000, PkScript: []byte{0xff}}},
+		2, 0, nil,
+	)
+	require.NoError(t, err)
+	registerReq := &lnrpc.RegisterFundingContributorsRequest{
+		PendingChanId:  pid[:31],
+		BasePsbt:       serializePsbt(t, base),
+		ContributorIds: []string{"a", "b"},
+		TimeoutSecs:    60,
+	}
+
+	// Invalid pending channel IDs and timeouts are rejected.
+	_, err = r.RegisterFundingContributors(ctx, registerReq)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	registerReq.PendingChanId = pid
+	registerReq.TimeoutSecs = 0
+	_, err = r.RegisterFundingContributors(ctx, registerReq)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	registerReq.TimeoutSecs = 60
+	_, err = r.RegisterFundingContributors(ctx, registerReq)
+	require.NoError(t, err)
+
+	// Unknown contributors are rejected.
+	_, err = r.ContributeFunding(ctx, &lnrpc.ContributeFundingRequest{
+		PendingChanId: pid,
+		ContributorId: "c",
+		Psbt:          contributionPsbt(t, 3),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Once both contributors added their inputs, the funded PSBT is
+	// verified and returned by the status.
+	for i, id := range []string{"a", "b"} {
+		_, err = r.ContributeFunding(
+			ctx, &lnrpc.ContributeFundingRequest{
+				PendingChanId: pid,
+				ContributorId: id,
+				Psbt:          contributionPsbt(t, uint32(i+1)),
+			},
+		)
+		require.NoError(t, err)
+	}
+	require.NotNil(t, verified)
+
+	resp, err := r.FundingContributionStatus(
+		ctx, &lnrpc.FundingContributionStatusRequest{
+			PendingChanId: pid,
+		},
+	)
+	require.NoError(t, err)
+	require.NotZero(t, resp.Deadline)
+	require.Len(t, resp.Contributors, 2)
+	for i, contributor := range resp.Contributors {
+		require.Equal(
+			t, lnrpc.FundingContributor_FUNDED, contributor.State,
+		)
+		require.Len(t, contributor.Inputs, 1)
+		require.EqualValues(
+			t, i+1, contributor.Inputs[0].OutputIndex,
+		)
+	}
+	require.Equal(t, serializePsbt(t, verified), resp.FundedPsbt)
+
+	// The contributors sign their inputs in the funded PSBT, after which
+	// the funding is finalized.
+	for i, id := range []string{"a", "b"} {
+		signed, err := psbt.NewFromRawBytes(
+			bytes.NewReader(resp.FundedPsbt), false,
+		)
+		require.NoError(t, err)
+		signed.Inputs[i].FinalScriptWitness = []byte{0x01}
+
+		_, err = r.SignFundingContribution(
+			ctx, &lnrpc.SignFundingContributionRequest{
+				PendingChanId: pid,
+				ContributorId: id,
+				SignedPsbt:    serializePsbt(t, signed),
+			},
+		)
+		require.NoError(t, err)
+	}
+	require.NotNil(t, finalized)
+	require.Len(t, finalized.Inputs, 2)
+
+	// The finalized funding transaction isn't tracked anymore.
+	_, err = r.FundingContributionStatus(
+		ctx, &lnrpc.FundingContributionStatusRequest{
+			PendingChanId: pid,
+		},
+	)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -11,7 +11,7 @@ require (
 )
 
 require (
-	github.com/btcsuite/btcd v0.23.2 // indirect
+	github.com/btcsuite/btcd v0.23.3 // indirect
 	github.com/btcsuite/btcd/chaincfg/chainhash v1.0.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/kr/pretty v0.3.0 // indirect
@@ -24,3 +24,7 @@ require (
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// TODO(tor): Remove this replace once the tor module with UNIX control socket
+// support and the daemon status check is tagged.
+replace github.com/lightningnetwork/lnd/tor => ../tor
@@ -55,6 +55,22 @@ func CheckTorServiceStatus(tc *tor.Controller,
 	}
 }
 
+// CheckTorDaemonStatus checks whether the Tor daemon has finished
+// bootstrapping and considers the Tor network reachable. An onion service can
+// be registered with a daemon that can't build circuits, for example because
+// it lost its network connection, in which case the node isn't reachable even
+// though CheckTorServiceStatus passes.
+func CheckTorDaemonStatus(tc *tor.Controller) error {
+	err := tc.CheckDaemonStatus()
+	if err != nil {
+		log.Debugf("Checking tor daemon status got: %v", err)
+
+		return fmt.Errorf("Tor daemon not ready: %w", err)
+	}
+
+	return nil
+}
+
 // restartTorController attempts to make a new connection to the Tor daemon and
 // re-create the Hidden Service.
 func restartTorController(tc *tor.Controller,
@@ -63,8 +79,11 @@ func restartTorController(tc *tor.Controller,
 	err := tc.Reconnect()
 
 	// If we get a connection refused error, it means Tor daemon might not
-	// be started.
-	if errors.Is(err, syscall.ECONNREFUSED) {
+	// be started. When connecting over a UNIX domain socket, the socket
+	// file doesn't exist while the Tor daemon isn't running.
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ENOENT) {
+
 		return fmt.Errorf("check if Tor daemon is running")
 	}
 
@@ -0,0 +1,260 @@
+// Package holdfee prices the risk of forwarded htlcs being held for a long
+// time. It requires a surcharge on top of the regular forwarding fee that
+// grows with the number of blocks the htlc may lock up our liquidity and with
+// the number of htlc slots already in use on the outgoing channel, and it
+// refuses htlcs whose risk is too high. This is meant as groundwork for
+// upfront fee based anti-jamming schemes.
+package holdfee
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// maxRecentDecisions is the number of the most recent decisions the policy
+// keeps for inspection.
+const maxRecentDecisions = 100
+
+// Outcome is the result of checking an htlc against the policy.
+type Outcome string
+
+const (
+	// OutcomeAccepted means the htlc pays the required surcharge or
+	// doesn't need to pay one.
+	OutcomeAccepted Outcome = "accepted"
+
+	// OutcomeInsufficientFee means the htlc doesn't pay the required
+	// surcharge.
+	OutcomeInsufficientFee Outcome = "insufficient_fee"
+
+	// OutcomeRefused means the hold risk of the htlc is too high to
+	// forward it for any fee.
+	OutcomeRefused Outcome = "refused"
+)
+
+// Config contains the parameters of the policy.
+type Config struct {
+	// Clock is used to timestamp the decisions.
+	Clock clock.Clock
+
+	// FreeBlocks is the CLTV distance of the outgoing htlc up to which no
+	// surcharge is required.
+	FreeBlocks uint32
+
+	// RatePerBlock is the surcharge in parts per million of the forwarded
+	// amount that is required for every block of CLTV distance above
+	// FreeBlocks.
+	RatePerBlock uint32
+
+	// QueueMultiplier escalates the surcharge rate with the share of htlc
+	// slots in use on the outgoing channel. With all slots in use, the
+	// rate is multiplied by 1 + QueueMultiplier.
+	QueueMultiplier uint32
+
+	// MaxRate is the surcharge rate in parts per million above which
+	// htlcs are refused. Zero means htlcs are never refused.
+	MaxRate uint32
+
+	// DryRun only records the decisions of the policy without enforcing
+	// them, so the effect of the parameters can be observed first.
+	DryRun bool
+}
+
+// HTLC describes a forwarded htlc to check against the policy.
+type HTLC struct {
+	// Amount is the amount forwarded on the outgoing channel.
+	Amount lnwire.MilliSatoshi
+
+	// Fee is the fee the htlc pays in excess of the regular forwarding
+	// fee, which is available to pay for the surcharge.
+	Fee lnwire.MilliSatoshi
+
+	// CltvDistance is the number of blocks until the outgoing htlc
+	// expires.
+	CltvDistance uint32
+
+	// PendingHTLCs is the number of htlcs already in flight on the
+	// outgoing channel.
+	PendingHTLCs uint16
+
+	// MaxHTLCs is the maximum number of htlcs that may be in flight on
+	// the outgoing channel.
+	MaxHTLCs uint16
+}
+
+// Decision is the verdict of the policy for an htlc.
+type Decision struct {
+	// Time is the time the htlc was checked.
+	Time time.Time `json:"time"`
+
+	// Amount is the amount forwarded on the outgoing channel.
+	Amount lnwire.MilliSatoshi `json:"amount_msat"`
+
+	// CltvDistance is the number of blocks until the outgoing htlc
+	// expires.
+	CltvDistance uint32 `json:"cltv_distance"`
+
+	// PendingHTLCs is the number of htlcs already in flight on the
+	// outgoing channel.
+	PendingHTLCs uint16 `json:"pending_htlcs"`
+
+	// Rate is the required surcharge rate in parts per million.
+	Rate uint64 `json:"rate_ppm"`
+
+	// Surcharge is the required surcharge.
+	Surcharge lnwire.MilliSatoshi `json:"surcharge_msat"`
+
+	// Outcome is the result of the check.
+	Outcome Outcome `json:"outcome"`
+
+	// Enforced is false if the policy runs in dry-run mode and the
+	// outcome was only recorded.
+	Enforced bool `json:"enforced"`
+}
+
+// Stats summarizes the decisions of the policy since it was created.
+type Stats struct {
+	// DryRun is true if the decisions aren't enforced.
+	DryRun bool `json:"dry_run"`
+
+	// Checked is the number of htlcs checked.
+	Checked uint64 `json:"checked"`
+
+	// Surcharged is the number of htlcs that required a surcharge.
+	Surcharged uint64 `json:"surcharged"`
+
+	// InsufficientFee is the number of htlcs that didn't pay the required
+	// surcharge.
+	InsufficientFee uint64 `json:"insufficient_fee"`
+
+	// Refused is the number of htlcs refused for their hold risk.
+	Refused uint64 `json:"refused"`
+
+	// CollectedSurcharge is the sum of the surcharges paid by accepted
+	// htlcs.
+	CollectedSurcharge lnwire.MilliSatoshi `json:"collected_surcharge_msat"`
+
+	// Recent are the most recent decisions, oldest first.
+	Recent []Decision `json:"recent"`
+}
+
+// Policy checks forwarded htlcs against the hold fee policy and keeps
+// statistics of its decisions.
+type Policy struct {
+	cfg *Config
+
+	mu     sync.Mutex
+	stats  Stats
+	recent []Decision
+}
+
+// New creates a new hold fee policy.
+func New(cfg *Config) *Policy {
+	return &Policy{
+		cfg: cfg,
+		stats: Stats{
+			DryRun: cfg.DryRun,
+		},
+	}
+}
+
+// Evaluate computes the decision for the htlc without recording it.
+func (p *Policy) Evaluate(htlc HTLC) Decision {
+	var holdBlocks uint64
+	if htlc.CltvDistance > p.cfg.FreeBlocks {
+		holdBlocks = uint64(htlc.CltvDistance - p.cfg.FreeBlocks)
+	}
+
+	rate := holdBlocks * uint64(p.cfg.RatePerBlock)
+
+	// The fuller the htlc slots of the outgoing channel, the more a
+	// jamming attempt hurts, so the rate is escalated accordingly.
+	if htlc.MaxHTLCs > 0 {
+		pending := uint64(htlc.PendingHTLCs)
+		if pending > uint64(htlc.MaxHTLCs) {
+			pending = uint64(htlc.MaxHTLCs)
+		}
+
+		rate += rate * uint64(p.cfg.QueueMultiplier) * pending /
+			uint64(htlc.MaxHTLCs)
+	}
+
+	decision := Decision{
+		Time:         p.cfg.Clock.Now(),
+		Amount:       htlc.Amount,
+		CltvDistance: htlc.CltvDistance,
+		PendingHTLCs: htlc.PendingHTLCs,
+		Rate:         rate,
+		Surcharge: lnwire.MilliSatoshi(
+			uint64(htlc.Amount) * rate / 1_000_000,
+		),
+		Enforced: !p.cfg.DryRun,
+	}
+
+	switch {
+	case p.cfg.MaxRate > 0 && rate > uint64(p.cfg.MaxRate):
+		decision.Outcome = OutcomeRefused
+
+	case htlc.Fee < decision.Surcharge:
+		decision.Outcome = OutcomeInsufficientFee
+
+	default:
+		decision.Outcome = OutcomeAccepted
+	}
+
+	return decision
+}
+
+// Check evaluates the htlc and records the decision. The caller must fail the
+// htlc if the decision is enforced and its outcome isn't OutcomeAccepted.
+func (p *Policy) Check(htlc HTLC) Decision {
+	decision := p.Evaluate(htlc)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stats.Checked++
+	if decision.Surcharge > 0 {
+		p.stats.Surcharged++
+	}
+
+	switch decision.Outcome {
+	case OutcomeAccepted:
+		p.stats.CollectedSurcharge += decision.Surcharge
+
+	case OutcomeInsufficientFee:
+		p.stats.InsufficientFee++
+
+	case OutcomeRefused:
+		p.stats.Refused++
+	}
+
+	if decision.Outcome != OutcomeAccepted {
+		log.Debugf("Hold fee policy outcome %v for htlc of %v with "+
+			"CLTV distance %d and %d pending htlcs (required "+
+			"surcharge %v, enforced=%v)", decision.Outcome,
+			htlc.Amount, htlc.CltvDistance, htlc.PendingHTLCs,
+			decision.Surcharge, decision.Enforced)
+	}
+
+	p.recent = append(p.recent, decision)
+	if len(p.recent) > maxRecentDecisions {
+		p.recent = p.recent[1:]
+	}
+
+	return decision
+}
+
+// Stats returns the statistics of the decisions of the policy.
+func (p *Policy) Stats() *Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Recent = append([]Decision(nil), p.recent...)
+
+	return &stats
+}
@@ -0,0 +1,146 @@
+package holdfee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns a policy config that requires 10ppm per block above 100
+// blocks and doubles the rate when all htlc slots are in use.
+func testConfig() *Config {
+	return &Config{
+		Clock:           clock.NewTestClock(time.Unix(1_000, 0)),
+		FreeBlocks:      100,
+		RatePerBlock:    10,
+		QueueMultiplier: 1,
+		MaxRate:         5_000,
+	}
+}
+
+// TestEvaluate tests the surcharge computation of the policy.
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		htlc            HTLC
+		expectedRate    uint64
+		expectedOutcome Outcome
+	}{{
+		name: "within free blocks",
+		htlc: HTLC{
+			Amount:       1_000_000,
+			CltvDistance: 80,
+			MaxHTLCs:     10,
+		},
+		expectedOutcome: OutcomeAccepted,
+	}, {
+		name: "surcharge paid",
+		htlc: HTLC{
+			Amount:       1_000_000,
+			Fee:          1_000,
+			CltvDistance: 200,
+			MaxHTLCs:     10,
+		},
+		expectedRate:    1_000,
+		expectedOutcome: OutcomeAccepted,
+	}, {
+		name: "surcharge not paid",
+		htlc: HTLC{
+			Amount:       1_000_000,
+			Fee:          999,
+			CltvDistance: 200,
+			MaxHTLCs:     10,
+		},
+		expectedRate:    1_000,
+		expectedOutcome: OutcomeInsufficientFee,
+	}, {
+		name: "escalated by queue",
+		htlc: HTLC{
+			Amount:       1_000_000,
+			Fee:          1_000,
+			CltvDistance: 200,
+			PendingHTLCs: 5,
+			MaxHTLCs:     10,
+		},
+		expectedRate:    1_500,
+		expectedOutcome: OutcomeInsufficientFee,
+	}, {
+		name: "refused",
+		htlc: HTLC{
+			Amount:       1_000_000,
+			Fee:          1_000_000,
+			CltvDistance: 400,
+			PendingHTLCs: 10,
+			MaxHTLCs:     10,
+		},
+		expectedRate:    6_000,
+		expectedOutcome: OutcomeRefused,
+	}}
+
+	policy := New(testConfig())
+	for _, tc := range testCases {
+		decision := policy.Evaluate(tc.htlc)
+		require.Equal(t, tc.expectedRate, decision.Rate, tc.name)
+		require.EqualValues(
+			t, tc.expectedRate*uint64(tc.htlc.Amount)/1_000_000,
+			decision.Surcharge, tc.name,
+		)
+		require.Equal(t, tc.expectedOutcome, decision.Outcome, tc.name)
+		require.True(t, decision.Enforced, tc.name)
+	}
+
+	// Evaluating htlcs doesn't record the decisions.
+	require.Zero(t, policy.Stats().Checked)
+}
+
+// TestCheckStats tests that checked htlcs are recorded in the statistics.
+func TestCheckStats(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+	cfg.DryRun = true
+	policy := New(cfg)
+
+	accepted := policy.Check(HTLC{
+		Amount:       1_000_000,
+		Fee:          1_000,
+		CltvDistance: 200,
+		MaxHTLCs:     10,
+	})
+	require.Equal(t, OutcomeAccepted, accepted.Outcome)
+	require.False(t, accepted.Enforced)
+
+	policy.Check(HTLC{
+		Amount:       1_000_000,
+		CltvDistance: 200,
+		MaxHTLCs:     10,
+	})
+	policy.Check(HTLC{
+		Amount:       1_000_000,
+		CltvDistance: 1_000,
+		MaxHTLCs:     10,
+	})
+
+	stats := policy.Stats()
+	require.True(t, stats.DryRun)
+	require.EqualValues(t, 3, stats.Checked)
+	require.EqualValues(t, 3, stats.Surcharged)
+	require.EqualValues(t, 1, stats.InsufficientFee)
+	require.EqualValues(t, 1, stats.Refused)
+	require.EqualValues(t, 1_000, stats.CollectedSurcharge)
+	require.Len(t, stats.Recent, 3)
+	require.Equal(t, accepted, stats.Recent[0])
+
+	// Only the most recent decisions are kept.
+	for i := 0; i < maxRecentDecisions; i++ {
+		policy.Check(HTLC{Amount: 1_000, CltvDistance: 10})
+	}
+	stats = policy.Stats()
+	require.EqualValues(t, maxRecentDecisions+3, stats.Checked)
+	require.Len(t, stats.Recent, maxRecentDecisions)
+	require.Zero(t, stats.Recent[0].Surcharge)
+}
@@ -3,6 +3,7 @@ package htlcswitch
 import (
 	"encoding/binary"
 	"io"
+	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
@@ -61,6 +62,12 @@ type PaymentCircuit struct {
 	// NOTE: This value is determined implicitly during a restart. It is not
 	// persisted, and should never be set outside the circuit map.
 	LoadedFromDisk bool
+
+	// receivedAt is the time the switch received the ADD to forward. It's
+	// only used for metrics and the hold time we report in the attribution
+	// data of failures, so it isn't persisted and is zero for local
+	// payments and circuits loaded from disk.
+	receivedAt time.Time
 }
 
 // HasKeystone returns true if an outgoing link has assigned this circuit's
@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	sphinx "github.com/lightningnetwork/lightning-onion"
@@ -163,10 +164,27 @@ type OnionErrorDecrypter interface {
 	DecryptError(encryptedData []byte) (*sphinx.DecryptedError, error)
 }
 
+// AttrErrorDecrypter is implemented by error decrypters that can use the
+// attribution data of a failure to pin a failure they can't decrypt to a hop
+// of the route.
+type AttrErrorDecrypter interface {
+	ErrorDecrypter
+
+	// AttributeError returns the position of the hop that made the given
+	// failure unreadable, counting from one for the first hop of the
+	// route.
+	AttributeError(reason lnwire.OpaqueReason, attrData []byte) (int,
+		error)
+}
+
 // SphinxErrorDecrypter wraps the sphinx data SphinxErrorDecrypter and maps the
 // returned errors to concrete lnwire.FailureMessage instances.
 type SphinxErrorDecrypter struct {
 	OnionErrorDecrypter
+
+	// Circuit is the circuit of the payment attempt. If set, it's used to
+	// attribute failures that can't be decrypted to a hop of the route.
+	Circuit *sphinx.Circuit
 }
 
 // DecryptError peels off each layer of onion encryption from the first hop, to
@@ -193,6 +211,36 @@ func (s *SphinxErrorDecrypter) DecryptError(reason lnwire.OpaqueReason) (
 	return NewForwardingError(failureMsg, failure.SenderIdx), nil
 }
 
+// AttributeError uses the attribution data of a failure that can't be
+// decrypted to find the hop that made it unreadable. It returns the position
+// of the first hop with invalid attribution data, counting from one for the
+// first hop of the route. If the attribution data of all hops is valid, the
+// last hop sent the unreadable failure.
+//
+// NOTE: Part of the AttrErrorDecrypter interface.
+func (s *SphinxErrorDecrypter) AttributeError(reason lnwire.OpaqueReason,
+	attrData []byte) (int, error) {
+
+	if s.Circuit == nil {
+		return 0, errors.New("no circuit to attribute error")
+	}
+
+	sharedSecrets, err := hop.CircuitSharedSecrets(s.Circuit)
+	if err != nil {
+		return 0, err
+	}
+
+	holdTimes, pos := hop.AttributeFailure(sharedSecrets, reason, attrData)
+	if pos == 0 {
+		pos = len(sharedSecrets)
+	}
+
+	log.Debugf("Attributed unreadable failure to hop %d, hold times: %v",
+		pos, holdTimes)
+
+	return pos, nil
+}
+
 // A compile time check to ensure ErrorDecrypter implements the Deobfuscator
 // interface.
-var _ ErrorDecrypter = (*SphinxErrorDecrypter)(nil)
+var _ AttrErrorDecrypter = (*SphinxErrorDecrypter)(nil)
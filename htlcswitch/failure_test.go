@@ -1,6 +1,7 @@
 package htlcswitch
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"os"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/require"
@@ -84,3 +86,79 @@ type varBytesRecordProducer struct {
 func (v *varBytesRecordProducer) Record() tlv.Record {
 	return tlv.MakePrimitiveRecord(34001, &v.data)
 }
+
+// TestAttributeUnreadableFailure tests that a failure that can't be decrypted
+// is pinned to a hop of the route using its attribution data.
+func TestAttributeUnreadableFailure(t *testing.T) {
+	t.Parallel()
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	circuit := &sphinx.Circuit{
+		SessionKey: sessionKey,
+	}
+	for i := 0; i < 3; i++ {
+		nodeKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		circuit.PaymentPath = append(
+			circuit.PaymentPath, nodeKey.PubKey(),
+		)
+	}
+
+	sharedSecrets, err := hop.CircuitSharedSecrets(circuit)
+	require.NoError(t, err)
+
+	encrypters := make([]*hop.SphinxErrorEncrypter, len(sharedSecrets))
+	for i := range sharedSecrets {
+		encrypter := &sphinx.OnionErrorEncrypter{}
+		err := encrypter.Decode(bytes.NewReader(sharedSecrets[i][:]))
+		require.NoError(t, err)
+
+		encrypters[i] = &hop.SphinxErrorEncrypter{
+			OnionErrorEncrypter: encrypter,
+		}
+	}
+
+	// The final hop fails the HTLC, but the second hop replaces the
+	// failure with garbage before it adds its attribution data.
+	reason, err := encrypters[2].EncryptFirstHop(
+		lnwire.NewTemporaryChannelFailure(nil),
+	)
+	require.NoError(t, err)
+	attrData, err := encrypters[2].AddAttribution(reason, nil, 0)
+	require.NoError(t, err)
+
+	reason = make(lnwire.OpaqueReason, len(reason))
+	attrData, err = encrypters[1].AddAttribution(reason, attrData, 0)
+	require.NoError(t, err)
+
+	reason = encrypters[0].IntermediateEncrypt(reason)
+	attrData, err = encrypters[0].AddAttribution(reason, attrData, 0)
+	require.NoError(t, err)
+
+	decrypter := &SphinxErrorDecrypter{
+		OnionErrorDecrypter: sphinx.NewOnionErrorDecrypter(circuit),
+		Circuit:             circuit,
+	}
+
+	htlc := &lnwire.UpdateFailHTLC{
+		Reason: reason,
+	}
+
+	_, err = decrypter.DecryptError(htlc.Reason)
+	require.Error(t, err)
+
+	// Without attribution data, the failure can't be attributed.
+	require.Nil(t, attributeFailure(decrypter, htlc))
+
+	// With attribution data, the failure is pinned to the final hop, as
+	// the second hop committed to the failure it sent. The final hop is
+	// reported, which penalizes the channel between the two.
+	htlc.AttrData = lnwire.NewAttrDataRecord(attrData)
+	failure := attributeFailure(decrypter, htlc)
+	require.NotNil(t, failure)
+	require.Equal(t, 3, failure.FailureSourceIdx)
+	require.Nil(t, failure.WireMessage())
+}
@@ -0,0 +1,270 @@
+package hop
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	// AttrMaxHops is the maximum number of hops that can add attribution
+	// data to a failure. Failures from hops further away from the sender
+	// can't be attributed.
+	AttrMaxHops = 20
+
+	// attrHoldTimeLen is the length of the hold time each hop reports in
+	// the attribution data.
+	attrHoldTimeLen = 4
+
+	// attrHmacLen is the length of the truncated HMACs each hop adds to
+	// the attribution data.
+	attrHmacLen = 4
+
+	// attrHoldTimesLen is the length of the hold times at the start of
+	// the attribution data.
+	attrHoldTimesLen = AttrMaxHops * attrHoldTimeLen
+
+	// AttrDataLen is the length of the attribution data. The hold times
+	// are followed by one row of HMACs per hop: the hop that added the
+	// attribution data last has a HMAC for each position it could have in
+	// the route, the hop before it one less, and so on.
+	AttrDataLen = attrHoldTimesLen +
+		AttrMaxHops*(AttrMaxHops+1)/2*attrHmacLen
+
+	// AttrHoldTimeUnit is the unit of the hold times in the attribution
+	// data.
+	AttrHoldTimeUnit = 100 * time.Millisecond
+)
+
+// AttrErrorEncrypter is implemented by error encrypters that can add
+// attribution data to the failures they send back to the sender.
+type AttrErrorEncrypter interface {
+	ErrorEncrypter
+
+	// AddAttribution returns the attribution data for the given failure
+	// reason, which is the reason as it's sent to the previous hop. The
+	// attribution data received from the next hop is included, if any,
+	// along with the time the HTLC was held by this hop.
+	AddAttribution(reason lnwire.OpaqueReason, prevAttrData []byte,
+		holdTime time.Duration) ([]byte, error)
+}
+
+// attrHmacOffset returns the offset of a HMAC in the attribution data, given
+// the row of the hop that added it and the position in the route the HMAC
+// was computed for.
+func attrHmacOffset(row, pos int) int {
+	rowStart := row*AttrMaxHops - row*(row-1)/2
+
+	return attrHoldTimesLen + (rowStart+pos)*attrHmacLen
+}
+
+// attrKey derives a key of the given type from a shared secret, the same way
+// sphinx derives the keys for onion packets and failures.
+func attrKey(keyType string, sharedSecret *sphinx.Hash256) []byte {
+	mac := hmac.New(sha256.New, []byte(keyType))
+	mac.Write(sharedSecret[:])
+
+	return mac.Sum(nil)
+}
+
+// attrCipher encrypts or decrypts data with chacha20, keyed by the key of the
+// given type and using an all-zero nonce.
+func attrCipher(keyType string, sharedSecret *sphinx.Hash256,
+	data []byte) []byte {
+
+	var nonce [chacha20.NonceSize]byte
+	cipher, err := chacha20.NewUnauthenticatedCipher(
+		attrKey(keyType, sharedSecret), nonce[:],
+	)
+	if err != nil {
+		// This can only happen if the key or nonce have an invalid
+		// size, which they never have.
+		panic(err)
+	}
+
+	result := make([]byte, len(data))
+	cipher.XORKeyStream(result, data)
+
+	return result
+}
+
+// attrHmacs computes the HMACs a hop commits to for every position from
+// minPos onwards it could have in the route. The plaintext attribution data
+// holds the hold times and, starting at its second row, the HMACs of the hops
+// after it.
+func attrHmacs(sharedSecret *sphinx.Hash256, reason []byte, data []byte,
+	minPos int) [][]byte {
+
+	umKey := attrKey("um", sharedSecret)
+
+	hmacs := make([][]byte, 0, AttrMaxHops-minPos)
+	for pos := minPos; pos < AttrMaxHops; pos++ {
+		// The hops in front of this hop shift out the data of the
+		// hops furthest away, so this hop only commits to the data
+		// that is still there when the failure reaches the sender.
+		numHops := AttrMaxHops - pos
+
+		mac := hmac.New(sha256.New, umKey)
+		mac.Write(reason)
+		mac.Write(data[:numHops*attrHoldTimeLen])
+		for row := 1; row < numHops; row++ {
+			offset := attrHmacOffset(row, pos)
+			mac.Write(data[offset : offset+attrHmacLen])
+		}
+
+		hmacs = append(hmacs, mac.Sum(nil)[:attrHmacLen])
+	}
+
+	return hmacs
+}
+
+// AddAttribution adds the hold time and HMACs of a hop to the attribution
+// data received from the next hop, and encrypts the result with the hop's
+// shared secret. The reason is the failure as the hop sends it to the previous
+// hop. If the next hop didn't send any attribution data, it's started from
+// scratch.
+func AddAttribution(sharedSecret sphinx.Hash256, reason lnwire.OpaqueReason,
+	prevAttrData []byte, holdTime time.Duration) []byte {
+
+	prev := prevAttrData
+	if len(prev) != AttrDataLen {
+		prev = make([]byte, AttrDataLen)
+	}
+
+	data := make([]byte, AttrDataLen)
+
+	// Our hold time goes first, followed by those of the hops after us.
+	// The hold time of the hop furthest away is shifted out.
+	binary.BigEndian.PutUint32(data, uint32(holdTime/AttrHoldTimeUnit))
+	copy(
+		data[attrHoldTimeLen:attrHoldTimesLen],
+		prev[:attrHoldTimesLen-attrHoldTimeLen],
+	)
+
+	// The HMACs of the hops after us move one row down, which drops the
+	// HMAC each of them computed for the position we have in the route.
+	for row := 1; row < AttrMaxHops; row++ {
+		rowLen := (AttrMaxHops - row) * attrHmacLen
+		start := attrHmacOffset(row, 0)
+		prevStart := attrHmacOffset(row-1, 1)
+
+		copy(data[start:start+rowLen], prev[prevStart:prevStart+rowLen])
+	}
+
+	for pos, mac := range attrHmacs(&sharedSecret, reason, data, 0) {
+		copy(data[attrHmacOffset(0, pos):], mac)
+	}
+
+	return attrCipher("ammagext", &sharedSecret, data)
+}
+
+// unwrapAttribution decrypts the attribution data added by the hop at the
+// given position in the route, and verifies the HMACs it computed for that
+// position. If they're valid, the hold time reported by the hop is returned
+// along with the attribution data as the next hop sent it, minus the data
+// that was shifted out since.
+func unwrapAttribution(sharedSecret *sphinx.Hash256, reason, attrData []byte,
+	pos int) (time.Duration, []byte, bool) {
+
+	data := attrCipher("ammagext", sharedSecret, attrData)
+
+	hmacs := attrHmacs(sharedSecret, reason, data, pos)
+	for i, mac := range hmacs {
+		offset := attrHmacOffset(0, pos+i)
+		if !hmac.Equal(mac, data[offset:offset+attrHmacLen]) {
+			return 0, nil, false
+		}
+	}
+
+	holdTime := time.Duration(binary.BigEndian.Uint32(data)) *
+		AttrHoldTimeUnit
+
+	// Undo the shift of the hop, so the data of the hops after it is
+	// back at the place they put it.
+	next := make([]byte, AttrDataLen)
+	copy(next, data[attrHoldTimeLen:attrHoldTimesLen])
+	for row := 0; row < AttrMaxHops-1; row++ {
+		rowLen := (AttrMaxHops - row - 1) * attrHmacLen
+		start := attrHmacOffset(row, 1)
+		nextStart := attrHmacOffset(row+1, 0)
+
+		copy(next[start:start+rowLen], data[nextStart:nextStart+rowLen])
+	}
+
+	return holdTime, next, true
+}
+
+// AttributeFailure walks the attribution data of a failure along the route,
+// using the shared secrets of the hops in the route. It returns the hold times
+// reported by the hops with valid attribution data, in the order of the
+// route. If a hop's attribution data is invalid, its position in the route is
+// returned as well, counting from one for the first hop. Zero is returned if
+// the attribution data of all hops is valid.
+func AttributeFailure(sharedSecrets []sphinx.Hash256,
+	reason lnwire.OpaqueReason, attrData []byte) ([]time.Duration, int) {
+
+	if len(attrData) != AttrDataLen {
+		return nil, 1
+	}
+
+	var holdTimes []time.Duration
+	for pos := range sharedSecrets {
+		if pos == AttrMaxHops {
+			break
+		}
+
+		holdTime, next, ok := unwrapAttribution(
+			&sharedSecrets[pos], reason, attrData, pos,
+		)
+		if !ok {
+			return holdTimes, pos + 1
+		}
+		holdTimes = append(holdTimes, holdTime)
+		attrData = next
+
+		// Strip the layer of encryption this hop added to the failure,
+		// to get the failure as the next hop sent it.
+		reason = attrCipher("ammag", &sharedSecrets[pos], reason)
+	}
+
+	return holdTimes, 0
+}
+
+// CircuitSharedSecrets derives the shared secrets with each of the hops of a
+// payment's route from the session key of its onion.
+func CircuitSharedSecrets(circuit *sphinx.Circuit) ([]sphinx.Hash256, error) {
+	secrets := make([]sphinx.Hash256, len(circuit.PaymentPath))
+
+	// The ephemeral private key is blinded after every hop, so that each
+	// hop sees a different ephemeral public key.
+	var ephemeralKey btcec.ModNScalar
+	ephemeralKey.Set(&circuit.SessionKey.Key)
+
+	for i, nodeKey := range circuit.PaymentPath {
+		ecdh := &sphinx.PrivKeyECDH{
+			PrivKey: btcec.PrivKeyFromScalar(&ephemeralKey),
+		}
+
+		secret, err := ecdh.ECDH(nodeKey)
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = secret
+
+		h := sha256.New()
+		h.Write(ecdh.PubKey().SerializeCompressed())
+		h.Write(secrets[i][:])
+
+		var blindingFactor btcec.ModNScalar
+		blindingFactor.SetByteSlice(h.Sum(nil))
+		ephemeralKey.Mul(&blindingFactor)
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,205 @@
+package hop
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newAttrRoute creates a route with the given number of hops, and returns its
+// circuit along with the error encrypter of each hop.
+func newAttrRoute(t *testing.T, numHops int) (*sphinx.Circuit,
+	[]*SphinxErrorEncrypter) {
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	circuit := &sphinx.Circuit{
+		SessionKey: sessionKey,
+	}
+	for i := 0; i < numHops; i++ {
+		nodeKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		circuit.PaymentPath = append(
+			circuit.PaymentPath, nodeKey.PubKey(),
+		)
+	}
+
+	secrets, err := CircuitSharedSecrets(circuit)
+	require.NoError(t, err)
+
+	encrypters := make([]*SphinxErrorEncrypter, numHops)
+	for i := range secrets {
+		encrypter := &sphinx.OnionErrorEncrypter{}
+		err := encrypter.Decode(bytes.NewReader(secrets[i][:]))
+		require.NoError(t, err)
+
+		encrypters[i] = &SphinxErrorEncrypter{
+			OnionErrorEncrypter: encrypter,
+		}
+	}
+
+	return circuit, encrypters
+}
+
+// TestAttributeFailure tests that the attribution data added by the hops of a
+// route pins a failure to the hop that corrupted it.
+func TestAttributeFailure(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numHops   = 5
+		failIndex = 2
+	)
+
+	holdTimes := []time.Duration{
+		300 * time.Millisecond, 200 * time.Millisecond,
+		100 * time.Millisecond, 0, 0,
+	}
+
+	testCases := []struct {
+		name string
+
+		// corrupt is called by each hop with the failure it received
+		// from the next hop, and the failure it sends to the previous
+		// hop, before it adds its attribution data.
+		corrupt func(hop int, received,
+			sent lnwire.OpaqueReason) lnwire.OpaqueReason
+
+		// expectedPos is the expected position of the first hop with
+		// invalid attribution data.
+		expectedPos int
+
+		// readable indicates whether the failure is expected to be
+		// readable.
+		readable bool
+	}{
+		{
+			name: "valid failure",
+			corrupt: func(_ int, _,
+				sent lnwire.OpaqueReason) lnwire.OpaqueReason {
+
+				return sent
+			},
+
+			// The hop after the failing hop didn't add any
+			// attribution data.
+			expectedPos: failIndex + 2,
+			readable:    true,
+		},
+		{
+			name: "corrupted before attribution",
+			corrupt: func(hop int, _,
+				sent lnwire.OpaqueReason) lnwire.OpaqueReason {
+
+				if hop == 1 {
+					sent[0] ^= 1
+				}
+
+				return sent
+			},
+
+			// The corrupting hop commits to the corrupted
+			// failure, which doesn't match the HMACs of the hop
+			// it received the failure from.
+			expectedPos: 3,
+		},
+		{
+			name: "corrupted by previous hop",
+			corrupt: func(hop int, received,
+				sent lnwire.OpaqueReason) lnwire.OpaqueReason {
+
+				// The first hop forwards a corrupted failure
+				// from the second hop, with valid attribution
+				// data of its own.
+				if hop == 0 {
+					received[0] ^= 1
+					sent = received
+				}
+
+				return sent
+			},
+			expectedPos: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			circuit, encrypters := newAttrRoute(t, numHops)
+
+			failure := lnwire.NewTemporaryChannelFailure(nil)
+			reason, err := encrypters[failIndex].EncryptFirstHop(
+				failure,
+			)
+			require.NoError(t, err)
+
+			var attrData []byte
+			for hop := failIndex; hop >= 0; hop-- {
+				encrypter := encrypters[hop]
+
+				sent := reason
+				if hop != failIndex {
+					sent = encrypter.IntermediateEncrypt(
+						reason,
+					)
+				}
+				reason = tc.corrupt(
+					hop, append([]byte(nil), reason...),
+					sent,
+				)
+
+				attrData, err = encrypter.AddAttribution(
+					reason, attrData, holdTimes[hop],
+				)
+				require.NoError(t, err)
+				require.Len(t, attrData, AttrDataLen)
+			}
+
+			// The shared secrets must match those of sphinx for
+			// the failure to be readable.
+			decrypter := sphinx.NewOnionErrorDecrypter(circuit)
+			decrypted, err := decrypter.DecryptError(reason)
+			if tc.readable {
+				require.NoError(t, err)
+				require.Equal(
+					t, failIndex+1, decrypted.SenderIdx,
+				)
+			} else {
+				require.Error(t, err)
+			}
+
+			secrets, err := CircuitSharedSecrets(circuit)
+			require.NoError(t, err)
+
+			reported, pos := AttributeFailure(
+				secrets, reason, attrData,
+			)
+			require.Equal(t, tc.expectedPos, pos)
+			require.Equal(t, holdTimes[:pos-1], reported)
+		})
+	}
+}
+
+// TestAttributeFailureMissingData tests that a failure without attribution
+// data is pinned to the first hop.
+func TestAttributeFailureMissingData(t *testing.T) {
+	t.Parallel()
+
+	circuit, _ := newAttrRoute(t, 3)
+	secrets, err := CircuitSharedSecrets(circuit)
+	require.NoError(t, err)
+
+	holdTimes, pos := AttributeFailure(secrets, make([]byte, 292), nil)
+	require.Empty(t, holdTimes)
+	require.Equal(t, 1, pos)
+}
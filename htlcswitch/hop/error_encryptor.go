@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	sphinx "github.com/lightningnetwork/lightning-onion"
@@ -161,6 +162,26 @@ func (s *SphinxErrorEncrypter) IntermediateEncrypt(
 	return s.EncryptError(false, reason)
 }
 
+// AddAttribution returns the attribution data for the given failure reason,
+// which includes the attribution data received from the next hop, if any, and
+// the time the HTLC was held by us.
+//
+// NOTE: Part of the AttrErrorEncrypter interface.
+func (s *SphinxErrorEncrypter) AddAttribution(reason lnwire.OpaqueReason,
+	prevAttrData []byte, holdTime time.Duration) ([]byte, error) {
+
+	// The sphinx error encrypter doesn't expose the shared secret with
+	// the sender, other than through its encoding.
+	var sharedSecret sphinx.Hash256
+	var b bytes.Buffer
+	if err := s.OnionErrorEncrypter.Encode(&b); err != nil {
+		return nil, err
+	}
+	copy(sharedSecret[:], b.Bytes())
+
+	return AddAttribution(sharedSecret, reason, prevAttrData, holdTime), nil
+}
+
 // Type returns the identifier for a sphinx error encrypter.
 func (s *SphinxErrorEncrypter) Type() EncrypterType {
 	return EncrypterTypeSphinx
@@ -218,8 +239,8 @@ func (s *SphinxErrorEncrypter) Reextract(
 }
 
 // A compile time check to ensure SphinxErrorEncrypter implements the
-// ErrorEncrypter interface.
-var _ ErrorEncrypter = (*SphinxErrorEncrypter)(nil)
+// AttrErrorEncrypter interface.
+var _ AttrErrorEncrypter = (*SphinxErrorEncrypter)(nil)
 
 // A compile time check to ensure that IntroductionErrorEncrypter implements
 // the ErrorEncrypter interface.
@@ -83,6 +83,10 @@ type InterceptableSwitch struct {
 	// currentHeight is the currently best known height.
 	currentHeight int32
 
+	// jitChannels takes over the forwards to channels that are yet to be
+	// opened just in time. It may be nil.
+	jitChannels JITChannelHandler
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -158,6 +162,11 @@ type InterceptableSwitchConfig struct {
 	// RequireInterceptor indicates whether processing should block if no
 	// interceptor is connected.
 	RequireInterceptor bool
+
+	// JITChannels takes over the forwards to channels that are yet to be
+	// opened just in time, before they are offered to the interceptor. It
+	// may be nil.
+	JITChannels JITChannelHandler
 }
 
 // NewInterceptableSwitch returns an instance of InterceptableSwitch.
@@ -181,6 +190,7 @@ func NewInterceptableSwitch(cfg *InterceptableSwitchConfig) (
 		cltvRejectDelta:         cfg.CltvRejectDelta,
 		cltvInterceptDelta:      cfg.CltvInterceptDelta,
 		notifier:                cfg.Notifier,
+		jitChannels:             cfg.JITChannels,
 
 		quit: make(chan struct{}),
 	}, nil
@@ -492,6 +502,16 @@ func (s *InterceptableSwitch) interceptForward(packet *htlcPacket,
 			return true, nil
 		}
 
+		// Forwards to channels that are opened just in time are held
+		// until the channel is open.
+		if s.jitChannels != nil &&
+			s.jitChannels.IsJITChannel(packet.outgoingChanID) {
+
+			s.jitChannels.HandleForward(intercepted)
+
+			return true, nil
+		}
+
 		return s.forward(intercepted, isReplay)
 
 	default:
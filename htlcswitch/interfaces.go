@@ -388,6 +388,20 @@ type InterceptedForward interface {
 	FailWithCode(code lnwire.FailCode) error
 }
 
+// JITChannelHandler takes over forwards to channels that are yet to be opened
+// just in time for the forward. Forwards it handles aren't offered to the
+// ForwardInterceptor.
+type JITChannelHandler interface {
+	// IsJITChannel returns true if the short channel id identifies a
+	// channel that is yet to be opened just in time.
+	IsJITChannel(scid lnwire.ShortChannelID) bool
+
+	// HandleForward holds the forward until its channel is opened, after
+	// which it is resumed, or failed if the channel can't be opened. It
+	// must not block.
+	HandleForward(fwd InterceptedForward)
+}
+
 // htlcNotifier is an interface which represents the input side of the
 // HtlcNotifier which htlc events are piped through. This interface is intended
 // to allow for mocking of the htlcNotifier in tests, so is unexported because
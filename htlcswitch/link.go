@@ -20,6 +20,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/holdfee"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/invoices"
@@ -218,6 +219,22 @@ type ChannelLinkConfig struct {
 	// risk offering an htlc that triggers channel closure.
 	OutgoingCltvRejectDelta uint32
 
+	// ExtraCltvDelta is an optional function that returns the number of
+	// blocks added to both the time-lock delta and the outgoing CLTV
+	// reject delta we require for forwards. It allows us to demand more
+	// safety margin while the chain is irregular.
+	ExtraCltvDelta func() uint32
+
+	// HoldFeePolicy is an optional policy that requires a surcharge on top
+	// of the regular forwarding fee for htlcs that may lock up liquidity
+	// for long, and refuses htlcs whose hold risk is too high.
+	HoldFeePolicy *holdfee.Policy
+
+	// InSafeMode is an optional function that returns true while we don't
+	// accept any new HTLCs. The link then fails the HTLCs it receives as
+	// the exit hop.
+	InSafeMode func() bool
+
 	// TowerClient is an optional engine that manages the signing,
 	// encrypting, and uploading of justice transactions to the daemon's
 	// configured set of watchtowers for legacy channels.
@@ -1750,12 +1767,30 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 			return
 		}
 
+		// Add our attribution data to the failure, on top of the
+		// attribution data of the next hop if the failure was
+		// forwarded to us.
+		var (
+			prevAttrData []byte
+			receivedAt   time.Time
+		)
+		htlc.AttrData.WhenSomeV(func(attrData []byte) {
+			prevAttrData = attrData
+		})
+		if pkt.circuit != nil {
+			receivedAt = pkt.circuit.receivedAt
+		}
+		attrData := l.failAttrData(
+			pkt.obfuscator, htlc.Reason, prevAttrData, receivedAt,
+		)
+
 		// An HTLC cancellation has been triggered somewhere upstream,
 		// we'll remove then HTLC from our local state machine.
 		inKey := pkt.inKey()
 		err := l.channel.FailHTLC(
 			pkt.incomingHTLCID,
 			htlc.Reason,
+			attrData,
 			pkt.sourceRef,
 			pkt.destRef,
 			&inKey,
@@ -1801,6 +1836,7 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 			htlc.ID,
 			pkt.obfuscator,
 			htlc.Reason,
+			attrData,
 		); err != nil {
 			l.log.Errorf("unable to send HTLC failure: %v",
 				err)
@@ -2084,7 +2120,7 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// If remote side have been unable to parse the onion blob we
 		// have sent to it, than we should transform the malformed HTLC
 		// message to the usual HTLC fail message.
-		err := l.channel.ReceiveFailHTLC(msg.ID, b.Bytes())
+		err := l.channel.ReceiveFailHTLC(msg.ID, b.Bytes(), nil)
 		if err != nil {
 			l.fail(LinkFailureError{code: ErrInvalidUpdate},
 				"unable to handle upstream fail HTLC: %v", err)
@@ -2120,9 +2156,16 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			}
 		}
 
+		// Keep the attribution data of the failure, if any, so we
+		// can add ours on top of it when we forward the failure.
+		var attrData []byte
+		msg.AttrData.WhenSomeV(func(data []byte) {
+			attrData = data
+		})
+
 		// Add fail to the update log.
 		idx := msg.ID
-		err := l.channel.ReceiveFailHTLC(idx, msg.Reason[:])
+		err := l.channel.ReceiveFailHTLC(idx, msg.Reason[:], attrData)
 		if err != nil {
 			l.fail(LinkFailureError{code: ErrInvalidUpdate},
 				"unable to handle upstream fail HTLC: %v", err)
@@ -2883,7 +2926,7 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	// the following constraint: the incoming time-lock minus our time-lock
 	// delta should equal the outgoing time lock. Otherwise, whether the
 	// sender messed up, or an intermediate node tampered with the HTLC.
-	timeDelta := policy.TimeLockDelta
+	timeDelta := policy.TimeLockDelta + l.extraCltvDelta()
 	if incomingTimeout < outgoingTimeout+timeDelta {
 		l.log.Warnf("incoming htlc(%x) has incorrect time-lock value: "+
 			"expected at least %v block delta, got %v block delta",
@@ -2900,6 +2943,65 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 		return NewLinkError(failure)
 	}
 
+	// The hold fee policy may require a surcharge, which must be covered
+	// by the fee paid in excess of the regular fee.
+	if l.cfg.HoldFeePolicy != nil {
+		return l.checkHoldFee(
+			amtToForward, actualFee-expectedFee, outgoingTimeout,
+			heightNow, originalScid,
+		)
+	}
+
+	return nil
+}
+
+// checkHoldFee checks the outgoing htlc against the hold fee policy. The
+// excess fee is the fee the htlc pays on top of the regular fee.
+func (l *channelLink) checkHoldFee(amtToForward lnwire.MilliSatoshi,
+	excessFee int64, outgoingTimeout, heightNow uint32,
+	originalScid lnwire.ShortChannelID) *LinkError {
+
+	var pendingHtlcs uint16
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if !htlc.Incoming {
+			pendingHtlcs++
+		}
+	}
+
+	decision := l.cfg.HoldFeePolicy.Check(holdfee.HTLC{
+		Amount:       amtToForward,
+		Fee:          lnwire.MilliSatoshi(excessFee),
+		CltvDistance: outgoingTimeout - heightNow,
+		PendingHTLCs: pendingHtlcs,
+		MaxHTLCs:     l.channel.State().RemoteChanCfg.MaxAcceptedHtlcs,
+	})
+	if !decision.Enforced {
+		return nil
+	}
+
+	switch decision.Outcome {
+	case holdfee.OutcomeInsufficientFee:
+		l.log.Debugf("outgoing htlc with CLTV distance %d doesn't "+
+			"pay required hold fee surcharge of %v",
+			decision.CltvDistance, decision.Surcharge)
+
+		cb := func(upd *lnwire.ChannelUpdate) lnwire.FailureMessage {
+			return lnwire.NewFeeInsufficient(amtToForward, *upd)
+		}
+		failure := l.createFailureWithUpdate(false, originalScid, cb)
+		return NewLinkError(failure)
+
+	case holdfee.OutcomeRefused:
+		l.log.Debugf("refusing outgoing htlc with CLTV distance %d "+
+			"for its hold risk", decision.CltvDistance)
+
+		cb := func(upd *lnwire.ChannelUpdate) lnwire.FailureMessage {
+			return lnwire.NewTemporaryChannelFailure(upd)
+		}
+		failure := l.createFailureWithUpdate(false, originalScid, cb)
+		return NewLinkError(failure)
+	}
+
 	return nil
 }
 
@@ -2924,6 +3026,16 @@ func (l *channelLink) CheckHtlcTransit(payHash [32]byte,
 	)
 }
 
+// extraCltvDelta returns the number of blocks currently added to the CLTV
+// deltas we require.
+func (l *channelLink) extraCltvDelta() uint32 {
+	if l.cfg.ExtraCltvDelta == nil {
+		return 0
+	}
+
+	return l.cfg.ExtraCltvDelta()
+}
+
 // canSendHtlc checks whether the given htlc parameters satisfy
 // the channel's amount and time lock constraints.
 func (l *channelLink) canSendHtlc(policy models.ForwardingPolicy,
@@ -2965,7 +3077,8 @@ func (l *channelLink) canSendHtlc(policy models.ForwardingPolicy,
 	// We want to avoid offering an HTLC which will expire in the near
 	// future, so we'll reject an HTLC if the outgoing expiration time is
 	// too close to the current height.
-	if timeout <= heightNow+l.cfg.OutgoingCltvRejectDelta {
+	rejectDelta := l.cfg.OutgoingCltvRejectDelta + l.extraCltvDelta()
+	if timeout <= heightNow+rejectDelta {
 		l.log.Warnf("htlc(%x) has an expiry that's too soon: "+
 			"outgoing_expiry=%v, best_height=%v", payHash[:],
 			timeout, heightNow)
@@ -3153,15 +3266,19 @@ func (l *channelLink) processRemoteSettleFails(fwdPkg *channeldb.FwdPkg,
 			// continue to propagate it. This failure originated
 			// from another node, so the linkFailure field is not
 			// set on the packet.
+			failMsg := &lnwire.UpdateFailHTLC{
+				Reason: lnwire.OpaqueReason(pd.FailReason),
+			}
+			if len(pd.FailAttrData) > 0 {
+				failMsg.AttrData = lnwire.NewAttrDataRecord(
+					pd.FailAttrData,
+				)
+			}
 			failPacket := &htlcPacket{
 				outgoingChanID: l.ShortChanID(),
 				outgoingHTLCID: pd.ParentIndex,
 				destRef:        pd.DestRef,
-				htlc: &lnwire.UpdateFailHTLC{
-					Reason: lnwire.OpaqueReason(
-						pd.FailReason,
-					),
-				},
+				htlc:           failMsg,
 			}
 
 			l.log.Debugf("Failed to send %s", pd.Amount)
@@ -3580,6 +3697,17 @@ func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
 		return nil
 	}
 
+	// In safe mode, we don't accept any new payments.
+	if l.cfg.InSafeMode != nil && l.cfg.InSafeMode() {
+		l.log.Warnf("failing incoming htlc(%x) in safe mode",
+			pd.RHash[:])
+
+		failure := NewLinkError(&lnwire.FailTemporaryNodeFailure{})
+		l.sendHTLCError(pd, failure, obfuscator, true)
+
+		return nil
+	}
+
 	// As we're the exit hop, we'll double check the hop-payload included in
 	// the HTLC to ensure that it was crafted correctly by the sender and
 	// is compatible with the HTLC we were extended.
@@ -3726,7 +3854,13 @@ func (l *channelLink) sendHTLCError(pd *lnwallet.PaymentDescriptor,
 		return
 	}
 
-	err = l.channel.FailHTLC(pd.HtlcIndex, reason, pd.SourceRef, nil, nil)
+	// We fail the HTLC right after receiving it, so our hold time is
+	// negligible.
+	attrData := l.failAttrData(e, reason, nil, time.Time{})
+
+	err = l.channel.FailHTLC(
+		pd.HtlcIndex, reason, attrData, pd.SourceRef, nil, nil,
+	)
 	if err != nil {
 		l.log.Errorf("unable cancel htlc: %v", err)
 		return
@@ -3735,7 +3869,7 @@ func (l *channelLink) sendHTLCError(pd *lnwallet.PaymentDescriptor,
 	// Send the appropriate failure message depending on whether we're
 	// in a blinded route or not.
 	if err := l.sendIncomingHTLCFailureMsg(
-		pd.HtlcIndex, e, reason,
+		pd.HtlcIndex, e, reason, attrData,
 	); err != nil {
 		l.log.Errorf("unable to send HTLC failure: %v", err)
 		return
@@ -3768,22 +3902,52 @@ func (l *channelLink) sendHTLCError(pd *lnwallet.PaymentDescriptor,
 	)
 }
 
+// failAttrData returns the attribution data to send along with the failure of
+// an incoming HTLC, which includes the attribution data of the next hop if
+// the failure was forwarded to us. The time we received the HTLC is used to
+// report our hold time, which is zero if it isn't known. Nil is returned if the
+// error encrypter of the HTLC can't add attribution data.
+func (l *channelLink) failAttrData(e hop.ErrorEncrypter,
+	reason lnwire.OpaqueReason, prevAttrData []byte,
+	receivedAt time.Time) []byte {
+
+	attrEncrypter, ok := e.(hop.AttrErrorEncrypter)
+	if !ok {
+		return nil
+	}
+
+	var holdTime time.Duration
+	if !receivedAt.IsZero() {
+		holdTime = time.Since(receivedAt)
+	}
+
+	attrData, err := attrEncrypter.AddAttribution(
+		reason, prevAttrData, holdTime,
+	)
+	if err != nil {
+		l.log.Errorf("unable to add attribution data: %v", err)
+		return nil
+	}
+
+	return attrData
+}
+
 // sendPeerHTLCFailure handles sending a HTLC failure message back to the
 // peer from which the HTLC was received. This function is primarily used to
 // handle the special requirements of route blinding, specifically:
 // - Forwarding nodes must switch out any errors with MalformedFailHTLC
 // - Introduction nodes should return regular HTLC failure messages.
 //
-// It accepts the original opaque failure, which will be used in the case
-// that we're not part of a blinded route and an error encrypter that'll be
-// used if we are the introduction node and need to present an error as if
-// we're the failing party.
+// It accepts the original opaque failure and its attribution data, which will
+// be used in the case that we're not part of a blinded route and an error
+// encrypter that'll be used if we are the introduction node and need to
+// present an error as if we're the failing party.
 //
 // Note: this function does not yet handle special error cases for receiving
 // nodes in blinded paths, as LND does not support blinded receives.
 func (l *channelLink) sendIncomingHTLCFailureMsg(htlcIndex uint64,
-	e hop.ErrorEncrypter,
-	originalFailure lnwire.OpaqueReason) error {
+	e hop.ErrorEncrypter, originalFailure lnwire.OpaqueReason,
+	attrData []byte) error {
 
 	var msg lnwire.Message
 	switch {
@@ -3808,11 +3972,15 @@ func (l *channelLink) sendIncomingHTLCFailureMsg(htlcIndex uint64,
 	// For cleartext hops (ie, non-blinded/normal) we don't need any
 	// transformation on the error message and can just send the original.
 	case !e.Type().IsBlinded():
-		msg = &lnwire.UpdateFailHTLC{
+		failMsg := &lnwire.UpdateFailHTLC{
 			ChanID: l.ChanID(),
 			ID:     htlcIndex,
 			Reason: originalFailure,
 		}
+		if len(attrData) > 0 {
+			failMsg.AttrData = lnwire.NewAttrDataRecord(attrData)
+		}
+		msg = failMsg
 
 	// When we're the introduction node, we need to convert the error to
 	// a UpdateFailHTLC.
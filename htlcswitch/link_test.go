@@ -2651,7 +2651,7 @@ func TestChannelLinkBandwidthConsistency(t *testing.T) {
 	reason := make([]byte, 292)
 	copy(reason, []byte("nop"))
 
-	err = harness.bobChannel.FailHTLC(bobIndex, reason, nil, nil, nil)
+	err = harness.bobChannel.FailHTLC(bobIndex, reason, nil, nil, nil, nil)
 	require.NoError(t, err, "unable to fail htlc")
 	failMsg := &lnwire.UpdateFailHTLC{
 		ID:     1,
@@ -2898,7 +2898,9 @@ func TestChannelLinkBandwidthConsistency(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected UpdateFailHTLC, got %T", msg)
 	}
-	err = harness.bobChannel.ReceiveFailHTLC(failMsg.ID, []byte("fail"))
+	err = harness.bobChannel.ReceiveFailHTLC(
+		failMsg.ID, []byte("fail"), nil,
+	)
 	require.NoError(t, err, "failed receiving fail htlc")
 
 	// After failing an HTLC, the link will automatically trigger
@@ -6266,6 +6268,35 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	})
 
+	// An extra CLTV delta is added to both the time-lock delta and the
+	// outgoing CLTV reject delta.
+	t.Run("extra cltv delta", func(t *testing.T) {
+		link.cfg.ExtraCltvDelta = func() uint32 { return 31 }
+		defer func() {
+			link.cfg.ExtraCltvDelta = nil
+		}()
+
+		result := link.CheckHtlcForward(hash, 1500, 1000,
+			200, 150, models.InboundFee{}, 0,
+			lnwire.ShortChannelID{},
+		)
+		_, ok := result.WireMessage().(*lnwire.FailIncorrectCltvExpiry)
+		require.True(t, ok, "expected FailIncorrectCltvExpiry")
+
+		result = link.CheckHtlcForward(hash, 1500, 1000,
+			300, 150, models.InboundFee{}, 120,
+			lnwire.ShortChannelID{},
+		)
+		_, ok = result.WireMessage().(*lnwire.FailExpiryTooSoon)
+		require.True(t, ok, "expected FailExpiryTooSoon")
+
+		result = link.CheckHtlcForward(hash, 1500, 1000,
+			201, 150, models.InboundFee{}, 118,
+			lnwire.ShortChannelID{},
+		)
+		require.Nil(t, result)
+	})
+
 	t.Run("cltv expiry too far in the future", func(t *testing.T) {
 		// Check that expiry isn't too far in the future.
 		result := link.CheckHtlcForward(hash, 1500, 1000,
@@ -7240,7 +7271,7 @@ func TestChannelLinkShortFailureRelay(t *testing.T) {
 	// Return a short htlc failure from Bob to Alice and lock in.
 	shortReason := make([]byte, 260)
 
-	err = harness.bobChannel.FailHTLC(0, shortReason, nil, nil, nil)
+	err = harness.bobChannel.FailHTLC(0, shortReason, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	harness.aliceLink.HandleChannelUpdate(&lnwire.UpdateFailHTLC{
@@ -1,6 +1,10 @@
 package htlcswitch
 
-import "github.com/go-errors/errors"
+import (
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
 
 var (
 	// ErrLinkShuttingDown signals that the link is shutting down.
@@ -151,3 +155,31 @@ func (e LinkFailureError) ShouldSendToPeer() bool {
 		return false
 	}
 }
+
+// WireCode returns the machine-readable error code that is sent to the peer
+// along with the error for this LinkFailureError. No error code is returned if
+// the link failed because of an error of the peer, as we merely echo it.
+func (e LinkFailureError) WireCode() fn.Option[lnwire.ErrorCode] {
+	switch e.code {
+	case ErrRemoteError:
+		return fn.None[lnwire.ErrorCode]()
+
+	case ErrSyncError:
+		return fn.Some(lnwire.ErrorCodeSyncFailed)
+
+	case ErrInvalidUpdate:
+		return fn.Some(lnwire.ErrorCodeInvalidUpdate)
+
+	case ErrInvalidCommitment:
+		return fn.Some(lnwire.ErrorCodeInvalidCommitSig)
+
+	case ErrInvalidRevocation:
+		return fn.Some(lnwire.ErrorCodeInvalidRevocation)
+
+	case ErrRecoveryError:
+		return fn.Some(lnwire.ErrorCodeRecoveryRequired)
+
+	default:
+		return fn.Some(lnwire.ErrorCodeInternal)
+	}
+}
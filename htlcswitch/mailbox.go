@@ -11,6 +11,7 @@ import (
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
 )
 
 var (
@@ -591,6 +592,9 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 		if m.repHead == nil {
 			m.repHead = entry
 		}
+		metrics.ObserveMailboxDepth(
+			metrics.QueueSettleFail, m.repPkts.Len(),
+		)
 
 	// Split off Add packets into the addPkts queue.
 	case *lnwire.UpdateAddHTLC:
@@ -607,6 +611,7 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 		if m.addHead == nil {
 			m.addHead = entry
 		}
+		metrics.ObserveMailboxDepth(metrics.QueueAdd, m.addPkts.Len())
 
 	default:
 		m.pktCond.L.Unlock()
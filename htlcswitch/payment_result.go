@@ -6,9 +6,12 @@ import (
 	"errors"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/multimutex"
 )
@@ -57,11 +60,27 @@ type networkResult struct {
 	// isResolution indicates whether this is a resolution message, in
 	// which the failure reason might not be included.
 	isResolution bool
+
+	// storedAt is the time the result was stored. It is zero for results
+	// stored before the time was recorded.
+	storedAt time.Time
 }
 
 // serializeNetworkResult serializes the networkResult.
 func serializeNetworkResult(w io.Writer, n *networkResult) error {
-	return channeldb.WriteElements(w, n.msg, n.unencrypted, n.isResolution)
+	err := channeldb.WriteElements(w, n.msg, n.unencrypted, n.isResolution)
+	if err != nil {
+		return err
+	}
+
+	// The time the result was stored is appended, so that results stored
+	// before it was recorded can still be read.
+	var storedAt uint64
+	if !n.storedAt.IsZero() {
+		storedAt = uint64(n.storedAt.UnixNano())
+	}
+
+	return channeldb.WriteElements(w, storedAt)
 }
 
 // deserializeNetworkResult deserializes the networkResult.
@@ -74,9 +93,76 @@ func deserializeNetworkResult(r io.Reader) (*networkResult, error) {
 		return nil, err
 	}
 
+	var storedAt uint64
+	err := channeldb.ReadElements(r, &storedAt)
+	switch {
+	// Results stored before the time was recorded end here.
+	case errors.Is(err, io.EOF):
+
+	case err != nil:
+		return nil, err
+
+	case storedAt != 0:
+		n.storedAt = time.Unix(0, int64(storedAt))
+	}
+
 	return n, nil
 }
 
+// NetworkResultInfo describes a result in the network result store.
+type NetworkResultInfo struct {
+	// AttemptID is the ID of the payment attempt the result is for.
+	AttemptID uint64
+
+	// StoredAt is the time the result was stored. It is zero for results
+	// stored before the time was recorded.
+	StoredAt time.Time
+
+	// Settled is true if the attempt was settled, and false if it failed.
+	Settled bool
+
+	// Preimage is the preimage a settled attempt was settled with.
+	Preimage lntypes.Preimage
+
+	// Unencrypted is true if the failure reason of a failed attempt isn't
+	// encrypted, because the failure happened locally.
+	Unencrypted bool
+
+	// IsResolution is true if the result was resolved by a resolution
+	// message, which may not include the failure reason.
+	IsResolution bool
+}
+
+// newNetworkResultInfo returns the description of the result of the attempt.
+func newNetworkResultInfo(attemptID uint64,
+	n *networkResult) *NetworkResultInfo {
+
+	info := &NetworkResultInfo{
+		AttemptID:    attemptID,
+		StoredAt:     n.storedAt,
+		Unencrypted:  n.unencrypted,
+		IsResolution: n.isResolution,
+	}
+
+	if settle, ok := n.msg.(*lnwire.UpdateFulfillHTLC); ok {
+		info.Settled = true
+		info.Preimage = settle.PaymentPreimage
+	}
+
+	return info
+}
+
+// NetworkResultsQuery selects a page of the results in the network result
+// store, which are ordered by their attempt ID.
+type NetworkResultsQuery struct {
+	// FirstAttemptID is the attempt ID the page starts at.
+	FirstAttemptID uint64
+
+	// MaxResults is the maximum number of results returned. Zero returns
+	// all results starting at the first attempt ID.
+	MaxResults int
+}
+
 // networkResultStore is a persistent store that stores any results of HTLCs in
 // flight on the network. Since payment results are inherently asynchronous, it
 // is used as a common access point for senders of HTLCs, to know when a result
@@ -85,6 +171,9 @@ func deserializeNetworkResult(r io.Reader) (*networkResult, error) {
 type networkResultStore struct {
 	backend kvdb.Backend
 
+	// clock is used to record the time results are stored.
+	clock clock.Clock
+
 	// results is a map from paymentIDs to channels where subscribers to
 	// payment results will be notified.
 	results    map[uint64][]chan *networkResult
@@ -96,9 +185,12 @@ type networkResultStore struct {
 	paymentIDMtx *multimutex.Mutex[uint64]
 }
 
-func newNetworkResultStore(db kvdb.Backend) *networkResultStore {
+func newNetworkResultStore(db kvdb.Backend,
+	clock clock.Clock) *networkResultStore {
+
 	return &networkResultStore{
 		backend:      db,
+		clock:        clock,
 		results:      make(map[uint64][]chan *networkResult),
 		paymentIDMtx: multimutex.NewMutex[uint64](),
 	}
@@ -117,6 +209,10 @@ func (store *networkResultStore) storeResult(paymentID uint64,
 
 	log.Debugf("Storing result for paymentID=%v", paymentID)
 
+	if result.storedAt.IsZero() {
+		result.storedAt = store.clock.Now()
+	}
+
 	// Serialize the payment result.
 	var b bytes.Buffer
 	if err := serializeNetworkResult(&b, result); err != nil {
@@ -298,3 +394,99 @@ func (store *networkResultStore) cleanStore(keep map[uint64]struct{}) error {
 		return nil
 	}, func() {})
 }
+
+// fetchResults returns a page of the stored results, ordered by their attempt
+// ID.
+func (store *networkResultStore) fetchResults(
+	q *NetworkResultsQuery) ([]*NetworkResultInfo, error) {
+
+	var results []*NetworkResultInfo
+	err := kvdb.View(store.backend, func(tx kvdb.RTx) error {
+		networkResults := tx.ReadBucket(networkResultStoreBucketKey)
+		if networkResults == nil {
+			return nil
+		}
+
+		var firstKey [8]byte
+		binary.BigEndian.PutUint64(firstKey[:], q.FirstAttemptID)
+
+		// The keys are big endian encoded attempt IDs, so the cursor
+		// iterates them in order.
+		cursor := networkResults.ReadCursor()
+		k, v := cursor.Seek(firstKey[:])
+		for ; k != nil; k, v = cursor.Next() {
+			if q.MaxResults > 0 && len(results) >= q.MaxResults {
+				return nil
+			}
+
+			n, err := deserializeNetworkResult(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			pid := binary.BigEndian.Uint64(k)
+			results = append(results, newNetworkResultInfo(pid, n))
+		}
+
+		return nil
+	}, func() {
+		results = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// pruneResults removes the results the prune function selects, given their
+// attempt ID and the time they were stored. It returns the number of removed
+// results.
+func (store *networkResultStore) pruneResults(
+	prune func(pid uint64, storedAt time.Time) bool) (int, error) {
+
+	var numPruned int
+	err := kvdb.Update(store.backend, func(tx kvdb.RwTx) error {
+		networkResults := tx.ReadWriteBucket(
+			networkResultStoreBucketKey,
+		)
+		if networkResults == nil {
+			return nil
+		}
+
+		var toPrune [][]byte
+		err := networkResults.ForEach(func(k, v []byte) error {
+			n, err := deserializeNetworkResult(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			pid := binary.BigEndian.Uint64(k)
+			if prune(pid, n.storedAt) {
+				toPrune = append(toPrune, k)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range toPrune {
+			if err := networkResults.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		numPruned = len(toPrune)
+
+		return nil
+	}, func() {
+		numPruned = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numPruned, nil
+}
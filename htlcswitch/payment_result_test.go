@@ -9,6 +9,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/stretchr/testify/require"
@@ -74,6 +75,10 @@ func TestNetworkResultSerialization(t *testing.T) {
 			unencrypted:  true,
 			isResolution: false,
 		},
+		{
+			msg:      settle,
+			storedAt: time.Unix(1_700_000_000, 123),
+		},
 	}
 
 	for _, p := range testCases {
@@ -108,7 +113,7 @@ func TestNetworkResultStore(t *testing.T) {
 	}
 	t.Cleanup(func() { db.Close() })
 
-	store := newNetworkResultStore(db)
+	store := newNetworkResultStore(db, clock.NewDefaultClock())
 
 	var results []*networkResult
 	for i := 0; i < numResults; i++ {
@@ -206,3 +211,67 @@ func TestNetworkResultStore(t *testing.T) {
 		}
 	}
 }
+
+// TestNetworkResultStorePruning tests that the results in the networkResult
+// store can be queried and pruned.
+func TestNetworkResultStorePruning(t *testing.T) {
+	t.Parallel()
+
+	db, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	testClock := clock.NewTestClock(time.Unix(1_700_000_000, 0))
+	store := newNetworkResultStore(db, testClock)
+
+	// Store a result every minute.
+	const numResults = 5
+	for i := uint64(0); i < numResults; i++ {
+		err := store.storeResult(i, &networkResult{
+			msg: &lnwire.UpdateFailHTLC{},
+		})
+		require.NoError(t, err)
+
+		testClock.SetTime(testClock.Now().Add(time.Minute))
+	}
+
+	// The results are returned in pages ordered by their attempt ID.
+	results, err := store.fetchResults(&NetworkResultsQuery{
+		FirstAttemptID: 1,
+		MaxResults:     3,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for i, result := range results {
+		require.EqualValues(t, i+1, result.AttemptID)
+		require.False(t, result.Settled)
+		require.Equal(
+			t, time.Unix(1_700_000_000, 0).Add(
+				time.Duration(i+1)*time.Minute,
+			).UnixNano(), result.StoredAt.UnixNano(),
+		)
+	}
+
+	results, err = store.fetchResults(&NetworkResultsQuery{})
+	require.NoError(t, err)
+	require.Len(t, results, numResults)
+
+	// Prune the results stored in the first two minutes.
+	cutoff := time.Unix(1_700_000_000, 0).Add(2 * time.Minute)
+	numPruned, err := store.pruneResults(
+		func(_ uint64, storedAt time.Time) bool {
+			return storedAt.Before(cutoff)
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, numPruned)
+
+	for i := uint64(0); i < numResults; i++ {
+		_, err := store.getResult(i)
+		if i < 2 {
+			require.ErrorIs(t, err, ErrPaymentIDNotFound)
+			continue
+		}
+		require.NoError(t, err)
+	}
+}
@@ -18,12 +18,14 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/fault"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
 	"github.com/lightningnetwork/lnd/ticker"
 )
 
@@ -43,6 +45,14 @@ const (
 	// DefaultMailboxDeliveryTimeout is the duration after which Adds will
 	// be cancelled if they could not get added to an outgoing commitment.
 	DefaultMailboxDeliveryTimeout = time.Minute
+
+	// DefaultNetworkResultRetention is the default duration the network
+	// results of resolved payment attempts are kept.
+	DefaultNetworkResultRetention = 7 * 24 * time.Hour
+
+	// DefaultNetworkResultPruneInterval is the default duration between
+	// prunes of the network result store.
+	DefaultNetworkResultPruneInterval = time.Hour
 )
 
 var (
@@ -222,6 +232,30 @@ type Config struct {
 
 	// IsAlias returns whether or not a given SCID is an alias.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// FaultInjector is used in dev builds to fail forwards on demand. It
+	// is nil otherwise.
+	FaultInjector *fault.Injector
+
+	// JammingMitigated returns true if forwards from the given incoming
+	// channel are refused because it is suspected of jamming. It is nil
+	// if jamming detection isn't active.
+	JammingMitigated func(incoming lnwire.ShortChannelID) bool
+
+	// FetchHtlcAttemptIDs returns the IDs of the HTLC attempts of all
+	// payments, mapped to whether the attempt is still in flight. It is
+	// used to prune the network results of resolved attempts and of
+	// deleted payments.
+	FetchHtlcAttemptIDs func() (map[uint64]bool, error)
+
+	// NetworkResultRetention is the time the network results of resolved
+	// attempts are kept. Zero keeps them until their payment is deleted.
+	NetworkResultRetention time.Duration
+
+	// NetworkResultPruneTicker signals the switch to prune the network
+	// result store. It may be nil, in which case the store is only
+	// pruned on request.
+	NetworkResultPruneTicker ticker.Ticker
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -297,6 +331,18 @@ type Switch struct {
 	// MUST be used with the indexMtx.
 	linkStopIndex map[lnwire.ChannelID]chan struct{}
 
+	// frozenLinks is the set of channels in maintenance mode, whose links
+	// don't forward new HTLCs in either direction. The set is kept
+	// independently of the links so it survives the peer reconnecting.
+	//
+	// MUST be used with the indexMtx.
+	frozenLinks map[lnwire.ChannelID]struct{}
+
+	// safeMode is set while the switch doesn't accept any new HTLCs,
+	// neither forwards nor local payments, for example because the
+	// database stalls.
+	safeMode atomic.Bool
+
 	// htlcPlex is the channel which all connected links use to coordinate
 	// the setup/teardown of Sphinx (onion routing) payment circuits.
 	// Active links forward any add/settle messages over this channel each
@@ -373,7 +419,8 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		interfaceIndex:    make(map[[33]byte]map[lnwire.ChannelID]ChannelLink),
 		pendingLinkIndex:  make(map[lnwire.ChannelID]ChannelLink),
 		linkStopIndex:     make(map[lnwire.ChannelID]chan struct{}),
-		networkResults:    newNetworkResultStore(cfg.DB),
+		frozenLinks:       make(map[lnwire.ChannelID]struct{}),
+		networkResults:    newNetworkResultStore(cfg.DB, cfg.Clock),
 		htlcPlex:          make(chan *plexPacket),
 		chanCloseRequests: make(chan *ChanClose),
 		resolutionMsgs:    make(chan *resolutionMsg),
@@ -518,6 +565,86 @@ func (s *Switch) CleanStore(keepPids map[uint64]struct{}) error {
 	return s.networkResults.cleanStore(keepPids)
 }
 
+// QueryNetworkResults returns a page of the results of our payment attempts
+// that are kept in the network result store.
+func (s *Switch) QueryNetworkResults(
+	q *NetworkResultsQuery) ([]*NetworkResultInfo, error) {
+
+	return s.networkResults.fetchResults(q)
+}
+
+// PruneNetworkResults removes the network results that are no longer needed
+// and returns the number of removed results. Results of attempts that are in
+// flight are always kept. Results of resolved attempts are removed once the
+// retention passed, and results of attempts whose payment was deleted are
+// removed right away.
+func (s *Switch) PruneNetworkResults() (int, error) {
+	if s.cfg.FetchHtlcAttemptIDs == nil {
+		return 0, errors.New("attempt IDs unavailable")
+	}
+
+	// Results stored after the attempts are fetched may belong to
+	// attempts we don't know about yet, so they aren't considered
+	// orphaned.
+	now := s.cfg.Clock.Now()
+	attempts, err := s.cfg.FetchHtlcAttemptIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	retention := s.cfg.NetworkResultRetention
+	numPruned, err := s.networkResults.pruneResults(
+		func(pid uint64, storedAt time.Time) bool {
+			inFlight, ok := attempts[pid]
+			switch {
+			case !ok:
+				return storedAt.Before(now)
+
+			case inFlight:
+				return false
+
+			default:
+				return retention > 0 &&
+					storedAt.Before(now.Add(-retention))
+			}
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if numPruned > 0 {
+		log.Infof("Pruned %d entries from network result store",
+			numPruned)
+	}
+
+	return numPruned, nil
+}
+
+// pruneNetworkResultsPeriodically prunes the network result store on every
+// tick of the prune ticker.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *Switch) pruneNetworkResultsPeriodically() {
+	defer s.wg.Done()
+
+	s.cfg.NetworkResultPruneTicker.Resume()
+	defer s.cfg.NetworkResultPruneTicker.Stop()
+
+	for {
+		select {
+		case <-s.cfg.NetworkResultPruneTicker.Ticks():
+			if _, err := s.PruneNetworkResults(); err != nil {
+				log.Errorf("Unable to prune network result "+
+					"store: %v", err)
+			}
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
 // SendHTLC is used by other subsystems which aren't belong to htlc switch
 // package in order to send the htlc update. The attemptID used MUST be unique
 // for this HTLC, and MUST be used only once, otherwise the switch might reject
@@ -703,6 +830,7 @@ func (s *Switch) ForwardPackets(linkQuit chan struct{},
 		switch htlc := packet.htlc.(type) {
 		case *lnwire.UpdateAddHTLC:
 			circuit := newPaymentCircuit(&htlc.PaymentHash, packet)
+			circuit.receivedAt = time.Now()
 			packet.circuit = circuit
 			circuits = append(circuits, circuit)
 			addBatch = append(addBatch, packet)
@@ -855,6 +983,14 @@ func (s *Switch) routeAsync(packet *htlcPacket, errChan chan error,
 func (s *Switch) getLocalLink(pkt *htlcPacket, htlc *lnwire.UpdateAddHTLC) (
 	ChannelLink, *LinkError) {
 
+	// In safe mode, we don't send any new payments.
+	if s.InSafeMode() {
+		log.Warnf("Not sending htlc(%x) in safe mode",
+			htlc.PaymentHash[:])
+
+		return nil, NewLinkError(&lnwire.FailTemporaryNodeFailure{})
+	}
+
 	// Try to find links by node destination.
 	s.indexMtx.RLock()
 	link, err := s.getLinkByShortID(pkt.outgoingChanID)
@@ -883,7 +1019,7 @@ func (s *Switch) getLocalLink(pkt *htlcPacket, htlc *lnwire.UpdateAddHTLC) (
 		}
 	}
 
-	if !link.EligibleToForward() {
+	if !link.EligibleToForward() || s.isLinkFrozen(link.ChanID()) {
 		log.Errorf("Link %v is not available to forward",
 			pkt.outgoingChanID)
 
@@ -1085,6 +1221,17 @@ func (s *Switch) parseFailedPayment(deobfuscator ErrorDecrypter,
 				"(hash=%v, pid=%d): %v",
 				paymentHash, attemptID, err)
 
+			// If the hops added attribution data to the failure,
+			// we can still pin it to the hop that made it
+			// unreadable. It's reported as an unknown failure of
+			// that hop, so that only that hop is penalized
+			// instead of the whole route.
+			if failure := attributeFailure(
+				deobfuscator, htlc,
+			); failure != nil {
+				return failure
+			}
+
 			return ErrUnreadableFailureMessage
 		}
 
@@ -1092,6 +1239,34 @@ func (s *Switch) parseFailedPayment(deobfuscator ErrorDecrypter,
 	}
 }
 
+// attributeFailure pins a failure that can't be decrypted to the hop that made
+// it unreadable, using the attribution data of the failure. Nil is returned if
+// the failure has no attribution data, or the decrypter can't use it.
+func attributeFailure(deobfuscator ErrorDecrypter,
+	htlc *lnwire.UpdateFailHTLC) *ForwardingError {
+
+	attrDecrypter, ok := deobfuscator.(AttrErrorDecrypter)
+	if !ok {
+		return nil
+	}
+
+	var attrData []byte
+	htlc.AttrData.WhenSomeV(func(data []byte) {
+		attrData = data
+	})
+	if attrData == nil {
+		return nil
+	}
+
+	pos, err := attrDecrypter.AttributeError(htlc.Reason, attrData)
+	if err != nil {
+		log.Errorf("Unable to attribute onion failure: %v", err)
+		return nil
+	}
+
+	return NewUnknownForwardingError(pos)
+}
+
 // handlePacketForward is used in cases when we need forward the htlc update
 // from one channel link to another and be able to propagate the settle/fail
 // updates back. This behaviour is achieved by creation of payment circuits.
@@ -1113,6 +1288,39 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.failAddPacket(packet, failure)
 		}
 
+		// In safe mode, we don't forward any new HTLCs.
+		if s.InSafeMode() {
+			failure := NewDetailedLinkError(
+				&lnwire.FailTemporaryNodeFailure{},
+				OutgoingFailureForwardsDisabled,
+			)
+
+			return s.failAddPacket(packet, failure)
+		}
+
+		// Forwards from channels suspected of jamming are refused
+		// temporarily, so they can't occupy more slots or liquidity
+		// of our outgoing channels.
+		if s.cfg.JammingMitigated != nil &&
+			s.cfg.JammingMitigated(packet.incomingChanID) {
+
+			failure := NewLinkError(
+				lnwire.NewTemporaryChannelFailure(nil),
+			)
+
+			return s.failAddPacket(packet, failure)
+		}
+
+		// In dev builds, a failure of this forward may have been
+		// requested to reproduce edge cases.
+		if s.cfg.FaultInjector.Trigger(fault.ForwardHTLC) {
+			failure := NewLinkError(
+				lnwire.NewTemporaryChannelFailure(nil),
+			)
+
+			return s.failAddPacket(packet, failure)
+		}
+
 		// Before we attempt to find a non-strict forwarding path for
 		// this htlc, check whether the htlc is being routed over the
 		// same incoming and outgoing channel. If our node does not
@@ -1149,6 +1357,13 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		}
 		targetPeerKey := targetLink.PeerPubKey()
 		interfaceLinks, _ := s.getLinks(targetPeerKey)
+
+		frozenLinks := make(map[lnwire.ChannelID]struct{})
+		for _, link := range interfaceLinks {
+			if s.isLinkFrozen(link.ChanID()) {
+				frozenLinks[link.ChanID()] = struct{}{}
+			}
+		}
 		s.indexMtx.RUnlock()
 
 		// We'll keep track of any HTLC failures during the link
@@ -1163,14 +1378,26 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		for _, link := range interfaceLinks {
 			var failure *LinkError
 
+			_, frozen := frozenLinks[link.ChanID()]
+
 			// We'll skip any links that aren't yet eligible for
 			// forwarding.
-			if !link.EligibleToForward() {
+			switch {
+			case !link.EligibleToForward():
 				failure = NewDetailedLinkError(
 					&lnwire.FailUnknownNextPeer{},
 					OutgoingFailureLinkNotEligible,
 				)
-			} else {
+
+			// Links in maintenance mode don't forward any HTLCs,
+			// as their channels are announced as disabled.
+			case frozen:
+				failure = NewDetailedLinkError(
+					&lnwire.FailChannelDisabled{},
+					OutgoingFailureLinkNotEligible,
+				)
+
+			default:
 				// We'll ensure that the HTLC satisfies the
 				// current forwarding conditions of this target
 				// link.
@@ -1236,6 +1463,8 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		// the incomingChanID is never set to hop.Source here.
 		s.indexMtx.RLock()
 		incomingLink, err := s.getLinkByShortID(packet.incomingChanID)
+		incomingFrozen := err == nil &&
+			s.isLinkFrozen(incomingLink.ChanID())
 		s.indexMtx.RUnlock()
 		if err != nil {
 			// If we couldn't find the incoming link, we can't
@@ -1248,6 +1477,16 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.failAddPacket(packet, linkErr)
 		}
 
+		// If the incoming link is in maintenance mode, we don't
+		// forward the HTLCs it receives.
+		if incomingFrozen {
+			linkErr := NewLinkError(
+				&lnwire.FailTemporaryChannelFailure{},
+			)
+
+			return s.failAddPacket(packet, linkErr)
+		}
+
 		// Evaluate whether this HTLC would increase our exposure to
 		// dust on the incoming link. If it does, fail it backwards.
 		if s.evaluateDustThreshold(
@@ -1372,6 +1611,18 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			}
 		}
 
+		// Record the time it took to resolve the forwarded HTLC.
+		if !circuit.receivedAt.IsZero() {
+			outcome := metrics.OutcomeSuccess
+			if isFail {
+				outcome = metrics.OutcomeFailure
+			}
+
+			metrics.ObserveForward(
+				outcome, time.Since(circuit.receivedAt),
+			)
+		}
+
 		// A blank IncomingChanID in a circuit indicates that it is a pending
 		// user-initiated payment.
 		if packet.incomingChanID == hop.Source {
@@ -2027,6 +2278,11 @@ func (s *Switch) Start() error {
 	s.wg.Add(1)
 	go s.htlcForwarder()
 
+	if s.cfg.NetworkResultPruneTicker != nil {
+		s.wg.Add(1)
+		go s.pruneNetworkResultsPeriodically()
+	}
+
 	if err := s.reforwardResponses(); err != nil {
 		s.Stop()
 		log.Errorf("unable to reforward responses: %v", err)
@@ -2228,13 +2484,21 @@ func (s *Switch) reforwardSettleFails(fwdPkgs []*channeldb.FwdPkg) {
 				// the linkFailure field is not set on this
 				// packet. We rely on the link to fill in
 				// additional circuit information for us.
+				reason := lnwire.OpaqueReason(pd.FailReason)
+				failMsg := &lnwire.UpdateFailHTLC{
+					Reason: reason,
+				}
+				if len(pd.FailAttrData) > 0 {
+					attrData := lnwire.NewAttrDataRecord(
+						pd.FailAttrData,
+					)
+					failMsg.AttrData = attrData
+				}
 				failPacket := &htlcPacket{
 					outgoingChanID: fwdPkg.Source,
 					outgoingHTLCID: pd.ParentIndex,
 					destRef:        pd.DestRef,
-					htlc: &lnwire.UpdateFailHTLC{
-						Reason: lnwire.OpaqueReason(pd.FailReason),
-					},
+					htlc:           failMsg,
 				}
 
 				// Add the packet to the batch to be forwarded, and
@@ -2556,6 +2820,55 @@ func (s *Switch) HasActiveLink(chanID lnwire.ChannelID) bool {
 	return false
 }
 
+// FreezeLink puts the link of the given channel into maintenance mode, in
+// which it doesn't forward new HTLCs in either direction, nor send new local
+// payments. HTLCs that are already in flight are resolved as usual.
+func (s *Switch) FreezeLink(chanID lnwire.ChannelID) {
+	s.indexMtx.Lock()
+	defer s.indexMtx.Unlock()
+
+	s.frozenLinks[chanID] = struct{}{}
+}
+
+// UnfreezeLink takes the link of the given channel out of maintenance mode.
+func (s *Switch) UnfreezeLink(chanID lnwire.ChannelID) {
+	s.indexMtx.Lock()
+	defer s.indexMtx.Unlock()
+
+	delete(s.frozenLinks, chanID)
+}
+
+// isLinkFrozen returns true if the link of the given channel is in maintenance
+// mode.
+//
+// NOTE: This MUST be called with the indexMtx held.
+func (s *Switch) isLinkFrozen(chanID lnwire.ChannelID) bool {
+	_, ok := s.frozenLinks[chanID]
+	return ok
+}
+
+// EnterSafeMode puts the switch into safe mode, in which it doesn't accept any
+// new HTLCs, neither forwards nor local payments. HTLCs that are already in
+// flight are resolved as usual.
+func (s *Switch) EnterSafeMode(reason string) {
+	if !s.safeMode.Swap(true) {
+		log.Warnf("Entering safe mode, not accepting new HTLCs: %v",
+			reason)
+	}
+}
+
+// ExitSafeMode takes the switch out of safe mode.
+func (s *Switch) ExitSafeMode() {
+	if s.safeMode.Swap(false) {
+		log.Infof("Exiting safe mode, accepting new HTLCs again")
+	}
+}
+
+// InSafeMode returns true if the switch is in safe mode.
+func (s *Switch) InSafeMode() bool {
+	return s.safeMode.Load()
+}
+
 // RemoveLink purges the switch of any link associated with chanID. If a pending
 // or active link is not found, this method does nothing. Otherwise, the method
 // returns after the link has been completely shutdown.
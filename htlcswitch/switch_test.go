@@ -8,6 +8,7 @@ import (
 	"io"
 	mrand "math/rand"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
@@ -4258,6 +4260,80 @@ func TestInterceptableSwitchWatchDog(t *testing.T) {
 	}))
 }
 
+// mockJITChannelHandler takes over the forwards to a single short channel id.
+type mockJITChannelHandler struct {
+	scid     lnwire.ShortChannelID
+	forwards chan InterceptedForward
+}
+
+func (m *mockJITChannelHandler) IsJITChannel(
+	scid lnwire.ShortChannelID) bool {
+
+	return scid == m.scid
+}
+
+func (m *mockJITChannelHandler) HandleForward(fwd InterceptedForward) {
+	m.forwards <- fwd
+}
+
+// TestInterceptableSwitchJITChannel tests that forwards to just-in-time
+// channels are handed to their handler instead of the interceptor.
+func TestInterceptableSwitchJITChannel(t *testing.T) {
+	t.Parallel()
+
+	c := newInterceptableSwitchTestContext(t)
+	defer c.finish()
+
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight}
+
+	jitChannels := &mockJITChannelHandler{
+		scid:     c.bobChannelLink.ShortChanID(),
+		forwards: make(chan InterceptedForward, 1),
+	}
+
+	switchForwardInterceptor, err := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:             c.s,
+			CltvRejectDelta:    c.cltvRejectDelta,
+			CltvInterceptDelta: c.cltvInterceptDelta,
+			Notifier:           notifier,
+			JITChannels:        jitChannels,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, switchForwardInterceptor.Start())
+	defer func() {
+		require.NoError(t, switchForwardInterceptor.Stop())
+	}()
+
+	switchForwardInterceptor.SetInterceptor(
+		c.forwardInterceptor.InterceptForwardHtlc,
+	)
+
+	linkQuit := make(chan struct{})
+	packet := c.createTestPacket()
+
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, false, packet)
+	require.NoError(t, err)
+
+	// The forward is held by the handler until it resumes it.
+	var fwd InterceptedForward
+	select {
+	case fwd = <-jitChannels.forwards:
+	case <-time.After(time.Second):
+		t.Fatalf("forward not handed to the just-in-time handler")
+	}
+
+	require.Equal(t, packet.outgoingChanID, fwd.Packet().OutgoingChanID)
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+
+	require.NoError(t, fwd.Resume())
+	assertOutgoingLinkReceive(t, c.bobChannelLink, true)
+}
+
 // TestSwitchDustForwarding tests that the switch properly fails HTLC's which
 // have incoming or outgoing links that breach their dust thresholds.
 func TestSwitchDustForwarding(t *testing.T) {
@@ -5539,3 +5615,336 @@ func testSwitchAliasInterceptFail(t *testing.T, zeroConf bool) {
 
 	require.NoError(t, interceptSwitch.Stop())
 }
+
+// TestSwitchForwardFrozenLink tests that the switch doesn't forward HTLCs into
+// or out of links in maintenance mode.
+func TestSwitchForwardFrozenLink(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create alice server")
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create bob server")
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err, "unable to init switch")
+	require.NoError(t, s.Start(), "unable to start switch")
+	t.Cleanup(func() {
+		require.NoError(t, s.Stop())
+	})
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	// forward forwards a new HTLC from Alice to Bob, and returns the
+	// failure sent back to Alice, if any.
+	var htlcID uint64
+	forward := func() lnwire.FailureMessage {
+		t.Helper()
+
+		obfuscator := NewMockObfuscator()
+		packet := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     obfuscator,
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: [32]byte{byte(htlcID)},
+				Amount:      1,
+			},
+		}
+		htlcID++
+
+		require.NoError(t, s.ForwardPackets(nil, packet))
+
+		select {
+		case <-aliceChannelLink.packets:
+			return obfuscator.(*mockObfuscator).failure
+
+		case <-bobChannelLink.packets:
+			return nil
+
+		case <-time.After(time.Second):
+			t.Fatal("no timely reply from switch")
+		}
+
+		return nil
+	}
+
+	// HTLCs aren't forwarded out of a frozen link.
+	s.FreezeLink(chanID2)
+	failure := forward()
+	require.NotNil(t, failure)
+	require.Equal(t, lnwire.CodeChannelDisabled, failure.Code())
+
+	// Nor are HTLCs received over a frozen link forwarded.
+	s.UnfreezeLink(chanID2)
+	s.FreezeLink(chanID1)
+	failure = forward()
+	require.NotNil(t, failure)
+	require.Equal(t, lnwire.CodeTemporaryChannelFailure, failure.Code())
+
+	// Once the link is unfrozen, HTLCs are forwarded again.
+	s.UnfreezeLink(chanID1)
+	require.Nil(t, forward())
+}
+
+// TestSwitchSafeMode tests that the switch neither forwards HTLCs nor sends
+// local payments while it is in safe mode.
+func TestSwitchSafeMode(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create alice server")
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create bob server")
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err, "unable to init switch")
+	require.NoError(t, s.Start(), "unable to start switch")
+	t.Cleanup(func() {
+		require.NoError(t, s.Stop())
+	})
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	// forward forwards a new HTLC from Alice to Bob, and returns the
+	// failure sent back to Alice, if any.
+	var htlcID uint64
+	forward := func() lnwire.FailureMessage {
+		t.Helper()
+
+		obfuscator := NewMockObfuscator()
+		packet := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     obfuscator,
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: [32]byte{byte(htlcID)},
+				Amount:      1,
+			},
+		}
+		htlcID++
+
+		require.NoError(t, s.ForwardPackets(nil, packet))
+
+		select {
+		case <-aliceChannelLink.packets:
+			return obfuscator.(*mockObfuscator).failure
+
+		case <-bobChannelLink.packets:
+			return nil
+
+		case <-time.After(time.Second):
+			t.Fatal("no timely reply from switch")
+		}
+
+		return nil
+	}
+
+	// send looks up the link to send a local payment to Bob over.
+	send := func() *LinkError {
+		htlc := &lnwire.UpdateAddHTLC{Amount: 1}
+		packet := &htlcPacket{
+			incomingChanID: hop.Source,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			htlc:           htlc,
+		}
+
+		_, linkErr := s.getLocalLink(packet, htlc)
+
+		return linkErr
+	}
+
+	// In safe mode, HTLCs are neither forwarded nor sent.
+	s.EnterSafeMode("test")
+	require.True(t, s.InSafeMode())
+
+	failure := forward()
+	require.NotNil(t, failure)
+	require.Equal(t, lnwire.CodeTemporaryNodeFailure, failure.Code())
+
+	linkErr := send()
+	require.NotNil(t, linkErr)
+	require.Equal(
+		t, lnwire.CodeTemporaryNodeFailure,
+		linkErr.WireMessage().Code(),
+	)
+
+	// Once out of safe mode, HTLCs are forwarded and sent again.
+	s.ExitSafeMode()
+	require.False(t, s.InSafeMode())
+	require.Nil(t, forward())
+	require.Nil(t, send())
+}
+
+// TestSwitchJammingMitigated tests that the switch refuses forwards from
+// incoming channels suspected of jamming.
+func TestSwitchJammingMitigated(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create alice server")
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create bob server")
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err, "unable to init switch")
+
+	var mitigated atomic.Bool
+	s.cfg.JammingMitigated = func(lnwire.ShortChannelID) bool {
+		return mitigated.Load()
+	}
+
+	require.NoError(t, s.Start(), "unable to start switch")
+	t.Cleanup(func() {
+		require.NoError(t, s.Stop())
+	})
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	// forward forwards a new HTLC from Alice to Bob, and returns the
+	// failure sent back to Alice, if any.
+	var htlcID uint64
+	forward := func() lnwire.FailureMessage {
+		t.Helper()
+
+		obfuscator := NewMockObfuscator()
+		packet := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     obfuscator,
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: [32]byte{byte(htlcID)},
+				Amount:      1,
+			},
+		}
+		htlcID++
+
+		require.NoError(t, s.ForwardPackets(nil, packet))
+
+		select {
+		case <-aliceChannelLink.packets:
+			return obfuscator.(*mockObfuscator).failure
+
+		case <-bobChannelLink.packets:
+			return nil
+
+		case <-time.After(time.Second):
+			t.Fatal("no timely reply from switch")
+		}
+
+		return nil
+	}
+
+	require.Nil(t, forward())
+
+	mitigated.Store(true)
+	failure := forward()
+	require.NotNil(t, failure)
+	require.Equal(t, lnwire.CodeTemporaryChannelFailure, failure.Code())
+
+	mitigated.Store(false)
+	require.Nil(t, forward())
+}
+
+// TestSwitchPruneNetworkResults tests that the switch prunes the network
+// results of resolved attempts once the retention passed, and those of
+// deleted payments right away, while keeping the results of attempts in
+// flight.
+func TestSwitchPruneNetworkResults(t *testing.T) {
+	t.Parallel()
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err)
+
+	testClock := clock.NewTestClock(time.Unix(1_700_000_000, 0))
+	s.cfg.Clock = testClock
+	s.networkResults.clock = testClock
+	s.cfg.NetworkResultRetention = time.Hour
+
+	// Attempt 0 is in flight, attempt 1 is resolved and attempt 2
+	// belongs to a deleted payment.
+	s.cfg.FetchHtlcAttemptIDs = func() (map[uint64]bool, error) {
+		return map[uint64]bool{0: true, 1: false}, nil
+	}
+
+	for pid := uint64(0); pid < 3; pid++ {
+		err := s.networkResults.storeResult(pid, &networkResult{
+			msg: &lnwire.UpdateFailHTLC{},
+		})
+		require.NoError(t, err)
+	}
+	testClock.SetTime(testClock.Now().Add(time.Minute))
+
+	assertResults := func(pids ...uint64) {
+		t.Helper()
+
+		results, err := s.QueryNetworkResults(&NetworkResultsQuery{})
+		require.NoError(t, err)
+		require.Len(t, results, len(pids))
+		for i, pid := range pids {
+			require.Equal(t, pid, results[i].AttemptID)
+		}
+	}
+
+	// Only the result of the deleted payment is pruned right away.
+	numPruned, err := s.PruneNetworkResults()
+	require.NoError(t, err)
+	require.Equal(t, 1, numPruned)
+	assertResults(0, 1)
+
+	// Once the retention passed, the result of the resolved attempt is
+	// pruned as well.
+	testClock.SetTime(testClock.Now().Add(time.Hour))
+	numPruned, err = s.PruneNetworkResults()
+	require.NoError(t, err)
+	require.Equal(t, 1, numPruned)
+	assertResults(0)
+}
@@ -0,0 +1,408 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/trampoline"
+)
+
+// TrampolineForward is a payment that we make to the next trampoline node to
+// relay a trampoline payment that we received.
+type TrampolineForward struct {
+	// PaymentHash is the payment hash of the relayed payment.
+	PaymentHash lntypes.Hash
+
+	// NextNode is the next trampoline node.
+	NextNode route.Vertex
+
+	// Amount is the amount that must be delivered to the next trampoline
+	// node.
+	Amount lnwire.MilliSatoshi
+
+	// OutgoingCltv is the absolute CLTV that the next trampoline node must
+	// receive.
+	OutgoingCltv uint32
+
+	// FeeLimit is the maximum routing fee that may be paid to reach the
+	// next trampoline node.
+	FeeLimit lnwire.MilliSatoshi
+
+	// CltvLimit is the maximum absolute CLTV of the htlcs that we send.
+	CltvLimit uint32
+
+	// Onion is the encoded trampoline onion for the next trampoline node,
+	// which must be delivered in its final hop payload.
+	Onion []byte
+}
+
+// TrampolineRelayConfig holds the dependencies of the TrampolineRelay.
+type TrampolineRelayConfig struct {
+	// Registry is the invoice registry that handles all exit hop htlcs
+	// that don't carry a trampoline onion.
+	Registry InvoiceDatabase
+
+	// NodeKey is used to peel our layer off the trampoline onions.
+	NodeKey sphinx.SingleKeyECDH
+
+	// Policy is the fee policy that we charge for relaying trampoline
+	// payments.
+	Policy trampoline.FeePolicy
+
+	// DB is the database that the relays that are in flight are persisted
+	// in, so that they can be resumed after a restart.
+	DB kvdb.Backend
+
+	// SendPayment makes the payment to the next trampoline node and blocks
+	// until it either succeeded or failed.
+	SendPayment func(fwd *TrampolineForward) (lntypes.Preimage, error)
+
+	// TrackPayment blocks until the payment with the given hash that was
+	// already made either succeeded or failed. It is used to resume a
+	// relay whose payment was made before a restart.
+	TrackPayment func(hash lntypes.Hash) (lntypes.Preimage, error)
+}
+
+// relayedHtlc is an incoming htlc whose trampoline payment we relay.
+type relayedHtlc struct {
+	// hodlChan is the channel that the resolution of the htlc is sent on.
+	// It is nil if the link of the htlc unsubscribed.
+	hodlChan chan<- interface{}
+
+	// resolution is the resolution of the htlc if the relay completed
+	// while nobody was subscribed.
+	resolution invoices.HtlcResolution
+}
+
+// TrampolineRelay relays trampoline payments. It sits in front of the invoice
+// registry and takes over the exit hop htlcs that carry a trampoline onion in
+// their final hop payload. If we're the final trampoline node the htlc is
+// handed to the registry, otherwise a payment is made to the next trampoline
+// node and the incoming htlc is resolved with its outcome, the same way that
+// hodl invoices are resolved. Relays that are in flight are persisted and
+// resumed on start up.
+type TrampolineRelay struct {
+	InvoiceDatabase
+
+	started sync.Once
+	stopped sync.Once
+
+	cfg *TrampolineRelayConfig
+
+	store *trampolineRelayStore
+
+	// relays are the htlcs whose payments are relayed by their circuit
+	// key.
+	relays map[models.CircuitKey]*relayedHtlc
+
+	mu   sync.Mutex
+	quit chan struct{}
+}
+
+// A compile time check to ensure TrampolineRelay implements the
+// InvoiceDatabase interface.
+var _ InvoiceDatabase = (*TrampolineRelay)(nil)
+
+// NewTrampolineRelay creates a new trampoline relay.
+func NewTrampolineRelay(cfg *TrampolineRelayConfig) *TrampolineRelay {
+	return &TrampolineRelay{
+		InvoiceDatabase: cfg.Registry,
+		cfg:             cfg,
+		store:           newTrampolineRelayStore(cfg.DB),
+		relays:          make(map[models.CircuitKey]*relayedHtlc),
+		quit:            make(chan struct{}),
+	}
+}
+
+// Start resumes the relays that were in flight when we shut down. It must be
+// called before the links replay their htlcs.
+func (r *TrampolineRelay) Start() error {
+	var err error
+	r.started.Do(func() {
+		log.Info("Trampoline relay starting...")
+		err = r.resumeRelays()
+	})
+
+	return err
+}
+
+// resumeRelays loads the persisted relays and resumes them.
+func (r *TrampolineRelay) resumeRelays() error {
+	records, err := r.store.fetchRelays()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for circuitKey, rec := range records {
+		log.Infof("Resuming trampoline relay of payment %v of htlc %v",
+			rec.fwd.PaymentHash, circuitKey)
+
+		// The link of the htlc subscribes again when it replays the
+		// htlc.
+		r.relays[circuitKey] = &relayedHtlc{}
+		go r.relay(circuitKey, rec.acceptHeight, rec.fwd)
+	}
+
+	return nil
+}
+
+// Stop stops the relay. Relays that complete afterwards aren't resolved.
+func (r *TrampolineRelay) Stop() error {
+	r.stopped.Do(func() {
+		log.Info("Trampoline relay shutting down...")
+		close(r.quit)
+	})
+
+	return nil
+}
+
+// trampolineFinalPayload is the payload of an htlc for which we're the final
+// trampoline node. The payment address and total amount of the payment are
+// taken from the trampoline payload, as the outer onion was created by the
+// last trampoline node.
+type trampolineFinalPayload struct {
+	invoices.Payload
+
+	mpp *record.MPP
+}
+
+// MultiPath returns the MPP record of the trampoline payload.
+func (p *trampolineFinalPayload) MultiPath() *record.MPP {
+	return p.mpp
+}
+
+// NotifyExitHopHtlc relays the trampoline payment of the htlc if its payload
+// carries a trampoline onion and passes the htlc on to the invoice registry
+// otherwise.
+//
+// NOTE: This is part of the InvoiceDatabase interface.
+func (r *TrampolineRelay) NotifyExitHopHtlc(payHash lntypes.Hash,
+	paidAmount lnwire.MilliSatoshi, expiry uint32, currentHeight int32,
+	circuitKey models.CircuitKey, hodlChan chan<- interface{},
+	payload invoices.Payload) (invoices.HtlcResolution, error) {
+
+	onion, ok := payload.CustomRecords()[uint64(record.TrampolineOnionType)]
+	if !ok {
+		return r.cfg.Registry.NotifyExitHopHtlc(
+			payHash, paidAmount, expiry, currentHeight, circuitKey,
+			hodlChan, payload,
+		)
+	}
+
+	fail := func(result invoices.FailResolutionResult) (
+		invoices.HtlcResolution, error) {
+
+		return invoices.NewFailResolution(
+			circuitKey, currentHeight, result,
+		), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// If the htlc is replayed, because its link restarted, we resubscribe
+	// to the relay that is already running or return its resolution.
+	if htlc, ok := r.relays[circuitKey]; ok {
+		if htlc.resolution != nil {
+			if err := r.store.deleteRelay(circuitKey); err != nil {
+				return nil, err
+			}
+			delete(r.relays, circuitKey)
+
+			return htlc.resolution, nil
+		}
+
+		htlc.hodlChan = hodlChan
+
+		return nil, nil
+	}
+
+	pkt, err := trampoline.DecodeOnionPacket(onion)
+	if err != nil {
+		log.Debugf("Invalid trampoline onion for htlc %v: %v",
+			circuitKey, err)
+
+		return fail(invoices.ResultTrampolineError)
+	}
+
+	processed, err := trampoline.ProcessOnionPacket(
+		r.cfg.NodeKey, pkt, payHash[:],
+	)
+	if err != nil {
+		log.Debugf("Unable to process trampoline onion for htlc %v: %v",
+			circuitKey, err)
+
+		return fail(invoices.ResultTrampolineError)
+	}
+
+	tPayload, err := trampoline.DecodePayload(
+		processed.Payload, processed.IsFinal(),
+	)
+	if err != nil {
+		log.Debugf("Invalid trampoline payload for htlc %v: %v",
+			circuitKey, err)
+
+		return fail(invoices.ResultTrampolineError)
+	}
+
+	// If we're the final trampoline node, the payment is for us and the
+	// registry takes over.
+	if processed.IsFinal() {
+		switch {
+		case paidAmount < tPayload.AmtToForward:
+			return fail(invoices.ResultAmountTooLow)
+
+		case expiry < tPayload.OutgoingCltv:
+			return fail(invoices.ResultExpiryTooSoon)
+		}
+
+		finalPayload := payload
+		if tPayload.MPP != nil {
+			finalPayload = &trampolineFinalPayload{
+				Payload: payload,
+				mpp:     tPayload.MPP,
+			}
+		}
+
+		return r.cfg.Registry.NotifyExitHopHtlc(
+			payHash, paidAmount, expiry, currentHeight, circuitKey,
+			hodlChan, finalPayload,
+		)
+	}
+
+	if err := r.cfg.Policy.CheckForward(
+		paidAmount, expiry, tPayload,
+	); err != nil {
+		log.Debugf("Rejecting trampoline relay of htlc %v: %v",
+			circuitKey, err)
+
+		return fail(invoices.ResultTrampolineError)
+	}
+
+	var nextOnion bytes.Buffer
+	if err := processed.NextPacket.Encode(&nextOnion); err != nil {
+		return nil, err
+	}
+
+	fee := r.cfg.Policy.Fee(tPayload.AmtToForward)
+	fwd := &TrampolineForward{
+		PaymentHash:  payHash,
+		NextNode:     route.NewVertex(tPayload.OutgoingNodeID),
+		Amount:       tPayload.AmtToForward,
+		OutgoingCltv: tPayload.OutgoingCltv,
+		FeeLimit:     paidAmount - tPayload.AmtToForward - fee,
+		CltvLimit:    expiry - r.cfg.Policy.CltvDelta,
+		Onion:        nextOnion.Bytes(),
+	}
+
+	log.Infof("Relaying trampoline payment %v of htlc %v to %v",
+		payHash, circuitKey, fwd.NextNode)
+
+	// The relay is persisted before the payment is made, so that it is
+	// resumed if we restart while it is in flight.
+	err = r.store.addRelay(circuitKey, &relayRecord{
+		acceptHeight: currentHeight,
+		fwd:          fwd,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.relays[circuitKey] = &relayedHtlc{
+		hodlChan: hodlChan,
+	}
+	go r.relay(circuitKey, currentHeight, fwd)
+
+	return nil, nil
+}
+
+// relay makes the payment to the next trampoline node and resolves the
+// incoming htlc with its outcome.
+func (r *TrampolineRelay) relay(circuitKey models.CircuitKey,
+	acceptHeight int32, fwd *TrampolineForward) {
+
+	var resolution invoices.HtlcResolution
+	preimage, err := r.cfg.SendPayment(fwd)
+
+	// If the payment was already made, because the relay is resumed or the
+	// htlc was replayed after its resolution was delivered, we settle the
+	// htlc with the outcome of that payment rather than failing it.
+	if errors.Is(err, channeldb.ErrAlreadyPaid) ||
+		errors.Is(err, channeldb.ErrPaymentInFlight) ||
+		errors.Is(err, channeldb.ErrPaymentExists) {
+
+		log.Infof("Tracking existing trampoline payment %v: %v",
+			fwd.PaymentHash, err)
+
+		preimage, err = r.cfg.TrackPayment(fwd.PaymentHash)
+	}
+	if err != nil {
+		log.Infof("Trampoline relay of payment %v failed: %v",
+			fwd.PaymentHash, err)
+
+		resolution = invoices.NewFailResolution(
+			circuitKey, acceptHeight,
+			invoices.ResultTrampolineError,
+		)
+	} else {
+		resolution = invoices.NewSettleResolution(
+			preimage, circuitKey, acceptHeight,
+			invoices.ResultSettled,
+		)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	htlc, ok := r.relays[circuitKey]
+	if !ok {
+		return
+	}
+
+	// If the link of the htlc isn't subscribed, we keep the resolution
+	// until the htlc is replayed.
+	if htlc.hodlChan == nil {
+		htlc.resolution = resolution
+		return
+	}
+
+	if err := r.store.deleteRelay(circuitKey); err != nil {
+		log.Errorf("Unable to delete trampoline relay of htlc %v: %v",
+			circuitKey, err)
+	}
+	delete(r.relays, circuitKey)
+
+	select {
+	case htlc.hodlChan <- resolution:
+	case <-r.quit:
+	}
+}
+
+// HodlUnsubscribeAll unsubscribes the subscriber from the resolutions of all
+// htlcs.
+//
+// NOTE: This is part of the InvoiceDatabase interface.
+func (r *TrampolineRelay) HodlUnsubscribeAll(subscriber chan<- interface{}) {
+	r.mu.Lock()
+	for _, htlc := range r.relays {
+		if htlc.hodlChan == subscriber {
+			htlc.hodlChan = nil
+		}
+	}
+	r.mu.Unlock()
+
+	r.cfg.Registry.HodlUnsubscribeAll(subscriber)
+}
@@ -0,0 +1,147 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// trampolineRelayBucketKey is used for the root level bucket that
+	// stores the trampoline relays that are in flight, keyed by the
+	// circuit key of their incoming htlc.
+	trampolineRelayBucketKey = []byte("trampoline-relay-bucket")
+)
+
+// relayRecord is the persisted state of a trampoline relay.
+type relayRecord struct {
+	// acceptHeight is the height at which the incoming htlc was accepted.
+	acceptHeight int32
+
+	// fwd is the payment to the next trampoline node.
+	fwd *TrampolineForward
+}
+
+// serializeRelayRecord serializes the relayRecord.
+func serializeRelayRecord(w io.Writer, r *relayRecord) error {
+	fwd := r.fwd
+
+	return channeldb.WriteElements(w,
+		r.acceptHeight, [32]byte(fwd.PaymentHash), fwd.NextNode[:],
+		fwd.Amount, fwd.OutgoingCltv, fwd.FeeLimit, fwd.CltvLimit,
+		fwd.Onion,
+	)
+}
+
+// deserializeRelayRecord deserializes the relayRecord.
+func deserializeRelayRecord(r io.Reader) (*relayRecord, error) {
+	var (
+		rec      = &relayRecord{fwd: &TrampolineForward{}}
+		hash     [32]byte
+		nextNode []byte
+	)
+
+	err := channeldb.ReadElements(r,
+		&rec.acceptHeight, &hash, &nextNode, &rec.fwd.Amount,
+		&rec.fwd.OutgoingCltv, &rec.fwd.FeeLimit, &rec.fwd.CltvLimit,
+		&rec.fwd.Onion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.fwd.PaymentHash = hash
+	rec.fwd.NextNode, err = route.NewVertexFromBytes(nextNode)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+// trampolineRelayStore persists the trampoline relays that are in flight, so
+// that they can be resumed after a restart.
+type trampolineRelayStore struct {
+	backend kvdb.Backend
+}
+
+// newTrampolineRelayStore creates a new store on top of the given database.
+func newTrampolineRelayStore(db kvdb.Backend) *trampolineRelayStore {
+	return &trampolineRelayStore{
+		backend: db,
+	}
+}
+
+// addRelay stores the relay of the incoming htlc with the given circuit key.
+func (s *trampolineRelayStore) addRelay(key models.CircuitKey,
+	rec *relayRecord) error {
+
+	var b bytes.Buffer
+	if err := serializeRelayRecord(&b, rec); err != nil {
+		return err
+	}
+
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		relays, err := tx.CreateTopLevelBucket(
+			trampolineRelayBucketKey,
+		)
+		if err != nil {
+			return err
+		}
+
+		return relays.Put(key.Bytes(), b.Bytes())
+	}, func() {})
+}
+
+// deleteRelay removes the relay of the incoming htlc with the given circuit
+// key. Removing a relay that isn't stored is not an error.
+func (s *trampolineRelayStore) deleteRelay(key models.CircuitKey) error {
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		relays := tx.ReadWriteBucket(trampolineRelayBucketKey)
+		if relays == nil {
+			return nil
+		}
+
+		return relays.Delete(key.Bytes())
+	}, func() {})
+}
+
+// fetchRelays returns all stored relays by the circuit keys of their incoming
+// htlcs.
+func (s *trampolineRelayStore) fetchRelays() (
+	map[models.CircuitKey]*relayRecord, error) {
+
+	var relays map[models.CircuitKey]*relayRecord
+	err := kvdb.View(s.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(trampolineRelayBucketKey)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var key models.CircuitKey
+			if err := key.SetBytes(k); err != nil {
+				return err
+			}
+
+			rec, err := deserializeRelayRecord(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			relays[key] = rec
+
+			return nil
+		})
+	}, func() {
+		relays = make(map[models.CircuitKey]*relayRecord)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return relays, nil
+}
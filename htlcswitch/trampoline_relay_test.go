@@ -0,0 +1,448 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/trampoline"
+	"github.com/stretchr/testify/require"
+)
+
+// relayTestPayload is an exit hop payload that only carries custom records.
+type relayTestPayload struct {
+	invoices.Payload
+
+	customRecords record.CustomSet
+}
+
+// MultiPath returns no MPP record.
+func (p *relayTestPayload) MultiPath() *record.MPP {
+	return nil
+}
+
+// CustomRecords returns the custom records of the payload.
+func (p *relayTestPayload) CustomRecords() record.CustomSet {
+	return p.customRecords
+}
+
+// relayTestRegistry records the htlcs that the relay passes on to the
+// registry.
+type relayTestRegistry struct {
+	InvoiceDatabase
+
+	payloads []invoices.Payload
+}
+
+// NotifyExitHopHtlc records the payload of the htlc and holds it.
+func (r *relayTestRegistry) NotifyExitHopHtlc(_ lntypes.Hash,
+	_ lnwire.MilliSatoshi, _ uint32, _ int32, _ models.CircuitKey,
+	_ chan<- interface{}, payload invoices.Payload) (
+	invoices.HtlcResolution, error) {
+
+	r.payloads = append(r.payloads, payload)
+
+	return nil, nil
+}
+
+// HodlUnsubscribeAll does nothing.
+func (r *relayTestRegistry) HodlUnsubscribeAll(chan<- interface{}) {}
+
+// relayTestContext holds the relay under test and the keys of the nodes of
+// the trampoline route.
+type relayTestContext struct {
+	db       kvdb.Backend
+	relay    *TrampolineRelay
+	registry *relayTestRegistry
+	forwards chan *TrampolineForward
+	result   chan error
+
+	ourKey  *btcec.PrivateKey
+	nextKey *btcec.PrivateKey
+	hash    lntypes.Hash
+}
+
+// newRelayTestContext creates a relay with a fee policy of 1000 msat base fee,
+// 1000 ppm and a CLTV delta of 100. Payments that were already made are
+// tracked to the preimage 7, 8, 9.
+func newRelayTestContext(t *testing.T) *relayTestContext {
+	ourKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	nextKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	db, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	c := &relayTestContext{
+		db:       db,
+		registry: &relayTestRegistry{},
+		forwards: make(chan *TrampolineForward, 1),
+		result:   make(chan error, 1),
+		ourKey:   ourKey,
+		nextKey:  nextKey,
+		hash:     lntypes.Hash{1, 2, 3},
+	}
+
+	c.relay = NewTrampolineRelay(&TrampolineRelayConfig{
+		Registry: c.registry,
+		NodeKey:  &sphinx.PrivKeyECDH{PrivKey: ourKey},
+		DB:       db,
+		Policy: trampoline.FeePolicy{
+			BaseFee:   1000,
+			FeeRate:   1000,
+			CltvDelta: 100,
+		},
+		SendPayment: func(fwd *TrampolineForward) (lntypes.Preimage,
+			error) {
+
+			c.forwards <- fwd
+			if err := <-c.result; err != nil {
+				return lntypes.Preimage{}, err
+			}
+
+			return lntypes.Preimage{4, 5, 6}, nil
+		},
+		TrackPayment: func(lntypes.Hash) (lntypes.Preimage, error) {
+			return lntypes.Preimage{7, 8, 9}, nil
+		},
+	})
+	require.NoError(t, c.relay.Start())
+	t.Cleanup(func() {
+		require.NoError(t, c.relay.Stop())
+	})
+
+	return c
+}
+
+// payload returns an exit hop payload with a trampoline onion that relays
+// 100_000 msat to the next node with a CLTV of 1000. If final is set, we're
+// the final trampoline node instead.
+func (c *relayTestContext) payload(t *testing.T,
+	final bool) invoices.Payload {
+
+	ourPayload := &trampoline.Payload{
+		AmtToForward:   100_000,
+		OutgoingCltv:   1000,
+		OutgoingNodeID: c.nextKey.PubKey(),
+	}
+	if final {
+		ourPayload.OutgoingNodeID = nil
+		ourPayload.MPP = record.NewMPP(100_000, [32]byte{9})
+	}
+
+	encoded, err := ourPayload.Encode()
+	require.NoError(t, err)
+
+	hops := []trampoline.OnionHop{
+		{NodeID: c.ourKey.PubKey(), Payload: encoded},
+	}
+	if !final {
+		nextPayload, err := (&trampoline.Payload{
+			AmtToForward: 100_000,
+			OutgoingCltv: 1000,
+		}).Encode()
+		require.NoError(t, err)
+
+		hops = append(hops, trampoline.OnionHop{
+			NodeID:  c.nextKey.PubKey(),
+			Payload: nextPayload,
+		})
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	onion, err := trampoline.NewOnionPacket(
+		hops, sessionKey, c.hash[:], trampoline.DefaultRoutingInfoSize,
+	)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, onion.Encode(&b))
+
+	return &relayTestPayload{
+		customRecords: record.CustomSet{
+			uint64(record.TrampolineOnionType): b.Bytes(),
+		},
+	}
+}
+
+// TestTrampolineRelay asserts that a trampoline payment is relayed to the next
+// trampoline node and that the incoming htlc is settled with the preimage.
+func TestTrampolineRelay(t *testing.T) {
+	t.Parallel()
+
+	c := newRelayTestContext(t)
+
+	key := models.CircuitKey{HtlcID: 1}
+	hodlChan := make(chan interface{}, 1)
+	resolution, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 102_000, 1200, 500, key, hodlChan, c.payload(t, false),
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	var fwd *TrampolineForward
+	select {
+	case fwd = <-c.forwards:
+	case <-time.After(time.Second):
+		t.Fatal("payment not relayed")
+	}
+
+	require.Equal(t, c.hash, fwd.PaymentHash)
+	require.Equal(t, route.NewVertex(c.nextKey.PubKey()), fwd.NextNode)
+	require.EqualValues(t, 100_000, fwd.Amount)
+	require.EqualValues(t, 1000, fwd.OutgoingCltv)
+	require.EqualValues(t, 1100, fwd.CltvLimit)
+
+	// Our fee is 1000 msat + 100 msat, which leaves 900 msat for the
+	// route to the next trampoline node.
+	require.EqualValues(t, 900, fwd.FeeLimit)
+
+	// The next trampoline node is the final one and finds its payload in
+	// the onion we pass on.
+	nextOnion, err := trampoline.DecodeOnionPacket(fwd.Onion)
+	require.NoError(t, err)
+	processed, err := trampoline.ProcessOnionPacket(
+		&sphinx.PrivKeyECDH{PrivKey: c.nextKey}, nextOnion, c.hash[:],
+	)
+	require.NoError(t, err)
+	require.True(t, processed.IsFinal())
+
+	c.result <- nil
+
+	select {
+	case res := <-hodlChan:
+		settle, ok := res.(*invoices.HtlcSettleResolution)
+		require.True(t, ok)
+		require.Equal(t, lntypes.Preimage{4, 5, 6}, settle.Preimage)
+		require.Equal(t, key, settle.CircuitKey())
+
+	case <-time.After(time.Second):
+		t.Fatal("htlc not resolved")
+	}
+
+	require.Empty(t, c.registry.payloads)
+}
+
+// TestTrampolineRelayReplay asserts that a relay that completes while the
+// link of the htlc is unsubscribed is resolved once the htlc is replayed.
+func TestTrampolineRelayReplay(t *testing.T) {
+	t.Parallel()
+
+	c := newRelayTestContext(t)
+
+	key := models.CircuitKey{HtlcID: 1}
+	payload := c.payload(t, false)
+	hodlChan := make(chan interface{}, 1)
+	_, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 102_000, 1200, 500, key, hodlChan, payload,
+	)
+	require.NoError(t, err)
+
+	<-c.forwards
+	c.relay.HodlUnsubscribeAll(hodlChan)
+	c.result <- errors.New("no route")
+
+	// Wait for the relay to complete.
+	require.Eventually(t, func() bool {
+		c.relay.mu.Lock()
+		defer c.relay.mu.Unlock()
+
+		return c.relay.relays[key].resolution != nil
+	}, time.Second, 10*time.Millisecond)
+
+	resolution, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 102_000, 1200, 500, key, make(chan interface{}),
+		payload,
+	)
+	require.NoError(t, err)
+
+	fail, ok := resolution.(*invoices.HtlcFailResolution)
+	require.True(t, ok)
+	require.Equal(t, invoices.ResultTrampolineError, fail.Outcome)
+	require.Empty(t, c.relay.relays)
+}
+
+// TestTrampolineRelayDuplicate asserts that the htlc is settled with the
+// preimage of the payment that was already made if the payment to the next
+// trampoline node is rejected as a duplicate.
+func TestTrampolineRelayDuplicate(t *testing.T) {
+	t.Parallel()
+
+	c := newRelayTestContext(t)
+
+	key := models.CircuitKey{HtlcID: 1}
+	hodlChan := make(chan interface{}, 1)
+	_, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 102_000, 1200, 500, key, hodlChan, c.payload(t, false),
+	)
+	require.NoError(t, err)
+
+	<-c.forwards
+	c.result <- channeldb.ErrAlreadyPaid
+
+	select {
+	case res := <-hodlChan:
+		settle, ok := res.(*invoices.HtlcSettleResolution)
+		require.True(t, ok)
+		require.Equal(t, lntypes.Preimage{7, 8, 9}, settle.Preimage)
+
+	case <-time.After(time.Second):
+		t.Fatal("htlc not resolved")
+	}
+
+	relays, err := c.relay.store.fetchRelays()
+	require.NoError(t, err)
+	require.Empty(t, relays)
+}
+
+// TestTrampolineRelayRestart asserts that a relay that is in flight when we
+// shut down is resumed on start up and resolves the replayed htlc.
+func TestTrampolineRelayRestart(t *testing.T) {
+	t.Parallel()
+
+	c := newRelayTestContext(t)
+
+	key := models.CircuitKey{HtlcID: 1}
+	payload := c.payload(t, false)
+	_, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 102_000, 1200, 500, key, make(chan interface{}, 1),
+		payload,
+	)
+	require.NoError(t, err)
+
+	fwd := <-c.forwards
+
+	// Shut down while the payment is in flight.
+	require.NoError(t, c.relay.Stop())
+	t.Cleanup(func() { c.result <- errors.New("shutdown") })
+
+	// The restarted relay makes the payment again, which the control
+	// tower rejects as it is still in flight, and tracks it instead.
+	resumed := make(chan *TrampolineForward, 1)
+	relay := NewTrampolineRelay(&TrampolineRelayConfig{
+		Registry: c.registry,
+		NodeKey:  &sphinx.PrivKeyECDH{PrivKey: c.ourKey},
+		Policy:   c.relay.cfg.Policy,
+		DB:       c.db,
+		SendPayment: func(fwd *TrampolineForward) (lntypes.Preimage,
+			error) {
+
+			resumed <- fwd
+			return lntypes.Preimage{}, channeldb.ErrPaymentInFlight
+		},
+		TrackPayment: func(lntypes.Hash) (lntypes.Preimage, error) {
+			return lntypes.Preimage{4, 5, 6}, nil
+		},
+	})
+	require.NoError(t, relay.Start())
+	t.Cleanup(func() {
+		require.NoError(t, relay.Stop())
+	})
+
+	select {
+	case resumedFwd := <-resumed:
+		require.Equal(t, fwd, resumedFwd)
+
+	case <-time.After(time.Second):
+		t.Fatal("relay not resumed")
+	}
+
+	// Wait for the relay to complete, after which the replayed htlc is
+	// settled.
+	require.Eventually(t, func() bool {
+		relay.mu.Lock()
+		defer relay.mu.Unlock()
+
+		return relay.relays[key].resolution != nil
+	}, time.Second, 10*time.Millisecond)
+
+	resolution, err := relay.NotifyExitHopHtlc(
+		c.hash, 102_000, 1200, 500, key, make(chan interface{}),
+		payload,
+	)
+	require.NoError(t, err)
+
+	settle, ok := resolution.(*invoices.HtlcSettleResolution)
+	require.True(t, ok)
+	require.Equal(t, lntypes.Preimage{4, 5, 6}, settle.Preimage)
+	require.EqualValues(t, 500, settle.AcceptHeight)
+
+	relays, err := relay.store.fetchRelays()
+	require.NoError(t, err)
+	require.Empty(t, relays)
+}
+
+// TestTrampolineRelayInsufficientFee asserts that a trampoline payment that
+// doesn't pay our fee is failed right away.
+func TestTrampolineRelayInsufficientFee(t *testing.T) {
+	t.Parallel()
+
+	c := newRelayTestContext(t)
+
+	resolution, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 101_000, 1200, 500, models.CircuitKey{},
+		make(chan interface{}), c.payload(t, false),
+	)
+	require.NoError(t, err)
+
+	fail, ok := resolution.(*invoices.HtlcFailResolution)
+	require.True(t, ok)
+	require.Equal(t, invoices.ResultTrampolineError, fail.Outcome)
+}
+
+// TestTrampolineRelayPassThrough asserts that htlcs without a trampoline onion
+// and htlcs for which we're the final trampoline node are handed to the
+// registry, the latter with the MPP record of the trampoline payload.
+func TestTrampolineRelayPassThrough(t *testing.T) {
+	t.Parallel()
+
+	c := newRelayTestContext(t)
+
+	regular := &relayTestPayload{}
+	_, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 100_000, 1200, 500, models.CircuitKey{HtlcID: 1},
+		make(chan interface{}), regular,
+	)
+	require.NoError(t, err)
+
+	_, err = c.relay.NotifyExitHopHtlc(
+		c.hash, 100_000, 1200, 500, models.CircuitKey{HtlcID: 2},
+		make(chan interface{}), c.payload(t, true),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, c.registry.payloads, 2)
+	require.Equal(t, regular, c.registry.payloads[0])
+
+	mpp := c.registry.payloads[1].MultiPath()
+	require.NotNil(t, mpp)
+	require.Equal(t, [32]byte{9}, mpp.PaymentAddr())
+	require.EqualValues(t, 100_000, mpp.TotalMsat())
+
+	// The final trampoline payload must not ask for more than we
+	// received.
+	resolution, err := c.relay.NotifyExitHopHtlc(
+		c.hash, 99_000, 1200, 500, models.CircuitKey{HtlcID: 3},
+		make(chan interface{}), c.payload(t, true),
+	)
+	require.NoError(t, err)
+
+	fail, ok := resolution.(*invoices.HtlcFailResolution)
+	require.True(t, ok)
+	require.Equal(t, invoices.ResultAmountTooLow, fail.Outcome)
+}
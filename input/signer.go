@@ -35,6 +35,21 @@ type Signer interface {
 		error)
 }
 
+// BatchSigner is an optional interface of a Signer that can generate multiple
+// raw signatures in a single operation. Signers that need a round trip for
+// every operation, like remote signers, implement it to sign all HTLCs of a
+// commitment at once.
+type BatchSigner interface {
+	// SignOutputRawBatch generates a signature for each of the passed
+	// transactions according to the SignDescriptor at the same index. The
+	// batch is signed as a whole: if any of the signatures can't be
+	// generated, an error is returned and no signatures at all.
+	//
+	// NOTE: The resulting signatures should be void of a sighash byte.
+	SignOutputRawBatch(txs []*wire.MsgTx,
+		signDescs []*SignDescriptor) ([]Signature, error)
+}
+
 // Script represents any script inputs required to redeem a previous
 // output. This struct is used rather than just a witness, or scripSig in order
 // to accommodate nested p2sh which utilizes both types of input scripts.
@@ -0,0 +1,601 @@
+package invoices
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// maxWebhookResponseSize is the largest webhook response we'll read.
+	maxWebhookResponseSize = 64 * 1024
+)
+
+// HoldAction is the action the hold invoice rules take on an accepted hold
+// invoice.
+type HoldAction uint8
+
+const (
+	// HoldActionHold leaves the invoice in the accepted state.
+	HoldActionHold HoldAction = iota
+
+	// HoldActionSettle settles the invoice.
+	HoldActionSettle
+
+	// HoldActionCancel cancels the invoice.
+	HoldActionCancel
+)
+
+// String returns a human-readable representation of the action.
+func (a HoldAction) String() string {
+	switch a {
+	case HoldActionHold:
+		return "hold"
+
+	case HoldActionSettle:
+		return "settle"
+
+	case HoldActionCancel:
+		return "cancel"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", a)
+	}
+}
+
+// HoldDecision is the decision a rule made for an accepted hold invoice.
+type HoldDecision struct {
+	// Action is the action to take.
+	Action HoldAction
+
+	// Preimage is the preimage to settle the invoice with. It must be set
+	// if the action is HoldActionSettle.
+	Preimage *lntypes.Preimage
+}
+
+// holdDecision is the decision to leave an invoice in the accepted state.
+var holdDecision = &HoldDecision{Action: HoldActionHold}
+
+// HoldRule decides what happens to an accepted hold invoice.
+type HoldRule interface {
+	// Decide returns the decision for the accepted hold invoice with the
+	// given payment hash.
+	Decide(ctx context.Context, hash lntypes.Hash,
+		invoice *Invoice) (*HoldDecision, error)
+
+	// Name returns the name of the rule, used for logging.
+	Name() string
+}
+
+// MinAmountRule cancels hold invoices that were paid less than a minimum
+// amount.
+type MinAmountRule struct {
+	// MinAmt is the minimum amount that must be paid.
+	MinAmt lnwire.MilliSatoshi
+}
+
+// Decide cancels the invoice if it was paid less than the minimum amount.
+//
+// NOTE: This is part of the HoldRule interface.
+func (r *MinAmountRule) Decide(_ context.Context, _ lntypes.Hash,
+	invoice *Invoice) (*HoldDecision, error) {
+
+	if invoice.AmtPaid < r.MinAmt {
+		return &HoldDecision{Action: HoldActionCancel}, nil
+	}
+
+	return holdDecision, nil
+}
+
+// Name returns the name of the rule.
+//
+// NOTE: This is part of the HoldRule interface.
+func (r *MinAmountRule) Name() string {
+	return "min-amount"
+}
+
+// AutoSettleRule settles hold invoices that were paid at most a maximum
+// amount, if their preimage is known.
+type AutoSettleRule struct {
+	// MaxAmt is the maximum amount that is settled automatically.
+	MaxAmt lnwire.MilliSatoshi
+
+	// LookupPreimage returns the preimage of the given payment hash, or
+	// an error if it isn't known.
+	LookupPreimage func(hash lntypes.Hash) (lntypes.Preimage, error)
+}
+
+// Decide settles the invoice if it was paid at most the maximum amount and its
+// preimage is known.
+//
+// NOTE: This is part of the HoldRule interface.
+func (r *AutoSettleRule) Decide(_ context.Context, hash lntypes.Hash,
+	invoice *Invoice) (*HoldDecision, error) {
+
+	if invoice.AmtPaid > r.MaxAmt {
+		return holdDecision, nil
+	}
+
+	preimage, err := r.LookupPreimage(hash)
+	if err != nil {
+		log.Debugf("Not auto-settling hold invoice %v: %v", hash, err)
+
+		return holdDecision, nil
+	}
+
+	return &HoldDecision{
+		Action:   HoldActionSettle,
+		Preimage: &preimage,
+	}, nil
+}
+
+// Name returns the name of the rule.
+//
+// NOTE: This is part of the HoldRule interface.
+func (r *AutoSettleRule) Name() string {
+	return "auto-settle"
+}
+
+// HoldWebhookRequest is the JSON body posted to the approval webhook for an
+// accepted hold invoice.
+type HoldWebhookRequest struct {
+	// PaymentHash is the hex encoded payment hash of the invoice.
+	PaymentHash string `json:"payment_hash"`
+
+	// ValueMsat is the amount the invoice was created for.
+	ValueMsat uint64 `json:"value_msat"`
+
+	// AmtPaidMsat is the amount that was paid to the invoice.
+	AmtPaidMsat uint64 `json:"amt_paid_msat"`
+
+	// Memo is the memo of the invoice.
+	Memo string `json:"memo"`
+
+	// AddIndex is the add index of the invoice.
+	AddIndex uint64 `json:"add_index"`
+}
+
+// HoldWebhookResponse is the JSON body the approval webhook responds with.
+type HoldWebhookResponse struct {
+	// Action is one of "settle", "cancel" or "hold".
+	Action string `json:"action"`
+
+	// Preimage is the hex encoded preimage to settle the invoice with.
+	Preimage string `json:"preimage,omitempty"`
+}
+
+// WebhookRule posts accepted hold invoices to a webhook that decides whether
+// they are settled, canceled or held.
+type WebhookRule struct {
+	// URL is the URL of the webhook.
+	URL string
+
+	// Timeout is the time the webhook may take to respond.
+	Timeout time.Duration
+}
+
+// Decide posts the invoice to the webhook and returns its decision.
+//
+// NOTE: This is part of the HoldRule interface.
+func (r *WebhookRule) Decide(ctx context.Context, hash lntypes.Hash,
+	invoice *Invoice) (*HoldDecision, error) {
+
+	body, err := json.Marshal(&HoldWebhookRequest{
+		PaymentHash: hash.String(),
+		ValueMsat:   uint64(invoice.Terms.Value),
+		AmtPaidMsat: uint64(invoice.AmtPaid),
+		Memo:        string(invoice.Memo),
+		AddIndex:    invoice.AddIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, r.URL, bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("webhook returned status %v",
+			resp.Status)
+	}
+
+	var webhookResp HoldWebhookResponse
+	err = json.NewDecoder(
+		io.LimitReader(resp.Body, maxWebhookResponseSize),
+	).Decode(&webhookResp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode webhook response: %w",
+			err)
+	}
+
+	switch webhookResp.Action {
+	case "hold", "":
+		return holdDecision, nil
+
+	case "cancel":
+		return &HoldDecision{Action: HoldActionCancel}, nil
+
+	case "settle":
+		preimageBytes, err := hex.DecodeString(webhookResp.Preimage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preimage: %w", err)
+		}
+		preimage, err := lntypes.MakePreimage(preimageBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preimage: %w", err)
+		}
+
+		return &HoldDecision{
+			Action:   HoldActionSettle,
+			Preimage: &preimage,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown webhook action %q",
+			webhookResp.Action)
+	}
+}
+
+// Name returns the name of the rule.
+//
+// NOTE: This is part of the HoldRule interface.
+func (r *WebhookRule) Name() string {
+	return "webhook"
+}
+
+// HoldInvoiceRegistry is the subset of the invoice registry the hold invoice
+// rules need.
+type HoldInvoiceRegistry interface {
+	// SubscribeNotifications subscribes to newly added and settled
+	// invoices.
+	SubscribeNotifications(ctx context.Context, addIndex,
+		settleIndex uint64) (*InvoiceSubscription, error)
+
+	// SubscribeSingleInvoice subscribes to the state updates of a single
+	// invoice.
+	SubscribeSingleInvoice(ctx context.Context,
+		hash lntypes.Hash) (*SingleInvoiceSubscription, error)
+
+	// SettleHodlInvoice settles an accepted hold invoice.
+	SettleHodlInvoice(ctx context.Context, preimage lntypes.Preimage) error
+
+	// CancelInvoice cancels an invoice.
+	CancelInvoice(ctx context.Context, payHash lntypes.Hash) error
+}
+
+// HoldInvoiceRulesConfig contains the dependencies of the hold invoice rules.
+type HoldInvoiceRulesConfig struct {
+	// Registry is the invoice registry the hold invoices are watched,
+	// settled and canceled with.
+	Registry HoldInvoiceRegistry
+
+	// InvoiceDB is the invoice database the pending hold invoices are
+	// fetched from on start up.
+	InvoiceDB InvoiceDB
+
+	// ChainParams are the parameters of the chain, used to decode the
+	// payment requests of new invoices.
+	ChainParams *chaincfg.Params
+
+	// Rules are the rules that are evaluated in order for every accepted
+	// hold invoice. The first rule that doesn't hold the invoice decides.
+	Rules []HoldRule
+
+	// AcceptTimeout is the time after which an accepted hold invoice is
+	// canceled if no rule settled or canceled it, and it wasn't resolved
+	// otherwise. Zero disables the timeout.
+	AcceptTimeout time.Duration
+
+	// Clock is the clock used for the accept timeout.
+	Clock clock.Clock
+}
+
+// HoldInvoiceRules automatically settles or cancels accepted hold invoices
+// based on a list of rules and an accept timeout. Hold invoices that no rule
+// decides on are left to be resolved through the invoices RPC as usual.
+type HoldInvoiceRules struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *HoldInvoiceRulesConfig
+
+	// watchedMtx guards watched.
+	watchedMtx sync.Mutex
+
+	// watched is the set of hold invoices that are currently watched.
+	watched map[lntypes.Hash]struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewHoldInvoiceRules creates new hold invoice rules from the given config.
+func NewHoldInvoiceRules(cfg *HoldInvoiceRulesConfig) *HoldInvoiceRules {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &HoldInvoiceRules{
+		cfg:     cfg,
+		watched: make(map[lntypes.Hash]struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to new hold invoices and starts watching the pending ones.
+func (h *HoldInvoiceRules) Start() error {
+	var startErr error
+	h.started.Do(func() {
+		log.Info("HoldInvoiceRules starting")
+		startErr = h.start()
+	})
+
+	return startErr
+}
+
+// start subscribes to new invoices before fetching the pending ones, so that
+// no invoice is missed in between.
+func (h *HoldInvoiceRules) start() error {
+	sub, err := h.cfg.Registry.SubscribeNotifications(h.ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	pending, err := h.cfg.InvoiceDB.FetchPendingInvoices(h.ctx)
+	if err != nil {
+		sub.Cancel()
+		return err
+	}
+
+	for hash, invoice := range pending {
+		if invoice.HodlInvoice {
+			h.watch(hash)
+		}
+	}
+
+	h.wg.Add(1)
+	go h.newInvoiceLoop(sub)
+
+	return nil
+}
+
+// Stop stops watching hold invoices.
+func (h *HoldInvoiceRules) Stop() error {
+	h.stopped.Do(func() {
+		log.Info("HoldInvoiceRules shutting down...")
+		defer log.Debug("HoldInvoiceRules shutdown complete")
+
+		h.cancel()
+		close(h.quit)
+		h.wg.Wait()
+	})
+
+	return nil
+}
+
+// newInvoiceLoop starts watching every new hold invoice.
+func (h *HoldInvoiceRules) newInvoiceLoop(sub *InvoiceSubscription) {
+	defer h.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		select {
+		case invoice := <-sub.NewInvoices:
+			if !invoice.HodlInvoice {
+				continue
+			}
+
+			// Spontaneous payments have no payment request. They
+			// are only held for the keysend hold time and are
+			// released by the registry itself.
+			if len(invoice.PaymentRequest) == 0 {
+				continue
+			}
+
+			payReq, err := zpay32.Decode(
+				string(invoice.PaymentRequest),
+				h.cfg.ChainParams,
+			)
+			if err != nil || payReq.PaymentHash == nil {
+				log.Errorf("Unable to decode payment request "+
+					"of hold invoice %v: %v",
+					invoice.AddIndex, err)
+
+				continue
+			}
+
+			h.watch(*payReq.PaymentHash)
+
+		case <-sub.SettledInvoices:
+
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// watch starts watching the hold invoice with the given payment hash, unless
+// it's watched already.
+func (h *HoldInvoiceRules) watch(hash lntypes.Hash) {
+	h.watchedMtx.Lock()
+	defer h.watchedMtx.Unlock()
+
+	if _, ok := h.watched[hash]; ok {
+		return
+	}
+	h.watched[hash] = struct{}{}
+
+	h.wg.Add(1)
+	go h.watchInvoice(hash)
+}
+
+// watchInvoice evaluates the rules once the hold invoice with the given
+// payment hash is accepted, and cancels it if it's still accepted when the
+// accept timeout expires.
+func (h *HoldInvoiceRules) watchInvoice(hash lntypes.Hash) {
+	defer h.wg.Done()
+	defer func() {
+		h.watchedMtx.Lock()
+		delete(h.watched, hash)
+		h.watchedMtx.Unlock()
+	}()
+
+	sub, err := h.cfg.Registry.SubscribeSingleInvoice(h.ctx, hash)
+	if err != nil {
+		log.Errorf("Unable to subscribe to hold invoice %v: %v", hash,
+			err)
+
+		return
+	}
+	defer sub.Cancel()
+
+	var (
+		decided bool
+		timeout <-chan time.Time
+	)
+	for {
+		select {
+		case invoice := <-sub.Updates:
+			switch invoice.State {
+			case ContractOpen:
+				continue
+
+			case ContractAccepted:
+				if decided {
+					continue
+				}
+				decided = true
+
+				if h.decide(hash, invoice) {
+					return
+				}
+
+				if h.cfg.AcceptTimeout > 0 {
+					timeout = h.timeoutTicker(invoice)
+				}
+
+			// The invoice was settled or canceled.
+			default:
+				return
+			}
+
+		case <-timeout:
+			log.Infof("Canceling hold invoice %v after accept "+
+				"timeout of %v", hash, h.cfg.AcceptTimeout)
+
+			err := h.cfg.Registry.CancelInvoice(h.ctx, hash)
+			if err != nil {
+				log.Errorf("Unable to cancel hold invoice %v: "+
+					"%v", hash, err)
+			}
+
+			return
+
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// timeoutTicker returns a channel that ticks once the accept timeout of the
+// given accepted invoice expires. The timeout starts when the last htlc of the
+// invoice was accepted, so it isn't reset by a restart.
+func (h *HoldInvoiceRules) timeoutTicker(invoice *Invoice) <-chan time.Time {
+	var acceptTime time.Time
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State == HtlcStateAccepted &&
+			htlc.AcceptTime.After(acceptTime) {
+
+			acceptTime = htlc.AcceptTime
+		}
+	}
+
+	expiry := acceptTime.Add(h.cfg.AcceptTimeout)
+
+	return h.cfg.Clock.TickAfter(expiry.Sub(h.cfg.Clock.Now()))
+}
+
+// decide evaluates the rules for the accepted hold invoice and settles or
+// cancels it accordingly. It returns true if the invoice was resolved.
+func (h *HoldInvoiceRules) decide(hash lntypes.Hash, invoice *Invoice) bool {
+	for _, rule := range h.cfg.Rules {
+		decision, err := rule.Decide(h.ctx, hash, invoice)
+		if err != nil {
+			log.Errorf("Hold invoice rule %v failed for %v: %v",
+				rule.Name(), hash, err)
+
+			continue
+		}
+
+		switch decision.Action {
+		case HoldActionHold:
+			continue
+
+		case HoldActionSettle:
+			err = h.settle(hash, decision.Preimage)
+
+		case HoldActionCancel:
+			err = h.cfg.Registry.CancelInvoice(h.ctx, hash)
+		}
+
+		if err != nil {
+			log.Errorf("Unable to %v hold invoice %v as decided by "+
+				"rule %v: %v", decision.Action, hash,
+				rule.Name(), err)
+
+			continue
+		}
+
+		log.Infof("Hold invoice %v: %v by rule %v", hash,
+			decision.Action, rule.Name())
+
+		return true
+	}
+
+	return false
+}
+
+// settle settles the hold invoice with the given payment hash after checking
+// that the preimage matches it.
+func (h *HoldInvoiceRules) settle(hash lntypes.Hash,
+	preimage *lntypes.Preimage) error {
+
+	if preimage == nil {
+		return errors.New("no preimage")
+	}
+	if !preimage.Matches(hash) {
+		return errors.New("preimage doesn't match the payment hash")
+	}
+
+	return h.cfg.Registry.SettleHodlInvoice(h.ctx, *preimage)
+}
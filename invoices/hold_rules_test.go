@@ -0,0 +1,207 @@
+package invoices_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	invpkg "github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// holdRulesTestAmt is the amount the hold invoices are paid with in the hold
+// invoice rules tests.
+const holdRulesTestAmt = lnwire.MilliSatoshi(100500)
+
+// startHoldRules creates a registry with an accepted hold invoice and starts
+// hold invoice rules with the given rules and accept timeout on top of it. It
+// returns the channel the resolution of the invoice's htlc is sent to.
+func startHoldRules(t *testing.T, rules []invpkg.HoldRule,
+	acceptTimeout time.Duration,
+	rulesClock clock.Clock) chan interface{} {
+
+	testClock := clock.NewTestClock(testNow)
+	idb, err := channeldb.MakeTestInvoiceDB(
+		t, channeldb.OptionClock(testClock),
+	)
+	require.NoError(t, err)
+
+	cfg := defaultRegistryConfig()
+	cfg.Clock = testClock
+	expiryWatcher := invpkg.NewInvoiceExpiryWatcher(
+		testClock, 0, uint32(testCurrentHeight), nil,
+		newMockNotifier(),
+	)
+	registry := invpkg.NewRegistry(idb, expiryWatcher, &cfg)
+	require.NoError(t, registry.Start())
+	t.Cleanup(func() {
+		require.NoError(t, registry.Stop())
+	})
+
+	ctxb := context.Background()
+	_, err = registry.AddInvoice(
+		ctxb, newInvoice(t, true), testInvoicePaymentHash,
+	)
+	require.NoError(t, err)
+
+	hodlChan := make(chan interface{}, 1)
+	resolution, err := registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, holdRulesTestAmt, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(0), hodlChan, testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	holdRules := invpkg.NewHoldInvoiceRules(&invpkg.HoldInvoiceRulesConfig{
+		Registry:      registry,
+		InvoiceDB:     idb,
+		ChainParams:   &chaincfg.TestNet3Params,
+		Rules:         rules,
+		AcceptTimeout: acceptTimeout,
+		Clock:         rulesClock,
+	})
+	require.NoError(t, holdRules.Start())
+	t.Cleanup(func() {
+		require.NoError(t, holdRules.Stop())
+	})
+
+	return hodlChan
+}
+
+// receiveResolution waits for the htlc resolution sent to the given channel.
+func receiveResolution(t *testing.T,
+	hodlChan chan interface{}) invpkg.HtlcResolution {
+
+	t.Helper()
+
+	select {
+	case resolution := <-hodlChan:
+		return resolution.(invpkg.HtlcResolution)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no htlc resolution received")
+		return nil
+	}
+}
+
+// TestHoldRulesAmount tests that the amount rules cancel and settle accepted
+// hold invoices.
+func TestHoldRulesAmount(t *testing.T) {
+	t.Parallel()
+
+	lookupPreimage := func(hash lntypes.Hash) (lntypes.Preimage, error) {
+		require.Equal(t, testInvoicePaymentHash, hash)
+		return testInvoicePreimage, nil
+	}
+
+	t.Run("cancel below minimum", func(t *testing.T) {
+		hodlChan := startHoldRules(t, []invpkg.HoldRule{
+			&invpkg.MinAmountRule{MinAmt: holdRulesTestAmt + 1},
+			&invpkg.AutoSettleRule{
+				MaxAmt:         holdRulesTestAmt * 2,
+				LookupPreimage: lookupPreimage,
+			},
+		}, 0, clock.NewTestClock(testNow))
+
+		checkFailResolution(
+			t, receiveResolution(t, hodlChan),
+			invpkg.ResultCanceled,
+		)
+	})
+
+	t.Run("settle up to maximum", func(t *testing.T) {
+		hodlChan := startHoldRules(t, []invpkg.HoldRule{
+			&invpkg.MinAmountRule{MinAmt: holdRulesTestAmt},
+			&invpkg.AutoSettleRule{
+				MaxAmt:         holdRulesTestAmt,
+				LookupPreimage: lookupPreimage,
+			},
+		}, 0, clock.NewTestClock(testNow))
+
+		checkSettleResolution(
+			t, receiveResolution(t, hodlChan), testInvoicePreimage,
+		)
+	})
+}
+
+// TestHoldRulesWebhook tests that accepted hold invoices are settled with the
+// preimage returned by the approval webhook.
+func TestHoldRulesWebhook(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var req invpkg.HoldWebhookRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+
+			require.Equal(
+				t, testInvoicePaymentHash.String(),
+				req.PaymentHash,
+			)
+			require.EqualValues(t, holdRulesTestAmt, req.AmtPaidMsat)
+
+			err = json.NewEncoder(w).Encode(
+				&invpkg.HoldWebhookResponse{
+					Action:   "settle",
+					Preimage: testInvoicePreimage.String(),
+				},
+			)
+			require.NoError(t, err)
+		},
+	))
+	t.Cleanup(server.Close)
+
+	hodlChan := startHoldRules(t, []invpkg.HoldRule{
+		&invpkg.WebhookRule{URL: server.URL, Timeout: time.Second},
+	}, 0, clock.NewTestClock(testNow))
+
+	checkSettleResolution(
+		t, receiveResolution(t, hodlChan), testInvoicePreimage,
+	)
+}
+
+// TestHoldRulesAcceptTimeout tests that accepted hold invoices no rule decided
+// on are canceled after the accept timeout.
+func TestHoldRulesAcceptTimeout(t *testing.T) {
+	t.Parallel()
+
+	const acceptTimeout = time.Minute
+
+	// The webhook holds the invoice.
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			err := json.NewEncoder(w).Encode(
+				&invpkg.HoldWebhookResponse{Action: "hold"},
+			)
+			require.NoError(t, err)
+		},
+	))
+	t.Cleanup(server.Close)
+
+	rulesClock := clock.NewTestClock(testNow)
+	hodlChan := startHoldRules(t, []invpkg.HoldRule{
+		&invpkg.WebhookRule{URL: server.URL, Timeout: time.Second},
+	}, acceptTimeout, rulesClock)
+
+	select {
+	case <-hodlChan:
+		t.Fatalf("invoice resolved before the accept timeout")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	rulesClock.SetTime(testNow.Add(acceptTimeout))
+
+	checkFailResolution(
+		t, receiveResolution(t, hodlChan), invpkg.ResultCanceled,
+	)
+}
@@ -35,6 +35,10 @@ const (
 	// DefaultHtlcHoldDuration defines the default for how long mpp htlcs
 	// are held while waiting for the other set members to arrive.
 	DefaultHtlcHoldDuration = 120 * time.Second
+
+	// DefaultSubscriptionShards is the default number of dispatchers that
+	// deliver invoice events to single invoice subscribers.
+	DefaultSubscriptionShards = 16
 )
 
 // RegistryConfig contains the configuration parameters for invoice registry.
@@ -74,6 +78,24 @@ type RegistryConfig struct {
 	// KeysendHoldTime indicates for how long we want to accept and hold
 	// spontaneous keysend payments.
 	KeysendHoldTime time.Duration
+
+	// ReceiveQuota limits the htlcs that invoices may hold in the accepted
+	// state.
+	ReceiveQuota ReceiveQuota
+
+	// InvoiceCreator returns the creator of the invoice with the given
+	// payment hash, or an empty string if it is unknown. It is used to
+	// enforce the creator limits of the receive quota, which don't apply
+	// to AMP payments, as their htlcs don't carry the payment hash of the
+	// invoice.
+	InvoiceCreator func(lntypes.Hash) (string, error)
+
+	// SubscriptionShards is the number of dispatchers that deliver invoice
+	// events to single invoice subscribers. Subscribers are sharded by
+	// payment hash, so the events of an invoice are still delivered in
+	// order, while the subscribers of different invoices don't wait for
+	// each other. If zero, DefaultSubscriptionShards is used.
+	SubscriptionShards int
 }
 
 // htlcReleaseEvent describes an htlc auto-release event. It is used to release
@@ -110,20 +132,19 @@ type InvoiceRegistry struct {
 	// cfg contains the registry's configuration parameters.
 	cfg *RegistryConfig
 
-	// notificationClientMux locks notificationClients and
-	// singleNotificationClients. Using a separate mutex for these maps is
-	// necessary to avoid deadlocks in the registry when processing invoice
-	// events.
+	// notificationClientMux locks notificationClients. Using a separate
+	// mutex for this map is necessary to avoid deadlocks in the registry
+	// when processing invoice events.
 	notificationClientMux sync.RWMutex
 
 	notificationClients map[uint32]*InvoiceSubscription
 
-	// TODO(yy): use map[lntypes.Hash]*SingleInvoiceSubscription for better
-	// performance.
-	singleNotificationClients map[uint32]*SingleInvoiceSubscription
+	// singleInvoiceShards dispatch the invoice events to the single
+	// invoice subscribers, which are sharded by payment hash.
+	singleInvoiceShards []*singleInvoiceShard
 
-	// invoiceEvents is a single channel over which invoice updates are
-	// carried.
+	// invoiceEvents is a single channel over which invoice updates for the
+	// subscribers of all invoices are carried.
 	invoiceEvents chan *invoiceEvent
 
 	// hodlSubscriptionsMux locks the hodlSubscriptions and
@@ -146,6 +167,10 @@ type InvoiceRegistry struct {
 
 	expiryWatcher *InvoiceExpiryWatcher
 
+	// quota tracks the pending htlc sets of the invoices to enforce the
+	// receive quota.
+	quota *quotaTracker
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -157,13 +182,22 @@ type InvoiceRegistry struct {
 func NewRegistry(idb InvoiceDB, expiryWatcher *InvoiceExpiryWatcher,
 	cfg *RegistryConfig) *InvoiceRegistry {
 
+	numShards := cfg.SubscriptionShards
+	if numShards <= 0 {
+		numShards = DefaultSubscriptionShards
+	}
+
+	singleInvoiceShards := make([]*singleInvoiceShard, numShards)
+	for idx := range singleInvoiceShards {
+		singleInvoiceShards[idx] = newSingleInvoiceShard()
+	}
+
 	notificationClients := make(map[uint32]*InvoiceSubscription)
-	singleNotificationClients := make(map[uint32]*SingleInvoiceSubscription)
 	return &InvoiceRegistry{
-		idb:                       idb,
-		notificationClients:       notificationClients,
-		singleNotificationClients: singleNotificationClients,
-		invoiceEvents:             make(chan *invoiceEvent, 100),
+		idb:                 idb,
+		notificationClients: notificationClients,
+		singleInvoiceShards: singleInvoiceShards,
+		invoiceEvents:       make(chan *invoiceEvent, 100),
 		hodlSubscriptions: make(
 			map[CircuitKey]map[chan<- interface{}]struct{},
 		),
@@ -173,7 +207,10 @@ func NewRegistry(idb InvoiceDB, expiryWatcher *InvoiceExpiryWatcher,
 		cfg:                 cfg,
 		htlcAutoReleaseChan: make(chan *htlcReleaseEvent),
 		expiryWatcher:       expiryWatcher,
-		quit:                make(chan struct{}),
+		quota: newQuotaTracker(
+			cfg.ReceiveQuota, cfg.InvoiceCreator,
+		),
+		quit: make(chan struct{}),
 	}
 }
 
@@ -193,6 +230,12 @@ func (i *InvoiceRegistry) scanInvoicesOnStart(ctx context.Context) error {
 		if expiryRef != nil {
 			pending = append(pending, expiryRef)
 		}
+
+		// Pick up the htlc sets that were pending when we shut down.
+		if hasAcceptedHtlcs(&invoice) {
+			creator := i.quota.lookupCreator(paymentHash)
+			i.quota.update(&invoice, nil, creator)
+		}
 	}
 
 	log.Debugf("Adding %d pending invoices to the expiry watcher",
@@ -227,6 +270,11 @@ func (i *InvoiceRegistry) Start() error {
 	i.wg.Add(1)
 	go i.invoiceEventLoop()
 
+	for _, shard := range i.singleInvoiceShards {
+		i.wg.Add(1)
+		go i.singleInvoiceEventLoop(shard)
+	}
+
 	// Now scan all pending and removable invoices to the expiry watcher or
 	// delete them.
 	err = i.scanInvoicesOnStart(context.Background())
@@ -267,9 +315,9 @@ func (i *InvoiceRegistry) tickAt(t time.Time) <-chan time.Time {
 	return i.cfg.Clock.TickAfter(t.Sub(now))
 }
 
-// invoiceEventLoop is the dedicated goroutine responsible for accepting
-// new notification subscriptions, cancelling old subscriptions, and
-// dispatching new invoice events.
+// invoiceEventLoop is the dedicated goroutine responsible for dispatching new
+// invoice events to the subscribers of all invoices and for auto-releasing
+// htlcs.
 func (i *InvoiceRegistry) invoiceEventLoop() {
 	defer i.wg.Done()
 
@@ -297,7 +345,6 @@ func (i *InvoiceRegistry) invoiceEventLoop() {
 
 				i.dispatchToClients(event)
 			}
-			i.dispatchToSingleClients(event)
 
 		// A new htlc came in for auto-release.
 		case event := <-i.htlcAutoReleaseChan:
@@ -328,30 +375,6 @@ func (i *InvoiceRegistry) invoiceEventLoop() {
 	}
 }
 
-// dispatchToSingleClients passes the supplied event to all notification
-// clients that subscribed to all the invoice this event applies to.
-func (i *InvoiceRegistry) dispatchToSingleClients(event *invoiceEvent) {
-	// Dispatch to single invoice subscribers.
-	clients := i.copySingleClients()
-	for _, client := range clients {
-		payHash := client.invoiceRef.PayHash()
-
-		if payHash == nil || *payHash != event.hash {
-			continue
-		}
-
-		select {
-		case <-client.backlogDelivered:
-			// We won't deliver any events until the backlog has
-			// went through first.
-		case <-i.quit:
-			return
-		}
-
-		client.notify(event)
-	}
-}
-
 // dispatchToClients passes the supplied event to all notification clients that
 // subscribed to all invoices. Add and settle indices are used to make sure
 // that clients don't receive duplicate or unwanted events.
@@ -713,6 +736,8 @@ func (i *InvoiceRegistry) cancelSingleHtlc(invoiceRef InvoiceRef,
 		return nil
 	}
 
+	i.quota.update(invoice, setID, "")
+
 	// The invoice has been updated. Notify subscribers of the htlc
 	// resolution.
 	htlc, ok := invoice.Htlcs[key]
@@ -983,6 +1008,57 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 	}
 }
 
+// SettleLocalPayment attempts to settle an invoice with a payment that
+// originates from our own node. The payment is treated like a single incoming
+// htlc identified by the given circuit key, but it never crosses the network,
+// so it is either settled or failed right away. The preimage of the invoice is
+// returned if the payment settled it.
+//
+// Hold invoices can't be paid this way, because the payment would need to
+// stay in flight until the preimage is released.
+func (i *InvoiceRegistry) SettleLocalPayment(rHash lntypes.Hash,
+	amtPaid lnwire.MilliSatoshi, expiry uint32, currentHeight int32,
+	circuitKey CircuitKey, payload Payload) (lntypes.Preimage, error) {
+
+	// A missing invoice is not an error at this point, as the registry
+	// may still create one for a keysend payment below.
+	invoice, err := i.LookupInvoice(context.Background(), rHash)
+	switch {
+	case err == nil && invoice.HodlInvoice:
+		return lntypes.Preimage{}, fmt.Errorf("hold invoice %v can't "+
+			"be settled locally", rHash)
+
+	case err != nil && !errors.Is(err, ErrInvoiceNotFound):
+		return lntypes.Preimage{}, err
+	}
+
+	hodlChan := make(chan interface{}, 1)
+	defer i.HodlUnsubscribeAll(hodlChan)
+
+	resolution, err := i.NotifyExitHopHtlc(
+		rHash, amtPaid, expiry, currentHeight, circuitKey, hodlChan,
+		payload,
+	)
+	if err != nil {
+		return lntypes.Preimage{}, err
+	}
+
+	switch res := resolution.(type) {
+	case *HtlcSettleResolution:
+		return res.Preimage, nil
+
+	case *HtlcFailResolution:
+		return lntypes.Preimage{}, fmt.Errorf("local payment to "+
+			"invoice %v failed: %v", rHash, res.Outcome)
+
+	// A nil resolution means that the payment was accepted, but didn't
+	// complete the invoice.
+	default:
+		return lntypes.Preimage{}, fmt.Errorf("local payment to "+
+			"invoice %v was not settled", rHash)
+	}
+}
+
 // notifyExitHopHtlcLocked is the internal implementation of NotifyExitHopHtlc
 // that should be executed inside the registry lock. The returned invoiceExpiry
 // (if not nil) needs to be added to the expiry watcher outside of the lock.
@@ -997,7 +1073,25 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 		updateSubscribers bool
 	)
 
+	// The creator of the invoice is looked up upfront, as the lookup
+	// can't be done from within the database transaction of the update.
+	var creator string
+	if ctx.amp == nil {
+		creator = i.quota.lookupCreator(ctx.hash)
+	}
+
 	callback := func(inv *Invoice) (*InvoiceUpdateDesc, error) {
+		// Fail the htlc without adding it to the invoice if accepting
+		// it would exceed the receive quota.
+		if !i.quota.allow(ctx, inv, creator) {
+			resolution = NewFailResolution(
+				ctx.circuitKey, ctx.currentHeight,
+				ResultReceiveQuotaExceeded,
+			)
+
+			return nil, nil
+		}
+
 		updateDesc, res, err := updateInvoice(ctx, inv)
 		if err != nil {
 			return nil, err
@@ -1049,6 +1143,8 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 		return nil, nil, err
 	}
 
+	i.quota.update(invoice, setID, creator)
+
 	var invoiceToExpire invoiceExpiry
 
 	switch res := resolution.(type) {
@@ -1237,6 +1333,8 @@ func (i *InvoiceRegistry) SettleHodlInvoice(ctx context.Context,
 		return err
 	}
 
+	i.quota.update(invoice, nil, "")
+
 	log.Debugf("Invoice%v: settled with preimage %v", invoiceRef,
 		invoice.Terms.PaymentPreimage)
 
@@ -1326,6 +1424,8 @@ func (i *InvoiceRegistry) cancelInvoiceImpl(ctx context.Context,
 		return err
 	}
 
+	i.quota.update(invoice, nil, "")
+
 	// Return without cancellation if the invoice state is ContractAccepted.
 	if invoice.State == ContractAccepted {
 		log.Debugf("Invoice%v: remains accepted as cancel wasn't"+
@@ -1393,6 +1493,13 @@ func (i *InvoiceRegistry) notifyClients(hash lntypes.Hash,
 
 	select {
 	case i.invoiceEvents <- event:
+	case <-i.quit:
+		return
+	}
+
+	shard := i.singleInvoiceShard(hash)
+	select {
+	case shard.events <- event:
 	case <-i.quit:
 	}
 }
@@ -1629,7 +1736,7 @@ func (i *InvoiceRegistry) SubscribeSingleInvoice(ctx context.Context,
 	i.wg.Add(1)
 	go func() {
 		defer i.wg.Done()
-		defer i.deleteClient(client.id)
+		defer i.deleteSingleClient(client)
 
 		for {
 			select {
@@ -1658,9 +1765,7 @@ func (i *InvoiceRegistry) SubscribeSingleInvoice(ctx context.Context,
 		}
 	}()
 
-	i.notificationClientMux.Lock()
-	i.singleNotificationClients[client.id] = client
-	i.notificationClientMux.Unlock()
+	i.addSingleClient(client)
 
 	err := i.deliverSingleBacklogEvents(ctx, client)
 	if err != nil {
@@ -1740,19 +1845,6 @@ func (i *InvoiceRegistry) HodlUnsubscribeAll(subscriber chan<- interface{}) {
 	delete(i.hodlReverseSubscriptions, subscriber)
 }
 
-// copySingleClients copies i.SingleInvoiceSubscription inside a lock. This is
-// useful when we need to iterate the map to send notifications.
-func (i *InvoiceRegistry) copySingleClients() map[uint32]*SingleInvoiceSubscription { //nolint:lll
-	i.notificationClientMux.RLock()
-	defer i.notificationClientMux.RUnlock()
-
-	clients := make(map[uint32]*SingleInvoiceSubscription)
-	for k, v := range i.singleNotificationClients {
-		clients[k] = v
-	}
-	return clients
-}
-
 // copyClients copies i.notificationClients inside a lock. This is useful when
 // we need to iterate the map to send notifications.
 func (i *InvoiceRegistry) copyClients() map[uint32]*InvoiceSubscription {
@@ -1774,5 +1866,4 @@ func (i *InvoiceRegistry) deleteClient(clientID uint32) {
 
 	log.Infof("Cancelling invoice subscription for client=%v", clientID)
 	delete(i.notificationClients, clientID)
-	delete(i.singleNotificationClients, clientID)
 }
@@ -109,6 +109,14 @@ func TestInvoiceRegistry(t *testing.T) {
 			name: "SpontaneousAmpPayment",
 			test: testSpontaneousAmpPayment,
 		},
+		{
+			name: "SettleLocalPayment",
+			test: testSettleLocalPayment,
+		},
+		{
+			name: "ReceiveQuota",
+			test: testReceiveQuota,
+		},
 	}
 
 	makeKeyValueDB := func(t *testing.T) (invpkg.InvoiceDB,
@@ -2095,3 +2103,157 @@ func testSpontaneousAmpPaymentImpl(
 		}
 	}
 }
+
+// testSettleLocalPayment tests that payments from our own node settle regular
+// invoices directly, and that hold invoices are rejected.
+func testSettleLocalPayment(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+
+	ctx := newTestContext(t, nil, makeDB)
+	ctxb := context.Background()
+
+	// Paying an unknown invoice fails.
+	_, err := ctx.registry.SettleLocalPayment(
+		testInvoicePaymentHash, testInvoiceAmount, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(0), testPayload,
+	)
+	require.Error(t, err)
+
+	// Hold invoices can't be settled locally and are left untouched.
+	hodlPreimage := lntypes.Preimage{2}
+	hodlHash := hodlPreimage.Hash()
+	_, err = ctx.registry.AddInvoice(ctxb, newInvoice(t, true), hodlHash)
+	require.NoError(t, err)
+
+	_, err = ctx.registry.SettleLocalPayment(
+		hodlHash, testInvoiceAmount, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(1), testPayload,
+	)
+	require.Error(t, err)
+
+	inv, err := ctx.registry.LookupInvoice(ctxb, hodlHash)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.ContractOpen, inv.State)
+
+	// A regular invoice is settled and its preimage returned.
+	_, err = ctx.registry.AddInvoice(
+		ctxb, newInvoice(t, false), testInvoicePaymentHash,
+	)
+	require.NoError(t, err)
+
+	preimage, err := ctx.registry.SettleLocalPayment(
+		testInvoicePaymentHash, testInvoiceAmount, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(2), testPayload,
+	)
+	require.NoError(t, err)
+	require.Equal(t, testInvoicePreimage, preimage)
+
+	inv, err = ctx.registry.LookupInvoice(ctxb, testInvoicePaymentHash)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.ContractSettled, inv.State)
+	require.Equal(t, testInvoiceAmount, inv.AmtPaid)
+
+	// Repeating the same payment, as happens when a payment is resumed
+	// after a restart, returns the preimage again.
+	preimage, err = ctx.registry.SettleLocalPayment(
+		testInvoicePaymentHash, testInvoiceAmount, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(2), testPayload,
+	)
+	require.NoError(t, err)
+	require.Equal(t, testInvoicePreimage, preimage)
+}
+
+// testReceiveQuota tests that htlcs that would exceed the receive quota of an
+// invoice or of its creator are failed, and that the quota is freed once the
+// htlcs are resolved.
+func testReceiveQuota(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+
+	aliceHash1 := lntypes.Hash{1}
+	aliceHash2 := lntypes.Hash{2}
+	anonHash := lntypes.Hash{3}
+
+	cfg := defaultRegistryConfig()
+	cfg.ReceiveQuota = invpkg.ReceiveQuota{
+		MaxInvoicePending: testInvoiceAmount,
+		MaxCreatorSets:    1,
+	}
+	cfg.InvoiceCreator = func(hash lntypes.Hash) (string, error) {
+		if hash == aliceHash1 || hash == aliceHash2 {
+			return "alice", nil
+		}
+
+		return "", nil
+	}
+
+	ctx := newTestContext(t, &cfg, makeDB)
+	ctxb := context.Background()
+
+	for _, hash := range []lntypes.Hash{aliceHash1, aliceHash2, anonHash} {
+		_, err := ctx.registry.AddInvoice(
+			ctxb, newInvoice(t, true), hash,
+		)
+		require.NoError(t, err)
+	}
+
+	notify := func(hash lntypes.Hash, amt lnwire.MilliSatoshi,
+		htlcID uint64, payload invpkg.Payload) invpkg.HtlcResolution {
+
+		t.Helper()
+
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			hash, amt, testHtlcExpiry, testCurrentHeight,
+			getCircuitKey(htlcID), make(chan interface{}, 1),
+			payload,
+		)
+		require.NoError(t, err)
+
+		return resolution
+	}
+
+	// Alice may hold a single htlc set, so the payment of her second
+	// invoice is failed while the first one is held.
+	resolution := notify(aliceHash1, testInvoiceAmount, 0, testPayload)
+	require.Nil(t, resolution)
+
+	resolution = notify(aliceHash2, testInvoiceAmount, 1, testPayload)
+	checkFailResolution(t, resolution, invpkg.ResultReceiveQuotaExceeded)
+
+	inv, err := ctx.registry.LookupInvoice(ctxb, aliceHash2)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.ContractOpen, inv.State)
+	require.Empty(t, inv.Htlcs)
+
+	// Partial payments of the invoice without a creator may not exceed
+	// its pending amount.
+	mppPayload := &mockPayload{
+		mpp: record.NewMPP(testInvoiceAmount, [32]byte{}),
+	}
+	resolution = notify(anonHash, testInvoiceAmount/2, 2, mppPayload)
+	require.Nil(t, resolution)
+
+	resolution = notify(anonHash, testInvoiceAmount/2+1, 3, mppPayload)
+	checkFailResolution(t, resolution, invpkg.ResultReceiveQuotaExceeded)
+
+	resolution = notify(anonHash, testInvoiceAmount/2, 4, mppPayload)
+	require.Nil(t, resolution)
+
+	inv, err = ctx.registry.LookupInvoice(ctxb, anonHash)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.ContractAccepted, inv.State)
+
+	// Once the first invoice of alice is canceled, her second invoice can
+	// be paid.
+	require.NoError(t, ctx.registry.CancelInvoice(ctxb, aliceHash1))
+
+	resolution = notify(aliceHash2, testInvoiceAmount, 5, testPayload)
+	require.Nil(t, resolution)
+
+	inv, err = ctx.registry.LookupInvoice(ctxb, aliceHash2)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.ContractAccepted, inv.State)
+}
@@ -0,0 +1,168 @@
+package invoices
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+var (
+	// preimageDeriverBucket is a root-level bucket that stores the state
+	// of the deterministic preimage deriver.
+	preimageDeriverBucket = []byte("preimage-deriver-bucket")
+
+	// nextPreimageIndexKey is a key in the preimageDeriverBucket whose
+	// value is the index of the next preimage that will be derived.
+	nextPreimageIndexKey = []byte("next-preimage-index")
+
+	// preimageRootKeyLoc is the locator of the key the root secret of all
+	// deterministic preimages is derived from. The first key of the base
+	// encryption family is used to encrypt static channel backups, so we
+	// use the second one.
+	preimageRootKeyLoc = keychain.KeyLocator{
+		Family: keychain.KeyFamilyBaseEncryption,
+		Index:  1,
+	}
+
+	// ErrDerivedPreimageNotFound is returned when no derived preimage
+	// matches a payment hash.
+	ErrDerivedPreimageNotFound = errors.New("no derived preimage matches " +
+		"the payment hash")
+)
+
+// PreimageDeriver derives invoice preimages from the wallet's seed instead of
+// drawing them at random. The preimage of the invoice with preimage index i
+// is HMAC-SHA256(rootSecret, i), where the root secret is the SHA256 of a
+// private key derived from the seed. This makes it possible to regenerate the
+// preimages of all invoices from the seed alone, for example to prove that an
+// invoice was paid after its database entry was lost, while nobody who only
+// knows our public keys can compute them.
+type PreimageDeriver struct {
+	rootSecret [sha256.Size]byte
+
+	backend kvdb.Backend
+}
+
+// NewPreimageDeriver creates a new preimage deriver that derives its root
+// secret from the given key ring and stores the next preimage index in the
+// given database backend. The key ring must have access to the private keys,
+// so deterministic preimages aren't available with a remote signer.
+func NewPreimageDeriver(keyRing keychain.SecretKeyRing,
+	db kvdb.Backend) (*PreimageDeriver, error) {
+
+	rootKey, err := keyRing.DerivePrivKey(keychain.KeyDescriptor{
+		KeyLocator: preimageRootKeyLoc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive preimage root key: "+
+			"%w", err)
+	}
+
+	err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(preimageDeriverBucket)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreimageDeriver{
+		rootSecret: sha256.Sum256(rootKey.Serialize()),
+		backend:    db,
+	}, nil
+}
+
+// Derive returns the preimage with the given preimage index.
+func (p *PreimageDeriver) Derive(index uint64) lntypes.Preimage {
+	var indexBytes [8]byte
+	binary.BigEndian.PutUint64(indexBytes[:], index)
+
+	mac := hmac.New(sha256.New, p.rootSecret[:])
+	_, _ = mac.Write(indexBytes[:])
+
+	var preimage lntypes.Preimage
+	copy(preimage[:], mac.Sum(nil))
+
+	return preimage
+}
+
+// Next derives the preimage with the next unused preimage index and returns
+// it along with its index. The index is persisted before the preimage is
+// returned, so a preimage is never handed out twice.
+func (p *PreimageDeriver) Next() (lntypes.Preimage, uint64, error) {
+	var index uint64
+	err := kvdb.Update(p.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(preimageDeriverBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		if v := bucket.Get(nextPreimageIndexKey); v != nil {
+			index = binary.BigEndian.Uint64(v)
+		}
+
+		var next [8]byte
+		binary.BigEndian.PutUint64(next[:], index+1)
+
+		return bucket.Put(nextPreimageIndexKey, next[:])
+	}, func() {
+		index = 0
+	})
+	if err != nil {
+		return lntypes.Preimage{}, 0, err
+	}
+
+	return p.Derive(index), index, nil
+}
+
+// NextIndex returns the preimage index that the next call to Next will use.
+func (p *PreimageDeriver) NextIndex() (uint64, error) {
+	var index uint64
+	err := kvdb.View(p.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(preimageDeriverBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		if v := bucket.Get(nextPreimageIndexKey); v != nil {
+			index = binary.BigEndian.Uint64(v)
+		}
+
+		return nil
+	}, func() {
+		index = 0
+	})
+
+	return index, err
+}
+
+// Find searches the preimages with an index below maxIndex for the one that
+// matches the given payment hash, and returns it along with its index. If
+// maxIndex is zero, all preimages that were handed out by Next are searched.
+// A maxIndex beyond that is useful after the database was lost.
+func (p *PreimageDeriver) Find(hash lntypes.Hash,
+	maxIndex uint64) (lntypes.Preimage, uint64, error) {
+
+	if maxIndex == 0 {
+		var err error
+		maxIndex, err = p.NextIndex()
+		if err != nil {
+			return lntypes.Preimage{}, 0, err
+		}
+	}
+
+	for index := uint64(0); index < maxIndex; index++ {
+		preimage := p.Derive(index)
+		if preimage.Matches(hash) {
+			return preimage, index, nil
+		}
+	}
+
+	return lntypes.Preimage{}, 0, ErrDerivedPreimageNotFound
+}
@@ -0,0 +1,107 @@
+package invoices
+
+import (
+	"crypto/sha256"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// preimageTestKeyRing is a key ring that derives the same private key for
+// every key locator.
+type preimageTestKeyRing struct {
+	keychain.SecretKeyRing
+
+	key *btcec.PrivateKey
+}
+
+// DeriveKey returns the public key of the key ring.
+func (k *preimageTestKeyRing) DeriveKey(
+	loc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	return keychain.KeyDescriptor{
+		KeyLocator: loc,
+		PubKey:     k.key.PubKey(),
+	}, nil
+}
+
+// DerivePrivKey returns the private key of the key ring.
+func (k *preimageTestKeyRing) DerivePrivKey(
+	_ keychain.KeyDescriptor) (*btcec.PrivateKey, error) {
+
+	return k.key, nil
+}
+
+// TestPreimageDeriver tests that preimages are derived deterministically,
+// handed out only once and can be found again by their payment hash.
+func TestPreimageDeriver(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	keyRing := &preimageTestKeyRing{key: key}
+	deriver, err := NewPreimageDeriver(keyRing, db)
+	require.NoError(t, err)
+
+	// The root secret can't be computed from the public key.
+	pubKeyHash := sha256.Sum256(key.PubKey().SerializeCompressed())
+	require.NotEqual(t, pubKeyHash, deriver.rootSecret)
+
+	// Preimages are handed out with increasing indexes.
+	preimage0, index, err := deriver.Next()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, index)
+
+	preimage1, index, err := deriver.Next()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, index)
+	require.NotEqual(t, preimage0, preimage1)
+
+	nextIndex, err := deriver.NextIndex()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, nextIndex)
+
+	// A deriver with a fresh database derives the same preimages from the
+	// same seed, and can find them by their hash if the index range is
+	// given.
+	dbPath = filepath.Join(t.TempDir(), "testdb")
+	freshDB, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, freshDB.Close())
+	})
+
+	restored, err := NewPreimageDeriver(keyRing, freshDB)
+	require.NoError(t, err)
+	require.Equal(t, preimage1, restored.Derive(1))
+
+	_, _, err = restored.Find(preimage1.Hash(), 0)
+	require.ErrorIs(t, err, ErrDerivedPreimageNotFound)
+
+	preimage, index, err := restored.Find(preimage1.Hash(), 10)
+	require.NoError(t, err)
+	require.Equal(t, preimage1, preimage)
+	require.EqualValues(t, 1, index)
+
+	// The original deriver searches the handed out preimages by default.
+	preimage, index, err = deriver.Find(preimage0.Hash(), 0)
+	require.NoError(t, err)
+	require.Equal(t, preimage0, preimage)
+	require.EqualValues(t, 0, index)
+}
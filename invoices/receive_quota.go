@@ -0,0 +1,302 @@
+package invoices
+
+import (
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ReceiveQuota limits the htlcs that invoices may hold in the accepted state,
+// both per invoice and per creator of the invoices. This protects services
+// that hold htlcs, for example with hold invoices, from being griefed with
+// many partial payments that get stuck. A zero limit isn't enforced.
+//
+// An htlc set is pending as long as it has accepted htlcs, and its pending
+// amount is the sum of those htlcs. Invoices that aren't AMP invoices have a
+// single htlc set.
+type ReceiveQuota struct {
+	// MaxInvoiceSets is the maximum number of pending htlc sets of a
+	// single invoice.
+	MaxInvoiceSets int
+
+	// MaxInvoicePending is the maximum pending amount of a single
+	// invoice.
+	MaxInvoicePending lnwire.MilliSatoshi
+
+	// MaxCreatorSets is the maximum number of pending htlc sets of all
+	// invoices with the same creator.
+	MaxCreatorSets int
+
+	// MaxCreatorPending is the maximum pending amount of all invoices with
+	// the same creator.
+	MaxCreatorPending lnwire.MilliSatoshi
+}
+
+// enforcesCreator returns true if any of the creator limits is set.
+func (q *ReceiveQuota) enforcesCreator() bool {
+	return q.MaxCreatorSets > 0 || q.MaxCreatorPending > 0
+}
+
+// enforced returns true if any of the limits is set.
+func (q *ReceiveQuota) enforced() bool {
+	return q.MaxInvoiceSets > 0 || q.MaxInvoicePending > 0 ||
+		q.enforcesCreator()
+}
+
+// quotaUsage is the part of a receive quota that is in use.
+type quotaUsage struct {
+	// sets is the number of pending htlc sets.
+	sets int
+
+	// pending is the pending amount.
+	pending lnwire.MilliSatoshi
+}
+
+// invoiceUsage tracks the pending htlc sets of an invoice.
+type invoiceUsage struct {
+	// creator is the creator of the invoice, if known.
+	creator string
+
+	// sets are the pending amounts of the pending htlc sets of the
+	// invoice. The set ID is blank for invoices that aren't AMP invoices.
+	sets map[SetID]lnwire.MilliSatoshi
+}
+
+// total returns the usage of the invoice.
+func (u *invoiceUsage) total() quotaUsage {
+	usage := quotaUsage{sets: len(u.sets)}
+	for _, pending := range u.sets {
+		usage.pending += pending
+	}
+
+	return usage
+}
+
+// quotaTracker tracks the pending htlc sets of invoices to enforce the
+// receive quota.
+type quotaTracker struct {
+	quota ReceiveQuota
+
+	// invoiceCreator returns the creator of the invoice with the given
+	// payment hash, or an empty string if it is unknown.
+	invoiceCreator func(lntypes.Hash) (string, error)
+
+	// invoices tracks the invoices with pending htlc sets by their add
+	// index.
+	invoices map[uint64]*invoiceUsage
+
+	// creators tracks the usage of the creators of the invoices with
+	// pending htlc sets.
+	creators map[string]*quotaUsage
+
+	mu sync.Mutex
+}
+
+// newQuotaTracker creates a tracker that enforces the given quota.
+func newQuotaTracker(quota ReceiveQuota,
+	invoiceCreator func(lntypes.Hash) (string, error)) *quotaTracker {
+
+	return &quotaTracker{
+		quota:          quota,
+		invoiceCreator: invoiceCreator,
+		invoices:       make(map[uint64]*invoiceUsage),
+		creators:       make(map[string]*quotaUsage),
+	}
+}
+
+// lookupCreator returns the creator of the invoice with the given payment
+// hash, or an empty string if the creator limits aren't enforced or the
+// creator is unknown.
+//
+// NOTE: This must not be called from within an invoice database transaction.
+func (q *quotaTracker) lookupCreator(hash lntypes.Hash) string {
+	if !q.quota.enforcesCreator() || q.invoiceCreator == nil {
+		return ""
+	}
+
+	creator, err := q.invoiceCreator(hash)
+	if err != nil {
+		log.Errorf("Unable to look up creator of invoice %v: %v", hash,
+			err)
+
+		return ""
+	}
+
+	return creator
+}
+
+// allow returns true if the htlc of the given update context may be accepted
+// on the invoice without exceeding the receive quota of the invoice or of the
+// given creator.
+func (q *quotaTracker) allow(ctx *invoiceUpdateCtx, invoice *Invoice,
+	creator string) bool {
+
+	if !q.quota.enforced() || invoice.State != ContractOpen {
+		return true
+	}
+
+	// Replayed htlcs were accepted before already.
+	if _, ok := invoice.Htlcs[ctx.circuitKey]; ok {
+		return true
+	}
+
+	var setID SetID
+	if ctx.amp != nil {
+		setID = ctx.amp.SetID()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var usage quotaUsage
+	newSet := true
+	if tracked, ok := q.invoices[invoice.AddIndex]; ok {
+		usage = tracked.total()
+		_, ok := tracked.sets[setID]
+		newSet = !ok
+	}
+	if newSet {
+		usage.sets++
+	}
+	usage.pending += ctx.amtPaid
+
+	if exceeds(usage, q.quota.MaxInvoiceSets, q.quota.MaxInvoicePending) {
+		ctx.log("receive quota of invoice exceeded")
+		return false
+	}
+
+	if creator == "" {
+		return true
+	}
+
+	var creatorUsage quotaUsage
+	if tracked, ok := q.creators[creator]; ok {
+		creatorUsage = *tracked
+	}
+	if newSet {
+		creatorUsage.sets++
+	}
+	creatorUsage.pending += ctx.amtPaid
+
+	if exceeds(creatorUsage, q.quota.MaxCreatorSets,
+		q.quota.MaxCreatorPending) {
+
+		ctx.log("receive quota of invoice creator " + creator +
+			" exceeded")
+
+		return false
+	}
+
+	return true
+}
+
+// exceeds returns true if the usage exceeds any of the given limits that are
+// set.
+func exceeds(usage quotaUsage, maxSets int,
+	maxPending lnwire.MilliSatoshi) bool {
+
+	return (maxSets > 0 && usage.sets > maxSets) ||
+		(maxPending > 0 && usage.pending > maxPending)
+}
+
+// hasAcceptedHtlcs returns true if the invoice has any accepted htlcs.
+func hasAcceptedHtlcs(invoice *Invoice) bool {
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State == HtlcStateAccepted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// update updates the tracked htlc sets of the invoice from its accepted
+// htlcs. If a set ID is given, only the htlc set with that ID is updated, as
+// the htlcs of other sets may be missing from the invoice. The given creator
+// is used if the invoice isn't tracked yet.
+func (q *quotaTracker) update(invoice *Invoice, setID *SetID,
+	creator string) {
+
+	if !q.quota.enforced() {
+		return
+	}
+
+	sets := make(map[SetID]lnwire.MilliSatoshi)
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State != HtlcStateAccepted {
+			continue
+		}
+
+		var htlcSetID SetID
+		if htlc.AMP != nil {
+			htlcSetID = htlc.AMP.Record.SetID()
+		}
+		if setID != nil && htlcSetID != *setID {
+			continue
+		}
+
+		sets[htlcSetID] += htlc.Amt
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tracked, ok := q.invoices[invoice.AddIndex]
+	if !ok {
+		if len(sets) == 0 {
+			return
+		}
+
+		tracked = &invoiceUsage{
+			creator: creator,
+			sets:    make(map[SetID]lnwire.MilliSatoshi),
+		}
+		q.invoices[invoice.AddIndex] = tracked
+	}
+
+	q.adjustCreator(tracked, -1)
+
+	if setID != nil {
+		delete(tracked.sets, *setID)
+		for id, pending := range sets {
+			tracked.sets[id] = pending
+		}
+	} else {
+		tracked.sets = sets
+	}
+
+	q.adjustCreator(tracked, 1)
+
+	if len(tracked.sets) == 0 {
+		delete(q.invoices, invoice.AddIndex)
+	}
+}
+
+// adjustCreator adds (sign 1) or removes (sign -1) the usage of the given
+// invoice to or from the usage of its creator.
+//
+// NOTE: The mutex of the tracker must be held.
+func (q *quotaTracker) adjustCreator(tracked *invoiceUsage, sign int) {
+	if tracked.creator == "" {
+		return
+	}
+
+	usage, ok := q.creators[tracked.creator]
+	if !ok {
+		usage = &quotaUsage{}
+		q.creators[tracked.creator] = usage
+	}
+
+	total := tracked.total()
+	usage.sets += sign * total.sets
+	if sign > 0 {
+		usage.pending += total.pending
+	} else {
+		usage.pending -= total.pending
+	}
+
+	if usage.sets == 0 {
+		delete(q.creators, tracked.creator)
+	}
+}
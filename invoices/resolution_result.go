@@ -120,6 +120,14 @@ const (
 	// ResultAmpReconstruction is returned when the derived child
 	// hash/preimage pairs were invalid for at least one HTLC in the set.
 	ResultAmpReconstruction
+
+	// ResultReceiveQuotaExceeded is returned when accepting the htlc would
+	// exceed the receive quota of the invoice or of its creator.
+	ResultReceiveQuotaExceeded
+
+	// ResultTrampolineError is returned when we receive an invalid
+	// trampoline onion or can't relay a trampoline payment.
+	ResultTrampolineError
 )
 
 // String returns a string representation of the result.
@@ -189,6 +197,12 @@ func (f FailResolutionResult) FailureString() string {
 	case ResultAmpReconstruction:
 		return "amp reconstruction failed"
 
+	case ResultReceiveQuotaExceeded:
+		return "receive quota exceeded"
+
+	case ResultTrampolineError:
+		return "trampoline relay failed"
+
 	default:
 		return "unknown failure resolution result"
 	}
@@ -0,0 +1,153 @@
+package invoices
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+// singleInvoiceShard dispatches the events of the invoices whose payment
+// hashes map to it to their single invoice subscribers. Each shard is served
+// by its own goroutine, so a subscriber whose backlog is still being delivered
+// only holds up the invoices of its shard.
+type singleInvoiceShard struct {
+	// events carries the invoice events of the shard's invoices.
+	events chan *invoiceEvent
+
+	// clientsMtx guards clients.
+	clientsMtx sync.RWMutex
+
+	// clients holds the subscribers of the shard's invoices by payment
+	// hash and client ID.
+	clients map[lntypes.Hash]map[uint32]*SingleInvoiceSubscription
+}
+
+// newSingleInvoiceShard creates a shard without subscribers.
+func newSingleInvoiceShard() *singleInvoiceShard {
+	return &singleInvoiceShard{
+		events: make(chan *invoiceEvent, 100),
+		clients: make(
+			map[lntypes.Hash]map[uint32]*SingleInvoiceSubscription,
+		),
+	}
+}
+
+// add registers a subscriber of the invoice with the given payment hash.
+func (s *singleInvoiceShard) add(hash lntypes.Hash,
+	client *SingleInvoiceSubscription) {
+
+	s.clientsMtx.Lock()
+	defer s.clientsMtx.Unlock()
+
+	clients, ok := s.clients[hash]
+	if !ok {
+		clients = make(map[uint32]*SingleInvoiceSubscription)
+		s.clients[hash] = clients
+	}
+	clients[client.id] = client
+}
+
+// remove unregisters a subscriber of the invoice with the given payment hash.
+// Noop if the client is not found.
+func (s *singleInvoiceShard) remove(hash lntypes.Hash, clientID uint32) {
+	s.clientsMtx.Lock()
+	defer s.clientsMtx.Unlock()
+
+	clients, ok := s.clients[hash]
+	if !ok {
+		return
+	}
+
+	delete(clients, clientID)
+	if len(clients) == 0 {
+		delete(s.clients, hash)
+	}
+}
+
+// subscribers returns a copy of the subscribers of the invoice with the given
+// payment hash, so that they can be notified without holding the lock.
+func (s *singleInvoiceShard) subscribers(
+	hash lntypes.Hash) []*SingleInvoiceSubscription {
+
+	s.clientsMtx.RLock()
+	defer s.clientsMtx.RUnlock()
+
+	clients := make([]*SingleInvoiceSubscription, 0, len(s.clients[hash]))
+	for _, client := range s.clients[hash] {
+		clients = append(clients, client)
+	}
+
+	return clients
+}
+
+// singleInvoiceShard returns the shard the subscribers of the invoice with the
+// given payment hash belong to.
+func (i *InvoiceRegistry) singleInvoiceShard(
+	hash lntypes.Hash) *singleInvoiceShard {
+
+	// Payment hashes are uniformly distributed, so their prefix spreads
+	// the invoices evenly across the shards.
+	idx := binary.BigEndian.Uint64(hash[:8]) %
+		uint64(len(i.singleInvoiceShards))
+
+	return i.singleInvoiceShards[idx]
+}
+
+// addSingleClient registers a single invoice subscriber with its shard.
+func (i *InvoiceRegistry) addSingleClient(client *SingleInvoiceSubscription) {
+	payHash := client.invoiceRef.PayHash()
+	if payHash == nil {
+		return
+	}
+
+	i.singleInvoiceShard(*payHash).add(*payHash, client)
+}
+
+// deleteSingleClient removes a single invoice subscriber from its shard. Noop
+// if the client is not found.
+func (i *InvoiceRegistry) deleteSingleClient(
+	client *SingleInvoiceSubscription) {
+
+	log.Infof("Cancelling single invoice subscription for client=%v",
+		client.id)
+
+	payHash := client.invoiceRef.PayHash()
+	if payHash == nil {
+		return
+	}
+
+	i.singleInvoiceShard(*payHash).remove(*payHash, client.id)
+}
+
+// singleInvoiceEventLoop dispatches the invoice events of a shard to the
+// subscribers of the invoices they apply to.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *InvoiceRegistry) singleInvoiceEventLoop(shard *singleInvoiceShard) {
+	defer i.wg.Done()
+
+	for {
+		select {
+		case event := <-shard.events:
+			for _, client := range shard.subscribers(event.hash) {
+				select {
+				case <-client.backlogDelivered:
+					// We won't deliver any events until the
+					// backlog has went through first.
+				case <-i.quit:
+					return
+				}
+
+				if err := client.notify(event); err != nil {
+					log.Debugf("Unable to notify single "+
+						"invoice client=%v: %v",
+						client.id, err)
+				}
+			}
+
+		case <-i.quit:
+			return
+		}
+	}
+}
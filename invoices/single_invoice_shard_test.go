@@ -0,0 +1,199 @@
+package invoices
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// notFoundInvoiceDB is an invoice database without invoices, so that single
+// invoice subscribers don't receive backlog events.
+type notFoundInvoiceDB struct {
+	InvoiceDB
+}
+
+// LookupInvoice returns ErrInvoiceNotFound for every invoice.
+func (d *notFoundInvoiceDB) LookupInvoice(context.Context,
+	InvoiceRef) (Invoice, error) {
+
+	return Invoice{}, ErrInvoiceNotFound
+}
+
+// FetchPendingInvoices returns no invoices.
+func (d *notFoundInvoiceDB) FetchPendingInvoices(
+	context.Context) (map[lntypes.Hash]Invoice, error) {
+
+	return nil, nil
+}
+
+// newShardTestRegistry creates and starts a registry that dispatches single
+// invoice events with the given number of shards.
+func newShardTestRegistry(t testing.TB, numShards int) *InvoiceRegistry {
+	testClock := clock.NewTestClock(testTime)
+	expiryWatcher := NewInvoiceExpiryWatcher(
+		testClock, 0, uint32(testCurrentHeight), nil, newMockNotifier(),
+	)
+
+	registry := NewRegistry(
+		&notFoundInvoiceDB{}, expiryWatcher, &RegistryConfig{
+			Clock:              testClock,
+			SubscriptionShards: numShards,
+		},
+	)
+	require.NoError(t, registry.Start())
+	t.Cleanup(func() {
+		require.NoError(t, registry.Stop())
+	})
+
+	return registry
+}
+
+// TestSingleInvoiceShards tests that invoice events are only dispatched to
+// the subscribers of their invoice, and that cancelled subscribers are removed
+// from their shard.
+func TestSingleInvoiceShards(t *testing.T) {
+	t.Parallel()
+
+	registry := newShardTestRegistry(t, 4)
+	ctx := context.Background()
+
+	hashA := lntypes.Hash{1}
+	hashB := lntypes.Hash{2}
+
+	subA1, err := registry.SubscribeSingleInvoice(ctx, hashA)
+	require.NoError(t, err)
+	subA2, err := registry.SubscribeSingleInvoice(ctx, hashA)
+	require.NoError(t, err)
+	subB, err := registry.SubscribeSingleInvoice(ctx, hashB)
+	require.NoError(t, err)
+
+	assertUpdate := func(sub *SingleInvoiceSubscription,
+		state ContractState) {
+
+		t.Helper()
+
+		select {
+		case invoice := <-sub.Updates:
+			require.Equal(t, state, invoice.State)
+
+		case <-time.After(testTimeout):
+			t.Fatalf("no update received")
+		}
+	}
+
+	assertNoUpdate := func(sub *SingleInvoiceSubscription) {
+		t.Helper()
+
+		select {
+		case invoice := <-sub.Updates:
+			t.Fatalf("unexpected update: %v", invoice.State)
+
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	// Both subscribers of the invoice receive its events in order.
+	registry.notifyClients(hashA, &Invoice{State: ContractAccepted}, nil)
+	registry.notifyClients(hashA, &Invoice{State: ContractSettled}, nil)
+
+	assertUpdate(subA1, ContractAccepted)
+	assertUpdate(subA1, ContractSettled)
+	assertUpdate(subA2, ContractAccepted)
+	assertUpdate(subA2, ContractSettled)
+	assertNoUpdate(subB)
+
+	// Once cancelled, a subscriber is removed from its shard and the
+	// others keep receiving events.
+	subA1.Cancel()
+	shard := registry.singleInvoiceShard(hashA)
+	require.Eventually(t, func() bool {
+		return len(shard.subscribers(hashA)) == 1
+	}, testTimeout, 10*time.Millisecond)
+
+	registry.notifyClients(hashB, &Invoice{State: ContractCanceled}, nil)
+	assertUpdate(subB, ContractCanceled)
+	assertNoUpdate(subA2)
+
+	subA2.Cancel()
+	require.Eventually(t, func() bool {
+		shard.clientsMtx.RLock()
+		defer shard.clientsMtx.RUnlock()
+
+		_, ok := shard.clients[hashA]
+		return !ok
+	}, testTimeout, 10*time.Millisecond)
+}
+
+// BenchmarkSingleInvoiceDispatch benchmarks the delivery of invoice events to
+// many concurrent single invoice subscribers. A single shard serializes the
+// dispatch of all invoices behind one goroutine, which the default number of
+// shards spreads across the available cores.
+func BenchmarkSingleInvoiceDispatch(b *testing.B) {
+	const numInvoices = 1000
+
+	for _, numShards := range []int{1, DefaultSubscriptionShards} {
+		numShards := numShards
+
+		name := fmt.Sprintf("shards=%d", numShards)
+		b.Run(name, func(b *testing.B) {
+			benchmarkSingleInvoiceDispatch(
+				b, numShards, numInvoices,
+			)
+		})
+	}
+}
+
+func benchmarkSingleInvoiceDispatch(b *testing.B, numShards,
+	numInvoices int) {
+
+	registry := newShardTestRegistry(b, numShards)
+
+	hashes := make([]lntypes.Hash, numInvoices)
+	for idx := range hashes {
+		binary.BigEndian.PutUint64(hashes[idx][:8], uint64(idx))
+	}
+
+	// Every invoice has a subscriber that consumes its updates, as an
+	// RPC stream would.
+	quit := make(chan struct{})
+	b.Cleanup(func() {
+		close(quit)
+	})
+
+	var received sync.WaitGroup
+	received.Add(b.N)
+	for idx := range hashes {
+		sub, err := registry.SubscribeSingleInvoice(
+			context.Background(), hashes[idx],
+		)
+		require.NoError(b, err)
+		b.Cleanup(sub.Cancel)
+
+		go func() {
+			for {
+				select {
+				case <-sub.Updates:
+					received.Done()
+
+				case <-quit:
+					return
+				}
+			}
+		}()
+	}
+
+	invoice := &Invoice{State: ContractAccepted}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		registry.notifyClients(hashes[n%numInvoices], invoice, nil)
+	}
+	received.Wait()
+}
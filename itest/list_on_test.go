@@ -630,4 +630,120 @@ var allTestCases = []*lntest.TestCase{
 		Name:     "coop close with external delivery",
 		TestFunc: testCoopCloseWithExternalDelivery,
 	},
+	{
+		Name:     "get chain info",
+		TestFunc: testGetChainInfo,
+	},
+	{
+		Name:     "freeze channel",
+		TestFunc: testFreezeChannel,
+	},
+	{
+		Name:     "known route hints",
+		TestFunc: testKnownRouteHints,
+	},
+	{
+		Name:     "jit channel registrations",
+		TestFunc: testJITChannelRegistrations,
+	},
+	{
+		Name:     "network results",
+		TestFunc: testNetworkResults,
+	},
+	{
+		Name:     "channel close history",
+		TestFunc: testChannelCloseHistory,
+	},
+	{
+		Name:     "address reuse report",
+		TestFunc: testAddressReuseReport,
+	},
+	{
+		Name:     "coop close rbf bump fee",
+		TestFunc: testCoopCloseRbfBumpFee,
+	},
+	{
+		Name:     "liquidity alerts",
+		TestFunc: testLiquidityAlerts,
+	},
+	{
+		Name:     "cltv guard status",
+		TestFunc: testCltvGuardStatus,
+	},
+	{
+		Name:     "safe mode status",
+		TestFunc: testSafeModeStatus,
+	},
+	{
+		Name:     "subscribe wallet balance",
+		TestFunc: testSubscribeWalletBalance,
+	},
+	{
+		Name:     "hold fee",
+		TestFunc: testHoldFee,
+	},
+	{
+		Name:     "jamming incidents",
+		TestFunc: testJammingIncidents,
+	},
+	{
+		Name:     "export ledger",
+		TestFunc: testExportLedger,
+	},
+	{
+		Name:     "list deprecations",
+		TestFunc: testListDeprecations,
+	},
+	{
+		Name:     "check open channel",
+		TestFunc: testCheckOpenChannel,
+	},
+	{
+		Name:     "diagnose connection",
+		TestFunc: testDiagnoseConnection,
+	},
+	{
+		Name:     "init records",
+		TestFunc: testInitRecords,
+	},
+	{
+		Name:     "node metadata",
+		TestFunc: testNodeMetadata,
+	},
+	{
+		Name:     "feature policy",
+		TestFunc: testFeaturePolicy,
+	},
+	{
+		Name:     "node capabilities",
+		TestFunc: testNodeCapabilities,
+	},
+	{
+		Name:     "funding contribution",
+		TestFunc: testFundingContribution,
+	},
+	{
+		Name:     "lsp",
+		TestFunc: testLSP,
+	},
+	{
+		Name:     "build topology",
+		TestFunc: testBuildTopology,
+	},
+	{
+		Name:     "chaos proxy",
+		TestFunc: testChaosProxy,
+	},
+	{
+		Name:     "node snapshot",
+		TestFunc: testNodeSnapshot,
+	},
+	{
+		Name:     "onion message",
+		TestFunc: testOnionMessage,
+	},
+	{
+		Name:     "async payment",
+		TestFunc: testAsyncPayment,
+	},
 }
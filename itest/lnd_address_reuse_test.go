@@ -0,0 +1,77 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testAddressReuseReport tests that an address of the wallet that received
+// funds more than once is reported by the AddressReuseReport RPC of both the
+// receiver and the sender.
+func testAddressReuseReport(ht *lntest.HarnessTest) {
+	alice := ht.Alice
+
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+
+	addr := carol.RPC.NewAddress(&lnrpc.NewAddressRequest{
+		Type: AddrTypeWitnessPubkeyHash,
+	})
+
+	// Before the address is reused, nothing is reported.
+	require.Empty(ht, carol.RPC.AddressReuseReport(
+		&lnrpc.AddressReuseReportRequest{},
+	).ReusedAddresses)
+
+	amt := btcutil.Amount(100_000)
+	var txHashes []string
+	for i := 0; i < 2; i++ {
+		resp := alice.RPC.SendCoins(&lnrpc.SendCoinsRequest{
+			Addr:       addr.Address,
+			Amount:     int64(amt),
+			TargetConf: 6,
+		})
+		txHashes = append(txHashes, resp.Txid)
+		ht.MineBlocksAndAssertNumTxes(1, 1)
+	}
+
+	// Carol received funds on the address twice.
+	var resp *lnrpc.AddressReuseReportResponse
+	err := wait.NoError(func() error {
+		resp = carol.RPC.AddressReuseReport(
+			&lnrpc.AddressReuseReportRequest{},
+		)
+		if len(resp.ReusedAddresses) != 1 {
+			return fmt.Errorf("expected 1 reused address, got %d",
+				len(resp.ReusedAddresses))
+		}
+
+		return nil
+	}, defaultTimeout)
+	require.NoError(ht, err, "carol address reuse")
+
+	reused := resp.ReusedAddresses[0]
+	require.Equal(ht, []string{addr.Address}, reused.Addresses)
+	require.True(ht, reused.IsOurAddress)
+	require.Equal(ht, txHashes, reused.TxHashes)
+	require.EqualValues(ht, 2*amt, reused.TotalValue)
+
+	// Alice paid to the foreign address twice.
+	resp = alice.RPC.AddressReuseReport(&lnrpc.AddressReuseReportRequest{})
+	var found bool
+	for _, reused := range resp.ReusedAddresses {
+		if len(reused.Addresses) == 1 &&
+			reused.Addresses[0] == addr.Address {
+
+			require.False(ht, reused.IsOurAddress)
+			require.Equal(ht, txHashes, reused.TxHashes)
+			found = true
+		}
+	}
+	require.True(ht, found, "address reuse not reported by sender")
+}
@@ -0,0 +1,91 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// testAsyncPayment tests that a payment to an offline recipient is held by the
+// sender, and sent once the recipient comes back online and receives the
+// message about it that its LSP held in the meantime.
+func testAsyncPayment(ht *lntest.HarnessTest) {
+	args := []string{
+		"--protocol.onion-messages",
+		"--protocol.async-payments",
+	}
+
+	// Carol pays Erin, whose LSP is Dave.
+	carol := ht.NewNode("Carol", args)
+	dave := ht.NewNode("Dave", args)
+	erin := ht.NewNode("Erin", args)
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, carol)
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, dave)
+	ht.EnsureConnected(carol, dave)
+	ht.EnsureConnected(dave, erin)
+
+	const chanAmt = 1_000_000
+	chanPointCarol := ht.OpenChannel(
+		carol, dave, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	chanPointDave := ht.OpenChannel(
+		dave, erin, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	ht.AssertTopologyChannelOpen(carol, chanPointDave)
+
+	preimage := ht.Random32Bytes()
+	invoice := erin.RPC.AddInvoice(&lnrpc.Invoice{
+		RPreimage: preimage,
+		Value:     10_000,
+	})
+
+	// Erin goes offline before the payment is held. Dave needs to notice,
+	// so that he holds the message for her instead of sending it.
+	restartErin := ht.SuspendNode(erin)
+	ht.AssertPeerNotConnected(dave, erin)
+
+	resp := carol.RPC.SendAsyncPayment(&routerrpc.SendAsyncPaymentRequest{
+		Payment: &routerrpc.SendPaymentRequest{
+			PaymentRequest: invoice.PaymentRequest,
+			TimeoutSeconds: 60,
+			FeeLimitMsat:   noFeeLimitMsat,
+		},
+		RecipientPath: buildMessagePath(ht, dave, erin),
+	})
+	require.Equal(ht, invoice.RHash, resp.PaymentHash)
+
+	// The payment is held until Erin releases it.
+	ht.AssertNumPayments(carol, 0)
+
+	// Once Erin is back, Dave delivers the message to her, and the
+	// payment is sent when she answers it.
+	require.NoError(ht, restartErin())
+	ht.EnsureConnected(dave, erin)
+
+	ht.AssertInvoiceSettled(erin, invoice.PaymentAddr)
+	ht.AssertPaymentStatus(
+		carol, lntypes.Preimage(preimage), lnrpc.Payment_SUCCEEDED,
+	)
+
+	// A node without async payments can't hold payments.
+	_, err := ht.Alice.RPC.Router.SendAsyncPayment(
+		ht.Context(), &routerrpc.SendAsyncPaymentRequest{
+			Payment: &routerrpc.SendPaymentRequest{
+				PaymentRequest: invoice.PaymentRequest,
+				TimeoutSeconds: 60,
+			},
+			RecipientPath: buildMessagePath(ht, dave, erin),
+		},
+	)
+	require.ErrorContains(ht, err, "async payments are not enabled")
+
+	ht.CloseChannel(carol, chanPointCarol)
+	ht.CloseChannel(dave, chanPointDave)
+
+	ht.Shutdown(carol)
+	ht.Shutdown(dave)
+	ht.Shutdown(erin)
+}
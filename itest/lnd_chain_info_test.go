@@ -0,0 +1,50 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testGetChainInfo tests that the GetChainInfo RPC reports the sync state of
+// the node and its chain backend, and that it follows newly mined blocks.
+func testGetChainInfo(ht *lntest.HarnessTest) {
+	alice := ht.Alice
+
+	ht.WaitForBlockchainSync(alice)
+
+	info := alice.RPC.GetChainInfo()
+	require.NotEmpty(ht, info.Backend)
+	require.True(ht, info.SyncedToChain)
+	require.Zero(ht, info.BlockLag)
+	require.NotZero(ht, info.BestBlockTimestamp)
+
+	bestHash, bestHeight := ht.Miner.GetBestBlock()
+	require.EqualValues(ht, bestHeight, info.BlockHeight)
+	require.Equal(ht, bestHash.String(), info.BlockHash)
+	require.GreaterOrEqual(ht, info.HeaderHeight, info.BlockHeight)
+
+	// Mine a few blocks and wait for Alice to report the new best block.
+	const numBlocks = 3
+	ht.MineEmptyBlocks(numBlocks)
+	ht.WaitForBlockchainSync(alice)
+
+	err := wait.NoError(func() error {
+		newInfo := alice.RPC.GetChainInfo()
+		if newInfo.BlockHeight != info.BlockHeight+numBlocks {
+			return fmt.Errorf("expected height %v, got %v",
+				info.BlockHeight+numBlocks,
+				newInfo.BlockHeight)
+		}
+
+		if newInfo.BlockLag != 0 {
+			return fmt.Errorf("expected no block lag, got %v",
+				newInfo.BlockLag)
+		}
+
+		return nil
+	}, wait.DefaultTimeout)
+	require.NoError(ht, err, "chain info not updated")
+}
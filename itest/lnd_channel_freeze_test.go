@@ -0,0 +1,87 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testFreezeChannel tests that a channel in maintenance mode is announced as
+// disabled and isn't used for payments until it's unfrozen again.
+func testFreezeChannel(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+	ht.EnsureConnected(alice, bob)
+
+	chanAmt := btcutil.Amount(100000)
+	chanPoint := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(alice, chanPoint)
+
+	// assertEdgeDisabled ensures that Alice announced her side of the
+	// channel with the given disabled state.
+	assertEdgeDisabled := func(disabled bool) {
+		err := wait.NoError(func() error {
+			edge := ht.AssertNumEdges(alice, 1, true)[0]
+
+			policy := edge.Node2Policy
+			if alice.PubKeyStr == edge.Node1Pub {
+				policy = edge.Node1Policy
+			}
+			if disabled != policy.Disabled {
+				return fmt.Errorf("expected policy.Disabled "+
+					"to be %v", disabled)
+			}
+
+			return nil
+		}, defaultTimeout)
+		require.NoError(ht, err, "assert edge disabled timeout")
+	}
+
+	// sendPayment pays a new invoice of Bob and asserts its final status.
+	sendPayment := func(status lnrpc.Payment_PaymentStatus) {
+		invoice := bob.RPC.AddInvoice(&lnrpc.Invoice{Value: 1000})
+		req := &routerrpc.SendPaymentRequest{
+			PaymentRequest: invoice.PaymentRequest,
+			TimeoutSeconds: 60,
+			FeeLimitMsat:   noFeeLimitMsat,
+		}
+		ht.SendPaymentAndAssertStatus(alice, req, status)
+	}
+
+	assertEdgeDisabled(false)
+	sendPayment(lnrpc.Payment_SUCCEEDED)
+
+	// Once Alice froze the channel, it is announced as disabled and can't
+	// be used to pay Bob.
+	alice.RPC.FreezeChannel(&routerrpc.FreezeChannelRequest{
+		ChanPoint: chanPoint,
+	})
+	assertEdgeDisabled(true)
+	sendPayment(lnrpc.Payment_FAILED)
+
+	// Forget about the failed attempt, so it doesn't keep Alice from
+	// using the channel once it's unfrozen.
+	alice.RPC.ResetMissionControl()
+
+	// Unfreezing the channel makes it usable again.
+	alice.RPC.UnfreezeChannel(&routerrpc.UnfreezeChannelRequest{
+		ChanPoint: chanPoint,
+	})
+	assertEdgeDisabled(false)
+	sendPayment(lnrpc.Payment_SUCCEEDED)
+
+	// A channel frozen for a duration is unfrozen automatically.
+	alice.RPC.FreezeChannel(&routerrpc.FreezeChannelRequest{
+		ChanPoint:       chanPoint,
+		DurationSeconds: 1,
+	})
+	assertEdgeDisabled(true)
+	assertEdgeDisabled(false)
+	sendPayment(lnrpc.Payment_SUCCEEDED)
+}
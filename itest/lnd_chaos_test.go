@@ -0,0 +1,63 @@
+package itest
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// testChaosProxy tests that faults injected by a chaos proxy, both between two
+// nodes and between a node and its chain backend, are observed by the nodes,
+// and that the nodes recover once the faults are lifted.
+func testChaosProxy(ht *lntest.HarnessTest) {
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+
+	dave := ht.NewNode("Dave", nil)
+	defer ht.Shutdown(dave)
+
+	// Carol connects to Dave through the proxy, and opens a channel over
+	// the proxied link.
+	proxy := ht.ConnectNodesWithProxy(carol, dave)
+	require.Equal(ht, 1, proxy.NumConns())
+
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, carol)
+	chanPoint := ht.OpenChannel(
+		carol, dave, lntest.OpenChannelParams{Amt: 1_000_000},
+	)
+
+	// Payments still succeed with added latency.
+	proxy.SetLatency(500 * time.Millisecond)
+	invoice := dave.RPC.AddInvoice(&lnrpc.Invoice{Value: 1000})
+	ht.CompletePaymentRequests(carol, []string{invoice.PaymentRequest})
+	proxy.SetLatency(0)
+
+	// While the proxy is offline, the nodes are disconnected and the
+	// channel is inactive.
+	proxy.SetOffline(true)
+	ht.AssertNotConnected(carol, dave)
+	ht.AssertChannelInactive(carol, chanPoint)
+
+	// Once it's back online, Carol reconnects through the proxy, as the
+	// connection is persistent.
+	proxy.SetOffline(false)
+	ht.AssertConnected(carol, dave)
+	ht.AssertChannelActive(carol, chanPoint)
+
+	ht.CloseChannel(carol, chanPoint)
+
+	// Erin's connection to the chain backend goes through a proxy. A block
+	// mined while the backend is unreachable is picked up once it's
+	// reachable again.
+	erin, chainProxy := ht.NewNodeWithChainProxy("Erin", nil)
+	defer ht.Shutdown(erin)
+
+	chainProxy.SetOffline(true)
+	block := ht.Miner.MineBlocks(1)[0]
+	chainProxy.SetOffline(false)
+
+	ht.WaitForBlockchainSyncTo(erin, block)
+}
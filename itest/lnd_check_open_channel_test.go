@@ -0,0 +1,97 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// checkResults returns the status of the checks of the response by name.
+func checkResults(
+	resp *lnrpc.CheckOpenChannelResponse,
+) map[string]lnrpc.OpenChannelCheck_CheckStatus {
+
+	results := make(map[string]lnrpc.OpenChannelCheck_CheckStatus)
+	for _, check := range resp.Checks {
+		results[check.Name] = check.Status
+	}
+
+	return results
+}
+
+// testCheckOpenChannel tests that a channel opening is checked without
+// opening the channel, and that the checks report why a channel can't be
+// opened.
+func testCheckOpenChannel(ht *lntest.HarnessTest) {
+	const (
+		walletAmt = btcutil.Amount(500_000)
+		chanAmt   = btcutil.Amount(100_000)
+	)
+
+	// Dave is connected to Bob and has a single coin.
+	bob := ht.Bob
+	dave := ht.NewNode("Dave", nil)
+	defer ht.Shutdown(dave)
+
+	ht.FundCoins(walletAmt, dave)
+	ht.EnsureConnected(dave, bob)
+
+	req := &lnrpc.OpenChannelRequest{
+		NodePubkey:         bob.PubKey[:],
+		LocalFundingAmount: int64(chanAmt),
+		SatPerVbyte:        1,
+	}
+
+	// Dave has enough funds to open a channel to Bob.
+	resp := dave.RPC.CheckOpenChannel(req)
+	require.True(ht, resp.CanOpen)
+	require.NotEqual(
+		ht, lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE,
+		resp.CommitmentType,
+	)
+	require.Positive(ht, resp.FundingFeeSat)
+	require.GreaterOrEqual(
+		ht, resp.RequiredBalanceSat,
+		int64(chanAmt)+resp.FundingFeeSat+resp.WalletReserveSat,
+	)
+	require.EqualValues(ht, walletAmt, resp.AvailableBalanceSat)
+	for _, check := range resp.Checks {
+		require.NotEqual(
+			ht, lnrpc.OpenChannelCheck_FAIL, check.Status,
+			check.Detail,
+		)
+	}
+
+	// Nothing was opened by the check.
+	ht.AssertNumPendingOpenChannels(dave, 0)
+
+	// Spending the whole coin leaves nothing for the funding fee.
+	req.LocalFundingAmount = int64(walletAmt)
+	resp = dave.RPC.CheckOpenChannel(req)
+	require.False(ht, resp.CanOpen)
+	require.Equal(
+		ht, lnrpc.OpenChannelCheck_FAIL,
+		checkResults(resp)["wallet_balance"],
+	)
+
+	// Neither can a channel to a node Dave isn't connected to, in which
+	// case the checks that need the peer are skipped.
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+
+	req = &lnrpc.OpenChannelRequest{
+		NodePubkey:         carol.PubKey[:],
+		LocalFundingAmount: int64(chanAmt),
+	}
+	resp = dave.RPC.CheckOpenChannel(req)
+	require.False(ht, resp.CanOpen)
+
+	results := checkResults(resp)
+	require.Equal(
+		ht, lnrpc.OpenChannelCheck_FAIL, results["peer_connected"],
+	)
+	require.Equal(
+		ht, lnrpc.OpenChannelCheck_SKIP, results["wallet_balance"],
+	)
+}
@@ -0,0 +1,65 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testChannelCloseHistory tests that both parties of a cooperative close
+// record who initiated the close and why.
+func testChannelCloseHistory(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+	ht.EnsureConnected(alice, bob)
+
+	chanAmt := btcutil.Amount(100000)
+	chanPoint := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	ht.CloseChannel(alice, chanPoint)
+
+	chanPointStr := ht.OutPointFromChannelPoint(chanPoint).String()
+
+	// assertCloseReason asserts that the node lists the channel in its
+	// close history with the given initiator and reason.
+	assertCloseReason := func(hn *node.HarnessNode,
+		initiator lnrpc.Initiator, reason lnrpc.CloseReason) {
+
+		var closed *lnrpc.ChannelCloseHistoryEntry
+		err := wait.NoError(func() error {
+			resp := hn.RPC.ChannelCloseHistory(
+				&lnrpc.ChannelCloseHistoryRequest{},
+			)
+			for _, entry := range resp.Channels {
+				if entry.Channel.ChannelPoint == chanPointStr {
+					closed = entry
+					return nil
+				}
+			}
+
+			return fmt.Errorf("channel %v not in close history",
+				chanPointStr)
+		}, defaultTimeout)
+		require.NoError(ht, err, "%s close history", hn.Name())
+
+		require.Equal(ht, initiator, closed.CloseInitiator)
+		require.Equal(ht, reason, closed.Reason)
+		require.NotZero(ht, closed.Timestamp)
+	}
+
+	// Alice closed the channel on request of the user, which Bob sees as
+	// a close requested by his peer.
+	assertCloseReason(
+		alice, lnrpc.Initiator_INITIATOR_LOCAL,
+		lnrpc.CloseReason_CLOSE_REASON_USER_REQUEST,
+	)
+	assertCloseReason(
+		bob, lnrpc.Initiator_INITIATOR_REMOTE,
+		lnrpc.CloseReason_CLOSE_REASON_PEER_REQUEST,
+	)
+}
@@ -0,0 +1,51 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testCltvGuardStatus tests that the CLTV guard engages when a block storm is
+// detected, and reports the extra delta it requires.
+func testCltvGuardStatus(ht *lntest.HarnessTest) {
+	const extraDelta = 10
+
+	// The stale tip detection is disabled, so only the storm engages the
+	// guard.
+	carol := ht.NewNode("Carol", []string{
+		"--cltvguard.active",
+		"--cltvguard.storm-blocks=5",
+		"--cltvguard.storm-window=10m",
+		"--cltvguard.max-tip-age=0",
+		fmt.Sprintf("--cltvguard.extra-delta=%d", extraDelta),
+	})
+	defer ht.Shutdown(carol)
+
+	status := carol.RPC.CltvGuardStatus()
+	require.False(ht, status.Engaged)
+	require.Empty(ht, status.Conditions)
+
+	ht.MineEmptyBlocks(5)
+
+	err := wait.NoError(func() error {
+		status = carol.RPC.CltvGuardStatus()
+		if !status.Engaged {
+			return fmt.Errorf("guard not engaged after %d blocks",
+				status.RecentBlocks)
+		}
+
+		return nil
+	}, defaultTimeout)
+	require.NoError(ht, err, "cltv guard status")
+
+	require.Equal(ht, []lnrpc.CltvGuardCondition{
+		lnrpc.CltvGuardCondition_CLTV_GUARD_CONDITION_BLOCK_STORM,
+	}, status.Conditions)
+	require.EqualValues(ht, extraDelta, status.ExtraDelta)
+	require.NotZero(ht, status.Since)
+	require.GreaterOrEqual(ht, status.RecentBlocks, uint32(5))
+}
@@ -0,0 +1,52 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// testCoopCloseRbfBumpFee tests that the fee of a pending RBF cooperative
+// close can be bumped, and that the replacement closing transaction confirms.
+func testCoopCloseRbfBumpFee(ht *lntest.HarnessTest) {
+	// Automatic fee bumps are disabled, so only the manual bump replaces
+	// the closing transaction.
+	args := []string{
+		"--protocol.rbf-coop-close",
+		"--coopcloserbf.bump-interval=0",
+	}
+	alice := ht.NewNode("Alice", args)
+	defer ht.Shutdown(alice)
+	bob := ht.NewNode("Bob", args)
+	defer ht.Shutdown(bob)
+
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, alice)
+	ht.EnsureConnected(alice, bob)
+
+	chanPoint := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: 1_000_000},
+	)
+
+	_, closeTxid := ht.CloseChannelAssertPending(alice, chanPoint, false)
+
+	alice.RPC.BumpCoopCloseFee(&lnrpc.BumpCoopCloseFeeRequest{
+		ChannelPoint: chanPoint,
+		SatPerVbyte:  50,
+	})
+
+	// Once the peer signed the replacement, it replaces the original
+	// closing transaction in the mempool.
+	ht.Miner.AssertTxNotInMempool(*closeTxid)
+	replacement := ht.Miner.AssertOutpointInMempool(
+		ht.OutPointFromChannelPoint(chanPoint),
+	)
+	replacementTxid := replacement.TxHash()
+	require.NotEqual(ht, *closeTxid, replacementTxid)
+
+	block := ht.MineBlocksAndAssertNumTxes(1, 1)[0]
+	ht.Miner.AssertTxInBlock(block, &replacementTxid)
+
+	ht.AssertNumWaitingClose(alice, 0)
+	ht.AssertNumWaitingClose(bob, 0)
+}
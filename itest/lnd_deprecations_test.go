@@ -0,0 +1,61 @@
+package itest
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// testListDeprecations tests that the API version is reported and that the
+// deprecated methods and fields of the RPC services can be listed.
+func testListDeprecations(ht *lntest.HarnessTest) {
+	alice := ht.Alice
+
+	// The API version is part of GetInfo and is sent as a response header.
+	info := alice.RPC.GetInfo()
+	require.EqualValues(ht, lnrpc.APIVersion, info.ApiVersion)
+
+	ctxt, cancel := context.WithTimeout(ht.Context(), wait.DefaultTimeout)
+	defer cancel()
+
+	var header metadata.MD
+	_, err := alice.RPC.LN.GetInfo(
+		ctxt, &lnrpc.GetInfoRequest{}, grpc.Header(&header),
+	)
+	require.NoError(ht, err)
+	require.Equal(
+		ht, []string{strconv.Itoa(lnrpc.APIVersion)},
+		header.Get(lnrpc.APIVersionMetadataKey),
+	)
+
+	// Deprecated methods and methods with deprecated fields are listed.
+	resp := alice.RPC.ListDeprecations(&lnrpc.ListDeprecationsRequest{
+		MethodPrefix: "/lnrpc.Lightning/",
+	})
+
+	methods := make(map[string]*lnrpc.MethodDeprecation)
+	for _, method := range resp.Methods {
+		methods[method.Method] = method
+	}
+
+	sendPayment, ok := methods["/lnrpc.Lightning/SendPayment"]
+	require.True(ht, ok, "SendPayment not listed")
+	require.True(ht, sendPayment.Deprecated)
+
+	getInfo, ok := methods["/lnrpc.Lightning/GetInfo"]
+	require.True(ht, ok, "GetInfo not listed")
+	require.False(ht, getInfo.Deprecated)
+	require.Contains(
+		ht, getInfo.ResponseFields, "lnrpc.GetInfoResponse.testnet",
+	)
+
+	// Without a prefix the methods of the sub-servers are listed too.
+	resp = alice.RPC.ListDeprecations(&lnrpc.ListDeprecationsRequest{})
+	require.Greater(ht, len(resp.Methods), len(methods))
+}
@@ -0,0 +1,69 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/lightningnetwork/lnd/lntest/port"
+	"github.com/stretchr/testify/require"
+)
+
+// testDiagnoseConnection tests that the stage a connection attempt fails in
+// is reported, and that a diagnosis doesn't leave a connection behind.
+func testDiagnoseConnection(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+
+	// diagnose diagnoses the connection to the node at the given address
+	// and returns the result of the single address.
+	diagnose := func(pubKey, addr string) *lnrpc.AddressDiagnosis {
+		resp := alice.RPC.DiagnoseConnection(
+			&lnrpc.DiagnoseConnectionRequest{
+				PubKey:      pubKey,
+				Address:     addr,
+				TimeoutSecs: 5,
+			},
+		)
+		require.Equal(ht, pubKey, resp.PubKey)
+		require.Len(ht, resp.Addresses, 1)
+
+		return resp.Addresses[0]
+	}
+
+	// Carol is reachable, and the diagnosis doesn't connect Alice to her.
+	diag := diagnose(carol.PubKeyStr, carol.Cfg.P2PAddr())
+	require.True(ht, diag.Reachable, diag.Detail)
+	require.Empty(ht, diag.FailedStage)
+	require.Equal(ht, "clearnet", diag.Transport)
+	ht.AssertNotConnected(alice, carol)
+
+	// The handshake fails if the address belongs to another node.
+	diag = diagnose(bob.PubKeyStr, carol.Cfg.P2PAddr())
+	require.False(ht, diag.Reachable)
+	require.Equal(ht, "act_two", diag.FailedStage)
+
+	// Nothing listens on an unused port.
+	unused := fmt.Sprintf(node.ListenerFormat, port.NextAvailablePort())
+	diag = diagnose(carol.PubKeyStr, unused)
+	require.False(ht, diag.Reachable)
+	require.Equal(ht, "connect", diag.FailedStage)
+
+	// An invalid address can't be resolved.
+	diag = diagnose(carol.PubKeyStr, "invalid:address")
+	require.Equal(ht, "resolve", diag.FailedStage)
+
+	// If Alice is already connected, only the TCP connection is tried, so
+	// the existing connection stays in place.
+	ht.EnsureConnected(alice, carol)
+	resp := alice.RPC.DiagnoseConnection(&lnrpc.DiagnoseConnectionRequest{
+		PubKey:  carol.PubKeyStr,
+		Address: carol.Cfg.P2PAddr(),
+	})
+	require.True(ht, resp.Connected)
+	require.True(ht, resp.Addresses[0].Reachable)
+	ht.AssertConnected(alice, carol)
+}
@@ -87,6 +87,14 @@ func testEtcdFailoverCase(ht *lntest.HarnessTest, kill bool) {
 	info1 := carol1.RPC.GetInfo()
 	ht.ConnectNodes(carol1, alice)
 
+	// Carol-1 fenced the databases with the token of its term when it was
+	// elected.
+	state1 := carol1.RPC.GetLeadershipState()
+	require.Equal(ht, "Carol-1", state1.Id)
+	require.Equal(ht, "Carol-1", state1.Leader)
+	require.True(ht, state1.IsLeader)
+	require.NotZero(ht, state1.FencingToken)
+
 	// Open a channel with 100k satoshis between Carol and Alice with Alice
 	// being the sole funder of the channel.
 	chanAmt := btcutil.Amount(100_000)
@@ -120,6 +128,13 @@ func testEtcdFailoverCase(ht *lntest.HarnessTest, kill bool) {
 	err = carol2.Unlock(req)
 	require.NoError(ht, err, "Unlocking Carol-2 failed")
 
+	// Carol-2 fenced the databases with a newer token, so any write of
+	// Carol-1 that is still in flight is rejected.
+	state2 := carol2.RPC.GetLeadershipState()
+	require.Equal(ht, "Carol-2", state2.Leader)
+	require.True(ht, state2.IsLeader)
+	require.Greater(ht, state2.FencingToken, state1.FencingToken)
+
 	// Make sure Carol-1 and Carol-2 have the same identity.
 	info2 := carol2.RPC.GetInfo()
 	require.Equal(ht, info1.IdentityPubkey, info2.IdentityPubkey,
@@ -0,0 +1,110 @@
+package itest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ledgerEntry is an entry of a ledger exported as JSON.
+type ledgerEntry struct {
+	Category  string `json:"category"`
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount_msat"`
+	Fee       int64  `json:"fee_msat"`
+}
+
+// testExportLedger tests that a payment shows up in the exported ledgers of
+// both its sender and its receiver, and that the ledger can be paged through.
+func testExportLedger(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+	ht.EnsureConnected(alice, bob)
+
+	start := time.Now().Add(-time.Second)
+
+	chanPoint := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: 100_000},
+	)
+	defer ht.CloseChannel(alice, chanPoint)
+
+	const amt = btcutil.Amount(10_000)
+	invoice := bob.RPC.AddInvoice(&lnrpc.Invoice{
+		Value: int64(amt),
+		Memo:  "ledger",
+	})
+	ht.CompletePaymentRequests(alice, []string{invoice.PaymentRequest})
+
+	// Entries are timestamped in seconds, so the range ends after the
+	// payment.
+	end := time.Now().Add(time.Second)
+
+	exportLedger := func(req *lnrpc.ExportLedgerRequest) []ledgerEntry {
+		req.StartTime = uint64(start.Unix())
+		req.EndTime = uint64(end.Unix())
+		req.Format = lnrpc.LedgerFormat_LEDGER_FORMAT_JSON
+
+		var entries []ledgerEntry
+		resp := alice.RPC.ExportLedger(req)
+		require.NoError(ht, json.Unmarshal(resp.Ledger, &entries))
+		require.Len(ht, entries, int(resp.NumEntries))
+
+		return entries
+	}
+
+	// Alice's ledger contains the channel funding and the payment.
+	entries := exportLedger(&lnrpc.ExportLedgerRequest{})
+	payHash := hex.EncodeToString(invoice.RHash)
+
+	var payment *ledgerEntry
+	for i, entry := range entries {
+		if entry.Category == "payment" && entry.Reference == payHash {
+			payment = &entries[i]
+		}
+	}
+	require.NotNil(ht, payment, "payment not in ledger")
+	require.EqualValues(ht, -int64(amt)*1000, payment.Amount)
+	require.Zero(ht, payment.Fee)
+
+	// Exporting the ledger in pages of one entry returns the same
+	// entries.
+	var paged []ledgerEntry
+	for offset := 0; offset < len(entries); offset++ {
+		page := exportLedger(&lnrpc.ExportLedgerRequest{
+			Offset:     uint64(offset),
+			MaxEntries: 1,
+		})
+		require.Len(ht, page, 1)
+		paged = append(paged, page...)
+	}
+	require.Equal(ht, entries, paged)
+
+	// Bob's ledger contains the settled invoice.
+	resp := bob.RPC.ExportLedger(&lnrpc.ExportLedgerRequest{
+		StartTime: uint64(start.Unix()),
+		EndTime:   uint64(end.Unix()),
+		Format:    lnrpc.LedgerFormat_LEDGER_FORMAT_JSON,
+	})
+	var bobEntries []ledgerEntry
+	require.NoError(ht, json.Unmarshal(resp.Ledger, &bobEntries))
+	require.Contains(ht, bobEntries, ledgerEntry{
+		Category:  "invoice",
+		Reference: payHash,
+		Amount:    int64(amt) * 1000,
+	})
+
+	// An empty range is rejected.
+	_, err := alice.RPC.LN.ExportLedger(
+		ht.Context(), &lnrpc.ExportLedgerRequest{
+			StartTime: uint64(end.Unix()),
+			EndTime:   uint64(start.Unix()),
+		},
+	)
+	require.Equal(ht, codes.InvalidArgument, status.Code(err))
+}
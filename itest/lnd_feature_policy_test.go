@@ -0,0 +1,67 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testFeaturePolicy tests that peers that don't signal a feature required by
+// the feature policy are disconnected and listed as rejected.
+func testFeaturePolicy(ht *lntest.HarnessTest) {
+	// An odd custom feature that only Eve signals.
+	const customFeature = 1001
+
+	carol := ht.NewNode("Carol", []string{
+		fmt.Sprintf("--featurepolicy.require=%d", customFeature),
+	})
+	defer ht.Shutdown(carol)
+
+	dave := ht.NewNode("Dave", nil)
+	defer ht.Shutdown(dave)
+
+	eve := ht.NewNode("Eve", []string{
+		fmt.Sprintf("--protocol.custom-init=%d", customFeature),
+	})
+	defer ht.Shutdown(eve)
+
+	// No peers were rejected yet.
+	require.Empty(ht, carol.RPC.ListRejectedPeers().Peers)
+
+	// Dave doesn't signal the feature, so Carol disconnects him during
+	// init. Depending on timing the connection attempt may or may not
+	// return an error, so we only check the rejection.
+	_, _ = dave.RPC.LN.ConnectPeer(
+		ht.Context(), &lnrpc.ConnectPeerRequest{
+			Addr: &lnrpc.LightningAddress{
+				Pubkey: carol.PubKeyStr,
+				Host:   carol.Cfg.P2PAddr(),
+			},
+		},
+	)
+
+	err := wait.NoError(func() error {
+		peers := carol.RPC.ListRejectedPeers().Peers
+		if len(peers) != 1 {
+			return fmt.Errorf("expected 1 rejected peer, got %d",
+				len(peers))
+		}
+
+		return nil
+	}, defaultTimeout)
+	require.NoError(ht, err, "dave not rejected")
+
+	rejected := carol.RPC.ListRejectedPeers().Peers[0]
+	require.Equal(ht, dave.PubKeyStr, rejected.PubKey)
+	require.Contains(ht, rejected.Reason, "required feature")
+	require.EqualValues(ht, 1, rejected.Count)
+	require.Positive(ht, rejected.LastRejected)
+	ht.AssertNotConnected(carol, dave)
+
+	// Eve signals the feature and is accepted.
+	ht.ConnectNodes(eve, carol)
+	require.Len(ht, carol.RPC.ListRejectedPeers().Peers, 1)
+}
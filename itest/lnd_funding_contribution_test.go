@@ -0,0 +1,215 @@
+package itest
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testFundingContribution tests that a channel can be funded with a PSBT that
+// is assembled from the inputs of several contributors.
+func testFundingContribution(ht *lntest.HarnessTest) {
+	const chanSize = btcutil.Amount(1_000_000)
+
+	alice, bob := ht.Alice, ht.Bob
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+	dave := ht.NewNode("Dave", nil)
+	defer ht.Shutdown(dave)
+	ht.EnsureConnected(carol, dave)
+
+	// Carol opens a channel to Dave that is funded through a PSBT. The
+	// returned PSBT only contains the channel funding output.
+	pendingChanID := ht.Random32Bytes()
+	chanUpdates, basePsbt := ht.OpenChannelPsbt(
+		carol, dave, lntest.OpenChannelParams{
+			Amt: chanSize,
+			FundingShim: &lnrpc.FundingShim{
+				Shim: &lnrpc.FundingShim_PsbtShim{
+					PsbtShim: &lnrpc.PsbtShim{
+						PendingChanId: pendingChanID,
+					},
+				},
+			},
+		},
+	)
+	base, err := psbt.NewFromRawBytes(bytes.NewReader(basePsbt), false)
+	require.NoError(ht, err)
+	require.Len(ht, base.UnsignedTx.TxOut, 1)
+	fundingOut := base.UnsignedTx.TxOut[0]
+
+	// Alice and Bob are registered as the contributors of the channel.
+	contributors := map[string]*node.HarnessNode{
+		"alice": alice,
+		"bob":   bob,
+	}
+	carol.RPC.RegisterFundingContributors(
+		&lnrpc.RegisterFundingContributorsRequest{
+			PendingChanId:  pendingChanID,
+			BasePsbt:       basePsbt,
+			ContributorIds: []string{"alice", "bob"},
+			TimeoutSecs:    600,
+		},
+	)
+
+	// Contributions from unknown contributors are rejected.
+	_, err = carol.RPC.LN.ContributeFunding(
+		ht.Context(), &lnrpc.ContributeFundingRequest{
+			PendingChanId: pendingChanID,
+			ContributorId: "carol",
+			Psbt:          basePsbt,
+		},
+	)
+	require.Equal(ht, codes.InvalidArgument, status.Code(err))
+
+	// Each contributor funds half of the channel and adds its inputs and
+	// change outputs.
+	for id, hn := range contributors {
+		contribution := fundContribution(
+			ht, hn, &wire.TxOut{
+				Value:    fundingOut.Value / 2,
+				PkScript: fundingOut.PkScript,
+			},
+		)
+		carol.RPC.ContributeFunding(&lnrpc.ContributeFundingRequest{
+			PendingChanId: pendingChanID,
+			ContributorId: id,
+			Psbt:          contribution,
+		})
+	}
+
+	// With all inputs added, the funded PSBT is available for signing.
+	resp := carol.RPC.FundingContributionStatus(
+		&lnrpc.FundingContributionStatusRequest{
+			PendingChanId: pendingChanID,
+		},
+	)
+	require.Len(ht, resp.Contributors, 2)
+	for _, contributor := range resp.Contributors {
+		require.Equal(
+			ht, lnrpc.FundingContributor_FUNDED, contributor.State,
+		)
+		require.NotEmpty(ht, contributor.Inputs)
+	}
+	require.NotEmpty(ht, resp.FundedPsbt)
+
+	// Once both contributors signed their inputs, the funding
+	// transaction is published.
+	for id, hn := range contributors {
+		carol.RPC.SignFundingContribution(
+			&lnrpc.SignFundingContributionRequest{
+				PendingChanId: pendingChanID,
+				ContributorId: id,
+				SignedPsbt: signContribution(
+					ht, hn, resp.FundedPsbt,
+				),
+			},
+		)
+	}
+
+	updateResp := ht.ReceiveOpenChannelUpdate(chanUpdates)
+	upd, ok := updateResp.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
+	require.True(ht, ok)
+	chanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: upd.ChanPending.Txid,
+		},
+		OutputIndex: upd.ChanPending.OutputIndex,
+	}
+
+	ht.MineBlocksAndAssertNumTxes(6, 1)
+	ht.AssertTopologyChannelOpen(carol, chanPoint)
+
+	// The finalized funding transaction isn't tracked anymore.
+	_, err = carol.RPC.LN.FundingContributionStatus(
+		ht.Context(), &lnrpc.FundingContributionStatusRequest{
+			PendingChanId: pendingChanID,
+		},
+	)
+	require.Equal(ht, codes.NotFound, status.Code(err))
+
+	ht.CloseChannel(carol, chanPoint)
+}
+
+// fundContribution lets the wallet of the given node fund the given share of
+// the channel funding output and returns the resulting inputs and change
+// outputs as a serialized PSBT.
+func fundContribution(ht *lntest.HarnessTest, hn *node.HarnessNode,
+	share *wire.TxOut) []byte {
+
+	template, err := psbt.New(nil, []*wire.TxOut{share}, 2, 0, nil)
+	require.NoError(ht, err)
+
+	var b bytes.Buffer
+	require.NoError(ht, template.Serialize(&b))
+
+	fundResp := hn.RPC.FundPsbt(&walletrpc.FundPsbtRequest{
+		Template: &walletrpc.FundPsbtRequest_Psbt{
+			Psbt: b.Bytes(),
+		},
+		Fees: &walletrpc.FundPsbtRequest_SatPerVbyte{
+			SatPerVbyte: 2,
+		},
+	})
+	packet, err := psbt.NewFromRawBytes(
+		bytes.NewReader(fundResp.FundedPsbt), false,
+	)
+	require.NoError(ht, err)
+
+	// The share of the funding output is already part of the base PSBT,
+	// so only the inputs and change outputs are contributed.
+	tx := packet.UnsignedTx
+	for i, txOut := range tx.TxOut {
+		if !bytes.Equal(txOut.PkScript, share.PkScript) {
+			continue
+		}
+
+		tx.TxOut = append(tx.TxOut[:i], tx.TxOut[i+1:]...)
+		packet.Outputs = append(
+			packet.Outputs[:i], packet.Outputs[i+1:]...,
+		)
+
+		break
+	}
+
+	b.Reset()
+	require.NoError(ht, packet.Serialize(&b))
+
+	return b.Bytes()
+}
+
+// signContribution lets the wallet of the given node sign and finalize its
+// inputs of the funded PSBT.
+func signContribution(ht *lntest.HarnessTest, hn *node.HarnessNode,
+	fundedPsbt []byte) []byte {
+
+	signResp := hn.RPC.SignPsbt(&walletrpc.SignPsbtRequest{
+		FundedPsbt: fundedPsbt,
+	})
+	require.NotEmpty(ht, signResp.SignedInputs)
+
+	packet, err := psbt.NewFromRawBytes(
+		bytes.NewReader(signResp.SignedPsbt), false,
+	)
+	require.NoError(ht, err)
+
+	for _, idx := range signResp.SignedInputs {
+		finalized, err := psbt.MaybeFinalize(packet, int(idx))
+		require.NoError(ht, err)
+		require.True(ht, finalized)
+	}
+
+	var b bytes.Buffer
+	require.NoError(ht, packet.Serialize(&b))
+
+	return b.Bytes()
+}
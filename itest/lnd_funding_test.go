@@ -3,6 +3,7 @@ package itest
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -883,9 +884,15 @@ func testChannelFundingPersistence(ht *lntest.HarnessTest) {
 	tx := ht.AssertTxAtHeight(alice, height, fundingTxID)
 
 	// At this stage, we expect the transaction to be labelled, but not with
-	// our channel ID because our transaction has not yet confirmed.
-	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
-	require.Equal(ht, label, tx.Label, "open channel label wrong")
+	// our channel ID because our transaction has not yet confirmed. The
+	// label ends with the alias of the peer if it is known by then.
+	chanPoint := lntest.ChanPointFromPendingUpdate(update)
+	fundingOutPoint := ht.OutPointFromChannelPoint(chanPoint)
+	label := labels.MakeChannelLabel(
+		labels.LabelTypeChannelOpen, nil, fundingOutPoint, "",
+	)
+	require.True(ht, strings.HasPrefix(tx.Label, label),
+		"open channel label wrong: %v", tx.Label)
 
 	// Both nodes should still show a single channel as pending.
 	ht.AssertNumPendingOpenChannels(alice, 1)
@@ -899,10 +906,6 @@ func testChannelFundingPersistence(ht *lntest.HarnessTest) {
 	ht.AssertNumPendingOpenChannels(alice, 0)
 	ht.AssertNumPendingOpenChannels(carol, 0)
 
-	// The channel should be listed in the peer information returned by
-	// both peers.
-	chanPoint := lntest.ChanPointFromPendingUpdate(update)
-
 	// Re-lookup our transaction in the block that it confirmed in.
 	tx = ht.AssertTxAtHeight(alice, height, fundingTxID)
 
@@ -918,8 +921,11 @@ func testChannelFundingPersistence(ht *lntest.HarnessTest) {
 	// Create an additional check for our channel assertion that will
 	// check that our label is as expected.
 	shortChanID := lnwire.NewShortChanIDFromInt(chanAlice.ChanId)
-	label = labels.MakeLabel(labels.LabelTypeChannelOpen, &shortChanID)
-	require.Equal(ht, label, tx.Label, "open channel label not updated")
+	label = labels.MakeChannelLabel(
+		labels.LabelTypeChannelOpen, &shortChanID, fundingOutPoint, "",
+	)
+	require.True(ht, strings.HasPrefix(tx.Label, label),
+		"open channel label not updated: %v", tx.Label)
 
 	// Finally, immediately close the channel. This function will also
 	// block until the channel is closed and will additionally assert the
@@ -0,0 +1,86 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testHoldFee tests that forwards are checked against the hold fee policy in
+// dry-run mode without being failed, and that hypothetical forwards can be
+// evaluated while the policy isn't active.
+func testHoldFee(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+
+	// Without the hold fee policy, there's no status to report, but
+	// forwards can still be simulated with the configured parameters.
+	_, err := alice.RPC.LN.HoldFeeStatus(
+		ht.Context(), &lnrpc.HoldFeeStatusRequest{},
+	)
+	require.Equal(ht, codes.FailedPrecondition, status.Code(err))
+
+	sim := alice.RPC.SimulateHoldFee(&lnrpc.SimulateHoldFeeRequest{
+		Htlcs: []*lnrpc.HoldFeeHtlc{{
+			AmtMsat:      1_000_000,
+			CltvDistance: 1000,
+		}},
+	})
+	require.Len(ht, sim.Decisions, 1)
+	require.Equal(
+		ht, lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_INSUFFICIENT_FEE,
+		sim.Decisions[0].Outcome,
+	)
+	require.NotZero(ht, sim.Decisions[0].SurchargeMsat)
+
+	// Carol forwards from Alice to Bob and requires a surcharge for any
+	// forward, but only records her decisions.
+	carol := ht.NewNode("Carol", []string{
+		"--holdfee.active",
+		"--holdfee.dry-run",
+		"--holdfee.free-blocks=0",
+		"--holdfee.rate-per-block=100",
+	})
+	defer ht.Shutdown(carol)
+
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, carol)
+	ht.EnsureConnected(alice, carol)
+	ht.EnsureConnected(carol, bob)
+
+	chanAmt := btcutil.Amount(100_000)
+	chanPointAC := ht.OpenChannel(
+		alice, carol, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(alice, chanPointAC)
+
+	chanPointCB := ht.OpenChannel(
+		carol, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(carol, chanPointCB)
+
+	ht.AssertTopologyChannelOpen(alice, chanPointCB)
+
+	// The payment doesn't pay the surcharge, but succeeds as the policy
+	// isn't enforced.
+	invoice := bob.RPC.AddInvoice(&lnrpc.Invoice{Value: 10_000})
+	ht.CompletePaymentRequests(alice, []string{invoice.PaymentRequest})
+
+	resp := carol.RPC.HoldFeeStatus()
+	require.True(ht, resp.DryRun)
+	require.EqualValues(ht, 1, resp.Checked)
+	require.EqualValues(ht, 1, resp.Surcharged)
+	require.EqualValues(ht, 1, resp.InsufficientFee)
+	require.Zero(ht, resp.CollectedSurchargeMsat)
+	require.Len(ht, resp.RecentDecisions, 1)
+
+	decision := resp.RecentDecisions[0]
+	require.Equal(
+		ht, lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_INSUFFICIENT_FEE,
+		decision.Outcome,
+	)
+	require.False(ht, decision.Enforced)
+	require.EqualValues(ht, 10_000_000, decision.AmtMsat)
+	require.NotZero(ht, decision.CltvDistance)
+}
@@ -0,0 +1,58 @@
+package itest
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testInitRecords tests that custom TLV records registered by one node are
+// sent in its init message and can be read by its peers.
+func testInitRecords(ht *lntest.HarnessTest) {
+	const recordType = 65537
+	value := []byte{1, 2, 3}
+
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+	dave := ht.NewNode("Dave", nil)
+	defer ht.Shutdown(dave)
+
+	// Even types can't be registered, as peers must disconnect on unknown
+	// even types.
+	_, err := carol.RPC.Peer.RegisterInitRecord(
+		ht.Context(), &peersrpc.RegisterInitRecordRequest{
+			Type:  recordType + 1,
+			Value: value,
+		},
+	)
+	require.Equal(ht, codes.InvalidArgument, status.Code(err))
+
+	carol.RPC.RegisterInitRecord(&peersrpc.RegisterInitRecordRequest{
+		Type:  recordType,
+		Value: value,
+	})
+	records := carol.RPC.ListInitRecords().Records
+	require.Equal(ht, map[uint64][]byte{recordType: value}, records)
+
+	// Dave receives the record in the init message of Carol.
+	ht.ConnectNodes(carol, dave)
+	records = dave.RPC.PeerInitRecords(&peersrpc.PeerInitRecordsRequest{
+		PubKey: carol.PubKey[:],
+	}).Records
+	require.Equal(ht, value, records[recordType])
+
+	// Once removed, the record isn't sent on new connections.
+	carol.RPC.UnregisterInitRecord(&peersrpc.UnregisterInitRecordRequest{
+		Type: recordType,
+	})
+	require.Empty(ht, carol.RPC.ListInitRecords().Records)
+
+	ht.DisconnectNodes(carol, dave)
+	ht.ConnectNodes(carol, dave)
+	records = dave.RPC.PeerInitRecords(&peersrpc.PeerInitRecordsRequest{
+		PubKey: carol.PubKey[:],
+	}).Records
+	require.NotContains(ht, records, uint64(recordType))
+}
@@ -0,0 +1,118 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testJammingIncidents tests that htlcs held on a forwarding node are reported
+// as a slot jamming incident, which is resolved once the htlcs are released.
+func testJammingIncidents(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+
+	// Without the jamming detector, the RPC is rejected.
+	_, err := bob.RPC.LN.ListJammingIncidents(
+		ht.Context(), &lnrpc.ListJammingIncidentsRequest{},
+	)
+	require.Equal(ht, codes.FailedPrecondition, status.Code(err))
+
+	// Carol forwards from Alice to Bob and reports two small htlcs that
+	// are held for a second as slot jamming.
+	carol := ht.NewNode("Carol", []string{
+		"--jamdetect.active",
+		"--jamdetect.slot-threshold=2",
+		"--jamdetect.small-amount-msat=100000000",
+		"--jamdetect.min-hold-time=1s",
+		"--jamdetect.interval=1s",
+	})
+	defer ht.Shutdown(carol)
+
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, carol)
+	ht.EnsureConnected(alice, carol)
+	ht.EnsureConnected(carol, bob)
+
+	chanAmt := btcutil.Amount(300_000)
+	chanPointAC := ht.OpenChannel(
+		alice, carol, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(alice, chanPointAC)
+
+	chanPointCB := ht.OpenChannel(
+		carol, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(carol, chanPointCB)
+
+	ht.AssertTopologyChannelOpen(alice, chanPointCB)
+
+	// Alice pays two hold invoices of Bob, which hold their htlcs on
+	// Carol until they are canceled.
+	hashes := make([]lntypes.Hash, 2)
+	for i := range hashes {
+		preimage := lntypes.Preimage(ht.Random32Bytes())
+		hashes[i] = preimage.Hash()
+		invoice := bob.RPC.AddHoldInvoice(
+			&invoicesrpc.AddHoldInvoiceRequest{
+				Value: 10_000,
+				Hash:  hashes[i][:],
+			},
+		)
+		stream := bob.RPC.SubscribeSingleInvoice(hashes[i][:])
+
+		alice.RPC.SendPayment(&routerrpc.SendPaymentRequest{
+			PaymentRequest: invoice.PaymentRequest,
+			TimeoutSeconds: 60,
+			FeeLimitMsat:   noFeeLimitMsat,
+		})
+		ht.AssertInvoiceState(stream, lnrpc.Invoice_ACCEPTED)
+	}
+
+	// assertIncident waits until Carol reports a single incident with
+	// the given resolved state.
+	assertIncident := func(resolved bool) *lnrpc.JammingIncident {
+		var incident *lnrpc.JammingIncident
+		err := wait.NoError(func() error {
+			incidents := carol.RPC.ListJammingIncidents().Incidents
+			if len(incidents) != 1 {
+				return fmt.Errorf("expected 1 incident, got %d",
+					len(incidents))
+			}
+
+			incident = incidents[0]
+			if incident.Resolved != resolved {
+				return fmt.Errorf("expected resolved=%v",
+					resolved)
+			}
+
+			return nil
+		}, defaultTimeout)
+		require.NoError(ht, err, "jamming incidents")
+
+		return incident
+	}
+
+	slotJamming := lnrpc.JammingIncidentType_JAMMING_INCIDENT_TYPE_SLOT_JAMMING //nolint:lll
+	incident := assertIncident(false)
+	require.Equal(ht, slotJamming, incident.Type)
+	require.EqualValues(ht, 2, incident.NumHtlcs)
+	require.Len(ht, incident.OutgoingChanIds, 1)
+	require.Zero(ht, incident.MitigatedUntil)
+
+	// Once the htlcs are released, the incident is resolved.
+	for _, hash := range hashes {
+		bob.RPC.CancelInvoice(hash[:])
+	}
+	ht.AssertNumActiveHtlcs(carol, 0)
+
+	incident = assertIncident(true)
+	require.EqualValues(ht, 2, incident.NumHtlcs)
+}
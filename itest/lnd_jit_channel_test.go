@@ -0,0 +1,56 @@
+package itest
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// testJITChannelRegistrations tests that just-in-time channels are registered,
+// listed and cancelled through the router RPCs.
+func testJITChannelRegistrations(ht *lntest.HarnessTest) {
+	// Just-in-time channels are opened as zero-conf channels, so the LSP
+	// needs both zero-conf and scid alias support.
+	lsp := ht.NewNode("Lsp", []string{
+		"--jitchannel.active",
+		"--protocol.zero-conf",
+		"--protocol.option-scid-alias",
+	})
+	client := ht.NewNode("Client", nil)
+
+	// Register two channels for the client. Each of them gets its own
+	// short channel id.
+	req := &routerrpc.RegisterJITChannelRequest{
+		Client: client.PubKey[:],
+	}
+	first := lsp.RPC.RegisterJITChannel(req)
+	second := lsp.RPC.RegisterJITChannel(req)
+	require.NotEqual(ht, first.Scid, second.Scid)
+
+	for _, jitChan := range []*routerrpc.JITChannel{first, second} {
+		require.Equal(ht, client.PubKey[:], jitChan.Client)
+		require.Equal(
+			ht, routerrpc.JITChannelState_JIT_CHANNEL_STATE_WAITING,
+			jitChan.State,
+		)
+		require.Zero(ht, jitChan.HeldForwards)
+	}
+
+	channels := lsp.RPC.ListJITChannels().Channels
+	require.Len(ht, channels, 2)
+
+	// Once cancelled, a registration is no longer listed.
+	lsp.RPC.CancelJITChannel(&routerrpc.CancelJITChannelRequest{
+		Scid: first.Scid,
+	})
+
+	channels = lsp.RPC.ListJITChannels().Channels
+	require.Len(ht, channels, 1)
+	require.Equal(ht, second.Scid, channels[0].Scid)
+
+	// Nodes that don't open just-in-time channels reject the RPCs.
+	_, err := client.RPC.Router.ListJITChannels(
+		ht.Context(), &routerrpc.ListJITChannelsRequest{},
+	)
+	require.ErrorContains(ht, err, "just-in-time channels are not active")
+}
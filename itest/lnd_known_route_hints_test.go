@@ -0,0 +1,81 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// testKnownRouteHints tests that route hints registered out of band let a
+// node pay a private destination whose invoice lacks the hints.
+func testKnownRouteHints(ht *lntest.HarnessTest) {
+	const chanAmt = btcutil.Amount(100000)
+
+	// Create the topology Alice -> Bob -> Carol, where the channel
+	// between Bob and Carol is private.
+	alice, bob := ht.Alice, ht.Bob
+	carol := ht.NewNode("Carol", nil)
+	ht.EnsureConnected(alice, bob)
+	ht.ConnectNodes(bob, carol)
+
+	chanPointAlice := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(alice, chanPointAlice)
+
+	chanPointBob := ht.OpenChannel(
+		bob, carol, lntest.OpenChannelParams{
+			Amt:     chanAmt,
+			Private: true,
+		},
+	)
+	defer ht.CloseChannel(bob, chanPointBob)
+
+	// The invoices of Carol only contain route hints if she's asked to
+	// include them, so we take the hints from such an invoice.
+	privInvoice := carol.RPC.AddInvoice(&lnrpc.Invoice{
+		Value:   1000,
+		Private: true,
+	})
+	routeHints := carol.RPC.DecodePayReq(
+		privInvoice.PaymentRequest,
+	).RouteHints
+	require.NotEmpty(ht, routeHints)
+
+	// Without the hints, Alice can't find a route for an invoice that
+	// lacks them.
+	invoice := carol.RPC.AddInvoice(&lnrpc.Invoice{Value: 1000})
+	req := &routerrpc.SendPaymentRequest{
+		PaymentRequest: invoice.PaymentRequest,
+		TimeoutSeconds: 60,
+		FeeLimitMsat:   noFeeLimitMsat,
+	}
+	ht.SendPaymentAssertFail(
+		alice, req, lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+	)
+
+	// Once Alice knows the hints for Carol, the same invoice can be paid.
+	alice.RPC.AddKnownRouteHints(&routerrpc.AddKnownRouteHintsRequest{
+		Dest:       carol.PubKey[:],
+		RouteHints: routeHints,
+		TtlSeconds: 3600,
+	})
+
+	known := alice.RPC.ListKnownRouteHints().KnownRouteHints
+	require.Len(ht, known, 1)
+	require.Equal(ht, carol.PubKey[:], known[0].Dest)
+	require.Equal(
+		ht, routerrpc.RouteHintScope_ROUTE_HINT_SCOPE_DESTINATION,
+		known[0].Scope,
+	)
+
+	ht.SendPaymentAssertSettled(alice, req)
+
+	// Removing the hints removes them from the list.
+	alice.RPC.RemoveKnownRouteHints(&routerrpc.RemoveKnownRouteHintsRequest{
+		Dest: carol.PubKey[:],
+	})
+	require.Empty(ht, alice.RPC.ListKnownRouteHints().KnownRouteHints)
+}
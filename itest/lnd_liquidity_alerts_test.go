@@ -0,0 +1,86 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testLiquidityAlerts tests that a channel without inbound liquidity raises a
+// liquidity alert, which is resolved once the channel has enough inbound
+// liquidity.
+func testLiquidityAlerts(ht *lntest.HarnessTest) {
+	bob := ht.Bob
+
+	// Without the liquidity monitor, the RPC is rejected.
+	_, err := bob.RPC.LN.LiquidityAlerts(
+		ht.Context(), &lnrpc.LiquidityAlertsRequest{},
+	)
+	require.Equal(ht, codes.FailedPrecondition, status.Code(err))
+
+	carol := ht.NewNode("Carol", []string{
+		"--liquidityalert.active",
+		"--liquidityalert.interval=1s",
+		"--liquidityalert.min-chan-inbound-ratio=0.1",
+	})
+	defer ht.Shutdown(carol)
+
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, carol)
+	ht.EnsureConnected(carol, bob)
+
+	chanAmt := btcutil.Amount(100_000)
+	chanPoint := ht.OpenChannel(
+		carol, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(carol, chanPoint)
+
+	chanPointStr := ht.OutPointFromChannelPoint(chanPoint).String()
+
+	// assertNumAlerts waits until Carol raises the given number of
+	// alerts.
+	assertNumAlerts := func(num int) []*lnrpc.LiquidityAlert {
+		var alerts []*lnrpc.LiquidityAlert
+		err := wait.NoError(func() error {
+			alerts = carol.RPC.LiquidityAlerts().Alerts
+			if len(alerts) != num {
+				return fmt.Errorf("expected %d alerts, got %d",
+					num, len(alerts))
+			}
+
+			return nil
+		}, defaultTimeout)
+		require.NoError(ht, err, "liquidity alerts")
+
+		return alerts
+	}
+
+	// All the capacity of the new channel is on Carol's side, so it lacks
+	// inbound liquidity.
+	alert := assertNumAlerts(1)[0]
+	require.Equal(
+		ht, lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_LOW_INBOUND,
+		alert.Kind,
+	)
+	require.Equal(
+		ht, lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_CHANNEL,
+		alert.Scope,
+	)
+	require.Equal(ht, chanPointStr, alert.ChanPoint)
+	require.Equal(ht, bob.PubKeyStr, alert.Peer)
+	require.Zero(ht, alert.InboundSat)
+	require.NotZero(ht, alert.Since)
+
+	// Once Carol paid half of the capacity to Bob, the alert is resolved.
+	invoice := bob.RPC.AddInvoice(&lnrpc.Invoice{
+		Value: int64(chanAmt / 2),
+	})
+	ht.CompletePaymentRequests(carol, []string{invoice.PaymentRequest})
+
+	assertNumAlerts(0)
+}
@@ -0,0 +1,140 @@
+package itest
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/lsprpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lsps"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serveLSPS answers the LSPS0 requests the given node receives with the
+// result registered for their method, until the test context is canceled.
+func serveLSPS(ht *lntest.HarnessTest, lsp *node.HarnessNode,
+	results map[string]interface{}) {
+
+	msgClient, cancel := lsp.RPC.SubscribeCustomMessages()
+	go func() {
+		defer cancel()
+
+		for {
+			msg, err := msgClient.Recv()
+			if err != nil {
+				return
+			}
+			if msg.Type != uint32(lsps.MessageType) {
+				continue
+			}
+
+			var req struct {
+				Method string `json:"method"`
+				ID     string `json:"id"`
+			}
+			if err := json.Unmarshal(msg.Data, &req); err != nil {
+				continue
+			}
+
+			data, err := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"result":  results[req.Method],
+			})
+			if err != nil {
+				continue
+			}
+
+			// The test fails on the client side if the response
+			// doesn't make it, so the error is ignored here.
+			_, _ = lsp.RPC.LN.SendCustomMessage(
+				ht.Context(), &lnrpc.SendCustomMessageRequest{
+					Peer: msg.Peer,
+					Type: msg.Type,
+					Data: data,
+				},
+			)
+		}
+	}()
+}
+
+// testLSP tests that the LSP sub-server talks to an LSP over custom peer
+// messages, by letting Dave answer the LSPS0 requests Carol sends through it.
+func testLSP(ht *lntest.HarnessTest) {
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+
+	dave := ht.NewNode("Dave", nil)
+	defer ht.Shutdown(dave)
+
+	ht.EnsureConnected(carol, dave)
+
+	validUntil := time.Now().Add(time.Hour).UTC()
+	serveLSPS(ht, dave, map[string]interface{}{
+		"lsps0.list_protocols": map[string]interface{}{
+			"protocols": []uint16{2},
+		},
+		"lsps2.get_info": map[string]interface{}{
+			"opening_fee_params_menu": []map[string]interface{}{{
+				"min_fee_msat":             "546000",
+				"proportional":             1200,
+				"valid_until":              validUntil,
+				"min_lifetime":             1000,
+				"max_client_to_self_delay": 2016,
+				"min_payment_size_msat":    "1000",
+				"max_payment_size_msat":    "1000000000",
+				"promise":                  "promise",
+			}},
+		},
+		"lsps2.buy": map[string]interface{}{
+			"jit_channel_scid":      "1x2x3",
+			"lsp_cltv_expiry_delta": 144,
+		},
+	})
+
+	protocols := carol.RPC.LSPListProtocols(&lsprpc.ListProtocolsRequest{
+		LspPubkey: dave.PubKey[:],
+	})
+	require.Equal(ht, []uint32{2}, protocols.Protocols)
+
+	info := carol.RPC.LSPS2GetInfo(&lsprpc.LSPS2GetInfoRequest{
+		LspPubkey: dave.PubKey[:],
+	})
+	require.Len(ht, info.OpeningFeeParamsMenu, 1)
+	params := info.OpeningFeeParamsMenu[0]
+	require.EqualValues(ht, 546_000, params.MinFeeMsat)
+	require.Equal(ht, "promise", params.Promise)
+
+	// A payment size outside of the range of the params is rejected
+	// before the request is sent.
+	err := carol.RPC.LSPS2BuyAssertErr(&lsprpc.LSPS2BuyRequest{
+		LspPubkey:        dave.PubKey[:],
+		OpeningFeeParams: params,
+		PaymentSizeMsat:  1,
+	})
+	require.Equal(ht, codes.InvalidArgument, status.Code(err))
+
+	jitChannel := carol.RPC.LSPS2Buy(&lsprpc.LSPS2BuyRequest{
+		LspPubkey:        dave.PubKey[:],
+		OpeningFeeParams: params,
+		PaymentSizeMsat:  10_000_000,
+	})
+	scid := lnwire.ShortChannelID{
+		BlockHeight: 1,
+		TxIndex:     2,
+		TxPosition:  3,
+	}
+	require.Equal(ht, dave.PubKey[:], jitChannel.LspPubkey)
+	require.Equal(ht, scid.ToUint64(), jitChannel.Scid)
+	require.EqualValues(ht, 144, jitChannel.LspCltvExpiryDelta)
+
+	// The bought channel is listed until Dave opens it.
+	channels := carol.RPC.LSPListJITChannels()
+	require.Len(ht, channels.Channels, 1)
+	require.Equal(ht, jitChannel.Scid, channels.Channels[0].Scid)
+}
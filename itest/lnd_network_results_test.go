@@ -0,0 +1,56 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// testNetworkResults tests that the results of payment attempts are listed by
+// the network result RPCs, and pruned once their payment is deleted.
+func testNetworkResults(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+	ht.EnsureConnected(alice, bob)
+
+	chanAmt := btcutil.Amount(100000)
+	chanPoint := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(alice, chanPoint)
+
+	// Start from an empty store, so only the result of the payment below
+	// is listed.
+	alice.RPC.DeleteAllPayments()
+	alice.RPC.PruneNetworkResults()
+
+	preimage := ht.Random32Bytes()
+	invoice := bob.RPC.AddInvoice(&lnrpc.Invoice{
+		Value:     1000,
+		RPreimage: preimage,
+	})
+	ht.CompletePaymentRequests(alice, []string{invoice.PaymentRequest})
+
+	resp := alice.RPC.QueryNetworkResults(
+		&routerrpc.QueryNetworkResultsRequest{},
+	)
+	require.Len(ht, resp.Results, 1)
+
+	result := resp.Results[0]
+	require.True(ht, result.Settled)
+	require.Equal(ht, preimage, result.Preimage)
+	require.NotZero(ht, result.StoredAt)
+
+	// The result of the resolved attempt is kept until its payment is
+	// deleted.
+	require.Zero(ht, alice.RPC.PruneNetworkResults().NumPruned)
+
+	alice.RPC.DeleteAllPayments()
+	require.EqualValues(ht, 1, alice.RPC.PruneNetworkResults().NumPruned)
+
+	resp = alice.RPC.QueryNetworkResults(
+		&routerrpc.QueryNetworkResultsRequest{},
+	)
+	require.Empty(ht, resp.Results)
+}
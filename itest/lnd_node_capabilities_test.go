@@ -0,0 +1,95 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testNodeCapabilities tests that the capabilities a node advertises in its
+// node announcement are reported, together with the relay statistics that
+// were measured for it.
+func testNodeCapabilities(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+	ht.EnsureConnected(alice, bob)
+
+	dave := ht.NewNode("Dave", []string{
+		"--protocol.onion-messages",
+		"--protocol.trampoline-routing",
+	})
+	defer ht.Shutdown(dave)
+
+	// Dave needs a public channel for his node announcement to be
+	// relayed to Alice.
+	ht.ConnectNodes(bob, dave)
+	const chanAmt = btcutil.Amount(1_000_000)
+	chanPointAlice := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	chanPointBob := ht.OpenChannel(
+		bob, dave, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+
+	// Alice learns about Dave's capabilities through Bob. Relay stats
+	// are only included when requested.
+	err := wait.NoError(func() error {
+		resp, err := alice.RPC.LN.GetNodeCapabilities(
+			ht.Context(), &lnrpc.NodeCapabilitiesRequest{
+				PubKey: dave.PubKeyStr,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if !resp.OnionMessages || !resp.Trampoline ||
+			!resp.RouteBlinding {
+
+			return fmt.Errorf("unexpected capabilities: %v", resp)
+		}
+		if resp.RelayStats != nil {
+			return fmt.Errorf("unexpected relay stats: %v",
+				resp.RelayStats)
+		}
+
+		return nil
+	}, defaultTimeout)
+	require.NoError(ht, err, "capabilities not announced")
+
+	// Bob was started without the optional protocol flags.
+	resp := alice.RPC.GetNodeCapabilities(&lnrpc.NodeCapabilitiesRequest{
+		PubKey: bob.PubKeyStr,
+	})
+	require.False(ht, resp.OnionMessages)
+	require.False(ht, resp.Trampoline)
+
+	// Once Alice has paid Dave through Bob, the successful relay is
+	// reflected in Bob's stats.
+	ht.AssertTopologyChannelOpen(alice, chanPointBob)
+	invoice := dave.RPC.AddInvoice(&lnrpc.Invoice{ValueMsat: 10_000})
+	ht.CompletePaymentRequests(alice, []string{invoice.PaymentRequest})
+
+	resp = alice.RPC.GetNodeCapabilities(&lnrpc.NodeCapabilitiesRequest{
+		PubKey:            bob.PubKeyStr,
+		IncludeRelayStats: true,
+	})
+	require.NotNil(ht, resp.RelayStats)
+	require.EqualValues(ht, 1, resp.RelayStats.SuccessPairs)
+	require.NotZero(ht, resp.RelayStats.LastSuccess)
+	require.EqualValues(ht, 1, resp.RelayStats.SuccessRatio)
+
+	// An invalid public key is rejected.
+	_, err = alice.RPC.LN.GetNodeCapabilities(
+		ht.Context(), &lnrpc.NodeCapabilitiesRequest{PubKey: "00"},
+	)
+	require.Equal(ht, codes.InvalidArgument, status.Code(err))
+
+	ht.CloseChannel(alice, chanPointAlice)
+	ht.CloseChannel(bob, chanPointBob)
+}
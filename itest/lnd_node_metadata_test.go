@@ -0,0 +1,88 @@
+package itest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testNodeMetadata tests that the operator information configured on a node
+// is announced to the network, and that it can be updated at runtime.
+func testNodeMetadata(ht *lntest.HarnessTest) {
+	alice, bob := ht.Alice, ht.Bob
+	ht.EnsureConnected(alice, bob)
+
+	dave := ht.NewNode("Dave", []string{
+		"--nodemetadata.contact=ops@example.com",
+	})
+	defer ht.Shutdown(dave)
+
+	// assertMetadata waits until Alice sees the given metadata in the
+	// node announcement of Dave.
+	assertMetadata := func(expected *lnrpc.NodeMetadata) {
+		err := wait.NoError(func() error {
+			resp, err := alice.RPC.LN.GetNodeMetadata(
+				ht.Context(), &lnrpc.NodeMetadataRequest{
+					PubKey: dave.PubKeyStr,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			if resp.Contact != expected.Contact ||
+				resp.OrgName != expected.OrgName ||
+				resp.Url != expected.Url {
+
+				return fmt.Errorf("expected metadata %v, got "+
+					"%v", expected, resp)
+			}
+
+			return nil
+		}, defaultTimeout)
+		require.NoError(ht, err, "metadata not announced")
+	}
+
+	// Dave needs a public channel for his node announcement to be
+	// relayed to Alice.
+	ht.ConnectNodes(bob, dave)
+	chanPoint := ht.OpenChannel(
+		bob, dave, lntest.OpenChannelParams{
+			Amt: btcutil.Amount(1_000_000),
+		},
+	)
+
+	// Dave knows his own metadata, and Alice receives it through Bob.
+	metadata := dave.RPC.GetNodeMetadata(dave.PubKeyStr)
+	require.Equal(ht, "ops@example.com", metadata.Contact)
+	assertMetadata(&lnrpc.NodeMetadata{Contact: "ops@example.com"})
+
+	// Records that are too long are rejected.
+	_, err := dave.RPC.Peer.UpdateNodeMetadata(
+		ht.Context(), &peersrpc.NodeMetadataUpdateRequest{
+			OrgName: strings.Repeat("a", 257),
+		},
+	)
+	require.Equal(ht, codes.InvalidArgument, status.Code(err))
+
+	// Dave replaces his metadata, removing the contact.
+	resp := dave.RPC.UpdateNodeMetadata(&peersrpc.NodeMetadataUpdateRequest{
+		OrgName: "Dave Inc",
+		Url:     "https://dave.example.com",
+	})
+	require.Len(ht, resp.Ops, 1)
+	assertMetadata(&lnrpc.NodeMetadata{
+		OrgName: "Dave Inc",
+		Url:     "https://dave.example.com",
+	})
+
+	ht.CloseChannel(bob, chanPoint)
+}
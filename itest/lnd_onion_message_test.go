@@ -0,0 +1,158 @@
+package itest
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/lightningnetwork/lnd/onionmessage"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// buildMessagePath builds an encoded blinded path for onion messages that
+// runs through the given nodes and ends at the last one of them.
+func buildMessagePath(ht *lntest.HarnessTest,
+	nodes ...*node.HarnessNode) []byte {
+
+	keys := make([]*btcec.PublicKey, len(nodes))
+	for i, hn := range nodes {
+		key, err := btcec.ParsePubKey(hn.PubKey[:])
+		require.NoError(ht, err)
+
+		keys[i] = key
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(ht, err)
+
+	path, err := onionmessage.BuildBlindedPath(sessionKey, keys, nil)
+	require.NoError(ht, err)
+
+	encoded, err := onionmessage.EncodeBlindedPath(path)
+	require.NoError(ht, err)
+
+	return encoded
+}
+
+// subscribeOnionMessages subscribes to the onion messages delivered to the
+// node and returns a channel they are passed on to, along with the function
+// that cancels the subscription.
+func subscribeOnionMessages(ht *lntest.HarnessTest,
+	hn *node.HarnessNode) (<-chan *lnrpc.OnionMessage, func()) {
+
+	client, cancel := hn.RPC.SubscribeOnionMessages()
+
+	messages := make(chan *lnrpc.OnionMessage)
+	go func() {
+		for {
+			// If we fail to receive, just exit. The test should
+			// fail elsewhere if it doesn't get a message that it
+			// was expecting.
+			msg, err := client.Recv()
+			if err != nil {
+				return
+			}
+
+			select {
+			case messages <- msg:
+			case <-ht.Context().Done():
+				return
+			}
+		}
+	}()
+
+	return messages, cancel
+}
+
+// receiveOnionMessage waits for the next onion message of the subscription.
+func receiveOnionMessage(ht *lntest.HarnessTest,
+	messages <-chan *lnrpc.OnionMessage) *lnrpc.OnionMessage {
+
+	select {
+	case msg := <-messages:
+		return msg
+
+	case <-time.After(lntest.DefaultTimeout):
+		require.Fail(ht, "onion message not received")
+		return nil
+	}
+}
+
+// testOnionMessage tests that onion messages are forwarded along a blinded
+// path, and that the recipient can answer over the included reply path.
+func testOnionMessage(ht *lntest.HarnessTest) {
+	args := []string{"--protocol.onion-messages"}
+
+	// Carol sends messages to Erin through Dave, and Dave introduces the
+	// reply path to Carol.
+	carol := ht.NewNode("Carol", args)
+	dave := ht.NewNode("Dave", args)
+	erin := ht.NewNode("Erin", args)
+	ht.EnsureConnected(carol, dave)
+	ht.EnsureConnected(dave, erin)
+
+	carolMessages, cancel := subscribeOnionMessages(ht, carol)
+	defer cancel()
+	erinMessages, cancel := subscribeOnionMessages(ht, erin)
+	defer cancel()
+
+	contents := map[uint64][]byte{
+		64:  []byte("hello"),
+		101: []byte("world"),
+	}
+	resp := carol.RPC.SendOnionMessage(&lnrpc.SendOnionMessageRequest{
+		BlindedPath:      buildMessagePath(ht, dave, erin),
+		Contents:         contents,
+		IncludeReplyPath: true,
+	})
+	require.Len(ht, resp.ReplyPathId, 32)
+
+	msg := receiveOnionMessage(ht, erinMessages)
+	require.Equal(ht, contents, msg.Contents)
+	require.Empty(ht, msg.PathId)
+	require.NotEmpty(ht, msg.ReplyPath)
+
+	// Erin answers over the reply path, and the reply carries the path ID
+	// Carol got for it.
+	replyContents := map[uint64][]byte{
+		66: []byte("reply"),
+	}
+	erin.RPC.SendOnionMessage(&lnrpc.SendOnionMessageRequest{
+		BlindedPath: msg.ReplyPath,
+		Contents:    replyContents,
+	})
+
+	reply := receiveOnionMessage(ht, carolMessages)
+	require.Equal(ht, replyContents, reply.Contents)
+	require.Equal(ht, resp.ReplyPathId, reply.PathId)
+	require.Empty(ht, reply.ReplyPath)
+
+	// Content types that are reserved for routing the message are
+	// rejected.
+	_, err := carol.RPC.LN.SendOnionMessage(
+		ht.Context(), &lnrpc.SendOnionMessageRequest{
+			BlindedPath: buildMessagePath(ht, dave, erin),
+			Contents: map[uint64][]byte{
+				4: []byte("reserved"),
+			},
+		},
+	)
+	require.Equal(ht, codes.InvalidArgument, status.Code(err))
+
+	// A node without onion message support can't send them.
+	_, err = ht.Alice.RPC.LN.SendOnionMessage(
+		ht.Context(), &lnrpc.SendOnionMessageRequest{
+			BlindedPath: buildMessagePath(ht, dave, erin),
+			Contents:    contents,
+		},
+	)
+	require.Equal(ht, codes.Unimplemented, status.Code(err))
+
+	ht.Shutdown(carol)
+	ht.Shutdown(dave)
+	ht.Shutdown(erin)
+}
@@ -452,7 +452,13 @@ func runBasicChannelCreationAndUpdates(ht *lntest.HarnessTest,
 				expectedCloseType = forceType
 			}
 
+			// The channels closed cooperatively by the peer are
+			// drained first, which isn't a close update.
 			chanUpdate := ht.ReceiveChannelEvent(sub)
+			for isDrainUpdate(chanUpdate) {
+				chanUpdate = ht.ReceiveChannelEvent(sub)
+			}
+
 			err := verifyCloseUpdate(
 				chanUpdate, expectedCloseType,
 				closeInitiator,
@@ -678,6 +684,20 @@ func testUpdateOnFundeePendingOpenChannels(ht *lntest.HarnessTest) {
 	ht.CloseChannel(alice, chanPoint)
 }
 
+// isDrainUpdate returns true if the channel update reports that a channel
+// started or finished draining.
+func isDrainUpdate(chanUpdate *lnrpc.ChannelEventUpdate) bool {
+	switch chanUpdate.Type {
+	case lnrpc.ChannelEventUpdate_DRAINING_CHANNEL,
+		lnrpc.ChannelEventUpdate_DRAINED_CHANNEL:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
 // verifyCloseUpdate is used to verify that a closed channel update is of the
 // expected type.
 func verifyCloseUpdate(chanUpdate *lnrpc.ChannelEventUpdate,
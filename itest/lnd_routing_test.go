@@ -988,6 +988,11 @@ func testQueryRoutes(ht *lntest.HarnessTest) {
 		}
 	}
 
+	// The fees of the route are broken down by the nodes that charge
+	// them.
+	forwarders := []*node.HarnessNode{bob, carol}
+	testQueryRouteFees(ht, alice, routesReq, forwarders)
+
 	// While we're here, we test updating mission control's config values
 	// and assert that they are correctly updated and check that our mission
 	// control import function updates appropriately.
@@ -1331,6 +1336,55 @@ func testRouteFeeCutoff(ht *lntest.HarnessTest) {
 	ht.CloseChannel(carol, chanPointCarolDave)
 }
 
+// testQueryRouteFees asserts that the fees of the route found for the given
+// query are charged by the given forwarding nodes, which all use the default
+// policy.
+func testQueryRouteFees(ht *lntest.HarnessTest, hn *node.HarnessNode,
+	query *lnrpc.QueryRoutesRequest, forwarders []*node.HarnessNode) {
+
+	resp := hn.RPC.QueryRouteFees(&routerrpc.QueryRouteFeesRequest{
+		Query:     query,
+		NumRoutes: 1,
+	})
+	require.Len(ht, resp.Routes, 1)
+
+	routeFees := resp.Routes[0]
+	require.Len(ht, routeFees.Hops, len(forwarders))
+
+	const mSat = 1000
+	amt := lnwire.MilliSatoshi(query.Amt * mSat)
+	feePerHopMSat := computeFee(1000, 1, amt)
+
+	var totalFees uint64
+	for i, hop := range routeFees.Hops {
+		require.Equal(ht, forwarders[i].PubKey[:], hop.PubKey)
+		require.EqualValues(ht, feePerHopMSat, hop.OutboundFeeMsat)
+		require.Zero(ht, hop.InboundFeeMsat)
+		require.Equal(ht, hop.OutboundFeeMsat, hop.FeeMsat)
+		require.Equal(
+			ht, routeFees.Route.Hops[i].ChanId, hop.IncomingChanId,
+		)
+		require.Equal(
+			ht, routeFees.Route.Hops[i+1].ChanId,
+			hop.OutgoingChanId,
+		)
+
+		totalFees += hop.FeeMsat
+	}
+	require.Equal(ht, totalFees, routeFees.TotalFeesMsat)
+	require.EqualValues(
+		ht, routeFees.Route.TotalFeesMsat, routeFees.TotalFeesMsat,
+	)
+
+	// Our own default policy charges the same fee for the amount of the
+	// whole payment.
+	totalAmt := lnwire.MilliSatoshi(routeFees.Route.TotalAmtMsat)
+	require.EqualValues(
+		ht, computeFee(1000, 1, totalAmt),
+		routeFees.OwnOutboundFeeMsat,
+	)
+}
+
 // computeFee calculates the payment fee as specified in BOLT07.
 func computeFee(baseFee, feeRate, amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
 	return baseFee + amt*feeRate/1000000
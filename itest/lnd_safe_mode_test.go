@@ -0,0 +1,25 @@
+package itest
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testSafeModeStatus tests that a healthy node reports that it isn't in safe
+// mode, and rejects requests to exit safe mode.
+func testSafeModeStatus(ht *lntest.HarnessTest) {
+	carol := ht.NewNode("Carol", []string{"--safemode.chainbackend"})
+	defer ht.Shutdown(carol)
+
+	resp := carol.RPC.SafeModeStatus()
+	require.False(ht, resp.Active)
+	require.Empty(ht, resp.Reasons)
+
+	_, err := carol.RPC.LN.ExitSafeMode(
+		ht.Context(), &lnrpc.ExitSafeModeRequest{},
+	)
+	require.Equal(ht, codes.FailedPrecondition, status.Code(err))
+}
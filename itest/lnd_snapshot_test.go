@@ -0,0 +1,48 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// testNodeSnapshot tests that a node restored from a snapshot has the
+// identity, wallet and channels of the node the snapshot was taken of, and
+// can use its channels.
+func testNodeSnapshot(ht *lntest.HarnessTest) {
+	alice := ht.Alice
+
+	carol := ht.NewNode("Carol", nil)
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, carol)
+	ht.EnsureConnected(carol, alice)
+	chanPoint := ht.OpenChannel(
+		carol, alice, lntest.OpenChannelParams{Amt: 1_000_000},
+	)
+
+	// Take a snapshot of Carol once her channel is open. She's restarted
+	// afterwards.
+	snapshot := ht.SnapshotNode(carol)
+	balance := carol.RPC.WalletBalance()
+
+	// Both nodes can't run at the same time, as they'd compete for the
+	// same channel.
+	ht.Shutdown(carol)
+
+	restored := ht.NewNodeFromSnapshot("CarolRestored", snapshot)
+	require.Equal(ht, carol.PubKeyStr, restored.PubKeyStr)
+	require.Equal(
+		ht, balance.ConfirmedBalance,
+		restored.RPC.WalletBalance().ConfirmedBalance,
+	)
+
+	// The restored node takes over Carol's channel.
+	ht.EnsureConnected(restored, alice)
+	ht.AssertChannelActive(restored, chanPoint)
+
+	invoice := alice.RPC.AddInvoice(&lnrpc.Invoice{Value: 1000})
+	ht.CompletePaymentRequests(restored, []string{invoice.PaymentRequest})
+
+	ht.CloseChannel(restored, chanPoint)
+	ht.Shutdown(restored)
+}
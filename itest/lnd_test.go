@@ -32,6 +32,16 @@ const (
 	// we run.
 	defaultRunTranche uint = 0
 
+	// tranchePortBase is the first port of the port range reserved for
+	// the first tranche.
+	tranchePortBase = 10000
+
+	// tranchePortRangeSize is the number of ports reserved for each
+	// tranche. Each tranche runs its own miner, chain backend and lnd
+	// nodes on ports from its own range, so tranches running in parallel
+	// never compete for the same ports.
+	tranchePortRangeSize = 2000
+
 	defaultTimeout = wait.DefaultTimeout
 	itestLndBinary = "../lnd-itest"
 
@@ -92,6 +102,10 @@ func TestLightningNetworkDaemon(t *testing.T) {
 	// Get the test cases to be run in this tranche.
 	testCases, trancheIndex, trancheOffset := getTestCaseSplitTranche()
 
+	// Isolate the ports of this tranche from the other tranches that
+	// might be running in parallel.
+	reserveTranchePorts(t, trancheIndex)
+
 	// Create a simple fee service.
 	feeService := lntest.NewFeeService(t)
 
@@ -169,14 +183,14 @@ func getTestCaseSplitTranche() ([]*lntest.TestCase, uint, uint) {
 		runTranche = *testCasesRunTranche
 	}
 
-	// There's a special flake-hunt mode where we run the same test multiple
-	// times in parallel. In that case the tranche index is equal to the
-	// thread ID, but we need to actually run all tests for the regex
-	// selection to work.
+	// If more processes than tranches run in parallel, the extra
+	// processes run the tranches again, starting from the first one. This
+	// includes the special flake-hunt mode where we run the same test
+	// multiple times in parallel with a single tranche, so all tests are
+	// run for the regex selection to work. The thread ID keeps the ports
+	// of each process isolated.
 	threadID := runTranche
-	if numTranches == 1 {
-		runTranche = 0
-	}
+	runTranche %= numTranches
 
 	numCases := uint(len(allTestCases))
 	testsPerTranche := numCases / numTranches
@@ -190,6 +204,23 @@ func getTestCaseSplitTranche() ([]*lntest.TestCase, uint, uint) {
 		trancheOffset
 }
 
+// reserveTranchePorts reserves a port range for the tranche with the given
+// index. If the index is too large to get its own range, the ports are
+// coordinated with other processes through the shared port file instead.
+func reserveTranchePorts(t *testing.T, trancheIndex uint) {
+	start := tranchePortBase + int(trancheIndex)*tranchePortRangeSize
+	end := start + tranchePortRangeSize
+	if end > math.MaxUint16 {
+		t.Logf("No port range available for tranche %d, using "+
+			"shared port allocation", trancheIndex)
+
+		return
+	}
+
+	require.NoError(t, port.ReservePortRange(start, end))
+	t.Logf("Using ports [%d, %d) for tranche %d", start, end, trancheIndex)
+}
+
 func getLndBinary(t *testing.T) string {
 	binary := itestLndBinary
 	lndExec := ""
@@ -0,0 +1,69 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// testBuildTopology tests that a topology built from a spec has all of its
+// channels and policies in place, and that payments are routed through it.
+func testBuildTopology(ht *lntest.HarnessTest) {
+	const (
+		chanAmt    = btcutil.Amount(1_000_000)
+		invoiceAmt = 10_000
+	)
+
+	// Carol charges a base fee for forwarding to Dave.
+	policy := &lnrpc.RoutingPolicy{
+		FeeBaseMsat:   2000,
+		TimeLockDelta: 50,
+		MinHtlc:       1000,
+		MaxHtlcMsat:   lntest.CalculateMaxHtlc(chanAmt),
+	}
+
+	spec := lntest.NewTopology().
+		AddExistingNode("alice", ht.Alice).
+		AddNode("carol").
+		AddNode("dave").
+		AddChannel("alice", "carol", lntest.OpenChannelParams{
+			Amt: chanAmt,
+		}).
+		AddChannel("carol", "dave", lntest.OpenChannelParams{
+			Amt: chanAmt,
+		}).
+		WithPolicy(policy)
+	topology := ht.BuildTopology(spec)
+	defer topology.Teardown()
+
+	alice := topology.Node("alice")
+	carol := topology.Node("carol")
+	dave := topology.Node("dave")
+	require.Len(ht, topology.ChanPoints(), 2)
+
+	// Every node knows about both channels.
+	for _, hn := range topology.Nodes() {
+		ht.AssertNumEdges(hn, 2, false)
+	}
+
+	// A payment from Alice to Dave is routed through Carol, who charges
+	// the base fee of her policy.
+	preimage := ht.Random32Bytes()
+	invoice := dave.RPC.AddInvoice(&lnrpc.Invoice{
+		Value:     invoiceAmt,
+		RPreimage: preimage,
+	})
+	ht.CompletePaymentRequests(alice, []string{invoice.PaymentRequest})
+
+	payment := ht.AssertPaymentStatus(
+		alice, lntypes.Preimage(preimage), lnrpc.Payment_SUCCEEDED,
+	)
+	require.EqualValues(ht, policy.FeeBaseMsat, payment.FeeMsat)
+
+	ht.AssertAmountPaid(
+		"carol => dave", carol, topology.ChanPoint("carol", "dave"),
+		invoiceAmt, 0,
+	)
+}
@@ -0,0 +1,58 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// testSubscribeWalletBalance tests that a wallet balance subscription sends
+// the current balance and an update whenever it changes.
+func testSubscribeWalletBalance(ht *lntest.HarnessTest) {
+	carol := ht.NewNode("Carol", nil)
+	defer ht.Shutdown(carol)
+
+	stream := carol.RPC.SubscribeWalletBalance(
+		&lnrpc.SubscribeWalletBalanceRequest{},
+	)
+
+	// The first update is the current, empty balance of the wallet.
+	update := ht.ReceiveWalletBalanceUpdate(stream)
+	require.Zero(ht, update.TotalBalance)
+	for _, account := range update.Accounts {
+		require.Zero(ht, account.ConfirmedBalance)
+		require.Zero(ht, account.UnconfirmedBalance)
+	}
+
+	// Send coins to Carol without confirming them, which shows up as
+	// unconfirmed balance of the default account. Neutrino doesn't see
+	// unconfirmed outputs, so there's no update before they confirm.
+	const amt = btcutil.Amount(100_000)
+	ht.FundCoinsUnconfirmed(amt, carol)
+
+	if !ht.IsNeutrinoBackend() {
+		update = ht.ReceiveWalletBalanceUpdate(stream)
+		require.EqualValues(ht, amt, update.UnconfirmedBalance)
+		require.Zero(ht, update.ConfirmedBalance)
+		require.EqualValues(ht, amt, update.TotalBalance)
+	}
+
+	// Once the coins confirm, the balance moves to the confirmed balance.
+	ht.MineBlocksAndAssertNumTxes(1, 1)
+
+	update = ht.ReceiveWalletBalanceUpdate(stream)
+	require.EqualValues(ht, amt, update.ConfirmedBalance)
+	require.Zero(ht, update.UnconfirmedBalance)
+	require.EqualValues(ht, amt, update.TotalBalance)
+
+	var defaultAccount *lnrpc.AccountBalanceDetail
+	for _, account := range update.Accounts {
+		if account.Account == lnwallet.DefaultAccountName {
+			defaultAccount = account
+		}
+	}
+	require.NotNil(ht, defaultAccount)
+	require.EqualValues(ht, amt, defaultAccount.ConfirmedBalance)
+}
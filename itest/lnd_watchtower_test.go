@@ -35,6 +35,84 @@ func testWatchtower(ht *lntest.HarnessTest) {
 		tt := ht.Subtest(t)
 		testTowerClientTowerAndSessionManagement(tt)
 	})
+
+	ht.Run("tower migration", func(t *testing.T) {
+		tt := ht.Subtest(t)
+		testTowerClientMigration(tt)
+	})
+}
+
+// testTowerClientMigration tests that the backups stored with a decommissioned
+// tower are uploaded again to the tower it is migrated to.
+func testTowerClientMigration(ht *lntest.HarnessTest) {
+	const (
+		chanAmt     = funding.MaxBtcFundingAmount
+		externalIP  = "1.2.3.4"
+		externalIP2 = "1.2.3.5"
+	)
+
+	// Wallis is the tower that is decommissioned and Wilma the one its
+	// backups are migrated to.
+	wallisPk, wallisListener, _ := setUpNewTower(ht, "Wallis", externalIP)
+	wilmaPk, wilmaListener, _ := setUpNewTower(ht, "Wilma", externalIP2)
+
+	dave := ht.NewNode("Dave", []string{"--wtclient.active"})
+	dave.RPC.AddTower(&wtclientrpc.AddTowerRequest{
+		Pubkey:  wallisPk,
+		Address: wallisListener,
+	})
+
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, dave)
+	ht.ConnectNodes(dave, ht.Alice)
+	chanPoint := ht.OpenChannel(
+		dave, ht.Alice, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+
+	generateBackups(ht, dave, ht.Alice, 4)
+	assertNumBackups(ht, dave.RPC, wallisPk, 4, false)
+
+	resp := dave.RPC.MigrateTower(&wtclientrpc.MigrateTowerRequest{
+		OldPubkey:  wallisPk,
+		NewPubkey:  wilmaPk,
+		NewAddress: wilmaListener,
+	})
+	require.EqualValues(ht, 4, resp.NumBackups)
+	require.Zero(ht, resp.NumSkipped)
+
+	// The old tower isn't used for new sessions anymore.
+	info := dave.RPC.GetTowerInfo(&wtclientrpc.GetTowerInfoRequest{
+		Pubkey: wallisPk,
+	})
+	require.GreaterOrEqual(ht, len(info.SessionInfo), 1)
+	require.False(ht, info.SessionInfo[0].ActiveSessionCandidate)
+
+	// All backups end up on the new tower, which completes the migration.
+	assertNumBackups(ht, dave.RPC, wilmaPk, 4, false)
+
+	err := wait.NoError(func() error {
+		status := dave.RPC.MigrationStatus()
+		if !status.Complete {
+			return fmt.Errorf("migration incomplete, %d of %d "+
+				"backups backed up", status.NumBackedUp,
+				status.NumBackups)
+		}
+
+		return nil
+	}, defaultTimeout)
+	require.NoError(ht, err)
+
+	status := dave.RPC.MigrationStatus()
+	require.Equal(ht, wallisPk, status.OldPubkey)
+	require.Equal(ht, wilmaPk, status.NewPubkey)
+	require.EqualValues(ht, 4, status.NumBackedUp)
+	require.Empty(ht, status.Error)
+
+	// The old tower can now be removed.
+	dave.RPC.RemoveTower(&wtclientrpc.RemoveTowerRequest{
+		Pubkey: wallisPk,
+	})
+
+	ht.CloseChannel(dave, chanPoint)
 }
 
 // testTowerClientTowerAndSessionManagement tests the various control commands
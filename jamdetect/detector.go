@@ -0,0 +1,420 @@
+// Package jamdetect watches the htlcs forwarded by the node for channel
+// jamming patterns. Slot jamming holds many small htlcs to exhaust the htlc
+// slots of a channel, while liquidity jamming holds htlcs to lock up the
+// balance of a channel. Both come from the same source for a long time, so
+// the detector groups the held htlcs by their incoming channel. It reports
+// suspected incidents and can temporarily refuse forwards from the incoming
+// channel of an incident.
+package jamdetect
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+// maxIncidents is the number of the most recent incidents the detector keeps.
+const maxIncidents = 100
+
+// IncidentType is the jamming pattern of an incident.
+type IncidentType string
+
+const (
+	// IncidentSlotJamming means that many small htlcs from the same
+	// incoming channel were held for a long time.
+	IncidentSlotJamming IncidentType = "slot_jamming"
+
+	// IncidentLiquidityJamming means that htlcs from the same incoming
+	// channel held a large amount for a long time.
+	IncidentLiquidityJamming IncidentType = "liquidity_jamming"
+)
+
+// Config contains the dependencies and parameters of the detector.
+type Config struct {
+	// SubscribeHtlcEvents subscribes to the events of the htlcs processed
+	// by the switch.
+	SubscribeHtlcEvents func() (*subscribe.Client, error)
+
+	// Clock is used to time how long htlcs are held.
+	Clock clock.Clock
+
+	// Ticker triggers the periodic checks of the held htlcs.
+	Ticker ticker.Ticker
+
+	// MinHoldTime is the time an htlc must be held before it counts
+	// towards an incident.
+	MinHoldTime time.Duration
+
+	// SlotThreshold is the number of small htlcs held from one incoming
+	// channel that is reported as slot jamming. Zero disables the
+	// detection.
+	SlotThreshold int
+
+	// SmallAmount is the amount up to which an htlc counts as small.
+	SmallAmount lnwire.MilliSatoshi
+
+	// LiquidityThreshold is the amount held from one incoming channel
+	// that is reported as liquidity jamming. Zero disables the detection.
+	LiquidityThreshold lnwire.MilliSatoshi
+
+	// Mitigate refuses forwards from the incoming channel of an incident
+	// while it is detected and for MitigationTime afterwards.
+	Mitigate bool
+
+	// MitigationTime is the time forwards from the incoming channel of an
+	// incident stay refused after it was last detected.
+	MitigationTime time.Duration
+}
+
+// Incident is a suspected jamming attempt.
+type Incident struct {
+	// ID identifies the incident.
+	ID uint64 `json:"id"`
+
+	// Type is the jamming pattern that was detected.
+	Type IncidentType `json:"type"`
+
+	// IncomingChannel is the channel the held htlcs arrived on.
+	IncomingChannel lnwire.ShortChannelID `json:"incoming_channel"`
+
+	// OutgoingChannels are the channels the held htlcs were forwarded
+	// to.
+	OutgoingChannels []lnwire.ShortChannelID `json:"outgoing_channels"`
+
+	// HTLCs is the number of htlcs held when the incident was last
+	// detected.
+	HTLCs int `json:"htlcs"`
+
+	// Amount is the amount held when the incident was last detected.
+	Amount lnwire.MilliSatoshi `json:"amount_msat"`
+
+	// DetectedAt is the time the incident was first detected.
+	DetectedAt time.Time `json:"detected_at"`
+
+	// LastSeen is the time the incident was last detected.
+	LastSeen time.Time `json:"last_seen"`
+
+	// Resolved is true once the pattern isn't detected anymore.
+	Resolved bool `json:"resolved"`
+
+	// MitigatedUntil is the time until which forwards from the incoming
+	// channel are refused. It is zero if no mitigation is applied.
+	MitigatedUntil time.Time `json:"mitigated_until,omitempty"`
+}
+
+// heldHTLC is a forwarded htlc that is not resolved yet.
+type heldHTLC struct {
+	outgoing lnwire.ShortChannelID
+	amount   lnwire.MilliSatoshi
+	since    time.Time
+}
+
+// incidentKey identifies the open incident of a pattern on a channel.
+type incidentKey struct {
+	incoming lnwire.ShortChannelID
+	kind     IncidentType
+}
+
+// Detector detects jamming patterns in the htlcs forwarded by the node.
+type Detector struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// held are the unresolved forwards by their incoming circuit.
+	held map[models.CircuitKey]heldHTLC
+
+	// incidents are the most recent incidents, oldest first.
+	incidents []*Incident
+
+	// open are the incidents that are currently detected.
+	open map[incidentKey]*Incident
+
+	// mitigated are the incoming channels forwards are refused from,
+	// mapped to the time until which they are.
+	mitigated map[lnwire.ShortChannelID]time.Time
+
+	nextID uint64
+
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new jamming detector.
+func New(cfg *Config) *Detector {
+	return &Detector{
+		cfg:       cfg,
+		held:      make(map[models.CircuitKey]heldHTLC),
+		open:      make(map[incidentKey]*Incident),
+		mitigated: make(map[lnwire.ShortChannelID]time.Time),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start subscribes to htlc events and starts the periodic checks.
+func (d *Detector) Start() error {
+	var startErr error
+	d.started.Do(func() {
+		log.Info("Jamming detector starting")
+
+		client, err := d.cfg.SubscribeHtlcEvents()
+		if err != nil {
+			startErr = fmt.Errorf("unable to subscribe to htlc "+
+				"events: %w", err)
+
+			return
+		}
+
+		d.cfg.Ticker.Resume()
+
+		d.wg.Add(1)
+		go d.run(client)
+	})
+
+	return startErr
+}
+
+// Stop stops the detector.
+func (d *Detector) Stop() error {
+	d.stopped.Do(func() {
+		log.Info("Jamming detector shutting down...")
+		defer log.Debug("Jamming detector shutdown complete")
+
+		close(d.quit)
+		d.wg.Wait()
+
+		d.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// Incidents returns the most recent incidents, newest first.
+func (d *Detector) Incidents() []Incident {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	incidents := make([]Incident, 0, len(d.incidents))
+	for i := len(d.incidents) - 1; i >= 0; i-- {
+		incident := *d.incidents[i]
+		incident.OutgoingChannels = append(
+			[]lnwire.ShortChannelID(nil),
+			incident.OutgoingChannels...,
+		)
+		incidents = append(incidents, incident)
+	}
+
+	return incidents
+}
+
+// Mitigated returns true if forwards from the given incoming channel are
+// currently refused.
+func (d *Detector) Mitigated(incoming lnwire.ShortChannelID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	until, ok := d.mitigated[incoming]
+
+	return ok && d.cfg.Clock.Now().Before(until)
+}
+
+// run processes htlc events and checks the held htlcs on every tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *Detector) run(client *subscribe.Client) {
+	defer d.wg.Done()
+	defer client.Cancel()
+
+	for {
+		select {
+		case event := <-client.Updates():
+			d.processEvent(event)
+
+		case <-d.cfg.Ticker.Ticks():
+			d.check()
+
+		case <-client.Quit():
+			log.Warn("Htlc event subscription closed")
+			return
+
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// processEvent tracks the forwards that are held by the node.
+func (d *Detector) processEvent(event interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch e := event.(type) {
+	case *htlcswitch.ForwardingEvent:
+		if e.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		d.held[e.IncomingCircuit] = heldHTLC{
+			outgoing: e.OutgoingCircuit.ChanID,
+			amount:   e.OutgoingAmt,
+			since:    e.Timestamp,
+		}
+
+	case *htlcswitch.SettleEvent:
+		delete(d.held, e.IncomingCircuit)
+
+	case *htlcswitch.ForwardingFailEvent:
+		delete(d.held, e.IncomingCircuit)
+
+	case *htlcswitch.LinkFailEvent:
+		delete(d.held, e.IncomingCircuit)
+
+	case *htlcswitch.FinalHtlcEvent:
+		delete(d.held, e.CircuitKey)
+	}
+}
+
+// channelHolds summarizes the htlcs held from an incoming channel.
+type channelHolds struct {
+	outgoing    map[lnwire.ShortChannelID]struct{}
+	htlcs       int
+	smallHTLCs  int
+	amount      lnwire.MilliSatoshi
+	smallAmount lnwire.MilliSatoshi
+}
+
+// check groups the htlcs held for at least the minimum hold time by their
+// incoming channel and updates the incidents and mitigations.
+func (d *Detector) check() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.cfg.Clock.Now()
+
+	holds := make(map[lnwire.ShortChannelID]*channelHolds)
+	for circuit, htlc := range d.held {
+		if now.Sub(htlc.since) < d.cfg.MinHoldTime {
+			continue
+		}
+
+		h, ok := holds[circuit.ChanID]
+		if !ok {
+			h = &channelHolds{
+				outgoing: make(
+					map[lnwire.ShortChannelID]struct{},
+				),
+			}
+			holds[circuit.ChanID] = h
+		}
+
+		h.outgoing[htlc.outgoing] = struct{}{}
+		h.htlcs++
+		h.amount += htlc.amount
+		if htlc.amount <= d.cfg.SmallAmount {
+			h.smallHTLCs++
+			h.smallAmount += htlc.amount
+		}
+	}
+
+	detected := make(map[incidentKey]struct{})
+	for incoming, h := range holds {
+		if d.cfg.SlotThreshold > 0 &&
+			h.smallHTLCs >= d.cfg.SlotThreshold {
+
+			key := incidentKey{incoming, IncidentSlotJamming}
+			d.detect(key, h, h.smallHTLCs, h.smallAmount, now)
+			detected[key] = struct{}{}
+		}
+
+		if d.cfg.LiquidityThreshold > 0 &&
+			h.amount >= d.cfg.LiquidityThreshold {
+
+			key := incidentKey{incoming, IncidentLiquidityJamming}
+			d.detect(key, h, h.htlcs, h.amount, now)
+			detected[key] = struct{}{}
+		}
+	}
+
+	for key, incident := range d.open {
+		if _, ok := detected[key]; ok {
+			continue
+		}
+
+		log.Infof("Jamming incident %d (%v) on channel %v resolved",
+			incident.ID, incident.Type, key.incoming)
+
+		incident.Resolved = true
+		delete(d.open, key)
+	}
+
+	for incoming, until := range d.mitigated {
+		if !now.Before(until) {
+			log.Infof("Lifting jamming mitigation for channel %v",
+				incoming)
+
+			delete(d.mitigated, incoming)
+		}
+	}
+}
+
+// detect records that the pattern of the key is detected on its incoming
+// channel with the given number of htlcs and amount, and mitigates it if
+// configured.
+//
+// NOTE: The mutex of the detector must be held.
+func (d *Detector) detect(key incidentKey, h *channelHolds, htlcs int,
+	amount lnwire.MilliSatoshi, now time.Time) {
+
+	incident, ok := d.open[key]
+	if !ok {
+		d.nextID++
+		incident = &Incident{
+			ID:              d.nextID,
+			Type:            key.kind,
+			IncomingChannel: key.incoming,
+			DetectedAt:      now,
+		}
+		d.open[key] = incident
+
+		d.incidents = append(d.incidents, incident)
+		if len(d.incidents) > maxIncidents {
+			d.incidents = d.incidents[1:]
+		}
+
+		log.Warnf("Suspected %v on incoming channel %v: %d htlcs "+
+			"holding %v (mitigate=%v)", key.kind, key.incoming,
+			htlcs, amount, d.cfg.Mitigate)
+	}
+
+	incident.OutgoingChannels = incident.OutgoingChannels[:0]
+	for outgoing := range h.outgoing {
+		incident.OutgoingChannels = append(
+			incident.OutgoingChannels, outgoing,
+		)
+	}
+	sort.Slice(incident.OutgoingChannels, func(i, j int) bool {
+		return incident.OutgoingChannels[i].ToUint64() <
+			incident.OutgoingChannels[j].ToUint64()
+	})
+
+	incident.HTLCs = htlcs
+	incident.Amount = amount
+	incident.LastSeen = now
+
+	if d.cfg.Mitigate {
+		until := now.Add(d.cfg.MitigationTime)
+		d.mitigated[key.incoming] = until
+		incident.MitigatedUntil = until
+	}
+}
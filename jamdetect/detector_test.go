@@ -0,0 +1,164 @@
+package jamdetect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardEvent returns the forward of an htlc with the given id from the
+// incoming to the outgoing channel.
+func forwardEvent(incoming, outgoing lnwire.ShortChannelID, id uint64,
+	amt lnwire.MilliSatoshi, ts time.Time) *htlcswitch.ForwardingEvent {
+
+	return &htlcswitch.ForwardingEvent{
+		HtlcKey: htlcswitch.HtlcKey{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: incoming,
+				HtlcID: id,
+			},
+			OutgoingCircuit: models.CircuitKey{
+				ChanID: outgoing,
+				HtlcID: id,
+			},
+		},
+		HtlcInfo: htlcswitch.HtlcInfo{
+			IncomingAmt: amt + 1,
+			OutgoingAmt: amt,
+		},
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+		Timestamp:     ts,
+	}
+}
+
+// TestDetectorIncidents tests that held htlcs from the same incoming channel
+// are reported as jamming incidents and mitigated until they are resolved.
+func TestDetectorIncidents(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Unix(1_000_000, 0))
+	detector := New(&Config{
+		Clock:              testClock,
+		Ticker:             ticker.NewForce(time.Hour),
+		MinHoldTime:        10 * time.Minute,
+		SlotThreshold:      3,
+		SmallAmount:        1_000,
+		LiquidityThreshold: 1_000_000,
+		Mitigate:           true,
+		MitigationTime:     time.Hour,
+	})
+
+	jammer := lnwire.NewShortChanIDFromInt(1)
+	honest := lnwire.NewShortChanIDFromInt(2)
+	outA := lnwire.NewShortChanIDFromInt(10)
+	outB := lnwire.NewShortChanIDFromInt(11)
+
+	start := testClock.Now()
+	for i := uint64(0); i < 3; i++ {
+		out := outA
+		if i == 2 {
+			out = outB
+		}
+		detector.processEvent(forwardEvent(jammer, out, i, 500, start))
+	}
+	detector.processEvent(forwardEvent(honest, outA, 0, 500, start))
+	detector.processEvent(forwardEvent(honest, outA, 1, 500, start))
+
+	// Local sends aren't forwards and are ignored.
+	send := forwardEvent(honest, outA, 2, 500, start)
+	send.HtlcEventType = htlcswitch.HtlcEventTypeSend
+	detector.processEvent(send)
+
+	// Before the minimum hold time passed, nothing is reported.
+	testClock.SetTime(start.Add(5 * time.Minute))
+	detector.check()
+	require.Empty(t, detector.Incidents())
+	require.False(t, detector.Mitigated(jammer))
+
+	// Once held long enough, the small htlcs from the jamming channel are
+	// reported as slot jamming and its forwards are refused.
+	detectedAt := start.Add(10 * time.Minute)
+	testClock.SetTime(detectedAt)
+	detector.check()
+
+	incidents := detector.Incidents()
+	require.Len(t, incidents, 1)
+	require.Equal(t, Incident{
+		ID:               1,
+		Type:             IncidentSlotJamming,
+		IncomingChannel:  jammer,
+		OutgoingChannels: []lnwire.ShortChannelID{outA, outB},
+		HTLCs:            3,
+		Amount:           1_500,
+		DetectedAt:       detectedAt,
+		LastSeen:         detectedAt,
+		MitigatedUntil:   detectedAt.Add(time.Hour),
+	}, incidents[0])
+	require.True(t, detector.Mitigated(jammer))
+	require.False(t, detector.Mitigated(honest))
+
+	// A large htlc from the same channel adds a liquidity incident.
+	detector.processEvent(
+		forwardEvent(jammer, outA, 3, 1_000_000, detectedAt),
+	)
+	testClock.SetTime(detectedAt.Add(10 * time.Minute))
+	detector.check()
+
+	incidents = detector.Incidents()
+	require.Len(t, incidents, 2)
+	require.Equal(t, IncidentLiquidityJamming, incidents[0].Type)
+	require.Equal(t, 4, incidents[0].HTLCs)
+	require.EqualValues(t, 1_001_500, incidents[0].Amount)
+	require.Equal(t, IncidentSlotJamming, incidents[1].Type)
+	require.Equal(t, detectedAt, incidents[1].DetectedAt)
+
+	// Once the htlcs are resolved, the incidents are resolved, but the
+	// mitigation stays in place for the mitigation time.
+	detector.processEvent(&htlcswitch.SettleEvent{
+		HtlcKey: htlcswitch.HtlcKey{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: jammer,
+				HtlcID: 0,
+			},
+		},
+	})
+	detector.processEvent(&htlcswitch.ForwardingFailEvent{
+		HtlcKey: htlcswitch.HtlcKey{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: jammer,
+				HtlcID: 1,
+			},
+		},
+	})
+	detector.processEvent(&htlcswitch.LinkFailEvent{
+		HtlcKey: htlcswitch.HtlcKey{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: jammer,
+				HtlcID: 2,
+			},
+		},
+	})
+	detector.processEvent(&htlcswitch.FinalHtlcEvent{
+		CircuitKey: models.CircuitKey{ChanID: jammer, HtlcID: 3},
+	})
+
+	resolvedAt := detectedAt.Add(20 * time.Minute)
+	testClock.SetTime(resolvedAt)
+	detector.check()
+
+	for _, incident := range detector.Incidents() {
+		require.True(t, incident.Resolved)
+	}
+	require.True(t, detector.Mitigated(jammer))
+
+	testClock.SetTime(detectedAt.Add(10*time.Minute + time.Hour))
+	detector.check()
+	require.False(t, detector.Mitigated(jammer))
+	require.Empty(t, detector.mitigated)
+}
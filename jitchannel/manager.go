@@ -0,0 +1,473 @@
+// Package jitchannel opens channels to clients just in time for the htlcs
+// forwarded to them. A client is assigned a short channel id that it puts into
+// the route hints of its invoices. Once an htlc is forwarded to that short
+// channel id, the htlc is held, a zero-conf channel is opened to the client
+// and the htlc is released into the new channel.
+package jitchannel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// ErrUnknownChannel is returned when a short channel id isn't
+	// registered for a just-in-time channel.
+	ErrUnknownChannel = errors.New("unknown just-in-time channel")
+
+	// ErrChannelOpening is returned when a registration is cancelled while
+	// its channel is being opened.
+	ErrChannelOpening = errors.New("just-in-time channel is being opened")
+
+	// ErrClientOffline is returned when the client didn't come online in
+	// time for the channel open.
+	ErrClientOffline = errors.New("client is offline")
+
+	// ErrOpenTimeout is returned when the channel wasn't opened in time.
+	ErrOpenTimeout = errors.New("timeout opening just-in-time channel")
+
+	// ErrManagerShuttingDown is returned when the manager shuts down while
+	// a channel is being opened.
+	ErrManagerShuttingDown = errors.New("just-in-time channel manager " +
+		"shutting down")
+)
+
+// State is the state of a just-in-time channel.
+type State string
+
+const (
+	// StateWaiting is the state of a channel that waits for the first
+	// forward.
+	StateWaiting State = "waiting"
+
+	// StateOpening is the state of a channel that is being opened while
+	// its forwards are held.
+	StateOpening State = "opening"
+)
+
+// Config contains the dependencies and parameters of the manager.
+type Config struct {
+	// RequestAlias allocates a new alias short channel id, which is
+	// assigned to the client as the short channel id of its channel.
+	RequestAlias func() (lnwire.ShortChannelID, error)
+
+	// NotifyWhenOnline delivers the peer on the channel once it is
+	// connected.
+	NotifyWhenOnline func(peerKey [33]byte, peerChan chan<- lnpeer.Peer)
+
+	// OpenChannel opens a private zero-conf channel to the client on the
+	// given terms. It returns the funding outpoint once the channel can
+	// forward htlcs, or an error once the cancel channel is closed.
+	OpenChannel func(client *btcec.PublicKey, terms *Terms,
+		cancel <-chan struct{}) (*wire.OutPoint, error)
+
+	// AddAlias makes the channel reachable under the alias, so that the
+	// held forwards can be resumed into it.
+	AddAlias func(chanID lnwire.ChannelID,
+		alias lnwire.ShortChannelID) error
+
+	// Pricing decides on which terms a channel is opened.
+	Pricing PricingHook
+
+	// Clock is used to expire registrations and to time out opens.
+	Clock clock.Clock
+
+	// RegistrationTTL is the time a registration is kept while no forward
+	// arrives for it.
+	RegistrationTTL time.Duration
+
+	// OpenTimeout is the time we wait for the client to come online and
+	// for its channel to be opened before the held forwards are failed.
+	OpenTimeout time.Duration
+}
+
+// Registration is a short channel id assigned to a client for a channel that
+// is opened once the first forward arrives for it.
+type Registration struct {
+	// SCID is the short channel id to put into the route hints of the
+	// client's invoices.
+	SCID lnwire.ShortChannelID
+
+	// Client is the node the channel is opened to.
+	Client route.Vertex
+
+	// Expiry is the time the registration expires if no forward arrived
+	// for it.
+	Expiry time.Time
+
+	// State is the state of the channel.
+	State State
+
+	// HeldForwards is the number of forwards held for the channel.
+	HeldForwards int
+}
+
+// registration is a registration and the forwards held for it.
+type registration struct {
+	Registration
+
+	// forwards are the forwards held while the channel is opened.
+	forwards map[models.CircuitKey]htlcswitch.InterceptedForward
+}
+
+// Manager opens channels to its registered clients just in time for the
+// forwards to them.
+//
+// NOTE: Registrations are only kept in memory. Forwards to registrations lost
+// on restart are failed by the switch as forwards to unknown channels.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// registrations are the registrations by their short channel id.
+	registrations map[lnwire.ShortChannelID]*registration
+
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile time check to ensure Manager implements the JITChannelHandler
+// interface.
+var _ htlcswitch.JITChannelHandler = (*Manager)(nil)
+
+// NewManager creates a new just-in-time channel manager.
+func NewManager(cfg *Config) *Manager {
+	return &Manager{
+		cfg:           cfg,
+		registrations: make(map[lnwire.ShortChannelID]*registration),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start starts the manager.
+func (m *Manager) Start() error {
+	m.started.Do(func() {
+		log.Info("Just-in-time channel manager starting")
+	})
+
+	return nil
+}
+
+// Stop stops the manager and fails the held forwards of the channels that are
+// still being opened.
+func (m *Manager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Just-in-time channel manager shutting down...")
+		defer log.Debug("Just-in-time channel manager shutdown " +
+			"complete")
+
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// Register assigns a new short channel id to the client. The first forward to
+// the short channel id triggers a channel open to the client.
+func (m *Manager) Register(client route.Vertex) (*Registration, error) {
+	scid, err := m.cfg.RequestAlias()
+	if err != nil {
+		return nil, fmt.Errorf("unable to request alias: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reg := &registration{
+		Registration: Registration{
+			SCID:   scid,
+			Client: client,
+			Expiry: m.cfg.Clock.Now().Add(m.cfg.RegistrationTTL),
+			State:  StateWaiting,
+		},
+	}
+	m.registrations[scid] = reg
+
+	log.Infof("Registered just-in-time channel %v for client %v",
+		scid, client)
+
+	return reg.snapshot(), nil
+}
+
+// Cancel removes the registration of a channel that doesn't wait for its
+// first forward anymore. Channels that are being opened can't be cancelled.
+func (m *Manager) Cancel(scid lnwire.ShortChannelID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pruneExpired()
+
+	reg, ok := m.registrations[scid]
+	if !ok {
+		return ErrUnknownChannel
+	}
+
+	if reg.State == StateOpening {
+		return ErrChannelOpening
+	}
+
+	delete(m.registrations, scid)
+
+	log.Infof("Cancelled just-in-time channel %v", scid)
+
+	return nil
+}
+
+// Registrations returns the registrations that didn't expire, ordered by their
+// short channel id.
+func (m *Manager) Registrations() []*Registration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pruneExpired()
+
+	regs := make([]*Registration, 0, len(m.registrations))
+	for _, reg := range m.registrations {
+		regs = append(regs, reg.snapshot())
+	}
+
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].SCID.ToUint64() < regs[j].SCID.ToUint64()
+	})
+
+	return regs
+}
+
+// IsJITChannel returns true if the short channel id is registered for a
+// channel that is yet to be opened.
+//
+// NOTE: This is part of the htlcswitch.JITChannelHandler interface.
+func (m *Manager) IsJITChannel(scid lnwire.ShortChannelID) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pruneExpired()
+
+	_, ok := m.registrations[scid]
+
+	return ok
+}
+
+// HandleForward holds the forward until the channel it is sent to is opened.
+// The first forward triggers the open.
+//
+// NOTE: This is part of the htlcswitch.JITChannelHandler interface.
+func (m *Manager) HandleForward(fwd htlcswitch.InterceptedForward) {
+	packet := fwd.Packet()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reg, ok := m.registrations[packet.OutgoingChanID]
+	if !ok {
+		// The channel was opened or the registration removed since
+		// the switch checked it. Either way, the switch knows best
+		// what to do with the forward.
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+
+			m.resolve(fwd, nil)
+		}()
+
+		return
+	}
+
+	// Forwards are replayed when the incoming link restarts, in which
+	// case we already hold them.
+	if _, ok := reg.forwards[packet.IncomingCircuit]; ok {
+		return
+	}
+
+	if reg.forwards == nil {
+		reg.forwards = make(
+			map[models.CircuitKey]htlcswitch.InterceptedForward,
+		)
+	}
+	reg.forwards[packet.IncomingCircuit] = fwd
+
+	log.Debugf("Holding forward %v for just-in-time channel %v",
+		packet.IncomingCircuit, reg.SCID)
+
+	if reg.State == StateOpening {
+		return
+	}
+	reg.State = StateOpening
+
+	req := &OpenRequest{
+		Client:         reg.Client,
+		SCID:           reg.SCID,
+		Hash:           packet.Hash,
+		IncomingAmount: packet.IncomingAmount,
+		OutgoingAmount: packet.OutgoingAmount,
+	}
+
+	m.wg.Add(1)
+	go m.openChannel(req)
+}
+
+// openChannel opens the channel of the request and resolves the held forwards
+// once it is open or failed to open.
+func (m *Manager) openChannel(req *OpenRequest) {
+	defer m.wg.Done()
+
+	err := m.tryOpenChannel(req)
+
+	m.mu.Lock()
+	reg := m.registrations[req.SCID]
+	forwards := reg.forwards
+	reg.forwards = nil
+
+	// The registration is done once its channel is open. Otherwise, it
+	// waits for the next forward to retry.
+	if err == nil {
+		delete(m.registrations, req.SCID)
+
+		log.Infof("Opened just-in-time channel %v to client %v, "+
+			"resuming %d forwards", req.SCID, req.Client,
+			len(forwards))
+	} else {
+		reg.State = StateWaiting
+
+		log.Errorf("Unable to open just-in-time channel %v to client "+
+			"%v, failing %d forwards: %v", req.SCID, req.Client,
+			len(forwards), err)
+	}
+	m.mu.Unlock()
+
+	for _, fwd := range forwards {
+		m.resolve(fwd, err)
+	}
+}
+
+// tryOpenChannel prices the channel of the request, waits for the client to
+// come online and opens the channel to it.
+func (m *Manager) tryOpenChannel(req *OpenRequest) error {
+	terms, err := m.cfg.Pricing(req)
+	if err != nil {
+		return fmt.Errorf("open rejected: %w", err)
+	}
+
+	clientKey, err := btcec.ParsePubKey(req.Client[:])
+	if err != nil {
+		return err
+	}
+
+	// Cancel the open once the timeout passes or we shut down.
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+
+	var timedOut bool
+	timeout := m.cfg.Clock.TickAfter(m.cfg.OpenTimeout)
+	go func() {
+		select {
+		case <-timeout:
+			timedOut = true
+		case <-m.quit:
+		case <-done:
+			return
+		}
+
+		close(cancel)
+	}()
+
+	cancelErr := func() error {
+		<-cancel
+		if timedOut {
+			return ErrOpenTimeout
+		}
+
+		return ErrManagerShuttingDown
+	}
+
+	peerChan := make(chan lnpeer.Peer, 1)
+	m.cfg.NotifyWhenOnline(req.Client, peerChan)
+
+	select {
+	case <-peerChan:
+
+	case <-cancel:
+		if err := cancelErr(); !errors.Is(err, ErrOpenTimeout) {
+			return err
+		}
+
+		return ErrClientOffline
+	}
+
+	log.Infof("Opening just-in-time channel %v to client %v with "+
+		"capacity %v", req.SCID, req.Client, terms.Capacity)
+
+	chanPoint, err := m.cfg.OpenChannel(clientKey, terms, cancel)
+	if err != nil {
+		select {
+		case <-cancel:
+			return fmt.Errorf("%w: %w", cancelErr(), err)
+		default:
+			return err
+		}
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(*chanPoint)
+	if err := m.cfg.AddAlias(chanID, req.SCID); err != nil {
+		return fmt.Errorf("unable to add alias to channel %v: %w",
+			chanPoint, err)
+	}
+
+	return nil
+}
+
+// resolve resumes the forward if its channel was opened, or fails it back if
+// the open failed.
+func (m *Manager) resolve(fwd htlcswitch.InterceptedForward, openErr error) {
+	var err error
+	if openErr == nil {
+		err = fwd.Resume()
+	} else {
+		err = fwd.FailWithCode(lnwire.CodeTemporaryChannelFailure)
+	}
+
+	if err != nil {
+		log.Errorf("Unable to resolve forward %v: %v",
+			fwd.Packet().IncomingCircuit, err)
+	}
+}
+
+// pruneExpired removes the expired registrations that wait for their first
+// forward. The caller must hold the lock.
+func (m *Manager) pruneExpired() {
+	now := m.cfg.Clock.Now()
+	for scid, reg := range m.registrations {
+		if reg.State == StateWaiting && !now.Before(reg.Expiry) {
+			log.Debugf("Just-in-time channel %v for client %v "+
+				"expired", scid, reg.Client)
+
+			delete(m.registrations, scid)
+		}
+	}
+}
+
+// snapshot returns a copy of the registration.
+func (r *registration) snapshot() *Registration {
+	reg := r.Registration
+	reg.HeldForwards = len(r.forwards)
+
+	return &reg
+}
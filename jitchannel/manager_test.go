@@ -0,0 +1,353 @@
+package jitchannel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+// mockForward is an intercepted forward that reports how it is resolved.
+type mockForward struct {
+	packet   htlcswitch.InterceptedPacket
+	resolved chan string
+}
+
+func newMockForward(htlcID uint64,
+	scid lnwire.ShortChannelID) *mockForward {
+
+	return &mockForward{
+		packet: htlcswitch.InterceptedPacket{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: lnwire.NewShortChanIDFromInt(1),
+				HtlcID: htlcID,
+			},
+			OutgoingChanID: scid,
+			IncomingAmount: 101_000,
+			OutgoingAmount: 100_000,
+		},
+		resolved: make(chan string, 1),
+	}
+}
+
+func (f *mockForward) Packet() htlcswitch.InterceptedPacket {
+	return f.packet
+}
+
+func (f *mockForward) Resume() error {
+	f.resolved <- "resume"
+	return nil
+}
+
+func (f *mockForward) Settle(lntypes.Preimage) error {
+	f.resolved <- "settle"
+	return nil
+}
+
+func (f *mockForward) Fail([]byte) error {
+	f.resolved <- "fail"
+	return nil
+}
+
+func (f *mockForward) FailWithCode(lnwire.FailCode) error {
+	f.resolved <- "fail"
+	return nil
+}
+
+// assertResolved asserts that the forward is resolved with the action.
+func (f *mockForward) assertResolved(t *testing.T, action string) {
+	t.Helper()
+
+	select {
+	case resolved := <-f.resolved:
+		require.Equal(t, action, resolved)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("forward not resolved")
+	}
+}
+
+// assertHeld asserts that the forward isn't resolved.
+func (f *mockForward) assertHeld(t *testing.T) {
+	t.Helper()
+
+	select {
+	case resolved := <-f.resolved:
+		t.Fatalf("forward resolved with %v", resolved)
+
+	default:
+	}
+}
+
+// openRequest is a channel open requested by the manager.
+type openRequest struct {
+	client *btcec.PublicKey
+	terms  *Terms
+	result chan error
+}
+
+// testContext holds a manager and the mocks of its dependencies.
+type testContext struct {
+	manager    *Manager
+	clock      *clock.TestClock
+	tickSignal chan time.Duration
+
+	client route.Vertex
+	online chan struct{}
+	opens  chan *openRequest
+	alias  chan lnwire.ShortChannelID
+
+	nextAlias uint64
+}
+
+func newTestContext(t *testing.T) *testContext {
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	tickSignal := make(chan time.Duration, 10)
+	ctx := &testContext{
+		clock: clock.NewTestClockWithTickSignal(
+			time.Unix(1_700_000_000, 0), tickSignal,
+		),
+		tickSignal: tickSignal,
+		client:     route.NewVertex(priv.PubKey()),
+		online:     make(chan struct{}),
+		opens:      make(chan *openRequest),
+		alias:      make(chan lnwire.ShortChannelID, 1),
+		nextAlias:  16_000_000 << 40,
+	}
+
+	ctx.manager = NewManager(&Config{
+		RequestAlias: func() (lnwire.ShortChannelID, error) {
+			ctx.nextAlias++
+			return lnwire.NewShortChanIDFromInt(ctx.nextAlias), nil
+		},
+		NotifyWhenOnline: func(_ [33]byte,
+			peerChan chan<- lnpeer.Peer) {
+
+			go func() {
+				<-ctx.online
+				peerChan <- nil
+			}()
+		},
+		OpenChannel: func(client *btcec.PublicKey, terms *Terms,
+			cancel <-chan struct{}) (*wire.OutPoint, error) {
+
+			req := &openRequest{
+				client: client,
+				terms:  terms,
+				result: make(chan error, 1),
+			}
+			ctx.opens <- req
+
+			select {
+			case err := <-req.result:
+				if err != nil {
+					return nil, err
+				}
+
+				return &wire.OutPoint{Index: 1}, nil
+
+			case <-cancel:
+				return nil, errors.New("cancelled")
+			}
+		},
+		AddAlias: func(_ lnwire.ChannelID,
+			alias lnwire.ShortChannelID) error {
+
+			ctx.alias <- alias
+			return nil
+		},
+		Pricing:         StaticPricing(100_000, 1_000_000, 1000, 10),
+		Clock:           ctx.clock,
+		RegistrationTTL: time.Hour,
+		OpenTimeout:     time.Minute,
+	})
+
+	require.NoError(t, ctx.manager.Start())
+	t.Cleanup(func() {
+		require.NoError(t, ctx.manager.Stop())
+	})
+
+	return ctx
+}
+
+// expectOpen waits for the manager to open a channel.
+func (c *testContext) expectOpen(t *testing.T) *openRequest {
+	t.Helper()
+
+	select {
+	case req := <-c.opens:
+		require.Equal(t, c.client, route.NewVertex(req.client))
+		return req
+
+	case <-time.After(testTimeout):
+		t.Fatalf("channel not opened")
+		return nil
+	}
+}
+
+// TestJITChannelOpen tests that forwards to a registered channel are held
+// until the channel is opened, and resumed into it afterwards.
+func TestJITChannelOpen(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext(t)
+	m := ctx.manager
+
+	reg, err := m.Register(ctx.client)
+	require.NoError(t, err)
+	require.Equal(t, StateWaiting, reg.State)
+	require.True(t, m.IsJITChannel(reg.SCID))
+	require.False(t, m.IsJITChannel(lnwire.NewShortChanIDFromInt(1)))
+
+	// The first forward triggers the open once the client is online. The
+	// parts of the payment that arrive meanwhile are held as well.
+	fwd1 := newMockForward(1, reg.SCID)
+	fwd2 := newMockForward(2, reg.SCID)
+	m.HandleForward(fwd1)
+	m.HandleForward(fwd1)
+	m.HandleForward(fwd2)
+
+	regs := m.Registrations()
+	require.Len(t, regs, 1)
+	require.Equal(t, StateOpening, regs[0].State)
+	require.Equal(t, 2, regs[0].HeldForwards)
+
+	// Registrations can't be cancelled while their channel is opened.
+	require.ErrorIs(t, m.Cancel(reg.SCID), ErrChannelOpening)
+
+	close(ctx.online)
+	req := ctx.expectOpen(t)
+	require.Equal(t, btcutil.Amount(100_000), req.terms.Capacity)
+	require.EqualValues(t, 1000, req.terms.BaseFee)
+
+	fwd1.assertHeld(t)
+	req.result <- nil
+
+	// Once the channel is open, it is reachable under the registered short
+	// channel id and the forwards are resumed into it.
+	require.Equal(t, reg.SCID, <-ctx.alias)
+	fwd1.assertResolved(t, "resume")
+	fwd2.assertResolved(t, "resume")
+
+	require.False(t, m.IsJITChannel(reg.SCID))
+	require.Empty(t, m.Registrations())
+
+	// Forwards that race with the open are resumed as well.
+	fwd3 := newMockForward(3, reg.SCID)
+	m.HandleForward(fwd3)
+	fwd3.assertResolved(t, "resume")
+}
+
+// TestJITChannelOpenFailure tests that held forwards are failed if the channel
+// can't be opened, and that the registration waits for a retry afterwards.
+func TestJITChannelOpenFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext(t)
+	m := ctx.manager
+
+	reg, err := m.Register(ctx.client)
+	require.NoError(t, err)
+
+	// The client doesn't come online in time.
+	fwd1 := newMockForward(1, reg.SCID)
+	m.HandleForward(fwd1)
+
+	select {
+	case <-ctx.tickSignal:
+	case <-time.After(testTimeout):
+		t.Fatalf("open timeout not started")
+	}
+	ctx.clock.SetTime(ctx.clock.Now().Add(time.Minute))
+
+	fwd1.assertResolved(t, "fail")
+	require.True(t, m.IsJITChannel(reg.SCID))
+
+	// The open fails.
+	close(ctx.online)
+	fwd2 := newMockForward(2, reg.SCID)
+	m.HandleForward(fwd2)
+
+	req := ctx.expectOpen(t)
+	req.result <- errors.New("funding failed")
+	fwd2.assertResolved(t, "fail")
+
+	regs := m.Registrations()
+	require.Len(t, regs, 1)
+	require.Equal(t, StateWaiting, regs[0].State)
+	require.Zero(t, regs[0].HeldForwards)
+
+	// Payments that are too large for a channel are rejected by the
+	// pricing hook.
+	fwd3 := newMockForward(3, reg.SCID)
+	fwd3.packet.OutgoingAmount = 1_000_000_000
+	m.HandleForward(fwd3)
+	fwd3.assertResolved(t, "fail")
+}
+
+// TestJITChannelRegistrations tests that registrations expire and can be
+// cancelled.
+func TestJITChannelRegistrations(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext(t)
+	m := ctx.manager
+
+	reg1, err := m.Register(ctx.client)
+	require.NoError(t, err)
+
+	ctx.clock.SetTime(ctx.clock.Now().Add(time.Minute))
+
+	reg2, err := m.Register(ctx.client)
+	require.NoError(t, err)
+	require.NotEqual(t, reg1.SCID, reg2.SCID)
+	require.Len(t, m.Registrations(), 2)
+
+	require.NoError(t, m.Cancel(reg2.SCID))
+	require.ErrorIs(t, m.Cancel(reg2.SCID), ErrUnknownChannel)
+
+	// Once expired, forwards are no longer taken over.
+	ctx.clock.SetTime(reg1.Expiry)
+	require.False(t, m.IsJITChannel(reg1.SCID))
+	require.Empty(t, m.Registrations())
+}
+
+// TestStaticPricing tests the capacity of channels opened by the static
+// pricing hook.
+func TestStaticPricing(t *testing.T) {
+	t.Parallel()
+
+	pricing := StaticPricing(100_000, 1_000_000, 1000, 10)
+
+	// Small payments get channels of the minimum capacity.
+	terms, err := pricing(&OpenRequest{OutgoingAmount: 1_000_000})
+	require.NoError(t, err)
+	require.Equal(t, &Terms{
+		Capacity: 100_000,
+		BaseFee:  1000,
+		FeeRate:  10,
+	}, terms)
+
+	// Larger payments get twice their amount, rounded up to satoshis.
+	terms, err = pricing(&OpenRequest{OutgoingAmount: 200_000_001})
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(400_002), terms.Capacity)
+
+	_, err = pricing(&OpenRequest{OutgoingAmount: 500_001_000})
+	require.ErrorIs(t, err, ErrPaymentTooLarge)
+}
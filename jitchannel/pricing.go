@@ -0,0 +1,87 @@
+package jitchannel
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// ErrPaymentTooLarge is returned by the static pricing hook if a payment
+// doesn't fit into the largest channel we open.
+var ErrPaymentTooLarge = errors.New("payment too large for a just-in-time " +
+	"channel")
+
+// OpenRequest describes the first forward to a just-in-time channel, which
+// triggers the channel open.
+type OpenRequest struct {
+	// Client is the node the channel is opened to.
+	Client route.Vertex
+
+	// SCID is the short channel id the forward was sent to.
+	SCID lnwire.ShortChannelID
+
+	// Hash is the payment hash of the forward.
+	Hash lntypes.Hash
+
+	// IncomingAmount is the amount of the incoming htlc.
+	IncomingAmount lnwire.MilliSatoshi
+
+	// OutgoingAmount is the amount to forward to the client.
+	OutgoingAmount lnwire.MilliSatoshi
+}
+
+// Terms are the terms a just-in-time channel is opened on.
+type Terms struct {
+	// Capacity is the capacity of the channel, which we fund entirely.
+	Capacity btcutil.Amount
+
+	// BaseFee is the base fee of the forwarding policy of the channel.
+	// The held forwards must pay it, so it is how the open is charged
+	// for.
+	BaseFee lnwire.MilliSatoshi
+
+	// FeeRate is the proportional fee of the forwarding policy of the
+	// channel in parts per million.
+	FeeRate uint64
+}
+
+// PricingHook decides on which terms a just-in-time channel is opened for the
+// given request. Returning an error rejects the open, which fails the held
+// forwards back.
+type PricingHook func(req *OpenRequest) (*Terms, error)
+
+// StaticPricing returns a pricing hook that opens channels of at least the
+// minimum capacity, but large enough to carry the payment, with the given
+// forwarding policy. Payments that don't fit into a channel of the maximum
+// capacity are rejected.
+func StaticPricing(minCapacity, maxCapacity btcutil.Amount,
+	baseFee lnwire.MilliSatoshi, feeRate uint64) PricingHook {
+
+	return func(req *OpenRequest) (*Terms, error) {
+		capacity := minCapacity
+
+		// Leave room for the payment, rounded up to full satoshis.
+		// The commitment fees are paid from our balance as well, so
+		// we double the payment to not run short of them.
+		required := 2 * (req.OutgoingAmount + 999).ToSatoshis()
+		if required > capacity {
+			capacity = required
+		}
+
+		if capacity > maxCapacity {
+			return nil, fmt.Errorf("%w: %v exceeds %v",
+				ErrPaymentTooLarge, req.OutgoingAmount,
+				maxCapacity)
+		}
+
+		return &Terms{
+			Capacity: capacity,
+			BaseFee:  baseFee,
+			FeeRate:  feeRate,
+		}, nil
+	}
+}
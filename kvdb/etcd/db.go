@@ -128,6 +128,11 @@ type db struct {
 	commitStatsCollector *commitStatsCollector
 	txQueue              *commitQueue
 	txMutex              sync.RWMutex
+
+	// fencingToken is the encoded fencing token that all transactions
+	// are conditional on, if set.
+	fencingToken string
+	fenceMtx     sync.RWMutex
 }
 
 // Enforce db implements the walletdb.DB interface.
@@ -211,6 +216,12 @@ func (db *db) getSTMOptions() []STMOptionFunc {
 		)
 	}
 
+	db.fenceMtx.RLock()
+	if db.fencingToken != "" {
+		opts = append(opts, WithFencingToken(db.fencingToken))
+	}
+	db.fenceMtx.RUnlock()
+
 	return opts
 }
 
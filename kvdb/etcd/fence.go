@@ -0,0 +1,106 @@
+//go:build kvdb_etcd
+// +build kvdb_etcd
+
+package etcd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	v3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	// fenceKey is the key that holds the fencing token of the current
+	// writer. It can't collide with bucket or value keys, which are
+	// always prefixed by a bucket id, or with sequence keys.
+	fenceKey = "$fence$"
+
+	// ErrFenced is returned when a transaction is rejected because the
+	// database was fenced by a writer holding a newer fencing token.
+	ErrFenced = errors.New("database fenced by a newer writer")
+)
+
+// encodeFencingToken encodes the fencing token such that the lexicographic
+// order of the encoded tokens matches their numeric order, which allows etcd
+// to compare them.
+func encodeFencingToken(token uint64) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], token)
+
+	return string(b[:])
+}
+
+// WithFencingToken makes the STM only commit if the fencing token stored in
+// the database still matches the given one. If the database was fenced by a
+// newer token in the meantime, the commit fails with ErrFenced.
+func WithFencingToken(token string) STMOptionFunc {
+	return func(so *STMOptions) {
+		so.fencingToken = token
+	}
+}
+
+// SetFencingToken stores the given fencing token in the database and makes
+// all subsequent transactions of this database instance conditional on the
+// token still being current. Fencing tokens must increase with every new
+// writer, for example by using the revision of an election key, so that
+// the writes of a previous writer that still believes it's active are
+// rejected once a new writer has fenced the database. An error is returned if
+// the database is already fenced by a newer token.
+func (db *db) SetFencingToken(token uint64) error {
+	encoded := encodeFencingToken(token)
+
+	for {
+		// Store the token if the database wasn't fenced before, or
+		// if it was fenced with an older or the same token.
+		txn := db.cli.Txn(db.ctx).If(
+			v3.Compare(v3.CreateRevision(fenceKey), "=", 0),
+		).Then(
+			v3.OpPut(fenceKey, encoded),
+		).Else(
+			v3.OpGet(fenceKey),
+		)
+
+		resp, err := txn.Commit()
+		if err != nil {
+			return err
+		}
+
+		if resp.Succeeded {
+			break
+		}
+
+		kvs := resp.Responses[0].GetResponseRange().Kvs
+		if len(kvs) != 1 {
+			continue
+		}
+
+		current := string(kvs[0].Value)
+		if current > encoded {
+			return fmt.Errorf("%w: current token %x, ours %x",
+				ErrFenced, current, encoded)
+		}
+
+		// Replace the current token unless it was changed in the
+		// meantime, in which case we'll try again.
+		resp, err = db.cli.Txn(db.ctx).If(
+			v3.Compare(v3.Value(fenceKey), "=", current),
+		).Then(
+			v3.OpPut(fenceKey, encoded),
+		).Commit()
+		if err != nil {
+			return err
+		}
+
+		if resp.Succeeded {
+			break
+		}
+	}
+
+	db.fenceMtx.Lock()
+	db.fencingToken = encoded
+	db.fenceMtx.Unlock()
+
+	return nil
+}
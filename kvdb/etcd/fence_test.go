@@ -0,0 +1,61 @@
+//go:build kvdb_etcd
+// +build kvdb_etcd
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFencingToken tests that once a database is fenced with a newer token,
+// transactions of database instances using an older token are rejected.
+func TestFencingToken(t *testing.T) {
+	t.Parallel()
+
+	f := NewEtcdTestFixture(t)
+
+	db1, err := newEtcdBackend(context.TODO(), f.BackendConfig())
+	require.NoError(t, err)
+
+	db2, err := newEtcdBackend(context.TODO(), f.BackendConfig())
+	require.NoError(t, err)
+
+	put := func(db *db, val string) error {
+		return db.Update(func(tx walletdb.ReadWriteTx) error {
+			bucket, err := tx.CreateTopLevelBucket([]byte("apple"))
+			if err != nil {
+				return err
+			}
+
+			return bucket.Put([]byte("key"), []byte(val))
+		}, func() {})
+	}
+
+	// The first writer fences the database and can write.
+	require.NoError(t, db1.SetFencingToken(1))
+	require.NoError(t, put(db1, "val1"))
+
+	// Fencing again with the same token is allowed.
+	require.NoError(t, db1.SetFencingToken(1))
+
+	// The second writer fences the first one out.
+	require.NoError(t, db2.SetFencingToken(2))
+	require.NoError(t, put(db2, "val2"))
+
+	// Both writes and reads of the first writer are rejected now.
+	require.ErrorIs(t, put(db1, "val3"), ErrFenced)
+	err = db1.View(func(tx walletdb.ReadTx) error {
+		require.NotNil(t, tx.ReadBucket([]byte("apple")))
+		return nil
+	}, func() {})
+	require.ErrorIs(t, err, ErrFenced)
+
+	// The first writer can't take over with its old token.
+	require.ErrorIs(t, db1.SetFencingToken(1), ErrFenced)
+
+	require.Equal(t, "val2", f.Dump()[ValueKey("key", "apple")])
+}
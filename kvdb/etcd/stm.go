@@ -189,6 +189,10 @@ type STMOptions struct {
 	// ctx holds an externally provided abort context.
 	ctx                 context.Context
 	commitStatsCallback func(bool, CommitStats)
+
+	// fencingToken is the encoded fencing token that must still be
+	// stored in the database for the STM to commit, if set.
+	fencingToken string
 }
 
 // STMOptionFunc is a function that updates the passed STMOptions.
@@ -1103,16 +1107,29 @@ func (s *stm) commit() (CommitStats, error) {
 
 	// Create the compare set.
 	cmps := append(rset, wset...)
+
+	// Prefetch keys and ranges in case of conflict to save as many
+	// round-trips as possible.
+	prefetch := s.rset.prefetchSet()
+
+	// If the database is fenced, we'll only commit if our fencing token
+	// is still current. We also fetch the token in case the commit fails,
+	// to tell a conflict from a newer writer fencing us out.
+	fenced := s.options.fencingToken != ""
+	if fenced {
+		cmps = append(cmps, v3.Compare(
+			v3.Value(fenceKey), "=", s.options.fencingToken,
+		))
+		prefetch = append(prefetch, v3.OpGet(fenceKey))
+	}
+
 	// Create a transaction with the optional abort context.
 	txn := s.client.Txn(s.options.ctx)
 
 	// If the compare set holds, try executing the puts.
 	txn = txn.If(cmps...)
 	txn = txn.Then(s.wset.puts()...)
-
-	// Prefetch keys and ranges in case of conflict to save as many
-	// round-trips as possible.
-	txn = txn.Else(s.rset.prefetchSet()...)
+	txn = txn.Else(prefetch...)
 
 	s.callCount++
 	txnresp, err := txn.Commit()
@@ -1132,14 +1149,31 @@ func (s *stm) commit() (CommitStats, error) {
 		return stats, nil
 	}
 
+	responses := txnresp.Responses
+	if fenced {
+		fenceResp := responses[len(responses)-1].GetResponseRange()
+		responses = responses[:len(responses)-1]
+
+		// Retrying is pointless if we were fenced out, as our token
+		// won't become current again.
+		if len(fenceResp.Kvs) != 1 || string(fenceResp.Kvs[0].Value) !=
+			s.options.fencingToken {
+
+			return stats, &DatabaseError{
+				msg: "stm.Commit() failed",
+				err: ErrFenced,
+			}
+		}
+	}
+
 	// Determine where our fetched full ranges begin in the response.
 	prefixes := s.rset.getFullRanges()
-	firstPrefixResp := len(txnresp.Responses) - len(prefixes)
+	firstPrefixResp := len(responses) - len(prefixes)
 
 	// Clear reload and preload it with the prefetched keys and ranges.
 	s.rset.clear()
-	s.rset.add(txnresp.Responses[:firstPrefixResp])
-	s.rset.addFullRange(prefixes, txnresp.Responses[firstPrefixResp:])
+	s.rset.add(responses[:firstPrefixResp])
+	s.rset.addFullRange(prefixes, responses[firstPrefixResp:])
 
 	// Set our revision boundary.
 	s.revision = txnresp.Header.Revision
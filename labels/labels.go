@@ -12,15 +12,27 @@
 // For version 0 we have the following optional data fields defined:
 //   - shortchanid: the short channel ID that a transaction is associated with,
 //     with its value set to the uint64 short channel id.
+//   - chanpoint: the channel point of the channel a transaction is associated
+//     with, formatted as {txid}_{output index}.
+//   - alias: the alias of the channel peer at the time the label was created.
+//
+// A transaction can carry multiple labels, which are stored in the wallet as a
+// single label with the individual labels separated by semicolons.
 package labels
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+// Separator separates the individual labels of a transaction that carries
+// multiple labels.
+const Separator = ";"
+
 // External labels a transaction as user initiated via the api. This
 // label is only used when a custom user provided label is not given.
 const External = "external"
@@ -76,6 +88,13 @@ type LabelField string
 const (
 	// ShortChanID is used to tag short channel id values in our labels.
 	ShortChanID LabelField = "shortchanid"
+
+	// ChanPoint is used to tag channel point values in our labels.
+	ChanPoint LabelField = "chanpoint"
+
+	// PeerAlias is used to tag the alias of the channel peer in our
+	// labels.
+	PeerAlias LabelField = "alias"
 )
 
 // MakeLabel creates a label with the provided type and short channel id. If
@@ -90,3 +109,89 @@ func MakeLabel(labelType LabelType, channelID *lnwire.ShortChannelID) string {
 	return fmt.Sprintf("%v:%v:%v-%v", LabelVersionZero, labelType,
 		ShortChanID, channelID.ToUint64())
 }
+
+// MakeChannelLabel creates a label like MakeLabel, extended with the channel
+// point of the channel and the alias of the channel peer. The alias is omitted
+// if it is empty. Characters of the alias that are used to separate fields or
+// labels are replaced.
+func MakeChannelLabel(labelType LabelType, channelID *lnwire.ShortChannelID,
+	chanPoint wire.OutPoint, peerAlias string) string {
+
+	label := fmt.Sprintf("%v:%v-%v_%v", MakeLabel(labelType, channelID),
+		ChanPoint, chanPoint.Hash, chanPoint.Index)
+
+	peerAlias = strings.TrimSpace(strings.Map(func(r rune) rune {
+		switch r {
+		case ':', ';', '\n', '\r':
+			return '_'
+
+		default:
+			return r
+		}
+	}, peerAlias))
+	if peerAlias == "" {
+		return label
+	}
+
+	return fmt.Sprintf("%v:%v-%v", label, PeerAlias, peerAlias)
+}
+
+// Split returns the individual labels of a transaction label.
+func Split(label string) []string {
+	var split []string
+	for _, part := range strings.Split(label, Separator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		split = append(split, part)
+	}
+
+	return split
+}
+
+// Join combines the given labels into a single transaction label, dropping
+// empty and duplicate labels. An error is returned if the result exceeds the
+// label length limit of the wallet.
+func Join(labels ...string) (string, error) {
+	var (
+		joined []string
+		seen   = make(map[string]struct{})
+	)
+	for _, label := range labels {
+		for _, part := range Split(label) {
+			if _, ok := seen[part]; ok {
+				continue
+			}
+			seen[part] = struct{}{}
+
+			joined = append(joined, part)
+		}
+	}
+
+	label := strings.Join(joined, Separator)
+	if len(label) > wtxmgr.TxLabelLimit {
+		return "", fmt.Errorf("label length: %v exceeds limit of %v",
+			len(label), wtxmgr.TxLabelLimit)
+	}
+
+	return label, nil
+}
+
+// Matches returns true if one of the individual labels of the transaction
+// label contains the query, ignoring case. An empty query matches any label.
+func Matches(label, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+
+	for _, part := range Split(label) {
+		if strings.Contains(strings.ToLower(part), query) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMakeChannelLabel tests that channel labels contain the channel point and
+// a sanitized peer alias.
+func TestMakeChannelLabel(t *testing.T) {
+	t.Parallel()
+
+	chanPoint := wire.OutPoint{
+		Hash:  chainhash.Hash{1},
+		Index: 2,
+	}
+	scid := lnwire.NewShortChanIDFromInt(123)
+
+	label := MakeChannelLabel(
+		LabelTypeChannelClose, &scid, chanPoint, " my;node: ",
+	)
+	require.Equal(
+		t, "0:closechannel:shortchanid-123:chanpoint-"+
+			chanPoint.Hash.String()+"_2:alias-my_node_", label,
+	)
+
+	label = MakeChannelLabel(LabelTypeChannelOpen, nil, chanPoint, "")
+	require.Equal(
+		t, "0:openchannel:chanpoint-"+chanPoint.Hash.String()+"_2",
+		label,
+	)
+}
+
+// TestJoinSplit tests that multiple labels are joined without duplicates and
+// split into the individual labels again.
+func TestJoinSplit(t *testing.T) {
+	t.Parallel()
+
+	label, err := Join("rent", "", "savings; rent", " taxes ")
+	require.NoError(t, err)
+	require.Equal(t, "rent;savings;taxes", label)
+	require.Equal(t, []string{"rent", "savings", "taxes"}, Split(label))
+	require.Empty(t, Split(""))
+
+	_, err = Join(strings.Repeat("a", wtxmgr.TxLabelLimit), "b")
+	require.Error(t, err)
+}
+
+// TestMatches tests that a query matches any of the individual labels.
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	label := "0:openchannel:alias-Bob;Invoice 42"
+
+	require.True(t, Matches(label, "alias-bob"))
+	require.True(t, Matches(label, "invoice"))
+	require.True(t, Matches(label, ""))
+	require.False(t, Matches(label, "bob;invoice"))
+	require.False(t, Matches(label, "closechannel"))
+}
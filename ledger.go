@@ -0,0 +1,427 @@
+package lnd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// LedgerFormatCSV exports the ledger as CSV with a header row.
+	LedgerFormatCSV = "csv"
+
+	// LedgerFormatJSON exports the ledger as a JSON array.
+	LedgerFormatJSON = "json"
+
+	// defaultLedgerEntries is the number of entries exported if no
+	// maximum is requested.
+	defaultLedgerEntries = 1000
+
+	// ledgerPageSize is the number of payments and forwards fetched from
+	// the database at once while building the ledger.
+	ledgerPageSize = 1000
+)
+
+// LedgerCategory is the kind of activity a ledger entry records.
+type LedgerCategory string
+
+const (
+	// LedgerOnChain is an on-chain transaction of the wallet, like a
+	// deposit, a withdrawal or a channel funding.
+	LedgerOnChain LedgerCategory = "onchain"
+
+	// LedgerSweep is an on-chain sweep of outputs of closed channels.
+	LedgerSweep LedgerCategory = "sweep"
+
+	// LedgerInvoice is a settled invoice.
+	LedgerInvoice LedgerCategory = "invoice"
+
+	// LedgerPayment is a succeeded outgoing payment.
+	LedgerPayment LedgerCategory = "payment"
+
+	// LedgerForward is a forwarded htlc that earned a fee.
+	LedgerForward LedgerCategory = "forward"
+)
+
+// LedgerEntry is a single balance change of the node. All entries use the same
+// semantics: Amount is the change of our balance before fees, positive for
+// incoming and negative for outgoing funds, and Fee is the fee we paid. The
+// net change of our balance is Amount - Fee. The fee earned by a forward is
+// recorded as its amount.
+type LedgerEntry struct {
+	// Timestamp is the time the balance changed: the block time of
+	// confirmed transactions, and the settle time of off-chain activity.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Category is the kind of activity.
+	Category LedgerCategory `json:"category"`
+
+	// Reference identifies the activity: the txid of on-chain entries, the
+	// payment hash of invoices and payments and the channel pair of
+	// forwards.
+	Reference string `json:"reference"`
+
+	// Amount is the change of our balance before fees in millisatoshis.
+	Amount int64 `json:"amount_msat"`
+
+	// Fee is the fee we paid in millisatoshis.
+	Fee int64 `json:"fee_msat"`
+
+	// Description is the label of a transaction or the memo of an
+	// invoice.
+	Description string `json:"description"`
+}
+
+// ledgerSources are the records the ledger is built from.
+type ledgerSources struct {
+	// transactions returns the transactions of the wallet.
+	transactions func() ([]*lnwallet.TransactionDetail, error)
+
+	// invoices queries the invoices.
+	invoices func(ctx context.Context, q invoices.InvoiceQuery) (
+		invoices.InvoiceSlice, error)
+
+	// payments queries the payments.
+	payments func(channeldb.PaymentsQuery) (channeldb.PaymentsResponse,
+		error)
+
+	// forwards queries the forwarding log.
+	forwards func(channeldb.ForwardingEventQuery) (
+		channeldb.ForwardingLogTimeSlice, error)
+}
+
+// buildLedger returns the entries with a timestamp in [start, end) from all
+// sources, ordered by their timestamp. Entries with the same timestamp are
+// ordered by category and reference, so the position of an entry is stable
+// as long as no activity is added to the range.
+func buildLedger(ctx context.Context, src *ledgerSources, start,
+	end time.Time) ([]*LedgerEntry, error) {
+
+	var entries []*LedgerEntry
+	add := func(entry *LedgerEntry) {
+		ts := entry.Timestamp
+		if ts.Before(start) || !ts.Before(end) {
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	txns, err := src.transactions()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list transactions: %w", err)
+	}
+	sweepLabel := labels.MakeLabel(labels.LabelTypeSweepTransaction, nil)
+	for _, tx := range txns {
+		// Unconfirmed transactions didn't change the balance yet.
+		if tx.NumConfirmations <= 0 {
+			continue
+		}
+
+		category := LedgerOnChain
+		if strings.HasPrefix(tx.Label, sweepLabel) {
+			category = LedgerSweep
+		}
+
+		// The value of a transaction is net of the fees we paid.
+		fee := satToMSat(btcutil.Amount(tx.TotalFees))
+		value := satToMSat(tx.Value)
+
+		add(&LedgerEntry{
+			Timestamp:   time.Unix(tx.Timestamp, 0),
+			Category:    category,
+			Reference:   tx.Hash.String(),
+			Amount:      value + fee,
+			Fee:         fee,
+			Description: tx.Label,
+		})
+	}
+
+	invoiceQuery := invoices.InvoiceQuery{NumMaxInvoices: ledgerPageSize}
+	for {
+		slice, err := src.invoices(ctx, invoiceQuery)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query invoices: %w",
+				err)
+		}
+
+		for _, invoice := range slice.Invoices {
+			if entry := invoiceLedgerEntry(&invoice); entry != nil {
+				add(entry)
+			}
+
+			// Every settled set of an AMP invoice is a separate
+			// payment.
+			for setID, ampState := range invoice.AMPState {
+				if ampState.State != invoices.HtlcStateSettled {
+					continue
+				}
+
+				ref := hex.EncodeToString(setID[:])
+				add(&LedgerEntry{
+					Timestamp:   ampState.SettleDate,
+					Category:    LedgerInvoice,
+					Reference:   ref,
+					Amount:      int64(ampState.AmtPaid),
+					Description: string(invoice.Memo),
+				})
+			}
+		}
+
+		if len(slice.Invoices) < ledgerPageSize {
+			break
+		}
+		invoiceQuery.IndexOffset = slice.LastIndexOffset
+	}
+
+	paymentQuery := channeldb.PaymentsQuery{MaxPayments: ledgerPageSize}
+	for {
+		resp, err := src.payments(paymentQuery)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query payments: %w",
+				err)
+		}
+
+		for _, payment := range resp.Payments {
+			if entry := paymentLedgerEntry(payment); entry != nil {
+				add(entry)
+			}
+		}
+
+		if len(resp.Payments) < ledgerPageSize {
+			break
+		}
+		paymentQuery.IndexOffset = resp.LastIndexOffset
+	}
+
+	forwardQuery := channeldb.ForwardingEventQuery{
+		StartTime:    start,
+		EndTime:      end,
+		NumMaxEvents: ledgerPageSize,
+	}
+	for {
+		slice, err := src.forwards(forwardQuery)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query forwarding "+
+				"log: %w", err)
+		}
+
+		for _, event := range slice.ForwardingEvents {
+			add(&LedgerEntry{
+				Timestamp: event.Timestamp,
+				Category:  LedgerForward,
+				Reference: fmt.Sprintf("%v:%v",
+					event.IncomingChanID.ToUint64(),
+					event.OutgoingChanID.ToUint64()),
+				Amount: int64(event.AmtIn - event.AmtOut),
+			})
+		}
+
+		if len(slice.ForwardingEvents) < ledgerPageSize {
+			break
+		}
+		forwardQuery.IndexOffset = slice.LastIndexOffset
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case !a.Timestamp.Equal(b.Timestamp):
+			return a.Timestamp.Before(b.Timestamp)
+
+		case a.Category != b.Category:
+			return a.Category < b.Category
+
+		default:
+			return a.Reference < b.Reference
+		}
+	})
+
+	return entries, nil
+}
+
+// satToMSat converts a possibly negative amount of satoshis to
+// millisatoshis.
+func satToMSat(amt btcutil.Amount) int64 {
+	return int64(amt) * 1000
+}
+
+// invoiceLedgerEntry returns the entry of a settled invoice, or nil if the
+// invoice isn't settled. AMP invoices are recorded per settled set instead.
+func invoiceLedgerEntry(invoice *invoices.Invoice) *LedgerEntry {
+	if invoice.IsAMP() || invoice.State != invoices.ContractSettled {
+		return nil
+	}
+
+	var reference string
+	if invoice.Terms.PaymentPreimage != nil {
+		reference = invoice.Terms.PaymentPreimage.Hash().String()
+	} else {
+		reference = "add_index:" + strconv.FormatUint(
+			invoice.AddIndex, 10,
+		)
+	}
+
+	return &LedgerEntry{
+		Timestamp:   invoice.SettleDate,
+		Category:    LedgerInvoice,
+		Reference:   reference,
+		Amount:      int64(invoice.AmtPaid),
+		Description: string(invoice.Memo),
+	}
+}
+
+// paymentLedgerEntry returns the entry of a succeeded payment, or nil if the
+// payment didn't succeed.
+func paymentLedgerEntry(payment *channeldb.MPPayment) *LedgerEntry {
+	if payment.Status != channeldb.StatusSucceeded {
+		return nil
+	}
+
+	// A payment completes once its last htlc settled.
+	var settleTime time.Time
+	for _, htlc := range payment.HTLCs {
+		if htlc.Settle != nil && htlc.Settle.SettleTime.After(
+			settleTime,
+		) {
+
+			settleTime = htlc.Settle.SettleTime
+		}
+	}
+
+	sent, fees := payment.SentAmt()
+
+	return &LedgerEntry{
+		Timestamp: settleTime,
+		Category:  LedgerPayment,
+		Reference: payment.Info.PaymentIdentifier.String(),
+		Amount:    -int64(sent),
+		Fee:       int64(fees),
+	}
+}
+
+// encodeLedger serializes the entries in the given format.
+func encodeLedger(entries []*LedgerEntry, format string) ([]byte, error) {
+	switch format {
+	case LedgerFormatJSON:
+		if entries == nil {
+			entries = []*LedgerEntry{}
+		}
+
+		return json.Marshal(entries)
+
+	case LedgerFormatCSV, "":
+		var b bytes.Buffer
+		w := csv.NewWriter(&b)
+
+		err := w.Write([]string{
+			"timestamp", "category", "reference", "amount_msat",
+			"fee_msat", "description",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			err := w.Write([]string{
+				entry.Timestamp.UTC().Format(time.RFC3339),
+				string(entry.Category),
+				entry.Reference,
+				strconv.FormatInt(entry.Amount, 10),
+				strconv.FormatInt(entry.Fee, 10),
+				entry.Description,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+
+		return b.Bytes(), w.Error()
+
+	default:
+		return nil, fmt.Errorf("unknown ledger format %q", format)
+	}
+}
+
+// ledgerFormats maps the RPC ledger formats to the formats of encodeLedger.
+var ledgerFormats = map[lnrpc.LedgerFormat]string{
+	lnrpc.LedgerFormat_LEDGER_FORMAT_CSV:  LedgerFormatCSV,
+	lnrpc.LedgerFormat_LEDGER_FORMAT_JSON: LedgerFormatJSON,
+}
+
+// exportLedger returns a page of the ledger for the request.
+func exportLedger(ctx context.Context, src *ledgerSources,
+	req *lnrpc.ExportLedgerRequest) (*lnrpc.ExportLedgerResponse, error) {
+
+	if req.StartTime > math.MaxInt64 || req.EndTime > math.MaxInt64 {
+		return nil, status.Error(codes.InvalidArgument, "invalid time "+
+			"range")
+	}
+
+	format, ok := ledgerFormats[req.Format]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown "+
+			"ledger format %v", req.Format)
+	}
+
+	end := time.Now()
+	if req.EndTime != 0 {
+		end = time.Unix(int64(req.EndTime), 0)
+	}
+	start := time.Unix(int64(req.StartTime), 0)
+	if !start.Before(end) {
+		return nil, status.Errorf(codes.InvalidArgument, "start "+
+			"time(%v) must be before end time(%v)", req.StartTime,
+			req.EndTime)
+	}
+
+	maxEntries := uint64(req.MaxEntries)
+	if maxEntries == 0 {
+		maxEntries = defaultLedgerEntries
+	}
+
+	entries, err := buildLedger(ctx, src, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	total := uint64(len(entries))
+	from := req.Offset
+	if from > total {
+		from = total
+	}
+	to := from + maxEntries
+	if to > total {
+		to = total
+	}
+	page := entries[from:to]
+
+	ledger, err := encodeLedger(page, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ExportLedgerResponse{
+		Ledger:       ledger,
+		NumEntries:   uint32(len(page)),
+		NextOffset:   to,
+		TotalEntries: total,
+	}, nil
+}
@@ -0,0 +1,248 @@
+package lnd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testLedgerSources returns sources with one record of each category, each
+// one second apart starting at the given time.
+func testLedgerSources(start time.Time) *ledgerSources {
+	at := func(i int) time.Time {
+		return start.Add(time.Duration(i) * time.Second)
+	}
+
+	preimage := lntypes.Preimage{1}
+
+	// A payment of 3000 msat paying 10 msat fees.
+	htlc := channeldb.HTLCAttempt{
+		HTLCAttemptInfo: channeldb.HTLCAttemptInfo{
+			Route: route.Route{
+				TotalAmount: 3_010,
+				Hops: []*route.Hop{{
+					AmtToForward: 3_000,
+				}},
+			},
+		},
+		Settle: &channeldb.HTLCSettleInfo{
+			SettleTime: at(3),
+		},
+	}
+
+	return &ledgerSources{
+		transactions: func() ([]*lnwallet.TransactionDetail, error) {
+			return []*lnwallet.TransactionDetail{{
+				// A withdrawal of 10k sat paying 500 sat fees.
+				Hash:             chainhash.Hash{1},
+				Value:            -10_500,
+				TotalFees:        500,
+				NumConfirmations: 3,
+				Timestamp:        at(0).Unix(),
+			}, {
+				Hash:             chainhash.Hash{2},
+				Value:            20_000,
+				NumConfirmations: 1,
+				Timestamp:        at(1).Unix(),
+				Label: labels.MakeLabel(
+					labels.LabelTypeSweepTransaction, nil,
+				),
+			}, {
+				// Unconfirmed transactions are skipped.
+				Hash:      chainhash.Hash{3},
+				Value:     30_000,
+				Timestamp: at(1).Unix(),
+			}}, nil
+		},
+		invoices: func(_ context.Context, _ invoices.InvoiceQuery) (
+			invoices.InvoiceSlice, error) {
+
+			return invoices.InvoiceSlice{
+				Invoices: []invoices.Invoice{{
+					Memo:       []byte("coffee"),
+					State:      invoices.ContractSettled,
+					SettleDate: at(2),
+					AmtPaid:    5_000,
+					Terms: invoices.ContractTerm{
+						PaymentPreimage: &preimage,
+					},
+				}, {
+					// Open invoices are skipped.
+					State: invoices.ContractOpen,
+				}},
+			}, nil
+		},
+		payments: func(_ channeldb.PaymentsQuery) (
+			channeldb.PaymentsResponse, error) {
+
+			return channeldb.PaymentsResponse{
+				Payments: []*channeldb.MPPayment{{
+					Info: &channeldb.PaymentCreationInfo{
+						PaymentIdentifier: lntypes.Hash{
+							2,
+						},
+					},
+					Status: channeldb.StatusSucceeded,
+					HTLCs:  []channeldb.HTLCAttempt{htlc},
+				}, {
+					// Failed payments are skipped.
+					Info: &channeldb.PaymentCreationInfo{
+						PaymentIdentifier: lntypes.Hash{
+							3,
+						},
+					},
+					Status: channeldb.StatusFailed,
+				}},
+			}, nil
+		},
+		forwards: func(_ channeldb.ForwardingEventQuery) (
+			channeldb.ForwardingLogTimeSlice, error) {
+
+			event := channeldb.ForwardingEvent{
+				Timestamp:      at(4),
+				IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+				OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+				AmtIn:          2_001,
+				AmtOut:         2_000,
+			}
+
+			return channeldb.ForwardingLogTimeSlice{
+				ForwardingEvents: []channeldb.ForwardingEvent{
+					event,
+				},
+			}, nil
+		},
+	}
+}
+
+// TestLedger tests that the ledger combines all sources with the same value
+// and fee semantics.
+func TestLedger(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	src := testLedgerSources(start)
+
+	entries, err := buildLedger(
+		context.Background(), src, start, start.Add(time.Hour),
+	)
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	categories := make([]LedgerCategory, 0, len(entries))
+	for _, entry := range entries {
+		categories = append(categories, entry.Category)
+	}
+	require.Equal(t, []LedgerCategory{
+		LedgerOnChain, LedgerSweep, LedgerInvoice, LedgerPayment,
+		LedgerForward,
+	}, categories)
+
+	// The withdrawal sent 10k sat and paid 500 sat fees.
+	require.EqualValues(t, -10_000_000, entries[0].Amount)
+	require.EqualValues(t, 500_000, entries[0].Fee)
+
+	require.EqualValues(t, 20_000_000, entries[1].Amount)
+	require.Zero(t, entries[1].Fee)
+
+	require.EqualValues(t, 5_000, entries[2].Amount)
+	require.Equal(t, "coffee", entries[2].Description)
+	preimage := lntypes.Preimage{1}
+	require.Equal(t, preimage.Hash().String(), entries[2].Reference)
+
+	require.EqualValues(t, -3_000, entries[3].Amount)
+	require.EqualValues(t, 10, entries[3].Fee)
+
+	require.EqualValues(t, 1, entries[4].Amount)
+	require.Equal(t, "1:2", entries[4].Reference)
+
+	// Only entries within the range are returned.
+	entries, err = buildLedger(
+		context.Background(), src, start.Add(time.Second),
+		start.Add(3*time.Second),
+	)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, LedgerSweep, entries[0].Category)
+	require.Equal(t, LedgerInvoice, entries[1].Category)
+}
+
+// TestExportLedger tests that the ledger is exported in pages in both formats.
+func TestExportLedger(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	src := testLedgerSources(start)
+
+	req := &lnrpc.ExportLedgerRequest{
+		StartTime:  uint64(start.Unix()),
+		EndTime:    uint64(start.Add(time.Hour).Unix()),
+		MaxEntries: 3,
+		Format:     lnrpc.LedgerFormat_LEDGER_FORMAT_CSV,
+	}
+	resp, err := exportLedger(context.Background(), src, req)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, resp.NumEntries)
+	require.EqualValues(t, 3, resp.NextOffset)
+	require.EqualValues(t, 5, resp.TotalEntries)
+
+	records, err := csv.NewReader(
+		strings.NewReader(string(resp.Ledger)),
+	).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	require.Equal(t, []string{
+		"timestamp", "category", "reference", "amount_msat",
+		"fee_msat", "description",
+	}, records[0])
+	require.Equal(t, "onchain", records[1][1])
+	require.Equal(t, "-10000000", records[1][3])
+	require.Equal(t, "500000", records[1][4])
+
+	// The next page contains the remaining entries.
+	req.Offset = resp.NextOffset
+	req.Format = lnrpc.LedgerFormat_LEDGER_FORMAT_JSON
+	resp, err = exportLedger(context.Background(), src, req)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, resp.NumEntries)
+	require.EqualValues(t, 5, resp.NextOffset)
+
+	var entries []*LedgerEntry
+	require.NoError(t, json.Unmarshal(resp.Ledger, &entries))
+	require.Len(t, entries, 2)
+	require.Equal(t, LedgerPayment, entries[0].Category)
+	require.Equal(t, LedgerForward, entries[1].Category)
+
+	// Past the end, an empty page is returned.
+	req.Offset = resp.NextOffset
+	resp, err = exportLedger(context.Background(), src, req)
+	require.NoError(t, err)
+	require.Zero(t, resp.NumEntries)
+	require.Equal(t, "[]", string(resp.Ledger))
+
+	// Unknown formats and empty ranges are rejected.
+	req.Format = lnrpc.LedgerFormat(2)
+	_, err = exportLedger(context.Background(), src, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req.Format = lnrpc.LedgerFormat_LEDGER_FORMAT_CSV
+	req.EndTime = req.StartTime
+	_, err = exportLedger(context.Background(), src, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,514 @@
+// Package liquidityalert tracks the liquidity of the channels, the peers and
+// the node as a whole, and alerts when it drops below configured thresholds or
+// channels stay one-sided for too long.
+package liquidityalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// EventRaised is the webhook event sent when an alert is raised.
+	EventRaised = "raised"
+
+	// EventResolved is the webhook event sent when the condition of an
+	// alert no longer holds.
+	EventResolved = "resolved"
+)
+
+// Kind is the liquidity condition an alert is raised for.
+type Kind string
+
+const (
+	// KindLowOutbound is raised when the local balance drops below its
+	// threshold.
+	KindLowOutbound Kind = "low_outbound"
+
+	// KindLowInbound is raised when the remote balance drops below its
+	// threshold.
+	KindLowInbound Kind = "low_inbound"
+
+	// KindOneSided is raised when almost all the capacity of a channel
+	// stayed on one side for too long.
+	KindOneSided Kind = "one_sided"
+)
+
+// kinds are all kinds of alerts.
+var kinds = []Kind{KindLowOutbound, KindLowInbound, KindOneSided}
+
+// Scope is what an alert is raised for.
+type Scope string
+
+const (
+	// ScopeChannel alerts are raised for a single channel.
+	ScopeChannel Scope = "channel"
+
+	// ScopePeer alerts are raised for all channels with a peer.
+	ScopePeer Scope = "peer"
+
+	// ScopeNode alerts are raised for all channels of the node.
+	ScopeNode Scope = "node"
+)
+
+// Channel describes the balances of an open channel.
+type Channel struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// Peer is the public key of the channel peer.
+	Peer route.Vertex
+
+	// Capacity is the capacity of the channel.
+	Capacity btcutil.Amount
+
+	// LocalBalance is our balance in the channel.
+	LocalBalance btcutil.Amount
+
+	// RemoteBalance is the balance of the peer in the channel.
+	RemoteBalance btcutil.Amount
+}
+
+// Thresholds are the liquidity levels below which alerts are raised. A zero
+// threshold disables the alerts it applies to.
+type Thresholds struct {
+	// MinChanOutboundRatio is the fraction of its capacity the local
+	// balance of a channel must not drop below.
+	MinChanOutboundRatio float64
+
+	// MinChanInboundRatio is the fraction of its capacity the remote
+	// balance of a channel must not drop below.
+	MinChanInboundRatio float64
+
+	// MinPeerOutbound is the total local balance of the channels with a
+	// peer that must not be undercut.
+	MinPeerOutbound btcutil.Amount
+
+	// MinPeerInbound is the total remote balance of the channels with a
+	// peer that must not be undercut.
+	MinPeerInbound btcutil.Amount
+
+	// MinNodeOutbound is the total local balance of all channels that must
+	// not be undercut.
+	MinNodeOutbound btcutil.Amount
+
+	// MinNodeInbound is the total remote balance of all channels that must
+	// not be undercut.
+	MinNodeInbound btcutil.Amount
+
+	// OneSidedRatio is the fraction of its capacity above which the local
+	// or remote balance of a channel makes the channel one-sided.
+	OneSidedRatio float64
+
+	// OneSidedDuration is the time a channel must stay one-sided before an
+	// alert is raised.
+	OneSidedDuration time.Duration
+}
+
+// Alert describes a liquidity condition that holds.
+type Alert struct {
+	// Kind is the condition that holds.
+	Kind Kind `json:"kind"`
+
+	// Scope is what the condition holds for.
+	Scope Scope `json:"scope"`
+
+	// ChanPoint is the channel of channel scoped alerts.
+	ChanPoint string `json:"chan_point,omitempty"`
+
+	// Peer is the public key of the peer of channel and peer scoped
+	// alerts.
+	Peer string `json:"peer,omitempty"`
+
+	// Outbound is the local balance within the scope.
+	Outbound btcutil.Amount `json:"outbound_sat"`
+
+	// Inbound is the remote balance within the scope.
+	Inbound btcutil.Amount `json:"inbound_sat"`
+
+	// Threshold is the balance that was undercut, or the balance above
+	// which a channel is one-sided.
+	Threshold btcutil.Amount `json:"threshold_sat"`
+
+	// Since is the time the condition was first detected.
+	Since time.Time `json:"since"`
+}
+
+// key identifies the condition of the alert across checks.
+func (a *Alert) key() string {
+	return fmt.Sprintf("%v/%v/%v/%v", a.Scope, a.Kind, a.Peer, a.ChanPoint)
+}
+
+// Notification is the JSON body posted to the alert webhook.
+type Notification struct {
+	// Event is either EventRaised or EventResolved.
+	Event string `json:"event"`
+
+	// Alert is the alert that was raised or resolved.
+	Alert *Alert `json:"alert"`
+}
+
+// Config contains the dependencies of the liquidity monitor.
+type Config struct {
+	// FetchChannels returns the open channels.
+	FetchChannels func() ([]Channel, error)
+
+	// Thresholds are the liquidity levels below which alerts are raised.
+	Thresholds Thresholds
+
+	// Clock is used to track how long channels stay one-sided.
+	Clock clock.Clock
+
+	// Ticker triggers the periodic checks.
+	Ticker ticker.Ticker
+
+	// WebhookURL is the URL alerts are posted to. If empty, alerts are
+	// only logged and recorded as metrics.
+	WebhookURL string
+
+	// WebhookTimeout is the time the webhook may take to respond.
+	WebhookTimeout time.Duration
+}
+
+// Monitor periodically checks the liquidity of the channels against the
+// configured thresholds.
+type Monitor struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// alerts are the alerts raised by the last check, by their key.
+	alerts map[string]*Alert
+	mu     sync.Mutex
+
+	// oneSidedSince tracks the time since which the channels are
+	// one-sided. It's only accessed by the check goroutine.
+	oneSidedSince map[wire.OutPoint]time.Time
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewMonitor creates a new liquidity monitor.
+func NewMonitor(cfg *Config) *Monitor {
+	return &Monitor{
+		cfg:           cfg,
+		alerts:        make(map[string]*Alert),
+		oneSidedSince: make(map[wire.OutPoint]time.Time),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start starts the periodic checks.
+func (m *Monitor) Start() error {
+	m.started.Do(func() {
+		log.Info("Liquidity monitor starting")
+
+		m.cfg.Ticker.Resume()
+
+		m.wg.Add(1)
+		go m.run()
+	})
+
+	return nil
+}
+
+// Stop stops the periodic checks.
+func (m *Monitor) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Liquidity monitor shutting down...")
+		defer log.Debug("Liquidity monitor shutdown complete")
+
+		close(m.quit)
+		m.wg.Wait()
+
+		m.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// Alerts returns the alerts raised by the last check, ordered by their scope,
+// kind, peer and channel.
+func (m *Monitor) Alerts() []*Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alerts := make([]*Alert, 0, len(m.alerts))
+	for _, alert := range m.alerts {
+		alertCopy := *alert
+		alerts = append(alerts, &alertCopy)
+	}
+	sortAlerts(alerts)
+
+	return alerts
+}
+
+// run checks the liquidity on start up and on every tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Monitor) run() {
+	defer m.wg.Done()
+
+	for {
+		if err := m.checkLiquidity(); err != nil {
+			log.Errorf("Unable to check liquidity: %v", err)
+		}
+
+		select {
+		case <-m.cfg.Ticker.Ticks():
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// checkLiquidity runs a single check, and raises and resolves alerts whose
+// conditions changed.
+func (m *Monitor) checkLiquidity() error {
+	channels, err := m.cfg.FetchChannels()
+	if err != nil {
+		return fmt.Errorf("unable to fetch channels: %w", err)
+	}
+
+	now := m.cfg.Clock.Now()
+	alerts := m.evaluate(channels, now)
+
+	var raised, resolved []*Alert
+	m.mu.Lock()
+	for key, alert := range alerts {
+		active, ok := m.alerts[key]
+		switch {
+		case ok:
+			alert.Since = active.Since
+
+		case alert.Since.IsZero():
+			alert.Since = now
+			raised = append(raised, alert)
+
+		default:
+			raised = append(raised, alert)
+		}
+	}
+	for key, alert := range m.alerts {
+		if _, ok := alerts[key]; !ok {
+			resolved = append(resolved, alert)
+		}
+	}
+	m.alerts = alerts
+	m.mu.Unlock()
+
+	numAlerts := make(map[Kind]int)
+	for _, alert := range alerts {
+		numAlerts[alert.Kind]++
+	}
+	for _, kind := range kinds {
+		metrics.SetLiquidityActiveAlerts(string(kind), numAlerts[kind])
+	}
+
+	sortAlerts(raised)
+	for _, alert := range raised {
+		log.Warnf("Liquidity alert raised: kind=%v, scope=%v, "+
+			"peer=%v, chan_point=%v, outbound=%v, inbound=%v, "+
+			"threshold=%v", alert.Kind, alert.Scope, alert.Peer,
+			alert.ChanPoint, alert.Outbound, alert.Inbound,
+			alert.Threshold)
+
+		metrics.IncLiquidityAlerts(string(alert.Kind))
+		m.sendNotification(EventRaised, alert)
+	}
+
+	sortAlerts(resolved)
+	for _, alert := range resolved {
+		log.Infof("Liquidity alert resolved: kind=%v, scope=%v, "+
+			"peer=%v, chan_point=%v", alert.Kind, alert.Scope,
+			alert.Peer, alert.ChanPoint)
+
+		m.sendNotification(EventResolved, alert)
+	}
+
+	return nil
+}
+
+// balances are the local and remote balances within the scope of an alert.
+type balances struct {
+	outbound btcutil.Amount
+	inbound  btcutil.Amount
+}
+
+// evaluate returns the alerts whose conditions hold for the given channels,
+// by their key. Only one-sided alerts have their Since time set.
+func (m *Monitor) evaluate(channels []Channel,
+	now time.Time) map[string]*Alert {
+
+	thresholds := m.cfg.Thresholds
+	alerts := make(map[string]*Alert)
+	addAlert := func(alert *Alert) {
+		alerts[alert.key()] = alert
+	}
+
+	// checkBalances raises the alerts for balances below the given
+	// thresholds.
+	checkBalances := func(template Alert, b balances, minOutbound,
+		minInbound btcutil.Amount) {
+
+		template.Outbound = b.outbound
+		template.Inbound = b.inbound
+
+		if b.outbound < minOutbound {
+			alert := template
+			alert.Kind = KindLowOutbound
+			alert.Threshold = minOutbound
+			addAlert(&alert)
+		}
+
+		if b.inbound < minInbound {
+			alert := template
+			alert.Kind = KindLowInbound
+			alert.Threshold = minInbound
+			addAlert(&alert)
+		}
+	}
+
+	var node balances
+	peers := make(map[route.Vertex]*balances)
+	oneSidedSince := make(map[wire.OutPoint]time.Time)
+	for _, channel := range channels {
+		chanBalances := balances{
+			outbound: channel.LocalBalance,
+			inbound:  channel.RemoteBalance,
+		}
+		template := Alert{
+			Scope:     ScopeChannel,
+			ChanPoint: channel.ChanPoint.String(),
+			Peer:      channel.Peer.String(),
+		}
+
+		capacity := channel.Capacity
+		checkBalances(
+			template, chanBalances,
+			ratioOf(capacity, thresholds.MinChanOutboundRatio),
+			ratioOf(capacity, thresholds.MinChanInboundRatio),
+		)
+
+		if thresholds.OneSidedRatio > 0 {
+			limit := ratioOf(capacity, thresholds.OneSidedRatio)
+
+			if channel.LocalBalance >= limit ||
+				channel.RemoteBalance >= limit {
+
+				chanPoint := channel.ChanPoint
+				since, ok := m.oneSidedSince[chanPoint]
+				if !ok {
+					since = now
+				}
+				oneSidedSince[chanPoint] = since
+
+				minDuration := thresholds.OneSidedDuration
+				if now.Sub(since) >= minDuration {
+					alert := template
+					alert.Kind = KindOneSided
+					alert.Outbound = channel.LocalBalance
+					alert.Inbound = channel.RemoteBalance
+					alert.Threshold = limit
+					alert.Since = since
+					addAlert(&alert)
+				}
+			}
+		}
+
+		peer, ok := peers[channel.Peer]
+		if !ok {
+			peer = &balances{}
+			peers[channel.Peer] = peer
+		}
+		peer.outbound += channel.LocalBalance
+		peer.inbound += channel.RemoteBalance
+
+		node.outbound += channel.LocalBalance
+		node.inbound += channel.RemoteBalance
+	}
+	m.oneSidedSince = oneSidedSince
+
+	for pubKey, peer := range peers {
+		checkBalances(
+			Alert{Scope: ScopePeer, Peer: pubKey.String()}, *peer,
+			thresholds.MinPeerOutbound, thresholds.MinPeerInbound,
+		)
+	}
+
+	checkBalances(
+		Alert{Scope: ScopeNode}, node, thresholds.MinNodeOutbound,
+		thresholds.MinNodeInbound,
+	)
+
+	return alerts
+}
+
+// sendNotification posts an alert to the webhook, if one is configured.
+func (m *Monitor) sendNotification(event string, alert *Alert) {
+	if m.cfg.WebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(&Notification{
+		Event: event,
+		Alert: alert,
+	})
+	if err != nil {
+		log.Errorf("Unable to encode alert: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), m.cfg.WebhookTimeout,
+	)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, m.cfg.WebhookURL, bytes.NewReader(body),
+	)
+	if err != nil {
+		log.Errorf("Unable to create alert request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Errorf("Unable to post %v alert: %v", event, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		log.Errorf("Alert webhook returned status %v", resp.Status)
+	}
+}
+
+// ratioOf returns the given fraction of the amount.
+func ratioOf(amt btcutil.Amount, ratio float64) btcutil.Amount {
+	return btcutil.Amount(float64(amt) * ratio)
+}
+
+// sortAlerts sorts the alerts by their scope, kind, peer and channel.
+func sortAlerts(alerts []*Alert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].key() < alerts[j].key()
+	})
+}
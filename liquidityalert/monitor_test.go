@@ -0,0 +1,236 @@
+package liquidityalert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+// TestMonitorAlerts tests that alerts are raised and resolved as the balances
+// of the channels change, and that both are posted to the webhook.
+func TestMonitorAlerts(t *testing.T) {
+	t.Parallel()
+
+	notifications := make(chan *Notification, 20)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			n := &Notification{}
+			err := json.NewDecoder(r.Body).Decode(n)
+			require.NoError(t, err)
+
+			notifications <- n
+		},
+	))
+	t.Cleanup(server.Close)
+
+	var alice, bob route.Vertex
+	alice[0], bob[0] = 1, 2
+
+	// Alice has two balanced channels, bob a single one.
+	channels := []Channel{
+		{
+			ChanPoint:     wire.OutPoint{Index: 1},
+			Peer:          alice,
+			Capacity:      100_000,
+			LocalBalance:  50_000,
+			RemoteBalance: 50_000,
+		},
+		{
+			ChanPoint:     wire.OutPoint{Index: 2},
+			Peer:          alice,
+			Capacity:      100_000,
+			LocalBalance:  50_000,
+			RemoteBalance: 50_000,
+		},
+		{
+			ChanPoint:     wire.OutPoint{Index: 3},
+			Peer:          bob,
+			Capacity:      100_000,
+			LocalBalance:  50_000,
+			RemoteBalance: 50_000,
+		},
+	}
+
+	testClock := clock.NewTestClock(time.Unix(1_000_000, 0))
+	monitor := NewMonitor(&Config{
+		FetchChannels: func() ([]Channel, error) {
+			return channels, nil
+		},
+		Thresholds: Thresholds{
+			MinChanOutboundRatio: 0.1,
+			MinPeerInbound:       50_000,
+			MinNodeOutbound:      120_000,
+			OneSidedRatio:        0.9,
+			OneSidedDuration:     time.Hour,
+		},
+		Clock:          testClock,
+		Ticker:         ticker.NewForce(time.Hour),
+		WebhookURL:     server.URL,
+		WebhookTimeout: testTimeout,
+	})
+
+	// receive returns the next notification posted to the webhook.
+	receive := func(event string) *Alert {
+		t.Helper()
+
+		select {
+		case n := <-notifications:
+			require.Equal(t, event, n.Event)
+			return n.Alert
+
+		case <-time.After(testTimeout):
+			t.Fatalf("no %v notification received", event)
+		}
+
+		return nil
+	}
+
+	// With balanced channels, no alerts are raised.
+	require.NoError(t, monitor.checkLiquidity())
+	require.Empty(t, monitor.Alerts())
+	require.Empty(t, notifications)
+
+	// Once we've pushed almost all of our balance in bob's channel to
+	// him, its outbound liquidity is low and that of the node as well.
+	// The channel is one-sided, but not long enough to raise an alert.
+	channels[2].LocalBalance = 5_000
+	channels[2].RemoteBalance = 95_000
+	require.NoError(t, monitor.checkLiquidity())
+
+	alerts := monitor.Alerts()
+	require.Len(t, alerts, 2)
+	require.Equal(t, KindLowOutbound, alerts[0].Kind)
+	require.Equal(t, ScopeChannel, alerts[0].Scope)
+	require.Equal(t, channels[2].ChanPoint.String(), alerts[0].ChanPoint)
+	require.Equal(t, bob.String(), alerts[0].Peer)
+	require.EqualValues(t, 10_000, alerts[0].Threshold)
+	require.Equal(t, testClock.Now(), alerts[0].Since)
+
+	require.Equal(t, KindLowOutbound, alerts[1].Kind)
+	require.Equal(t, ScopeNode, alerts[1].Scope)
+	require.EqualValues(t, 105_000, alerts[1].Outbound)
+	require.EqualValues(t, 195_000, alerts[1].Inbound)
+
+	receive(EventRaised)
+	receive(EventRaised)
+
+	// Alerts that still hold aren't raised again, and keep the time they
+	// were first raised.
+	raisedAt := testClock.Now()
+	testClock.SetTime(raisedAt.Add(time.Minute))
+	require.NoError(t, monitor.checkLiquidity())
+	require.Len(t, monitor.Alerts(), 2)
+	require.Equal(t, raisedAt, monitor.Alerts()[0].Since)
+	require.Empty(t, notifications)
+
+	// Once the channel stayed one-sided for long enough, an alert is
+	// raised that dates back to when it became one-sided.
+	testClock.SetTime(raisedAt.Add(time.Hour))
+	require.NoError(t, monitor.checkLiquidity())
+
+	alert := receive(EventRaised)
+	require.Equal(t, KindOneSided, alert.Kind)
+	require.Equal(t, ScopeChannel, alert.Scope)
+	require.EqualValues(t, 90_000, alert.Threshold)
+	require.True(t, raisedAt.Equal(alert.Since))
+	require.Len(t, monitor.Alerts(), 3)
+
+	// If bob pushes back part of the balance, the channel alerts are
+	// resolved, but the outbound liquidity of the node is still low.
+	channels[2].LocalBalance = 15_000
+	channels[2].RemoteBalance = 85_000
+	require.NoError(t, monitor.checkLiquidity())
+
+	resolved := []Kind{
+		receive(EventResolved).Kind, receive(EventResolved).Kind,
+	}
+	require.ElementsMatch(
+		t, []Kind{KindLowOutbound, KindOneSided}, resolved,
+	)
+
+	alerts = monitor.Alerts()
+	require.Len(t, alerts, 1)
+	require.Equal(t, ScopeNode, alerts[0].Scope)
+
+	// If alice closes one of her channels, her inbound liquidity drops
+	// below the peer threshold.
+	channels = channels[1:]
+	channels[0].LocalBalance = 60_000
+	channels[0].RemoteBalance = 40_000
+	require.NoError(t, monitor.checkLiquidity())
+
+	alert = receive(EventRaised)
+	require.Equal(t, KindLowInbound, alert.Kind)
+	require.Equal(t, ScopePeer, alert.Scope)
+	require.Equal(t, alice.String(), alert.Peer)
+	require.EqualValues(t, 40_000, alert.Inbound)
+
+	alerts = monitor.Alerts()
+	require.Len(t, alerts, 2)
+	require.Equal(t, ScopeNode, alerts[0].Scope)
+	require.Equal(t, ScopePeer, alerts[1].Scope)
+	require.Empty(t, notifications)
+}
+
+// TestMonitorStartStop tests that the monitor checks the liquidity on start
+// up and on every tick.
+func TestMonitorStartStop(t *testing.T) {
+	t.Parallel()
+
+	checks := make(chan struct{}, 10)
+	forceTicker := ticker.NewForce(time.Hour)
+	monitor := NewMonitor(&Config{
+		FetchChannels: func() ([]Channel, error) {
+			checks <- struct{}{}
+
+			return []Channel{{
+				Capacity:      btcutil.SatoshiPerBitcoin,
+				RemoteBalance: btcutil.SatoshiPerBitcoin,
+			}}, nil
+		},
+		Thresholds: Thresholds{
+			MinNodeOutbound: 1,
+		},
+		Clock:  clock.NewTestClock(time.Unix(1_000_000, 0)),
+		Ticker: forceTicker,
+	})
+
+	require.NoError(t, monitor.Start())
+	t.Cleanup(func() {
+		require.NoError(t, monitor.Stop())
+	})
+
+	waitCheck := func() {
+		t.Helper()
+
+		select {
+		case <-checks:
+		case <-time.After(testTimeout):
+			t.Fatalf("liquidity not checked")
+		}
+	}
+
+	waitCheck()
+
+	select {
+	case forceTicker.Force <- time.Now():
+	case <-time.After(testTimeout):
+		t.Fatalf("tick not consumed")
+	}
+	waitCheck()
+
+	require.Eventually(t, func() bool {
+		return len(monitor.Alerts()) == 1
+	}, testTimeout, 10*time.Millisecond)
+}
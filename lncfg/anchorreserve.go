@@ -0,0 +1,96 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultAnchorReserveInterval is the default interval at which the
+	// anchor reserve is checked.
+	DefaultAnchorReserveInterval = 10 * time.Minute
+
+	// DefaultAnchorReserveConfTarget is the default confirmation target
+	// used to estimate the fee rate of a force close fee bump.
+	DefaultAnchorReserveConfTarget = 6
+
+	// DefaultAnchorReserveMaxConsolidationFeeRate is the default maximum
+	// fee rate in sat/vbyte at which UTXOs are consolidated.
+	DefaultAnchorReserveMaxConsolidationFeeRate = 10
+
+	// DefaultAnchorReserveWebhookTimeout is the default time the alert
+	// webhook may take to respond.
+	DefaultAnchorReserveWebhookTimeout = 10 * time.Second
+)
+
+// AnchorReserve holds the configuration of the anchor reserve monitor.
+//
+//nolint:lll
+type AnchorReserve struct {
+	Active bool `long:"active" description:"Continuously check whether the wallet can cover the fee bumps needed to force close the anchor channels, and alert if it can't."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which the anchor reserve is checked. Valid time units are {s, m, h}."`
+
+	ConfTarget uint32 `long:"conftarget" description:"The confirmation target whose fee estimate is used to price a force close fee bump."`
+
+	Consolidate bool `long:"consolidate" description:"Consolidate the confirmed UTXOs of the default wallet account into a single output when no UTXO alone can pay for a force close fee bump, but their sum can."`
+
+	MaxConsolidationFeeRate uint64 `long:"max-consolidation-feerate" description:"The maximum fee rate in sat/vbyte at which UTXOs are consolidated. Consolidation is postponed while fees are higher."`
+
+	WebhookURL string `long:"webhook" description:"The http(s) URL alerts are posted to when the wallet can no longer cover a force close fee bump and when it can again."`
+
+	WebhookTimeout time.Duration `long:"webhook-timeout" description:"The time the alert webhook may take to respond. Valid time units are {s, m, h}."`
+}
+
+// DefaultAnchorReserve returns the default configuration of the anchor reserve
+// monitor.
+func DefaultAnchorReserve() *AnchorReserve {
+	return &AnchorReserve{
+		Interval:                DefaultAnchorReserveInterval,
+		ConfTarget:              DefaultAnchorReserveConfTarget,
+		MaxConsolidationFeeRate: DefaultAnchorReserveMaxConsolidationFeeRate, //nolint:lll
+		WebhookTimeout:          DefaultAnchorReserveWebhookTimeout,
+	}
+}
+
+// Validate checks the values configured for the anchor reserve monitor.
+func (a *AnchorReserve) Validate() error {
+	if !a.Active {
+		return nil
+	}
+
+	if a.Interval <= 0 {
+		return fmt.Errorf("anchorreserve.interval must be positive")
+	}
+
+	if a.ConfTarget == 0 {
+		return fmt.Errorf("anchorreserve.conftarget must be positive")
+	}
+
+	if a.Consolidate && a.MaxConsolidationFeeRate == 0 {
+		return fmt.Errorf("anchorreserve.max-consolidation-feerate " +
+			"must be positive")
+	}
+
+	if a.WebhookURL != "" {
+		u, err := url.Parse(a.WebhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid anchorreserve.webhook: %w",
+				err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			return fmt.Errorf("anchorreserve.webhook must be an " +
+				"http or https URL")
+		}
+
+		if a.WebhookTimeout <= 0 {
+			return fmt.Errorf("anchorreserve.webhook-timeout " +
+				"must be positive")
+		}
+	}
+
+	return nil
+}
@@ -13,4 +13,6 @@ type AutoPilot struct {
 	Private        bool               `long:"private" description:"Whether the channels created by the autopilot agent should be private or not. Private channels won't be announced to the network."`
 	MinConfs       int32              `long:"minconfs" description:"The minimum number of confirmations each of your inputs in funding transactions created by the autopilot agent must have."`
 	ConfTarget     uint32             `long:"conftarget" description:"The confirmation target (in blocks) for channels opened by autopilot."`
+
+	MaxPendingCloses uint16 `long:"maxpendingcloses" description:"The maximum number of channels recommended for closing by an external autopilot agent that can be closing at the same time. Set to 0 to ignore the channel closes recommended by external agents."`
 }
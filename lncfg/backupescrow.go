@@ -0,0 +1,57 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultBackupEscrowTimeout is the default time a single request to
+	// the backup escrow endpoint may take.
+	DefaultBackupEscrowTimeout = 30 * time.Second
+)
+
+// BackupEscrow holds the configuration of the remote endpoint the channel
+// backup is escrowed with.
+//
+//nolint:lll
+type BackupEscrow struct {
+	Active bool `long:"active" description:"Push an escrow bundle of the channel backup to a remote endpoint every time the backup changes."`
+
+	URL string `long:"url" description:"The http(s) URL the escrow bundle is uploaded to with a PUT request. Credentials can be passed as part of the URL."`
+
+	Timeout time.Duration `long:"timeout" description:"The time a single request to the escrow endpoint may take. Valid time units are {s, m, h}."`
+}
+
+// DefaultBackupEscrow returns the default configuration of the backup escrow.
+func DefaultBackupEscrow() *BackupEscrow {
+	return &BackupEscrow{
+		Timeout: DefaultBackupEscrowTimeout,
+	}
+}
+
+// Validate checks the values configured for the backup escrow.
+func (b *BackupEscrow) Validate() error {
+	if !b.Active {
+		return nil
+	}
+
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return fmt.Errorf("invalid backupescrow.url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("backupescrow.url must be an http or https " +
+			"URL")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("backupescrow.url must have a host")
+	}
+
+	if b.Timeout <= 0 {
+		return fmt.Errorf("backupescrow.timeout must be positive")
+	}
+
+	return nil
+}
@@ -22,6 +22,7 @@ type Chain struct {
 	SigNet          bool     `long:"signet" description:"Use the signet test network"`
 	SigNetChallenge string   `long:"signetchallenge" description:"Connect to a custom signet network defined by this challenge instead of using the global default signet test network -- Can be specified multiple times"`
 	SigNetSeedNode  []string `long:"signetseednode" description:"Specify a seed node for the signet network instead of using the global default signet network seed nodes"`
+	SigNetGenesis   string   `long:"signetgenesis" description:"The hex encoded serialized genesis block of a custom signet network. This gives the custom signet its own chain hash, which keeps its channels, gossip and watchtower sessions apart from those of the global default signet test network"`
 
 	DefaultNumChanConfs int                 `long:"defaultchanconfs" description:"The default number of confirmations a channel must have before it's considered open. If this is not set, we will scale the value according to the channel size."`
 	DefaultRemoteDelay  int                 `long:"defaultremotedelay" description:"The default number of blocks we will require our channel counterparty to wait before accessing its funds in case of unilateral close. If this is not set, we will scale the value according to the channel size."`
@@ -30,6 +31,8 @@ type Chain struct {
 	MinHTLCOut          lnwire.MilliSatoshi `long:"minhtlcout" description:"The smallest HTLC we are willing to send out on our channels, in millisatoshi"`
 	BaseFee             lnwire.MilliSatoshi `long:"basefee" description:"The base fee in millisatoshi we will charge for forwarding payments on our channels"`
 	FeeRate             lnwire.MilliSatoshi `long:"feerate" description:"The fee rate used when forwarding payments on our channels. The total fee charged is basefee + (amount * feerate / 1000000), where amount is the forwarded amount."`
+	InboundBaseFee      int32               `long:"inboundbasefee" description:"The inbound base fee in millisatoshi we will charge for payments that are forwarded from our channels. A negative value grants a discount on the outbound fee. Positive values require accept-positive-inbound-fees to be set."`
+	InboundFeeRate      int32               `long:"inboundfeerate" description:"The inbound fee rate in parts per million we will charge for payments that are forwarded from our channels. A negative value grants a discount on the outbound fee. Positive values require accept-positive-inbound-fees to be set."`
 	TimeLockDelta       uint32              `long:"timelockdelta" description:"The CLTV delta we will subtract from a forwarded HTLC's timelock value"`
 	DNSSeeds            []string            `long:"dnsseed" description:"The seed DNS server(s) to use for initial peer discovery. Must be specified as a '<primary_dns>[,<soa_primary_dns>]' tuple where the SOA address is needed for DNS resolution through Tor but is optional for clearnet users. Multiple tuples can be specified, will overwrite the default seed servers."`
 }
@@ -0,0 +1,81 @@
+package lncfg
+
+// CLTVDeltas holds the block deltas that determine at which point before the
+// expiry of an htlc we stop accepting, intercepting or forwarding it and at
+// which point we go on chain to resolve it.
+type CLTVDeltas struct {
+	// IncomingBroadcastDelta is the number of blocks before the expiry of
+	// an incoming htlc at which we force close the channel.
+	IncomingBroadcastDelta uint32
+
+	// FinalCltvRejectDelta is the number of blocks before the expiry of
+	// an incoming exit hop htlc at which we cancel it back immediately.
+	FinalCltvRejectDelta uint32
+
+	// CltvInterceptDelta is the number of blocks before the expiry of an
+	// htlc at which we don't offer it to the interceptor anymore.
+	CltvInterceptDelta uint32
+
+	// OutgoingBroadcastDelta is the number of blocks before the expiry of
+	// an outgoing htlc at which we force close the channel.
+	OutgoingBroadcastDelta uint32
+
+	// OutgoingCltvRejectDelta is the number of blocks before the expiry
+	// of an outgoing htlc at which we don't offer it to the next peer
+	// anymore.
+	OutgoingCltvRejectDelta uint32
+
+	// HoldInvoiceExpiryDelta is the default number of blocks before the
+	// expiry of an accepted hold invoice htlc at which we cancel it back.
+	HoldInvoiceExpiryDelta uint32
+}
+
+// DefaultCLTVDeltas returns the CLTV deltas used in production.
+func DefaultCLTVDeltas() CLTVDeltas {
+	return ScaledCLTVDeltas(1)
+}
+
+// ScaledCLTVDeltas returns the default CLTV deltas scaled down by the given
+// factor. Rather than scaling every delta on its own, only the margins between
+// the deltas are scaled and added up again. This makes sure every delta keeps
+// a margin of at least one block over the delta it builds on, which the htlc
+// switch and the invoice registry rely on.
+func ScaledCLTVDeltas(scale uint32) CLTVDeltas {
+	incoming := ScaleBlocks(DefaultIncomingBroadcastDelta, scale)
+	finalReject := incoming + ScaleBlocks(
+		DefaultFinalCltvRejectDelta-DefaultIncomingBroadcastDelta,
+		scale,
+	)
+	intercept := finalReject + ScaleBlocks(
+		DefaultCltvInterceptDelta-DefaultFinalCltvRejectDelta, scale,
+	)
+	outgoing := ScaleBlocks(DefaultOutgoingBroadcastDelta, scale)
+	outgoingReject := outgoing + ScaleBlocks(
+		DefaultOutgoingCltvRejectDelta-DefaultOutgoingBroadcastDelta,
+		scale,
+	)
+	holdExpiry := incoming + ScaleBlocks(
+		DefaultHoldInvoiceExpiryDelta-DefaultIncomingBroadcastDelta,
+		scale,
+	)
+
+	return CLTVDeltas{
+		IncomingBroadcastDelta:  incoming,
+		FinalCltvRejectDelta:    finalReject,
+		CltvInterceptDelta:      intercept,
+		OutgoingBroadcastDelta:  outgoing,
+		OutgoingCltvRejectDelta: outgoingReject,
+		HoldInvoiceExpiryDelta:  holdExpiry,
+	}
+}
+
+// ScaleBlocks scales down a number of blocks by the given factor. The result
+// is rounded up, so a non-zero number of blocks never becomes zero. A factor
+// of zero or one leaves the number of blocks unchanged.
+func ScaleBlocks(blocks, scale uint32) uint32 {
+	if scale <= 1 {
+		return blocks
+	}
+
+	return (blocks + scale - 1) / scale
+}
@@ -0,0 +1,67 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestScaledCLTVDeltas tests that scaling the CLTV deltas leaves the defaults
+// untouched for a factor of one and preserves the margins between the deltas
+// for any other factor.
+func TestScaledCLTVDeltas(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, CLTVDeltas{
+		IncomingBroadcastDelta:  DefaultIncomingBroadcastDelta,
+		FinalCltvRejectDelta:    DefaultFinalCltvRejectDelta,
+		CltvInterceptDelta:      DefaultCltvInterceptDelta,
+		OutgoingBroadcastDelta:  DefaultOutgoingBroadcastDelta,
+		OutgoingCltvRejectDelta: DefaultOutgoingCltvRejectDelta,
+		HoldInvoiceExpiryDelta:  DefaultHoldInvoiceExpiryDelta,
+	}, DefaultCLTVDeltas())
+
+	for scale := uint32(0); scale <= 100; scale++ {
+		deltas := ScaledCLTVDeltas(scale)
+
+		require.Positive(t, deltas.IncomingBroadcastDelta)
+		require.Greater(
+			t, deltas.FinalCltvRejectDelta,
+			deltas.IncomingBroadcastDelta,
+		)
+		require.Greater(
+			t, deltas.CltvInterceptDelta,
+			deltas.FinalCltvRejectDelta,
+		)
+		require.Greater(
+			t, deltas.OutgoingCltvRejectDelta,
+			deltas.OutgoingBroadcastDelta,
+		)
+		require.Greater(
+			t, deltas.HoldInvoiceExpiryDelta,
+			deltas.IncomingBroadcastDelta,
+		)
+	}
+
+	// A large factor compresses every margin down to a single block.
+	require.Equal(t, CLTVDeltas{
+		IncomingBroadcastDelta:  1,
+		FinalCltvRejectDelta:    2,
+		CltvInterceptDelta:      3,
+		OutgoingBroadcastDelta:  0,
+		OutgoingCltvRejectDelta: 1,
+		HoldInvoiceExpiryDelta:  2,
+	}, ScaledCLTVDeltas(100))
+}
+
+// TestScaleBlocks tests that scaling a number of blocks rounds up and never
+// turns a non-zero number of blocks into zero.
+func TestScaleBlocks(t *testing.T) {
+	t.Parallel()
+
+	require.EqualValues(t, 144, ScaleBlocks(144, 0))
+	require.EqualValues(t, 144, ScaleBlocks(144, 1))
+	require.EqualValues(t, 15, ScaleBlocks(144, 10))
+	require.EqualValues(t, 1, ScaleBlocks(3, 10))
+	require.EqualValues(t, 0, ScaleBlocks(0, 10))
+}
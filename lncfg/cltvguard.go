@@ -0,0 +1,96 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultCltvGuardStormBlocks is the default number of blocks that
+	// must arrive within the storm window to detect a block storm.
+	DefaultCltvGuardStormBlocks = 7
+
+	// DefaultCltvGuardStormWindow is the default window block storms are
+	// detected in.
+	DefaultCltvGuardStormWindow = 15 * time.Minute
+
+	// DefaultCltvGuardMaxTipAge is the default age of the best block above
+	// which the tip is stale.
+	DefaultCltvGuardMaxTipAge = 90 * time.Minute
+
+	// DefaultCltvGuardExtraDelta is the default number of blocks added to
+	// the CLTV deltas required for forwards while the guard is engaged.
+	DefaultCltvGuardExtraDelta = 24
+
+	// DefaultCltvGuardHoldTime is the default time the guard stays engaged
+	// after the last condition cleared.
+	DefaultCltvGuardHoldTime = time.Hour
+
+	// DefaultCltvGuardInterval is the default interval at which the age
+	// of the best block is checked.
+	DefaultCltvGuardInterval = time.Minute
+)
+
+// CltvGuard holds the configuration of the CLTV guard.
+//
+//nolint:lll
+type CltvGuard struct {
+	Active bool `long:"active" description:"Require larger CLTV deltas for forwards while blocks arrive in storms or the best block is stale, for example because the chain backend lags behind."`
+
+	StormBlocks int `long:"storm-blocks" description:"The number of blocks that must arrive within the storm window to detect a block storm. Set to 0 to disable the detection."`
+
+	StormWindow time.Duration `long:"storm-window" description:"The window block storms are detected in. Valid time units are {s, m, h}."`
+
+	MaxTipAge time.Duration `long:"max-tip-age" description:"The age of the timestamp of the best block above which it is considered stale. Set to 0 to disable the detection. Valid time units are {s, m, h}."`
+
+	ExtraDelta uint32 `long:"extra-delta" description:"The number of blocks added to the time-lock delta and the outgoing CLTV reject delta required for forwards while the guard is engaged."`
+
+	HoldTime time.Duration `long:"hold-time" description:"The time the guard stays engaged after the last condition cleared. Valid time units are {s, m, h}."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which the age of the best block is checked. Valid time units are {s, m, h}."`
+}
+
+// DefaultCltvGuard returns the default configuration of the CLTV guard.
+func DefaultCltvGuard() *CltvGuard {
+	return &CltvGuard{
+		StormBlocks: DefaultCltvGuardStormBlocks,
+		StormWindow: DefaultCltvGuardStormWindow,
+		MaxTipAge:   DefaultCltvGuardMaxTipAge,
+		ExtraDelta:  DefaultCltvGuardExtraDelta,
+		HoldTime:    DefaultCltvGuardHoldTime,
+		Interval:    DefaultCltvGuardInterval,
+	}
+}
+
+// Validate checks the values configured for the CLTV guard.
+func (c *CltvGuard) Validate() error {
+	if !c.Active {
+		return nil
+	}
+
+	if c.StormBlocks < 0 {
+		return fmt.Errorf("cltvguard.storm-blocks must not be negative")
+	}
+
+	if c.StormBlocks > 0 && c.StormWindow <= 0 {
+		return fmt.Errorf("cltvguard.storm-window must be positive")
+	}
+
+	if c.MaxTipAge < 0 {
+		return fmt.Errorf("cltvguard.max-tip-age must not be negative")
+	}
+
+	if c.ExtraDelta == 0 {
+		return fmt.Errorf("cltvguard.extra-delta must be positive")
+	}
+
+	if c.HoldTime < 0 {
+		return fmt.Errorf("cltvguard.hold-time must not be negative")
+	}
+
+	if c.Interval <= 0 {
+		return fmt.Errorf("cltvguard.interval must be positive")
+	}
+
+	return nil
+}
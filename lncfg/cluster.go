@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/lightningnetwork/lnd/cluster"
+	"github.com/lightningnetwork/lnd/kvdb"
 )
 
 const (
@@ -14,6 +15,17 @@ const (
 	DefaultEtcdElectionPrefix = "/leader/"
 )
 
+// FencedBackend is a database backend that supports fencing tokens. Once a
+// backend is fenced with a token, writes of other instances that use an older
+// token are rejected, so that a leader that was replaced can't corrupt the
+// database of the new leader.
+type FencedBackend interface {
+	kvdb.Backend
+
+	// SetFencingToken fences the database with the given token.
+	SetFencingToken(token uint64) error
+}
+
 // Cluster holds configuration for clustered LND.
 type Cluster struct {
 	EnableLeaderElection bool `long:"enable-leader-election" description:"Enables leader election if set."`
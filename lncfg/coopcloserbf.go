@@ -0,0 +1,54 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultCoopCloseRbfBumpInterval is the default interval at which an
+	// unconfirmed RBF cooperative close transaction is fee bumped.
+	DefaultCoopCloseRbfBumpInterval = 30 * time.Minute
+
+	// DefaultCoopCloseRbfFeeRateIncrease is the default percentage by
+	// which the fee rate of a closing transaction is increased on each
+	// automatic fee bump.
+	DefaultCoopCloseRbfFeeRateIncrease = 25
+)
+
+// CoopCloseRbf holds the configuration of the automatic fee escalation of RBF
+// cooperative closes.
+//
+//nolint:lll
+type CoopCloseRbf struct {
+	BumpInterval time.Duration `long:"bump-interval" description:"The interval at which the fee of an unconfirmed RBF cooperative close transaction that we initiated is bumped. Set to 0 to only bump the fee manually. Valid time units are {s, m, h}."`
+
+	FeeRateIncrease uint32 `long:"feerate-increase" description:"The percentage by which the fee rate of the closing transaction is increased on each automatic fee bump."`
+
+	MaxFeeRate uint64 `long:"max-feerate" description:"The maximum fee rate in sat/vbyte up to which the closing transaction is bumped automatically. If 0, the max fee of the close request is used, which defaults to three times the initial fee."`
+}
+
+// DefaultCoopCloseRbf returns the default configuration of the automatic fee
+// escalation of RBF cooperative closes.
+func DefaultCoopCloseRbf() *CoopCloseRbf {
+	return &CoopCloseRbf{
+		BumpInterval:    DefaultCoopCloseRbfBumpInterval,
+		FeeRateIncrease: DefaultCoopCloseRbfFeeRateIncrease,
+	}
+}
+
+// Validate checks the values configured for the automatic fee escalation of
+// RBF cooperative closes.
+func (c *CoopCloseRbf) Validate() error {
+	if c.BumpInterval < 0 {
+		return fmt.Errorf("coopcloserbf.bump-interval must not be " +
+			"negative")
+	}
+
+	if c.BumpInterval > 0 && c.FeeRateIncrease == 0 {
+		return fmt.Errorf("coopcloserbf.feerate-increase must be " +
+			"positive")
+	}
+
+	return nil
+}
@@ -37,6 +37,7 @@ const (
 	PostgresBackend            = "postgres"
 	SqliteBackend              = "sqlite"
 	DefaultBatchCommitInterval = 500 * time.Millisecond
+	DefaultMaxBatchSize        = 1000
 
 	defaultPostgresMaxConnections = 50
 	defaultSqliteMaxConnections   = 2
@@ -77,6 +78,8 @@ type DB struct {
 
 	BatchCommitInterval time.Duration `long:"batch-commit-interval" description:"The maximum duration the channel graph batch schedulers will wait before attempting to commit a batch of pending updates. This can be tradeoff database contenion for commit latency."`
 
+	MaxBatchSize int `long:"max-batch-size" description:"The maximum number of pending updates the channel graph batch schedulers commit in one transaction. A batch is committed before the batch commit interval elapsed once it reaches this size. Set to 0 to not limit the size of batches."`
+
 	Etcd *etcd.Config `group:"etcd" namespace:"etcd" description:"Etcd settings."`
 
 	Bolt *kvdb.BoltConfig `group:"bolt" namespace:"bolt" description:"Bolt settings."`
@@ -92,6 +95,8 @@ type DB struct {
 	PruneRevocation bool `long:"prune-revocation" description:"Run the optional migration that prunes the revocation logs to save disk space."`
 
 	NoRevLogAmtData bool `long:"no-rev-log-amt-data" description:"If set, the to-local and to-remote output amounts of revoked commitment transactions will not be stored in the revocation log. Note that once this data is lost, a watchtower client will not be able to back up the revoked state."`
+
+	CompactRevLog bool `long:"compact-rev-log" description:"If set, the HTLC entries of revocation logs are stored once per channel and only referenced by the logs, and the logs of existing channels are compacted in the background. Note that compacted revocation logs can't be read by older versions of lnd, so once this flag has been used the database version is bumped and older versions of lnd refuse to open the database."`
 }
 
 // DefaultDB creates and returns a new default DB config.
@@ -99,6 +104,7 @@ func DefaultDB() *DB {
 	return &DB{
 		Backend:             BoltBackend,
 		BatchCommitInterval: DefaultBatchCommitInterval,
+		MaxBatchSize:        DefaultMaxBatchSize,
 		Bolt: &kvdb.BoltConfig{
 			NoFreelistSync:    true,
 			AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
@@ -163,6 +169,10 @@ func (db *DB) Validate() error {
 			"backend '%v'", db.Backend)
 	}
 
+	if db.MaxBatchSize < 0 {
+		return fmt.Errorf("max-batch-size must not be negative")
+	}
+
 	return nil
 }
 
@@ -240,6 +250,15 @@ type DatabaseBackends struct {
 	// replicated instances or local bbolt or sqlite backed databases.
 	Remote bool
 
+	// BoltDBs holds the bolt database files that were opened, keyed by
+	// their namespace name. This is empty if a different database backend
+	// is used.
+	BoltDBs map[string]*BoltDB
+
+	// FencedDBs holds the database backends that support fencing tokens,
+	// keyed by their namespace name.
+	FencedDBs map[string]FencedBackend
+
 	// CloseFuncs is a map of close functions for each of the initialized
 	// DB backends keyed by their namespace name.
 	CloseFuncs map[string]func() error
@@ -265,11 +284,12 @@ func GetSqliteConfigKVDB(cfg *sqldb.SqliteConfig) *sqlite.Config {
 	}
 }
 
-// GetBackends returns a set of kvdb.Backends as set in the DB config.
+// GetBackends returns a set of kvdb.Backends as set in the DB config. If
+// readOnly is set, none of the writes to the returned backends are persisted.
 func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	walletDBPath, towerServerDBPath string, towerClientEnabled,
-	towerServerEnabled bool, logger btclog.Logger) (*DatabaseBackends,
-	error) {
+	towerServerEnabled, readOnly bool,
+	logger btclog.Logger) (*DatabaseBackends, error) {
 
 	// We keep track of all the kvdb backends we actually open and return a
 	// reference to their close function so they can be cleaned up properly
@@ -361,6 +381,26 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		}
 		closeFuncs[NSWalletDB] = etcdWalletBackend.Close
 
+		// In read-only mode none of the writes are persisted. This
+		// also means that the databases can't be fenced, since that
+		// requires writing the fencing token.
+		if readOnly {
+			makeReadOnly(
+				&etcdBackend, &etcdMacaroonBackend,
+				&etcdDecayedLogBackend, &etcdTowerClientBackend,
+				&etcdTowerServerBackend, &etcdWalletBackend,
+			)
+		}
+
+		fencedDBs := fencedBackends(map[string]kvdb.Backend{
+			NSChannelDB:     etcdBackend,
+			NSMacaroonDB:    etcdMacaroonBackend,
+			NSDecayedLogDB:  etcdDecayedLogBackend,
+			NSTowerClientDB: etcdTowerClientBackend,
+			NSTowerServerDB: etcdTowerServerBackend,
+			NSWalletDB:      etcdWalletBackend,
+		})
+
 		returnEarly = false
 
 		return &DatabaseBackends{
@@ -381,6 +421,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 			),
 			Remote:     true,
 			CloseFuncs: closeFuncs,
+			FencedDBs:  fencedDBs,
 		}, nil
 
 	case PostgresBackend:
@@ -449,6 +490,16 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		}
 		closeFuncs[NSWalletDB] = postgresWalletBackend.Close
 
+		if readOnly {
+			makeReadOnly(
+				&postgresBackend, &postgresMacaroonBackend,
+				&postgresDecayedLogBackend,
+				&postgresTowerClientBackend,
+				&postgresTowerServerBackend,
+				&postgresWalletBackend,
+			)
+		}
+
 		var nativeSQLStore *sqldb.BaseDB
 		if db.UseNativeSQL {
 			nativePostgresStore, err := sqldb.NewPostgresStore(
@@ -571,6 +622,15 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		}
 		closeFuncs[NSWalletDB] = sqliteWalletBackend.Close
 
+		if readOnly {
+			makeReadOnly(
+				&sqliteBackend, &sqliteMacaroonBackend,
+				&sqliteDecayedLogBackend,
+				&sqliteTowerClientBackend,
+				&sqliteTowerServerBackend, &sqliteWalletBackend,
+			)
+		}
+
 		var nativeSQLStore *sqldb.BaseDB
 		if db.UseNativeSQL {
 			nativeSQLiteStore, err := sqldb.NewSqliteStore(
@@ -619,63 +679,50 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	}
 
 	// We're using all bbolt based databases by default.
-	boltBackend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
-		DBPath:            chanDBPath,
-		DBFileName:        ChannelDBName,
-		DBTimeout:         db.Bolt.DBTimeout,
-		NoFreelistSync:    db.Bolt.NoFreelistSync,
-		AutoCompact:       db.Bolt.AutoCompact,
-		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
-	})
+	boltDBs := make(map[string]*BoltDB)
+	boltDB, err := db.openBoltBackend(
+		chanDBPath, ChannelDBName, readOnly, logger,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error opening bolt DB: %w", err)
 	}
+	boltBackend := boltDB.Backend
+	boltDBs[NSChannelDB] = boltDB
 	closeFuncs[NSChannelDB] = boltBackend.Close
 
-	macaroonBackend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
-		DBPath:            walletDBPath,
-		DBFileName:        MacaroonDBName,
-		DBTimeout:         db.Bolt.DBTimeout,
-		NoFreelistSync:    db.Bolt.NoFreelistSync,
-		AutoCompact:       db.Bolt.AutoCompact,
-		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
-	})
+	macaroonDB, err := db.openBoltBackend(
+		walletDBPath, MacaroonDBName, readOnly, logger,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error opening macaroon DB: %w", err)
 	}
+	macaroonBackend := macaroonDB.Backend
+	boltDBs[NSMacaroonDB] = macaroonDB
 	closeFuncs[NSMacaroonDB] = macaroonBackend.Close
 
-	decayedLogBackend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
-		DBPath:            chanDBPath,
-		DBFileName:        DecayedLogDbName,
-		DBTimeout:         db.Bolt.DBTimeout,
-		NoFreelistSync:    db.Bolt.NoFreelistSync,
-		AutoCompact:       db.Bolt.AutoCompact,
-		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
-	})
+	decayedLogDB, err := db.openBoltBackend(
+		chanDBPath, DecayedLogDbName, readOnly, logger,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error opening decayed log DB: %w", err)
 	}
+	decayedLogBackend := decayedLogDB.Backend
+	boltDBs[NSDecayedLogDB] = decayedLogDB
 	closeFuncs[NSDecayedLogDB] = decayedLogBackend.Close
 
 	// The tower client is optional and might not be enabled by the user. We
 	// handle it being nil properly in the main server.
 	var towerClientBackend kvdb.Backend
 	if towerClientEnabled {
-		towerClientBackend, err = kvdb.GetBoltBackend(
-			&kvdb.BoltBackendConfig{
-				DBPath:            chanDBPath,
-				DBFileName:        TowerClientDBName,
-				DBTimeout:         db.Bolt.DBTimeout,
-				NoFreelistSync:    db.Bolt.NoFreelistSync,
-				AutoCompact:       db.Bolt.AutoCompact,
-				AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
-			},
+		towerClientDB, err := db.openBoltBackend(
+			chanDBPath, TowerClientDBName, readOnly, logger,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error opening tower client "+
 				"DB: %v", err)
 		}
+		towerClientBackend = towerClientDB.Backend
+		boltDBs[NSTowerClientDB] = towerClientDB
 		closeFuncs[NSTowerClientDB] = towerClientBackend.Close
 	}
 
@@ -683,23 +730,42 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	// handle it being nil properly in the main server.
 	var towerServerBackend kvdb.Backend
 	if towerServerEnabled {
-		towerServerBackend, err = kvdb.GetBoltBackend(
-			&kvdb.BoltBackendConfig{
-				DBPath:            towerServerDBPath,
-				DBFileName:        TowerServerDBName,
-				DBTimeout:         db.Bolt.DBTimeout,
-				NoFreelistSync:    db.Bolt.NoFreelistSync,
-				AutoCompact:       db.Bolt.AutoCompact,
-				AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
-			},
+		towerServerDB, err := db.openBoltBackend(
+			towerServerDBPath, TowerServerDBName, readOnly, logger,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error opening tower server "+
 				"DB: %v", err)
 		}
+		towerServerBackend = towerServerDB.Backend
+		boltDBs[NSTowerServerDB] = towerServerDB
 		closeFuncs[NSTowerServerDB] = towerServerBackend.Close
 	}
 
+	// When "running locally", LND will use the bbolt wallet.db to store
+	// the wallet located in the chain data dir, parametrized by the active
+	// network. The wallet loader has its own cleanup method so we don't
+	// need to add anything to our map (in fact nothing is opened just
+	// yet). In read-only mode however, we open the wallet DB ourselves so
+	// that none of the writes of the wallet are persisted either.
+	walletDB := btcwallet.LoaderWithLocalWalletDB(
+		walletDBPath, db.Bolt.NoFreelistSync, db.Bolt.DBTimeout,
+	)
+	if readOnly {
+		walletBoltDB, err := db.openBoltBackend(
+			walletDBPath, WalletDBName, readOnly, logger,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening wallet DB: %w",
+				err)
+		}
+		closeFuncs[NSWalletDB] = walletBoltDB.Backend.Close
+
+		walletDB = btcwallet.LoaderWithOpenedLocalWalletDB(
+			walletBoltDB.Backend,
+		)
+	}
+
 	returnEarly = false
 
 	return &DatabaseBackends{
@@ -710,18 +776,27 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		DecayedLogDB:  decayedLogBackend,
 		TowerClientDB: towerClientBackend,
 		TowerServerDB: towerServerBackend,
-		// When "running locally", LND will use the bbolt wallet.db to
-		// store the wallet located in the chain data dir, parametrized
-		// by the active network. The wallet loader has its own cleanup
-		// method so we don't need to add anything to our map (in fact
-		// nothing is opened just yet).
-		WalletDB: btcwallet.LoaderWithLocalWalletDB(
-			walletDBPath, db.Bolt.NoFreelistSync, db.Bolt.DBTimeout,
-		),
-		CloseFuncs: closeFuncs,
+		WalletDB:      walletDB,
+		BoltDBs:       boltDBs,
+		CloseFuncs:    closeFuncs,
 	}, nil
 }
 
+// fencedBackends returns the given database backends that support fencing
+// tokens.
+func fencedBackends(
+	backends map[string]kvdb.Backend) map[string]FencedBackend {
+
+	fenced := make(map[string]FencedBackend)
+	for name, backend := range backends {
+		if fencedBackend, ok := backend.(FencedBackend); ok {
+			fenced[name] = fencedBackend
+		}
+	}
+
+	return fenced
+}
+
 // warnExistingBoltDBs checks if there is an existing bbolt database in the
 // given location and logs a warning if so.
 func warnExistingBoltDBs(log btclog.Logger, dbType, dir, fileName string) {
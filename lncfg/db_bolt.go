@@ -0,0 +1,283 @@
+package lncfg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// CompactOnRestartFileNameSuffix is the suffix we append to the file
+	// name of a bolt database file to request that the database is
+	// compacted the next time it is opened, regardless of the auto compact
+	// settings.
+	CompactOnRestartFileNameSuffix = ".compact-on-restart"
+)
+
+// BoltDB describes an open bolt database file.
+type BoltDB struct {
+	// FilePath is the full path of the database file.
+	FilePath string
+
+	// Backend is the open database backend.
+	Backend kvdb.Backend
+}
+
+// BucketUsage describes the disk usage of a top level bucket of a bolt
+// database, including all of its nested buckets.
+type BucketUsage struct {
+	// Name is the name of the bucket.
+	Name string
+
+	// NumKeys is the number of key/value pairs stored in the bucket and
+	// its nested buckets.
+	NumKeys uint64
+
+	// NumBuckets is the number of nested buckets.
+	NumBuckets uint64
+
+	// KeyBytes is the total size of all keys in bytes.
+	KeyBytes uint64
+
+	// ValueBytes is the total size of all values in bytes.
+	ValueBytes uint64
+}
+
+// TotalBytes returns the total number of bytes used by the keys and values of
+// the bucket. The actual usage on disk is higher because of the page overhead
+// of the database.
+func (b *BucketUsage) TotalBytes() uint64 {
+	return b.KeyBytes + b.ValueBytes
+}
+
+// BoltDBUsage describes the disk usage of a bolt database file.
+type BoltDBUsage struct {
+	// FilePath is the full path of the database file.
+	FilePath string
+
+	// FileSize is the size of the database file in bytes. The difference
+	// between the file size and the total size of the buckets is free
+	// space that can be reclaimed by compacting the database.
+	FileSize int64
+
+	// LastCompacted is the time the database was last compacted. It is
+	// zero if the database was never compacted.
+	LastCompacted time.Time
+
+	// CompactionScheduled is true if the database is compacted on the
+	// next restart.
+	CompactionScheduled bool
+
+	// Buckets is the usage of each top level bucket, sorted by name.
+	Buckets []*BucketUsage
+}
+
+// GetBoltDBUsage walks all buckets of the given bolt database and returns its
+// disk usage. As this reads the whole database within a single read
+// transaction, it can take a while for large databases.
+func GetBoltDBUsage(db *BoltDB) (*BoltDBUsage, error) {
+	fileInfo, err := os.Stat(db.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine size of %v: %w",
+			db.FilePath, err)
+	}
+
+	lastCompacted, err := lastBoltCompaction(db.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &BoltDBUsage{
+		FilePath:      db.FilePath,
+		FileSize:      fileInfo.Size(),
+		LastCompacted: lastCompacted,
+		CompactionScheduled: lnrpc.FileExists(
+			compactOnRestartFile(db.FilePath),
+		),
+	}
+
+	err = kvdb.View(db.Backend, func(tx kvdb.RTx) error {
+		return tx.ForEachBucket(func(name []byte) error {
+			bucketUsage := &BucketUsage{
+				Name: kvdb.LoggableKeyName(name),
+			}
+
+			bucket := tx.ReadBucket(name)
+			if bucket == nil {
+				return fmt.Errorf("bucket %x not found", name)
+			}
+
+			err := addBucketUsage(bucketUsage, bucket)
+			if err != nil {
+				return err
+			}
+
+			usage.Buckets = append(usage.Buckets, bucketUsage)
+
+			return nil
+		})
+	}, func() {
+		usage.Buckets = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to walk buckets of %v: %w",
+			db.FilePath, err)
+	}
+
+	sort.Slice(usage.Buckets, func(i, j int) bool {
+		return usage.Buckets[i].Name < usage.Buckets[j].Name
+	})
+
+	return usage, nil
+}
+
+// addBucketUsage adds the keys and values of the given bucket and all of its
+// nested buckets to the usage.
+func addBucketUsage(usage *BucketUsage, bucket kvdb.RBucket) error {
+	return bucket.ForEach(func(k, v []byte) error {
+		usage.KeyBytes += uint64(len(k))
+
+		// A nil value indicates a nested bucket.
+		if v != nil {
+			usage.NumKeys++
+			usage.ValueBytes += uint64(len(v))
+
+			return nil
+		}
+
+		nested := bucket.NestedReadBucket(k)
+		if nested == nil {
+			return fmt.Errorf("nested bucket %x not found", k)
+		}
+		usage.NumBuckets++
+
+		return addBucketUsage(usage, nested)
+	})
+}
+
+// ScheduleBoltCompaction requests that the bolt database at the given path is
+// compacted the next time it is opened. Bolt databases can't be compacted in
+// place while they are open, so the compaction is done by copying the
+// database into a new file on startup, which is then swapped with the
+// original file.
+func ScheduleBoltCompaction(dbFilePath string) error {
+	if !lnrpc.FileExists(dbFilePath) {
+		return fmt.Errorf("database file %v not found", dbFilePath)
+	}
+
+	return os.WriteFile(compactOnRestartFile(dbFilePath), nil, 0600)
+}
+
+// CancelBoltCompaction cancels a compaction of the bolt database at the given
+// path that was requested with ScheduleBoltCompaction.
+func CancelBoltCompaction(dbFilePath string) error {
+	err := os.Remove(compactOnRestartFile(dbFilePath))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// openBoltBackend opens the bolt database with the given file name in the
+// given directory. The database is compacted before it is opened if auto
+// compaction is enabled or a compaction was scheduled with
+// ScheduleBoltCompaction. If readOnly is set, the database must already exist.
+// It is then opened read-only and none of the writes to it are persisted.
+func (db *DB) openBoltBackend(dbPath, dbFileName string, readOnly bool,
+	log btclog.Logger) (*BoltDB, error) {
+
+	dbFilePath := filepath.Join(dbPath, dbFileName)
+
+	// In read-only mode we neither create nor compact the database file,
+	// as both would modify it.
+	if readOnly {
+		if !lnrpc.FileExists(dbFilePath) {
+			return nil, fmt.Errorf("database %v doesn't exist, "+
+				"it can't be created in read-only mode",
+				dbFilePath)
+		}
+
+		backend, err := openBoltReadOnly(dbFilePath, db.Bolt)
+		if err != nil {
+			return nil, err
+		}
+
+		return &BoltDB{
+			FilePath: dbFilePath,
+			Backend:  backend,
+		}, nil
+	}
+
+	cfg := &kvdb.BoltBackendConfig{
+		DBPath:            dbPath,
+		DBFileName:        dbFileName,
+		DBTimeout:         db.Bolt.DBTimeout,
+		NoFreelistSync:    db.Bolt.NoFreelistSync,
+		AutoCompact:       db.Bolt.AutoCompact,
+		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+	}
+
+	// A scheduled compaction is done regardless of the time that has
+	// passed since the last compaction.
+	scheduled := lnrpc.FileExists(compactOnRestartFile(dbFilePath))
+	if scheduled {
+		cfg.AutoCompact = true
+		cfg.AutoCompactMinAge = 0
+	}
+
+	backend, err := kvdb.GetBoltBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Now that the database was compacted, the request can be removed. If
+	// that fails, we'll just compact again on the next startup.
+	if scheduled {
+		if err := CancelBoltCompaction(dbFilePath); err != nil {
+			log.Warnf("Unable to remove compaction request for "+
+				"%v: %v", dbFilePath, err)
+		}
+	}
+
+	return &BoltDB{
+		FilePath: dbFilePath,
+		Backend:  backend,
+	}, nil
+}
+
+// compactOnRestartFile returns the path of the file that requests the
+// compaction of the given database file on the next startup.
+func compactOnRestartFile(dbFilePath string) string {
+	return dbFilePath + CompactOnRestartFileNameSuffix
+}
+
+// lastBoltCompaction returns the time the given database file was last
+// compacted, as recorded by the kvdb package, or a zero time if the database
+// was never compacted.
+func lastBoltCompaction(dbFilePath string) (time.Time, error) {
+	tsFile := dbFilePath + kvdb.LastCompactionFileNameSuffix
+	if !lnrpc.FileExists(tsFile) {
+		return time.Time{}, nil
+	}
+
+	tsBytes, err := os.ReadFile(tsFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(tsBytes) != 8 {
+		return time.Time{}, fmt.Errorf("invalid compaction timestamp "+
+			"in %v", tsFile)
+	}
+
+	tsNano := binary.BigEndian.Uint64(tsBytes)
+
+	return time.Unix(0, int64(tsNano)), nil
+}
@@ -0,0 +1,793 @@
+package lncfg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"go.etcd.io/bbolt"
+)
+
+// boltReadOnlyDB is a database backend that operates on a bolt database that
+// is opened read-only, which only takes a shared lock on its file. Bolt
+// doesn't allow read-write transactions on such a database, so they are
+// emulated: a read-write transaction reads from a bolt read transaction and
+// keeps its writes in memory, where they are visible to the transaction
+// itself until it's committed or rolled back. Either way, the writes are
+// discarded. This allows the subsystems that write to the database during
+// startup, for example to create their buckets, to keep working without any
+// change making it to the database file.
+type boltReadOnlyDB struct {
+	db *bbolt.DB
+}
+
+// A compile time check to ensure boltReadOnlyDB implements the kvdb.Backend
+// interface.
+var _ kvdb.Backend = (*boltReadOnlyDB)(nil)
+
+// openBoltReadOnly opens the bolt database at the given path read-only.
+func openBoltReadOnly(dbFilePath string, cfg *kvdb.BoltConfig) (kvdb.Backend,
+	error) {
+
+	db, err := bbolt.Open(dbFilePath, 0600, &bbolt.Options{
+		ReadOnly: true,
+		Timeout:  cfg.DBTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %v read-only: %w",
+			dbFilePath, err)
+	}
+
+	return &boltReadOnlyDB{
+		db: db,
+	}, nil
+}
+
+// beginTx starts a bolt read transaction and wraps it such that it can also
+// be used as a read-write transaction.
+func (b *boltReadOnlyDB) beginTx() (*boltReadOnlyTx, error) {
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+
+	readOnlyTx := &boltReadOnlyTx{
+		tx: tx,
+	}
+	readOnlyTx.root = newOverlayBucket(readOnlyTx, &rootBucket{tx: tx})
+
+	return readOnlyTx, nil
+}
+
+// BeginReadTx opens a database read transaction.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *boltReadOnlyDB) BeginReadTx() (walletdb.ReadTx, error) {
+	return b.beginTx()
+}
+
+// BeginReadWriteTx opens a database read+write transaction whose writes are
+// discarded when it's committed.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *boltReadOnlyDB) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	return b.beginTx()
+}
+
+// Copy writes a copy of the database to the provided writer.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *boltReadOnlyDB) Copy(w io.Writer) error {
+	return b.db.View(func(tx *bbolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
+// Close closes the database, releasing its lock.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *boltReadOnlyDB) Close() error {
+	return b.db.Close()
+}
+
+// PrintStats returns all collected stats pretty printed into a string.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *boltReadOnlyDB) PrintStats() string {
+	return "<no stats are collected by the read-only bolt backend>"
+}
+
+// View opens a database read transaction and executes the function f with the
+// transaction passed as a parameter.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *boltReadOnlyDB) View(f func(tx walletdb.ReadTx) error,
+	reset func()) error {
+
+	reset()
+
+	tx, err := b.beginTx()
+	if err != nil {
+		return err
+	}
+
+	err = f(tx)
+	rollbackErr := tx.Rollback()
+	if err != nil {
+		return err
+	}
+
+	return rollbackErr
+}
+
+// Update opens a database read+write transaction and executes the function f
+// with the transaction passed as a parameter. Regardless of the outcome of f,
+// the writes of the transaction are discarded afterwards.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *boltReadOnlyDB) Update(f func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	reset()
+
+	tx, err := b.beginTx()
+	if err != nil {
+		return err
+	}
+
+	err = f(tx)
+	rollbackErr := tx.Rollback()
+	if err != nil {
+		return err
+	}
+
+	return rollbackErr
+}
+
+// boltReadOnlyTx is a transaction on a read-only bolt database. It can be used
+// as a read-write transaction, whose writes are kept in memory.
+type boltReadOnlyTx struct {
+	tx *bbolt.Tx
+
+	// root is the root bucket holding the top-level buckets.
+	root *overlayBucket
+}
+
+// ReadBucket opens the top-level bucket with the given key, returning nil if
+// it doesn't exist.
+//
+// NOTE: This is part of the walletdb.ReadTx interface.
+func (tx *boltReadOnlyTx) ReadBucket(key []byte) walletdb.ReadBucket {
+	return tx.ReadWriteBucket(key)
+}
+
+// ForEachBucket calls the passed function with the key of every top-level
+// bucket.
+//
+// NOTE: This is part of the walletdb.ReadTx interface.
+func (tx *boltReadOnlyTx) ForEachBucket(f func(key []byte) error) error {
+	return tx.root.ForEach(func(k, _ []byte) error {
+		return f(k)
+	})
+}
+
+// Rollback closes the transaction, discarding its writes.
+//
+// NOTE: This is part of the walletdb.ReadTx interface.
+func (tx *boltReadOnlyTx) Rollback() error {
+	return tx.tx.Rollback()
+}
+
+// ReadWriteBucket opens the top-level bucket with the given key, returning nil
+// if it doesn't exist.
+//
+// NOTE: This is part of the walletdb.ReadWriteTx interface.
+func (tx *boltReadOnlyTx) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	bucket := tx.root.nested(key)
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket
+}
+
+// CreateTopLevelBucket creates the top-level bucket with the given key if it
+// doesn't exist yet, and returns it.
+//
+// NOTE: This is part of the walletdb.ReadWriteTx interface.
+func (tx *boltReadOnlyTx) CreateTopLevelBucket(
+	key []byte) (walletdb.ReadWriteBucket, error) {
+
+	return tx.root.CreateBucketIfNotExists(key)
+}
+
+// DeleteTopLevelBucket deletes the top-level bucket with the given key.
+//
+// NOTE: This is part of the walletdb.ReadWriteTx interface.
+func (tx *boltReadOnlyTx) DeleteTopLevelBucket(key []byte) error {
+	return tx.root.DeleteNestedBucket(key)
+}
+
+// Commit closes the transaction. As the database is read-only, the writes of
+// the transaction are discarded, just like on a rollback.
+//
+// NOTE: This is part of the walletdb.ReadWriteTx interface.
+func (tx *boltReadOnlyTx) Commit() error {
+	return tx.tx.Rollback()
+}
+
+// OnCommit is a no-op, as the transaction's writes are never committed.
+//
+// NOTE: This is part of the walletdb.ReadWriteTx interface.
+func (tx *boltReadOnlyTx) OnCommit(func()) {}
+
+// baseBucket is a bucket of the bolt database that an overlay bucket reads
+// from.
+type baseBucket interface {
+	// Get returns the value of the given key, or nil if the key doesn't
+	// exist or is a nested bucket.
+	Get(key []byte) []byte
+
+	// Bucket returns the nested bucket with the given key, or nil if it
+	// doesn't exist.
+	Bucket(key []byte) *bbolt.Bucket
+
+	// Cursor returns a cursor over the keys of the bucket.
+	Cursor() *bbolt.Cursor
+
+	// Sequence returns the current sequence number of the bucket.
+	Sequence() uint64
+}
+
+// rootBucket makes the top-level buckets of a bolt transaction accessible as
+// the nested buckets of a base bucket.
+type rootBucket struct {
+	tx *bbolt.Tx
+}
+
+// Get returns nil, as there are only buckets at the top level.
+func (r *rootBucket) Get([]byte) []byte {
+	return nil
+}
+
+// Bucket returns the top-level bucket with the given key.
+func (r *rootBucket) Bucket(key []byte) *bbolt.Bucket {
+	return r.tx.Bucket(key)
+}
+
+// Cursor returns a cursor over the keys of the top-level buckets.
+func (r *rootBucket) Cursor() *bbolt.Cursor {
+	return r.tx.Cursor()
+}
+
+// Sequence returns 0, as there is no sequence at the top level.
+func (r *rootBucket) Sequence() uint64 {
+	return 0
+}
+
+// overlayBucket is a bucket of a read-write transaction on a read-only bolt
+// database. Its content is read from the bolt database, but writes are kept
+// in memory, where they shadow the content of the bolt database.
+type overlayBucket struct {
+	tx *boltReadOnlyTx
+
+	// base is the bucket of the bolt database, or nil if the bucket was
+	// created in the transaction.
+	base baseBucket
+
+	// values holds the values that were put in the transaction.
+	values map[string][]byte
+
+	// buckets holds the nested buckets that were opened or created in the
+	// transaction.
+	buckets map[string]*overlayBucket
+
+	// deleted holds the keys of the values and nested buckets that were
+	// deleted in the transaction.
+	deleted map[string]struct{}
+
+	// sequence is the sequence number of the bucket, if it was changed in
+	// the transaction.
+	sequence *uint64
+}
+
+// A compile time check to ensure overlayBucket implements the
+// walletdb.ReadWriteBucket interface.
+var _ walletdb.ReadWriteBucket = (*overlayBucket)(nil)
+
+// newOverlayBucket creates an overlay bucket on top of the given base bucket,
+// which may be nil.
+func newOverlayBucket(tx *boltReadOnlyTx, base baseBucket) *overlayBucket {
+	return &overlayBucket{
+		tx:      tx,
+		base:    base,
+		values:  make(map[string][]byte),
+		buckets: make(map[string]*overlayBucket),
+		deleted: make(map[string]struct{}),
+	}
+}
+
+// shadowed returns whether the given key of the base bucket is shadowed by a
+// write of the transaction.
+func (b *overlayBucket) shadowed(key []byte) bool {
+	k := string(key)
+	if _, ok := b.deleted[k]; ok {
+		return true
+	}
+	if _, ok := b.values[k]; ok {
+		return true
+	}
+	_, ok := b.buckets[k]
+
+	return ok
+}
+
+// nested returns the nested bucket with the given key, or nil if it doesn't
+// exist.
+func (b *overlayBucket) nested(key []byte) *overlayBucket {
+	k := string(key)
+	if bucket, ok := b.buckets[k]; ok {
+		return bucket
+	}
+
+	if b.base == nil || b.shadowed(key) {
+		return nil
+	}
+
+	base := b.base.Bucket(key)
+	if base == nil {
+		return nil
+	}
+
+	bucket := newOverlayBucket(b.tx, base)
+	b.buckets[k] = bucket
+
+	return bucket
+}
+
+// NestedReadBucket returns the nested bucket with the given key, or nil if it
+// doesn't exist.
+//
+// NOTE: This is part of the walletdb.ReadBucket interface.
+func (b *overlayBucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
+	return b.NestedReadWriteBucket(key)
+}
+
+// ForEach calls the passed function with every key and value in the bucket.
+// The value of nested buckets is nil.
+//
+// NOTE: This is part of the walletdb.ReadBucket interface.
+func (b *overlayBucket) ForEach(f func(k, v []byte) error) error {
+	c := b.cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := f(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value of the given key, or nil if the key doesn't exist or
+// is a nested bucket.
+//
+// NOTE: This is part of the walletdb.ReadBucket interface.
+func (b *overlayBucket) Get(key []byte) []byte {
+	if value, ok := b.values[string(key)]; ok {
+		return value
+	}
+
+	if b.base == nil || b.shadowed(key) {
+		return nil
+	}
+
+	return b.base.Get(key)
+}
+
+// ReadCursor returns a cursor over the keys of the bucket.
+//
+// NOTE: This is part of the walletdb.ReadBucket interface.
+func (b *overlayBucket) ReadCursor() walletdb.ReadCursor {
+	return b.cursor()
+}
+
+// NestedReadWriteBucket returns the nested bucket with the given key, or nil
+// if it doesn't exist.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) NestedReadWriteBucket(
+	key []byte) walletdb.ReadWriteBucket {
+
+	bucket := b.nested(key)
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket
+}
+
+// CreateBucket creates the nested bucket with the given key. An error is
+// returned if the key already exists.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) CreateBucket(
+	key []byte) (walletdb.ReadWriteBucket, error) {
+
+	switch {
+	case len(key) == 0:
+		return nil, walletdb.ErrBucketNameRequired
+
+	case b.nested(key) != nil:
+		return nil, walletdb.ErrBucketExists
+
+	case b.Get(key) != nil:
+		return nil, walletdb.ErrIncompatibleValue
+	}
+
+	bucket := newOverlayBucket(b.tx, nil)
+	delete(b.deleted, string(key))
+	b.buckets[string(key)] = bucket
+
+	return bucket, nil
+}
+
+// CreateBucketIfNotExists creates the nested bucket with the given key if it
+// doesn't exist yet, and returns it.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) CreateBucketIfNotExists(
+	key []byte) (walletdb.ReadWriteBucket, error) {
+
+	if bucket := b.nested(key); bucket != nil {
+		return bucket, nil
+	}
+
+	return b.CreateBucket(key)
+}
+
+// DeleteNestedBucket deletes the nested bucket with the given key.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) DeleteNestedBucket(key []byte) error {
+	if b.nested(key) == nil {
+		return walletdb.ErrBucketNotFound
+	}
+
+	delete(b.buckets, string(key))
+	b.deleted[string(key)] = struct{}{}
+
+	return nil
+}
+
+// Put sets the value of the given key.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) Put(key, value []byte) error {
+	switch {
+	case len(key) == 0:
+		return walletdb.ErrKeyRequired
+
+	case b.nested(key) != nil:
+		return walletdb.ErrIncompatibleValue
+	}
+
+	// Bolt stores an empty value for a nil value, so we do the same to
+	// distinguish it from a missing key.
+	valueCopy := make([]byte, len(value))
+	copy(valueCopy, value)
+
+	delete(b.deleted, string(key))
+	b.values[string(key)] = valueCopy
+
+	return nil
+}
+
+// Delete deletes the given key. Deleting a key that doesn't exist is not an
+// error.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) Delete(key []byte) error {
+	if b.nested(key) != nil {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	delete(b.values, string(key))
+	b.deleted[string(key)] = struct{}{}
+
+	return nil
+}
+
+// ReadWriteCursor returns a cursor over the keys of the bucket that can delete
+// the key it points to.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) ReadWriteCursor() walletdb.ReadWriteCursor {
+	return b.cursor()
+}
+
+// Tx returns the transaction of the bucket.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) Tx() walletdb.ReadWriteTx {
+	return b.tx
+}
+
+// NextSequence increments the sequence number of the bucket and returns it.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) NextSequence() (uint64, error) {
+	sequence := b.Sequence() + 1
+	b.sequence = &sequence
+
+	return sequence, nil
+}
+
+// SetSequence sets the sequence number of the bucket.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) SetSequence(v uint64) error {
+	b.sequence = &v
+
+	return nil
+}
+
+// Sequence returns the current sequence number of the bucket.
+//
+// NOTE: This is part of the walletdb.ReadWriteBucket interface.
+func (b *overlayBucket) Sequence() uint64 {
+	switch {
+	case b.sequence != nil:
+		return *b.sequence
+
+	case b.base != nil:
+		return b.base.Sequence()
+
+	default:
+		return 0
+	}
+}
+
+// sortedKeys returns the keys of the values and nested buckets that were put,
+// created or opened in the transaction, in ascending order.
+func (b *overlayBucket) sortedKeys() []string {
+	keys := make([]string, 0, len(b.values)+len(b.buckets))
+	for k := range b.values {
+		keys = append(keys, k)
+	}
+	for k := range b.buckets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// cursor returns a cursor over the keys of the bucket.
+func (b *overlayBucket) cursor() *overlayCursor {
+	c := &overlayCursor{
+		bucket: b,
+	}
+	if b.base != nil {
+		c.base = b.base.Cursor()
+	}
+
+	return c
+}
+
+// overlayCursor is a cursor over the keys of an overlay bucket. It merges the
+// keys of the bolt database that aren't shadowed with the keys written in the
+// transaction. The position of the cursor is the key it points to, so that
+// writes to the bucket while iterating don't invalidate it.
+type overlayCursor struct {
+	bucket *overlayBucket
+
+	// base is the cursor over the bucket of the bolt database, or nil if
+	// the bucket was created in the transaction.
+	base *bbolt.Cursor
+
+	// key is the key the cursor points to, or nil if it doesn't point to
+	// any key.
+	key []byte
+}
+
+// A compile time check to ensure overlayCursor implements the
+// walletdb.ReadWriteCursor interface.
+var _ walletdb.ReadWriteCursor = (*overlayCursor)(nil)
+
+// baseNext returns the first key of the bolt database that isn't shadowed and
+// that comes after the given key, or is equal to it if inclusive is set. A nil
+// key returns the first key.
+func (c *overlayCursor) baseNext(key []byte, inclusive bool) []byte {
+	if c.base == nil {
+		return nil
+	}
+
+	var k []byte
+	if key == nil {
+		k, _ = c.base.First()
+	} else {
+		k, _ = c.base.Seek(key)
+		if k != nil && !inclusive && bytes.Equal(k, key) {
+			k, _ = c.base.Next()
+		}
+	}
+
+	for k != nil && c.bucket.shadowed(k) {
+		k, _ = c.base.Next()
+	}
+
+	return k
+}
+
+// basePrev returns the last key of the bolt database that isn't shadowed and
+// that comes before the given key. A nil key returns the last key.
+func (c *overlayCursor) basePrev(key []byte) []byte {
+	if c.base == nil {
+		return nil
+	}
+
+	var k []byte
+	if key == nil {
+		k, _ = c.base.Last()
+	} else {
+		k, _ = c.base.Seek(key)
+		if k == nil {
+			k, _ = c.base.Last()
+		} else {
+			k, _ = c.base.Prev()
+		}
+	}
+
+	for k != nil && c.bucket.shadowed(k) {
+		k, _ = c.base.Prev()
+	}
+
+	return k
+}
+
+// overlayNext returns the first key written in the transaction that comes
+// after the given key, or is equal to it if inclusive is set. A nil key
+// returns the first key.
+func (c *overlayCursor) overlayNext(key []byte, inclusive bool) []byte {
+	keys := c.bucket.sortedKeys()
+	i := sort.Search(len(keys), func(i int) bool {
+		cmp := bytes.Compare([]byte(keys[i]), key)
+		if key == nil || inclusive {
+			return cmp >= 0
+		}
+
+		return cmp > 0
+	})
+	if i == len(keys) {
+		return nil
+	}
+
+	return []byte(keys[i])
+}
+
+// overlayPrev returns the last key written in the transaction that comes
+// before the given key. A nil key returns the last key.
+func (c *overlayCursor) overlayPrev(key []byte) []byte {
+	keys := c.bucket.sortedKeys()
+	i := len(keys)
+	if key != nil {
+		i = sort.Search(len(keys), func(i int) bool {
+			return bytes.Compare([]byte(keys[i]), key) >= 0
+		})
+	}
+	if i == 0 {
+		return nil
+	}
+
+	return []byte(keys[i-1])
+}
+
+// moveTo moves the cursor to the given key and returns the key and its value.
+func (c *overlayCursor) moveTo(key []byte) ([]byte, []byte) {
+	c.key = key
+	if key == nil {
+		return nil, nil
+	}
+
+	return key, c.bucket.Get(key)
+}
+
+// next moves the cursor to the first key after the given key, or equal to it
+// if inclusive is set.
+func (c *overlayCursor) next(key []byte, inclusive bool) ([]byte, []byte) {
+	baseKey := c.baseNext(key, inclusive)
+	overlayKey := c.overlayNext(key, inclusive)
+
+	switch {
+	case baseKey == nil:
+		return c.moveTo(overlayKey)
+
+	case overlayKey == nil:
+		return c.moveTo(baseKey)
+
+	case bytes.Compare(baseKey, overlayKey) < 0:
+		return c.moveTo(baseKey)
+
+	default:
+		return c.moveTo(overlayKey)
+	}
+}
+
+// prev moves the cursor to the last key before the given key.
+func (c *overlayCursor) prev(key []byte) ([]byte, []byte) {
+	baseKey := c.basePrev(key)
+	overlayKey := c.overlayPrev(key)
+
+	switch {
+	case baseKey == nil:
+		return c.moveTo(overlayKey)
+
+	case overlayKey == nil:
+		return c.moveTo(baseKey)
+
+	case bytes.Compare(baseKey, overlayKey) > 0:
+		return c.moveTo(baseKey)
+
+	default:
+		return c.moveTo(overlayKey)
+	}
+}
+
+// First moves the cursor to the first key and returns it with its value.
+//
+// NOTE: This is part of the walletdb.ReadCursor interface.
+func (c *overlayCursor) First() ([]byte, []byte) {
+	return c.next(nil, true)
+}
+
+// Last moves the cursor to the last key and returns it with its value.
+//
+// NOTE: This is part of the walletdb.ReadCursor interface.
+func (c *overlayCursor) Last() ([]byte, []byte) {
+	return c.prev(nil)
+}
+
+// Next moves the cursor to the next key and returns it with its value.
+//
+// NOTE: This is part of the walletdb.ReadCursor interface.
+func (c *overlayCursor) Next() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+
+	return c.next(c.key, false)
+}
+
+// Prev moves the cursor to the previous key and returns it with its value.
+//
+// NOTE: This is part of the walletdb.ReadCursor interface.
+func (c *overlayCursor) Prev() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+
+	return c.prev(c.key)
+}
+
+// Seek moves the cursor to the first key that is equal to or comes after the
+// given key and returns it with its value.
+//
+// NOTE: This is part of the walletdb.ReadCursor interface.
+func (c *overlayCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.next(seek, true)
+}
+
+// Delete deletes the key the cursor points to.
+//
+// NOTE: This is part of the walletdb.ReadWriteCursor interface.
+func (c *overlayCursor) Delete() error {
+	if c.key == nil {
+		return walletdb.ErrKeyRequired
+	}
+
+	return c.bucket.Delete(c.key)
+}
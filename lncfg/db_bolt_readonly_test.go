@@ -0,0 +1,161 @@
+package lncfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBoltReadOnlyDB tests that the writes of read-write transactions on a
+// read-only bolt database are visible within the transaction, but are never
+// written to the database file.
+func TestBoltReadOnlyDB(t *testing.T) {
+	t.Parallel()
+
+	dbPath := t.TempDir()
+	db, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:     dbPath,
+		DBFileName: "test.db",
+		DBTimeout:  kvdb.DefaultDBTimeout,
+	})
+	require.NoError(t, err)
+
+	var (
+		topKey    = []byte("top")
+		nestedKey = []byte("b")
+	)
+	err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+		top, err := tx.CreateTopLevelBucket(topKey)
+		if err != nil {
+			return err
+		}
+
+		for _, k := range []string{"a", "c", "e"} {
+			if err := top.Put([]byte(k), []byte(k)); err != nil {
+				return err
+			}
+		}
+
+		nested, err := top.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		if err := nested.Put([]byte("x"), []byte("x")); err != nil {
+			return err
+		}
+
+		return top.SetSequence(5)
+	}, func() {})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	dbFilePath := filepath.Join(dbPath, "test.db")
+	original, err := os.ReadFile(dbFilePath)
+	require.NoError(t, err)
+
+	readOnlyDB, err := openBoltReadOnly(dbFilePath, DefaultDB().Bolt)
+	require.NoError(t, err)
+
+	// keys returns the keys of the bucket, iterating forwards or
+	// backwards.
+	keys := func(bucket kvdb.RBucket, forward bool) []string {
+		var keys []string
+		c := bucket.ReadCursor()
+		k, _ := c.First()
+		next := c.Next
+		if !forward {
+			k, _ = c.Last()
+			next = c.Prev
+		}
+		for ; k != nil; k, _ = next() {
+			keys = append(keys, string(k))
+		}
+
+		return keys
+	}
+
+	err = kvdb.Update(readOnlyDB, func(tx kvdb.RwTx) error {
+		top := tx.ReadWriteBucket(topKey)
+		require.NotNil(t, top)
+		require.Equal(t, []string{"a", "b", "c", "e"}, keys(top, true))
+
+		// Writes are visible within the transaction and merged with
+		// the content of the database.
+		require.NoError(t, top.Put([]byte("d"), []byte("d")))
+		require.NoError(t, top.Put([]byte("a"), []byte("new")))
+		require.NoError(t, top.Delete([]byte("c")))
+		require.Equal(t, []byte("new"), top.Get([]byte("a")))
+		require.Nil(t, top.Get([]byte("c")))
+
+		created, err := top.CreateBucket([]byte("f"))
+		require.NoError(t, err)
+		require.NoError(t, created.Put([]byte("y"), []byte("y")))
+
+		require.Equal(
+			t, []string{"a", "b", "d", "e", "f"}, keys(top, true),
+		)
+		require.Equal(
+			t, []string{"f", "e", "d", "b", "a"}, keys(top, false),
+		)
+
+		k, v := top.ReadCursor().Seek([]byte("c"))
+		require.Equal(t, []byte("d"), k)
+		require.Equal(t, []byte("d"), v)
+
+		// Nested buckets are only accessible as buckets.
+		_, err = top.CreateBucket(nestedKey)
+		require.ErrorIs(t, err, kvdb.ErrBucketExists)
+		err = top.Put(nestedKey, nil)
+		require.ErrorIs(t, err, walletdb.ErrIncompatibleValue)
+		require.Equal(
+			t, []byte("x"),
+			top.NestedReadBucket(nestedKey).Get([]byte("x")),
+		)
+
+		// Deleting and recreating a nested bucket empties it.
+		require.NoError(t, top.DeleteNestedBucket(nestedKey))
+		require.Nil(t, top.NestedReadBucket(nestedKey))
+		nested, err := top.CreateBucket(nestedKey)
+		require.NoError(t, err)
+		require.Empty(t, keys(nested, true))
+
+		// Deleting the keys through a cursor while iterating skips
+		// none of them.
+		c := top.ReadWriteCursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if top.NestedReadBucket(k) == nil {
+				require.NoError(t, c.Delete())
+			}
+		}
+		require.Equal(t, []string{"b", "f"}, keys(top, true))
+
+		require.EqualValues(t, 5, top.Sequence())
+		seq, err := top.NextSequence()
+		require.NoError(t, err)
+		require.EqualValues(t, 6, seq)
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// None of the writes are visible in later transactions.
+	err = kvdb.View(readOnlyDB, func(tx kvdb.RTx) error {
+		top := tx.ReadBucket(topKey)
+		require.Equal(t, []string{"a", "b", "c", "e"}, keys(top, true))
+		require.Equal(t, []byte("a"), top.Get([]byte("a")))
+		require.Nil(t, tx.ReadBucket([]byte("other")))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+	require.NoError(t, readOnlyDB.Close())
+
+	// The database file wasn't modified.
+	current, err := os.ReadFile(dbFilePath)
+	require.NoError(t, err)
+	require.Equal(t, original, current)
+}
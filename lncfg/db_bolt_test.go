@@ -0,0 +1,101 @@
+package lncfg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBoltDBUsageAndCompaction tests that the disk usage of bolt databases is
+// reported and that a scheduled compaction is done on the next startup.
+func TestBoltDBUsageAndCompaction(t *testing.T) {
+	t.Parallel()
+
+	cfg := lncfg.DefaultDB()
+	chanDBPath := t.TempDir()
+	walletDBPath := t.TempDir()
+
+	openBackends := func() *lncfg.DatabaseBackends {
+		backends, err := cfg.GetBackends(
+			context.Background(), chanDBPath, walletDBPath, "",
+			false, false, false, btclog.Disabled,
+		)
+		require.NoError(t, err)
+
+		return backends
+	}
+	closeBackends := func(backends *lncfg.DatabaseBackends) {
+		for _, closeFunc := range backends.CloseFuncs {
+			require.NoError(t, closeFunc())
+		}
+	}
+
+	backends := openBackends()
+	require.Contains(t, backends.BoltDBs, lncfg.NSChannelDB)
+	require.Contains(t, backends.BoltDBs, lncfg.NSMacaroonDB)
+	require.Contains(t, backends.BoltDBs, lncfg.NSDecayedLogDB)
+
+	// Add a bucket with a nested bucket to the channel DB.
+	channelDB := backends.BoltDBs[lncfg.NSChannelDB]
+	err := kvdb.Update(channelDB.Backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket([]byte("test"))
+		if err != nil {
+			return err
+		}
+
+		err = bucket.Put([]byte("key"), []byte("value"))
+		if err != nil {
+			return err
+		}
+
+		nested, err := bucket.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+
+		return nested.Put([]byte("k"), []byte("v"))
+	}, func() {})
+	require.NoError(t, err)
+
+	usage, err := lncfg.GetBoltDBUsage(channelDB)
+	require.NoError(t, err)
+	require.Positive(t, usage.FileSize)
+	require.True(t, usage.LastCompacted.IsZero())
+	require.False(t, usage.CompactionScheduled)
+	require.Equal(t, []*lncfg.BucketUsage{{
+		Name:       "test",
+		NumKeys:    2,
+		NumBuckets: 1,
+		KeyBytes:   uint64(len("key") + len("nested") + len("k")),
+		ValueBytes: uint64(len("value") + len("v")),
+	}}, usage.Buckets)
+
+	// Schedule the compaction of the channel DB.
+	require.NoError(t, lncfg.ScheduleBoltCompaction(channelDB.FilePath))
+
+	usage, err = lncfg.GetBoltDBUsage(channelDB)
+	require.NoError(t, err)
+	require.True(t, usage.CompactionScheduled)
+
+	// The compaction is done when the database is opened again, after
+	// which the request is removed.
+	closeBackends(backends)
+	backends = openBackends()
+	defer closeBackends(backends)
+
+	channelDB = backends.BoltDBs[lncfg.NSChannelDB]
+	usage, err = lncfg.GetBoltDBUsage(channelDB)
+	require.NoError(t, err)
+	require.False(t, usage.LastCompacted.IsZero())
+	require.False(t, usage.CompactionScheduled)
+	require.Len(t, usage.Buckets, 1)
+
+	// The other databases weren't compacted.
+	usage, err = lncfg.GetBoltDBUsage(backends.BoltDBs[lncfg.NSMacaroonDB])
+	require.NoError(t, err)
+	require.True(t, usage.LastCompacted.IsZero())
+}
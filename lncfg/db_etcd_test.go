@@ -0,0 +1,57 @@
+//go:build kvdb_etcd
+// +build kvdb_etcd
+
+package lncfg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lntest/port"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEtcdFencedBackends tests that all etcd database backends support fencing
+// tokens, and that fencing one namespace doesn't affect the others.
+func TestEtcdFencedBackends(t *testing.T) {
+	etcdCfg, cleanup, err := kvdb.StartEtcdTestBackend(
+		t.TempDir(), uint16(port.NextAvailablePort()),
+		uint16(port.NextAvailablePort()), "",
+	)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	cfg := lncfg.DefaultDB()
+	cfg.Backend = lncfg.EtcdBackend
+	cfg.Etcd = etcdCfg
+
+	backends, err := cfg.GetBackends(
+		context.Background(), t.TempDir(), t.TempDir(), t.TempDir(),
+		true, true, false, btclog.Disabled,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		for _, closeFunc := range backends.CloseFuncs {
+			require.NoError(t, closeFunc())
+		}
+	})
+
+	require.Len(t, backends.FencedDBs, 6)
+	for _, ns := range []string{
+		lncfg.NSChannelDB, lncfg.NSMacaroonDB, lncfg.NSDecayedLogDB,
+		lncfg.NSTowerClientDB, lncfg.NSTowerServerDB,
+		lncfg.NSWalletDB,
+	} {
+		require.Contains(t, backends.FencedDBs, ns)
+	}
+
+	// Fencing is tracked per namespace, so a newer token in one of them
+	// doesn't reject an older one in another.
+	fencedDBs := backends.FencedDBs
+	require.NoError(t, fencedDBs[lncfg.NSChannelDB].SetFencingToken(2))
+	require.NoError(t, fencedDBs[lncfg.NSWalletDB].SetFencingToken(1))
+	require.Error(t, fencedDBs[lncfg.NSChannelDB].SetFencingToken(1))
+}
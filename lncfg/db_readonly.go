@@ -0,0 +1,91 @@
+package lncfg
+
+import (
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// readOnlyBackend is a database backend that never persists any writes.
+// Read-write transactions can still be used, but they are rolled back instead
+// of being committed. This allows the subsystems that write to the database
+// during startup, for example to create their buckets, to keep working
+// without any change making it to the underlying database.
+type readOnlyBackend struct {
+	kvdb.Backend
+}
+
+// A compile time check to ensure readOnlyBackend implements the kvdb.Backend
+// interface.
+var _ kvdb.Backend = (*readOnlyBackend)(nil)
+
+// newReadOnlyBackend wraps the given backend such that no writes are ever
+// persisted. A nil backend is returned as is.
+func newReadOnlyBackend(backend kvdb.Backend) kvdb.Backend {
+	if backend == nil {
+		return nil
+	}
+
+	return &readOnlyBackend{
+		Backend: backend,
+	}
+}
+
+// makeReadOnly wraps all the given backends such that none of their writes
+// are persisted.
+func makeReadOnly(backends ...*kvdb.Backend) {
+	for _, backend := range backends {
+		*backend = newReadOnlyBackend(*backend)
+	}
+}
+
+// BeginReadWriteTx opens a database read+write transaction that is rolled back
+// when it is committed.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *readOnlyBackend) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	tx, err := b.Backend.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyTx{
+		ReadWriteTx: tx,
+	}, nil
+}
+
+// Update opens a database read+write transaction and executes the function f
+// with the transaction passed as a parameter. Regardless of the outcome of f,
+// the transaction is rolled back afterwards.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *readOnlyBackend) Update(f func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	reset()
+
+	tx, err := b.Backend.BeginReadWriteTx()
+	if err != nil {
+		return err
+	}
+
+	err = f(tx)
+	rollbackErr := tx.Rollback()
+	if err != nil {
+		return err
+	}
+
+	return rollbackErr
+}
+
+// readOnlyTx is a read+write transaction that is rolled back instead of being
+// committed.
+type readOnlyTx struct {
+	walletdb.ReadWriteTx
+}
+
+// Commit rolls back the transaction, discarding all of its changes.
+//
+// NOTE: This is part of the walletdb.ReadWriteTx interface.
+func (tx *readOnlyTx) Commit() error {
+	return tx.ReadWriteTx.Rollback()
+}
@@ -0,0 +1,126 @@
+package lncfg_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+// TestReadOnlyBackends tests that none of the writes to the database backends
+// are persisted in read-only mode.
+func TestReadOnlyBackends(t *testing.T) {
+	t.Parallel()
+
+	cfg := lncfg.DefaultDB()
+	cfg.Bolt.DBTimeout = time.Second
+	chanDBPath := t.TempDir()
+	walletDBPath := t.TempDir()
+
+	openBackends := func(readOnly bool) (*lncfg.DatabaseBackends, error) {
+		return cfg.GetBackends(
+			context.Background(), chanDBPath, walletDBPath, "",
+			false, false, readOnly, btclog.Disabled,
+		)
+	}
+	closeBackends := func(backends *lncfg.DatabaseBackends) {
+		for _, closeFunc := range backends.CloseFuncs {
+			require.NoError(t, closeFunc())
+		}
+	}
+
+	// The databases can't be created in read-only mode.
+	_, err := openBackends(true)
+	require.ErrorContains(t, err, "can't be created in read-only mode")
+
+	// Create the databases and store a value in the channel DB.
+	backends, err := openBackends(false)
+	require.NoError(t, err)
+
+	var (
+		bucketKey = []byte("bucket")
+		key       = []byte("key")
+		value     = []byte("value")
+	)
+	put := func(db kvdb.Backend, value []byte) error {
+		return kvdb.Update(db, func(tx kvdb.RwTx) error {
+			bucket, err := tx.CreateTopLevelBucket(bucketKey)
+			if err != nil {
+				return err
+			}
+
+			return bucket.Put(key, value)
+		}, func() {})
+	}
+	get := func(db kvdb.Backend) []byte {
+		var result []byte
+		err := kvdb.View(db, func(tx kvdb.RTx) error {
+			bucket := tx.ReadBucket(bucketKey)
+			if bucket == nil {
+				return nil
+			}
+
+			result = bucket.Get(key)
+
+			return nil
+		}, func() {})
+		require.NoError(t, err)
+
+		return result
+	}
+
+	require.NoError(t, put(backends.ChanStateDB, value))
+	closeBackends(backends)
+
+	// The wallet DB is opened by the wallet loader in normal mode, so we
+	// create it here to be able to open the backends in read-only mode.
+	walletDB, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:     walletDBPath,
+		DBFileName: lncfg.WalletDBName,
+		DBTimeout:  kvdb.DefaultDBTimeout,
+	})
+	require.NoError(t, err)
+	require.NoError(t, walletDB.Close())
+
+	// In read-only mode, the existing value can be read and writes
+	// succeed, but they aren't visible afterwards.
+	backends, err = openBackends(true)
+	require.NoError(t, err)
+	require.Equal(t, value, get(backends.ChanStateDB))
+
+	require.NoError(t, put(backends.ChanStateDB, []byte("other")))
+	require.Equal(t, value, get(backends.ChanStateDB))
+
+	// The databases are only locked shared in read-only mode, so another
+	// read-only instance can open them, but an instance that writes to
+	// them can't.
+	otherBackends, err := openBackends(true)
+	require.NoError(t, err)
+	require.Equal(t, value, get(otherBackends.ChanStateDB))
+	closeBackends(otherBackends)
+
+	_, err = openBackends(false)
+	require.ErrorIs(t, err, bbolt.ErrTimeout)
+
+	tx, err := backends.MacaroonDB.BeginReadWriteTx()
+	require.NoError(t, err)
+	_, err = tx.CreateTopLevelBucket(bucketKey)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Nil(t, get(backends.MacaroonDB))
+
+	closeBackends(backends)
+
+	// The write also wasn't persisted once the databases are opened in
+	// normal mode again.
+	backends, err = openBackends(false)
+	require.NoError(t, err)
+	defer closeBackends(backends)
+
+	require.Equal(t, value, get(backends.ChanStateDB))
+}
@@ -46,3 +46,21 @@ func (d *DevConfig) GetReservationTimeout() time.Duration {
 func (d *DevConfig) GetZombieSweeperInterval() time.Duration {
 	return DefaultZombieSweeperInterval
 }
+
+// GetImportChannelDB returns the config value for `ImportChannelDB`, which is
+// always empty for production build.
+func (d *DevConfig) GetImportChannelDB() string {
+	return ""
+}
+
+// GetTimeWarp returns the config value for `TimeWarp`, which is always false
+// for production build.
+func (d *DevConfig) GetTimeWarp() bool {
+	return false
+}
+
+// GetCLTVScale returns the config value for `CLTVScale`, which is always 1 for
+// production build.
+func (d *DevConfig) GetCLTVScale() uint32 {
+	return 1
+}
@@ -25,6 +25,9 @@ type DevConfig struct {
 	ReservationTimeout      time.Duration `long:"reservationtimeout" description:"The maximum time we keep a pending channel open flow in memory."`
 	ZombieSweeperInterval   time.Duration `long:"zombiesweeperinterval" description:"The time interval at which channel opening flows are evaluated for zombie status."`
 	UnsafeDisconnect        bool          `long:"unsafedisconnect" description:"Allows the rpcserver to intentionally disconnect from peers with open channels."`
+	ImportChannelDB         string        `long:"importchanneldb" description:"Path to a channel db export created with the ExportChannelDB dev RPC that is imported into the empty SQL database on startup."`
+	TimeWarp                bool          `long:"timewarp" description:"Use a clock for all time based subsystems that can be moved forward with the WarpTime dev RPC."`
+	CLTVScale               uint32        `long:"cltvscale" description:"Scale down the CLTV and CSV deltas used for invoices, htlc forwarding and on-chain resolution by this factor, so timeout paths can be tested without mining hundreds of blocks. Only allowed on simnet and regtest."`
 }
 
 // ChannelReadyWait returns the config value `ProcessChannelReadyWait`.
@@ -54,3 +57,23 @@ func (d *DevConfig) GetZombieSweeperInterval() time.Duration {
 func (d *DevConfig) GetUnsafeDisconnect() bool {
 	return d.UnsafeDisconnect
 }
+
+// GetImportChannelDB returns the config value for `ImportChannelDB`.
+func (d *DevConfig) GetImportChannelDB() string {
+	return d.ImportChannelDB
+}
+
+// GetTimeWarp returns the config value for `TimeWarp`.
+func (d *DevConfig) GetTimeWarp() bool {
+	return d.TimeWarp
+}
+
+// GetCLTVScale returns the config value for `CLTVScale`. A value of zero is
+// treated as 1, which leaves all deltas unchanged.
+func (d *DevConfig) GetCLTVScale() uint32 {
+	if d.CLTVScale == 0 {
+		return 1
+	}
+
+	return d.CLTVScale
+}
@@ -0,0 +1,88 @@
+package lncfg
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// FeaturePolicy holds the feature bits peers must or must not set in their
+// init message.
+//
+//nolint:lll
+type FeaturePolicy struct {
+	Require []string `long:"require" description:"A feature peers must signal, given by its name (e.g. anchors-zero-fee-htlc-tx) or bit number. Either bit of the feature's pair satisfies the requirement. Peers that don't signal it are disconnected. Can be specified multiple times."`
+
+	Forbid []string `long:"forbid" description:"A feature peers must not signal, given by its name or bit number. Peers that set either bit of the feature's pair are disconnected. Can be specified multiple times."`
+}
+
+// parseFeatureBit parses a feature given by its name or bit number.
+func parseFeatureBit(feature string) (lnwire.FeatureBit, error) {
+	if bit, err := strconv.ParseUint(feature, 10, 16); err == nil {
+		return lnwire.FeatureBit(bit), nil
+	}
+
+	for bit, name := range lnwire.Features {
+		if name == feature {
+			return bit, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown feature %q", feature)
+}
+
+// parseFeatureBits parses the given features, given by their names or bit
+// numbers.
+func parseFeatureBits(features []string) ([]lnwire.FeatureBit, error) {
+	bits := make([]lnwire.FeatureBit, 0, len(features))
+	for _, feature := range features {
+		bit, err := parseFeatureBit(feature)
+		if err != nil {
+			return nil, err
+		}
+		bits = append(bits, bit)
+	}
+
+	return bits, nil
+}
+
+// Bits returns the feature bits that are required and forbidden by the
+// policy.
+func (f *FeaturePolicy) Bits() ([]lnwire.FeatureBit, []lnwire.FeatureBit,
+	error) {
+
+	required, err := parseFeatureBits(f.Require)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid featurepolicy.require: %w",
+			err)
+	}
+
+	forbidden, err := parseFeatureBits(f.Forbid)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid featurepolicy.forbid: %w",
+			err)
+	}
+
+	return required, forbidden, nil
+}
+
+// Validate checks the values configured for the feature policy.
+func (f *FeaturePolicy) Validate() error {
+	required, forbidden, err := f.Bits()
+	if err != nil {
+		return err
+	}
+
+	// A feature pair can't be both required and forbidden.
+	for _, req := range required {
+		for _, forbid := range forbidden {
+			if req>>1 == forbid>>1 {
+				return fmt.Errorf("feature bit %d can't be "+
+					"both required and forbidden", req)
+			}
+		}
+	}
+
+	return nil
+}
@@ -17,6 +17,12 @@ type Gossip struct {
 
 	ChannelUpdateInterval time.Duration `long:"channel-update-interval" description:"The interval used to determine how often lnd should allow a burst of new updates for a specific channel and direction."`
 
+	MaxLocalChannelUpdateBurst int `long:"max-local-channel-update-burst" description:"The maximum number of our own updates for a specific channel and direction that lnd will broadcast over the local channel update interval. Updates exceeding this rate are held back until the rate allows broadcasting them, and are replaced by newer updates for the same channel in the meantime."`
+
+	LocalChannelUpdateInterval time.Duration `long:"local-channel-update-interval" description:"The interval used to determine how often lnd should allow broadcasting a burst of its own updates for a specific channel and direction. Set to 0 to not rate limit our own updates."`
+
+	ThrottleStatusUpdates bool `long:"throttle-status-updates" description:"If set, our own updates that disable or enable a channel are rate limited like any other update. By default they are broadcast right away, so that the network learns about unusable channels without delay."`
+
 	SubBatchDelay time.Duration `long:"sub-batch-delay" description:"The duration to wait before sending the next announcement batch if there are multiple. Use a small value if there are a lot announcements and they need to be broadcast quickly."`
 }
 
@@ -31,9 +31,11 @@ type HealthCheckConfig struct {
 
 	TLSCheck *CheckConfig `group:"tls" namespace:"tls"`
 
-	TorConnection *CheckConfig `group:"torconnection" namespace:"torconnection"`
+	TorConnection *TorConnectionCheckConfig `group:"torconnection" namespace:"torconnection"`
 
 	RemoteSigner *CheckConfig `group:"remotesigner" namespace:"remotesigner"`
+
+	RemoteDB *RemoteDBCheckConfig `group:"remotedb" namespace:"remotedb"`
 }
 
 // Validate checks the values configured for our health checks.
@@ -60,6 +62,15 @@ func (h *HealthCheckConfig) Validate() error {
 		return err
 	}
 
+	if err := h.RemoteDB.validate("remote database"); err != nil {
+		return err
+	}
+
+	if h.RemoteDB.MaxLatency < 0 || h.RemoteDB.MaxReplicationLag < 0 {
+		return errors.New("remote database max latency and max " +
+			"replication lag must not be negative")
+	}
+
 	return nil
 }
 
@@ -104,3 +115,27 @@ type DiskCheckConfig struct {
 
 	*CheckConfig
 }
+
+// TorConnectionCheckConfig contains the configuration of the health check of
+// the connection to the Tor daemon.
+//
+//nolint:lll
+type TorConnectionCheckConfig struct {
+	DaemonStatus bool `long:"daemonstatus" description:"Also fail the check if the Tor daemon hasn't finished bootstrapping or doesn't consider the Tor network reachable, even if the connection to it and the onion service are up."`
+
+	*CheckConfig
+}
+
+// RemoteDBCheckConfig contains the configuration of the health check of remote
+// database backends, such as postgres and etcd.
+//
+//nolint:lll
+type RemoteDBCheckConfig struct {
+	MaxLatency time.Duration `long:"maxlatency" description:"The maximum time a probe of the database may take before the attempt fails. Set this value to 0 to only fail on the timeout."`
+
+	MaxReplicationLag time.Duration `long:"maxreplicationlag" description:"The maximum replay lag of the postgres replicas before the attempt fails. This is only detectable if native SQL is used and the database user may read the replication statistics. Set this value to 0 to not check the replication lag."`
+
+	Shutdown bool `long:"shutdown" description:"If the check fails, shut lnd down instead of entering safe mode. In safe mode, lnd stops accepting new HTLCs until the check passes again."`
+
+	*CheckConfig
+}
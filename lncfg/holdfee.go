@@ -0,0 +1,58 @@
+package lncfg
+
+import "fmt"
+
+const (
+	// DefaultHoldFeeFreeBlocks is the default CLTV distance up to which
+	// forwards don't need to pay a hold fee surcharge.
+	DefaultHoldFeeFreeBlocks = 144
+
+	// DefaultHoldFeeRatePerBlock is the default surcharge in parts per
+	// million of the forwarded amount per block of CLTV distance above
+	// the free blocks.
+	DefaultHoldFeeRatePerBlock = 1
+
+	// DefaultHoldFeeQueueMultiplier is the default factor the surcharge
+	// rate is escalated with when all htlc slots of the outgoing channel
+	// are in use.
+	DefaultHoldFeeQueueMultiplier = 4
+)
+
+// HoldFee holds the configuration of the hold fee policy.
+//
+//nolint:lll
+type HoldFee struct {
+	Active bool `long:"active" description:"Require a surcharge on top of the regular forwarding fee for forwards that may lock up liquidity for long, and refuse forwards whose hold risk is too high."`
+
+	DryRun bool `long:"dry-run" description:"Only record the decisions of the hold fee policy without enforcing them. The decisions are returned by the HoldFeeStatus RPC."`
+
+	FreeBlocks uint32 `long:"free-blocks" description:"The CLTV distance of the outgoing htlc up to which no surcharge is required."`
+
+	RatePerBlock uint32 `long:"rate-per-block" description:"The surcharge in parts per million of the forwarded amount required for every block of CLTV distance above the free blocks."`
+
+	QueueMultiplier uint32 `long:"queue-multiplier" description:"Escalate the surcharge rate with the share of htlc slots in use on the outgoing channel. With all slots in use, the rate is multiplied by 1 + queue-multiplier."`
+
+	MaxRate uint32 `long:"max-rate" description:"The surcharge rate in parts per million above which forwards are refused. Set to 0 to never refuse forwards."`
+}
+
+// DefaultHoldFee returns the default configuration of the hold fee policy.
+func DefaultHoldFee() *HoldFee {
+	return &HoldFee{
+		FreeBlocks:      DefaultHoldFeeFreeBlocks,
+		RatePerBlock:    DefaultHoldFeeRatePerBlock,
+		QueueMultiplier: DefaultHoldFeeQueueMultiplier,
+	}
+}
+
+// Validate checks the values configured for the hold fee policy.
+func (h *HoldFee) Validate() error {
+	if !h.Active {
+		return nil
+	}
+
+	if h.RatePerBlock == 0 {
+		return fmt.Errorf("holdfee.rate-per-block must be positive")
+	}
+
+	return nil
+}
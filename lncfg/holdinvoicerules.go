@@ -0,0 +1,81 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultHoldInvoiceWebhookTimeout is the default time the approval
+	// webhook may take to respond for a single accepted hold invoice.
+	DefaultHoldInvoiceWebhookTimeout = 10 * time.Second
+)
+
+// HoldInvoiceRules holds the configuration of the rules that automatically
+// settle or cancel accepted hold invoices.
+//
+//nolint:lll
+type HoldInvoiceRules struct {
+	Active bool `long:"active" description:"Automatically settle or cancel accepted hold invoices according to the configured rules."`
+
+	WebhookURL string `long:"webhook" description:"The http(s) URL accepted hold invoices are posted to for approval. The response decides whether the invoice is settled, canceled or held."`
+
+	WebhookTimeout time.Duration `long:"webhook-timeout" description:"The time the webhook may take to respond. An invoice is held if the webhook doesn't respond in time. Valid time units are {s, m, h}."`
+
+	AcceptTimeout time.Duration `long:"accept-timeout" description:"Cancel hold invoices that were neither settled nor canceled within this time after they were accepted. Zero disables the timeout. Valid time units are {s, m, h}."`
+
+	MinAmtMsat uint64 `long:"min-amt-msat" description:"Cancel accepted hold invoices that were paid less than this amount in millisatoshis."`
+
+	MaxAutoSettleAmtMsat uint64 `long:"max-autosettle-amt-msat" description:"Settle accepted hold invoices that were paid at most this amount in millisatoshis, if lnd knows their preimage. Zero disables the rule."`
+}
+
+// DefaultHoldInvoiceRules returns the default configuration of the hold
+// invoice rules.
+func DefaultHoldInvoiceRules() *HoldInvoiceRules {
+	return &HoldInvoiceRules{
+		WebhookTimeout: DefaultHoldInvoiceWebhookTimeout,
+	}
+}
+
+// Validate checks the values configured for the hold invoice rules.
+func (h *HoldInvoiceRules) Validate() error {
+	if !h.Active {
+		return nil
+	}
+
+	if h.WebhookURL != "" {
+		u, err := url.Parse(h.WebhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid holdinvoicerules.webhook: %w",
+				err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("holdinvoicerules.webhook must be an " +
+				"http or https URL")
+		}
+		if u.Host == "" {
+			return fmt.Errorf("holdinvoicerules.webhook must have " +
+				"a host")
+		}
+
+		if h.WebhookTimeout <= 0 {
+			return fmt.Errorf("holdinvoicerules.webhook-timeout " +
+				"must be positive")
+		}
+	}
+
+	if h.AcceptTimeout < 0 {
+		return fmt.Errorf("holdinvoicerules.accept-timeout must not " +
+			"be negative")
+	}
+
+	if h.MaxAutoSettleAmtMsat != 0 &&
+		h.MaxAutoSettleAmtMsat < h.MinAmtMsat {
+
+		return fmt.Errorf("holdinvoicerules.max-autosettle-amt-msat " +
+			"must not be below holdinvoicerules.min-amt-msat")
+	}
+
+	return nil
+}
@@ -11,11 +11,23 @@ var (
 	// where both side send 483 payments at the same time to stress test
 	// lnd.
 	MaxMailboxDeliveryTimeout = 2 * time.Minute
+
+	// DefaultFlapDrainWindow is the default window in which the
+	// disconnects of a peer are counted to decide whether it's flapping.
+	DefaultFlapDrainWindow = 10 * time.Minute
 )
 
 //nolint:lll
 type Htlcswitch struct {
 	MailboxDeliveryTimeout time.Duration `long:"mailboxdeliverytimeout" description:"The timeout value when delivering HTLCs to a channel link. Setting this value too small will result in local payment failures if large number of payments are sent over a short period."`
+
+	FlapDrainThreshold uint32 `long:"flapdrainthreshold" description:"The number of disconnects within the flapdrainwindow after which a peer is considered flapping. New forwards through the channels of a flapping peer are paused once it reconnects, so its outstanding HTLCs can be resolved. Forwards are resumed if the peer stays connected for the flapdrainwindow. Set to 0 to disable."`
+
+	FlapDrainWindow time.Duration `long:"flapdrainwindow" description:"The window in which the disconnects of a peer are counted to decide whether it's flapping, which is also the duration a reconnected flapping peer must stay connected before forwards through its channels are resumed."`
+
+	NetworkResultRetention time.Duration `long:"networkresultretention" description:"The duration the results of resolved payment attempts are kept in the network result store. Results of attempts whose payment was deleted are always removed. Set to 0 to keep results until their payment is deleted."`
+
+	NetworkResultPruneInterval time.Duration `long:"networkresultpruneinterval" description:"The interval at which the network result store is pruned."`
 }
 
 // Validate checks the values configured for htlcswitch.
@@ -30,5 +42,20 @@ func (h *Htlcswitch) Validate() error {
 			MaxMailboxDeliveryTimeout)
 	}
 
+	if h.FlapDrainThreshold > 0 && h.FlapDrainWindow <= 0 {
+		return fmt.Errorf("flapdrainwindow must be positive when " +
+			"flapdrainthreshold is set")
+	}
+
+	if h.NetworkResultRetention < 0 {
+		return fmt.Errorf("networkresultretention must not be " +
+			"negative")
+	}
+
+	if h.NetworkResultPruneInterval <= 0 {
+		return fmt.Errorf("networkresultpruneinterval must be " +
+			"positive")
+	}
+
 	return nil
 }
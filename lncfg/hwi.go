@@ -0,0 +1,54 @@
+package lncfg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultHWIPath is the default path of the HWI executable.
+	DefaultHWIPath = "hwi"
+
+	// DefaultHWITimeout is the default timeout of a single call to the
+	// hardware wallet. It includes the time the user needs to confirm a
+	// transaction on the device.
+	DefaultHWITimeout = 2 * time.Minute
+)
+
+// HWI holds the configuration options for signing wallet inputs with a
+// hardware wallet through HWI.
+//
+//nolint:lll
+type HWI struct {
+	Enable       bool          `long:"enable" description:"Sign on-chain wallet inputs that are derived from the master key of a hardware wallet with the device, using HWI. Channel keys are never signed by the device."`
+	Path         string        `long:"path" description:"The path to the HWI executable."`
+	DeviceType   string        `long:"devicetype" description:"The type of the hardware wallet, for example trezor or ledger. Only needed if HWI can't detect the device by its fingerprint alone."`
+	Fingerprint  string        `long:"fingerprint" description:"The hex encoded master key fingerprint of the hardware wallet. Only inputs derived from this master key are sent to the device."`
+	Timeout      time.Duration `long:"timeout" description:"The maximum time a single call to the hardware wallet may take, including the time needed to confirm the transaction on the device. Valid time units are {s, m, h}."`
+	SkipApproval bool          `long:"skipapproval" description:"Forward signing requests to the hardware wallet right away instead of waiting for their approval through RPC."`
+}
+
+// Validate checks the values configured for the hardware wallet signer.
+func (h *HWI) Validate() error {
+	if !h.Enable {
+		return nil
+	}
+
+	if h.Path == "" {
+		return fmt.Errorf("hwi: path of the HWI executable must be set")
+	}
+
+	fingerprint, err := hex.DecodeString(h.Fingerprint)
+	if err != nil || len(fingerprint) != 4 {
+		return fmt.Errorf("hwi: fingerprint must be 4 hex encoded "+
+			"bytes, got %q", h.Fingerprint)
+	}
+
+	if h.Timeout < time.Second {
+		return fmt.Errorf("hwi: timeout of %v is invalid, cannot be "+
+			"smaller than %v", h.Timeout, time.Second)
+	}
+
+	return nil
+}
@@ -1,5 +1,10 @@
 package lncfg
 
+import (
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
 // DefaultHoldInvoiceExpiryDelta defines the number of blocks before the expiry
 // height of a hold invoice's htlc that lnd will automatically cancel the
 // invoice to prevent the channel from force closing. This value *must* be
@@ -11,4 +16,24 @@ const DefaultHoldInvoiceExpiryDelta = DefaultIncomingBroadcastDelta + 2
 //nolint:lll
 type Invoices struct {
 	HoldExpiryDelta uint32 `long:"holdexpirydelta" description:"The number of blocks before a hold invoice's htlc expires that the invoice should be canceled to prevent a force close. Force closes will not be prevented if this value is not greater than DefaultIncomingBroadcastDelta."`
+
+	DeterministicPreimages bool `long:"deterministic-preimages" description:"Derive the preimages of new invoices from the wallet seed and a preimage index instead of drawing them at random, so they can be recovered from the seed."`
+
+	MaxInvoiceHtlcSets uint32 `long:"max-invoice-htlc-sets" description:"The maximum number of HTLC sets with accepted HTLCs a single invoice may have. Only AMP invoices can have more than one. Set to 0 to disable."`
+
+	MaxInvoicePendingMsat uint64 `long:"max-invoice-pending-msat" description:"The maximum amount in millisatoshis of the accepted HTLCs of a single invoice. Set to 0 to disable."`
+
+	MaxCreatorHtlcSets uint32 `long:"max-creator-htlc-sets" description:"The maximum number of HTLC sets with accepted HTLCs of all invoices that were created with macaroons of the same root key ID. Set to 0 to disable."`
+
+	MaxCreatorPendingMsat uint64 `long:"max-creator-pending-msat" description:"The maximum amount in millisatoshis of the accepted HTLCs of all invoices that were created with macaroons of the same root key ID. Set to 0 to disable."`
+}
+
+// ReceiveQuota returns the receive quota of the invoice registry.
+func (i *Invoices) ReceiveQuota() invoices.ReceiveQuota {
+	return invoices.ReceiveQuota{
+		MaxInvoiceSets:    int(i.MaxInvoiceHtlcSets),
+		MaxInvoicePending: lnwire.MilliSatoshi(i.MaxInvoicePendingMsat),
+		MaxCreatorSets:    int(i.MaxCreatorHtlcSets),
+		MaxCreatorPending: lnwire.MilliSatoshi(i.MaxCreatorPendingMsat),
+	}
 }
@@ -0,0 +1,92 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultJamDetectMinHoldTime is the default time a forward must be
+	// held before it counts towards a jamming incident.
+	DefaultJamDetectMinHoldTime = 10 * time.Minute
+
+	// DefaultJamDetectSlotThreshold is the default number of small htlcs
+	// held from one incoming channel that is reported as slot jamming.
+	DefaultJamDetectSlotThreshold = 50
+
+	// DefaultJamDetectSmallAmount is the default amount in millisatoshis
+	// up to which an htlc counts as small.
+	DefaultJamDetectSmallAmount = 10_000
+
+	// DefaultJamDetectMitigationTime is the default time forwards from a
+	// jamming channel stay refused after the incident was last detected.
+	DefaultJamDetectMitigationTime = time.Hour
+
+	// DefaultJamDetectInterval is the default interval at which the held
+	// htlcs are checked for jamming patterns.
+	DefaultJamDetectInterval = time.Minute
+)
+
+// JamDetect holds the configuration of the channel jamming detector.
+//
+//nolint:lll
+type JamDetect struct {
+	Active bool `long:"active" description:"Detect slot and liquidity jamming patterns in the forwarded htlcs held by the node. Suspected incidents are returned by the ListJammingIncidents RPC."`
+
+	Mitigate bool `long:"mitigate" description:"Refuse forwards from the incoming channel of a suspected jamming incident while it is detected and for the mitigation time afterwards."`
+
+	MinHoldTime time.Duration `long:"min-hold-time" description:"The time a forward must be held before it counts towards a jamming incident. Valid time units are {s, m, h}."`
+
+	SlotThreshold int `long:"slot-threshold" description:"The number of small htlcs held from one incoming channel that is reported as slot jamming. Set to 0 to disable the detection."`
+
+	SmallAmount uint64 `long:"small-amount-msat" description:"The amount in millisatoshis up to which an htlc counts as small for the slot jamming detection."`
+
+	LiquidityThreshold uint64 `long:"liquidity-threshold-msat" description:"The amount in millisatoshis held from one incoming channel that is reported as liquidity jamming. Set to 0 to disable the detection."`
+
+	MitigationTime time.Duration `long:"mitigation-time" description:"The time forwards from the incoming channel of an incident stay refused after it was last detected. Valid time units are {s, m, h}."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which the held htlcs are checked for jamming patterns. Valid time units are {s, m, h}."`
+}
+
+// DefaultJamDetect returns the default configuration of the jamming detector.
+func DefaultJamDetect() *JamDetect {
+	return &JamDetect{
+		MinHoldTime:    DefaultJamDetectMinHoldTime,
+		SlotThreshold:  DefaultJamDetectSlotThreshold,
+		SmallAmount:    DefaultJamDetectSmallAmount,
+		MitigationTime: DefaultJamDetectMitigationTime,
+		Interval:       DefaultJamDetectInterval,
+	}
+}
+
+// Validate checks the values configured for the jamming detector.
+func (j *JamDetect) Validate() error {
+	if !j.Active {
+		return nil
+	}
+
+	if j.MinHoldTime < 0 {
+		return fmt.Errorf("jamdetect.min-hold-time must not be " +
+			"negative")
+	}
+
+	if j.SlotThreshold < 0 {
+		return fmt.Errorf("jamdetect.slot-threshold must not be " +
+			"negative")
+	}
+
+	if j.SlotThreshold == 0 && j.LiquidityThreshold == 0 {
+		return fmt.Errorf("jamdetect.slot-threshold or " +
+			"jamdetect.liquidity-threshold-msat must be positive")
+	}
+
+	if j.Mitigate && j.MitigationTime <= 0 {
+		return fmt.Errorf("jamdetect.mitigation-time must be positive")
+	}
+
+	if j.Interval <= 0 {
+		return fmt.Errorf("jamdetect.interval must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultJITChannelMinChanSize is the default minimum capacity of
+	// just-in-time channels in satoshis.
+	DefaultJITChannelMinChanSize = 100_000
+
+	// DefaultJITChannelMaxChanSize is the default maximum capacity of
+	// just-in-time channels in satoshis, which is the largest non-wumbo
+	// channel.
+	DefaultJITChannelMaxChanSize = 16_777_215
+
+	// DefaultJITChannelBaseFee is the default base fee of the forwarding
+	// policy of just-in-time channels in millisatoshis.
+	DefaultJITChannelBaseFee = 1000
+
+	// DefaultJITChannelFeeRate is the default proportional fee of the
+	// forwarding policy of just-in-time channels in parts per million.
+	DefaultJITChannelFeeRate = 1000
+
+	// DefaultJITChannelRegistrationTTL is the default time a just-in-time
+	// channel waits for its first forward.
+	DefaultJITChannelRegistrationTTL = 24 * time.Hour
+
+	// DefaultJITChannelOpenTimeout is the default time the forwards to a
+	// just-in-time channel are held while the channel is opened.
+	DefaultJITChannelOpenTimeout = time.Minute
+)
+
+// JITChannel holds the configuration of just-in-time channels.
+//
+//nolint:lll
+type JITChannel struct {
+	Active bool `long:"active" description:"Open zero-conf channels to registered clients once htlcs are forwarded to the short channel ids assigned to them, and release the held htlcs into the new channels. Requires protocol.zero-conf and protocol.option-scid-alias."`
+
+	MinChanSize int64 `long:"min-chan-size" description:"The minimum capacity of just-in-time channels in satoshis."`
+
+	MaxChanSize int64 `long:"max-chan-size" description:"The maximum capacity of just-in-time channels in satoshis. Forwards that don't fit into a channel of this capacity are failed."`
+
+	BaseFee uint64 `long:"base-fee" description:"The base fee in millisatoshis of the forwarding policy of just-in-time channels, which the forwards that trigger the open must pay."`
+
+	FeeRate uint64 `long:"fee-rate" description:"The proportional fee in parts per million of the forwarding policy of just-in-time channels, which the forwards that trigger the open must pay."`
+
+	RegistrationTTL time.Duration `long:"registration-ttl" description:"The time a short channel id assigned to a client waits for its first forward. Valid time units are {s, m, h}."`
+
+	OpenTimeout time.Duration `long:"open-timeout" description:"The time forwards are held while waiting for the client to come online and for its channel to be opened, before they are failed. Valid time units are {s, m, h}."`
+}
+
+// DefaultJITChannel returns the default configuration of just-in-time
+// channels.
+func DefaultJITChannel() *JITChannel {
+	return &JITChannel{
+		MinChanSize:     DefaultJITChannelMinChanSize,
+		MaxChanSize:     DefaultJITChannelMaxChanSize,
+		BaseFee:         DefaultJITChannelBaseFee,
+		FeeRate:         DefaultJITChannelFeeRate,
+		RegistrationTTL: DefaultJITChannelRegistrationTTL,
+		OpenTimeout:     DefaultJITChannelOpenTimeout,
+	}
+}
+
+// Validate checks the values configured for just-in-time channels.
+func (j *JITChannel) Validate() error {
+	if !j.Active {
+		return nil
+	}
+
+	if j.MinChanSize <= 0 {
+		return fmt.Errorf("jitchannel.min-chan-size must be positive")
+	}
+
+	if j.MaxChanSize < j.MinChanSize {
+		return fmt.Errorf("jitchannel.max-chan-size must not be " +
+			"smaller than jitchannel.min-chan-size")
+	}
+
+	if j.RegistrationTTL <= 0 {
+		return fmt.Errorf("jitchannel.registration-ttl must be " +
+			"positive")
+	}
+
+	if j.OpenTimeout <= 0 {
+		return fmt.Errorf("jitchannel.open-timeout must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultLiquidityAlertInterval is the default interval at which the
+	// liquidity is checked.
+	DefaultLiquidityAlertInterval = 10 * time.Minute
+
+	// DefaultLiquidityAlertOneSidedRatio is the default fraction of its
+	// capacity above which a balance makes a channel one-sided.
+	DefaultLiquidityAlertOneSidedRatio = 0.95
+
+	// DefaultLiquidityAlertOneSidedDuration is the default time a channel
+	// must stay one-sided before an alert is raised.
+	DefaultLiquidityAlertOneSidedDuration = 72 * time.Hour
+
+	// DefaultLiquidityAlertWebhookTimeout is the default time the alert
+	// webhook may take to respond.
+	DefaultLiquidityAlertWebhookTimeout = 10 * time.Second
+)
+
+// LiquidityAlert holds the configuration of the liquidity monitor.
+//
+//nolint:lll
+type LiquidityAlert struct {
+	Active bool `long:"active" description:"Continuously check the liquidity of the channels, the peers and the node as a whole, and alert when it drops below the configured thresholds."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which the liquidity is checked. Valid time units are {s, m, h}."`
+
+	MinChanOutboundRatio float64 `long:"min-chan-outbound-ratio" description:"Alert when the local balance of a channel drops below this fraction of its capacity. Set to 0 to disable."`
+
+	MinChanInboundRatio float64 `long:"min-chan-inbound-ratio" description:"Alert when the remote balance of a channel drops below this fraction of its capacity. Set to 0 to disable."`
+
+	MinPeerOutbound int64 `long:"min-peer-outbound" description:"Alert when the total local balance in satoshis of the channels with a peer drops below this amount. Set to 0 to disable."`
+
+	MinPeerInbound int64 `long:"min-peer-inbound" description:"Alert when the total remote balance in satoshis of the channels with a peer drops below this amount. Set to 0 to disable."`
+
+	MinNodeOutbound int64 `long:"min-node-outbound" description:"Alert when the total local balance in satoshis of all channels drops below this amount. Set to 0 to disable."`
+
+	MinNodeInbound int64 `long:"min-node-inbound" description:"Alert when the total remote balance in satoshis of all channels drops below this amount. Set to 0 to disable."`
+
+	OneSidedRatio float64 `long:"onesided-ratio" description:"The fraction of its capacity above which the local or remote balance makes a channel one-sided. Set to 0 to disable one-sided alerts."`
+
+	OneSidedDuration time.Duration `long:"onesided-duration" description:"The time a channel must stay one-sided before an alert is raised. Valid time units are {s, m, h}."`
+
+	WebhookURL string `long:"webhook" description:"The http(s) URL alerts are posted to when they are raised and resolved."`
+
+	WebhookTimeout time.Duration `long:"webhook-timeout" description:"The time the alert webhook may take to respond. Valid time units are {s, m, h}."`
+}
+
+// DefaultLiquidityAlert returns the default configuration of the liquidity
+// monitor.
+func DefaultLiquidityAlert() *LiquidityAlert {
+	return &LiquidityAlert{
+		Interval:         DefaultLiquidityAlertInterval,
+		OneSidedRatio:    DefaultLiquidityAlertOneSidedRatio,
+		OneSidedDuration: DefaultLiquidityAlertOneSidedDuration,
+		WebhookTimeout:   DefaultLiquidityAlertWebhookTimeout,
+	}
+}
+
+// Validate checks the values configured for the liquidity monitor.
+func (l *LiquidityAlert) Validate() error {
+	if !l.Active {
+		return nil
+	}
+
+	if l.Interval <= 0 {
+		return fmt.Errorf("liquidityalert.interval must be positive")
+	}
+
+	ratios := map[string]float64{
+		"min-chan-outbound-ratio": l.MinChanOutboundRatio,
+		"min-chan-inbound-ratio":  l.MinChanInboundRatio,
+		"onesided-ratio":          l.OneSidedRatio,
+	}
+	for name, ratio := range ratios {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("liquidityalert.%v must be between "+
+				"0 and 1", name)
+		}
+	}
+
+	amounts := map[string]int64{
+		"min-peer-outbound": l.MinPeerOutbound,
+		"min-peer-inbound":  l.MinPeerInbound,
+		"min-node-outbound": l.MinNodeOutbound,
+		"min-node-inbound":  l.MinNodeInbound,
+	}
+	for name, amount := range amounts {
+		if amount < 0 {
+			return fmt.Errorf("liquidityalert.%v must not be "+
+				"negative", name)
+		}
+	}
+
+	if l.OneSidedDuration < 0 {
+		return fmt.Errorf("liquidityalert.onesided-duration must not " +
+			"be negative")
+	}
+
+	if l.WebhookURL != "" {
+		u, err := url.Parse(l.WebhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid liquidityalert.webhook: %w",
+				err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			return fmt.Errorf("liquidityalert.webhook must be an " +
+				"http or https URL")
+		}
+
+		if l.WebhookTimeout <= 0 {
+			return fmt.Errorf("liquidityalert.webhook-timeout " +
+				"must be positive")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultLNURLCallbackRate is the default number of LNURL-pay
+	// callbacks served per second.
+	DefaultLNURLCallbackRate = 1
+
+	// DefaultLNURLCallbackBurst is the default number of LNURL-pay
+	// callbacks that can be served at once.
+	DefaultLNURLCallbackBurst = 10
+)
+
+// lnurlUsernameRegex matches the usernames allowed in Lightning Addresses by
+// LUD-16.
+var lnurlUsernameRegex = regexp.MustCompile(`^[a-z0-9\-_.]+$`)
+
+// LNURL holds the configuration of the LNURL-pay and Lightning Address server.
+//
+//nolint:lll
+type LNURL struct {
+	Active bool `long:"active" description:"Serve LNURL-pay requests and Lightning Address callbacks on the REST listeners."`
+
+	Domain string `long:"domain" description:"The domain name the REST listeners are publicly reachable at. It is the domain of the Lightning Addresses and callback URLs."`
+
+	Users []string `long:"user" description:"A user that can be paid at <name>@<domain>, in the form <name>:<min_sat>:<max_sat>[:<description>]. Can be specified multiple times."`
+
+	Zaps bool `long:"zaps" description:"Support Nostr zaps. Zap requests are accepted in LNURL-pay callbacks and a zap receipt is published to Nostr relays once the invoice is settled."`
+
+	NostrRelays []string `long:"nostr-relay" description:"The websocket URL of a Nostr relay every zap receipt is published to, next to the relays listed in its zap request. Can be specified multiple times."`
+
+	CallbackRate float64 `long:"callback-rate" description:"The number of LNURL-pay callbacks served per second across all users. Every callback adds an invoice, so callbacks beyond the limit are rejected."`
+
+	CallbackBurst int `long:"callback-burst" description:"The number of LNURL-pay callbacks that can be served at once before callback-rate applies."`
+}
+
+// DefaultLNURL returns the default configuration of the LNURL server.
+func DefaultLNURL() *LNURL {
+	return &LNURL{
+		CallbackRate:  DefaultLNURLCallbackRate,
+		CallbackBurst: DefaultLNURLCallbackBurst,
+	}
+}
+
+// LNURLUser is a user of the LNURL server as parsed from the config.
+type LNURLUser struct {
+	// Name is the username of the user.
+	Name string
+
+	// MinSat is the smallest amount in satoshis the user can be paid.
+	MinSat uint64
+
+	// MaxSat is the largest amount in satoshis the user can be paid.
+	MaxSat uint64
+
+	// Description is the description shown to the payer.
+	Description string
+}
+
+// ParseUsers parses the configured users.
+func (l *LNURL) ParseUsers() ([]*LNURLUser, error) {
+	users := make([]*LNURLUser, 0, len(l.Users))
+	names := make(map[string]struct{}, len(l.Users))
+	for _, userStr := range l.Users {
+		parts := strings.SplitN(userStr, ":", 4)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("invalid lnurl.user %q, expected "+
+				"<name>:<min_sat>:<max_sat>[:<description>]",
+				userStr)
+		}
+
+		user := &LNURLUser{
+			Name: strings.ToLower(parts[0]),
+		}
+		if !lnurlUsernameRegex.MatchString(user.Name) {
+			return nil, fmt.Errorf("invalid lnurl.user name %q",
+				parts[0])
+		}
+		if _, ok := names[user.Name]; ok {
+			return nil, fmt.Errorf("duplicate lnurl.user name %q",
+				user.Name)
+		}
+		names[user.Name] = struct{}{}
+
+		var err error
+		user.MinSat, err = strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lnurl.user minimum "+
+				"%q: %w", parts[1], err)
+		}
+		user.MaxSat, err = strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lnurl.user maximum "+
+				"%q: %w", parts[2], err)
+		}
+		if user.MinSat == 0 || user.MaxSat < user.MinSat {
+			return nil, fmt.Errorf("invalid lnurl.user %q, the "+
+				"minimum must be positive and not above the "+
+				"maximum", user.Name)
+		}
+
+		user.Description = "Payment to " + user.Name
+		if len(parts) == 4 && parts[3] != "" {
+			user.Description = parts[3]
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// Validate checks the values configured for the LNURL server.
+func (l *LNURL) Validate() error {
+	if !l.Active {
+		return nil
+	}
+
+	if l.Domain == "" || strings.ContainsAny(l.Domain, "/:@") {
+		return fmt.Errorf("lnurl.domain must be a domain name")
+	}
+
+	users, err := l.ParseUsers()
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("lnurl.active requires at least one " +
+			"lnurl.user")
+	}
+
+	if l.CallbackRate <= 0 || l.CallbackBurst <= 0 {
+		return fmt.Errorf("lnurl.callback-rate and " +
+			"lnurl.callback-burst must be positive")
+	}
+
+	for _, relay := range l.NostrRelays {
+		u, err := url.Parse(relay)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") ||
+			u.Host == "" {
+
+			return fmt.Errorf("invalid lnurl.nostr-relay %q, "+
+				"expected a ws or wss URL", relay)
+		}
+	}
+
+	return nil
+}
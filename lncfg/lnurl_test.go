@@ -0,0 +1,72 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestLNURLParseUsers tests parsing the users of the LNURL server.
+func TestLNURLParseUsers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		users []string
+		exp   []*LNURLUser
+		valid bool
+	}{{
+		name:  "description with colons",
+		users: []string{"Alice:1:1000:Tips: thanks!"},
+		exp: []*LNURLUser{{
+			Name:        "alice",
+			MinSat:      1,
+			MaxSat:      1000,
+			Description: "Tips: thanks!",
+		}},
+		valid: true,
+	}, {
+		name:  "default description",
+		users: []string{"bob:10:10"},
+		exp: []*LNURLUser{{
+			Name:        "bob",
+			MinSat:      10,
+			MaxSat:      10,
+			Description: "Payment to bob",
+		}},
+		valid: true,
+	}, {
+		name:  "missing maximum",
+		users: []string{"alice:1"},
+	}, {
+		name:  "invalid name",
+		users: []string{"al ice:1:2"},
+	}, {
+		name:  "duplicate name",
+		users: []string{"alice:1:2", "ALICE:1:2"},
+	}, {
+		name:  "zero minimum",
+		users: []string{"alice:0:2"},
+	}, {
+		name:  "minimum above maximum",
+		users: []string{"alice:3:2"},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &LNURL{Users: tc.users}
+			users, err := cfg.ParseUsers()
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, users)
+		})
+	}
+}
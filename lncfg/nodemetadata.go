@@ -0,0 +1,36 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// NodeMetadata holds the operator information that is attached to the node
+// announcement.
+//
+//nolint:lll
+type NodeMetadata struct {
+	Contact string `long:"contact" description:"The contact of the node operator announced with the node, for example an email address or a Nostr public key."`
+
+	OrgName string `long:"org" description:"The name of the organization running the node, announced with the node."`
+
+	URL string `long:"url" description:"The canonical http(s) URL of the node or its operator, announced with the node."`
+}
+
+// Validate checks the values configured for the node metadata.
+func (n *NodeMetadata) Validate() error {
+	if n.URL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(n.URL)
+	if err != nil {
+		return fmt.Errorf("invalid nodemetadata.url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("nodemetadata.url must be an http or " +
+			"https URL")
+	}
+
+	return nil
+}
@@ -33,6 +33,34 @@ type ProtocolOptions struct {
 	// experimental simple taproot chans commitment type.
 	TaprootChans bool `long:"simple-taproot-chans" description:"if set, then lnd will create and accept requests for channels using the simple taproot commitment type"`
 
+	// TaprootGossip should be set if we want to signal support for the
+	// experimental pure TLV gossip messages used to announce taproot
+	// channels.
+	TaprootGossip bool `long:"taproot-gossip" description:"if set, then lnd will signal support for the experimental gossip messages used to announce taproot channels"`
+
+	// PeerStorage should be set if we want to offer to store encrypted
+	// backup blobs on behalf of our channel peers, and ask them to store
+	// our own backup blob in return.
+	PeerStorage bool `long:"peer-storage" description:"if set, then lnd will store small encrypted backup blobs for channel peers that request it and will push its own channel backup to peers that offer storage"`
+
+	// OnionMessages should be set if we want to forward onion messages
+	// for our peers and receive the ones that are sent to us.
+	OnionMessages bool `long:"onion-messages" description:"if set, then lnd will signal support for onion messages, forward them for its peers and handle the ones it receives"`
+
+	// AsyncPayments should be set if we want to take part in the async
+	// payments protocol, which requires onion messages.
+	AsyncPayments bool `long:"async-payments" description:"if set, then lnd will hold onion messages for offline channel peers, release payments held for it and allow holding payments until their recipient comes online; requires protocol.onion-messages"`
+
+	// TrampolineRouting should be set if we want to relay trampoline
+	// payments and receive payments that are sent through trampoline
+	// nodes.
+	TrampolineRouting bool `long:"trampoline-routing" description:"if set, then lnd will signal support for trampoline routing, relay trampoline payments for its peers according to the trampoline fee policy and accept payments that reach it through trampoline nodes"`
+
+	// RbfCoopClose should be set if we want to signal support for the RBF
+	// cooperative close protocol, which lets the party that initiated a
+	// cooperative close bump the fee of the closing transaction.
+	RbfCoopClose bool `long:"rbf-coop-close" description:"if set, then lnd will signal support for the RBF cooperative close protocol and use it to close channels with peers that support it, which allows the closing transaction to be fee bumped"`
+
 	// Anchors enables anchor commitments.
 	// TODO(halseth): transition itests to anchors instead!
 	Anchors bool `long:"anchors" description:"enable support for anchor commitments"`
@@ -52,6 +80,11 @@ type ProtocolOptions struct {
 	// feature bit.
 	OptionZeroConf bool `long:"zero-conf" description:"enable support for zero-conf channels, must have option-scid-alias set also"`
 
+	// ZeroConfMempoolActivation should be set if zero-conf channels we
+	// fund should only become active once their funding transaction was
+	// accepted to the mempool.
+	ZeroConfMempoolActivation bool `long:"zero-conf-mempool-activation" description:"only activate zero-conf channels we fund once their funding transaction was accepted to the mempool, and stop sending HTLCs over them if a conflicting transaction is detected"`
+
 	// NoOptionAnySegwit should be set to true if we don't want to use any
 	// Taproot (and beyond) addresses for co-op closing.
 	NoOptionAnySegwit bool `long:"no-any-segwit" description:"disallow using any segiwt witness version as a co-op close address"`
@@ -112,6 +145,12 @@ func (l *ProtocolOptions) ZeroConf() bool {
 	return l.OptionZeroConf
 }
 
+// ZeroConfMempool returns true if zero-conf channels we fund should only
+// become active once their funding transaction was accepted to the mempool.
+func (l *ProtocolOptions) ZeroConfMempool() bool {
+	return l.ZeroConfMempoolActivation
+}
+
 // NoAnySegwit returns true if we don't signal that we understand other newer
 // segwit witness versions for co-op close addresses.
 func (l *ProtocolOptions) NoAnySegwit() bool {
@@ -0,0 +1,10 @@
+package lncfg
+
+// SafeMode holds the configuration of the safe mode of the node, in which it
+// stays online after critical failures but doesn't accept new HTLCs and
+// channel operations.
+//
+//nolint:lll
+type SafeMode struct {
+	ChainBackend bool `long:"chainbackend" description:"If the chain backend health check fails, enter safe mode until it passes again instead of shutting lnd down."`
+}
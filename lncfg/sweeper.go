@@ -26,6 +26,10 @@ type Sweeper struct {
 
 	NoDeadlineConfTarget uint32 `long:"nodeadlineconftarget" description:"The conf target to use when sweeping non-time-sensitive outputs. This is useful for sweeping outputs that are not time-sensitive, and can be swept at a lower fee rate."`
 
+	MempoolAware bool `long:"mempoolaware" description:"If set, the fee rate of a time-sensitive sweeping transaction is only increased when it's at risk of missing its deadline given the fee rates in the mempool of the chain backend, rather than on every block. This reduces overpaying during temporary fee spikes. Only supported by the bitcoind and btcd backends."`
+
+	DeadlineBatchDelta uint32 `long:"deadlinebatchdelta" description:"The max number of blocks by which the deadlines of inputs from different closed channels may differ to still be swept in a single transaction, which then uses the earliest of their deadlines. Inputs that the remote party can spend as well are never batched with inputs of a later deadline. Set to 0 to only batch inputs sharing the same deadline."`
+
 	Budget *contractcourt.BudgetConfig `group:"sweeper.budget" namespace:"budget" long:"budget" description:"An optional config group that's used for the automatic sweep fee estimation. The Budget config gives options to limits ones fee exposure when sweeping unilateral close outputs and the fee rate calculated from budgets is capped at sweeper.maxfeerate. Check the budget config options for more details."`
 }
 
@@ -50,6 +54,13 @@ func (s *Sweeper) Validate() error {
 		return fmt.Errorf("nodeadlineconftarget must be at least 144")
 	}
 
+	// Batching inputs whose deadlines are further apart than the conf
+	// target of non-time-sensitive outputs would rush all of them.
+	if s.DeadlineBatchDelta >= s.NoDeadlineConfTarget {
+		return fmt.Errorf("deadlinebatchdelta must be less than " +
+			"nodeadlineconftarget")
+	}
+
 	// Validate the budget configuration.
 	if err := s.Budget.Validate(); err != nil {
 		return fmt.Errorf("invalid budget config: %w", err)
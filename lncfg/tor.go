@@ -1,5 +1,26 @@
 package lncfg
 
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/tor"
+)
+
+const (
+	// TorIsolationNone doesn't isolate the connections made through Tor
+	// from each other.
+	TorIsolationNone = "none"
+
+	// TorIsolationPeer isolates the connections to different peers from
+	// each other.
+	TorIsolationPeer = "peer"
+
+	// TorIsolationSubsystem isolates the connections of different
+	// subsystems, like peers, watchtowers and the fee estimator, from each
+	// other.
+	TorIsolationSubsystem = "subsystem"
+)
+
 // Tor holds the configuration options for the daemon's connection to tor.
 //
 //nolint:lll
@@ -8,8 +29,9 @@ type Tor struct {
 	SOCKS                       string `long:"socks" description:"The host:port that Tor's exposed SOCKS5 proxy is listening on"`
 	DNS                         string `long:"dns" description:"The DNS server as host:port that Tor will use for SRV queries - NOTE must have TCP resolution enabled"`
 	StreamIsolation             bool   `long:"streamisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection."`
+	IsolationPolicy             string `long:"isolationpolicy" description:"The policy determining which connections use distinct Tor circuits, by using distinct user credentials. 'peer' uses random credentials per peer address, 'subsystem' uses distinct credentials for peers, watchtowers and the fee estimator. Can't be combined with streamisolation." choice:"none" choice:"peer" choice:"subsystem"`
 	SkipProxyForClearNetTargets bool   `long:"skip-proxy-for-clearnet-targets" description:"Allow the node to establish direct connections to services not running behind Tor."`
-	Control                     string `long:"control" description:"The host:port that Tor is listening on for Tor control connections"`
+	Control                     string `long:"control" description:"The host:port, or unix:// followed by the path of a UNIX domain socket, that Tor is listening on for Tor control connections"`
 	TargetIPAddress             string `long:"targetipaddress" description:"IP address that Tor should use as the target of the hidden service"`
 	Password                    string `long:"password" description:"The password used to arrive at the HashedControlPassword for the control port. If provided, the HASHEDPASSWORD authentication method will be used instead of the SAFECOOKIE one."`
 	V2                          bool   `long:"v2" description:"Automatically set up a v2 onion service to listen for inbound connections"`
@@ -18,3 +40,22 @@ type Tor struct {
 	EncryptKey                  bool   `long:"encryptkey" description:"Encrypts the Tor private key file on disk"`
 	WatchtowerKeyPath           string `long:"watchtowerkeypath" description:"The path to the private key of the watchtower onion service being created"`
 }
+
+// ProxyIsolationPolicy returns the isolation policy of the Tor proxy dialer
+// for the configured isolation policy.
+func (t *Tor) ProxyIsolationPolicy() (tor.IsolationPolicy, error) {
+	switch t.IsolationPolicy {
+	case TorIsolationNone, "":
+		return tor.IsolationNone, nil
+
+	case TorIsolationPeer:
+		return tor.IsolationPerDestination, nil
+
+	case TorIsolationSubsystem:
+		return tor.IsolationPerClass, nil
+
+	default:
+		return 0, fmt.Errorf("unknown tor isolation policy: %v",
+			t.IsolationPolicy)
+	}
+}
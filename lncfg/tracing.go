@@ -0,0 +1,51 @@
+package lncfg
+
+import "fmt"
+
+const (
+	// DefaultTracingEndpoint is the default address of the OTLP collector
+	// that traces are exported to.
+	DefaultTracingEndpoint = "127.0.0.1:4317"
+
+	// DefaultTracingSampleRatio is the default fraction of traces that
+	// are sampled.
+	DefaultTracingSampleRatio = 1.0
+)
+
+// Tracing holds the configuration of the OpenTelemetry trace exporter.
+//
+//nolint:lll
+type Tracing struct {
+	Enable bool `long:"enable" description:"Export OpenTelemetry traces of RPC calls and the payment pipeline to an OTLP collector."`
+
+	Endpoint string `long:"endpoint" description:"The host:port of the OTLP gRPC collector that traces are exported to."`
+
+	Insecure bool `long:"insecure" description:"Connect to the OTLP collector without TLS."`
+
+	SampleRatio float64 `long:"sampleratio" description:"The fraction of traces to sample, between 0 and 1."`
+}
+
+// DefaultTracing returns the default configuration of the trace exporter.
+func DefaultTracing() *Tracing {
+	return &Tracing{
+		Endpoint:    DefaultTracingEndpoint,
+		SampleRatio: DefaultTracingSampleRatio,
+	}
+}
+
+// Validate checks the values configured for the trace exporter.
+func (t *Tracing) Validate() error {
+	if !t.Enable {
+		return nil
+	}
+
+	if t.Endpoint == "" {
+		return fmt.Errorf("tracing.endpoint must be set")
+	}
+
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sampleratio must be between 0 and 1")
+	}
+
+	return nil
+}
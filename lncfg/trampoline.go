@@ -0,0 +1,55 @@
+package lncfg
+
+import "fmt"
+
+const (
+	// DefaultTrampolineBaseFee is the default base fee in millisatoshis
+	// that we charge for relaying trampoline payments.
+	DefaultTrampolineBaseFee = 1000
+
+	// DefaultTrampolineFeeRate is the default proportional fee in parts
+	// per million that we charge for relaying trampoline payments.
+	DefaultTrampolineFeeRate = 1000
+
+	// DefaultTrampolineCltvDelta is the default CLTV delta that we require
+	// for relaying trampoline payments. It is larger than a regular
+	// channel CLTV delta, as the payment may take multiple hops to the
+	// next trampoline node.
+	DefaultTrampolineCltvDelta = 576
+
+	// minTrampolineCltvDelta is the smallest CLTV delta that we accept for
+	// relaying trampoline payments, which matches the minimum CLTV delta
+	// of our channels.
+	minTrampolineCltvDelta = 18
+)
+
+// Trampoline holds the fee policy that we apply to relayed trampoline
+// payments.
+//
+//nolint:lll
+type Trampoline struct {
+	BaseFee uint64 `long:"base-fee" description:"The base fee in millisatoshis charged for relaying a trampoline payment. Senders must add the routing fees to the next trampoline node on top of the trampoline fee. Only used with protocol.trampoline-routing."`
+
+	FeeRate uint64 `long:"fee-rate" description:"The proportional fee in parts per million charged for relaying a trampoline payment. Only used with protocol.trampoline-routing."`
+
+	CltvDelta uint32 `long:"cltv-delta" description:"The minimum difference between the incoming and the outgoing CLTV of a relayed trampoline payment, which must cover the CLTV deltas of the route to the next trampoline node. Only used with protocol.trampoline-routing."`
+}
+
+// DefaultTrampoline returns the default trampoline fee policy.
+func DefaultTrampoline() *Trampoline {
+	return &Trampoline{
+		BaseFee:   DefaultTrampolineBaseFee,
+		FeeRate:   DefaultTrampolineFeeRate,
+		CltvDelta: DefaultTrampolineCltvDelta,
+	}
+}
+
+// Validate checks the values configured for the trampoline fee policy.
+func (t *Trampoline) Validate() error {
+	if t.CltvDelta < minTrampolineCltvDelta {
+		return fmt.Errorf("trampoline.cltv-delta must be at least %v",
+			minTrampolineCltvDelta)
+	}
+
+	return nil
+}
@@ -14,6 +14,10 @@ const (
 	// DefaultSigWorkers is the default maximum number of concurrent workers
 	// used by the daemon's sig pool.
 	DefaultSigWorkers = 8
+
+	// DefaultGossipSigWorkers is the default maximum number of concurrent
+	// workers used to verify the signatures of gossip messages.
+	DefaultGossipSigWorkers = 8
 )
 
 // Workers exposes CLI configuration for turning resources consumed by worker
@@ -29,6 +33,10 @@ type Workers struct {
 
 	// Sig is the maximum number of concurrent sig pool workers.
 	Sig int `long:"sig" description:"Maximum number of concurrent sig pool workers. This number should be proportional to the number of CPUs on the host."`
+
+	// GossipSig is the maximum number of concurrent gossip signature
+	// verification workers.
+	GossipSig int `long:"gossip-sig" description:"Maximum number of concurrent workers verifying the signatures of gossip messages. This number should be proportional to the number of CPUs on the host."`
 }
 
 // Validate checks the Workers configuration to ensure that the input values are
@@ -46,6 +54,10 @@ func (w *Workers) Validate() error {
 		return fmt.Errorf("number of sig workers (%d) must be "+
 			"positive", w.Sig)
 	}
+	if w.GossipSig <= 0 {
+		return fmt.Errorf("number of gossip sig workers (%d) must be "+
+			"positive", w.GossipSig)
+	}
 
 	return nil
 }
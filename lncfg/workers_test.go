@@ -23,67 +23,93 @@ func TestValidateWorkers(t *testing.T) {
 		{
 			name: "min valid",
 			cfg: &lncfg.Workers{
-				Read:  1,
-				Write: 1,
-				Sig:   1,
+				Read:      1,
+				Write:     1,
+				Sig:       1,
+				GossipSig: 1,
 			},
 			valid: true,
 		},
 		{
 			name: "max valid",
 			cfg: &lncfg.Workers{
-				Read:  maxInt,
-				Write: maxInt,
-				Sig:   maxInt,
+				Read:      maxInt,
+				Write:     maxInt,
+				Sig:       maxInt,
+				GossipSig: maxInt,
 			},
 			valid: true,
 		},
 		{
 			name: "read max invalid",
 			cfg: &lncfg.Workers{
-				Read:  0,
-				Write: 1,
-				Sig:   1,
+				Read:      0,
+				Write:     1,
+				Sig:       1,
+				GossipSig: 1,
 			},
 		},
 		{
 			name: "write max invalid",
 			cfg: &lncfg.Workers{
-				Read:  1,
-				Write: 0,
-				Sig:   1,
+				Read:      1,
+				Write:     0,
+				Sig:       1,
+				GossipSig: 1,
 			},
 		},
 		{
 			name: "sig max invalid",
 			cfg: &lncfg.Workers{
-				Read:  1,
-				Write: 1,
-				Sig:   0,
+				Read:      1,
+				Write:     1,
+				Sig:       0,
+				GossipSig: 1,
 			},
 		},
 		{
 			name: "read min invalid",
 			cfg: &lncfg.Workers{
-				Read:  minInt,
-				Write: 1,
-				Sig:   1,
+				Read:      minInt,
+				Write:     1,
+				Sig:       1,
+				GossipSig: 1,
 			},
 		},
 		{
 			name: "write min invalid",
 			cfg: &lncfg.Workers{
-				Read:  1,
-				Write: minInt,
-				Sig:   1,
+				Read:      1,
+				Write:     minInt,
+				Sig:       1,
+				GossipSig: 1,
 			},
 		},
 		{
 			name: "sig min invalid",
 			cfg: &lncfg.Workers{
-				Read:  1,
-				Write: 1,
-				Sig:   minInt,
+				Read:      1,
+				Write:     1,
+				Sig:       minInt,
+				GossipSig: 1,
+			},
+		},
+		{
+			name: "gossip sig max invalid",
+			cfg: &lncfg.Workers{
+				Read:      1,
+				Write:     1,
+				Sig:       1,
+				GossipSig: 0,
+			},
+		},
+		{
+			name: "gossip sig min invalid",
+			cfg: &lncfg.Workers{
+				Read:      1,
+				Write:     1,
+				Sig:       1,
+				GossipSig: minInt,
 			},
 		},
 	}
@@ -22,14 +22,17 @@ import (
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanacceptor"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/cluster"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/monitoring"
+	"github.com/lightningnetwork/lnd/monitoring/tracing"
 	"github.com/lightningnetwork/lnd/rpcperms"
 	"github.com/lightningnetwork/lnd/signal"
 	"github.com/lightningnetwork/lnd/tor"
@@ -248,6 +251,25 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		defer runtimePprof.StopCPUProfile()
 	}
 
+	// Install the trace exporter before any RPC call or payment can create
+	// spans, so that their traces are complete.
+	if cfg.Tracing.Enable {
+		shutdownTracing, err := tracing.Start(ctx, &tracing.Config{
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			return mkErr("unable to start tracing: %v", err)
+		}
+		defer func() {
+			err := shutdownTracing(context.Background())
+			if err != nil {
+				ltndLog.Errorf("Stop tracing got err: %v", err)
+			}
+		}()
+	}
+
 	// Run configuration dependent DB pre-initialization. Note that this
 	// needs to be done early and once during the startup process, before
 	// any DB access.
@@ -311,7 +333,8 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	// Create a new RPC interceptor that we'll add to the GRPC server. This
 	// will be used to log the API calls invoked on the GRPC server.
 	interceptorChain := rpcperms.NewInterceptorChain(
-		rpcsLog, cfg.NoMacaroons, cfg.RPCMiddleware.Mandatory,
+		rpcsLog, cfg.NoMacaroons, cfg.ReadOnly,
+		cfg.RPCMiddleware.Mandatory,
 	)
 	if err := interceptorChain.Start(); err != nil {
 		return mkErr("error starting interceptor chain: %v", err)
@@ -335,7 +358,13 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		PermitWithoutStream: cfg.GRPC.ClientAllowPingWithoutStream,
 	}
 
-	rpcServerOpts := interceptorChain.CreateServerOpts()
+	rpcServerOpts := interceptorChain.CreateServerOpts(
+		cfg.Tracing.Enable, &rpcperms.Limits{
+			MaxListResults:  cfg.GRPC.MaxListResults,
+			RejectUnbounded: cfg.GRPC.RejectUnboundedLists,
+			MaxResponseSize: cfg.GRPC.MaxResponseSize,
+		},
+	)
 	serverOpts = append(serverOpts, rpcServerOpts...)
 	serverOpts = append(
 		serverOpts, grpc.MaxRecvMsgSize(lnrpc.MaxGrpcMsgSize),
@@ -380,8 +409,14 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	// Start leader election if we're running on etcd. Continuation will be
 	// blocked until this instance is elected as the current leader or
 	// shutting down.
-	elected := false
-	if cfg.Cluster.EnableLeaderElection {
+	var (
+		elected       bool
+		leaderElector cluster.LeaderElector
+	)
+	// An instance in read-only mode never writes to the databases, so it
+	// doesn't need to be the leader and must not take that role away from
+	// the instance that does.
+	if cfg.Cluster.EnableLeaderElection && !cfg.ReadOnly {
 		electionCtx, cancelElection := context.WithCancel(ctx)
 
 		go func() {
@@ -392,7 +427,7 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		ltndLog.Infof("Using %v leader elector",
 			cfg.Cluster.LeaderElector)
 
-		leaderElector, err := cfg.Cluster.MakeLeaderElector(
+		leaderElector, err = cfg.Cluster.MakeLeaderElector(
 			electionCtx, cfg.DB,
 		)
 		if err != nil {
@@ -422,9 +457,28 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 
 		elected = true
 		ltndLog.Infof("Elected as leader (%v)", cfg.Cluster.ID)
+
+		// If our election session expires, another instance may be
+		// elected as the leader, so we need to shut down to avoid
+		// having two active instances.
+		go func() {
+			select {
+			case <-leaderElector.Done():
+				ltndLog.Errorf("Leader election session "+
+					"expired, shutting down (%v)",
+					cfg.Cluster.ID)
+				interceptor.RequestShutdown()
+
+			case <-interceptor.ShutdownChannel():
+			}
+		}()
 	}
 
+	// We record the timings of the stages of the startup, so they can be
+	// queried through the State service while lnd is starting.
+	finishStage := interceptorChain.StartStage("open databases")
 	dbs, cleanUp, err := implCfg.DatabaseBuilder.BuildDatabase(ctx)
+	finishStage()
 	switch {
 	case err == channeldb.ErrDryRunMigrationOK:
 		ltndLog.Infof("%v, exiting", err)
@@ -435,24 +489,57 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 
 	defer cleanUp()
 
+	// Now that we are the leader, we fence the databases with the token
+	// of our term. This makes sure that a previous leader that didn't
+	// notice yet that it was replaced can't write to them anymore.
+	if elected {
+		if err := fenceDatabases(dbs, leaderElector); err != nil {
+			return mkErr("unable to fence databases: %v", err)
+		}
+	}
+
+	finishStage = interceptorChain.StartStage("wallet unlock")
 	partialChainControl, walletConfig, cleanUp, err := implCfg.BuildWalletConfig(
 		ctx, dbs, interceptorChain, grpcListeners,
 	)
+	finishStage()
 	if err != nil {
 		return mkErr("error creating wallet config: %v", err)
 	}
 
 	defer cleanUp()
 
+	finishStage = interceptorChain.StartStage("chain control")
 	activeChainControl, cleanUp, err := implCfg.BuildChainControl(
 		partialChainControl, walletConfig,
 	)
+	finishStage()
 	if err != nil {
 		return mkErr("error loading chain control: %v", err)
 	}
 
 	defer cleanUp()
 
+	// We'll wait until we're fully synced before we start the server. This
+	// ensures that we don't accept any possibly invalid state transitions,
+	// or accept channels with spent funds. As the wallet is running now,
+	// we start waiting concurrently to setting up the server and the RPC
+	// server. In read-only mode we never start the server, so we don't
+	// need to wait.
+	chainSynced := make(chan error, 1)
+	stopSyncWait := make(chan struct{})
+	defer close(stopSyncWait)
+	if !cfg.ReadOnly {
+		finishSync := interceptorChain.StartStage("chain sync")
+		go func() {
+			defer finishSync()
+
+			chainSynced <- waitForChainSync(
+				activeChainControl, interceptor, stopSyncWait,
+			)
+		}()
+	}
+
 	// TODO(roasbeef): add rotation
 	idKeyDesc, err := activeChainControl.KeyRing.DeriveKey(
 		keychain.KeyLocator{
@@ -576,14 +663,17 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 
 	// Set up the core server which will listen for incoming peer
 	// connections.
+	finishStage = interceptorChain.StartStage("server setup")
 	server, err := newServer(
 		cfg, cfg.Listeners, dbs, activeChainControl, &idKeyDesc,
 		activeChainControl.Cfg.WalletUnlockParams.ChansToRestore,
 		multiAcceptor, torController, tlsManager,
 	)
+	finishStage()
 	if err != nil {
 		return mkErr("unable to create server: %v", err)
 	}
+	server.leaderElector = leaderElector
 
 	// Set up an autopilot manager from the current config. This will be
 	// used to manage the underlying autopilot agent, starting and stopping
@@ -605,6 +695,10 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	}
 	defer atplManager.Stop()
 
+	server.configReloader = newConfigReloader(
+		cfg, server, atplManager, os.Args[1:],
+	)
+
 	err = tlsManager.LoadPermanentCertificate(activeChainControl.KeyRing)
 	if err != nil {
 		return mkErr("unable to load permanent TLS certificate: %v",
@@ -632,50 +726,33 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		return mkErr("error notifying ready: %v", err)
 	}
 
-	// We'll wait until we're fully synced to continue the start up of the
-	// remainder of the daemon. This ensures that we don't accept any
-	// possibly invalid state transitions, or accept channels with spent
-	// funds.
-	_, bestHeight, err := activeChainControl.ChainIO.GetBestBlock()
-	if err != nil {
-		return mkErr("unable to determine chain tip: %v", err)
-	}
-
-	ltndLog.Infof("Waiting for chain backend to finish sync, "+
-		"start_height=%v", bestHeight)
-
-	for {
-		if !interceptor.Alive() {
-			return nil
-		}
-
-		synced, ts, err := activeChainControl.Wallet.IsSynced()
-		if err != nil {
-			return mkErr("unable to determine if wallet is "+
-				"synced: %v", err)
-		}
-
-		ltndLog.Debugf("Syncing to block timestamp: %v, is synced=%v",
-			time.Unix(ts, 0), synced)
-
-		if synced {
-			break
-		}
+	// In read-only mode we don't start the server, so that we never
+	// connect to any peers or act on-chain. The RPC server keeps serving
+	// the data that is in the databases until we're asked to shut down.
+	// Streaming calls whose events are sent by subsystems of the server
+	// are rejected by the interceptor chain.
+	if cfg.ReadOnly {
+		ltndLog.Infof("Running in read-only mode, not starting server")
 
-		time.Sleep(time.Second * 1)
+		<-interceptor.ShutdownChannel()
+		return nil
 	}
 
-	_, bestHeight, err = activeChainControl.ChainIO.GetBestBlock()
-	if err != nil {
-		return mkErr("unable to determine chain tip: %v", err)
+	// Wait for the chain backend to finish syncing, which we've been
+	// waiting for concurrently since the wallet was started.
+	if err := <-chainSynced; err != nil {
+		return mkErr("error waiting for chain sync: %v", err)
+	}
+	if !interceptor.Alive() {
+		return nil
 	}
-
-	ltndLog.Infof("Chain backend is fully synced (end_height=%v)!",
-		bestHeight)
 
 	// With all the relevant chains initialized, we can finally start the
 	// server itself.
-	if err := server.Start(); err != nil {
+	finishStage = interceptorChain.StartStage("server start")
+	err = server.Start()
+	finishStage()
+	if err != nil {
 		return mkErr("unable to start server: %v", err)
 	}
 	defer server.Stop()
@@ -700,8 +777,67 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	}
 
 	// Wait for shutdown signal from either a graceful server stop or from
-	// the interrupt handler.
-	<-interceptor.ShutdownChannel()
+	// the interrupt handler. In the meantime, we reload the config every
+	// time we're asked to with SIGHUP.
+	for {
+		select {
+		case <-interceptor.ReloadChannel():
+			_, err := server.configReloader.Reload()
+			if err != nil {
+				ltndLog.Errorf("Unable to reload config: %v",
+					err)
+			}
+
+		case <-interceptor.ShutdownChannel():
+			return nil
+		}
+	}
+}
+
+// waitForChainSync blocks until the chain backend is fully synced, lnd is
+// shutting down, or the given quit channel is closed.
+func waitForChainSync(cc *chainreg.ChainControl, interceptor signal.Interceptor,
+	quit <-chan struct{}) error {
+
+	_, bestHeight, err := cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return fmt.Errorf("unable to determine chain tip: %w", err)
+	}
+
+	ltndLog.Infof("Waiting for chain backend to finish sync, "+
+		"start_height=%v", bestHeight)
+
+	for {
+		synced, ts, err := cc.Wallet.IsSynced()
+		if err != nil {
+			return fmt.Errorf("unable to determine if wallet is "+
+				"synced: %w", err)
+		}
+
+		ltndLog.Debugf("Syncing to block timestamp: %v, is synced=%v",
+			time.Unix(ts, 0), synced)
+
+		if synced {
+			break
+		}
+
+		select {
+		case <-time.After(time.Second):
+		case <-interceptor.ShutdownChannel():
+			return nil
+		case <-quit:
+			return nil
+		}
+	}
+
+	_, bestHeight, err = cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return fmt.Errorf("unable to determine chain tip: %w", err)
+	}
+
+	ltndLog.Infof("Chain backend is fully synced (end_height=%v)!",
+		bestHeight)
+
 	return nil
 }
 
@@ -738,6 +874,35 @@ func saveMacaroon(ctx context.Context, svc *macaroons.Service, filename string,
 	return nil
 }
 
+// fenceDatabases fences all databases that support fencing tokens with the
+// token of the current leadership term.
+func fenceDatabases(dbs *DatabaseInstances,
+	leaderElector cluster.LeaderElector) error {
+
+	if len(dbs.FencedDBs) == 0 {
+		ltndLog.Warnf("Database backend doesn't support fencing " +
+			"tokens, writes of a replaced leader won't be rejected")
+
+		return nil
+	}
+
+	token, err := leaderElector.FencingToken()
+	if err != nil {
+		return err
+	}
+
+	for name, db := range dbs.FencedDBs {
+		if err := db.SetFencingToken(token); err != nil {
+			return fmt.Errorf("unable to fence %v database: %w",
+				name, err)
+		}
+	}
+
+	ltndLog.Infof("Fenced databases with token %d", token)
+
+	return nil
+}
+
 // genDefaultMacaroons checks for three default macaroon files and generates
 // them if they do not exist; one admin-level, one for invoice access and one
 // read-only. Each macaroon is checked and created independently to ensure all
@@ -913,9 +1078,16 @@ func startRestProxy(cfg *Config, rpcServer *rpcServer, restDialOpts []grpc.DialO
 	// Wrap the default grpc-gateway handler with the WebSocket handler.
 	restHandler := lnrpc.NewWebSocketProxy(
 		mux, rpcsLog, cfg.WSPingInterval, cfg.WSPongWait,
-		lnrpc.LndClientStreamingURIs,
+		cfg.WSResumeTimeout, lnrpc.LndClientStreamingURIs,
 	)
 
+	// Let clients skip the transfer of large responses that didn't change
+	// since they last fetched them, and compress the rest unless disabled.
+	restHandler = lnrpc.NewETagHandler(restHandler, lnrpc.LndETagURIs)
+	if !cfg.DisableRestGzip {
+		restHandler = lnrpc.NewCompressionHandler(restHandler)
+	}
+
 	// Use a WaitGroup so we can be sure the instructions on how to input the
 	// password is the last thing to be printed to the console.
 	var wg sync.WaitGroup
@@ -944,8 +1116,9 @@ func startRestProxy(cfg *Config, rpcServer *rpcServer, restDialOpts []grpc.DialO
 
 			// Create our proxy chain now. A request will pass
 			// through the following chain:
-			// req ---> CORS handler --> WS proxy --->
-			//   REST proxy --> gRPC endpoint
+			// req ---> CORS handler --> compression handler -->
+			//   ETag handler --> WS proxy --> REST proxy -->
+			//   gRPC endpoint
 			corsHandler := allowCORS(restHandler, cfg.RestCORS)
 
 			wg.Done()
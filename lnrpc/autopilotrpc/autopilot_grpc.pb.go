@@ -32,6 +32,13 @@ type AutopilotClient interface {
 	// SetScores attempts to set the scores used by the running autopilot agent,
 	// if the external scoring heuristic is enabled.
 	SetScores(ctx context.Context, in *SetScoresRequest, opts ...grpc.CallOption) (*SetScoresResponse, error)
+	// RegisterAgent registers an external agent that decides which channels the
+	// autopilot opens and closes instead of its heuristics. Whenever the
+	// autopilot assesses its state, it sends the state of the node over the
+	// stream, which the agent must answer with its recommendations. These are
+	// executed within the budget of the autopilot. Only one agent can be
+	// registered at a time, and it is unregistered when the stream ends.
+	RegisterAgent(ctx context.Context, opts ...grpc.CallOption) (Autopilot_RegisterAgentClient, error)
 }
 
 type autopilotClient struct {
@@ -78,6 +85,37 @@ func (c *autopilotClient) SetScores(ctx context.Context, in *SetScoresRequest, o
 	return out, nil
 }
 
+func (c *autopilotClient) RegisterAgent(ctx context.Context, opts ...grpc.CallOption) (Autopilot_RegisterAgentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Autopilot_ServiceDesc.Streams[0], "/autopilotrpc.Autopilot/RegisterAgent", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &autopilotRegisterAgentClient{stream}
+	return x, nil
+}
+
+type Autopilot_RegisterAgentClient interface {
+	Send(*AgentRecommendations) error
+	Recv() (*AgentState, error)
+	grpc.ClientStream
+}
+
+type autopilotRegisterAgentClient struct {
+	grpc.ClientStream
+}
+
+func (x *autopilotRegisterAgentClient) Send(m *AgentRecommendations) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *autopilotRegisterAgentClient) Recv() (*AgentState, error) {
+	m := new(AgentState)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AutopilotServer is the server API for Autopilot service.
 // All implementations must embed UnimplementedAutopilotServer
 // for forward compatibility
@@ -96,6 +134,13 @@ type AutopilotServer interface {
 	// SetScores attempts to set the scores used by the running autopilot agent,
 	// if the external scoring heuristic is enabled.
 	SetScores(context.Context, *SetScoresRequest) (*SetScoresResponse, error)
+	// RegisterAgent registers an external agent that decides which channels the
+	// autopilot opens and closes instead of its heuristics. Whenever the
+	// autopilot assesses its state, it sends the state of the node over the
+	// stream, which the agent must answer with its recommendations. These are
+	// executed within the budget of the autopilot. Only one agent can be
+	// registered at a time, and it is unregistered when the stream ends.
+	RegisterAgent(Autopilot_RegisterAgentServer) error
 	mustEmbedUnimplementedAutopilotServer()
 }
 
@@ -115,6 +160,9 @@ func (UnimplementedAutopilotServer) QueryScores(context.Context, *QueryScoresReq
 func (UnimplementedAutopilotServer) SetScores(context.Context, *SetScoresRequest) (*SetScoresResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetScores not implemented")
 }
+func (UnimplementedAutopilotServer) RegisterAgent(Autopilot_RegisterAgentServer) error {
+	return status.Errorf(codes.Unimplemented, "method RegisterAgent not implemented")
+}
 func (UnimplementedAutopilotServer) mustEmbedUnimplementedAutopilotServer() {}
 
 // UnsafeAutopilotServer may be embedded to opt out of forward compatibility for this service.
@@ -200,6 +248,32 @@ func _Autopilot_SetScores_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Autopilot_RegisterAgent_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AutopilotServer).RegisterAgent(&autopilotRegisterAgentServer{stream})
+}
+
+type Autopilot_RegisterAgentServer interface {
+	Send(*AgentState) error
+	Recv() (*AgentRecommendations, error)
+	grpc.ServerStream
+}
+
+type autopilotRegisterAgentServer struct {
+	grpc.ServerStream
+}
+
+func (x *autopilotRegisterAgentServer) Send(m *AgentState) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *autopilotRegisterAgentServer) Recv() (*AgentRecommendations, error) {
+	m := new(AgentRecommendations)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Autopilot_ServiceDesc is the grpc.ServiceDesc for Autopilot service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -224,6 +298,13 @@ var Autopilot_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Autopilot_SetScores_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RegisterAgent",
+			Handler:       _Autopilot_RegisterAgent_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "autopilotrpc/autopilot.proto",
 }
@@ -6,6 +6,8 @@ package autopilotrpc
 import (
 	"context"
 	"encoding/hex"
+	"errors"
+	"io"
 	"sync/atomic"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -13,6 +15,8 @@ import (
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
 
@@ -49,6 +53,13 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/autopilotrpc.Autopilot/RegisterAgent": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 )
 
@@ -297,3 +308,32 @@ func (s *Server) SetScores(ctx context.Context,
 
 	return &SetScoresResponse{}, nil
 }
+
+// RegisterAgent registers an external agent that decides which channels the
+// autopilot opens and closes instead of its heuristics. The autopilot sends
+// the state of the node over the stream whenever it assesses it, and executes
+// the recommendations the agent answers with within its configured budget.
+// The agent is unregistered when the stream ends.
+//
+// NOTE: Part of the AutopilotServer interface.
+func (s *Server) RegisterAgent(stream Autopilot_RegisterAgentServer) error {
+	agent := newRPCAgent(stream)
+
+	unregister, err := s.manager.RegisterExternalAgent(agent)
+	if errors.Is(err, autopilot.ErrExternalAgentRegistered) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	defer unregister()
+
+	log.Debugf("Registered external agent")
+
+	err = agent.receive()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err
+}
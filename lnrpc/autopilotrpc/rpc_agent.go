@@ -0,0 +1,217 @@
+//go:build autopilotrpc
+// +build autopilotrpc
+
+package autopilotrpc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/autopilot"
+)
+
+var (
+	// agentResponseTimeout is the time an external agent has to answer a
+	// state with its recommendations. The autopilot waits for the answer
+	// in its control loop, so the timeout is kept short.
+	agentResponseTimeout = 10 * time.Second
+
+	// errAgentGone is returned when the stream of an external agent ended
+	// while the autopilot waited for its recommendations.
+	errAgentGone = errors.New("external agent disconnected")
+)
+
+// rpcAgent is an external autopilot agent that is connected over the
+// RegisterAgent stream.
+type rpcAgent struct {
+	stream Autopilot_RegisterAgentServer
+
+	// mu serializes the calls to Recommend, so that each state gets its
+	// own answer.
+	mu sync.Mutex
+
+	// stateID is the ID of the last state sent to the agent.
+	stateID uint64
+
+	// recommendations receives the recommendations sent by the agent.
+	recommendations chan *AgentRecommendations
+
+	// quit is closed once the stream ended.
+	quit chan struct{}
+}
+
+// A compile time check to ensure that rpcAgent implements the
+// autopilot.ExternalAgent interface.
+var _ autopilot.ExternalAgent = (*rpcAgent)(nil)
+
+// newRPCAgent creates an external agent for the given stream.
+func newRPCAgent(stream Autopilot_RegisterAgentServer) *rpcAgent {
+	return &rpcAgent{
+		stream:          stream,
+		recommendations: make(chan *AgentRecommendations),
+		quit:            make(chan struct{}),
+	}
+}
+
+// receive passes the recommendations received over the stream on to
+// Recommend until the stream ends.
+func (a *rpcAgent) receive() error {
+	defer close(a.quit)
+
+	for {
+		recs, err := a.stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case a.recommendations <- recs:
+		case <-a.stream.Context().Done():
+			return a.stream.Context().Err()
+		}
+	}
+}
+
+// Recommend sends the state of the node to the agent and returns the
+// recommendations it answers with.
+//
+// NOTE: This is part of the autopilot.ExternalAgent interface.
+func (a *rpcAgent) Recommend(
+	state *autopilot.AgentState) (*autopilot.Recommendations, error) {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stateID++
+	err := a.stream.Send(marshallAgentState(a.stateID, state))
+	if err != nil {
+		return nil, fmt.Errorf("unable to send state: %w", err)
+	}
+
+	timeout := time.After(agentResponseTimeout)
+	for {
+		select {
+		case recs := <-a.recommendations:
+			// Late answers to states we stopped waiting for are
+			// skipped.
+			if recs.StateId != a.stateID {
+				log.Debugf("Ignoring recommendations for "+
+					"outdated state %d", recs.StateId)
+
+				continue
+			}
+
+			return unmarshallRecommendations(recs, state.Graph)
+
+		case <-timeout:
+			return nil, fmt.Errorf("external agent didn't answer "+
+				"within %v", agentResponseTimeout)
+
+		case <-a.quit:
+			return nil, errAgentGone
+		}
+	}
+}
+
+// marshallAgentState converts the state of the node to its RPC
+// representation.
+func marshallAgentState(id uint64, state *autopilot.AgentState) *AgentState {
+	channels := make([]*AgentChannel, 0, len(state.Channels))
+	for _, channel := range state.Channels {
+		rpcChannel := &AgentChannel{
+			ChanId:     channel.ChanID.ToUint64(),
+			Node:       channel.Node[:],
+			BalanceSat: int64(channel.Balance),
+		}
+		if channel.ChanPoint != (wire.OutPoint{}) {
+			rpcChannel.ChannelPoint = channel.ChanPoint.String()
+		}
+
+		channels = append(channels, rpcChannel)
+	}
+
+	return &AgentState{
+		StateId:           id,
+		Channels:          channels,
+		WalletBalanceSat:  int64(state.WalletBalance),
+		AvailableFundsSat: int64(state.AvailableFunds),
+		NumChans:          state.NumChans,
+	}
+}
+
+// unmarshallRecommendations converts the recommendations of an agent. The
+// addresses of the nodes to open channels with are looked up in the graph,
+// and nodes without known addresses are skipped.
+func unmarshallRecommendations(recs *AgentRecommendations,
+	graph autopilot.ChannelGraph) (*autopilot.Recommendations, error) {
+
+	nodeIDs := make([]autopilot.NodeID, len(recs.Opens))
+	nodes := make(map[autopilot.NodeID]struct{}, len(recs.Opens))
+	for i, open := range recs.Opens {
+		pubKey, err := btcec.ParsePubKey(open.Node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node %x: %w", open.Node,
+				err)
+		}
+		if open.AmountSat <= 0 {
+			return nil, fmt.Errorf("invalid amount %d for node %x",
+				open.AmountSat, open.Node)
+		}
+
+		nodeIDs[i] = autopilot.NewNodeID(pubKey)
+		nodes[nodeIDs[i]] = struct{}{}
+	}
+
+	addrs := make(map[autopilot.NodeID][]net.Addr, len(nodes))
+	if len(nodes) > 0 {
+		err := graph.ForEachNode(func(node autopilot.Node) error {
+			nodeID := autopilot.NodeID(node.PubKey())
+			if _, ok := nodes[nodeID]; ok {
+				addrs[nodeID] = node.Addrs()
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to look up node "+
+				"addresses: %w", err)
+		}
+	}
+
+	var result autopilot.Recommendations
+	for i, open := range recs.Opens {
+		nodeID := nodeIDs[i]
+		if len(addrs[nodeID]) == 0 {
+			log.Debugf("Skipping recommended node %x without "+
+				"known addresses", open.Node)
+
+			continue
+		}
+
+		result.Opens = append(result.Opens,
+			autopilot.AttachmentDirective{
+				NodeID:  nodeID,
+				ChanAmt: btcutil.Amount(open.AmountSat),
+				Addrs:   addrs[nodeID],
+			},
+		)
+	}
+
+	for _, chanPoint := range recs.Closes {
+		outPoint, err := wire.NewOutPointFromString(chanPoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel point %v: %w",
+				chanPoint, err)
+		}
+
+		result.Closes = append(result.Closes, *outPoint)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,147 @@
+//go:build autopilotrpc
+// +build autopilotrpc
+
+package autopilotrpc
+
+import (
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/autopilot"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode is a graph node with the given addresses.
+type testNode struct {
+	pubKey [33]byte
+	addrs  []net.Addr
+}
+
+// PubKey returns the public key of the node.
+func (n *testNode) PubKey() [33]byte {
+	return n.pubKey
+}
+
+// Addrs returns the addresses of the node.
+func (n *testNode) Addrs() []net.Addr {
+	return n.addrs
+}
+
+// ForEachChannel does nothing, as the node has no channels.
+func (n *testNode) ForEachChannel(func(autopilot.ChannelEdge) error) error {
+	return nil
+}
+
+// testGraph is a channel graph made of the given nodes.
+type testGraph []*testNode
+
+// ForEachNode calls the callback for every node of the graph.
+func (g testGraph) ForEachNode(cb func(autopilot.Node) error) error {
+	for _, node := range g {
+		if err := cb(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TestUnmarshallRecommendations tests that the recommendations of an external
+// agent are validated, and that the addresses of the nodes are looked up in
+// the graph.
+func TestUnmarshallRecommendations(t *testing.T) {
+	t.Parallel()
+
+	newNode := func() *btcec.PublicKey {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return priv.PubKey()
+	}
+	known, unknown := newNode(), newNode()
+
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9735}
+	graph := testGraph{{
+		pubKey: autopilot.NewNodeID(known),
+		addrs:  []net.Addr{addr},
+	}}
+
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+
+	testCases := []struct {
+		name      string
+		recs      *AgentRecommendations
+		expected  *autopilot.Recommendations
+		expectErr string
+	}{{
+		name: "open and close",
+		recs: &AgentRecommendations{
+			Opens: []*AgentChannelOpen{{
+				Node:      known.SerializeCompressed(),
+				AmountSat: 100_000,
+			}},
+			Closes: []string{chanPoint.String()},
+		},
+		expected: &autopilot.Recommendations{
+			Opens: []autopilot.AttachmentDirective{{
+				NodeID:  autopilot.NewNodeID(known),
+				ChanAmt: btcutil.Amount(100_000),
+				Addrs:   []net.Addr{addr},
+			}},
+			Closes: []wire.OutPoint{chanPoint},
+		},
+	}, {
+		name: "node without addresses skipped",
+		recs: &AgentRecommendations{
+			Opens: []*AgentChannelOpen{{
+				Node:      unknown.SerializeCompressed(),
+				AmountSat: 100_000,
+			}},
+		},
+		expected: &autopilot.Recommendations{},
+	}, {
+		name: "invalid node",
+		recs: &AgentRecommendations{
+			Opens: []*AgentChannelOpen{{
+				Node:      []byte{1, 2, 3},
+				AmountSat: 100_000,
+			}},
+		},
+		expectErr: "invalid node",
+	}, {
+		name: "invalid amount",
+		recs: &AgentRecommendations{
+			Opens: []*AgentChannelOpen{{
+				Node: known.SerializeCompressed(),
+			}},
+		},
+		expectErr: "invalid amount",
+	}, {
+		name: "invalid channel point",
+		recs: &AgentRecommendations{
+			Closes: []string{"abc"},
+		},
+		expectErr: "invalid channel point",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			recs, err := unmarshallRecommendations(tc.recs, graph)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, recs)
+		})
+	}
+}
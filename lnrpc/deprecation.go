@@ -0,0 +1,161 @@
+package lnrpc
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// APIVersion is the version of lnd's RPC API. It is incremented
+	// whenever RPC methods or fields are deprecated or removed, so clients
+	// can detect such changes without parsing the lnd version.
+	APIVersion = 1
+
+	// APIVersionMetadataKey is the gRPC response header that carries the
+	// API version. Through the REST proxy it is returned as the
+	// Grpc-Metadata-Lnd-Api-Version header field.
+	APIVersionMetadataKey = "lnd-api-version"
+
+	// DeprecatedMetadataKey is the gRPC response header that is set on
+	// calls to deprecated RPC methods, holding the full method name.
+	DeprecatedMetadataKey = "lnd-deprecated"
+)
+
+var (
+	// deprecations caches the deprecations by full method name, as the
+	// registered descriptors don't change at runtime.
+	deprecations     map[string]*MethodDeprecation
+	deprecationsOnce sync.Once
+)
+
+// deprecatedFields returns the full names of the deprecated fields of the
+// message and of the messages it contains. Messages that were already
+// visited are skipped, which terminates recursive message definitions.
+func deprecatedFields(msg protoreflect.MessageDescriptor,
+	visited map[protoreflect.FullName]bool) []string {
+
+	if visited[msg.FullName()] {
+		return nil
+	}
+	visited[msg.FullName()] = true
+
+	var names []string
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+
+		opts, ok := field.Options().(*descriptorpb.FieldOptions)
+		if ok && opts.GetDeprecated() {
+			names = append(names, string(field.FullName()))
+		}
+
+		// The value of a map field is the second field of its entry
+		// message.
+		nested := field.Message()
+		if field.IsMap() {
+			nested = field.MapValue().Message()
+		}
+		if nested != nil {
+			names = append(
+				names, deprecatedFields(nested, visited)...,
+			)
+		}
+	}
+
+	return names
+}
+
+// loadDeprecations collects the deprecations of all registered RPC services.
+func loadDeprecations() map[string]*MethodDeprecation {
+	result := make(map[string]*MethodDeprecation)
+
+	protoregistry.GlobalFiles.RangeFiles(
+		func(file protoreflect.FileDescriptor) bool {
+			services := file.Services()
+			for i := 0; i < services.Len(); i++ {
+				service := services.Get(i)
+				methods := service.Methods()
+				for j := 0; j < methods.Len(); j++ {
+					method := methods.Get(j)
+					d := methodDeprecation(service, method)
+					if d != nil {
+						result[d.Method] = d
+					}
+				}
+			}
+
+			return true
+		},
+	)
+
+	return result
+}
+
+// methodDeprecation returns the deprecations of the method, or nil if neither
+// the method nor any of its fields are deprecated.
+func methodDeprecation(service protoreflect.ServiceDescriptor,
+	method protoreflect.MethodDescriptor) *MethodDeprecation {
+
+	opts, ok := method.Options().(*descriptorpb.MethodOptions)
+
+	d := &MethodDeprecation{
+		Method: "/" + string(service.FullName()) + "/" +
+			string(method.Name()),
+		Deprecated: ok && opts.GetDeprecated(),
+		RequestFields: deprecatedFields(
+			method.Input(),
+			make(map[protoreflect.FullName]bool),
+		),
+		ResponseFields: deprecatedFields(
+			method.Output(),
+			make(map[protoreflect.FullName]bool),
+		),
+	}
+	if !d.Deprecated && len(d.RequestFields) == 0 &&
+		len(d.ResponseFields) == 0 {
+
+		return nil
+	}
+
+	return d
+}
+
+// Deprecations returns the deprecations of all RPC methods that are
+// deprecated or have deprecated request or response fields, ordered by method
+// name. If a prefix is given, only methods whose full name starts with it are
+// returned, for example /routerrpc.Router/ for all methods of the router.
+func Deprecations(prefix string) []*MethodDeprecation {
+	deprecationsOnce.Do(func() {
+		deprecations = loadDeprecations()
+	})
+
+	var result []*MethodDeprecation
+	for method, d := range deprecations {
+		if strings.HasPrefix(method, prefix) {
+			result = append(result, d)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Method < result[j].Method
+	})
+
+	return result
+}
+
+// IsDeprecatedMethod returns true if the RPC method with the given full name
+// is deprecated.
+func IsDeprecatedMethod(fullMethod string) bool {
+	deprecationsOnce.Do(func() {
+		deprecations = loadDeprecations()
+	})
+
+	d, ok := deprecations[fullMethod]
+
+	return ok && d.Deprecated
+}
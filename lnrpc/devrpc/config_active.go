@@ -6,6 +6,8 @@ package devrpc
 import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fault"
 )
 
 // Config is the primary configuration struct for the DEV RPC server. It
@@ -16,4 +18,7 @@ import (
 type Config struct {
 	ActiveNetParams *chaincfg.Params
 	GraphDB         *channeldb.ChannelGraph
+	ChanStateDB     *channeldb.ChannelStateDB
+	Clock           clock.Clock
+	FaultInjector   *fault.Injector
 }
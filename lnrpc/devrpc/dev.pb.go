@@ -59,6 +59,721 @@ func (*ImportGraphResponse) Descriptor() ([]byte, []int) {
 	return file_devrpc_dev_proto_rawDescGZIP(), []int{0}
 }
 
+type GetProfileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The type of the profile to capture. This is either "cpu" for a CPU
+	// profile, "trace" for a runtime execution trace or the name of any profile
+	// known to runtime/pprof, like "heap" or "goroutine".
+	ProfileType string `protobuf:"bytes,1,opt,name=profile_type,json=profileType,proto3" json:"profile_type,omitempty"`
+	// The number of seconds to capture a CPU profile or an execution trace for.
+	// Must be positive and at most 300 for those types and is ignored for all
+	// other profile types.
+	DurationSeconds uint32 `protobuf:"varint,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+}
+
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileRequest) ProtoMessage() {}
+
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetProfileRequest) GetProfileType() string {
+	if x != nil {
+		return x.ProfileType
+	}
+	return ""
+}
+
+func (x *GetProfileRequest) GetDurationSeconds() uint32 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type ProfileChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The next chunk of the profile, in the format go tool pprof, or go tool
+	// trace for execution traces, reads. The chunks need to be concatenated in
+	// the order they are received.
+	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+}
+
+func (x *ProfileChunk) Reset() {
+	*x = ProfileChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProfileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileChunk) ProtoMessage() {}
+
+func (x *ProfileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileChunk.ProtoReflect.Descriptor instead.
+func (*ProfileChunk) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProfileChunk) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type DecodeWireMessageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The raw wire message, including its two byte message type.
+	RawMessage []byte `protobuf:"bytes,1,opt,name=raw_message,json=rawMessage,proto3" json:"raw_message,omitempty"`
+}
+
+func (x *DecodeWireMessageRequest) Reset() {
+	*x = DecodeWireMessageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeWireMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeWireMessageRequest) ProtoMessage() {}
+
+func (x *DecodeWireMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeWireMessageRequest.ProtoReflect.Descriptor instead.
+func (*DecodeWireMessageRequest) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DecodeWireMessageRequest) GetRawMessage() []byte {
+	if x != nil {
+		return x.RawMessage
+	}
+	return nil
+}
+
+type DecodeWireMessageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The message type given by the first two bytes of the raw message.
+	MsgType uint32 `protobuf:"varint,1,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
+	// The name of the message type, or "<unknown>" for message types that aren't
+	// known. It is empty if the raw message is too short to hold a type.
+	MsgTypeName string `protobuf:"bytes,2,opt,name=msg_type_name,json=msgTypeName,proto3" json:"msg_type_name,omitempty"`
+	// Whether the message could be decoded.
+	Decoded bool `protobuf:"varint,3,opt,name=decoded,proto3" json:"decoded,omitempty"`
+	// The size of the raw message, excluding the message type.
+	PayloadSize uint32 `protobuf:"varint,4,opt,name=payload_size,json=payloadSize,proto3" json:"payload_size,omitempty"`
+	// The largest payload size a message is allowed to have.
+	MaxPayloadSize uint32 `protobuf:"varint,5,opt,name=max_payload_size,json=maxPayloadSize,proto3" json:"max_payload_size,omitempty"`
+	// The payload size of the decoded message once encoded again. It is zero if
+	// decoding failed.
+	EncodedSize uint32 `protobuf:"varint,6,opt,name=encoded_size,json=encodedSize,proto3" json:"encoded_size,omitempty"`
+	// Whether encoding the decoded message again yields the raw message.
+	RoundTrip bool `protobuf:"varint,7,opt,name=round_trip,json=roundTrip,proto3" json:"round_trip,omitempty"`
+	// The error that occurred while decoding the message or encoding it again,
+	// if any.
+	Error string `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	// Whether the message codec panicked. This is always a bug, as it would
+	// allow a peer to crash the node with a crafted message.
+	Panicked bool `protobuf:"varint,9,opt,name=panicked,proto3" json:"panicked,omitempty"`
+}
+
+func (x *DecodeWireMessageResponse) Reset() {
+	*x = DecodeWireMessageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeWireMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeWireMessageResponse) ProtoMessage() {}
+
+func (x *DecodeWireMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeWireMessageResponse.ProtoReflect.Descriptor instead.
+func (*DecodeWireMessageResponse) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DecodeWireMessageResponse) GetMsgType() uint32 {
+	if x != nil {
+		return x.MsgType
+	}
+	return 0
+}
+
+func (x *DecodeWireMessageResponse) GetMsgTypeName() string {
+	if x != nil {
+		return x.MsgTypeName
+	}
+	return ""
+}
+
+func (x *DecodeWireMessageResponse) GetDecoded() bool {
+	if x != nil {
+		return x.Decoded
+	}
+	return false
+}
+
+func (x *DecodeWireMessageResponse) GetPayloadSize() uint32 {
+	if x != nil {
+		return x.PayloadSize
+	}
+	return 0
+}
+
+func (x *DecodeWireMessageResponse) GetMaxPayloadSize() uint32 {
+	if x != nil {
+		return x.MaxPayloadSize
+	}
+	return 0
+}
+
+func (x *DecodeWireMessageResponse) GetEncodedSize() uint32 {
+	if x != nil {
+		return x.EncodedSize
+	}
+	return 0
+}
+
+func (x *DecodeWireMessageResponse) GetRoundTrip() bool {
+	if x != nil {
+		return x.RoundTrip
+	}
+	return false
+}
+
+func (x *DecodeWireMessageResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DecodeWireMessageResponse) GetPanicked() bool {
+	if x != nil {
+		return x.Panicked
+	}
+	return false
+}
+
+type FailNextForwardsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of HTLC forwards to fail. Passing zero clears any pending
+	// failures.
+	NumForwards uint32 `protobuf:"varint,1,opt,name=num_forwards,json=numForwards,proto3" json:"num_forwards,omitempty"`
+}
+
+func (x *FailNextForwardsRequest) Reset() {
+	*x = FailNextForwardsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailNextForwardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailNextForwardsRequest) ProtoMessage() {}
+
+func (x *FailNextForwardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailNextForwardsRequest.ProtoReflect.Descriptor instead.
+func (*FailNextForwardsRequest) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FailNextForwardsRequest) GetNumForwards() uint32 {
+	if x != nil {
+		return x.NumForwards
+	}
+	return 0
+}
+
+type FailNextForwardsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FailNextForwardsResponse) Reset() {
+	*x = FailNextForwardsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailNextForwardsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailNextForwardsResponse) ProtoMessage() {}
+
+func (x *FailNextForwardsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailNextForwardsResponse.ProtoReflect.Descriptor instead.
+func (*FailNextForwardsResponse) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{6}
+}
+
+type DropNextGossipBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DropNextGossipBatchRequest) Reset() {
+	*x = DropNextGossipBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DropNextGossipBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropNextGossipBatchRequest) ProtoMessage() {}
+
+func (x *DropNextGossipBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropNextGossipBatchRequest.ProtoReflect.Descriptor instead.
+func (*DropNextGossipBatchRequest) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{7}
+}
+
+type DropNextGossipBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DropNextGossipBatchResponse) Reset() {
+	*x = DropNextGossipBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DropNextGossipBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropNextGossipBatchResponse) ProtoMessage() {}
+
+func (x *DropNextGossipBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropNextGossipBatchResponse.ProtoReflect.Descriptor instead.
+func (*DropNextGossipBatchResponse) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{8}
+}
+
+type FailChainNotifierOnceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FailChainNotifierOnceRequest) Reset() {
+	*x = FailChainNotifierOnceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailChainNotifierOnceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailChainNotifierOnceRequest) ProtoMessage() {}
+
+func (x *FailChainNotifierOnceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailChainNotifierOnceRequest.ProtoReflect.Descriptor instead.
+func (*FailChainNotifierOnceRequest) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{9}
+}
+
+type FailChainNotifierOnceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FailChainNotifierOnceResponse) Reset() {
+	*x = FailChainNotifierOnceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailChainNotifierOnceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailChainNotifierOnceResponse) ProtoMessage() {}
+
+func (x *FailChainNotifierOnceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailChainNotifierOnceResponse.ProtoReflect.Descriptor instead.
+func (*FailChainNotifierOnceResponse) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{10}
+}
+
+type ExportChannelDBRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The path of the export file on the machine lnd runs on. The file must not
+	// exist yet.
+	FilePath string `protobuf:"bytes,1,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+}
+
+func (x *ExportChannelDBRequest) Reset() {
+	*x = ExportChannelDBRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportChannelDBRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChannelDBRequest) ProtoMessage() {}
+
+func (x *ExportChannelDBRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChannelDBRequest.ProtoReflect.Descriptor instead.
+func (*ExportChannelDBRequest) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportChannelDBRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+type ExportChannelDBResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ExportChannelDBResponse) Reset() {
+	*x = ExportChannelDBResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportChannelDBResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChannelDBResponse) ProtoMessage() {}
+
+func (x *ExportChannelDBResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChannelDBResponse.ProtoReflect.Descriptor instead.
+func (*ExportChannelDBResponse) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{12}
+}
+
+type WarpTimeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of seconds to move the clock forward by. Must be positive.
+	DurationSeconds uint64 `protobuf:"varint,1,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+}
+
+func (x *WarpTimeRequest) Reset() {
+	*x = WarpTimeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WarpTimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarpTimeRequest) ProtoMessage() {}
+
+func (x *WarpTimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarpTimeRequest.ProtoReflect.Descriptor instead.
+func (*WarpTimeRequest) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WarpTimeRequest) GetDurationSeconds() uint64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type WarpTimeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The current time of the node after moving the clock, in unix seconds.
+	Time int64 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	// The total number of seconds the clock of the node is ahead of the wall
+	// clock.
+	OffsetSeconds int64 `protobuf:"varint,2,opt,name=offset_seconds,json=offsetSeconds,proto3" json:"offset_seconds,omitempty"`
+}
+
+func (x *WarpTimeResponse) Reset() {
+	*x = WarpTimeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_devrpc_dev_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WarpTimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarpTimeResponse) ProtoMessage() {}
+
+func (x *WarpTimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devrpc_dev_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarpTimeResponse.ProtoReflect.Descriptor instead.
+func (*WarpTimeResponse) Descriptor() ([]byte, []int) {
+	return file_devrpc_dev_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WarpTimeResponse) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *WarpTimeResponse) GetOffsetSeconds() int64 {
+	if x != nil {
+		return x.OffsetSeconds
+	}
+	return 0
+}
+
 var File_devrpc_dev_proto protoreflect.FileDescriptor
 
 var file_devrpc_dev_proto_rawDesc = []byte{
@@ -66,15 +781,111 @@ var file_devrpc_dev_proto_rawDesc = []byte{
 	0x74, 0x6f, 0x12, 0x06, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x1a, 0x0f, 0x6c, 0x69, 0x67, 0x68,
 	0x74, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x15, 0x0a, 0x13, 0x49,
 	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x47, 0x72, 0x61, 0x70, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x32, 0x46, 0x0a, 0x03, 0x44, 0x65, 0x76, 0x12, 0x3f, 0x0a, 0x0b, 0x49, 0x6d, 0x70,
-	0x6f, 0x72, 0x74, 0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
-	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x47, 0x72, 0x61, 0x70, 0x68, 0x1a, 0x1b, 0x2e,
-	0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x47, 0x72, 0x61,
-	0x70, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69,
-	0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6c, 0x6e, 0x64, 0x2f, 0x6c, 0x6e,
-	0x72, 0x70, 0x63, 0x2f, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x73, 0x65, 0x22, 0x61, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x24, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x3b, 0x0a, 0x18, 0x44,
+	0x65, 0x63, 0x6f, 0x64, 0x65, 0x57, 0x69, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x61, 0x77, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x72, 0x61,
+	0x77, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xb5, 0x02, 0x0a, 0x19, 0x44, 0x65, 0x63,
+	0x6f, 0x64, 0x65, 0x57, 0x69, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x61,
+	0x78, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x74, 0x72, 0x69, 0x70, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x54, 0x72, 0x69, 0x70, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x6e, 0x69, 0x63, 0x6b, 0x65, 0x64,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x70, 0x61, 0x6e, 0x69, 0x63, 0x6b, 0x65, 0x64,
+	0x22, 0x3c, 0x0a, 0x17, 0x46, 0x61, 0x69, 0x6c, 0x4e, 0x65, 0x78, 0x74, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6e,
+	0x75, 0x6d, 0x5f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x6e, 0x75, 0x6d, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x22, 0x1a,
+	0x0a, 0x18, 0x46, 0x61, 0x69, 0x6c, 0x4e, 0x65, 0x78, 0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x44, 0x72,
+	0x6f, 0x70, 0x4e, 0x65, 0x78, 0x74, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x1d, 0x0a, 0x1b, 0x44, 0x72, 0x6f, 0x70,
+	0x4e, 0x65, 0x78, 0x74, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x0a, 0x1c, 0x46, 0x61, 0x69, 0x6c, 0x43,
+	0x68, 0x61, 0x69, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4f, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x1f, 0x0a, 0x1d, 0x46, 0x61, 0x69, 0x6c, 0x43,
+	0x68, 0x61, 0x69, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4f, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x35, 0x0a, 0x16, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x42, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22,
+	0x19, 0x0a, 0x17, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x44, 0x42, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3c, 0x0a, 0x0f, 0x57, 0x61,
+	0x72, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a,
+	0x10, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x4d, 0x0a, 0x10, 0x57, 0x61, 0x72, 0x70,
+	0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x25, 0x0a, 0x0e, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x32, 0x91, 0x05, 0x0a, 0x03, 0x44, 0x65, 0x76, 0x12,
+	0x3f, 0x0a, 0x0b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x13,
+	0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x47, 0x72,
+	0x61, 0x70, 0x68, 0x1a, 0x1b, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x47, 0x72, 0x61, 0x70, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3f, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x19,
+	0x2e, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x64, 0x65, 0x76, 0x72,
+	0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30,
+	0x01, 0x12, 0x58, 0x0a, 0x11, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x57, 0x69, 0x72, 0x65, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e,
+	0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x57, 0x69, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70,
+	0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x57, 0x69, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x10, 0x46,
+	0x61, 0x69, 0x6c, 0x4e, 0x65, 0x78, 0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x12,
+	0x1f, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x4e, 0x65, 0x78,
+	0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x20, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x4e, 0x65,
+	0x78, 0x74, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x5e, 0x0a, 0x13, 0x44, 0x72, 0x6f, 0x70, 0x4e, 0x65, 0x78, 0x74, 0x47, 0x6f,
+	0x73, 0x73, 0x69, 0x70, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x22, 0x2e, 0x64, 0x65, 0x76, 0x72,
+	0x70, 0x63, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x4e, 0x65, 0x78, 0x74, 0x47, 0x6f, 0x73, 0x73, 0x69,
+	0x70, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e,
+	0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x4e, 0x65, 0x78, 0x74, 0x47,
+	0x6f, 0x73, 0x73, 0x69, 0x70, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x64, 0x0a, 0x15, 0x46, 0x61, 0x69, 0x6c, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4f, 0x6e, 0x63, 0x65, 0x12, 0x24, 0x2e, 0x64, 0x65,
+	0x76, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x4e, 0x6f,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4f, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x25, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x43,
+	0x68, 0x61, 0x69, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4f, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x42, 0x12, 0x1e, 0x2e, 0x64, 0x65,
+	0x76, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x42, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x64, 0x65,
+	0x76, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x42, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x08,
+	0x57, 0x61, 0x72, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x17, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70,
+	0x63, 0x2e, 0x57, 0x61, 0x72, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x61, 0x72, 0x70, 0x54,
+	0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2e, 0x5a, 0x2c, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e,
+	0x69, 0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6c, 0x6e, 0x64, 0x2f, 0x6c,
+	0x6e, 0x72, 0x70, 0x63, 0x2f, 0x64, 0x65, 0x76, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -89,19 +900,47 @@ func file_devrpc_dev_proto_rawDescGZIP() []byte {
 	return file_devrpc_dev_proto_rawDescData
 }
 
-var file_devrpc_dev_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_devrpc_dev_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_devrpc_dev_proto_goTypes = []interface{}{
-	(*ImportGraphResponse)(nil), // 0: devrpc.ImportGraphResponse
-	(*lnrpc.ChannelGraph)(nil),  // 1: lnrpc.ChannelGraph
+	(*ImportGraphResponse)(nil),           // 0: devrpc.ImportGraphResponse
+	(*GetProfileRequest)(nil),             // 1: devrpc.GetProfileRequest
+	(*ProfileChunk)(nil),                  // 2: devrpc.ProfileChunk
+	(*DecodeWireMessageRequest)(nil),      // 3: devrpc.DecodeWireMessageRequest
+	(*DecodeWireMessageResponse)(nil),     // 4: devrpc.DecodeWireMessageResponse
+	(*FailNextForwardsRequest)(nil),       // 5: devrpc.FailNextForwardsRequest
+	(*FailNextForwardsResponse)(nil),      // 6: devrpc.FailNextForwardsResponse
+	(*DropNextGossipBatchRequest)(nil),    // 7: devrpc.DropNextGossipBatchRequest
+	(*DropNextGossipBatchResponse)(nil),   // 8: devrpc.DropNextGossipBatchResponse
+	(*FailChainNotifierOnceRequest)(nil),  // 9: devrpc.FailChainNotifierOnceRequest
+	(*FailChainNotifierOnceResponse)(nil), // 10: devrpc.FailChainNotifierOnceResponse
+	(*ExportChannelDBRequest)(nil),        // 11: devrpc.ExportChannelDBRequest
+	(*ExportChannelDBResponse)(nil),       // 12: devrpc.ExportChannelDBResponse
+	(*WarpTimeRequest)(nil),               // 13: devrpc.WarpTimeRequest
+	(*WarpTimeResponse)(nil),              // 14: devrpc.WarpTimeResponse
+	(*lnrpc.ChannelGraph)(nil),            // 15: lnrpc.ChannelGraph
 }
 var file_devrpc_dev_proto_depIdxs = []int32{
-	1, // 0: devrpc.Dev.ImportGraph:input_type -> lnrpc.ChannelGraph
-	0, // 1: devrpc.Dev.ImportGraph:output_type -> devrpc.ImportGraphResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	15, // 0: devrpc.Dev.ImportGraph:input_type -> lnrpc.ChannelGraph
+	1,  // 1: devrpc.Dev.GetProfile:input_type -> devrpc.GetProfileRequest
+	3,  // 2: devrpc.Dev.DecodeWireMessage:input_type -> devrpc.DecodeWireMessageRequest
+	5,  // 3: devrpc.Dev.FailNextForwards:input_type -> devrpc.FailNextForwardsRequest
+	7,  // 4: devrpc.Dev.DropNextGossipBatch:input_type -> devrpc.DropNextGossipBatchRequest
+	9,  // 5: devrpc.Dev.FailChainNotifierOnce:input_type -> devrpc.FailChainNotifierOnceRequest
+	11, // 6: devrpc.Dev.ExportChannelDB:input_type -> devrpc.ExportChannelDBRequest
+	13, // 7: devrpc.Dev.WarpTime:input_type -> devrpc.WarpTimeRequest
+	0,  // 8: devrpc.Dev.ImportGraph:output_type -> devrpc.ImportGraphResponse
+	2,  // 9: devrpc.Dev.GetProfile:output_type -> devrpc.ProfileChunk
+	4,  // 10: devrpc.Dev.DecodeWireMessage:output_type -> devrpc.DecodeWireMessageResponse
+	6,  // 11: devrpc.Dev.FailNextForwards:output_type -> devrpc.FailNextForwardsResponse
+	8,  // 12: devrpc.Dev.DropNextGossipBatch:output_type -> devrpc.DropNextGossipBatchResponse
+	10, // 13: devrpc.Dev.FailChainNotifierOnce:output_type -> devrpc.FailChainNotifierOnceResponse
+	12, // 14: devrpc.Dev.ExportChannelDB:output_type -> devrpc.ExportChannelDBResponse
+	14, // 15: devrpc.Dev.WarpTime:output_type -> devrpc.WarpTimeResponse
+	8,  // [8:16] is the sub-list for method output_type
+	0,  // [0:8] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_devrpc_dev_proto_init() }
@@ -122,6 +961,174 @@ func file_devrpc_dev_proto_init() {
 				return nil
 			}
 		}
+		file_devrpc_dev_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProfileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProfileChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeWireMessageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeWireMessageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailNextForwardsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailNextForwardsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DropNextGossipBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DropNextGossipBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailChainNotifierOnceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailChainNotifierOnceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportChannelDBRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportChannelDBResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WarpTimeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_devrpc_dev_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WarpTimeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -129,7 +1136,7 @@ func file_devrpc_dev_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_devrpc_dev_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,46 @@ type DevClient interface {
 	// ImportGraph imports a ChannelGraph into the graph database. Should only be
 	// used for development.
 	ImportGraph(ctx context.Context, in *lnrpc.ChannelGraph, opts ...grpc.CallOption) (*ImportGraphResponse, error)
+	// lncli: `getprofile`
+	// GetProfile captures a profile of the given type and streams it back in
+	// chunks. CPU profiles and execution traces are captured for the given
+	// duration, all other profiles known to runtime/pprof, like heap or
+	// goroutine, are taken as a snapshot right away. Should only be used for
+	// development.
+	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (Dev_GetProfileClient, error)
+	// DecodeWireMessage decodes the given raw wire message and reports whether
+	// it could be decoded, whether it survives being encoded again and how its
+	// size compares to the maximum message size. Decoding happens in a sandbox
+	// that recovers from panics of the message codec, so that crashers can be
+	// found without risking the node. Should only be used for development.
+	DecodeWireMessage(ctx context.Context, in *DecodeWireMessageRequest, opts ...grpc.CallOption) (*DecodeWireMessageResponse, error)
+	// FailNextForwards makes the switch fail the next HTLC forwards with a
+	// temporary channel failure, as if the outgoing channel was unavailable.
+	// Requires lnd to be built with the integration tag. Should only be used for
+	// development.
+	FailNextForwards(ctx context.Context, in *FailNextForwardsRequest, opts ...grpc.CallOption) (*FailNextForwardsResponse, error)
+	// DropNextGossipBatch makes the gossiper drop the next batch of
+	// announcements it would broadcast to its peers. Requires lnd to be built
+	// with the integration tag. Should only be used for development.
+	DropNextGossipBatch(ctx context.Context, in *DropNextGossipBatchRequest, opts ...grpc.CallOption) (*DropNextGossipBatchResponse, error)
+	// FailChainNotifierOnce makes the next notification registration with the
+	// chain notifier fail, no matter whether it's for a confirmation, a spend or
+	// new blocks. Requires lnd to be built with the integration tag. Should only
+	// be used for development.
+	FailChainNotifierOnce(ctx context.Context, in *FailChainNotifierOnceRequest, opts ...grpc.CallOption) (*FailChainNotifierOnceResponse, error)
+	// ExportChannelDB writes the complete channel database to the given file,
+	// from which it can be imported into an empty SQL database by starting lnd
+	// with the dev.importchanneldb option. Once exported, the channel database
+	// of this node is fenced off: all writes to it fail and lnd refuses to
+	// start with it again, so the node should be shut down right away. Should
+	// only be used for development.
+	ExportChannelDB(ctx context.Context, in *ExportChannelDBRequest, opts ...grpc.CallOption) (*ExportChannelDBResponse, error)
+	// WarpTime moves the clock of all time based subsystems of the node forward
+	// by the given duration. Timers that expire as a result, for example the
+	// expiry of hold invoices or of packets in the switch, are triggered right
+	// away. Requires lnd to be started with the dev.timewarp option. Should
+	// only be used for development.
+	WarpTime(ctx context.Context, in *WarpTimeRequest, opts ...grpc.CallOption) (*WarpTimeResponse, error)
 }
 
 type devClient struct {
@@ -42,6 +82,92 @@ func (c *devClient) ImportGraph(ctx context.Context, in *lnrpc.ChannelGraph, opt
 	return out, nil
 }
 
+func (c *devClient) GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (Dev_GetProfileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Dev_ServiceDesc.Streams[0], "/devrpc.Dev/GetProfile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &devGetProfileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Dev_GetProfileClient interface {
+	Recv() (*ProfileChunk, error)
+	grpc.ClientStream
+}
+
+type devGetProfileClient struct {
+	grpc.ClientStream
+}
+
+func (x *devGetProfileClient) Recv() (*ProfileChunk, error) {
+	m := new(ProfileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *devClient) DecodeWireMessage(ctx context.Context, in *DecodeWireMessageRequest, opts ...grpc.CallOption) (*DecodeWireMessageResponse, error) {
+	out := new(DecodeWireMessageResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/DecodeWireMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devClient) FailNextForwards(ctx context.Context, in *FailNextForwardsRequest, opts ...grpc.CallOption) (*FailNextForwardsResponse, error) {
+	out := new(FailNextForwardsResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/FailNextForwards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devClient) DropNextGossipBatch(ctx context.Context, in *DropNextGossipBatchRequest, opts ...grpc.CallOption) (*DropNextGossipBatchResponse, error) {
+	out := new(DropNextGossipBatchResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/DropNextGossipBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devClient) FailChainNotifierOnce(ctx context.Context, in *FailChainNotifierOnceRequest, opts ...grpc.CallOption) (*FailChainNotifierOnceResponse, error) {
+	out := new(FailChainNotifierOnceResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/FailChainNotifierOnce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devClient) ExportChannelDB(ctx context.Context, in *ExportChannelDBRequest, opts ...grpc.CallOption) (*ExportChannelDBResponse, error) {
+	out := new(ExportChannelDBResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/ExportChannelDB", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devClient) WarpTime(ctx context.Context, in *WarpTimeRequest, opts ...grpc.CallOption) (*WarpTimeResponse, error) {
+	out := new(WarpTimeResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/WarpTime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DevServer is the server API for Dev service.
 // All implementations must embed UnimplementedDevServer
 // for forward compatibility
@@ -50,6 +176,46 @@ type DevServer interface {
 	// ImportGraph imports a ChannelGraph into the graph database. Should only be
 	// used for development.
 	ImportGraph(context.Context, *lnrpc.ChannelGraph) (*ImportGraphResponse, error)
+	// lncli: `getprofile`
+	// GetProfile captures a profile of the given type and streams it back in
+	// chunks. CPU profiles and execution traces are captured for the given
+	// duration, all other profiles known to runtime/pprof, like heap or
+	// goroutine, are taken as a snapshot right away. Should only be used for
+	// development.
+	GetProfile(*GetProfileRequest, Dev_GetProfileServer) error
+	// DecodeWireMessage decodes the given raw wire message and reports whether
+	// it could be decoded, whether it survives being encoded again and how its
+	// size compares to the maximum message size. Decoding happens in a sandbox
+	// that recovers from panics of the message codec, so that crashers can be
+	// found without risking the node. Should only be used for development.
+	DecodeWireMessage(context.Context, *DecodeWireMessageRequest) (*DecodeWireMessageResponse, error)
+	// FailNextForwards makes the switch fail the next HTLC forwards with a
+	// temporary channel failure, as if the outgoing channel was unavailable.
+	// Requires lnd to be built with the integration tag. Should only be used for
+	// development.
+	FailNextForwards(context.Context, *FailNextForwardsRequest) (*FailNextForwardsResponse, error)
+	// DropNextGossipBatch makes the gossiper drop the next batch of
+	// announcements it would broadcast to its peers. Requires lnd to be built
+	// with the integration tag. Should only be used for development.
+	DropNextGossipBatch(context.Context, *DropNextGossipBatchRequest) (*DropNextGossipBatchResponse, error)
+	// FailChainNotifierOnce makes the next notification registration with the
+	// chain notifier fail, no matter whether it's for a confirmation, a spend or
+	// new blocks. Requires lnd to be built with the integration tag. Should only
+	// be used for development.
+	FailChainNotifierOnce(context.Context, *FailChainNotifierOnceRequest) (*FailChainNotifierOnceResponse, error)
+	// ExportChannelDB writes the complete channel database to the given file,
+	// from which it can be imported into an empty SQL database by starting lnd
+	// with the dev.importchanneldb option. Once exported, the channel database
+	// of this node is fenced off: all writes to it fail and lnd refuses to
+	// start with it again, so the node should be shut down right away. Should
+	// only be used for development.
+	ExportChannelDB(context.Context, *ExportChannelDBRequest) (*ExportChannelDBResponse, error)
+	// WarpTime moves the clock of all time based subsystems of the node forward
+	// by the given duration. Timers that expire as a result, for example the
+	// expiry of hold invoices or of packets in the switch, are triggered right
+	// away. Requires lnd to be started with the dev.timewarp option. Should
+	// only be used for development.
+	WarpTime(context.Context, *WarpTimeRequest) (*WarpTimeResponse, error)
 	mustEmbedUnimplementedDevServer()
 }
 
@@ -60,6 +226,27 @@ type UnimplementedDevServer struct {
 func (UnimplementedDevServer) ImportGraph(context.Context, *lnrpc.ChannelGraph) (*ImportGraphResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportGraph not implemented")
 }
+func (UnimplementedDevServer) GetProfile(*GetProfileRequest, Dev_GetProfileServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedDevServer) DecodeWireMessage(context.Context, *DecodeWireMessageRequest) (*DecodeWireMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodeWireMessage not implemented")
+}
+func (UnimplementedDevServer) FailNextForwards(context.Context, *FailNextForwardsRequest) (*FailNextForwardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FailNextForwards not implemented")
+}
+func (UnimplementedDevServer) DropNextGossipBatch(context.Context, *DropNextGossipBatchRequest) (*DropNextGossipBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropNextGossipBatch not implemented")
+}
+func (UnimplementedDevServer) FailChainNotifierOnce(context.Context, *FailChainNotifierOnceRequest) (*FailChainNotifierOnceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FailChainNotifierOnce not implemented")
+}
+func (UnimplementedDevServer) ExportChannelDB(context.Context, *ExportChannelDBRequest) (*ExportChannelDBResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportChannelDB not implemented")
+}
+func (UnimplementedDevServer) WarpTime(context.Context, *WarpTimeRequest) (*WarpTimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WarpTime not implemented")
+}
 func (UnimplementedDevServer) mustEmbedUnimplementedDevServer() {}
 
 // UnsafeDevServer may be embedded to opt out of forward compatibility for this service.
@@ -91,6 +278,135 @@ func _Dev_ImportGraph_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dev_GetProfile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetProfileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DevServer).GetProfile(m, &devGetProfileServer{stream})
+}
+
+type Dev_GetProfileServer interface {
+	Send(*ProfileChunk) error
+	grpc.ServerStream
+}
+
+type devGetProfileServer struct {
+	grpc.ServerStream
+}
+
+func (x *devGetProfileServer) Send(m *ProfileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Dev_DecodeWireMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeWireMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).DecodeWireMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/DecodeWireMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).DecodeWireMessage(ctx, req.(*DecodeWireMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dev_FailNextForwards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FailNextForwardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).FailNextForwards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/FailNextForwards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).FailNextForwards(ctx, req.(*FailNextForwardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dev_DropNextGossipBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropNextGossipBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).DropNextGossipBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/DropNextGossipBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).DropNextGossipBatch(ctx, req.(*DropNextGossipBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dev_FailChainNotifierOnce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FailChainNotifierOnceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).FailChainNotifierOnce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/FailChainNotifierOnce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).FailChainNotifierOnce(ctx, req.(*FailChainNotifierOnceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dev_ExportChannelDB_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportChannelDBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).ExportChannelDB(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/ExportChannelDB",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).ExportChannelDB(ctx, req.(*ExportChannelDBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dev_WarpTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarpTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).WarpTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/WarpTime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).WarpTime(ctx, req.(*WarpTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Dev_ServiceDesc is the grpc.ServiceDesc for Dev service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -102,7 +418,37 @@ var Dev_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ImportGraph",
 			Handler:    _Dev_ImportGraph_Handler,
 		},
+		{
+			MethodName: "DecodeWireMessage",
+			Handler:    _Dev_DecodeWireMessage_Handler,
+		},
+		{
+			MethodName: "FailNextForwards",
+			Handler:    _Dev_FailNextForwards_Handler,
+		},
+		{
+			MethodName: "DropNextGossipBatch",
+			Handler:    _Dev_DropNextGossipBatch_Handler,
+		},
+		{
+			MethodName: "FailChainNotifierOnce",
+			Handler:    _Dev_FailChainNotifierOnce_Handler,
+		},
+		{
+			MethodName: "ExportChannelDB",
+			Handler:    _Dev_ExportChannelDB_Handler,
+		},
+		{
+			MethodName: "WarpTime",
+			Handler:    _Dev_WarpTime_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetProfile",
+			Handler:       _Dev_GetProfile_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "devrpc/dev.proto",
 }
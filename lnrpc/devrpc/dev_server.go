@@ -6,7 +6,10 @@ package devrpc
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math"
+	"os"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -18,8 +21,10 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fault"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -40,6 +45,34 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/devrpc.Dev/ExportChannelDB": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/devrpc.Dev/WarpTime": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/devrpc.Dev/FailNextForwards": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/devrpc.Dev/DropNextGossipBatch": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/devrpc.Dev/FailChainNotifierOnce": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/devrpc.Dev/GetProfile": {{
+			Entity: "info",
+			Action: "write",
+		}},
+		"/devrpc.Dev/DecodeWireMessage": {{
+			Entity: "info",
+			Action: "read",
+		}},
 	}
 )
 
@@ -342,3 +375,177 @@ func (s *Server) ImportGraph(ctx context.Context,
 
 	return &ImportGraphResponse{}, nil
 }
+
+// ExportChannelDB exports the complete channel state of the node to a new file
+// at the given path, to migrate the node to a different database backend. The
+// export can be imported into an empty SQL database by starting lnd with the
+// dev.importchanneldb option. Once exported, the channel database of this
+// node is fenced off: all writes to it fail and lnd refuses to start with it
+// again, so the node should be shut down right away.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) ExportChannelDB(_ context.Context,
+	req *ExportChannelDBRequest) (*ExportChannelDBResponse, error) {
+
+	filePath := req.FilePath
+	if filePath == "" {
+		return nil, fmt.Errorf("export file path must be set")
+	}
+
+	// Never overwrite an existing file, it could be a previous export.
+	file, err := os.OpenFile(
+		filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create export file: %w",
+			err)
+	}
+
+	err = s.cfg.ChanStateDB.GetParentDB().ExportForMigration(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(filePath)
+
+		return nil, fmt.Errorf("unable to export channel db: %w",
+			err)
+	}
+
+	log.Infof("Exported channel db to %v, the node must be shut down "+
+		"now", filePath)
+
+	return &ExportChannelDBResponse{}, nil
+}
+
+// WarpTime moves the clock of all time based subsystems of the node forward
+// by the given duration and returns the new time. Timers that expire as a
+// result, for example the expiry of hold invoices or of packets in the
+// switch, are triggered right away. This is only possible if the node was
+// started with the dev.timewarp option.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) WarpTime(_ context.Context,
+	req *WarpTimeRequest) (*WarpTimeResponse, error) {
+
+	warpClock, ok := s.cfg.Clock.(*lnutils.WarpClock)
+	if !ok {
+		return nil, fmt.Errorf("time warp not enabled, lnd must be " +
+			"started with dev.timewarp")
+	}
+
+	// Durations beyond the range of time.Duration would overflow.
+	switch {
+	case req.DurationSeconds == 0:
+		return nil, fmt.Errorf("duration must be positive")
+
+	case req.DurationSeconds > math.MaxInt64/uint64(time.Second):
+		return nil, fmt.Errorf("duration of %d seconds too large",
+			req.DurationSeconds)
+	}
+
+	duration := time.Duration(req.DurationSeconds) * time.Second
+	now := warpClock.Warp(duration)
+
+	log.Infof("Moved clock forward by %v to %v, total offset is %v",
+		duration, now, warpClock.Offset())
+
+	return &WarpTimeResponse{
+		Time:          now.Unix(),
+		OffsetSeconds: int64(warpClock.Offset().Seconds()),
+	}, nil
+}
+
+// injectFault arms the given number of faults at the injection point.
+func (s *Server) injectFault(p fault.Point, n uint32) error {
+	if s.cfg.FaultInjector == nil {
+		return fmt.Errorf("fault injection not available, lnd " +
+			"must be built with the integration tag")
+	}
+
+	s.cfg.FaultInjector.Arm(p, n)
+
+	return nil
+}
+
+// FailNextForwards makes the switch fail the next n HTLC forwards with a
+// temporary channel failure, as if the outgoing channel was unavailable.
+// Passing zero clears any pending failures.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) FailNextForwards(_ context.Context,
+	req *FailNextForwardsRequest) (*FailNextForwardsResponse, error) {
+
+	err := s.injectFault(fault.ForwardHTLC, req.NumForwards)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FailNextForwardsResponse{}, nil
+}
+
+// DropNextGossipBatch makes the gossiper drop the next batch of announcements
+// it would broadcast to its peers.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) DropNextGossipBatch(_ context.Context,
+	_ *DropNextGossipBatchRequest) (*DropNextGossipBatchResponse, error) {
+
+	if err := s.injectFault(fault.GossipBatch, 1); err != nil {
+		return nil, err
+	}
+
+	return &DropNextGossipBatchResponse{}, nil
+}
+
+// FailChainNotifierOnce makes the next notification registration with the
+// chain notifier fail, no matter whether it's for a confirmation, a spend or
+// new blocks.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) FailChainNotifierOnce(_ context.Context,
+	_ *FailChainNotifierOnceRequest) (*FailChainNotifierOnceResponse,
+	error) {
+
+	if err := s.injectFault(fault.ChainNotifier, 1); err != nil {
+		return nil, err
+	}
+
+	return &FailChainNotifierOnceResponse{}, nil
+}
+
+// DecodeWireMessage decodes the given raw wire message, including its two
+// byte message type, and reports whether it could be decoded, whether it
+// survives being encoded again and how its size compares to the maximum
+// message size. Decoding happens in a sandbox that recovers from panics of the
+// message codec, so that crashers can be found without risking the node.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) DecodeWireMessage(_ context.Context,
+	req *DecodeWireMessageRequest) (*DecodeWireMessageResponse, error) {
+
+	report := lnwire.DecodeMessage(req.RawMessage)
+	panicked := errors.Is(report.Err, lnwire.ErrDecodePanic)
+	if panicked {
+		log.Errorf("Decoding wire message of type %v panicked: %v",
+			report.Type, report.Err)
+	}
+
+	resp := &DecodeWireMessageResponse{
+		MsgType:        uint32(report.Type),
+		Decoded:        report.Msg != nil,
+		PayloadSize:    uint32(report.PayloadSize),
+		MaxPayloadSize: uint32(report.MaxPayloadSize),
+		EncodedSize:    uint32(report.EncodedSize),
+		RoundTrip:      report.RoundTrip,
+		Panicked:       panicked,
+	}
+	if len(req.RawMessage) >= 2 {
+		resp.MsgTypeName = report.Type.String()
+	}
+	if report.Err != nil {
+		resp.Error = report.Err.Error()
+	}
+
+	return resp, nil
+}
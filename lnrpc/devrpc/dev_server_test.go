@@ -0,0 +1,182 @@
+//go:build dev
+// +build dev
+
+package devrpc
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fault"
+	"github.com/lightningnetwork/lnd/lnutils"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeWireMessage tests that the decode report of a raw wire message is
+// converted to its RPC representation.
+func TestDecodeWireMessage(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	_, err := lnwire.WriteMessage(
+		&b, &lnwire.DynAck{ChanID: lnwire.ChannelID{1}}, 0,
+	)
+	require.NoError(t, err)
+	raw := b.Bytes()
+
+	testCases := []struct {
+		name     string
+		raw      []byte
+		expected *DecodeWireMessageResponse
+	}{{
+		name: "valid message",
+		raw:  raw,
+		expected: &DecodeWireMessageResponse{
+			MsgType:        uint32(lnwire.MsgDynAck),
+			MsgTypeName:    "DynAck",
+			Decoded:        true,
+			PayloadSize:    uint32(len(raw) - 2),
+			MaxPayloadSize: lnwire.MaxMsgBody,
+			EncodedSize:    uint32(len(raw) - 2),
+			RoundTrip:      true,
+		},
+	}, {
+		name: "too short",
+		raw:  []byte{0},
+		expected: &DecodeWireMessageResponse{
+			MaxPayloadSize: lnwire.MaxMsgBody,
+			Error:          "message too short: 1 bytes",
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Server{}
+			resp, err := s.DecodeWireMessage(
+				context.Background(), &DecodeWireMessageRequest{
+					RawMessage: tc.raw,
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, resp)
+		})
+	}
+
+	// A truncated message fails to decode, which is reported without
+	// failing the call.
+	s := &Server{}
+	resp, err := s.DecodeWireMessage(
+		context.Background(), &DecodeWireMessageRequest{
+			RawMessage: raw[:10],
+		},
+	)
+	require.NoError(t, err)
+	require.False(t, resp.Decoded)
+	require.False(t, resp.Panicked)
+	require.NotEmpty(t, resp.Error)
+}
+
+// TestFaultInjection tests that the fault injection RPCs arm the faults at
+// their injection points, and that they fail if fault injection isn't
+// available.
+func TestFaultInjection(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	s := &Server{cfg: &Config{}}
+	_, err := s.FailNextForwards(ctx, &FailNextForwardsRequest{
+		NumForwards: 1,
+	})
+	require.ErrorContains(t, err, "fault injection not available")
+
+	injector := fault.NewInjector()
+	s = &Server{cfg: &Config{FaultInjector: injector}}
+
+	_, err = s.FailNextForwards(ctx, &FailNextForwardsRequest{
+		NumForwards: 3,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, injector.Armed(fault.ForwardHTLC))
+
+	_, err = s.DropNextGossipBatch(ctx, &DropNextGossipBatchRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, injector.Armed(fault.GossipBatch))
+
+	_, err = s.FailChainNotifierOnce(ctx, &FailChainNotifierOnceRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, injector.Armed(fault.ChainNotifier))
+
+	// Arming zero faults clears the pending ones.
+	_, err = s.FailNextForwards(ctx, &FailNextForwardsRequest{})
+	require.NoError(t, err)
+	require.Zero(t, injector.Armed(fault.ForwardHTLC))
+}
+
+// TestExportChannelDBInvalidPath tests that the channel db isn't exported if
+// the export file path is missing or the file already exists.
+func TestExportChannelDBInvalidPath(t *testing.T) {
+	t.Parallel()
+
+	existing := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, os.WriteFile(existing, []byte("previous"), 0600))
+
+	s := &Server{cfg: &Config{}}
+	_, err := s.ExportChannelDB(
+		context.Background(), &ExportChannelDBRequest{},
+	)
+	require.ErrorContains(t, err, "export file path must be set")
+
+	_, err = s.ExportChannelDB(
+		context.Background(), &ExportChannelDBRequest{
+			FilePath: existing,
+		},
+	)
+	require.ErrorIs(t, err, os.ErrExist)
+
+	// The previous export is left untouched.
+	content, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	require.Equal(t, []byte("previous"), content)
+}
+
+// TestWarpTime tests that the clock is moved forward, and that invalid
+// durations are rejected.
+func TestWarpTime(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	s := &Server{cfg: &Config{Clock: clock.NewDefaultClock()}}
+	_, err := s.WarpTime(ctx, &WarpTimeRequest{DurationSeconds: 60})
+	require.ErrorContains(t, err, "time warp not enabled")
+
+	warpClock := lnutils.NewWarpClock()
+	s = &Server{cfg: &Config{Clock: warpClock}}
+
+	_, err = s.WarpTime(ctx, &WarpTimeRequest{})
+	require.ErrorContains(t, err, "duration must be positive")
+
+	_, err = s.WarpTime(ctx, &WarpTimeRequest{
+		DurationSeconds: math.MaxUint64,
+	})
+	require.ErrorContains(t, err, "too large")
+
+	before := time.Now()
+	resp, err := s.WarpTime(ctx, &WarpTimeRequest{DurationSeconds: 3600})
+	require.NoError(t, err)
+	require.EqualValues(t, 3600, resp.OffsetSeconds)
+	require.GreaterOrEqual(t, resp.Time, before.Add(time.Hour).Unix())
+	require.Equal(t, time.Hour, warpClock.Offset())
+}
@@ -0,0 +1,136 @@
+//go:build dev
+// +build dev
+
+package devrpc
+
+import (
+	"fmt"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+)
+
+const (
+	// ProfileCPU is the profile type of a CPU profile.
+	ProfileCPU = "cpu"
+
+	// ProfileTrace is the profile type of a runtime execution trace.
+	ProfileTrace = "trace"
+
+	// MaxProfileDuration is the longest duration a CPU profile or an
+	// execution trace can be captured for.
+	MaxProfileDuration = 5 * time.Minute
+
+	// profileChunkSize is the maximum size of a chunk of a profile that is
+	// sent back to the caller.
+	profileChunkSize = 64 * 1024
+)
+
+// chunkWriter is an io.Writer that passes everything written to it on to a
+// send function in chunks of at most profileChunkSize bytes.
+type chunkWriter struct {
+	buf  []byte
+	send func([]byte) error
+}
+
+// Write buffers the given bytes and sends every chunk that is full.
+//
+// NOTE: This is part of the io.Writer interface.
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= profileChunkSize {
+		if err := w.send(w.buf[:profileChunkSize]); err != nil {
+			return 0, err
+		}
+
+		w.buf = w.buf[profileChunkSize:]
+	}
+
+	return len(p), nil
+}
+
+// flush sends the remaining buffered bytes, if any.
+func (w *chunkWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	err := w.send(w.buf)
+	w.buf = nil
+
+	return err
+}
+
+// GetProfile captures a profile of the given type and streams it back in
+// chunks. CPU profiles and execution traces are captured for the given
+// duration, or until the stream is canceled. All other types are the profiles
+// known to runtime/pprof, like heap or goroutine, and are taken as a snapshot
+// right away. Every profile is sent in the format the go tool pprof, or go
+// tool trace for execution traces, reads.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) GetProfile(req *GetProfileRequest,
+	stream Dev_GetProfileServer) error {
+
+	var (
+		ctx         = stream.Context()
+		profileType = req.ProfileType
+		duration    = time.Duration(req.DurationSeconds) * time.Second
+	)
+
+	send := func(chunk []byte) error {
+		return stream.Send(&ProfileChunk{
+			Chunk: chunk,
+		})
+	}
+	w := &chunkWriter{send: send}
+
+	switch profileType {
+	case ProfileCPU, ProfileTrace:
+		if duration <= 0 || duration > MaxProfileDuration {
+			return fmt.Errorf("duration must be positive and at "+
+				"most %v", MaxProfileDuration)
+		}
+
+		start, stop := pprof.StartCPUProfile, pprof.StopCPUProfile
+		if profileType == ProfileTrace {
+			start, stop = trace.Start, trace.Stop
+		}
+
+		// Only one CPU profile or trace can be running at a time, so
+		// this fails if one was started through the pprof HTTP port or
+		// the cpuprofile option.
+		if err := start(w); err != nil {
+			return fmt.Errorf("unable to start %v profile: %w",
+				profileType, err)
+		}
+
+		log.Infof("Capturing %v profile for %v", profileType,
+			duration)
+
+		select {
+		case <-time.After(duration):
+		case <-ctx.Done():
+		}
+
+		stop()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+	default:
+		profile := pprof.Lookup(profileType)
+		if profile == nil {
+			return fmt.Errorf("unknown profile type %v",
+				profileType)
+		}
+
+		if err := profile.WriteTo(w, 0); err != nil {
+			return fmt.Errorf("unable to write %v profile: %w",
+				profileType, err)
+		}
+	}
+
+	return w.flush()
+}
@@ -0,0 +1,135 @@
+//go:build dev
+// +build dev
+
+package devrpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockProfileStream is a mock implementation of the Dev_GetProfileServer
+// stream that collects all chunks sent to it.
+type mockProfileStream struct {
+	grpc.ServerStream
+
+	ctx    context.Context
+	chunks [][]byte
+}
+
+// Context returns the context of the stream.
+func (m *mockProfileStream) Context() context.Context {
+	return m.ctx
+}
+
+// Send stores a copy of the given chunk.
+func (m *mockProfileStream) Send(chunk *ProfileChunk) error {
+	m.chunks = append(m.chunks, bytes.Clone(chunk.Chunk))
+
+	return nil
+}
+
+// TestGetProfile tests that profiles are streamed back in chunks and that
+// invalid requests are rejected.
+func TestGetProfile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		req  *GetProfileRequest
+		err  string
+	}{{
+		name: "heap snapshot",
+		req: &GetProfileRequest{
+			ProfileType: "heap",
+		},
+	}, {
+		name: "goroutine snapshot ignores duration",
+		req: &GetProfileRequest{
+			ProfileType:     "goroutine",
+			DurationSeconds: 1000,
+		},
+	}, {
+		name: "unknown profile",
+		req: &GetProfileRequest{
+			ProfileType: "unknown",
+		},
+		err: "unknown profile type",
+	}, {
+		name: "cpu without duration",
+		req: &GetProfileRequest{
+			ProfileType: ProfileCPU,
+		},
+		err: "duration must be positive",
+	}, {
+		name: "trace too long",
+		req: &GetProfileRequest{
+			ProfileType:     ProfileTrace,
+			DurationSeconds: 301,
+		},
+		err: "duration must be positive",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Server{}
+			stream := &mockProfileStream{ctx: context.Background()}
+
+			err := s.GetProfile(tc.req, stream)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				require.Empty(t, stream.chunks)
+
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, stream.chunks)
+
+			// The chunks make up a gzip compressed pprof profile.
+			for _, chunk := range stream.chunks {
+				require.LessOrEqual(
+					t, len(chunk), profileChunkSize,
+				)
+			}
+			r, err := gzip.NewReader(
+				bytes.NewReader(bytes.Join(stream.chunks, nil)),
+			)
+			require.NoError(t, err)
+			_, err = io.ReadAll(r)
+			require.NoError(t, err)
+		})
+	}
+}
+
+// TestChunkWriter tests that the chunkWriter splits its input into chunks of
+// at most profileChunkSize bytes.
+func TestChunkWriter(t *testing.T) {
+	t.Parallel()
+
+	var chunks [][]byte
+	w := &chunkWriter{send: func(b []byte) error {
+		chunks = append(chunks, bytes.Clone(b))
+		return nil
+	}}
+
+	data := bytes.Repeat([]byte{1}, 2*profileChunkSize+10)
+	_, err := w.Write(data[:10])
+	require.NoError(t, err)
+	_, err = w.Write(data[10:])
+	require.NoError(t, err)
+	require.NoError(t, w.flush())
+
+	require.Len(t, chunks, 3)
+	require.Len(t, chunks[2], 10)
+	require.Equal(t, data, bytes.Join(chunks, nil))
+}
@@ -66,6 +66,10 @@ type AddInvoiceConfig struct {
 	// specified.
 	DefaultCLTVExpiry uint32
 
+	// MinCLTVExpiry is the minimum final CLTV delta a user may choose for
+	// an invoice. If zero, routing.MinCLTVDelta is used.
+	MinCLTVExpiry uint32
+
 	// ChanDB is a global boltdb instance which is needed to access the
 	// channel graph.
 	ChanDB *channeldb.ChannelStateDB
@@ -84,6 +88,16 @@ type AddInvoiceConfig struct {
 	// GetAlias allows the peer's alias SCID to be retrieved for private
 	// option_scid_alias channels.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// GeneratePreimage, if set, generates the preimage of non-AMP
+	// invoices that are given neither a preimage nor a hash, instead of
+	// drawing it at random.
+	GeneratePreimage func() (lntypes.Preimage, error)
+
+	// AttributeCreator, if set, attributes the invoice with the given
+	// payment hash to the creator of the request in the given context,
+	// once the invoice was added.
+	AttributeCreator func(ctx context.Context, hash lntypes.Hash) error
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -238,6 +252,16 @@ func (d *AddInvoiceData) mppPaymentHashAndPreimage() (*lntypes.Preimage,
 func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	invoice *AddInvoiceData) (*lntypes.Hash, *invoices.Invoice, error) {
 
+	if cfg.GeneratePreimage != nil && !invoice.Amp &&
+		invoice.Preimage == nil && invoice.Hash == nil {
+
+		preimage, err := cfg.GeneratePreimage()
+		if err != nil {
+			return nil, nil, err
+		}
+		invoice.Preimage = &preimage
+	}
+
 	paymentPreimage, paymentHash, err := invoice.paymentHashAndPreimage()
 	if err != nil {
 		return nil, nil, err
@@ -364,10 +388,14 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	case invoice.CltvExpiry != 0:
 		// Disallow user-chosen final CLTV deltas below the required
 		// minimum.
-		if invoice.CltvExpiry < routing.MinCLTVDelta {
+		minCLTVExpiry := uint64(routing.MinCLTVDelta)
+		if cfg.MinCLTVExpiry != 0 {
+			minCLTVExpiry = uint64(cfg.MinCLTVExpiry)
+		}
+		if invoice.CltvExpiry < minCLTVExpiry {
 			return nil, nil, fmt.Errorf("CLTV delta of %v must be "+
 				"greater than minimum of %v",
-				invoice.CltvExpiry, routing.MinCLTVDelta)
+				invoice.CltvExpiry, minCLTVExpiry)
 		}
 
 		options = append(options,
@@ -484,6 +512,13 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		return nil, nil, err
 	}
 
+	if cfg.AttributeCreator != nil {
+		err := cfg.AttributeCreator(ctx, paymentHash)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return &paymentHash, newInvoice, nil
 }
 
@@ -4,9 +4,12 @@
 package invoicesrpc
 
 import (
+	"context"
+
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/netann"
@@ -45,6 +48,10 @@ type Config struct {
 	// specified.
 	DefaultCLTVExpiry uint32
 
+	// MinCLTVExpiry is the minimum final CLTV delta a user may choose for
+	// an invoice.
+	MinCLTVExpiry uint32
+
 	// GraphDB is a global database instance which is needed to access the
 	// channel graph.
 	GraphDB *channeldb.ChannelGraph
@@ -64,4 +71,13 @@ type Config struct {
 	// GetAlias returns the peer's alias SCID if it exists given the
 	// 32-byte ChannelID.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// PreimageDeriver derives the preimages of invoices from the seed. It
+	// is nil unless deterministic preimages are enabled.
+	PreimageDeriver *invoices.PreimageDeriver
+
+	// AttributeInvoiceCreator attributes the invoice with the given
+	// payment hash to the macaroon of the request in the given context.
+	AttributeInvoiceCreator func(ctx context.Context,
+		hash lntypes.Hash) error
 }
@@ -39,6 +39,12 @@ type InvoicesClient interface {
 	// LookupInvoiceV2 attempts to look up at invoice. An invoice can be refrenced
 	// using either its payment hash, payment address, or set ID.
 	LookupInvoiceV2(ctx context.Context, in *LookupInvoiceMsg, opts ...grpc.CallOption) (*lnrpc.Invoice, error)
+	// LookupDerivedPreimage searches the preimages that were derived from the
+	// seed for the one that matches the given payment hash, and returns it along
+	// with its preimage index. As the preimages only depend on the seed, this
+	// also works after the invoice database was lost, given a large enough
+	// max_index. Requires the invoices.deterministic-preimages option.
+	LookupDerivedPreimage(ctx context.Context, in *LookupDerivedPreimageRequest, opts ...grpc.CallOption) (*LookupDerivedPreimageResponse, error)
 }
 
 type invoicesClient struct {
@@ -117,6 +123,15 @@ func (c *invoicesClient) LookupInvoiceV2(ctx context.Context, in *LookupInvoiceM
 	return out, nil
 }
 
+func (c *invoicesClient) LookupDerivedPreimage(ctx context.Context, in *LookupDerivedPreimageRequest, opts ...grpc.CallOption) (*LookupDerivedPreimageResponse, error) {
+	out := new(LookupDerivedPreimageResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/LookupDerivedPreimage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InvoicesServer is the server API for Invoices service.
 // All implementations must embed UnimplementedInvoicesServer
 // for forward compatibility
@@ -141,6 +156,12 @@ type InvoicesServer interface {
 	// LookupInvoiceV2 attempts to look up at invoice. An invoice can be refrenced
 	// using either its payment hash, payment address, or set ID.
 	LookupInvoiceV2(context.Context, *LookupInvoiceMsg) (*lnrpc.Invoice, error)
+	// LookupDerivedPreimage searches the preimages that were derived from the
+	// seed for the one that matches the given payment hash, and returns it along
+	// with its preimage index. As the preimages only depend on the seed, this
+	// also works after the invoice database was lost, given a large enough
+	// max_index. Requires the invoices.deterministic-preimages option.
+	LookupDerivedPreimage(context.Context, *LookupDerivedPreimageRequest) (*LookupDerivedPreimageResponse, error)
 	mustEmbedUnimplementedInvoicesServer()
 }
 
@@ -163,6 +184,9 @@ func (UnimplementedInvoicesServer) SettleInvoice(context.Context, *SettleInvoice
 func (UnimplementedInvoicesServer) LookupInvoiceV2(context.Context, *LookupInvoiceMsg) (*lnrpc.Invoice, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LookupInvoiceV2 not implemented")
 }
+func (UnimplementedInvoicesServer) LookupDerivedPreimage(context.Context, *LookupDerivedPreimageRequest) (*LookupDerivedPreimageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupDerivedPreimage not implemented")
+}
 func (UnimplementedInvoicesServer) mustEmbedUnimplementedInvoicesServer() {}
 
 // UnsafeInvoicesServer may be embedded to opt out of forward compatibility for this service.
@@ -269,6 +293,24 @@ func _Invoices_LookupInvoiceV2_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Invoices_LookupDerivedPreimage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupDerivedPreimageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).LookupDerivedPreimage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/LookupDerivedPreimage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).LookupDerivedPreimage(ctx, req.(*LookupDerivedPreimageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Invoices_ServiceDesc is the grpc.ServiceDesc for Invoices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -292,6 +334,10 @@ var Invoices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupInvoiceV2",
 			Handler:    _Invoices_LookupInvoiceV2_Handler,
 		},
+		{
+			MethodName: "LookupDerivedPreimage",
+			Handler:    _Invoices_LookupDerivedPreimage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
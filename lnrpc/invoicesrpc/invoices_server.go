@@ -65,6 +65,10 @@ var (
 			Entity: "invoices",
 			Action: "write",
 		}},
+		"/invoicesrpc.Invoices/LookupDerivedPreimage": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
 	}
 
 	// DefaultInvoicesMacFilename is the default name of the invoices
@@ -329,11 +333,13 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		ChainParams:           s.cfg.ChainParams,
 		NodeSigner:            s.cfg.NodeSigner,
 		DefaultCLTVExpiry:     s.cfg.DefaultCLTVExpiry,
+		MinCLTVExpiry:         s.cfg.MinCLTVExpiry,
 		ChanDB:                s.cfg.ChanStateDB,
 		Graph:                 s.cfg.GraphDB,
 		GenInvoiceFeatures:    s.cfg.GenInvoiceFeatures,
 		GenAmpInvoiceFeatures: s.cfg.GenAmpInvoiceFeatures,
 		GetAlias:              s.cfg.GetAlias,
+		AttributeCreator:      s.cfg.AttributeInvoiceCreator,
 	}
 
 	hash, err := lntypes.MakeHash(invoice.Hash)
@@ -446,3 +452,40 @@ func (s *Server) LookupInvoiceV2(ctx context.Context,
 
 	return CreateRPCInvoice(&invoice, s.cfg.ChainParams)
 }
+
+// LookupDerivedPreimage searches the preimages that were derived from the seed
+// for the one that matches the given payment hash, and returns it along with
+// its preimage index. The preimages with an index below the given max index
+// are searched, or all preimages that were handed out so far if it is zero. As
+// the preimages only depend on the seed, this also works after the invoice
+// database was lost, given a large enough max index.
+func (s *Server) LookupDerivedPreimage(_ context.Context,
+	req *LookupDerivedPreimageRequest) (*LookupDerivedPreimageResponse,
+	error) {
+
+	if s.cfg.PreimageDeriver == nil {
+		return nil, status.Error(
+			codes.FailedPrecondition, "deterministic preimages "+
+				"are not enabled",
+		)
+	}
+
+	hash, err := lntypes.MakeHash(req.PaymentHash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	preimage, index, err := s.cfg.PreimageDeriver.Find(hash, req.MaxIndex)
+	switch {
+	case errors.Is(err, invoices.ErrDerivedPreimageNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &LookupDerivedPreimageResponse{
+		Preimage:      preimage[:],
+		PreimageIndex: index,
+	}, nil
+}
@@ -23,15 +23,31 @@ type LightningClient interface {
 	// confirmed unspent outputs and all unconfirmed unspent outputs under control
 	// of the wallet.
 	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	// lncli: `subscribewalletbalance`
+	// SubscribeWalletBalance sends the on-chain balance of the wallet, broken
+	// down by account into confirmed, unconfirmed, leased and anchor reserve
+	// amounts, and then sends an update whenever it changes. A macaroon that is
+	// bound to an account only sees the balance of that account.
+	SubscribeWalletBalance(ctx context.Context, in *SubscribeWalletBalanceRequest, opts ...grpc.CallOption) (Lightning_SubscribeWalletBalanceClient, error)
 	// lncli: `channelbalance`
 	// ChannelBalance returns a report on the total funds across all open channels,
 	// categorized in local/remote, pending local/remote and unsettled local/remote
 	// balances.
 	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
+	// lncli: `liquidityalerts`
+	// LiquidityAlerts returns the liquidity alerts that are currently raised by
+	// the liquidity monitor. This requires liquidityalert.active to be set.
+	LiquidityAlerts(ctx context.Context, in *LiquidityAlertsRequest, opts ...grpc.CallOption) (*LiquidityAlertsResponse, error)
 	// lncli: `listchaintxns`
 	// GetTransactions returns a list describing all the known transactions
 	// relevant to the wallet.
 	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
+	// lncli: `addressreuse`
+	// AddressReuseReport returns the output scripts that more than one wallet
+	// transaction paid to, in the order they were first paid to. This includes
+	// addresses of the wallet that received funds more than once as well as
+	// foreign addresses the wallet paid to more than once.
+	AddressReuseReport(ctx context.Context, in *AddressReuseReportRequest, opts ...grpc.CallOption) (*AddressReuseReportResponse, error)
 	// lncli: `estimatefee`
 	// EstimateFee asks the chain backend to estimate the fee rate and total fees
 	// for a transaction that pays to multiple specified outputs.
@@ -90,6 +106,14 @@ type LightningClient interface {
 	// given pubKey. In the case that we currently have a pending or active channel
 	// with the target peer, then this action will be not be allowed.
 	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
+	// lncli: `diagnoseconnection`
+	// DiagnoseConnection attempts to connect to a node through the given address,
+	// or through each address the node advertises, and reports the stage each
+	// attempt failed in: resolving the address, the TCP connection, the acts of
+	// the noise handshake or the exchange of init messages. The connections are
+	// closed after the diagnosis. If we are already connected to the node, only
+	// the TCP connection is attempted so the existing connection isn't replaced.
+	DiagnoseConnection(ctx context.Context, in *DiagnoseConnectionRequest, opts ...grpc.CallOption) (*DiagnoseConnectionResponse, error)
 	// lncli: `listpeers`
 	// ListPeers returns a verbose listing of all currently active peers.
 	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
@@ -107,17 +131,71 @@ type LightningClient interface {
 	// and its subsystems. This includes the full configuration and the latest log
 	// entries from the log file.
 	GetDebugInfo(ctx context.Context, in *GetDebugInfoRequest, opts ...grpc.CallOption) (*GetDebugInfoResponse, error)
+	// lncli: `geteffectiveconfig`
+	// GetEffectiveConfig returns the configuration the daemon is running with,
+	// after defaults were applied and the config was validated, with secrets
+	// redacted. Unlike GetDebugInfo, it doesn't read the log file.
+	GetEffectiveConfig(ctx context.Context, in *GetEffectiveConfigRequest, opts ...grpc.CallOption) (*GetEffectiveConfigResponse, error)
+	// lncli: `listdeprecations`
+	// ListDeprecations returns the deprecated RPC methods and the deprecated
+	// request and response fields of all methods, as declared in the proto
+	// definitions. This allows clients to detect their use of deprecated parts
+	// of the API before they are removed.
+	ListDeprecations(ctx context.Context, in *ListDeprecationsRequest, opts ...grpc.CallOption) (*ListDeprecationsResponse, error)
 	// * lncli: `getrecoveryinfo`
 	// GetRecoveryInfo returns information concerning the recovery mode including
 	// whether it's in a recovery mode, whether the recovery is finished, and the
 	// progress made so far.
 	GetRecoveryInfo(ctx context.Context, in *GetRecoveryInfoRequest, opts ...grpc.CallOption) (*GetRecoveryInfoResponse, error)
+	// lncli: `getchaininfo`
+	// GetChainInfo returns how far the chain backend and the node have synced
+	// the chain, including the block lag of the node behind the backend and the
+	// age of its best block.
+	GetChainInfo(ctx context.Context, in *GetChainInfoRequest, opts ...grpc.CallOption) (*GetChainInfoResponse, error)
+	// lncli: `getleadershipstate`
+	// GetLeadershipState returns the leader election state of the node when it
+	// runs as part of a cluster, including the current leader and the fencing
+	// token of the node's leadership term.
+	GetLeadershipState(ctx context.Context, in *GetLeadershipStateRequest, opts ...grpc.CallOption) (*GetLeadershipStateResponse, error)
+	// lncli: `dbusage`
+	// GetDBUsage returns the disk usage of the bolt database files of the node,
+	// broken down by top level bucket. As this reads all of the databases, it
+	// can take a while for large databases. The call fails if the node doesn't
+	// use the bolt database backend.
+	GetDBUsage(ctx context.Context, in *GetDBUsageRequest, opts ...grpc.CallOption) (*GetDBUsageResponse, error)
+	// lncli: `compactdb`
+	// ScheduleDBCompaction requests that the given bolt database files are
+	// compacted on the next restart of the node, which reclaims their free
+	// space. All bolt database files are compacted if none are given.
+	ScheduleDBCompaction(ctx context.Context, in *ScheduleDBCompactionRequest, opts ...grpc.CallOption) (*ScheduleDBCompactionResponse, error)
+	// lncli: `cltvguardstatus`
+	// CltvGuardStatus returns the state of the CLTV guard, including the chain
+	// conditions that currently engage it and the extra CLTV delta it requires
+	// for forwards. This requires cltvguard.active to be set.
+	CltvGuardStatus(ctx context.Context, in *CltvGuardStatusRequest, opts ...grpc.CallOption) (*CltvGuardStatusResponse, error)
+	// lncli: `safemode`
+	// SafeModeStatus returns whether the node is in safe mode, and the failures
+	// of its subsystems that put it into safe mode. In safe mode, the node
+	// neither forwards nor initiates new HTLCs, and doesn't open or cooperatively
+	// close channels, but it keeps resolving existing state.
+	SafeModeStatus(ctx context.Context, in *SafeModeStatusRequest, opts ...grpc.CallOption) (*SafeModeStatusResponse, error)
+	// lncli: `exitsafemode`
+	// ExitSafeMode takes the node out of safe mode, so that it accepts new HTLCs
+	// and channel operations again. Subsystems that still fail put the node back
+	// into safe mode once their failure is detected again.
+	ExitSafeMode(ctx context.Context, in *ExitSafeModeRequest, opts ...grpc.CallOption) (*ExitSafeModeResponse, error)
 	// lncli: `pendingchannels`
 	// PendingChannels returns a list of all the channels that are currently
 	// considered "pending". A channel is pending if it has finished the funding
 	// workflow and is waiting for confirmations for the funding txn, or is in the
 	// process of closure, either initiated cooperatively or non-cooperatively.
 	PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error)
+	// PendingChannelsPage returns a page of the channels that are pending open
+	// or close. The force closed channels that are still being resolved are
+	// paged by their close height, and can be filtered by their close height
+	// and peer. Channels pending open or waiting for their closing transaction
+	// to confirm are only returned with the first page, filtered by peer.
+	PendingChannelsPage(ctx context.Context, in *PendingChannelsPageRequest, opts ...grpc.CallOption) (*PendingChannelsPageResponse, error)
 	// lncli: `listchannels`
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
@@ -131,6 +209,23 @@ type LightningClient interface {
 	// ClosedChannels returns a description of all the closed channels that
 	// this node was a participant in.
 	ClosedChannels(ctx context.Context, in *ClosedChannelsRequest, opts ...grpc.CallOption) (*ClosedChannelsResponse, error)
+	// ClosedChannelsPage returns a page of the channels that have been closed,
+	// ordered by their close height. Besides the close type filters, the
+	// channels can be filtered by their close height and peer. Unlike
+	// ClosedChannels, this doesn't load all closed channels at once.
+	ClosedChannelsPage(ctx context.Context, in *ClosedChannelsPageRequest, opts ...grpc.CallOption) (*ClosedChannelsPageResponse, error)
+	// lncli: `closehistory`
+	// ChannelCloseHistory returns who initiated the close of all closed channels
+	// and why, ordered by their close height. For channels closed before close
+	// reasons were recorded, the reason is derived from the close type as far as
+	// possible.
+	ChannelCloseHistory(ctx context.Context, in *ChannelCloseHistoryRequest, opts ...grpc.CallOption) (*ChannelCloseHistoryResponse, error)
+	// AnchorOwnershipProofs returns signed proofs that tie the anchor outputs of
+	// our channels to our node, so auditors can attribute the anchors and their
+	// sweeps on-chain without access to the node database. Each proof is signed
+	// by both the node key and the funding key of the channel. Taproot channels
+	// aren't supported, as their anchor scripts change with every commitment.
+	AnchorOwnershipProofs(ctx context.Context, in *AnchorOwnershipProofsRequest, opts ...grpc.CallOption) (*AnchorOwnershipProofsResponse, error)
 	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
 	// call is meant to be consumed by clients to the REST proxy. As with all
 	// other sync calls, all byte slices are intended to be populated as hex
@@ -153,6 +248,14 @@ type LightningClient interface {
 	// This is the safer variant of using PSBTs to manually fund a batch of
 	// channels through the OpenChannel RPC.
 	BatchOpenChannel(ctx context.Context, in *BatchOpenChannelRequest, opts ...grpc.CallOption) (*BatchOpenChannelResponse, error)
+	// lncli: `openchannel --check`
+	// CheckOpenChannel checks an OpenChannelRequest without opening the channel.
+	// It reports everything that is known to make the channel opening fail: the
+	// state of the node, invalid parameters, a disconnected peer, features the
+	// peer doesn't support, insufficient wallet funds including the funding fee
+	// and the anchor reserve, and pending channels or channel acceptor policies
+	// the peer may reject the channel for.
+	CheckOpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*CheckOpenChannelResponse, error)
 	// FundingStateStep is an advanced funding related call that allows the caller
 	// to either execute some preparatory steps for a funding workflow, or
 	// manually progress a funding workflow. The primary way a funding flow is
@@ -162,6 +265,31 @@ type LightningClient interface {
 	// Alternatively, this can be used to interactively drive PSBT signing for
 	// funding for partially complete funding transactions.
 	FundingStateStep(ctx context.Context, in *FundingTransitionMsg, opts ...grpc.CallOption) (*FundingStateStepResp, error)
+	// lncli: `registerfundingcontributors`
+	// RegisterFundingContributors registers the contributors whose inputs fund
+	// the channel with the given pending channel ID, which must already have a
+	// PSBT funding intent. The base PSBT must contain the channel funding output.
+	// If not all contributors signed their inputs within the timeout, the funding
+	// intent is canceled.
+	RegisterFundingContributors(ctx context.Context, in *RegisterFundingContributorsRequest, opts ...grpc.CallOption) (*RegisterFundingContributorsResponse, error)
+	// lncli: `contributefunding`
+	// ContributeFunding adds the inputs and optional change outputs of the given
+	// PSBT to the funding transaction of the channel with the given pending
+	// channel ID on behalf of the given contributor. Once all contributors added
+	// their inputs, the funded PSBT is verified and can be fetched with
+	// FundingContributionStatus for signing.
+	ContributeFunding(ctx context.Context, in *ContributeFundingRequest, opts ...grpc.CallOption) (*ContributeFundingResponse, error)
+	// lncli: `signfundingcontribution`
+	// SignFundingContribution adds the signed inputs of the given contributor
+	// from the given funded PSBT to the funding transaction of the channel with
+	// the given pending channel ID. Once all contributors signed their inputs,
+	// the funding of the channel is finalized.
+	SignFundingContribution(ctx context.Context, in *SignFundingContributionRequest, opts ...grpc.CallOption) (*SignFundingContributionResponse, error)
+	// lncli: `fundingcontributionstatus`
+	// FundingContributionStatus returns the state of the contributors to the
+	// funding transaction of the channel with the given pending channel ID, and
+	// the funded PSBT once all of them added their inputs.
+	FundingContributionStatus(ctx context.Context, in *FundingContributionStatusRequest, opts ...grpc.CallOption) (*FundingContributionStatusResponse, error)
 	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	// OpenChannel requests are sent to the client and the client responds with
 	// a boolean that tells LND whether or not to accept the channel. This allows
@@ -177,6 +305,11 @@ type LightningClient interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// lncli: `bumpclosefee`
+	// BumpCoopCloseFee bumps the fee of the pending RBF cooperative close
+	// transaction of a channel we closed. This requires the rbf-coop-close
+	// protocol option to be set on both peers, and the peer to be connected.
+	BumpCoopCloseFee(ctx context.Context, in *BumpCoopCloseFeeRequest, opts ...grpc.CallOption) (*BumpCoopCloseFeeResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -276,6 +409,18 @@ type LightningClient interface {
 	// GetNodeInfo returns the latest advertised, aggregated, and authenticated
 	// channel information for the specified node identified by its public key.
 	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	// lncli: `getnodemetadata`
+	// GetNodeMetadata returns the operator information records the node with the
+	// given public key advertises in its node announcement. The raw records are
+	// also part of the custom records returned by GetNodeInfo.
+	GetNodeMetadata(ctx context.Context, in *NodeMetadataRequest, opts ...grpc.CallOption) (*NodeMetadata, error)
+	// lncli: `getnodecapabilities`
+	// GetNodeCapabilities returns the capabilities the node with the given public
+	// key advertises, such as support for onion messages and route blinding. If
+	// requested, the relay statistics we measured locally for the node are
+	// included as well, which helps when selecting introduction nodes for reply
+	// paths.
+	GetNodeCapabilities(ctx context.Context, in *NodeCapabilitiesRequest, opts ...grpc.CallOption) (*NodeCapabilities, error)
 	// lncli: `queryroutes`
 	// QueryRoutes attempts to query the daemon's Channel Router for a possible
 	// route to a target destination capable of carrying a specific amount of
@@ -309,6 +454,11 @@ type LightningClient interface {
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	// lncli: `reloadconfig`
+	// ReloadConfig reloads the config file and applies the changed options that
+	// can be changed without a restart, just like on SIGHUP. The response lists
+	// which of the changed options were applied and which were rejected.
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
 	// lncli: `feereport`
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
@@ -330,6 +480,30 @@ type LightningClient interface {
 	// the index offset of the last entry. The index offset can be provided to the
 	// request to allow the caller to skip a series of records.
 	ForwardingHistory(ctx context.Context, in *ForwardingHistoryRequest, opts ...grpc.CallOption) (*ForwardingHistoryResponse, error)
+	// lncli: `exportledger`
+	// ExportLedger exports the on-chain and off-chain activity of the node as one
+	// ledger in CSV or JSON: the transactions of the wallet, settled invoices,
+	// succeeded payments and the fees earned by forwards. All entries share the
+	// same amount, fee and timestamp semantics. Large ranges can be exported in
+	// pages by passing the returned next_offset as the offset of the next request
+	// with the same time range.
+	ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (*ExportLedgerResponse, error)
+	// lncli: `holdfeestatus`
+	// HoldFeeStatus returns the statistics of the hold fee policy, including its
+	// most recent decisions. In dry-run mode, they show the effect the policy
+	// would have on forwards if it was enforced.
+	HoldFeeStatus(ctx context.Context, in *HoldFeeStatusRequest, opts ...grpc.CallOption) (*HoldFeeStatusResponse, error)
+	// lncli: `simulateholdfee`
+	// SimulateHoldFee evaluates the given hypothetical forwards against the
+	// configured hold fee policy without recording the decisions. It works while
+	// the policy isn't active, so its parameters can be tuned before enabling
+	// it.
+	SimulateHoldFee(ctx context.Context, in *SimulateHoldFeeRequest, opts ...grpc.CallOption) (*SimulateHoldFeeResponse, error)
+	// lncli: `listjammingincidents`
+	// ListJammingIncidents returns the most recent suspected channel jamming
+	// incidents, newest first, including whether forwards from their incoming
+	// channels are currently refused.
+	ListJammingIncidents(ctx context.Context, in *ListJammingIncidentsRequest, opts ...grpc.CallOption) (*ListJammingIncidentsResponse, error)
 	// lncli: `exportchanbackup`
 	// ExportChannelBackup attempts to return an encrypted static channel backup
 	// for the target channel identified by it channel point. The backup is
@@ -407,6 +581,13 @@ type LightningClient interface {
 	// needs to be compiled with  the `dev` build tag, and the message type to
 	// override should be specified in lnd's experimental protocol configuration.
 	SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error)
+	// SendOnionMessage sends an onion message over a blinded path. The message
+	// is handed to the introduction node of the path, which must be a connected
+	// peer that supports onion messages. Requires protocol.onion-messages.
+	SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error)
+	// SubscribeOnionMessages subscribes to a stream of the onion messages that
+	// are delivered to our node. Requires protocol.onion-messages.
+	SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error)
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -435,6 +616,38 @@ func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRe
 	return out, nil
 }
 
+func (c *lightningClient) SubscribeWalletBalance(ctx context.Context, in *SubscribeWalletBalanceRequest, opts ...grpc.CallOption) (Lightning_SubscribeWalletBalanceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[0], "/lnrpc.Lightning/SubscribeWalletBalance", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeWalletBalanceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeWalletBalanceClient interface {
+	Recv() (*WalletBalanceUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeWalletBalanceClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeWalletBalanceClient) Recv() (*WalletBalanceUpdate, error) {
+	m := new(WalletBalanceUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
 	out := new(ChannelBalanceResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, opts...)
@@ -444,6 +657,15 @@ func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalance
 	return out, nil
 }
 
+func (c *lightningClient) LiquidityAlerts(ctx context.Context, in *LiquidityAlertsRequest, opts ...grpc.CallOption) (*LiquidityAlertsResponse, error) {
+	out := new(LiquidityAlertsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/LiquidityAlerts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error) {
 	out := new(TransactionDetails)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetTransactions", in, out, opts...)
@@ -453,6 +675,15 @@ func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactio
 	return out, nil
 }
 
+func (c *lightningClient) AddressReuseReport(ctx context.Context, in *AddressReuseReportRequest, opts ...grpc.CallOption) (*AddressReuseReportResponse, error) {
+	out := new(AddressReuseReportResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AddressReuseReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
 	out := new(EstimateFeeResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/EstimateFee", in, out, opts...)
@@ -481,7 +712,7 @@ func (c *lightningClient) ListUnspent(ctx context.Context, in *ListUnspentReques
 }
 
 func (c *lightningClient) SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[0], "/lnrpc.Lightning/SubscribeTransactions", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[1], "/lnrpc.Lightning/SubscribeTransactions", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -566,6 +797,15 @@ func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeer
 	return out, nil
 }
 
+func (c *lightningClient) DiagnoseConnection(ctx context.Context, in *DiagnoseConnectionRequest, opts ...grpc.CallOption) (*DiagnoseConnectionResponse, error) {
+	out := new(DiagnoseConnectionResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DiagnoseConnection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
 	out := new(ListPeersResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, opts...)
@@ -576,7 +816,7 @@ func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, o
 }
 
 func (c *lightningClient) SubscribePeerEvents(ctx context.Context, in *PeerEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribePeerEventsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[1], "/lnrpc.Lightning/SubscribePeerEvents", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[2], "/lnrpc.Lightning/SubscribePeerEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -625,6 +865,24 @@ func (c *lightningClient) GetDebugInfo(ctx context.Context, in *GetDebugInfoRequ
 	return out, nil
 }
 
+func (c *lightningClient) GetEffectiveConfig(ctx context.Context, in *GetEffectiveConfigRequest, opts ...grpc.CallOption) (*GetEffectiveConfigResponse, error) {
+	out := new(GetEffectiveConfigResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetEffectiveConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListDeprecations(ctx context.Context, in *ListDeprecationsRequest, opts ...grpc.CallOption) (*ListDeprecationsResponse, error) {
+	out := new(ListDeprecationsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListDeprecations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetRecoveryInfo(ctx context.Context, in *GetRecoveryInfoRequest, opts ...grpc.CallOption) (*GetRecoveryInfoResponse, error) {
 	out := new(GetRecoveryInfoResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetRecoveryInfo", in, out, opts...)
@@ -634,6 +892,69 @@ func (c *lightningClient) GetRecoveryInfo(ctx context.Context, in *GetRecoveryIn
 	return out, nil
 }
 
+func (c *lightningClient) GetChainInfo(ctx context.Context, in *GetChainInfoRequest, opts ...grpc.CallOption) (*GetChainInfoResponse, error) {
+	out := new(GetChainInfoResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetChainInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetLeadershipState(ctx context.Context, in *GetLeadershipStateRequest, opts ...grpc.CallOption) (*GetLeadershipStateResponse, error) {
+	out := new(GetLeadershipStateResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetLeadershipState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetDBUsage(ctx context.Context, in *GetDBUsageRequest, opts ...grpc.CallOption) (*GetDBUsageResponse, error) {
+	out := new(GetDBUsageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetDBUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ScheduleDBCompaction(ctx context.Context, in *ScheduleDBCompactionRequest, opts ...grpc.CallOption) (*ScheduleDBCompactionResponse, error) {
+	out := new(ScheduleDBCompactionResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ScheduleDBCompaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) CltvGuardStatus(ctx context.Context, in *CltvGuardStatusRequest, opts ...grpc.CallOption) (*CltvGuardStatusResponse, error) {
+	out := new(CltvGuardStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CltvGuardStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SafeModeStatus(ctx context.Context, in *SafeModeStatusRequest, opts ...grpc.CallOption) (*SafeModeStatusResponse, error) {
+	out := new(SafeModeStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SafeModeStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExitSafeMode(ctx context.Context, in *ExitSafeModeRequest, opts ...grpc.CallOption) (*ExitSafeModeResponse, error) {
+	out := new(ExitSafeModeResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExitSafeMode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error) {
 	out := new(PendingChannelsResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, opts...)
@@ -643,6 +964,15 @@ func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChanne
 	return out, nil
 }
 
+func (c *lightningClient) PendingChannelsPage(ctx context.Context, in *PendingChannelsPageRequest, opts ...grpc.CallOption) (*PendingChannelsPageResponse, error) {
+	out := new(PendingChannelsPageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/PendingChannelsPage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
 	out := new(ListChannelsResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, opts...)
@@ -653,7 +983,7 @@ func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequ
 }
 
 func (c *lightningClient) SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[2], "/lnrpc.Lightning/SubscribeChannelEvents", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/SubscribeChannelEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -693,6 +1023,33 @@ func (c *lightningClient) ClosedChannels(ctx context.Context, in *ClosedChannels
 	return out, nil
 }
 
+func (c *lightningClient) ClosedChannelsPage(ctx context.Context, in *ClosedChannelsPageRequest, opts ...grpc.CallOption) (*ClosedChannelsPageResponse, error) {
+	out := new(ClosedChannelsPageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ClosedChannelsPage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelCloseHistory(ctx context.Context, in *ChannelCloseHistoryRequest, opts ...grpc.CallOption) (*ChannelCloseHistoryResponse, error) {
+	out := new(ChannelCloseHistoryResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ChannelCloseHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AnchorOwnershipProofs(ctx context.Context, in *AnchorOwnershipProofsRequest, opts ...grpc.CallOption) (*AnchorOwnershipProofsResponse, error) {
+	out := new(AnchorOwnershipProofsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AnchorOwnershipProofs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
 	out := new(ChannelPoint)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/OpenChannelSync", in, out, opts...)
@@ -703,7 +1060,7 @@ func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRe
 }
 
 func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/OpenChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/OpenChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -743,6 +1100,15 @@ func (c *lightningClient) BatchOpenChannel(ctx context.Context, in *BatchOpenCha
 	return out, nil
 }
 
+func (c *lightningClient) CheckOpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*CheckOpenChannelResponse, error) {
+	out := new(CheckOpenChannelResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CheckOpenChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTransitionMsg, opts ...grpc.CallOption) (*FundingStateStepResp, error) {
 	out := new(FundingStateStepResp)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/FundingStateStep", in, out, opts...)
@@ -752,8 +1118,44 @@ func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTrans
 	return out, nil
 }
 
+func (c *lightningClient) RegisterFundingContributors(ctx context.Context, in *RegisterFundingContributorsRequest, opts ...grpc.CallOption) (*RegisterFundingContributorsResponse, error) {
+	out := new(RegisterFundingContributorsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/RegisterFundingContributors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ContributeFunding(ctx context.Context, in *ContributeFundingRequest, opts ...grpc.CallOption) (*ContributeFundingResponse, error) {
+	out := new(ContributeFundingResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ContributeFunding", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SignFundingContribution(ctx context.Context, in *SignFundingContributionRequest, opts ...grpc.CallOption) (*SignFundingContributionResponse, error) {
+	out := new(SignFundingContributionResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SignFundingContribution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FundingContributionStatus(ctx context.Context, in *FundingContributionStatusRequest, opts ...grpc.CallOption) (*FundingContributionStatusResponse, error) {
+	out := new(FundingContributionStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/FundingContributionStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/ChannelAcceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/ChannelAcceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -784,7 +1186,7 @@ func (x *lightningChannelAcceptorClient) Recv() (*ChannelAcceptRequest, error) {
 }
 
 func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/CloseChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/CloseChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -815,6 +1217,15 @@ func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
 	return m, nil
 }
 
+func (c *lightningClient) BumpCoopCloseFee(ctx context.Context, in *BumpCoopCloseFeeRequest, opts ...grpc.CallOption) (*BumpCoopCloseFeeResponse, error) {
+	out := new(BumpCoopCloseFeeResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BumpCoopCloseFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
 	out := new(AbandonChannelResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, opts...)
@@ -826,7 +1237,7 @@ func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannel
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -867,7 +1278,7 @@ func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest,
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendToRoute(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendToRouteClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendToRoute", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SendToRoute", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -934,7 +1345,7 @@ func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, op
 }
 
 func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeInvoices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1037,6 +1448,24 @@ func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest,
 	return out, nil
 }
 
+func (c *lightningClient) GetNodeMetadata(ctx context.Context, in *NodeMetadataRequest, opts ...grpc.CallOption) (*NodeMetadata, error) {
+	out := new(NodeMetadata)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetNodeMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNodeCapabilities(ctx context.Context, in *NodeCapabilitiesRequest, opts ...grpc.CallOption) (*NodeCapabilities, error) {
+	out := new(NodeCapabilities)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetNodeCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error) {
 	out := new(QueryRoutesResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/QueryRoutes", in, out, opts...)
@@ -1065,7 +1494,7 @@ func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts
 }
 
 func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1105,6 +1534,15 @@ func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest,
 	return out, nil
 }
 
+func (c *lightningClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
 	out := new(FeeReportResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, opts...)
@@ -1132,6 +1570,42 @@ func (c *lightningClient) ForwardingHistory(ctx context.Context, in *ForwardingH
 	return out, nil
 }
 
+func (c *lightningClient) ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (*ExportLedgerResponse, error) {
+	out := new(ExportLedgerResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportLedger", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) HoldFeeStatus(ctx context.Context, in *HoldFeeStatusRequest, opts ...grpc.CallOption) (*HoldFeeStatusResponse, error) {
+	out := new(HoldFeeStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/HoldFeeStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SimulateHoldFee(ctx context.Context, in *SimulateHoldFeeRequest, opts ...grpc.CallOption) (*SimulateHoldFeeResponse, error) {
+	out := new(SimulateHoldFeeResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SimulateHoldFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListJammingIncidents(ctx context.Context, in *ListJammingIncidentsRequest, opts ...grpc.CallOption) (*ListJammingIncidentsResponse, error) {
+	out := new(ListJammingIncidentsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListJammingIncidents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ExportChannelBackup(ctx context.Context, in *ExportChannelBackupRequest, opts ...grpc.CallOption) (*ChannelBackup, error) {
 	out := new(ChannelBackup)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportChannelBackup", in, out, opts...)
@@ -1169,7 +1643,7 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 }
 
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1246,7 +1720,7 @@ func (c *lightningClient) CheckMacaroonPermissions(ctx context.Context, in *Chec
 }
 
 func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1286,7 +1760,7 @@ func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomM
 }
 
 func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1317,6 +1791,47 @@ func (x *lightningSubscribeCustomMessagesClient) Recv() (*CustomMessage, error)
 	return m, nil
 }
 
+func (c *lightningClient) SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error) {
+	out := new(SendOnionMessageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SendOnionMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[14], "/lnrpc.Lightning/SubscribeOnionMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeOnionMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeOnionMessagesClient interface {
+	Recv() (*OnionMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeOnionMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeOnionMessagesClient) Recv() (*OnionMessage, error) {
+	m := new(OnionMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *lightningClient) ListAliases(ctx context.Context, in *ListAliasesRequest, opts ...grpc.CallOption) (*ListAliasesResponse, error) {
 	out := new(ListAliasesResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListAliases", in, out, opts...)
@@ -1344,15 +1859,31 @@ type LightningServer interface {
 	// confirmed unspent outputs and all unconfirmed unspent outputs under control
 	// of the wallet.
 	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
+	// lncli: `subscribewalletbalance`
+	// SubscribeWalletBalance sends the on-chain balance of the wallet, broken
+	// down by account into confirmed, unconfirmed, leased and anchor reserve
+	// amounts, and then sends an update whenever it changes. A macaroon that is
+	// bound to an account only sees the balance of that account.
+	SubscribeWalletBalance(*SubscribeWalletBalanceRequest, Lightning_SubscribeWalletBalanceServer) error
 	// lncli: `channelbalance`
 	// ChannelBalance returns a report on the total funds across all open channels,
 	// categorized in local/remote, pending local/remote and unsettled local/remote
 	// balances.
 	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
+	// lncli: `liquidityalerts`
+	// LiquidityAlerts returns the liquidity alerts that are currently raised by
+	// the liquidity monitor. This requires liquidityalert.active to be set.
+	LiquidityAlerts(context.Context, *LiquidityAlertsRequest) (*LiquidityAlertsResponse, error)
 	// lncli: `listchaintxns`
 	// GetTransactions returns a list describing all the known transactions
 	// relevant to the wallet.
 	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error)
+	// lncli: `addressreuse`
+	// AddressReuseReport returns the output scripts that more than one wallet
+	// transaction paid to, in the order they were first paid to. This includes
+	// addresses of the wallet that received funds more than once as well as
+	// foreign addresses the wallet paid to more than once.
+	AddressReuseReport(context.Context, *AddressReuseReportRequest) (*AddressReuseReportResponse, error)
 	// lncli: `estimatefee`
 	// EstimateFee asks the chain backend to estimate the fee rate and total fees
 	// for a transaction that pays to multiple specified outputs.
@@ -1411,6 +1942,14 @@ type LightningServer interface {
 	// given pubKey. In the case that we currently have a pending or active channel
 	// with the target peer, then this action will be not be allowed.
 	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
+	// lncli: `diagnoseconnection`
+	// DiagnoseConnection attempts to connect to a node through the given address,
+	// or through each address the node advertises, and reports the stage each
+	// attempt failed in: resolving the address, the TCP connection, the acts of
+	// the noise handshake or the exchange of init messages. The connections are
+	// closed after the diagnosis. If we are already connected to the node, only
+	// the TCP connection is attempted so the existing connection isn't replaced.
+	DiagnoseConnection(context.Context, *DiagnoseConnectionRequest) (*DiagnoseConnectionResponse, error)
 	// lncli: `listpeers`
 	// ListPeers returns a verbose listing of all currently active peers.
 	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
@@ -1428,17 +1967,71 @@ type LightningServer interface {
 	// and its subsystems. This includes the full configuration and the latest log
 	// entries from the log file.
 	GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error)
+	// lncli: `geteffectiveconfig`
+	// GetEffectiveConfig returns the configuration the daemon is running with,
+	// after defaults were applied and the config was validated, with secrets
+	// redacted. Unlike GetDebugInfo, it doesn't read the log file.
+	GetEffectiveConfig(context.Context, *GetEffectiveConfigRequest) (*GetEffectiveConfigResponse, error)
+	// lncli: `listdeprecations`
+	// ListDeprecations returns the deprecated RPC methods and the deprecated
+	// request and response fields of all methods, as declared in the proto
+	// definitions. This allows clients to detect their use of deprecated parts
+	// of the API before they are removed.
+	ListDeprecations(context.Context, *ListDeprecationsRequest) (*ListDeprecationsResponse, error)
 	// * lncli: `getrecoveryinfo`
 	// GetRecoveryInfo returns information concerning the recovery mode including
 	// whether it's in a recovery mode, whether the recovery is finished, and the
 	// progress made so far.
 	GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error)
+	// lncli: `getchaininfo`
+	// GetChainInfo returns how far the chain backend and the node have synced
+	// the chain, including the block lag of the node behind the backend and the
+	// age of its best block.
+	GetChainInfo(context.Context, *GetChainInfoRequest) (*GetChainInfoResponse, error)
+	// lncli: `getleadershipstate`
+	// GetLeadershipState returns the leader election state of the node when it
+	// runs as part of a cluster, including the current leader and the fencing
+	// token of the node's leadership term.
+	GetLeadershipState(context.Context, *GetLeadershipStateRequest) (*GetLeadershipStateResponse, error)
+	// lncli: `dbusage`
+	// GetDBUsage returns the disk usage of the bolt database files of the node,
+	// broken down by top level bucket. As this reads all of the databases, it
+	// can take a while for large databases. The call fails if the node doesn't
+	// use the bolt database backend.
+	GetDBUsage(context.Context, *GetDBUsageRequest) (*GetDBUsageResponse, error)
+	// lncli: `compactdb`
+	// ScheduleDBCompaction requests that the given bolt database files are
+	// compacted on the next restart of the node, which reclaims their free
+	// space. All bolt database files are compacted if none are given.
+	ScheduleDBCompaction(context.Context, *ScheduleDBCompactionRequest) (*ScheduleDBCompactionResponse, error)
+	// lncli: `cltvguardstatus`
+	// CltvGuardStatus returns the state of the CLTV guard, including the chain
+	// conditions that currently engage it and the extra CLTV delta it requires
+	// for forwards. This requires cltvguard.active to be set.
+	CltvGuardStatus(context.Context, *CltvGuardStatusRequest) (*CltvGuardStatusResponse, error)
+	// lncli: `safemode`
+	// SafeModeStatus returns whether the node is in safe mode, and the failures
+	// of its subsystems that put it into safe mode. In safe mode, the node
+	// neither forwards nor initiates new HTLCs, and doesn't open or cooperatively
+	// close channels, but it keeps resolving existing state.
+	SafeModeStatus(context.Context, *SafeModeStatusRequest) (*SafeModeStatusResponse, error)
+	// lncli: `exitsafemode`
+	// ExitSafeMode takes the node out of safe mode, so that it accepts new HTLCs
+	// and channel operations again. Subsystems that still fail put the node back
+	// into safe mode once their failure is detected again.
+	ExitSafeMode(context.Context, *ExitSafeModeRequest) (*ExitSafeModeResponse, error)
 	// lncli: `pendingchannels`
 	// PendingChannels returns a list of all the channels that are currently
 	// considered "pending". A channel is pending if it has finished the funding
 	// workflow and is waiting for confirmations for the funding txn, or is in the
 	// process of closure, either initiated cooperatively or non-cooperatively.
 	PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error)
+	// PendingChannelsPage returns a page of the channels that are pending open
+	// or close. The force closed channels that are still being resolved are
+	// paged by their close height, and can be filtered by their close height
+	// and peer. Channels pending open or waiting for their closing transaction
+	// to confirm are only returned with the first page, filtered by peer.
+	PendingChannelsPage(context.Context, *PendingChannelsPageRequest) (*PendingChannelsPageResponse, error)
 	// lncli: `listchannels`
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
@@ -1452,6 +2045,23 @@ type LightningServer interface {
 	// ClosedChannels returns a description of all the closed channels that
 	// this node was a participant in.
 	ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error)
+	// ClosedChannelsPage returns a page of the channels that have been closed,
+	// ordered by their close height. Besides the close type filters, the
+	// channels can be filtered by their close height and peer. Unlike
+	// ClosedChannels, this doesn't load all closed channels at once.
+	ClosedChannelsPage(context.Context, *ClosedChannelsPageRequest) (*ClosedChannelsPageResponse, error)
+	// lncli: `closehistory`
+	// ChannelCloseHistory returns who initiated the close of all closed channels
+	// and why, ordered by their close height. For channels closed before close
+	// reasons were recorded, the reason is derived from the close type as far as
+	// possible.
+	ChannelCloseHistory(context.Context, *ChannelCloseHistoryRequest) (*ChannelCloseHistoryResponse, error)
+	// AnchorOwnershipProofs returns signed proofs that tie the anchor outputs of
+	// our channels to our node, so auditors can attribute the anchors and their
+	// sweeps on-chain without access to the node database. Each proof is signed
+	// by both the node key and the funding key of the channel. Taproot channels
+	// aren't supported, as their anchor scripts change with every commitment.
+	AnchorOwnershipProofs(context.Context, *AnchorOwnershipProofsRequest) (*AnchorOwnershipProofsResponse, error)
 	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
 	// call is meant to be consumed by clients to the REST proxy. As with all
 	// other sync calls, all byte slices are intended to be populated as hex
@@ -1474,6 +2084,14 @@ type LightningServer interface {
 	// This is the safer variant of using PSBTs to manually fund a batch of
 	// channels through the OpenChannel RPC.
 	BatchOpenChannel(context.Context, *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error)
+	// lncli: `openchannel --check`
+	// CheckOpenChannel checks an OpenChannelRequest without opening the channel.
+	// It reports everything that is known to make the channel opening fail: the
+	// state of the node, invalid parameters, a disconnected peer, features the
+	// peer doesn't support, insufficient wallet funds including the funding fee
+	// and the anchor reserve, and pending channels or channel acceptor policies
+	// the peer may reject the channel for.
+	CheckOpenChannel(context.Context, *OpenChannelRequest) (*CheckOpenChannelResponse, error)
 	// FundingStateStep is an advanced funding related call that allows the caller
 	// to either execute some preparatory steps for a funding workflow, or
 	// manually progress a funding workflow. The primary way a funding flow is
@@ -1483,6 +2101,31 @@ type LightningServer interface {
 	// Alternatively, this can be used to interactively drive PSBT signing for
 	// funding for partially complete funding transactions.
 	FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error)
+	// lncli: `registerfundingcontributors`
+	// RegisterFundingContributors registers the contributors whose inputs fund
+	// the channel with the given pending channel ID, which must already have a
+	// PSBT funding intent. The base PSBT must contain the channel funding output.
+	// If not all contributors signed their inputs within the timeout, the funding
+	// intent is canceled.
+	RegisterFundingContributors(context.Context, *RegisterFundingContributorsRequest) (*RegisterFundingContributorsResponse, error)
+	// lncli: `contributefunding`
+	// ContributeFunding adds the inputs and optional change outputs of the given
+	// PSBT to the funding transaction of the channel with the given pending
+	// channel ID on behalf of the given contributor. Once all contributors added
+	// their inputs, the funded PSBT is verified and can be fetched with
+	// FundingContributionStatus for signing.
+	ContributeFunding(context.Context, *ContributeFundingRequest) (*ContributeFundingResponse, error)
+	// lncli: `signfundingcontribution`
+	// SignFundingContribution adds the signed inputs of the given contributor
+	// from the given funded PSBT to the funding transaction of the channel with
+	// the given pending channel ID. Once all contributors signed their inputs,
+	// the funding of the channel is finalized.
+	SignFundingContribution(context.Context, *SignFundingContributionRequest) (*SignFundingContributionResponse, error)
+	// lncli: `fundingcontributionstatus`
+	// FundingContributionStatus returns the state of the contributors to the
+	// funding transaction of the channel with the given pending channel ID, and
+	// the funded PSBT once all of them added their inputs.
+	FundingContributionStatus(context.Context, *FundingContributionStatusRequest) (*FundingContributionStatusResponse, error)
 	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	// OpenChannel requests are sent to the client and the client responds with
 	// a boolean that tells LND whether or not to accept the channel. This allows
@@ -1498,6 +2141,11 @@ type LightningServer interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	// lncli: `bumpclosefee`
+	// BumpCoopCloseFee bumps the fee of the pending RBF cooperative close
+	// transaction of a channel we closed. This requires the rbf-coop-close
+	// protocol option to be set on both peers, and the peer to be connected.
+	BumpCoopCloseFee(context.Context, *BumpCoopCloseFeeRequest) (*BumpCoopCloseFeeResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -1597,6 +2245,18 @@ type LightningServer interface {
 	// GetNodeInfo returns the latest advertised, aggregated, and authenticated
 	// channel information for the specified node identified by its public key.
 	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
+	// lncli: `getnodemetadata`
+	// GetNodeMetadata returns the operator information records the node with the
+	// given public key advertises in its node announcement. The raw records are
+	// also part of the custom records returned by GetNodeInfo.
+	GetNodeMetadata(context.Context, *NodeMetadataRequest) (*NodeMetadata, error)
+	// lncli: `getnodecapabilities`
+	// GetNodeCapabilities returns the capabilities the node with the given public
+	// key advertises, such as support for onion messages and route blinding. If
+	// requested, the relay statistics we measured locally for the node are
+	// included as well, which helps when selecting introduction nodes for reply
+	// paths.
+	GetNodeCapabilities(context.Context, *NodeCapabilitiesRequest) (*NodeCapabilities, error)
 	// lncli: `queryroutes`
 	// QueryRoutes attempts to query the daemon's Channel Router for a possible
 	// route to a target destination capable of carrying a specific amount of
@@ -1630,6 +2290,11 @@ type LightningServer interface {
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+	// lncli: `reloadconfig`
+	// ReloadConfig reloads the config file and applies the changed options that
+	// can be changed without a restart, just like on SIGHUP. The response lists
+	// which of the changed options were applied and which were rejected.
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
 	// lncli: `feereport`
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
@@ -1651,6 +2316,30 @@ type LightningServer interface {
 	// the index offset of the last entry. The index offset can be provided to the
 	// request to allow the caller to skip a series of records.
 	ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error)
+	// lncli: `exportledger`
+	// ExportLedger exports the on-chain and off-chain activity of the node as one
+	// ledger in CSV or JSON: the transactions of the wallet, settled invoices,
+	// succeeded payments and the fees earned by forwards. All entries share the
+	// same amount, fee and timestamp semantics. Large ranges can be exported in
+	// pages by passing the returned next_offset as the offset of the next request
+	// with the same time range.
+	ExportLedger(context.Context, *ExportLedgerRequest) (*ExportLedgerResponse, error)
+	// lncli: `holdfeestatus`
+	// HoldFeeStatus returns the statistics of the hold fee policy, including its
+	// most recent decisions. In dry-run mode, they show the effect the policy
+	// would have on forwards if it was enforced.
+	HoldFeeStatus(context.Context, *HoldFeeStatusRequest) (*HoldFeeStatusResponse, error)
+	// lncli: `simulateholdfee`
+	// SimulateHoldFee evaluates the given hypothetical forwards against the
+	// configured hold fee policy without recording the decisions. It works while
+	// the policy isn't active, so its parameters can be tuned before enabling
+	// it.
+	SimulateHoldFee(context.Context, *SimulateHoldFeeRequest) (*SimulateHoldFeeResponse, error)
+	// lncli: `listjammingincidents`
+	// ListJammingIncidents returns the most recent suspected channel jamming
+	// incidents, newest first, including whether forwards from their incoming
+	// channels are currently refused.
+	ListJammingIncidents(context.Context, *ListJammingIncidentsRequest) (*ListJammingIncidentsResponse, error)
 	// lncli: `exportchanbackup`
 	// ExportChannelBackup attempts to return an encrypted static channel backup
 	// for the target channel identified by it channel point. The backup is
@@ -1728,6 +2417,13 @@ type LightningServer interface {
 	// needs to be compiled with  the `dev` build tag, and the message type to
 	// override should be specified in lnd's experimental protocol configuration.
 	SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error
+	// SendOnionMessage sends an onion message over a blinded path. The message
+	// is handed to the introduction node of the path, which must be a connected
+	// peer that supports onion messages. Requires protocol.onion-messages.
+	SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error)
+	// SubscribeOnionMessages subscribes to a stream of the onion messages that
+	// are delivered to our node. Requires protocol.onion-messages.
+	SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -1747,12 +2443,21 @@ type UnimplementedLightningServer struct {
 func (UnimplementedLightningServer) WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WalletBalance not implemented")
 }
+func (UnimplementedLightningServer) SubscribeWalletBalance(*SubscribeWalletBalanceRequest, Lightning_SubscribeWalletBalanceServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeWalletBalance not implemented")
+}
 func (UnimplementedLightningServer) ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChannelBalance not implemented")
 }
+func (UnimplementedLightningServer) LiquidityAlerts(context.Context, *LiquidityAlertsRequest) (*LiquidityAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LiquidityAlerts not implemented")
+}
 func (UnimplementedLightningServer) GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTransactions not implemented")
 }
+func (UnimplementedLightningServer) AddressReuseReport(context.Context, *AddressReuseReportRequest) (*AddressReuseReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddressReuseReport not implemented")
+}
 func (UnimplementedLightningServer) EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
 }
@@ -1783,6 +2488,9 @@ func (UnimplementedLightningServer) ConnectPeer(context.Context, *ConnectPeerReq
 func (UnimplementedLightningServer) DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DisconnectPeer not implemented")
 }
+func (UnimplementedLightningServer) DiagnoseConnection(context.Context, *DiagnoseConnectionRequest) (*DiagnoseConnectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiagnoseConnection not implemented")
+}
 func (UnimplementedLightningServer) ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListPeers not implemented")
 }
@@ -1795,12 +2503,42 @@ func (UnimplementedLightningServer) GetInfo(context.Context, *GetInfoRequest) (*
 func (UnimplementedLightningServer) GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDebugInfo not implemented")
 }
+func (UnimplementedLightningServer) GetEffectiveConfig(context.Context, *GetEffectiveConfigRequest) (*GetEffectiveConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEffectiveConfig not implemented")
+}
+func (UnimplementedLightningServer) ListDeprecations(context.Context, *ListDeprecationsRequest) (*ListDeprecationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeprecations not implemented")
+}
 func (UnimplementedLightningServer) GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRecoveryInfo not implemented")
 }
+func (UnimplementedLightningServer) GetChainInfo(context.Context, *GetChainInfoRequest) (*GetChainInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChainInfo not implemented")
+}
+func (UnimplementedLightningServer) GetLeadershipState(context.Context, *GetLeadershipStateRequest) (*GetLeadershipStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeadershipState not implemented")
+}
+func (UnimplementedLightningServer) GetDBUsage(context.Context, *GetDBUsageRequest) (*GetDBUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDBUsage not implemented")
+}
+func (UnimplementedLightningServer) ScheduleDBCompaction(context.Context, *ScheduleDBCompactionRequest) (*ScheduleDBCompactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleDBCompaction not implemented")
+}
+func (UnimplementedLightningServer) CltvGuardStatus(context.Context, *CltvGuardStatusRequest) (*CltvGuardStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CltvGuardStatus not implemented")
+}
+func (UnimplementedLightningServer) SafeModeStatus(context.Context, *SafeModeStatusRequest) (*SafeModeStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SafeModeStatus not implemented")
+}
+func (UnimplementedLightningServer) ExitSafeMode(context.Context, *ExitSafeModeRequest) (*ExitSafeModeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExitSafeMode not implemented")
+}
 func (UnimplementedLightningServer) PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PendingChannels not implemented")
 }
+func (UnimplementedLightningServer) PendingChannelsPage(context.Context, *PendingChannelsPageRequest) (*PendingChannelsPageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PendingChannelsPage not implemented")
+}
 func (UnimplementedLightningServer) ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListChannels not implemented")
 }
@@ -1810,6 +2548,15 @@ func (UnimplementedLightningServer) SubscribeChannelEvents(*ChannelEventSubscrip
 func (UnimplementedLightningServer) ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ClosedChannels not implemented")
 }
+func (UnimplementedLightningServer) ClosedChannelsPage(context.Context, *ClosedChannelsPageRequest) (*ClosedChannelsPageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClosedChannelsPage not implemented")
+}
+func (UnimplementedLightningServer) ChannelCloseHistory(context.Context, *ChannelCloseHistoryRequest) (*ChannelCloseHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelCloseHistory not implemented")
+}
+func (UnimplementedLightningServer) AnchorOwnershipProofs(context.Context, *AnchorOwnershipProofsRequest) (*AnchorOwnershipProofsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnchorOwnershipProofs not implemented")
+}
 func (UnimplementedLightningServer) OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OpenChannelSync not implemented")
 }
@@ -1819,15 +2566,33 @@ func (UnimplementedLightningServer) OpenChannel(*OpenChannelRequest, Lightning_O
 func (UnimplementedLightningServer) BatchOpenChannel(context.Context, *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchOpenChannel not implemented")
 }
+func (UnimplementedLightningServer) CheckOpenChannel(context.Context, *OpenChannelRequest) (*CheckOpenChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckOpenChannel not implemented")
+}
 func (UnimplementedLightningServer) FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FundingStateStep not implemented")
 }
+func (UnimplementedLightningServer) RegisterFundingContributors(context.Context, *RegisterFundingContributorsRequest) (*RegisterFundingContributorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterFundingContributors not implemented")
+}
+func (UnimplementedLightningServer) ContributeFunding(context.Context, *ContributeFundingRequest) (*ContributeFundingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ContributeFunding not implemented")
+}
+func (UnimplementedLightningServer) SignFundingContribution(context.Context, *SignFundingContributionRequest) (*SignFundingContributionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignFundingContribution not implemented")
+}
+func (UnimplementedLightningServer) FundingContributionStatus(context.Context, *FundingContributionStatusRequest) (*FundingContributionStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FundingContributionStatus not implemented")
+}
 func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorServer) error {
 	return status.Errorf(codes.Unimplemented, "method ChannelAcceptor not implemented")
 }
 func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
 	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
 }
+func (UnimplementedLightningServer) BumpCoopCloseFee(context.Context, *BumpCoopCloseFeeRequest) (*BumpCoopCloseFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BumpCoopCloseFee not implemented")
+}
 func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
 }
@@ -1879,6 +2644,12 @@ func (UnimplementedLightningServer) GetChanInfo(context.Context, *ChanInfoReques
 func (UnimplementedLightningServer) GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
 }
+func (UnimplementedLightningServer) GetNodeMetadata(context.Context, *NodeMetadataRequest) (*NodeMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeMetadata not implemented")
+}
+func (UnimplementedLightningServer) GetNodeCapabilities(context.Context, *NodeCapabilitiesRequest) (*NodeCapabilities, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeCapabilities not implemented")
+}
 func (UnimplementedLightningServer) QueryRoutes(context.Context, *QueryRoutesRequest) (*QueryRoutesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryRoutes not implemented")
 }
@@ -1894,6 +2665,9 @@ func (UnimplementedLightningServer) SubscribeChannelGraph(*GraphTopologySubscrip
 func (UnimplementedLightningServer) DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DebugLevel not implemented")
 }
+func (UnimplementedLightningServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
 func (UnimplementedLightningServer) FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FeeReport not implemented")
 }
@@ -1903,8 +2677,20 @@ func (UnimplementedLightningServer) UpdateChannelPolicy(context.Context, *Policy
 func (UnimplementedLightningServer) ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForwardingHistory not implemented")
 }
-func (UnimplementedLightningServer) ExportChannelBackup(context.Context, *ExportChannelBackupRequest) (*ChannelBackup, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ExportChannelBackup not implemented")
+func (UnimplementedLightningServer) ExportLedger(context.Context, *ExportLedgerRequest) (*ExportLedgerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportLedger not implemented")
+}
+func (UnimplementedLightningServer) HoldFeeStatus(context.Context, *HoldFeeStatusRequest) (*HoldFeeStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HoldFeeStatus not implemented")
+}
+func (UnimplementedLightningServer) SimulateHoldFee(context.Context, *SimulateHoldFeeRequest) (*SimulateHoldFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateHoldFee not implemented")
+}
+func (UnimplementedLightningServer) ListJammingIncidents(context.Context, *ListJammingIncidentsRequest) (*ListJammingIncidentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJammingIncidents not implemented")
+}
+func (UnimplementedLightningServer) ExportChannelBackup(context.Context, *ExportChannelBackupRequest) (*ChannelBackup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportChannelBackup not implemented")
 }
 func (UnimplementedLightningServer) ExportAllChannelBackups(context.Context, *ChanBackupExportRequest) (*ChanBackupSnapshot, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExportAllChannelBackups not implemented")
@@ -1942,6 +2728,12 @@ func (UnimplementedLightningServer) SendCustomMessage(context.Context, *SendCust
 func (UnimplementedLightningServer) SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeCustomMessages not implemented")
 }
+func (UnimplementedLightningServer) SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOnionMessage not implemented")
+}
+func (UnimplementedLightningServer) SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeOnionMessages not implemented")
+}
 func (UnimplementedLightningServer) ListAliases(context.Context, *ListAliasesRequest) (*ListAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAliases not implemented")
 }
@@ -1979,6 +2771,27 @@ func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_SubscribeWalletBalance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeWalletBalanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeWalletBalance(m, &lightningSubscribeWalletBalanceServer{stream})
+}
+
+type Lightning_SubscribeWalletBalanceServer interface {
+	Send(*WalletBalanceUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeWalletBalanceServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeWalletBalanceServer) Send(m *WalletBalanceUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChannelBalanceRequest)
 	if err := dec(in); err != nil {
@@ -1997,6 +2810,24 @@ func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_LiquidityAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LiquidityAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).LiquidityAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/LiquidityAlerts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).LiquidityAlerts(ctx, req.(*LiquidityAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetTransactionsRequest)
 	if err := dec(in); err != nil {
@@ -2015,6 +2846,24 @@ func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_AddressReuseReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressReuseReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddressReuseReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddressReuseReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddressReuseReport(ctx, req.(*AddressReuseReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EstimateFeeRequest)
 	if err := dec(in); err != nil {
@@ -2198,6 +3047,24 @@ func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_DiagnoseConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiagnoseConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DiagnoseConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DiagnoseConnection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DiagnoseConnection(ctx, req.(*DiagnoseConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPeersRequest)
 	if err := dec(in); err != nil {
@@ -2273,6 +3140,42 @@ func _Lightning_GetDebugInfo_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_GetEffectiveConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEffectiveConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetEffectiveConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetEffectiveConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetEffectiveConfig(ctx, req.(*GetEffectiveConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListDeprecations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeprecationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListDeprecations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListDeprecations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListDeprecations(ctx, req.(*ListDeprecationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetRecoveryInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetRecoveryInfoRequest)
 	if err := dec(in); err != nil {
@@ -2291,6 +3194,132 @@ func _Lightning_GetRecoveryInfo_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_GetChainInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChainInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetChainInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetChainInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetChainInfo(ctx, req.(*GetChainInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetLeadershipState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeadershipStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetLeadershipState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetLeadershipState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetLeadershipState(ctx, req.(*GetLeadershipStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetDBUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDBUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetDBUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetDBUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetDBUsage(ctx, req.(*GetDBUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ScheduleDBCompaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleDBCompactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ScheduleDBCompaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ScheduleDBCompaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ScheduleDBCompaction(ctx, req.(*ScheduleDBCompactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_CltvGuardStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CltvGuardStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CltvGuardStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CltvGuardStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CltvGuardStatus(ctx, req.(*CltvGuardStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SafeModeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeModeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SafeModeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SafeModeStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SafeModeStatus(ctx, req.(*SafeModeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ExitSafeMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExitSafeModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExitSafeMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExitSafeMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExitSafeMode(ctx, req.(*ExitSafeModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PendingChannelsRequest)
 	if err := dec(in); err != nil {
@@ -2309,6 +3338,24 @@ func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_PendingChannelsPage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingChannelsPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).PendingChannelsPage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PendingChannelsPage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PendingChannelsPage(ctx, req.(*PendingChannelsPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListChannelsRequest)
 	if err := dec(in); err != nil {
@@ -2366,6 +3413,60 @@ func _Lightning_ClosedChannels_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ClosedChannelsPage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClosedChannelsPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ClosedChannelsPage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ClosedChannelsPage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ClosedChannelsPage(ctx, req.(*ClosedChannelsPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ChannelCloseHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelCloseHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelCloseHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelCloseHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelCloseHistory(ctx, req.(*ChannelCloseHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_AnchorOwnershipProofs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnchorOwnershipProofsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AnchorOwnershipProofs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AnchorOwnershipProofs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AnchorOwnershipProofs(ctx, req.(*AnchorOwnershipProofsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(OpenChannelRequest)
 	if err := dec(in); err != nil {
@@ -2423,6 +3524,24 @@ func _Lightning_BatchOpenChannel_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_CheckOpenChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CheckOpenChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CheckOpenChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CheckOpenChannel(ctx, req.(*OpenChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FundingTransitionMsg)
 	if err := dec(in); err != nil {
@@ -2441,6 +3560,78 @@ func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_RegisterFundingContributors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterFundingContributorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).RegisterFundingContributors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/RegisterFundingContributors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).RegisterFundingContributors(ctx, req.(*RegisterFundingContributorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ContributeFunding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContributeFundingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ContributeFunding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ContributeFunding",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ContributeFunding(ctx, req.(*ContributeFundingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SignFundingContribution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignFundingContributionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SignFundingContribution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SignFundingContribution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SignFundingContribution(ctx, req.(*SignFundingContributionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_FundingContributionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundingContributionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).FundingContributionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/FundingContributionStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).FundingContributionStatus(ctx, req.(*FundingContributionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ChannelAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LightningServer).ChannelAcceptor(&lightningChannelAcceptorServer{stream})
 }
@@ -2488,6 +3679,24 @@ func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_BumpCoopCloseFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpCoopCloseFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BumpCoopCloseFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BumpCoopCloseFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BumpCoopCloseFee(ctx, req.(*BumpCoopCloseFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AbandonChannelRequest)
 	if err := dec(in); err != nil {
@@ -2813,6 +4022,42 @@ func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_GetNodeMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetNodeMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetNodeMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetNodeMetadata(ctx, req.(*NodeMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetNodeCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetNodeCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetNodeCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetNodeCapabilities(ctx, req.(*NodeCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(QueryRoutesRequest)
 	if err := dec(in); err != nil {
@@ -2906,6 +4151,24 @@ func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FeeReportRequest)
 	if err := dec(in); err != nil {
@@ -2960,6 +4223,78 @@ func _Lightning_ForwardingHistory_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ExportLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportLedger",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportLedger(ctx, req.(*ExportLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_HoldFeeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HoldFeeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).HoldFeeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/HoldFeeStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).HoldFeeStatus(ctx, req.(*HoldFeeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SimulateHoldFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateHoldFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SimulateHoldFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SimulateHoldFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SimulateHoldFee(ctx, req.(*SimulateHoldFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListJammingIncidents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJammingIncidentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListJammingIncidents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListJammingIncidents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListJammingIncidents(ctx, req.(*ListJammingIncidentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ExportChannelBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExportChannelBackupRequest)
 	if err := dec(in); err != nil {
@@ -3208,6 +4543,45 @@ func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SendOnionMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOnionMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendOnionMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendOnionMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendOnionMessage(ctx, req.(*SendOnionMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeOnionMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeOnionMessagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeOnionMessages(m, &lightningSubscribeOnionMessagesServer{stream})
+}
+
+type Lightning_SubscribeOnionMessagesServer interface {
+	Send(*OnionMessage) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeOnionMessagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeOnionMessagesServer) Send(m *OnionMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Lightning_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListAliasesRequest)
 	if err := dec(in); err != nil {
@@ -3259,10 +4633,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ChannelBalance",
 			Handler:    _Lightning_ChannelBalance_Handler,
 		},
+		{
+			MethodName: "LiquidityAlerts",
+			Handler:    _Lightning_LiquidityAlerts_Handler,
+		},
 		{
 			MethodName: "GetTransactions",
 			Handler:    _Lightning_GetTransactions_Handler,
 		},
+		{
+			MethodName: "AddressReuseReport",
+			Handler:    _Lightning_AddressReuseReport_Handler,
+		},
 		{
 			MethodName: "EstimateFee",
 			Handler:    _Lightning_EstimateFee_Handler,
@@ -3299,6 +4681,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DisconnectPeer",
 			Handler:    _Lightning_DisconnectPeer_Handler,
 		},
+		{
+			MethodName: "DiagnoseConnection",
+			Handler:    _Lightning_DiagnoseConnection_Handler,
+		},
 		{
 			MethodName: "ListPeers",
 			Handler:    _Lightning_ListPeers_Handler,
@@ -3311,14 +4697,54 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDebugInfo",
 			Handler:    _Lightning_GetDebugInfo_Handler,
 		},
+		{
+			MethodName: "GetEffectiveConfig",
+			Handler:    _Lightning_GetEffectiveConfig_Handler,
+		},
+		{
+			MethodName: "ListDeprecations",
+			Handler:    _Lightning_ListDeprecations_Handler,
+		},
 		{
 			MethodName: "GetRecoveryInfo",
 			Handler:    _Lightning_GetRecoveryInfo_Handler,
 		},
+		{
+			MethodName: "GetChainInfo",
+			Handler:    _Lightning_GetChainInfo_Handler,
+		},
+		{
+			MethodName: "GetLeadershipState",
+			Handler:    _Lightning_GetLeadershipState_Handler,
+		},
+		{
+			MethodName: "GetDBUsage",
+			Handler:    _Lightning_GetDBUsage_Handler,
+		},
+		{
+			MethodName: "ScheduleDBCompaction",
+			Handler:    _Lightning_ScheduleDBCompaction_Handler,
+		},
+		{
+			MethodName: "CltvGuardStatus",
+			Handler:    _Lightning_CltvGuardStatus_Handler,
+		},
+		{
+			MethodName: "SafeModeStatus",
+			Handler:    _Lightning_SafeModeStatus_Handler,
+		},
+		{
+			MethodName: "ExitSafeMode",
+			Handler:    _Lightning_ExitSafeMode_Handler,
+		},
 		{
 			MethodName: "PendingChannels",
 			Handler:    _Lightning_PendingChannels_Handler,
 		},
+		{
+			MethodName: "PendingChannelsPage",
+			Handler:    _Lightning_PendingChannelsPage_Handler,
+		},
 		{
 			MethodName: "ListChannels",
 			Handler:    _Lightning_ListChannels_Handler,
@@ -3327,6 +4753,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ClosedChannels",
 			Handler:    _Lightning_ClosedChannels_Handler,
 		},
+		{
+			MethodName: "ClosedChannelsPage",
+			Handler:    _Lightning_ClosedChannelsPage_Handler,
+		},
+		{
+			MethodName: "ChannelCloseHistory",
+			Handler:    _Lightning_ChannelCloseHistory_Handler,
+		},
+		{
+			MethodName: "AnchorOwnershipProofs",
+			Handler:    _Lightning_AnchorOwnershipProofs_Handler,
+		},
 		{
 			MethodName: "OpenChannelSync",
 			Handler:    _Lightning_OpenChannelSync_Handler,
@@ -3335,10 +4773,34 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchOpenChannel",
 			Handler:    _Lightning_BatchOpenChannel_Handler,
 		},
+		{
+			MethodName: "CheckOpenChannel",
+			Handler:    _Lightning_CheckOpenChannel_Handler,
+		},
 		{
 			MethodName: "FundingStateStep",
 			Handler:    _Lightning_FundingStateStep_Handler,
 		},
+		{
+			MethodName: "RegisterFundingContributors",
+			Handler:    _Lightning_RegisterFundingContributors_Handler,
+		},
+		{
+			MethodName: "ContributeFunding",
+			Handler:    _Lightning_ContributeFunding_Handler,
+		},
+		{
+			MethodName: "SignFundingContribution",
+			Handler:    _Lightning_SignFundingContribution_Handler,
+		},
+		{
+			MethodName: "FundingContributionStatus",
+			Handler:    _Lightning_FundingContributionStatus_Handler,
+		},
+		{
+			MethodName: "BumpCoopCloseFee",
+			Handler:    _Lightning_BumpCoopCloseFee_Handler,
+		},
 		{
 			MethodName: "AbandonChannel",
 			Handler:    _Lightning_AbandonChannel_Handler,
@@ -3395,6 +4857,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNodeInfo",
 			Handler:    _Lightning_GetNodeInfo_Handler,
 		},
+		{
+			MethodName: "GetNodeMetadata",
+			Handler:    _Lightning_GetNodeMetadata_Handler,
+		},
+		{
+			MethodName: "GetNodeCapabilities",
+			Handler:    _Lightning_GetNodeCapabilities_Handler,
+		},
 		{
 			MethodName: "QueryRoutes",
 			Handler:    _Lightning_QueryRoutes_Handler,
@@ -3411,6 +4881,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DebugLevel",
 			Handler:    _Lightning_DebugLevel_Handler,
 		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _Lightning_ReloadConfig_Handler,
+		},
 		{
 			MethodName: "FeeReport",
 			Handler:    _Lightning_FeeReport_Handler,
@@ -3423,6 +4897,22 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ForwardingHistory",
 			Handler:    _Lightning_ForwardingHistory_Handler,
 		},
+		{
+			MethodName: "ExportLedger",
+			Handler:    _Lightning_ExportLedger_Handler,
+		},
+		{
+			MethodName: "HoldFeeStatus",
+			Handler:    _Lightning_HoldFeeStatus_Handler,
+		},
+		{
+			MethodName: "SimulateHoldFee",
+			Handler:    _Lightning_SimulateHoldFee_Handler,
+		},
+		{
+			MethodName: "ListJammingIncidents",
+			Handler:    _Lightning_ListJammingIncidents_Handler,
+		},
 		{
 			MethodName: "ExportChannelBackup",
 			Handler:    _Lightning_ExportChannelBackup_Handler,
@@ -3463,6 +4953,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendCustomMessage",
 			Handler:    _Lightning_SendCustomMessage_Handler,
 		},
+		{
+			MethodName: "SendOnionMessage",
+			Handler:    _Lightning_SendOnionMessage_Handler,
+		},
 		{
 			MethodName: "ListAliases",
 			Handler:    _Lightning_ListAliases_Handler,
@@ -3473,6 +4967,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeWalletBalance",
+			Handler:       _Lightning_SubscribeWalletBalance_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeTransactions",
 			Handler:       _Lightning_SubscribeTransactions_Handler,
@@ -3542,6 +5041,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeCustomMessages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeOnionMessages",
+			Handler:       _Lightning_SubscribeOnionMessages_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "lightning.proto",
 }
@@ -0,0 +1,19 @@
+//go:build lsprpc
+// +build lsprpc
+
+package lsprpc
+
+import (
+	"github.com/lightningnetwork/lnd/lsps"
+)
+
+// Config is the primary configuration struct for the LSP RPC subserver. It
+// contains all the items required for the server to carry out its duties. The
+// fields with struct tags are meant to be parsed as normal configuration
+// options, while if able to be populated, the latter fields MUST also be
+// specified.
+type Config struct {
+	// LSPClient is the client that talks to LSPs over custom peer
+	// messages.
+	LSPClient *lsps.Client
+}
@@ -0,0 +1,7 @@
+//go:build !lsprpc
+// +build !lsprpc
+
+package lsprpc
+
+// Config is empty for non-lsprpc builds.
+type Config struct{}
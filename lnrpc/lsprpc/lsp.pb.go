@@ -0,0 +1,2067 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        v3.21.12
+// source: lsprpc/lsp.proto
+
+package lsprpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PaymentState int32
+
+const (
+	// The LSP reported a payment state that is unknown to lnd.
+	PaymentState_PAYMENT_STATE_UNKNOWN PaymentState = 0
+	// The LSP waits for the payment.
+	PaymentState_EXPECT_PAYMENT PaymentState = 1
+	// The LSP received the payment but didn't settle it yet.
+	PaymentState_HOLD PaymentState = 2
+	// The LSP settled the payment.
+	PaymentState_PAID PaymentState = 3
+	// The LSP refunded the payment.
+	PaymentState_REFUNDED PaymentState = 4
+)
+
+// Enum value maps for PaymentState.
+var (
+	PaymentState_name = map[int32]string{
+		0: "PAYMENT_STATE_UNKNOWN",
+		1: "EXPECT_PAYMENT",
+		2: "HOLD",
+		3: "PAID",
+		4: "REFUNDED",
+	}
+	PaymentState_value = map[string]int32{
+		"PAYMENT_STATE_UNKNOWN": 0,
+		"EXPECT_PAYMENT":        1,
+		"HOLD":                  2,
+		"PAID":                  3,
+		"REFUNDED":              4,
+	}
+)
+
+func (x PaymentState) Enum() *PaymentState {
+	p := new(PaymentState)
+	*p = x
+	return p
+}
+
+func (x PaymentState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentState) Descriptor() protoreflect.EnumDescriptor {
+	return file_lsprpc_lsp_proto_enumTypes[0].Descriptor()
+}
+
+func (PaymentState) Type() protoreflect.EnumType {
+	return &file_lsprpc_lsp_proto_enumTypes[0]
+}
+
+func (x PaymentState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentState.Descriptor instead.
+func (PaymentState) EnumDescriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{0}
+}
+
+type LSPS1Order_OrderState int32
+
+const (
+	// The LSP reported an order state that is unknown to lnd.
+	LSPS1Order_ORDER_STATE_UNKNOWN LSPS1Order_OrderState = 0
+	// The order waits for its payment or for the channel to be opened.
+	LSPS1Order_CREATED LSPS1Order_OrderState = 1
+	// The channel of the order is open.
+	LSPS1Order_COMPLETED LSPS1Order_OrderState = 2
+	// The order failed, in which case the payment is refunded.
+	LSPS1Order_FAILED LSPS1Order_OrderState = 3
+)
+
+// Enum value maps for LSPS1Order_OrderState.
+var (
+	LSPS1Order_OrderState_name = map[int32]string{
+		0: "ORDER_STATE_UNKNOWN",
+		1: "CREATED",
+		2: "COMPLETED",
+		3: "FAILED",
+	}
+	LSPS1Order_OrderState_value = map[string]int32{
+		"ORDER_STATE_UNKNOWN": 0,
+		"CREATED":             1,
+		"COMPLETED":           2,
+		"FAILED":              3,
+	}
+)
+
+func (x LSPS1Order_OrderState) Enum() *LSPS1Order_OrderState {
+	p := new(LSPS1Order_OrderState)
+	*p = x
+	return p
+}
+
+func (x LSPS1Order_OrderState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LSPS1Order_OrderState) Descriptor() protoreflect.EnumDescriptor {
+	return file_lsprpc_lsp_proto_enumTypes[1].Descriptor()
+}
+
+func (LSPS1Order_OrderState) Type() protoreflect.EnumType {
+	return &file_lsprpc_lsp_proto_enumTypes[1]
+}
+
+func (x LSPS1Order_OrderState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LSPS1Order_OrderState.Descriptor instead.
+func (LSPS1Order_OrderState) EnumDescriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type ListProtocolsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed public key of the LSP.
+	LspPubkey []byte `protobuf:"bytes,1,opt,name=lsp_pubkey,json=lspPubkey,proto3" json:"lsp_pubkey,omitempty"`
+}
+
+func (x *ListProtocolsRequest) Reset() {
+	*x = ListProtocolsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProtocolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProtocolsRequest) ProtoMessage() {}
+
+func (x *ListProtocolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProtocolsRequest.ProtoReflect.Descriptor instead.
+func (*ListProtocolsRequest) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListProtocolsRequest) GetLspPubkey() []byte {
+	if x != nil {
+		return x.LspPubkey
+	}
+	return nil
+}
+
+type ListProtocolsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The numbers of the LSP specifications the LSP supports, such as 1 for
+	// LSPS1 and 2 for LSPS2.
+	Protocols []uint32 `protobuf:"varint,1,rep,packed,name=protocols,proto3" json:"protocols,omitempty"`
+}
+
+func (x *ListProtocolsResponse) Reset() {
+	*x = ListProtocolsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProtocolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProtocolsResponse) ProtoMessage() {}
+
+func (x *ListProtocolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProtocolsResponse.ProtoReflect.Descriptor instead.
+func (*ListProtocolsResponse) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListProtocolsResponse) GetProtocols() []uint32 {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+type LSPS1GetInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed public key of the LSP.
+	LspPubkey []byte `protobuf:"bytes,1,opt,name=lsp_pubkey,json=lspPubkey,proto3" json:"lsp_pubkey,omitempty"`
+}
+
+func (x *LSPS1GetInfoRequest) Reset() {
+	*x = LSPS1GetInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS1GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS1GetInfoRequest) ProtoMessage() {}
+
+func (x *LSPS1GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS1GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*LSPS1GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LSPS1GetInfoRequest) GetLspPubkey() []byte {
+	if x != nil {
+		return x.LspPubkey
+	}
+	return nil
+}
+
+type LSPS1Info struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The minimum number of confirmations the LSP waits for before the channel
+	// can be used.
+	MinRequiredChannelConfirmations uint32 `protobuf:"varint,1,opt,name=min_required_channel_confirmations,json=minRequiredChannelConfirmations,proto3" json:"min_required_channel_confirmations,omitempty"`
+	// The minimum number of blocks the funding transaction can be requested to
+	// confirm in.
+	MinFundingConfirmsWithinBlocks uint32 `protobuf:"varint,2,opt,name=min_funding_confirms_within_blocks,json=minFundingConfirmsWithinBlocks,proto3" json:"min_funding_confirms_within_blocks,omitempty"`
+	// Whether the LSP opens channels without a reserve for the client.
+	SupportsZeroChannelReserve bool `protobuf:"varint,3,opt,name=supports_zero_channel_reserve,json=supportsZeroChannelReserve,proto3" json:"supports_zero_channel_reserve,omitempty"`
+	// The maximum number of blocks the LSP keeps a channel open for.
+	MaxChannelExpiryBlocks uint32 `protobuf:"varint,4,opt,name=max_channel_expiry_blocks,json=maxChannelExpiryBlocks,proto3" json:"max_channel_expiry_blocks,omitempty"`
+	// The minimum balance the client can buy on its side of the channel.
+	MinInitialClientBalanceSat int64 `protobuf:"varint,5,opt,name=min_initial_client_balance_sat,json=minInitialClientBalanceSat,proto3" json:"min_initial_client_balance_sat,omitempty"`
+	// The maximum balance the client can buy on its side of the channel.
+	MaxInitialClientBalanceSat int64 `protobuf:"varint,6,opt,name=max_initial_client_balance_sat,json=maxInitialClientBalanceSat,proto3" json:"max_initial_client_balance_sat,omitempty"`
+	// The minimum balance the client can request on the LSP side.
+	MinInitialLspBalanceSat int64 `protobuf:"varint,7,opt,name=min_initial_lsp_balance_sat,json=minInitialLspBalanceSat,proto3" json:"min_initial_lsp_balance_sat,omitempty"`
+	// The maximum balance the client can request on the LSP side.
+	MaxInitialLspBalanceSat int64 `protobuf:"varint,8,opt,name=max_initial_lsp_balance_sat,json=maxInitialLspBalanceSat,proto3" json:"max_initial_lsp_balance_sat,omitempty"`
+	// The minimum capacity of a channel.
+	MinChannelBalanceSat int64 `protobuf:"varint,9,opt,name=min_channel_balance_sat,json=minChannelBalanceSat,proto3" json:"min_channel_balance_sat,omitempty"`
+	// The maximum capacity of a channel.
+	MaxChannelBalanceSat int64 `protobuf:"varint,10,opt,name=max_channel_balance_sat,json=maxChannelBalanceSat,proto3" json:"max_channel_balance_sat,omitempty"`
+}
+
+func (x *LSPS1Info) Reset() {
+	*x = LSPS1Info{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS1Info) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS1Info) ProtoMessage() {}
+
+func (x *LSPS1Info) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS1Info.ProtoReflect.Descriptor instead.
+func (*LSPS1Info) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LSPS1Info) GetMinRequiredChannelConfirmations() uint32 {
+	if x != nil {
+		return x.MinRequiredChannelConfirmations
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetMinFundingConfirmsWithinBlocks() uint32 {
+	if x != nil {
+		return x.MinFundingConfirmsWithinBlocks
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetSupportsZeroChannelReserve() bool {
+	if x != nil {
+		return x.SupportsZeroChannelReserve
+	}
+	return false
+}
+
+func (x *LSPS1Info) GetMaxChannelExpiryBlocks() uint32 {
+	if x != nil {
+		return x.MaxChannelExpiryBlocks
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetMinInitialClientBalanceSat() int64 {
+	if x != nil {
+		return x.MinInitialClientBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetMaxInitialClientBalanceSat() int64 {
+	if x != nil {
+		return x.MaxInitialClientBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetMinInitialLspBalanceSat() int64 {
+	if x != nil {
+		return x.MinInitialLspBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetMaxInitialLspBalanceSat() int64 {
+	if x != nil {
+		return x.MaxInitialLspBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetMinChannelBalanceSat() int64 {
+	if x != nil {
+		return x.MinChannelBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1Info) GetMaxChannelBalanceSat() int64 {
+	if x != nil {
+		return x.MaxChannelBalanceSat
+	}
+	return 0
+}
+
+type LSPS1CreateOrderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed public key of the LSP.
+	LspPubkey []byte `protobuf:"bytes,1,opt,name=lsp_pubkey,json=lspPubkey,proto3" json:"lsp_pubkey,omitempty"`
+	// The balance on the LSP side of the channel.
+	LspBalanceSat int64 `protobuf:"varint,2,opt,name=lsp_balance_sat,json=lspBalanceSat,proto3" json:"lsp_balance_sat,omitempty"`
+	// The balance on the client side of the channel, which the client pays for
+	// on top of the fees.
+	ClientBalanceSat int64 `protobuf:"varint,3,opt,name=client_balance_sat,json=clientBalanceSat,proto3" json:"client_balance_sat,omitempty"`
+	// The number of confirmations the LSP waits for before the channel can be
+	// used.
+	RequiredChannelConfirmations uint32 `protobuf:"varint,4,opt,name=required_channel_confirmations,json=requiredChannelConfirmations,proto3" json:"required_channel_confirmations,omitempty"`
+	// The number of blocks the funding transaction should confirm in.
+	FundingConfirmsWithinBlocks uint32 `protobuf:"varint,5,opt,name=funding_confirms_within_blocks,json=fundingConfirmsWithinBlocks,proto3" json:"funding_confirms_within_blocks,omitempty"`
+	// The number of blocks the LSP keeps the channel open for at least.
+	ChannelExpiryBlocks uint32 `protobuf:"varint,6,opt,name=channel_expiry_blocks,json=channelExpiryBlocks,proto3" json:"channel_expiry_blocks,omitempty"`
+	// An optional token the LSP handed out, for example for a discount.
+	Token string `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`
+	// The address the LSP refunds on-chain payments to if the order fails.
+	RefundOnchainAddress string `protobuf:"bytes,8,opt,name=refund_onchain_address,json=refundOnchainAddress,proto3" json:"refund_onchain_address,omitempty"`
+	// Whether the channel is announced to the network.
+	AnnounceChannel bool `protobuf:"varint,9,opt,name=announce_channel,json=announceChannel,proto3" json:"announce_channel,omitempty"`
+}
+
+func (x *LSPS1CreateOrderRequest) Reset() {
+	*x = LSPS1CreateOrderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS1CreateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS1CreateOrderRequest) ProtoMessage() {}
+
+func (x *LSPS1CreateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS1CreateOrderRequest.ProtoReflect.Descriptor instead.
+func (*LSPS1CreateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LSPS1CreateOrderRequest) GetLspPubkey() []byte {
+	if x != nil {
+		return x.LspPubkey
+	}
+	return nil
+}
+
+func (x *LSPS1CreateOrderRequest) GetLspBalanceSat() int64 {
+	if x != nil {
+		return x.LspBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1CreateOrderRequest) GetClientBalanceSat() int64 {
+	if x != nil {
+		return x.ClientBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1CreateOrderRequest) GetRequiredChannelConfirmations() uint32 {
+	if x != nil {
+		return x.RequiredChannelConfirmations
+	}
+	return 0
+}
+
+func (x *LSPS1CreateOrderRequest) GetFundingConfirmsWithinBlocks() uint32 {
+	if x != nil {
+		return x.FundingConfirmsWithinBlocks
+	}
+	return 0
+}
+
+func (x *LSPS1CreateOrderRequest) GetChannelExpiryBlocks() uint32 {
+	if x != nil {
+		return x.ChannelExpiryBlocks
+	}
+	return 0
+}
+
+func (x *LSPS1CreateOrderRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LSPS1CreateOrderRequest) GetRefundOnchainAddress() string {
+	if x != nil {
+		return x.RefundOnchainAddress
+	}
+	return ""
+}
+
+func (x *LSPS1CreateOrderRequest) GetAnnounceChannel() bool {
+	if x != nil {
+		return x.AnnounceChannel
+	}
+	return false
+}
+
+type LSPS1GetOrderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed public key of the LSP.
+	LspPubkey []byte `protobuf:"bytes,1,opt,name=lsp_pubkey,json=lspPubkey,proto3" json:"lsp_pubkey,omitempty"`
+	// The ID of the order.
+	OrderId string `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+}
+
+func (x *LSPS1GetOrderRequest) Reset() {
+	*x = LSPS1GetOrderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS1GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS1GetOrderRequest) ProtoMessage() {}
+
+func (x *LSPS1GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS1GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*LSPS1GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LSPS1GetOrderRequest) GetLspPubkey() []byte {
+	if x != nil {
+		return x.LspPubkey
+	}
+	return nil
+}
+
+func (x *LSPS1GetOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type Bolt11Payment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The state of the payment.
+	State PaymentState `protobuf:"varint,1,opt,name=state,proto3,enum=lsprpc.PaymentState" json:"state,omitempty"`
+	// The unix timestamp in seconds at which the invoice expires.
+	ExpiresAt int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// The fee the LSP charges for the channel.
+	FeeTotalSat int64 `protobuf:"varint,3,opt,name=fee_total_sat,json=feeTotalSat,proto3" json:"fee_total_sat,omitempty"`
+	// The amount to pay, which is the fee plus the client balance.
+	OrderTotalSat int64 `protobuf:"varint,4,opt,name=order_total_sat,json=orderTotalSat,proto3" json:"order_total_sat,omitempty"`
+	// The invoice to pay.
+	Invoice string `protobuf:"bytes,5,opt,name=invoice,proto3" json:"invoice,omitempty"`
+}
+
+func (x *Bolt11Payment) Reset() {
+	*x = Bolt11Payment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bolt11Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bolt11Payment) ProtoMessage() {}
+
+func (x *Bolt11Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bolt11Payment.ProtoReflect.Descriptor instead.
+func (*Bolt11Payment) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Bolt11Payment) GetState() PaymentState {
+	if x != nil {
+		return x.State
+	}
+	return PaymentState_PAYMENT_STATE_UNKNOWN
+}
+
+func (x *Bolt11Payment) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *Bolt11Payment) GetFeeTotalSat() int64 {
+	if x != nil {
+		return x.FeeTotalSat
+	}
+	return 0
+}
+
+func (x *Bolt11Payment) GetOrderTotalSat() int64 {
+	if x != nil {
+		return x.OrderTotalSat
+	}
+	return 0
+}
+
+func (x *Bolt11Payment) GetInvoice() string {
+	if x != nil {
+		return x.Invoice
+	}
+	return ""
+}
+
+type OnchainPayment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The state of the payment.
+	State PaymentState `protobuf:"varint,1,opt,name=state,proto3,enum=lsprpc.PaymentState" json:"state,omitempty"`
+	// The unix timestamp in seconds after which the address is no longer
+	// watched.
+	ExpiresAt int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// The fee the LSP charges for the channel.
+	FeeTotalSat int64 `protobuf:"varint,3,opt,name=fee_total_sat,json=feeTotalSat,proto3" json:"fee_total_sat,omitempty"`
+	// The amount to pay, which is the fee plus the client balance.
+	OrderTotalSat int64 `protobuf:"varint,4,opt,name=order_total_sat,json=orderTotalSat,proto3" json:"order_total_sat,omitempty"`
+	// The address to pay to.
+	Address string `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	// The number of confirmations the LSP waits for before it considers the
+	// payment paid, or 0 if the LSP didn't specify it.
+	MinOnchainPaymentConfirmations uint32 `protobuf:"varint,6,opt,name=min_onchain_payment_confirmations,json=minOnchainPaymentConfirmations,proto3" json:"min_onchain_payment_confirmations,omitempty"`
+	// The minimum fee rate in sat/vB of a payment the LSP accepts without
+	// confirmation, or 0 if it doesn't accept unconfirmed payments.
+	MinFeeForZeroConf uint64 `protobuf:"varint,7,opt,name=min_fee_for_zero_conf,json=minFeeForZeroConf,proto3" json:"min_fee_for_zero_conf,omitempty"`
+}
+
+func (x *OnchainPayment) Reset() {
+	*x = OnchainPayment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OnchainPayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnchainPayment) ProtoMessage() {}
+
+func (x *OnchainPayment) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnchainPayment.ProtoReflect.Descriptor instead.
+func (*OnchainPayment) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OnchainPayment) GetState() PaymentState {
+	if x != nil {
+		return x.State
+	}
+	return PaymentState_PAYMENT_STATE_UNKNOWN
+}
+
+func (x *OnchainPayment) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *OnchainPayment) GetFeeTotalSat() int64 {
+	if x != nil {
+		return x.FeeTotalSat
+	}
+	return 0
+}
+
+func (x *OnchainPayment) GetOrderTotalSat() int64 {
+	if x != nil {
+		return x.OrderTotalSat
+	}
+	return 0
+}
+
+func (x *OnchainPayment) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *OnchainPayment) GetMinOnchainPaymentConfirmations() uint32 {
+	if x != nil {
+		return x.MinOnchainPaymentConfirmations
+	}
+	return 0
+}
+
+func (x *OnchainPayment) GetMinFeeForZeroConf() uint64 {
+	if x != nil {
+		return x.MinFeeForZeroConf
+	}
+	return 0
+}
+
+type OrderChannel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unix timestamp in seconds at which the funding transaction was
+	// published.
+	FundedAt int64 `protobuf:"varint,1,opt,name=funded_at,json=fundedAt,proto3" json:"funded_at,omitempty"`
+	// The funding outpoint of the channel.
+	FundingOutpoint string `protobuf:"bytes,2,opt,name=funding_outpoint,json=fundingOutpoint,proto3" json:"funding_outpoint,omitempty"`
+	// The unix timestamp in seconds from which the LSP may close the channel.
+	ExpiresAt int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+}
+
+func (x *OrderChannel) Reset() {
+	*x = OrderChannel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderChannel) ProtoMessage() {}
+
+func (x *OrderChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderChannel.ProtoReflect.Descriptor instead.
+func (*OrderChannel) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OrderChannel) GetFundedAt() int64 {
+	if x != nil {
+		return x.FundedAt
+	}
+	return 0
+}
+
+func (x *OrderChannel) GetFundingOutpoint() string {
+	if x != nil {
+		return x.FundingOutpoint
+	}
+	return ""
+}
+
+func (x *OrderChannel) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type LSPS1Order struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the order.
+	OrderId string `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// The unix timestamp in seconds at which the order was created.
+	CreatedAt int64 `protobuf:"varint,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// The state of the order.
+	State LSPS1Order_OrderState `protobuf:"varint,3,opt,name=state,proto3,enum=lsprpc.LSPS1Order_OrderState" json:"state,omitempty"`
+	// The balance on the LSP side of the channel.
+	LspBalanceSat int64 `protobuf:"varint,4,opt,name=lsp_balance_sat,json=lspBalanceSat,proto3" json:"lsp_balance_sat,omitempty"`
+	// The balance on the client side of the channel.
+	ClientBalanceSat int64 `protobuf:"varint,5,opt,name=client_balance_sat,json=clientBalanceSat,proto3" json:"client_balance_sat,omitempty"`
+	// The number of confirmations the LSP waits for before the channel can be
+	// used.
+	RequiredChannelConfirmations uint32 `protobuf:"varint,6,opt,name=required_channel_confirmations,json=requiredChannelConfirmations,proto3" json:"required_channel_confirmations,omitempty"`
+	// The number of blocks the funding transaction should confirm in.
+	FundingConfirmsWithinBlocks uint32 `protobuf:"varint,7,opt,name=funding_confirms_within_blocks,json=fundingConfirmsWithinBlocks,proto3" json:"funding_confirms_within_blocks,omitempty"`
+	// The number of blocks the LSP keeps the channel open for at least.
+	ChannelExpiryBlocks uint32 `protobuf:"varint,8,opt,name=channel_expiry_blocks,json=channelExpiryBlocks,proto3" json:"channel_expiry_blocks,omitempty"`
+	// The token the order was created with.
+	Token string `protobuf:"bytes,9,opt,name=token,proto3" json:"token,omitempty"`
+	// The address the LSP refunds on-chain payments to.
+	RefundOnchainAddress string `protobuf:"bytes,10,opt,name=refund_onchain_address,json=refundOnchainAddress,proto3" json:"refund_onchain_address,omitempty"`
+	// Whether the channel is announced to the network.
+	AnnounceChannel bool `protobuf:"varint,11,opt,name=announce_channel,json=announceChannel,proto3" json:"announce_channel,omitempty"`
+	// How to pay for the order with a lightning payment, if supported.
+	Bolt11 *Bolt11Payment `protobuf:"bytes,12,opt,name=bolt11,proto3" json:"bolt11,omitempty"`
+	// How to pay for the order on-chain, if supported.
+	Onchain *OnchainPayment `protobuf:"bytes,13,opt,name=onchain,proto3" json:"onchain,omitempty"`
+	// The channel of the order, once it's funded.
+	Channel *OrderChannel `protobuf:"bytes,14,opt,name=channel,proto3" json:"channel,omitempty"`
+}
+
+func (x *LSPS1Order) Reset() {
+	*x = LSPS1Order{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS1Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS1Order) ProtoMessage() {}
+
+func (x *LSPS1Order) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS1Order.ProtoReflect.Descriptor instead.
+func (*LSPS1Order) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LSPS1Order) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *LSPS1Order) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *LSPS1Order) GetState() LSPS1Order_OrderState {
+	if x != nil {
+		return x.State
+	}
+	return LSPS1Order_ORDER_STATE_UNKNOWN
+}
+
+func (x *LSPS1Order) GetLspBalanceSat() int64 {
+	if x != nil {
+		return x.LspBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1Order) GetClientBalanceSat() int64 {
+	if x != nil {
+		return x.ClientBalanceSat
+	}
+	return 0
+}
+
+func (x *LSPS1Order) GetRequiredChannelConfirmations() uint32 {
+	if x != nil {
+		return x.RequiredChannelConfirmations
+	}
+	return 0
+}
+
+func (x *LSPS1Order) GetFundingConfirmsWithinBlocks() uint32 {
+	if x != nil {
+		return x.FundingConfirmsWithinBlocks
+	}
+	return 0
+}
+
+func (x *LSPS1Order) GetChannelExpiryBlocks() uint32 {
+	if x != nil {
+		return x.ChannelExpiryBlocks
+	}
+	return 0
+}
+
+func (x *LSPS1Order) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LSPS1Order) GetRefundOnchainAddress() string {
+	if x != nil {
+		return x.RefundOnchainAddress
+	}
+	return ""
+}
+
+func (x *LSPS1Order) GetAnnounceChannel() bool {
+	if x != nil {
+		return x.AnnounceChannel
+	}
+	return false
+}
+
+func (x *LSPS1Order) GetBolt11() *Bolt11Payment {
+	if x != nil {
+		return x.Bolt11
+	}
+	return nil
+}
+
+func (x *LSPS1Order) GetOnchain() *OnchainPayment {
+	if x != nil {
+		return x.Onchain
+	}
+	return nil
+}
+
+func (x *LSPS1Order) GetChannel() *OrderChannel {
+	if x != nil {
+		return x.Channel
+	}
+	return nil
+}
+
+type LSPS2GetInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed public key of the LSP.
+	LspPubkey []byte `protobuf:"bytes,1,opt,name=lsp_pubkey,json=lspPubkey,proto3" json:"lsp_pubkey,omitempty"`
+	// An optional token the LSP handed out.
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *LSPS2GetInfoRequest) Reset() {
+	*x = LSPS2GetInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS2GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS2GetInfoRequest) ProtoMessage() {}
+
+func (x *LSPS2GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS2GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*LSPS2GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LSPS2GetInfoRequest) GetLspPubkey() []byte {
+	if x != nil {
+		return x.LspPubkey
+	}
+	return nil
+}
+
+func (x *LSPS2GetInfoRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type OpeningFeeParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The minimum fee the LSP charges for opening a channel.
+	MinFeeMsat uint64 `protobuf:"varint,1,opt,name=min_fee_msat,json=minFeeMsat,proto3" json:"min_fee_msat,omitempty"`
+	// The fee the LSP charges in parts per million of the payment size.
+	Proportional uint32 `protobuf:"varint,2,opt,name=proportional,proto3" json:"proportional,omitempty"`
+	// The time after which the params are no longer valid, in the RFC 3339
+	// format the LSP sent it in.
+	ValidUntil string `protobuf:"bytes,3,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
+	// The number of blocks the LSP keeps the channel open for at least.
+	MinLifetime uint32 `protobuf:"varint,4,opt,name=min_lifetime,json=minLifetime,proto3" json:"min_lifetime,omitempty"`
+	// The maximum to_self_delay the LSP accepts for its funds in the channel.
+	MaxClientToSelfDelay uint32 `protobuf:"varint,5,opt,name=max_client_to_self_delay,json=maxClientToSelfDelay,proto3" json:"max_client_to_self_delay,omitempty"`
+	// The minimum payment size the LSP opens a channel for.
+	MinPaymentSizeMsat uint64 `protobuf:"varint,6,opt,name=min_payment_size_msat,json=minPaymentSizeMsat,proto3" json:"min_payment_size_msat,omitempty"`
+	// The maximum payment size the LSP opens a channel for.
+	MaxPaymentSizeMsat uint64 `protobuf:"varint,7,opt,name=max_payment_size_msat,json=maxPaymentSizeMsat,proto3" json:"max_payment_size_msat,omitempty"`
+	// The LSP's commitment to the params, which must be passed back unchanged
+	// when buying a channel.
+	Promise string `protobuf:"bytes,8,opt,name=promise,proto3" json:"promise,omitempty"`
+}
+
+func (x *OpeningFeeParams) Reset() {
+	*x = OpeningFeeParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpeningFeeParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpeningFeeParams) ProtoMessage() {}
+
+func (x *OpeningFeeParams) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpeningFeeParams.ProtoReflect.Descriptor instead.
+func (*OpeningFeeParams) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OpeningFeeParams) GetMinFeeMsat() uint64 {
+	if x != nil {
+		return x.MinFeeMsat
+	}
+	return 0
+}
+
+func (x *OpeningFeeParams) GetProportional() uint32 {
+	if x != nil {
+		return x.Proportional
+	}
+	return 0
+}
+
+func (x *OpeningFeeParams) GetValidUntil() string {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return ""
+}
+
+func (x *OpeningFeeParams) GetMinLifetime() uint32 {
+	if x != nil {
+		return x.MinLifetime
+	}
+	return 0
+}
+
+func (x *OpeningFeeParams) GetMaxClientToSelfDelay() uint32 {
+	if x != nil {
+		return x.MaxClientToSelfDelay
+	}
+	return 0
+}
+
+func (x *OpeningFeeParams) GetMinPaymentSizeMsat() uint64 {
+	if x != nil {
+		return x.MinPaymentSizeMsat
+	}
+	return 0
+}
+
+func (x *OpeningFeeParams) GetMaxPaymentSizeMsat() uint64 {
+	if x != nil {
+		return x.MaxPaymentSizeMsat
+	}
+	return 0
+}
+
+func (x *OpeningFeeParams) GetPromise() string {
+	if x != nil {
+		return x.Promise
+	}
+	return ""
+}
+
+type LSPS2GetInfoResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The offered opening fee params, ordered by increasing fees.
+	OpeningFeeParamsMenu []*OpeningFeeParams `protobuf:"bytes,1,rep,name=opening_fee_params_menu,json=openingFeeParamsMenu,proto3" json:"opening_fee_params_menu,omitempty"`
+}
+
+func (x *LSPS2GetInfoResponse) Reset() {
+	*x = LSPS2GetInfoResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS2GetInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS2GetInfoResponse) ProtoMessage() {}
+
+func (x *LSPS2GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS2GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*LSPS2GetInfoResponse) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LSPS2GetInfoResponse) GetOpeningFeeParamsMenu() []*OpeningFeeParams {
+	if x != nil {
+		return x.OpeningFeeParamsMenu
+	}
+	return nil
+}
+
+type LSPS2BuyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed public key of the LSP.
+	LspPubkey []byte `protobuf:"bytes,1,opt,name=lsp_pubkey,json=lspPubkey,proto3" json:"lsp_pubkey,omitempty"`
+	// The opening fee params to buy the channel with, as offered by the LSP.
+	OpeningFeeParams *OpeningFeeParams `protobuf:"bytes,2,opt,name=opening_fee_params,json=openingFeeParams,proto3" json:"opening_fee_params,omitempty"`
+	// The size of the payment the channel is bought for. If 0, the invoice may
+	// be paid with any amount the params allow.
+	PaymentSizeMsat uint64 `protobuf:"varint,3,opt,name=payment_size_msat,json=paymentSizeMsat,proto3" json:"payment_size_msat,omitempty"`
+}
+
+func (x *LSPS2BuyRequest) Reset() {
+	*x = LSPS2BuyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSPS2BuyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSPS2BuyRequest) ProtoMessage() {}
+
+func (x *LSPS2BuyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSPS2BuyRequest.ProtoReflect.Descriptor instead.
+func (*LSPS2BuyRequest) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LSPS2BuyRequest) GetLspPubkey() []byte {
+	if x != nil {
+		return x.LspPubkey
+	}
+	return nil
+}
+
+func (x *LSPS2BuyRequest) GetOpeningFeeParams() *OpeningFeeParams {
+	if x != nil {
+		return x.OpeningFeeParams
+	}
+	return nil
+}
+
+func (x *LSPS2BuyRequest) GetPaymentSizeMsat() uint64 {
+	if x != nil {
+		return x.PaymentSizeMsat
+	}
+	return 0
+}
+
+type JITChannel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed public key of the LSP that opens the channel.
+	LspPubkey []byte `protobuf:"bytes,1,opt,name=lsp_pubkey,json=lspPubkey,proto3" json:"lsp_pubkey,omitempty"`
+	// The short channel ID the route hint of the invoice needs to use for the
+	// channel.
+	Scid uint64 `protobuf:"varint,2,opt,name=scid,proto3" json:"scid,omitempty"`
+	// The CLTV delta the LSP requires for the route hint.
+	LspCltvExpiryDelta uint32 `protobuf:"varint,3,opt,name=lsp_cltv_expiry_delta,json=lspCltvExpiryDelta,proto3" json:"lsp_cltv_expiry_delta,omitempty"`
+	// Whether the LSP expects the client to release the preimage before the
+	// funding transaction is published.
+	ClientTrustsLsp bool `protobuf:"varint,4,opt,name=client_trusts_lsp,json=clientTrustsLsp,proto3" json:"client_trusts_lsp,omitempty"`
+	// The opening fee params the channel was bought with.
+	OpeningFeeParams *OpeningFeeParams `protobuf:"bytes,5,opt,name=opening_fee_params,json=openingFeeParams,proto3" json:"opening_fee_params,omitempty"`
+	// The size of the payment the channel was bought for, or 0 if the size
+	// isn't fixed.
+	PaymentSizeMsat uint64 `protobuf:"varint,6,opt,name=payment_size_msat,json=paymentSizeMsat,proto3" json:"payment_size_msat,omitempty"`
+}
+
+func (x *JITChannel) Reset() {
+	*x = JITChannel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JITChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JITChannel) ProtoMessage() {}
+
+func (x *JITChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JITChannel.ProtoReflect.Descriptor instead.
+func (*JITChannel) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *JITChannel) GetLspPubkey() []byte {
+	if x != nil {
+		return x.LspPubkey
+	}
+	return nil
+}
+
+func (x *JITChannel) GetScid() uint64 {
+	if x != nil {
+		return x.Scid
+	}
+	return 0
+}
+
+func (x *JITChannel) GetLspCltvExpiryDelta() uint32 {
+	if x != nil {
+		return x.LspCltvExpiryDelta
+	}
+	return 0
+}
+
+func (x *JITChannel) GetClientTrustsLsp() bool {
+	if x != nil {
+		return x.ClientTrustsLsp
+	}
+	return false
+}
+
+func (x *JITChannel) GetOpeningFeeParams() *OpeningFeeParams {
+	if x != nil {
+		return x.OpeningFeeParams
+	}
+	return nil
+}
+
+func (x *JITChannel) GetPaymentSizeMsat() uint64 {
+	if x != nil {
+		return x.PaymentSizeMsat
+	}
+	return 0
+}
+
+type ListJITChannelsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListJITChannelsRequest) Reset() {
+	*x = ListJITChannelsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJITChannelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJITChannelsRequest) ProtoMessage() {}
+
+func (x *ListJITChannelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJITChannelsRequest.ProtoReflect.Descriptor instead.
+func (*ListJITChannelsRequest) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{15}
+}
+
+type ListJITChannelsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The just-in-time channels the LSPs are still expected to open.
+	Channels []*JITChannel `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+}
+
+func (x *ListJITChannelsResponse) Reset() {
+	*x = ListJITChannelsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lsprpc_lsp_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJITChannelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJITChannelsResponse) ProtoMessage() {}
+
+func (x *ListJITChannelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lsprpc_lsp_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJITChannelsResponse.ProtoReflect.Descriptor instead.
+func (*ListJITChannelsResponse) Descriptor() ([]byte, []int) {
+	return file_lsprpc_lsp_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListJITChannelsResponse) GetChannels() []*JITChannel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+var File_lsprpc_lsp_proto protoreflect.FileDescriptor
+
+var file_lsprpc_lsp_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2f, 0x6c, 0x73, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x06, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x22, 0x35, 0x0a, 0x14, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x73, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x73, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x22, 0x35, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x22, 0x34, 0x0a, 0x13, 0x4c, 0x53, 0x50, 0x53,
+	0x31, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6c, 0x73, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x73, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x22, 0x94,
+	0x05, 0x0a, 0x09, 0x4c, 0x53, 0x50, 0x53, 0x31, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x4b, 0x0a, 0x22,
+	0x6d, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1f, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x4a, 0x0a, 0x22, 0x6d, 0x69, 0x6e,
+	0x5f, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d,
+	0x73, 0x5f, 0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1e, 0x6d, 0x69, 0x6e, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x73, 0x57, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x41, 0x0a, 0x1d, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74,
+	0x73, 0x5f, 0x7a, 0x65, 0x72, 0x6f, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x72,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x73, 0x75,
+	0x70, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x5a, 0x65, 0x72, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x12, 0x39, 0x0a, 0x19, 0x6d, 0x61, 0x78, 0x5f,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x16, 0x6d, 0x61, 0x78,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x73, 0x12, 0x42, 0x0a, 0x1e, 0x6d, 0x69, 0x6e, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x6c, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1a, 0x6d, 0x69, 0x6e,
+	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x42, 0x0a, 0x1e, 0x6d, 0x61, 0x78, 0x5f, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x1a, 0x6d, 0x61, 0x78, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x3c, 0x0a, 0x1b, 0x6d,
+	0x69, 0x6e, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x6c, 0x73, 0x70, 0x5f, 0x62,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x17, 0x6d, 0x69, 0x6e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x4c, 0x73, 0x70, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x3c, 0x0a, 0x1b, 0x6d, 0x61, 0x78,
+	0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x6c, 0x73, 0x70, 0x5f, 0x62, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x17,
+	0x6d, 0x61, 0x78, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x4c, 0x73, 0x70, 0x42, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x35, 0x0a, 0x17, 0x6d, 0x69, 0x6e, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73,
+	0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x6d, 0x69, 0x6e, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x35,
+	0x0a, 0x17, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x62, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x14, 0x6d, 0x61, 0x78, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x53, 0x61, 0x74, 0x22, 0xc4, 0x03, 0x0a, 0x17, 0x4c, 0x53, 0x50, 0x53, 0x31, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x73, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x73, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x73, 0x70, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f,
+	0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c, 0x73, 0x70, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x44, 0x0a, 0x1e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72,
+	0x65, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1c,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x43, 0x0a, 0x1e,
+	0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x73,
+	0x5f, 0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x1b, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x72, 0x6d, 0x73, 0x57, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x79, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x13, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x34, 0x0a, 0x16, 0x72,
+	0x65, 0x66, 0x75, 0x6e, 0x64, 0x5f, 0x6f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x72, 0x65, 0x66,
+	0x75, 0x6e, 0x64, 0x4f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x5f, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x61, 0x6e, 0x6e,
+	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x22, 0x50, 0x0a, 0x14,
+	0x4c, 0x53, 0x50, 0x53, 0x31, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x73, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x73, 0x70, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x64, 0x22, 0xc0,
+	0x01, 0x0a, 0x0d, 0x42, 0x6f, 0x6c, 0x74, 0x31, 0x31, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x2a, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x14, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x66,
+	0x65, 0x65, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x66, 0x65, 0x65, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x61, 0x74, 0x12,
+	0x26, 0x0a, 0x0f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73,
+	0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x54,
+	0x6f, 0x74, 0x61, 0x6c, 0x53, 0x61, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63,
+	0x65, 0x22, 0xbe, 0x02, 0x0a, 0x0e, 0x4f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12,
+	0x22, 0x0a, 0x0d, 0x66, 0x65, 0x65, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x61, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x65, 0x65, 0x54, 0x6f, 0x74, 0x61, 0x6c,
+	0x53, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x61, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x49, 0x0a, 0x21, 0x6d, 0x69, 0x6e, 0x5f, 0x6f, 0x6e, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x1e, 0x6d, 0x69, 0x6e, 0x4f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x30, 0x0a, 0x15, 0x6d, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x66, 0x6f, 0x72, 0x5f,
+	0x7a, 0x65, 0x72, 0x6f, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x11, 0x6d, 0x69, 0x6e, 0x46, 0x65, 0x65, 0x46, 0x6f, 0x72, 0x5a, 0x65, 0x72, 0x6f, 0x43, 0x6f,
+	0x6e, 0x66, 0x22, 0x75, 0x0a, 0x0c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x29, 0x0a, 0x10, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x66, 0x75, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0xe7, 0x05, 0x0a, 0x0a, 0x4c, 0x53,
+	0x50, 0x53, 0x31, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1d, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50, 0x53, 0x31,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x73, 0x70, 0x5f, 0x62,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0d, 0x6c, 0x73, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12,
+	0x2c, 0x0a, 0x12, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x44, 0x0a,
+	0x1e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1c, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x43, 0x0a, 0x1e, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x73, 0x5f, 0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1b, 0x66, 0x75, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x73, 0x57, 0x69, 0x74, 0x68,
+	0x69, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x34, 0x0a, 0x16, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x5f, 0x6f, 0x6e, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x14, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x4f, 0x6e, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x2d, 0x0a, 0x06, 0x62, 0x6f, 0x6c, 0x74, 0x31, 0x31, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x6f, 0x6c,
+	0x74, 0x31, 0x31, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x62, 0x6f, 0x6c, 0x74,
+	0x31, 0x31, 0x12, 0x30, 0x0a, 0x07, 0x6f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x6e, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x6f, 0x6e, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x12, 0x2e, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x07, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x22, 0x4d, 0x0a, 0x0a, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x43,
+	0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4d, 0x50,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45,
+	0x44, 0x10, 0x03, 0x22, 0x4a, 0x0a, 0x13, 0x4c, 0x53, 0x50, 0x53, 0x32, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x73,
+	0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x6c, 0x73, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0xd4, 0x02, 0x0a, 0x10, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x6d, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x5f,
+	0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x46,
+	0x65, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x72,
+	0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x72,
+	0x6f, 0x70, 0x6f, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x6d,
+	0x69, 0x6e, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x4c, 0x69, 0x66, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x36,
+	0x0a, 0x18, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x5f,
+	0x73, 0x65, 0x6c, 0x66, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x14, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x53, 0x65, 0x6c,
+	0x66, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x31, 0x0a, 0x15, 0x6d, 0x69, 0x6e, 0x5f, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6d, 0x69, 0x6e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x31, 0x0a, 0x15, 0x6d, 0x61, 0x78,
+	0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x73,
+	0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x72, 0x6f, 0x6d, 0x69, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x6d, 0x69, 0x73, 0x65, 0x22, 0x67, 0x0a, 0x14, 0x4c, 0x53, 0x50, 0x53, 0x32, 0x47,
+	0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f,
+	0x0a, 0x17, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x5f, 0x6d, 0x65, 0x6e, 0x75, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67,
+	0x46, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x14, 0x6f, 0x70, 0x65, 0x6e, 0x69,
+	0x6e, 0x67, 0x46, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x4d, 0x65, 0x6e, 0x75, 0x22,
+	0xa4, 0x01, 0x0a, 0x0f, 0x4c, 0x53, 0x50, 0x53, 0x32, 0x42, 0x75, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x73, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x73, 0x70, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x12, 0x46, 0x0a, 0x12, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x65,
+	0x65, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x46,
+	0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x10, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e,
+	0x67, 0x46, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x69,
+	0x7a, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x92, 0x02, 0x0a, 0x0a, 0x4a, 0x49, 0x54, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x73, 0x70, 0x5f, 0x70, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x73, 0x70, 0x50, 0x75,
+	0x62, 0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x63, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x73, 0x63, 0x69, 0x64, 0x12, 0x31, 0x0a, 0x15, 0x6c, 0x73, 0x70, 0x5f,
+	0x63, 0x6c, 0x74, 0x76, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f, 0x64, 0x65, 0x6c, 0x74,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x6c, 0x73, 0x70, 0x43, 0x6c, 0x74, 0x76,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x11, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x73, 0x5f, 0x6c, 0x73, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x72,
+	0x75, 0x73, 0x74, 0x73, 0x4c, 0x73, 0x70, 0x12, 0x46, 0x0a, 0x12, 0x6f, 0x70, 0x65, 0x6e, 0x69,
+	0x6e, 0x67, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x70, 0x65,
+	0x6e, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x10, 0x6f,
+	0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12,
+	0x2a, 0x0a, 0x11, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f,
+	0x6d, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x18, 0x0a, 0x16, 0x4c,
+	0x69, 0x73, 0x74, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x49, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x49, 0x54,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2e, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4a, 0x49, 0x54, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73,
+	0x2a, 0x5f, 0x0a, 0x0c, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x19, 0x0a, 0x15, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x45,
+	0x58, 0x50, 0x45, 0x43, 0x54, 0x5f, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x01, 0x12,
+	0x08, 0x0a, 0x04, 0x48, 0x4f, 0x4c, 0x44, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x41, 0x49,
+	0x44, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x46, 0x55, 0x4e, 0x44, 0x45, 0x44, 0x10,
+	0x04, 0x32, 0xf7, 0x03, 0x0a, 0x03, 0x4c, 0x53, 0x50, 0x12, 0x4c, 0x0a, 0x0d, 0x4c, 0x69, 0x73,
+	0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x12, 0x1c, 0x2e, 0x6c, 0x73, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x4c, 0x53, 0x50, 0x53, 0x31,
+	0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x4c, 0x53, 0x50, 0x53, 0x31, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53,
+	0x50, 0x53, 0x31, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x47, 0x0a, 0x10, 0x4c, 0x53, 0x50, 0x53, 0x31,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x1f, 0x2e, 0x6c, 0x73,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50, 0x53, 0x31, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6c,
+	0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50, 0x53, 0x31, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x12, 0x41, 0x0a, 0x0d, 0x4c, 0x53, 0x50, 0x53, 0x31, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x12, 0x1c, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50, 0x53, 0x31,
+	0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x12, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50, 0x53, 0x31, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x12, 0x49, 0x0a, 0x0c, 0x4c, 0x53, 0x50, 0x53, 0x32, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50,
+	0x53, 0x32, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50, 0x53, 0x32, 0x47,
+	0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37,
+	0x0a, 0x08, 0x4c, 0x53, 0x50, 0x53, 0x32, 0x42, 0x75, 0x79, 0x12, 0x17, 0x2e, 0x6c, 0x73, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4c, 0x53, 0x50, 0x53, 0x32, 0x42, 0x75, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4a, 0x49, 0x54,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x52, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4a,
+	0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x1e, 0x2e, 0x6c, 0x73, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6c, 0x73, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2e, 0x5a, 0x2c, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e,
+	0x69, 0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6c, 0x6e, 0x64, 0x2f, 0x6c,
+	0x6e, 0x72, 0x70, 0x63, 0x2f, 0x6c, 0x73, 0x70, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_lsprpc_lsp_proto_rawDescOnce sync.Once
+	file_lsprpc_lsp_proto_rawDescData = file_lsprpc_lsp_proto_rawDesc
+)
+
+func file_lsprpc_lsp_proto_rawDescGZIP() []byte {
+	file_lsprpc_lsp_proto_rawDescOnce.Do(func() {
+		file_lsprpc_lsp_proto_rawDescData = protoimpl.X.CompressGZIP(file_lsprpc_lsp_proto_rawDescData)
+	})
+	return file_lsprpc_lsp_proto_rawDescData
+}
+
+var file_lsprpc_lsp_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_lsprpc_lsp_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_lsprpc_lsp_proto_goTypes = []interface{}{
+	(PaymentState)(0),               // 0: lsprpc.PaymentState
+	(LSPS1Order_OrderState)(0),      // 1: lsprpc.LSPS1Order.OrderState
+	(*ListProtocolsRequest)(nil),    // 2: lsprpc.ListProtocolsRequest
+	(*ListProtocolsResponse)(nil),   // 3: lsprpc.ListProtocolsResponse
+	(*LSPS1GetInfoRequest)(nil),     // 4: lsprpc.LSPS1GetInfoRequest
+	(*LSPS1Info)(nil),               // 5: lsprpc.LSPS1Info
+	(*LSPS1CreateOrderRequest)(nil), // 6: lsprpc.LSPS1CreateOrderRequest
+	(*LSPS1GetOrderRequest)(nil),    // 7: lsprpc.LSPS1GetOrderRequest
+	(*Bolt11Payment)(nil),           // 8: lsprpc.Bolt11Payment
+	(*OnchainPayment)(nil),          // 9: lsprpc.OnchainPayment
+	(*OrderChannel)(nil),            // 10: lsprpc.OrderChannel
+	(*LSPS1Order)(nil),              // 11: lsprpc.LSPS1Order
+	(*LSPS2GetInfoRequest)(nil),     // 12: lsprpc.LSPS2GetInfoRequest
+	(*OpeningFeeParams)(nil),        // 13: lsprpc.OpeningFeeParams
+	(*LSPS2GetInfoResponse)(nil),    // 14: lsprpc.LSPS2GetInfoResponse
+	(*LSPS2BuyRequest)(nil),         // 15: lsprpc.LSPS2BuyRequest
+	(*JITChannel)(nil),              // 16: lsprpc.JITChannel
+	(*ListJITChannelsRequest)(nil),  // 17: lsprpc.ListJITChannelsRequest
+	(*ListJITChannelsResponse)(nil), // 18: lsprpc.ListJITChannelsResponse
+}
+var file_lsprpc_lsp_proto_depIdxs = []int32{
+	0,  // 0: lsprpc.Bolt11Payment.state:type_name -> lsprpc.PaymentState
+	0,  // 1: lsprpc.OnchainPayment.state:type_name -> lsprpc.PaymentState
+	1,  // 2: lsprpc.LSPS1Order.state:type_name -> lsprpc.LSPS1Order.OrderState
+	8,  // 3: lsprpc.LSPS1Order.bolt11:type_name -> lsprpc.Bolt11Payment
+	9,  // 4: lsprpc.LSPS1Order.onchain:type_name -> lsprpc.OnchainPayment
+	10, // 5: lsprpc.LSPS1Order.channel:type_name -> lsprpc.OrderChannel
+	13, // 6: lsprpc.LSPS2GetInfoResponse.opening_fee_params_menu:type_name -> lsprpc.OpeningFeeParams
+	13, // 7: lsprpc.LSPS2BuyRequest.opening_fee_params:type_name -> lsprpc.OpeningFeeParams
+	13, // 8: lsprpc.JITChannel.opening_fee_params:type_name -> lsprpc.OpeningFeeParams
+	16, // 9: lsprpc.ListJITChannelsResponse.channels:type_name -> lsprpc.JITChannel
+	2,  // 10: lsprpc.LSP.ListProtocols:input_type -> lsprpc.ListProtocolsRequest
+	4,  // 11: lsprpc.LSP.LSPS1GetInfo:input_type -> lsprpc.LSPS1GetInfoRequest
+	6,  // 12: lsprpc.LSP.LSPS1CreateOrder:input_type -> lsprpc.LSPS1CreateOrderRequest
+	7,  // 13: lsprpc.LSP.LSPS1GetOrder:input_type -> lsprpc.LSPS1GetOrderRequest
+	12, // 14: lsprpc.LSP.LSPS2GetInfo:input_type -> lsprpc.LSPS2GetInfoRequest
+	15, // 15: lsprpc.LSP.LSPS2Buy:input_type -> lsprpc.LSPS2BuyRequest
+	17, // 16: lsprpc.LSP.ListJITChannels:input_type -> lsprpc.ListJITChannelsRequest
+	3,  // 17: lsprpc.LSP.ListProtocols:output_type -> lsprpc.ListProtocolsResponse
+	5,  // 18: lsprpc.LSP.LSPS1GetInfo:output_type -> lsprpc.LSPS1Info
+	11, // 19: lsprpc.LSP.LSPS1CreateOrder:output_type -> lsprpc.LSPS1Order
+	11, // 20: lsprpc.LSP.LSPS1GetOrder:output_type -> lsprpc.LSPS1Order
+	14, // 21: lsprpc.LSP.LSPS2GetInfo:output_type -> lsprpc.LSPS2GetInfoResponse
+	16, // 22: lsprpc.LSP.LSPS2Buy:output_type -> lsprpc.JITChannel
+	18, // 23: lsprpc.LSP.ListJITChannels:output_type -> lsprpc.ListJITChannelsResponse
+	17, // [17:24] is the sub-list for method output_type
+	10, // [10:17] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_lsprpc_lsp_proto_init() }
+func file_lsprpc_lsp_proto_init() {
+	if File_lsprpc_lsp_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_lsprpc_lsp_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProtocolsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProtocolsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS1GetInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS1Info); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS1CreateOrderRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS1GetOrderRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bolt11Payment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OnchainPayment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderChannel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS1Order); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS2GetInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpeningFeeParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS2GetInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSPS2BuyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JITChannel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJITChannelsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lsprpc_lsp_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJITChannelsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_lsprpc_lsp_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lsprpc_lsp_proto_goTypes,
+		DependencyIndexes: file_lsprpc_lsp_proto_depIdxs,
+		EnumInfos:         file_lsprpc_lsp_proto_enumTypes,
+		MessageInfos:      file_lsprpc_lsp_proto_msgTypes,
+	}.Build()
+	File_lsprpc_lsp_proto = out.File
+	file_lsprpc_lsp_proto_rawDesc = nil
+	file_lsprpc_lsp_proto_goTypes = nil
+	file_lsprpc_lsp_proto_depIdxs = nil
+}
@@ -0,0 +1,359 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package lsprpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// LSPClient is the client API for LSP service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LSPClient interface {
+	// lncli: lsp listprotocols
+	// ListProtocols returns the LSP specifications the given LSP supports
+	// (LSPS0).
+	ListProtocols(ctx context.Context, in *ListProtocolsRequest, opts ...grpc.CallOption) (*ListProtocolsResponse, error)
+	// lncli: lsp lsps1getinfo
+	// LSPS1GetInfo returns the channels the given LSP sells (LSPS1).
+	LSPS1GetInfo(ctx context.Context, in *LSPS1GetInfoRequest, opts ...grpc.CallOption) (*LSPS1Info, error)
+	// lncli: lsp lsps1createorder
+	// LSPS1CreateOrder orders a channel from the given LSP (LSPS1). The returned
+	// order holds the invoice or address to pay the LSP with.
+	LSPS1CreateOrder(ctx context.Context, in *LSPS1CreateOrderRequest, opts ...grpc.CallOption) (*LSPS1Order, error)
+	// lncli: lsp lsps1getorder
+	// LSPS1GetOrder returns the current state of a channel order (LSPS1).
+	LSPS1GetOrder(ctx context.Context, in *LSPS1GetOrderRequest, opts ...grpc.CallOption) (*LSPS1Order, error)
+	// lncli: lsp lsps2getinfo
+	// LSPS2GetInfo returns the opening fee params the given LSP offers
+	// just-in-time channels for (LSPS2).
+	LSPS2GetInfo(ctx context.Context, in *LSPS2GetInfoRequest, opts ...grpc.CallOption) (*LSPS2GetInfoResponse, error)
+	// lncli: lsp lsps2buy
+	// LSPS2Buy buys a just-in-time channel from the given LSP (LSPS2). The hop
+	// hint of the returned channel needs to be added to the invoice that is paid
+	// through it. Until the opening fee params expire, a zero-conf channel from
+	// the LSP is accepted for it.
+	LSPS2Buy(ctx context.Context, in *LSPS2BuyRequest, opts ...grpc.CallOption) (*JITChannel, error)
+	// lncli: lsp listjitchannels
+	// ListJITChannels returns the just-in-time channels we bought and still
+	// expect the LSPs to open.
+	ListJITChannels(ctx context.Context, in *ListJITChannelsRequest, opts ...grpc.CallOption) (*ListJITChannelsResponse, error)
+}
+
+type lSPClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLSPClient(cc grpc.ClientConnInterface) LSPClient {
+	return &lSPClient{cc}
+}
+
+func (c *lSPClient) ListProtocols(ctx context.Context, in *ListProtocolsRequest, opts ...grpc.CallOption) (*ListProtocolsResponse, error) {
+	out := new(ListProtocolsResponse)
+	err := c.cc.Invoke(ctx, "/lsprpc.LSP/ListProtocols", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lSPClient) LSPS1GetInfo(ctx context.Context, in *LSPS1GetInfoRequest, opts ...grpc.CallOption) (*LSPS1Info, error) {
+	out := new(LSPS1Info)
+	err := c.cc.Invoke(ctx, "/lsprpc.LSP/LSPS1GetInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lSPClient) LSPS1CreateOrder(ctx context.Context, in *LSPS1CreateOrderRequest, opts ...grpc.CallOption) (*LSPS1Order, error) {
+	out := new(LSPS1Order)
+	err := c.cc.Invoke(ctx, "/lsprpc.LSP/LSPS1CreateOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lSPClient) LSPS1GetOrder(ctx context.Context, in *LSPS1GetOrderRequest, opts ...grpc.CallOption) (*LSPS1Order, error) {
+	out := new(LSPS1Order)
+	err := c.cc.Invoke(ctx, "/lsprpc.LSP/LSPS1GetOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lSPClient) LSPS2GetInfo(ctx context.Context, in *LSPS2GetInfoRequest, opts ...grpc.CallOption) (*LSPS2GetInfoResponse, error) {
+	out := new(LSPS2GetInfoResponse)
+	err := c.cc.Invoke(ctx, "/lsprpc.LSP/LSPS2GetInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lSPClient) LSPS2Buy(ctx context.Context, in *LSPS2BuyRequest, opts ...grpc.CallOption) (*JITChannel, error) {
+	out := new(JITChannel)
+	err := c.cc.Invoke(ctx, "/lsprpc.LSP/LSPS2Buy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lSPClient) ListJITChannels(ctx context.Context, in *ListJITChannelsRequest, opts ...grpc.CallOption) (*ListJITChannelsResponse, error) {
+	out := new(ListJITChannelsResponse)
+	err := c.cc.Invoke(ctx, "/lsprpc.LSP/ListJITChannels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LSPServer is the server API for LSP service.
+// All implementations must embed UnimplementedLSPServer
+// for forward compatibility
+type LSPServer interface {
+	// lncli: lsp listprotocols
+	// ListProtocols returns the LSP specifications the given LSP supports
+	// (LSPS0).
+	ListProtocols(context.Context, *ListProtocolsRequest) (*ListProtocolsResponse, error)
+	// lncli: lsp lsps1getinfo
+	// LSPS1GetInfo returns the channels the given LSP sells (LSPS1).
+	LSPS1GetInfo(context.Context, *LSPS1GetInfoRequest) (*LSPS1Info, error)
+	// lncli: lsp lsps1createorder
+	// LSPS1CreateOrder orders a channel from the given LSP (LSPS1). The returned
+	// order holds the invoice or address to pay the LSP with.
+	LSPS1CreateOrder(context.Context, *LSPS1CreateOrderRequest) (*LSPS1Order, error)
+	// lncli: lsp lsps1getorder
+	// LSPS1GetOrder returns the current state of a channel order (LSPS1).
+	LSPS1GetOrder(context.Context, *LSPS1GetOrderRequest) (*LSPS1Order, error)
+	// lncli: lsp lsps2getinfo
+	// LSPS2GetInfo returns the opening fee params the given LSP offers
+	// just-in-time channels for (LSPS2).
+	LSPS2GetInfo(context.Context, *LSPS2GetInfoRequest) (*LSPS2GetInfoResponse, error)
+	// lncli: lsp lsps2buy
+	// LSPS2Buy buys a just-in-time channel from the given LSP (LSPS2). The hop
+	// hint of the returned channel needs to be added to the invoice that is paid
+	// through it. Until the opening fee params expire, a zero-conf channel from
+	// the LSP is accepted for it.
+	LSPS2Buy(context.Context, *LSPS2BuyRequest) (*JITChannel, error)
+	// lncli: lsp listjitchannels
+	// ListJITChannels returns the just-in-time channels we bought and still
+	// expect the LSPs to open.
+	ListJITChannels(context.Context, *ListJITChannelsRequest) (*ListJITChannelsResponse, error)
+	mustEmbedUnimplementedLSPServer()
+}
+
+// UnimplementedLSPServer must be embedded to have forward compatible implementations.
+type UnimplementedLSPServer struct {
+}
+
+func (UnimplementedLSPServer) ListProtocols(context.Context, *ListProtocolsRequest) (*ListProtocolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProtocols not implemented")
+}
+func (UnimplementedLSPServer) LSPS1GetInfo(context.Context, *LSPS1GetInfoRequest) (*LSPS1Info, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LSPS1GetInfo not implemented")
+}
+func (UnimplementedLSPServer) LSPS1CreateOrder(context.Context, *LSPS1CreateOrderRequest) (*LSPS1Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LSPS1CreateOrder not implemented")
+}
+func (UnimplementedLSPServer) LSPS1GetOrder(context.Context, *LSPS1GetOrderRequest) (*LSPS1Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LSPS1GetOrder not implemented")
+}
+func (UnimplementedLSPServer) LSPS2GetInfo(context.Context, *LSPS2GetInfoRequest) (*LSPS2GetInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LSPS2GetInfo not implemented")
+}
+func (UnimplementedLSPServer) LSPS2Buy(context.Context, *LSPS2BuyRequest) (*JITChannel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LSPS2Buy not implemented")
+}
+func (UnimplementedLSPServer) ListJITChannels(context.Context, *ListJITChannelsRequest) (*ListJITChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJITChannels not implemented")
+}
+func (UnimplementedLSPServer) mustEmbedUnimplementedLSPServer() {}
+
+// UnsafeLSPServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LSPServer will
+// result in compilation errors.
+type UnsafeLSPServer interface {
+	mustEmbedUnimplementedLSPServer()
+}
+
+func RegisterLSPServer(s grpc.ServiceRegistrar, srv LSPServer) {
+	s.RegisterService(&LSP_ServiceDesc, srv)
+}
+
+func _LSP_ListProtocols_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProtocolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LSPServer).ListProtocols(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lsprpc.LSP/ListProtocols",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LSPServer).ListProtocols(ctx, req.(*ListProtocolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LSP_LSPS1GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LSPS1GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LSPServer).LSPS1GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lsprpc.LSP/LSPS1GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LSPServer).LSPS1GetInfo(ctx, req.(*LSPS1GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LSP_LSPS1CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LSPS1CreateOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LSPServer).LSPS1CreateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lsprpc.LSP/LSPS1CreateOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LSPServer).LSPS1CreateOrder(ctx, req.(*LSPS1CreateOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LSP_LSPS1GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LSPS1GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LSPServer).LSPS1GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lsprpc.LSP/LSPS1GetOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LSPServer).LSPS1GetOrder(ctx, req.(*LSPS1GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LSP_LSPS2GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LSPS2GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LSPServer).LSPS2GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lsprpc.LSP/LSPS2GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LSPServer).LSPS2GetInfo(ctx, req.(*LSPS2GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LSP_LSPS2Buy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LSPS2BuyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LSPServer).LSPS2Buy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lsprpc.LSP/LSPS2Buy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LSPServer).LSPS2Buy(ctx, req.(*LSPS2BuyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LSP_ListJITChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJITChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LSPServer).ListJITChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lsprpc.LSP/ListJITChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LSPServer).ListJITChannels(ctx, req.(*ListJITChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LSP_ServiceDesc is the grpc.ServiceDesc for LSP service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LSP_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lsprpc.LSP",
+	HandlerType: (*LSPServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListProtocols",
+			Handler:    _LSP_ListProtocols_Handler,
+		},
+		{
+			MethodName: "LSPS1GetInfo",
+			Handler:    _LSP_LSPS1GetInfo_Handler,
+		},
+		{
+			MethodName: "LSPS1CreateOrder",
+			Handler:    _LSP_LSPS1CreateOrder_Handler,
+		},
+		{
+			MethodName: "LSPS1GetOrder",
+			Handler:    _LSP_LSPS1GetOrder_Handler,
+		},
+		{
+			MethodName: "LSPS2GetInfo",
+			Handler:    _LSP_LSPS2GetInfo_Handler,
+		},
+		{
+			MethodName: "LSPS2Buy",
+			Handler:    _LSP_LSPS2Buy_Handler,
+		},
+		{
+			MethodName: "ListJITChannels",
+			Handler:    _LSP_ListJITChannels_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lsprpc/lsp.proto",
+}
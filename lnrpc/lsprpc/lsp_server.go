@@ -0,0 +1,361 @@
+//go:build lsprpc
+// +build lsprpc
+
+package lsprpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+const (
+	// subServerName is the name of the sub rpc server. We'll use this name
+	// to register ourselves, and we also require that the main
+	// SubServerConfigDispatcher instance recognize it as the name of our
+	// RPC service.
+	subServerName = "LspRPC"
+)
+
+var (
+	// macPermissions maps RPC calls to the permissions they require.
+	macPermissions = map[string][]bakery.Op{
+		"/lsprpc.LSP/ListProtocols": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lsprpc.LSP/LSPS1GetInfo": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lsprpc.LSP/LSPS1CreateOrder": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lsprpc.LSP/LSPS1GetOrder": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lsprpc.LSP/LSPS2GetInfo": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lsprpc.LSP/LSPS2Buy": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lsprpc.LSP/ListJITChannels": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+	}
+)
+
+// ServerShell is a shell struct holding a reference to the actual sub-server.
+// It is used to register the gRPC sub-server with the root server before we
+// have the necessary dependencies to populate the actual sub-server.
+type ServerShell struct {
+	*Server
+}
+
+// Server is a sub-server of the main RPC server: the LSP RPC. This sub RPC
+// server allows wallets built on lnd to order channels from LSPs and to buy
+// just-in-time channels from them, following the LSP specifications.
+type Server struct {
+	started  int32 // To be used atomically.
+	shutdown int32 // To be used atomically.
+
+	// Required by the grpc-gateway/v2 library for forward compatibility.
+	// Must be after the atomically used variables to not break struct
+	// alignment.
+	UnimplementedLSPServer
+
+	cfg *Config
+}
+
+// A compile time check to ensure that Server fully implements the LSPServer
+// gRPC service.
+var _ LSPServer = (*Server)(nil)
+
+// New returns a new instance of the lsprpc LSP sub-server. We also return the
+// set of permissions for the macaroons that we may create within this method.
+func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
+	server := &Server{
+		cfg: cfg,
+	}
+
+	return server, macPermissions, nil
+}
+
+// Start launches any helper goroutines required for the Server to function.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Start() error {
+	if atomic.AddInt32(&s.started, 1) != 1 {
+		return nil
+	}
+
+	return nil
+}
+
+// Stop signals any active goroutines for a graceful closure.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Stop() error {
+	if atomic.AddInt32(&s.shutdown, 1) != 1 {
+		return nil
+	}
+
+	return nil
+}
+
+// Name returns a unique string representation of the sub-server. This can be
+// used to identify the sub-server and also de-duplicate them.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Name() string {
+	return subServerName
+}
+
+// RegisterWithRootServer will be called by the root gRPC server to direct a
+// sub RPC server to register itself with the main gRPC root server. Until this
+// is called, each sub-server won't be able to have requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) RegisterWithRootServer(grpcServer *grpc.Server) error {
+	// We make sure that we register it with the main gRPC server to ensure
+	// all our methods are routed properly.
+	RegisterLSPServer(grpcServer, r)
+
+	log.Debugf("LSP RPC server successfully registered with root gRPC " +
+		"server")
+
+	return nil
+}
+
+// RegisterWithRestServer will be called by the root REST mux to direct a sub
+// RPC server to register itself with the main REST mux server. Until this is
+// called, each sub-server won't be able to have requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) RegisterWithRestServer(ctx context.Context,
+	mux *runtime.ServeMux, dest string, opts []grpc.DialOption) error {
+
+	// We make sure that we register it with the main REST server to ensure
+	// all our methods are routed properly.
+	err := RegisterLSPHandlerFromEndpoint(ctx, mux, dest, opts)
+	if err != nil {
+		log.Errorf("Could not register LSP REST server with root "+
+			"REST server: %v", err)
+		return err
+	}
+
+	log.Debugf("LSP REST server successfully registered with root REST " +
+		"server")
+
+	return nil
+}
+
+// CreateSubServer populates the subserver's dependencies using the passed
+// SubServerConfigDispatcher. This method should fully initialize the
+// sub-server instance, making it ready for action. It returns the macaroon
+// permissions that the sub-server wishes to pass on to the root server for all
+// methods routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) CreateSubServer(
+	configRegistry lnrpc.SubServerConfigDispatcher) (lnrpc.SubServer,
+	lnrpc.MacaroonPerms, error) {
+
+	subServer, macPermissions, err := createNewSubServer(configRegistry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.Server = subServer
+	return subServer, macPermissions, nil
+}
+
+// ListProtocols returns the LSP specifications the given LSP supports.
+func (s *Server) ListProtocols(ctx context.Context,
+	in *ListProtocolsRequest) (*ListProtocolsResponse, error) {
+
+	lsp, err := parseLSP(in.LspPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("ListProtocols called for LSP %v", lsp)
+
+	protocols, err := s.cfg.LSPClient.ListProtocols(ctx, lsp)
+	if err != nil {
+		return nil, lspErr(err)
+	}
+
+	resp := &ListProtocolsResponse{
+		Protocols: make([]uint32, 0, len(protocols)),
+	}
+	for _, protocol := range protocols {
+		resp.Protocols = append(resp.Protocols, uint32(protocol))
+	}
+
+	return resp, nil
+}
+
+// LSPS1GetInfo returns the channels the given LSP sells.
+func (s *Server) LSPS1GetInfo(ctx context.Context,
+	in *LSPS1GetInfoRequest) (*LSPS1Info, error) {
+
+	lsp, err := parseLSP(in.LspPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("LSPS1GetInfo called for LSP %v", lsp)
+
+	info, err := s.cfg.LSPClient.LSPS1GetInfo(ctx, lsp)
+	if err != nil {
+		return nil, lspErr(err)
+	}
+
+	return marshalLSPS1Info(info), nil
+}
+
+// LSPS1CreateOrder orders a channel from the given LSP. The returned order
+// holds the invoice or address to pay the LSP with.
+func (s *Server) LSPS1CreateOrder(ctx context.Context,
+	in *LSPS1CreateOrderRequest) (*LSPS1Order, error) {
+
+	lsp, err := parseLSP(in.LspPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("LSPS1CreateOrder called for LSP %v", lsp)
+
+	order, err := s.cfg.LSPClient.LSPS1CreateOrder(
+		ctx, lsp, unmarshalOrderRequest(in),
+	)
+	if err != nil {
+		return nil, lspErr(err)
+	}
+
+	return marshalOrder(order), nil
+}
+
+// LSPS1GetOrder returns the current state of a channel order.
+func (s *Server) LSPS1GetOrder(ctx context.Context,
+	in *LSPS1GetOrderRequest) (*LSPS1Order, error) {
+
+	lsp, err := parseLSP(in.LspPubkey)
+	if err != nil {
+		return nil, err
+	}
+	if in.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id "+
+			"must be set")
+	}
+
+	log.Debugf("LSPS1GetOrder called for order %v of LSP %v", in.OrderId,
+		lsp)
+
+	order, err := s.cfg.LSPClient.LSPS1GetOrder(ctx, lsp, in.OrderId)
+	if err != nil {
+		return nil, lspErr(err)
+	}
+
+	return marshalOrder(order), nil
+}
+
+// LSPS2GetInfo returns the opening fee params the given LSP offers just-in-time
+// channels for.
+func (s *Server) LSPS2GetInfo(ctx context.Context,
+	in *LSPS2GetInfoRequest) (*LSPS2GetInfoResponse, error) {
+
+	lsp, err := parseLSP(in.LspPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("LSPS2GetInfo called for LSP %v", lsp)
+
+	info, err := s.cfg.LSPClient.LSPS2GetInfo(ctx, lsp, in.Token)
+	if err != nil {
+		return nil, lspErr(err)
+	}
+
+	resp := &LSPS2GetInfoResponse{
+		OpeningFeeParamsMenu: make(
+			[]*OpeningFeeParams, 0, len(info.OpeningFeeParamsMenu),
+		),
+	}
+	for _, params := range info.OpeningFeeParamsMenu {
+		resp.OpeningFeeParamsMenu = append(
+			resp.OpeningFeeParamsMenu,
+			marshalOpeningFeeParams(params),
+		)
+	}
+
+	return resp, nil
+}
+
+// LSPS2Buy buys a just-in-time channel from the given LSP. The hop hint of the
+// returned channel needs to be added to the invoice that is paid through it.
+// Until the opening fee params expire, a zero-conf channel from the LSP is
+// accepted for it.
+func (s *Server) LSPS2Buy(ctx context.Context,
+	in *LSPS2BuyRequest) (*JITChannel, error) {
+
+	lsp, err := parseLSP(in.LspPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := unmarshalOpeningFeeParams(in.OpeningFeeParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var paymentSize *lnwire.MilliSatoshi
+	if in.PaymentSizeMsat != 0 {
+		size := lnwire.MilliSatoshi(in.PaymentSizeMsat)
+		paymentSize = &size
+	}
+
+	log.Debugf("LSPS2Buy called for LSP %v", lsp)
+
+	jitChannel, err := s.cfg.LSPClient.LSPS2Buy(
+		ctx, lsp, params, paymentSize,
+	)
+	if err != nil {
+		return nil, lspErr(err)
+	}
+
+	return marshalJITChannel(jitChannel), nil
+}
+
+// ListJITChannels returns the just-in-time channels we bought and still expect
+// the LSPs to open.
+func (s *Server) ListJITChannels(_ context.Context,
+	_ *ListJITChannelsRequest) (*ListJITChannelsResponse, error) {
+
+	jitChannels := s.cfg.LSPClient.JITChannels()
+
+	resp := &ListJITChannelsResponse{
+		Channels: make([]*JITChannel, 0, len(jitChannels)),
+	}
+	for _, jitChannel := range jitChannels {
+		resp.Channels = append(
+			resp.Channels, marshalJITChannel(jitChannel),
+		)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,190 @@
+//go:build lsprpc
+// +build lsprpc
+
+package lsprpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lsps"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testTime is the current time of the test clock.
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestServer creates a server whose LSP client is answered by the given
+// results, by method.
+func newTestServer(t *testing.T,
+	results map[string]interface{}) *Server {
+
+	var client *lsps.Client
+	client = lsps.NewClient(&lsps.Config{
+		SendCustomMessage: func(peer [33]byte,
+			_ lnwire.MessageType, data []byte) error {
+
+			var req struct {
+				Method string `json:"method"`
+				ID     string `json:"id"`
+			}
+			require.NoError(t, json.Unmarshal(data, &req))
+
+			respData, err := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"result":  results[req.Method],
+			})
+			require.NoError(t, err)
+
+			go client.HandleMessage(peer, respData)
+
+			return nil
+		},
+		Clock:          clock.NewTestClock(testTime),
+		RequestTimeout: time.Second,
+	})
+	require.NoError(t, client.Start())
+	t.Cleanup(func() {
+		require.NoError(t, client.Stop())
+	})
+
+	return &Server{cfg: &Config{LSPClient: client}}
+}
+
+// TestLSPRPCs tests that the LSP client is reachable through the RPCs, and
+// that invalid requests are rejected with the matching status codes.
+func TestLSPRPCs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	lsp := priv.PubKey().SerializeCompressed()
+
+	validUntil := testTime.Add(time.Hour)
+	s := newTestServer(t, map[string]interface{}{
+		"lsps0.list_protocols": map[string]interface{}{
+			"protocols": []uint16{1, 2},
+		},
+		"lsps1.create_order": map[string]interface{}{
+			"order_id":        "order",
+			"lsp_balance_sat": "100000",
+			"order_state":     "CREATED",
+			"created_at":      testTime,
+			"payment": map[string]interface{}{
+				"bolt11": map[string]interface{}{
+					"state":           "EXPECT_PAYMENT",
+					"expires_at":      validUntil,
+					"fee_total_sat":   "1000",
+					"order_total_sat": "1000",
+					"invoice":         "lnbc",
+				},
+			},
+		},
+		"lsps2.get_info": map[string]interface{}{
+			"opening_fee_params_menu": []map[string]interface{}{{
+				"min_fee_msat":             "546000",
+				"proportional":             1200,
+				"valid_until":              validUntil,
+				"min_lifetime":             1000,
+				"max_client_to_self_delay": 2016,
+				"min_payment_size_msat":    "1000",
+				"max_payment_size_msat":    "1000000000",
+				"promise":                  "promise",
+			}},
+		},
+		"lsps2.buy": map[string]interface{}{
+			"jit_channel_scid":      "1x2x3",
+			"lsp_cltv_expiry_delta": 144,
+		},
+	})
+
+	// Invalid public keys are rejected.
+	_, err = s.ListProtocols(ctx, &ListProtocolsRequest{
+		LspPubkey: lsp[:32],
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	protocols, err := s.ListProtocols(ctx, &ListProtocolsRequest{
+		LspPubkey: lsp,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1, 2}, protocols.Protocols)
+
+	order, err := s.LSPS1CreateOrder(ctx, &LSPS1CreateOrderRequest{
+		LspPubkey:     lsp,
+		LspBalanceSat: 100_000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "order", order.OrderId)
+	require.Equal(t, LSPS1Order_CREATED, order.State)
+	require.Equal(t, testTime.Unix(), order.CreatedAt)
+	require.EqualValues(t, 100_000, order.LspBalanceSat)
+	require.Equal(t, PaymentState_EXPECT_PAYMENT, order.Bolt11.State)
+	require.Equal(t, "lnbc", order.Bolt11.Invoice)
+	require.Nil(t, order.Onchain)
+	require.Nil(t, order.Channel)
+
+	_, err = s.LSPS1GetOrder(ctx, &LSPS1GetOrderRequest{LspPubkey: lsp})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	info, err := s.LSPS2GetInfo(ctx, &LSPS2GetInfoRequest{
+		LspPubkey: lsp,
+	})
+	require.NoError(t, err)
+	require.Len(t, info.OpeningFeeParamsMenu, 1)
+	params := info.OpeningFeeParamsMenu[0]
+	require.EqualValues(t, 546_000, params.MinFeeMsat)
+	require.Equal(t, "promise", params.Promise)
+
+	// Buying a channel requires fee params, and the payment size must be
+	// in their range.
+	_, err = s.LSPS2Buy(ctx, &LSPS2BuyRequest{LspPubkey: lsp})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.LSPS2Buy(ctx, &LSPS2BuyRequest{
+		LspPubkey:        lsp,
+		OpeningFeeParams: params,
+		PaymentSizeMsat:  1,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The params returned by LSPS2GetInfo can be passed back unchanged.
+	jitChannel, err := s.LSPS2Buy(ctx, &LSPS2BuyRequest{
+		LspPubkey:        lsp,
+		OpeningFeeParams: params,
+		PaymentSizeMsat:  10_000_000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, lsp, jitChannel.LspPubkey)
+	scid := lnwire.ShortChannelID{
+		BlockHeight: 1,
+		TxIndex:     2,
+		TxPosition:  3,
+	}
+	require.Equal(t, scid.ToUint64(), jitChannel.Scid)
+	require.EqualValues(t, 144, jitChannel.LspCltvExpiryDelta)
+	require.EqualValues(t, 10_000_000, jitChannel.PaymentSizeMsat)
+	require.Equal(t, params, jitChannel.OpeningFeeParams)
+
+	channels, err := s.ListJITChannels(ctx, &ListJITChannelsRequest{})
+	require.NoError(t, err)
+	require.Len(t, channels.Channels, 1)
+	require.Equal(t, jitChannel.Scid, channels.Channels[0].Scid)
+
+	// Expired params are rejected.
+	params.ValidUntil = testTime.Format(time.RFC3339Nano)
+	_, err = s.LSPS2Buy(ctx, &LSPS2BuyRequest{
+		LspPubkey:        lsp,
+		OpeningFeeParams: params,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
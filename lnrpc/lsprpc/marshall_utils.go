@@ -0,0 +1,259 @@
+//go:build lsprpc
+// +build lsprpc
+
+package lsprpc
+
+import (
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lsps"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseLSP parses the public key of an LSP.
+func parseLSP(pubKey []byte) (route.Vertex, error) {
+	if _, err := btcec.ParsePubKey(pubKey); err != nil {
+		return route.Vertex{}, status.Errorf(codes.InvalidArgument,
+			"invalid lsp_pubkey: %v", err)
+	}
+
+	return route.NewVertexFromBytes(pubKey)
+}
+
+// lspErr converts an error of the LSP client into a gRPC status error.
+func lspErr(err error) error {
+	switch {
+	case errors.Is(err, lsps.ErrRequestTimeout):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+
+	case errors.Is(err, lsps.ErrFeeParamsExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+
+	case errors.Is(err, lsps.ErrPaymentSizeOutOfRange):
+		return status.Error(codes.InvalidArgument, err.Error())
+
+	default:
+		return err
+	}
+}
+
+// unixOrZero returns the unix timestamp of the given time in seconds, or 0 if
+// the time isn't set.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+// marshalLSPS1Info converts the LSPS1 info of an LSP into its RPC
+// representation.
+func marshalLSPS1Info(info *lsps.LSPS1Info) *LSPS1Info {
+	return &LSPS1Info{
+		MinRequiredChannelConfirmations: uint32(
+			info.MinRequiredChannelConfirmations,
+		),
+		MinFundingConfirmsWithinBlocks: uint32(
+			info.MinFundingConfirmsWithinBlocks,
+		),
+		SupportsZeroChannelReserve: info.SupportsZeroChannelReserve,
+		MaxChannelExpiryBlocks:     info.MaxChannelExpiryBlocks,
+		MinInitialClientBalanceSat: int64(info.MinInitialClientBalance),
+		MaxInitialClientBalanceSat: int64(info.MaxInitialClientBalance),
+		MinInitialLspBalanceSat:    int64(info.MinInitialLSPBalance),
+		MaxInitialLspBalanceSat:    int64(info.MaxInitialLSPBalance),
+		MinChannelBalanceSat:       int64(info.MinChannelBalance),
+		MaxChannelBalanceSat:       int64(info.MaxChannelBalance),
+	}
+}
+
+// unmarshalOrderRequest converts an RPC channel order into the order the LSP
+// client sends.
+func unmarshalOrderRequest(in *LSPS1CreateOrderRequest) *lsps.OrderRequest {
+	return &lsps.OrderRequest{
+		LSPBalance:    btcutil.Amount(in.LspBalanceSat),
+		ClientBalance: btcutil.Amount(in.ClientBalanceSat),
+		RequiredChannelConfirmations: uint16(
+			in.RequiredChannelConfirmations,
+		),
+		FundingConfirmsWithinBlocks: uint16(
+			in.FundingConfirmsWithinBlocks,
+		),
+		ChannelExpiryBlocks:  in.ChannelExpiryBlocks,
+		Token:                in.Token,
+		RefundOnchainAddress: in.RefundOnchainAddress,
+		AnnounceChannel:      in.AnnounceChannel,
+	}
+}
+
+// marshalPaymentState converts the state of an order payment into its RPC
+// representation.
+func marshalPaymentState(state lsps.PaymentState) PaymentState {
+	switch state {
+	case lsps.PaymentStateExpectPayment:
+		return PaymentState_EXPECT_PAYMENT
+
+	case lsps.PaymentStateHold:
+		return PaymentState_HOLD
+
+	case lsps.PaymentStatePaid:
+		return PaymentState_PAID
+
+	case lsps.PaymentStateRefunded:
+		return PaymentState_REFUNDED
+
+	default:
+		return PaymentState_PAYMENT_STATE_UNKNOWN
+	}
+}
+
+// marshalOrderState converts the state of an order into its RPC
+// representation.
+func marshalOrderState(state lsps.OrderState) LSPS1Order_OrderState {
+	switch state {
+	case lsps.OrderStateCreated:
+		return LSPS1Order_CREATED
+
+	case lsps.OrderStateCompleted:
+		return LSPS1Order_COMPLETED
+
+	case lsps.OrderStateFailed:
+		return LSPS1Order_FAILED
+
+	default:
+		return LSPS1Order_ORDER_STATE_UNKNOWN
+	}
+}
+
+// marshalOrder converts a channel order into its RPC representation.
+func marshalOrder(order *lsps.Order) *LSPS1Order {
+	rpcOrder := &LSPS1Order{
+		OrderId:          order.OrderID,
+		CreatedAt:        unixOrZero(order.CreatedAt),
+		State:            marshalOrderState(order.State),
+		LspBalanceSat:    int64(order.LSPBalance),
+		ClientBalanceSat: int64(order.ClientBalance),
+		RequiredChannelConfirmations: uint32(
+			order.RequiredChannelConfirmations,
+		),
+		FundingConfirmsWithinBlocks: uint32(
+			order.FundingConfirmsWithinBlocks,
+		),
+		ChannelExpiryBlocks:  order.ChannelExpiryBlocks,
+		Token:                order.Token,
+		RefundOnchainAddress: order.RefundOnchainAddress,
+		AnnounceChannel:      order.AnnounceChannel,
+	}
+
+	if bolt11 := order.Payment.Bolt11; bolt11 != nil {
+		rpcOrder.Bolt11 = &Bolt11Payment{
+			State:         marshalPaymentState(bolt11.State),
+			ExpiresAt:     unixOrZero(bolt11.ExpiresAt),
+			FeeTotalSat:   int64(bolt11.FeeTotal),
+			OrderTotalSat: int64(bolt11.OrderTotal),
+			Invoice:       bolt11.Invoice,
+		}
+	}
+
+	if onchain := order.Payment.Onchain; onchain != nil {
+		rpcOrder.Onchain = &OnchainPayment{
+			State:             marshalPaymentState(onchain.State),
+			ExpiresAt:         unixOrZero(onchain.ExpiresAt),
+			FeeTotalSat:       int64(onchain.FeeTotal),
+			OrderTotalSat:     int64(onchain.OrderTotal),
+			Address:           onchain.Address,
+			MinFeeForZeroConf: onchain.MinFeeFor0Conf,
+		}
+
+		minConfs := onchain.MinOnchainPaymentConfirmations
+		if minConfs != nil {
+			rpcOrder.Onchain.MinOnchainPaymentConfirmations =
+				uint32(*minConfs)
+		}
+	}
+
+	if channel := order.Channel; channel != nil {
+		rpcOrder.Channel = &OrderChannel{
+			FundedAt:        unixOrZero(channel.FundedAt),
+			FundingOutpoint: channel.FundingOutpoint,
+			ExpiresAt:       unixOrZero(channel.ExpiresAt),
+		}
+	}
+
+	return rpcOrder
+}
+
+// marshalOpeningFeeParams converts the opening fee params of an LSP into their
+// RPC representation.
+func marshalOpeningFeeParams(params *lsps.OpeningFeeParams) *OpeningFeeParams {
+	validUntil := params.ValidUntil.Format(time.RFC3339Nano)
+
+	return &OpeningFeeParams{
+		MinFeeMsat:           uint64(params.MinFee),
+		Proportional:         params.Proportional,
+		ValidUntil:           validUntil,
+		MinLifetime:          params.MinLifetime,
+		MaxClientToSelfDelay: params.MaxClientToSelfDelay,
+		MinPaymentSizeMsat:   uint64(params.MinPaymentSize),
+		MaxPaymentSizeMsat:   uint64(params.MaxPaymentSize),
+		Promise:              params.Promise,
+	}
+}
+
+// unmarshalOpeningFeeParams converts RPC opening fee params into the params
+// the LSP client buys a channel with.
+func unmarshalOpeningFeeParams(
+	params *OpeningFeeParams) (*lsps.OpeningFeeParams, error) {
+
+	if params == nil {
+		return nil, status.Error(codes.InvalidArgument,
+			"opening_fee_params must be set")
+	}
+
+	validUntil, err := time.Parse(time.RFC3339Nano, params.ValidUntil)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid "+
+			"valid_until: %v", err)
+	}
+
+	return &lsps.OpeningFeeParams{
+		MinFee:               lnwire.MilliSatoshi(params.MinFeeMsat),
+		Proportional:         params.Proportional,
+		ValidUntil:           validUntil,
+		MinLifetime:          params.MinLifetime,
+		MaxClientToSelfDelay: params.MaxClientToSelfDelay,
+		MinPaymentSize: lnwire.MilliSatoshi(
+			params.MinPaymentSizeMsat,
+		),
+		MaxPaymentSize: lnwire.MilliSatoshi(
+			params.MaxPaymentSizeMsat,
+		),
+		Promise: params.Promise,
+	}, nil
+}
+
+// marshalJITChannel converts a bought just-in-time channel into its RPC
+// representation.
+func marshalJITChannel(jitChannel *lsps.JITChannel) *JITChannel {
+	rpcChannel := &JITChannel{
+		LspPubkey:          jitChannel.LSP[:],
+		Scid:               jitChannel.SCID.ToUint64(),
+		LspCltvExpiryDelta: uint32(jitChannel.LSPCltvExpiryDelta),
+		ClientTrustsLsp:    jitChannel.ClientTrustsLSP,
+		OpeningFeeParams: marshalOpeningFeeParams(
+			jitChannel.OpeningFeeParams,
+		),
+	}
+	if jitChannel.PaymentSize != nil {
+		rpcChannel.PaymentSizeMsat = uint64(*jitChannel.PaymentSize)
+	}
+
+	return rpcChannel
+}
@@ -16,6 +16,14 @@ var (
 		regexp.MustCompile("^/v1/middleware$"),
 	}
 
+	// LndETagURIs is a list of the REST URIs of lnd RPCs with large
+	// responses that are commonly polled. Their responses carry an ETag
+	// so clients can skip the transfer if nothing changed.
+	LndETagURIs = []*regexp.Regexp{
+		regexp.MustCompile("^/v1/graph$"),
+		regexp.MustCompile("^/v1/channels$"),
+	}
+
 	// MaxGrpcMsgSize is used when we configure both server and clients to
 	// allow sending/receiving at most 200 MiB GRPC messages.
 	MaxGrpcMsgSize = 200 * 1024 * 1024
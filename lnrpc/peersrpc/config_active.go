@@ -6,8 +6,11 @@ package peersrpc
 import (
 	"net"
 
+	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/peer"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 // Config is the primary configuration struct for the peers RPC subserver.
@@ -29,4 +32,20 @@ type Config struct {
 	// vector should be provided.
 	UpdateNodeAnnouncement func(features *lnwire.RawFeatureVector,
 		mods ...netann.NodeAnnModifier) error
+
+	// FeaturePolicyRejections returns the peers that were disconnected
+	// because their features violate the feature policy.
+	FeaturePolicyRejections func() []feature.PolicyRejection
+
+	// InitRecords are the custom TLV records included in the init message
+	// we send to peers.
+	InitRecords *peer.InitRecords
+
+	// PeerInitRecords returns the TLV records of the init message received
+	// from the connected peer with the given public key.
+	PeerInitRecords func(pubKey [33]byte) (map[uint64][]byte, error)
+
+	// PeerStorageBlobs returns the blobs that we store on behalf of our
+	// peers, keyed by the peer's public key.
+	PeerStorageBlobs func() (map[route.Vertex][]byte, error)
 }
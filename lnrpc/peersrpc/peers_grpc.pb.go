@@ -22,6 +22,39 @@ type PeersClient interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnouncementUpdateRequest, opts ...grpc.CallOption) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers updatenodemetadata
+	// UpdateNodeMetadata replaces the operator information records in the node
+	// announcement and broadcasts a new version of the node announcement to its
+	// peers. Empty fields remove their record.
+	UpdateNodeMetadata(ctx context.Context, in *NodeMetadataUpdateRequest, opts ...grpc.CallOption) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers listrejectedpeers
+	// ListRejectedPeers returns the peers that were disconnected because the
+	// features they signaled in their init message violate the configured feature
+	// policy, starting with the most recent rejection.
+	ListRejectedPeers(ctx context.Context, in *ListRejectedPeersRequest, opts ...grpc.CallOption) (*ListRejectedPeersResponse, error)
+	// lncli: peers registerinitrecord
+	// RegisterInitRecord adds a custom TLV record to the init message we send to
+	// peers, replacing the value of an already registered record of the type. The
+	// type must be odd and at least 65536. The record is only sent on connections
+	// established after it was registered.
+	RegisterInitRecord(ctx context.Context, in *RegisterInitRecordRequest, opts ...grpc.CallOption) (*RegisterInitRecordResponse, error)
+	// lncli: peers unregisterinitrecord
+	// UnregisterInitRecord removes a custom TLV record from the init message we
+	// send to peers.
+	UnregisterInitRecord(ctx context.Context, in *UnregisterInitRecordRequest, opts ...grpc.CallOption) (*UnregisterInitRecordResponse, error)
+	// lncli: peers listinitrecords
+	// ListInitRecords returns the custom TLV records included in the init message
+	// we send to peers.
+	ListInitRecords(ctx context.Context, in *ListInitRecordsRequest, opts ...grpc.CallOption) (*InitRecordsResponse, error)
+	// lncli: peers peerinitrecords
+	// PeerInitRecords returns the TLV records of the init message received from a
+	// connected peer.
+	PeerInitRecords(ctx context.Context, in *PeerInitRecordsRequest, opts ...grpc.CallOption) (*InitRecordsResponse, error)
+	// lncli: peers listpeerstorage
+	// ListPeerStorage returns the backup blobs that we store on behalf of our
+	// peers through the peer storage protocol, sorted by the public key of the
+	// peer.
+	ListPeerStorage(ctx context.Context, in *ListPeerStorageRequest, opts ...grpc.CallOption) (*ListPeerStorageResponse, error)
 }
 
 type peersClient struct {
@@ -41,6 +74,69 @@ func (c *peersClient) UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnoun
 	return out, nil
 }
 
+func (c *peersClient) UpdateNodeMetadata(ctx context.Context, in *NodeMetadataUpdateRequest, opts ...grpc.CallOption) (*NodeAnnouncementUpdateResponse, error) {
+	out := new(NodeAnnouncementUpdateResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/UpdateNodeMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) ListRejectedPeers(ctx context.Context, in *ListRejectedPeersRequest, opts ...grpc.CallOption) (*ListRejectedPeersResponse, error) {
+	out := new(ListRejectedPeersResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/ListRejectedPeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) RegisterInitRecord(ctx context.Context, in *RegisterInitRecordRequest, opts ...grpc.CallOption) (*RegisterInitRecordResponse, error) {
+	out := new(RegisterInitRecordResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/RegisterInitRecord", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) UnregisterInitRecord(ctx context.Context, in *UnregisterInitRecordRequest, opts ...grpc.CallOption) (*UnregisterInitRecordResponse, error) {
+	out := new(UnregisterInitRecordResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/UnregisterInitRecord", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) ListInitRecords(ctx context.Context, in *ListInitRecordsRequest, opts ...grpc.CallOption) (*InitRecordsResponse, error) {
+	out := new(InitRecordsResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/ListInitRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) PeerInitRecords(ctx context.Context, in *PeerInitRecordsRequest, opts ...grpc.CallOption) (*InitRecordsResponse, error) {
+	out := new(InitRecordsResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/PeerInitRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) ListPeerStorage(ctx context.Context, in *ListPeerStorageRequest, opts ...grpc.CallOption) (*ListPeerStorageResponse, error) {
+	out := new(ListPeerStorageResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/ListPeerStorage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeersServer is the server API for Peers service.
 // All implementations must embed UnimplementedPeersServer
 // for forward compatibility
@@ -49,6 +145,39 @@ type PeersServer interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers updatenodemetadata
+	// UpdateNodeMetadata replaces the operator information records in the node
+	// announcement and broadcasts a new version of the node announcement to its
+	// peers. Empty fields remove their record.
+	UpdateNodeMetadata(context.Context, *NodeMetadataUpdateRequest) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers listrejectedpeers
+	// ListRejectedPeers returns the peers that were disconnected because the
+	// features they signaled in their init message violate the configured feature
+	// policy, starting with the most recent rejection.
+	ListRejectedPeers(context.Context, *ListRejectedPeersRequest) (*ListRejectedPeersResponse, error)
+	// lncli: peers registerinitrecord
+	// RegisterInitRecord adds a custom TLV record to the init message we send to
+	// peers, replacing the value of an already registered record of the type. The
+	// type must be odd and at least 65536. The record is only sent on connections
+	// established after it was registered.
+	RegisterInitRecord(context.Context, *RegisterInitRecordRequest) (*RegisterInitRecordResponse, error)
+	// lncli: peers unregisterinitrecord
+	// UnregisterInitRecord removes a custom TLV record from the init message we
+	// send to peers.
+	UnregisterInitRecord(context.Context, *UnregisterInitRecordRequest) (*UnregisterInitRecordResponse, error)
+	// lncli: peers listinitrecords
+	// ListInitRecords returns the custom TLV records included in the init message
+	// we send to peers.
+	ListInitRecords(context.Context, *ListInitRecordsRequest) (*InitRecordsResponse, error)
+	// lncli: peers peerinitrecords
+	// PeerInitRecords returns the TLV records of the init message received from a
+	// connected peer.
+	PeerInitRecords(context.Context, *PeerInitRecordsRequest) (*InitRecordsResponse, error)
+	// lncli: peers listpeerstorage
+	// ListPeerStorage returns the backup blobs that we store on behalf of our
+	// peers through the peer storage protocol, sorted by the public key of the
+	// peer.
+	ListPeerStorage(context.Context, *ListPeerStorageRequest) (*ListPeerStorageResponse, error)
 	mustEmbedUnimplementedPeersServer()
 }
 
@@ -59,6 +188,27 @@ type UnimplementedPeersServer struct {
 func (UnimplementedPeersServer) UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateNodeAnnouncement not implemented")
 }
+func (UnimplementedPeersServer) UpdateNodeMetadata(context.Context, *NodeMetadataUpdateRequest) (*NodeAnnouncementUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNodeMetadata not implemented")
+}
+func (UnimplementedPeersServer) ListRejectedPeers(context.Context, *ListRejectedPeersRequest) (*ListRejectedPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRejectedPeers not implemented")
+}
+func (UnimplementedPeersServer) RegisterInitRecord(context.Context, *RegisterInitRecordRequest) (*RegisterInitRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterInitRecord not implemented")
+}
+func (UnimplementedPeersServer) UnregisterInitRecord(context.Context, *UnregisterInitRecordRequest) (*UnregisterInitRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterInitRecord not implemented")
+}
+func (UnimplementedPeersServer) ListInitRecords(context.Context, *ListInitRecordsRequest) (*InitRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInitRecords not implemented")
+}
+func (UnimplementedPeersServer) PeerInitRecords(context.Context, *PeerInitRecordsRequest) (*InitRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeerInitRecords not implemented")
+}
+func (UnimplementedPeersServer) ListPeerStorage(context.Context, *ListPeerStorageRequest) (*ListPeerStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeerStorage not implemented")
+}
 func (UnimplementedPeersServer) mustEmbedUnimplementedPeersServer() {}
 
 // UnsafePeersServer may be embedded to opt out of forward compatibility for this service.
@@ -90,6 +240,132 @@ func _Peers_UpdateNodeAnnouncement_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Peers_UpdateNodeMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeMetadataUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).UpdateNodeMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/UpdateNodeMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).UpdateNodeMetadata(ctx, req.(*NodeMetadataUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_ListRejectedPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRejectedPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).ListRejectedPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/ListRejectedPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).ListRejectedPeers(ctx, req.(*ListRejectedPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_RegisterInitRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterInitRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).RegisterInitRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/RegisterInitRecord",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).RegisterInitRecord(ctx, req.(*RegisterInitRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_UnregisterInitRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterInitRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).UnregisterInitRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/UnregisterInitRecord",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).UnregisterInitRecord(ctx, req.(*UnregisterInitRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_ListInitRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInitRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).ListInitRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/ListInitRecords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).ListInitRecords(ctx, req.(*ListInitRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_PeerInitRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerInitRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).PeerInitRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/PeerInitRecords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).PeerInitRecords(ctx, req.(*PeerInitRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_ListPeerStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeerStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).ListPeerStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/ListPeerStorage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).ListPeerStorage(ctx, req.(*ListPeerStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Peers_ServiceDesc is the grpc.ServiceDesc for Peers service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,6 +377,34 @@ var Peers_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateNodeAnnouncement",
 			Handler:    _Peers_UpdateNodeAnnouncement_Handler,
 		},
+		{
+			MethodName: "UpdateNodeMetadata",
+			Handler:    _Peers_UpdateNodeMetadata_Handler,
+		},
+		{
+			MethodName: "ListRejectedPeers",
+			Handler:    _Peers_ListRejectedPeers_Handler,
+		},
+		{
+			MethodName: "RegisterInitRecord",
+			Handler:    _Peers_RegisterInitRecord_Handler,
+		},
+		{
+			MethodName: "UnregisterInitRecord",
+			Handler:    _Peers_UnregisterInitRecord_Handler,
+		},
+		{
+			MethodName: "ListInitRecords",
+			Handler:    _Peers_ListInitRecords_Handler,
+		},
+		{
+			MethodName: "PeerInitRecords",
+			Handler:    _Peers_PeerInitRecords_Handler,
+		},
+		{
+			MethodName: "ListPeerStorage",
+			Handler:    _Peers_ListPeerStorage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peersrpc/peers.proto",
@@ -5,8 +5,11 @@ package peersrpc
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"sync/atomic"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -15,7 +18,10 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/peer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
 
@@ -34,6 +40,34 @@ var (
 			Entity: "peers",
 			Action: "write",
 		}},
+		"/peersrpc.Peers/UpdateNodeMetadata": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/ListRejectedPeers": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/peersrpc.Peers/RegisterInitRecord": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/UnregisterInitRecord": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/ListInitRecords": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/peersrpc.Peers/PeerInitRecords": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/peersrpc.Peers/ListPeerStorage": {{
+			Entity: "peers",
+			Action: "read",
+		}},
 	}
 )
 
@@ -400,3 +434,207 @@ func (s *Server) UpdateNodeAnnouncement(_ context.Context,
 
 	return resp, nil
 }
+
+// UpdateNodeMetadata replaces the operator information records in the node
+// announcement with the given metadata and broadcasts a new version of the
+// node announcement to its peers. Empty fields remove their record.
+func (s *Server) UpdateNodeMetadata(_ context.Context,
+	req *NodeMetadataUpdateRequest) (*NodeAnnouncementUpdateResponse,
+	error) {
+
+	metadata := &netann.NodeMetadata{
+		Contact: req.Contact,
+		OrgName: req.OrgName,
+		URL:     req.Url,
+	}
+
+	currentNodeAnn := s.cfg.GetNodeAnnouncement()
+
+	currentMetadata, err := netann.ParseNodeMetadata(
+		currentNodeAnn.ExtraOpaqueData,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse current node "+
+			"metadata: %w", err)
+	}
+	if *currentMetadata == *metadata {
+		return nil, fmt.Errorf("unable to detect any new values to " +
+			"update the node announcement")
+	}
+
+	extraData, err := netann.SetNodeMetadata(
+		currentNodeAnn.ExtraOpaqueData, metadata,
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid "+
+			"node metadata: %v", err)
+	}
+
+	ops := &lnrpc.Op{Entity: "metadata"}
+	for _, field := range []struct {
+		name     string
+		old, new string
+	}{
+		{"contact", currentMetadata.Contact, metadata.Contact},
+		{"org", currentMetadata.OrgName, metadata.OrgName},
+		{"url", currentMetadata.URL, metadata.URL},
+	} {
+		switch {
+		case field.old == field.new:
+			continue
+
+		case field.new == "":
+			ops.Actions = append(
+				ops.Actions, fmt.Sprintf("%s removed",
+					field.name),
+			)
+
+		default:
+			ops.Actions = append(
+				ops.Actions, fmt.Sprintf("%s changed to %v",
+					field.name, field.new),
+			)
+		}
+	}
+
+	if err := s.cfg.UpdateNodeAnnouncement(
+		nil, netann.NodeAnnSetExtraOpaqueData(extraData),
+	); err != nil {
+		return nil, err
+	}
+
+	return &NodeAnnouncementUpdateResponse{
+		Ops: []*lnrpc.Op{ops},
+	}, nil
+}
+
+// ListRejectedPeers returns the peers that were disconnected because the
+// features they signaled in their init message violate the configured feature
+// policy, starting with the most recent rejection.
+func (s *Server) ListRejectedPeers(_ context.Context,
+	_ *ListRejectedPeersRequest) (*ListRejectedPeersResponse, error) {
+
+	rejections := s.cfg.FeaturePolicyRejections()
+
+	resp := &ListRejectedPeersResponse{
+		Peers: make([]*RejectedPeer, 0, len(rejections)),
+	}
+	for _, rejection := range rejections {
+		resp.Peers = append(resp.Peers, &RejectedPeer{
+			PubKey:       hex.EncodeToString(rejection.PubKey[:]),
+			Reason:       rejection.Reason,
+			LastRejected: rejection.Timestamp.Unix(),
+			Count:        rejection.Count,
+		})
+	}
+
+	return resp, nil
+}
+
+// RegisterInitRecord adds a custom TLV record to the init message we send to
+// peers, replacing the value of an already registered record of the type. The
+// type must be odd and in the custom range. The record is only sent on
+// connections established after it was registered.
+
+func (s *Server) RegisterInitRecord(_ context.Context,
+	req *RegisterInitRecordRequest) (*RegisterInitRecordResponse, error) {
+
+	err := s.cfg.InitRecords.Register(req.Type, req.Value)
+	switch {
+	case errors.Is(err, peer.ErrInitRecordType),
+		errors.Is(err, peer.ErrInitRecordsTooLarge):
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &RegisterInitRecordResponse{}, nil
+}
+
+// UnregisterInitRecord removes a custom TLV record from the init message we
+// send to peers.
+
+func (s *Server) UnregisterInitRecord(_ context.Context,
+	req *UnregisterInitRecordRequest) (*UnregisterInitRecordResponse,
+	error) {
+
+	err := s.cfg.InitRecords.Unregister(req.Type)
+	switch {
+	case errors.Is(err, peer.ErrInitRecordNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &UnregisterInitRecordResponse{}, nil
+}
+
+// ListInitRecords returns the custom TLV records included in the init message
+// we send to peers, by type.
+
+func (s *Server) ListInitRecords(_ context.Context,
+	_ *ListInitRecordsRequest) (*InitRecordsResponse, error) {
+
+	return &InitRecordsResponse{
+		Records: s.cfg.InitRecords.Records(),
+	}, nil
+}
+
+// PeerInitRecords returns the TLV records of the init message received from
+// the connected peer with the given public key, by type.
+
+func (s *Server) PeerInitRecords(_ context.Context,
+	req *PeerInitRecordsRequest) (*InitRecordsResponse, error) {
+
+	if len(req.PubKey) != 33 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid "+
+			"public key length %d, expected 33", len(req.PubKey))
+	}
+
+	var key [33]byte
+	copy(key[:], req.PubKey)
+
+	records, err := s.cfg.PeerInitRecords(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InitRecordsResponse{
+		Records: records,
+	}, nil
+}
+
+// ListPeerStorage returns the backup blobs that we store on behalf of our peers
+// through the peer storage protocol, sorted by the public key of the peer.
+func (s *Server) ListPeerStorage(_ context.Context,
+	req *ListPeerStorageRequest) (*ListPeerStorageResponse, error) {
+
+	blobs, err := s.cfg.PeerStorageBlobs()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListPeerStorageResponse{
+		Blobs: make([]*PeerStorageBlob, 0, len(blobs)),
+	}
+	for pubKey, blob := range blobs {
+		rpcBlob := &PeerStorageBlob{
+			PubKey: hex.EncodeToString(pubKey[:]),
+			Size:   uint32(len(blob)),
+		}
+		if req.IncludeBlobs {
+			rpcBlob.Blob = blob
+		}
+
+		resp.Blobs = append(resp.Blobs, rpcBlob)
+	}
+
+	sort.Slice(resp.Blobs, func(i, j int) bool {
+		return resp.Blobs[i].PubKey < resp.Blobs[j].PubKey
+	})
+
+	return resp, nil
+}
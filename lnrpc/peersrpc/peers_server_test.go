@@ -0,0 +1,226 @@
+//go:build peersrpc
+// +build peersrpc
+
+package peersrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/feature"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/peer"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestInitRecordRPCs tests that init records are registered, listed and
+// removed through the RPCs, and that invalid requests are rejected with the
+// matching status codes.
+func TestInitRecordRPCs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	peerKey := [33]byte{2, 1}
+	s := &Server{cfg: &Config{
+		InitRecords: peer.NewInitRecords(),
+		PeerInitRecords: func(key [33]byte) (map[uint64][]byte,
+			error) {
+
+			require.Equal(t, peerKey, key)
+
+			return map[uint64][]byte{65537: {1}}, nil
+		},
+	}}
+
+	// Even types and types below the custom range are rejected.
+	for _, typ := range []uint64{65536, 65535} {
+		_, err := s.RegisterInitRecord(ctx, &RegisterInitRecordRequest{
+			Type: typ,
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	// So are records that exceed the size limit.
+	_, err := s.RegisterInitRecord(ctx, &RegisterInitRecordRequest{
+		Type:  65537,
+		Value: make([]byte, peer.MaxInitRecordsSize),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.RegisterInitRecord(ctx, &RegisterInitRecordRequest{
+		Type:  65537,
+		Value: []byte{1, 2},
+	})
+	require.NoError(t, err)
+
+	resp, err := s.ListInitRecords(ctx, &ListInitRecordsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, map[uint64][]byte{65537: {1, 2}}, resp.Records)
+
+	_, err = s.UnregisterInitRecord(ctx, &UnregisterInitRecordRequest{
+		Type: 65537,
+	})
+	require.NoError(t, err)
+
+	resp, err = s.ListInitRecords(ctx, &ListInitRecordsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Records)
+
+	// Removing a record that isn't registered fails.
+	_, err = s.UnregisterInitRecord(ctx, &UnregisterInitRecordRequest{
+		Type: 65537,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// The records of a peer are looked up by its public key.
+	_, err = s.PeerInitRecords(ctx, &PeerInitRecordsRequest{
+		PubKey: []byte{1},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resp, err = s.PeerInitRecords(ctx, &PeerInitRecordsRequest{
+		PubKey: peerKey[:],
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[uint64][]byte{65537: {1}}, resp.Records)
+}
+
+// TestUpdateNodeMetadata tests that the metadata records of the node
+// announcement are replaced, and that invalid or unchanged metadata is
+// rejected.
+func TestUpdateNodeMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var nodeAnn lnwire.NodeAnnouncement
+	s := &Server{cfg: &Config{
+		GetNodeAnnouncement: func() lnwire.NodeAnnouncement {
+			return nodeAnn
+		},
+		UpdateNodeAnnouncement: func(_ *lnwire.RawFeatureVector,
+			mods ...netann.NodeAnnModifier) error {
+
+			for _, mod := range mods {
+				mod(&nodeAnn)
+			}
+
+			return nil
+		},
+	}}
+
+	resp, err := s.UpdateNodeMetadata(ctx, &NodeMetadataUpdateRequest{
+		Contact: "ops@example.com",
+		Url:     "https://example.com",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Ops, 1)
+	require.ElementsMatch(t, []string{
+		"contact changed to ops@example.com",
+		"url changed to https://example.com",
+	}, resp.Ops[0].Actions)
+
+	metadata, err := netann.ParseNodeMetadata(nodeAnn.ExtraOpaqueData)
+	require.NoError(t, err)
+	require.Equal(t, &netann.NodeMetadata{
+		Contact: "ops@example.com",
+		URL:     "https://example.com",
+	}, metadata)
+
+	// Sending the same metadata again is rejected.
+	_, err = s.UpdateNodeMetadata(ctx, &NodeMetadataUpdateRequest{
+		Contact: "ops@example.com",
+		Url:     "https://example.com",
+	})
+	require.ErrorContains(t, err, "unable to detect any new values")
+
+	// Records that are too long are rejected.
+	_, err = s.UpdateNodeMetadata(ctx, &NodeMetadataUpdateRequest{
+		OrgName: string(make([]byte, netann.MaxNodeMetadataLen+1)),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Empty fields remove their record.
+	resp, err = s.UpdateNodeMetadata(ctx, &NodeMetadataUpdateRequest{
+		Contact: "ops@example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"url removed"}, resp.Ops[0].Actions)
+}
+
+// TestListRejectedPeers tests that the peers rejected by the feature policy
+// are returned in the order of the policy.
+func TestListRejectedPeers(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	s := &Server{cfg: &Config{
+		FeaturePolicyRejections: func() []feature.PolicyRejection {
+			return []feature.PolicyRejection{{
+				PubKey:    [33]byte{3},
+				Reason:    "forbidden feature wumbo set",
+				Timestamp: now,
+				Count:     2,
+			}, {
+				PubKey:    [33]byte{2},
+				Reason:    "required feature 1001 not set",
+				Timestamp: now.Add(-time.Minute),
+				Count:     1,
+			}}
+		},
+	}}
+
+	resp, err := s.ListRejectedPeers(
+		context.Background(), &ListRejectedPeersRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Peers, 2)
+
+	require.Equal(t, "03"+strings.Repeat("00", 32), resp.Peers[0].PubKey)
+	require.Equal(t, "forbidden feature wumbo set", resp.Peers[0].Reason)
+	require.Equal(t, now.Unix(), resp.Peers[0].LastRejected)
+	require.EqualValues(t, 2, resp.Peers[0].Count)
+
+	require.Equal(t, "02"+strings.Repeat("00", 32), resp.Peers[1].PubKey)
+	require.EqualValues(t, 1, resp.Peers[1].Count)
+}
+
+// TestListPeerStorage tests that the blobs stored on behalf of our peers are
+// listed sorted by the public key of the peer, and that the blobs themselves
+// are only returned if requested.
+func TestListPeerStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := &Server{cfg: &Config{
+		PeerStorageBlobs: func() (map[route.Vertex][]byte, error) {
+			return map[route.Vertex][]byte{
+				{3}: {1, 2, 3},
+				{2}: {4},
+			}, nil
+		},
+	}}
+
+	resp, err := s.ListPeerStorage(ctx, &ListPeerStorageRequest{})
+	require.NoError(t, err)
+	first := route.Vertex{2}
+	require.Len(t, resp.Blobs, 2)
+	require.Equal(t, hex.EncodeToString(first[:]), resp.Blobs[0].PubKey)
+	require.EqualValues(t, 1, resp.Blobs[0].Size)
+	require.EqualValues(t, 3, resp.Blobs[1].Size)
+	require.Nil(t, resp.Blobs[0].Blob)
+
+	resp, err = s.ListPeerStorage(ctx, &ListPeerStorageRequest{
+		IncludeBlobs: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{4}, resp.Blobs[0].Blob)
+	require.Equal(t, []byte{1, 2, 3}, resp.Blobs[1].Blob)
+}
@@ -0,0 +1,251 @@
+package lnrpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	// gzipWriterPool holds gzip writers for reuse, as allocating their
+	// compression state is expensive.
+	gzipWriterPool = sync.Pool{
+		New: func() interface{} {
+			return gzip.NewWriter(nil)
+		},
+	}
+)
+
+// isWebSocketUpgrade returns true if the request is a WebSocket handshake,
+// which must reach the WebSocket proxy with the original response writer so
+// the connection can be hijacked.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// headerContains returns true if the comma separated header field of the
+// request contains the given token.
+func headerContains(r *http.Request, field, token string) bool {
+	for _, value := range r.Header.Values(field) {
+		for _, part := range strings.Split(value, ",") {
+			// Strip parameters like the quality value of
+			// "gzip;q=0.8".
+			part, _, _ = strings.Cut(part, ";")
+			if strings.TrimSpace(part) == token {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// gzipResponseWriter compresses everything written to the response. The
+// compression is only enabled once the status code is known, as responses
+// without a body must not be compressed.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+// WriteHeader sets the compression headers if the response has a body and
+// writes the header.
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+
+	header := g.Header()
+	hasBody := code != http.StatusNoContent &&
+		code != http.StatusNotModified
+	if hasBody && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+
+		g.gz = gzipWriterPool.Get().(*gzip.Writer)
+		g.gz.Reset(g.ResponseWriter)
+	}
+	header.Add("Vary", "Accept-Encoding")
+
+	g.ResponseWriter.WriteHeader(code)
+}
+
+// Write compresses the data and writes it to the response.
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+
+	if g.gz == nil {
+		return g.ResponseWriter.Write(b)
+	}
+
+	return g.gz.Write(b)
+}
+
+// Flush writes all data compressed so far to the client. This is used by the
+// REST proxy to deliver the messages of streaming RPCs.
+func (g *gzipResponseWriter) Flush() {
+	if g.gz != nil {
+		// The error will surface on the next write.
+		_ = g.gz.Flush()
+	}
+
+	if flusher, ok := g.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// close finishes the compressed stream and returns the gzip writer to the
+// pool.
+func (g *gzipResponseWriter) close() {
+	if g.gz == nil {
+		return
+	}
+
+	// The client may have gone away, in which case there is nothing left
+	// to do with the error.
+	_ = g.gz.Close()
+	gzipWriterPool.Put(g.gz)
+	g.gz = nil
+}
+
+// NewCompressionHandler wraps the given handler so that its responses are
+// compressed with gzip if the client accepts it. This considerably reduces the
+// size of large JSON responses, like the graph or the list of channels.
+// Streaming responses are compressed as well, with every flushed message
+// delivered immediately.
+func NewCompressionHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebSocketUpgrade(r) || r.Method == http.MethodHead ||
+			!headerContains(r, "Accept-Encoding", "gzip") {
+
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+
+		h.ServeHTTP(gw, r)
+	})
+}
+
+// bufferedResponseWriter holds back the body of a response until the handler
+// finished.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+
+	code int
+	body bytes.Buffer
+}
+
+// WriteHeader records the status code of the response.
+func (b *bufferedResponseWriter) WriteHeader(code int) {
+	if b.code == 0 {
+		b.code = code
+	}
+}
+
+// Write appends the data to the buffered body.
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if b.code == 0 {
+		b.code = http.StatusOK
+	}
+
+	return b.body.Write(p)
+}
+
+// NewETagHandler wraps the given handler so that the GET responses of the
+// given URIs carry an ETag header derived from their content. If a client
+// sends the ETag of the current response in the If-None-Match header, the
+// response is replaced by an empty 304 Not Modified response. This allows
+// clients polling large responses to skip the transfer if nothing changed.
+//
+// NOTE: The request is still executed in full, so authentication is unchanged
+// and the response is never stale.
+func NewETagHandler(h http.Handler, uris []*regexp.Regexp) http.Handler {
+	matches := func(path string) bool {
+		for _, uri := range uris {
+			if uri.MatchString(path) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || isWebSocketUpgrade(r) ||
+			!matches(r.URL.Path) {
+
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(bw, r)
+
+		if bw.code == 0 {
+			bw.code = http.StatusOK
+		}
+		if bw.code != http.StatusOK {
+			w.WriteHeader(bw.code)
+			_, _ = w.Write(bw.body.Bytes())
+
+			return
+		}
+
+		// The ETag is weak as the response may be compressed on its
+		// way to the client, which changes its bytes but not its
+		// meaning.
+		hash := sha256.Sum256(bw.body.Bytes())
+		etag := `W/"` + hex.EncodeToString(hash[:16]) + `"`
+
+		header := w.Header()
+		header.Set("ETag", etag)
+
+		// Responses depend on the macaroon of the request, so they
+		// must not be stored by shared caches, and clients need to
+		// revalidate them every time.
+		header.Set("Cache-Control", "private, no-cache")
+
+		if etagMatches(r, etag) {
+			header.Del("Content-Length")
+			header.Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bw.body.Bytes())
+	})
+}
+
+// etagMatches returns true if the If-None-Match header of the request matches
+// the given ETag. As recommended for If-None-Match, the comparison is weak, so
+// the W/ prefix is ignored.
+func etagMatches(r *http.Request, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, value := range r.Header.Values("If-None-Match") {
+		for _, candidate := range strings.Split(value, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" ||
+				strings.TrimPrefix(candidate, "W/") == etag {
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
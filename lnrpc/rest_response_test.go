@@ -0,0 +1,352 @@
+package lnrpc
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompressionHandler tests that responses are only compressed if the
+// client accepts it and the response has a body.
+func TestCompressionHandler(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"channels":[]}`
+
+	testCases := []struct {
+		name           string
+		method         string
+		header         http.Header
+		code           int
+		expectGzip     bool
+		expectVary     bool
+		expectBodySent bool
+	}{{
+		name:           "no accept-encoding",
+		method:         http.MethodGet,
+		header:         http.Header{},
+		code:           http.StatusOK,
+		expectBodySent: true,
+	}, {
+		name:   "gzip accepted",
+		method: http.MethodGet,
+		header: http.Header{
+			"Accept-Encoding": []string{"deflate, gzip;q=0.8"},
+		},
+		code:           http.StatusOK,
+		expectGzip:     true,
+		expectVary:     true,
+		expectBodySent: true,
+	}, {
+		name:   "only other encodings accepted",
+		method: http.MethodGet,
+		header: http.Header{
+			"Accept-Encoding": []string{"deflate, br"},
+		},
+		code:           http.StatusOK,
+		expectBodySent: true,
+	}, {
+		name:   "error response",
+		method: http.MethodPost,
+		header: http.Header{
+			"Accept-Encoding": []string{"gzip"},
+		},
+		code:           http.StatusInternalServerError,
+		expectGzip:     true,
+		expectVary:     true,
+		expectBodySent: true,
+	}, {
+		name:   "no content",
+		method: http.MethodGet,
+		header: http.Header{
+			"Accept-Encoding": []string{"gzip"},
+		},
+		code:       http.StatusNoContent,
+		expectVary: true,
+	}, {
+		name:   "head request",
+		method: http.MethodHead,
+		header: http.Header{
+			"Accept-Encoding": []string{"gzip"},
+		},
+		code:           http.StatusOK,
+		expectBodySent: true,
+	}, {
+		name:   "websocket upgrade",
+		method: http.MethodGet,
+		header: http.Header{
+			"Accept-Encoding": []string{"gzip"},
+			"Upgrade":         []string{"websocket"},
+		},
+		code:           http.StatusOK,
+		expectBodySent: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewCompressionHandler(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tc.code)
+					if tc.code == http.StatusNoContent {
+						return
+					}
+
+					_, err := w.Write([]byte(body))
+					require.NoError(t, err)
+				},
+			))
+
+			req := httptest.NewRequest(
+				tc.method, "/v1/channels", nil,
+			)
+			req.Header = tc.header
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.code, rec.Code)
+			require.Equal(
+				t, tc.expectVary,
+				rec.Header().Get("Vary") == "Accept-Encoding",
+			)
+
+			if !tc.expectGzip {
+				require.Empty(
+					t, rec.Header().Get("Content-Encoding"),
+				)
+
+				expected := ""
+				if tc.expectBodySent {
+					expected = body
+				}
+				require.Equal(t, expected, rec.Body.String())
+
+				return
+			}
+
+			require.Equal(
+				t, "gzip", rec.Header().Get("Content-Encoding"),
+			)
+
+			gz, err := gzip.NewReader(rec.Body)
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			require.Equal(t, body, string(decompressed))
+		})
+	}
+}
+
+// TestCompressionHandlerFlush tests that every flushed message of a streaming
+// response can be decompressed before the response is complete.
+func TestCompressionHandlerFlush(t *testing.T) {
+	t.Parallel()
+
+	messages := []string{`{"result":1}`, `{"result":2}`}
+
+	// The handler only writes the next message once the previous one was
+	// read, so each message must be delivered by its flush.
+	read := make(chan struct{})
+	h := NewCompressionHandler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			for _, msg := range messages {
+				_, err := w.Write([]byte(msg + "\n"))
+				require.NoError(t, err)
+				w.(http.Flusher).Flush()
+
+				<-read
+			}
+		},
+	))
+
+	req := httptest.NewRequest(
+		http.MethodGet, "/v1/invoices/subscribe", nil,
+	)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	pr, pw := io.Pipe()
+	w := &pipeResponseWriter{header: http.Header{}, w: pw}
+
+	go func() {
+		h.ServeHTTP(w, req)
+		_ = pw.Close()
+	}()
+
+	gz, err := gzip.NewReader(pr)
+	require.NoError(t, err)
+
+	for _, msg := range messages {
+		line := make([]byte, len(msg)+1)
+		_, err := io.ReadFull(gz, line)
+		require.NoError(t, err)
+		require.Equal(t, msg+"\n", string(line))
+
+		read <- struct{}{}
+	}
+}
+
+// pipeResponseWriter is a response writer that writes the body to a pipe, so
+// the body can be read while the handler is still running.
+type pipeResponseWriter struct {
+	header http.Header
+	w      io.Writer
+}
+
+// Header returns the header fields of the response.
+func (p *pipeResponseWriter) Header() http.Header {
+	return p.header
+}
+
+// Write writes the data to the pipe.
+func (p *pipeResponseWriter) Write(b []byte) (int, error) {
+	return p.w.Write(b)
+}
+
+// WriteHeader ignores the status code.
+func (p *pipeResponseWriter) WriteHeader(int) {}
+
+// Flush is a no-op, as the pipe has no buffer.
+func (p *pipeResponseWriter) Flush() {}
+
+// TestETagHandler tests that ETags are only added to successful GET responses
+// of the given URIs, and that a matching If-None-Match header results in an
+// empty 304 response.
+func TestETagHandler(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"nodes":[]}`
+
+	// Find the ETag of the body to use it in the requests.
+	h := NewETagHandler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		},
+	), []*regexp.Regexp{regexp.MustCompile("^/v1/graph$")})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(
+		rec, httptest.NewRequest(http.MethodGet, "/v1/graph", nil),
+	)
+	etag := rec.Header().Get("ETag")
+	require.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		ifNoneMatch string
+		code        int
+		expectCode  int
+		expectETag  bool
+		expectBody  string
+	}{{
+		name:       "no if-none-match",
+		method:     http.MethodGet,
+		path:       "/v1/graph",
+		code:       http.StatusOK,
+		expectCode: http.StatusOK,
+		expectETag: true,
+		expectBody: body,
+	}, {
+		name:        "matching etag",
+		method:      http.MethodGet,
+		path:        "/v1/graph",
+		ifNoneMatch: etag,
+		code:        http.StatusOK,
+		expectCode:  http.StatusNotModified,
+		expectETag:  true,
+	}, {
+		name:        "matching strong etag in list",
+		method:      http.MethodGet,
+		path:        "/v1/graph",
+		ifNoneMatch: `"other", ` + etag[2:],
+		code:        http.StatusOK,
+		expectCode:  http.StatusNotModified,
+		expectETag:  true,
+	}, {
+		name:        "wildcard",
+		method:      http.MethodGet,
+		path:        "/v1/graph",
+		ifNoneMatch: "*",
+		code:        http.StatusOK,
+		expectCode:  http.StatusNotModified,
+		expectETag:  true,
+	}, {
+		name:        "stale etag",
+		method:      http.MethodGet,
+		path:        "/v1/graph",
+		ifNoneMatch: `W/"stale"`,
+		code:        http.StatusOK,
+		expectCode:  http.StatusOK,
+		expectETag:  true,
+		expectBody:  body,
+	}, {
+		name:        "error response",
+		method:      http.MethodGet,
+		path:        "/v1/graph",
+		ifNoneMatch: etag,
+		code:        http.StatusInternalServerError,
+		expectCode:  http.StatusInternalServerError,
+		expectBody:  body,
+	}, {
+		name:        "other uri",
+		method:      http.MethodGet,
+		path:        "/v1/channels",
+		ifNoneMatch: etag,
+		code:        http.StatusOK,
+		expectCode:  http.StatusOK,
+		expectBody:  body,
+	}, {
+		name:        "post request",
+		method:      http.MethodPost,
+		path:        "/v1/graph",
+		ifNoneMatch: etag,
+		code:        http.StatusOK,
+		expectCode:  http.StatusOK,
+		expectBody:  body,
+	}}
+
+	uris := []*regexp.Regexp{regexp.MustCompile("^/v1/graph$")}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewETagHandler(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tc.code)
+					_, _ = w.Write([]byte(body))
+				},
+			), uris)
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectCode, rec.Code)
+			require.Equal(t, tc.expectBody, rec.Body.String())
+
+			if !tc.expectETag {
+				require.Empty(t, rec.Header().Get("ETag"))
+				return
+			}
+
+			require.Equal(t, etag, rec.Header().Get("ETag"))
+			require.Equal(
+				t, "private, no-cache",
+				rec.Header().Get("Cache-Control"),
+			)
+		})
+	}
+}
@@ -54,10 +54,15 @@ func DefaultConfig() *Config {
 		ProbabilityEstimatorType: routing.DefaultEstimator,
 		MinRouteProbability:      routing.DefaultMinRouteProbability,
 
-		AttemptCost:     routing.DefaultAttemptCost.ToSatoshis(),
-		AttemptCostPPM:  routing.DefaultAttemptCostPPM,
-		MaxMcHistory:    routing.DefaultMaxMcHistory,
-		McFlushInterval: routing.DefaultMcFlushInterval,
+		AttemptCost:            routing.DefaultAttemptCost.ToSatoshis(),
+		AttemptCostPPM:         routing.DefaultAttemptCostPPM,
+		EndorsementWindow:      routing.DefaultEndorsementWindow,
+		BlindedFeeTolerancePPM: routing.DefaultBlindedFeeTolerancePPM,
+		ParallelPathSearches:   routing.DefaultParallelPathSearches,
+		ShadowRouteMaxCltv:     routing.DefaultShadowRouteMaxCltv,
+		AmountRandomization:    routing.DefaultAmountRandomization,
+		MaxMcHistory:           routing.DefaultMaxMcHistory,
+		McFlushInterval:        routing.DefaultMcFlushInterval,
 		AprioriConfig: &AprioriConfig{
 			HopProbability:   routing.DefaultAprioriHopProbability,
 			Weight:           routing.DefaultAprioriWeight,
@@ -84,6 +89,13 @@ func GetRoutingConfig(cfg *Config) *RoutingConfig {
 		MinRouteProbability:      cfg.MinRouteProbability,
 		AttemptCost:              cfg.AttemptCost,
 		AttemptCostPPM:           cfg.AttemptCostPPM,
+		EndorsementAware:         cfg.EndorsementAware,
+		EndorsementWindow:        cfg.EndorsementWindow,
+		BlindedFeeTolerancePPM:   cfg.BlindedFeeTolerancePPM,
+		ParallelPathSearches:     cfg.ParallelPathSearches,
+		RouteCacheTTL:            cfg.RouteCacheTTL,
+		ShadowRouteMaxCltv:       cfg.ShadowRouteMaxCltv,
+		AmountRandomization:      cfg.AmountRandomization,
 		MaxMcHistory:             cfg.MaxMcHistory,
 		McFlushInterval:          cfg.McFlushInterval,
 		AprioriConfig: &AprioriConfig{
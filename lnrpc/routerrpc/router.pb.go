@@ -21,6 +21,60 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type AmountRandomization int32
+
+const (
+	// Use the strategy that is configured for the router.
+	AmountRandomization_AMOUNT_RANDOMIZATION_DEFAULT AmountRandomization = 0
+	// Halve the amount of the next shard when no route can be found for the
+	// current amount.
+	AmountRandomization_AMOUNT_RANDOMIZATION_NONE AmountRandomization = 1
+	// Pick the amount of the next shard at random between three eighths and five
+	// eighths of the current amount.
+	AmountRandomization_AMOUNT_RANDOMIZATION_SHARD AmountRandomization = 2
+)
+
+// Enum value maps for AmountRandomization.
+var (
+	AmountRandomization_name = map[int32]string{
+		0: "AMOUNT_RANDOMIZATION_DEFAULT",
+		1: "AMOUNT_RANDOMIZATION_NONE",
+		2: "AMOUNT_RANDOMIZATION_SHARD",
+	}
+	AmountRandomization_value = map[string]int32{
+		"AMOUNT_RANDOMIZATION_DEFAULT": 0,
+		"AMOUNT_RANDOMIZATION_NONE":    1,
+		"AMOUNT_RANDOMIZATION_SHARD":   2,
+	}
+)
+
+func (x AmountRandomization) Enum() *AmountRandomization {
+	p := new(AmountRandomization)
+	*p = x
+	return p
+}
+
+func (x AmountRandomization) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AmountRandomization) Descriptor() protoreflect.EnumDescriptor {
+	return file_routerrpc_router_proto_enumTypes[0].Descriptor()
+}
+
+func (AmountRandomization) Type() protoreflect.EnumType {
+	return &file_routerrpc_router_proto_enumTypes[0]
+}
+
+func (x AmountRandomization) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AmountRandomization.Descriptor instead.
+func (AmountRandomization) EnumDescriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{0}
+}
+
 type FailureDetail int32
 
 const (
@@ -114,11 +168,11 @@ func (x FailureDetail) String() string {
 }
 
 func (FailureDetail) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[0].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[1].Descriptor()
 }
 
 func (FailureDetail) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[0]
+	return &file_routerrpc_router_proto_enumTypes[1]
 }
 
 func (x FailureDetail) Number() protoreflect.EnumNumber {
@@ -127,7 +181,7 @@ func (x FailureDetail) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use FailureDetail.Descriptor instead.
 func (FailureDetail) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{0}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{1}
 }
 
 type PaymentState int32
@@ -184,11 +238,11 @@ func (x PaymentState) String() string {
 }
 
 func (PaymentState) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[1].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[2].Descriptor()
 }
 
 func (PaymentState) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[1]
+	return &file_routerrpc_router_proto_enumTypes[2]
 }
 
 func (x PaymentState) Number() protoreflect.EnumNumber {
@@ -197,7 +251,7 @@ func (x PaymentState) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PaymentState.Descriptor instead.
 func (PaymentState) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{1}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{2}
 }
 
 type ResolveHoldForwardAction int32
@@ -233,11 +287,11 @@ func (x ResolveHoldForwardAction) String() string {
 }
 
 func (ResolveHoldForwardAction) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[2].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[3].Descriptor()
 }
 
 func (ResolveHoldForwardAction) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[2]
+	return &file_routerrpc_router_proto_enumTypes[3]
 }
 
 func (x ResolveHoldForwardAction) Number() protoreflect.EnumNumber {
@@ -246,7 +300,7 @@ func (x ResolveHoldForwardAction) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ResolveHoldForwardAction.Descriptor instead.
 func (ResolveHoldForwardAction) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{2}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{3}
 }
 
 type ChanStatusAction int32
@@ -282,11 +336,11 @@ func (x ChanStatusAction) String() string {
 }
 
 func (ChanStatusAction) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[3].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[4].Descriptor()
 }
 
 func (ChanStatusAction) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[3]
+	return &file_routerrpc_router_proto_enumTypes[4]
 }
 
 func (x ChanStatusAction) Number() protoreflect.EnumNumber {
@@ -295,7 +349,106 @@ func (x ChanStatusAction) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ChanStatusAction.Descriptor instead.
 func (ChanStatusAction) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{3}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{4}
+}
+
+type RouteHintScope int32
+
+const (
+	// Only payments to the destination the hints were registered for use the
+	// hints.
+	RouteHintScope_ROUTE_HINT_SCOPE_DESTINATION RouteHintScope = 0
+	// Any payment may route through the channels described by the hints. This
+	// should only be used for hints from a trusted source, as the hints of one
+	// destination then affect the routes of payments to other destinations.
+	RouteHintScope_ROUTE_HINT_SCOPE_ALL RouteHintScope = 1
+)
+
+// Enum value maps for RouteHintScope.
+var (
+	RouteHintScope_name = map[int32]string{
+		0: "ROUTE_HINT_SCOPE_DESTINATION",
+		1: "ROUTE_HINT_SCOPE_ALL",
+	}
+	RouteHintScope_value = map[string]int32{
+		"ROUTE_HINT_SCOPE_DESTINATION": 0,
+		"ROUTE_HINT_SCOPE_ALL":         1,
+	}
+)
+
+func (x RouteHintScope) Enum() *RouteHintScope {
+	p := new(RouteHintScope)
+	*p = x
+	return p
+}
+
+func (x RouteHintScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RouteHintScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_routerrpc_router_proto_enumTypes[5].Descriptor()
+}
+
+func (RouteHintScope) Type() protoreflect.EnumType {
+	return &file_routerrpc_router_proto_enumTypes[5]
+}
+
+func (x RouteHintScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RouteHintScope.Descriptor instead.
+func (RouteHintScope) EnumDescriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{5}
+}
+
+type JITChannelState int32
+
+const (
+	// The channel waits for the first forward.
+	JITChannelState_JIT_CHANNEL_STATE_WAITING JITChannelState = 0
+	// The channel is being opened while its forwards are held.
+	JITChannelState_JIT_CHANNEL_STATE_OPENING JITChannelState = 1
+)
+
+// Enum value maps for JITChannelState.
+var (
+	JITChannelState_name = map[int32]string{
+		0: "JIT_CHANNEL_STATE_WAITING",
+		1: "JIT_CHANNEL_STATE_OPENING",
+	}
+	JITChannelState_value = map[string]int32{
+		"JIT_CHANNEL_STATE_WAITING": 0,
+		"JIT_CHANNEL_STATE_OPENING": 1,
+	}
+)
+
+func (x JITChannelState) Enum() *JITChannelState {
+	p := new(JITChannelState)
+	*p = x
+	return p
+}
+
+func (x JITChannelState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JITChannelState) Descriptor() protoreflect.EnumDescriptor {
+	return file_routerrpc_router_proto_enumTypes[6].Descriptor()
+}
+
+func (JITChannelState) Type() protoreflect.EnumType {
+	return &file_routerrpc_router_proto_enumTypes[6]
+}
+
+func (x JITChannelState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JITChannelState.Descriptor instead.
+func (JITChannelState) EnumDescriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{6}
 }
 
 type MissionControlConfig_ProbabilityModel int32
@@ -328,11 +481,11 @@ func (x MissionControlConfig_ProbabilityModel) String() string {
 }
 
 func (MissionControlConfig_ProbabilityModel) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[4].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[7].Descriptor()
 }
 
 func (MissionControlConfig_ProbabilityModel) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[4]
+	return &file_routerrpc_router_proto_enumTypes[7]
 }
 
 func (x MissionControlConfig_ProbabilityModel) Number() protoreflect.EnumNumber {
@@ -341,7 +494,7 @@ func (x MissionControlConfig_ProbabilityModel) Number() protoreflect.EnumNumber
 
 // Deprecated: Use MissionControlConfig_ProbabilityModel.Descriptor instead.
 func (MissionControlConfig_ProbabilityModel) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{19, 0}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{28, 0}
 }
 
 type HtlcEvent_EventType int32
@@ -380,11 +533,11 @@ func (x HtlcEvent_EventType) String() string {
 }
 
 func (HtlcEvent_EventType) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[5].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[8].Descriptor()
 }
 
 func (HtlcEvent_EventType) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[5]
+	return &file_routerrpc_router_proto_enumTypes[8]
 }
 
 func (x HtlcEvent_EventType) Number() protoreflect.EnumNumber {
@@ -393,7 +546,7 @@ func (x HtlcEvent_EventType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use HtlcEvent_EventType.Descriptor instead.
 func (HtlcEvent_EventType) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{27, 0}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{38, 0}
 }
 
 type SendPaymentRequest struct {
@@ -498,6 +651,18 @@ type SendPaymentRequest struct {
 	// still settle afterwards. Canceling will only prevent further attempts from
 	// being sent.
 	Cancelable bool `protobuf:"varint,24,opt,name=cancelable,proto3" json:"cancelable,omitempty"`
+	// The maximum number of blocks of random padding that is added to the final
+	// CLTV delta of this payment's routes, to hide the distance to the receiver.
+	// If zero, the maximum configured for the router is used. It can't exceed
+	// 144 blocks.
+	ShadowRouteMaxCltv uint32 `protobuf:"varint,25,opt,name=shadow_route_max_cltv,json=shadowRouteMaxCltv,proto3" json:"shadow_route_max_cltv,omitempty"`
+	// If set, no padding is added to the final CLTV delta of this payment's
+	// routes, regardless of the maximum configured for the router. It can't be
+	// combined with shadow_route_max_cltv.
+	NoShadowRoute bool `protobuf:"varint,26,opt,name=no_shadow_route,json=noShadowRoute,proto3" json:"no_shadow_route,omitempty"`
+	// The strategy used to pick the amounts of this payment's shards. If not set,
+	// the strategy configured for the router is used.
+	AmountRandomization AmountRandomization `protobuf:"varint,27,opt,name=amount_randomization,json=amountRandomization,proto3,enum=routerrpc.AmountRandomization" json:"amount_randomization,omitempty"`
 }
 
 func (x *SendPaymentRequest) Reset() {
@@ -701,20 +866,47 @@ func (x *SendPaymentRequest) GetCancelable() bool {
 	return false
 }
 
-type TrackPaymentRequest struct {
+func (x *SendPaymentRequest) GetShadowRouteMaxCltv() uint32 {
+	if x != nil {
+		return x.ShadowRouteMaxCltv
+	}
+	return 0
+}
+
+func (x *SendPaymentRequest) GetNoShadowRoute() bool {
+	if x != nil {
+		return x.NoShadowRoute
+	}
+	return false
+}
+
+func (x *SendPaymentRequest) GetAmountRandomization() AmountRandomization {
+	if x != nil {
+		return x.AmountRandomization
+	}
+	return AmountRandomization_AMOUNT_RANDOMIZATION_DEFAULT
+}
+
+type SendTrampolinePaymentRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The hash of the payment to look up.
-	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
-	// If set, only the final payment update is streamed back. Intermediate updates
-	// that show which htlcs are still in flight are suppressed.
-	NoInflightUpdates bool `protobuf:"varint,2,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
-}
-
-func (x *TrackPaymentRequest) Reset() {
-	*x = TrackPaymentRequest{}
+	// The payment to make through the trampoline node.
+	Payment *SendPaymentRequest `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	// The compressed public key of the trampoline node.
+	TrampolineNode []byte `protobuf:"bytes,2,opt,name=trampoline_node,json=trampolineNode,proto3" json:"trampoline_node,omitempty"`
+	// The base fee in millisatoshis that the trampoline node charges.
+	BaseFeeMsat uint64 `protobuf:"varint,3,opt,name=base_fee_msat,json=baseFeeMsat,proto3" json:"base_fee_msat,omitempty"`
+	// The proportional fee in parts per million that the trampoline node
+	// charges.
+	FeeRatePpm uint64 `protobuf:"varint,4,opt,name=fee_rate_ppm,json=feeRatePpm,proto3" json:"fee_rate_ppm,omitempty"`
+	// The CLTV delta that the trampoline node requires.
+	CltvDelta uint32 `protobuf:"varint,5,opt,name=cltv_delta,json=cltvDelta,proto3" json:"cltv_delta,omitempty"`
+}
+
+func (x *SendTrampolinePaymentRequest) Reset() {
+	*x = SendTrampolinePaymentRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -722,13 +914,13 @@ func (x *TrackPaymentRequest) Reset() {
 	}
 }
 
-func (x *TrackPaymentRequest) String() string {
+func (x *SendTrampolinePaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrackPaymentRequest) ProtoMessage() {}
+func (*SendTrampolinePaymentRequest) ProtoMessage() {}
 
-func (x *TrackPaymentRequest) ProtoReflect() protoreflect.Message {
+func (x *SendTrampolinePaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -740,37 +932,62 @@ func (x *TrackPaymentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrackPaymentRequest.ProtoReflect.Descriptor instead.
-func (*TrackPaymentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendTrampolinePaymentRequest.ProtoReflect.Descriptor instead.
+func (*SendTrampolinePaymentRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *TrackPaymentRequest) GetPaymentHash() []byte {
+func (x *SendTrampolinePaymentRequest) GetPayment() *SendPaymentRequest {
 	if x != nil {
-		return x.PaymentHash
+		return x.Payment
 	}
 	return nil
 }
 
-func (x *TrackPaymentRequest) GetNoInflightUpdates() bool {
+func (x *SendTrampolinePaymentRequest) GetTrampolineNode() []byte {
 	if x != nil {
-		return x.NoInflightUpdates
+		return x.TrampolineNode
 	}
-	return false
+	return nil
 }
 
-type TrackPaymentsRequest struct {
+func (x *SendTrampolinePaymentRequest) GetBaseFeeMsat() uint64 {
+	if x != nil {
+		return x.BaseFeeMsat
+	}
+	return 0
+}
+
+func (x *SendTrampolinePaymentRequest) GetFeeRatePpm() uint64 {
+	if x != nil {
+		return x.FeeRatePpm
+	}
+	return 0
+}
+
+func (x *SendTrampolinePaymentRequest) GetCltvDelta() uint32 {
+	if x != nil {
+		return x.CltvDelta
+	}
+	return 0
+}
+
+type SendAsyncPaymentRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// If set, only the final payment updates are streamed back. Intermediate
-	// updates that show which htlcs are still in flight are suppressed.
-	NoInflightUpdates bool `protobuf:"varint,1,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
+	// The payment to hold until the recipient releases it.
+	Payment *SendPaymentRequest `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	// The blinded path to reach the recipient over with onion messages,
+	// encoded as a blinded_path of the specification. It usually runs through
+	// the recipient's LSP, which holds the message while the recipient is
+	// offline.
+	RecipientPath []byte `protobuf:"bytes,2,opt,name=recipient_path,json=recipientPath,proto3" json:"recipient_path,omitempty"`
 }
 
-func (x *TrackPaymentsRequest) Reset() {
-	*x = TrackPaymentsRequest{}
+func (x *SendAsyncPaymentRequest) Reset() {
+	*x = SendAsyncPaymentRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -778,13 +995,13 @@ func (x *TrackPaymentsRequest) Reset() {
 	}
 }
 
-func (x *TrackPaymentsRequest) String() string {
+func (x *SendAsyncPaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrackPaymentsRequest) ProtoMessage() {}
+func (*SendAsyncPaymentRequest) ProtoMessage() {}
 
-func (x *TrackPaymentsRequest) ProtoReflect() protoreflect.Message {
+func (x *SendAsyncPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -796,50 +1013,37 @@ func (x *TrackPaymentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrackPaymentsRequest.ProtoReflect.Descriptor instead.
-func (*TrackPaymentsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendAsyncPaymentRequest.ProtoReflect.Descriptor instead.
+func (*SendAsyncPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *TrackPaymentsRequest) GetNoInflightUpdates() bool {
+func (x *SendAsyncPaymentRequest) GetPayment() *SendPaymentRequest {
 	if x != nil {
-		return x.NoInflightUpdates
+		return x.Payment
 	}
-	return false
+	return nil
 }
 
-type RouteFeeRequest struct {
+func (x *SendAsyncPaymentRequest) GetRecipientPath() []byte {
+	if x != nil {
+		return x.RecipientPath
+	}
+	return nil
+}
+
+type SendAsyncPaymentResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The destination one wishes to obtain a routing fee quote to. If set, this
-	// parameter requires the amt_sat parameter also to be set. This parameter
-	// combination triggers a graph based routing fee estimation as opposed to a
-	// payment probe based estimate in case a payment request is provided. The
-	// graph based estimation is an algorithm that is executed on the in memory
-	// graph. Hence its runtime is significantly shorter than a payment probe
-	// estimation that sends out actual payments to the network.
-	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
-	// The amount one wishes to send to the target destination. It is only to be
-	// used in combination with the dest parameter.
-	AmtSat int64 `protobuf:"varint,2,opt,name=amt_sat,json=amtSat,proto3" json:"amt_sat,omitempty"`
-	// A payment request of the target node that the route fee request is intended
-	// for. Its parameters are input to probe payments that estimate routing fees.
-	// The timeout parameter can be specified to set a maximum time on the probing
-	// attempt. Cannot be used in combination with dest and amt_sat.
-	PaymentRequest string `protobuf:"bytes,3,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
-	// A user preference of how long a probe payment should maximally be allowed to
-	// take, denoted in seconds. The probing payment loop is aborted if this
-	// timeout is reached. Note that the probing process itself can take longer
-	// than the timeout if the HTLC becomes delayed or stuck. Canceling the context
-	// of this call will not cancel the payment loop, the duration is only
-	// controlled by the timeout parameter.
-	Timeout uint32 `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// The hash of the held payment, which can be used to track it once it
+	// was released.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
 }
 
-func (x *RouteFeeRequest) Reset() {
-	*x = RouteFeeRequest{}
+func (x *SendAsyncPaymentResponse) Reset() {
+	*x = SendAsyncPaymentResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -847,13 +1051,13 @@ func (x *RouteFeeRequest) Reset() {
 	}
 }
 
-func (x *RouteFeeRequest) String() string {
+func (x *SendAsyncPaymentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RouteFeeRequest) ProtoMessage() {}
+func (*SendAsyncPaymentResponse) ProtoMessage() {}
 
-func (x *RouteFeeRequest) ProtoReflect() protoreflect.Message {
+func (x *SendAsyncPaymentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -865,72 +1069,46 @@ func (x *RouteFeeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RouteFeeRequest.ProtoReflect.Descriptor instead.
-func (*RouteFeeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendAsyncPaymentResponse.ProtoReflect.Descriptor instead.
+func (*SendAsyncPaymentResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *RouteFeeRequest) GetDest() []byte {
+func (x *SendAsyncPaymentResponse) GetPaymentHash() []byte {
 	if x != nil {
-		return x.Dest
+		return x.PaymentHash
 	}
 	return nil
 }
 
-func (x *RouteFeeRequest) GetAmtSat() int64 {
-	if x != nil {
-		return x.AmtSat
-	}
-	return 0
+type TrackPaymentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The hash of the payment to look up.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// If set, only the final payment update is streamed back. Intermediate updates
+	// that show which htlcs are still in flight are suppressed.
+	NoInflightUpdates bool `protobuf:"varint,2,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
 }
 
-func (x *RouteFeeRequest) GetPaymentRequest() string {
-	if x != nil {
-		return x.PaymentRequest
+func (x *TrackPaymentRequest) Reset() {
+	*x = TrackPaymentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *RouteFeeRequest) GetTimeout() uint32 {
-	if x != nil {
-		return x.Timeout
-	}
-	return 0
+func (x *TrackPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type RouteFeeResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (*TrackPaymentRequest) ProtoMessage() {}
 
-	// A lower bound of the estimated fee to the target destination within the
-	// network, expressed in milli-satoshis.
-	RoutingFeeMsat int64 `protobuf:"varint,1,opt,name=routing_fee_msat,json=routingFeeMsat,proto3" json:"routing_fee_msat,omitempty"`
-	// An estimate of the worst case time delay that can occur. Note that callers
-	// will still need to factor in the final CLTV delta of the last hop into this
-	// value.
-	TimeLockDelay int64 `protobuf:"varint,2,opt,name=time_lock_delay,json=timeLockDelay,proto3" json:"time_lock_delay,omitempty"`
-	// An indication whether a probing payment succeeded or whether and why it
-	// failed. FAILURE_REASON_NONE indicates success.
-	FailureReason lnrpc.PaymentFailureReason `protobuf:"varint,5,opt,name=failure_reason,json=failureReason,proto3,enum=lnrpc.PaymentFailureReason" json:"failure_reason,omitempty"`
-}
-
-func (x *RouteFeeResponse) Reset() {
-	*x = RouteFeeResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *RouteFeeResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*RouteFeeResponse) ProtoMessage() {}
-
-func (x *RouteFeeResponse) ProtoReflect() protoreflect.Message {
+func (x *TrackPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -942,50 +1120,37 @@ func (x *RouteFeeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RouteFeeResponse.ProtoReflect.Descriptor instead.
-func (*RouteFeeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TrackPaymentRequest.ProtoReflect.Descriptor instead.
+func (*TrackPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RouteFeeResponse) GetRoutingFeeMsat() int64 {
-	if x != nil {
-		return x.RoutingFeeMsat
-	}
-	return 0
-}
-
-func (x *RouteFeeResponse) GetTimeLockDelay() int64 {
+func (x *TrackPaymentRequest) GetPaymentHash() []byte {
 	if x != nil {
-		return x.TimeLockDelay
+		return x.PaymentHash
 	}
-	return 0
+	return nil
 }
 
-func (x *RouteFeeResponse) GetFailureReason() lnrpc.PaymentFailureReason {
+func (x *TrackPaymentRequest) GetNoInflightUpdates() bool {
 	if x != nil {
-		return x.FailureReason
+		return x.NoInflightUpdates
 	}
-	return lnrpc.PaymentFailureReason(0)
+	return false
 }
 
-type SendToRouteRequest struct {
+type TrackPaymentsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The payment hash to use for the HTLC.
-	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
-	// Route that should be used to attempt to complete the payment.
-	Route *lnrpc.Route `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
-	// Whether the payment should be marked as failed when a temporary error is
-	// returned from the given route. Set it to true so the payment won't be
-	// failed unless a terminal error is occurred, such as payment timeout, no
-	// routes, incorrect payment details, or insufficient funds.
-	SkipTempErr bool `protobuf:"varint,3,opt,name=skip_temp_err,json=skipTempErr,proto3" json:"skip_temp_err,omitempty"`
+	// If set, only the final payment updates are streamed back. Intermediate
+	// updates that show which htlcs are still in flight are suppressed.
+	NoInflightUpdates bool `protobuf:"varint,1,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
 }
 
-func (x *SendToRouteRequest) Reset() {
-	*x = SendToRouteRequest{}
+func (x *TrackPaymentsRequest) Reset() {
+	*x = TrackPaymentsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -993,13 +1158,13 @@ func (x *SendToRouteRequest) Reset() {
 	}
 }
 
-func (x *SendToRouteRequest) String() string {
+func (x *TrackPaymentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendToRouteRequest) ProtoMessage() {}
+func (*TrackPaymentsRequest) ProtoMessage() {}
 
-func (x *SendToRouteRequest) ProtoReflect() protoreflect.Message {
+func (x *TrackPaymentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1011,45 +1176,29 @@ func (x *SendToRouteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendToRouteRequest.ProtoReflect.Descriptor instead.
-func (*SendToRouteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TrackPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*TrackPaymentsRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *SendToRouteRequest) GetPaymentHash() []byte {
-	if x != nil {
-		return x.PaymentHash
-	}
-	return nil
-}
-
-func (x *SendToRouteRequest) GetRoute() *lnrpc.Route {
-	if x != nil {
-		return x.Route
-	}
-	return nil
-}
-
-func (x *SendToRouteRequest) GetSkipTempErr() bool {
+func (x *TrackPaymentsRequest) GetNoInflightUpdates() bool {
 	if x != nil {
-		return x.SkipTempErr
+		return x.NoInflightUpdates
 	}
 	return false
 }
 
-type SendToRouteResponse struct {
+type CancelPaymentRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The preimage obtained by making the payment.
-	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
-	// The failure message in case the payment failed.
-	Failure *lnrpc.Failure `protobuf:"bytes,2,opt,name=failure,proto3" json:"failure,omitempty"`
+	// The hash of the payment to cancel.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
 }
 
-func (x *SendToRouteResponse) Reset() {
-	*x = SendToRouteResponse{}
+func (x *CancelPaymentRequest) Reset() {
+	*x = CancelPaymentRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1057,13 +1206,13 @@ func (x *SendToRouteResponse) Reset() {
 	}
 }
 
-func (x *SendToRouteResponse) String() string {
+func (x *CancelPaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendToRouteResponse) ProtoMessage() {}
+func (*CancelPaymentRequest) ProtoMessage() {}
 
-func (x *SendToRouteResponse) ProtoReflect() protoreflect.Message {
+func (x *CancelPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1075,33 +1224,26 @@ func (x *SendToRouteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendToRouteResponse.ProtoReflect.Descriptor instead.
-func (*SendToRouteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelPaymentRequest.ProtoReflect.Descriptor instead.
+func (*CancelPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SendToRouteResponse) GetPreimage() []byte {
-	if x != nil {
-		return x.Preimage
-	}
-	return nil
-}
-
-func (x *SendToRouteResponse) GetFailure() *lnrpc.Failure {
+func (x *CancelPaymentRequest) GetPaymentHash() []byte {
 	if x != nil {
-		return x.Failure
+		return x.PaymentHash
 	}
 	return nil
 }
 
-type ResetMissionControlRequest struct {
+type CancelPaymentResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *ResetMissionControlRequest) Reset() {
-	*x = ResetMissionControlRequest{}
+func (x *CancelPaymentResponse) Reset() {
+	*x = CancelPaymentResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1109,13 +1251,13 @@ func (x *ResetMissionControlRequest) Reset() {
 	}
 }
 
-func (x *ResetMissionControlRequest) String() string {
+func (x *CancelPaymentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetMissionControlRequest) ProtoMessage() {}
+func (*CancelPaymentResponse) ProtoMessage() {}
 
-func (x *ResetMissionControlRequest) ProtoReflect() protoreflect.Message {
+func (x *CancelPaymentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1127,19 +1269,43 @@ func (x *ResetMissionControlRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetMissionControlRequest.ProtoReflect.Descriptor instead.
-func (*ResetMissionControlRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelPaymentResponse.ProtoReflect.Descriptor instead.
+func (*CancelPaymentResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{7}
 }
 
-type ResetMissionControlResponse struct {
+type RouteFeeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The destination one wishes to obtain a routing fee quote to. If set, this
+	// parameter requires the amt_sat parameter also to be set. This parameter
+	// combination triggers a graph based routing fee estimation as opposed to a
+	// payment probe based estimate in case a payment request is provided. The
+	// graph based estimation is an algorithm that is executed on the in memory
+	// graph. Hence its runtime is significantly shorter than a payment probe
+	// estimation that sends out actual payments to the network.
+	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
+	// The amount one wishes to send to the target destination. It is only to be
+	// used in combination with the dest parameter.
+	AmtSat int64 `protobuf:"varint,2,opt,name=amt_sat,json=amtSat,proto3" json:"amt_sat,omitempty"`
+	// A payment request of the target node that the route fee request is intended
+	// for. Its parameters are input to probe payments that estimate routing fees.
+	// The timeout parameter can be specified to set a maximum time on the probing
+	// attempt. Cannot be used in combination with dest and amt_sat.
+	PaymentRequest string `protobuf:"bytes,3,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	// A user preference of how long a probe payment should maximally be allowed to
+	// take, denoted in seconds. The probing payment loop is aborted if this
+	// timeout is reached. Note that the probing process itself can take longer
+	// than the timeout if the HTLC becomes delayed or stuck. Canceling the context
+	// of this call will not cancel the payment loop, the duration is only
+	// controlled by the timeout parameter.
+	Timeout uint32 `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
 }
 
-func (x *ResetMissionControlResponse) Reset() {
-	*x = ResetMissionControlResponse{}
+func (x *RouteFeeRequest) Reset() {
+	*x = RouteFeeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1147,13 +1313,13 @@ func (x *ResetMissionControlResponse) Reset() {
 	}
 }
 
-func (x *ResetMissionControlResponse) String() string {
+func (x *RouteFeeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetMissionControlResponse) ProtoMessage() {}
+func (*RouteFeeRequest) ProtoMessage() {}
 
-func (x *ResetMissionControlResponse) ProtoReflect() protoreflect.Message {
+func (x *RouteFeeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1165,19 +1331,58 @@ func (x *ResetMissionControlResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetMissionControlResponse.ProtoReflect.Descriptor instead.
-func (*ResetMissionControlResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RouteFeeRequest.ProtoReflect.Descriptor instead.
+func (*RouteFeeRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{8}
 }
 
-type QueryMissionControlRequest struct {
+func (x *RouteFeeRequest) GetDest() []byte {
+	if x != nil {
+		return x.Dest
+	}
+	return nil
+}
+
+func (x *RouteFeeRequest) GetAmtSat() int64 {
+	if x != nil {
+		return x.AmtSat
+	}
+	return 0
+}
+
+func (x *RouteFeeRequest) GetPaymentRequest() string {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return ""
+}
+
+func (x *RouteFeeRequest) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type RouteFeeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// A lower bound of the estimated fee to the target destination within the
+	// network, expressed in milli-satoshis.
+	RoutingFeeMsat int64 `protobuf:"varint,1,opt,name=routing_fee_msat,json=routingFeeMsat,proto3" json:"routing_fee_msat,omitempty"`
+	// An estimate of the worst case time delay that can occur. Note that callers
+	// will still need to factor in the final CLTV delta of the last hop into this
+	// value.
+	TimeLockDelay int64 `protobuf:"varint,2,opt,name=time_lock_delay,json=timeLockDelay,proto3" json:"time_lock_delay,omitempty"`
+	// An indication whether a probing payment succeeded or whether and why it
+	// failed. FAILURE_REASON_NONE indicates success.
+	FailureReason lnrpc.PaymentFailureReason `protobuf:"varint,5,opt,name=failure_reason,json=failureReason,proto3,enum=lnrpc.PaymentFailureReason" json:"failure_reason,omitempty"`
 }
 
-func (x *QueryMissionControlRequest) Reset() {
-	*x = QueryMissionControlRequest{}
+func (x *RouteFeeResponse) Reset() {
+	*x = RouteFeeResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1185,13 +1390,13 @@ func (x *QueryMissionControlRequest) Reset() {
 	}
 }
 
-func (x *QueryMissionControlRequest) String() string {
+func (x *RouteFeeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryMissionControlRequest) ProtoMessage() {}
+func (*RouteFeeResponse) ProtoMessage() {}
 
-func (x *QueryMissionControlRequest) ProtoReflect() protoreflect.Message {
+func (x *RouteFeeResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1203,23 +1408,45 @@ func (x *QueryMissionControlRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryMissionControlRequest.ProtoReflect.Descriptor instead.
-func (*QueryMissionControlRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RouteFeeResponse.ProtoReflect.Descriptor instead.
+func (*RouteFeeResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{9}
 }
 
-// QueryMissionControlResponse contains mission control state.
-type QueryMissionControlResponse struct {
+func (x *RouteFeeResponse) GetRoutingFeeMsat() int64 {
+	if x != nil {
+		return x.RoutingFeeMsat
+	}
+	return 0
+}
+
+func (x *RouteFeeResponse) GetTimeLockDelay() int64 {
+	if x != nil {
+		return x.TimeLockDelay
+	}
+	return 0
+}
+
+func (x *RouteFeeResponse) GetFailureReason() lnrpc.PaymentFailureReason {
+	if x != nil {
+		return x.FailureReason
+	}
+	return lnrpc.PaymentFailureReason(0)
+}
+
+type QueryRouteFeesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Node pair-level mission control state.
-	Pairs []*PairHistory `protobuf:"bytes,2,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// The query describing the payment to find routes for.
+	Query *lnrpc.QueryRoutesRequest `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// The maximum number of routes to return. Must be at least one.
+	NumRoutes uint32 `protobuf:"varint,2,opt,name=num_routes,json=numRoutes,proto3" json:"num_routes,omitempty"`
 }
 
-func (x *QueryMissionControlResponse) Reset() {
-	*x = QueryMissionControlResponse{}
+func (x *QueryRouteFeesRequest) Reset() {
+	*x = QueryRouteFeesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1227,13 +1454,13 @@ func (x *QueryMissionControlResponse) Reset() {
 	}
 }
 
-func (x *QueryMissionControlResponse) String() string {
+func (x *QueryRouteFeesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryMissionControlResponse) ProtoMessage() {}
+func (*QueryRouteFeesRequest) ProtoMessage() {}
 
-func (x *QueryMissionControlResponse) ProtoReflect() protoreflect.Message {
+func (x *QueryRouteFeesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1245,33 +1472,36 @@ func (x *QueryMissionControlResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryMissionControlResponse.ProtoReflect.Descriptor instead.
-func (*QueryMissionControlResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryRouteFeesRequest.ProtoReflect.Descriptor instead.
+func (*QueryRouteFeesRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *QueryMissionControlResponse) GetPairs() []*PairHistory {
+func (x *QueryRouteFeesRequest) GetQuery() *lnrpc.QueryRoutesRequest {
 	if x != nil {
-		return x.Pairs
+		return x.Query
 	}
 	return nil
 }
 
-type XImportMissionControlRequest struct {
-	state         protoimpl.MessageState
+func (x *QueryRouteFeesRequest) GetNumRoutes() uint32 {
+	if x != nil {
+		return x.NumRoutes
+	}
+	return 0
+}
+
+type QueryRouteFeesResponse struct {
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Node pair-level mission control state to be imported.
-	Pairs []*PairHistory `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
-	// Whether to force override MC pair history. Note that even with force
-	// override the failure pair is imported before the success pair and both
-	// still clamp existing failure/success amounts.
-	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	// The fees of the routes found, ordered from the best route.
+	Routes []*RouteFees `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
 }
 
-func (x *XImportMissionControlRequest) Reset() {
-	*x = XImportMissionControlRequest{}
+func (x *QueryRouteFeesResponse) Reset() {
+	*x = QueryRouteFeesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1279,13 +1509,13 @@ func (x *XImportMissionControlRequest) Reset() {
 	}
 }
 
-func (x *XImportMissionControlRequest) String() string {
+func (x *QueryRouteFeesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*XImportMissionControlRequest) ProtoMessage() {}
+func (*QueryRouteFeesResponse) ProtoMessage() {}
 
-func (x *XImportMissionControlRequest) ProtoReflect() protoreflect.Message {
+func (x *QueryRouteFeesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1297,33 +1527,42 @@ func (x *XImportMissionControlRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use XImportMissionControlRequest.ProtoReflect.Descriptor instead.
-func (*XImportMissionControlRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryRouteFeesResponse.ProtoReflect.Descriptor instead.
+func (*QueryRouteFeesResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *XImportMissionControlRequest) GetPairs() []*PairHistory {
+func (x *QueryRouteFeesResponse) GetRoutes() []*RouteFees {
 	if x != nil {
-		return x.Pairs
+		return x.Routes
 	}
 	return nil
 }
 
-func (x *XImportMissionControlRequest) GetForce() bool {
-	if x != nil {
-		return x.Force
-	}
-	return false
-}
-
-type XImportMissionControlResponse struct {
+type RouteFees struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-}
 
-func (x *XImportMissionControlResponse) Reset() {
-	*x = XImportMissionControlResponse{}
+	// The route the fees are paid along.
+	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	// The fees charged by each forwarding node of the route, in the order of the
+	// route.
+	Hops []*HopFees `protobuf:"bytes,2,rep,name=hops,proto3" json:"hops,omitempty"`
+	// The total fee paid along the route.
+	TotalFeesMsat uint64 `protobuf:"varint,3,opt,name=total_fees_msat,json=totalFeesMsat,proto3" json:"total_fees_msat,omitempty"`
+	// The fee our own policy for the first channel of the route charges when
+	// others forward the amount of the payment over it. We don't pay it when
+	// sending the payment ourselves.
+	OwnOutboundFeeMsat uint64 `protobuf:"varint,4,opt,name=own_outbound_fee_msat,json=ownOutboundFeeMsat,proto3" json:"own_outbound_fee_msat,omitempty"`
+	// The inbound fee our own policy for the first channel of the route charges
+	// for the amount of the payment when others forward it to us over that
+	// channel.
+	OwnInboundFeeMsat int64 `protobuf:"varint,5,opt,name=own_inbound_fee_msat,json=ownInboundFeeMsat,proto3" json:"own_inbound_fee_msat,omitempty"`
+}
+
+func (x *RouteFees) Reset() {
+	*x = RouteFees{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1331,13 +1570,13 @@ func (x *XImportMissionControlResponse) Reset() {
 	}
 }
 
-func (x *XImportMissionControlResponse) String() string {
+func (x *RouteFees) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*XImportMissionControlResponse) ProtoMessage() {}
+func (*RouteFees) ProtoMessage() {}
 
-func (x *XImportMissionControlResponse) ProtoReflect() protoreflect.Message {
+func (x *RouteFees) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1349,115 +1588,87 @@ func (x *XImportMissionControlResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use XImportMissionControlResponse.ProtoReflect.Descriptor instead.
-func (*XImportMissionControlResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RouteFees.ProtoReflect.Descriptor instead.
+func (*RouteFees) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{12}
 }
 
-// PairHistory contains the mission control state for a particular node pair.
-type PairHistory struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// The source node pubkey of the pair.
-	NodeFrom []byte `protobuf:"bytes,1,opt,name=node_from,json=nodeFrom,proto3" json:"node_from,omitempty"`
-	// The destination node pubkey of the pair.
-	NodeTo  []byte    `protobuf:"bytes,2,opt,name=node_to,json=nodeTo,proto3" json:"node_to,omitempty"`
-	History *PairData `protobuf:"bytes,7,opt,name=history,proto3" json:"history,omitempty"`
-}
-
-func (x *PairHistory) Reset() {
-	*x = PairHistory{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[13]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *RouteFees) GetRoute() *lnrpc.Route {
+	if x != nil {
+		return x.Route
 	}
+	return nil
 }
 
-func (x *PairHistory) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*PairHistory) ProtoMessage() {}
-
-func (x *PairHistory) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[13]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *RouteFees) GetHops() []*HopFees {
+	if x != nil {
+		return x.Hops
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use PairHistory.ProtoReflect.Descriptor instead.
-func (*PairHistory) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{13}
+	return nil
 }
 
-func (x *PairHistory) GetNodeFrom() []byte {
+func (x *RouteFees) GetTotalFeesMsat() uint64 {
 	if x != nil {
-		return x.NodeFrom
+		return x.TotalFeesMsat
 	}
-	return nil
+	return 0
 }
 
-func (x *PairHistory) GetNodeTo() []byte {
+func (x *RouteFees) GetOwnOutboundFeeMsat() uint64 {
 	if x != nil {
-		return x.NodeTo
+		return x.OwnOutboundFeeMsat
 	}
-	return nil
+	return 0
 }
 
-func (x *PairHistory) GetHistory() *PairData {
+func (x *RouteFees) GetOwnInboundFeeMsat() int64 {
 	if x != nil {
-		return x.History
+		return x.OwnInboundFeeMsat
 	}
-	return nil
+	return 0
 }
 
-type PairData struct {
+type HopFees struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Time of last failure.
-	FailTime int64 `protobuf:"varint,1,opt,name=fail_time,json=failTime,proto3" json:"fail_time,omitempty"`
-	// Lowest amount that failed to forward rounded to whole sats. This may be
-	// set to zero if the failure is independent of amount.
-	FailAmtSat int64 `protobuf:"varint,2,opt,name=fail_amt_sat,json=failAmtSat,proto3" json:"fail_amt_sat,omitempty"`
-	// Lowest amount that failed to forward in millisats. This may be
-	// set to zero if the failure is independent of amount.
-	FailAmtMsat int64 `protobuf:"varint,4,opt,name=fail_amt_msat,json=failAmtMsat,proto3" json:"fail_amt_msat,omitempty"`
-	// Time of last success.
-	SuccessTime int64 `protobuf:"varint,5,opt,name=success_time,json=successTime,proto3" json:"success_time,omitempty"`
-	// Highest amount that we could successfully forward rounded to whole sats.
-	SuccessAmtSat int64 `protobuf:"varint,6,opt,name=success_amt_sat,json=successAmtSat,proto3" json:"success_amt_sat,omitempty"`
-	// Highest amount that we could successfully forward in millisats.
-	SuccessAmtMsat int64 `protobuf:"varint,7,opt,name=success_amt_msat,json=successAmtMsat,proto3" json:"success_amt_msat,omitempty"`
-}
-
-func (x *PairData) Reset() {
-	*x = PairData{}
+	// The public key of the forwarding node.
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// The channel over which the payment arrives at the node.
+	IncomingChanId uint64 `protobuf:"varint,2,opt,name=incoming_chan_id,json=incomingChanId,proto3" json:"incoming_chan_id,omitempty"`
+	// The channel over which the node forwards the payment.
+	OutgoingChanId uint64 `protobuf:"varint,3,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
+	// The amount the node forwards over the outgoing channel.
+	AmtToForwardMsat uint64 `protobuf:"varint,4,opt,name=amt_to_forward_msat,json=amtToForwardMsat,proto3" json:"amt_to_forward_msat,omitempty"`
+	// The fee the policy of the node for the outgoing channel charges for
+	// forwarding the amount.
+	OutboundFeeMsat uint64 `protobuf:"varint,5,opt,name=outbound_fee_msat,json=outboundFeeMsat,proto3" json:"outbound_fee_msat,omitempty"`
+	// The fee the policy of the node for the incoming channel charges on top of
+	// the outbound fee. A negative inbound fee is a discount, which can't exceed
+	// the outbound fee.
+	InboundFeeMsat int64 `protobuf:"varint,6,opt,name=inbound_fee_msat,json=inboundFeeMsat,proto3" json:"inbound_fee_msat,omitempty"`
+	// The total fee paid to the node along the route.
+	FeeMsat uint64 `protobuf:"varint,7,opt,name=fee_msat,json=feeMsat,proto3" json:"fee_msat,omitempty"`
+}
+
+func (x *HopFees) Reset() {
+	*x = HopFees{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[14]
+		mi := &file_routerrpc_router_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PairData) String() string {
+func (x *HopFees) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PairData) ProtoMessage() {}
+func (*HopFees) ProtoMessage() {}
 
-func (x *PairData) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[14]
+func (x *HopFees) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1468,76 +1679,93 @@ func (x *PairData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PairData.ProtoReflect.Descriptor instead.
-func (*PairData) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use HopFees.ProtoReflect.Descriptor instead.
+func (*HopFees) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PairData) GetFailTime() int64 {
+func (x *HopFees) GetPubKey() []byte {
 	if x != nil {
-		return x.FailTime
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *HopFees) GetIncomingChanId() uint64 {
+	if x != nil {
+		return x.IncomingChanId
 	}
 	return 0
 }
 
-func (x *PairData) GetFailAmtSat() int64 {
+func (x *HopFees) GetOutgoingChanId() uint64 {
 	if x != nil {
-		return x.FailAmtSat
+		return x.OutgoingChanId
 	}
 	return 0
 }
 
-func (x *PairData) GetFailAmtMsat() int64 {
+func (x *HopFees) GetAmtToForwardMsat() uint64 {
 	if x != nil {
-		return x.FailAmtMsat
+		return x.AmtToForwardMsat
 	}
 	return 0
 }
 
-func (x *PairData) GetSuccessTime() int64 {
+func (x *HopFees) GetOutboundFeeMsat() uint64 {
 	if x != nil {
-		return x.SuccessTime
+		return x.OutboundFeeMsat
 	}
 	return 0
 }
 
-func (x *PairData) GetSuccessAmtSat() int64 {
+func (x *HopFees) GetInboundFeeMsat() int64 {
 	if x != nil {
-		return x.SuccessAmtSat
+		return x.InboundFeeMsat
 	}
 	return 0
 }
 
-func (x *PairData) GetSuccessAmtMsat() int64 {
+func (x *HopFees) GetFeeMsat() uint64 {
 	if x != nil {
-		return x.SuccessAmtMsat
+		return x.FeeMsat
 	}
 	return 0
 }
 
-type GetMissionControlConfigRequest struct {
+type SendToRouteRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The payment hash to use for the HTLC.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// Route that should be used to attempt to complete the payment.
+	Route *lnrpc.Route `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
+	// Whether the payment should be marked as failed when a temporary error is
+	// returned from the given route. Set it to true so the payment won't be
+	// failed unless a terminal error is occurred, such as payment timeout, no
+	// routes, incorrect payment details, or insufficient funds.
+	SkipTempErr bool `protobuf:"varint,3,opt,name=skip_temp_err,json=skipTempErr,proto3" json:"skip_temp_err,omitempty"`
 }
 
-func (x *GetMissionControlConfigRequest) Reset() {
-	*x = GetMissionControlConfigRequest{}
+func (x *SendToRouteRequest) Reset() {
+	*x = SendToRouteRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[15]
+		mi := &file_routerrpc_router_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetMissionControlConfigRequest) String() string {
+func (x *SendToRouteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMissionControlConfigRequest) ProtoMessage() {}
+func (*SendToRouteRequest) ProtoMessage() {}
 
-func (x *GetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[15]
+func (x *SendToRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1548,37 +1776,60 @@ func (x *GetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
-func (*GetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use SendToRouteRequest.ProtoReflect.Descriptor instead.
+func (*SendToRouteRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{14}
 }
 
-type GetMissionControlConfigResponse struct {
+func (x *SendToRouteRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+func (x *SendToRouteRequest) GetRoute() *lnrpc.Route {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *SendToRouteRequest) GetSkipTempErr() bool {
+	if x != nil {
+		return x.SkipTempErr
+	}
+	return false
+}
+
+type SendToRouteResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Mission control's currently active config.
-	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// The preimage obtained by making the payment.
+	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// The failure message in case the payment failed.
+	Failure *lnrpc.Failure `protobuf:"bytes,2,opt,name=failure,proto3" json:"failure,omitempty"`
 }
 
-func (x *GetMissionControlConfigResponse) Reset() {
-	*x = GetMissionControlConfigResponse{}
+func (x *SendToRouteResponse) Reset() {
+	*x = SendToRouteResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[16]
+		mi := &file_routerrpc_router_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetMissionControlConfigResponse) String() string {
+func (x *SendToRouteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMissionControlConfigResponse) ProtoMessage() {}
+func (*SendToRouteResponse) ProtoMessage() {}
 
-func (x *GetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[16]
+func (x *SendToRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1589,45 +1840,48 @@ func (x *GetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
-func (*GetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use SendToRouteResponse.ProtoReflect.Descriptor instead.
+func (*SendToRouteResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GetMissionControlConfigResponse) GetConfig() *MissionControlConfig {
+func (x *SendToRouteResponse) GetPreimage() []byte {
 	if x != nil {
-		return x.Config
+		return x.Preimage
 	}
 	return nil
 }
 
-type SetMissionControlConfigRequest struct {
+func (x *SendToRouteResponse) GetFailure() *lnrpc.Failure {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
+}
+
+type ResetMissionControlRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The config to set for mission control. Note that all values *must* be set,
-	// because the full config will be applied.
-	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *SetMissionControlConfigRequest) Reset() {
-	*x = SetMissionControlConfigRequest{}
+func (x *ResetMissionControlRequest) Reset() {
+	*x = ResetMissionControlRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[17]
+		mi := &file_routerrpc_router_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SetMissionControlConfigRequest) String() string {
+func (x *ResetMissionControlRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetMissionControlConfigRequest) ProtoMessage() {}
+func (*ResetMissionControlRequest) ProtoMessage() {}
 
-func (x *SetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[17]
+func (x *ResetMissionControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1638,41 +1892,34 @@ func (x *SetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
-func (*SetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *SetMissionControlConfigRequest) GetConfig() *MissionControlConfig {
-	if x != nil {
-		return x.Config
-	}
-	return nil
+// Deprecated: Use ResetMissionControlRequest.ProtoReflect.Descriptor instead.
+func (*ResetMissionControlRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{16}
 }
 
-type SetMissionControlConfigResponse struct {
+type ResetMissionControlResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *SetMissionControlConfigResponse) Reset() {
-	*x = SetMissionControlConfigResponse{}
+func (x *ResetMissionControlResponse) Reset() {
+	*x = ResetMissionControlResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[18]
+		mi := &file_routerrpc_router_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SetMissionControlConfigResponse) String() string {
+func (x *ResetMissionControlResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetMissionControlConfigResponse) ProtoMessage() {}
+func (*ResetMissionControlResponse) ProtoMessage() {}
 
-func (x *SetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[18]
+func (x *ResetMissionControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1683,75 +1930,34 @@ func (x *SetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
-func (*SetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ResetMissionControlResponse.ProtoReflect.Descriptor instead.
+func (*ResetMissionControlResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{17}
 }
 
-type MissionControlConfig struct {
+type QueryMissionControlRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// Deprecated, use AprioriParameters. The amount of time mission control will
-	// take to restore a penalized node or channel back to 50% success probability,
-	// expressed in seconds. Setting this value to a higher value will penalize
-	// failures for longer, making mission control less likely to route through
-	// nodes and channels that we have previously recorded failures for.
-	//
-	// Deprecated: Marked as deprecated in routerrpc/router.proto.
-	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
-	// Deprecated, use AprioriParameters. The probability of success mission
-	// control should assign to hop in a route where it has no other information
-	// available. Higher values will make mission control more willing to try hops
-	// that we have no information about, lower values will discourage trying these
-	// hops.
-	//
-	// Deprecated: Marked as deprecated in routerrpc/router.proto.
-	HopProbability float32 `protobuf:"fixed32,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
-	// Deprecated, use AprioriParameters. The importance that mission control
-	// should place on historical results, expressed as a value in [0;1]. Setting
-	// this value to 1 will ignore all historical payments and just use the hop
-	// probability to assess the probability of success for each hop. A zero value
-	// ignores hop probability completely and relies entirely on historical
-	// results, unless none are available.
-	//
-	// Deprecated: Marked as deprecated in routerrpc/router.proto.
-	Weight float32 `protobuf:"fixed32,3,opt,name=weight,proto3" json:"weight,omitempty"`
-	// The maximum number of payment results that mission control will store.
-	MaximumPaymentResults uint32 `protobuf:"varint,4,opt,name=maximum_payment_results,json=maximumPaymentResults,proto3" json:"maximum_payment_results,omitempty"`
-	// The minimum time that must have passed since the previously recorded failure
-	// before we raise the failure amount.
-	MinimumFailureRelaxInterval uint64 `protobuf:"varint,5,opt,name=minimum_failure_relax_interval,json=minimumFailureRelaxInterval,proto3" json:"minimum_failure_relax_interval,omitempty"`
-	// ProbabilityModel defines which probability estimator should be used in
-	// pathfinding. Note that the bimodal estimator is experimental.
-	Model MissionControlConfig_ProbabilityModel `protobuf:"varint,6,opt,name=model,proto3,enum=routerrpc.MissionControlConfig_ProbabilityModel" json:"model,omitempty"`
-	// EstimatorConfig is populated dependent on the estimator type.
-	//
-	// Types that are assignable to EstimatorConfig:
-	//
-	//	*MissionControlConfig_Apriori
-	//	*MissionControlConfig_Bimodal
-	EstimatorConfig isMissionControlConfig_EstimatorConfig `protobuf_oneof:"EstimatorConfig"`
 }
 
-func (x *MissionControlConfig) Reset() {
-	*x = MissionControlConfig{}
+func (x *QueryMissionControlRequest) Reset() {
+	*x = QueryMissionControlRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[19]
+		mi := &file_routerrpc_router_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MissionControlConfig) String() string {
+func (x *QueryMissionControlRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MissionControlConfig) ProtoMessage() {}
+func (*QueryMissionControlRequest) ProtoMessage() {}
 
-func (x *MissionControlConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[19]
+func (x *QueryMissionControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1762,118 +1968,75 @@ func (x *MissionControlConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MissionControlConfig.ProtoReflect.Descriptor instead.
-func (*MissionControlConfig) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{19}
-}
-
-// Deprecated: Marked as deprecated in routerrpc/router.proto.
-func (x *MissionControlConfig) GetHalfLifeSeconds() uint64 {
-	if x != nil {
-		return x.HalfLifeSeconds
-	}
-	return 0
+// Deprecated: Use QueryMissionControlRequest.ProtoReflect.Descriptor instead.
+func (*QueryMissionControlRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{18}
 }
 
-// Deprecated: Marked as deprecated in routerrpc/router.proto.
-func (x *MissionControlConfig) GetHopProbability() float32 {
-	if x != nil {
-		return x.HopProbability
-	}
-	return 0
-}
+// QueryMissionControlResponse contains mission control state.
+type QueryMissionControlResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-// Deprecated: Marked as deprecated in routerrpc/router.proto.
-func (x *MissionControlConfig) GetWeight() float32 {
-	if x != nil {
-		return x.Weight
-	}
-	return 0
+	// Node pair-level mission control state.
+	Pairs []*PairHistory `protobuf:"bytes,2,rep,name=pairs,proto3" json:"pairs,omitempty"`
 }
 
-func (x *MissionControlConfig) GetMaximumPaymentResults() uint32 {
-	if x != nil {
-		return x.MaximumPaymentResults
+func (x *QueryMissionControlResponse) Reset() {
+	*x = QueryMissionControlResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *MissionControlConfig) GetMinimumFailureRelaxInterval() uint64 {
-	if x != nil {
-		return x.MinimumFailureRelaxInterval
-	}
-	return 0
+func (x *QueryMissionControlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *MissionControlConfig) GetModel() MissionControlConfig_ProbabilityModel {
-	if x != nil {
-		return x.Model
-	}
-	return MissionControlConfig_APRIORI
-}
+func (*QueryMissionControlResponse) ProtoMessage() {}
 
-func (m *MissionControlConfig) GetEstimatorConfig() isMissionControlConfig_EstimatorConfig {
-	if m != nil {
-		return m.EstimatorConfig
+func (x *QueryMissionControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *MissionControlConfig) GetApriori() *AprioriParameters {
-	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Apriori); ok {
-		return x.Apriori
-	}
-	return nil
+// Deprecated: Use QueryMissionControlResponse.ProtoReflect.Descriptor instead.
+func (*QueryMissionControlResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *MissionControlConfig) GetBimodal() *BimodalParameters {
-	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Bimodal); ok {
-		return x.Bimodal
+func (x *QueryMissionControlResponse) GetPairs() []*PairHistory {
+	if x != nil {
+		return x.Pairs
 	}
 	return nil
 }
 
-type isMissionControlConfig_EstimatorConfig interface {
-	isMissionControlConfig_EstimatorConfig()
-}
-
-type MissionControlConfig_Apriori struct {
-	Apriori *AprioriParameters `protobuf:"bytes,7,opt,name=apriori,proto3,oneof"`
-}
-
-type MissionControlConfig_Bimodal struct {
-	Bimodal *BimodalParameters `protobuf:"bytes,8,opt,name=bimodal,proto3,oneof"`
-}
-
-func (*MissionControlConfig_Apriori) isMissionControlConfig_EstimatorConfig() {}
-
-func (*MissionControlConfig_Bimodal) isMissionControlConfig_EstimatorConfig() {}
-
-type BimodalParameters struct {
+type XImportMissionControlRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// NodeWeight defines how strongly other previous forwardings on channels of a
-	// router should be taken into account when computing a channel's probability
-	// to route. The allowed values are in the range [0, 1], where a value of 0
-	// means that only direct information about a channel is taken into account.
-	NodeWeight float64 `protobuf:"fixed64,1,opt,name=node_weight,json=nodeWeight,proto3" json:"node_weight,omitempty"`
-	// ScaleMsat describes the scale over which channels statistically have some
-	// liquidity left. The value determines how quickly the bimodal distribution
-	// drops off from the edges of a channel. A larger value (compared to typical
-	// channel capacities) means that the drop off is slow and that channel
-	// balances are distributed more uniformly. A small value leads to the
-	// assumption of very unbalanced channels.
-	ScaleMsat uint64 `protobuf:"varint,2,opt,name=scale_msat,json=scaleMsat,proto3" json:"scale_msat,omitempty"`
-	// DecayTime describes the information decay of knowledge about previous
-	// successes and failures in channels. The smaller the decay time, the quicker
-	// we forget about past forwardings.
-	DecayTime uint64 `protobuf:"varint,3,opt,name=decay_time,json=decayTime,proto3" json:"decay_time,omitempty"`
+	// Node pair-level mission control state to be imported.
+	Pairs []*PairHistory `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// Whether to force override MC pair history. Note that even with force
+	// override the failure pair is imported before the success pair and both
+	// still clamp existing failure/success amounts.
+	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
 }
 
-func (x *BimodalParameters) Reset() {
-	*x = BimodalParameters{}
+func (x *XImportMissionControlRequest) Reset() {
+	*x = XImportMissionControlRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1881,13 +2044,13 @@ func (x *BimodalParameters) Reset() {
 	}
 }
 
-func (x *BimodalParameters) String() string {
+func (x *XImportMissionControlRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BimodalParameters) ProtoMessage() {}
+func (*XImportMissionControlRequest) ProtoMessage() {}
 
-func (x *BimodalParameters) ProtoReflect() protoreflect.Message {
+func (x *XImportMissionControlRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1899,64 +2062,33 @@ func (x *BimodalParameters) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BimodalParameters.ProtoReflect.Descriptor instead.
-func (*BimodalParameters) Descriptor() ([]byte, []int) {
+// Deprecated: Use XImportMissionControlRequest.ProtoReflect.Descriptor instead.
+func (*XImportMissionControlRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *BimodalParameters) GetNodeWeight() float64 {
-	if x != nil {
-		return x.NodeWeight
-	}
-	return 0
-}
-
-func (x *BimodalParameters) GetScaleMsat() uint64 {
+func (x *XImportMissionControlRequest) GetPairs() []*PairHistory {
 	if x != nil {
-		return x.ScaleMsat
+		return x.Pairs
 	}
-	return 0
+	return nil
 }
 
-func (x *BimodalParameters) GetDecayTime() uint64 {
+func (x *XImportMissionControlRequest) GetForce() bool {
 	if x != nil {
-		return x.DecayTime
+		return x.Force
 	}
-	return 0
+	return false
 }
 
-type AprioriParameters struct {
+type XImportMissionControlResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+}
 
-	// The amount of time mission control will take to restore a penalized node
-	// or channel back to 50% success probability, expressed in seconds. Setting
-	// this value to a higher value will penalize failures for longer, making
-	// mission control less likely to route through nodes and channels that we
-	// have previously recorded failures for.
-	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
-	// The probability of success mission control should assign to hop in a route
-	// where it has no other information available. Higher values will make mission
-	// control more willing to try hops that we have no information about, lower
-	// values will discourage trying these hops.
-	HopProbability float64 `protobuf:"fixed64,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
-	// The importance that mission control should place on historical results,
-	// expressed as a value in [0;1]. Setting this value to 1 will ignore all
-	// historical payments and just use the hop probability to assess the
-	// probability of success for each hop. A zero value ignores hop probability
-	// completely and relies entirely on historical results, unless none are
-	// available.
-	Weight float64 `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
-	// The fraction of a channel's capacity that we consider to have liquidity. For
-	// amounts that come close to or exceed the fraction, an additional penalty is
-	// applied. A value of 1.0 disables the capacity factor. Allowed values are in
-	// [0.75, 1.0].
-	CapacityFraction float64 `protobuf:"fixed64,4,opt,name=capacity_fraction,json=capacityFraction,proto3" json:"capacity_fraction,omitempty"`
-}
-
-func (x *AprioriParameters) Reset() {
-	*x = AprioriParameters{}
+func (x *XImportMissionControlResponse) Reset() {
+	*x = XImportMissionControlResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1964,13 +2096,13 @@ func (x *AprioriParameters) Reset() {
 	}
 }
 
-func (x *AprioriParameters) String() string {
+func (x *XImportMissionControlResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AprioriParameters) ProtoMessage() {}
+func (*XImportMissionControlResponse) ProtoMessage() {}
 
-func (x *AprioriParameters) ProtoReflect() protoreflect.Message {
+func (x *XImportMissionControlResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1982,54 +2114,26 @@ func (x *AprioriParameters) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AprioriParameters.ProtoReflect.Descriptor instead.
-func (*AprioriParameters) Descriptor() ([]byte, []int) {
+// Deprecated: Use XImportMissionControlResponse.ProtoReflect.Descriptor instead.
+func (*XImportMissionControlResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *AprioriParameters) GetHalfLifeSeconds() uint64 {
-	if x != nil {
-		return x.HalfLifeSeconds
-	}
-	return 0
-}
-
-func (x *AprioriParameters) GetHopProbability() float64 {
-	if x != nil {
-		return x.HopProbability
-	}
-	return 0
-}
-
-func (x *AprioriParameters) GetWeight() float64 {
-	if x != nil {
-		return x.Weight
-	}
-	return 0
-}
-
-func (x *AprioriParameters) GetCapacityFraction() float64 {
-	if x != nil {
-		return x.CapacityFraction
-	}
-	return 0
-}
-
-type QueryProbabilityRequest struct {
+// PairHistory contains the mission control state for a particular node pair.
+type PairHistory struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// The source node pubkey of the pair.
-	FromNode []byte `protobuf:"bytes,1,opt,name=from_node,json=fromNode,proto3" json:"from_node,omitempty"`
+	NodeFrom []byte `protobuf:"bytes,1,opt,name=node_from,json=nodeFrom,proto3" json:"node_from,omitempty"`
 	// The destination node pubkey of the pair.
-	ToNode []byte `protobuf:"bytes,2,opt,name=to_node,json=toNode,proto3" json:"to_node,omitempty"`
-	// The amount for which to calculate a probability.
-	AmtMsat int64 `protobuf:"varint,3,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
+	NodeTo  []byte    `protobuf:"bytes,2,opt,name=node_to,json=nodeTo,proto3" json:"node_to,omitempty"`
+	History *PairData `protobuf:"bytes,7,opt,name=history,proto3" json:"history,omitempty"`
 }
 
-func (x *QueryProbabilityRequest) Reset() {
-	*x = QueryProbabilityRequest{}
+func (x *PairHistory) Reset() {
+	*x = PairHistory{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2037,13 +2141,13 @@ func (x *QueryProbabilityRequest) Reset() {
 	}
 }
 
-func (x *QueryProbabilityRequest) String() string {
+func (x *PairHistory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryProbabilityRequest) ProtoMessage() {}
+func (*PairHistory) ProtoMessage() {}
 
-func (x *QueryProbabilityRequest) ProtoReflect() protoreflect.Message {
+func (x *PairHistory) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2055,45 +2159,55 @@ func (x *QueryProbabilityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryProbabilityRequest.ProtoReflect.Descriptor instead.
-func (*QueryProbabilityRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PairHistory.ProtoReflect.Descriptor instead.
+func (*PairHistory) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *QueryProbabilityRequest) GetFromNode() []byte {
+func (x *PairHistory) GetNodeFrom() []byte {
 	if x != nil {
-		return x.FromNode
+		return x.NodeFrom
 	}
 	return nil
 }
 
-func (x *QueryProbabilityRequest) GetToNode() []byte {
+func (x *PairHistory) GetNodeTo() []byte {
 	if x != nil {
-		return x.ToNode
+		return x.NodeTo
 	}
 	return nil
 }
 
-func (x *QueryProbabilityRequest) GetAmtMsat() int64 {
+func (x *PairHistory) GetHistory() *PairData {
 	if x != nil {
-		return x.AmtMsat
+		return x.History
 	}
-	return 0
+	return nil
 }
 
-type QueryProbabilityResponse struct {
+type PairData struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The success probability for the requested pair.
-	Probability float64 `protobuf:"fixed64,1,opt,name=probability,proto3" json:"probability,omitempty"`
-	// The historical data for the requested pair.
-	History *PairData `protobuf:"bytes,2,opt,name=history,proto3" json:"history,omitempty"`
+	// Time of last failure.
+	FailTime int64 `protobuf:"varint,1,opt,name=fail_time,json=failTime,proto3" json:"fail_time,omitempty"`
+	// Lowest amount that failed to forward rounded to whole sats. This may be
+	// set to zero if the failure is independent of amount.
+	FailAmtSat int64 `protobuf:"varint,2,opt,name=fail_amt_sat,json=failAmtSat,proto3" json:"fail_amt_sat,omitempty"`
+	// Lowest amount that failed to forward in millisats. This may be
+	// set to zero if the failure is independent of amount.
+	FailAmtMsat int64 `protobuf:"varint,4,opt,name=fail_amt_msat,json=failAmtMsat,proto3" json:"fail_amt_msat,omitempty"`
+	// Time of last success.
+	SuccessTime int64 `protobuf:"varint,5,opt,name=success_time,json=successTime,proto3" json:"success_time,omitempty"`
+	// Highest amount that we could successfully forward rounded to whole sats.
+	SuccessAmtSat int64 `protobuf:"varint,6,opt,name=success_amt_sat,json=successAmtSat,proto3" json:"success_amt_sat,omitempty"`
+	// Highest amount that we could successfully forward in millisats.
+	SuccessAmtMsat int64 `protobuf:"varint,7,opt,name=success_amt_msat,json=successAmtMsat,proto3" json:"success_amt_msat,omitempty"`
 }
 
-func (x *QueryProbabilityResponse) Reset() {
-	*x = QueryProbabilityResponse{}
+func (x *PairData) Reset() {
+	*x = PairData{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2101,13 +2215,13 @@ func (x *QueryProbabilityResponse) Reset() {
 	}
 }
 
-func (x *QueryProbabilityResponse) String() string {
+func (x *PairData) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryProbabilityResponse) ProtoMessage() {}
+func (*PairData) ProtoMessage() {}
 
-func (x *QueryProbabilityResponse) ProtoReflect() protoreflect.Message {
+func (x *PairData) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2119,49 +2233,61 @@ func (x *QueryProbabilityResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryProbabilityResponse.ProtoReflect.Descriptor instead.
-func (*QueryProbabilityResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use PairData.ProtoReflect.Descriptor instead.
+func (*PairData) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *QueryProbabilityResponse) GetProbability() float64 {
+func (x *PairData) GetFailTime() int64 {
 	if x != nil {
-		return x.Probability
+		return x.FailTime
 	}
 	return 0
 }
 
-func (x *QueryProbabilityResponse) GetHistory() *PairData {
+func (x *PairData) GetFailAmtSat() int64 {
 	if x != nil {
-		return x.History
+		return x.FailAmtSat
 	}
-	return nil
+	return 0
 }
 
-type BuildRouteRequest struct {
+func (x *PairData) GetFailAmtMsat() int64 {
+	if x != nil {
+		return x.FailAmtMsat
+	}
+	return 0
+}
+
+func (x *PairData) GetSuccessTime() int64 {
+	if x != nil {
+		return x.SuccessTime
+	}
+	return 0
+}
+
+func (x *PairData) GetSuccessAmtSat() int64 {
+	if x != nil {
+		return x.SuccessAmtSat
+	}
+	return 0
+}
+
+func (x *PairData) GetSuccessAmtMsat() int64 {
+	if x != nil {
+		return x.SuccessAmtMsat
+	}
+	return 0
+}
+
+type GetMissionControlConfigRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The amount to send expressed in msat. If set to zero, the minimum routable
-	// amount is used.
-	AmtMsat int64 `protobuf:"varint,1,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
-	// CLTV delta from the current height that should be used for the timelock
-	// of the final hop
-	FinalCltvDelta int32 `protobuf:"varint,2,opt,name=final_cltv_delta,json=finalCltvDelta,proto3" json:"final_cltv_delta,omitempty"`
-	// The channel id of the channel that must be taken to the first hop. If zero,
-	// any channel may be used.
-	OutgoingChanId uint64 `protobuf:"varint,3,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
-	// A list of hops that defines the route. This does not include the source hop
-	// pubkey.
-	HopPubkeys [][]byte `protobuf:"bytes,4,rep,name=hop_pubkeys,json=hopPubkeys,proto3" json:"hop_pubkeys,omitempty"`
-	// An optional payment addr to be included within the last hop of the route.
-	// This is also called payment secret in specifications (e.g. BOLT 11).
-	PaymentAddr []byte `protobuf:"bytes,5,opt,name=payment_addr,json=paymentAddr,proto3" json:"payment_addr,omitempty"`
 }
 
-func (x *BuildRouteRequest) Reset() {
-	*x = BuildRouteRequest{}
+func (x *GetMissionControlConfigRequest) Reset() {
+	*x = GetMissionControlConfigRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2169,13 +2295,13 @@ func (x *BuildRouteRequest) Reset() {
 	}
 }
 
-func (x *BuildRouteRequest) String() string {
+func (x *GetMissionControlConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuildRouteRequest) ProtoMessage() {}
+func (*GetMissionControlConfigRequest) ProtoMessage() {}
 
-func (x *BuildRouteRequest) ProtoReflect() protoreflect.Message {
+func (x *GetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2187,57 +2313,22 @@ func (x *BuildRouteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuildRouteRequest.ProtoReflect.Descriptor instead.
-func (*BuildRouteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *BuildRouteRequest) GetAmtMsat() int64 {
-	if x != nil {
-		return x.AmtMsat
-	}
-	return 0
-}
-
-func (x *BuildRouteRequest) GetFinalCltvDelta() int32 {
-	if x != nil {
-		return x.FinalCltvDelta
-	}
-	return 0
-}
-
-func (x *BuildRouteRequest) GetOutgoingChanId() uint64 {
-	if x != nil {
-		return x.OutgoingChanId
-	}
-	return 0
-}
-
-func (x *BuildRouteRequest) GetHopPubkeys() [][]byte {
-	if x != nil {
-		return x.HopPubkeys
-	}
-	return nil
-}
-
-func (x *BuildRouteRequest) GetPaymentAddr() []byte {
-	if x != nil {
-		return x.PaymentAddr
-	}
-	return nil
-}
-
-type BuildRouteResponse struct {
+type GetMissionControlConfigResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Fully specified route that can be used to execute the payment.
-	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	// Mission control's currently active config.
+	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *BuildRouteResponse) Reset() {
-	*x = BuildRouteResponse{}
+func (x *GetMissionControlConfigResponse) Reset() {
+	*x = GetMissionControlConfigResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2245,13 +2336,13 @@ func (x *BuildRouteResponse) Reset() {
 	}
 }
 
-func (x *BuildRouteResponse) String() string {
+func (x *GetMissionControlConfigResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuildRouteResponse) ProtoMessage() {}
+func (*GetMissionControlConfigResponse) ProtoMessage() {}
 
-func (x *BuildRouteResponse) ProtoReflect() protoreflect.Message {
+func (x *GetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2263,26 +2354,30 @@ func (x *BuildRouteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuildRouteResponse.ProtoReflect.Descriptor instead.
-func (*BuildRouteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *BuildRouteResponse) GetRoute() *lnrpc.Route {
+func (x *GetMissionControlConfigResponse) GetConfig() *MissionControlConfig {
 	if x != nil {
-		return x.Route
+		return x.Config
 	}
 	return nil
 }
 
-type SubscribeHtlcEventsRequest struct {
+type SetMissionControlConfigRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The config to set for mission control. Note that all values *must* be set,
+	// because the full config will be applied.
+	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *SubscribeHtlcEventsRequest) Reset() {
-	*x = SubscribeHtlcEventsRequest{}
+func (x *SetMissionControlConfigRequest) Reset() {
+	*x = SetMissionControlConfigRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2290,13 +2385,13 @@ func (x *SubscribeHtlcEventsRequest) Reset() {
 	}
 }
 
-func (x *SubscribeHtlcEventsRequest) String() string {
+func (x *SetMissionControlConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribeHtlcEventsRequest) ProtoMessage() {}
+func (*SetMissionControlConfigRequest) ProtoMessage() {}
 
-func (x *SubscribeHtlcEventsRequest) ProtoReflect() protoreflect.Message {
+func (x *SetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2308,52 +2403,26 @@ func (x *SubscribeHtlcEventsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribeHtlcEventsRequest.ProtoReflect.Descriptor instead.
-func (*SubscribeHtlcEventsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
+func (*SetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{26}
 }
 
-// HtlcEvent contains the htlc event that was processed. These are served on a
-// best-effort basis; events are not persisted, delivery is not guaranteed
-// (in the event of a crash in the switch, forward events may be lost) and
-// some events may be replayed upon restart. Events consumed from this package
-// should be de-duplicated by the htlc's unique combination of incoming and
-// outgoing channel id and htlc id. [EXPERIMENTAL]
-type HtlcEvent struct {
+func (x *SetMissionControlConfigRequest) GetConfig() *MissionControlConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type SetMissionControlConfigResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The short channel id that the incoming htlc arrived at our node on. This
-	// value is zero for sends.
-	IncomingChannelId uint64 `protobuf:"varint,1,opt,name=incoming_channel_id,json=incomingChannelId,proto3" json:"incoming_channel_id,omitempty"`
-	// The short channel id that the outgoing htlc left our node on. This value
-	// is zero for receives.
-	OutgoingChannelId uint64 `protobuf:"varint,2,opt,name=outgoing_channel_id,json=outgoingChannelId,proto3" json:"outgoing_channel_id,omitempty"`
-	// Incoming id is the index of the incoming htlc in the incoming channel.
-	// This value is zero for sends.
-	IncomingHtlcId uint64 `protobuf:"varint,3,opt,name=incoming_htlc_id,json=incomingHtlcId,proto3" json:"incoming_htlc_id,omitempty"`
-	// Outgoing id is the index of the outgoing htlc in the outgoing channel.
-	// This value is zero for receives.
-	OutgoingHtlcId uint64 `protobuf:"varint,4,opt,name=outgoing_htlc_id,json=outgoingHtlcId,proto3" json:"outgoing_htlc_id,omitempty"`
-	// The time in unix nanoseconds that the event occurred.
-	TimestampNs uint64 `protobuf:"varint,5,opt,name=timestamp_ns,json=timestampNs,proto3" json:"timestamp_ns,omitempty"`
-	// The event type indicates whether the htlc was part of a send, receive or
-	// forward.
-	EventType HtlcEvent_EventType `protobuf:"varint,6,opt,name=event_type,json=eventType,proto3,enum=routerrpc.HtlcEvent_EventType" json:"event_type,omitempty"`
-	// Types that are assignable to Event:
-	//
-	//	*HtlcEvent_ForwardEvent
-	//	*HtlcEvent_ForwardFailEvent
-	//	*HtlcEvent_SettleEvent
-	//	*HtlcEvent_LinkFailEvent
-	//	*HtlcEvent_SubscribedEvent
-	//	*HtlcEvent_FinalHtlcEvent
-	Event isHtlcEvent_Event `protobuf_oneof:"event"`
 }
 
-func (x *HtlcEvent) Reset() {
-	*x = HtlcEvent{}
+func (x *SetMissionControlConfigResponse) Reset() {
+	*x = SetMissionControlConfigResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2361,13 +2430,13 @@ func (x *HtlcEvent) Reset() {
 	}
 }
 
-func (x *HtlcEvent) String() string {
+func (x *SetMissionControlConfigResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HtlcEvent) ProtoMessage() {}
+func (*SetMissionControlConfigResponse) ProtoMessage() {}
 
-func (x *HtlcEvent) ProtoReflect() protoreflect.Message {
+func (x *SetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2379,174 +2448,295 @@ func (x *HtlcEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HtlcEvent.ProtoReflect.Descriptor instead.
-func (*HtlcEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
+func (*SetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *HtlcEvent) GetIncomingChannelId() uint64 {
-	if x != nil {
-		return x.IncomingChannelId
+type MissionControlConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Deprecated, use AprioriParameters. The amount of time mission control will
+	// take to restore a penalized node or channel back to 50% success probability,
+	// expressed in seconds. Setting this value to a higher value will penalize
+	// failures for longer, making mission control less likely to route through
+	// nodes and channels that we have previously recorded failures for.
+	//
+	// Deprecated: Marked as deprecated in routerrpc/router.proto.
+	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
+	// Deprecated, use AprioriParameters. The probability of success mission
+	// control should assign to hop in a route where it has no other information
+	// available. Higher values will make mission control more willing to try hops
+	// that we have no information about, lower values will discourage trying these
+	// hops.
+	//
+	// Deprecated: Marked as deprecated in routerrpc/router.proto.
+	HopProbability float32 `protobuf:"fixed32,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
+	// Deprecated, use AprioriParameters. The importance that mission control
+	// should place on historical results, expressed as a value in [0;1]. Setting
+	// this value to 1 will ignore all historical payments and just use the hop
+	// probability to assess the probability of success for each hop. A zero value
+	// ignores hop probability completely and relies entirely on historical
+	// results, unless none are available.
+	//
+	// Deprecated: Marked as deprecated in routerrpc/router.proto.
+	Weight float32 `protobuf:"fixed32,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	// The maximum number of payment results that mission control will store.
+	MaximumPaymentResults uint32 `protobuf:"varint,4,opt,name=maximum_payment_results,json=maximumPaymentResults,proto3" json:"maximum_payment_results,omitempty"`
+	// The minimum time that must have passed since the previously recorded failure
+	// before we raise the failure amount.
+	MinimumFailureRelaxInterval uint64 `protobuf:"varint,5,opt,name=minimum_failure_relax_interval,json=minimumFailureRelaxInterval,proto3" json:"minimum_failure_relax_interval,omitempty"`
+	// ProbabilityModel defines which probability estimator should be used in
+	// pathfinding. Note that the bimodal estimator is experimental.
+	Model MissionControlConfig_ProbabilityModel `protobuf:"varint,6,opt,name=model,proto3,enum=routerrpc.MissionControlConfig_ProbabilityModel" json:"model,omitempty"`
+	// EstimatorConfig is populated dependent on the estimator type.
+	//
+	// Types that are assignable to EstimatorConfig:
+	//
+	//	*MissionControlConfig_Apriori
+	//	*MissionControlConfig_Bimodal
+	EstimatorConfig isMissionControlConfig_EstimatorConfig `protobuf_oneof:"EstimatorConfig"`
+}
+
+func (x *MissionControlConfig) Reset() {
+	*x = MissionControlConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *HtlcEvent) GetOutgoingChannelId() uint64 {
-	if x != nil {
-		return x.OutgoingChannelId
+func (x *MissionControlConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MissionControlConfig) ProtoMessage() {}
+
+func (x *MissionControlConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *HtlcEvent) GetIncomingHtlcId() uint64 {
+// Deprecated: Use MissionControlConfig.ProtoReflect.Descriptor instead.
+func (*MissionControlConfig) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{28}
+}
+
+// Deprecated: Marked as deprecated in routerrpc/router.proto.
+func (x *MissionControlConfig) GetHalfLifeSeconds() uint64 {
 	if x != nil {
-		return x.IncomingHtlcId
+		return x.HalfLifeSeconds
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetOutgoingHtlcId() uint64 {
+// Deprecated: Marked as deprecated in routerrpc/router.proto.
+func (x *MissionControlConfig) GetHopProbability() float32 {
 	if x != nil {
-		return x.OutgoingHtlcId
+		return x.HopProbability
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetTimestampNs() uint64 {
+// Deprecated: Marked as deprecated in routerrpc/router.proto.
+func (x *MissionControlConfig) GetWeight() float32 {
 	if x != nil {
-		return x.TimestampNs
+		return x.Weight
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetEventType() HtlcEvent_EventType {
+func (x *MissionControlConfig) GetMaximumPaymentResults() uint32 {
 	if x != nil {
-		return x.EventType
+		return x.MaximumPaymentResults
 	}
-	return HtlcEvent_UNKNOWN
+	return 0
 }
 
-func (m *HtlcEvent) GetEvent() isHtlcEvent_Event {
-	if m != nil {
-		return m.Event
+func (x *MissionControlConfig) GetMinimumFailureRelaxInterval() uint64 {
+	if x != nil {
+		return x.MinimumFailureRelaxInterval
 	}
-	return nil
+	return 0
 }
 
-func (x *HtlcEvent) GetForwardEvent() *ForwardEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_ForwardEvent); ok {
-		return x.ForwardEvent
+func (x *MissionControlConfig) GetModel() MissionControlConfig_ProbabilityModel {
+	if x != nil {
+		return x.Model
 	}
-	return nil
+	return MissionControlConfig_APRIORI
 }
 
-func (x *HtlcEvent) GetForwardFailEvent() *ForwardFailEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_ForwardFailEvent); ok {
-		return x.ForwardFailEvent
+func (m *MissionControlConfig) GetEstimatorConfig() isMissionControlConfig_EstimatorConfig {
+	if m != nil {
+		return m.EstimatorConfig
 	}
 	return nil
 }
 
-func (x *HtlcEvent) GetSettleEvent() *SettleEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_SettleEvent); ok {
-		return x.SettleEvent
+func (x *MissionControlConfig) GetApriori() *AprioriParameters {
+	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Apriori); ok {
+		return x.Apriori
 	}
 	return nil
 }
 
-func (x *HtlcEvent) GetLinkFailEvent() *LinkFailEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_LinkFailEvent); ok {
-		return x.LinkFailEvent
+func (x *MissionControlConfig) GetBimodal() *BimodalParameters {
+	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Bimodal); ok {
+		return x.Bimodal
 	}
 	return nil
 }
 
-func (x *HtlcEvent) GetSubscribedEvent() *SubscribedEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_SubscribedEvent); ok {
-		return x.SubscribedEvent
-	}
-	return nil
+type isMissionControlConfig_EstimatorConfig interface {
+	isMissionControlConfig_EstimatorConfig()
 }
 
-func (x *HtlcEvent) GetFinalHtlcEvent() *FinalHtlcEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_FinalHtlcEvent); ok {
-		return x.FinalHtlcEvent
-	}
-	return nil
+type MissionControlConfig_Apriori struct {
+	Apriori *AprioriParameters `protobuf:"bytes,7,opt,name=apriori,proto3,oneof"`
 }
 
-type isHtlcEvent_Event interface {
-	isHtlcEvent_Event()
+type MissionControlConfig_Bimodal struct {
+	Bimodal *BimodalParameters `protobuf:"bytes,8,opt,name=bimodal,proto3,oneof"`
 }
 
-type HtlcEvent_ForwardEvent struct {
-	ForwardEvent *ForwardEvent `protobuf:"bytes,7,opt,name=forward_event,json=forwardEvent,proto3,oneof"`
-}
+func (*MissionControlConfig_Apriori) isMissionControlConfig_EstimatorConfig() {}
 
-type HtlcEvent_ForwardFailEvent struct {
-	ForwardFailEvent *ForwardFailEvent `protobuf:"bytes,8,opt,name=forward_fail_event,json=forwardFailEvent,proto3,oneof"`
-}
+func (*MissionControlConfig_Bimodal) isMissionControlConfig_EstimatorConfig() {}
 
-type HtlcEvent_SettleEvent struct {
-	SettleEvent *SettleEvent `protobuf:"bytes,9,opt,name=settle_event,json=settleEvent,proto3,oneof"`
-}
+type BimodalParameters struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-type HtlcEvent_LinkFailEvent struct {
-	LinkFailEvent *LinkFailEvent `protobuf:"bytes,10,opt,name=link_fail_event,json=linkFailEvent,proto3,oneof"`
-}
+	// NodeWeight defines how strongly other previous forwardings on channels of a
+	// router should be taken into account when computing a channel's probability
+	// to route. The allowed values are in the range [0, 1], where a value of 0
+	// means that only direct information about a channel is taken into account.
+	NodeWeight float64 `protobuf:"fixed64,1,opt,name=node_weight,json=nodeWeight,proto3" json:"node_weight,omitempty"`
+	// ScaleMsat describes the scale over which channels statistically have some
+	// liquidity left. The value determines how quickly the bimodal distribution
+	// drops off from the edges of a channel. A larger value (compared to typical
+	// channel capacities) means that the drop off is slow and that channel
+	// balances are distributed more uniformly. A small value leads to the
+	// assumption of very unbalanced channels.
+	ScaleMsat uint64 `protobuf:"varint,2,opt,name=scale_msat,json=scaleMsat,proto3" json:"scale_msat,omitempty"`
+	// DecayTime describes the information decay of knowledge about previous
+	// successes and failures in channels. The smaller the decay time, the quicker
+	// we forget about past forwardings.
+	DecayTime uint64 `protobuf:"varint,3,opt,name=decay_time,json=decayTime,proto3" json:"decay_time,omitempty"`
+}
 
-type HtlcEvent_SubscribedEvent struct {
-	SubscribedEvent *SubscribedEvent `protobuf:"bytes,11,opt,name=subscribed_event,json=subscribedEvent,proto3,oneof"`
+func (x *BimodalParameters) Reset() {
+	*x = BimodalParameters{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-type HtlcEvent_FinalHtlcEvent struct {
-	FinalHtlcEvent *FinalHtlcEvent `protobuf:"bytes,12,opt,name=final_htlc_event,json=finalHtlcEvent,proto3,oneof"`
+func (x *BimodalParameters) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HtlcEvent_ForwardEvent) isHtlcEvent_Event() {}
+func (*BimodalParameters) ProtoMessage() {}
 
-func (*HtlcEvent_ForwardFailEvent) isHtlcEvent_Event() {}
+func (x *BimodalParameters) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-func (*HtlcEvent_SettleEvent) isHtlcEvent_Event() {}
+// Deprecated: Use BimodalParameters.ProtoReflect.Descriptor instead.
+func (*BimodalParameters) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{29}
+}
 
-func (*HtlcEvent_LinkFailEvent) isHtlcEvent_Event() {}
+func (x *BimodalParameters) GetNodeWeight() float64 {
+	if x != nil {
+		return x.NodeWeight
+	}
+	return 0
+}
 
-func (*HtlcEvent_SubscribedEvent) isHtlcEvent_Event() {}
+func (x *BimodalParameters) GetScaleMsat() uint64 {
+	if x != nil {
+		return x.ScaleMsat
+	}
+	return 0
+}
 
-func (*HtlcEvent_FinalHtlcEvent) isHtlcEvent_Event() {}
+func (x *BimodalParameters) GetDecayTime() uint64 {
+	if x != nil {
+		return x.DecayTime
+	}
+	return 0
+}
 
-type HtlcInfo struct {
+type AprioriParameters struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The timelock on the incoming htlc.
-	IncomingTimelock uint32 `protobuf:"varint,1,opt,name=incoming_timelock,json=incomingTimelock,proto3" json:"incoming_timelock,omitempty"`
-	// The timelock on the outgoing htlc.
-	OutgoingTimelock uint32 `protobuf:"varint,2,opt,name=outgoing_timelock,json=outgoingTimelock,proto3" json:"outgoing_timelock,omitempty"`
-	// The amount of the incoming htlc.
-	IncomingAmtMsat uint64 `protobuf:"varint,3,opt,name=incoming_amt_msat,json=incomingAmtMsat,proto3" json:"incoming_amt_msat,omitempty"`
-	// The amount of the outgoing htlc.
-	OutgoingAmtMsat uint64 `protobuf:"varint,4,opt,name=outgoing_amt_msat,json=outgoingAmtMsat,proto3" json:"outgoing_amt_msat,omitempty"`
+	// The amount of time mission control will take to restore a penalized node
+	// or channel back to 50% success probability, expressed in seconds. Setting
+	// this value to a higher value will penalize failures for longer, making
+	// mission control less likely to route through nodes and channels that we
+	// have previously recorded failures for.
+	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
+	// The probability of success mission control should assign to hop in a route
+	// where it has no other information available. Higher values will make mission
+	// control more willing to try hops that we have no information about, lower
+	// values will discourage trying these hops.
+	HopProbability float64 `protobuf:"fixed64,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
+	// The importance that mission control should place on historical results,
+	// expressed as a value in [0;1]. Setting this value to 1 will ignore all
+	// historical payments and just use the hop probability to assess the
+	// probability of success for each hop. A zero value ignores hop probability
+	// completely and relies entirely on historical results, unless none are
+	// available.
+	Weight float64 `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	// The fraction of a channel's capacity that we consider to have liquidity. For
+	// amounts that come close to or exceed the fraction, an additional penalty is
+	// applied. A value of 1.0 disables the capacity factor. Allowed values are in
+	// [0.75, 1.0].
+	CapacityFraction float64 `protobuf:"fixed64,4,opt,name=capacity_fraction,json=capacityFraction,proto3" json:"capacity_fraction,omitempty"`
 }
 
-func (x *HtlcInfo) Reset() {
-	*x = HtlcInfo{}
+func (x *AprioriParameters) Reset() {
+	*x = AprioriParameters{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[28]
+		mi := &file_routerrpc_router_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *HtlcInfo) String() string {
+func (x *AprioriParameters) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HtlcInfo) ProtoMessage() {}
+func (*AprioriParameters) ProtoMessage() {}
 
-func (x *HtlcInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[28]
+func (x *AprioriParameters) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2557,65 +2747,69 @@ func (x *HtlcInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HtlcInfo.ProtoReflect.Descriptor instead.
-func (*HtlcInfo) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use AprioriParameters.ProtoReflect.Descriptor instead.
+func (*AprioriParameters) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *HtlcInfo) GetIncomingTimelock() uint32 {
+func (x *AprioriParameters) GetHalfLifeSeconds() uint64 {
 	if x != nil {
-		return x.IncomingTimelock
+		return x.HalfLifeSeconds
 	}
 	return 0
 }
 
-func (x *HtlcInfo) GetOutgoingTimelock() uint32 {
+func (x *AprioriParameters) GetHopProbability() float64 {
 	if x != nil {
-		return x.OutgoingTimelock
+		return x.HopProbability
 	}
 	return 0
 }
 
-func (x *HtlcInfo) GetIncomingAmtMsat() uint64 {
+func (x *AprioriParameters) GetWeight() float64 {
 	if x != nil {
-		return x.IncomingAmtMsat
+		return x.Weight
 	}
 	return 0
 }
 
-func (x *HtlcInfo) GetOutgoingAmtMsat() uint64 {
+func (x *AprioriParameters) GetCapacityFraction() float64 {
 	if x != nil {
-		return x.OutgoingAmtMsat
+		return x.CapacityFraction
 	}
 	return 0
 }
 
-type ForwardEvent struct {
+type QueryProbabilityRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Info contains details about the htlc that was forwarded.
-	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	// The source node pubkey of the pair.
+	FromNode []byte `protobuf:"bytes,1,opt,name=from_node,json=fromNode,proto3" json:"from_node,omitempty"`
+	// The destination node pubkey of the pair.
+	ToNode []byte `protobuf:"bytes,2,opt,name=to_node,json=toNode,proto3" json:"to_node,omitempty"`
+	// The amount for which to calculate a probability.
+	AmtMsat int64 `protobuf:"varint,3,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
 }
 
-func (x *ForwardEvent) Reset() {
-	*x = ForwardEvent{}
+func (x *QueryProbabilityRequest) Reset() {
+	*x = QueryProbabilityRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[29]
+		mi := &file_routerrpc_router_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ForwardEvent) String() string {
+func (x *QueryProbabilityRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardEvent) ProtoMessage() {}
+func (*QueryProbabilityRequest) ProtoMessage() {}
 
-func (x *ForwardEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[29]
+func (x *QueryProbabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2626,41 +2820,60 @@ func (x *ForwardEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardEvent.ProtoReflect.Descriptor instead.
-func (*ForwardEvent) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use QueryProbabilityRequest.ProtoReflect.Descriptor instead.
+func (*QueryProbabilityRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *ForwardEvent) GetInfo() *HtlcInfo {
+func (x *QueryProbabilityRequest) GetFromNode() []byte {
 	if x != nil {
-		return x.Info
+		return x.FromNode
 	}
 	return nil
 }
 
-type ForwardFailEvent struct {
+func (x *QueryProbabilityRequest) GetToNode() []byte {
+	if x != nil {
+		return x.ToNode
+	}
+	return nil
+}
+
+func (x *QueryProbabilityRequest) GetAmtMsat() int64 {
+	if x != nil {
+		return x.AmtMsat
+	}
+	return 0
+}
+
+type QueryProbabilityResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The success probability for the requested pair.
+	Probability float64 `protobuf:"fixed64,1,opt,name=probability,proto3" json:"probability,omitempty"`
+	// The historical data for the requested pair.
+	History *PairData `protobuf:"bytes,2,opt,name=history,proto3" json:"history,omitempty"`
 }
 
-func (x *ForwardFailEvent) Reset() {
-	*x = ForwardFailEvent{}
+func (x *QueryProbabilityResponse) Reset() {
+	*x = QueryProbabilityResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[30]
+		mi := &file_routerrpc_router_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ForwardFailEvent) String() string {
+func (x *QueryProbabilityResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardFailEvent) ProtoMessage() {}
+func (*QueryProbabilityResponse) ProtoMessage() {}
 
-func (x *ForwardFailEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[30]
+func (x *QueryProbabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2671,37 +2884,54 @@ func (x *ForwardFailEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardFailEvent.ProtoReflect.Descriptor instead.
-func (*ForwardFailEvent) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use QueryProbabilityResponse.ProtoReflect.Descriptor instead.
+func (*QueryProbabilityResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{32}
 }
 
-type SettleEvent struct {
+func (x *QueryProbabilityResponse) GetProbability() float64 {
+	if x != nil {
+		return x.Probability
+	}
+	return 0
+}
+
+func (x *QueryProbabilityResponse) GetHistory() *PairData {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type CompareRouteProbabilitiesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The revealed preimage.
-	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// The route to estimate the success probability of.
+	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	// The estimator configs to estimate the probability with. Only the model and
+	// the estimator config of each entry are used.
+	Configs []*MissionControlConfig `protobuf:"bytes,2,rep,name=configs,proto3" json:"configs,omitempty"`
 }
 
-func (x *SettleEvent) Reset() {
-	*x = SettleEvent{}
+func (x *CompareRouteProbabilitiesRequest) Reset() {
+	*x = CompareRouteProbabilitiesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[31]
+		mi := &file_routerrpc_router_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SettleEvent) String() string {
+func (x *CompareRouteProbabilitiesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SettleEvent) ProtoMessage() {}
+func (*CompareRouteProbabilitiesRequest) ProtoMessage() {}
 
-func (x *SettleEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[31]
+func (x *CompareRouteProbabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2712,44 +2942,52 @@ func (x *SettleEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SettleEvent.ProtoReflect.Descriptor instead.
-func (*SettleEvent) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use CompareRouteProbabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*CompareRouteProbabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *SettleEvent) GetPreimage() []byte {
+func (x *CompareRouteProbabilitiesRequest) GetRoute() *lnrpc.Route {
 	if x != nil {
-		return x.Preimage
+		return x.Route
 	}
 	return nil
 }
 
-type FinalHtlcEvent struct {
+func (x *CompareRouteProbabilitiesRequest) GetConfigs() []*MissionControlConfig {
+	if x != nil {
+		return x.Configs
+	}
+	return nil
+}
+
+type CompareRouteProbabilitiesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Settled  bool `protobuf:"varint,1,opt,name=settled,proto3" json:"settled,omitempty"`
-	Offchain bool `protobuf:"varint,2,opt,name=offchain,proto3" json:"offchain,omitempty"`
+	// The success probabilities of the route, in the same order as the requested
+	// estimator configs.
+	Probabilities []float64 `protobuf:"fixed64,1,rep,packed,name=probabilities,proto3" json:"probabilities,omitempty"`
 }
 
-func (x *FinalHtlcEvent) Reset() {
-	*x = FinalHtlcEvent{}
+func (x *CompareRouteProbabilitiesResponse) Reset() {
+	*x = CompareRouteProbabilitiesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[32]
+		mi := &file_routerrpc_router_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *FinalHtlcEvent) String() string {
+func (x *CompareRouteProbabilitiesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FinalHtlcEvent) ProtoMessage() {}
+func (*CompareRouteProbabilitiesResponse) ProtoMessage() {}
 
-func (x *FinalHtlcEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[32]
+func (x *CompareRouteProbabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2760,48 +2998,57 @@ func (x *FinalHtlcEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FinalHtlcEvent.ProtoReflect.Descriptor instead.
-func (*FinalHtlcEvent) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{32}
-}
-
-func (x *FinalHtlcEvent) GetSettled() bool {
-	if x != nil {
-		return x.Settled
-	}
-	return false
+// Deprecated: Use CompareRouteProbabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*CompareRouteProbabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *FinalHtlcEvent) GetOffchain() bool {
+func (x *CompareRouteProbabilitiesResponse) GetProbabilities() []float64 {
 	if x != nil {
-		return x.Offchain
+		return x.Probabilities
 	}
-	return false
+	return nil
 }
 
-type SubscribedEvent struct {
+type BuildRouteRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The amount to send expressed in msat. If set to zero, the minimum routable
+	// amount is used.
+	AmtMsat int64 `protobuf:"varint,1,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
+	// CLTV delta from the current height that should be used for the timelock
+	// of the final hop
+	FinalCltvDelta int32 `protobuf:"varint,2,opt,name=final_cltv_delta,json=finalCltvDelta,proto3" json:"final_cltv_delta,omitempty"`
+	// The channel id of the channel that must be taken to the first hop. If zero,
+	// any channel may be used.
+	OutgoingChanId uint64 `protobuf:"varint,3,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
+	// A list of hops that defines the route. This does not include the source hop
+	// pubkey.
+	HopPubkeys [][]byte `protobuf:"bytes,4,rep,name=hop_pubkeys,json=hopPubkeys,proto3" json:"hop_pubkeys,omitempty"`
+	// An optional payment addr to be included within the last hop of the route.
+	// This is also called payment secret in specifications (e.g. BOLT 11).
+	PaymentAddr []byte `protobuf:"bytes,5,opt,name=payment_addr,json=paymentAddr,proto3" json:"payment_addr,omitempty"`
 }
 
-func (x *SubscribedEvent) Reset() {
-	*x = SubscribedEvent{}
+func (x *BuildRouteRequest) Reset() {
+	*x = BuildRouteRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[33]
+		mi := &file_routerrpc_router_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SubscribedEvent) String() string {
+func (x *BuildRouteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribedEvent) ProtoMessage() {}
+func (*BuildRouteRequest) ProtoMessage() {}
 
-func (x *SubscribedEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[33]
+func (x *BuildRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2812,118 +3059,72 @@ func (x *SubscribedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribedEvent.ProtoReflect.Descriptor instead.
-func (*SubscribedEvent) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{33}
-}
-
-type LinkFailEvent struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// Info contains details about the htlc that we failed.
-	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
-	// FailureCode is the BOLT error code for the failure.
-	WireFailure lnrpc.Failure_FailureCode `protobuf:"varint,2,opt,name=wire_failure,json=wireFailure,proto3,enum=lnrpc.Failure_FailureCode" json:"wire_failure,omitempty"`
-	// FailureDetail provides additional information about the reason for the
-	// failure. This detail enriches the information provided by the wire message
-	// and may be 'no detail' if the wire message requires no additional metadata.
-	FailureDetail FailureDetail `protobuf:"varint,3,opt,name=failure_detail,json=failureDetail,proto3,enum=routerrpc.FailureDetail" json:"failure_detail,omitempty"`
-	// A string representation of the link failure.
-	FailureString string `protobuf:"bytes,4,opt,name=failure_string,json=failureString,proto3" json:"failure_string,omitempty"`
-}
-
-func (x *LinkFailEvent) Reset() {
-	*x = LinkFailEvent{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[34]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *LinkFailEvent) String() string {
-	return protoimpl.X.MessageStringOf(x)
+// Deprecated: Use BuildRouteRequest.ProtoReflect.Descriptor instead.
+func (*BuildRouteRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{35}
 }
 
-func (*LinkFailEvent) ProtoMessage() {}
-
-func (x *LinkFailEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[34]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *BuildRouteRequest) GetAmtMsat() int64 {
+	if x != nil {
+		return x.AmtMsat
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use LinkFailEvent.ProtoReflect.Descriptor instead.
-func (*LinkFailEvent) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{34}
+	return 0
 }
 
-func (x *LinkFailEvent) GetInfo() *HtlcInfo {
+func (x *BuildRouteRequest) GetFinalCltvDelta() int32 {
 	if x != nil {
-		return x.Info
+		return x.FinalCltvDelta
 	}
-	return nil
+	return 0
 }
 
-func (x *LinkFailEvent) GetWireFailure() lnrpc.Failure_FailureCode {
+func (x *BuildRouteRequest) GetOutgoingChanId() uint64 {
 	if x != nil {
-		return x.WireFailure
+		return x.OutgoingChanId
 	}
-	return lnrpc.Failure_FailureCode(0)
+	return 0
 }
 
-func (x *LinkFailEvent) GetFailureDetail() FailureDetail {
+func (x *BuildRouteRequest) GetHopPubkeys() [][]byte {
 	if x != nil {
-		return x.FailureDetail
+		return x.HopPubkeys
 	}
-	return FailureDetail_UNKNOWN
+	return nil
 }
 
-func (x *LinkFailEvent) GetFailureString() string {
+func (x *BuildRouteRequest) GetPaymentAddr() []byte {
 	if x != nil {
-		return x.FailureString
+		return x.PaymentAddr
 	}
-	return ""
+	return nil
 }
 
-type PaymentStatus struct {
+type BuildRouteResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Current state the payment is in.
-	State PaymentState `protobuf:"varint,1,opt,name=state,proto3,enum=routerrpc.PaymentState" json:"state,omitempty"`
-	// The pre-image of the payment when state is SUCCEEDED.
-	Preimage []byte `protobuf:"bytes,2,opt,name=preimage,proto3" json:"preimage,omitempty"`
-	// The HTLCs made in attempt to settle the payment [EXPERIMENTAL].
-	Htlcs []*lnrpc.HTLCAttempt `protobuf:"bytes,4,rep,name=htlcs,proto3" json:"htlcs,omitempty"`
+	// Fully specified route that can be used to execute the payment.
+	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
 }
 
-func (x *PaymentStatus) Reset() {
-	*x = PaymentStatus{}
+func (x *BuildRouteResponse) Reset() {
+	*x = BuildRouteResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[35]
+		mi := &file_routerrpc_router_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PaymentStatus) String() string {
+func (x *BuildRouteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PaymentStatus) ProtoMessage() {}
+func (*BuildRouteResponse) ProtoMessage() {}
 
-func (x *PaymentStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[35]
+func (x *BuildRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2934,60 +3135,41 @@ func (x *PaymentStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PaymentStatus.ProtoReflect.Descriptor instead.
-func (*PaymentStatus) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{35}
-}
-
-func (x *PaymentStatus) GetState() PaymentState {
-	if x != nil {
-		return x.State
-	}
-	return PaymentState_IN_FLIGHT
-}
-
-func (x *PaymentStatus) GetPreimage() []byte {
-	if x != nil {
-		return x.Preimage
-	}
-	return nil
+// Deprecated: Use BuildRouteResponse.ProtoReflect.Descriptor instead.
+func (*BuildRouteResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *PaymentStatus) GetHtlcs() []*lnrpc.HTLCAttempt {
+func (x *BuildRouteResponse) GetRoute() *lnrpc.Route {
 	if x != nil {
-		return x.Htlcs
+		return x.Route
 	}
 	return nil
 }
 
-type CircuitKey struct {
+type SubscribeHtlcEventsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// / The id of the channel that the is part of this circuit.
-	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
-	// / The index of the incoming htlc in the incoming channel.
-	HtlcId uint64 `protobuf:"varint,2,opt,name=htlc_id,json=htlcId,proto3" json:"htlc_id,omitempty"`
 }
 
-func (x *CircuitKey) Reset() {
-	*x = CircuitKey{}
+func (x *SubscribeHtlcEventsRequest) Reset() {
+	*x = SubscribeHtlcEventsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[36]
+		mi := &file_routerrpc_router_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CircuitKey) String() string {
+func (x *SubscribeHtlcEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CircuitKey) ProtoMessage() {}
+func (*SubscribeHtlcEventsRequest) ProtoMessage() {}
 
-func (x *CircuitKey) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[36]
+func (x *SubscribeHtlcEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2998,75 +3180,67 @@ func (x *CircuitKey) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CircuitKey.ProtoReflect.Descriptor instead.
-func (*CircuitKey) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{36}
-}
-
-func (x *CircuitKey) GetChanId() uint64 {
-	if x != nil {
-		return x.ChanId
-	}
-	return 0
-}
-
-func (x *CircuitKey) GetHtlcId() uint64 {
-	if x != nil {
-		return x.HtlcId
-	}
-	return 0
+// Deprecated: Use SubscribeHtlcEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeHtlcEventsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{37}
 }
 
-type ForwardHtlcInterceptRequest struct {
+// HtlcEvent contains the htlc event that was processed. These are served on a
+// best-effort basis; events are not persisted, delivery is not guaranteed
+// (in the event of a crash in the switch, forward events may be lost) and
+// some events may be replayed upon restart. Events consumed from this package
+// should be de-duplicated by the htlc's unique combination of incoming and
+// outgoing channel id and htlc id. [EXPERIMENTAL]
+type HtlcEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The key of this forwarded htlc. It defines the incoming channel id and
-	// the index in this channel.
-	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
-	// The incoming htlc amount.
-	IncomingAmountMsat uint64 `protobuf:"varint,5,opt,name=incoming_amount_msat,json=incomingAmountMsat,proto3" json:"incoming_amount_msat,omitempty"`
-	// The incoming htlc expiry.
-	IncomingExpiry uint32 `protobuf:"varint,6,opt,name=incoming_expiry,json=incomingExpiry,proto3" json:"incoming_expiry,omitempty"`
-	// The htlc payment hash. This value is not guaranteed to be unique per
-	// request.
-	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
-	// The requested outgoing channel id for this forwarded htlc. Because of
-	// non-strict forwarding, this isn't necessarily the channel over which the
-	// packet will be forwarded eventually. A different channel to the same peer
-	// may be selected as well.
-	OutgoingRequestedChanId uint64 `protobuf:"varint,7,opt,name=outgoing_requested_chan_id,json=outgoingRequestedChanId,proto3" json:"outgoing_requested_chan_id,omitempty"`
-	// The outgoing htlc amount.
-	OutgoingAmountMsat uint64 `protobuf:"varint,3,opt,name=outgoing_amount_msat,json=outgoingAmountMsat,proto3" json:"outgoing_amount_msat,omitempty"`
-	// The outgoing htlc expiry.
-	OutgoingExpiry uint32 `protobuf:"varint,4,opt,name=outgoing_expiry,json=outgoingExpiry,proto3" json:"outgoing_expiry,omitempty"`
-	// Any custom records that were present in the payload.
-	CustomRecords map[uint64][]byte `protobuf:"bytes,8,rep,name=custom_records,json=customRecords,proto3" json:"custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// The onion blob for the next hop
-	OnionBlob []byte `protobuf:"bytes,9,opt,name=onion_blob,json=onionBlob,proto3" json:"onion_blob,omitempty"`
-	// The block height at which this htlc will be auto-failed to prevent the
-	// channel from force-closing.
-	AutoFailHeight int32 `protobuf:"varint,10,opt,name=auto_fail_height,json=autoFailHeight,proto3" json:"auto_fail_height,omitempty"`
+	// The short channel id that the incoming htlc arrived at our node on. This
+	// value is zero for sends.
+	IncomingChannelId uint64 `protobuf:"varint,1,opt,name=incoming_channel_id,json=incomingChannelId,proto3" json:"incoming_channel_id,omitempty"`
+	// The short channel id that the outgoing htlc left our node on. This value
+	// is zero for receives.
+	OutgoingChannelId uint64 `protobuf:"varint,2,opt,name=outgoing_channel_id,json=outgoingChannelId,proto3" json:"outgoing_channel_id,omitempty"`
+	// Incoming id is the index of the incoming htlc in the incoming channel.
+	// This value is zero for sends.
+	IncomingHtlcId uint64 `protobuf:"varint,3,opt,name=incoming_htlc_id,json=incomingHtlcId,proto3" json:"incoming_htlc_id,omitempty"`
+	// Outgoing id is the index of the outgoing htlc in the outgoing channel.
+	// This value is zero for receives.
+	OutgoingHtlcId uint64 `protobuf:"varint,4,opt,name=outgoing_htlc_id,json=outgoingHtlcId,proto3" json:"outgoing_htlc_id,omitempty"`
+	// The time in unix nanoseconds that the event occurred.
+	TimestampNs uint64 `protobuf:"varint,5,opt,name=timestamp_ns,json=timestampNs,proto3" json:"timestamp_ns,omitempty"`
+	// The event type indicates whether the htlc was part of a send, receive or
+	// forward.
+	EventType HtlcEvent_EventType `protobuf:"varint,6,opt,name=event_type,json=eventType,proto3,enum=routerrpc.HtlcEvent_EventType" json:"event_type,omitempty"`
+	// Types that are assignable to Event:
+	//
+	//	*HtlcEvent_ForwardEvent
+	//	*HtlcEvent_ForwardFailEvent
+	//	*HtlcEvent_SettleEvent
+	//	*HtlcEvent_LinkFailEvent
+	//	*HtlcEvent_SubscribedEvent
+	//	*HtlcEvent_FinalHtlcEvent
+	Event isHtlcEvent_Event `protobuf_oneof:"event"`
 }
 
-func (x *ForwardHtlcInterceptRequest) Reset() {
-	*x = ForwardHtlcInterceptRequest{}
+func (x *HtlcEvent) Reset() {
+	*x = HtlcEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[37]
+		mi := &file_routerrpc_router_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ForwardHtlcInterceptRequest) String() string {
+func (x *HtlcEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardHtlcInterceptRequest) ProtoMessage() {}
+func (*HtlcEvent) ProtoMessage() {}
 
-func (x *ForwardHtlcInterceptRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[37]
+func (x *HtlcEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3077,193 +3251,159 @@ func (x *ForwardHtlcInterceptRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardHtlcInterceptRequest.ProtoReflect.Descriptor instead.
-func (*ForwardHtlcInterceptRequest) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use HtlcEvent.ProtoReflect.Descriptor instead.
+func (*HtlcEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *ForwardHtlcInterceptRequest) GetIncomingCircuitKey() *CircuitKey {
+func (x *HtlcEvent) GetIncomingChannelId() uint64 {
 	if x != nil {
-		return x.IncomingCircuitKey
+		return x.IncomingChannelId
 	}
-	return nil
+	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetIncomingAmountMsat() uint64 {
+func (x *HtlcEvent) GetOutgoingChannelId() uint64 {
 	if x != nil {
-		return x.IncomingAmountMsat
+		return x.OutgoingChannelId
 	}
 	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetIncomingExpiry() uint32 {
+func (x *HtlcEvent) GetIncomingHtlcId() uint64 {
 	if x != nil {
-		return x.IncomingExpiry
+		return x.IncomingHtlcId
 	}
 	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetPaymentHash() []byte {
+func (x *HtlcEvent) GetOutgoingHtlcId() uint64 {
 	if x != nil {
-		return x.PaymentHash
+		return x.OutgoingHtlcId
 	}
-	return nil
+	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOutgoingRequestedChanId() uint64 {
+func (x *HtlcEvent) GetTimestampNs() uint64 {
 	if x != nil {
-		return x.OutgoingRequestedChanId
+		return x.TimestampNs
 	}
 	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOutgoingAmountMsat() uint64 {
+func (x *HtlcEvent) GetEventType() HtlcEvent_EventType {
 	if x != nil {
-		return x.OutgoingAmountMsat
+		return x.EventType
 	}
-	return 0
+	return HtlcEvent_UNKNOWN
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOutgoingExpiry() uint32 {
-	if x != nil {
-		return x.OutgoingExpiry
+func (m *HtlcEvent) GetEvent() isHtlcEvent_Event {
+	if m != nil {
+		return m.Event
 	}
-	return 0
+	return nil
 }
 
-func (x *ForwardHtlcInterceptRequest) GetCustomRecords() map[uint64][]byte {
-	if x != nil {
-		return x.CustomRecords
+func (x *HtlcEvent) GetForwardEvent() *ForwardEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_ForwardEvent); ok {
+		return x.ForwardEvent
 	}
 	return nil
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOnionBlob() []byte {
-	if x != nil {
-		return x.OnionBlob
+func (x *HtlcEvent) GetForwardFailEvent() *ForwardFailEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_ForwardFailEvent); ok {
+		return x.ForwardFailEvent
 	}
 	return nil
 }
 
-func (x *ForwardHtlcInterceptRequest) GetAutoFailHeight() int32 {
-	if x != nil {
-		return x.AutoFailHeight
+func (x *HtlcEvent) GetSettleEvent() *SettleEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_SettleEvent); ok {
+		return x.SettleEvent
 	}
-	return 0
+	return nil
 }
 
-// *
-// ForwardHtlcInterceptResponse enables the caller to resolve a previously hold
-// forward. The caller can choose either to:
-// - `Resume`: Execute the default behavior (usually forward).
-// - `Reject`: Fail the htlc backwards.
-// - `Settle`: Settle this htlc with a given preimage.
-type ForwardHtlcInterceptResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// *
-	// The key of this forwarded htlc. It defines the incoming channel id and
-	// the index in this channel.
-	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
-	// The resolve action for this intercepted htlc.
-	Action ResolveHoldForwardAction `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ResolveHoldForwardAction" json:"action,omitempty"`
-	// The preimage in case the resolve action is Settle.
-	Preimage []byte `protobuf:"bytes,3,opt,name=preimage,proto3" json:"preimage,omitempty"`
-	// Encrypted failure message in case the resolve action is Fail.
-	//
-	// If failure_message is specified, the failure_code field must be set
-	// to zero.
-	FailureMessage []byte `protobuf:"bytes,4,opt,name=failure_message,json=failureMessage,proto3" json:"failure_message,omitempty"`
-	// Return the specified failure code in case the resolve action is Fail. The
-	// message data fields are populated automatically.
-	//
-	// If a non-zero failure_code is specified, failure_message must not be set.
-	//
-	// For backwards-compatibility reasons, TEMPORARY_CHANNEL_FAILURE is the
-	// default value for this field.
-	FailureCode lnrpc.Failure_FailureCode `protobuf:"varint,5,opt,name=failure_code,json=failureCode,proto3,enum=lnrpc.Failure_FailureCode" json:"failure_code,omitempty"`
+func (x *HtlcEvent) GetLinkFailEvent() *LinkFailEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_LinkFailEvent); ok {
+		return x.LinkFailEvent
+	}
+	return nil
 }
 
-func (x *ForwardHtlcInterceptResponse) Reset() {
-	*x = ForwardHtlcInterceptResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[38]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *HtlcEvent) GetSubscribedEvent() *SubscribedEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_SubscribedEvent); ok {
+		return x.SubscribedEvent
 	}
+	return nil
 }
 
-func (x *ForwardHtlcInterceptResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *HtlcEvent) GetFinalHtlcEvent() *FinalHtlcEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_FinalHtlcEvent); ok {
+		return x.FinalHtlcEvent
+	}
+	return nil
 }
 
-func (*ForwardHtlcInterceptResponse) ProtoMessage() {}
-
-func (x *ForwardHtlcInterceptResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[38]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+type isHtlcEvent_Event interface {
+	isHtlcEvent_Event()
 }
 
-// Deprecated: Use ForwardHtlcInterceptResponse.ProtoReflect.Descriptor instead.
-func (*ForwardHtlcInterceptResponse) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{38}
+type HtlcEvent_ForwardEvent struct {
+	ForwardEvent *ForwardEvent `protobuf:"bytes,7,opt,name=forward_event,json=forwardEvent,proto3,oneof"`
 }
 
-func (x *ForwardHtlcInterceptResponse) GetIncomingCircuitKey() *CircuitKey {
-	if x != nil {
-		return x.IncomingCircuitKey
-	}
-	return nil
+type HtlcEvent_ForwardFailEvent struct {
+	ForwardFailEvent *ForwardFailEvent `protobuf:"bytes,8,opt,name=forward_fail_event,json=forwardFailEvent,proto3,oneof"`
 }
 
-func (x *ForwardHtlcInterceptResponse) GetAction() ResolveHoldForwardAction {
-	if x != nil {
-		return x.Action
-	}
-	return ResolveHoldForwardAction_SETTLE
+type HtlcEvent_SettleEvent struct {
+	SettleEvent *SettleEvent `protobuf:"bytes,9,opt,name=settle_event,json=settleEvent,proto3,oneof"`
 }
 
-func (x *ForwardHtlcInterceptResponse) GetPreimage() []byte {
-	if x != nil {
-		return x.Preimage
-	}
-	return nil
+type HtlcEvent_LinkFailEvent struct {
+	LinkFailEvent *LinkFailEvent `protobuf:"bytes,10,opt,name=link_fail_event,json=linkFailEvent,proto3,oneof"`
 }
 
-func (x *ForwardHtlcInterceptResponse) GetFailureMessage() []byte {
-	if x != nil {
-		return x.FailureMessage
-	}
-	return nil
+type HtlcEvent_SubscribedEvent struct {
+	SubscribedEvent *SubscribedEvent `protobuf:"bytes,11,opt,name=subscribed_event,json=subscribedEvent,proto3,oneof"`
 }
 
-func (x *ForwardHtlcInterceptResponse) GetFailureCode() lnrpc.Failure_FailureCode {
-	if x != nil {
-		return x.FailureCode
-	}
-	return lnrpc.Failure_FailureCode(0)
+type HtlcEvent_FinalHtlcEvent struct {
+	FinalHtlcEvent *FinalHtlcEvent `protobuf:"bytes,12,opt,name=final_htlc_event,json=finalHtlcEvent,proto3,oneof"`
 }
 
-type UpdateChanStatusRequest struct {
+func (*HtlcEvent_ForwardEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_ForwardFailEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_SettleEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_LinkFailEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_SubscribedEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_FinalHtlcEvent) isHtlcEvent_Event() {}
+
+type HtlcInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
-	Action    ChanStatusAction    `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ChanStatusAction" json:"action,omitempty"`
+	// The timelock on the incoming htlc.
+	IncomingTimelock uint32 `protobuf:"varint,1,opt,name=incoming_timelock,json=incomingTimelock,proto3" json:"incoming_timelock,omitempty"`
+	// The timelock on the outgoing htlc.
+	OutgoingTimelock uint32 `protobuf:"varint,2,opt,name=outgoing_timelock,json=outgoingTimelock,proto3" json:"outgoing_timelock,omitempty"`
+	// The amount of the incoming htlc.
+	IncomingAmtMsat uint64 `protobuf:"varint,3,opt,name=incoming_amt_msat,json=incomingAmtMsat,proto3" json:"incoming_amt_msat,omitempty"`
+	// The amount of the outgoing htlc.
+	OutgoingAmtMsat uint64 `protobuf:"varint,4,opt,name=outgoing_amt_msat,json=outgoingAmtMsat,proto3" json:"outgoing_amt_msat,omitempty"`
 }
 
-func (x *UpdateChanStatusRequest) Reset() {
-	*x = UpdateChanStatusRequest{}
+func (x *HtlcInfo) Reset() {
+	*x = HtlcInfo{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3271,13 +3411,13 @@ func (x *UpdateChanStatusRequest) Reset() {
 	}
 }
 
-func (x *UpdateChanStatusRequest) String() string {
+func (x *HtlcInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateChanStatusRequest) ProtoMessage() {}
+func (*HtlcInfo) ProtoMessage() {}
 
-func (x *UpdateChanStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *HtlcInfo) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3289,33 +3429,50 @@ func (x *UpdateChanStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateChanStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateChanStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use HtlcInfo.ProtoReflect.Descriptor instead.
+func (*HtlcInfo) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *UpdateChanStatusRequest) GetChanPoint() *lnrpc.ChannelPoint {
+func (x *HtlcInfo) GetIncomingTimelock() uint32 {
 	if x != nil {
-		return x.ChanPoint
+		return x.IncomingTimelock
 	}
-	return nil
+	return 0
 }
 
-func (x *UpdateChanStatusRequest) GetAction() ChanStatusAction {
+func (x *HtlcInfo) GetOutgoingTimelock() uint32 {
 	if x != nil {
-		return x.Action
+		return x.OutgoingTimelock
 	}
-	return ChanStatusAction_ENABLE
+	return 0
 }
 
-type UpdateChanStatusResponse struct {
+func (x *HtlcInfo) GetIncomingAmtMsat() uint64 {
+	if x != nil {
+		return x.IncomingAmtMsat
+	}
+	return 0
+}
+
+func (x *HtlcInfo) GetOutgoingAmtMsat() uint64 {
+	if x != nil {
+		return x.OutgoingAmtMsat
+	}
+	return 0
+}
+
+type ForwardEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// Info contains details about the htlc that was forwarded.
+	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
 }
 
-func (x *UpdateChanStatusResponse) Reset() {
-	*x = UpdateChanStatusResponse{}
+func (x *ForwardEvent) Reset() {
+	*x = ForwardEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3323,772 +3480,3407 @@ func (x *UpdateChanStatusResponse) Reset() {
 	}
 }
 
-func (x *UpdateChanStatusResponse) String() string {
+func (x *ForwardEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateChanStatusResponse) ProtoMessage() {}
+func (*ForwardEvent) ProtoMessage() {}
 
-func (x *UpdateChanStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *ForwardEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UpdateChanStatusResponse.ProtoReflect.Descriptor instead.
-func (*UpdateChanStatusResponse) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{40}
-}
-
-var File_routerrpc_router_proto protoreflect.FileDescriptor
-
-var file_routerrpc_router_proto_rawDesc = []byte{
-	0x0a, 0x16, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x1a, 0x0f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x94, 0x08, 0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64,
-	0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12,
-	0x10, 0x0a, 0x03, 0x61, 0x6d, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x61, 0x6d,
-	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73,
-	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x48, 0x61, 0x73, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6c,
-	0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
-	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x27,
-	0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
-	0x12, 0x22, 0x0a, 0x0d, 0x66, 0x65, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x61,
-	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69,
-	0x74, 0x53, 0x61, 0x74, 0x12, 0x2e, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
-	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x42, 0x04,
-	0x18, 0x01, 0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68,
-	0x61, 0x6e, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x6c, 0x69, 0x6d,
-	0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x6c, 0x74, 0x76, 0x4c, 0x69,
-	0x6d, 0x69, 0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x68, 0x69, 0x6e,
-	0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
-	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x64, 0x0a, 0x13, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x63,
-	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x0b, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
-	0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x64, 0x65, 0x73, 0x74, 0x43,
-	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x19, 0x0a, 0x08,
-	0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
-	0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x66, 0x65, 0x65, 0x5f, 0x6c,
-	0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0c, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a,
-	0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79,
-	0x18, 0x0e, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x50,
-	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x73,
-	0x65, 0x6c, 0x66, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x0d, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x65, 0x61, 0x74,
-	0x75, 0x72, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6c, 0x6e, 0x72,
-	0x70, 0x63, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x69, 0x74, 0x52, 0x0c, 0x64,
-	0x65, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
-	0x61, 0x78, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
-	0x6d, 0x61, 0x78, 0x50, 0x61, 0x72, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69,
-	0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18,
-	0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68,
-	0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67,
-	0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x13, 0x20,
-	0x03, 0x28, 0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61,
-	0x6e, 0x49, 0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
-	0x61, 0x64, 0x64, 0x72, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x2d, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f, 0x73,
-	0x68, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x15,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x68, 0x61, 0x72, 0x64, 0x53, 0x69,
-	0x7a, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6d, 0x70, 0x18, 0x16, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
-	0x5f, 0x70, 0x72, 0x65, 0x66, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x69, 0x6d,
-	0x65, 0x50, 0x72, 0x65, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x61,
-	0x62, 0x6c, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x1a, 0x44, 0x0a, 0x16, 0x44, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73,
-	0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x68, 0x0a, 0x13, 0x54,
-	0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61,
-	0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69, 0x6e, 0x66, 0x6c,
-	0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x46, 0x0a, 0x14, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a,
-	0x13, 0x6e, 0x6f, 0x5f, 0x69, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e,
-	0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x81, 0x01,
-	0x0a, 0x0f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x04, 0x64, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x27,
-	0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75,
-	0x74, 0x22, 0xa8, 0x01, 0x0a, 0x10, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e,
-	0x67, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0e, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x4d, 0x73, 0x61, 0x74,
-	0x12, 0x26, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x64, 0x65,
-	0x6c, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x4c,
-	0x6f, 0x63, 0x6b, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x42, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c,
-	0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x1b, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x0d, 0x66,
-	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x7f, 0x0a, 0x12,
-	0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61,
-	0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75,
-	0x74, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x6b, 0x69,
-	0x70, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0b, 0x73, 0x6b, 0x69, 0x70, 0x54, 0x65, 0x6d, 0x70, 0x45, 0x72, 0x72, 0x22, 0x5b, 0x0a,
-	0x13, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
-	0x12, 0x28, 0x0a, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72,
-	0x65, 0x52, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x52, 0x65,
-	0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x1d, 0x0a, 0x1b, 0x52, 0x65, 0x73, 0x65,
-	0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x51, 0x75, 0x65, 0x72, 0x79,
-	0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x51, 0x0a, 0x1b, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
-	0x50, 0x61, 0x69, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x05, 0x70, 0x61, 0x69,
-	0x72, 0x73, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x62, 0x0a, 0x1c, 0x58, 0x49, 0x6d, 0x70,
-	0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x61, 0x69, 0x72,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52,
-	0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x22, 0x1f, 0x0a, 0x1d,
-	0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
-	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x8a, 0x01,
-	0x0a, 0x0b, 0x50, 0x61, 0x69, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1b, 0x0a,
-	0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
-	0x64, 0x65, 0x5f, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64,
-	0x65, 0x54, 0x6f, 0x12, 0x2d, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x50, 0x61, 0x69, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f,
-	0x72, 0x79, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x4a, 0x04,
-	0x08, 0x05, 0x10, 0x06, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x22, 0xe8, 0x01, 0x0a, 0x08, 0x50,
-	0x61, 0x69, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x61, 0x69, 0x6c, 0x5f,
-	0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c,
-	0x54, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x61, 0x6d, 0x74,
-	0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x61, 0x69, 0x6c,
-	0x41, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x61,
-	0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66,
-	0x61, 0x69, 0x6c, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75,
-	0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0b, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x26, 0x0a,
-	0x0f, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x41,
-	0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0e, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x4a,
-	0x04, 0x08, 0x03, 0x10, 0x04, 0x22, 0x20, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5a, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x4d, 0x69,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
-	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x22, 0x59, 0x0a, 0x1e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
-	0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x21,
-	0x0a, 0x1f, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x89, 0x04, 0x0a, 0x14, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
-	0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2e, 0x0a, 0x11, 0x68, 0x61,
-	0x6c, 0x66, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0f, 0x68, 0x61, 0x6c, 0x66, 0x4c,
-	0x69, 0x66, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2b, 0x0a, 0x0f, 0x68, 0x6f,
-	0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x02, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0e, 0x68, 0x6f, 0x70, 0x50, 0x72, 0x6f, 0x62,
-	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69,
-	0x67, 0x68, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x70,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x15, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x50, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x43, 0x0a, 0x1e, 0x6d,
-	0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72,
-	0x65, 0x6c, 0x61, 0x78, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x1b, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x46, 0x61, 0x69, 0x6c,
-	0x75, 0x72, 0x65, 0x52, 0x65, 0x6c, 0x61, 0x78, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x12, 0x46, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x30, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2e, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4d, 0x6f, 0x64, 0x65,
-	0x6c, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x38, 0x0a, 0x07, 0x61, 0x70, 0x72, 0x69,
-	0x6f, 0x72, 0x69, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x50, 0x61, 0x72,
-	0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x07, 0x61, 0x70, 0x72, 0x69, 0x6f,
-	0x72, 0x69, 0x12, 0x38, 0x0a, 0x07, 0x62, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x18, 0x08, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
-	0x42, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72,
-	0x73, 0x48, 0x00, 0x52, 0x07, 0x62, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x22, 0x2c, 0x0a, 0x10,
-	0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c,
-	0x12, 0x0b, 0x0a, 0x07, 0x41, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x10, 0x00, 0x12, 0x0b, 0x0a,
-	0x07, 0x42, 0x49, 0x4d, 0x4f, 0x44, 0x41, 0x4c, 0x10, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x45, 0x73,
-	0x74, 0x69, 0x6d, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x72, 0x0a,
-	0x11, 0x42, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
-	0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6e, 0x6f, 0x64, 0x65, 0x57, 0x65, 0x69,
-	0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x5f, 0x6d, 0x73, 0x61,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x4d, 0x73,
-	0x61, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x63, 0x61, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x64, 0x65, 0x63, 0x61, 0x79, 0x54, 0x69, 0x6d,
-	0x65, 0x22, 0xad, 0x01, 0x0a, 0x11, 0x41, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x50, 0x61, 0x72,
-	0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x68, 0x61, 0x6c, 0x66, 0x5f,
-	0x6c, 0x69, 0x66, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x0f, 0x68, 0x61, 0x6c, 0x66, 0x4c, 0x69, 0x66, 0x65, 0x53, 0x65, 0x63, 0x6f,
-	0x6e, 0x64, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61,
-	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x68, 0x6f,
-	0x70, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06,
-	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65,
-	0x69, 0x67, 0x68, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
-	0x5f, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x10, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x22, 0x6a, 0x0a, 0x17, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62,
-	0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09,
-	0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x08, 0x66, 0x72, 0x6f, 0x6d, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x6f, 0x5f,
-	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x6f, 0x4e, 0x6f,
-	0x64, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x6b, 0x0a,
-	0x18, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
-	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f,
-	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b,
-	0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x2d, 0x0a, 0x07, 0x68,
-	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
-	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x44, 0x61, 0x74,
-	0x61, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0xca, 0x01, 0x0a, 0x11, 0x42,
-	0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x66,
-	0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6c, 0x74, 0x76,
-	0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e,
-	0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x42,
-	0x02, 0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61,
-	0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x68, 0x6f, 0x70, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
-	0x61, 0x64, 0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x38, 0x0a, 0x12, 0x42, 0x75, 0x69, 0x6c, 0x64,
-	0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a,
-	0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c,
-	0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74,
-	0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
-	0x86, 0x06, 0x0a, 0x09, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a,
-	0x13, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x69, 0x6e, 0x63, 0x6f,
-	0x6d, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x2e, 0x0a,
-	0x13, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6f, 0x75, 0x74, 0x67,
-	0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x28, 0x0a,
-	0x10, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x69,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e,
-	0x67, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f,
-	0x69, 0x6e, 0x67, 0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x48, 0x74, 0x6c, 0x63, 0x49,
-	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6e,
-	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x4e, 0x73, 0x12, 0x3d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x4b, 0x0a, 0x12, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x66,
-	0x61, 0x69, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77,
-	0x61, 0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10,
-	0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x12, 0x3b, 0x0a, 0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
-	0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
-	0x52, 0x0b, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x42, 0x0a,
-	0x0f, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
-	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x48, 0x00, 0x52, 0x0d, 0x6c, 0x69, 0x6e, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x12, 0x47, 0x0a, 0x10, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x5f,
-	0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
-	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x45, 0x0a, 0x10, 0x66, 0x69,
-	0x6e, 0x61, 0x6c, 0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0c,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
-	0x00, 0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x22, 0x3c, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b,
-	0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53,
-	0x45, 0x4e, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45,
-	0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x03, 0x42,
-	0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xbc, 0x01, 0x0a, 0x08, 0x48, 0x74, 0x6c,
-	0x63, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e,
-	0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x10, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f,
-	0x63, 0x6b, 0x12, 0x2b, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x74,
-	0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6f,
-	0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x12,
-	0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f,
-	0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6f,
-	0x6d, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6f,
-	0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
-	0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x37, 0x0a, 0x0c, 0x46, 0x6f, 0x72, 0x77, 0x61,
-	0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
-	0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f,
-	0x22, 0x12, 0x0a, 0x10, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x22, 0x29, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22,
-	0x46, 0x0a, 0x0e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x07, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6f,
-	0x66, 0x66, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f,
-	0x66, 0x66, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x22, 0x11, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xdf, 0x01, 0x0a, 0x0d, 0x4c,
-	0x69, 0x6e, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04,
-	0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52,
-	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x3d, 0x0a, 0x0c, 0x77, 0x69, 0x72, 0x65, 0x5f, 0x66, 0x61,
-	0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x6e,
-	0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x2e, 0x46, 0x61, 0x69, 0x6c,
-	0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x77, 0x69, 0x72, 0x65, 0x46, 0x61, 0x69,
-	0x6c, 0x75, 0x72, 0x65, 0x12, 0x3f, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f,
-	0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72,
-	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
-	0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44,
-	0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
-	0x5f, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x66,
-	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x22, 0x8a, 0x01, 0x0a,
-	0x0d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2d,
-	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a,
-	0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x68, 0x74, 0x6c,
-	0x63, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
-	0x2e, 0x48, 0x54, 0x4c, 0x43, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x52, 0x05, 0x68, 0x74,
-	0x6c, 0x63, 0x73, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x22, 0x3e, 0x0a, 0x0a, 0x43, 0x69, 0x72,
-	0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64,
-	0x12, 0x17, 0x0a, 0x07, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x06, 0x68, 0x74, 0x6c, 0x63, 0x49, 0x64, 0x22, 0xe9, 0x04, 0x0a, 0x1b, 0x46, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65,
-	0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x47, 0x0a, 0x14, 0x69, 0x6e, 0x63,
-	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12,
-	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b,
-	0x65, 0x79, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x61,
-	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x4d, 0x73, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67,
-	0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x69,
-	0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x21, 0x0a,
-	0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68,
-	0x12, 0x3b, 0x0a, 0x1a, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x17, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a,
-	0x14, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6f, 0x75, 0x74,
-	0x67, 0x6f, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12,
-	0x27, 0x0a, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x69,
-	0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
-	0x6e, 0x67, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x60, 0x0a, 0x0e, 0x63, 0x75, 0x73, 0x74,
-	0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x39, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72,
-	0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x63, 0x75, 0x73,
-	0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6e,
-	0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
-	0x6f, 0x6e, 0x69, 0x6f, 0x6e, 0x42, 0x6c, 0x6f, 0x62, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x75, 0x74,
-	0x6f, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0a, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0e, 0x61, 0x75, 0x74, 0x6f, 0x46, 0x61, 0x69, 0x6c, 0x48, 0x65, 0x69,
-	0x67, 0x68, 0x74, 0x1a, 0x40, 0x0a, 0x12, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa8, 0x02, 0x0a, 0x1c, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72,
-	0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x14, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69,
-	0x6e, 0x67, 0x5f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12, 0x69, 0x6e, 0x63,
-	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12,
-	0x3b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x6f,
-	0x6c, 0x76, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
-	0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
-	0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x61, 0x69, 0x6c,
-	0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x6f, 0x64,
-	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e,
-	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43,
-	0x6f, 0x64, 0x65, 0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65,
-	0x22, 0x82, 0x01, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a,
-	0x63, 0x68, 0x61, 0x6e, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
-	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74,
-	0x12, 0x33, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61,
-	0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x1a, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
-	0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x2a, 0x81, 0x04, 0x0a, 0x0d, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74,
-	0x61, 0x69, 0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
-	0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x5f, 0x44, 0x45, 0x54, 0x41, 0x49, 0x4c, 0x10, 0x01, 0x12,
-	0x10, 0x0a, 0x0c, 0x4f, 0x4e, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x10,
-	0x02, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x4c,
-	0x49, 0x47, 0x49, 0x42, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x4f, 0x4e, 0x5f, 0x43,
-	0x48, 0x41, 0x49, 0x4e, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x04, 0x12, 0x14,
-	0x0a, 0x10, 0x48, 0x54, 0x4c, 0x43, 0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x53, 0x5f, 0x4d,
-	0x41, 0x58, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x49, 0x4e, 0x53, 0x55, 0x46, 0x46, 0x49, 0x43,
-	0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42, 0x41, 0x4c, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x06, 0x12, 0x16,
-	0x0a, 0x12, 0x49, 0x4e, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x46, 0x4f, 0x52,
-	0x57, 0x41, 0x52, 0x44, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x48, 0x54, 0x4c, 0x43, 0x5f, 0x41,
-	0x44, 0x44, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x08, 0x12, 0x15, 0x0a, 0x11, 0x46,
-	0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x53, 0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44,
-	0x10, 0x09, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x43, 0x41,
-	0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10, 0x0a, 0x12, 0x15, 0x0a, 0x11, 0x49, 0x4e, 0x56, 0x4f,
-	0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x52, 0x50, 0x41, 0x49, 0x44, 0x10, 0x0b, 0x12,
-	0x1b, 0x0a, 0x17, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52,
-	0x59, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x53, 0x4f, 0x4f, 0x4e, 0x10, 0x0c, 0x12, 0x14, 0x0a, 0x10,
-	0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x4f, 0x50, 0x45, 0x4e,
-	0x10, 0x0d, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x50, 0x50, 0x5f, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43,
-	0x45, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x0e, 0x12, 0x14, 0x0a, 0x10, 0x41,
-	0x44, 0x44, 0x52, 0x45, 0x53, 0x53, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10,
-	0x0f, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x45, 0x54, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x5f, 0x4d,
-	0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x10, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x54,
-	0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x11,
-	0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x54, 0x5f, 0x4f, 0x56, 0x45, 0x52, 0x50, 0x41, 0x49, 0x44,
-	0x10, 0x12, 0x12, 0x13, 0x0a, 0x0f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x49, 0x4e,
-	0x56, 0x4f, 0x49, 0x43, 0x45, 0x10, 0x13, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x56, 0x41, 0x4c,
-	0x49, 0x44, 0x5f, 0x4b, 0x45, 0x59, 0x53, 0x45, 0x4e, 0x44, 0x10, 0x14, 0x12, 0x13, 0x0a, 0x0f,
-	0x4d, 0x50, 0x50, 0x5f, 0x49, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10,
-	0x15, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x49, 0x52, 0x43, 0x55, 0x4c, 0x41, 0x52, 0x5f, 0x52, 0x4f,
-	0x55, 0x54, 0x45, 0x10, 0x16, 0x2a, 0xae, 0x01, 0x0a, 0x0c, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x5f, 0x46, 0x4c, 0x49,
-	0x47, 0x48, 0x54, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44,
-	0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x54,
-	0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x49, 0x4c,
-	0x45, 0x44, 0x5f, 0x4e, 0x4f, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x10, 0x03, 0x12, 0x10, 0x0a,
-	0x0c, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x04, 0x12,
-	0x24, 0x0a, 0x20, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x49, 0x4e, 0x43, 0x4f, 0x52, 0x52,
-	0x45, 0x43, 0x54, 0x5f, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x44, 0x45, 0x54, 0x41,
-	0x49, 0x4c, 0x53, 0x10, 0x05, 0x12, 0x1f, 0x0a, 0x1b, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f,
-	0x49, 0x4e, 0x53, 0x55, 0x46, 0x46, 0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42, 0x41, 0x4c,
-	0x41, 0x4e, 0x43, 0x45, 0x10, 0x06, 0x2a, 0x3c, 0x0a, 0x18, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76,
-	0x65, 0x48, 0x6f, 0x6c, 0x64, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x41, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45, 0x54, 0x54, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x08,
-	0x0a, 0x04, 0x46, 0x41, 0x49, 0x4c, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x53, 0x55,
-	0x4d, 0x45, 0x10, 0x02, 0x2a, 0x35, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x4e, 0x41, 0x42,
-	0x4c, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x10,
-	0x01, 0x12, 0x08, 0x0a, 0x04, 0x41, 0x55, 0x54, 0x4f, 0x10, 0x02, 0x32, 0xb5, 0x0c, 0x0a, 0x06,
-	0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x12, 0x40, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x56, 0x32, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x63,
-	0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x56, 0x32, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72,
-	0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0d,
-	0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
-	0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01,
-	0x12, 0x4b, 0x0a, 0x10, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x75, 0x74,
-	0x65, 0x46, 0x65, 0x65, 0x12, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75,
-	0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a,
-	0x0b, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x72,
-	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52,
-	0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f,
-	0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x03, 0x88, 0x02, 0x01,
-	0x12, 0x42, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x56,
-	0x32, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
-	0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x54, 0x4c, 0x43, 0x41, 0x74, 0x74,
-	0x65, 0x6d, 0x70, 0x74, 0x12, 0x64, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x25, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52,
-	0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
-	0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a, 0x13, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x6a, 0x0a, 0x15, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x27, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x28, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x58,
-	0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
-	0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x17,
-	0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
-	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47,
-	0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70,
-	0x0a, 0x17, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
-	0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x5b, 0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69,
-	0x6c, 0x69, 0x74, 0x79, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
-	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62,
-	0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a,
-	0x0a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75,
-	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
-	0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
-	0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x4d,
-	0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72,
-	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x03, 0x88, 0x02, 0x01, 0x30, 0x01, 0x12, 0x4f, 0x0a,
-	0x0c, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x03, 0x88, 0x02, 0x01, 0x30, 0x01, 0x12, 0x66,
-	0x0a, 0x0f, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x6f,
-	0x72, 0x12, 0x27, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65,
-	0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74,
-	0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x28, 0x01, 0x30, 0x01, 0x12, 0x5b, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61,
-	0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
-	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x2f, 0x6c, 0x6e, 0x64, 0x2f, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_routerrpc_router_proto_rawDescOnce sync.Once
-	file_routerrpc_router_proto_rawDescData = file_routerrpc_router_proto_rawDesc
-)
-
-func file_routerrpc_router_proto_rawDescGZIP() []byte {
-	file_routerrpc_router_proto_rawDescOnce.Do(func() {
-		file_routerrpc_router_proto_rawDescData = protoimpl.X.CompressGZIP(file_routerrpc_router_proto_rawDescData)
-	})
-	return file_routerrpc_router_proto_rawDescData
-}
-
-var file_routerrpc_router_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_routerrpc_router_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
-var file_routerrpc_router_proto_goTypes = []interface{}{
-	(FailureDetail)(0),                         // 0: routerrpc.FailureDetail
-	(PaymentState)(0),                          // 1: routerrpc.PaymentState
-	(ResolveHoldForwardAction)(0),              // 2: routerrpc.ResolveHoldForwardAction
-	(ChanStatusAction)(0),                      // 3: routerrpc.ChanStatusAction
-	(MissionControlConfig_ProbabilityModel)(0), // 4: routerrpc.MissionControlConfig.ProbabilityModel
-	(HtlcEvent_EventType)(0),                   // 5: routerrpc.HtlcEvent.EventType
-	(*SendPaymentRequest)(nil),                 // 6: routerrpc.SendPaymentRequest
-	(*TrackPaymentRequest)(nil),                // 7: routerrpc.TrackPaymentRequest
-	(*TrackPaymentsRequest)(nil),               // 8: routerrpc.TrackPaymentsRequest
-	(*RouteFeeRequest)(nil),                    // 9: routerrpc.RouteFeeRequest
-	(*RouteFeeResponse)(nil),                   // 10: routerrpc.RouteFeeResponse
-	(*SendToRouteRequest)(nil),                 // 11: routerrpc.SendToRouteRequest
-	(*SendToRouteResponse)(nil),                // 12: routerrpc.SendToRouteResponse
-	(*ResetMissionControlRequest)(nil),         // 13: routerrpc.ResetMissionControlRequest
-	(*ResetMissionControlResponse)(nil),        // 14: routerrpc.ResetMissionControlResponse
-	(*QueryMissionControlRequest)(nil),         // 15: routerrpc.QueryMissionControlRequest
-	(*QueryMissionControlResponse)(nil),        // 16: routerrpc.QueryMissionControlResponse
-	(*XImportMissionControlRequest)(nil),       // 17: routerrpc.XImportMissionControlRequest
-	(*XImportMissionControlResponse)(nil),      // 18: routerrpc.XImportMissionControlResponse
-	(*PairHistory)(nil),                        // 19: routerrpc.PairHistory
-	(*PairData)(nil),                           // 20: routerrpc.PairData
-	(*GetMissionControlConfigRequest)(nil),     // 21: routerrpc.GetMissionControlConfigRequest
-	(*GetMissionControlConfigResponse)(nil),    // 22: routerrpc.GetMissionControlConfigResponse
-	(*SetMissionControlConfigRequest)(nil),     // 23: routerrpc.SetMissionControlConfigRequest
-	(*SetMissionControlConfigResponse)(nil),    // 24: routerrpc.SetMissionControlConfigResponse
-	(*MissionControlConfig)(nil),               // 25: routerrpc.MissionControlConfig
-	(*BimodalParameters)(nil),                  // 26: routerrpc.BimodalParameters
-	(*AprioriParameters)(nil),                  // 27: routerrpc.AprioriParameters
-	(*QueryProbabilityRequest)(nil),            // 28: routerrpc.QueryProbabilityRequest
-	(*QueryProbabilityResponse)(nil),           // 29: routerrpc.QueryProbabilityResponse
-	(*BuildRouteRequest)(nil),                  // 30: routerrpc.BuildRouteRequest
-	(*BuildRouteResponse)(nil),                 // 31: routerrpc.BuildRouteResponse
-	(*SubscribeHtlcEventsRequest)(nil),         // 32: routerrpc.SubscribeHtlcEventsRequest
-	(*HtlcEvent)(nil),                          // 33: routerrpc.HtlcEvent
-	(*HtlcInfo)(nil),                           // 34: routerrpc.HtlcInfo
-	(*ForwardEvent)(nil),                       // 35: routerrpc.ForwardEvent
-	(*ForwardFailEvent)(nil),                   // 36: routerrpc.ForwardFailEvent
-	(*SettleEvent)(nil),                        // 37: routerrpc.SettleEvent
-	(*FinalHtlcEvent)(nil),                     // 38: routerrpc.FinalHtlcEvent
-	(*SubscribedEvent)(nil),                    // 39: routerrpc.SubscribedEvent
-	(*LinkFailEvent)(nil),                      // 40: routerrpc.LinkFailEvent
-	(*PaymentStatus)(nil),                      // 41: routerrpc.PaymentStatus
-	(*CircuitKey)(nil),                         // 42: routerrpc.CircuitKey
-	(*ForwardHtlcInterceptRequest)(nil),        // 43: routerrpc.ForwardHtlcInterceptRequest
-	(*ForwardHtlcInterceptResponse)(nil),       // 44: routerrpc.ForwardHtlcInterceptResponse
-	(*UpdateChanStatusRequest)(nil),            // 45: routerrpc.UpdateChanStatusRequest
-	(*UpdateChanStatusResponse)(nil),           // 46: routerrpc.UpdateChanStatusResponse
-	nil,                                        // 47: routerrpc.SendPaymentRequest.DestCustomRecordsEntry
-	nil,                                        // 48: routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
-	(*lnrpc.RouteHint)(nil),                    // 49: lnrpc.RouteHint
-	(lnrpc.FeatureBit)(0),                      // 50: lnrpc.FeatureBit
-	(lnrpc.PaymentFailureReason)(0),            // 51: lnrpc.PaymentFailureReason
-	(*lnrpc.Route)(nil),                        // 52: lnrpc.Route
-	(*lnrpc.Failure)(nil),                      // 53: lnrpc.Failure
-	(lnrpc.Failure_FailureCode)(0),             // 54: lnrpc.Failure.FailureCode
-	(*lnrpc.HTLCAttempt)(nil),                  // 55: lnrpc.HTLCAttempt
-	(*lnrpc.ChannelPoint)(nil),                 // 56: lnrpc.ChannelPoint
-	(*lnrpc.Payment)(nil),                      // 57: lnrpc.Payment
-}
-var file_routerrpc_router_proto_depIdxs = []int32{
-	49, // 0: routerrpc.SendPaymentRequest.route_hints:type_name -> lnrpc.RouteHint
-	47, // 1: routerrpc.SendPaymentRequest.dest_custom_records:type_name -> routerrpc.SendPaymentRequest.DestCustomRecordsEntry
-	50, // 2: routerrpc.SendPaymentRequest.dest_features:type_name -> lnrpc.FeatureBit
-	51, // 3: routerrpc.RouteFeeResponse.failure_reason:type_name -> lnrpc.PaymentFailureReason
-	52, // 4: routerrpc.SendToRouteRequest.route:type_name -> lnrpc.Route
-	53, // 5: routerrpc.SendToRouteResponse.failure:type_name -> lnrpc.Failure
-	19, // 6: routerrpc.QueryMissionControlResponse.pairs:type_name -> routerrpc.PairHistory
-	19, // 7: routerrpc.XImportMissionControlRequest.pairs:type_name -> routerrpc.PairHistory
-	20, // 8: routerrpc.PairHistory.history:type_name -> routerrpc.PairData
-	25, // 9: routerrpc.GetMissionControlConfigResponse.config:type_name -> routerrpc.MissionControlConfig
-	25, // 10: routerrpc.SetMissionControlConfigRequest.config:type_name -> routerrpc.MissionControlConfig
-	4,  // 11: routerrpc.MissionControlConfig.model:type_name -> routerrpc.MissionControlConfig.ProbabilityModel
-	27, // 12: routerrpc.MissionControlConfig.apriori:type_name -> routerrpc.AprioriParameters
-	26, // 13: routerrpc.MissionControlConfig.bimodal:type_name -> routerrpc.BimodalParameters
-	20, // 14: routerrpc.QueryProbabilityResponse.history:type_name -> routerrpc.PairData
-	52, // 15: routerrpc.BuildRouteResponse.route:type_name -> lnrpc.Route
-	5,  // 16: routerrpc.HtlcEvent.event_type:type_name -> routerrpc.HtlcEvent.EventType
-	35, // 17: routerrpc.HtlcEvent.forward_event:type_name -> routerrpc.ForwardEvent
-	36, // 18: routerrpc.HtlcEvent.forward_fail_event:type_name -> routerrpc.ForwardFailEvent
-	37, // 19: routerrpc.HtlcEvent.settle_event:type_name -> routerrpc.SettleEvent
-	40, // 20: routerrpc.HtlcEvent.link_fail_event:type_name -> routerrpc.LinkFailEvent
-	39, // 21: routerrpc.HtlcEvent.subscribed_event:type_name -> routerrpc.SubscribedEvent
-	38, // 22: routerrpc.HtlcEvent.final_htlc_event:type_name -> routerrpc.FinalHtlcEvent
-	34, // 23: routerrpc.ForwardEvent.info:type_name -> routerrpc.HtlcInfo
-	34, // 24: routerrpc.LinkFailEvent.info:type_name -> routerrpc.HtlcInfo
-	54, // 25: routerrpc.LinkFailEvent.wire_failure:type_name -> lnrpc.Failure.FailureCode
-	0,  // 26: routerrpc.LinkFailEvent.failure_detail:type_name -> routerrpc.FailureDetail
-	1,  // 27: routerrpc.PaymentStatus.state:type_name -> routerrpc.PaymentState
-	55, // 28: routerrpc.PaymentStatus.htlcs:type_name -> lnrpc.HTLCAttempt
-	42, // 29: routerrpc.ForwardHtlcInterceptRequest.incoming_circuit_key:type_name -> routerrpc.CircuitKey
-	48, // 30: routerrpc.ForwardHtlcInterceptRequest.custom_records:type_name -> routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
-	42, // 31: routerrpc.ForwardHtlcInterceptResponse.incoming_circuit_key:type_name -> routerrpc.CircuitKey
-	2,  // 32: routerrpc.ForwardHtlcInterceptResponse.action:type_name -> routerrpc.ResolveHoldForwardAction
-	54, // 33: routerrpc.ForwardHtlcInterceptResponse.failure_code:type_name -> lnrpc.Failure.FailureCode
-	56, // 34: routerrpc.UpdateChanStatusRequest.chan_point:type_name -> lnrpc.ChannelPoint
-	3,  // 35: routerrpc.UpdateChanStatusRequest.action:type_name -> routerrpc.ChanStatusAction
-	6,  // 36: routerrpc.Router.SendPaymentV2:input_type -> routerrpc.SendPaymentRequest
-	7,  // 37: routerrpc.Router.TrackPaymentV2:input_type -> routerrpc.TrackPaymentRequest
-	8,  // 38: routerrpc.Router.TrackPayments:input_type -> routerrpc.TrackPaymentsRequest
-	9,  // 39: routerrpc.Router.EstimateRouteFee:input_type -> routerrpc.RouteFeeRequest
-	11, // 40: routerrpc.Router.SendToRoute:input_type -> routerrpc.SendToRouteRequest
-	11, // 41: routerrpc.Router.SendToRouteV2:input_type -> routerrpc.SendToRouteRequest
-	13, // 42: routerrpc.Router.ResetMissionControl:input_type -> routerrpc.ResetMissionControlRequest
-	15, // 43: routerrpc.Router.QueryMissionControl:input_type -> routerrpc.QueryMissionControlRequest
-	17, // 44: routerrpc.Router.XImportMissionControl:input_type -> routerrpc.XImportMissionControlRequest
-	21, // 45: routerrpc.Router.GetMissionControlConfig:input_type -> routerrpc.GetMissionControlConfigRequest
-	23, // 46: routerrpc.Router.SetMissionControlConfig:input_type -> routerrpc.SetMissionControlConfigRequest
-	28, // 47: routerrpc.Router.QueryProbability:input_type -> routerrpc.QueryProbabilityRequest
-	30, // 48: routerrpc.Router.BuildRoute:input_type -> routerrpc.BuildRouteRequest
-	32, // 49: routerrpc.Router.SubscribeHtlcEvents:input_type -> routerrpc.SubscribeHtlcEventsRequest
-	6,  // 50: routerrpc.Router.SendPayment:input_type -> routerrpc.SendPaymentRequest
-	7,  // 51: routerrpc.Router.TrackPayment:input_type -> routerrpc.TrackPaymentRequest
-	44, // 52: routerrpc.Router.HtlcInterceptor:input_type -> routerrpc.ForwardHtlcInterceptResponse
-	45, // 53: routerrpc.Router.UpdateChanStatus:input_type -> routerrpc.UpdateChanStatusRequest
-	57, // 54: routerrpc.Router.SendPaymentV2:output_type -> lnrpc.Payment
-	57, // 55: routerrpc.Router.TrackPaymentV2:output_type -> lnrpc.Payment
-	57, // 56: routerrpc.Router.TrackPayments:output_type -> lnrpc.Payment
-	10, // 57: routerrpc.Router.EstimateRouteFee:output_type -> routerrpc.RouteFeeResponse
-	12, // 58: routerrpc.Router.SendToRoute:output_type -> routerrpc.SendToRouteResponse
-	55, // 59: routerrpc.Router.SendToRouteV2:output_type -> lnrpc.HTLCAttempt
-	14, // 60: routerrpc.Router.ResetMissionControl:output_type -> routerrpc.ResetMissionControlResponse
-	16, // 61: routerrpc.Router.QueryMissionControl:output_type -> routerrpc.QueryMissionControlResponse
-	18, // 62: routerrpc.Router.XImportMissionControl:output_type -> routerrpc.XImportMissionControlResponse
-	22, // 63: routerrpc.Router.GetMissionControlConfig:output_type -> routerrpc.GetMissionControlConfigResponse
-	24, // 64: routerrpc.Router.SetMissionControlConfig:output_type -> routerrpc.SetMissionControlConfigResponse
-	29, // 65: routerrpc.Router.QueryProbability:output_type -> routerrpc.QueryProbabilityResponse
-	31, // 66: routerrpc.Router.BuildRoute:output_type -> routerrpc.BuildRouteResponse
-	33, // 67: routerrpc.Router.SubscribeHtlcEvents:output_type -> routerrpc.HtlcEvent
-	41, // 68: routerrpc.Router.SendPayment:output_type -> routerrpc.PaymentStatus
-	41, // 69: routerrpc.Router.TrackPayment:output_type -> routerrpc.PaymentStatus
-	43, // 70: routerrpc.Router.HtlcInterceptor:output_type -> routerrpc.ForwardHtlcInterceptRequest
-	46, // 71: routerrpc.Router.UpdateChanStatus:output_type -> routerrpc.UpdateChanStatusResponse
-	54, // [54:72] is the sub-list for method output_type
-	36, // [36:54] is the sub-list for method input_type
-	36, // [36:36] is the sub-list for extension type_name
-	36, // [36:36] is the sub-list for extension extendee
-	0,  // [0:36] is the sub-list for field type_name
-}
-
-func init() { file_routerrpc_router_proto_init() }
-func file_routerrpc_router_proto_init() {
-	if File_routerrpc_router_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_routerrpc_router_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendPaymentRequest); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardEvent.ProtoReflect.Descriptor instead.
+func (*ForwardEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ForwardEvent) GetInfo() *HtlcInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type ForwardFailEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ForwardFailEvent) Reset() {
+	*x = ForwardFailEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardFailEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardFailEvent) ProtoMessage() {}
+
+func (x *ForwardFailEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardFailEvent.ProtoReflect.Descriptor instead.
+func (*ForwardFailEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{41}
+}
+
+type SettleEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The revealed preimage.
+	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+}
+
+func (x *SettleEvent) Reset() {
+	*x = SettleEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettleEvent) ProtoMessage() {}
+
+func (x *SettleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettleEvent.ProtoReflect.Descriptor instead.
+func (*SettleEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *SettleEvent) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+type FinalHtlcEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Settled  bool `protobuf:"varint,1,opt,name=settled,proto3" json:"settled,omitempty"`
+	Offchain bool `protobuf:"varint,2,opt,name=offchain,proto3" json:"offchain,omitempty"`
+}
+
+func (x *FinalHtlcEvent) Reset() {
+	*x = FinalHtlcEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalHtlcEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalHtlcEvent) ProtoMessage() {}
+
+func (x *FinalHtlcEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalHtlcEvent.ProtoReflect.Descriptor instead.
+func (*FinalHtlcEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *FinalHtlcEvent) GetSettled() bool {
+	if x != nil {
+		return x.Settled
+	}
+	return false
+}
+
+func (x *FinalHtlcEvent) GetOffchain() bool {
+	if x != nil {
+		return x.Offchain
+	}
+	return false
+}
+
+type SubscribedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SubscribedEvent) Reset() {
+	*x = SubscribedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribedEvent) ProtoMessage() {}
+
+func (x *SubscribedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribedEvent.ProtoReflect.Descriptor instead.
+func (*SubscribedEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{44}
+}
+
+type LinkFailEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Info contains details about the htlc that we failed.
+	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	// FailureCode is the BOLT error code for the failure.
+	WireFailure lnrpc.Failure_FailureCode `protobuf:"varint,2,opt,name=wire_failure,json=wireFailure,proto3,enum=lnrpc.Failure_FailureCode" json:"wire_failure,omitempty"`
+	// FailureDetail provides additional information about the reason for the
+	// failure. This detail enriches the information provided by the wire message
+	// and may be 'no detail' if the wire message requires no additional metadata.
+	FailureDetail FailureDetail `protobuf:"varint,3,opt,name=failure_detail,json=failureDetail,proto3,enum=routerrpc.FailureDetail" json:"failure_detail,omitempty"`
+	// A string representation of the link failure.
+	FailureString string `protobuf:"bytes,4,opt,name=failure_string,json=failureString,proto3" json:"failure_string,omitempty"`
+}
+
+func (x *LinkFailEvent) Reset() {
+	*x = LinkFailEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LinkFailEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkFailEvent) ProtoMessage() {}
+
+func (x *LinkFailEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkFailEvent.ProtoReflect.Descriptor instead.
+func (*LinkFailEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *LinkFailEvent) GetInfo() *HtlcInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *LinkFailEvent) GetWireFailure() lnrpc.Failure_FailureCode {
+	if x != nil {
+		return x.WireFailure
+	}
+	return lnrpc.Failure_FailureCode(0)
+}
+
+func (x *LinkFailEvent) GetFailureDetail() FailureDetail {
+	if x != nil {
+		return x.FailureDetail
+	}
+	return FailureDetail_UNKNOWN
+}
+
+func (x *LinkFailEvent) GetFailureString() string {
+	if x != nil {
+		return x.FailureString
+	}
+	return ""
+}
+
+type PaymentStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Current state the payment is in.
+	State PaymentState `protobuf:"varint,1,opt,name=state,proto3,enum=routerrpc.PaymentState" json:"state,omitempty"`
+	// The pre-image of the payment when state is SUCCEEDED.
+	Preimage []byte `protobuf:"bytes,2,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// The HTLCs made in attempt to settle the payment [EXPERIMENTAL].
+	Htlcs []*lnrpc.HTLCAttempt `protobuf:"bytes,4,rep,name=htlcs,proto3" json:"htlcs,omitempty"`
+}
+
+func (x *PaymentStatus) Reset() {
+	*x = PaymentStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentStatus) ProtoMessage() {}
+
+func (x *PaymentStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentStatus.ProtoReflect.Descriptor instead.
+func (*PaymentStatus) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *PaymentStatus) GetState() PaymentState {
+	if x != nil {
+		return x.State
+	}
+	return PaymentState_IN_FLIGHT
+}
+
+func (x *PaymentStatus) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *PaymentStatus) GetHtlcs() []*lnrpc.HTLCAttempt {
+	if x != nil {
+		return x.Htlcs
+	}
+	return nil
+}
+
+type CircuitKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// / The id of the channel that the is part of this circuit.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+	// / The index of the incoming htlc in the incoming channel.
+	HtlcId uint64 `protobuf:"varint,2,opt,name=htlc_id,json=htlcId,proto3" json:"htlc_id,omitempty"`
+}
+
+func (x *CircuitKey) Reset() {
+	*x = CircuitKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CircuitKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CircuitKey) ProtoMessage() {}
+
+func (x *CircuitKey) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CircuitKey.ProtoReflect.Descriptor instead.
+func (*CircuitKey) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CircuitKey) GetChanId() uint64 {
+	if x != nil {
+		return x.ChanId
+	}
+	return 0
+}
+
+func (x *CircuitKey) GetHtlcId() uint64 {
+	if x != nil {
+		return x.HtlcId
+	}
+	return 0
+}
+
+type ForwardHtlcInterceptRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key of this forwarded htlc. It defines the incoming channel id and
+	// the index in this channel.
+	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
+	// The incoming htlc amount.
+	IncomingAmountMsat uint64 `protobuf:"varint,5,opt,name=incoming_amount_msat,json=incomingAmountMsat,proto3" json:"incoming_amount_msat,omitempty"`
+	// The incoming htlc expiry.
+	IncomingExpiry uint32 `protobuf:"varint,6,opt,name=incoming_expiry,json=incomingExpiry,proto3" json:"incoming_expiry,omitempty"`
+	// The htlc payment hash. This value is not guaranteed to be unique per
+	// request.
+	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// The requested outgoing channel id for this forwarded htlc. Because of
+	// non-strict forwarding, this isn't necessarily the channel over which the
+	// packet will be forwarded eventually. A different channel to the same peer
+	// may be selected as well.
+	OutgoingRequestedChanId uint64 `protobuf:"varint,7,opt,name=outgoing_requested_chan_id,json=outgoingRequestedChanId,proto3" json:"outgoing_requested_chan_id,omitempty"`
+	// The outgoing htlc amount.
+	OutgoingAmountMsat uint64 `protobuf:"varint,3,opt,name=outgoing_amount_msat,json=outgoingAmountMsat,proto3" json:"outgoing_amount_msat,omitempty"`
+	// The outgoing htlc expiry.
+	OutgoingExpiry uint32 `protobuf:"varint,4,opt,name=outgoing_expiry,json=outgoingExpiry,proto3" json:"outgoing_expiry,omitempty"`
+	// Any custom records that were present in the payload.
+	CustomRecords map[uint64][]byte `protobuf:"bytes,8,rep,name=custom_records,json=customRecords,proto3" json:"custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The onion blob for the next hop
+	OnionBlob []byte `protobuf:"bytes,9,opt,name=onion_blob,json=onionBlob,proto3" json:"onion_blob,omitempty"`
+	// The block height at which this htlc will be auto-failed to prevent the
+	// channel from force-closing.
+	AutoFailHeight int32 `protobuf:"varint,10,opt,name=auto_fail_height,json=autoFailHeight,proto3" json:"auto_fail_height,omitempty"`
+}
+
+func (x *ForwardHtlcInterceptRequest) Reset() {
+	*x = ForwardHtlcInterceptRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardHtlcInterceptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardHtlcInterceptRequest) ProtoMessage() {}
+
+func (x *ForwardHtlcInterceptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardHtlcInterceptRequest.ProtoReflect.Descriptor instead.
+func (*ForwardHtlcInterceptRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ForwardHtlcInterceptRequest) GetIncomingCircuitKey() *CircuitKey {
+	if x != nil {
+		return x.IncomingCircuitKey
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetIncomingAmountMsat() uint64 {
+	if x != nil {
+		return x.IncomingAmountMsat
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetIncomingExpiry() uint32 {
+	if x != nil {
+		return x.IncomingExpiry
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOutgoingRequestedChanId() uint64 {
+	if x != nil {
+		return x.OutgoingRequestedChanId
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOutgoingAmountMsat() uint64 {
+	if x != nil {
+		return x.OutgoingAmountMsat
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOutgoingExpiry() uint32 {
+	if x != nil {
+		return x.OutgoingExpiry
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetCustomRecords() map[uint64][]byte {
+	if x != nil {
+		return x.CustomRecords
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOnionBlob() []byte {
+	if x != nil {
+		return x.OnionBlob
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetAutoFailHeight() int32 {
+	if x != nil {
+		return x.AutoFailHeight
+	}
+	return 0
+}
+
+// *
+// ForwardHtlcInterceptResponse enables the caller to resolve a previously hold
+// forward. The caller can choose either to:
+// - `Resume`: Execute the default behavior (usually forward).
+// - `Reject`: Fail the htlc backwards.
+// - `Settle`: Settle this htlc with a given preimage.
+type ForwardHtlcInterceptResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// *
+	// The key of this forwarded htlc. It defines the incoming channel id and
+	// the index in this channel.
+	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
+	// The resolve action for this intercepted htlc.
+	Action ResolveHoldForwardAction `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ResolveHoldForwardAction" json:"action,omitempty"`
+	// The preimage in case the resolve action is Settle.
+	Preimage []byte `protobuf:"bytes,3,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// Encrypted failure message in case the resolve action is Fail.
+	//
+	// If failure_message is specified, the failure_code field must be set
+	// to zero.
+	FailureMessage []byte `protobuf:"bytes,4,opt,name=failure_message,json=failureMessage,proto3" json:"failure_message,omitempty"`
+	// Return the specified failure code in case the resolve action is Fail. The
+	// message data fields are populated automatically.
+	//
+	// If a non-zero failure_code is specified, failure_message must not be set.
+	//
+	// For backwards-compatibility reasons, TEMPORARY_CHANNEL_FAILURE is the
+	// default value for this field.
+	FailureCode lnrpc.Failure_FailureCode `protobuf:"varint,5,opt,name=failure_code,json=failureCode,proto3,enum=lnrpc.Failure_FailureCode" json:"failure_code,omitempty"`
+}
+
+func (x *ForwardHtlcInterceptResponse) Reset() {
+	*x = ForwardHtlcInterceptResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardHtlcInterceptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardHtlcInterceptResponse) ProtoMessage() {}
+
+func (x *ForwardHtlcInterceptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardHtlcInterceptResponse.ProtoReflect.Descriptor instead.
+func (*ForwardHtlcInterceptResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ForwardHtlcInterceptResponse) GetIncomingCircuitKey() *CircuitKey {
+	if x != nil {
+		return x.IncomingCircuitKey
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptResponse) GetAction() ResolveHoldForwardAction {
+	if x != nil {
+		return x.Action
+	}
+	return ResolveHoldForwardAction_SETTLE
+}
+
+func (x *ForwardHtlcInterceptResponse) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptResponse) GetFailureMessage() []byte {
+	if x != nil {
+		return x.FailureMessage
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptResponse) GetFailureCode() lnrpc.Failure_FailureCode {
+	if x != nil {
+		return x.FailureCode
+	}
+	return lnrpc.Failure_FailureCode(0)
+}
+
+type UpdateChanStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
+	Action    ChanStatusAction    `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ChanStatusAction" json:"action,omitempty"`
+}
+
+func (x *UpdateChanStatusRequest) Reset() {
+	*x = UpdateChanStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateChanStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChanStatusRequest) ProtoMessage() {}
+
+func (x *UpdateChanStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChanStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateChanStatusRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *UpdateChanStatusRequest) GetChanPoint() *lnrpc.ChannelPoint {
+	if x != nil {
+		return x.ChanPoint
+	}
+	return nil
+}
+
+func (x *UpdateChanStatusRequest) GetAction() ChanStatusAction {
+	if x != nil {
+		return x.Action
+	}
+	return ChanStatusAction_ENABLE
+}
+
+type UpdateChanStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateChanStatusResponse) Reset() {
+	*x = UpdateChanStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateChanStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChanStatusResponse) ProtoMessage() {}
+
+func (x *UpdateChanStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChanStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateChanStatusResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{51}
+}
+
+type FreezeChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The channel to put into maintenance mode.
+	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
+	// The number of seconds after which the channel is unfrozen automatically.
+	// If zero, the channel stays frozen until UnfreezeChannel is called.
+	DurationSeconds uint64 `protobuf:"varint,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+}
+
+func (x *FreezeChannelRequest) Reset() {
+	*x = FreezeChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FreezeChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeChannelRequest) ProtoMessage() {}
+
+func (x *FreezeChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeChannelRequest.ProtoReflect.Descriptor instead.
+func (*FreezeChannelRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *FreezeChannelRequest) GetChanPoint() *lnrpc.ChannelPoint {
+	if x != nil {
+		return x.ChanPoint
+	}
+	return nil
+}
+
+func (x *FreezeChannelRequest) GetDurationSeconds() uint64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type FreezeChannelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FreezeChannelResponse) Reset() {
+	*x = FreezeChannelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FreezeChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeChannelResponse) ProtoMessage() {}
+
+func (x *FreezeChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeChannelResponse.ProtoReflect.Descriptor instead.
+func (*FreezeChannelResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{53}
+}
+
+type UnfreezeChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The channel to take out of maintenance mode.
+	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
+}
+
+func (x *UnfreezeChannelRequest) Reset() {
+	*x = UnfreezeChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnfreezeChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnfreezeChannelRequest) ProtoMessage() {}
+
+func (x *UnfreezeChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnfreezeChannelRequest.ProtoReflect.Descriptor instead.
+func (*UnfreezeChannelRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *UnfreezeChannelRequest) GetChanPoint() *lnrpc.ChannelPoint {
+	if x != nil {
+		return x.ChanPoint
+	}
+	return nil
+}
+
+type UnfreezeChannelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UnfreezeChannelResponse) Reset() {
+	*x = UnfreezeChannelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnfreezeChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnfreezeChannelResponse) ProtoMessage() {}
+
+func (x *UnfreezeChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnfreezeChannelResponse.ProtoReflect.Descriptor instead.
+func (*UnfreezeChannelResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{55}
+}
+
+type AddKnownRouteHintsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The public key of the private destination the hints lead to.
+	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
+	// The route hints to the destination.
+	RouteHints []*lnrpc.RouteHint `protobuf:"bytes,2,rep,name=route_hints,json=routeHints,proto3" json:"route_hints,omitempty"`
+	// The number of seconds the hints are used for. Must be positive.
+	TtlSeconds uint64 `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	// The payments that may use the hints.
+	Scope RouteHintScope `protobuf:"varint,4,opt,name=scope,proto3,enum=routerrpc.RouteHintScope" json:"scope,omitempty"`
+}
+
+func (x *AddKnownRouteHintsRequest) Reset() {
+	*x = AddKnownRouteHintsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddKnownRouteHintsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddKnownRouteHintsRequest) ProtoMessage() {}
+
+func (x *AddKnownRouteHintsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddKnownRouteHintsRequest.ProtoReflect.Descriptor instead.
+func (*AddKnownRouteHintsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *AddKnownRouteHintsRequest) GetDest() []byte {
+	if x != nil {
+		return x.Dest
+	}
+	return nil
+}
+
+func (x *AddKnownRouteHintsRequest) GetRouteHints() []*lnrpc.RouteHint {
+	if x != nil {
+		return x.RouteHints
+	}
+	return nil
+}
+
+func (x *AddKnownRouteHintsRequest) GetTtlSeconds() uint64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *AddKnownRouteHintsRequest) GetScope() RouteHintScope {
+	if x != nil {
+		return x.Scope
+	}
+	return RouteHintScope_ROUTE_HINT_SCOPE_DESTINATION
+}
+
+type AddKnownRouteHintsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddKnownRouteHintsResponse) Reset() {
+	*x = AddKnownRouteHintsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddKnownRouteHintsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddKnownRouteHintsResponse) ProtoMessage() {}
+
+func (x *AddKnownRouteHintsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddKnownRouteHintsResponse.ProtoReflect.Descriptor instead.
+func (*AddKnownRouteHintsResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{57}
+}
+
+type RemoveKnownRouteHintsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The public key of the destination to remove the hints of.
+	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
+}
+
+func (x *RemoveKnownRouteHintsRequest) Reset() {
+	*x = RemoveKnownRouteHintsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveKnownRouteHintsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveKnownRouteHintsRequest) ProtoMessage() {}
+
+func (x *RemoveKnownRouteHintsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveKnownRouteHintsRequest.ProtoReflect.Descriptor instead.
+func (*RemoveKnownRouteHintsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *RemoveKnownRouteHintsRequest) GetDest() []byte {
+	if x != nil {
+		return x.Dest
+	}
+	return nil
+}
+
+type RemoveKnownRouteHintsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RemoveKnownRouteHintsResponse) Reset() {
+	*x = RemoveKnownRouteHintsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveKnownRouteHintsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveKnownRouteHintsResponse) ProtoMessage() {}
+
+func (x *RemoveKnownRouteHintsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveKnownRouteHintsResponse.ProtoReflect.Descriptor instead.
+func (*RemoveKnownRouteHintsResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{59}
+}
+
+type ListKnownRouteHintsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListKnownRouteHintsRequest) Reset() {
+	*x = ListKnownRouteHintsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListKnownRouteHintsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKnownRouteHintsRequest) ProtoMessage() {}
+
+func (x *ListKnownRouteHintsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKnownRouteHintsRequest.ProtoReflect.Descriptor instead.
+func (*ListKnownRouteHintsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{60}
+}
+
+type ListKnownRouteHintsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The registered route hints that haven't expired yet.
+	KnownRouteHints []*KnownRouteHint `protobuf:"bytes,1,rep,name=known_route_hints,json=knownRouteHints,proto3" json:"known_route_hints,omitempty"`
+}
+
+func (x *ListKnownRouteHintsResponse) Reset() {
+	*x = ListKnownRouteHintsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListKnownRouteHintsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKnownRouteHintsResponse) ProtoMessage() {}
+
+func (x *ListKnownRouteHintsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKnownRouteHintsResponse.ProtoReflect.Descriptor instead.
+func (*ListKnownRouteHintsResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListKnownRouteHintsResponse) GetKnownRouteHints() []*KnownRouteHint {
+	if x != nil {
+		return x.KnownRouteHints
+	}
+	return nil
+}
+
+type KnownRouteHint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The public key of the private destination the hints lead to.
+	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
+	// The route hints to the destination.
+	RouteHints []*lnrpc.RouteHint `protobuf:"bytes,2,rep,name=route_hints,json=routeHints,proto3" json:"route_hints,omitempty"`
+	// The payments that may use the hints.
+	Scope RouteHintScope `protobuf:"varint,3,opt,name=scope,proto3,enum=routerrpc.RouteHintScope" json:"scope,omitempty"`
+	// The unix timestamp after which the hints are no longer used.
+	Expiry int64 `protobuf:"varint,4,opt,name=expiry,proto3" json:"expiry,omitempty"`
+}
+
+func (x *KnownRouteHint) Reset() {
+	*x = KnownRouteHint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KnownRouteHint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KnownRouteHint) ProtoMessage() {}
+
+func (x *KnownRouteHint) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KnownRouteHint.ProtoReflect.Descriptor instead.
+func (*KnownRouteHint) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *KnownRouteHint) GetDest() []byte {
+	if x != nil {
+		return x.Dest
+	}
+	return nil
+}
+
+func (x *KnownRouteHint) GetRouteHints() []*lnrpc.RouteHint {
+	if x != nil {
+		return x.RouteHints
+	}
+	return nil
+}
+
+func (x *KnownRouteHint) GetScope() RouteHintScope {
+	if x != nil {
+		return x.Scope
+	}
+	return RouteHintScope_ROUTE_HINT_SCOPE_DESTINATION
+}
+
+func (x *KnownRouteHint) GetExpiry() int64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+type RegisterJITChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The public key of the client to open the channel to.
+	Client []byte `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`
+}
+
+func (x *RegisterJITChannelRequest) Reset() {
+	*x = RegisterJITChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterJITChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterJITChannelRequest) ProtoMessage() {}
+
+func (x *RegisterJITChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterJITChannelRequest.ProtoReflect.Descriptor instead.
+func (*RegisterJITChannelRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RegisterJITChannelRequest) GetClient() []byte {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+type CancelJITChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id of the just-in-time channel to cancel.
+	Scid uint64 `protobuf:"varint,1,opt,name=scid,proto3" json:"scid,omitempty"`
+}
+
+func (x *CancelJITChannelRequest) Reset() {
+	*x = CancelJITChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelJITChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJITChannelRequest) ProtoMessage() {}
+
+func (x *CancelJITChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJITChannelRequest.ProtoReflect.Descriptor instead.
+func (*CancelJITChannelRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *CancelJITChannelRequest) GetScid() uint64 {
+	if x != nil {
+		return x.Scid
+	}
+	return 0
+}
+
+type CancelJITChannelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CancelJITChannelResponse) Reset() {
+	*x = CancelJITChannelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelJITChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJITChannelResponse) ProtoMessage() {}
+
+func (x *CancelJITChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJITChannelResponse.ProtoReflect.Descriptor instead.
+func (*CancelJITChannelResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{65}
+}
+
+type ListJITChannelsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListJITChannelsRequest) Reset() {
+	*x = ListJITChannelsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJITChannelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJITChannelsRequest) ProtoMessage() {}
+
+func (x *ListJITChannelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJITChannelsRequest.ProtoReflect.Descriptor instead.
+func (*ListJITChannelsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{66}
+}
+
+type ListJITChannelsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The registered just-in-time channels.
+	Channels []*JITChannel `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+}
+
+func (x *ListJITChannelsResponse) Reset() {
+	*x = ListJITChannelsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJITChannelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJITChannelsResponse) ProtoMessage() {}
+
+func (x *ListJITChannelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJITChannelsResponse.ProtoReflect.Descriptor instead.
+func (*ListJITChannelsResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListJITChannelsResponse) GetChannels() []*JITChannel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+type JITChannel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id the client puts into the route hints of its
+	// invoices.
+	Scid uint64 `protobuf:"varint,1,opt,name=scid,proto3" json:"scid,omitempty"`
+	// The public key of the client the channel is opened to.
+	Client []byte `protobuf:"bytes,2,opt,name=client,proto3" json:"client,omitempty"`
+	// The unix timestamp the registration expires at if no forward arrived for
+	// it.
+	Expiry int64 `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	// The state of the channel.
+	State JITChannelState `protobuf:"varint,4,opt,name=state,proto3,enum=routerrpc.JITChannelState" json:"state,omitempty"`
+	// The number of forwards held for the channel.
+	HeldForwards uint32 `protobuf:"varint,5,opt,name=held_forwards,json=heldForwards,proto3" json:"held_forwards,omitempty"`
+}
+
+func (x *JITChannel) Reset() {
+	*x = JITChannel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JITChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JITChannel) ProtoMessage() {}
+
+func (x *JITChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JITChannel.ProtoReflect.Descriptor instead.
+func (*JITChannel) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *JITChannel) GetScid() uint64 {
+	if x != nil {
+		return x.Scid
+	}
+	return 0
+}
+
+func (x *JITChannel) GetClient() []byte {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+func (x *JITChannel) GetExpiry() int64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+func (x *JITChannel) GetState() JITChannelState {
+	if x != nil {
+		return x.State
+	}
+	return JITChannelState_JIT_CHANNEL_STATE_WAITING
+}
+
+func (x *JITChannel) GetHeldForwards() uint32 {
+	if x != nil {
+		return x.HeldForwards
+	}
+	return 0
+}
+
+type QueryNetworkResultsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The attempt ID the page starts at.
+	FirstAttemptId uint64 `protobuf:"varint,1,opt,name=first_attempt_id,json=firstAttemptId,proto3" json:"first_attempt_id,omitempty"`
+	// The maximum number of results to return. If zero, all results starting at
+	// the first attempt ID are returned.
+	MaxResults uint32 `protobuf:"varint,2,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+}
+
+func (x *QueryNetworkResultsRequest) Reset() {
+	*x = QueryNetworkResultsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryNetworkResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryNetworkResultsRequest) ProtoMessage() {}
+
+func (x *QueryNetworkResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryNetworkResultsRequest.ProtoReflect.Descriptor instead.
+func (*QueryNetworkResultsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *QueryNetworkResultsRequest) GetFirstAttemptId() uint64 {
+	if x != nil {
+		return x.FirstAttemptId
+	}
+	return 0
+}
+
+func (x *QueryNetworkResultsRequest) GetMaxResults() uint32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+type QueryNetworkResultsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The results of the page, ordered by their attempt ID.
+	Results []*NetworkResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *QueryNetworkResultsResponse) Reset() {
+	*x = QueryNetworkResultsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryNetworkResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryNetworkResultsResponse) ProtoMessage() {}
+
+func (x *QueryNetworkResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryNetworkResultsResponse.ProtoReflect.Descriptor instead.
+func (*QueryNetworkResultsResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *QueryNetworkResultsResponse) GetResults() []*NetworkResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type NetworkResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the payment attempt the result is for.
+	AttemptId uint64 `protobuf:"varint,1,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	// The unix timestamp the result was stored at. It is zero for results
+	// stored before the time was recorded.
+	StoredAt int64 `protobuf:"varint,2,opt,name=stored_at,json=storedAt,proto3" json:"stored_at,omitempty"`
+	// Whether the attempt was settled. If false, the attempt failed.
+	Settled bool `protobuf:"varint,3,opt,name=settled,proto3" json:"settled,omitempty"`
+	// The preimage a settled attempt was settled with.
+	Preimage []byte `protobuf:"bytes,4,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// Whether the failure reason of a failed attempt isn't encrypted, because
+	// the failure happened locally.
+	Unencrypted bool `protobuf:"varint,5,opt,name=unencrypted,proto3" json:"unencrypted,omitempty"`
+	// Whether the result was resolved by a resolution message, which may not
+	// include the failure reason.
+	IsResolution bool `protobuf:"varint,6,opt,name=is_resolution,json=isResolution,proto3" json:"is_resolution,omitempty"`
+}
+
+func (x *NetworkResult) Reset() {
+	*x = NetworkResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkResult) ProtoMessage() {}
+
+func (x *NetworkResult) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkResult.ProtoReflect.Descriptor instead.
+func (*NetworkResult) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *NetworkResult) GetAttemptId() uint64 {
+	if x != nil {
+		return x.AttemptId
+	}
+	return 0
+}
+
+func (x *NetworkResult) GetStoredAt() int64 {
+	if x != nil {
+		return x.StoredAt
+	}
+	return 0
+}
+
+func (x *NetworkResult) GetSettled() bool {
+	if x != nil {
+		return x.Settled
+	}
+	return false
+}
+
+func (x *NetworkResult) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *NetworkResult) GetUnencrypted() bool {
+	if x != nil {
+		return x.Unencrypted
+	}
+	return false
+}
+
+func (x *NetworkResult) GetIsResolution() bool {
+	if x != nil {
+		return x.IsResolution
+	}
+	return false
+}
+
+type PruneNetworkResultsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PruneNetworkResultsRequest) Reset() {
+	*x = PruneNetworkResultsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PruneNetworkResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PruneNetworkResultsRequest) ProtoMessage() {}
+
+func (x *PruneNetworkResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PruneNetworkResultsRequest.ProtoReflect.Descriptor instead.
+func (*PruneNetworkResultsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{72}
+}
+
+type PruneNetworkResultsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of results that were removed.
+	NumPruned uint64 `protobuf:"varint,1,opt,name=num_pruned,json=numPruned,proto3" json:"num_pruned,omitempty"`
+}
+
+func (x *PruneNetworkResultsResponse) Reset() {
+	*x = PruneNetworkResultsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PruneNetworkResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PruneNetworkResultsResponse) ProtoMessage() {}
+
+func (x *PruneNetworkResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PruneNetworkResultsResponse.ProtoReflect.Descriptor instead.
+func (*PruneNetworkResultsResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *PruneNetworkResultsResponse) GetNumPruned() uint64 {
+	if x != nil {
+		return x.NumPruned
+	}
+	return 0
+}
+
+var File_routerrpc_router_proto protoreflect.FileDescriptor
+
+var file_routerrpc_router_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x1a, 0x0f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc2, 0x09, 0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x61, 0x6d, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x61, 0x6d,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6c,
+	0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
+	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x27,
+	0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x12, 0x22, 0x0a, 0x0d, 0x66, 0x65, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x61,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x53, 0x61, 0x74, 0x12, 0x2e, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x42, 0x04,
+	0x18, 0x01, 0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68,
+	0x61, 0x6e, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x6c, 0x74, 0x76, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x68, 0x69, 0x6e,
+	0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x64, 0x0a, 0x13, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x63,
+	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x0b, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x64, 0x65, 0x73, 0x74, 0x43,
+	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x19, 0x0a, 0x08,
+	0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x66, 0x65, 0x65, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0c, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a,
+	0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x73,
+	0x65, 0x6c, 0x66, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x0d, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6c, 0x6e, 0x72,
+	0x70, 0x63, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x69, 0x74, 0x52, 0x0c, 0x64,
+	0x65, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
+	0x61, 0x78, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x6d, 0x61, 0x78, 0x50, 0x61, 0x72, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69,
+	0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68,
+	0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67,
+	0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x13, 0x20,
+	0x03, 0x28, 0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61,
+	0x6e, 0x49, 0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x2d, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f, 0x73,
+	0x68, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x68, 0x61, 0x72, 0x64, 0x53, 0x69,
+	0x7a, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6d, 0x70, 0x18, 0x16, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x70, 0x72, 0x65, 0x66, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x50, 0x72, 0x65, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x31, 0x0a, 0x15, 0x73, 0x68, 0x61, 0x64, 0x6f, 0x77, 0x5f,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x74, 0x76, 0x18, 0x19,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x73, 0x68, 0x61, 0x64, 0x6f, 0x77, 0x52, 0x6f, 0x75, 0x74,
+	0x65, 0x4d, 0x61, 0x78, 0x43, 0x6c, 0x74, 0x76, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x6f, 0x5f, 0x73,
+	0x68, 0x61, 0x64, 0x6f, 0x77, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0d, 0x6e, 0x6f, 0x53, 0x68, 0x61, 0x64, 0x6f, 0x77, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x12, 0x51, 0x0a, 0x14, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x72, 0x61, 0x6e, 0x64, 0x6f,
+	0x6d, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x13,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x7a, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x1a, 0x44, 0x0a, 0x16, 0x44, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73, 0x74, 0x6f,
+	0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe5, 0x01, 0x0a, 0x1c, 0x53, 0x65,
+	0x6e, 0x64, 0x54, 0x72, 0x61, 0x6d, 0x70, 0x6f, 0x6c, 0x69, 0x6e, 0x65, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x07, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6d, 0x70, 0x6f, 0x6c, 0x69, 0x6e,
+	0x65, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72,
+	0x61, 0x6d, 0x70, 0x6f, 0x6c, 0x69, 0x6e, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x0d,
+	0x62, 0x61, 0x73, 0x65, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x65, 0x46, 0x65, 0x65, 0x4d, 0x73, 0x61, 0x74,
+	0x12, 0x20, 0x0a, 0x0c, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x70, 0x6d,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x66, 0x65, 0x65, 0x52, 0x61, 0x74, 0x65, 0x50,
+	0x70, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74,
+	0x61, 0x22, 0x79, 0x0a, 0x17, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x79, 0x6e, 0x63, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x07,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x72,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x68, 0x22, 0x3d, 0x0a, 0x18,
+	0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x79, 0x6e, 0x63, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x68, 0x0a, 0x13, 0x54,
+	0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69, 0x6e, 0x66, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x46, 0x0a, 0x14, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a,
+	0x13, 0x6e, 0x6f, 0x5f, 0x69, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e,
+	0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x39, 0x0a,
+	0x14, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x17, 0x0a, 0x15, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x81, 0x01, 0x0a, 0x0f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x6d, 0x74,
+	0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x74, 0x53,
+	0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0xa8, 0x01, 0x0a, 0x10, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46,
+	0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65,
+	0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74,
+	0x69, 0x6d, 0x65, 0x4c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x42, 0x0a, 0x0e,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x22, 0x67, 0x0a, 0x15, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x05, 0x71, 0x75, 0x65,
+	0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x75,
+	0x6d, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x6e, 0x75, 0x6d, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x22, 0x46, 0x0a, 0x16, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x73, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x73, 0x22, 0xe3, 0x01, 0x0a, 0x09, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x73, 0x12,
+	0x22, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x6f,
+	0x70, 0x46, 0x65, 0x65, 0x73, 0x52, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x66, 0x65, 0x65, 0x73, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x46, 0x65, 0x65, 0x73, 0x4d,
+	0x73, 0x61, 0x74, 0x12, 0x31, 0x0a, 0x15, 0x6f, 0x77, 0x6e, 0x5f, 0x6f, 0x75, 0x74, 0x62, 0x6f,
+	0x75, 0x6e, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x12, 0x6f, 0x77, 0x6e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x46,
+	0x65, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x6f, 0x77, 0x6e, 0x5f, 0x69, 0x6e,
+	0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x6f, 0x77, 0x6e, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64,
+	0x46, 0x65, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x9e, 0x02, 0x0a, 0x07, 0x48, 0x6f, 0x70, 0x46,
+	0x65, 0x65, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x10,
+	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f,
+	0x6d, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x10, 0x6f, 0x75,
+	0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
+	0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x13, 0x61, 0x6d, 0x74, 0x5f,
+	0x74, 0x6f, 0x5f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x61, 0x6d, 0x74, 0x54, 0x6f, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x62, 0x6f,
+	0x75, 0x6e, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x46, 0x65, 0x65, 0x4d,
+	0x73, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x66,
+	0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x69,
+	0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x46, 0x65, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x66, 0x65, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x7f, 0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64,
+	0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73,
+	0x68, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x74, 0x65,
+	0x6d, 0x70, 0x5f, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x6b,
+	0x69, 0x70, 0x54, 0x65, 0x6d, 0x70, 0x45, 0x72, 0x72, 0x22, 0x5b, 0x0a, 0x13, 0x53, 0x65, 0x6e,
+	0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x07,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x07, 0x66,
+	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x1d, 0x0a, 0x1b, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0x51, 0x0a, 0x1b, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2c, 0x0a, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x4a, 0x04,
+	0x08, 0x01, 0x10, 0x02, 0x22, 0x62, 0x0a, 0x1c, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x50, 0x61, 0x69, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x05, 0x70, 0x61, 0x69,
+	0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x58, 0x49, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x8a, 0x01, 0x0a, 0x0b, 0x50, 0x61,
+	0x69, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64,
+	0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6e, 0x6f,
+	0x64, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x74,
+	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x54, 0x6f, 0x12,
+	0x2d, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69,
+	0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x4a, 0x04,
+	0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06,
+	0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x22, 0xe8, 0x01, 0x0a, 0x08, 0x50, 0x61, 0x69, 0x72, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x20, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x61, 0x69, 0x6c, 0x41, 0x6d, 0x74, 0x53,
+	0x61, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d,
+	0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x41,
+	0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0d, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x41, 0x6d, 0x74, 0x53, 0x61,
+	0x74, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x61, 0x6d, 0x74,
+	0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x4a, 0x04, 0x08, 0x03, 0x10,
+	0x04, 0x22, 0x20, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x5a, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22,
+	0x59, 0x0a, 0x1e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x21, 0x0a, 0x1f, 0x53, 0x65,
+	0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x89, 0x04,
+	0x0a, 0x14, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2e, 0x0a, 0x11, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x6c,
+	0x69, 0x66, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0f, 0x68, 0x61, 0x6c, 0x66, 0x4c, 0x69, 0x66, 0x65, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2b, 0x0a, 0x0f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x72,
+	0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x42,
+	0x02, 0x18, 0x01, 0x52, 0x0e, 0x68, 0x6f, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x02, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x36, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x15, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x43, 0x0a, 0x1e, 0x6d, 0x69, 0x6e, 0x69, 0x6d,
+	0x75, 0x6d, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x78,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x1b, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52,
+	0x65, 0x6c, 0x61, 0x78, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x46, 0x0a, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x30, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x6f,
+	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x12, 0x38, 0x0a, 0x07, 0x61, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x41, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x07, 0x61, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x12, 0x38,
+	0x0a, 0x07, 0x62, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x69, 0x6d, 0x6f,
+	0x64, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52,
+	0x07, 0x62, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x22, 0x2c, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x62,
+	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x0b, 0x0a, 0x07,
+	0x41, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x49, 0x4d,
+	0x4f, 0x44, 0x41, 0x4c, 0x10, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x72, 0x0a, 0x11, 0x42, 0x69, 0x6d,
+	0x6f, 0x64, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0a, 0x6e, 0x6f, 0x64, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x64, 0x65, 0x63, 0x61, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x64, 0x65, 0x63, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xad, 0x01,
+	0x0a, 0x11, 0x41, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x6c, 0x69, 0x66, 0x65,
+	0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f,
+	0x68, 0x61, 0x6c, 0x66, 0x4c, 0x69, 0x66, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12,
+	0x27, 0x0a, 0x0f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x68, 0x6f, 0x70, 0x50, 0x72, 0x6f,
+	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x2b, 0x0a, 0x11, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x66, 0x72, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6a, 0x0a,
+	0x17, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x72, 0x6f, 0x6d,
+	0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x66, 0x72, 0x6f,
+	0x6d, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x6f, 0x5f, 0x6e, 0x6f, 0x64, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x6f, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x19,
+	0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x6b, 0x0a, 0x18, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x62,
+	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x2d, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x68,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x81, 0x01, 0x0a, 0x20, 0x43, 0x6f, 0x6d, 0x70, 0x61,
+	0x72, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x05, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72,
+	0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x12,
+	0x39, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x22, 0x49, 0x0a, 0x21, 0x43, 0x6f,
+	0x6d, 0x70, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x24, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xca, 0x01, 0x0a, 0x11, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61,
+	0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61,
+	0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f,
+	0x63, 0x6c, 0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61,
+	0x12, 0x2c, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x0e,
+	0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x0a, 0x68, 0x6f, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x73, 0x12,
+	0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x64,
+	0x64, 0x72, 0x22, 0x38, 0x0a, 0x12, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e,
+	0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22, 0x1c, 0x0a, 0x1a,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x86, 0x06, 0x0a, 0x09, 0x48,
+	0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x63, 0x6f,
+	0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x13, 0x6f, 0x75, 0x74, 0x67,
+	0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6f,
+	0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x48, 0x74, 0x6c, 0x63,
+	0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x68,
+	0x74, 0x6c, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x6f, 0x75,
+	0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x12,
+	0x3d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3e,
+	0x0a, 0x0d, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x0c, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x4b,
+	0x0a, 0x12, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x46, 0x61,
+	0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x66, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3b, 0x0a, 0x0c, 0x73,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x74, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x42, 0x0a, 0x0f, 0x6c, 0x69, 0x6e, 0x6b,
+	0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69,
+	0x6e, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0d, 0x6c,
+	0x69, 0x6e, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x47, 0x0a, 0x10,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x48, 0x00, 0x52, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x45, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x68,
+	0x74, 0x6c, 0x63, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6e, 0x61,
+	0x6c, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x66, 0x69,
+	0x6e, 0x61, 0x6c, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x09,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b,
+	0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x45, 0x4e, 0x44, 0x10, 0x01,
+	0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x0b, 0x0a,
+	0x07, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x03, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x22, 0xbc, 0x01, 0x0a, 0x08, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x2b, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x69, 0x6e, 0x63,
+	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2b, 0x0a,
+	0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f,
+	0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
+	0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2a, 0x0a, 0x11, 0x69, 0x6e,
+	0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x41,
+	0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
+	0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x4d, 0x73,
+	0x61, 0x74, 0x22, 0x37, 0x0a, 0x0c, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c,
+	0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x12, 0x0a, 0x10, 0x46,
+	0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22,
+	0x29, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0x46, 0x0a, 0x0e, 0x46, 0x69,
+	0x6e, 0x61, 0x6c, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x66, 0x66, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x66, 0x66, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x22, 0x11, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xdf, 0x01, 0x0a, 0x0d, 0x4c, 0x69, 0x6e, 0x6b, 0x46, 0x61,
+	0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f,
+	0x12, 0x3d, 0x0a, 0x0c, 0x77, 0x69, 0x72, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46,
+	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x52, 0x0b, 0x77, 0x69, 0x72, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12,
+	0x3f, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x61, 0x69,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69,
+	0x6c, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x12, 0x25, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x22, 0x8a, 0x01, 0x0a, 0x0d, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x68, 0x74, 0x6c, 0x63, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x54, 0x4c, 0x43,
+	0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x52, 0x05, 0x68, 0x74, 0x6c, 0x63, 0x73, 0x4a, 0x04,
+	0x08, 0x03, 0x10, 0x04, 0x22, 0x3e, 0x0a, 0x0a, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b,
+	0x65, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x68,
+	0x74, 0x6c, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68, 0x74,
+	0x6c, 0x63, 0x49, 0x64, 0x22, 0xe9, 0x04, 0x0a, 0x1b, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x47, 0x0a, 0x14, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67,
+	0x5f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43,
+	0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d,
+	0x69, 0x6e, 0x67, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x30, 0x0a,
+	0x14, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x69, 0x6e, 0x63,
+	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12,
+	0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69,
+	0x6e, 0x67, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x3b, 0x0a, 0x1a, 0x6f,
+	0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65,
+	0x64, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x17, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x6f, 0x75, 0x74, 0x67,
+	0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x75,
+	0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x45, 0x78, 0x70,
+	0x69, 0x72, 0x79, 0x12, 0x60, 0x0a, 0x0e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48,
+	0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6e, 0x69, 0x6f, 0x6e, 0x5f, 0x62,
+	0x6c, 0x6f, 0x62, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6f, 0x6e, 0x69, 0x6f, 0x6e,
+	0x42, 0x6c, 0x6f, 0x62, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x66, 0x61, 0x69,
+	0x6c, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
+	0x61, 0x75, 0x74, 0x6f, 0x46, 0x61, 0x69, 0x6c, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x1a, 0x40,
+	0x0a, 0x12, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0xa8, 0x02, 0x0a, 0x1c, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x47, 0x0a, 0x14, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63,
+	0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67,
+	0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x3b, 0x0a, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x48, 0x6f,
+	0x6c, 0x64, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3d, 0x0a, 0x0c,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75,
+	0x72, 0x65, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0b,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x82, 0x01, 0x0a, 0x17,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x5f,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x1a, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x75, 0x0a, 0x14,
+	0x46, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x63,
+	0x68, 0x61, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x46, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4c, 0x0a, 0x16,
+	0x55, 0x6e, 0x66, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52,
+	0x09, 0x63, 0x68, 0x61, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0x19, 0x0a, 0x17, 0x55, 0x6e,
+	0x66, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xb4, 0x01, 0x0a, 0x19, 0x41, 0x64, 0x64, 0x4b, 0x6e, 0x6f,
+	0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x5f, 0x68, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c,
+	0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x52, 0x0a,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x74,
+	0x6c, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0a, 0x74, 0x74, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74,
+	0x53, 0x63, 0x6f, 0x70, 0x65, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x22, 0x1c, 0x0a, 0x1a,
+	0x41, 0x64, 0x64, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x32, 0x0a, 0x1c, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x22, 0x1f,
+	0x0a, 0x1d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x1c, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74,
+	0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x64, 0x0a,
+	0x1b, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48,
+	0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x11,
+	0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x68, 0x69, 0x6e, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69,
+	0x6e, 0x74, 0x52, 0x0f, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69,
+	0x6e, 0x74, 0x73, 0x22, 0xa0, 0x01, 0x0a, 0x0e, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e,
+	0x74, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x2f, 0x0a,
+	0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69,
+	0x6e, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x22, 0x33, 0x0a, 0x19, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x31, 0x0a, 0x17, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x04, 0x73, 0x63, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x04, 0x73, 0x63, 0x69, 0x64, 0x22, 0x1a,
+	0x0a, 0x18, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x18, 0x0a, 0x16, 0x4c, 0x69,
+	0x73, 0x74, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x4c, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x49, 0x54, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x31, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4a, 0x49,
+	0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x22, 0xab, 0x01, 0x0a, 0x0a, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x12, 0x16, 0x0a, 0x04, 0x73, 0x63, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42,
+	0x02, 0x30, 0x01, 0x52, 0x04, 0x73, 0x63, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x68,
+	0x65, 0x6c, 0x64, 0x5f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0c, 0x68, 0x65, 0x6c, 0x64, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73,
+	0x22, 0x67, 0x0a, 0x1a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28,
+	0x0a, 0x10, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x66, 0x69, 0x72, 0x73, 0x74, 0x41,
+	0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x6d,
+	0x61, 0x78, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x51, 0x0a, 0x1b, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0xc8, 0x01, 0x0a,
+	0x0d, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x41, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65,
+	0x74, 0x74, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x75, 0x6e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x65, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x73, 0x52, 0x65, 0x73,
+	0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x1c, 0x0a, 0x1a, 0x50, 0x72, 0x75, 0x6e, 0x65,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3c, 0x0a, 0x1b, 0x50, 0x72, 0x75, 0x6e, 0x65, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x75, 0x6d, 0x5f, 0x70, 0x72, 0x75, 0x6e,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6e, 0x75, 0x6d, 0x50, 0x72, 0x75,
+	0x6e, 0x65, 0x64, 0x2a, 0x76, 0x0a, 0x13, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x61, 0x6e,
+	0x64, 0x6f, 0x6d, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x1c, 0x41, 0x4d,
+	0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x52, 0x41, 0x4e, 0x44, 0x4f, 0x4d, 0x49, 0x5a, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19,
+	0x41, 0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x52, 0x41, 0x4e, 0x44, 0x4f, 0x4d, 0x49, 0x5a, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x41,
+	0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x52, 0x41, 0x4e, 0x44, 0x4f, 0x4d, 0x49, 0x5a, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x48, 0x41, 0x52, 0x44, 0x10, 0x02, 0x2a, 0x81, 0x04, 0x0a, 0x0d,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x0b, 0x0a,
+	0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f,
+	0x5f, 0x44, 0x45, 0x54, 0x41, 0x49, 0x4c, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x4e, 0x49,
+	0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x4c,
+	0x49, 0x4e, 0x4b, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x4c, 0x49, 0x47, 0x49, 0x42, 0x4c, 0x45,
+	0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x4f, 0x4e, 0x5f, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x5f, 0x54,
+	0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x48, 0x54, 0x4c, 0x43,
+	0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x53, 0x5f, 0x4d, 0x41, 0x58, 0x10, 0x05, 0x12, 0x18,
+	0x0a, 0x14, 0x49, 0x4e, 0x53, 0x55, 0x46, 0x46, 0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42,
+	0x41, 0x4c, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x06, 0x12, 0x16, 0x0a, 0x12, 0x49, 0x4e, 0x43, 0x4f,
+	0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x07,
+	0x12, 0x13, 0x0a, 0x0f, 0x48, 0x54, 0x4c, 0x43, 0x5f, 0x41, 0x44, 0x44, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x10, 0x08, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44,
+	0x53, 0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x09, 0x12, 0x14, 0x0a, 0x10,
+	0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44,
+	0x10, 0x0a, 0x12, 0x15, 0x0a, 0x11, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e,
+	0x44, 0x45, 0x52, 0x50, 0x41, 0x49, 0x44, 0x10, 0x0b, 0x12, 0x1b, 0x0a, 0x17, 0x49, 0x4e, 0x56,
+	0x4f, 0x49, 0x43, 0x45, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x59, 0x5f, 0x54, 0x4f, 0x4f, 0x5f,
+	0x53, 0x4f, 0x4f, 0x4e, 0x10, 0x0c, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43,
+	0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x0d, 0x12, 0x17, 0x0a, 0x13,
+	0x4d, 0x50, 0x50, 0x5f, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x54, 0x49, 0x4d, 0x45,
+	0x4f, 0x55, 0x54, 0x10, 0x0e, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x44, 0x44, 0x52, 0x45, 0x53, 0x53,
+	0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x0f, 0x12, 0x16, 0x0a, 0x12, 0x53,
+	0x45, 0x54, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43,
+	0x48, 0x10, 0x10, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x54, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c,
+	0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x11, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45,
+	0x54, 0x5f, 0x4f, 0x56, 0x45, 0x52, 0x50, 0x41, 0x49, 0x44, 0x10, 0x12, 0x12, 0x13, 0x0a, 0x0f,
+	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x10,
+	0x13, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4b, 0x45, 0x59,
+	0x53, 0x45, 0x4e, 0x44, 0x10, 0x14, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x50, 0x50, 0x5f, 0x49, 0x4e,
+	0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x15, 0x12, 0x12, 0x0a, 0x0e, 0x43,
+	0x49, 0x52, 0x43, 0x55, 0x4c, 0x41, 0x52, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x10, 0x16, 0x2a,
+	0xae, 0x01, 0x0a, 0x0c, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x5f, 0x46, 0x4c, 0x49, 0x47, 0x48, 0x54, 0x10, 0x00, 0x12,
+	0x0d, 0x0a, 0x09, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12,
+	0x0a, 0x0e, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54,
+	0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x4e, 0x4f, 0x5f,
+	0x52, 0x4f, 0x55, 0x54, 0x45, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x41, 0x49, 0x4c, 0x45,
+	0x44, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x04, 0x12, 0x24, 0x0a, 0x20, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x5f, 0x49, 0x4e, 0x43, 0x4f, 0x52, 0x52, 0x45, 0x43, 0x54, 0x5f, 0x50, 0x41,
+	0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x44, 0x45, 0x54, 0x41, 0x49, 0x4c, 0x53, 0x10, 0x05, 0x12,
+	0x1f, 0x0a, 0x1b, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x49, 0x4e, 0x53, 0x55, 0x46, 0x46,
+	0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42, 0x41, 0x4c, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x06,
+	0x2a, 0x3c, 0x0a, 0x18, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x46,
+	0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06,
+	0x53, 0x45, 0x54, 0x54, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x41, 0x49, 0x4c,
+	0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x45, 0x10, 0x02, 0x2a, 0x35,
+	0x0a, 0x10, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x4e, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x0b,
+	0x0a, 0x07, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x41,
+	0x55, 0x54, 0x4f, 0x10, 0x02, 0x2a, 0x4c, 0x0a, 0x0e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69,
+	0x6e, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x52, 0x4f, 0x55, 0x54, 0x45,
+	0x5f, 0x48, 0x49, 0x4e, 0x54, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f, 0x44, 0x45, 0x53, 0x54,
+	0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x4f, 0x55,
+	0x54, 0x45, 0x5f, 0x48, 0x49, 0x4e, 0x54, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f, 0x41, 0x4c,
+	0x4c, 0x10, 0x01, 0x2a, 0x4f, 0x0a, 0x0f, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x19, 0x4a, 0x49, 0x54, 0x5f, 0x43, 0x48,
+	0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x57, 0x41, 0x49, 0x54,
+	0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x4a, 0x49, 0x54, 0x5f, 0x43, 0x48, 0x41,
+	0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x49,
+	0x4e, 0x47, 0x10, 0x01, 0x32, 0xc2, 0x17, 0x0a, 0x06, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x12,
+	0x40, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x56, 0x32,
+	0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e,
+	0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30,
+	0x01, 0x12, 0x52, 0x0a, 0x15, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x72, 0x61, 0x6d, 0x70, 0x6f, 0x6c,
+	0x69, 0x6e, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x72, 0x61, 0x6d, 0x70,
+	0x6f, 0x6c, 0x69, 0x6e, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x5b, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x79,
+	0x6e, 0x63, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x79, 0x6e, 0x63, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73,
+	0x79, 0x6e, 0x63, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x42, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x56, 0x32, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x52, 0x0a, 0x0d, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b,
+	0x0a, 0x10, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46,
+	0x65, 0x65, 0x12, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x0e, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x73, 0x12, 0x20, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x21, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x51, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74,
+	0x65, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e,
+	0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x03, 0x88, 0x02, 0x01, 0x12, 0x42, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x56, 0x32, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x54,
+	0x4c, 0x43, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x64, 0x0a, 0x13, 0x52, 0x65, 0x73,
+	0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73,
+	0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x64, 0x0a, 0x13, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6a, 0x0a, 0x15, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74,
+	0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x27,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x58, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x70, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x4d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72,
+	0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50,
+	0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x76, 0x0a, 0x19, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x2b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6d, 0x70,
+	0x61, 0x72, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65,
+	0x52, 0x6f, 0x75, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0a, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x4d, 0x0a, 0x0b, 0x53,
+	0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x22, 0x03, 0x88, 0x02, 0x01, 0x30, 0x01, 0x12, 0x4f, 0x0a, 0x0c, 0x54, 0x72,
+	0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x03, 0x88, 0x02, 0x01, 0x30, 0x01, 0x12, 0x66, 0x0a, 0x0f, 0x48,
+	0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x27,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x28,
+	0x01, 0x30, 0x01, 0x12, 0x5b, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68,
+	0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x52, 0x0a, 0x0d, 0x46, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x12, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x72,
+	0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46,
+	0x72, 0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x0f, 0x55, 0x6e, 0x66, 0x72, 0x65, 0x65, 0x7a, 0x65,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x21, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6e, 0x66, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6e, 0x66, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61,
+	0x0a, 0x12, 0x41, 0x64, 0x64, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48,
+	0x69, 0x6e, 0x74, 0x73, 0x12, 0x24, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x41, 0x64, 0x64, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x6a, 0x0a, 0x15, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x6e, 0x6f, 0x77, 0x6e,
+	0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x27, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x6e, 0x6f,
+	0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a,
+	0x13, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48,
+	0x69, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48,
+	0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x6e, 0x6f, 0x77,
+	0x6e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x12, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4a,
+	0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x24, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4a, 0x49,
+	0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4a, 0x49, 0x54, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x5b, 0x0a, 0x10, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
+	0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a, 0x49, 0x54,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x49, 0x54, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x21, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x49, 0x54, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a,
+	0x13, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a, 0x13, 0x50, 0x72, 0x75, 0x6e, 0x65, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x75, 0x6e, 0x65, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72,
+	0x75, 0x6e, 0x65, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e,
+	0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6c, 0x6e, 0x64, 0x2f, 0x6c, 0x6e, 0x72,
+	0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_routerrpc_router_proto_rawDescOnce sync.Once
+	file_routerrpc_router_proto_rawDescData = file_routerrpc_router_proto_rawDesc
+)
+
+func file_routerrpc_router_proto_rawDescGZIP() []byte {
+	file_routerrpc_router_proto_rawDescOnce.Do(func() {
+		file_routerrpc_router_proto_rawDescData = protoimpl.X.CompressGZIP(file_routerrpc_router_proto_rawDescData)
+	})
+	return file_routerrpc_router_proto_rawDescData
+}
+
+var file_routerrpc_router_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_routerrpc_router_proto_msgTypes = make([]protoimpl.MessageInfo, 76)
+var file_routerrpc_router_proto_goTypes = []interface{}{
+	(AmountRandomization)(0),                   // 0: routerrpc.AmountRandomization
+	(FailureDetail)(0),                         // 1: routerrpc.FailureDetail
+	(PaymentState)(0),                          // 2: routerrpc.PaymentState
+	(ResolveHoldForwardAction)(0),              // 3: routerrpc.ResolveHoldForwardAction
+	(ChanStatusAction)(0),                      // 4: routerrpc.ChanStatusAction
+	(RouteHintScope)(0),                        // 5: routerrpc.RouteHintScope
+	(JITChannelState)(0),                       // 6: routerrpc.JITChannelState
+	(MissionControlConfig_ProbabilityModel)(0), // 7: routerrpc.MissionControlConfig.ProbabilityModel
+	(HtlcEvent_EventType)(0),                   // 8: routerrpc.HtlcEvent.EventType
+	(*SendPaymentRequest)(nil),                 // 9: routerrpc.SendPaymentRequest
+	(*SendTrampolinePaymentRequest)(nil),       // 10: routerrpc.SendTrampolinePaymentRequest
+	(*SendAsyncPaymentRequest)(nil),            // 11: routerrpc.SendAsyncPaymentRequest
+	(*SendAsyncPaymentResponse)(nil),           // 12: routerrpc.SendAsyncPaymentResponse
+	(*TrackPaymentRequest)(nil),                // 13: routerrpc.TrackPaymentRequest
+	(*TrackPaymentsRequest)(nil),               // 14: routerrpc.TrackPaymentsRequest
+	(*CancelPaymentRequest)(nil),               // 15: routerrpc.CancelPaymentRequest
+	(*CancelPaymentResponse)(nil),              // 16: routerrpc.CancelPaymentResponse
+	(*RouteFeeRequest)(nil),                    // 17: routerrpc.RouteFeeRequest
+	(*RouteFeeResponse)(nil),                   // 18: routerrpc.RouteFeeResponse
+	(*QueryRouteFeesRequest)(nil),              // 19: routerrpc.QueryRouteFeesRequest
+	(*QueryRouteFeesResponse)(nil),             // 20: routerrpc.QueryRouteFeesResponse
+	(*RouteFees)(nil),                          // 21: routerrpc.RouteFees
+	(*HopFees)(nil),                            // 22: routerrpc.HopFees
+	(*SendToRouteRequest)(nil),                 // 23: routerrpc.SendToRouteRequest
+	(*SendToRouteResponse)(nil),                // 24: routerrpc.SendToRouteResponse
+	(*ResetMissionControlRequest)(nil),         // 25: routerrpc.ResetMissionControlRequest
+	(*ResetMissionControlResponse)(nil),        // 26: routerrpc.ResetMissionControlResponse
+	(*QueryMissionControlRequest)(nil),         // 27: routerrpc.QueryMissionControlRequest
+	(*QueryMissionControlResponse)(nil),        // 28: routerrpc.QueryMissionControlResponse
+	(*XImportMissionControlRequest)(nil),       // 29: routerrpc.XImportMissionControlRequest
+	(*XImportMissionControlResponse)(nil),      // 30: routerrpc.XImportMissionControlResponse
+	(*PairHistory)(nil),                        // 31: routerrpc.PairHistory
+	(*PairData)(nil),                           // 32: routerrpc.PairData
+	(*GetMissionControlConfigRequest)(nil),     // 33: routerrpc.GetMissionControlConfigRequest
+	(*GetMissionControlConfigResponse)(nil),    // 34: routerrpc.GetMissionControlConfigResponse
+	(*SetMissionControlConfigRequest)(nil),     // 35: routerrpc.SetMissionControlConfigRequest
+	(*SetMissionControlConfigResponse)(nil),    // 36: routerrpc.SetMissionControlConfigResponse
+	(*MissionControlConfig)(nil),               // 37: routerrpc.MissionControlConfig
+	(*BimodalParameters)(nil),                  // 38: routerrpc.BimodalParameters
+	(*AprioriParameters)(nil),                  // 39: routerrpc.AprioriParameters
+	(*QueryProbabilityRequest)(nil),            // 40: routerrpc.QueryProbabilityRequest
+	(*QueryProbabilityResponse)(nil),           // 41: routerrpc.QueryProbabilityResponse
+	(*CompareRouteProbabilitiesRequest)(nil),   // 42: routerrpc.CompareRouteProbabilitiesRequest
+	(*CompareRouteProbabilitiesResponse)(nil),  // 43: routerrpc.CompareRouteProbabilitiesResponse
+	(*BuildRouteRequest)(nil),                  // 44: routerrpc.BuildRouteRequest
+	(*BuildRouteResponse)(nil),                 // 45: routerrpc.BuildRouteResponse
+	(*SubscribeHtlcEventsRequest)(nil),         // 46: routerrpc.SubscribeHtlcEventsRequest
+	(*HtlcEvent)(nil),                          // 47: routerrpc.HtlcEvent
+	(*HtlcInfo)(nil),                           // 48: routerrpc.HtlcInfo
+	(*ForwardEvent)(nil),                       // 49: routerrpc.ForwardEvent
+	(*ForwardFailEvent)(nil),                   // 50: routerrpc.ForwardFailEvent
+	(*SettleEvent)(nil),                        // 51: routerrpc.SettleEvent
+	(*FinalHtlcEvent)(nil),                     // 52: routerrpc.FinalHtlcEvent
+	(*SubscribedEvent)(nil),                    // 53: routerrpc.SubscribedEvent
+	(*LinkFailEvent)(nil),                      // 54: routerrpc.LinkFailEvent
+	(*PaymentStatus)(nil),                      // 55: routerrpc.PaymentStatus
+	(*CircuitKey)(nil),                         // 56: routerrpc.CircuitKey
+	(*ForwardHtlcInterceptRequest)(nil),        // 57: routerrpc.ForwardHtlcInterceptRequest
+	(*ForwardHtlcInterceptResponse)(nil),       // 58: routerrpc.ForwardHtlcInterceptResponse
+	(*UpdateChanStatusRequest)(nil),            // 59: routerrpc.UpdateChanStatusRequest
+	(*UpdateChanStatusResponse)(nil),           // 60: routerrpc.UpdateChanStatusResponse
+	(*FreezeChannelRequest)(nil),               // 61: routerrpc.FreezeChannelRequest
+	(*FreezeChannelResponse)(nil),              // 62: routerrpc.FreezeChannelResponse
+	(*UnfreezeChannelRequest)(nil),             // 63: routerrpc.UnfreezeChannelRequest
+	(*UnfreezeChannelResponse)(nil),            // 64: routerrpc.UnfreezeChannelResponse
+	(*AddKnownRouteHintsRequest)(nil),          // 65: routerrpc.AddKnownRouteHintsRequest
+	(*AddKnownRouteHintsResponse)(nil),         // 66: routerrpc.AddKnownRouteHintsResponse
+	(*RemoveKnownRouteHintsRequest)(nil),       // 67: routerrpc.RemoveKnownRouteHintsRequest
+	(*RemoveKnownRouteHintsResponse)(nil),      // 68: routerrpc.RemoveKnownRouteHintsResponse
+	(*ListKnownRouteHintsRequest)(nil),         // 69: routerrpc.ListKnownRouteHintsRequest
+	(*ListKnownRouteHintsResponse)(nil),        // 70: routerrpc.ListKnownRouteHintsResponse
+	(*KnownRouteHint)(nil),                     // 71: routerrpc.KnownRouteHint
+	(*RegisterJITChannelRequest)(nil),          // 72: routerrpc.RegisterJITChannelRequest
+	(*CancelJITChannelRequest)(nil),            // 73: routerrpc.CancelJITChannelRequest
+	(*CancelJITChannelResponse)(nil),           // 74: routerrpc.CancelJITChannelResponse
+	(*ListJITChannelsRequest)(nil),             // 75: routerrpc.ListJITChannelsRequest
+	(*ListJITChannelsResponse)(nil),            // 76: routerrpc.ListJITChannelsResponse
+	(*JITChannel)(nil),                         // 77: routerrpc.JITChannel
+	(*QueryNetworkResultsRequest)(nil),         // 78: routerrpc.QueryNetworkResultsRequest
+	(*QueryNetworkResultsResponse)(nil),        // 79: routerrpc.QueryNetworkResultsResponse
+	(*NetworkResult)(nil),                      // 80: routerrpc.NetworkResult
+	(*PruneNetworkResultsRequest)(nil),         // 81: routerrpc.PruneNetworkResultsRequest
+	(*PruneNetworkResultsResponse)(nil),        // 82: routerrpc.PruneNetworkResultsResponse
+	nil,                                        // 83: routerrpc.SendPaymentRequest.DestCustomRecordsEntry
+	nil,                                        // 84: routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
+	(*lnrpc.RouteHint)(nil),                    // 85: lnrpc.RouteHint
+	(lnrpc.FeatureBit)(0),                      // 86: lnrpc.FeatureBit
+	(lnrpc.PaymentFailureReason)(0),            // 87: lnrpc.PaymentFailureReason
+	(*lnrpc.QueryRoutesRequest)(nil),           // 88: lnrpc.QueryRoutesRequest
+	(*lnrpc.Route)(nil),                        // 89: lnrpc.Route
+	(*lnrpc.Failure)(nil),                      // 90: lnrpc.Failure
+	(lnrpc.Failure_FailureCode)(0),             // 91: lnrpc.Failure.FailureCode
+	(*lnrpc.HTLCAttempt)(nil),                  // 92: lnrpc.HTLCAttempt
+	(*lnrpc.ChannelPoint)(nil),                 // 93: lnrpc.ChannelPoint
+	(*lnrpc.Payment)(nil),                      // 94: lnrpc.Payment
+}
+var file_routerrpc_router_proto_depIdxs = []int32{
+	85, // 0: routerrpc.SendPaymentRequest.route_hints:type_name -> lnrpc.RouteHint
+	83, // 1: routerrpc.SendPaymentRequest.dest_custom_records:type_name -> routerrpc.SendPaymentRequest.DestCustomRecordsEntry
+	86, // 2: routerrpc.SendPaymentRequest.dest_features:type_name -> lnrpc.FeatureBit
+	0,  // 3: routerrpc.SendPaymentRequest.amount_randomization:type_name -> routerrpc.AmountRandomization
+	9,  // 4: routerrpc.SendTrampolinePaymentRequest.payment:type_name -> routerrpc.SendPaymentRequest
+	9,  // 5: routerrpc.SendAsyncPaymentRequest.payment:type_name -> routerrpc.SendPaymentRequest
+	87, // 6: routerrpc.RouteFeeResponse.failure_reason:type_name -> lnrpc.PaymentFailureReason
+	88, // 7: routerrpc.QueryRouteFeesRequest.query:type_name -> lnrpc.QueryRoutesRequest
+	21, // 8: routerrpc.QueryRouteFeesResponse.routes:type_name -> routerrpc.RouteFees
+	89, // 9: routerrpc.RouteFees.route:type_name -> lnrpc.Route
+	22, // 10: routerrpc.RouteFees.hops:type_name -> routerrpc.HopFees
+	89, // 11: routerrpc.SendToRouteRequest.route:type_name -> lnrpc.Route
+	90, // 12: routerrpc.SendToRouteResponse.failure:type_name -> lnrpc.Failure
+	31, // 13: routerrpc.QueryMissionControlResponse.pairs:type_name -> routerrpc.PairHistory
+	31, // 14: routerrpc.XImportMissionControlRequest.pairs:type_name -> routerrpc.PairHistory
+	32, // 15: routerrpc.PairHistory.history:type_name -> routerrpc.PairData
+	37, // 16: routerrpc.GetMissionControlConfigResponse.config:type_name -> routerrpc.MissionControlConfig
+	37, // 17: routerrpc.SetMissionControlConfigRequest.config:type_name -> routerrpc.MissionControlConfig
+	7,  // 18: routerrpc.MissionControlConfig.model:type_name -> routerrpc.MissionControlConfig.ProbabilityModel
+	39, // 19: routerrpc.MissionControlConfig.apriori:type_name -> routerrpc.AprioriParameters
+	38, // 20: routerrpc.MissionControlConfig.bimodal:type_name -> routerrpc.BimodalParameters
+	32, // 21: routerrpc.QueryProbabilityResponse.history:type_name -> routerrpc.PairData
+	89, // 22: routerrpc.CompareRouteProbabilitiesRequest.route:type_name -> lnrpc.Route
+	37, // 23: routerrpc.CompareRouteProbabilitiesRequest.configs:type_name -> routerrpc.MissionControlConfig
+	89, // 24: routerrpc.BuildRouteResponse.route:type_name -> lnrpc.Route
+	8,  // 25: routerrpc.HtlcEvent.event_type:type_name -> routerrpc.HtlcEvent.EventType
+	49, // 26: routerrpc.HtlcEvent.forward_event:type_name -> routerrpc.ForwardEvent
+	50, // 27: routerrpc.HtlcEvent.forward_fail_event:type_name -> routerrpc.ForwardFailEvent
+	51, // 28: routerrpc.HtlcEvent.settle_event:type_name -> routerrpc.SettleEvent
+	54, // 29: routerrpc.HtlcEvent.link_fail_event:type_name -> routerrpc.LinkFailEvent
+	53, // 30: routerrpc.HtlcEvent.subscribed_event:type_name -> routerrpc.SubscribedEvent
+	52, // 31: routerrpc.HtlcEvent.final_htlc_event:type_name -> routerrpc.FinalHtlcEvent
+	48, // 32: routerrpc.ForwardEvent.info:type_name -> routerrpc.HtlcInfo
+	48, // 33: routerrpc.LinkFailEvent.info:type_name -> routerrpc.HtlcInfo
+	91, // 34: routerrpc.LinkFailEvent.wire_failure:type_name -> lnrpc.Failure.FailureCode
+	1,  // 35: routerrpc.LinkFailEvent.failure_detail:type_name -> routerrpc.FailureDetail
+	2,  // 36: routerrpc.PaymentStatus.state:type_name -> routerrpc.PaymentState
+	92, // 37: routerrpc.PaymentStatus.htlcs:type_name -> lnrpc.HTLCAttempt
+	56, // 38: routerrpc.ForwardHtlcInterceptRequest.incoming_circuit_key:type_name -> routerrpc.CircuitKey
+	84, // 39: routerrpc.ForwardHtlcInterceptRequest.custom_records:type_name -> routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
+	56, // 40: routerrpc.ForwardHtlcInterceptResponse.incoming_circuit_key:type_name -> routerrpc.CircuitKey
+	3,  // 41: routerrpc.ForwardHtlcInterceptResponse.action:type_name -> routerrpc.ResolveHoldForwardAction
+	91, // 42: routerrpc.ForwardHtlcInterceptResponse.failure_code:type_name -> lnrpc.Failure.FailureCode
+	93, // 43: routerrpc.UpdateChanStatusRequest.chan_point:type_name -> lnrpc.ChannelPoint
+	4,  // 44: routerrpc.UpdateChanStatusRequest.action:type_name -> routerrpc.ChanStatusAction
+	93, // 45: routerrpc.FreezeChannelRequest.chan_point:type_name -> lnrpc.ChannelPoint
+	93, // 46: routerrpc.UnfreezeChannelRequest.chan_point:type_name -> lnrpc.ChannelPoint
+	85, // 47: routerrpc.AddKnownRouteHintsRequest.route_hints:type_name -> lnrpc.RouteHint
+	5,  // 48: routerrpc.AddKnownRouteHintsRequest.scope:type_name -> routerrpc.RouteHintScope
+	71, // 49: routerrpc.ListKnownRouteHintsResponse.known_route_hints:type_name -> routerrpc.KnownRouteHint
+	85, // 50: routerrpc.KnownRouteHint.route_hints:type_name -> lnrpc.RouteHint
+	5,  // 51: routerrpc.KnownRouteHint.scope:type_name -> routerrpc.RouteHintScope
+	77, // 52: routerrpc.ListJITChannelsResponse.channels:type_name -> routerrpc.JITChannel
+	6,  // 53: routerrpc.JITChannel.state:type_name -> routerrpc.JITChannelState
+	80, // 54: routerrpc.QueryNetworkResultsResponse.results:type_name -> routerrpc.NetworkResult
+	9,  // 55: routerrpc.Router.SendPaymentV2:input_type -> routerrpc.SendPaymentRequest
+	10, // 56: routerrpc.Router.SendTrampolinePayment:input_type -> routerrpc.SendTrampolinePaymentRequest
+	11, // 57: routerrpc.Router.SendAsyncPayment:input_type -> routerrpc.SendAsyncPaymentRequest
+	13, // 58: routerrpc.Router.TrackPaymentV2:input_type -> routerrpc.TrackPaymentRequest
+	14, // 59: routerrpc.Router.TrackPayments:input_type -> routerrpc.TrackPaymentsRequest
+	15, // 60: routerrpc.Router.CancelPayment:input_type -> routerrpc.CancelPaymentRequest
+	17, // 61: routerrpc.Router.EstimateRouteFee:input_type -> routerrpc.RouteFeeRequest
+	19, // 62: routerrpc.Router.QueryRouteFees:input_type -> routerrpc.QueryRouteFeesRequest
+	23, // 63: routerrpc.Router.SendToRoute:input_type -> routerrpc.SendToRouteRequest
+	23, // 64: routerrpc.Router.SendToRouteV2:input_type -> routerrpc.SendToRouteRequest
+	25, // 65: routerrpc.Router.ResetMissionControl:input_type -> routerrpc.ResetMissionControlRequest
+	27, // 66: routerrpc.Router.QueryMissionControl:input_type -> routerrpc.QueryMissionControlRequest
+	29, // 67: routerrpc.Router.XImportMissionControl:input_type -> routerrpc.XImportMissionControlRequest
+	33, // 68: routerrpc.Router.GetMissionControlConfig:input_type -> routerrpc.GetMissionControlConfigRequest
+	35, // 69: routerrpc.Router.SetMissionControlConfig:input_type -> routerrpc.SetMissionControlConfigRequest
+	40, // 70: routerrpc.Router.QueryProbability:input_type -> routerrpc.QueryProbabilityRequest
+	42, // 71: routerrpc.Router.CompareRouteProbabilities:input_type -> routerrpc.CompareRouteProbabilitiesRequest
+	44, // 72: routerrpc.Router.BuildRoute:input_type -> routerrpc.BuildRouteRequest
+	46, // 73: routerrpc.Router.SubscribeHtlcEvents:input_type -> routerrpc.SubscribeHtlcEventsRequest
+	9,  // 74: routerrpc.Router.SendPayment:input_type -> routerrpc.SendPaymentRequest
+	13, // 75: routerrpc.Router.TrackPayment:input_type -> routerrpc.TrackPaymentRequest
+	58, // 76: routerrpc.Router.HtlcInterceptor:input_type -> routerrpc.ForwardHtlcInterceptResponse
+	59, // 77: routerrpc.Router.UpdateChanStatus:input_type -> routerrpc.UpdateChanStatusRequest
+	61, // 78: routerrpc.Router.FreezeChannel:input_type -> routerrpc.FreezeChannelRequest
+	63, // 79: routerrpc.Router.UnfreezeChannel:input_type -> routerrpc.UnfreezeChannelRequest
+	65, // 80: routerrpc.Router.AddKnownRouteHints:input_type -> routerrpc.AddKnownRouteHintsRequest
+	67, // 81: routerrpc.Router.RemoveKnownRouteHints:input_type -> routerrpc.RemoveKnownRouteHintsRequest
+	69, // 82: routerrpc.Router.ListKnownRouteHints:input_type -> routerrpc.ListKnownRouteHintsRequest
+	72, // 83: routerrpc.Router.RegisterJITChannel:input_type -> routerrpc.RegisterJITChannelRequest
+	73, // 84: routerrpc.Router.CancelJITChannel:input_type -> routerrpc.CancelJITChannelRequest
+	75, // 85: routerrpc.Router.ListJITChannels:input_type -> routerrpc.ListJITChannelsRequest
+	78, // 86: routerrpc.Router.QueryNetworkResults:input_type -> routerrpc.QueryNetworkResultsRequest
+	81, // 87: routerrpc.Router.PruneNetworkResults:input_type -> routerrpc.PruneNetworkResultsRequest
+	94, // 88: routerrpc.Router.SendPaymentV2:output_type -> lnrpc.Payment
+	94, // 89: routerrpc.Router.SendTrampolinePayment:output_type -> lnrpc.Payment
+	12, // 90: routerrpc.Router.SendAsyncPayment:output_type -> routerrpc.SendAsyncPaymentResponse
+	94, // 91: routerrpc.Router.TrackPaymentV2:output_type -> lnrpc.Payment
+	94, // 92: routerrpc.Router.TrackPayments:output_type -> lnrpc.Payment
+	16, // 93: routerrpc.Router.CancelPayment:output_type -> routerrpc.CancelPaymentResponse
+	18, // 94: routerrpc.Router.EstimateRouteFee:output_type -> routerrpc.RouteFeeResponse
+	20, // 95: routerrpc.Router.QueryRouteFees:output_type -> routerrpc.QueryRouteFeesResponse
+	24, // 96: routerrpc.Router.SendToRoute:output_type -> routerrpc.SendToRouteResponse
+	92, // 97: routerrpc.Router.SendToRouteV2:output_type -> lnrpc.HTLCAttempt
+	26, // 98: routerrpc.Router.ResetMissionControl:output_type -> routerrpc.ResetMissionControlResponse
+	28, // 99: routerrpc.Router.QueryMissionControl:output_type -> routerrpc.QueryMissionControlResponse
+	30, // 100: routerrpc.Router.XImportMissionControl:output_type -> routerrpc.XImportMissionControlResponse
+	34, // 101: routerrpc.Router.GetMissionControlConfig:output_type -> routerrpc.GetMissionControlConfigResponse
+	36, // 102: routerrpc.Router.SetMissionControlConfig:output_type -> routerrpc.SetMissionControlConfigResponse
+	41, // 103: routerrpc.Router.QueryProbability:output_type -> routerrpc.QueryProbabilityResponse
+	43, // 104: routerrpc.Router.CompareRouteProbabilities:output_type -> routerrpc.CompareRouteProbabilitiesResponse
+	45, // 105: routerrpc.Router.BuildRoute:output_type -> routerrpc.BuildRouteResponse
+	47, // 106: routerrpc.Router.SubscribeHtlcEvents:output_type -> routerrpc.HtlcEvent
+	55, // 107: routerrpc.Router.SendPayment:output_type -> routerrpc.PaymentStatus
+	55, // 108: routerrpc.Router.TrackPayment:output_type -> routerrpc.PaymentStatus
+	57, // 109: routerrpc.Router.HtlcInterceptor:output_type -> routerrpc.ForwardHtlcInterceptRequest
+	60, // 110: routerrpc.Router.UpdateChanStatus:output_type -> routerrpc.UpdateChanStatusResponse
+	62, // 111: routerrpc.Router.FreezeChannel:output_type -> routerrpc.FreezeChannelResponse
+	64, // 112: routerrpc.Router.UnfreezeChannel:output_type -> routerrpc.UnfreezeChannelResponse
+	66, // 113: routerrpc.Router.AddKnownRouteHints:output_type -> routerrpc.AddKnownRouteHintsResponse
+	68, // 114: routerrpc.Router.RemoveKnownRouteHints:output_type -> routerrpc.RemoveKnownRouteHintsResponse
+	70, // 115: routerrpc.Router.ListKnownRouteHints:output_type -> routerrpc.ListKnownRouteHintsResponse
+	77, // 116: routerrpc.Router.RegisterJITChannel:output_type -> routerrpc.JITChannel
+	74, // 117: routerrpc.Router.CancelJITChannel:output_type -> routerrpc.CancelJITChannelResponse
+	76, // 118: routerrpc.Router.ListJITChannels:output_type -> routerrpc.ListJITChannelsResponse
+	79, // 119: routerrpc.Router.QueryNetworkResults:output_type -> routerrpc.QueryNetworkResultsResponse
+	82, // 120: routerrpc.Router.PruneNetworkResults:output_type -> routerrpc.PruneNetworkResultsResponse
+	88, // [88:121] is the sub-list for method output_type
+	55, // [55:88] is the sub-list for method input_type
+	55, // [55:55] is the sub-list for extension type_name
+	55, // [55:55] is the sub-list for extension extendee
+	0,  // [0:55] is the sub-list for field type_name
+}
+
+func init() { file_routerrpc_router_proto_init() }
+func file_routerrpc_router_proto_init() {
+	if File_routerrpc_router_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_routerrpc_router_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendPaymentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendTrampolinePaymentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendAsyncPaymentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendAsyncPaymentResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackPaymentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackPaymentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelPaymentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelPaymentResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteFeeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteFeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryRouteFeesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryRouteFeesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteFees); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HopFees); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendToRouteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendToRouteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetMissionControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetMissionControlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryMissionControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryMissionControlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*XImportMissionControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*XImportMissionControlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PairHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PairData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMissionControlConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMissionControlConfigResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetMissionControlConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetMissionControlConfigResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MissionControlConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BimodalParameters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AprioriParameters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryProbabilityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryProbabilityResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4099,8 +6891,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TrackPaymentRequest); i {
+		file_routerrpc_router_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompareRouteProbabilitiesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4111,8 +6903,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TrackPaymentsRequest); i {
+		file_routerrpc_router_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompareRouteProbabilitiesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4123,8 +6915,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RouteFeeRequest); i {
+		file_routerrpc_router_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildRouteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4135,8 +6927,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RouteFeeResponse); i {
+		file_routerrpc_router_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildRouteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4147,8 +6939,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendToRouteRequest); i {
+		file_routerrpc_router_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeHtlcEventsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4159,8 +6951,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendToRouteResponse); i {
+		file_routerrpc_router_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HtlcEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4171,8 +6963,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResetMissionControlRequest); i {
+		file_routerrpc_router_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HtlcInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4183,8 +6975,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResetMissionControlResponse); i {
+		file_routerrpc_router_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4195,8 +6987,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryMissionControlRequest); i {
+		file_routerrpc_router_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardFailEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4207,8 +6999,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryMissionControlResponse); i {
+		file_routerrpc_router_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettleEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4219,8 +7011,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*XImportMissionControlRequest); i {
+		file_routerrpc_router_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalHtlcEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4231,8 +7023,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*XImportMissionControlResponse); i {
+		file_routerrpc_router_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribedEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4243,8 +7035,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PairHistory); i {
+		file_routerrpc_router_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LinkFailEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4255,8 +7047,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PairData); i {
+		file_routerrpc_router_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaymentStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4267,8 +7059,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetMissionControlConfigRequest); i {
+		file_routerrpc_router_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CircuitKey); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4279,8 +7071,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetMissionControlConfigResponse); i {
+		file_routerrpc_router_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardHtlcInterceptRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4291,8 +7083,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetMissionControlConfigRequest); i {
+		file_routerrpc_router_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardHtlcInterceptResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4303,8 +7095,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetMissionControlConfigResponse); i {
+		file_routerrpc_router_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateChanStatusRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4315,8 +7107,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MissionControlConfig); i {
+		file_routerrpc_router_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateChanStatusResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4327,8 +7119,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BimodalParameters); i {
+		file_routerrpc_router_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FreezeChannelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4339,8 +7131,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AprioriParameters); i {
+		file_routerrpc_router_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FreezeChannelResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4351,8 +7143,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryProbabilityRequest); i {
+		file_routerrpc_router_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnfreezeChannelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4363,8 +7155,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryProbabilityResponse); i {
+		file_routerrpc_router_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnfreezeChannelResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4375,8 +7167,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildRouteRequest); i {
+		file_routerrpc_router_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddKnownRouteHintsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4387,8 +7179,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildRouteResponse); i {
+		file_routerrpc_router_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddKnownRouteHintsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4399,8 +7191,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribeHtlcEventsRequest); i {
+		file_routerrpc_router_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveKnownRouteHintsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4411,8 +7203,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HtlcEvent); i {
+		file_routerrpc_router_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveKnownRouteHintsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4423,8 +7215,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HtlcInfo); i {
+		file_routerrpc_router_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKnownRouteHintsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4435,8 +7227,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardEvent); i {
+		file_routerrpc_router_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKnownRouteHintsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4447,8 +7239,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardFailEvent); i {
+		file_routerrpc_router_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KnownRouteHint); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4459,8 +7251,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SettleEvent); i {
+		file_routerrpc_router_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterJITChannelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4471,8 +7263,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FinalHtlcEvent); i {
+		file_routerrpc_router_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelJITChannelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4483,8 +7275,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribedEvent); i {
+		file_routerrpc_router_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelJITChannelResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4495,8 +7287,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LinkFailEvent); i {
+		file_routerrpc_router_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJITChannelsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4507,8 +7299,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PaymentStatus); i {
+		file_routerrpc_router_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJITChannelsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4519,8 +7311,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CircuitKey); i {
+		file_routerrpc_router_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JITChannel); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4531,8 +7323,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardHtlcInterceptRequest); i {
+		file_routerrpc_router_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryNetworkResultsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4543,8 +7335,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardHtlcInterceptResponse); i {
+		file_routerrpc_router_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryNetworkResultsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4555,8 +7347,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateChanStatusRequest); i {
+		file_routerrpc_router_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4567,8 +7359,20 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateChanStatusResponse); i {
+		file_routerrpc_router_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PruneNetworkResultsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PruneNetworkResultsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4580,11 +7384,11 @@ func file_routerrpc_router_proto_init() {
 			}
 		}
 	}
-	file_routerrpc_router_proto_msgTypes[19].OneofWrappers = []interface{}{
+	file_routerrpc_router_proto_msgTypes[28].OneofWrappers = []interface{}{
 		(*MissionControlConfig_Apriori)(nil),
 		(*MissionControlConfig_Bimodal)(nil),
 	}
-	file_routerrpc_router_proto_msgTypes[27].OneofWrappers = []interface{}{
+	file_routerrpc_router_proto_msgTypes[38].OneofWrappers = []interface{}{
 		(*HtlcEvent_ForwardEvent)(nil),
 		(*HtlcEvent_ForwardFailEvent)(nil),
 		(*HtlcEvent_SettleEvent)(nil),
@@ -4597,8 +7401,8 @@ func file_routerrpc_router_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_routerrpc_router_proto_rawDesc,
-			NumEnums:      6,
-			NumMessages:   43,
+			NumEnums:      9,
+			NumMessages:   76,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
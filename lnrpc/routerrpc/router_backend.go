@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/jitchannel"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -60,6 +61,15 @@ type RouterBackend struct {
 	// routes.
 	FindRoute func(*routing.RouteRequest) (*route.Route, float64, error)
 
+	// FindRoutes is a closure that abstracts away how we locate/query for
+	// multiple routes that satisfy a diversity constraint.
+	FindRoutes func(*routing.RouteRequest, int,
+		routing.RouteDiversity) ([]*route.Route, []float64, error)
+
+	// RouteFees is a closure that breaks down the fees of a route by the
+	// nodes that charge them.
+	RouteFees func(*route.Route) (*routing.RouteFees, error)
+
 	MissionControl MissionControl
 
 	// ActiveNetParams are the network parameters of the primary network
@@ -98,6 +108,47 @@ type RouterBackend struct {
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
 
+	// CancelPayment cancels the in-flight payment with the given hash.
+	CancelPayment func(lntypes.Hash) error
+
+	// FreezeChannel exposes the ability to put a channel into maintenance
+	// mode, optionally unfreezing it automatically after the given
+	// duration.
+	FreezeChannel func(wire.OutPoint, time.Duration) error
+
+	// UnfreezeChannel exposes the ability to take a channel out of
+	// maintenance mode.
+	UnfreezeChannel func(wire.OutPoint) error
+
+	// AttributePayment attributes the payment with the given hash to the
+	// given wallet account.
+	AttributePayment func(hash lntypes.Hash, account string) error
+
+	// KnownRouteHints holds the route hints for private destinations that
+	// were registered out of band and are used by path finding for our
+	// outgoing payments.
+	KnownRouteHints *routing.KnownRouteHints
+
+	// JITChannels opens channels to registered clients just in time for
+	// the htlcs forwarded to them. It is nil unless just-in-time channels
+	// are active.
+	JITChannels *jitchannel.Manager
+
+	// HoldPayment holds a payment until its recipient, which is reached
+	// over the given encoded blinded path, releases it. The release
+	// closure is called to send the payment. It is nil unless async
+	// payments are enabled.
+	HoldPayment func(recipientPath []byte, release func()) error
+
+	// QueryNetworkResults returns a page of the results of our payment
+	// attempts that are kept in the network result store of the switch.
+	QueryNetworkResults func(*htlcswitch.NetworkResultsQuery) (
+		[]*htlcswitch.NetworkResultInfo, error)
+
+	// PruneNetworkResults removes the network results that are no longer
+	// needed and returns the number of removed results.
+	PruneNetworkResults func() (int, error)
+
 	// UseStatusInitiated is a boolean that indicates whether the router
 	// should use the new status code `Payment_INITIATED`.
 	//
@@ -113,6 +164,13 @@ type MissionControl interface {
 	GetProbability(fromNode, toNode route.Vertex,
 		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 
+	// GetProbabilityWithEstimator is expected to return the success
+	// probability of a payment from fromNode to toNode as given by the
+	// passed estimator.
+	GetProbabilityWithEstimator(estimator routing.Estimator, fromNode,
+		toNode route.Vertex, amt lnwire.MilliSatoshi,
+		capacity btcutil.Amount) float64
+
 	// ResetHistory resets the history of MissionControl returning it to a
 	// state as if no payment attempts have been made.
 	ResetHistory() error
@@ -144,13 +202,15 @@ type MissionControl interface {
 // satoshis within the route's flow. The returned route contains the full
 // details required to craft and send an HTLC, also including the necessary
 // information that should be present within the Sphinx packet encapsulated
-// within the HTLC.
-//
-// TODO(roasbeef): should return a slice of routes in reality * create separate
-// PR to send based on well formatted route
+// within the HTLC. If more than one route is requested, the routes are found
+// by QueryDiverseRoutes.
 func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	in *lnrpc.QueryRoutesRequest) (*lnrpc.QueryRoutesResponse, error) {
 
+	if in.NumRoutes > 1 {
+		return r.QueryDiverseRoutes(in)
+	}
+
 	routeReq, err := r.parseQueryRoutesRequest(in)
 	if err != nil {
 		return nil, err
@@ -172,13 +232,167 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	}
 
 	routeResp := &lnrpc.QueryRoutesResponse{
-		Routes:      []*lnrpc.Route{rpcRoute},
-		SuccessProb: successProb,
+		Routes:       []*lnrpc.Route{rpcRoute},
+		SuccessProb:  successProb,
+		SuccessProbs: []float64{successProb},
+	}
+
+	return routeResp, nil
+}
+
+// QueryDiverseRoutes attempts to find up to the requested number of routes for
+// the query routes request that satisfy the requested diversity constraint.
+// Next to the routes, the success probability of each of the routes is
+// returned. The success probability of the response is that of the first
+// route.
+func (r *RouterBackend) QueryDiverseRoutes(in *lnrpc.QueryRoutesRequest) (
+	*lnrpc.QueryRoutesResponse, error) {
+
+	diversity, err := unmarshallRouteDiversity(in.RouteDiversity)
+	if err != nil {
+		return nil, err
+	}
+
+	routeReq, err := r.parseQueryRoutesRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, probabilities, err := r.FindRoutes(
+		routeReq, int(in.NumRoutes), diversity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcRoutes := make([]*lnrpc.Route, 0, len(routes))
+	for _, route := range routes {
+		rpcRoute, err := r.MarshallRoute(route)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcRoutes = append(rpcRoutes, rpcRoute)
+	}
+
+	routeResp := &lnrpc.QueryRoutesResponse{
+		Routes:       rpcRoutes,
+		SuccessProb:  probabilities[0],
+		SuccessProbs: probabilities,
 	}
 
 	return routeResp, nil
 }
 
+// unmarshallRouteDiversity converts the rpc route diversity to its routing
+// counterpart.
+func unmarshallRouteDiversity(
+	diversity lnrpc.RouteDiversity) (routing.RouteDiversity, error) {
+
+	switch diversity {
+	case lnrpc.RouteDiversity_ROUTE_DIVERSITY_EDGE_DISJOINT:
+		return routing.DiversityEdgeDisjoint, nil
+
+	case lnrpc.RouteDiversity_ROUTE_DIVERSITY_NODE_DISJOINT:
+		return routing.DiversityNodeDisjoint, nil
+
+	default:
+		return 0, fmt.Errorf("unknown route diversity: %v", diversity)
+	}
+}
+
+// unmarshallAmountRandomization converts the rpc amount randomization
+// strategy to its routing counterpart.
+func unmarshallAmountRandomization(
+	strategy AmountRandomization) (routing.AmountRandomization, error) {
+
+	switch strategy {
+	case AmountRandomization_AMOUNT_RANDOMIZATION_DEFAULT:
+		return routing.AmountRandomizationDefault, nil
+
+	case AmountRandomization_AMOUNT_RANDOMIZATION_NONE:
+		return routing.AmountRandomizationNone, nil
+
+	case AmountRandomization_AMOUNT_RANDOMIZATION_SHARD:
+		return routing.AmountRandomizationShard, nil
+
+	default:
+		return 0, fmt.Errorf("unknown amount randomization: %v",
+			strategy)
+	}
+}
+
+// QueryRouteFees attempts to find up to numRoutes routes for the query routes
+// request that satisfy its diversity constraint, and breaks down the fees of
+// each of them by the nodes that charge them.
+func (r *RouterBackend) QueryRouteFees(in *lnrpc.QueryRoutesRequest,
+	numRoutes int) ([]*RouteFees, error) {
+
+	diversity, err := unmarshallRouteDiversity(in.RouteDiversity)
+	if err != nil {
+		return nil, err
+	}
+
+	routeReq, err := r.parseQueryRoutesRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, _, err := r.FindRoutes(routeReq, numRoutes, diversity)
+	if err != nil {
+		return nil, err
+	}
+
+	routeFees := make([]*RouteFees, 0, len(routes))
+	for _, route := range routes {
+		fees, err := r.RouteFees(route)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcFees, err := r.marshallRouteFees(fees)
+		if err != nil {
+			return nil, err
+		}
+
+		routeFees = append(routeFees, rpcFees)
+	}
+
+	return routeFees, nil
+}
+
+// marshallRouteFees marshalls the fee breakdown of a route to its rpc
+// representation.
+func (r *RouterBackend) marshallRouteFees(
+	fees *routing.RouteFees) (*RouteFees, error) {
+
+	rpcRoute, err := r.MarshallRoute(fees.Route)
+	if err != nil {
+		return nil, err
+	}
+
+	hops := make([]*HopFees, 0, len(fees.Hops))
+	for _, hop := range fees.Hops {
+		hops = append(hops, &HopFees{
+			PubKey:           hop.Node[:],
+			IncomingChanId:   hop.IncomingChannel,
+			OutgoingChanId:   hop.OutgoingChannel,
+			AmtToForwardMsat: uint64(hop.AmtToForward),
+			OutboundFeeMsat:  uint64(hop.OutboundFee),
+			InboundFeeMsat:   hop.InboundFee,
+			FeeMsat:          uint64(hop.Fee),
+		})
+	}
+
+	return &RouteFees{
+		Route:              rpcRoute,
+		Hops:               hops,
+		TotalFeesMsat:      uint64(fees.TotalFees),
+		OwnOutboundFeeMsat: uint64(fees.OwnOutboundFee),
+		OwnInboundFeeMsat:  fees.OwnInboundFee,
+	}, nil
+}
+
 func parsePubKey(key string) (route.Vertex, error) {
 	pubKeyBytes, err := hex.DecodeString(key)
 	if err != nil {
@@ -497,6 +711,34 @@ func unmarshalBlindedPayment(rpcPayment *lnrpc.BlindedPaymentPath) (
 	}, nil
 }
 
+// unmarshalInvoiceBlindedPaths converts the blinded payment paths of a
+// decoded invoice into the blinded payments that the router pays to.
+func unmarshalInvoiceBlindedPaths(paths []*zpay32.BlindedPaymentPath) (
+	[]*routing.BlindedPayment, error) {
+
+	blindedPmts := make([]*routing.BlindedPayment, len(paths))
+	for i, path := range paths {
+		blindedPmt := &routing.BlindedPayment{
+			BlindedPath:         path.BlindedPath,
+			BaseFee:             path.FeeBaseMsat,
+			ProportionalFeeRate: path.FeeRate,
+			CltvExpiryDelta:     path.CltvExpiryDelta,
+			HtlcMinimum:         path.HTLCMinMsat,
+			HtlcMaximum:         path.HTLCMaxMsat,
+			Features:            path.Features,
+		}
+
+		if err := blindedPmt.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid blinded path %d: %w",
+				i, err)
+		}
+
+		blindedPmts[i] = blindedPmt
+	}
+
+	return blindedPmts, nil
+}
+
 func unmarshalBlindedPaymentPaths(rpcPath *lnrpc.BlindedPath) (
 	*sphinx.BlindedPath, error) {
 
@@ -575,6 +817,50 @@ func (r *RouterBackend) rpcEdgeToPair(e *lnrpc.EdgeLocator) (
 	return pair, nil
 }
 
+// CompareRouteProbabilities estimates the success probability of the given
+// route with each of the passed estimators, based on the payment history that
+// mission control has collected. The returned probabilities are in the same
+// order as the estimators, which allows the outputs of different probability
+// models to be compared for the same route.
+func (r *RouterBackend) CompareRouteProbabilities(rt *route.Route,
+	estimators ...routing.Estimator) ([]float64, error) {
+
+	if len(rt.Hops) == 0 {
+		return nil, errors.New("route has no hops")
+	}
+
+	// Fetch the capacities of the channels along the route once, as they
+	// are the same for every estimator.
+	capacities := make([]btcutil.Amount, len(rt.Hops))
+	for i, hop := range rt.Hops {
+		capacity, err := r.FetchChannelCapacity(hop.ChannelID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch capacity of "+
+				"channel %v: %w", hop.ChannelID, err)
+		}
+		capacities[i] = capacity
+	}
+
+	probabilities := make([]float64, len(estimators))
+	for i, estimator := range estimators {
+		probability := 1.0
+		fromNode := rt.SourcePubKey
+		for j, hop := range rt.Hops {
+			probability *= r.MissionControl.
+				GetProbabilityWithEstimator(
+					estimator, fromNode, hop.PubKeyBytes,
+					hop.AmtToForward, capacities[j],
+				)
+
+			fromNode = hop.PubKeyBytes
+		}
+
+		probabilities[i] = probability
+	}
+
+	return probabilities, nil
+}
+
 // MarshallRoute marshalls an internal route to an rpc route struct.
 func (r *RouterBackend) MarshallRoute(route *route.Route) (*lnrpc.Route, error) {
 	resp := &lnrpc.Route{
@@ -839,6 +1125,37 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.MaxShardAmt = &shardAmtMsat
 	}
 
+	// Apply the privacy overrides of this payment, if any.
+	switch {
+	case rpcPayReq.NoShadowRoute && rpcPayReq.ShadowRouteMaxCltv != 0:
+		return nil, errors.New("no_shadow_route and " +
+			"shadow_route_max_cltv are mutually exclusive")
+
+	case rpcPayReq.NoShadowRoute:
+		var noPadding uint16
+		payIntent.ShadowRouteMaxCltv = &noPadding
+
+	case rpcPayReq.ShadowRouteMaxCltv > 0:
+		if rpcPayReq.ShadowRouteMaxCltv >
+			uint32(routing.MaxShadowRouteMaxCltv) {
+
+			return nil, fmt.Errorf("shadow_route_max_cltv of %v "+
+				"exceeds maximum of %v",
+				rpcPayReq.ShadowRouteMaxCltv,
+				routing.MaxShadowRouteMaxCltv)
+		}
+
+		maxPadding := uint16(rpcPayReq.ShadowRouteMaxCltv)
+		payIntent.ShadowRouteMaxCltv = &maxPadding
+	}
+
+	payIntent.AmountRandomization, err = unmarshallAmountRandomization(
+		rpcPayReq.AmountRandomization,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Take fee limit from request.
 	payIntent.FeeLimit, err = lnrpc.UnmarshallAmt(
 		rpcPayReq.FeeLimitSat, rpcPayReq.FeeLimitMsat,
@@ -999,6 +1316,32 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.PaymentAddr = payAddr
 		payIntent.PaymentRequest = []byte(rpcPayReq.PaymentRequest)
 		payIntent.Metadata = payReq.Metadata
+
+		// If the invoice contains blinded paths, we'll pay to those
+		// instead of the destination node.
+		if len(payReq.BlindedPaymentPaths) > 0 {
+			if payReq.Features.HasFeature(lnwire.AMPOptional) {
+				return nil, errors.New("AMP payments to " +
+					"blinded paths are not supported")
+			}
+
+			if len(payIntent.RouteHints) > 0 {
+				return nil, errors.New("route hints and " +
+					"blinded paths can't both be set")
+			}
+
+			blindedPmts, err := unmarshalInvoiceBlindedPaths(
+				payReq.BlindedPaymentPaths,
+			)
+			if err != nil {
+				return nil, err
+			}
+			payIntent.BlindedPayments = blindedPmts
+
+			// The final cltv delta of the receiver is already
+			// included in the delta of the blinded paths.
+			payIntent.FinalCLTVDelta = 0
+		}
 	} else {
 		// Otherwise, If the payment request field was not specified
 		// (and a custom route wasn't specified), construct the payment
@@ -1129,9 +1472,10 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		return nil, err
 	}
 
-	// Check for disallowed payments to self.
+	// Payments to self are settled directly with our invoice registry,
+	// unless a circular route through the network was requested.
 	if !rpcPayReq.AllowSelfPayment && payIntent.Target == r.SelfNode {
-		return nil, errors.New("self-payments not allowed")
+		payIntent.LocalSettle = true
 	}
 
 	return payIntent, nil
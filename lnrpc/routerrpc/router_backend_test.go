@@ -20,6 +20,8 @@ const (
 	hintNodeKey   = "0274e7fb33eafd74fe1acb6db7680bb4aa78e9c839a6e954e38abfad680f645ef7"
 
 	testMissionControlProb = 0.5
+
+	testBimodalProb = 0.8
 )
 
 var (
@@ -241,6 +243,194 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 	}
 }
 
+// TestQueryDiverseRoutes asserts that multiple routes are requested from the
+// router and returned along with their success probabilities.
+func TestQueryDiverseRoutes(t *testing.T) {
+	t.Parallel()
+
+	findRoutes := func(req *routing.RouteRequest, numRoutes int,
+		diversity routing.RouteDiversity) ([]*route.Route, []float64,
+		error) {
+
+		require.Equal(t, 3, numRoutes)
+		require.Equal(t, routing.DiversityNodeDisjoint, diversity)
+
+		var routes []*route.Route
+		for _, node := range []route.Vertex{node1, node2} {
+			hops := []*route.Hop{
+				{ChannelID: 1, PubKeyBytes: node},
+				{ChannelID: 2, PubKeyBytes: req.Target},
+			}
+
+			rt, err := route.NewRouteFromHops(
+				req.Amount, 144, req.Source, hops,
+			)
+			require.NoError(t, err)
+
+			routes = append(routes, rt)
+		}
+
+		return routes, []float64{0.6, 0.3}, nil
+	}
+
+	backend := &RouterBackend{
+		FindRoutes:       findRoutes,
+		SelfNode:         sourceKey,
+		MaxTotalTimelock: 1000,
+		MissionControl:   &mockMissionControl{},
+		FetchChannelCapacity: func(chanID uint64) (btcutil.Amount,
+			error) {
+
+			return 1, nil
+		},
+	}
+
+	request := &lnrpc.QueryRoutesRequest{
+		PubKey:         destKey,
+		Amt:            100000,
+		FinalCltvDelta: 100,
+		NumRoutes:      3,
+	}
+	request.RouteDiversity =
+		lnrpc.RouteDiversity_ROUTE_DIVERSITY_NODE_DISJOINT
+
+	resp, err := backend.QueryRoutes(context.Background(), request)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Routes, 2)
+	require.Equal(t, []float64{0.6, 0.3}, resp.SuccessProbs)
+	require.Equal(t, 0.6, resp.SuccessProb)
+
+	// Unknown diversity constraints are rejected.
+	request.RouteDiversity = 100
+	_, err = backend.QueryRoutes(context.Background(), request)
+	require.ErrorContains(t, err, "unknown route diversity")
+}
+
+// TestQueryRouteFees asserts that the fee breakdowns of the routes found are
+// marshalled to their rpc representation.
+func TestQueryRouteFees(t *testing.T) {
+	t.Parallel()
+
+	findRoutes := func(req *routing.RouteRequest, numRoutes int,
+		diversity routing.RouteDiversity) ([]*route.Route, []float64,
+		error) {
+
+		require.Equal(t, 2, numRoutes)
+		require.Equal(t, routing.DiversityEdgeDisjoint, diversity)
+
+		hops := []*route.Hop{
+			{ChannelID: 1, PubKeyBytes: node1, AmtToForward: 1000},
+			{ChannelID: 2, PubKeyBytes: req.Target,
+				AmtToForward: 1000},
+		}
+		rt, err := route.NewRouteFromHops(1010, 144, req.Source, hops)
+		require.NoError(t, err)
+
+		return []*route.Route{rt}, []float64{0.5}, nil
+	}
+
+	routeFees := func(rt *route.Route) (*routing.RouteFees, error) {
+		return &routing.RouteFees{
+			Route: rt,
+			Hops: []routing.HopFees{{
+				Node:            node1,
+				IncomingChannel: 1,
+				OutgoingChannel: 2,
+				AmtToForward:    1000,
+				OutboundFee:     15,
+				InboundFee:      -5,
+				Fee:             10,
+			}},
+			TotalFees:      10,
+			OwnOutboundFee: 20,
+			OwnInboundFee:  -2,
+		}, nil
+	}
+
+	backend := &RouterBackend{
+		FindRoutes:       findRoutes,
+		RouteFees:        routeFees,
+		SelfNode:         sourceKey,
+		MaxTotalTimelock: 1000,
+		FetchChannelCapacity: func(chanID uint64) (btcutil.Amount,
+			error) {
+
+			return 1, nil
+		},
+	}
+
+	request := &lnrpc.QueryRoutesRequest{
+		PubKey:         destKey,
+		Amt:            1,
+		FinalCltvDelta: 100,
+	}
+
+	resp, err := backend.QueryRouteFees(request, 2)
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+
+	fees := resp[0]
+	require.Len(t, fees.Route.Hops, 2)
+	require.EqualValues(t, 10, fees.TotalFeesMsat)
+	require.EqualValues(t, 20, fees.OwnOutboundFeeMsat)
+	require.EqualValues(t, -2, fees.OwnInboundFeeMsat)
+	require.Equal(t, []*HopFees{{
+		PubKey:           node1[:],
+		IncomingChanId:   1,
+		OutgoingChanId:   2,
+		AmtToForwardMsat: 1000,
+		OutboundFeeMsat:  15,
+		InboundFeeMsat:   -5,
+		FeeMsat:          10,
+	}}, fees.Hops)
+}
+
+// TestCompareRouteProbabilities asserts that the probability of a route is
+// estimated with each of the passed estimators.
+func TestCompareRouteProbabilities(t *testing.T) {
+	t.Parallel()
+
+	apriori, err := routing.NewAprioriEstimator(
+		routing.DefaultAprioriConfig(),
+	)
+	require.NoError(t, err)
+
+	bimodal, err := routing.NewBimodalEstimator(
+		routing.DefaultBimodalConfig(),
+	)
+	require.NoError(t, err)
+
+	backend := &RouterBackend{
+		MissionControl: &mockMissionControl{},
+		FetchChannelCapacity: func(chanID uint64) (btcutil.Amount,
+			error) {
+
+			return 1_000_000, nil
+		},
+	}
+
+	rt := &route.Route{
+		SourcePubKey: sourceKey,
+		Hops: []*route.Hop{
+			{ChannelID: 1, PubKeyBytes: node1, AmtToForward: 100},
+			{ChannelID: 2, PubKeyBytes: node2, AmtToForward: 100},
+		},
+	}
+
+	probabilities, err := backend.CompareRouteProbabilities(
+		rt, apriori, bimodal,
+	)
+	require.NoError(t, err)
+	require.InDeltaSlice(t, []float64{0.25, 0.64}, probabilities, 1e-9)
+
+	// A route without any hops can't be evaluated.
+	_, err = backend.CompareRouteProbabilities(
+		&route.Route{}, apriori, bimodal,
+	)
+	require.Error(t, err)
+}
+
 type mockMissionControl struct {
 	MissionControl
 }
@@ -251,6 +441,17 @@ func (m *mockMissionControl) GetProbability(fromNode, toNode route.Vertex,
 	return testMissionControlProb
 }
 
+func (m *mockMissionControl) GetProbabilityWithEstimator(
+	estimator routing.Estimator, fromNode, toNode route.Vertex,
+	amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
+
+	if _, ok := estimator.(*routing.BimodalEstimator); ok {
+		return testBimodalProb
+	}
+
+	return testMissionControlProb
+}
+
 func (m *mockMissionControl) ResetHistory() error {
 	return nil
 }
@@ -475,3 +676,106 @@ func testUnmarshalAMP(t *testing.T, test unmarshalAMPTest) {
 		t.Fatalf("test case has non-standard outcome")
 	}
 }
+
+// TestExtractIntentPrivacyOverrides tests that the per-payment shadow route
+// and amount randomization overrides of a send request are applied to the
+// payment intent.
+func TestExtractIntentPrivacyOverrides(t *testing.T) {
+	t.Parallel()
+
+	dest, err := hex.DecodeString(destKey)
+	require.NoError(t, err)
+
+	backend := &RouterBackend{
+		MaxTotalTimelock:      1000,
+		DefaultFinalCltvDelta: 40,
+	}
+
+	maxPadding := uint16(20)
+	noPadding := uint16(0)
+	shard := AmountRandomization_AMOUNT_RANDOMIZATION_SHARD
+
+	testCases := []struct {
+		name                string
+		shadowRouteMaxCltv  uint32
+		noShadowRoute       bool
+		amountRandomization AmountRandomization
+		expectedMaxCltv     *uint16
+		expectedStrategy    routing.AmountRandomization
+		expectErr           bool
+	}{
+		{
+			name:             "router defaults",
+			expectedStrategy: routing.AmountRandomizationDefault,
+		},
+		{
+			name:               "max shadow route padding",
+			shadowRouteMaxCltv: 20,
+			expectedMaxCltv:    &maxPadding,
+			expectedStrategy:   routing.AmountRandomizationDefault,
+		},
+		{
+			name:             "no shadow route",
+			noShadowRoute:    true,
+			expectedMaxCltv:  &noPadding,
+			expectedStrategy: routing.AmountRandomizationDefault,
+		},
+		{
+			name:               "shadow route padding too large",
+			shadowRouteMaxCltv: 145,
+			expectErr:          true,
+		},
+		{
+			name:               "conflicting shadow route options",
+			shadowRouteMaxCltv: 20,
+			noShadowRoute:      true,
+			expectErr:          true,
+		},
+		{
+			name:                "randomized shard amounts",
+			amountRandomization: shard,
+			expectedStrategy:    routing.AmountRandomizationShard,
+		},
+		{
+			name:                "unknown amount randomization",
+			amountRandomization: 100,
+			expectErr:           true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := &SendPaymentRequest{
+				Dest:                dest,
+				Amt:                 1000,
+				PaymentHash:         make([]byte, 32),
+				TimeoutSeconds:      60,
+				ShadowRouteMaxCltv:  tc.shadowRouteMaxCltv,
+				NoShadowRoute:       tc.noShadowRoute,
+				AmountRandomization: tc.amountRandomization,
+			}
+
+			payIntent, err := backend.extractIntentFromSendRequest(
+				req,
+			)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(
+				t, tc.expectedMaxCltv,
+				payIntent.ShadowRouteMaxCltv,
+			)
+			require.Equal(
+				t, tc.expectedStrategy,
+				payIntent.AmountRandomization,
+			)
+		})
+	}
+}
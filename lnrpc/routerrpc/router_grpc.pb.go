@@ -26,6 +26,20 @@ type RouterClient interface {
 	// routes without fees will be attempted which often fails with
 	// FAILURE_REASON_NO_ROUTE.
 	SendPaymentV2(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (Router_SendPaymentV2Client, error)
+	// SendTrampolinePayment attempts to route a payment described by the passed
+	// PaymentRequest through the given trampoline node, which finds the route to
+	// the final destination itself. The trampoline node is paid according to the
+	// given fee policy, out of the fee limit of the payment. The call returns a
+	// stream of payment updates, the same way as SendPaymentV2.
+	SendTrampolinePayment(ctx context.Context, in *SendTrampolinePaymentRequest, opts ...grpc.CallOption) (Router_SendTrampolinePaymentClient, error)
+	// SendAsyncPayment holds a payment to an often-offline recipient until the
+	// recipient comes online, following the async payments protocol. The
+	// recipient is told about the payment over the given blinded path, and the
+	// payment is sent once the recipient released it. Only then is the payment
+	// known to TrackPaymentV2, which reports it as unknown before. Held payments
+	// are kept in memory and are dropped on restart, in which case they are never
+	// sent. Requires protocol.async-payments.
+	SendAsyncPayment(ctx context.Context, in *SendAsyncPaymentRequest, opts ...grpc.CallOption) (*SendAsyncPaymentResponse, error)
 	// lncli: `trackpayment`
 	// TrackPaymentV2 returns an update stream for the payment identified by the
 	// payment hash.
@@ -37,9 +51,24 @@ type RouterClient interface {
 	// payment attempt make sure to subscribe to this method before initiating any
 	// payments.
 	TrackPayments(ctx context.Context, in *TrackPaymentsRequest, opts ...grpc.CallOption) (Router_TrackPaymentsClient, error)
+	// lncli: `cancelpayment`
+	// CancelPayment cancels the in-flight payment identified by the payment hash.
+	// No new HTLC attempts are made for the payment, and it is failed once the
+	// results of the attempts already in flight are known. The final status of
+	// the payment is reported to the streams tracking it.
+	CancelPayment(ctx context.Context, in *CancelPaymentRequest, opts ...grpc.CallOption) (*CancelPaymentResponse, error)
 	// EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
 	// may cost to send an HTLC to the target end destination.
 	EstimateRouteFee(ctx context.Context, in *RouteFeeRequest, opts ...grpc.CallOption) (*RouteFeeResponse, error)
+	// lncli: `queryroutefees`
+	// QueryRouteFees finds up to num_routes of the best routes to the
+	// destination of the query that satisfy its route diversity constraint, and
+	// breaks down the fees expected along each of them by the nodes that charge
+	// them. Next to the fees paid to each hop,
+	// the inbound and outbound fees our own policy for the first channel of a
+	// route charges for the same amount are returned, to put the cost of the
+	// payment into perspective.
+	QueryRouteFees(ctx context.Context, in *QueryRouteFeesRequest, opts ...grpc.CallOption) (*QueryRouteFeesResponse, error)
 	// Deprecated: Do not use.
 	//
 	// Deprecated, use SendToRouteV2. SendToRoute attempts to make a payment via
@@ -80,6 +109,12 @@ type RouterClient interface {
 	// probability if no channel is available or if the amount violates min/max
 	// HTLC constraints.
 	QueryProbability(ctx context.Context, in *QueryProbabilityRequest, opts ...grpc.CallOption) (*QueryProbabilityResponse, error)
+	// CompareRouteProbabilities estimates the success probability of a route
+	// with each of the given estimator configs, based on the payment history
+	// mission control has collected. This allows the outputs of the apriori and
+	// bimodal probability models to be compared without switching the estimator
+	// of mission control.
+	CompareRouteProbabilities(ctx context.Context, in *CompareRouteProbabilitiesRequest, opts ...grpc.CallOption) (*CompareRouteProbabilitiesResponse, error)
 	// lncli: `buildroute`
 	// BuildRoute builds a fully specified route based on a list of hop public
 	// keys. It retrieves the relevant channel policies from the graph in order to
@@ -116,6 +151,55 @@ type RouterClient interface {
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	// lncli: `freezechannel`
+	// FreezeChannel puts a channel into maintenance mode, which is useful before
+	// a planned downtime of the peer. The channel stops forwarding HTLCs in both
+	// directions and rejects new payments, and is announced as disabled to the
+	// network, but stays open. Freezes don't persist across restarts.
+	FreezeChannel(ctx context.Context, in *FreezeChannelRequest, opts ...grpc.CallOption) (*FreezeChannelResponse, error)
+	// lncli: `unfreezechannel`
+	// UnfreezeChannel takes a channel out of maintenance mode, so it forwards
+	// HTLCs and is announced as enabled again.
+	UnfreezeChannel(ctx context.Context, in *UnfreezeChannelRequest, opts ...grpc.CallOption) (*UnfreezeChannelResponse, error)
+	// lncli: `addroutehints`
+	// AddKnownRouteHints registers route hints for a private destination we
+	// know about out of band, for example a client of our LSP or a partner's
+	// private channel. Path finding uses the hints for our outgoing payments
+	// until the time to live elapses. Hints registered for the destination
+	// before are replaced. Known route hints don't persist across restarts.
+	AddKnownRouteHints(ctx context.Context, in *AddKnownRouteHintsRequest, opts ...grpc.CallOption) (*AddKnownRouteHintsResponse, error)
+	// lncli: `removeroutehints`
+	// RemoveKnownRouteHints removes the route hints registered for a
+	// destination.
+	RemoveKnownRouteHints(ctx context.Context, in *RemoveKnownRouteHintsRequest, opts ...grpc.CallOption) (*RemoveKnownRouteHintsResponse, error)
+	// lncli: `listroutehints`
+	// ListKnownRouteHints returns the registered route hints that haven't
+	// expired yet.
+	ListKnownRouteHints(ctx context.Context, in *ListKnownRouteHintsRequest, opts ...grpc.CallOption) (*ListKnownRouteHintsResponse, error)
+	// lncli: `registerjitchannel`
+	// RegisterJITChannel assigns a new short channel id to a client, which the
+	// client puts into the route hints of its invoices. Once an htlc is
+	// forwarded to the short channel id, the htlc is held, a zero-conf channel
+	// is opened to the client and the htlc is released into it. Requires
+	// jitchannel.active to be set.
+	RegisterJITChannel(ctx context.Context, in *RegisterJITChannelRequest, opts ...grpc.CallOption) (*JITChannel, error)
+	// lncli: `canceljitchannel`
+	// CancelJITChannel removes the registration of a just-in-time channel that
+	// still waits for its first forward.
+	CancelJITChannel(ctx context.Context, in *CancelJITChannelRequest, opts ...grpc.CallOption) (*CancelJITChannelResponse, error)
+	// lncli: `listjitchannels`
+	// ListJITChannels returns the registered just-in-time channels that wait
+	// for their first forward or are being opened.
+	ListJITChannels(ctx context.Context, in *ListJITChannelsRequest, opts ...grpc.CallOption) (*ListJITChannelsResponse, error)
+	// lncli: `querynetworkresults`
+	// QueryNetworkResults returns a page of the results of our payment attempts
+	// that are kept in the network result store, ordered by their attempt ID.
+	QueryNetworkResults(ctx context.Context, in *QueryNetworkResultsRequest, opts ...grpc.CallOption) (*QueryNetworkResultsResponse, error)
+	// lncli: `prunenetworkresults`
+	// PruneNetworkResults removes the network results of resolved attempts
+	// whose retention passed and of attempts whose payment was deleted, without
+	// waiting for the next periodic prune.
+	PruneNetworkResults(ctx context.Context, in *PruneNetworkResultsRequest, opts ...grpc.CallOption) (*PruneNetworkResultsResponse, error)
 }
 
 type routerClient struct {
@@ -158,8 +242,49 @@ func (x *routerSendPaymentV2Client) Recv() (*lnrpc.Payment, error) {
 	return m, nil
 }
 
+func (c *routerClient) SendTrampolinePayment(ctx context.Context, in *SendTrampolinePaymentRequest, opts ...grpc.CallOption) (Router_SendTrampolinePaymentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[1], "/routerrpc.Router/SendTrampolinePayment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerSendTrampolinePaymentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_SendTrampolinePaymentClient interface {
+	Recv() (*lnrpc.Payment, error)
+	grpc.ClientStream
+}
+
+type routerSendTrampolinePaymentClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerSendTrampolinePaymentClient) Recv() (*lnrpc.Payment, error) {
+	m := new(lnrpc.Payment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routerClient) SendAsyncPayment(ctx context.Context, in *SendAsyncPaymentRequest, opts ...grpc.CallOption) (*SendAsyncPaymentResponse, error) {
+	out := new(SendAsyncPaymentResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/SendAsyncPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) TrackPaymentV2(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Router_TrackPaymentV2Client, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[1], "/routerrpc.Router/TrackPaymentV2", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[2], "/routerrpc.Router/TrackPaymentV2", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +316,7 @@ func (x *routerTrackPaymentV2Client) Recv() (*lnrpc.Payment, error) {
 }
 
 func (c *routerClient) TrackPayments(ctx context.Context, in *TrackPaymentsRequest, opts ...grpc.CallOption) (Router_TrackPaymentsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[2], "/routerrpc.Router/TrackPayments", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[3], "/routerrpc.Router/TrackPayments", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -222,6 +347,15 @@ func (x *routerTrackPaymentsClient) Recv() (*lnrpc.Payment, error) {
 	return m, nil
 }
 
+func (c *routerClient) CancelPayment(ctx context.Context, in *CancelPaymentRequest, opts ...grpc.CallOption) (*CancelPaymentResponse, error) {
+	out := new(CancelPaymentResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/CancelPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) EstimateRouteFee(ctx context.Context, in *RouteFeeRequest, opts ...grpc.CallOption) (*RouteFeeResponse, error) {
 	out := new(RouteFeeResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/EstimateRouteFee", in, out, opts...)
@@ -231,6 +365,15 @@ func (c *routerClient) EstimateRouteFee(ctx context.Context, in *RouteFeeRequest
 	return out, nil
 }
 
+func (c *routerClient) QueryRouteFees(ctx context.Context, in *QueryRouteFeesRequest, opts ...grpc.CallOption) (*QueryRouteFeesResponse, error) {
+	out := new(QueryRouteFeesResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/QueryRouteFees", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Deprecated: Do not use.
 func (c *routerClient) SendToRoute(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*SendToRouteResponse, error) {
 	out := new(SendToRouteResponse)
@@ -304,6 +447,15 @@ func (c *routerClient) QueryProbability(ctx context.Context, in *QueryProbabilit
 	return out, nil
 }
 
+func (c *routerClient) CompareRouteProbabilities(ctx context.Context, in *CompareRouteProbabilitiesRequest, opts ...grpc.CallOption) (*CompareRouteProbabilitiesResponse, error) {
+	out := new(CompareRouteProbabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/CompareRouteProbabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error) {
 	out := new(BuildRouteResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/BuildRoute", in, out, opts...)
@@ -314,7 +466,7 @@ func (c *routerClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, op
 }
 
 func (c *routerClient) SubscribeHtlcEvents(ctx context.Context, in *SubscribeHtlcEventsRequest, opts ...grpc.CallOption) (Router_SubscribeHtlcEventsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[3], "/routerrpc.Router/SubscribeHtlcEvents", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[4], "/routerrpc.Router/SubscribeHtlcEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -347,7 +499,7 @@ func (x *routerSubscribeHtlcEventsClient) Recv() (*HtlcEvent, error) {
 
 // Deprecated: Do not use.
 func (c *routerClient) SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (Router_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[4], "/routerrpc.Router/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[5], "/routerrpc.Router/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -380,7 +532,7 @@ func (x *routerSendPaymentClient) Recv() (*PaymentStatus, error) {
 
 // Deprecated: Do not use.
 func (c *routerClient) TrackPayment(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Router_TrackPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[5], "/routerrpc.Router/TrackPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[6], "/routerrpc.Router/TrackPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -412,7 +564,7 @@ func (x *routerTrackPaymentClient) Recv() (*PaymentStatus, error) {
 }
 
 func (c *routerClient) HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[6], "/routerrpc.Router/HtlcInterceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[7], "/routerrpc.Router/HtlcInterceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -451,6 +603,96 @@ func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatu
 	return out, nil
 }
 
+func (c *routerClient) FreezeChannel(ctx context.Context, in *FreezeChannelRequest, opts ...grpc.CallOption) (*FreezeChannelResponse, error) {
+	out := new(FreezeChannelResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/FreezeChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) UnfreezeChannel(ctx context.Context, in *UnfreezeChannelRequest, opts ...grpc.CallOption) (*UnfreezeChannelResponse, error) {
+	out := new(UnfreezeChannelResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/UnfreezeChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) AddKnownRouteHints(ctx context.Context, in *AddKnownRouteHintsRequest, opts ...grpc.CallOption) (*AddKnownRouteHintsResponse, error) {
+	out := new(AddKnownRouteHintsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/AddKnownRouteHints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) RemoveKnownRouteHints(ctx context.Context, in *RemoveKnownRouteHintsRequest, opts ...grpc.CallOption) (*RemoveKnownRouteHintsResponse, error) {
+	out := new(RemoveKnownRouteHintsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/RemoveKnownRouteHints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListKnownRouteHints(ctx context.Context, in *ListKnownRouteHintsRequest, opts ...grpc.CallOption) (*ListKnownRouteHintsResponse, error) {
+	out := new(ListKnownRouteHintsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ListKnownRouteHints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) RegisterJITChannel(ctx context.Context, in *RegisterJITChannelRequest, opts ...grpc.CallOption) (*JITChannel, error) {
+	out := new(JITChannel)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/RegisterJITChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) CancelJITChannel(ctx context.Context, in *CancelJITChannelRequest, opts ...grpc.CallOption) (*CancelJITChannelResponse, error) {
+	out := new(CancelJITChannelResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/CancelJITChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListJITChannels(ctx context.Context, in *ListJITChannelsRequest, opts ...grpc.CallOption) (*ListJITChannelsResponse, error) {
+	out := new(ListJITChannelsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ListJITChannels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) QueryNetworkResults(ctx context.Context, in *QueryNetworkResultsRequest, opts ...grpc.CallOption) (*QueryNetworkResultsResponse, error) {
+	out := new(QueryNetworkResultsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/QueryNetworkResults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) PruneNetworkResults(ctx context.Context, in *PruneNetworkResultsRequest, opts ...grpc.CallOption) (*PruneNetworkResultsResponse, error) {
+	out := new(PruneNetworkResultsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/PruneNetworkResults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouterServer is the server API for Router service.
 // All implementations must embed UnimplementedRouterServer
 // for forward compatibility
@@ -462,6 +704,20 @@ type RouterServer interface {
 	// routes without fees will be attempted which often fails with
 	// FAILURE_REASON_NO_ROUTE.
 	SendPaymentV2(*SendPaymentRequest, Router_SendPaymentV2Server) error
+	// SendTrampolinePayment attempts to route a payment described by the passed
+	// PaymentRequest through the given trampoline node, which finds the route to
+	// the final destination itself. The trampoline node is paid according to the
+	// given fee policy, out of the fee limit of the payment. The call returns a
+	// stream of payment updates, the same way as SendPaymentV2.
+	SendTrampolinePayment(*SendTrampolinePaymentRequest, Router_SendTrampolinePaymentServer) error
+	// SendAsyncPayment holds a payment to an often-offline recipient until the
+	// recipient comes online, following the async payments protocol. The
+	// recipient is told about the payment over the given blinded path, and the
+	// payment is sent once the recipient released it. Only then is the payment
+	// known to TrackPaymentV2, which reports it as unknown before. Held payments
+	// are kept in memory and are dropped on restart, in which case they are never
+	// sent. Requires protocol.async-payments.
+	SendAsyncPayment(context.Context, *SendAsyncPaymentRequest) (*SendAsyncPaymentResponse, error)
 	// lncli: `trackpayment`
 	// TrackPaymentV2 returns an update stream for the payment identified by the
 	// payment hash.
@@ -473,9 +729,24 @@ type RouterServer interface {
 	// payment attempt make sure to subscribe to this method before initiating any
 	// payments.
 	TrackPayments(*TrackPaymentsRequest, Router_TrackPaymentsServer) error
+	// lncli: `cancelpayment`
+	// CancelPayment cancels the in-flight payment identified by the payment hash.
+	// No new HTLC attempts are made for the payment, and it is failed once the
+	// results of the attempts already in flight are known. The final status of
+	// the payment is reported to the streams tracking it.
+	CancelPayment(context.Context, *CancelPaymentRequest) (*CancelPaymentResponse, error)
 	// EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
 	// may cost to send an HTLC to the target end destination.
 	EstimateRouteFee(context.Context, *RouteFeeRequest) (*RouteFeeResponse, error)
+	// lncli: `queryroutefees`
+	// QueryRouteFees finds up to num_routes of the best routes to the
+	// destination of the query that satisfy its route diversity constraint, and
+	// breaks down the fees expected along each of them by the nodes that charge
+	// them. Next to the fees paid to each hop,
+	// the inbound and outbound fees our own policy for the first channel of a
+	// route charges for the same amount are returned, to put the cost of the
+	// payment into perspective.
+	QueryRouteFees(context.Context, *QueryRouteFeesRequest) (*QueryRouteFeesResponse, error)
 	// Deprecated: Do not use.
 	//
 	// Deprecated, use SendToRouteV2. SendToRoute attempts to make a payment via
@@ -516,6 +787,12 @@ type RouterServer interface {
 	// probability if no channel is available or if the amount violates min/max
 	// HTLC constraints.
 	QueryProbability(context.Context, *QueryProbabilityRequest) (*QueryProbabilityResponse, error)
+	// CompareRouteProbabilities estimates the success probability of a route
+	// with each of the given estimator configs, based on the payment history
+	// mission control has collected. This allows the outputs of the apriori and
+	// bimodal probability models to be compared without switching the estimator
+	// of mission control.
+	CompareRouteProbabilities(context.Context, *CompareRouteProbabilitiesRequest) (*CompareRouteProbabilitiesResponse, error)
 	// lncli: `buildroute`
 	// BuildRoute builds a fully specified route based on a list of hop public
 	// keys. It retrieves the relevant channel policies from the graph in order to
@@ -552,6 +829,55 @@ type RouterServer interface {
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	// lncli: `freezechannel`
+	// FreezeChannel puts a channel into maintenance mode, which is useful before
+	// a planned downtime of the peer. The channel stops forwarding HTLCs in both
+	// directions and rejects new payments, and is announced as disabled to the
+	// network, but stays open. Freezes don't persist across restarts.
+	FreezeChannel(context.Context, *FreezeChannelRequest) (*FreezeChannelResponse, error)
+	// lncli: `unfreezechannel`
+	// UnfreezeChannel takes a channel out of maintenance mode, so it forwards
+	// HTLCs and is announced as enabled again.
+	UnfreezeChannel(context.Context, *UnfreezeChannelRequest) (*UnfreezeChannelResponse, error)
+	// lncli: `addroutehints`
+	// AddKnownRouteHints registers route hints for a private destination we
+	// know about out of band, for example a client of our LSP or a partner's
+	// private channel. Path finding uses the hints for our outgoing payments
+	// until the time to live elapses. Hints registered for the destination
+	// before are replaced. Known route hints don't persist across restarts.
+	AddKnownRouteHints(context.Context, *AddKnownRouteHintsRequest) (*AddKnownRouteHintsResponse, error)
+	// lncli: `removeroutehints`
+	// RemoveKnownRouteHints removes the route hints registered for a
+	// destination.
+	RemoveKnownRouteHints(context.Context, *RemoveKnownRouteHintsRequest) (*RemoveKnownRouteHintsResponse, error)
+	// lncli: `listroutehints`
+	// ListKnownRouteHints returns the registered route hints that haven't
+	// expired yet.
+	ListKnownRouteHints(context.Context, *ListKnownRouteHintsRequest) (*ListKnownRouteHintsResponse, error)
+	// lncli: `registerjitchannel`
+	// RegisterJITChannel assigns a new short channel id to a client, which the
+	// client puts into the route hints of its invoices. Once an htlc is
+	// forwarded to the short channel id, the htlc is held, a zero-conf channel
+	// is opened to the client and the htlc is released into it. Requires
+	// jitchannel.active to be set.
+	RegisterJITChannel(context.Context, *RegisterJITChannelRequest) (*JITChannel, error)
+	// lncli: `canceljitchannel`
+	// CancelJITChannel removes the registration of a just-in-time channel that
+	// still waits for its first forward.
+	CancelJITChannel(context.Context, *CancelJITChannelRequest) (*CancelJITChannelResponse, error)
+	// lncli: `listjitchannels`
+	// ListJITChannels returns the registered just-in-time channels that wait
+	// for their first forward or are being opened.
+	ListJITChannels(context.Context, *ListJITChannelsRequest) (*ListJITChannelsResponse, error)
+	// lncli: `querynetworkresults`
+	// QueryNetworkResults returns a page of the results of our payment attempts
+	// that are kept in the network result store, ordered by their attempt ID.
+	QueryNetworkResults(context.Context, *QueryNetworkResultsRequest) (*QueryNetworkResultsResponse, error)
+	// lncli: `prunenetworkresults`
+	// PruneNetworkResults removes the network results of resolved attempts
+	// whose retention passed and of attempts whose payment was deleted, without
+	// waiting for the next periodic prune.
+	PruneNetworkResults(context.Context, *PruneNetworkResultsRequest) (*PruneNetworkResultsResponse, error)
 	mustEmbedUnimplementedRouterServer()
 }
 
@@ -562,15 +888,27 @@ type UnimplementedRouterServer struct {
 func (UnimplementedRouterServer) SendPaymentV2(*SendPaymentRequest, Router_SendPaymentV2Server) error {
 	return status.Errorf(codes.Unimplemented, "method SendPaymentV2 not implemented")
 }
+func (UnimplementedRouterServer) SendTrampolinePayment(*SendTrampolinePaymentRequest, Router_SendTrampolinePaymentServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendTrampolinePayment not implemented")
+}
+func (UnimplementedRouterServer) SendAsyncPayment(context.Context, *SendAsyncPaymentRequest) (*SendAsyncPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAsyncPayment not implemented")
+}
 func (UnimplementedRouterServer) TrackPaymentV2(*TrackPaymentRequest, Router_TrackPaymentV2Server) error {
 	return status.Errorf(codes.Unimplemented, "method TrackPaymentV2 not implemented")
 }
 func (UnimplementedRouterServer) TrackPayments(*TrackPaymentsRequest, Router_TrackPaymentsServer) error {
 	return status.Errorf(codes.Unimplemented, "method TrackPayments not implemented")
 }
+func (UnimplementedRouterServer) CancelPayment(context.Context, *CancelPaymentRequest) (*CancelPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelPayment not implemented")
+}
 func (UnimplementedRouterServer) EstimateRouteFee(context.Context, *RouteFeeRequest) (*RouteFeeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EstimateRouteFee not implemented")
 }
+func (UnimplementedRouterServer) QueryRouteFees(context.Context, *QueryRouteFeesRequest) (*QueryRouteFeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRouteFees not implemented")
+}
 func (UnimplementedRouterServer) SendToRoute(context.Context, *SendToRouteRequest) (*SendToRouteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendToRoute not implemented")
 }
@@ -595,6 +933,9 @@ func (UnimplementedRouterServer) SetMissionControlConfig(context.Context, *SetMi
 func (UnimplementedRouterServer) QueryProbability(context.Context, *QueryProbabilityRequest) (*QueryProbabilityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryProbability not implemented")
 }
+func (UnimplementedRouterServer) CompareRouteProbabilities(context.Context, *CompareRouteProbabilitiesRequest) (*CompareRouteProbabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareRouteProbabilities not implemented")
+}
 func (UnimplementedRouterServer) BuildRoute(context.Context, *BuildRouteRequest) (*BuildRouteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildRoute not implemented")
 }
@@ -613,6 +954,36 @@ func (UnimplementedRouterServer) HtlcInterceptor(Router_HtlcInterceptorServer) e
 func (UnimplementedRouterServer) UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChanStatus not implemented")
 }
+func (UnimplementedRouterServer) FreezeChannel(context.Context, *FreezeChannelRequest) (*FreezeChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeChannel not implemented")
+}
+func (UnimplementedRouterServer) UnfreezeChannel(context.Context, *UnfreezeChannelRequest) (*UnfreezeChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfreezeChannel not implemented")
+}
+func (UnimplementedRouterServer) AddKnownRouteHints(context.Context, *AddKnownRouteHintsRequest) (*AddKnownRouteHintsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddKnownRouteHints not implemented")
+}
+func (UnimplementedRouterServer) RemoveKnownRouteHints(context.Context, *RemoveKnownRouteHintsRequest) (*RemoveKnownRouteHintsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveKnownRouteHints not implemented")
+}
+func (UnimplementedRouterServer) ListKnownRouteHints(context.Context, *ListKnownRouteHintsRequest) (*ListKnownRouteHintsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKnownRouteHints not implemented")
+}
+func (UnimplementedRouterServer) RegisterJITChannel(context.Context, *RegisterJITChannelRequest) (*JITChannel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterJITChannel not implemented")
+}
+func (UnimplementedRouterServer) CancelJITChannel(context.Context, *CancelJITChannelRequest) (*CancelJITChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJITChannel not implemented")
+}
+func (UnimplementedRouterServer) ListJITChannels(context.Context, *ListJITChannelsRequest) (*ListJITChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJITChannels not implemented")
+}
+func (UnimplementedRouterServer) QueryNetworkResults(context.Context, *QueryNetworkResultsRequest) (*QueryNetworkResultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryNetworkResults not implemented")
+}
+func (UnimplementedRouterServer) PruneNetworkResults(context.Context, *PruneNetworkResultsRequest) (*PruneNetworkResultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneNetworkResults not implemented")
+}
 func (UnimplementedRouterServer) mustEmbedUnimplementedRouterServer() {}
 
 // UnsafeRouterServer may be embedded to opt out of forward compatibility for this service.
@@ -647,6 +1018,45 @@ func (x *routerSendPaymentV2Server) Send(m *lnrpc.Payment) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Router_SendTrampolinePayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SendTrampolinePaymentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).SendTrampolinePayment(m, &routerSendTrampolinePaymentServer{stream})
+}
+
+type Router_SendTrampolinePaymentServer interface {
+	Send(*lnrpc.Payment) error
+	grpc.ServerStream
+}
+
+type routerSendTrampolinePaymentServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerSendTrampolinePaymentServer) Send(m *lnrpc.Payment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Router_SendAsyncPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAsyncPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).SendAsyncPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/SendAsyncPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).SendAsyncPayment(ctx, req.(*SendAsyncPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_TrackPaymentV2_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(TrackPaymentRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -689,6 +1099,24 @@ func (x *routerTrackPaymentsServer) Send(m *lnrpc.Payment) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Router_CancelPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).CancelPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/CancelPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).CancelPayment(ctx, req.(*CancelPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_EstimateRouteFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RouteFeeRequest)
 	if err := dec(in); err != nil {
@@ -707,6 +1135,24 @@ func _Router_EstimateRouteFee_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_QueryRouteFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRouteFeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).QueryRouteFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/QueryRouteFees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).QueryRouteFees(ctx, req.(*QueryRouteFeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_SendToRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SendToRouteRequest)
 	if err := dec(in); err != nil {
@@ -851,6 +1297,24 @@ func _Router_QueryProbability_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_CompareRouteProbabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareRouteProbabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).CompareRouteProbabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/CompareRouteProbabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).CompareRouteProbabilities(ctx, req.(*CompareRouteProbabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_BuildRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BuildRouteRequest)
 	if err := dec(in); err != nil {
@@ -976,6 +1440,186 @@ func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_FreezeChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).FreezeChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/FreezeChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).FreezeChannel(ctx, req.(*FreezeChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_UnfreezeChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfreezeChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).UnfreezeChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/UnfreezeChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).UnfreezeChannel(ctx, req.(*UnfreezeChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_AddKnownRouteHints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddKnownRouteHintsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).AddKnownRouteHints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/AddKnownRouteHints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).AddKnownRouteHints(ctx, req.(*AddKnownRouteHintsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_RemoveKnownRouteHints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveKnownRouteHintsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).RemoveKnownRouteHints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/RemoveKnownRouteHints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).RemoveKnownRouteHints(ctx, req.(*RemoveKnownRouteHintsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListKnownRouteHints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKnownRouteHintsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListKnownRouteHints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ListKnownRouteHints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListKnownRouteHints(ctx, req.(*ListKnownRouteHintsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_RegisterJITChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterJITChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).RegisterJITChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/RegisterJITChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).RegisterJITChannel(ctx, req.(*RegisterJITChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_CancelJITChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJITChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).CancelJITChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/CancelJITChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).CancelJITChannel(ctx, req.(*CancelJITChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListJITChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJITChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListJITChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ListJITChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListJITChannels(ctx, req.(*ListJITChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_QueryNetworkResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryNetworkResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).QueryNetworkResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/QueryNetworkResults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).QueryNetworkResults(ctx, req.(*QueryNetworkResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_PruneNetworkResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneNetworkResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).PruneNetworkResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/PruneNetworkResults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).PruneNetworkResults(ctx, req.(*PruneNetworkResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Router_ServiceDesc is the grpc.ServiceDesc for Router service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -983,10 +1627,22 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "routerrpc.Router",
 	HandlerType: (*RouterServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendAsyncPayment",
+			Handler:    _Router_SendAsyncPayment_Handler,
+		},
+		{
+			MethodName: "CancelPayment",
+			Handler:    _Router_CancelPayment_Handler,
+		},
 		{
 			MethodName: "EstimateRouteFee",
 			Handler:    _Router_EstimateRouteFee_Handler,
 		},
+		{
+			MethodName: "QueryRouteFees",
+			Handler:    _Router_QueryRouteFees_Handler,
+		},
 		{
 			MethodName: "SendToRoute",
 			Handler:    _Router_SendToRoute_Handler,
@@ -1019,6 +1675,10 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryProbability",
 			Handler:    _Router_QueryProbability_Handler,
 		},
+		{
+			MethodName: "CompareRouteProbabilities",
+			Handler:    _Router_CompareRouteProbabilities_Handler,
+		},
 		{
 			MethodName: "BuildRoute",
 			Handler:    _Router_BuildRoute_Handler,
@@ -1027,6 +1687,46 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateChanStatus",
 			Handler:    _Router_UpdateChanStatus_Handler,
 		},
+		{
+			MethodName: "FreezeChannel",
+			Handler:    _Router_FreezeChannel_Handler,
+		},
+		{
+			MethodName: "UnfreezeChannel",
+			Handler:    _Router_UnfreezeChannel_Handler,
+		},
+		{
+			MethodName: "AddKnownRouteHints",
+			Handler:    _Router_AddKnownRouteHints_Handler,
+		},
+		{
+			MethodName: "RemoveKnownRouteHints",
+			Handler:    _Router_RemoveKnownRouteHints_Handler,
+		},
+		{
+			MethodName: "ListKnownRouteHints",
+			Handler:    _Router_ListKnownRouteHints_Handler,
+		},
+		{
+			MethodName: "RegisterJITChannel",
+			Handler:    _Router_RegisterJITChannel_Handler,
+		},
+		{
+			MethodName: "CancelJITChannel",
+			Handler:    _Router_CancelJITChannel_Handler,
+		},
+		{
+			MethodName: "ListJITChannels",
+			Handler:    _Router_ListJITChannels_Handler,
+		},
+		{
+			MethodName: "QueryNetworkResults",
+			Handler:    _Router_QueryNetworkResults_Handler,
+		},
+		{
+			MethodName: "PruneNetworkResults",
+			Handler:    _Router_PruneNetworkResults_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1034,6 +1734,11 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Router_SendPaymentV2_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SendTrampolinePayment",
+			Handler:       _Router_SendTrampolinePayment_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "TrackPaymentV2",
 			Handler:       _Router_TrackPaymentV2_Handler,
@@ -6,6 +6,7 @@ import (
 	crand "crypto/rand"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sync/atomic"
@@ -15,13 +16,17 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/jitchannel"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/monitoring/tracing"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/trampoline"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -73,6 +78,14 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/SendAsyncPayment": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/SendTrampolinePayment": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/SendToRouteV2": {{
 			Entity: "offchain",
 			Action: "write",
@@ -113,6 +126,10 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/routerrpc.Router/CompareRouteProbabilities": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/routerrpc.Router/ResetMissionControl": {{
 			Entity: "offchain",
 			Action: "write",
@@ -141,6 +158,54 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/CancelPayment": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/FreezeChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/UnfreezeChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/QueryRouteFees": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/AddKnownRouteHints": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/RemoveKnownRouteHints": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/ListKnownRouteHints": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/RegisterJITChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/CancelJITChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/ListJITChannels": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/QueryNetworkResults": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/PruneNetworkResults": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -331,9 +396,61 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 		return err
 	}
 
+	return s.sendPayment(req, payment, stream)
+}
+
+// SendTrampolinePayment attempts to route a payment described by the passed
+// PaymentRequest through the given trampoline node, which finds the route to
+// the final destination itself. The trampoline node is paid according to the
+// given fee policy, out of the fee limit of the payment. Payment updates are
+// streamed back the same way as for SendPaymentV2.
+func (s *Server) SendTrampolinePayment(req *SendTrampolinePaymentRequest,
+	stream Router_SendTrampolinePaymentServer) error {
+
+	if req.Payment == nil {
+		return status.Error(codes.InvalidArgument, "payment required")
+	}
+
+	nodeID, err := route.NewVertexFromBytes(req.TrampolineNode)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid "+
+			"trampoline node: %v", err)
+	}
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(
+		req.Payment,
+	)
+	if err != nil {
+		return err
+	}
+
+	payment.Trampoline = &routing.TrampolineHop{
+		NodeID: nodeID,
+		Policy: trampoline.FeePolicy{
+			BaseFee:   lnwire.MilliSatoshi(req.BaseFeeMsat),
+			FeeRate:   lnwire.MilliSatoshi(req.FeeRatePpm),
+			CltvDelta: req.CltvDelta,
+		},
+	}
+
+	return s.sendPayment(req.Payment, payment, stream)
+}
+
+// sendPayment sends the payment that was extracted from the request and
+// streams its updates.
+func (s *Server) sendPayment(req *SendPaymentRequest,
+	payment *routing.LightningPayment,
+	stream Router_SendPaymentV2Server) error {
+
 	// Get the payment hash.
 	payHash := payment.Identifier()
 
+	// If the macaroon is bound to an account, the payment is attributed
+	// to it before it is initiated.
+	if err := s.attributePayment(stream.Context(), payHash); err != nil {
+		return err
+	}
+
 	// Init the payment in db.
 	paySession, shardTracker, err := s.cfg.Router.PreparePayment(payment)
 	if err != nil {
@@ -350,6 +467,12 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 			)
 		}
 
+		if errors.Is(err, routing.ErrTrampolineUnsupported) {
+			return status.Error(
+				codes.InvalidArgument, err.Error(),
+			)
+		}
+
 		return err
 	}
 
@@ -368,8 +491,10 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 	// The second context parameter is the timeout. If the user provides a
 	// timeout, we will additionally wrap the context in a deadline. If the
 	// user provided 'cancelable' and ends the stream before the timeout is
-	// reached the payment will be canceled.
-	ctx := context.Background()
+	// reached the payment will be canceled. A payment that isn't
+	// cancelable still carries the span of the call, so that it's traced
+	// as part of it.
+	ctx := tracing.Detach(stream.Context())
 	if req.Cancelable {
 		ctx = stream.Context()
 	}
@@ -381,6 +506,84 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 	return s.trackPayment(sub, payHash, stream, req.NoInflightUpdates)
 }
 
+// attributePayment attributes the payment with the given hash to the account
+// the macaroon of the request is bound to, if any.
+func (s *Server) attributePayment(ctx context.Context,
+	hash lntypes.Hash) error {
+
+	account, err := macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if account == "" || s.cfg.RouterBackend.AttributePayment == nil {
+		return nil
+	}
+
+	return s.cfg.RouterBackend.AttributePayment(hash, account)
+}
+
+// SendAsyncPayment holds a payment to an often-offline recipient until the
+// recipient comes online, following the async payments protocol. The
+// recipient is told about the payment over the given blinded path, encoded as
+// a blinded_path of the specification, and the payment is sent once the
+// recipient released it. Only then is the payment known to TrackPaymentV2.
+// Held payments are kept in memory and are dropped on restart.
+func (s *Server) SendAsyncPayment(ctx context.Context,
+	req *SendAsyncPaymentRequest) (*SendAsyncPaymentResponse, error) {
+
+	if s.cfg.RouterBackend.HoldPayment == nil {
+		return nil, status.Error(
+			codes.Unimplemented, "async payments are not enabled",
+		)
+	}
+
+	if req.Payment == nil {
+		return nil, status.Error(
+			codes.InvalidArgument, "payment required",
+		)
+	}
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(
+		req.Payment,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payHash := payment.Identifier()
+	if err := s.attributePayment(ctx, payHash); err != nil {
+		return nil, err
+	}
+
+	// The payment outlives the call, so it must not be canceled along
+	// with it.
+	payCtx := tracing.Detach(ctx)
+	release := func() {
+		paySession, shardTracker, err := s.cfg.Router.PreparePayment(
+			payment,
+		)
+		if err != nil {
+			log.Errorf("Unable to send released payment %x: %v",
+				payHash, err)
+
+			return
+		}
+
+		s.cfg.Router.SendPaymentAsync(
+			payCtx, payment, paySession, shardTracker,
+		)
+	}
+
+	err = s.cfg.RouterBackend.HoldPayment(req.RecipientPath, release)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SendAsyncPaymentResponse{
+		PaymentHash: payHash[:],
+	}, nil
+}
+
 // EstimateRouteFee allows callers to obtain an expected value w.r.t how much it
 // may cost to send an HTLC to the target end destination. This method sends
 // probe payments to the target node, based on target invoice parameters and a
@@ -846,6 +1049,10 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 		return nil, err
 	}
 
+	if err := s.attributePayment(ctx, hash); err != nil {
+		return nil, err
+	}
+
 	var attempt *channeldb.HTLCAttempt
 
 	// Pass route to the router. This call returns the full htlc attempt
@@ -968,13 +1175,28 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 		) * time.Second,
 	}
 
-	switch req.Config.Model {
+	estimator, err := unmarshallEstimator(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	mcCfg.Estimator = estimator
+
+	return &SetMissionControlConfigResponse{},
+		s.cfg.RouterBackend.MissionControl.SetConfig(mcCfg)
+}
+
+// unmarshallEstimator creates the probability estimator described by the
+// model and estimator config of the given mission control config.
+func unmarshallEstimator(cfg *MissionControlConfig) (routing.Estimator,
+	error) {
+
+	switch cfg.Model {
 	case MissionControlConfig_APRIORI:
 		var aprioriConfig routing.AprioriConfig
 
 		// Determine the apriori config with backward compatibility
 		// should the api use deprecated fields.
-		switch v := req.Config.EstimatorConfig.(type) {
+		switch v := cfg.EstimatorConfig.(type) {
 		case *MissionControlConfig_Bimodal:
 			return nil, fmt.Errorf("bimodal config " +
 				"provided, but apriori model requested")
@@ -993,12 +1215,12 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 		default:
 			aprioriConfig = routing.AprioriConfig{
 				PenaltyHalfLife: time.Duration(
-					int64(req.Config.HalfLifeSeconds),
+					int64(cfg.HalfLifeSeconds),
 				) * time.Second,
 				AprioriHopProbability: float64(
-					req.Config.HopProbability,
+					cfg.HopProbability,
 				),
-				AprioriWeight:    float64(req.Config.Weight),
+				AprioriWeight:    float64(cfg.Weight),
 				CapacityFraction: routing.DefaultCapacityFraction, //nolint:lll
 			}
 		}
@@ -1007,16 +1229,17 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 		if err != nil {
 			return nil, err
 		}
-		mcCfg.Estimator = estimator
+
+		return estimator, nil
 
 	case MissionControlConfig_BIMODAL:
-		cfg, ok := req.Config.
+		bimodal, ok := cfg.
 			EstimatorConfig.(*MissionControlConfig_Bimodal)
 		if !ok {
 			return nil, fmt.Errorf("bimodal estimator requested " +
 				"but corresponding config not set")
 		}
-		bCfg := cfg.Bimodal
+		bCfg := bimodal.Bimodal
 
 		bimodalConfig := routing.BimodalConfig{
 			BimodalDecayTime: time.Duration(
@@ -1030,15 +1253,59 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 		if err != nil {
 			return nil, err
 		}
-		mcCfg.Estimator = estimator
+
+		return estimator, nil
 
 	default:
 		return nil, fmt.Errorf("unknown estimator type %v",
-			req.Config.Model)
+			cfg.Model)
 	}
+}
 
-	return &SetMissionControlConfigResponse{},
-		s.cfg.RouterBackend.MissionControl.SetConfig(mcCfg)
+// CompareRouteProbabilities estimates the success probability of a route with
+// each of the requested estimator configs, so that the outputs of different
+// probability models can be compared.
+func (s *Server) CompareRouteProbabilities(_ context.Context,
+	req *CompareRouteProbabilitiesRequest) (
+	*CompareRouteProbabilitiesResponse, error) {
+
+	if req.Route == nil {
+		return nil, errors.New("route must be set")
+	}
+	if len(req.Configs) == 0 {
+		return nil, errors.New("at least one estimator config must " +
+			"be set")
+	}
+
+	rt, err := s.cfg.RouterBackend.UnmarshallRoute(req.Route)
+	if err != nil {
+		return nil, err
+	}
+
+	estimators := make([]routing.Estimator, 0, len(req.Configs))
+	for _, cfg := range req.Configs {
+		if cfg == nil {
+			return nil, errors.New("estimator config must be set")
+		}
+
+		estimator, err := unmarshallEstimator(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		estimators = append(estimators, estimator)
+	}
+
+	probabilities, err := s.cfg.RouterBackend.CompareRouteProbabilities(
+		rt, estimators...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompareRouteProbabilitiesResponse{
+		Probabilities: probabilities,
+	}, nil
 }
 
 // QueryMissionControl exposes the internal mission control state to callers. It
@@ -1526,8 +1793,7 @@ func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
 	).run()
 }
 
-func extractOutPoint(req *UpdateChanStatusRequest) (*wire.OutPoint, error) {
-	chanPoint := req.GetChanPoint()
+func extractOutPoint(chanPoint *lnrpc.ChannelPoint) (*wire.OutPoint, error) {
 	txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
 	if err != nil {
 		return nil, err
@@ -1540,7 +1806,7 @@ func extractOutPoint(req *UpdateChanStatusRequest) (*wire.OutPoint, error) {
 func (s *Server) UpdateChanStatus(_ context.Context,
 	req *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error) {
 
-	outPoint, err := extractOutPoint(req)
+	outPoint, err := extractOutPoint(req.GetChanPoint())
 	if err != nil {
 		return nil, err
 	}
@@ -1566,3 +1832,369 @@ func (s *Server) UpdateChanStatus(_ context.Context,
 	}
 	return &UpdateChanStatusResponse{}, nil
 }
+
+// CancelPayment cancels the in-flight payment with the given hash. No new HTLC
+// attempts are made for the payment, and it is failed once the results of
+// the attempts already in flight are known. The final status of the payment
+// is reported to the streams tracking it.
+func (s *Server) CancelPayment(_ context.Context,
+	req *CancelPaymentRequest) (*CancelPaymentResponse, error) {
+
+	paymentHash, err := lntypes.MakeHash(req.PaymentHash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Debugf("CancelPayment called for payment %v", paymentHash)
+
+	err = s.cfg.RouterBackend.CancelPayment(paymentHash)
+	switch {
+	case errors.Is(err, routing.ErrPaymentNotActive):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &CancelPaymentResponse{}, nil
+}
+
+// FreezeChannel puts a channel into maintenance mode, which is useful before
+// a planned downtime of the peer. The channel stops forwarding HTLCs in both
+// directions and rejects new payments, and is announced as disabled to the
+// network, but stays open. If the duration is non-zero, the channel is
+// unfrozen automatically once it elapsed.
+func (s *Server) FreezeChannel(_ context.Context,
+	req *FreezeChannelRequest) (*FreezeChannelResponse, error) {
+
+	chanPoint, err := extractOutPoint(req.GetChanPoint())
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject durations that would overflow a time.Duration.
+	if req.DurationSeconds > uint64(math.MaxInt64/time.Second) {
+		return nil, fmt.Errorf("freeze duration of %v seconds out of "+
+			"range", req.DurationSeconds)
+	}
+	duration := time.Duration(req.DurationSeconds) * time.Second
+
+	log.Debugf("FreezeChannel called for channel(%v) with duration %v",
+		chanPoint, duration)
+
+	err = s.cfg.RouterBackend.FreezeChannel(*chanPoint, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FreezeChannelResponse{}, nil
+}
+
+// UnfreezeChannel takes a channel out of maintenance mode, so it forwards
+// HTLCs and is announced as enabled again.
+func (s *Server) UnfreezeChannel(_ context.Context,
+	req *UnfreezeChannelRequest) (*UnfreezeChannelResponse, error) {
+
+	chanPoint, err := extractOutPoint(req.GetChanPoint())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("UnfreezeChannel called for channel(%v)", chanPoint)
+
+	err = s.cfg.RouterBackend.UnfreezeChannel(*chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnfreezeChannelResponse{}, nil
+}
+
+// QueryRouteFees finds up to numRoutes of the best routes to the destination
+// of the request and breaks down the fees expected along each of them by the
+// nodes that charge them. Next to the fees paid to each hop, the inbound and
+// outbound fees our own policy for the first channel of a route charges for
+// the same amount are returned, to put the cost of the payment into
+// perspective.
+func (s *Server) QueryRouteFees(_ context.Context,
+	req *QueryRouteFeesRequest) (*QueryRouteFeesResponse, error) {
+
+	if req.Query == nil {
+		return nil, status.Error(codes.InvalidArgument, "query is "+
+			"required")
+	}
+
+	if req.NumRoutes < 1 {
+		return nil, status.Error(codes.InvalidArgument, "at least "+
+			"one route must be queried")
+	}
+
+	routes, err := s.cfg.RouterBackend.QueryRouteFees(
+		req.Query, int(req.NumRoutes),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryRouteFeesResponse{
+		Routes: routes,
+	}, nil
+}
+
+// AddKnownRouteHints registers route hints for a private destination we know
+// about out of band, for example a client of our LSP or a partner's private
+// channel. Path finding uses the hints for our outgoing payments until the
+// time to live elapses. With the destination scope, only payments to the
+// destination use the hints, with the all scope any payment may route through
+// the hinted channels. Hints registered for the destination before are
+// replaced.
+func (s *Server) AddKnownRouteHints(_ context.Context,
+	req *AddKnownRouteHintsRequest) (*AddKnownRouteHintsResponse, error) {
+
+	dest, err := route.NewVertexFromBytes(req.Dest)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	hints, err := unmarshallRouteHints(req.RouteHints)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var scope routing.HintScope
+	switch req.Scope {
+	case RouteHintScope_ROUTE_HINT_SCOPE_DESTINATION:
+		scope = routing.HintScopeDestination
+
+	case RouteHintScope_ROUTE_HINT_SCOPE_ALL:
+		scope = routing.HintScopeAll
+
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown "+
+			"route hint scope %v", req.Scope)
+	}
+
+	// Reject time to lives that would overflow a time.Duration.
+	if req.TtlSeconds > uint64(math.MaxInt64/time.Second) {
+		return nil, status.Errorf(codes.InvalidArgument, "route hint "+
+			"ttl of %v seconds out of range", req.TtlSeconds)
+	}
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+
+	log.Debugf("AddKnownRouteHints called for %v with %d hints, scope %v "+
+		"and ttl %v", dest, len(hints), scope, ttl)
+
+	err = s.cfg.RouterBackend.KnownRouteHints.Add(dest, hints, ttl, scope)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &AddKnownRouteHintsResponse{}, nil
+}
+
+// RemoveKnownRouteHints removes the route hints registered for a destination.
+func (s *Server) RemoveKnownRouteHints(_ context.Context,
+	req *RemoveKnownRouteHintsRequest) (*RemoveKnownRouteHintsResponse,
+	error) {
+
+	dest, err := route.NewVertexFromBytes(req.Dest)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Debugf("RemoveKnownRouteHints called for %v", dest)
+
+	err = s.cfg.RouterBackend.KnownRouteHints.Remove(dest)
+	switch {
+	case errors.Is(err, routing.ErrNoKnownRouteHints):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &RemoveKnownRouteHintsResponse{}, nil
+}
+
+// ListKnownRouteHints returns the registered route hints that haven't expired
+// yet.
+func (s *Server) ListKnownRouteHints(_ context.Context,
+	_ *ListKnownRouteHintsRequest) (*ListKnownRouteHintsResponse, error) {
+
+	knownHints := s.cfg.RouterBackend.KnownRouteHints.List()
+
+	rpcHints := make([]*KnownRouteHint, 0, len(knownHints))
+	for _, known := range knownHints {
+		scope := RouteHintScope_ROUTE_HINT_SCOPE_DESTINATION
+		if known.Scope == routing.HintScopeAll {
+			scope = RouteHintScope_ROUTE_HINT_SCOPE_ALL
+		}
+
+		rpcHints = append(rpcHints, &KnownRouteHint{
+			Dest: known.Destination[:],
+			RouteHints: invoicesrpc.CreateRPCRouteHints(
+				known.Hints,
+			),
+			Scope:  scope,
+			Expiry: known.Expiry.Unix(),
+		})
+	}
+
+	return &ListKnownRouteHintsResponse{
+		KnownRouteHints: rpcHints,
+	}, nil
+}
+
+// errJITChannelsInactive is returned by the just-in-time channel RPCs if
+// just-in-time channels aren't active.
+var errJITChannelsInactive = status.Error(
+	codes.FailedPrecondition, "just-in-time channels are not active",
+)
+
+// RegisterJITChannel assigns a new short channel id to a client, which the
+// client puts into the route hints of its invoices. Once an htlc is forwarded
+// to the short channel id, the htlc is held, a zero-conf channel is opened to
+// the client and the htlc is released into it.
+func (s *Server) RegisterJITChannel(_ context.Context,
+	req *RegisterJITChannelRequest) (*JITChannel, error) {
+
+	if s.cfg.RouterBackend.JITChannels == nil {
+		return nil, errJITChannelsInactive
+	}
+
+	client, err := route.NewVertexFromBytes(req.Client)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Debugf("RegisterJITChannel called for client %v", client)
+
+	reg, err := s.cfg.RouterBackend.JITChannels.Register(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshallJITChannel(reg), nil
+}
+
+// CancelJITChannel removes the registration of a just-in-time channel that
+// still waits for its first forward.
+func (s *Server) CancelJITChannel(_ context.Context,
+	req *CancelJITChannelRequest) (*CancelJITChannelResponse, error) {
+
+	if s.cfg.RouterBackend.JITChannels == nil {
+		return nil, errJITChannelsInactive
+	}
+
+	scid := lnwire.NewShortChanIDFromInt(req.Scid)
+
+	log.Debugf("CancelJITChannel called for %v", scid)
+
+	err := s.cfg.RouterBackend.JITChannels.Cancel(scid)
+	switch {
+	case errors.Is(err, jitchannel.ErrUnknownChannel):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case errors.Is(err, jitchannel.ErrChannelOpening):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &CancelJITChannelResponse{}, nil
+}
+
+// ListJITChannels returns the registered just-in-time channels that wait for
+// their first forward or are being opened.
+func (s *Server) ListJITChannels(_ context.Context,
+	_ *ListJITChannelsRequest) (*ListJITChannelsResponse, error) {
+
+	if s.cfg.RouterBackend.JITChannels == nil {
+		return nil, errJITChannelsInactive
+	}
+
+	regs := s.cfg.RouterBackend.JITChannels.Registrations()
+
+	channels := make([]*JITChannel, 0, len(regs))
+	for _, reg := range regs {
+		channels = append(channels, marshallJITChannel(reg))
+	}
+
+	return &ListJITChannelsResponse{
+		Channels: channels,
+	}, nil
+}
+
+// marshallJITChannel marshalls a just-in-time channel registration to its rpc
+// representation.
+func marshallJITChannel(reg *jitchannel.Registration) *JITChannel {
+	state := JITChannelState_JIT_CHANNEL_STATE_WAITING
+	if reg.State == jitchannel.StateOpening {
+		state = JITChannelState_JIT_CHANNEL_STATE_OPENING
+	}
+
+	return &JITChannel{
+		Scid:         reg.SCID.ToUint64(),
+		Client:       reg.Client[:],
+		Expiry:       reg.Expiry.Unix(),
+		State:        state,
+		HeldForwards: uint32(reg.HeldForwards),
+	}
+}
+
+// QueryNetworkResults returns a page of the results of our payment attempts
+// that are kept in the network result store, ordered by their attempt ID.
+func (s *Server) QueryNetworkResults(_ context.Context,
+	req *QueryNetworkResultsRequest) (*QueryNetworkResultsResponse, error) {
+
+	results, err := s.cfg.RouterBackend.QueryNetworkResults(
+		&htlcswitch.NetworkResultsQuery{
+			FirstAttemptID: req.FirstAttemptId,
+			MaxResults:     int(req.MaxResults),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcResults := make([]*NetworkResult, 0, len(results))
+	for _, result := range results {
+		rpcResult := &NetworkResult{
+			AttemptId:    result.AttemptID,
+			Settled:      result.Settled,
+			Unencrypted:  result.Unencrypted,
+			IsResolution: result.IsResolution,
+		}
+		if !result.StoredAt.IsZero() {
+			rpcResult.StoredAt = result.StoredAt.Unix()
+		}
+		if result.Settled {
+			rpcResult.Preimage = result.Preimage[:]
+		}
+
+		rpcResults = append(rpcResults, rpcResult)
+	}
+
+	return &QueryNetworkResultsResponse{
+		Results: rpcResults,
+	}, nil
+}
+
+// PruneNetworkResults removes the network results of resolved attempts whose
+// retention passed and of attempts whose payment was deleted, without waiting
+// for the next periodic prune. It returns the number of removed results.
+func (s *Server) PruneNetworkResults(_ context.Context,
+	_ *PruneNetworkResultsRequest) (*PruneNetworkResultsResponse, error) {
+
+	log.Debugf("PruneNetworkResults called")
+
+	numPruned, err := s.cfg.RouterBackend.PruneNetworkResults()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PruneNetworkResultsResponse{
+		NumPruned: uint64(numPruned),
+	}, nil
+}
@@ -2,18 +2,29 @@ package routerrpc
 
 import (
 	"context"
+	"encoding/hex"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/jitchannel"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type streamMock struct {
@@ -400,3 +411,541 @@ func TestIsLsp(t *testing.T) {
 		})
 	}
 }
+
+// TestCancelPayment tests that CancelPayment decodes the payment hash and maps
+// the errors of the router.
+func TestCancelPayment(t *testing.T) {
+	t.Parallel()
+
+	active := lntypes.Hash{1}
+
+	var canceled []lntypes.Hash
+	cancelPayment := func(hash lntypes.Hash) error {
+		if hash != active {
+			return routing.ErrPaymentNotActive
+		}
+
+		canceled = append(canceled, hash)
+
+		return nil
+	}
+
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{
+				CancelPayment: cancelPayment,
+			},
+		},
+	}
+
+	cancel := func(hash []byte) (*CancelPaymentResponse, error) {
+		return server.CancelPayment(
+			context.Background(), &CancelPaymentRequest{
+				PaymentHash: hash,
+			},
+		)
+	}
+
+	resp, err := cancel(active[:])
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Equal(t, []lntypes.Hash{active}, canceled)
+
+	_, err = cancel(make([]byte, lntypes.HashSize))
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = cancel([]byte{1, 2, 3})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestFreezeChannel tests that channels are frozen and unfrozen through the
+// router backend with the requested duration.
+func TestFreezeChannel(t *testing.T) {
+	t.Parallel()
+
+	var (
+		frozen   = make(map[wire.OutPoint]time.Duration)
+		rpcPoint = &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: make([]byte, 32),
+			},
+			OutputIndex: 1,
+		}
+		chanPoint = wire.OutPoint{Index: 1}
+	)
+
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{
+				FreezeChannel: func(op wire.OutPoint,
+					d time.Duration) error {
+
+					frozen[op] = d
+					return nil
+				},
+				UnfreezeChannel: func(op wire.OutPoint) error {
+					delete(frozen, op)
+					return nil
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	_, err := server.FreezeChannel(ctx, &FreezeChannelRequest{
+		ChanPoint:       rpcPoint,
+		DurationSeconds: 60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, frozen[chanPoint])
+
+	_, err = server.UnfreezeChannel(ctx, &UnfreezeChannelRequest{
+		ChanPoint: rpcPoint,
+	})
+	require.NoError(t, err)
+	require.Empty(t, frozen)
+
+	// Durations that overflow a time.Duration and requests without a
+	// channel point are rejected.
+	_, err = server.FreezeChannel(ctx, &FreezeChannelRequest{
+		ChanPoint:       rpcPoint,
+		DurationSeconds: math.MaxUint64,
+	})
+	require.ErrorContains(t, err, "out of range")
+
+	_, err = server.FreezeChannel(ctx, &FreezeChannelRequest{})
+	require.Error(t, err)
+	require.Empty(t, frozen)
+}
+
+// TestCompareRouteProbabilitiesRPC tests that the probability of a route is
+// estimated with each of the requested estimator configs.
+func TestCompareRouteProbabilitiesRPC(t *testing.T) {
+	t.Parallel()
+
+	backend := &RouterBackend{
+		SelfNode:       sourceKey,
+		MissionControl: &mockMissionControl{},
+		FetchChannelCapacity: func(chanID uint64) (btcutil.Amount,
+			error) {
+
+			return 1_000_000, nil
+		},
+	}
+	server := &Server{cfg: &Config{RouterBackend: backend}}
+
+	aCfg := routing.DefaultAprioriConfig()
+	bCfg := routing.DefaultBimodalConfig()
+	configs := []*MissionControlConfig{{
+		Model: MissionControlConfig_APRIORI,
+		EstimatorConfig: &MissionControlConfig_Apriori{
+			Apriori: &AprioriParameters{
+				HalfLifeSeconds: uint64(
+					aCfg.PenaltyHalfLife.Seconds(),
+				),
+				HopProbability:   aCfg.AprioriHopProbability,
+				Weight:           aCfg.AprioriWeight,
+				CapacityFraction: aCfg.CapacityFraction,
+			},
+		},
+	}, {
+		Model: MissionControlConfig_BIMODAL,
+		EstimatorConfig: &MissionControlConfig_Bimodal{
+			Bimodal: &BimodalParameters{
+				NodeWeight: bCfg.BimodalNodeWeight,
+				ScaleMsat:  uint64(bCfg.BimodalScaleMsat),
+				DecayTime: uint64(
+					bCfg.BimodalDecayTime.Seconds(),
+				),
+			},
+		},
+	}}
+
+	rpcRoute := &lnrpc.Route{
+		TotalAmtMsat: 100,
+		Hops: []*lnrpc.Hop{{
+			ChanId:           1,
+			PubKey:           hex.EncodeToString(node1[:]),
+			AmtToForwardMsat: 100,
+		}, {
+			ChanId:           2,
+			PubKey:           hex.EncodeToString(node2[:]),
+			AmtToForwardMsat: 100,
+		}},
+	}
+
+	ctx := context.Background()
+	resp, err := server.CompareRouteProbabilities(
+		ctx, &CompareRouteProbabilitiesRequest{
+			Route:   rpcRoute,
+			Configs: configs,
+		},
+	)
+	require.NoError(t, err)
+	require.InDeltaSlice(
+		t, []float64{0.25, 0.64}, resp.Probabilities, 1e-9,
+	)
+
+	// A request without estimator configs, or with a config that doesn't
+	// match its model, is rejected.
+	_, err = server.CompareRouteProbabilities(
+		ctx, &CompareRouteProbabilitiesRequest{Route: rpcRoute},
+	)
+	require.Error(t, err)
+
+	_, err = server.CompareRouteProbabilities(
+		ctx, &CompareRouteProbabilitiesRequest{
+			Route: rpcRoute,
+			Configs: []*MissionControlConfig{{
+				Model: MissionControlConfig_BIMODAL,
+			}},
+		},
+	)
+	require.ErrorContains(t, err, "corresponding config not set")
+}
+
+// TestKnownRouteHints tests that route hints are registered, listed and
+// removed through the known route hints RPCs.
+func TestKnownRouteHints(t *testing.T) {
+	t.Parallel()
+
+	hopKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	destKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	dest := destKey.PubKey().SerializeCompressed()
+
+	testClock := clock.NewTestClock(time.Unix(1700000000, 0))
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{
+				KnownRouteHints: routing.NewKnownRouteHints(
+					testClock,
+				),
+			},
+		},
+	}
+
+	routeHints := []*lnrpc.RouteHint{{
+		HopHints: []*lnrpc.HopHint{{
+			NodeId: hex.EncodeToString(
+				hopKey.PubKey().SerializeCompressed(),
+			),
+			ChanId:                    123,
+			FeeBaseMsat:               1000,
+			FeeProportionalMillionths: 1,
+			CltvExpiryDelta:           40,
+		}},
+	}}
+
+	ctx := context.Background()
+	_, err = server.AddKnownRouteHints(ctx, &AddKnownRouteHintsRequest{
+		Dest:       dest,
+		RouteHints: routeHints,
+		TtlSeconds: 60,
+		Scope:      RouteHintScope_ROUTE_HINT_SCOPE_ALL,
+	})
+	require.NoError(t, err)
+
+	resp, err := server.ListKnownRouteHints(
+		ctx, &ListKnownRouteHintsRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.KnownRouteHints, 1)
+
+	known := resp.KnownRouteHints[0]
+	require.Equal(t, dest, known.Dest)
+	require.Equal(t, RouteHintScope_ROUTE_HINT_SCOPE_ALL, known.Scope)
+	require.Equal(t, testClock.Now().Unix()+60, known.Expiry)
+	require.Len(t, known.RouteHints, 1)
+	require.Equal(
+		t, routeHints[0].HopHints[0].String(),
+		known.RouteHints[0].HopHints[0].String(),
+	)
+
+	// Invalid requests are rejected.
+	_, err = server.AddKnownRouteHints(ctx, &AddKnownRouteHintsRequest{
+		Dest:       dest,
+		RouteHints: routeHints,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.AddKnownRouteHints(ctx, &AddKnownRouteHintsRequest{
+		Dest:       dest,
+		RouteHints: routeHints,
+		TtlSeconds: 60,
+		Scope:      RouteHintScope(5),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.AddKnownRouteHints(ctx, &AddKnownRouteHintsRequest{
+		Dest:       []byte{1, 2, 3},
+		RouteHints: routeHints,
+		TtlSeconds: 60,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Once removed, the hints are no longer listed and can't be removed
+	// again.
+	removeReq := &RemoveKnownRouteHintsRequest{Dest: dest}
+	_, err = server.RemoveKnownRouteHints(ctx, removeReq)
+	require.NoError(t, err)
+
+	resp, err = server.ListKnownRouteHints(
+		ctx, &ListKnownRouteHintsRequest{},
+	)
+	require.NoError(t, err)
+	require.Empty(t, resp.KnownRouteHints)
+
+	_, err = server.RemoveKnownRouteHints(ctx, removeReq)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestJITChannels tests that just-in-time channels are registered, listed and
+// cancelled through the just-in-time channel RPCs.
+func TestJITChannels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := route.Vertex{1, 2, 3}
+
+	// Without the just-in-time channel manager, the RPCs are rejected.
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{},
+		},
+	}
+	_, err := server.ListJITChannels(ctx, &ListJITChannelsRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	scid := lnwire.ShortChannelID{BlockHeight: 16_000_000, TxIndex: 1}
+	testClock := clock.NewTestClock(time.Unix(1700000000, 0))
+	server.cfg.RouterBackend.JITChannels = jitchannel.NewManager(
+		&jitchannel.Config{
+			RequestAlias: func() (lnwire.ShortChannelID, error) {
+				return scid, nil
+			},
+			Clock:           testClock,
+			RegistrationTTL: time.Hour,
+		},
+	)
+
+	resp, err := server.RegisterJITChannel(
+		ctx, &RegisterJITChannelRequest{Client: client[:]},
+	)
+	require.NoError(t, err)
+
+	expected := &JITChannel{
+		Scid:   scid.ToUint64(),
+		Client: client[:],
+		Expiry: testClock.Now().Add(time.Hour).Unix(),
+		State:  JITChannelState_JIT_CHANNEL_STATE_WAITING,
+	}
+	require.Equal(t, expected.String(), resp.String())
+
+	list, err := server.ListJITChannels(ctx, &ListJITChannelsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Channels, 1)
+	require.Equal(t, expected.String(), list.Channels[0].String())
+
+	_, err = server.RegisterJITChannel(
+		ctx, &RegisterJITChannelRequest{Client: []byte{1}},
+	)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Once cancelled, the channel is no longer listed and can't be
+	// cancelled again.
+	cancelReq := &CancelJITChannelRequest{Scid: scid.ToUint64()}
+	_, err = server.CancelJITChannel(ctx, cancelReq)
+	require.NoError(t, err)
+
+	list, err = server.ListJITChannels(ctx, &ListJITChannelsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, list.Channels)
+
+	_, err = server.CancelJITChannel(ctx, cancelReq)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestNetworkResults tests that the network results are queried and pruned
+// through the network result RPCs.
+func TestNetworkResults(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	preimage := lntypes.Preimage{1, 2, 3}
+	storedAt := time.Unix(1700000000, 0)
+
+	var query *htlcswitch.NetworkResultsQuery
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{
+				QueryNetworkResults: func(
+					q *htlcswitch.NetworkResultsQuery) (
+					[]*htlcswitch.NetworkResultInfo,
+					error) {
+
+					query = q
+
+					return []*htlcswitch.NetworkResultInfo{{
+						AttemptID: 5,
+						StoredAt:  storedAt,
+						Settled:   true,
+						Preimage:  preimage,
+					}, {
+						AttemptID:    6,
+						Preimage:     preimage,
+						Unencrypted:  true,
+						IsResolution: true,
+					}}, nil
+				},
+				PruneNetworkResults: func() (int, error) {
+					return 2, nil
+				},
+			},
+		},
+	}
+
+	resp, err := server.QueryNetworkResults(
+		ctx, &QueryNetworkResultsRequest{
+			FirstAttemptId: 5,
+			MaxResults:     10,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, &htlcswitch.NetworkResultsQuery{
+		FirstAttemptID: 5,
+		MaxResults:     10,
+	}, query)
+
+	// The preimage is only set for settled results, and results stored
+	// before the time was recorded have no storage time.
+	require.Len(t, resp.Results, 2)
+	require.Equal(t, (&NetworkResult{
+		AttemptId: 5,
+		StoredAt:  storedAt.Unix(),
+		Settled:   true,
+		Preimage:  preimage[:],
+	}).String(), resp.Results[0].String())
+	require.Equal(t, (&NetworkResult{
+		AttemptId:    6,
+		Unencrypted:  true,
+		IsResolution: true,
+	}).String(), resp.Results[1].String())
+
+	pruneResp, err := server.PruneNetworkResults(
+		ctx, &PruneNetworkResultsRequest{},
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, pruneResp.NumPruned)
+}
+
+// TestSendAsyncPayment asserts that async payments are handed to the backend
+// to be held, and are rejected if async payments are disabled.
+func TestSendAsyncPayment(t *testing.T) {
+	t.Parallel()
+
+	dest, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	recipientPath := []byte{1, 2, 3}
+	req := &SendAsyncPaymentRequest{
+		Payment: &SendPaymentRequest{
+			Dest:           dest.PubKey().SerializeCompressed(),
+			Amt:            1000,
+			PaymentHash:    make([]byte, lntypes.HashSize),
+			FinalCltvDelta: 40,
+			TimeoutSeconds: 60,
+		},
+		RecipientPath: recipientPath,
+	}
+
+	backend := &RouterBackend{
+		MaxTotalTimelock:      1000,
+		DefaultFinalCltvDelta: 40,
+	}
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: backend,
+		},
+	}
+
+	_, err = server.SendAsyncPayment(context.Background(), req)
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	var heldPaths [][]byte
+	backend.HoldPayment = func(path []byte, _ func()) error {
+		heldPaths = append(heldPaths, path)
+		return nil
+	}
+
+	resp, err := server.SendAsyncPayment(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, req.Payment.PaymentHash, resp.PaymentHash)
+	require.Equal(t, [][]byte{recipientPath}, heldPaths)
+
+	// Requests without a payment are rejected.
+	_, err = server.SendAsyncPayment(
+		context.Background(), &SendAsyncPaymentRequest{
+			RecipientPath: recipientPath,
+		},
+	)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Invalid payments are rejected before they are held.
+	req.Payment.TimeoutSeconds = 0
+	_, err = server.SendAsyncPayment(context.Background(), req)
+	require.Error(t, err)
+	require.Len(t, heldPaths, 1)
+}
+
+// TestSendTrampolinePayment asserts that trampoline payments with an invalid
+// trampoline node or an invalid payment request are rejected before they are
+// sent.
+func TestSendTrampolinePayment(t *testing.T) {
+	t.Parallel()
+
+	dest, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	trampolineNode, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	req := &SendPaymentRequest{
+		Dest:           dest.PubKey().SerializeCompressed(),
+		Amt:            1000,
+		PaymentHash:    make([]byte, lntypes.HashSize),
+		FinalCltvDelta: 40,
+	}
+
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{
+				MaxTotalTimelock:      1000,
+				DefaultFinalCltvDelta: 40,
+			},
+		},
+	}
+	stream := makeStreamMock(context.Background())
+
+	err = server.SendTrampolinePayment(&SendTrampolinePaymentRequest{
+		TrampolineNode: trampolineNode.PubKey().SerializeCompressed(),
+	}, stream)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = server.SendTrampolinePayment(&SendTrampolinePaymentRequest{
+		Payment:        req,
+		TrampolineNode: []byte{1, 2, 3},
+	}, stream)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The request is missing the timeout.
+	err = server.SendTrampolinePayment(&SendTrampolinePaymentRequest{
+		Payment:        req,
+		TrampolineNode: trampolineNode.PubKey().SerializeCompressed(),
+		BaseFeeMsat:    1000,
+		FeeRatePpm:     100,
+		CltvDelta:      144,
+	}, stream)
+	require.Error(t, err)
+}
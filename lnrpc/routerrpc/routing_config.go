@@ -28,6 +28,40 @@ type RoutingConfig struct {
 	// expressed in parts per million of the total payment amount.
 	AttemptCostPPM int64 `long:"attemptcostppm" description:"The proportional (virtual) cost in sats of a failed payment attempt expressed in parts per million of the total payment amount"`
 
+	// EndorsementAware enables the experimental endorsement aware path
+	// finding, which prefers hops that are likely to give our HTLCs
+	// endorsed treatment based on the reputation built up through past
+	// payments.
+	EndorsementAware bool `long:"endorsement-aware" description:"(experimental) prefer hops that are likely to give our HTLCs endorsed treatment based on the reputation built up through past payments"`
+
+	// EndorsementWindow is the period over which a successful payment
+	// across a node pair is considered to have built up reputation.
+	EndorsementWindow time.Duration `long:"endorsement-window" description:"the period over which a successful payment across a node pair is considered to have built up reputation for endorsement aware path finding"`
+
+	// BlindedFeeTolerancePPM is the maximum aggregate fee, expressed in
+	// parts per million of the amount delivered to the receiver, that we
+	// are willing to pay to the blinded portion of a route.
+	BlindedFeeTolerancePPM int64 `long:"blinded-fee-tolerance-ppm" description:"the maximum aggregate fee in ppm of the payment amount that we are willing to pay to the blinded portion of a route when paying to blinded paths, blinded paths that charge more are skipped; set to 0 to disable"`
+
+	// ParallelPathSearches is the maximum number of path searches for
+	// different shard amounts that run concurrently when splitting a
+	// multi-part payment.
+	ParallelPathSearches int `long:"parallel-path-searches" description:"the maximum number of path searches for successively smaller shard amounts that run concurrently when no route is found for the full amount of a multi-part payment; set to 1 to search sequentially"`
+
+	// RouteCacheTTL is the time for which the path of a successful payment
+	// is cached and reused for payments to the same destination and of a
+	// similar amount. A value of zero disables the route cache.
+	RouteCacheTTL time.Duration `long:"route-cache-ttl" description:"the time for which the path of a successful payment is reused for payments to the same destination with an amount of the same order of magnitude, cached paths are invalidated when any of their channels is updated; set to 0 to disable the route cache"`
+
+	// ShadowRouteMaxCltv is the maximum number of blocks of random padding
+	// that is added to the final CLTV delta of a payment's routes.
+	ShadowRouteMaxCltv uint16 `long:"shadow-route-max-cltv" description:"the maximum number of blocks of random padding that is added to the final CLTV delta of a payment's routes, so that the receiver can't infer its distance to the sender; at most half of the CLTV budget of the payment is used for padding; set to 0 to disable"`
+
+	// AmountRandomization is the strategy used to pick the amount of the
+	// next shard when no route can be found for the current amount of a
+	// payment.
+	AmountRandomization string `long:"amount-randomization" choice:"none" choice:"shard" description:"the strategy used to pick the amount of the next shard when no route is found for the current amount of a payment; 'none' halves the amount, 'shard' picks a random amount between 3/8 and 5/8 of it so that shards of the same payment are harder to relate to each other"`
+
 	// MaxMcHistory defines the maximum number of payment results that
 	// are held on disk by mission control.
 	MaxMcHistory int `long:"maxmchistory" description:"the maximum number of payment results that are held on disk by mission control"`
@@ -139,6 +139,38 @@ func RPCTransactionDetails(txns []*lnwallet.TransactionDetail) *TransactionDetai
 	return txDetails
 }
 
+// RPCAddressReuse returns the rpc representation of the given reused output
+// scripts.
+func RPCAddressReuse(
+	reused []*lnwallet.AddressReuse) *AddressReuseReportResponse {
+
+	resp := &AddressReuseReportResponse{
+		ReusedAddresses: make([]*AddressReuse, len(reused)),
+	}
+
+	for i, use := range reused {
+		addresses := make([]string, len(use.Addresses))
+		for j, addr := range use.Addresses {
+			addresses[j] = addr.EncodeAddress()
+		}
+
+		txHashes := make([]string, len(use.Txids))
+		for j, txid := range use.Txids {
+			txHashes[j] = txid.String()
+		}
+
+		resp.ReusedAddresses[i] = &AddressReuse{
+			PkScript:     hex.EncodeToString(use.PkScript),
+			Addresses:    addresses,
+			IsOurAddress: use.IsOurAddress,
+			TxHashes:     txHashes,
+			TotalValue:   int64(use.TotalValue),
+		}
+	}
+
+	return resp
+}
+
 // ExtractMinConfs extracts the minimum number of confirmations that each
 // output used to fund a transaction should satisfy.
 func ExtractMinConfs(minConfs int32, spendUnconfirmed bool) (int32, error) {
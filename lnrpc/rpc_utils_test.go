@@ -0,0 +1,45 @@
+package lnrpc
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRPCAddressReuse tests that reused output scripts are converted to their
+// rpc representation.
+func TestRPCAddressReuse(t *testing.T) {
+	t.Parallel()
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+
+	resp := RPCAddressReuse([]*lnwallet.AddressReuse{{
+		PkScript:     []byte{0x00, 0x14},
+		Addresses:    []btcutil.Address{addr},
+		IsOurAddress: true,
+		Txids:        []chainhash.Hash{{1}, {2}},
+		TotalValue:   2_000,
+	}})
+
+	require.Equal(t, (&AddressReuseReportResponse{
+		ReusedAddresses: []*AddressReuse{{
+			PkScript:     "0014",
+			Addresses:    []string{addr.EncodeAddress()},
+			IsOurAddress: true,
+			TxHashes: []string{
+				chainhash.Hash{1}.String(),
+				chainhash.Hash{2}.String(),
+			},
+			TotalValue: 2_000,
+		}},
+	}).String(), resp.String())
+
+	require.Empty(t, RPCAddressReuse(nil).ReusedAddresses)
+}
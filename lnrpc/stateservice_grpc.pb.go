@@ -24,6 +24,10 @@ type StateClient interface {
 	// GetState returns the current wallet state without streaming further
 	// changes.
 	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error)
+	// GetStartupStages returns the timings of the stages of the startup of
+	// lnd, in the order they started. Stages that didn't finish yet report the
+	// time they have taken so far.
+	GetStartupStages(ctx context.Context, in *GetStartupStagesRequest, opts ...grpc.CallOption) (*GetStartupStagesResponse, error)
 }
 
 type stateClient struct {
@@ -75,6 +79,15 @@ func (c *stateClient) GetState(ctx context.Context, in *GetStateRequest, opts ..
 	return out, nil
 }
 
+func (c *stateClient) GetStartupStages(ctx context.Context, in *GetStartupStagesRequest, opts ...grpc.CallOption) (*GetStartupStagesResponse, error) {
+	out := new(GetStartupStagesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.State/GetStartupStages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StateServer is the server API for State service.
 // All implementations must embed UnimplementedStateServer
 // for forward compatibility
@@ -85,6 +98,10 @@ type StateServer interface {
 	// GetState returns the current wallet state without streaming further
 	// changes.
 	GetState(context.Context, *GetStateRequest) (*GetStateResponse, error)
+	// GetStartupStages returns the timings of the stages of the startup of
+	// lnd, in the order they started. Stages that didn't finish yet report the
+	// time they have taken so far.
+	GetStartupStages(context.Context, *GetStartupStagesRequest) (*GetStartupStagesResponse, error)
 	mustEmbedUnimplementedStateServer()
 }
 
@@ -98,6 +115,9 @@ func (UnimplementedStateServer) SubscribeState(*SubscribeStateRequest, State_Sub
 func (UnimplementedStateServer) GetState(context.Context, *GetStateRequest) (*GetStateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
 }
+func (UnimplementedStateServer) GetStartupStages(context.Context, *GetStartupStagesRequest) (*GetStartupStagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStartupStages not implemented")
+}
 func (UnimplementedStateServer) mustEmbedUnimplementedStateServer() {}
 
 // UnsafeStateServer may be embedded to opt out of forward compatibility for this service.
@@ -150,6 +170,24 @@ func _State_GetState_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _State_GetStartupStages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStartupStagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServer).GetStartupStages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.State/GetStartupStages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServer).GetStartupStages(ctx, req.(*GetStartupStagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // State_ServiceDesc is the grpc.ServiceDesc for State service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,6 +199,10 @@ var State_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetState",
 			Handler:    _State_GetState_Handler,
 		},
+		{
+			MethodName: "GetStartupStages",
+			Handler:    _State_GetStartupStages_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -14,4 +14,10 @@ var (
 	ErrInsufficientReserve = errors.New("the outputs to be sent " +
 		"would leave insufficient reserves for anchor channels in " +
 		"the wallet")
+
+	// ErrNoHardwareSigner is returned when hardware wallet signing
+	// requests are queried or decided but no hardware wallet is
+	// configured.
+	ErrNoHardwareSigner = errors.New("no hardware wallet signer " +
+		"configured")
 )
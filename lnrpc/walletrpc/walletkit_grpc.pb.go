@@ -127,6 +127,13 @@ type WalletKitClient interface {
 	// detected by lnd if they happen after the import. Rescans to detect past
 	// events will be supported later on.
 	ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*ImportAccountResponse, error)
+	// CreateAccount creates a new account that is derived from the wallet's
+	// seed. Its funds are kept apart from those of all other accounts, and
+	// macaroons can be bound to it with the account caveat. Only BIP-0084
+	// (witness pubkey hash) and BIP-0086 (taproot pubkey) accounts can be
+	// created, BIP-0084 being the default. Macaroons that are bound to an
+	// account can't create accounts.
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
 	// lncli: `wallet accounts import-pubkey`
 	// ImportPublicKey imports a public key as watch-only into the wallet. The
 	// public key is converted into a simple address of the given type and that
@@ -178,6 +185,12 @@ type WalletKitClient interface {
 	// remain supported. This is an advanced API that depends on the internals of
 	// the UtxoSweeper, so things may change.
 	PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error)
+	// PreviewSweepBatches returns the batches the outputs lnd is currently
+	// attempting to sweep would be swept in if they were swept now. Outputs of
+	// different closed channels whose deadlines are close enough are batched
+	// into a single transaction with a shared change output. Each batch reports
+	// the fee it saves compared to sweeping its outputs one by one.
+	PreviewSweepBatches(ctx context.Context, in *PreviewSweepBatchesRequest, opts ...grpc.CallOption) (*PreviewSweepBatchesResponse, error)
 	// lncli: `wallet bumpfee`
 	// BumpFee is an endpoint that allows users to interact with lnd's sweeper
 	// directly. It takes an outpoint from an unconfirmed transaction and sends it
@@ -219,6 +232,14 @@ type WalletKitClient interface {
 	// overwrite the existing transaction label. Labels must not be empty, and
 	// cannot exceed 500 characters.
 	LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error)
+	// BulkLabelTransactions labels many transactions at once. Unless replace is
+	// set, the new labels are added to the labels the transactions already
+	// carry. Multiple labels are separated by semicolons. All labels are
+	// validated before the first transaction is labeled.
+	BulkLabelTransactions(ctx context.Context, in *BulkLabelTransactionsRequest, opts ...grpc.CallOption) (*BulkLabelTransactionsResponse, error)
+	// SearchTransactions returns the wallet transactions between the start and
+	// end height that carry a label containing the query, ignoring case.
+	SearchTransactions(ctx context.Context, in *SearchTransactionsRequest, opts ...grpc.CallOption) (*lnrpc.TransactionDetails, error)
 	// lncli: `wallet psbt fund`
 	// FundPsbt creates a fully populated PSBT that contains enough inputs to fund
 	// the outputs specified in the template. There are three ways a user can
@@ -261,6 +282,12 @@ type WalletKitClient interface {
 	// input/output/fee value validation, PSBT finalization). Any input that is
 	// incomplete will be skipped.
 	SignPsbt(ctx context.Context, in *SignPsbtRequest, opts ...grpc.CallOption) (*SignPsbtResponse, error)
+	// SignPsbtBatch signs multiple PSBTs like SignPsbt, but in a single round
+	// trip. This is used by watch-only nodes to get all HTLC signatures of a
+	// commitment from their remote signer at once. The batch is accepted or
+	// rejected as a whole: all PSBTs are validated before the first one is
+	// signed, and if any of them fails, no signed PSBTs are returned.
+	SignPsbtBatch(ctx context.Context, in *SignPsbtBatchRequest, opts ...grpc.CallOption) (*SignPsbtBatchResponse, error)
 	// lncli: `wallet psbt finalize`
 	// FinalizePsbt expects a partial transaction with all inputs and outputs fully
 	// declared and tries to sign all inputs that belong to the wallet. Lnd must be
@@ -274,6 +301,14 @@ type WalletKitClient interface {
 	// caller's responsibility to either publish the transaction on success or
 	// unlock/release any locked UTXOs in case of an error in this method.
 	FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error)
+	// ListHardwareSignRequests returns all requests to sign a PSBT with the
+	// hardware wallet that wait for the approval of the operator. Requires the
+	// hwi config section to be enabled.
+	ListHardwareSignRequests(ctx context.Context, in *ListHardwareSignRequestsRequest, opts ...grpc.CallOption) (*ListHardwareSignRequestsResponse, error)
+	// DecideHardwareSignRequest approves or denies a pending request to sign a
+	// PSBT with the hardware wallet. Approved requests are forwarded to the
+	// device, where the transaction needs to be confirmed again.
+	DecideHardwareSignRequest(ctx context.Context, in *DecideHardwareSignRequestRequest, opts ...grpc.CallOption) (*DecideHardwareSignRequestResponse, error)
 }
 
 type walletKitClient struct {
@@ -410,6 +445,15 @@ func (c *walletKitClient) ImportAccount(ctx context.Context, in *ImportAccountRe
 	return out, nil
 }
 
+func (c *walletKitClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error) {
+	out := new(CreateAccountResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/CreateAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) ImportPublicKey(ctx context.Context, in *ImportPublicKeyRequest, opts ...grpc.CallOption) (*ImportPublicKeyResponse, error) {
 	out := new(ImportPublicKeyResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ImportPublicKey", in, out, opts...)
@@ -473,6 +517,15 @@ func (c *walletKitClient) PendingSweeps(ctx context.Context, in *PendingSweepsRe
 	return out, nil
 }
 
+func (c *walletKitClient) PreviewSweepBatches(ctx context.Context, in *PreviewSweepBatchesRequest, opts ...grpc.CallOption) (*PreviewSweepBatchesResponse, error) {
+	out := new(PreviewSweepBatchesResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/PreviewSweepBatches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error) {
 	out := new(BumpFeeResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/BumpFee", in, out, opts...)
@@ -500,6 +553,24 @@ func (c *walletKitClient) LabelTransaction(ctx context.Context, in *LabelTransac
 	return out, nil
 }
 
+func (c *walletKitClient) BulkLabelTransactions(ctx context.Context, in *BulkLabelTransactionsRequest, opts ...grpc.CallOption) (*BulkLabelTransactionsResponse, error) {
+	out := new(BulkLabelTransactionsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/BulkLabelTransactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) SearchTransactions(ctx context.Context, in *SearchTransactionsRequest, opts ...grpc.CallOption) (*lnrpc.TransactionDetails, error) {
+	out := new(lnrpc.TransactionDetails)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/SearchTransactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error) {
 	out := new(FundPsbtResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/FundPsbt", in, out, opts...)
@@ -518,6 +589,15 @@ func (c *walletKitClient) SignPsbt(ctx context.Context, in *SignPsbtRequest, opt
 	return out, nil
 }
 
+func (c *walletKitClient) SignPsbtBatch(ctx context.Context, in *SignPsbtBatchRequest, opts ...grpc.CallOption) (*SignPsbtBatchResponse, error) {
+	out := new(SignPsbtBatchResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/SignPsbtBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error) {
 	out := new(FinalizePsbtResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/FinalizePsbt", in, out, opts...)
@@ -527,6 +607,24 @@ func (c *walletKitClient) FinalizePsbt(ctx context.Context, in *FinalizePsbtRequ
 	return out, nil
 }
 
+func (c *walletKitClient) ListHardwareSignRequests(ctx context.Context, in *ListHardwareSignRequestsRequest, opts ...grpc.CallOption) (*ListHardwareSignRequestsResponse, error) {
+	out := new(ListHardwareSignRequestsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ListHardwareSignRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) DecideHardwareSignRequest(ctx context.Context, in *DecideHardwareSignRequestRequest, opts ...grpc.CallOption) (*DecideHardwareSignRequestResponse, error) {
+	out := new(DecideHardwareSignRequestResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/DecideHardwareSignRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WalletKitServer is the server API for WalletKit service.
 // All implementations must embed UnimplementedWalletKitServer
 // for forward compatibility
@@ -638,6 +736,13 @@ type WalletKitServer interface {
 	// detected by lnd if they happen after the import. Rescans to detect past
 	// events will be supported later on.
 	ImportAccount(context.Context, *ImportAccountRequest) (*ImportAccountResponse, error)
+	// CreateAccount creates a new account that is derived from the wallet's
+	// seed. Its funds are kept apart from those of all other accounts, and
+	// macaroons can be bound to it with the account caveat. Only BIP-0084
+	// (witness pubkey hash) and BIP-0086 (taproot pubkey) accounts can be
+	// created, BIP-0084 being the default. Macaroons that are bound to an
+	// account can't create accounts.
+	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
 	// lncli: `wallet accounts import-pubkey`
 	// ImportPublicKey imports a public key as watch-only into the wallet. The
 	// public key is converted into a simple address of the given type and that
@@ -689,6 +794,12 @@ type WalletKitServer interface {
 	// remain supported. This is an advanced API that depends on the internals of
 	// the UtxoSweeper, so things may change.
 	PendingSweeps(context.Context, *PendingSweepsRequest) (*PendingSweepsResponse, error)
+	// PreviewSweepBatches returns the batches the outputs lnd is currently
+	// attempting to sweep would be swept in if they were swept now. Outputs of
+	// different closed channels whose deadlines are close enough are batched
+	// into a single transaction with a shared change output. Each batch reports
+	// the fee it saves compared to sweeping its outputs one by one.
+	PreviewSweepBatches(context.Context, *PreviewSweepBatchesRequest) (*PreviewSweepBatchesResponse, error)
 	// lncli: `wallet bumpfee`
 	// BumpFee is an endpoint that allows users to interact with lnd's sweeper
 	// directly. It takes an outpoint from an unconfirmed transaction and sends it
@@ -730,6 +841,14 @@ type WalletKitServer interface {
 	// overwrite the existing transaction label. Labels must not be empty, and
 	// cannot exceed 500 characters.
 	LabelTransaction(context.Context, *LabelTransactionRequest) (*LabelTransactionResponse, error)
+	// BulkLabelTransactions labels many transactions at once. Unless replace is
+	// set, the new labels are added to the labels the transactions already
+	// carry. Multiple labels are separated by semicolons. All labels are
+	// validated before the first transaction is labeled.
+	BulkLabelTransactions(context.Context, *BulkLabelTransactionsRequest) (*BulkLabelTransactionsResponse, error)
+	// SearchTransactions returns the wallet transactions between the start and
+	// end height that carry a label containing the query, ignoring case.
+	SearchTransactions(context.Context, *SearchTransactionsRequest) (*lnrpc.TransactionDetails, error)
 	// lncli: `wallet psbt fund`
 	// FundPsbt creates a fully populated PSBT that contains enough inputs to fund
 	// the outputs specified in the template. There are three ways a user can
@@ -772,6 +891,12 @@ type WalletKitServer interface {
 	// input/output/fee value validation, PSBT finalization). Any input that is
 	// incomplete will be skipped.
 	SignPsbt(context.Context, *SignPsbtRequest) (*SignPsbtResponse, error)
+	// SignPsbtBatch signs multiple PSBTs like SignPsbt, but in a single round
+	// trip. This is used by watch-only nodes to get all HTLC signatures of a
+	// commitment from their remote signer at once. The batch is accepted or
+	// rejected as a whole: all PSBTs are validated before the first one is
+	// signed, and if any of them fails, no signed PSBTs are returned.
+	SignPsbtBatch(context.Context, *SignPsbtBatchRequest) (*SignPsbtBatchResponse, error)
 	// lncli: `wallet psbt finalize`
 	// FinalizePsbt expects a partial transaction with all inputs and outputs fully
 	// declared and tries to sign all inputs that belong to the wallet. Lnd must be
@@ -785,6 +910,14 @@ type WalletKitServer interface {
 	// caller's responsibility to either publish the transaction on success or
 	// unlock/release any locked UTXOs in case of an error in this method.
 	FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error)
+	// ListHardwareSignRequests returns all requests to sign a PSBT with the
+	// hardware wallet that wait for the approval of the operator. Requires the
+	// hwi config section to be enabled.
+	ListHardwareSignRequests(context.Context, *ListHardwareSignRequestsRequest) (*ListHardwareSignRequestsResponse, error)
+	// DecideHardwareSignRequest approves or denies a pending request to sign a
+	// PSBT with the hardware wallet. Approved requests are forwarded to the
+	// device, where the transaction needs to be confirmed again.
+	DecideHardwareSignRequest(context.Context, *DecideHardwareSignRequestRequest) (*DecideHardwareSignRequestResponse, error)
 	mustEmbedUnimplementedWalletKitServer()
 }
 
@@ -834,6 +967,9 @@ func (UnimplementedWalletKitServer) VerifyMessageWithAddr(context.Context, *Veri
 func (UnimplementedWalletKitServer) ImportAccount(context.Context, *ImportAccountRequest) (*ImportAccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportAccount not implemented")
 }
+func (UnimplementedWalletKitServer) CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
 func (UnimplementedWalletKitServer) ImportPublicKey(context.Context, *ImportPublicKeyRequest) (*ImportPublicKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportPublicKey not implemented")
 }
@@ -855,6 +991,9 @@ func (UnimplementedWalletKitServer) EstimateFee(context.Context, *EstimateFeeReq
 func (UnimplementedWalletKitServer) PendingSweeps(context.Context, *PendingSweepsRequest) (*PendingSweepsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PendingSweeps not implemented")
 }
+func (UnimplementedWalletKitServer) PreviewSweepBatches(context.Context, *PreviewSweepBatchesRequest) (*PreviewSweepBatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewSweepBatches not implemented")
+}
 func (UnimplementedWalletKitServer) BumpFee(context.Context, *BumpFeeRequest) (*BumpFeeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BumpFee not implemented")
 }
@@ -864,15 +1003,30 @@ func (UnimplementedWalletKitServer) ListSweeps(context.Context, *ListSweepsReque
 func (UnimplementedWalletKitServer) LabelTransaction(context.Context, *LabelTransactionRequest) (*LabelTransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LabelTransaction not implemented")
 }
+func (UnimplementedWalletKitServer) BulkLabelTransactions(context.Context, *BulkLabelTransactionsRequest) (*BulkLabelTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkLabelTransactions not implemented")
+}
+func (UnimplementedWalletKitServer) SearchTransactions(context.Context, *SearchTransactionsRequest) (*lnrpc.TransactionDetails, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTransactions not implemented")
+}
 func (UnimplementedWalletKitServer) FundPsbt(context.Context, *FundPsbtRequest) (*FundPsbtResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FundPsbt not implemented")
 }
 func (UnimplementedWalletKitServer) SignPsbt(context.Context, *SignPsbtRequest) (*SignPsbtResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignPsbt not implemented")
 }
+func (UnimplementedWalletKitServer) SignPsbtBatch(context.Context, *SignPsbtBatchRequest) (*SignPsbtBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignPsbtBatch not implemented")
+}
 func (UnimplementedWalletKitServer) FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FinalizePsbt not implemented")
 }
+func (UnimplementedWalletKitServer) ListHardwareSignRequests(context.Context, *ListHardwareSignRequestsRequest) (*ListHardwareSignRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHardwareSignRequests not implemented")
+}
+func (UnimplementedWalletKitServer) DecideHardwareSignRequest(context.Context, *DecideHardwareSignRequestRequest) (*DecideHardwareSignRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecideHardwareSignRequest not implemented")
+}
 func (UnimplementedWalletKitServer) mustEmbedUnimplementedWalletKitServer() {}
 
 // UnsafeWalletKitServer may be embedded to opt out of forward compatibility for this service.
@@ -1138,6 +1292,24 @@ func _WalletKit_ImportAccount_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/CreateAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_ImportPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ImportPublicKeyRequest)
 	if err := dec(in); err != nil {
@@ -1264,6 +1436,24 @@ func _WalletKit_PendingSweeps_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_PreviewSweepBatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewSweepBatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).PreviewSweepBatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/PreviewSweepBatches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).PreviewSweepBatches(ctx, req.(*PreviewSweepBatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BumpFeeRequest)
 	if err := dec(in); err != nil {
@@ -1318,6 +1508,42 @@ func _WalletKit_LabelTransaction_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_BulkLabelTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkLabelTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).BulkLabelTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/BulkLabelTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).BulkLabelTransactions(ctx, req.(*BulkLabelTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_SearchTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).SearchTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/SearchTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).SearchTransactions(ctx, req.(*SearchTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_FundPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FundPsbtRequest)
 	if err := dec(in); err != nil {
@@ -1354,6 +1580,24 @@ func _WalletKit_SignPsbt_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_SignPsbtBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignPsbtBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).SignPsbtBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/SignPsbtBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).SignPsbtBatch(ctx, req.(*SignPsbtBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_FinalizePsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FinalizePsbtRequest)
 	if err := dec(in); err != nil {
@@ -1372,6 +1616,42 @@ func _WalletKit_FinalizePsbt_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_ListHardwareSignRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHardwareSignRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ListHardwareSignRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ListHardwareSignRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ListHardwareSignRequests(ctx, req.(*ListHardwareSignRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_DecideHardwareSignRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideHardwareSignRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).DecideHardwareSignRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/DecideHardwareSignRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).DecideHardwareSignRequest(ctx, req.(*DecideHardwareSignRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WalletKit_ServiceDesc is the grpc.ServiceDesc for WalletKit service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1435,6 +1715,10 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ImportAccount",
 			Handler:    _WalletKit_ImportAccount_Handler,
 		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _WalletKit_CreateAccount_Handler,
+		},
 		{
 			MethodName: "ImportPublicKey",
 			Handler:    _WalletKit_ImportPublicKey_Handler,
@@ -1463,6 +1747,10 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PendingSweeps",
 			Handler:    _WalletKit_PendingSweeps_Handler,
 		},
+		{
+			MethodName: "PreviewSweepBatches",
+			Handler:    _WalletKit_PreviewSweepBatches_Handler,
+		},
 		{
 			MethodName: "BumpFee",
 			Handler:    _WalletKit_BumpFee_Handler,
@@ -1475,6 +1763,14 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LabelTransaction",
 			Handler:    _WalletKit_LabelTransaction_Handler,
 		},
+		{
+			MethodName: "BulkLabelTransactions",
+			Handler:    _WalletKit_BulkLabelTransactions_Handler,
+		},
+		{
+			MethodName: "SearchTransactions",
+			Handler:    _WalletKit_SearchTransactions_Handler,
+		},
 		{
 			MethodName: "FundPsbt",
 			Handler:    _WalletKit_FundPsbt_Handler,
@@ -1483,10 +1779,22 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SignPsbt",
 			Handler:    _WalletKit_SignPsbt_Handler,
 		},
+		{
+			MethodName: "SignPsbtBatch",
+			Handler:    _WalletKit_SignPsbtBatch_Handler,
+		},
 		{
 			MethodName: "FinalizePsbt",
 			Handler:    _WalletKit_FinalizePsbt_Handler,
 		},
+		{
+			MethodName: "ListHardwareSignRequests",
+			Handler:    _WalletKit_ListHardwareSignRequests_Handler,
+		},
+		{
+			MethodName: "DecideHardwareSignRequest",
+			Handler:    _WalletKit_DecideHardwareSignRequest_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "walletrpc/walletkit.proto",
@@ -40,6 +40,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwallet/psbtv2"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/sweep"
 	"google.golang.org/grpc"
@@ -102,6 +103,10 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/walletrpc.WalletKit/PreviewSweepBatches": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/walletrpc.WalletKit/BumpFee": {{
 			Entity: "onchain",
 			Action: "write",
@@ -114,6 +119,14 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/BulkLabelTransactions": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/SearchTransactions": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/walletrpc.WalletKit/LeaseOutput": {{
 			Entity: "onchain",
 			Action: "write",
@@ -150,10 +163,22 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/SignPsbtBatch": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 		"/walletrpc.WalletKit/FinalizePsbt": {{
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/ListHardwareSignRequests": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/DecideHardwareSignRequest": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 		"/walletrpc.WalletKit/ListAccounts": {{
 			Entity: "onchain",
 			Action: "read",
@@ -166,6 +191,10 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/CreateAccount": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 		"/walletrpc.WalletKit/ImportPublicKey": {{
 			Entity: "onchain",
 			Action: "write",
@@ -418,6 +447,11 @@ func (w *WalletKit) ListUnspent(ctx context.Context,
 		return nil, err
 	}
 
+	account, err := macaroons.EnforceAccount(ctx, req.Account)
+	if err != nil {
+		return nil, err
+	}
+
 	// With our arguments validated, we'll query the internal wallet for
 	// the set of UTXOs that match our query.
 	//
@@ -427,7 +461,7 @@ func (w *WalletKit) ListUnspent(ctx context.Context,
 	var utxos []*lnwallet.Utxo
 	err = w.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
 		utxos, err = w.cfg.Wallet.ListUnspentWitness(
-			minConfs, maxConfs, req.Account,
+			minConfs, maxConfs, account,
 		)
 
 		return err
@@ -595,9 +629,12 @@ func (w *WalletKit) DeriveKey(ctx context.Context,
 func (w *WalletKit) NextAddr(ctx context.Context,
 	req *AddrRequest) (*AddrResponse, error) {
 
-	account := lnwallet.DefaultAccountName
-	if req.Account != "" {
-		account = req.Account
+	account, err := macaroons.EnforceAccount(ctx, req.Account)
+	if err != nil {
+		return nil, err
+	}
+	if account == "" {
+		account = lnwallet.DefaultAccountName
 	}
 
 	addrType := lnwallet.WitnessPubKey
@@ -909,6 +946,45 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 	}, nil
 }
 
+// PreviewSweepBatches returns the batches the outputs lnd is currently
+// attempting to sweep would be swept in if they were swept now. Outputs of
+// different closed channels whose deadlines are close enough are batched into
+// a single transaction with a shared change output. Each batch reports the fee
+// it saves compared to sweeping its outputs one by one.
+func (w *WalletKit) PreviewSweepBatches(_ context.Context,
+	_ *PreviewSweepBatchesRequest) (*PreviewSweepBatchesResponse, error) {
+
+	batches, err := w.cfg.Sweeper.PreviewBatches()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcBatches := make([]*SweepBatchPreview, 0, len(batches))
+	for _, batch := range batches {
+		inputs := make([]*lnrpc.OutPoint, 0, len(batch.Inputs))
+		for i := range batch.Inputs {
+			inputs = append(
+				inputs, lnrpc.MarshalOutPoint(&batch.Inputs[i]),
+			)
+		}
+
+		feeRate := batch.FeeRate.FeePerVByte()
+		rpcBatches = append(rpcBatches, &SweepBatchPreview{
+			Inputs:              inputs,
+			DeadlineHeight:      batch.DeadlineHeight,
+			BudgetSat:           uint64(batch.Budget),
+			SatPerVbyte:         uint64(feeRate),
+			Weight:              uint64(batch.Weight),
+			UnbatchedWeight:     uint64(batch.UnbatchedWeight),
+			EstimatedSavingsSat: int64(batch.EstimatedSavings),
+		})
+	}
+
+	return &PreviewSweepBatchesResponse{
+		Batches: rpcBatches,
+	}, nil
+}
+
 // UnmarshallOutPoint converts an outpoint from its lnrpc type to its canonical
 // type.
 func UnmarshallOutPoint(op *lnrpc.OutPoint) (*wire.OutPoint, error) {
@@ -1280,6 +1356,90 @@ func (w *WalletKit) LabelTransaction(ctx context.Context,
 	return &LabelTransactionResponse{}, err
 }
 
+// BulkLabelTransactions labels all given transactions at once. Unless replace
+// is set, the labels are added to the labels the transactions already carry,
+// otherwise they replace them. All labels are validated before the first
+// transaction is labeled.
+func (w *WalletKit) BulkLabelTransactions(_ context.Context,
+	req *BulkLabelTransactionsRequest) (*BulkLabelTransactionsResponse,
+	error) {
+
+	txids := make([]chainhash.Hash, len(req.Labels))
+	newLabels := make([]string, len(req.Labels))
+	for i, txLabel := range req.Labels {
+		txid, err := chainhash.NewHash(txLabel.Txid)
+		if err != nil {
+			return nil, err
+		}
+		txids[i] = *txid
+
+		label := txLabel.Label
+
+		// Unless the current labels are replaced, the new ones are
+		// added to them.
+		if !req.Replace {
+			tx, err := w.cfg.Wallet.GetTransactionDetails(txid)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch "+
+					"transaction %v: %w", txid, err)
+			}
+
+			label = tx.Label + labels.Separator + label
+		}
+
+		joined, err := labels.Join(label)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label for transaction "+
+				"%v: %w", txid, err)
+		}
+		if len(joined) == 0 {
+			return nil, fmt.Errorf("transaction %v: %w", txid,
+				ErrZeroLabel)
+		}
+
+		newLabels[i] = joined
+	}
+
+	for i, txid := range txids {
+		err := w.cfg.Wallet.LabelTransaction(txid, newLabels[i], true)
+		if err != nil {
+			return nil, fmt.Errorf("unable to label transaction "+
+				"%v: %w", txid, err)
+		}
+	}
+
+	return &BulkLabelTransactionsResponse{}, nil
+}
+
+// SearchTransactions returns the wallet transactions between the start and end
+// height that carry a label containing the query, ignoring case. An end height
+// of zero includes unconfirmed transactions. If an account is given, only its
+// transactions are searched.
+func (w *WalletKit) SearchTransactions(_ context.Context,
+	req *SearchTransactionsRequest) (*lnrpc.TransactionDetails, error) {
+
+	endHeight := req.EndHeight
+	if endHeight == 0 {
+		endHeight = btcwallet.UnconfirmedHeight
+	}
+
+	txns, err := w.cfg.Wallet.ListTransactionDetails(
+		req.StartHeight, endHeight, req.Account,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*lnwallet.TransactionDetail
+	for _, tx := range txns {
+		if labels.Matches(tx.Label, req.Query) {
+			matches = append(matches, tx)
+		}
+	}
+
+	return lnrpc.RPCTransactionDetails(matches), nil
+}
+
 // FundPsbt creates a fully populated PSBT that contains enough inputs to fund
 // the outputs specified in the template. There are three ways a user can
 // specify what we call the template (a list of inputs and outputs to use in the
@@ -1310,7 +1470,7 @@ func (w *WalletKit) LabelTransaction(ctx context.Context,
 // responsibility to either spend the locked UTXOs (by finalizing and then
 // publishing the transaction) or to unlock/release the locked UTXOs in case of
 // an error on the caller's side.
-func (w *WalletKit) FundPsbt(_ context.Context,
+func (w *WalletKit) FundPsbt(ctx context.Context,
 	req *FundPsbtRequest) (*FundPsbtResponse, error) {
 
 	coinSelectionStrategy, err := lnrpc.UnmarshallCoinSelectionStrategy(
@@ -1360,10 +1520,19 @@ func (w *WalletKit) FundPsbt(_ context.Context,
 	}
 
 	// We'll assume the PSBT will be funded by the default account unless
-	// otherwise specified.
-	account := lnwallet.DefaultAccountName
-	if req.Account != "" {
-		account = req.Account
+	// otherwise specified, or the macaroon is bound to an account.
+	account, err := macaroons.EnforceAccount(ctx, req.Account)
+	if err != nil {
+		return nil, err
+	}
+	if account == "" {
+		account = lnwallet.DefaultAccountName
+	}
+
+	// Make sure the requested PSBT version is known before any inputs are
+	// locked, so the checks below can't fail after funding.
+	if _, err := outputPsbtVersion(req.PsbtVersion, 0); err != nil {
+		return nil, err
 	}
 
 	// There are three ways a user can specify what we call the template (a
@@ -1374,29 +1543,38 @@ func (w *WalletKit) FundPsbt(_ context.Context,
 	switch {
 	// The template is specified as a PSBT. All we have to do is parse it.
 	case req.GetPsbt() != nil:
-		r := bytes.NewReader(req.GetPsbt())
-		packet, err := psbt.NewFromRawBytes(r, false)
+		packet, version, err := psbtv2.Parse(req.GetPsbt())
 		if err != nil {
 			return nil, fmt.Errorf("could not parse PSBT: %w", err)
 		}
 
+		// The requested version was validated above.
+		version, _ = outputPsbtVersion(req.PsbtVersion, version)
+
 		// Run the actual funding process now, using the internal
 		// wallet.
-		return w.fundPsbtInternalWallet(
+		resp, err := w.fundPsbtInternalWallet(
 			account, keyScopeFromChangeAddressType(req.ChangeType),
 			packet, minConfs, feeSatPerKW, coinSelectionStrategy,
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		return fundedPsbtInVersion(resp, version)
 
 	// The template is specified as a PSBT with the intention to perform
 	// coin selection even if inputs are already present.
 	case req.GetCoinSelect() != nil:
 		coinSelectRequest := req.GetCoinSelect()
-		r := bytes.NewReader(coinSelectRequest.Psbt)
-		packet, err := psbt.NewFromRawBytes(r, false)
+		packet, version, err := psbtv2.Parse(coinSelectRequest.Psbt)
 		if err != nil {
 			return nil, fmt.Errorf("could not parse PSBT: %w", err)
 		}
 
+		// The requested version was validated above.
+		version, _ = outputPsbtVersion(req.PsbtVersion, version)
+
 		numOutputs := int32(len(packet.UnsignedTx.TxOut))
 		if numOutputs == 0 {
 			return nil, fmt.Errorf("no outputs specified in " +
@@ -1458,10 +1636,15 @@ func (w *WalletKit) FundPsbt(_ context.Context,
 
 		// Run the actual funding process now, using the channel funding
 		// coin selection algorithm.
-		return w.fundPsbtCoinSelect(
+		resp, err := w.fundPsbtCoinSelect(
 			account, changeIndex, packet, minConfs, changeType,
 			feeSatPerKW, coinSelectionStrategy,
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		return fundedPsbtInVersion(resp, version)
 
 	// The template is specified as a RPC message. We need to create a new
 	// PSBT and copy the RPC information over.
@@ -1515,10 +1698,20 @@ func (w *WalletKit) FundPsbt(_ context.Context,
 
 		// Run the actual funding process now, using the internal
 		// wallet.
-		return w.fundPsbtInternalWallet(
+		resp, err := w.fundPsbtInternalWallet(
 			account, keyScopeFromChangeAddressType(req.ChangeType),
 			packet, minConfs, feeSatPerKW, coinSelectionStrategy,
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		// The requested version was validated above.
+		version, _ := outputPsbtVersion(
+			req.PsbtVersion, psbtv2.Version0,
+		)
+
+		return fundedPsbtInVersion(resp, version)
 
 	default:
 		return nil, fmt.Errorf("transaction template missing, need " +
@@ -1806,6 +1999,23 @@ func (w *WalletKit) fundPsbtCoinSelect(account string, changeIndex int32,
 			}
 		}
 
+		// An oldest-first strategy arranges the coins by the
+		// confirmations we just listed.
+		strategy, err := chanfunding.WithConfirmations(
+			strategy, func() (map[wire.OutPoint]int64, error) {
+				confs := make(map[wire.OutPoint]int64)
+				for _, utxo := range utxos {
+					confs[utxo.OutPoint] =
+						utxo.Confirmations
+				}
+
+				return confs, nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+
 		selectedCoins, changeAmount, err := chanfunding.CoinSelect(
 			feeRate, fundingAmount, changeDustLimit, coins,
 			strategy, estimator, changeType,
@@ -1892,6 +2102,48 @@ func (w *WalletKit) assertNotAvailable(inputs []*wire.TxIn, minConfs int32,
 	})
 }
 
+// outputPsbtVersion returns the version a PSBT that was given in the given
+// version is returned in, as requested by the caller.
+func outputPsbtVersion(requested PsbtVersion, given uint32) (uint32, error) {
+	switch requested {
+	case PsbtVersion_PSBT_VERSION_UNCHANGED:
+		return given, nil
+
+	case PsbtVersion_PSBT_VERSION_V0:
+		return psbtv2.Version0, nil
+
+	case PsbtVersion_PSBT_VERSION_V2:
+		return psbtv2.Version2, nil
+
+	default:
+		return 0, fmt.Errorf("unknown PSBT version: %v", requested)
+	}
+}
+
+// fundedPsbtInVersion converts the funded PSBT of the response into the given
+// PSBT version, so callers get back the version they asked for or the same
+// version they used for their template.
+func fundedPsbtInVersion(resp *FundPsbtResponse,
+	version uint32) (*FundPsbtResponse, error) {
+
+	if version == psbtv2.Version0 {
+		return resp, nil
+	}
+
+	packet, _, err := psbtv2.Parse(resp.FundedPsbt)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing funded PSBT: %w", err)
+	}
+
+	resp.FundedPsbt, err = psbtv2.Serialize(packet, version)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing funded PSBT: %w",
+			err)
+	}
+
+	return resp, nil
+}
+
 // lockAndCreateFundingResponse locks the given outpoints and creates a funding
 // response with the serialized PSBT, the change index and the locked UTXOs.
 func (w *WalletKit) lockAndCreateFundingResponse(packet *psbt.Packet,
@@ -2049,31 +2301,46 @@ func addrTypeFromChangeAddressType(
 // perform any other tasks (such as coin selection, UTXO locking or
 // input/output/fee value validation, PSBT finalization). Any input that is
 // incomplete will be skipped.
-func (w *WalletKit) SignPsbt(_ context.Context, req *SignPsbtRequest) (
+func (w *WalletKit) SignPsbt(ctx context.Context, req *SignPsbtRequest) (
 	*SignPsbtResponse, error) {
 
-	packet, err := psbt.NewFromRawBytes(
-		bytes.NewReader(req.FundedPsbt), false,
-	)
+	packet, version, err := parsePsbtToSign(req.FundedPsbt)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.signPsbt(ctx, packet, version)
+}
+
+// parsePsbtToSign parses a PSBT that is going to be signed and makes sure
+// every input either has a witness UTXO, or a non witness UTXO.
+func parsePsbtToSign(rawPsbt []byte) (*psbt.Packet, uint32, error) {
+	packet, version, err := psbtv2.Parse(rawPsbt)
 	if err != nil {
 		log.Debugf("Error parsing PSBT: %v, raw input: %x", err,
-			req.FundedPsbt)
-		return nil, fmt.Errorf("error parsing PSBT: %w", err)
+			rawPsbt)
+		return nil, 0, fmt.Errorf("error parsing PSBT: %w", err)
 	}
 
-	// Before we attempt to sign the packet, ensure that every input either
-	// has a witness UTXO, or a non witness UTXO.
 	for idx := range packet.UnsignedTx.TxIn {
 		in := packet.Inputs[idx]
 
 		// Doesn't have either a witness or non witness UTXO so we need
 		// to exit here as otherwise signing will fail.
 		if in.WitnessUtxo == nil && in.NonWitnessUtxo == nil {
-			return nil, fmt.Errorf("input (index=%v) doesn't "+
+			return nil, 0, fmt.Errorf("input (index=%v) doesn't "+
 				"specify any UTXO info", idx)
 		}
 	}
 
+	return packet, version, nil
+}
+
+// signPsbt signs all inputs of the packet that the wallet or the hardware
+// wallet can sign and serializes the result in the given PSBT version.
+func (w *WalletKit) signPsbt(ctx context.Context, packet *psbt.Packet,
+	version uint32) (*SignPsbtResponse, error) {
+
 	// Let the wallet do the heavy lifting. This will sign all inputs that
 	// we have the UTXO for. If some inputs can't be signed and don't have
 	// witness data attached, they will just be skipped.
@@ -2082,19 +2349,137 @@ func (w *WalletKit) SignPsbt(_ context.Context, req *SignPsbtRequest) (
 		return nil, fmt.Errorf("error signing PSBT: %w", err)
 	}
 
-	// Serialize the signed PSBT in both the packet and wire format.
-	var signedPsbtBytes bytes.Buffer
-	err = packet.Serialize(&signedPsbtBytes)
+	// Inputs that belong to a hardware wallet are signed by the device.
+	hwSignedInputs, err := w.signWithHardwareWallet(ctx, packet, false)
+	if err != nil {
+		return nil, err
+	}
+	signedInputs = append(signedInputs, hwSignedInputs...)
+
+	// Serialize the signed PSBT in the same version it was given to us.
+	signedPsbt, err := psbtv2.Serialize(packet, version)
 	if err != nil {
 		return nil, fmt.Errorf("error serializing PSBT: %w", err)
 	}
 
 	return &SignPsbtResponse{
-		SignedPsbt:   signedPsbtBytes.Bytes(),
+		SignedPsbt:   signedPsbt,
 		SignedInputs: signedInputs,
 	}, nil
 }
 
+// SignPsbtBatch signs multiple PSBTs like SignPsbt, but in a single round
+// trip. This is used by watch-only nodes to get all HTLC signatures of a
+// commitment from their remote signer at once. The batch is accepted or
+// rejected as a whole: all PSBTs are validated before the first one is
+// signed, and if any of them fails, no signed PSBTs are returned.
+func (w *WalletKit) SignPsbtBatch(ctx context.Context,
+	req *SignPsbtBatchRequest) (*SignPsbtBatchResponse, error) {
+
+	if len(req.FundedPsbts) == 0 {
+		return nil, fmt.Errorf("no PSBTs to sign")
+	}
+
+	packets := make([]*psbt.Packet, len(req.FundedPsbts))
+	versions := make([]uint32, len(req.FundedPsbts))
+	for i, rawPsbt := range req.FundedPsbts {
+		var err error
+		packets[i], versions[i], err = parsePsbtToSign(rawPsbt)
+		if err != nil {
+			return nil, fmt.Errorf("PSBT %d of batch: %w", i, err)
+		}
+	}
+
+	resp := &SignPsbtBatchResponse{
+		Responses: make([]*SignPsbtResponse, len(packets)),
+	}
+	for i, packet := range packets {
+		var err error
+		resp.Responses[i], err = w.signPsbt(ctx, packet, versions[i])
+		if err != nil {
+			return nil, fmt.Errorf("PSBT %d of batch: %w", i, err)
+		}
+	}
+
+	return resp, nil
+}
+
+// signWithHardwareWallet signs the inputs of the packet that belong to the
+// configured hardware wallet, if any, and returns their indices. If finalize
+// is true, the signed inputs are finalized as well.
+func (w *WalletKit) signWithHardwareWallet(ctx context.Context,
+	packet *psbt.Packet, finalize bool) ([]uint32, error) {
+
+	if w.cfg.HardwareSigner == nil {
+		return nil, nil
+	}
+
+	signedInputs, err := w.cfg.HardwareSigner.SignPsbt(ctx, packet)
+	if err != nil {
+		return nil, fmt.Errorf("error signing PSBT with hardware "+
+			"wallet: %w", err)
+	}
+
+	if !finalize {
+		return signedInputs, nil
+	}
+
+	for _, idx := range signedInputs {
+		err := psbt.Finalize(packet, int(idx))
+		if err != nil {
+			return nil, fmt.Errorf("error finalizing input %d "+
+				"signed by hardware wallet: %w", idx, err)
+		}
+	}
+
+	return signedInputs, nil
+}
+
+// ListHardwareSignRequests returns all requests to sign a PSBT with the
+// hardware wallet that wait for the approval of the operator.
+func (w *WalletKit) ListHardwareSignRequests(_ context.Context,
+	_ *ListHardwareSignRequestsRequest) (*ListHardwareSignRequestsResponse,
+	error) {
+
+	if w.cfg.HardwareSigner == nil {
+		return nil, ErrNoHardwareSigner
+	}
+
+	pending := w.cfg.HardwareSigner.PendingRequests()
+	requests := make([]*HardwareSignRequest, len(pending))
+	for i, req := range pending {
+		requests[i] = &HardwareSignRequest{
+			Id:        req.ID,
+			Psbt:      req.Psbt,
+			Inputs:    req.Inputs,
+			CreatedAt: req.CreatedAt.Unix(),
+		}
+	}
+
+	return &ListHardwareSignRequestsResponse{
+		Requests: requests,
+	}, nil
+}
+
+// DecideHardwareSignRequest approves or denies a pending request to sign a
+// PSBT with the hardware wallet. Approved requests are forwarded to the
+// device, where the transaction needs to be confirmed again.
+func (w *WalletKit) DecideHardwareSignRequest(_ context.Context,
+	req *DecideHardwareSignRequestRequest) (
+	*DecideHardwareSignRequestResponse, error) {
+
+	if w.cfg.HardwareSigner == nil {
+		return nil, ErrNoHardwareSigner
+	}
+
+	err := w.cfg.HardwareSigner.Decide(req.Id, req.Approve)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DecideHardwareSignRequestResponse{}, nil
+}
+
 // FinalizePsbt expects a partial transaction with all inputs and outputs fully
 // declared and tries to sign all inputs that belong to the wallet. Lnd must be
 // the last signer of the transaction. That means, if there are any unsigned
@@ -2106,30 +2491,43 @@ func (w *WalletKit) SignPsbt(_ context.Context, req *SignPsbtRequest) (
 // NOTE: This method does NOT publish the transaction once finalized. It is the
 // caller's responsibility to either publish the transaction on success or
 // unlock/release any locked UTXOs in case of an error in this method.
-func (w *WalletKit) FinalizePsbt(_ context.Context,
+func (w *WalletKit) FinalizePsbt(ctx context.Context,
 	req *FinalizePsbtRequest) (*FinalizePsbtResponse, error) {
 
 	// We'll assume the PSBT was funded by the default account unless
-	// otherwise specified.
-	account := lnwallet.DefaultAccountName
-	if req.Account != "" {
-		account = req.Account
+	// otherwise specified, or the macaroon is bound to an account.
+	account, err := macaroons.EnforceAccount(ctx, req.Account)
+	if err != nil {
+		return nil, err
+	}
+	if account == "" {
+		account = lnwallet.DefaultAccountName
 	}
 
-	// Parse the funded PSBT.
-	packet, err := psbt.NewFromRawBytes(
-		bytes.NewReader(req.FundedPsbt), false,
-	)
+	// Parse the funded PSBT, which may be of version 0 or 2.
+	packet, version, err := psbtv2.Parse(req.FundedPsbt)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing PSBT: %w", err)
 	}
 
+	version, err = outputPsbtVersion(req.PsbtVersion, version)
+	if err != nil {
+		return nil, err
+	}
+
 	// The only check done at this level is to validate that the PSBT is
 	// not complete. The wallet performs all other checks.
 	if packet.IsComplete() {
 		return nil, fmt.Errorf("PSBT is already fully signed")
 	}
 
+	// Inputs that belong to a hardware wallet are signed and finalized
+	// first, so the wallet skips them below.
+	_, err = w.signWithHardwareWallet(ctx, packet, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// Let the wallet do the heavy lifting. This will sign all inputs that
 	// we have the UTXO for. If some inputs can't be signed and don't have
 	// witness data attached, this will fail.
@@ -2138,16 +2536,15 @@ func (w *WalletKit) FinalizePsbt(_ context.Context,
 		return nil, fmt.Errorf("error finalizing PSBT: %w", err)
 	}
 
-	var (
-		finalPsbtBytes bytes.Buffer
-		finalTxBytes   bytes.Buffer
-	)
-
-	// Serialize the finalized PSBT in both the packet and wire format.
-	err = packet.Serialize(&finalPsbtBytes)
+	// Serialize the finalized PSBT in both the packet and wire format. The
+	// packet is returned in the requested version, or the version it was
+	// given to us in.
+	finalPsbt, err := psbtv2.Serialize(packet, version)
 	if err != nil {
 		return nil, fmt.Errorf("error serializing PSBT: %w", err)
 	}
+
+	var finalTxBytes bytes.Buffer
 	finalTx, err := psbt.Extract(packet)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract final TX: %w", err)
@@ -2158,7 +2555,7 @@ func (w *WalletKit) FinalizePsbt(_ context.Context,
 	}
 
 	return &FinalizePsbtResponse{
-		SignedPsbt: finalPsbtBytes.Bytes(),
+		SignedPsbt: finalPsbt,
 		RawFinalTx: finalTxBytes.Bytes(),
 	}, nil
 }
@@ -2301,7 +2698,13 @@ func (w *WalletKit) ListAccounts(ctx context.Context,
 			req.AddressType)
 	}
 
-	accounts, err := w.cfg.Wallet.ListAccounts(req.Name, keyScopeFilter)
+	// A macaroon that is bound to an account only sees that account.
+	name, err := macaroons.EnforceAccount(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := w.cfg.Wallet.ListAccounts(name, keyScopeFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -2355,9 +2758,17 @@ func (w *WalletKit) RequiredReserve(ctx context.Context,
 func (w *WalletKit) ListAddresses(ctx context.Context,
 	req *ListAddressesRequest) (*ListAddressesResponse, error) {
 
+	// A macaroon that is bound to an account only sees the addresses of
+	// that account.
+	accountName, err := macaroons.EnforceAccount(ctx, req.AccountName)
+	if err != nil {
+		return nil, err
+	}
+	showCustomAccounts := req.ShowCustomAccounts ||
+		accountName != req.AccountName
+
 	addressLists, err := w.cfg.Wallet.ListAddresses(
-		req.AccountName,
-		req.ShowCustomAccounts,
+		accountName, showCustomAccounts,
 	)
 	if err != nil {
 		return nil, err
@@ -2688,6 +3099,57 @@ func (w *WalletKit) ImportAccount(_ context.Context,
 	return resp, nil
 }
 
+// CreateAccount creates a new account that is derived from the wallet's seed.
+// Its funds are kept apart from those of all other accounts, and macaroons can
+// be bound to it with the account caveat. Only BIP-0084 (witness pubkey hash)
+// and BIP-0086 (taproot pubkey) accounts can be created, BIP-0084 being the
+// default.
+func (w *WalletKit) CreateAccount(ctx context.Context,
+	req *CreateAccountRequest) (*CreateAccountResponse, error) {
+
+	account, err := macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if account != "" {
+		return nil, fmt.Errorf("macaroon is bound to account %v and "+
+			"can't create accounts", account)
+	}
+
+	if req.Name == "" {
+		return nil, errors.New("account name must be set")
+	}
+
+	var walletAddrType lnwallet.AddressType
+	switch req.AddressType {
+	case AddressType_UNKNOWN, AddressType_WITNESS_PUBKEY_HASH:
+		walletAddrType = lnwallet.WitnessPubKey
+
+	case AddressType_TAPROOT_PUBKEY:
+		walletAddrType = lnwallet.TaprootPubkey
+
+	default:
+		return nil, fmt.Errorf("unsupported address type %v for new "+
+			"account", req.AddressType)
+	}
+
+	accountProps, err := w.cfg.Wallet.CreateAccount(
+		req.Name, walletAddrType,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcAccount, err := marshalWalletAccount(
+		w.internalScope(), accountProps,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateAccountResponse{Account: rpcAccount}, nil
+}
+
 // ImportPublicKey imports a single derived public key into the wallet. The
 // address type can usually be inferred from the key's version, but in the case
 // of legacy versions (xpub, tpub), an address type must be specified as we
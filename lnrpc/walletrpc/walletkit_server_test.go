@@ -5,6 +5,7 @@ package walletrpc
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -22,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwallet/psbtv2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -631,3 +633,189 @@ func TestFundPsbtCoinSelect(t *testing.T) {
 		})
 	}
 }
+
+// labelWallet is a mock wallet that keeps the labels of its transactions.
+type labelWallet struct {
+	mock.WalletController
+
+	labels map[chainhash.Hash]string
+}
+
+// GetTransactionDetails returns the transaction with its label.
+func (w *labelWallet) GetTransactionDetails(
+	txid *chainhash.Hash) (*lnwallet.TransactionDetail, error) {
+
+	label, ok := w.labels[*txid]
+	if !ok {
+		return nil, fmt.Errorf("transaction %v not found", txid)
+	}
+
+	return &lnwallet.TransactionDetail{Hash: *txid, Label: label}, nil
+}
+
+// ListTransactionDetails returns all transactions of the wallet.
+func (w *labelWallet) ListTransactionDetails(int32, int32,
+	string) ([]*lnwallet.TransactionDetail, error) {
+
+	var txns []*lnwallet.TransactionDetail
+	for txid, label := range w.labels {
+		txns = append(txns, &lnwallet.TransactionDetail{
+			Hash:  txid,
+			Label: label,
+		})
+	}
+
+	return txns, nil
+}
+
+// LabelTransaction sets the label of the transaction.
+func (w *labelWallet) LabelTransaction(txid chainhash.Hash, label string,
+	_ bool) error {
+
+	w.labels[txid] = label
+
+	return nil
+}
+
+// TestBulkLabelTransactions tests that labels are added to or replace the
+// labels of many transactions, and that no transaction is labeled if one of
+// the labels is invalid.
+func TestBulkLabelTransactions(t *testing.T) {
+	t.Parallel()
+
+	txid1, txid2 := chainhash.Hash{1}, chainhash.Hash{2}
+	walletMock := &labelWallet{
+		labels: map[chainhash.Hash]string{
+			txid1: "open",
+			txid2: "sweep",
+		},
+	}
+	w := &WalletKit{cfg: &Config{Wallet: walletMock}}
+	ctx := context.Background()
+
+	_, err := w.BulkLabelTransactions(ctx, &BulkLabelTransactionsRequest{
+		Labels: []*TransactionLabel{{
+			Txid:  txid1[:],
+			Label: "exchange; open",
+		}, {
+			Txid:  txid2[:],
+			Label: "cold storage",
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "open;exchange", walletMock.labels[txid1])
+	require.Equal(t, "sweep;cold storage", walletMock.labels[txid2])
+
+	// Labels can be searched for, ignoring case.
+	resp, err := w.SearchTransactions(ctx, &SearchTransactionsRequest{
+		Query: "EXCHANGE",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Transactions, 1)
+	require.Equal(t, txid1.String(), resp.Transactions[0].TxHash)
+
+	// An invalid label keeps all transactions from being labeled.
+	_, err = w.BulkLabelTransactions(ctx, &BulkLabelTransactionsRequest{
+		Labels: []*TransactionLabel{{
+			Txid:  txid1[:],
+			Label: "merchant",
+		}, {
+			Txid:  txid2[:],
+			Label: " ; ",
+		}},
+		Replace: true,
+	})
+	require.ErrorIs(t, err, ErrZeroLabel)
+	require.Equal(t, "open;exchange", walletMock.labels[txid1])
+
+	_, err = w.BulkLabelTransactions(ctx, &BulkLabelTransactionsRequest{
+		Labels: []*TransactionLabel{{
+			Txid:  []byte{1, 2, 3},
+			Label: "merchant",
+		}},
+	})
+	require.Error(t, err)
+
+	// Replacing drops the labels the transactions carried before.
+	_, err = w.BulkLabelTransactions(ctx, &BulkLabelTransactionsRequest{
+		Labels: []*TransactionLabel{{
+			Txid:  txid1[:],
+			Label: "merchant",
+		}},
+		Replace: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "merchant", walletMock.labels[txid1])
+}
+
+// TestFundedPsbtInVersion tests that funded PSBTs are returned in the
+// requested version, or the version of the template if none is requested.
+func TestFundedPsbtInVersion(t *testing.T) {
+	t.Parallel()
+
+	packet, err := psbt.New(
+		[]*wire.OutPoint{{Index: 1}}, []*wire.TxOut{{Value: 1000}}, 2,
+		0, []uint32{0},
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, packet.Serialize(&buf))
+
+	testCases := []struct {
+		name            string
+		requested       PsbtVersion
+		given           uint32
+		expectedVersion uint32
+	}{
+		{
+			name:            "unchanged v0",
+			requested:       PsbtVersion_PSBT_VERSION_UNCHANGED,
+			given:           psbtv2.Version0,
+			expectedVersion: psbtv2.Version0,
+		},
+		{
+			name:            "unchanged v2",
+			requested:       PsbtVersion_PSBT_VERSION_UNCHANGED,
+			given:           psbtv2.Version2,
+			expectedVersion: psbtv2.Version2,
+		},
+		{
+			name:            "v2 requested",
+			requested:       PsbtVersion_PSBT_VERSION_V2,
+			given:           psbtv2.Version0,
+			expectedVersion: psbtv2.Version2,
+		},
+		{
+			name:            "v0 requested",
+			requested:       PsbtVersion_PSBT_VERSION_V0,
+			given:           psbtv2.Version2,
+			expectedVersion: psbtv2.Version0,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			version, err := outputPsbtVersion(
+				tc.requested, tc.given,
+			)
+			require.NoError(t, err)
+
+			resp, err := fundedPsbtInVersion(&FundPsbtResponse{
+				FundedPsbt: buf.Bytes(),
+			}, version)
+			require.NoError(t, err)
+
+			funded, err := psbtv2.Version(resp.FundedPsbt)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, funded)
+		})
+	}
+
+	_, err = outputPsbtVersion(100, psbtv2.Version0)
+	require.Error(t, err)
+}
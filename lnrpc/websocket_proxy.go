@@ -5,7 +5,10 @@ package lnrpc
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/textproto"
 	"regexp"
@@ -43,6 +46,66 @@ const (
 	// decode in the gRPC <-> WS proxy. gRPC has a similar setting used
 	// elsewhere.
 	MaxWsMsgSize = 4 * 1024 * 1024
+
+	// PingIntervalParam is the GET query parameter a client can use to
+	// set the interval of the ping messages the server sends, for example
+	// to keep the connection alive behind a proxy with a short idle
+	// timeout. The value is a duration like "10s".
+	PingIntervalParam = "ping_interval"
+
+	// ResumableParam is the GET query parameter that, if set to true,
+	// starts a resumable session for a server-streaming RPC. The first
+	// message sent to the client then contains the resume token, and
+	// every message of the stream is wrapped with its sequence number.
+	ResumableParam = "resumable"
+
+	// ResumeTokenParam is the GET query parameter to resume a session
+	// with. The macaroon must be the same as when starting the session.
+	ResumeTokenParam = "resume_token"
+
+	// LastSeqParam is the GET query parameter that holds the sequence
+	// number of the last message the client received when resuming a
+	// session. All later messages are sent again.
+	LastSeqParam = "last_seq"
+
+	// MinPingInterval is the shortest ping interval a client can request.
+	MinPingInterval = time.Second
+)
+
+// The close codes the WebSocket proxy sends when it closes a connection. The
+// codes in the 4000 range are specific to lnd.
+const (
+	// WSCloseNormal is sent when the RPC call finished. The stream may
+	// have ended with an error, which is then the last message.
+	WSCloseNormal = websocket.CloseNormalClosure
+
+	// WSCloseMessageTooBig is sent when a message of the RPC call exceeds
+	// MaxWsMsgSize.
+	WSCloseMessageTooBig = websocket.CloseMessageTooBig
+
+	// WSCloseInternalError is sent when reading the response of the RPC
+	// call failed.
+	WSCloseInternalError = websocket.CloseInternalServerErr
+
+	// WSCloseKeepaliveTimeout is sent when the client didn't answer a
+	// ping in time. Resumable sessions can be resumed afterwards.
+	WSCloseKeepaliveTimeout = 4000
+
+	// WSCloseBadRequest is sent when the parameters of a resumption are
+	// invalid.
+	WSCloseBadRequest = 4400
+
+	// WSCloseUnknownSession is sent when the session to resume doesn't
+	// exist, has expired or was started with another macaroon.
+	WSCloseUnknownSession = 4404
+
+	// WSCloseSessionTaken is sent to the connection of a session that was
+	// resumed by another connection.
+	WSCloseSessionTaken = 4409
+
+	// WSCloseResumeGap is sent when some of the messages after the last
+	// received one are no longer available to resume a session.
+	WSCloseResumeGap = 4410
 )
 
 var (
@@ -78,9 +141,10 @@ var (
 // client. The clientStreamingURIs parameter can hold a list of all patterns
 // for URIs that are mapped to client-streaming RPC methods. We need to keep
 // track of those to make sure we initialize the request body correctly for the
-// underlying grpc-gateway library.
+// underlying grpc-gateway library. Resumable sessions are kept for the resume
+// timeout after their connection dropped, a timeout of zero disables them.
 func NewWebSocketProxy(h http.Handler, logger btclog.Logger,
-	pingInterval, pongWait time.Duration,
+	pingInterval, pongWait, resumeTimeout time.Duration,
 	clientStreamingURIs []*regexp.Regexp) http.Handler {
 
 	p := &WebsocketProxy{
@@ -94,11 +158,13 @@ func NewWebSocketProxy(h http.Handler, logger btclog.Logger,
 			},
 		},
 		clientStreamingURIs: clientStreamingURIs,
+		resumeTimeout:       resumeTimeout,
+		sessions:            newWsSessionStore(),
 	}
 
 	if pingInterval > 0 && pongWait > 0 {
-		p.pingInterval = pingInterval
-		p.pongWait = pongWait
+		p.keepalive.pingInterval = pingInterval
+		p.keepalive.pongWait = pongWait
 	}
 
 	return p
@@ -116,14 +182,68 @@ type WebsocketProxy struct {
 	// underlying grpc-gateway library.
 	clientStreamingURIs []*regexp.Regexp
 
+	// keepalive is the default keepalive of the connections.
+	keepalive wsKeepalive
+
+	// resumeTimeout is the time a resumable session is kept after its
+	// connection dropped.
+	resumeTimeout time.Duration
+
+	// sessions holds the resumable sessions.
+	sessions *wsSessionStore
+}
+
+// wsKeepalive holds the ping/pong settings of a connection.
+type wsKeepalive struct {
 	pingInterval time.Duration
 	pongWait     time.Duration
 }
 
-// pingPongEnabled returns true if a ping interval is set to enable sending and
+// enabled returns true if a ping interval is set to enable sending and
 // expecting regular ping/pong messages.
-func (p *WebsocketProxy) pingPongEnabled() bool {
-	return p.pingInterval > 0 && p.pongWait > 0
+func (k wsKeepalive) enabled() bool {
+	return k.pingInterval > 0 && k.pongWait > 0
+}
+
+// requestKeepalive returns the keepalive settings for the request, which may
+// override the ping interval.
+func (p *WebsocketProxy) requestKeepalive(r *http.Request) (wsKeepalive,
+	error) {
+
+	keepalive := p.keepalive
+	interval := r.URL.Query().Get(PingIntervalParam)
+	if interval == "" {
+		return keepalive, nil
+	}
+
+	pingInterval, err := time.ParseDuration(interval)
+	if err != nil {
+		return keepalive, fmt.Errorf("invalid %s: %w",
+			PingIntervalParam, err)
+	}
+	if pingInterval < MinPingInterval {
+		return keepalive, fmt.Errorf("%s must be at least %v",
+			PingIntervalParam, MinPingInterval)
+	}
+
+	keepalive.pingInterval = pingInterval
+	if keepalive.pongWait == 0 {
+		keepalive.pongWait = DefaultPongWait
+	}
+
+	return keepalive, nil
+}
+
+// isClientStreaming returns true if the path belongs to a client-streaming RPC
+// method.
+func (p *WebsocketProxy) isClientStreaming(path string) bool {
+	for _, pattern := range p.clientStreamingURIs {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // ServeHTTP handles the incoming HTTP request. If the request is an
@@ -135,14 +255,156 @@ func (p *WebsocketProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		p.backend.ServeHTTP(w, r)
 		return
 	}
-	p.upgradeToWebSocketProxy(w, r)
+
+	keepalive, err := p.requestKeepalive(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get(ResumableParam) != "true" &&
+		query.Get(ResumeTokenParam) == "" {
+
+		p.upgradeToWebSocketProxy(w, r, keepalive)
+		return
+	}
+
+	switch {
+	case p.resumeTimeout == 0:
+		http.Error(w, "resumable sessions are disabled",
+			http.StatusBadRequest)
+
+	case p.isClientStreaming(r.URL.Path):
+		http.Error(w, "resumable sessions are only supported for "+
+			"server-streaming RPCs", http.StatusBadRequest)
+
+	default:
+		p.serveResumable(w, r, keepalive)
+	}
+}
+
+// newBackendRequest creates the request to forward to the REST proxy, with
+// the given body.
+func newBackendRequest(ctx context.Context, r *http.Request,
+	body io.Reader) (*http.Request, error) {
+
+	// The parameters of the WebSocket proxy must not reach the REST proxy,
+	// which would try to map them to fields of the RPC request.
+	target := *r.URL
+	query := target.Query()
+	query.Del(PingIntervalParam)
+	query.Del(ResumableParam)
+	query.Del(ResumeTokenParam)
+	query.Del(LastSeqParam)
+	target.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(
+		ctx, r.Method, target.String(), body,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Allow certain headers to be forwarded, either from source headers
+	// or the special Sec-Websocket-Protocol header field.
+	forwardHeaders(r.Header, request.Header)
+
+	// Also allow the target request method to be overwritten, as all
+	// WebSocket establishment calls MUST be GET requests.
+	if m := r.URL.Query().Get(MethodOverrideParam); m != "" {
+		request.Method = m
+	}
+
+	return request, nil
+}
+
+// isTimeoutError returns true if the error is caused by a deadline passing,
+// which happens when a client doesn't answer our pings anymore.
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// startKeepalive sets up the ping/pong handling of the connection if enabled
+// and sends pings until the context is canceled.
+func (p *WebsocketProxy) startKeepalive(ctx context.Context,
+	conn *websocket.Conn, keepalive wsKeepalive) {
+
+	if !keepalive.enabled() {
+		return
+	}
+
+	// We'll send out our first ping in pingInterval. So the initial
+	// deadline is that interval plus the time we allow for a response to
+	// be sent.
+	deadline := keepalive.pingInterval + keepalive.pongWait
+	_ = conn.SetReadDeadline(time.Now().Add(deadline))
+
+	// Whenever a pong message comes in, we extend the deadline until the
+	// next read is expected by the interval plus pong wait time. Since we
+	// can never _reach_ any of the deadlines, we also have to advance the
+	// deadline for the next expected write to happen, in case the next
+	// thing we actually write is the next ping.
+	conn.SetPongHandler(func(appData string) error {
+		nextDeadline := time.Now().Add(deadline)
+		_ = conn.SetReadDeadline(nextDeadline)
+		_ = conn.SetWriteDeadline(nextDeadline)
+
+		return nil
+	})
+
+	go func() {
+		ticker := time.NewTicker(keepalive.pingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				p.logger.Debug("WS: ping loop done")
+				return
+
+			case <-ticker.C:
+				// Writing the ping shouldn't take any longer
+				// than we'll wait for a response in the first
+				// place.
+				writeDeadline := time.Now().Add(
+					keepalive.pongWait,
+				)
+				err := conn.WriteControl(
+					websocket.PingMessage,
+					[]byte(PingContent), writeDeadline,
+				)
+				if err != nil {
+					p.logger.Warnf("WS: could not send "+
+						"ping message: %v", err)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// scanCloseCode returns the close code and reason to send after the response
+// of the RPC call was read with the given error.
+func scanCloseCode(err error) (int, string) {
+	switch {
+	case errors.Is(err, bufio.ErrTooLong):
+		return WSCloseMessageTooBig, "message too big"
+
+	case err != nil && !IsClosedConnError(err):
+		return WSCloseInternalError, "error reading response"
+
+	default:
+		return WSCloseNormal, "call finished"
+	}
 }
 
 // upgradeToWebSocketProxy upgrades the incoming request to a WebSocket, reads
 // one incoming message then streams all responses until either the client or
 // server quit the connection.
 func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
-	r *http.Request) {
+	r *http.Request, keepalive wsKeepalive) {
 
 	conn, err := p.upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -161,31 +423,14 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 	defer cancelFn()
 
 	requestForwarder := newRequestForwardingReader()
-	request, err := http.NewRequestWithContext(
-		ctx, r.Method, r.URL.String(), requestForwarder,
-	)
+	request, err := newBackendRequest(ctx, r, requestForwarder)
 	if err != nil {
 		p.logger.Errorf("WS: error preparing request:", err)
 		return
 	}
 
-	// Allow certain headers to be forwarded, either from source headers
-	// or the special Sec-Websocket-Protocol header field.
-	forwardHeaders(r.Header, request.Header)
-
-	// Also allow the target request method to be overwritten, as all
-	// WebSocket establishment calls MUST be GET requests.
-	if m := r.URL.Query().Get(MethodOverrideParam); m != "" {
-		request.Method = m
-	}
-
 	// Is this a call to a client-streaming RPC method?
-	clientStreaming := false
-	for _, pattern := range p.clientStreamingURIs {
-		if pattern.MatchString(r.URL.Path) {
-			clientStreaming = true
-		}
-	}
+	clientStreaming := p.isClientStreaming(r.URL.Path)
 
 	responseForwarder := newResponseForwardingWriter()
 	go func() {
@@ -226,6 +471,12 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 						"closed: %v", err)
 					return
 				}
+				if isTimeoutError(err) {
+					closeConn(
+						conn, WSCloseKeepaliveTimeout,
+						"keepalive timeout",
+					)
+				}
 				p.logger.Errorf("error reading message: %v",
 					err)
 				return
@@ -278,60 +529,7 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 
 	// Ping write loop: Send a ping message regularly if ping/pong is
 	// enabled.
-	if p.pingPongEnabled() {
-		// We'll send out our first ping in pingInterval. So the initial
-		// deadline is that interval plus the time we allow for a
-		// response to be sent.
-		initialDeadline := time.Now().Add(p.pingInterval + p.pongWait)
-		_ = conn.SetReadDeadline(initialDeadline)
-
-		// Whenever a pong message comes in, we extend the deadline
-		// until the next read is expected by the interval plus pong
-		// wait time. Since we can never _reach_ any of the deadlines,
-		// we also have to advance the deadline for the next expected
-		// write to happen, in case the next thing we actually write is
-		// the next ping.
-		conn.SetPongHandler(func(appData string) error {
-			nextDeadline := time.Now().Add(
-				p.pingInterval + p.pongWait,
-			)
-			_ = conn.SetReadDeadline(nextDeadline)
-			_ = conn.SetWriteDeadline(nextDeadline)
-
-			return nil
-		})
-		go func() {
-			ticker := time.NewTicker(p.pingInterval)
-			defer ticker.Stop()
-
-			for {
-				select {
-				case <-ctx.Done():
-					p.logger.Debug("WS: ping loop done")
-					return
-
-				case <-ticker.C:
-					// Writing the ping shouldn't take any
-					// longer than we'll wait for a response
-					// in the first place.
-					writeDeadline := time.Now().Add(
-						p.pongWait,
-					)
-					err := conn.WriteControl(
-						websocket.PingMessage,
-						[]byte(PingContent),
-						writeDeadline,
-					)
-					if err != nil {
-						p.logger.Warnf("WS: could not "+
-							"send ping message: %v",
-							err)
-						return
-					}
-				}
-			}
-		}()
-	}
+	p.startKeepalive(ctx, conn, keepalive)
 
 	// Write loop: Take messages from the response forwarder and write them
 	// to the WebSocket.
@@ -354,6 +552,10 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 	if err := responseForwarder.Err(); err != nil && !IsClosedConnError(err) {
 		p.logger.Errorf("WS: scanner err: %v", err)
 	}
+
+	// Let the client know why the connection is closed.
+	code, reason := scanCloseCode(responseForwarder.Err())
+	closeConn(conn, code, reason)
 }
 
 // forwardHeaders forwards certain allowed header fields from the source request
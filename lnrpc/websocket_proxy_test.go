@@ -0,0 +1,294 @@
+package lnrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btclog"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// streamBackend is a REST handler of a server-streaming RPC that sends every
+// message passed to it until the channel is closed.
+type streamBackend struct {
+	messages chan string
+}
+
+// ServeHTTP reads the request and streams the messages.
+func (b *streamBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, err := io.ReadAll(r.Body); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case msg, ok := <-b.messages:
+			if !ok {
+				return
+			}
+
+			_, _ = w.Write([]byte(msg + "\n"))
+			w.(http.Flusher).Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// newTestWebSocketProxy starts a server with a WebSocket proxy in front of
+// the backend and returns its WebSocket URL.
+func newTestWebSocketProxy(t *testing.T, backend http.Handler,
+	resumeTimeout time.Duration) string {
+
+	proxy := NewWebSocketProxy(
+		backend, btclog.Disabled, 0, 0, resumeTimeout,
+		[]*regexp.Regexp{regexp.MustCompile("^/v1/client-stream$")},
+	)
+	server := httptest.NewServer(proxy)
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// dialWs connects to the given path of the proxy with the macaroon and query
+// parameters.
+func dialWs(t *testing.T, baseURL, path, macaroon string,
+	query url.Values) (*websocket.Conn, *http.Response, error) {
+
+	header := http.Header{}
+	header.Set("Grpc-Metadata-Macaroon", macaroon)
+
+	conn, resp, err := websocket.DefaultDialer.Dial(
+		baseURL+path+"?"+query.Encode(), header,
+	)
+	if conn != nil {
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+	}
+
+	return conn, resp, err
+}
+
+// readSeqs reads the given number of messages of a resumable session and
+// returns their sequence numbers.
+func readSeqs(t *testing.T, conn *websocket.Conn, n int) []uint64 {
+	seqs := make([]uint64, 0, n)
+	for i := 0; i < n; i++ {
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		var msg struct {
+			Seq     uint64          `json:"seq"`
+			Message json.RawMessage `json:"message"`
+		}
+		require.NoError(t, json.Unmarshal(data, &msg))
+		require.JSONEq(
+			t, fmt.Sprintf(`{"n":%d}`, msg.Seq),
+			string(msg.Message),
+		)
+
+		seqs = append(seqs, msg.Seq)
+	}
+
+	return seqs
+}
+
+// requireCloseCode reads from the connection until it is closed and asserts
+// the close code.
+func requireCloseCode(t *testing.T, conn *websocket.Conn, code int) {
+	_, _, err := conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, code), err)
+}
+
+// TestWebSocketProxyResumableRejected tests that requests for a resumable
+// session are rejected during the handshake if they can't be served.
+func TestWebSocketProxyResumableRejected(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		resumeTimeout time.Duration
+		path          string
+		query         url.Values
+	}{{
+		name:          "resumable sessions disabled",
+		resumeTimeout: 0,
+		path:          "/v1/stream",
+		query:         url.Values{ResumableParam: {"true"}},
+	}, {
+		name:          "client-streaming rpc",
+		resumeTimeout: time.Minute,
+		path:          "/v1/client-stream",
+		query:         url.Values{ResumableParam: {"true"}},
+	}, {
+		name:          "invalid last_seq",
+		resumeTimeout: time.Minute,
+		path:          "/v1/stream",
+		query: url.Values{
+			ResumeTokenParam: {"00"},
+			LastSeqParam:     {"-1"},
+		},
+	}, {
+		name:          "ping interval too short",
+		resumeTimeout: time.Minute,
+		path:          "/v1/stream",
+		query: url.Values{
+			ResumableParam:    {"true"},
+			PingIntervalParam: {"10ms"},
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wsURL := newTestWebSocketProxy(
+				t, &streamBackend{}, tc.resumeTimeout,
+			)
+
+			_, resp, err := dialWs(
+				t, wsURL, tc.path, "mac", tc.query,
+			)
+			require.ErrorIs(t, err, websocket.ErrBadHandshake)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			require.NoError(t, resp.Body.Close())
+		})
+	}
+}
+
+// TestWebSocketProxyResume tests that a resumable session can be resumed by a
+// new connection with the same macaroon, which receives the messages it
+// missed, and that the session is closed with the right code once the stream
+// ends.
+func TestWebSocketProxyResume(t *testing.T) {
+	t.Parallel()
+
+	backend := &streamBackend{messages: make(chan string)}
+	wsURL := newTestWebSocketProxy(t, backend, time.Minute)
+
+	// Start the session and read the resume token.
+	conn, _, err := dialWs(
+		t, wsURL, "/v1/stream", "mac",
+		url.Values{ResumableParam: {"true"}},
+	)
+	require.NoError(t, err)
+	err = conn.WriteMessage(websocket.TextMessage, []byte("{}"))
+	require.NoError(t, err)
+
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var start struct {
+		ResumeToken string `json:"resume_token"`
+	}
+	require.NoError(t, json.Unmarshal(data, &start))
+	require.NotEmpty(t, start.ResumeToken)
+
+	backend.messages <- `{"n":1}`
+	require.Equal(t, []uint64{1}, readSeqs(t, conn, 1))
+
+	// Drop the connection and send more messages while no connection is
+	// attached.
+	require.NoError(t, conn.Close())
+	backend.messages <- `{"n":2}`
+	backend.messages <- `{"n":3}`
+
+	resumeQuery := func(lastSeq string) url.Values {
+		return url.Values{
+			ResumeTokenParam: {start.ResumeToken},
+			LastSeqParam:     {lastSeq},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		macaroon   string
+		query      url.Values
+		expectCode int
+		expectSeqs []uint64
+	}{{
+		name:     "unknown token",
+		macaroon: "mac",
+		query: url.Values{
+			ResumeTokenParam: {"00"},
+		},
+		expectCode: WSCloseUnknownSession,
+	}, {
+		name:       "other macaroon",
+		macaroon:   "other",
+		query:      resumeQuery("1"),
+		expectCode: WSCloseUnknownSession,
+	}, {
+		name:       "last_seq never sent",
+		macaroon:   "mac",
+		query:      resumeQuery("4"),
+		expectCode: WSCloseBadRequest,
+	}, {
+		name:       "resume from the start",
+		macaroon:   "mac",
+		query:      resumeQuery("0"),
+		expectSeqs: []uint64{1, 2, 3},
+	}, {
+		name:       "resume after the last received message",
+		macaroon:   "mac",
+		query:      resumeQuery("1"),
+		expectSeqs: []uint64{2, 3},
+	}}
+
+	// The cases share the session, so they can't run in parallel.
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, _, err := dialWs(
+				t, wsURL, "/v1/stream", tc.macaroon, tc.query,
+			)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			if tc.expectCode != 0 {
+				requireCloseCode(t, conn, tc.expectCode)
+				return
+			}
+
+			require.Equal(
+				t, tc.expectSeqs,
+				readSeqs(t, conn, len(tc.expectSeqs)),
+			)
+		})
+	}
+
+	// A connection resuming the session takes it over from the one
+	// attached.
+	first, _, err := dialWs(
+		t, wsURL, "/v1/stream", "mac", resumeQuery("3"),
+	)
+	require.NoError(t, err)
+
+	backend.messages <- `{"n":4}`
+	require.Equal(t, []uint64{4}, readSeqs(t, first, 1))
+
+	second, _, err := dialWs(
+		t, wsURL, "/v1/stream", "mac", resumeQuery("4"),
+	)
+	require.NoError(t, err)
+	requireCloseCode(t, first, WSCloseSessionTaken)
+
+	// Once the stream ends, the attached connection is closed normally.
+	backend.messages <- `{"n":5}`
+	require.Equal(t, []uint64{5}, readSeqs(t, second, 1))
+
+	close(backend.messages)
+	requireCloseCode(t, second, WSCloseNormal)
+}
@@ -0,0 +1,481 @@
+package lnrpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// MaxWsResumeMessages is the number of the most recent messages of a
+	// resumable WebSocket session that are kept for replay when a client
+	// resumes the session.
+	MaxWsResumeMessages = 1000
+)
+
+var (
+	// DefaultResumeTimeout is the default duration a resumable WebSocket
+	// session is kept alive after its connection dropped.
+	DefaultResumeTimeout = time.Minute
+)
+
+// wsMessage is a message of a resumable WebSocket session.
+type wsMessage struct {
+	seq  uint64
+	data []byte
+}
+
+// wsSession is a server-streaming RPC call that outlives the WebSocket
+// connection it was started with. The messages of the stream are numbered and
+// the most recent ones are kept, so a client can resume the session with a new
+// connection after the old one dropped and receive the messages it missed.
+type wsSession struct {
+	// token identifies the session to resume.
+	token string
+
+	// authHash is the hash of the macaroon the session was started with.
+	// Only a client presenting the same macaroon can resume it.
+	authHash [32]byte
+
+	// cancel stops the RPC call of the session.
+	cancel func()
+
+	mu sync.Mutex
+
+	// messages are the most recent messages of the stream, at most
+	// MaxWsResumeMessages.
+	messages []wsMessage
+
+	// nextSeq is the sequence number of the next message. Sequence numbers
+	// start at one.
+	nextSeq uint64
+
+	// done is true once the stream ended, closeCode and closeReason
+	// describe how.
+	done        bool
+	closeCode   int
+	closeReason string
+
+	// changed is closed and replaced whenever a message is added or the
+	// stream ended.
+	changed chan struct{}
+
+	// conn is the attached connection, if any, and connID its number.
+	conn   *websocket.Conn
+	connID uint64
+
+	// expiry removes the session once the resume timeout passed without
+	// a connection being attached.
+	expiry *time.Timer
+}
+
+// newWsSession creates a new session with a random token.
+func newWsSession(authHash [32]byte, cancel func()) (*wsSession, error) {
+	var token [16]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		return nil, err
+	}
+
+	return &wsSession{
+		token:    hex.EncodeToString(token[:]),
+		authHash: authHash,
+		cancel:   cancel,
+		nextSeq:  1,
+		changed:  make(chan struct{}),
+	}, nil
+}
+
+// authorized returns true if the given hash of a macaroon matches the one the
+// session was started with.
+func (s *wsSession) authorized(authHash [32]byte) bool {
+	return subtle.ConstantTimeCompare(s.authHash[:], authHash[:]) == 1
+}
+
+// notifyLocked wakes up the connection waiting for changes.
+//
+// NOTE: The mutex must be held.
+func (s *wsSession) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// add appends a message of the stream, dropping the oldest one if the buffer
+// is full.
+func (s *wsSession) add(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, wsMessage{
+		seq:  s.nextSeq,
+		data: append([]byte(nil), data...),
+	})
+	s.nextSeq++
+
+	if len(s.messages) > MaxWsResumeMessages {
+		s.messages = s.messages[len(s.messages)-MaxWsResumeMessages:]
+	}
+
+	s.notifyLocked()
+}
+
+// finish marks the stream as ended.
+func (s *wsSession) finish(code int, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done = true
+	s.closeCode = code
+	s.closeReason = reason
+	s.notifyLocked()
+}
+
+// pending returns the messages after the given sequence number, whether the
+// stream ended and the channel that is closed on the next change.
+func (s *wsSession) pending(after uint64) ([]wsMessage, bool,
+	<-chan struct{}) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var msgs []wsMessage
+	for _, msg := range s.messages {
+		if msg.seq > after {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs, s.done, s.changed
+}
+
+// attach makes the connection the one the session's messages are delivered
+// to, replacing any connection attached before. The messages after lastSeq
+// must still be available, otherwise a close code is returned.
+func (s *wsSession) attach(conn *websocket.Conn, lastSeq uint64) (uint64,
+	int, string) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lastSeq >= s.nextSeq {
+		return 0, WSCloseBadRequest, fmt.Sprintf("last_seq %d was "+
+			"never sent", lastSeq)
+	}
+
+	// The client missed messages that were already dropped.
+	if len(s.messages) > 0 && s.messages[0].seq > lastSeq+1 {
+		return 0, WSCloseResumeGap, fmt.Sprintf("messages after "+
+			"last_seq %d are no longer available", lastSeq)
+	}
+
+	if s.conn != nil {
+		closeConn(
+			s.conn, WSCloseSessionTaken, "session resumed by "+
+				"another connection",
+		)
+	}
+	if s.expiry != nil {
+		s.expiry.Stop()
+		s.expiry = nil
+	}
+
+	s.conn = conn
+	s.connID++
+
+	return s.connID, 0, ""
+}
+
+// detach removes the connection from the session if it is still attached and
+// removes the session with the given function once the timeout passed
+// without another connection being attached.
+func (s *wsSession) detach(connID uint64, timeout time.Duration,
+	remove func()) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.connID != connID || s.conn == nil {
+		return
+	}
+
+	s.conn = nil
+	s.expiry = time.AfterFunc(timeout, remove)
+}
+
+// wsSessionStore holds the resumable sessions by their token.
+type wsSessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*wsSession
+}
+
+// newWsSessionStore creates an empty session store.
+func newWsSessionStore() *wsSessionStore {
+	return &wsSessionStore{
+		sessions: make(map[string]*wsSession),
+	}
+}
+
+// add stores the session.
+func (s *wsSessionStore) add(session *wsSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[session.token] = session
+}
+
+// get returns the session with the given token if it exists and the
+// macaroon hash matches.
+func (s *wsSessionStore) get(token string, authHash [32]byte) (*wsSession,
+	bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[token]
+	if !ok || !session.authorized(authHash) {
+		return nil, false
+	}
+
+	return session, true
+}
+
+// remove stops the session's RPC call and deletes it.
+func (s *wsSessionStore) remove(session *wsSession) {
+	s.mu.Lock()
+	delete(s.sessions, session.token)
+	s.mu.Unlock()
+
+	session.cancel()
+}
+
+// macaroonHash returns the hash of the macaroon header of a request.
+func macaroonHash(header http.Header) [32]byte {
+	return sha256.Sum256([]byte(header.Get("Grpc-Metadata-Macaroon")))
+}
+
+// closeConn sends a close message with the given code and reason. Errors are
+// ignored, as the connection may already be gone.
+func closeConn(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(time.Second),
+	)
+}
+
+// serveResumable upgrades the request to a WebSocket that is attached to a new
+// resumable session, or to the existing session named by the resume token.
+func (p *WebsocketProxy) serveResumable(w http.ResponseWriter, r *http.Request,
+	keepalive wsKeepalive) {
+
+	query := r.URL.Query()
+
+	var lastSeq uint64
+	if seq := query.Get(LastSeqParam); seq != "" {
+		var err error
+		lastSeq, err = strconv.ParseUint(seq, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s: %v",
+				LastSeqParam, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	conn, err := p.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		p.logger.Errorf("error upgrading websocket: %v", err)
+		return
+	}
+	defer func() {
+		err := conn.Close()
+		if err != nil && !IsClosedConnError(err) {
+			p.logger.Errorf("WS: error closing upgraded conn: %v",
+				err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	p.startKeepalive(ctx, conn, keepalive)
+
+	// The macaroon is forwarded in a header or a protocol, so we hash it
+	// as it would be forwarded.
+	forwarded := http.Header{}
+	forwardHeaders(r.Header, forwarded)
+	authHash := macaroonHash(forwarded)
+
+	var session *wsSession
+	if token := query.Get(ResumeTokenParam); token != "" {
+		var ok bool
+		session, ok = p.sessions.get(token, authHash)
+		if !ok {
+			closeConn(
+				conn, WSCloseUnknownSession, "unknown session",
+			)
+			return
+		}
+	} else {
+		session, err = p.startSession(r, conn, authHash)
+		if err != nil {
+			p.logger.Errorf("WS: unable to start session: %v", err)
+			closeConn(
+				conn, WSCloseInternalError,
+				"unable to start session",
+			)
+
+			return
+		}
+	}
+
+	connID, code, reason := session.attach(conn, lastSeq)
+	if code != 0 {
+		closeConn(conn, code, reason)
+		return
+	}
+	defer session.detach(connID, p.resumeTimeout, func() {
+		p.sessions.remove(session)
+	})
+
+	// Read loop: The request was sent when the session started, so we only
+	// read to process control messages and to notice when the connection
+	// is gone.
+	go func() {
+		defer cancel()
+
+		for {
+			_, _, err := conn.ReadMessage()
+			if err == nil {
+				continue
+			}
+
+			if isTimeoutError(err) {
+				closeConn(
+					conn, WSCloseKeepaliveTimeout,
+					"keepalive timeout",
+				)
+			}
+
+			return
+		}
+	}()
+
+	// Write loop: Deliver all messages after the last one the client
+	// received, until the stream ends or the connection is gone.
+	sent := lastSeq
+	for {
+		msgs, done, changed := session.pending(sent)
+		for _, msg := range msgs {
+			err := conn.WriteMessage(
+				websocket.TextMessage, []byte(fmt.Sprintf(
+					`{"seq":%d,"message":%s}`, msg.seq,
+					msg.data,
+				)),
+			)
+			if err != nil {
+				p.logger.Debugf("WS: error writing message "+
+					"of session: %v", err)
+				return
+			}
+			sent = msg.seq
+		}
+
+		if done {
+			session.mu.Lock()
+			code, reason := session.closeCode, session.closeReason
+			session.mu.Unlock()
+
+			closeConn(conn, code, reason)
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// startSession starts the RPC call of a new session. The first message of the
+// connection is the request of the call, and the resume token is sent back.
+func (p *WebsocketProxy) startSession(r *http.Request, conn *websocket.Conn,
+	authHash [32]byte) (*wsSession, error) {
+
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read request: %w", err)
+	}
+
+	// The call outlives the connection, so it must not be canceled with
+	// the request of the connection.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	session, err := newWsSession(authHash, cancel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	requestForwarder := newRequestForwardingReader()
+	request, err := newBackendRequest(ctx, r, requestForwarder)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	err = conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+		`{"resume_token":%q}`, session.token,
+	)))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	responseForwarder := newResponseForwardingWriter()
+	go func() {
+		<-ctx.Done()
+		responseForwarder.Close()
+		requestForwarder.CloseWriter()
+	}()
+
+	go func() {
+		defer cancel()
+		p.backend.ServeHTTP(responseForwarder, request)
+	}()
+
+	go func() {
+		// The request isn't streaming, so it is complete after the
+		// first message.
+		_, err := requestForwarder.Write(append(payload, '\n'))
+		if err != nil {
+			p.logger.Errorf("WS: error writing message to "+
+				"upstream http server: %v", err)
+		}
+		requestForwarder.CloseWriter()
+	}()
+
+	go func() {
+		for responseForwarder.Scan() {
+			if len(responseForwarder.Bytes()) == 0 {
+				continue
+			}
+
+			session.add(responseForwarder.Bytes())
+		}
+
+		session.finish(scanCloseCode(responseForwarder.Err()))
+	}()
+
+	p.sessions.add(session)
+
+	return session, nil
+}
@@ -0,0 +1,290 @@
+package lnrpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWsSessionAttach tests that a connection can only be attached to a
+// session if all messages after the last received one are still available.
+func TestWsSessionAttach(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		numMessages int
+		lastSeq     uint64
+		expectCode  int
+		expectSeqs  []uint64
+	}{{
+		name:        "new session",
+		numMessages: 0,
+		lastSeq:     0,
+	}, {
+		name:        "all messages missed",
+		numMessages: 3,
+		lastSeq:     0,
+		expectSeqs:  []uint64{1, 2, 3},
+	}, {
+		name:        "some messages missed",
+		numMessages: 3,
+		lastSeq:     1,
+		expectSeqs:  []uint64{2, 3},
+	}, {
+		name:        "no messages missed",
+		numMessages: 3,
+		lastSeq:     3,
+	}, {
+		name:        "last_seq never sent",
+		numMessages: 3,
+		lastSeq:     4,
+		expectCode:  WSCloseBadRequest,
+	}, {
+		name:        "oldest missed message still kept",
+		numMessages: MaxWsResumeMessages + 1,
+		lastSeq:     1,
+		expectSeqs:  seqRange(2, MaxWsResumeMessages+1),
+	}, {
+		name:        "missed messages dropped",
+		numMessages: MaxWsResumeMessages + 1,
+		lastSeq:     0,
+		expectCode:  WSCloseResumeGap,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			session, err := newWsSession([32]byte{1}, func() {})
+			require.NoError(t, err)
+
+			for i := 0; i < tc.numMessages; i++ {
+				session.add([]byte(fmt.Sprintf("%d", i)))
+			}
+
+			connID, code, reason := session.attach(nil, tc.lastSeq)
+			require.Equal(t, tc.expectCode, code, reason)
+			if tc.expectCode != 0 {
+				require.Zero(t, connID)
+				return
+			}
+			require.EqualValues(t, 1, connID)
+
+			msgs, done, _ := session.pending(tc.lastSeq)
+			require.False(t, done)
+
+			var seqs []uint64
+			for _, msg := range msgs {
+				seqs = append(seqs, msg.seq)
+			}
+			require.Equal(t, tc.expectSeqs, seqs)
+		})
+	}
+}
+
+// seqRange returns the sequence numbers from first to last.
+func seqRange(first, last uint64) []uint64 {
+	var seqs []uint64
+	for seq := first; seq <= last; seq++ {
+		seqs = append(seqs, seq)
+	}
+
+	return seqs
+}
+
+// TestWsSessionFinish tests that a finished session wakes up its connection
+// and reports how the stream ended.
+func TestWsSessionFinish(t *testing.T) {
+	t.Parallel()
+
+	session, err := newWsSession([32]byte{1}, func() {})
+	require.NoError(t, err)
+
+	_, _, changed := session.pending(0)
+	session.add([]byte("1"))
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("adding a message didn't notify")
+	}
+
+	_, done, changed := session.pending(1)
+	require.False(t, done)
+	session.finish(WSCloseNormal, "call finished")
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("finishing the session didn't notify")
+	}
+
+	msgs, done, _ := session.pending(1)
+	require.Empty(t, msgs)
+	require.True(t, done)
+	require.Equal(t, WSCloseNormal, session.closeCode)
+	require.Equal(t, "call finished", session.closeReason)
+}
+
+// newWsConnPair returns both ends of a new WebSocket connection.
+func newWsConnPair(t *testing.T) (*websocket.Conn, *websocket.Conn) {
+	serverConns := make(chan *websocket.Conn, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			require.NoError(t, err)
+
+			serverConns <- conn
+		},
+	))
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+
+	serverConn := <-serverConns
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = serverConn.Close()
+	})
+
+	return client, serverConn
+}
+
+// TestWsSessionDetach tests that a session is only removed after the resume
+// timeout if its current connection is detached and no other connection is
+// attached in the meantime.
+func TestWsSessionDetach(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		replace       bool
+		reattach      bool
+		expectRemoved bool
+	}{{
+		name:          "connection detached",
+		expectRemoved: true,
+	}, {
+		name:    "replaced connection detached",
+		replace: true,
+	}, {
+		name:     "resumed before timeout",
+		reattach: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			const timeout = 50 * time.Millisecond
+
+			canceled := make(chan struct{})
+			session, err := newWsSession([32]byte{1}, func() {
+				close(canceled)
+			})
+			require.NoError(t, err)
+
+			store := newWsSessionStore()
+			store.add(session)
+			remove := func() {
+				store.remove(session)
+			}
+
+			conn, peer := newWsConnPair(t)
+			connID, code, _ := session.attach(conn, 0)
+			require.Zero(t, code)
+
+			// The replaced connection is closed, and detaching it
+			// must not detach the new one.
+			if tc.replace {
+				newConn, _ := newWsConnPair(t)
+				_, code, _ := session.attach(newConn, 0)
+				require.Zero(t, code)
+
+				_, _, err := peer.ReadMessage()
+				require.True(t, websocket.IsCloseError(
+					err, WSCloseSessionTaken,
+				), err)
+			}
+
+			session.detach(connID, timeout, remove)
+
+			if tc.reattach {
+				newConn, _ := newWsConnPair(t)
+				_, code, _ := session.attach(newConn, 0)
+				require.Zero(t, code)
+			}
+
+			select {
+			case <-canceled:
+				require.True(t, tc.expectRemoved)
+
+			case <-time.After(4 * timeout):
+				require.False(t, tc.expectRemoved)
+			}
+
+			_, ok := store.get(session.token, [32]byte{1})
+			require.Equal(t, !tc.expectRemoved, ok)
+		})
+	}
+}
+
+// TestWsSessionStoreGet tests that a session can only be retrieved with the
+// macaroon it was started with.
+func TestWsSessionStoreGet(t *testing.T) {
+	t.Parallel()
+
+	session, err := newWsSession([32]byte{1}, func() {})
+	require.NoError(t, err)
+
+	store := newWsSessionStore()
+	store.add(session)
+
+	testCases := []struct {
+		name     string
+		token    string
+		authHash [32]byte
+		expectOK bool
+	}{{
+		name:     "matching macaroon",
+		token:    session.token,
+		authHash: [32]byte{1},
+		expectOK: true,
+	}, {
+		name:     "other macaroon",
+		token:    session.token,
+		authHash: [32]byte{2},
+	}, {
+		name:     "unknown token",
+		token:    "00",
+		authHash: [32]byte{1},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			found, ok := store.get(tc.token, tc.authHash)
+			require.Equal(t, tc.expectOK, ok)
+			if tc.expectOK {
+				require.Same(t, session, found)
+			}
+		})
+	}
+}
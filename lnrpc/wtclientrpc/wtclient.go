@@ -68,6 +68,14 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/wtclientrpc.WatchtowerClient/MigrateTower": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/wtclientrpc.WatchtowerClient/MigrationStatus": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 
 	// ErrWtclientNotActive signals that RPC calls cannot be processed
@@ -518,6 +526,84 @@ func (c *WatchtowerClient) Policy(ctx context.Context,
 	}, nil
 }
 
+// MigrateTower migrates the backups stored with a decommissioned tower to a
+// new one, so switching towers doesn't leave a protection gap. The new tower
+// is added, the old one is deactivated and the backed up states of all open
+// channels are uploaded again.
+func (c *WatchtowerClient) MigrateTower(_ context.Context,
+	req *MigrateTowerRequest) (*MigrateTowerResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	oldKey, err := btcec.ParsePubKey(req.OldPubkey)
+	if err != nil {
+		return nil, err
+	}
+	newKey, err := btcec.ParsePubKey(req.NewPubkey)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := lncfg.ParseAddressString(
+		req.NewAddress, strconv.Itoa(watchtower.DefaultPeerPort),
+		c.cfg.Resolver,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %v: %w",
+			req.NewAddress, err)
+	}
+
+	err = c.cfg.ClientMgr.MigrateTower(oldKey, &lnwire.NetAddress{
+		IdentityKey: newKey,
+		Address:     addr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := c.cfg.ClientMgr.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MigrateTowerResponse{
+		NumBackups: uint32(status.NumBackups),
+		NumSkipped: uint32(status.NumSkipped),
+	}, nil
+}
+
+// MigrationStatus returns the progress of the last tower migration started
+// since startup.
+func (c *WatchtowerClient) MigrationStatus(_ context.Context,
+	_ *MigrationStatusRequest) (*MigrationStatusResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	status, err := c.cfg.ClientMgr.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MigrationStatusResponse{
+		OldPubkey:   status.OldTower.SerializeCompressed(),
+		NewPubkey:   status.NewTower.SerializeCompressed(),
+		StartTime:   status.StartTime.Unix(),
+		NumBackups:  uint32(status.NumBackups),
+		NumSkipped:  uint32(status.NumSkipped),
+		NumQueued:   uint32(status.NumQueued),
+		NumBackedUp: uint32(status.NumBackedUp),
+		Complete:    status.Complete(),
+	}
+	if status.Err != nil {
+		resp.Error = status.Err.Error()
+	}
+
+	return resp, nil
+}
+
 // marshallTower converts a client registered watchtower into its corresponding
 // RPC type.
 func marshallTower(tower *wtclient.RegisteredTower, policyType PolicyType,
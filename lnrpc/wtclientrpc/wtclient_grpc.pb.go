@@ -51,6 +51,18 @@ type WatchtowerClientClient interface {
 	// lncli: `wtclient policy`
 	// Policy returns the active watchtower client policy configuration.
 	Policy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
+	// lncli: `wtclient migrate`
+	// MigrateTower migrates the backups stored with a decommissioned tower to a
+	// new one, so switching towers doesn't leave a protection gap. The new tower
+	// is added, the old one is deactivated and the backed up states of all open
+	// channels are uploaded again. The progress can be followed with
+	// MigrationStatus.
+	MigrateTower(ctx context.Context, in *MigrateTowerRequest, opts ...grpc.CallOption) (*MigrateTowerResponse, error)
+	// lncli: `wtclient migration`
+	// MigrationStatus returns the progress of the last tower migration started
+	// since startup. Once the migration is complete, the old tower can be
+	// removed.
+	MigrationStatus(ctx context.Context, in *MigrationStatusRequest, opts ...grpc.CallOption) (*MigrationStatusResponse, error)
 }
 
 type watchtowerClientClient struct {
@@ -133,6 +145,24 @@ func (c *watchtowerClientClient) Policy(ctx context.Context, in *PolicyRequest,
 	return out, nil
 }
 
+func (c *watchtowerClientClient) MigrateTower(ctx context.Context, in *MigrateTowerRequest, opts ...grpc.CallOption) (*MigrateTowerResponse, error) {
+	out := new(MigrateTowerResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/MigrateTower", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) MigrationStatus(ctx context.Context, in *MigrationStatusRequest, opts ...grpc.CallOption) (*MigrationStatusResponse, error) {
+	out := new(MigrationStatusResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/MigrationStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WatchtowerClientServer is the server API for WatchtowerClient service.
 // All implementations must embed UnimplementedWatchtowerClientServer
 // for forward compatibility
@@ -170,6 +200,18 @@ type WatchtowerClientServer interface {
 	// lncli: `wtclient policy`
 	// Policy returns the active watchtower client policy configuration.
 	Policy(context.Context, *PolicyRequest) (*PolicyResponse, error)
+	// lncli: `wtclient migrate`
+	// MigrateTower migrates the backups stored with a decommissioned tower to a
+	// new one, so switching towers doesn't leave a protection gap. The new tower
+	// is added, the old one is deactivated and the backed up states of all open
+	// channels are uploaded again. The progress can be followed with
+	// MigrationStatus.
+	MigrateTower(context.Context, *MigrateTowerRequest) (*MigrateTowerResponse, error)
+	// lncli: `wtclient migration`
+	// MigrationStatus returns the progress of the last tower migration started
+	// since startup. Once the migration is complete, the old tower can be
+	// removed.
+	MigrationStatus(context.Context, *MigrationStatusRequest) (*MigrationStatusResponse, error)
 	mustEmbedUnimplementedWatchtowerClientServer()
 }
 
@@ -201,6 +243,12 @@ func (UnimplementedWatchtowerClientServer) Stats(context.Context, *StatsRequest)
 func (UnimplementedWatchtowerClientServer) Policy(context.Context, *PolicyRequest) (*PolicyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Policy not implemented")
 }
+func (UnimplementedWatchtowerClientServer) MigrateTower(context.Context, *MigrateTowerRequest) (*MigrateTowerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateTower not implemented")
+}
+func (UnimplementedWatchtowerClientServer) MigrationStatus(context.Context, *MigrationStatusRequest) (*MigrationStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrationStatus not implemented")
+}
 func (UnimplementedWatchtowerClientServer) mustEmbedUnimplementedWatchtowerClientServer() {}
 
 // UnsafeWatchtowerClientServer may be embedded to opt out of forward compatibility for this service.
@@ -358,6 +406,42 @@ func _WatchtowerClient_Policy_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WatchtowerClient_MigrateTower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateTowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).MigrateTower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/MigrateTower",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).MigrateTower(ctx, req.(*MigrateTowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_MigrationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).MigrationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/MigrationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).MigrationStatus(ctx, req.(*MigrationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WatchtowerClient_ServiceDesc is the grpc.ServiceDesc for WatchtowerClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -397,6 +481,14 @@ var WatchtowerClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Policy",
 			Handler:    _WatchtowerClient_Policy_Handler,
 		},
+		{
+			MethodName: "MigrateTower",
+			Handler:    _WatchtowerClient_MigrateTower_Handler,
+		},
+		{
+			MethodName: "MigrationStatus",
+			Handler:    _WatchtowerClient_MigrationStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "wtclientrpc/wtclient.proto",
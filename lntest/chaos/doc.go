@@ -0,0 +1,10 @@
+/*
+Package chaos provides fault injection for the integration test harness.
+
+Its Proxy is a TCP proxy that can be placed between two lnd nodes or between
+an lnd node and its chain backend. While a test runs, it can add latency to
+the link, silently drop all traffic, drop the active connections, or take the
+target offline. This allows the reconnection and retransmission logic of lnd
+to be exercised deterministically.
+*/
+package chaos
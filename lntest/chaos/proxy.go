@@ -0,0 +1,301 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lntest/port"
+)
+
+const (
+	// readBufferSize is the size of the buffer used to read from either
+	// side of a proxied connection.
+	readBufferSize = 64 * 1024
+
+	// pendingChunks is the number of chunks that can be in flight for one
+	// direction of a proxied connection before reading is paused.
+	pendingChunks = 1024
+)
+
+// chunk is a piece of data read from one side of a proxied connection.
+type chunk struct {
+	data []byte
+
+	// deliverAt is the time the chunk is written to the other side.
+	deliverAt time.Time
+}
+
+// Proxy is a TCP proxy placed between two endpoints, such as two lnd nodes or
+// an lnd node and its chain backend. It forwards all traffic unmodified, but
+// allows a test to inject faults on the link:
+//   - latency that is added to all data in both directions.
+//   - a blackhole mode in which all data is silently dropped while the
+//     connections stay open, simulating lost messages or a network
+//     partition.
+//   - disconnects of all active connections.
+//   - an offline mode in which the target can't be reached at all.
+//
+// NOTE: the traffic between lnd nodes is encrypted, so individual messages
+// can't be dropped without breaking the connection. The blackhole mode drops
+// everything sent while it's active instead.
+type Proxy struct {
+	// target is the address all connections are forwarded to.
+	target string
+
+	listener net.Listener
+
+	// latency is the delay added to all forwarded data, in nanoseconds.
+	latency atomic.Int64
+
+	// blackhole indicates whether all data is currently dropped.
+	blackhole atomic.Bool
+
+	// offline indicates whether new connections are currently rejected.
+	offline atomic.Bool
+
+	// conns holds both sides of all active connections.
+	conns   map[net.Conn]struct{}
+	connsMu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewProxy creates a new proxy for the given target address that listens on
+// the next available local port.
+func NewProxy(target string) (*Proxy, error) {
+	addr := fmt.Sprintf(port.ListenerFormat, port.NextAvailablePort())
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen: %w", err)
+	}
+
+	p := &Proxy{
+		target:   target,
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+		quit:     make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.acceptConns()
+
+	return p, nil
+}
+
+// Addr returns the address the proxy listens on. Connecting to it is the same
+// as connecting to the target.
+func (p *Proxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+// Target returns the address the proxy forwards all connections to.
+func (p *Proxy) Target() string {
+	return p.target
+}
+
+// SetLatency sets the latency that is added to all data forwarded in either
+// direction. It only applies to data read after the call.
+func (p *Proxy) SetLatency(latency time.Duration) {
+	p.latency.Store(int64(latency))
+}
+
+// SetBlackhole enables or disables the blackhole mode. While enabled, all data
+// in both directions is dropped, but the connections stay open.
+func (p *Proxy) SetBlackhole(enabled bool) {
+	p.blackhole.Store(enabled)
+}
+
+// SetOffline enables or disables the offline mode. When enabled, all active
+// connections are closed and new ones are rejected until it's disabled again.
+func (p *Proxy) SetOffline(enabled bool) {
+	p.offline.Store(enabled)
+
+	if enabled {
+		p.Disconnect()
+	}
+}
+
+// Disconnect closes all active connections. New connections are still
+// accepted.
+func (p *Proxy) Disconnect() {
+	p.connsMu.Lock()
+	defer p.connsMu.Unlock()
+
+	for conn := range p.conns {
+		_ = conn.Close()
+	}
+}
+
+// NumConns returns the number of active connections.
+func (p *Proxy) NumConns() int {
+	p.connsMu.Lock()
+	defer p.connsMu.Unlock()
+
+	// Both sides of each connection are tracked.
+	return len(p.conns) / 2
+}
+
+// Stop closes the listener and all active connections, and waits for all
+// goroutines to exit.
+func (p *Proxy) Stop() error {
+	close(p.quit)
+	err := p.listener.Close()
+
+	p.Disconnect()
+	p.wg.Wait()
+
+	return err
+}
+
+// acceptConns accepts new connections and starts forwarding them to the
+// target.
+//
+// NOTE: must be run as a goroutine.
+func (p *Proxy) acceptConns() {
+	defer p.wg.Done()
+
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			select {
+			case <-p.quit:
+				return
+			default:
+			}
+
+			// Temporary errors are retried, everything else means
+			// the listener is gone.
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+
+			return
+		}
+
+		if p.offline.Load() {
+			_ = conn.Close()
+			continue
+		}
+
+		target, err := net.Dial("tcp", p.target)
+		if err != nil {
+			_ = conn.Close()
+			continue
+		}
+
+		if !p.track(conn, target) {
+			return
+		}
+
+		p.wg.Add(2)
+		go p.forward(conn, target)
+		go p.forward(target, conn)
+	}
+}
+
+// track adds both sides of a connection to the set of active connections. It
+// returns false and closes both sides if the proxy is shutting down.
+func (p *Proxy) track(a, b net.Conn) bool {
+	p.connsMu.Lock()
+	defer p.connsMu.Unlock()
+
+	select {
+	case <-p.quit:
+		_ = a.Close()
+		_ = b.Close()
+
+		return false
+	default:
+	}
+
+	p.conns[a] = struct{}{}
+	p.conns[b] = struct{}{}
+
+	return true
+}
+
+// untrack closes both sides of a connection and removes them from the set of
+// active connections.
+func (p *Proxy) untrack(a, b net.Conn) {
+	p.connsMu.Lock()
+	defer p.connsMu.Unlock()
+
+	_ = a.Close()
+	_ = b.Close()
+
+	delete(p.conns, a)
+	delete(p.conns, b)
+}
+
+// forward copies all data from src to dst, applying the configured faults.
+// Once either side is closed, both sides are closed.
+//
+// NOTE: must be run as a goroutine.
+func (p *Proxy) forward(src, dst net.Conn) {
+	defer p.wg.Done()
+	defer p.untrack(src, dst)
+
+	chunks := make(chan chunk, pendingChunks)
+
+	// The data is written in a separate goroutine, so the latency delays
+	// the data without limiting the throughput.
+	writeDone := make(chan struct{})
+	go func() {
+		defer close(writeDone)
+
+		for c := range chunks {
+			if wait := time.Until(c.deliverAt); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-p.quit:
+					return
+				}
+			}
+
+			if _, err := dst.Write(c.data); err != nil {
+				// Make sure the reader exits too.
+				_ = src.Close()
+
+				return
+			}
+		}
+	}()
+
+	defer func() {
+		close(chunks)
+		<-writeDone
+	}()
+
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 && !p.blackhole.Load() {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+
+			latency := time.Duration(p.latency.Load())
+			c := chunk{
+				data:      data,
+				deliverAt: time.Now().Add(latency),
+			}
+
+			select {
+			case chunks <- c:
+			case <-writeDone:
+				return
+			}
+		}
+
+		// Any data that was already read is delivered before the
+		// connection is closed by the deferred calls.
+		if err != nil {
+			return
+		}
+	}
+}
@@ -358,6 +358,13 @@ func (h *HarnessTest) Stop() {
 		h.Shutdown(node)
 	}
 
+	// Remove all node snapshots.
+	for _, snapshot := range h.manager.snapshots {
+		err := snapshot.Cleanup()
+		require.NoErrorf(h, err, "failed to remove snapshot of %s",
+			snapshot.Name)
+	}
+
 	close(h.lndErrorChan)
 
 	// Stop the fee service.
@@ -1739,6 +1746,57 @@ func (h *HarnessTest) RestartNodeAndRestoreDB(hn *node.HarnessNode) {
 	h.WaitForBlockchainSync(hn)
 }
 
+// SnapshotNode takes a snapshot of the complete state of the given node. The
+// node is stopped while the snapshot is taken and restarted afterwards. The
+// snapshot stays available for all following test cases and can be restored
+// into new nodes using NewNodeFromSnapshot, to avoid repeating an expensive
+// setup such as syncing the graph or opening channels.
+func (h *HarnessTest) SnapshotNode(hn *node.HarnessNode) *node.Snapshot {
+	restart := h.SuspendNode(hn)
+
+	snapshot, err := hn.Snapshot()
+	require.NoErrorf(h, err, "%s: failed to take snapshot", hn.Name())
+
+	h.manager.Lock()
+	h.manager.snapshots = append(h.manager.snapshots, snapshot)
+	h.manager.Unlock()
+
+	err = restart()
+	require.NoErrorf(h, err, "%s: failed to restart", hn.Name())
+
+	return snapshot
+}
+
+// NewNodeFromSnapshot creates a new node with the given name whose state is
+// restored from the snapshot. The new node has the same identity, wallet and
+// channels as the node the snapshot was taken of, but listens on new ports.
+//
+// NOTE: the node the snapshot was taken of must not be running at the same
+// time, otherwise the two nodes compete for the same channels.
+func (h *HarnessTest) NewNodeFromSnapshot(name string,
+	snapshot *node.Snapshot) *node.HarnessNode {
+
+	hn, err := h.manager.newNode(
+		h.T, name, snapshot.ExtraArgs, snapshot.Password, false,
+	)
+	require.NoErrorf(h, err, "unable to create new node for %s", name)
+
+	err = hn.RestoreSnapshot(snapshot)
+	require.NoErrorf(h, err, "%s: failed to restore snapshot", name)
+
+	err = hn.Start(h.runCtx)
+	require.NoError(h, err, "failed to start node %s", name)
+
+	err = h.manager.unlockNode(hn)
+	require.NoErrorf(h, err, "failed to unlock node %s", name)
+
+	// Give the node some time to catch up with the chain before we
+	// continue with the tests.
+	h.WaitForBlockchainSync(hn)
+
+	return hn
+}
+
 // MineBlocks mines blocks and asserts all active nodes have synced to the
 // chain.
 //
@@ -2030,6 +2088,40 @@ func (h *HarnessTest) ReceiveInvoiceUpdate(
 	return nil
 }
 
+// ReceiveWalletBalanceUpdate waits until a message is received on the
+// subscribe wallet balance stream or the timeout is reached.
+func (h *HarnessTest) ReceiveWalletBalanceUpdate(
+	stream rpc.WalletBalanceClient) *lnrpc.WalletBalanceUpdate {
+
+	chanMsg := make(chan *lnrpc.WalletBalanceUpdate)
+	errChan := make(chan error)
+	go func() {
+		// Consume one message. This will block until the message is
+		// received.
+		resp, err := stream.Recv()
+		if err != nil {
+			errChan <- err
+			return
+		}
+		chanMsg <- resp
+	}()
+
+	select {
+	case <-time.After(DefaultTimeout):
+		require.Fail(h, "timeout", "timeout receiving wallet balance "+
+			"update")
+
+	case err := <-errChan:
+		require.Failf(h, "err from stream",
+			"received err from stream: %v", err)
+
+	case updateMsg := <-chanMsg:
+		return updateMsg
+	}
+
+	return nil
+}
+
 // CalculateTxFee retrieves parent transactions and reconstructs the fee paid.
 func (h *HarnessTest) CalculateTxFee(tx *wire.MsgTx) btcutil.Amount {
 	var balance btcutil.Amount
@@ -0,0 +1,88 @@
+package lntest
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest/chaos"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/stretchr/testify/require"
+)
+
+// newChaosProxy creates a chaos proxy to the given target address that is
+// stopped once the current test finishes.
+func (h *HarnessTest) newChaosProxy(target string) *chaos.Proxy {
+	proxy, err := chaos.NewProxy(target)
+	require.NoError(h, err, "unable to create chaos proxy")
+
+	h.Cleanup(func() {
+		require.NoError(h, proxy.Stop(), "unable to stop chaos proxy")
+	})
+
+	return proxy
+}
+
+// ConnectNodesWithProxy creates a persistent connection from node a to node b
+// that is routed through a chaos proxy, and asserts the connection succeeded.
+// The returned proxy can be used to inject latency, message drops and
+// disconnects on the link between the two nodes. Since the connection is
+// persistent, node a reconnects through the proxy once it's disconnected.
+//
+// NOTE: the two nodes must not be connected yet. Node b must also not have a
+// persistent connection to node a, otherwise it may reconnect bypassing the
+// proxy.
+func (h *HarnessTest) ConnectNodesWithProxy(a,
+	b *node.HarnessNode) *chaos.Proxy {
+
+	proxy := h.newChaosProxy(b.Cfg.P2PAddr())
+
+	req := &lnrpc.ConnectPeerRequest{
+		Addr: &lnrpc.LightningAddress{
+			Pubkey: b.PubKeyStr,
+			Host:   proxy.Addr(),
+		},
+		Perm: true,
+	}
+	a.RPC.ConnectPeer(req)
+	h.AssertPeerConnected(a, b)
+
+	return proxy
+}
+
+// NewNodeWithChainProxy creates a new node whose connection to the chain
+// backend is routed through a chaos proxy. The returned proxy can be used to
+// simulate chain backend outages and latency for this node only.
+//
+// NOTE: only the RPC connection is proxied. For the bitcoind backend, blocks
+// and transactions are still received through ZMQ unless polling is used.
+// The neutrino backend is not supported.
+func (h *HarnessTest) NewNodeWithChainProxy(name string,
+	extraArgs []string) (*node.HarnessNode, *chaos.Proxy) {
+
+	backend := h.manager.chainBackend
+
+	_, _, host, err := backend.Credentials()
+	require.NoError(h, err, "unable to get chain backend credentials")
+
+	var hostArg string
+	switch backend.Name() {
+	case "btcd":
+		hostArg = "--btcd.rpchost=%v"
+
+	case "bitcoind":
+		hostArg = "--bitcoind.rpchost=%v"
+
+	default:
+		require.Failf(h, "unsupported backend", "chain proxy not "+
+			"supported for backend %v", backend.Name())
+	}
+
+	proxy := h.newChaosProxy(host)
+
+	// The backend arguments are passed before the extra arguments, so
+	// the proxy address takes precedence.
+	args := append([]string{}, extraArgs...)
+	args = append(args, fmt.Sprintf(hostArg, proxy.Addr()))
+
+	return h.NewNode(name, args), proxy
+}
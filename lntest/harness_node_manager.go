@@ -47,6 +47,10 @@ type nodeManager struct {
 	// node's unique ID.
 	nodeCounter uint32
 
+	// snapshots are all node snapshots taken during the run. They are
+	// kept across test cases and removed once the harness is stopped.
+	snapshots []*node.Snapshot
+
 	// feeServiceURL is the url of the fee service.
 	feeServiceURL string
 }
@@ -0,0 +1,371 @@
+package lntest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/stretchr/testify/require"
+)
+
+// TopologyNode describes a node that is part of a network topology.
+type TopologyNode struct {
+	// Name is the name the node is referenced by in the topology.
+	Name string
+
+	// Node is an already running node, such as one of the standby nodes,
+	// that is used instead of creating a new one. If set, Args is
+	// ignored.
+	Node *node.HarnessNode
+
+	// Args are the extra arguments the new node is started with.
+	Args []string
+}
+
+// TopologyChannel describes a channel that is part of a network topology.
+type TopologyChannel struct {
+	// Local is the name of the node that funds the channel.
+	Local string
+
+	// Remote is the name of the node that receives the channel.
+	Remote string
+
+	// Param is the open channel params. The balance of the remote node is
+	// set through the push amount.
+	Param OpenChannelParams
+
+	// Policy is the optional routing policy the local node applies to the
+	// channel once it is open.
+	Policy *lnrpc.RoutingPolicy
+}
+
+// TopologySpec is the declarative description of a network of nodes, their
+// connections and the channels between them. It's materialized in a single
+// step using HarnessTest.BuildTopology, for example:
+//
+//	spec := lntest.NewTopology().
+//		AddExistingNode("alice", ht.Alice).
+//		AddNode("carol").
+//		AddNode("dave", "--accept-amp").
+//		AddChannel("alice", "carol", lntest.OpenChannelParams{
+//			Amt: 1_000_000,
+//		}).
+//		AddChannel("carol", "dave", lntest.OpenChannelParams{
+//			Amt:     1_000_000,
+//			PushAmt: 500_000,
+//		}).
+//		WithPolicy(&lnrpc.RoutingPolicy{
+//			FeeBaseMsat:   1000,
+//			TimeLockDelta: 40,
+//		})
+//	topology := ht.BuildTopology(spec)
+type TopologySpec struct {
+	// Nodes are the nodes of the topology.
+	Nodes []TopologyNode
+
+	// Channels are the channels opened between the nodes. The nodes of a
+	// channel are always connected to each other.
+	Channels []TopologyChannel
+
+	// Connections are additional pairs of nodes that are connected
+	// without opening a channel between them.
+	Connections [][2]string
+}
+
+// NewTopology creates a new, empty topology spec.
+func NewTopology() *TopologySpec {
+	return &TopologySpec{}
+}
+
+// AddNode adds a new node that is started with the given extra arguments.
+func (s *TopologySpec) AddNode(name string, args ...string) *TopologySpec {
+	s.Nodes = append(s.Nodes, TopologyNode{
+		Name: name,
+		Args: args,
+	})
+
+	return s
+}
+
+// AddExistingNode adds an already running node to the topology.
+func (s *TopologySpec) AddExistingNode(name string,
+	hn *node.HarnessNode) *TopologySpec {
+
+	s.Nodes = append(s.Nodes, TopologyNode{
+		Name: name,
+		Node: hn,
+	})
+
+	return s
+}
+
+// AddChannel adds a channel funded by the local node to the remote node.
+func (s *TopologySpec) AddChannel(local, remote string,
+	p OpenChannelParams) *TopologySpec {
+
+	s.Channels = append(s.Channels, TopologyChannel{
+		Local:  local,
+		Remote: remote,
+		Param:  p,
+	})
+
+	return s
+}
+
+// WithPolicy sets the routing policy the local node applies to the channel
+// that was added last.
+func (s *TopologySpec) WithPolicy(policy *lnrpc.RoutingPolicy) *TopologySpec {
+	if len(s.Channels) > 0 {
+		s.Channels[len(s.Channels)-1].Policy = policy
+	}
+
+	return s
+}
+
+// Connect adds a connection between the two nodes without a channel.
+func (s *TopologySpec) Connect(a, b string) *TopologySpec {
+	s.Connections = append(s.Connections, [2]string{a, b})
+
+	return s
+}
+
+// validate checks that all nodes referenced in the spec are defined exactly
+// once.
+func (s *TopologySpec) validate() error {
+	names := make(map[string]struct{}, len(s.Nodes))
+	for _, n := range s.Nodes {
+		if n.Name == "" {
+			return fmt.Errorf("node without name")
+		}
+
+		if _, ok := names[n.Name]; ok {
+			return fmt.Errorf("duplicate node %s", n.Name)
+		}
+		names[n.Name] = struct{}{}
+	}
+
+	checkNode := func(name string) error {
+		if _, ok := names[name]; !ok {
+			return fmt.Errorf("unknown node %s", name)
+		}
+
+		return nil
+	}
+
+	for _, c := range s.Channels {
+		if err := checkNode(c.Local); err != nil {
+			return err
+		}
+		if err := checkNode(c.Remote); err != nil {
+			return err
+		}
+		if c.Local == c.Remote {
+			return fmt.Errorf("channel from %s to itself", c.Local)
+		}
+	}
+
+	for _, c := range s.Connections {
+		if err := checkNode(c[0]); err != nil {
+			return err
+		}
+		if err := checkNode(c[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Topology is a network topology that was materialized from a TopologySpec.
+type Topology struct {
+	ht *HarnessTest
+
+	// spec is the spec the topology was built from.
+	spec *TopologySpec
+
+	// nodes maps the node names to the running nodes.
+	nodes map[string]*node.HarnessNode
+
+	// created is the set of nodes that were created for the topology.
+	created map[string]struct{}
+
+	// chanPoints holds the channel points in the same order as the
+	// channels in the spec.
+	chanPoints []*lnrpc.ChannelPoint
+}
+
+// BuildTopology materializes the given spec. It creates and funds the new
+// nodes, connects all nodes, opens all channels in a single batch, and applies
+// the channel policies. Once it returns, every node knows about all public
+// channels and their policies.
+//
+// NOTE: new nodes that receive multiple channels are started with a matching
+// `--maxpendingchannels` value. Existing nodes must already allow the number
+// of pending channels they receive.
+func (h *HarnessTest) BuildTopology(spec *TopologySpec) *Topology {
+	require.NoError(h, spec.validate(), "invalid topology")
+
+	t := &Topology{
+		ht:      h,
+		spec:    spec,
+		nodes:   make(map[string]*node.HarnessNode, len(spec.Nodes)),
+		created: make(map[string]struct{}),
+	}
+
+	// Count the channels each node receives, so new nodes can be started
+	// with a sufficient number of allowed pending channels.
+	numIncoming := make(map[string]int)
+	for _, c := range spec.Channels {
+		numIncoming[c.Remote]++
+	}
+
+	for _, n := range spec.Nodes {
+		if n.Node != nil {
+			t.nodes[n.Name] = n.Node
+			continue
+		}
+
+		args := append([]string{}, n.Args...)
+		if numIncoming[n.Name] > 1 {
+			args = append(args, fmt.Sprintf(
+				"--maxpendingchannels=%d", numIncoming[n.Name],
+			))
+		}
+
+		t.nodes[n.Name] = h.NewNode(n.Name, args)
+		t.created[n.Name] = struct{}{}
+	}
+
+	// Connect the nodes of all channels and the extra connections.
+	for _, c := range spec.Channels {
+		h.EnsureConnected(t.nodes[c.Local], t.nodes[c.Remote])
+	}
+	for _, c := range spec.Connections {
+		h.EnsureConnected(t.nodes[c[0]], t.nodes[c[1]])
+	}
+
+	if len(spec.Channels) == 0 {
+		return t
+	}
+
+	// Fund the new nodes with one output for each channel they open.
+	// Existing nodes are expected to be funded already.
+	reqs := make([]*OpenChannelRequest, 0, len(spec.Channels))
+	for _, c := range spec.Channels {
+		if _, ok := t.created[c.Local]; ok {
+			h.FundCoins(
+				c.Param.Amt+btcutil.SatoshiPerBitcoin,
+				t.nodes[c.Local],
+			)
+		}
+
+		reqs = append(reqs, &OpenChannelRequest{
+			Local:  t.nodes[c.Local],
+			Remote: t.nodes[c.Remote],
+			Param:  c.Param,
+		})
+	}
+
+	t.chanPoints = h.OpenMultiChannelsAsync(reqs)
+
+	// Make sure every node has heard about every public channel.
+	for _, hn := range t.nodes {
+		for i, c := range spec.Channels {
+			if c.Param.Private {
+				continue
+			}
+
+			h.AssertTopologyChannelOpen(hn, t.chanPoints[i])
+		}
+	}
+
+	// Finally, apply the policies and wait for all nodes that know about
+	// the channel to receive the update.
+	for i, c := range spec.Channels {
+		if c.Policy == nil {
+			continue
+		}
+
+		local := t.nodes[c.Local]
+		local.RPC.UpdateChannelPolicy(&lnrpc.PolicyUpdateRequest{
+			BaseFeeMsat: c.Policy.FeeBaseMsat,
+			FeeRate: float64(c.Policy.FeeRateMilliMsat) /
+				float64(1_000_000),
+			TimeLockDelta: c.Policy.TimeLockDelta,
+			MaxHtlcMsat:   c.Policy.MaxHtlcMsat,
+			Scope: &lnrpc.PolicyUpdateRequest_ChanPoint{
+				ChanPoint: t.chanPoints[i],
+			},
+		})
+
+		observers := []*node.HarnessNode{local, t.nodes[c.Remote]}
+		if !c.Param.Private {
+			observers = t.Nodes()
+		}
+
+		for _, hn := range observers {
+			h.AssertChannelPolicyUpdate(
+				hn, local, c.Policy, t.chanPoints[i],
+				c.Param.Private,
+			)
+		}
+	}
+
+	return t
+}
+
+// Node returns the node with the given name.
+func (t *Topology) Node(name string) *node.HarnessNode {
+	hn, ok := t.nodes[name]
+	require.Truef(t.ht, ok, "unknown node %s", name)
+
+	return hn
+}
+
+// Nodes returns all nodes of the topology in the order of the spec.
+func (t *Topology) Nodes() []*node.HarnessNode {
+	nodes := make([]*node.HarnessNode, 0, len(t.spec.Nodes))
+	for _, n := range t.spec.Nodes {
+		nodes = append(nodes, t.nodes[n.Name])
+	}
+
+	return nodes
+}
+
+// ChanPoint returns the channel point of the first channel in the spec that
+// was opened from the local to the remote node.
+func (t *Topology) ChanPoint(local, remote string) *lnrpc.ChannelPoint {
+	for i, c := range t.spec.Channels {
+		if c.Local == local && c.Remote == remote {
+			return t.chanPoints[i]
+		}
+	}
+
+	require.Failf(t.ht, "unknown channel", "no channel from %s to %s",
+		local, remote)
+
+	return nil
+}
+
+// ChanPoints returns the channel points of all channels in the order of the
+// spec.
+func (t *Topology) ChanPoints() []*lnrpc.ChannelPoint {
+	return t.chanPoints
+}
+
+// Teardown cooperatively closes all channels of the topology and shuts down
+// the nodes that were created for it. Existing nodes are kept running.
+func (t *Topology) Teardown() {
+	for i, c := range t.spec.Channels {
+		t.ht.CloseChannel(t.nodes[c.Local], t.chanPoints[i])
+	}
+
+	for _, n := range t.spec.Nodes {
+		if _, ok := t.created[n.Name]; !ok {
+			continue
+		}
+
+		t.ht.Shutdown(t.nodes[n.Name])
+	}
+}
@@ -125,6 +125,13 @@ func (w *WalletController) ImportAccount(string, *hdkeychain.ExtendedKey,
 	return nil, nil, nil, nil
 }
 
+// CreateAccount currently returns a dummy value.
+func (w *WalletController) CreateAccount(string,
+	lnwallet.AddressType) (*waddrmgr.AccountProperties, error) {
+
+	return nil, nil
+}
+
 // ImportPublicKey currently returns a dummy value.
 func (w *WalletController) ImportPublicKey(*btcec.PublicKey,
 	waddrmgr.AddressType) error {
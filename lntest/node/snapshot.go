@@ -0,0 +1,177 @@
+package node
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Snapshot is a checkpoint of the complete state of a node, taken after an
+// expensive setup such as syncing the graph or opening channels. It can be
+// restored into new nodes, also in later test cases, instead of repeating the
+// setup.
+type Snapshot struct {
+	// Name is the name of the node the snapshot was taken of.
+	Name string
+
+	// Password is the wallet password of the node, if any.
+	Password []byte
+
+	// ExtraArgs are the extra arguments the node was running with.
+	ExtraArgs []string
+
+	// dir is the directory holding a copy of the node's base directory,
+	// without the logs.
+	dir string
+
+	// postgresDBName is the name of the postgres database holding a copy
+	// of the node's database, if the postgres backend is used.
+	postgresDBName string
+}
+
+// Snapshot creates a snapshot of the node's state.
+//
+// NOTE: the node must be stopped when calling this method, otherwise the
+// copied database files may be inconsistent.
+func (hn *HarnessNode) Snapshot() (*Snapshot, error) {
+	if hn.Cfg.SkipUnlock {
+		return nil, fmt.Errorf("snapshots of stateless nodes are not " +
+			"supported")
+	}
+
+	dir, err := os.MkdirTemp("", "lndtest-snapshot-"+hn.Cfg.Name+"-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create snapshot dir: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		Name:      hn.Cfg.Name,
+		Password:  hn.Cfg.Password,
+		ExtraArgs: hn.Cfg.ExtraArgs,
+		dir:       dir,
+	}
+
+	if err := copyNodeState(dir, hn.Cfg.BaseDir); err != nil {
+		_ = os.RemoveAll(dir)
+
+		return nil, fmt.Errorf("unable to copy node state: %w", err)
+	}
+
+	if hn.Cfg.postgresDBName != "" {
+		randBytes := make([]byte, 8)
+		if _, err := rand.Read(randBytes); err != nil {
+			_ = os.RemoveAll(dir)
+
+			return nil, err
+		}
+		dbName := "itest_snapshot_" + hex.EncodeToString(randBytes)
+
+		err := executePgQuery(
+			"CREATE DATABASE " + dbName + " WITH TEMPLATE " +
+				hn.Cfg.postgresDBName,
+		)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+
+			return nil, fmt.Errorf("unable to copy database: %w",
+				err)
+		}
+
+		snapshot.postgresDBName = dbName
+	}
+
+	return snapshot, nil
+}
+
+// RestoreSnapshot replaces the state of the node with the given snapshot. The
+// snapshot itself stays untouched, so it can be restored multiple times.
+//
+// NOTE: the node must not be running when calling this method.
+func (hn *HarnessNode) RestoreSnapshot(snapshot *Snapshot) error {
+	if (snapshot.postgresDBName != "") != (hn.Cfg.postgresDBName != "") {
+		return fmt.Errorf("snapshot of %s was taken with a different "+
+			"database backend", snapshot.Name)
+	}
+
+	if err := copyNodeState(hn.Cfg.BaseDir, snapshot.dir); err != nil {
+		return fmt.Errorf("unable to copy node state: %w", err)
+	}
+
+	if snapshot.postgresDBName == "" {
+		return nil
+	}
+
+	err := executePgQuery("DROP DATABASE " + hn.Cfg.postgresDBName)
+	if err != nil {
+		return err
+	}
+
+	return executePgQuery(
+		"CREATE DATABASE " + hn.Cfg.postgresDBName + " WITH TEMPLATE " +
+			snapshot.postgresDBName,
+	)
+}
+
+// Cleanup removes all data held by the snapshot. It can't be restored
+// afterwards.
+func (s *Snapshot) Cleanup() error {
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("unable to remove snapshot dir: %w", err)
+	}
+
+	if s.postgresDBName == "" {
+		return nil
+	}
+
+	return executePgQuery("DROP DATABASE " + s.postgresDBName)
+}
+
+// copyNodeState copies the state of a node from the source base directory to
+// the destination base directory, skipping the log files.
+func copyNodeState(dstDir, srcDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		// The logs stay with the node they were written by.
+		if entry.Name() == "logs" {
+			continue
+		}
+
+		srcPath := filepath.Join(srcDir, entry.Name())
+		dstPath := filepath.Join(dstDir, entry.Name())
+
+		if !entry.IsDir() {
+			if err := CopyFile(dstPath, srcPath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// Remove any existing state first, so no files that aren't
+		// part of the snapshot are left behind.
+		if err := os.RemoveAll(dstPath); err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if err := os.Mkdir(dstPath, info.Mode()); err != nil {
+			return err
+		}
+
+		if err := copyAll(dstPath, srcPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -28,6 +28,14 @@ const (
 )
 
 var (
+	// portRangeStart and portRangeEnd define the port range reserved for
+	// this process, if set through ReservePortRange. The next port to try
+	// is stored in nextRangePort. All of them are guarded by
+	// portFileMutex.
+	portRangeStart int
+	portRangeEnd   int
+	nextRangePort  int
+
 	// portFileMutex is a mutex that is used to make sure that the port file
 	// is not accessed by multiple goroutines of the same process at the
 	// same time. This is used in conjunction with the lock file to make
@@ -44,6 +52,12 @@ func NextAvailablePort() int {
 	portFileMutex.Lock()
 	defer portFileMutex.Unlock()
 
+	// If this process has its own port range, there's no need to
+	// coordinate with other processes through the lock file.
+	if portRangeEnd != 0 {
+		return nextPortInRange()
+	}
+
 	lockFile := filepath.Join(os.TempDir(), uniquePortFile+".lock")
 	timeout := time.After(time.Second)
 
@@ -139,6 +153,57 @@ func NextAvailablePort() int {
 	panic("no ports available for listening")
 }
 
+// ReservePortRange makes NextAvailablePort hand out ports from the given
+// range only, instead of coordinating with other processes through the port
+// file. This allows parallel test processes to each use their own port range
+// without contending for the lock file, as long as the ranges don't overlap.
+func ReservePortRange(start, end int) error {
+	if start <= 0 || end > 65535 || start >= end {
+		return fmt.Errorf("invalid port range [%d, %d)", start, end)
+	}
+
+	portFileMutex.Lock()
+	defer portFileMutex.Unlock()
+
+	portRangeStart = start
+	portRangeEnd = end
+	nextRangePort = start
+
+	return nil
+}
+
+// nextPortInRange returns the next port in the reserved port range that is
+// available for listening. Once the end of the range is reached, it starts
+// from the beginning again.
+//
+// NOTE: portFileMutex must be held when calling this function.
+func nextPortInRange() int {
+	for i := 0; i < portRangeEnd-portRangeStart; i++ {
+		port := nextRangePort
+
+		nextRangePort++
+		if nextRangePort == portRangeEnd {
+			nextRangePort = portRangeStart
+		}
+
+		addr := fmt.Sprintf(ListenerFormat, port)
+		l, err := net.Listen("tcp4", addr)
+		if err != nil {
+			continue
+		}
+
+		if err := l.Close(); err != nil {
+			continue
+		}
+
+		return port
+	}
+
+	// No ports available? Must be a mistake.
+	panic(fmt.Sprintf("no ports available for listening in range "+
+		"[%d, %d)", portRangeStart, portRangeEnd))
+}
+
 // GenerateSystemUniqueListenerAddresses is a function that returns two
 // listener addresses with unique ports per system and should be used to
 // overwrite rpctest's default generator which is prone to use colliding ports.
@@ -86,6 +86,68 @@ func (h *HarnessRPC) GetInfo() *lnrpc.GetInfoResponse {
 	return info
 }
 
+// ListDeprecations makes a RPC call to the node's ListDeprecations and
+// asserts.
+func (h *HarnessRPC) ListDeprecations(
+	req *lnrpc.ListDeprecationsRequest) *lnrpc.ListDeprecationsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.ListDeprecations(ctxt, req)
+	h.NoError(err, "ListDeprecations")
+
+	return resp
+}
+
+// GetChainInfo calls the GetChainInfo RPC and asserts there's no error.
+func (h *HarnessRPC) GetChainInfo() *lnrpc.GetChainInfoResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.GetChainInfo(ctxt, &lnrpc.GetChainInfoRequest{})
+	h.NoError(err, "GetChainInfo")
+
+	return resp
+}
+
+// GetLeadershipState calls the GetLeadershipState RPC and asserts there's no
+// error.
+func (h *HarnessRPC) GetLeadershipState() *lnrpc.GetLeadershipStateResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &lnrpc.GetLeadershipStateRequest{}
+	resp, err := h.LN.GetLeadershipState(ctxt, req)
+	h.NoError(err, "GetLeadershipState")
+
+	return resp
+}
+
+// CltvGuardStatus calls the CltvGuardStatus RPC and asserts there's no error.
+func (h *HarnessRPC) CltvGuardStatus() *lnrpc.CltvGuardStatusResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &lnrpc.CltvGuardStatusRequest{}
+	resp, err := h.LN.CltvGuardStatus(ctxt, req)
+	h.NoError(err, "CltvGuardStatus")
+
+	return resp
+}
+
+// SafeModeStatus calls the SafeModeStatus RPC and asserts there's no error.
+func (h *HarnessRPC) SafeModeStatus() *lnrpc.SafeModeStatusResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &lnrpc.SafeModeStatusRequest{}
+	resp, err := h.LN.SafeModeStatus(ctxt, req)
+	h.NoError(err, "SafeModeStatus")
+
+	return resp
+}
+
 // ConnectPeer makes a RPC call to ConnectPeer and asserts there's no error.
 func (h *HarnessRPC) ConnectPeer(
 	req *lnrpc.ConnectPeerRequest) *lnrpc.ConnectPeerResponse {
@@ -163,6 +225,21 @@ func (h *HarnessRPC) PendingChannels() *lnrpc.PendingChannelsResponse {
 	return resp
 }
 
+// BumpCoopCloseFee makes a RPC call to node's BumpCoopCloseFee and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) BumpCoopCloseFee(
+	req *lnrpc.BumpCoopCloseFeeRequest) *lnrpc.BumpCoopCloseFeeResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.BumpCoopCloseFee(ctxt, req)
+	h.NoError(err, "BumpCoopCloseFee")
+
+	return resp
+}
+
 // ClosedChannels makes a RPC call to node's ClosedChannels and asserts.
 func (h *HarnessRPC) ClosedChannels(
 	req *lnrpc.ClosedChannelsRequest) *lnrpc.ClosedChannelsResponse {
@@ -176,6 +253,22 @@ func (h *HarnessRPC) ClosedChannels(
 	return resp
 }
 
+// ChannelCloseHistory makes a RPC call to node's ChannelCloseHistory and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) ChannelCloseHistory(
+	req *lnrpc.ChannelCloseHistoryRequest) *lnrpc.ChannelCloseHistoryResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.ChannelCloseHistory(ctxt, req)
+	h.NoError(err, "ChannelCloseHistory")
+
+	return resp
+}
+
 // ListPayments lists the node's payments and asserts.
 func (h *HarnessRPC) ListPayments(
 	req *lnrpc.ListPaymentsRequest) *lnrpc.ListPaymentsResponse {
@@ -232,6 +325,18 @@ func (h *HarnessRPC) ChannelBalance() *lnrpc.ChannelBalanceResponse {
 	return resp
 }
 
+// LiquidityAlerts makes a RPC call to node's LiquidityAlerts and asserts.
+func (h *HarnessRPC) LiquidityAlerts() *lnrpc.LiquidityAlertsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &lnrpc.LiquidityAlertsRequest{}
+	resp, err := h.LN.LiquidityAlerts(ctxt, req)
+	h.NoError(err, "LiquidityAlerts")
+
+	return resp
+}
+
 type OpenChanClient lnrpc.Lightning_OpenChannelClient
 
 // OpenChannel makes a rpc call to LightningClient and returns the open channel
@@ -281,6 +386,68 @@ func (h *HarnessRPC) FundingStateStepAssertErr(m *lnrpc.FundingTransitionMsg) {
 	require.Error(h, err, "expected an error from FundingStateStep")
 }
 
+// RegisterFundingContributors makes a RPC call to the node's
+// RegisterFundingContributors and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) RegisterFundingContributors(
+	req *lnrpc.RegisterFundingContributorsRequest) *lnrpc.RegisterFundingContributorsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.RegisterFundingContributors(ctxt, req)
+	h.NoError(err, "RegisterFundingContributors")
+
+	return resp
+}
+
+// ContributeFunding makes a RPC call to the node's ContributeFunding and
+// asserts.
+func (h *HarnessRPC) ContributeFunding(
+	req *lnrpc.ContributeFundingRequest) *lnrpc.ContributeFundingResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.ContributeFunding(ctxt, req)
+	h.NoError(err, "ContributeFunding")
+
+	return resp
+}
+
+// SignFundingContribution makes a RPC call to the node's
+// SignFundingContribution and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) SignFundingContribution(
+	req *lnrpc.SignFundingContributionRequest) *lnrpc.SignFundingContributionResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.SignFundingContribution(ctxt, req)
+	h.NoError(err, "SignFundingContribution")
+
+	return resp
+}
+
+// FundingContributionStatus makes a RPC call to the node's
+// FundingContributionStatus and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) FundingContributionStatus(
+	req *lnrpc.FundingContributionStatusRequest) *lnrpc.FundingContributionStatusResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.FundingContributionStatus(ctxt, req)
+	h.NoError(err, "FundingContributionStatus")
+
+	return resp
+}
+
 // AddInvoice adds a invoice for the given node and asserts.
 func (h *HarnessRPC) AddInvoice(req *lnrpc.Invoice) *lnrpc.AddInvoiceResponse {
 	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
@@ -403,6 +570,22 @@ func (h *HarnessRPC) GetTransactions(
 	return resp
 }
 
+// AddressReuseReport makes a RPC call to node's AddressReuseReport and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) AddressReuseReport(
+	req *lnrpc.AddressReuseReportRequest) *lnrpc.AddressReuseReportResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.AddressReuseReport(ctxt, req)
+	h.NoError(err, "AddressReuseReport")
+
+	return resp
+}
+
 // SignMessage makes a RPC call to node's SignMessage and asserts.
 func (h *HarnessRPC) SignMessage(msg []byte) *lnrpc.SignMessageResponse {
 	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
@@ -474,6 +657,36 @@ func (h *HarnessRPC) BatchOpenChannelAssertErr(
 	return err
 }
 
+// DiagnoseConnection makes a RPC call to the node's DiagnoseConnection and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) DiagnoseConnection(
+	req *lnrpc.DiagnoseConnectionRequest) *lnrpc.DiagnoseConnectionResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.DiagnoseConnection(ctxt, req)
+	h.NoError(err, "DiagnoseConnection")
+
+	return resp
+}
+
+// CheckOpenChannel makes a RPC call to the node's CheckOpenChannel and
+// asserts.
+func (h *HarnessRPC) CheckOpenChannel(
+	req *lnrpc.OpenChannelRequest) *lnrpc.CheckOpenChannelResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.CheckOpenChannel(ctxt, req)
+	h.NoError(err, "CheckOpenChannel")
+
+	return resp
+}
+
 // QueryRoutes makes a RPC call to QueryRoutes and asserts.
 func (h *HarnessRPC) QueryRoutes(
 	req *lnrpc.QueryRoutesRequest) *lnrpc.QueryRoutesResponse {
@@ -549,6 +762,21 @@ func (h *HarnessRPC) SubscribeInvoices(
 	return client
 }
 
+type WalletBalanceClient lnrpc.Lightning_SubscribeWalletBalanceClient
+
+// SubscribeWalletBalance creates a subscription client for wallet balance
+// updates and asserts its creation.
+func (h *HarnessRPC) SubscribeWalletBalance(
+	req *lnrpc.SubscribeWalletBalanceRequest) WalletBalanceClient {
+
+	// Use runCtx here instead of timeout context to keep the stream client
+	// alive.
+	client, err := h.LN.SubscribeWalletBalance(h.runCtx, req)
+	h.NoError(err, "SubscribeWalletBalance")
+
+	return client
+}
+
 type BackupSubscriber lnrpc.Lightning_SubscribeChannelBackupsClient
 
 // SubscribeChannelBackups creates a client to listen to channel backup stream.
@@ -618,6 +846,59 @@ func (h *HarnessRPC) ForwardingHistory(
 	return resp
 }
 
+// ExportLedger makes a RPC call to the node's ExportLedger and asserts.
+func (h *HarnessRPC) ExportLedger(
+	req *lnrpc.ExportLedgerRequest) *lnrpc.ExportLedgerResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.ExportLedger(ctxt, req)
+	h.NoError(err, "ExportLedger")
+
+	return resp
+}
+
+// HoldFeeStatus makes a RPC call to the node's HoldFeeStatus and asserts.
+func (h *HarnessRPC) HoldFeeStatus() *lnrpc.HoldFeeStatusResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &lnrpc.HoldFeeStatusRequest{}
+	resp, err := h.LN.HoldFeeStatus(ctxt, req)
+	h.NoError(err, "HoldFeeStatus")
+
+	return resp
+}
+
+// SimulateHoldFee makes a RPC call to the node's SimulateHoldFee and asserts.
+func (h *HarnessRPC) SimulateHoldFee(
+	req *lnrpc.SimulateHoldFeeRequest) *lnrpc.SimulateHoldFeeResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.SimulateHoldFee(ctxt, req)
+	h.NoError(err, "SimulateHoldFee")
+
+	return resp
+}
+
+// ListJammingIncidents makes a RPC call to the node's ListJammingIncidents and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) ListJammingIncidents() *lnrpc.ListJammingIncidentsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &lnrpc.ListJammingIncidentsRequest{}
+	resp, err := h.LN.ListJammingIncidents(ctxt, req)
+	h.NoError(err, "ListJammingIncidents")
+
+	return resp
+}
+
 type MiddlewareClient lnrpc.Lightning_RegisterRPCMiddlewareClient
 
 // RegisterRPCMiddleware makes a RPC call to the node's RegisterRPCMiddleware
@@ -685,6 +966,41 @@ func (h *HarnessRPC) SendCustomMessage(
 	return resp
 }
 
+type OnionMessageClient lnrpc.Lightning_SubscribeOnionMessagesClient
+
+// SubscribeOnionMessages creates a subscription client for the onion messages
+// delivered to the node.
+func (h *HarnessRPC) SubscribeOnionMessages() (OnionMessageClient,
+	context.CancelFunc) {
+
+	ctxt, cancel := context.WithCancel(h.runCtx)
+
+	req := &lnrpc.SubscribeOnionMessagesRequest{}
+
+	// SubscribeOnionMessages needs to have the context alive for the
+	// entire test case as the returned client will be used for send and
+	// receive events stream. Thus we use runCtx here instead of a timeout
+	// context.
+	stream, err := h.LN.SubscribeOnionMessages(ctxt, req)
+	h.NoError(err, "SubscribeOnionMessages")
+
+	return stream, cancel
+}
+
+// SendOnionMessage makes a RPC call to the node's SendOnionMessage and
+// returns the response.
+func (h *HarnessRPC) SendOnionMessage(
+	req *lnrpc.SendOnionMessageRequest) *lnrpc.SendOnionMessageResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.SendOnionMessage(ctxt, req)
+	h.NoError(err, "SendOnionMessage")
+
+	return resp
+}
+
 // GetChanInfo makes a RPC call to the node's GetChanInfo and returns the
 // response.
 func (h *HarnessRPC) GetChanInfo(
@@ -699,6 +1015,32 @@ func (h *HarnessRPC) GetChanInfo(
 	return resp
 }
 
+// GetNodeMetadata makes a RPC call to the node's GetNodeMetadata and asserts.
+func (h *HarnessRPC) GetNodeMetadata(pubKey string) *lnrpc.NodeMetadata {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &lnrpc.NodeMetadataRequest{PubKey: pubKey}
+	resp, err := h.LN.GetNodeMetadata(ctxt, req)
+	h.NoError(err, "GetNodeMetadata")
+
+	return resp
+}
+
+// GetNodeCapabilities makes a RPC call to the node's GetNodeCapabilities and
+// asserts.
+func (h *HarnessRPC) GetNodeCapabilities(
+	req *lnrpc.NodeCapabilitiesRequest) *lnrpc.NodeCapabilities {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.GetNodeCapabilities(ctxt, req)
+	h.NoError(err, "GetNodeCapabilities")
+
+	return resp
+}
+
 // LookupHtlcResolution makes a RPC call to the node's LookupHtlcResolution and
 // returns the response.
 //
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/lightningnetwork/lnd/lnrpc/lsprpc"
+	"github.com/stretchr/testify/require"
+)
+
+// =====================
+// LSPClient related RPCs.
+// =====================
+
+// LSPListProtocols makes a ListProtocols RPC call to the lsprpc client and
+// asserts.
+func (h *HarnessRPC) LSPListProtocols(
+	req *lsprpc.ListProtocolsRequest) *lsprpc.ListProtocolsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LSP.ListProtocols(ctxt, req)
+	h.NoError(err, "ListProtocols")
+
+	return resp
+}
+
+// LSPS2GetInfo makes a LSPS2GetInfo RPC call to the lsprpc client and
+// asserts.
+func (h *HarnessRPC) LSPS2GetInfo(
+	req *lsprpc.LSPS2GetInfoRequest) *lsprpc.LSPS2GetInfoResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LSP.LSPS2GetInfo(ctxt, req)
+	h.NoError(err, "LSPS2GetInfo")
+
+	return resp
+}
+
+// LSPS2Buy makes a LSPS2Buy RPC call to the lsprpc client and asserts.
+func (h *HarnessRPC) LSPS2Buy(req *lsprpc.LSPS2BuyRequest) *lsprpc.JITChannel {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LSP.LSPS2Buy(ctxt, req)
+	h.NoError(err, "LSPS2Buy")
+
+	return resp
+}
+
+// LSPS2BuyAssertErr makes a LSPS2Buy RPC call to the lsprpc client and
+// asserts an error is returned.
+func (h *HarnessRPC) LSPS2BuyAssertErr(req *lsprpc.LSPS2BuyRequest) error {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	_, err := h.LSP.LSPS2Buy(ctxt, req)
+	require.Error(h, err, "expect an error from LSPS2Buy")
+
+	return err
+}
+
+// LSPListJITChannels makes a ListJITChannels RPC call to the lsprpc client
+// and asserts.
+func (h *HarnessRPC) LSPListJITChannels() *lsprpc.ListJITChannelsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LSP.ListJITChannels(
+		ctxt, &lsprpc.ListJITChannelsRequest{},
+	)
+	h.NoError(err, "ListJITChannels")
+
+	return resp
+}
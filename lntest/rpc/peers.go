@@ -37,3 +37,83 @@ func (h *HarnessRPC) UpdateNodeAnnouncementErr(req AnnReq) {
 	_, err := h.Peer.UpdateNodeAnnouncement(ctxt, req)
 	require.Error(h, err, "expect an error from update announcement")
 }
+
+// UpdateNodeMetadata makes an UpdateNodeMetadata RPC call to the peersrpc
+// client and asserts.
+func (h *HarnessRPC) UpdateNodeMetadata(
+	req *peersrpc.NodeMetadataUpdateRequest) AnnResp {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Peer.UpdateNodeMetadata(ctxt, req)
+	h.NoError(err, "UpdateNodeMetadata")
+
+	return resp
+}
+
+// ListRejectedPeers makes a ListRejectedPeers RPC call to the peersrpc client
+// and asserts.
+func (h *HarnessRPC) ListRejectedPeers() *peersrpc.ListRejectedPeersResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Peer.ListRejectedPeers(
+		ctxt, &peersrpc.ListRejectedPeersRequest{},
+	)
+	h.NoError(err, "ListRejectedPeers")
+
+	return resp
+}
+
+// RegisterInitRecord makes a RegisterInitRecord RPC call to the peersrpc
+// client and asserts.
+func (h *HarnessRPC) RegisterInitRecord(
+	req *peersrpc.RegisterInitRecordRequest) {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	_, err := h.Peer.RegisterInitRecord(ctxt, req)
+	h.NoError(err, "RegisterInitRecord")
+}
+
+// UnregisterInitRecord makes an UnregisterInitRecord RPC call to the peersrpc
+// client and asserts.
+func (h *HarnessRPC) UnregisterInitRecord(
+	req *peersrpc.UnregisterInitRecordRequest) {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	_, err := h.Peer.UnregisterInitRecord(ctxt, req)
+	h.NoError(err, "UnregisterInitRecord")
+}
+
+// ListInitRecords makes a ListInitRecords RPC call to the peersrpc client and
+// asserts.
+func (h *HarnessRPC) ListInitRecords() *peersrpc.InitRecordsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Peer.ListInitRecords(
+		ctxt, &peersrpc.ListInitRecordsRequest{},
+	)
+	h.NoError(err, "ListInitRecords")
+
+	return resp
+}
+
+// PeerInitRecords makes a PeerInitRecords RPC call to the peersrpc client and
+// asserts.
+func (h *HarnessRPC) PeerInitRecords(
+	req *peersrpc.PeerInitRecordsRequest) *peersrpc.InitRecordsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Peer.PeerInitRecords(ctxt, req)
+	h.NoError(err, "PeerInitRecords")
+
+	return resp
+}
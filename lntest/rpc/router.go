@@ -28,6 +28,169 @@ func (h *HarnessRPC) UpdateChanStatus(
 	return resp
 }
 
+// QueryRouteFees makes a RPC call to the node's RouterClient and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) QueryRouteFees(
+	req *routerrpc.QueryRouteFeesRequest) *routerrpc.QueryRouteFeesResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.QueryRouteFees(ctxt, req)
+	h.NoError(err, "QueryRouteFees")
+
+	return resp
+}
+
+// AddKnownRouteHints makes a RPC call to the node's RouterClient and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) AddKnownRouteHints(
+	req *routerrpc.AddKnownRouteHintsRequest) *routerrpc.AddKnownRouteHintsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.AddKnownRouteHints(ctxt, req)
+	h.NoError(err, "AddKnownRouteHints")
+
+	return resp
+}
+
+// RemoveKnownRouteHints makes a RPC call to the node's RouterClient and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) RemoveKnownRouteHints(
+	req *routerrpc.RemoveKnownRouteHintsRequest) *routerrpc.RemoveKnownRouteHintsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.RemoveKnownRouteHints(ctxt, req)
+	h.NoError(err, "RemoveKnownRouteHints")
+
+	return resp
+}
+
+// ListKnownRouteHints makes a RPC call to the node's RouterClient and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) ListKnownRouteHints() *routerrpc.ListKnownRouteHintsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.ListKnownRouteHints(
+		ctxt, &routerrpc.ListKnownRouteHintsRequest{},
+	)
+	h.NoError(err, "ListKnownRouteHints")
+
+	return resp
+}
+
+// RegisterJITChannel makes a RPC call to the node's RouterClient and asserts.
+func (h *HarnessRPC) RegisterJITChannel(
+	req *routerrpc.RegisterJITChannelRequest) *routerrpc.JITChannel {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.RegisterJITChannel(ctxt, req)
+	h.NoError(err, "RegisterJITChannel")
+
+	return resp
+}
+
+// CancelJITChannel makes a RPC call to the node's RouterClient and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) CancelJITChannel(
+	req *routerrpc.CancelJITChannelRequest) *routerrpc.CancelJITChannelResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.CancelJITChannel(ctxt, req)
+	h.NoError(err, "CancelJITChannel")
+
+	return resp
+}
+
+// ListJITChannels makes a RPC call to the node's RouterClient and asserts.
+func (h *HarnessRPC) ListJITChannels() *routerrpc.ListJITChannelsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.ListJITChannels(
+		ctxt, &routerrpc.ListJITChannelsRequest{},
+	)
+	h.NoError(err, "ListJITChannels")
+
+	return resp
+}
+
+// QueryNetworkResults makes a RPC call to the node's RouterClient and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) QueryNetworkResults(
+	req *routerrpc.QueryNetworkResultsRequest) *routerrpc.QueryNetworkResultsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.QueryNetworkResults(ctxt, req)
+	h.NoError(err, "QueryNetworkResults")
+
+	return resp
+}
+
+// PruneNetworkResults makes a RPC call to the node's RouterClient and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) PruneNetworkResults() *routerrpc.PruneNetworkResultsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.PruneNetworkResults(
+		ctxt, &routerrpc.PruneNetworkResultsRequest{},
+	)
+	h.NoError(err, "PruneNetworkResults")
+
+	return resp
+}
+
+// FreezeChannel makes a RPC call to the node's RouterClient and asserts.
+func (h *HarnessRPC) FreezeChannel(
+	req *routerrpc.FreezeChannelRequest) *routerrpc.FreezeChannelResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.FreezeChannel(ctxt, req)
+	h.NoError(err, "FreezeChannel")
+
+	return resp
+}
+
+// UnfreezeChannel makes a RPC call to the node's RouterClient and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) UnfreezeChannel(
+	req *routerrpc.UnfreezeChannelRequest) *routerrpc.UnfreezeChannelResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.UnfreezeChannel(ctxt, req)
+	h.NoError(err, "UnfreezeChannel")
+
+	return resp
+}
+
 type PaymentClient routerrpc.Router_SendPaymentV2Client
 
 // SendPayment sends a payment using the given node and payment request. It
@@ -44,6 +207,22 @@ func (h *HarnessRPC) SendPayment(
 	return stream
 }
 
+// SendAsyncPayment makes a RPC call to the node's SendAsyncPayment and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) SendAsyncPayment(
+	req *routerrpc.SendAsyncPaymentRequest) *routerrpc.SendAsyncPaymentResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.Router.SendAsyncPayment(ctxt, req)
+	h.NoError(err, "SendAsyncPayment")
+
+	return resp
+}
+
 type HtlcEventsClient routerrpc.Router_SubscribeHtlcEventsClient
 
 // SubscribeHtlcEvents makes a subscription to the HTLC events and returns a
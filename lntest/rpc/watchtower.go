@@ -96,3 +96,32 @@ func (h *HarnessRPC) WatchtowerStats() *wtclientrpc.StatsResponse {
 
 	return resp
 }
+
+// MigrateTower makes a RPC call to the WatchtowerClient of the given node and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) MigrateTower(
+	req *wtclientrpc.MigrateTowerRequest) *wtclientrpc.MigrateTowerResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.WatchtowerClient.MigrateTower(ctxt, req)
+	h.NoError(err, "MigrateTower")
+
+	return resp
+}
+
+// MigrationStatus makes a RPC call to the WatchtowerClient of the given node
+// and asserts.
+func (h *HarnessRPC) MigrationStatus() *wtclientrpc.MigrationStatusResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &wtclientrpc.MigrationStatusRequest{}
+	resp, err := h.WatchtowerClient.MigrationStatus(ctxt, req)
+	h.NoError(err, "MigrationStatus")
+
+	return resp
+}
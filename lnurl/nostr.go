@@ -0,0 +1,213 @@
+package lnurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// KindZapRequest is the kind of a Nostr zap request event, as defined
+	// in NIP-57.
+	KindZapRequest = 9734
+
+	// KindZapReceipt is the kind of a Nostr zap receipt event, as defined
+	// in NIP-57.
+	KindZapReceipt = 9735
+)
+
+var (
+	// ErrInvalidEventID is returned when the ID of an event doesn't match
+	// its content.
+	ErrInvalidEventID = errors.New("invalid event id")
+
+	// ErrInvalidEventSig is returned when the signature of an event is
+	// invalid.
+	ErrInvalidEventSig = errors.New("invalid event signature")
+)
+
+// Event is a Nostr event, as defined in NIP-01.
+type Event struct {
+	ID        string     `json:"id"`
+	PubKey    string     `json:"pubkey"`
+	CreatedAt int64      `json:"created_at"`
+	Kind      int        `json:"kind"`
+	Tags      [][]string `json:"tags"`
+	Content   string     `json:"content"`
+	Sig       string     `json:"sig"`
+}
+
+// SignFunc creates a BIP-340 signature over the SHA-256 hash of the given
+// serialized event. This allows events to be signed by a key that isn't
+// available locally, for example when a remote signer is used.
+type SignFunc func(serialized []byte) (*schnorr.Signature, error)
+
+// serialize returns the serialization of the event that its ID commits to.
+func (e *Event) serialize() ([]byte, error) {
+	tags := e.Tags
+	if tags == nil {
+		tags = [][]string{}
+	}
+
+	return json.Marshal([]interface{}{
+		0, e.PubKey, e.CreatedAt, e.Kind, tags, e.Content,
+	})
+}
+
+// hash returns the hash of the serialized event that is its ID.
+func (e *Event) hash() ([sha256.Size]byte, error) {
+	serialized, err := e.serialize()
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(serialized), nil
+}
+
+// Sign sets the public key, ID and signature of the event using the given
+// private key.
+func (e *Event) Sign(privKey *btcec.PrivateKey) error {
+	return e.SignWith(privKey.PubKey(), func(serialized []byte) (
+		*schnorr.Signature, error) {
+
+		hash := sha256.Sum256(serialized)
+		return schnorr.Sign(privKey, hash[:])
+	})
+}
+
+// SignWith sets the public key, ID and signature of the event using the given
+// sign function, which must sign with the private key of the given public key.
+func (e *Event) SignWith(pubKey *btcec.PublicKey, sign SignFunc) error {
+	e.PubKey = hex.EncodeToString(schnorr.SerializePubKey(pubKey))
+
+	serialized, err := e.serialize()
+	if err != nil {
+		return err
+	}
+
+	sig, err := sign(serialized)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(serialized)
+	e.ID = hex.EncodeToString(hash[:])
+	e.Sig = hex.EncodeToString(sig.Serialize())
+
+	return nil
+}
+
+// Verify checks the ID and signature of the event.
+func (e *Event) Verify() error {
+	hash, err := e.hash()
+	if err != nil {
+		return err
+	}
+	if e.ID != hex.EncodeToString(hash[:]) {
+		return ErrInvalidEventID
+	}
+
+	pubKeyBytes, err := hex.DecodeString(e.PubKey)
+	if err != nil {
+		return fmt.Errorf("invalid event pubkey: %w", err)
+	}
+	pubKey, err := schnorr.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return fmt.Errorf("invalid event pubkey: %w", err)
+	}
+
+	sigBytes, err := hex.DecodeString(e.Sig)
+	if err != nil {
+		return ErrInvalidEventSig
+	}
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return ErrInvalidEventSig
+	}
+	if !sig.Verify(hash[:], pubKey) {
+		return ErrInvalidEventSig
+	}
+
+	return nil
+}
+
+// Tag returns the values of the first tag with the given name, or nil if the
+// event has no such tag.
+func (e *Event) Tag(name string) []string {
+	for _, tag := range e.Tags {
+		if len(tag) > 0 && tag[0] == name {
+			return tag[1:]
+		}
+	}
+
+	return nil
+}
+
+// PublishEvent publishes the event to the relay with the given websocket URL
+// and waits for the relay to accept it.
+func PublishEvent(ctx context.Context, relayURL string, event *Event,
+	timeout time.Duration) error {
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, relayURL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+
+	err = conn.WriteJSON([]interface{}{"EVENT", event})
+	if err != nil {
+		return err
+	}
+
+	// The relay answers with ["OK", <event id>, <accepted>, <message>],
+	// other messages are skipped.
+	for {
+		var msg []json.RawMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+
+		var msgType, eventID string
+		if len(msg) < 4 ||
+			json.Unmarshal(msg[0], &msgType) != nil ||
+			msgType != "OK" ||
+			json.Unmarshal(msg[1], &eventID) != nil ||
+			eventID != event.ID {
+
+			continue
+		}
+
+		var (
+			accepted bool
+			message  string
+		)
+		if err := json.Unmarshal(msg[2], &accepted); err != nil {
+			return err
+		}
+		if !accepted {
+			_ = json.Unmarshal(msg[3], &message)
+			return fmt.Errorf("relay rejected event: %v", message)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,302 @@
+package lnurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// PayRequestPattern is the path pattern of the LNURL-pay endpoint
+	// that a Lightning Address resolves to, as defined in LUD-16.
+	PayRequestPattern = "/.well-known/lnurlp/{username}"
+
+	// CallbackPattern is the path pattern of the LNURL-pay callback that
+	// returns an invoice, as defined in LUD-06.
+	CallbackPattern = "/lnurlp/{username}/callback"
+
+	// UsernameParam is the name of the path parameter holding the
+	// username in both patterns.
+	UsernameParam = "username"
+
+	// payRequestTag is the tag that identifies an LNURL-pay response.
+	payRequestTag = "payRequest"
+)
+
+// User is a user that can be paid through LNURL-pay at its Lightning Address
+// <name>@<domain>.
+type User struct {
+	// Name is the username of the user.
+	Name string
+
+	// MinSendable is the smallest amount the user can be paid.
+	MinSendable lnwire.MilliSatoshi
+
+	// MaxSendable is the largest amount the user can be paid.
+	MaxSendable lnwire.MilliSatoshi
+
+	// Description is the description shown to the payer.
+	Description string
+}
+
+// Config contains the dependencies and the users of the LNURL server.
+type Config struct {
+	// Domain is the domain name the server is publicly reachable at.
+	Domain string
+
+	// Users are the users that can be paid, keyed by their username.
+	Users map[string]*User
+
+	// AddInvoice adds an invoice for the given amount that commits to
+	// the given description hash, and returns its payment hash and
+	// payment request.
+	AddInvoice func(ctx context.Context, amt lnwire.MilliSatoshi,
+		descHash [sha256.Size]byte) (lntypes.Hash, string, error)
+
+	// NostrPubKey returns the hex encoded public key zap receipts are
+	// signed with. Nostr zaps are disabled if it is nil.
+	NostrPubKey func() (string, error)
+
+	// AddZapRequest stores the raw zap request the invoice with the given
+	// payment hash was created for, so a zap receipt is published once
+	// the invoice is settled.
+	AddZapRequest func(hash lntypes.Hash, raw []byte) error
+
+	// CallbackRate is the number of callbacks served per second across
+	// all users. Every callback adds an invoice, so callbacks beyond the
+	// limit are rejected. Zero disables the limit.
+	CallbackRate rate.Limit
+
+	// CallbackBurst is the number of callbacks that can be served at once
+	// before CallbackRate applies.
+	CallbackBurst int
+}
+
+// Server serves LNURL-pay requests and Lightning Address callbacks of the
+// configured users with invoices added to the node's invoice registry.
+type Server struct {
+	cfg *Config
+
+	// callbackLimiter limits the rate at which callbacks add invoices.
+	callbackLimiter *rate.Limiter
+}
+
+// NewServer creates a new LNURL server from the given config.
+func NewServer(cfg *Config) *Server {
+	callbackLimiter := rate.NewLimiter(rate.Inf, 0)
+	if cfg.CallbackRate != 0 {
+		callbackLimiter = rate.NewLimiter(
+			cfg.CallbackRate, cfg.CallbackBurst,
+		)
+	}
+
+	return &Server{
+		cfg:             cfg,
+		callbackLimiter: callbackLimiter,
+	}
+}
+
+// payResponse is the response to an LNURL-pay request, as defined in LUD-06.
+type payResponse struct {
+	Callback    string `json:"callback"`
+	MaxSendable uint64 `json:"maxSendable"`
+	MinSendable uint64 `json:"minSendable"`
+	Metadata    string `json:"metadata"`
+	Tag         string `json:"tag"`
+
+	// AllowsNostr and NostrPubKey announce support for Nostr zaps, as
+	// defined in NIP-57.
+	AllowsNostr bool   `json:"allowsNostr,omitempty"`
+	NostrPubKey string `json:"nostrPubkey,omitempty"`
+}
+
+// callbackResponse is the response to an LNURL-pay callback, as defined in
+// LUD-06.
+type callbackResponse struct {
+	PR     string   `json:"pr"`
+	Routes []string `json:"routes"`
+}
+
+// errorResponse is the response to a failed LNURL request.
+type errorResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// HandlePayRequest answers the LNURL-pay request of the user with the given
+// username.
+func (s *Server) HandlePayRequest(w http.ResponseWriter, _ *http.Request,
+	username string) {
+
+	user, ok := s.cfg.Users[strings.ToLower(username)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown user")
+		return
+	}
+
+	resp := &payResponse{
+		Callback:    s.callbackURL(user),
+		MaxSendable: uint64(user.MaxSendable),
+		MinSendable: uint64(user.MinSendable),
+		Metadata:    s.metadata(user),
+		Tag:         payRequestTag,
+	}
+
+	if s.cfg.NostrPubKey != nil {
+		pubKey, err := s.cfg.NostrPubKey()
+		if err != nil {
+			log.Errorf("Unable to get nostr pubkey: %v", err)
+
+			writeError(
+				w, http.StatusServiceUnavailable, "not ready",
+			)
+
+			return
+		}
+
+		resp.AllowsNostr = true
+		resp.NostrPubKey = pubKey
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleCallback answers the LNURL-pay callback of the user with the given
+// username with a new invoice for the requested amount.
+func (s *Server) HandleCallback(w http.ResponseWriter, r *http.Request,
+	username string) {
+
+	user, ok := s.cfg.Users[strings.ToLower(username)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown user")
+		return
+	}
+
+	if !s.callbackLimiter.Allow() {
+		writeError(w, http.StatusTooManyRequests, "too many requests")
+		return
+	}
+
+	amt, err := strconv.ParseUint(r.URL.Query().Get("amount"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid amount")
+		return
+	}
+	if lnwire.MilliSatoshi(amt) < user.MinSendable ||
+		lnwire.MilliSatoshi(amt) > user.MaxSendable {
+
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("amount "+
+			"must be between %d and %d msat", user.MinSendable,
+			user.MaxSendable))
+
+		return
+	}
+
+	// A zap invoice commits to the zap request instead of the metadata.
+	// Servers that don't support zaps ignore the zap request.
+	descHash := sha256.Sum256([]byte(s.metadata(user)))
+	zapRequest := r.URL.Query().Get("nostr")
+	isZap := zapRequest != "" && s.cfg.NostrPubKey != nil
+	if isZap {
+		_, err := ParseZapRequest(zapRequest, lnwire.MilliSatoshi(amt))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		descHash = sha256.Sum256([]byte(zapRequest))
+	}
+
+	hash, payReq, err := s.cfg.AddInvoice(
+		r.Context(), lnwire.MilliSatoshi(amt), descHash,
+	)
+	if err != nil {
+		log.Errorf("Unable to add invoice for %v: %v",
+			s.address(user), err)
+
+		writeError(
+			w, http.StatusInternalServerError, "unable to "+
+				"create invoice",
+		)
+
+		return
+	}
+
+	if isZap {
+		err := s.cfg.AddZapRequest(hash, []byte(zapRequest))
+		if err != nil {
+			log.Errorf("Unable to store zap request of %v: %v",
+				hash, err)
+
+			writeError(
+				w, http.StatusInternalServerError, "unable "+
+					"to store zap request",
+			)
+
+			return
+		}
+	}
+
+	log.Debugf("Created invoice %v of %d msat for %v (zap=%v)", hash, amt,
+		s.address(user), isZap)
+
+	writeJSON(w, http.StatusOK, &callbackResponse{
+		PR:     payReq,
+		Routes: []string{},
+	})
+}
+
+// address returns the Lightning Address of the user.
+func (s *Server) address(user *User) string {
+	return user.Name + "@" + s.cfg.Domain
+}
+
+// callbackURL returns the URL of the user's LNURL-pay callback. Onion domains
+// are served over plain http, all others must use https.
+func (s *Server) callbackURL(user *User) string {
+	scheme := "https"
+	if strings.HasSuffix(s.cfg.Domain, ".onion") {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s/lnurlp/%s/callback", scheme, s.cfg.Domain,
+		user.Name)
+}
+
+// metadata returns the LNURL-pay metadata of the user. The invoices of the
+// user commit to the hash of the metadata, so it must not change between the
+// pay request and the callback.
+func (s *Server) metadata(user *User) string {
+	metadata, _ := json.Marshal([][2]string{
+		{"text/plain", user.Description},
+		{"text/identifier", s.address(user)},
+	})
+
+	return string(metadata)
+}
+
+// writeJSON writes the given response as JSON with the given status code.
+func writeJSON(w http.ResponseWriter, status int, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Errorf("Unable to write response: %v", err)
+	}
+}
+
+// writeError writes an LNURL error response with the given reason.
+func writeError(w http.ResponseWriter, status int, reason string) {
+	writeJSON(w, status, &errorResponse{
+		Status: "ERROR",
+		Reason: reason,
+	})
+}
@@ -0,0 +1,152 @@
+package lnurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServer tests the LNURL-pay request and callback of a user.
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	var invoiceDescHash [sha256.Size]byte
+	server := NewServer(&Config{
+		Domain: "example.com",
+		Users: map[string]*User{
+			"alice": {
+				Name:        "alice",
+				MinSendable: 1000,
+				MaxSendable: 100_000,
+				Description: "Pay alice",
+			},
+		},
+		AddInvoice: func(_ context.Context, amt lnwire.MilliSatoshi,
+			descHash [sha256.Size]byte) (lntypes.Hash, string,
+			error) {
+
+			require.EqualValues(t, 5000, amt)
+			invoiceDescHash = descHash
+
+			return lntypes.Hash{}, "lnbc1invoice", nil
+		},
+	})
+
+	// The pay request of an unknown user fails.
+	rec := httptest.NewRecorder()
+	server.HandlePayRequest(rec, nil, "bob")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Usernames are case insensitive.
+	rec = httptest.NewRecorder()
+	server.HandlePayRequest(rec, nil, "Alice")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var payResp payResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&payResp))
+	require.Equal(t, payRequestTag, payResp.Tag)
+	require.Equal(
+		t, "https://example.com/lnurlp/alice/callback",
+		payResp.Callback,
+	)
+	require.EqualValues(t, 1000, payResp.MinSendable)
+	require.EqualValues(t, 100_000, payResp.MaxSendable)
+
+	var metadata [][2]string
+	err := json.Unmarshal([]byte(payResp.Metadata), &metadata)
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{"text/plain", "Pay alice"},
+		{"text/identifier", "alice@example.com"},
+	}, metadata)
+
+	// An amount outside of the limits is rejected.
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(
+		http.MethodGet, "/lnurlp/alice/callback?amount=999", nil,
+	)
+	server.HandleCallback(rec, req, "alice")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var errResp errorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResp))
+	require.Equal(t, "ERROR", errResp.Status)
+
+	// A valid amount returns an invoice that commits to the metadata.
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(
+		http.MethodGet, "/lnurlp/alice/callback?amount=5000", nil,
+	)
+	server.HandleCallback(rec, req, "alice")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var callbackResp callbackResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&callbackResp))
+	require.Equal(t, "lnbc1invoice", callbackResp.PR)
+	require.Equal(
+		t, sha256.Sum256([]byte(payResp.Metadata)), invoiceDescHash,
+	)
+}
+
+// TestServerCallbackLimits tests that callbacks beyond the rate limit and
+// oversized zap requests are rejected.
+func TestServerCallbackLimits(t *testing.T) {
+	t.Parallel()
+
+	var numInvoices int
+	server := NewServer(&Config{
+		Domain: "example.com",
+		Users: map[string]*User{
+			"alice": {
+				Name:        "alice",
+				MinSendable: 1000,
+				MaxSendable: 100_000,
+			},
+		},
+		AddInvoice: func(context.Context, lnwire.MilliSatoshi,
+			[sha256.Size]byte) (lntypes.Hash, string, error) {
+
+			numInvoices++
+
+			return lntypes.Hash{}, "lnbc1invoice", nil
+		},
+		NostrPubKey: func() (string, error) {
+			return strings.Repeat("ab", 32), nil
+		},
+		CallbackRate:  0.001,
+		CallbackBurst: 2,
+	})
+
+	callback := func(query string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(
+			http.MethodGet, "/lnurlp/alice/callback?"+query, nil,
+		)
+		server.HandleCallback(rec, req, "alice")
+
+		return rec.Code
+	}
+
+	// An oversized zap request is rejected before an invoice is added.
+	oversized := url.Values{
+		"amount": {"5000"},
+		"nostr":  {strings.Repeat("a", MaxZapRequestSize+1)},
+	}
+	require.Equal(t, http.StatusBadRequest, callback(oversized.Encode()))
+	require.Zero(t, numInvoices)
+
+	// The second callback uses up the burst, after which callbacks are
+	// rejected.
+	require.Equal(t, http.StatusOK, callback("amount=5000"))
+	require.Equal(t, http.StatusTooManyRequests, callback("amount=5000"))
+	require.Equal(t, 1, numInvoices)
+}
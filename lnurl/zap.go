@@ -0,0 +1,575 @@
+package lnurl
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// DefaultPublishTimeout is the default time publishing a zap receipt
+	// to a single relay may take.
+	DefaultPublishTimeout = 30 * time.Second
+
+	// DefaultGCInterval is the default interval at which the zap requests
+	// of canceled invoices are deleted.
+	DefaultGCInterval = time.Hour
+
+	// MaxZapRequestSize is the maximum size of a raw zap request. Zap
+	// requests are stored until their invoice is settled or canceled, and
+	// are included in the zap receipt.
+	MaxZapRequestSize = 4096
+
+	// MaxZapRelays is the maximum number of relays a zap request may ask
+	// its receipt to be published to.
+	MaxZapRelays = 10
+)
+
+var (
+	// NostrKeyLoc is the locator of the key zap receipts are signed with.
+	// The first two keys of the base encryption family are used for
+	// static channel backups and deterministic invoice preimages.
+	NostrKeyLoc = keychain.KeyLocator{
+		Family: keychain.KeyFamilyBaseEncryption,
+		Index:  2,
+	}
+
+	// zapBucket is a root-level bucket that stores the zap requests of
+	// unsettled invoices, keyed by their payment hash.
+	zapBucket = []byte("lnurl-zap-request-bucket")
+
+	// zapSettleIndexKey is a key in the zapBucket whose value is the
+	// settle index of the last invoice that was checked for a zap
+	// request. Payment hashes are 32 bytes long, so it can't collide with
+	// them.
+	zapSettleIndexKey = []byte("settle-index")
+
+	// ErrInvalidZapRequest is returned when a zap request doesn't meet the
+	// requirements of NIP-57.
+	ErrInvalidZapRequest = errors.New("invalid zap request")
+)
+
+// ParseZapRequest parses and validates the raw zap request event of a
+// payment of the given amount.
+func ParseZapRequest(raw string, amt lnwire.MilliSatoshi) (*Event, error) {
+	if len(raw) > MaxZapRequestSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes",
+			ErrInvalidZapRequest, MaxZapRequestSize)
+	}
+
+	var zapRequest Event
+	if err := json.Unmarshal([]byte(raw), &zapRequest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidZapRequest, err)
+	}
+
+	if zapRequest.Kind != KindZapRequest {
+		return nil, fmt.Errorf("%w: kind %d", ErrInvalidZapRequest,
+			zapRequest.Kind)
+	}
+
+	if err := zapRequest.Verify(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidZapRequest, err)
+	}
+
+	var numRecipients int
+	for _, tag := range zapRequest.Tags {
+		if len(tag) > 1 && tag[0] == "p" {
+			numRecipients++
+		}
+	}
+	if numRecipients != 1 {
+		return nil, fmt.Errorf("%w: must have exactly one p tag",
+			ErrInvalidZapRequest)
+	}
+
+	relays := zapRequest.Tag("relays")
+	if len(relays) == 0 {
+		return nil, fmt.Errorf("%w: no relays", ErrInvalidZapRequest)
+	}
+	if len(relays) > MaxZapRelays {
+		return nil, fmt.Errorf("%w: more than %d relays",
+			ErrInvalidZapRequest, MaxZapRelays)
+	}
+	for _, relay := range relays {
+		u, err := url.Parse(relay)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") ||
+			u.Host == "" {
+
+			return nil, fmt.Errorf("%w: invalid relay %q",
+				ErrInvalidZapRequest, relay)
+		}
+	}
+
+	if amtTag := zapRequest.Tag("amount"); len(amtTag) > 0 {
+		zapAmt, err := strconv.ParseUint(amtTag[0], 10, 64)
+		if err != nil || lnwire.MilliSatoshi(zapAmt) != amt {
+			return nil, fmt.Errorf("%w: amount doesn't match",
+				ErrInvalidZapRequest)
+		}
+	}
+
+	return &zapRequest, nil
+}
+
+// ZapStore stores the zap requests invoices were created for until their zap
+// receipts are published.
+type ZapStore struct {
+	backend kvdb.Backend
+}
+
+// NewZapStore creates a new zap store in the given database backend.
+func NewZapStore(db kvdb.Backend) (*ZapStore, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(zapBucket)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ZapStore{
+		backend: db,
+	}, nil
+}
+
+// AddZapRequest stores the raw zap request the invoice with the given payment
+// hash was created for.
+func (s *ZapStore) AddZapRequest(hash lntypes.Hash, raw []byte) error {
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(zapBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		return bucket.Put(hash[:], raw)
+	}, func() {})
+}
+
+// ZapRequest returns the raw zap request the invoice with the given payment
+// hash was created for, or nil if there is none.
+func (s *ZapStore) ZapRequest(hash lntypes.Hash) ([]byte, error) {
+	var raw []byte
+	err := kvdb.View(s.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(zapBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		if v := bucket.Get(hash[:]); v != nil {
+			raw = append([]byte{}, v...)
+		}
+
+		return nil
+	}, func() {
+		raw = nil
+	})
+
+	return raw, err
+}
+
+// DeleteZapRequest deletes the zap request of the invoice with the given
+// payment hash.
+func (s *ZapStore) DeleteZapRequest(hash lntypes.Hash) error {
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(zapBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		return bucket.Delete(hash[:])
+	}, func() {})
+}
+
+// ZapRequestHashes returns the payment hashes of all invoices that have a zap
+// request stored.
+func (s *ZapStore) ZapRequestHashes() ([]lntypes.Hash, error) {
+	var hashes []lntypes.Hash
+	err := kvdb.View(s.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(zapBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		return bucket.ForEach(func(k, _ []byte) error {
+			// Skip the settle index.
+			if len(k) != lntypes.HashSize {
+				return nil
+			}
+
+			var hash lntypes.Hash
+			copy(hash[:], k)
+			hashes = append(hashes, hash)
+
+			return nil
+		})
+	}, func() {
+		hashes = nil
+	})
+
+	return hashes, err
+}
+
+// SettleIndex returns the settle index of the last invoice that was checked
+// for a zap request.
+func (s *ZapStore) SettleIndex() (uint64, error) {
+	var index uint64
+	err := kvdb.View(s.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(zapBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		if v := bucket.Get(zapSettleIndexKey); len(v) == 8 {
+			index = binary.BigEndian.Uint64(v)
+		}
+
+		return nil
+	}, func() {
+		index = 0
+	})
+
+	return index, err
+}
+
+// SetSettleIndex stores the settle index of the last invoice that was checked
+// for a zap request.
+func (s *ZapStore) SetSettleIndex(index uint64) error {
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(zapBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		var v [8]byte
+		binary.BigEndian.PutUint64(v[:], index)
+
+		return bucket.Put(zapSettleIndexKey, v[:])
+	}, func() {})
+}
+
+// ZapInvoiceRegistry is the subset of the invoice registry the zap publisher
+// needs.
+type ZapInvoiceRegistry interface {
+	// SubscribeNotifications subscribes to newly added and settled
+	// invoices.
+	SubscribeNotifications(ctx context.Context, addIndex,
+		settleIndex uint64) (*invoices.InvoiceSubscription, error)
+
+	// LookupInvoice looks up the invoice with the given payment hash.
+	LookupInvoice(ctx context.Context, hash lntypes.Hash) (invoices.Invoice,
+		error)
+}
+
+// ZapPublisherConfig contains the dependencies of the zap publisher.
+type ZapPublisherConfig struct {
+	// Registry is the invoice registry settled invoices are received
+	// from.
+	Registry ZapInvoiceRegistry
+
+	// Store stores the zap requests of the invoices.
+	Store *ZapStore
+
+	// PubKey is the key zap receipts are signed with.
+	PubKey *btcec.PublicKey
+
+	// SignEvent signs a serialized zap receipt with the private key of
+	// PubKey. The key doesn't need to be available locally, so receipts
+	// can also be signed by a remote signer.
+	SignEvent SignFunc
+
+	// Relays are the websocket URLs of the relays every zap receipt is
+	// published to, next to the relays listed in its zap request.
+	Relays []string
+
+	// PublishTimeout is the time publishing to a single relay may take.
+	PublishTimeout time.Duration
+
+	// GCTicker signals when the zap requests of canceled invoices are
+	// deleted. Expired invoices are canceled by the invoice registry, so
+	// their zap requests are deleted as well.
+	GCTicker ticker.Ticker
+}
+
+// ZapPublisher publishes a zap receipt for every settled invoice that was
+// created for a zap request.
+type ZapPublisher struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *ZapPublisherConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewZapPublisher creates a new zap publisher from the given config.
+func NewZapPublisher(cfg *ZapPublisherConfig) *ZapPublisher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &ZapPublisher{
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		quit:   make(chan struct{}),
+	}
+}
+
+// PubKey returns the x-only public key zap receipts are signed with, as
+// announced in LNURL-pay responses.
+func (z *ZapPublisher) PubKey() string {
+	pubKey := schnorr.SerializePubKey(z.cfg.PubKey)
+	return hex.EncodeToString(pubKey)
+}
+
+// AddZapRequest stores the raw zap request the invoice with the given payment
+// hash was created for, so a zap receipt is published once it is settled.
+func (z *ZapPublisher) AddZapRequest(hash lntypes.Hash, raw []byte) error {
+	return z.cfg.Store.AddZapRequest(hash, raw)
+}
+
+// Start subscribes to the invoices settled since the last one that was
+// checked for a zap request.
+func (z *ZapPublisher) Start() error {
+	var startErr error
+	z.started.Do(func() {
+		log.Info("ZapPublisher starting")
+
+		settleIndex, err := z.cfg.Store.SettleIndex()
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		sub, err := z.cfg.Registry.SubscribeNotifications(
+			z.ctx, 0, settleIndex,
+		)
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		// Delete the zap requests of the invoices that were canceled
+		// while we were offline before handling new ones.
+		z.gcZapRequests()
+
+		z.cfg.GCTicker.Resume()
+
+		z.wg.Add(1)
+		go z.settleLoop(sub)
+	})
+
+	return startErr
+}
+
+// Stop stops publishing zap receipts.
+func (z *ZapPublisher) Stop() error {
+	z.stopped.Do(func() {
+		log.Info("ZapPublisher shutting down...")
+		defer log.Debug("ZapPublisher shutdown complete")
+
+		z.cancel()
+		close(z.quit)
+		z.wg.Wait()
+
+		z.cfg.GCTicker.Stop()
+	})
+
+	return nil
+}
+
+// settleLoop publishes the zap receipts of settled invoices, and deletes the
+// zap requests of canceled invoices.
+func (z *ZapPublisher) settleLoop(sub *invoices.InvoiceSubscription) {
+	defer z.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		select {
+		case invoice := <-sub.SettledInvoices:
+			z.handleSettledInvoice(invoice)
+
+		case <-sub.NewInvoices:
+
+		case <-z.cfg.GCTicker.Ticks():
+			z.gcZapRequests()
+
+		case <-z.quit:
+			return
+		}
+	}
+}
+
+// handleSettledInvoice publishes the zap receipt of the settled invoice if it
+// was created for a zap request.
+func (z *ZapPublisher) handleSettledInvoice(invoice *invoices.Invoice) {
+	defer func() {
+		err := z.cfg.Store.SetSettleIndex(invoice.SettleIndex)
+		if err != nil {
+			log.Errorf("Unable to store zap settle index: %v", err)
+		}
+	}()
+
+	// AMP invoices don't have a single preimage and can't be zapped.
+	if invoice.Terms.PaymentPreimage == nil {
+		return
+	}
+	preimage := *invoice.Terms.PaymentPreimage
+	hash := preimage.Hash()
+
+	raw, err := z.cfg.Store.ZapRequest(hash)
+	if err != nil {
+		log.Errorf("Unable to fetch zap request of %v: %v", hash, err)
+		return
+	}
+	if raw == nil {
+		return
+	}
+
+	receipt, relays, err := z.zapReceipt(raw, invoice, preimage)
+	if err != nil {
+		log.Errorf("Unable to create zap receipt for %v: %v", hash,
+			err)
+
+		return
+	}
+
+	z.wg.Add(1)
+	go func() {
+		defer z.wg.Done()
+
+		z.publish(receipt, relays)
+
+		if err := z.cfg.Store.DeleteZapRequest(hash); err != nil {
+			log.Errorf("Unable to delete zap request of %v: %v",
+				hash, err)
+		}
+	}()
+}
+
+// gcZapRequests deletes the zap requests of invoices that were canceled or
+// no longer exist, as they will never be settled.
+func (z *ZapPublisher) gcZapRequests() {
+	hashes, err := z.cfg.Store.ZapRequestHashes()
+	if err != nil {
+		log.Errorf("Unable to fetch zap requests: %v", err)
+		return
+	}
+
+	var numDeleted int
+	for _, hash := range hashes {
+		invoice, err := z.cfg.Registry.LookupInvoice(z.ctx, hash)
+		switch {
+		case errors.Is(err, invoices.ErrInvoiceNotFound) ||
+			errors.Is(err, invoices.ErrNoInvoicesCreated):
+
+		case err != nil:
+			log.Errorf("Unable to look up invoice %v: %v", hash,
+				err)
+
+			continue
+
+		case invoice.State != invoices.ContractCanceled:
+			continue
+		}
+
+		if err := z.cfg.Store.DeleteZapRequest(hash); err != nil {
+			log.Errorf("Unable to delete zap request of %v: %v",
+				hash, err)
+
+			continue
+		}
+		numDeleted++
+	}
+
+	if numDeleted > 0 {
+		log.Infof("Deleted %d zap requests of canceled invoices",
+			numDeleted)
+	}
+}
+
+// zapReceipt creates the signed zap receipt of the settled invoice that was
+// created for the given raw zap request, and returns it along with the relays
+// to publish it to.
+func (z *ZapPublisher) zapReceipt(raw []byte, invoice *invoices.Invoice,
+	preimage lntypes.Preimage) (*Event, []string, error) {
+
+	var zapRequest Event
+	if err := json.Unmarshal(raw, &zapRequest); err != nil {
+		return nil, nil, err
+	}
+
+	receipt := &Event{
+		CreatedAt: invoice.SettleDate.Unix(),
+		Kind:      KindZapReceipt,
+		Tags: [][]string{
+			append([]string{"p"}, zapRequest.Tag("p")...),
+		},
+	}
+	for _, name := range []string{"e", "a"} {
+		if values := zapRequest.Tag(name); len(values) > 0 {
+			receipt.Tags = append(
+				receipt.Tags, append([]string{name}, values...),
+			)
+		}
+	}
+	receipt.Tags = append(receipt.Tags,
+		[]string{"P", zapRequest.PubKey},
+		[]string{"bolt11", string(invoice.PaymentRequest)},
+		[]string{"description", string(raw)},
+		[]string{"preimage", preimage.String()},
+	)
+
+	err := receipt.SignWith(z.cfg.PubKey, z.cfg.SignEvent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The receipt is published to the relays the sender asked for, as
+	// well as to our own.
+	var relays []string
+	seen := make(map[string]struct{})
+	allRelays := append([]string{}, zapRequest.Tag("relays")...)
+	for _, relay := range append(allRelays, z.cfg.Relays...) {
+		if _, ok := seen[relay]; ok {
+			continue
+		}
+		seen[relay] = struct{}{}
+		relays = append(relays, relay)
+	}
+
+	return receipt, relays, nil
+}
+
+// publish publishes the zap receipt to the given relays.
+func (z *ZapPublisher) publish(receipt *Event, relays []string) {
+	for _, relay := range relays {
+		err := PublishEvent(z.ctx, relay, receipt, z.cfg.PublishTimeout)
+		if err != nil {
+			log.Warnf("Unable to publish zap receipt %v to %v: %v",
+				receipt.ID, relay, err)
+
+			continue
+		}
+
+		log.Debugf("Published zap receipt %v to %v", receipt.ID, relay)
+	}
+}
@@ -0,0 +1,276 @@
+package lnurl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/gorilla/websocket"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// newZapRequest returns a signed raw zap request of the given amount that
+// asks for the receipt to be published to the given relays.
+func newZapRequest(t *testing.T, amt string, relays ...string) string {
+	t.Helper()
+
+	senderKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	zapRequest := &Event{
+		CreatedAt: 1700000000,
+		Kind:      KindZapRequest,
+		Tags: [][]string{
+			append([]string{"relays"}, relays...),
+			{"amount", amt},
+			{"p", strings.Repeat("ab", 32)},
+			{"e", strings.Repeat("cd", 32)},
+		},
+		Content: "great post",
+	}
+	require.NoError(t, zapRequest.Sign(senderKey))
+
+	raw, err := json.Marshal(zapRequest)
+	require.NoError(t, err)
+
+	return string(raw)
+}
+
+// TestParseZapRequest tests the validation of zap requests.
+func TestParseZapRequest(t *testing.T) {
+	t.Parallel()
+
+	raw := newZapRequest(t, "21000", "wss://relay.example.com")
+
+	zapRequest, err := ParseZapRequest(raw, 21000)
+	require.NoError(t, err)
+	require.Equal(t, []string{"wss://relay.example.com"},
+		zapRequest.Tag("relays"))
+
+	// The amount must match the invoice.
+	_, err = ParseZapRequest(raw, 21001)
+	require.ErrorIs(t, err, ErrInvalidZapRequest)
+
+	// A tampered zap request is rejected.
+	tampered := strings.Replace(raw, "great post", "bad post", 1)
+	_, err = ParseZapRequest(tampered, 21000)
+	require.ErrorIs(t, err, ErrInvalidZapRequest)
+
+	// Only a bounded number of websocket relays is accepted.
+	relays := make([]string, MaxZapRelays+1)
+	for i := range relays {
+		relays[i] = "wss://relay.example.com"
+	}
+	_, err = ParseZapRequest(newZapRequest(t, "21000", relays...), 21000)
+	require.ErrorIs(t, err, ErrInvalidZapRequest)
+
+	raw = newZapRequest(t, "21000", "http://relay.example.com")
+	_, err = ParseZapRequest(raw, 21000)
+	require.ErrorIs(t, err, ErrInvalidZapRequest)
+
+	// Oversized zap requests are rejected.
+	raw = newZapRequest(
+		t, "21000", "wss://"+strings.Repeat("a", MaxZapRequestSize),
+	)
+	_, err = ParseZapRequest(raw, 21000)
+	require.ErrorIs(t, err, ErrInvalidZapRequest)
+}
+
+// TestZapPublisher tests that the zap receipt of a settled zap invoice is
+// published to the relays of the zap request.
+func TestZapPublisher(t *testing.T) {
+	t.Parallel()
+
+	// The relay accepts every event and hands it to the test.
+	receipts := make(chan *Event, 1)
+	upgrader := websocket.Upgrader{}
+	relay := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			var msg []json.RawMessage
+			require.NoError(t, conn.ReadJSON(&msg))
+
+			var event Event
+			require.NoError(t, json.Unmarshal(msg[1], &event))
+			receipts <- &event
+
+			err = conn.WriteJSON(
+				[]interface{}{"OK", event.ID, true, ""},
+			)
+			require.NoError(t, err)
+		},
+	))
+	t.Cleanup(relay.Close)
+	relayURL := "ws" + strings.TrimPrefix(relay.URL, "http")
+
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	store, err := NewZapStore(db)
+	require.NoError(t, err)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// Sign the receipts like the key ring does, which only receives the
+	// serialized event.
+	signEvent := func(serialized []byte) (*schnorr.Signature, error) {
+		return schnorr.Sign(privKey, chainhash.HashB(serialized))
+	}
+
+	publisher := NewZapPublisher(&ZapPublisherConfig{
+		Store:          store,
+		PubKey:         privKey.PubKey(),
+		SignEvent:      signEvent,
+		PublishTimeout: 5 * time.Second,
+		GCTicker:       ticker.NewForce(time.Hour),
+	})
+	t.Cleanup(func() {
+		require.NoError(t, publisher.Stop())
+	})
+
+	preimage := lntypes.Preimage{1}
+	rawZapRequest := newZapRequest(t, "21000", relayURL)
+	err = publisher.AddZapRequest(preimage.Hash(), []byte(rawZapRequest))
+	require.NoError(t, err)
+
+	publisher.handleSettledInvoice(&invoices.Invoice{
+		PaymentRequest: []byte("lnbc1invoice"),
+		SettleDate:     time.Unix(1700000100, 0),
+		SettleIndex:    7,
+		Terms: invoices.ContractTerm{
+			PaymentPreimage: &preimage,
+		},
+	})
+
+	var receipt *Event
+	select {
+	case receipt = <-receipts:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no zap receipt published")
+	}
+
+	require.NoError(t, receipt.Verify())
+	require.Equal(t, KindZapReceipt, receipt.Kind)
+	require.Equal(t, publisher.PubKey(), receipt.PubKey)
+	require.EqualValues(t, 1700000100, receipt.CreatedAt)
+	require.Equal(t, []string{strings.Repeat("ab", 32)}, receipt.Tag("p"))
+	require.Equal(t, []string{strings.Repeat("cd", 32)}, receipt.Tag("e"))
+	require.Equal(t, []string{"lnbc1invoice"}, receipt.Tag("bolt11"))
+	require.Equal(t, []string{rawZapRequest}, receipt.Tag("description"))
+	require.Equal(t, []string{preimage.String()}, receipt.Tag("preimage"))
+
+	// The zap request is deleted once the receipt was published, and the
+	// settle index is stored.
+	require.Eventually(t, func() bool {
+		raw, err := store.ZapRequest(preimage.Hash())
+		require.NoError(t, err)
+
+		return raw == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	settleIndex, err := store.SettleIndex()
+	require.NoError(t, err)
+	require.EqualValues(t, 7, settleIndex)
+}
+
+// mockZapRegistry is a ZapInvoiceRegistry that only looks up invoices.
+type mockZapRegistry struct {
+	invoices map[lntypes.Hash]invoices.Invoice
+}
+
+// SubscribeNotifications isn't used by the tests.
+func (m *mockZapRegistry) SubscribeNotifications(context.Context, uint64,
+	uint64) (*invoices.InvoiceSubscription, error) {
+
+	return nil, nil
+}
+
+// LookupInvoice returns the invoice with the given payment hash.
+func (m *mockZapRegistry) LookupInvoice(_ context.Context,
+	hash lntypes.Hash) (invoices.Invoice, error) {
+
+	invoice, ok := m.invoices[hash]
+	if !ok {
+		return invoices.Invoice{}, invoices.ErrInvoiceNotFound
+	}
+
+	return invoice, nil
+}
+
+// TestZapPublisherGC tests that the zap requests of canceled and unknown
+// invoices are deleted, while those of open invoices are kept.
+func TestZapPublisherGC(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	store, err := NewZapStore(db)
+	require.NoError(t, err)
+	require.NoError(t, store.SetSettleIndex(3))
+
+	openHash := lntypes.Hash{1}
+	canceledHash := lntypes.Hash{2}
+	unknownHash := lntypes.Hash{3}
+	for _, hash := range []lntypes.Hash{
+		openHash, canceledHash, unknownHash,
+	} {
+		require.NoError(t, store.AddZapRequest(hash, []byte("zap")))
+	}
+
+	registry := &mockZapRegistry{
+		invoices: map[lntypes.Hash]invoices.Invoice{
+			openHash: {State: invoices.ContractOpen},
+			canceledHash: {
+				State: invoices.ContractCanceled,
+			},
+		},
+	}
+	publisher := NewZapPublisher(&ZapPublisherConfig{
+		Registry: registry,
+		Store:    store,
+		GCTicker: ticker.NewForce(time.Hour),
+	})
+	t.Cleanup(func() {
+		require.NoError(t, publisher.Stop())
+	})
+
+	publisher.gcZapRequests()
+
+	hashes, err := store.ZapRequestHashes()
+	require.NoError(t, err)
+	require.Equal(t, []lntypes.Hash{openHash}, hashes)
+
+	// The settle index is kept.
+	settleIndex, err := store.SettleIndex()
+	require.NoError(t, err)
+	require.EqualValues(t, 3, settleIndex)
+}
@@ -0,0 +1,120 @@
+package lnutils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+)
+
+// WarpClock is a clock that follows the wall clock, but can be moved forward
+// in time at will. This allows simulating the passing of time in a running
+// node, for example to let hold invoices expire without having to wait for
+// the actual time to pass.
+type WarpClock struct {
+	// offset is the total duration the clock has been moved forward by.
+	offset time.Duration
+
+	// tickers holds all pending channels returned by TickAfter, mapped to
+	// the (warped) time they need to be triggered at.
+	tickers map[chan time.Time]time.Time
+
+	mu sync.Mutex
+}
+
+// A compile-time check to ensure WarpClock implements the clock.Clock
+// interface.
+var _ clock.Clock = (*WarpClock)(nil)
+
+// NewWarpClock creates a new WarpClock that starts at the current wall clock
+// time.
+func NewWarpClock() *WarpClock {
+	return &WarpClock{
+		tickers: make(map[chan time.Time]time.Time),
+	}
+}
+
+// Now returns the current wall clock time plus the total duration the clock
+// has been moved forward by.
+//
+// NOTE: This is part of the clock.Clock interface.
+func (c *WarpClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now()
+}
+
+// now returns the current warped time.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *WarpClock) now() time.Time {
+	return time.Now().Add(c.offset)
+}
+
+// TickAfter returns a channel that receives a tick once the given duration
+// has passed, either because the wall clock caught up or because the clock
+// was moved forward.
+//
+// NOTE: This is part of the clock.Clock interface.
+func (c *WarpClock) TickAfter(duration time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+
+	// If the duration has already passed, tick immediately.
+	if duration <= 0 {
+		ch <- c.now()
+		return ch
+	}
+
+	c.tickers[ch] = c.now().Add(duration)
+
+	// Since the clock can only move forward, the trigger time is reached
+	// at the latest once the wall clock has advanced by the full duration.
+	time.AfterFunc(duration, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if _, ok := c.tickers[ch]; !ok {
+			return
+		}
+
+		delete(c.tickers, ch)
+		ch <- c.now()
+	})
+
+	return ch
+}
+
+// Warp moves the clock forward by the given duration and triggers all
+// tickers that have expired as a result. The new time is returned.
+func (c *WarpClock) Warp(duration time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if duration > 0 {
+		c.offset += duration
+	}
+
+	now := c.now()
+	for ch, triggerTime := range c.tickers {
+		if triggerTime.After(now) {
+			continue
+		}
+
+		delete(c.tickers, ch)
+		ch <- now
+	}
+
+	return now
+}
+
+// Offset returns the total duration the clock has been moved forward by.
+func (c *WarpClock) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.offset
+}
@@ -0,0 +1,69 @@
+package lnutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestWarpClock tests that moving a WarpClock forward advances its time and
+// triggers the expired tickers.
+func TestWarpClock(t *testing.T) {
+	t.Parallel()
+
+	c := NewWarpClock()
+
+	start := c.Now()
+	require.WithinDuration(t, time.Now(), start, time.Second)
+
+	// A zero duration ticks immediately.
+	select {
+	case <-c.TickAfter(0):
+	default:
+		t.Fatal("expected immediate tick")
+	}
+
+	shortTick := c.TickAfter(time.Hour)
+	longTick := c.TickAfter(2 * time.Hour)
+
+	// Moving backwards isn't possible.
+	c.Warp(-time.Hour)
+	require.Zero(t, c.Offset())
+
+	// Moving forward by more than an hour triggers the first, but not the
+	// second ticker.
+	now := c.Warp(time.Hour + time.Minute)
+	require.Equal(t, time.Hour+time.Minute, c.Offset())
+	require.False(t, now.Before(start.Add(time.Hour+time.Minute)))
+	require.False(t, c.Now().Before(now))
+
+	select {
+	case tick := <-shortTick:
+		require.Equal(t, now, tick)
+	default:
+		t.Fatal("expected tick")
+	}
+
+	select {
+	case <-longTick:
+		t.Fatal("unexpected tick")
+	default:
+	}
+
+	// Once moved forward again, the second ticker is triggered too.
+	c.Warp(time.Hour)
+
+	select {
+	case <-longTick:
+	default:
+		t.Fatal("expected tick")
+	}
+
+	// A short ticker is also triggered by the wall clock.
+	select {
+	case <-c.TickAfter(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("expected tick")
+	}
+}
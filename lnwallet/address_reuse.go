@@ -0,0 +1,90 @@
+package lnwallet
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// AddressReuse describes an output script that was paid to by more than one
+// wallet transaction.
+type AddressReuse struct {
+	// PkScript is the reused output script.
+	PkScript []byte
+
+	// Addresses are the addresses encoded by the output script.
+	Addresses []btcutil.Address
+
+	// IsOurAddress is true if the output script belongs to the wallet.
+	IsOurAddress bool
+
+	// Txids are the hashes of the transactions that paid to the output
+	// script, in the order they were passed in.
+	Txids []chainhash.Hash
+
+	// TotalValue is the total value paid to the output script.
+	TotalValue btcutil.Amount
+}
+
+// FindAddressReuse returns the output scripts that more than one of the given
+// transactions paid to, in the order they were first paid to. Multiple outputs
+// of a single transaction that pay to the same script count as one use.
+func FindAddressReuse(txns []*TransactionDetail) []*AddressReuse {
+	var (
+		uses  []*AddressReuse
+		index = make(map[string]*AddressReuse)
+	)
+	for _, tx := range txns {
+		for _, output := range tx.OutputDetails {
+			key := string(output.PkScript)
+
+			use, ok := index[key]
+			if !ok {
+				use = &AddressReuse{
+					PkScript:     output.PkScript,
+					Addresses:    output.Addresses,
+					IsOurAddress: output.IsOurAddress,
+				}
+				index[key] = use
+				uses = append(uses, use)
+			}
+
+			use.TotalValue += output.Value
+
+			numTxids := len(use.Txids)
+			if numTxids > 0 && use.Txids[numTxids-1] == tx.Hash {
+				continue
+			}
+			use.Txids = append(use.Txids, tx.Hash)
+		}
+	}
+
+	var reused []*AddressReuse
+	for _, use := range uses {
+		if len(use.Txids) > 1 {
+			reused = append(reused, use)
+		}
+	}
+
+	return reused
+}
+
+// PreviouslyPaid returns those of the given output scripts that one of the
+// given transactions already paid to.
+func PreviouslyPaid(txns []*TransactionDetail, pkScripts [][]byte) [][]byte {
+	var paid [][]byte
+	for _, pkScript := range pkScripts {
+	search:
+		for _, tx := range txns {
+			for _, output := range tx.OutputDetails {
+				if bytes.Equal(output.PkScript, pkScript) {
+					paid = append(paid, pkScript)
+					break search
+				}
+			}
+		}
+	}
+
+	return paid
+}
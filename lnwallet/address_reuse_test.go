@@ -0,0 +1,70 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+// reuseTestTx returns a transaction with outputs paying to the given scripts.
+func reuseTestTx(hash byte, pkScripts ...string) *TransactionDetail {
+	tx := &TransactionDetail{
+		Hash: chainhash.Hash{hash},
+	}
+	for i, pkScript := range pkScripts {
+		tx.OutputDetails = append(tx.OutputDetails, OutputDetail{
+			PkScript:    []byte(pkScript),
+			OutputIndex: i,
+			Value:       btcutil.Amount(1_000),
+		})
+	}
+
+	return tx
+}
+
+// TestFindAddressReuse tests that only output scripts paid to by more than
+// one transaction are reported.
+func TestFindAddressReuse(t *testing.T) {
+	t.Parallel()
+
+	txns := []*TransactionDetail{
+		reuseTestTx(1, "a", "b"),
+		reuseTestTx(2, "c", "c"),
+		reuseTestTx(3, "b", "d"),
+		reuseTestTx(4, "a"),
+	}
+
+	reused := FindAddressReuse(txns)
+	require.Len(t, reused, 2)
+
+	require.Equal(t, []byte("a"), reused[0].PkScript)
+	require.Equal(
+		t, []chainhash.Hash{{1}, {4}}, reused[0].Txids,
+	)
+	require.Equal(t, btcutil.Amount(2_000), reused[0].TotalValue)
+
+	require.Equal(t, []byte("b"), reused[1].PkScript)
+	require.Equal(
+		t, []chainhash.Hash{{1}, {3}}, reused[1].Txids,
+	)
+}
+
+// TestPreviouslyPaid tests that the output scripts a transaction already paid
+// to are returned.
+func TestPreviouslyPaid(t *testing.T) {
+	t.Parallel()
+
+	txns := []*TransactionDetail{
+		reuseTestTx(1, "a", "b"),
+		reuseTestTx(2, "c"),
+	}
+
+	paid := PreviouslyPaid(txns, [][]byte{
+		[]byte("c"), []byte("d"), []byte("a"),
+	})
+	require.Equal(t, [][]byte{[]byte("c"), []byte("a")}, paid)
+
+	require.Empty(t, PreviouslyPaid(txns, [][]byte{[]byte("e")}))
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 )
 
 const (
@@ -52,6 +53,10 @@ const (
 	// walletReadyKey is used to indicate that the wallet has been
 	// initialized.
 	walletReadyKey = "ready"
+
+	// waddrmgrBucket is the top level bucket btcwallet stores its address
+	// manager in, which exists in every initialized wallet database.
+	waddrmgrBucket = "waddrmgr"
 )
 
 var (
@@ -191,6 +196,10 @@ type loaderCfg struct {
 	dbTimeout      time.Duration
 	useLocalDB     bool
 	externalDB     kvdb.Backend
+
+	// externalLocalDB indicates that the external db holds a wallet that
+	// was created in a local db.
+	externalLocalDB bool
 }
 
 // LoaderOption is a functional option to update the optional loader config.
@@ -215,6 +224,17 @@ func LoaderWithExternalWalletDB(db kvdb.Backend) LoaderOption {
 	}
 }
 
+// LoaderWithOpenedLocalWalletDB configures the wallet loader to use the given,
+// already opened local db. In contrast to LoaderWithExternalWalletDB, this
+// allows wallets to be loaded that were created in the local db. The db is
+// never closed by the loader.
+func LoaderWithOpenedLocalWalletDB(db kvdb.Backend) LoaderOption {
+	return func(cfg *loaderCfg) {
+		cfg.externalDB = db
+		cfg.externalLocalDB = true
+	}
+}
+
 // NewWalletLoader constructs a wallet loader.
 func NewWalletLoader(chainParams *chaincfg.Params, recoveryWindow uint32,
 	opts ...LoaderOption) (*base.Loader, error) {
@@ -232,11 +252,17 @@ func NewWalletLoader(chainParams *chaincfg.Params, recoveryWindow uint32,
 	}
 
 	if cfg.externalDB != nil {
+		walletExists := func() (bool, error) {
+			if cfg.externalLocalDB {
+				return localWalletExists(cfg.externalDB)
+			}
+
+			return externalWalletExists(cfg.externalDB)
+		}
+
 		loader, err := base.NewLoaderWithDB(
 			chainParams, recoveryWindow, cfg.externalDB,
-			func() (bool, error) {
-				return externalWalletExists(cfg.externalDB)
-			},
+			walletExists,
 		)
 		if err != nil {
 			return nil, err
@@ -272,6 +298,20 @@ func externalWalletExists(db kvdb.Backend) (bool, error) {
 	return exists, err
 }
 
+// localWalletExists is a helper function that we use to template btcwallet's
+// Loader in order to be able check if a wallet that was created in a local DB
+// exists in an opened instance of that DB.
+func localWalletExists(db kvdb.Backend) (bool, error) {
+	exists := false
+	err := kvdb.View(db, func(tx kvdb.RTx) error {
+		exists = tx.ReadBucket([]byte(waddrmgrBucket)) != nil
+
+		return nil
+	}, func() {})
+
+	return exists, err
+}
+
 // onWalletCreated is executed when btcwallet creates the wallet the first time.
 func onWalletCreated(tx kvdb.RwTx) error {
 	metaBucket, err := tx.CreateTopLevelBucket([]byte(walletMetaBucket))
@@ -937,6 +977,47 @@ func (b *BtcWallet) ImportAccount(name string, accountPubKey *hdkeychain.Extende
 	return accountProps, externalAddrs, internalAddrs, nil
 }
 
+// CreateAccount creates a new account that is derived from the wallet's seed,
+// in the key scope of the given address type. Account names must be unique
+// across all key scopes.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) CreateAccount(name string,
+	addrType lnwallet.AddressType) (*waddrmgr.AccountProperties, error) {
+
+	if name == lnwallet.DefaultAccountName ||
+		name == waddrmgr.ImportedAddrAccountName {
+
+		return nil, fmt.Errorf("account '%s' is reserved", name)
+	}
+
+	_, err := b.ListAccounts(name, nil)
+	if err == nil {
+		return nil, fmt.Errorf("account '%s' already exists", name)
+	}
+	if !waddrmgr.IsError(err, waddrmgr.ErrAccountNotFound) {
+		return nil, err
+	}
+
+	var keyScope waddrmgr.KeyScope
+	switch addrType {
+	case lnwallet.WitnessPubKey:
+		keyScope = waddrmgr.KeyScopeBIP0084
+	case lnwallet.TaprootPubkey:
+		keyScope = waddrmgr.KeyScopeBIP0086
+	default:
+		return nil, fmt.Errorf("unsupported address type %v for new "+
+			"account", addrType)
+	}
+
+	account, err := b.wallet.NextAccount(keyScope, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.wallet.AccountProperties(keyScope, account)
+}
+
 // ImportPublicKey imports a single derived public key into the wallet. The
 // address type can usually be inferred from the key's version, but in the case
 // of legacy versions (xpub, tpub), an address type must be specified as we
@@ -995,6 +1076,11 @@ func (b *BtcWallet) SendOutputs(outputs []*wire.TxOut,
 		return nil, lnwallet.ErrInvalidMinconf
 	}
 
+	strategy, err := b.resolveCoinSelectionStrategy(strategy, outputs)
+	if err != nil {
+		return nil, err
+	}
+
 	return b.wallet.SendOutputs(
 		outputs, nil, defaultAccount, minConfs, feeSatPerKB,
 		strategy, label,
@@ -1047,12 +1133,64 @@ func (b *BtcWallet) CreateSimpleTx(outputs []*wire.TxOut,
 		}
 	}
 
+	strategy, err := b.resolveCoinSelectionStrategy(strategy, outputs)
+	if err != nil {
+		return nil, err
+	}
+
 	return b.wallet.CreateSimpleTx(
 		nil, defaultAccount, outputs, minConfs, feeSatPerKB,
 		strategy, dryRun,
 	)
 }
 
+// resolveCoinSelectionStrategy returns the given coin selection strategy with
+// the information it needs to arrange the wallet's coins for a transaction
+// paying to the given outputs, like the confirmations of the coins or the
+// amount to fund.
+//
+// NOTE: This method requires the global coin selection lock to be held.
+func (b *BtcWallet) resolveCoinSelectionStrategy(
+	strategy base.CoinSelectionStrategy,
+	outputs []*wire.TxOut) (base.CoinSelectionStrategy, error) {
+
+	var (
+		target btcutil.Amount
+		weight input.TxWeightEstimator
+	)
+	for _, output := range outputs {
+		target += btcutil.Amount(output.Value)
+		weight.AddTxOutput(output)
+	}
+
+	// The wallet accounts for a change output while selecting coins, even
+	// if it's dropped in the end.
+	weight.AddP2TROutput()
+
+	strategy = chanfunding.WithTarget(
+		strategy, target, weight,
+		lnwallet.DustLimitForSize(input.P2WPKHSize),
+	)
+
+	return chanfunding.WithConfirmations(
+		strategy, func() (map[wire.OutPoint]int64, error) {
+			utxos, err := b.ListUnspentWitness(
+				0, math.MaxInt32, "",
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			confs := make(map[wire.OutPoint]int64, len(utxos))
+			for _, utxo := range utxos {
+				confs[utxo.OutPoint] = utxo.Confirmations
+			}
+
+			return confs, nil
+		},
+	)
+}
+
 // LeaseOutput locks an output to the given ID, preventing it from being
 // available for any future coin selection attempts. The absolute time of the
 // lock's expiration is returned. The expiration of the lock can be extended by
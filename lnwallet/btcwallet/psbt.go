@@ -139,6 +139,17 @@ func (b *BtcWallet) FundPsbt(packet *psbt.Packet, minConfs int32,
 		opts = append(opts, wallet.WithUtxoFilter(allowUtxo))
 	}
 
+	// Coins are only selected if the packet doesn't have any inputs yet.
+	if len(packet.UnsignedTx.TxIn) == 0 {
+		var err error
+		strategy, err = b.resolveCoinSelectionStrategy(
+			strategy, packet.UnsignedTx.TxOut,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Let the wallet handle coin selection and/or fee estimation based on
 	// the partial TX information in the packet.
 	return b.wallet.FundPsbt(
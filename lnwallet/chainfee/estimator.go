@@ -157,6 +157,9 @@ type BtcdEstimator struct {
 	// suitable feerate to use that will allow successful transaction
 	// propagation.
 	filterManager *filterManager
+
+	// mempoolFees caches the fee distribution of btcd's mempool.
+	mempoolFees *mempoolFeeCache
 }
 
 // NewBtcdEstimator creates a new BtcdEstimator given a fully populated
@@ -182,6 +185,11 @@ func NewBtcdEstimator(rpcConfig rpcclient.ConnConfig,
 		fallbackFeePerKW: fallBackFeeRate,
 		btcdConn:         chainConn,
 		filterManager:    newFilterManager(fetchCb),
+		mempoolFees: newMempoolFeeCache(
+			func() (*MempoolFeeDistribution, error) {
+				return fetchMempoolFeeDistribution(chainConn)
+			},
+		),
 	}, nil
 }
 
@@ -325,9 +333,19 @@ func (b *BtcdEstimator) fetchEstimateInner(confTarget uint32) (SatPerKWeight,
 	return SatPerKVByte(satPerKB).FeePerKWeight(), nil
 }
 
+// MempoolFeeDistribution returns the fee distribution of btcd's mempool.
+//
+// NOTE: This method is part of the MempoolFeeSource interface.
+func (b *BtcdEstimator) MempoolFeeDistribution() (*MempoolFeeDistribution,
+	error) {
+
+	return b.mempoolFees.get()
+}
+
 // A compile-time assertion to ensure that BtcdEstimator implements the
-// Estimator interface.
+// Estimator and MempoolFeeSource interfaces.
 var _ Estimator = (*BtcdEstimator)(nil)
+var _ MempoolFeeSource = (*BtcdEstimator)(nil)
 
 // BitcoindEstimator is an implementation of the Estimator interface backed by
 // the RPC interface of an active bitcoind node. This implementation will proxy
@@ -357,6 +375,9 @@ type BitcoindEstimator struct {
 	// suitable feerate to use that will allow successful transaction
 	// propagation.
 	filterManager *filterManager
+
+	// mempoolFees caches the fee distribution of bitcoind's mempool.
+	mempoolFees *mempoolFeeCache
 }
 
 // NewBitcoindEstimator creates a new BitcoindEstimator given a fully populated
@@ -385,6 +406,11 @@ func NewBitcoindEstimator(rpcConfig rpcclient.ConnConfig, feeMode string,
 		bitcoindConn:     chainConn,
 		feeMode:          feeMode,
 		filterManager:    newFilterManager(fetchCb),
+		mempoolFees: newMempoolFeeCache(
+			func() (*MempoolFeeDistribution, error) {
+				return fetchMempoolFeeDistribution(chainConn)
+			},
+		),
 	}, nil
 }
 
@@ -619,9 +645,19 @@ func chooseMinFee(minRelayFeeFunc func() SatPerKWeight,
 	return minRelayFee
 }
 
+// MempoolFeeDistribution returns the fee distribution of bitcoind's mempool.
+//
+// NOTE: This method is part of the MempoolFeeSource interface.
+func (b *BitcoindEstimator) MempoolFeeDistribution() (*MempoolFeeDistribution,
+	error) {
+
+	return b.mempoolFees.get()
+}
+
 // A compile-time assertion to ensure that BitcoindEstimator implements the
-// Estimator interface.
+// Estimator and MempoolFeeSource interfaces.
 var _ Estimator = (*BitcoindEstimator)(nil)
+var _ MempoolFeeSource = (*BitcoindEstimator)(nil)
 
 // WebAPIFeeSource is an interface allows the WebAPIEstimator to query an
 // arbitrary HTTP-based fee estimator. Each new set/network will gain an
@@ -640,6 +676,11 @@ type WebAPIFeeSource interface {
 type SparseConfFeeSource struct {
 	// URL is the fee estimation API specified by the user.
 	URL string
+
+	// Dial, if set, is used to establish the connections to the API, for
+	// example to route them through Tor. Otherwise a direct connection is
+	// made.
+	Dial func(network, addr string) (net.Conn, error)
 }
 
 // parseResponse attempts to parse the body of the response generated by the
@@ -670,10 +711,15 @@ func (s SparseConfFeeSource) GetFeeMap() (map[uint32]uint32, error) {
 	// which will allow us to control how long we'll wait to read the
 	// response from the service. This way, if the service is down or
 	// overloaded, we can exit early and use our default fee.
+	dial := (&net.Dialer{
+		Timeout: WebAPIConnectionTimeout,
+	}).Dial
+	if s.Dial != nil {
+		dial = s.Dial
+	}
+
 	netTransport := &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: WebAPIConnectionTimeout,
-		}).Dial,
+		Dial:                dial,
 		TLSHandshakeTimeout: WebAPIConnectionTimeout,
 	}
 	netClient := &http.Client{
@@ -3,6 +3,9 @@ package chainfee
 import (
 	"bytes"
 	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -131,6 +134,39 @@ func TestSparseConfFeeSource(t *testing.T) {
 	require.Error(t, err, "expected error when parsing bad JSON")
 }
 
+// TestSparseConfFeeSourceDial checks that SparseConfFeeSource establishes its
+// connections to the API with the configured dialer.
+func TestSparseConfFeeSourceDial(t *testing.T) {
+	t.Parallel()
+
+	testFees := map[uint32]uint32{1: 12345}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			err := json.NewEncoder(w).Encode(
+				map[string]map[uint32]uint32{
+					"fee_by_block_target": testFees,
+				},
+			)
+			require.NoError(t, err)
+		},
+	))
+	t.Cleanup(server.Close)
+
+	var dialed []string
+	feeSource := SparseConfFeeSource{
+		URL: server.URL,
+		Dial: func(network, addr string) (net.Conn, error) {
+			dialed = append(dialed, addr)
+			return net.Dial(network, addr)
+		},
+	}
+
+	fees, err := feeSource.GetFeeMap()
+	require.NoError(t, err)
+	require.Equal(t, testFees, fees)
+	require.Equal(t, []string{server.Listener.Addr().String()}, dialed)
+}
+
 // TestWebAPIFeeEstimator checks that the WebAPIFeeEstimator returns fee rates
 // as expected.
 func TestWebAPIFeeEstimator(t *testing.T) {
@@ -0,0 +1,207 @@
+package chainfee
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+const (
+	// maxBlockVSize is the max virtual size of the transactions in a
+	// block.
+	maxBlockVSize = lntypes.VByte(blockchain.MaxBlockWeight / 4)
+
+	// mempoolCacheDuration is the duration for which a fetched mempool fee
+	// distribution is reused, as fetching it requires the chain backend
+	// to list all its mempool transactions.
+	mempoolCacheDuration = 30 * time.Second
+)
+
+// MempoolFeeSource provides the fee rates paid by the transactions in the
+// mempool of the chain backend.
+type MempoolFeeSource interface {
+	// MempoolFeeDistribution returns the fee distribution of the current
+	// mempool.
+	MempoolFeeDistribution() (*MempoolFeeDistribution, error)
+}
+
+// MempoolTx describes the fee paid by a transaction in the mempool.
+type MempoolTx struct {
+	// Fee is the fee paid by the transaction.
+	Fee btcutil.Amount
+
+	// VSize is the virtual size of the transaction.
+	VSize lntypes.VByte
+}
+
+// feeRate returns the fee rate paid by the transaction.
+func (m MempoolTx) feeRate() SatPerKWeight {
+	return SatPerKVByte(m.Fee * 1000 / btcutil.Amount(m.VSize)).
+		FeePerKWeight()
+}
+
+// MempoolFeeDistribution describes the fee rates paid by the transactions in a
+// mempool, which are expected to be mined in the order of their fee rates.
+//
+// NOTE: The fee rates of the transactions are considered on their own, so
+// the projection doesn't account for transactions that are mined earlier
+// because a child pays for them.
+type MempoolFeeDistribution struct {
+	// txns are the transactions of the mempool, sorted by their fee rates
+	// in descending order.
+	txns []MempoolTx
+}
+
+// NewMempoolFeeDistribution creates the fee distribution of a mempool holding
+// the given transactions.
+func NewMempoolFeeDistribution(txns []MempoolTx) *MempoolFeeDistribution {
+	sorted := make([]MempoolTx, 0, len(txns))
+	for _, tx := range txns {
+		// Skip malformed entries to avoid dividing by zero.
+		if tx.VSize <= 0 {
+			continue
+		}
+
+		sorted = append(sorted, tx)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].feeRate() > sorted[j].feeRate()
+	})
+
+	return &MempoolFeeDistribution{
+		txns: sorted,
+	}
+}
+
+// FeeRateForBlocks returns the fee rate a transaction must pay to be mined in
+// one of the next numBlocks blocks, given the current mempool. This is the
+// lowest fee rate among the transactions that fill those blocks. If the
+// mempool doesn't fill them, FeePerKwFloor is returned.
+func (m *MempoolFeeDistribution) FeeRateForBlocks(
+	numBlocks uint32) SatPerKWeight {
+
+	capacity := lntypes.VByte(numBlocks) * maxBlockVSize
+
+	var filled lntypes.VByte
+	for _, tx := range m.txns {
+		filled += tx.VSize
+		if filled >= capacity {
+			return max(tx.feeRate(), FeePerKwFloor)
+		}
+	}
+
+	return FeePerKwFloor
+}
+
+// rawMempoolEntry is the part of an entry of the verbose getrawmempool
+// response that describes the fee paid by a transaction.
+type rawMempoolEntry struct {
+	// VSize is the virtual size of the transaction.
+	VSize int64 `json:"vsize"`
+
+	// Fee is the fee paid by the transaction in BTC, which is only
+	// returned by btcd and older versions of bitcoind.
+	Fee float64 `json:"fee"`
+
+	// Fees holds the fees paid by the transaction in BTC, which replaced
+	// the fee field in bitcoind.
+	Fees *struct {
+		// Modified is the fee used to prioritize the transaction for
+		// mining.
+		Modified float64 `json:"modified"`
+	} `json:"fees"`
+}
+
+// fetchMempoolFeeDistribution fetches all the transactions of the mempool of
+// the given btcd or bitcoind node to create its fee distribution.
+func fetchMempoolFeeDistribution(
+	client *rpcclient.Client) (*MempoolFeeDistribution, error) {
+
+	verbose, err := json.Marshal(true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.RawRequest(
+		"getrawmempool", []json.RawMessage{verbose},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]rawMempoolEntry
+	if err := json.Unmarshal(resp, &entries); err != nil {
+		return nil, err
+	}
+
+	txns := make([]MempoolTx, 0, len(entries))
+	for _, entry := range entries {
+		fee := entry.Fee
+		if entry.Fees != nil {
+			fee = entry.Fees.Modified
+		}
+
+		amt, err := btcutil.NewAmount(fee)
+		if err != nil {
+			return nil, err
+		}
+
+		txns = append(txns, MempoolTx{
+			Fee:   amt,
+			VSize: lntypes.VByte(entry.VSize),
+		})
+	}
+
+	return NewMempoolFeeDistribution(txns), nil
+}
+
+// mempoolFeeCache caches the mempool fee distribution fetched from the chain
+// backend for the mempoolCacheDuration.
+type mempoolFeeCache struct {
+	// fetch fetches the current mempool fee distribution.
+	fetch func() (*MempoolFeeDistribution, error)
+
+	mu           sync.Mutex
+	distribution *MempoolFeeDistribution
+	fetchedAt    time.Time
+}
+
+// newMempoolFeeCache creates a new mempool fee cache that uses the given
+// function to fetch the mempool fee distribution.
+func newMempoolFeeCache(
+	fetch func() (*MempoolFeeDistribution, error)) *mempoolFeeCache {
+
+	return &mempoolFeeCache{
+		fetch: fetch,
+	}
+}
+
+// get returns the cached mempool fee distribution, fetching a new one if the
+// cached one is outdated.
+func (c *mempoolFeeCache) get() (*MempoolFeeDistribution, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.distribution != nil &&
+		time.Since(c.fetchedAt) < mempoolCacheDuration {
+
+		return c.distribution, nil
+	}
+
+	distribution, err := c.fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.distribution = distribution
+	c.fetchedAt = time.Now()
+
+	return distribution, nil
+}
@@ -0,0 +1,80 @@
+package chainfee
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMempoolFeeRateForBlocks checks that the fee rate required to be mined
+// within a number of blocks is derived from the mempool transactions filling
+// those blocks.
+func TestMempoolFeeRateForBlocks(t *testing.T) {
+	t.Parallel()
+
+	// Each transaction fills half a block, paying 40, 20, 10 and 5 sat/vb.
+	const halfBlock = maxBlockVSize / 2
+	distribution := NewMempoolFeeDistribution([]MempoolTx{
+		{Fee: btcutil.Amount(10 * halfBlock), VSize: halfBlock},
+		{Fee: btcutil.Amount(40 * halfBlock), VSize: halfBlock},
+		{Fee: btcutil.Amount(5 * halfBlock), VSize: halfBlock},
+		{Fee: btcutil.Amount(20 * halfBlock), VSize: halfBlock},
+		{Fee: 1_000, VSize: 0},
+	})
+
+	require.Equal(
+		t, SatPerVByte(20).FeePerKWeight(),
+		distribution.FeeRateForBlocks(1),
+	)
+	require.Equal(
+		t, SatPerVByte(5).FeePerKWeight(),
+		distribution.FeeRateForBlocks(2),
+	)
+
+	// If the mempool doesn't fill the blocks, any fee rate that's relayed
+	// will do.
+	require.Equal(t, FeePerKwFloor, distribution.FeeRateForBlocks(3))
+	require.Equal(
+		t, FeePerKwFloor,
+		NewMempoolFeeDistribution(nil).FeeRateForBlocks(1),
+	)
+}
+
+// TestMempoolFeeCache checks that the mempool fee distribution is only fetched
+// again once the cached one is outdated.
+func TestMempoolFeeCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fetches  int
+		fetchErr error
+	)
+	cache := newMempoolFeeCache(func() (*MempoolFeeDistribution, error) {
+		fetches++
+
+		return NewMempoolFeeDistribution(nil), fetchErr
+	})
+
+	// A failed fetch isn't cached.
+	fetchErr = errors.New("backend down")
+	_, err := cache.get()
+	require.ErrorIs(t, err, fetchErr)
+
+	fetchErr = nil
+	distribution, err := cache.get()
+	require.NoError(t, err)
+	require.Equal(t, 2, fetches)
+
+	cached, err := cache.get()
+	require.NoError(t, err)
+	require.Same(t, distribution, cached)
+	require.Equal(t, 2, fetches)
+
+	// Once outdated, the distribution is fetched again.
+	cache.fetchedAt = cache.fetchedAt.Add(-mempoolCacheDuration)
+	_, err = cache.get()
+	require.NoError(t, err)
+	require.Equal(t, 3, fetches)
+}
@@ -64,3 +64,26 @@ func (m *MockEstimator) RelayFeePerKW() SatPerKWeight {
 
 	return args.Get(0).(SatPerKWeight)
 }
+
+// MockMempoolFeeSource implements the `MempoolFeeSource` interface and is used
+// by other packages for mock testing.
+type MockMempoolFeeSource struct {
+	mock.Mock
+}
+
+// Compile time assertion that MockMempoolFeeSource implements
+// MempoolFeeSource.
+var _ MempoolFeeSource = (*MockMempoolFeeSource)(nil)
+
+// MempoolFeeDistribution returns the fee distribution of the current mempool.
+func (m *MockMempoolFeeSource) MempoolFeeDistribution() (
+	*MempoolFeeDistribution, error) {
+
+	args := m.Called()
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*MempoolFeeDistribution), args.Error(1)
+}
@@ -138,6 +138,27 @@ type ChanCloseCfg struct {
 	// FeeEstimator is used to estimate the absolute starting co-op close
 	// fee.
 	FeeEstimator CoopFeeEstimator
+
+	// PeerAlias is the alias of the channel peer, which is added to the
+	// label of the closing transaction. It is empty if the alias isn't
+	// known.
+	PeerAlias string
+
+	// RbfCoopClose is true if the channel is closed using the RBF coop
+	// close protocol. In this protocol the party that initiated the close
+	// pays the full fee, and can replace the closing transaction with one
+	// paying a higher fee after it was broadcast.
+	RbfCoopClose bool
+
+	// RbfFeeRateIncrease is the percentage by which the fee rate of the
+	// closing transaction is increased by a scheduled fee bump of an RBF
+	// coop close.
+	RbfFeeRateIncrease uint32
+
+	// RbfMaxFeeRate, if non-zero, is the fee rate up to which the closing
+	// transaction of an RBF coop close is bumped on schedule. Otherwise,
+	// scheduled fee bumps stop at the max fee of the close.
+	RbfMaxFeeRate chainfee.SatPerKWeight
 }
 
 // ChanCloser is a state machine that handles the cooperative channel closure
@@ -213,6 +234,22 @@ type ChanCloser struct {
 	// we use to handle a specific race condition caused by the independent
 	// message processing queues.
 	cachedClosingSigned fn.Option[lnwire.ClosingSigned]
+
+	// cachedClosingComplete is a cached copy of a received ClosingComplete
+	// that we use to handle the same race condition for RBF coop closes.
+	cachedClosingComplete fn.Option[lnwire.ClosingComplete]
+
+	// rbfProposal is the last closing transaction that we proposed as the
+	// closer of an RBF coop close, which the closee didn't sign yet.
+	rbfProposal fn.Option[rbfProposal]
+
+	// rbfFeeRate is the fee rate of the last closing transaction of an
+	// RBF coop close that was signed by both parties.
+	rbfFeeRate chainfee.SatPerKWeight
+
+	// rbfSignedFee is the fee of the last closing transaction of an RBF
+	// coop close that was signed by both parties.
+	rbfSignedFee btcutil.Amount
 }
 
 // calcCoopCloseFee computes an "ideal" absolute co-op close fee given the
@@ -286,11 +323,10 @@ func NewChanCloser(cfg ChanCloseCfg, deliveryScript []byte,
 	}
 }
 
-// initFeeBaseline computes our ideal fee rate, and also the largest fee we'll
-// accept given information about the delivery script of the remote party.
-func (c *ChanCloser) initFeeBaseline() {
-	// Depending on if a balance ends up being dust or not, we'll pass a
-	// nil TxOut into the EstimateFee call which can handle it.
+// closeTxOuts returns the outputs of the closing transaction that are used to
+// estimate its fee. Depending on if a balance ends up being dust or not, a nil
+// TxOut is returned for it, which the EstimateFee call can handle.
+func (c *ChanCloser) closeTxOuts() (*wire.TxOut, *wire.TxOut) {
 	var localTxOut, remoteTxOut *wire.TxOut
 	if !c.cfg.Channel.LocalBalanceDust() {
 		localTxOut = &wire.TxOut{
@@ -305,6 +341,14 @@ func (c *ChanCloser) initFeeBaseline() {
 		}
 	}
 
+	return localTxOut, remoteTxOut
+}
+
+// initFeeBaseline computes our ideal fee rate, and also the largest fee we'll
+// accept given information about the delivery script of the remote party.
+func (c *ChanCloser) initFeeBaseline() {
+	localTxOut, remoteTxOut := c.closeTxOuts()
+
 	// Given the target fee-per-kw, we'll compute what our ideal _total_
 	// fee will be starting at for this fee negotiation.
 	c.idealFeeSat = c.cfg.FeeEstimator.EstimateFee(
@@ -426,6 +470,12 @@ func (c *ChanCloser) CloseRequest() *htlcswitch.ChanClose {
 	return c.closeReq
 }
 
+// LocallyInitiated returns true if we initiated the cooperative closure
+// workflow.
+func (c *ChanCloser) LocallyInitiated() bool {
+	return c.locallyInitiated
+}
+
 // Channel returns the channel stored in the config as a
 // *lnwallet.LightningChannel.
 //
@@ -708,6 +758,13 @@ func (c *ChanCloser) ReceiveClosingSigned( //nolint:funlen
 		return fn.None[lnwire.ClosingSigned](), nil
 
 	case closeFeeNegotiation:
+		// The closing transaction of an RBF coop close is only signed
+		// with ClosingComplete and ClosingSig messages.
+		if c.cfg.RbfCoopClose {
+			return noClosing, fmt.Errorf("%w: closing_signed "+
+				"received for RBF coop close", ErrInvalidState)
+		}
+
 		// If this is a taproot channel, then it MUST have a partial
 		// signature set at this point.
 		isTaproot := c.cfg.Channel.ChanType().IsTaproot()
@@ -876,8 +933,9 @@ func (c *ChanCloser) ReceiveClosingSigned( //nolint:funlen
 
 		// Create a close channel label.
 		chanID := c.cfg.Channel.ShortChanID()
-		closeLabel := labels.MakeLabel(
+		closeLabel := labels.MakeChannelLabel(
 			labels.LabelTypeChannelClose, &chanID,
+			c.cfg.Channel.ChannelPoint(), c.cfg.PeerAlias,
 		)
 
 		if err := c.cfg.BroadcastTx(closeTx, closeLabel); err != nil {
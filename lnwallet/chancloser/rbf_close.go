@@ -0,0 +1,493 @@
+package chancloser
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrNotRbfCoopClose is returned when a method of the RBF coop close
+	// protocol is called for a channel that is closed with the legacy
+	// fee negotiation.
+	ErrNotRbfCoopClose = fmt.Errorf("channel isn't closed using the RBF " +
+		"coop close protocol")
+
+	// ErrNotCloser is returned when the closee of an RBF coop close
+	// attempts to propose a closing transaction, or the closer receives a
+	// proposal.
+	ErrNotCloser = fmt.Errorf("only the party that initiated the close " +
+		"proposes closing transactions")
+
+	// ErrRbfFeeTooLow is returned when a replacement closing transaction
+	// doesn't pay a higher fee than the closing transaction it replaces.
+	ErrRbfFeeTooLow = fmt.Errorf("replacement closing transaction must " +
+		"pay a higher fee")
+
+	// ErrMaxRbfFeeReached is returned when a scheduled fee bump is
+	// attempted while the closing transaction already pays the max fee.
+	ErrMaxRbfFeeReached = fmt.Errorf("closing transaction already pays " +
+		"the max fee")
+
+	// ErrRbfProposalPending is returned when a fee bump is attempted while
+	// the closee didn't sign our last proposal yet.
+	ErrRbfProposalPending = fmt.Errorf("closing transaction proposal " +
+		"still pending")
+
+	// errNoClosingSig is returned when a ClosingComplete or ClosingSig
+	// message doesn't carry the expected signature.
+	errNoClosingSig = fmt.Errorf("closing signature not populated")
+)
+
+// closingSigType denotes which outputs a closing transaction signed with a
+// ClosingComplete or ClosingSig message has.
+type closingSigType uint8
+
+const (
+	// closerAndClosee is a closing transaction paying out to both the
+	// closer and the closee.
+	closerAndClosee closingSigType = iota
+
+	// closerNoClosee is a closing transaction without the dust output of
+	// the closee.
+	closerNoClosee
+
+	// noCloserClosee is a closing transaction without the dust output of
+	// the closer.
+	noCloserClosee
+)
+
+// newClosingSigs returns the closing signatures with the given signature set
+// for the given type of closing transaction.
+func newClosingSigs(sigType closingSigType, sig lnwire.Sig) lnwire.ClosingSigs {
+	var sigs lnwire.ClosingSigs
+	switch sigType {
+	case closerAndClosee:
+		sigs.CloserAndClosee = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType3](sig),
+		)
+
+	case closerNoClosee:
+		sigs.CloserNoClosee = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType1](sig),
+		)
+
+	case noCloserClosee:
+		sigs.NoCloserClosee = tlv.SomeRecordT(
+			tlv.NewRecordT[tlv.TlvType2](sig),
+		)
+	}
+
+	return sigs
+}
+
+// closingSig returns the signature for the given type of closing transaction
+// from the passed closing signatures.
+func closingSig(sigs lnwire.ClosingSigs,
+	sigType closingSigType) (lnwire.Sig, error) {
+
+	switch sigType {
+	case closerAndClosee:
+		return sigs.CloserAndClosee.UnwrapOrErrV(errNoClosingSig)
+
+	case closerNoClosee:
+		return sigs.CloserNoClosee.UnwrapOrErrV(errNoClosingSig)
+
+	default:
+		return sigs.NoCloserClosee.UnwrapOrErrV(errNoClosingSig)
+	}
+}
+
+// proposedClosingSig returns the single signature of a ClosingComplete message
+// together with the type of closing transaction it signs.
+func proposedClosingSig(sigs lnwire.ClosingSigs) (closingSigType, lnwire.Sig,
+	error) {
+
+	for _, sigType := range []closingSigType{
+		closerAndClosee, closerNoClosee, noCloserClosee,
+	} {
+		sig, err := closingSig(sigs, sigType)
+		if err == nil {
+			return sigType, sig, nil
+		}
+	}
+
+	return 0, lnwire.Sig{}, errNoClosingSig
+}
+
+// rbfProposal is a closing transaction that we proposed as the closer of an
+// RBF coop close.
+type rbfProposal struct {
+	// fee is the fee the closing transaction pays.
+	fee btcutil.Amount
+
+	// feeRate is the fee rate the fee was derived from.
+	feeRate chainfee.SatPerKWeight
+
+	// sequence is the sequence of the input spending the funding output.
+	sequence uint32
+
+	// sigType is the type of closing transaction we signed.
+	sigType closingSigType
+
+	// localSig is our signature for the closing transaction.
+	localSig input.Signature
+}
+
+// RbfCoopClose returns true if the channel is closed using the RBF coop close
+// protocol.
+func (c *ChanCloser) RbfCoopClose() bool {
+	return c.cfg.RbfCoopClose
+}
+
+// IsCloser returns true if we initiated the close, which makes us the party
+// that pays for and proposes the closing transactions of an RBF coop close.
+func (c *ChanCloser) IsCloser() bool {
+	return c.locallyInitiated
+}
+
+// BeginRbfNegotiation is the counterpart of BeginNegotiation for RBF coop
+// closes. If we are the closer, it returns a ClosingComplete message that
+// proposes a closing transaction at our ideal fee rate. If we are the closee
+// and already received the proposal of the closer, a ClosingSig message is
+// returned instead.
+func (c *ChanCloser) BeginRbfNegotiation() (fn.Option[lnwire.Message],
+	error) {
+
+	noMsg := fn.None[lnwire.Message]()
+
+	if !c.cfg.RbfCoopClose {
+		return noMsg, ErrNotRbfCoopClose
+	}
+	if c.state != closeAwaitingFlush {
+		return noMsg, ErrInvalidState
+	}
+
+	// Now that we know their desired delivery script, we can compute what
+	// our max/ideal fee will be.
+	c.initFeeBaseline()
+
+	// Mark the channel as cooperatively closed with a nil txn, just like
+	// the legacy negotiation does.
+	err := c.cfg.Channel.MarkCoopBroadcasted(nil, c.locallyInitiated)
+	if err != nil {
+		return noMsg, err
+	}
+
+	c.state = closeFeeNegotiation
+
+	// As the closee we wait for the proposal of the closer, unless it
+	// already arrived while the channel was being flushed.
+	if !c.locallyInitiated {
+		res := noMsg
+		c.cachedClosingComplete.WhenSome(
+			func(msg lnwire.ClosingComplete) {
+				var oSig fn.Option[lnwire.ClosingSig]
+				oSig, err = c.ReceiveClosingComplete(msg)
+				oSig.WhenSome(func(sig lnwire.ClosingSig) {
+					res = fn.Some[lnwire.Message](&sig)
+				})
+			},
+		)
+
+		return res, err
+	}
+
+	closingComplete, err := c.proposeRbfClose(
+		c.idealFeeSat, c.idealFeeRate,
+	)
+	if err != nil {
+		return noMsg, fmt.Errorf("unable to sign closing tx: %w", err)
+	}
+
+	return fn.Some[lnwire.Message](closingComplete), nil
+}
+
+// ReceiveClosingComplete processes a closing transaction proposed by the
+// closer of an RBF coop close. If it pays a higher fee than the last closing
+// transaction we signed, we sign and broadcast it and return a ClosingSig
+// message so the closer can broadcast it as well.
+func (c *ChanCloser) ReceiveClosingComplete(msg lnwire.ClosingComplete) (
+	fn.Option[lnwire.ClosingSig], error) {
+
+	noSig := fn.None[lnwire.ClosingSig]()
+
+	if !c.cfg.RbfCoopClose {
+		return noSig, ErrNotRbfCoopClose
+	}
+	if c.locallyInitiated {
+		return noSig, ErrNotCloser
+	}
+
+	switch c.state {
+	// If we hit this case our chanCloser received the proposal before the
+	// link finished processing the channel flush, so we'll process it
+	// once the negotiation begins.
+	case closeAwaitingFlush:
+		c.cachedClosingComplete = fn.Some(msg)
+		return noSig, nil
+
+	case closeFeeNegotiation, closeFinished:
+
+	default:
+		return noSig, ErrInvalidState
+	}
+
+	if msg.FeeSatoshis <= c.rbfSignedFee {
+		return noSig, fmt.Errorf("%w: %v <= %v", ErrRbfFeeTooLow,
+			msg.FeeSatoshis, c.rbfSignedFee)
+	}
+	if msg.Sequence > mempool.MaxRBFSequence {
+		return noSig, fmt.Errorf("closing tx sequence %v doesn't "+
+			"signal RBF", msg.Sequence)
+	}
+
+	sigType, remoteWireSig, err := proposedClosingSig(msg.ClosingSigs)
+	if err != nil {
+		return noSig, err
+	}
+	remoteSig, err := remoteWireSig.ToSignature()
+	if err != nil {
+		return noSig, err
+	}
+
+	// The closer pays the full fee of the closing transaction.
+	closeOpts := []lnwallet.ChanCloseOpt{
+		lnwallet.WithCustomCoopFeePayer(false),
+		lnwallet.WithRbfCoopClose(msg.Sequence),
+	}
+	localSig, _, _, err := c.cfg.Channel.CreateCloseProposal(
+		msg.FeeSatoshis, c.localDeliveryScript, c.remoteDeliveryScript,
+		closeOpts...,
+	)
+	if err != nil {
+		return noSig, err
+	}
+	localWireSig, err := lnwire.NewSigFromSignature(localSig)
+	if err != nil {
+		return noSig, err
+	}
+
+	closeTx, _, err := c.cfg.Channel.CompleteCooperativeClose(
+		localSig, remoteSig, c.localDeliveryScript,
+		c.remoteDeliveryScript, msg.FeeSatoshis, closeOpts...,
+	)
+	if err != nil {
+		return noSig, err
+	}
+
+	chancloserLog.Infof("ChannelPoint(%v): signing closing tx with fee "+
+		"of %v proposed by closer", c.chanPoint, msg.FeeSatoshis)
+
+	if err := c.broadcastRbfClose(closeTx); err != nil {
+		return noSig, err
+	}
+	c.rbfSignedFee = msg.FeeSatoshis
+
+	return fn.Some(lnwire.ClosingSig{
+		ChannelID:   c.cid,
+		ClosingSigs: newClosingSigs(sigType, localWireSig),
+	}), nil
+}
+
+// ReceiveClosingSig processes the signature of the closee for the last closing
+// transaction we proposed as the closer of an RBF coop close, and broadcasts
+// the fully signed closing transaction.
+func (c *ChanCloser) ReceiveClosingSig(msg lnwire.ClosingSig) error {
+	if !c.cfg.RbfCoopClose {
+		return ErrNotRbfCoopClose
+	}
+	if !c.locallyInitiated {
+		return ErrNotCloser
+	}
+
+	proposal, err := c.rbfProposal.UnwrapOrErr(ErrInvalidState)
+	if err != nil {
+		return err
+	}
+
+	remoteWireSig, err := closingSig(msg.ClosingSigs, proposal.sigType)
+	if err != nil {
+		return err
+	}
+	remoteSig, err := remoteWireSig.ToSignature()
+	if err != nil {
+		return err
+	}
+
+	closeTx, _, err := c.cfg.Channel.CompleteCooperativeClose(
+		proposal.localSig, remoteSig, c.localDeliveryScript,
+		c.remoteDeliveryScript, proposal.fee,
+		lnwallet.WithCustomCoopFeePayer(true),
+		lnwallet.WithRbfCoopClose(proposal.sequence),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := c.broadcastRbfClose(closeTx); err != nil {
+		return err
+	}
+
+	c.rbfProposal = fn.None[rbfProposal]()
+	c.rbfSignedFee = proposal.fee
+	c.rbfFeeRate = proposal.feeRate
+
+	return nil
+}
+
+// BumpFee proposes a closing transaction that replaces the last one we
+// broadcast as the closer of an RBF coop close. If the passed fee rate is
+// zero, the fee rate is increased according to the fee escalation schedule,
+// up to the max fee of the close. Otherwise, the passed fee rate is used,
+// which must result in a higher fee than that of the last closing
+// transaction.
+func (c *ChanCloser) BumpFee(feeRate chainfee.SatPerKWeight) (
+	*lnwire.ClosingComplete, error) {
+
+	if !c.cfg.RbfCoopClose {
+		return nil, ErrNotRbfCoopClose
+	}
+	if !c.locallyInitiated {
+		return nil, ErrNotCloser
+	}
+	if c.state != closeFinished {
+		return nil, ErrInvalidState
+	}
+	if c.rbfProposal.IsSome() {
+		return nil, ErrRbfProposalPending
+	}
+
+	// A replacement must at least pay for its own relay on top of the fee
+	// of the transaction it replaces.
+	minFee := c.rbfSignedFee + c.rbfFee(chainfee.FeePerKwFloor)
+
+	// A manual fee bump uses the passed fee rate as is.
+	if feeRate != 0 {
+		fee := c.rbfFee(feeRate)
+		if fee < minFee {
+			return nil, fmt.Errorf("%w: %v < %v", ErrRbfFeeTooLow,
+				fee, minFee)
+		}
+
+		return c.proposeRbfClose(fee, feeRate)
+	}
+
+	maxFee := c.maxFee
+	if c.cfg.RbfMaxFeeRate != 0 {
+		maxFee = c.rbfFee(c.cfg.RbfMaxFeeRate)
+	}
+	if minFee > maxFee {
+		return nil, ErrMaxRbfFeeReached
+	}
+
+	increase := chainfee.SatPerKWeight(100 + c.cfg.RbfFeeRateIncrease)
+	feeRate = c.rbfFeeRate * increase / 100
+	fee := c.rbfFee(feeRate)
+
+	switch {
+	case fee < minFee:
+		fee = minFee
+
+	case fee > maxFee:
+		fee = maxFee
+	}
+
+	return c.proposeRbfClose(fee, feeRate)
+}
+
+// rbfFee returns the fee the closing transaction pays at the given fee rate.
+func (c *ChanCloser) rbfFee(feeRate chainfee.SatPerKWeight) btcutil.Amount {
+	localTxOut, remoteTxOut := c.closeTxOuts()
+
+	return c.cfg.FeeEstimator.EstimateFee(
+		0, localTxOut, remoteTxOut, feeRate,
+	)
+}
+
+// proposeRbfClose signs a closing transaction paying the given fee, which we
+// pay in full as the closer, and returns the ClosingComplete message that
+// proposes it to the closee.
+func (c *ChanCloser) proposeRbfClose(fee btcutil.Amount,
+	feeRate chainfee.SatPerKWeight) (*lnwire.ClosingComplete, error) {
+
+	sequence := uint32(mempool.MaxRBFSequence)
+	rawSig, _, _, err := c.cfg.Channel.CreateCloseProposal(
+		fee, c.localDeliveryScript, c.remoteDeliveryScript,
+		lnwallet.WithCustomCoopFeePayer(true),
+		lnwallet.WithRbfCoopClose(sequence),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		return nil, err
+	}
+
+	// The output of the closee is left out if its balance is dust.
+	sigType := closerAndClosee
+	if c.cfg.Channel.RemoteBalanceDust() {
+		sigType = closerNoClosee
+	}
+
+	c.rbfProposal = fn.Some(rbfProposal{
+		fee:      fee,
+		feeRate:  feeRate,
+		sequence: sequence,
+		sigType:  sigType,
+		localSig: rawSig,
+	})
+
+	chancloserLog.Infof("ChannelPoint(%v): proposing closing tx with fee "+
+		"of %v sat (fee_rate=%v)", c.chanPoint, int64(fee), feeRate)
+
+	return &lnwire.ClosingComplete{
+		ChannelID:   c.cid,
+		FeeSatoshis: fee,
+		Sequence:    sequence,
+		ClosingSigs: newClosingSigs(sigType, sig),
+	}, nil
+}
+
+// broadcastRbfClose persists and broadcasts a fully signed closing transaction
+// of an RBF coop close, which replaces any closing transaction broadcast
+// before.
+func (c *ChanCloser) broadcastRbfClose(closeTx *wire.MsgTx) error {
+	// Before publishing the closing tx, we persist it to the database,
+	// such that it can be republished if something goes wrong.
+	err := c.cfg.Channel.MarkCoopBroadcasted(closeTx, c.locallyInitiated)
+	if err != nil {
+		return err
+	}
+
+	chancloserLog.Infof("Broadcasting RBF cooperative close tx: %v",
+		newLogClosure(func() string {
+			return spew.Sdump(closeTx)
+		}),
+	)
+
+	chanID := c.cfg.Channel.ShortChanID()
+	closeLabel := labels.MakeChannelLabel(
+		labels.LabelTypeChannelClose, &chanID,
+		c.cfg.Channel.ChannelPoint(), c.cfg.PeerAlias,
+	)
+	if err := c.cfg.BroadcastTx(closeTx, closeLabel); err != nil {
+		return err
+	}
+
+	c.closingTx = closeTx
+	c.state = closeFinished
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package chancloser
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRbfChannel is a mock segwit v0 channel that produces ECDSA signatures,
+// and closing transactions whose lock time is the fee they pay.
+type mockRbfChannel struct {
+	mockChannel
+
+	sig input.Signature
+}
+
+func newMockRbfChannel(t *testing.T, initiator bool) *mockRbfChannel {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &mockRbfChannel{
+		mockChannel: mockChannel{
+			initiator: initiator,
+		},
+		sig: ecdsa.Sign(privKey, chainhash.HashB([]byte("close"))),
+	}
+}
+
+func (m *mockRbfChannel) CreateCloseProposal(btcutil.Amount, []byte, []byte,
+	...lnwallet.ChanCloseOpt) (input.Signature, *chainhash.Hash,
+	btcutil.Amount, error) {
+
+	return m.sig, nil, 0, nil
+}
+
+func (m *mockRbfChannel) CompleteCooperativeClose(_, _ input.Signature, _,
+	_ []byte, proposedFee btcutil.Amount,
+	_ ...lnwallet.ChanCloseOpt) (*wire.MsgTx, btcutil.Amount, error) {
+
+	return &wire.MsgTx{LockTime: uint32(proposedFee)}, 0, nil
+}
+
+// newRbfTestCloser creates a chan closer for an RBF coop close whose broadcast
+// closing transactions are sent over the returned channel.
+func newRbfTestCloser(t *testing.T, closer bool,
+	maxFeeRate chainfee.SatPerKWeight) (*ChanCloser, chan *wire.MsgTx) {
+
+	broadcasts := make(chan *wire.MsgTx, 10)
+	chanCloser := NewChanCloser(
+		ChanCloseCfg{
+			Channel: newMockRbfChannel(t, true),
+			BroadcastTx: func(tx *wire.MsgTx, _ string) error {
+				broadcasts <- tx
+				return nil
+			},
+			DisableChannel: func(wire.OutPoint) error {
+				return nil
+			},
+			FeeEstimator:       &SimpleCoopFeeEstimator{},
+			RbfCoopClose:       true,
+			RbfFeeRateIncrease: 50,
+			RbfMaxFeeRate:      maxFeeRate,
+		}, nil, chainfee.FeePerKwFloor, 0, nil, closer,
+	)
+
+	return chanCloser, broadcasts
+}
+
+// TestRbfCoopClose tests that the closer of an RBF coop close can bump the fee
+// of the closing transaction until the max fee rate is reached, and that the
+// closee only signs replacements that pay a higher fee.
+func TestRbfCoopClose(t *testing.T) {
+	t.Parallel()
+
+	const maxFeeRate = chainfee.FeePerKwFloor * 4
+
+	alice, aliceBroadcasts := newRbfTestCloser(t, true, maxFeeRate)
+	bob, bobBroadcasts := newRbfTestCloser(t, false, 0)
+
+	// Alice initiates the close, and Bob responds without a proposal as
+	// he's the closee.
+	shutdown, err := alice.ShutdownChan()
+	require.NoError(t, err)
+
+	oShutdown, err := bob.ReceiveShutdown(*shutdown)
+	require.NoError(t, err)
+	oMsg, err := bob.BeginRbfNegotiation()
+	require.NoError(t, err)
+	require.True(t, oMsg.IsNone())
+
+	_, err = alice.ReceiveShutdown(oShutdown.UnwrapOrFail(t))
+	require.NoError(t, err)
+	oMsg, err = alice.BeginRbfNegotiation()
+	require.NoError(t, err)
+
+	closingComplete, ok := oMsg.UnwrapOrFail(t).(*lnwire.ClosingComplete)
+	require.True(t, ok)
+	require.Equal(t, alice.idealFeeSat, closingComplete.FeeSatoshis)
+
+	// The legacy fee negotiation is refused.
+	_, err = bob.ReceiveClosingSigned(lnwire.ClosingSigned{})
+	require.ErrorIs(t, err, ErrInvalidState)
+
+	// signRound has Bob sign the proposal of Alice and both of them
+	// broadcast the closing transaction.
+	signRound := func(msg *lnwire.ClosingComplete) {
+		t.Helper()
+
+		oSig, err := bob.ReceiveClosingComplete(*msg)
+		require.NoError(t, err)
+		require.Equal(
+			t, uint32(msg.FeeSatoshis), (<-bobBroadcasts).LockTime,
+		)
+
+		err = alice.ReceiveClosingSig(oSig.UnwrapOrFail(t))
+		require.NoError(t, err)
+		require.Equal(
+			t, uint32(msg.FeeSatoshis),
+			(<-aliceBroadcasts).LockTime,
+		)
+	}
+	signRound(closingComplete)
+
+	// Only the closer can bump the fee.
+	_, err = bob.BumpFee(0)
+	require.ErrorIs(t, err, ErrNotCloser)
+
+	// A scheduled bump increases the fee rate by 50%.
+	bump, err := alice.BumpFee(0)
+	require.NoError(t, err)
+	require.Greater(t, bump.FeeSatoshis, closingComplete.FeeSatoshis)
+	proposal := alice.rbfProposal.UnwrapOrFail(t)
+	require.Equal(t, alice.rbfFeeRate*3/2, proposal.feeRate)
+
+	// Another bump has to wait for Bob's signature.
+	_, err = alice.BumpFee(0)
+	require.ErrorIs(t, err, ErrRbfProposalPending)
+
+	// Bob refuses to sign a closing transaction that doesn't pay more than
+	// the one he already signed.
+	_, err = bob.ReceiveClosingComplete(*closingComplete)
+	require.ErrorIs(t, err, ErrRbfFeeTooLow)
+
+	signRound(bump)
+
+	// A manual bump must pay more than the last closing transaction.
+	_, err = alice.BumpFee(chainfee.FeePerKwFloor)
+	require.ErrorIs(t, err, ErrRbfFeeTooLow)
+
+	// Scheduled bumps stop at the max fee rate, while a manual bump can
+	// still go beyond it.
+	maxFee := alice.rbfFee(maxFeeRate)
+	for i := 0; ; i++ {
+		require.Less(t, i, 10, "max fee not reached")
+
+		bump, err = alice.BumpFee(0)
+		if err != nil {
+			require.ErrorIs(t, err, ErrMaxRbfFeeReached)
+			break
+		}
+		require.LessOrEqual(t, bump.FeeSatoshis, maxFee)
+
+		signRound(bump)
+	}
+	require.LessOrEqual(t, alice.rbfSignedFee, maxFee)
+	require.Greater(t, alice.rbfSignedFee, closingComplete.FeeSatoshis*3)
+
+	bump, err = alice.BumpFee(maxFeeRate * 2)
+	require.NoError(t, err)
+	signRound(bump)
+
+	tx, err := alice.ClosingTx()
+	require.NoError(t, err)
+	require.Equal(t, uint32(bump.FeeSatoshis), tx.LockTime)
+}
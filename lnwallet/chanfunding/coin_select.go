@@ -165,6 +165,11 @@ func CoinSelect(feeRate chainfee.SatPerKWeight, amt, dustLimit btcutil.Amount,
 	existingWeight input.TxWeightEstimator,
 	changeType ChangeAddressType) ([]wallet.Coin, btcutil.Amount, error) {
 
+	// A no-change strategy needs to know the amount and weight of the
+	// transaction it funds. A remainder below the dust limit is added to
+	// the fee.
+	strategy = WithTarget(strategy, amt, existingWeight, dustLimit)
+
 	amtNeeded := amt
 	for {
 		// First perform an initial round of coin selection to estimate
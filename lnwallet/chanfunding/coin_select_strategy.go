@@ -0,0 +1,281 @@
+package chanfunding
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// maxNoChangeTries is the maximum number of coin combinations the no-change
+// coin selector tries before giving up.
+const maxNoChangeTries = 100_000
+
+var (
+	// CoinSelectionSmallest always picks the smallest available utxo that
+	// still pays for itself to add to the transaction next. This strategy
+	// consolidates small utxos over time.
+	CoinSelectionSmallest wallet.CoinSelectionStrategy = &SmallestFirstCoinSelector{} //nolint:lll
+
+	// CoinSelectionOldest picks the utxo with the most confirmations to add
+	// to the transaction next. The confirmations of the coins must be set
+	// with WithConfirmations before coins are arranged.
+	CoinSelectionOldest wallet.CoinSelectionStrategy = &OldestFirstCoinSelector{} //nolint:lll
+
+	// CoinSelectionNoChange only picks a combination of utxos that funds
+	// the transaction without creating a change output, which doesn't
+	// reveal which output is the payment. The amount to fund must be set
+	// with WithTarget before coins are arranged.
+	CoinSelectionNoChange wallet.CoinSelectionStrategy = &NoChangeCoinSelector{} //nolint:lll
+
+	// ErrConfirmationsUnknown is returned when coins are arranged oldest
+	// first without knowing their confirmations.
+	ErrConfirmationsUnknown = errors.New("oldest-first coin selection " +
+		"requires the confirmations of the coins")
+
+	// ErrTargetUnknown is returned when coins are arranged to avoid change
+	// without knowing the amount to fund.
+	ErrTargetUnknown = errors.New("no-change coin selection requires " +
+		"the amount to fund")
+
+	// ErrNoChangeSelection is returned when no combination of coins funds
+	// the transaction without creating a change output.
+	ErrNoChangeSelection = errors.New("no combination of coins funds the " +
+		"transaction without change")
+)
+
+// SmallestFirstCoinSelector is an implementation of the CoinSelectionStrategy
+// that selects the smallest coins first, skipping coins that cost more to
+// spend than they are worth.
+type SmallestFirstCoinSelector struct{}
+
+// ArrangeCoins takes a list of coins and arranges them according to the
+// specified coin selection strategy and fee rate.
+func (*SmallestFirstCoinSelector) ArrangeCoins(eligible []wallet.Coin,
+	feeSatPerKb btcutil.Amount) ([]wallet.Coin, error) {
+
+	feeRate := chainfee.SatPerKVByte(feeSatPerKb).FeePerKWeight()
+
+	arranged := make([]wallet.Coin, 0, len(eligible))
+	for _, coin := range eligible {
+		if effectiveValue(coin, feeRate) <= 0 {
+			continue
+		}
+
+		arranged = append(arranged, coin)
+	}
+
+	sort.SliceStable(arranged, func(i, j int) bool {
+		return arranged[i].Value < arranged[j].Value
+	})
+
+	return arranged, nil
+}
+
+// OldestFirstCoinSelector is an implementation of the CoinSelectionStrategy
+// that selects the coins with the most confirmations first. Coins with the
+// same number of confirmations are selected largest first.
+type OldestFirstCoinSelector struct {
+	// Confirmations maps the outpoints of the coins to their number of
+	// confirmations. Coins that aren't in the map are treated as
+	// unconfirmed.
+	Confirmations map[wire.OutPoint]int64
+}
+
+// ArrangeCoins takes a list of coins and arranges them according to the
+// specified coin selection strategy and fee rate.
+func (o *OldestFirstCoinSelector) ArrangeCoins(eligible []wallet.Coin,
+	_ btcutil.Amount) ([]wallet.Coin, error) {
+
+	if o.Confirmations == nil {
+		return nil, ErrConfirmationsUnknown
+	}
+
+	arranged := make([]wallet.Coin, len(eligible))
+	copy(arranged, eligible)
+
+	sort.SliceStable(arranged, func(i, j int) bool {
+		confsI := o.Confirmations[arranged[i].OutPoint]
+		confsJ := o.Confirmations[arranged[j].OutPoint]
+		if confsI != confsJ {
+			return confsI > confsJ
+		}
+
+		return arranged[i].Value > arranged[j].Value
+	})
+
+	return arranged, nil
+}
+
+// NoChangeCoinSelector is an implementation of the CoinSelectionStrategy that
+// only selects a combination of coins whose value is just enough to fund the
+// transaction and its fee, so the remainder is too small for a change output.
+type NoChangeCoinSelector struct {
+	// Target is the amount the transaction pays out, excluding fees.
+	Target btcutil.Amount
+
+	// Weight is the weight of the transaction without any of the coins
+	// added as inputs.
+	Weight input.TxWeightEstimator
+
+	// DustLimit is the amount below which a remainder is added to the fee
+	// instead of being paid to a change output.
+	DustLimit btcutil.Amount
+}
+
+// ArrangeCoins takes a list of coins and arranges them according to the
+// specified coin selection strategy and fee rate. Only the selected coins are
+// returned, so the coins can't be topped up with a change creating coin.
+func (n *NoChangeCoinSelector) ArrangeCoins(eligible []wallet.Coin,
+	feeSatPerKb btcutil.Amount) ([]wallet.Coin, error) {
+
+	if n.Target == 0 {
+		return nil, ErrTargetUnknown
+	}
+
+	feeRate := chainfee.SatPerKVByte(feeSatPerKb).FeePerKWeight()
+	lowerBound := n.Target + feeRate.FeeForWeight(n.Weight.Weight())
+	upperBound := lowerBound + n.DustLimit
+
+	// We search the coins largest effective value first, skipping those
+	// that don't pay for themselves.
+	type candidate struct {
+		coin  wallet.Coin
+		value btcutil.Amount
+	}
+	var (
+		candidates []candidate
+		remaining  btcutil.Amount
+	)
+	for _, coin := range eligible {
+		value := effectiveValue(coin, feeRate)
+		if value <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{coin, value})
+		remaining += value
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].value > candidates[j].value
+	})
+
+	// suffix[i] is the total value of the candidates from index i on,
+	// which lets us prune branches that can't reach the lower bound.
+	suffix := make([]btcutil.Amount, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		suffix[i] = suffix[i+1] + candidates[i].value
+	}
+
+	var (
+		selected []int
+		tries    int
+		search   func(idx int, total btcutil.Amount) bool
+	)
+	search = func(idx int, total btcutil.Amount) bool {
+		tries++
+
+		switch {
+		case total >= upperBound:
+			return false
+
+		case total >= lowerBound:
+			return true
+
+		case idx == len(candidates), tries > maxNoChangeTries,
+			total+suffix[idx] < lowerBound:
+
+			return false
+		}
+
+		selected = append(selected, idx)
+		if search(idx+1, total+candidates[idx].value) {
+			return true
+		}
+		selected = selected[:len(selected)-1]
+
+		return search(idx+1, total)
+	}
+
+	if !search(0, 0) {
+		return nil, ErrNoChangeSelection
+	}
+
+	arranged := make([]wallet.Coin, 0, len(selected))
+	for _, idx := range selected {
+		arranged = append(arranged, candidates[idx].coin)
+	}
+
+	return arranged, nil
+}
+
+// WithConfirmations returns the given strategy with the confirmations of the
+// coins set if it arranges coins oldest first. The confirmations are only
+// fetched if they are needed.
+func WithConfirmations(strategy wallet.CoinSelectionStrategy,
+	fetchConfs func() (map[wire.OutPoint]int64, error)) (
+	wallet.CoinSelectionStrategy, error) {
+
+	oldest, ok := strategy.(*OldestFirstCoinSelector)
+	if !ok || oldest.Confirmations != nil {
+		return strategy, nil
+	}
+
+	confs, err := fetchConfs()
+	if err != nil {
+		return nil, err
+	}
+
+	return &OldestFirstCoinSelector{
+		Confirmations: confs,
+	}, nil
+}
+
+// WithTarget returns the given strategy with the amount to fund set if it
+// avoids change. The weight is the weight of the transaction without any
+// inputs that are selected.
+func WithTarget(strategy wallet.CoinSelectionStrategy, target btcutil.Amount,
+	weight input.TxWeightEstimator,
+	dustLimit btcutil.Amount) wallet.CoinSelectionStrategy {
+
+	noChange, ok := strategy.(*NoChangeCoinSelector)
+	if !ok || noChange.Target != 0 {
+		return strategy
+	}
+
+	return &NoChangeCoinSelector{
+		Target:    target,
+		Weight:    weight,
+		DustLimit: dustLimit,
+	}
+}
+
+// effectiveValue returns the value of the coin minus the fee it costs to spend
+// it at the given fee rate.
+func effectiveValue(coin wallet.Coin,
+	feeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	var weightEstimate input.TxWeightEstimator
+	switch {
+	case txscript.IsPayToTaproot(coin.PkScript):
+		weightEstimate.AddTaprootKeySpendInput(txscript.SigHashDefault)
+
+	case txscript.IsPayToScriptHash(coin.PkScript):
+		weightEstimate.AddNestedP2WKHInput()
+
+	default:
+		weightEstimate.AddP2WKHInput()
+	}
+
+	// The estimator includes the transaction overhead, which we don't want
+	// to attribute to a single input.
+	var overhead input.TxWeightEstimator
+	inputWeight := weightEstimate.Weight() - overhead.Weight()
+
+	return btcutil.Amount(coin.Value) - feeRate.FeeForWeight(inputWeight)
+}
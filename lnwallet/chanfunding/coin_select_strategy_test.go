@@ -0,0 +1,137 @@
+package chanfunding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// strategyTestCoins returns P2WKH coins with the given values, whose outpoint
+// index is their position.
+func strategyTestCoins(values ...btcutil.Amount) []wallet.Coin {
+	coins := make([]wallet.Coin, len(values))
+	for i, value := range values {
+		coins[i] = wallet.Coin{
+			TxOut: wire.TxOut{
+				Value:    int64(value),
+				PkScript: p2wkhScript,
+			},
+			OutPoint: wire.OutPoint{Index: uint32(i)},
+		}
+	}
+
+	return coins
+}
+
+// coinValues returns the values of the given coins.
+func coinValues(coins []wallet.Coin) []btcutil.Amount {
+	values := make([]btcutil.Amount, len(coins))
+	for i, coin := range coins {
+		values[i] = btcutil.Amount(coin.Value)
+	}
+
+	return values
+}
+
+// TestSmallestFirstCoinSelector tests that coins are arranged smallest first
+// and that coins which don't pay for themselves are skipped.
+func TestSmallestFirstCoinSelector(t *testing.T) {
+	t.Parallel()
+
+	// At 10 sat/vbyte, spending a P2WKH input costs about 680 sats.
+	feeSatPerKb := btcutil.Amount(10_000)
+	coins := strategyTestCoins(50_000, 500, 2_000, 100_000)
+
+	arranged, err := CoinSelectionSmallest.ArrangeCoins(coins, feeSatPerKb)
+	require.NoError(t, err)
+	require.Equal(
+		t, []btcutil.Amount{2_000, 50_000, 100_000},
+		coinValues(arranged),
+	)
+}
+
+// TestOldestFirstCoinSelector tests that coins are arranged by their
+// confirmations once they are known.
+func TestOldestFirstCoinSelector(t *testing.T) {
+	t.Parallel()
+
+	coins := strategyTestCoins(1_000, 2_000, 3_000, 4_000)
+
+	_, err := CoinSelectionOldest.ArrangeCoins(coins, 0)
+	require.ErrorIs(t, err, ErrConfirmationsUnknown)
+
+	strategy, err := WithConfirmations(
+		CoinSelectionOldest, func() (map[wire.OutPoint]int64, error) {
+			return map[wire.OutPoint]int64{
+				coins[0].OutPoint: 10,
+				coins[1].OutPoint: 100,
+				coins[3].OutPoint: 10,
+			}, nil
+		},
+	)
+	require.NoError(t, err)
+
+	arranged, err := strategy.ArrangeCoins(coins, 0)
+	require.NoError(t, err)
+	require.Equal(
+		t, []btcutil.Amount{2_000, 4_000, 1_000, 3_000},
+		coinValues(arranged),
+	)
+
+	// Other strategies don't need the confirmations.
+	strategy, err = WithConfirmations(
+		wallet.CoinSelectionLargest,
+		func() (map[wire.OutPoint]int64, error) {
+			t.Fatalf("confirmations fetched")
+			return nil, nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, wallet.CoinSelectionLargest, strategy)
+}
+
+// TestNoChangeCoinSelector tests that only a combination of coins that funds
+// the target without change is selected.
+func TestNoChangeCoinSelector(t *testing.T) {
+	t.Parallel()
+
+	feeRate := chainfee.SatPerKWeight(250)
+	feeSatPerKb := btcutil.Amount(feeRate.FeePerKVByte())
+	coins := strategyTestCoins(70_000, 30_000, 45_000, 20_500, 10_000)
+
+	var weight input.TxWeightEstimator
+	weight.AddP2WSHOutput()
+
+	_, err := CoinSelectionNoChange.ArrangeCoins(coins, feeSatPerKb)
+	require.ErrorIs(t, err, ErrTargetUnknown)
+
+	// Only 30_000 + 20_500 covers the target and the fee of two inputs
+	// without leaving more than the dust limit.
+	strategy := WithTarget(CoinSelectionNoChange, 50_000, weight, 330)
+	arranged, err := strategy.ArrangeCoins(coins, feeSatPerKb)
+	require.NoError(t, err)
+	require.ElementsMatch(
+		t, []btcutil.Amount{30_000, 20_500}, coinValues(arranged),
+	)
+
+	// No combination leaves less than the dust limit for this target.
+	strategy = WithTarget(CoinSelectionNoChange, 52_000, weight, 330)
+	_, err = strategy.ArrangeCoins(coins, feeSatPerKb)
+	require.ErrorIs(t, err, ErrNoChangeSelection)
+
+	// Coin selection for a channel funding doesn't create change either.
+	selected, changeAmt, err := CoinSelect(
+		feeRate, 50_000, 330, coins, CoinSelectionNoChange, weight,
+		defaultChanFundingChangeType,
+	)
+	require.NoError(t, err)
+	require.Zero(t, changeAmt)
+	require.ElementsMatch(
+		t, []btcutil.Amount{30_000, 20_500}, coinValues(selected),
+	)
+}
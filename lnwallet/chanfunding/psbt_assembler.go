@@ -113,6 +113,11 @@ type PsbtIntent struct {
 	// for the funding transaction.
 	BasePsbt *psbt.Packet
 
+	// PsbtVersion is the version of the PSBT format the funding PSBT is
+	// handed to the user in. It matches the version of the base PSBT, so
+	// users that supplied a version 2 PSBT get one back.
+	PsbtVersion uint32
+
 	// PendingPsbt is the parsed version of the current PSBT. This can be
 	// in two stages: If the user has not yet provided any PSBT, this is
 	// nil. Once the user sends us an unsigned funded PSBT, we verify that
@@ -486,6 +491,10 @@ type PsbtAssembler struct {
 	// added to.
 	basePsbt *psbt.Packet
 
+	// psbtVersion is the version of the PSBT format the funding PSBT is
+	// handed to the user in.
+	psbtVersion uint32
+
 	// netParams are the network parameters used to encode the P2WSH funding
 	// address.
 	netParams *chaincfg.Params
@@ -500,11 +509,13 @@ type PsbtAssembler struct {
 // be supplied which will be used to add the channel output to instead of
 // creating a new one.
 func NewPsbtAssembler(fundingAmt btcutil.Amount, basePsbt *psbt.Packet,
-	netParams *chaincfg.Params, shouldPublish bool) *PsbtAssembler {
+	psbtVersion uint32, netParams *chaincfg.Params,
+	shouldPublish bool) *PsbtAssembler {
 
 	return &PsbtAssembler{
 		fundingAmt:    fundingAmt,
 		basePsbt:      basePsbt,
+		psbtVersion:   psbtVersion,
 		netParams:     netParams,
 		shouldPublish: shouldPublish,
 	}
@@ -537,6 +548,7 @@ func (p *PsbtAssembler) ProvisionChannel(req *Request) (Intent, error) {
 		},
 		State:         PsbtShimRegistered,
 		BasePsbt:      p.basePsbt,
+		PsbtVersion:   p.psbtVersion,
 		PsbtReady:     make(chan error, 1),
 		shouldPublish: p.shouldPublish,
 		netParams:     p.netParams,
@@ -36,7 +36,7 @@ func TestPsbtIntent(t *testing.T) {
 
 	// Create a simple assembler and ask it to provision a channel to get
 	// the funding intent.
-	a := NewPsbtAssembler(chanCapacity, nil, &params, true)
+	a := NewPsbtAssembler(chanCapacity, nil, 0, &params, true)
 	intent, err := a.ProvisionChannel(&Request{LocalAmt: chanCapacity})
 	require.NoError(t, err, "error provisioning channel")
 	psbtIntent, ok := intent.(*PsbtIntent)
@@ -197,7 +197,7 @@ func TestPsbtIntentBasePsbt(t *testing.T) {
 
 	// Now as the next step, create a new assembler/intent pair with a base
 	// PSBT to see that we can add an additional output to it.
-	a := NewPsbtAssembler(chanCapacity, pendingPsbt, &params, true)
+	a := NewPsbtAssembler(chanCapacity, pendingPsbt, 0, &params, true)
 	intent, err := a.ProvisionChannel(&Request{LocalAmt: chanCapacity})
 	require.NoError(t, err, "error provisioning channel")
 	psbtIntent, ok := intent.(*PsbtIntent)
@@ -442,7 +442,7 @@ func TestPsbtVerify(t *testing.T) {
 
 	// Create a simple assembler and ask it to provision a channel to get
 	// the funding intent.
-	a := NewPsbtAssembler(chanCapacity, nil, &params, true)
+	a := NewPsbtAssembler(chanCapacity, nil, 0, &params, true)
 	intent, err := a.ProvisionChannel(&Request{LocalAmt: chanCapacity})
 	require.NoError(t, err, "error provisioning channel")
 	psbtIntent := intent.(*PsbtIntent)
@@ -608,7 +608,7 @@ func TestPsbtFinalize(t *testing.T) {
 
 	// Create a simple assembler and ask it to provision a channel to get
 	// the funding intent.
-	a := NewPsbtAssembler(chanCapacity, nil, &params, true)
+	a := NewPsbtAssembler(chanCapacity, nil, 0, &params, true)
 	intent, err := a.ProvisionChannel(&Request{LocalAmt: chanCapacity})
 	require.NoError(t, err, "error provisioning channel")
 	psbtIntent := intent.(*PsbtIntent)
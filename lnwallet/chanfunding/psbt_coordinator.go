@@ -0,0 +1,424 @@
+package chanfunding
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ContributorState is the state of a single contributor to a channel funding
+// transaction that is assembled from the inputs of several parties.
+type ContributorState uint8
+
+const (
+	// ContributorPending denotes that the contributor hasn't added its
+	// inputs to the funding transaction yet.
+	ContributorPending ContributorState = iota
+
+	// ContributorFunded denotes that the contributor added its inputs to
+	// the funding transaction, but hasn't signed them yet.
+	ContributorFunded
+
+	// ContributorSigned denotes that the contributor signed all of its
+	// inputs of the funding transaction.
+	ContributorSigned
+)
+
+// String returns a string representation of the ContributorState.
+func (s ContributorState) String() string {
+	switch s {
+	case ContributorPending:
+		return "pending"
+
+	case ContributorFunded:
+		return "funded"
+
+	case ContributorSigned:
+		return "signed"
+
+	default:
+		return fmt.Sprintf("<unknown(%d)>", s)
+	}
+}
+
+var (
+	// ErrUnknownContributor is returned if a contribution is made by a
+	// party that wasn't registered as a contributor of the channel.
+	ErrUnknownContributor = errors.New("unknown funding contributor")
+
+	// ErrContributionsExpired is returned if not all contributors added
+	// their signed inputs to the funding transaction in time.
+	ErrContributionsExpired = errors.New("funding contributions timed " +
+		"out")
+)
+
+// ContributorStatus is the status of a single contributor to a channel
+// funding transaction.
+type ContributorStatus struct {
+	// ID identifies the contributor.
+	ID string
+
+	// State is the state of the contributor.
+	State ContributorState
+
+	// Inputs are the inputs the contributor added to the funding
+	// transaction.
+	Inputs []wire.OutPoint
+}
+
+// contributor tracks the contribution of a single party to a channel funding
+// transaction.
+type contributor struct {
+	// state is the state of the contributor.
+	state ContributorState
+
+	// contribution is the PSBT with the inputs and optional change outputs
+	// of the contributor.
+	contribution *psbt.Packet
+
+	// signedInputs are the signed inputs of the contributor, with the
+	// same order as the inputs of the contribution.
+	signedInputs []psbt.PInput
+}
+
+// PsbtCoordinator assembles the PSBT that funds a channel from the inputs of
+// several contributors. Every contributor first adds its inputs and optional
+// change outputs to the base PSBT that contains the channel funding output.
+// Once all contributors did, the combined PSBT is handed out to the
+// contributors to sign their inputs. The signatures are then collected until
+// the funding transaction is fully signed. The resulting PSBTs are meant to
+// be passed to the Verify and Finalize steps of the PsbtIntent of the channel.
+type PsbtCoordinator struct {
+	// basePsbt is the PSBT containing the channel funding output the
+	// contributions are added to.
+	basePsbt *psbt.Packet
+
+	// ids are the IDs of the contributors in the order their
+	// contributions are added to the funding transaction.
+	ids []string
+
+	// contributors are the contributors, indexed by their ID.
+	contributors map[string]*contributor
+
+	// deadline is the time by which all contributions need to be made.
+	deadline time.Time
+
+	// expired is set once the deadline passed before the funding
+	// transaction was fully signed.
+	expired bool
+
+	sync.Mutex
+}
+
+// NewPsbtCoordinator creates a new coordinator that adds the contributions of
+// the given contributors to the base PSBT, which must already contain the
+// channel funding output.
+func NewPsbtCoordinator(basePsbt *psbt.Packet, contributorIDs []string,
+	deadline time.Time) (*PsbtCoordinator, error) {
+
+	if basePsbt == nil || len(basePsbt.UnsignedTx.TxOut) == 0 {
+		return nil, fmt.Errorf("base PSBT must contain the funding " +
+			"output")
+	}
+	if len(contributorIDs) == 0 {
+		return nil, fmt.Errorf("at least one contributor required")
+	}
+
+	contributors := make(map[string]*contributor, len(contributorIDs))
+	for _, id := range contributorIDs {
+		if _, ok := contributors[id]; ok {
+			return nil, fmt.Errorf("duplicate contributor %q", id)
+		}
+		contributors[id] = &contributor{}
+	}
+
+	return &PsbtCoordinator{
+		basePsbt:     basePsbt,
+		ids:          contributorIDs,
+		contributors: contributors,
+		deadline:     deadline,
+	}, nil
+}
+
+// Deadline returns the time by which all contributions need to be made.
+func (c *PsbtCoordinator) Deadline() time.Time {
+	return c.deadline
+}
+
+// Expire marks the contributions as timed out, unless the funding
+// transaction was already fully signed. It returns true if the contributions
+// were expired.
+func (c *PsbtCoordinator) Expire() bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.allInState(ContributorSigned) {
+		return false
+	}
+	c.expired = true
+
+	return true
+}
+
+// contributor returns the contributor with the given ID, making sure the
+// contributions didn't expire yet.
+//
+// NOTE: The caller must hold the coordinator's lock.
+func (c *PsbtCoordinator) contributor(id string) (*contributor, error) {
+	if c.expired {
+		return nil, ErrContributionsExpired
+	}
+
+	contrib, ok := c.contributors[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownContributor, id)
+	}
+
+	return contrib, nil
+}
+
+// allInState returns true if all contributors are in the given state.
+//
+// NOTE: The caller must hold the coordinator's lock.
+func (c *PsbtCoordinator) allInState(state ContributorState) bool {
+	for _, contrib := range c.contributors {
+		if contrib.state != state {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AddContribution adds the inputs and optional change outputs of the given
+// PSBT to the funding transaction on behalf of the given contributor. It
+// returns true once all contributors added their inputs.
+func (c *PsbtCoordinator) AddContribution(id string,
+	packet *psbt.Packet) (bool, error) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	contrib, err := c.contributor(id)
+	if err != nil {
+		return false, err
+	}
+	if contrib.state != ContributorPending {
+		return false, fmt.Errorf("contributor %q already added its "+
+			"inputs", id)
+	}
+
+	if packet == nil || len(packet.UnsignedTx.TxIn) == 0 {
+		return false, fmt.Errorf("contribution has no inputs")
+	}
+
+	// The funding transaction can only be verified if the values of all
+	// inputs are known.
+	if _, err := psbt.SumUtxoInputValues(packet); err != nil {
+		return false, fmt.Errorf("error determining input sum: %w",
+			err)
+	}
+
+	// Make sure no input is spent twice, which would render the whole
+	// funding transaction invalid.
+	spent := make(map[wire.OutPoint]string)
+	for _, txIn := range c.basePsbt.UnsignedTx.TxIn {
+		spent[txIn.PreviousOutPoint] = "base PSBT"
+	}
+	for otherID, other := range c.contributors {
+		if other.contribution == nil {
+			continue
+		}
+		for _, txIn := range other.contribution.UnsignedTx.TxIn {
+			spent[txIn.PreviousOutPoint] = otherID
+		}
+	}
+	for _, txIn := range packet.UnsignedTx.TxIn {
+		if spender, ok := spent[txIn.PreviousOutPoint]; ok {
+			return false, fmt.Errorf("input %v already added by "+
+				"%v", txIn.PreviousOutPoint, spender)
+		}
+		spent[txIn.PreviousOutPoint] = id
+	}
+
+	contrib.contribution = packet
+	contrib.state = ContributorFunded
+
+	return c.allInState(ContributorFunded), nil
+}
+
+// FundedPsbt returns the unsigned funding PSBT that contains the inputs and
+// outputs of all contributors. It can only be created once all contributors
+// added their inputs.
+func (c *PsbtCoordinator) FundedPsbt() (*psbt.Packet, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.expired {
+		return nil, ErrContributionsExpired
+	}
+
+	return c.fundedPsbt()
+}
+
+// fundedPsbt assembles the unsigned funding PSBT.
+//
+// NOTE: The caller must hold the coordinator's lock.
+func (c *PsbtCoordinator) fundedPsbt() (*psbt.Packet, error) {
+	for _, id := range c.ids {
+		if c.contributors[id].state == ContributorPending {
+			return nil, fmt.Errorf("contributor %q didn't add its "+
+				"inputs yet", id)
+		}
+	}
+
+	base := c.basePsbt
+	packet := &psbt.Packet{
+		UnsignedTx: base.UnsignedTx.Copy(),
+		Inputs:     append([]psbt.PInput{}, base.Inputs...),
+		Outputs:    append([]psbt.POutput{}, base.Outputs...),
+		Unknowns:   base.Unknowns,
+	}
+	for _, id := range c.ids {
+		contribution := c.contributors[id].contribution
+
+		tx := contribution.UnsignedTx
+		for _, txIn := range tx.TxIn {
+			packet.UnsignedTx.AddTxIn(&wire.TxIn{
+				PreviousOutPoint: txIn.PreviousOutPoint,
+				Sequence:         txIn.Sequence,
+			})
+		}
+		for _, txOut := range tx.TxOut {
+			packet.UnsignedTx.AddTxOut(&wire.TxOut{
+				Value:    txOut.Value,
+				PkScript: txOut.PkScript,
+			})
+		}
+		packet.Inputs = append(packet.Inputs, contribution.Inputs...)
+		packet.Outputs = append(packet.Outputs, contribution.Outputs...)
+	}
+
+	return packet, nil
+}
+
+// AddSignatures adds the signed inputs of the given contributor from the
+// given PSBT, which must be the funded PSBT with the contributor's inputs
+// finalized. It returns true once all contributors signed their inputs.
+func (c *PsbtCoordinator) AddSignatures(id string,
+	packet *psbt.Packet) (bool, error) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	contrib, err := c.contributor(id)
+	if err != nil {
+		return false, err
+	}
+	if contrib.state != ContributorFunded {
+		return false, fmt.Errorf("contributor %q can't sign in state "+
+			"%v", id, contrib.state)
+	}
+
+	funded, err := c.fundedPsbt()
+	if err != nil {
+		return false, err
+	}
+	if packet == nil || packet.UnsignedTx.TxHash() !=
+		funded.UnsignedTx.TxHash() {
+
+		return false, fmt.Errorf("signed PSBT doesn't match funded " +
+			"PSBT")
+	}
+
+	// Collect the contributor's inputs, which must all be finalized.
+	txIns := contrib.contribution.UnsignedTx.TxIn
+	signedInputs := make([]psbt.PInput, len(txIns))
+	for i, txIn := range txIns {
+		idx := -1
+		for j, signedIn := range packet.UnsignedTx.TxIn {
+			if signedIn.PreviousOutPoint == txIn.PreviousOutPoint {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 || idx >= len(packet.Inputs) {
+			return false, fmt.Errorf("input %v missing in signed "+
+				"PSBT", txIn.PreviousOutPoint)
+		}
+
+		signedIn := packet.Inputs[idx]
+		if len(signedIn.FinalScriptWitness) == 0 {
+			return false, fmt.Errorf("input %v not finalized",
+				txIn.PreviousOutPoint)
+		}
+		signedInputs[i] = signedIn
+	}
+
+	contrib.signedInputs = signedInputs
+	contrib.state = ContributorSigned
+
+	return c.allInState(ContributorSigned), nil
+}
+
+// SignedPsbt returns the funding PSBT with the signed inputs of all
+// contributors. It can only be created once all contributors signed their
+// inputs.
+func (c *PsbtCoordinator) SignedPsbt() (*psbt.Packet, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.allInState(ContributorSigned) {
+		return nil, fmt.Errorf("not all contributors signed their " +
+			"inputs yet")
+	}
+
+	packet, err := c.fundedPsbt()
+	if err != nil {
+		return nil, err
+	}
+
+	// The contributed inputs follow the inputs of the base PSBT in the
+	// order of the contributors.
+	idx := len(c.basePsbt.Inputs)
+	for _, id := range c.ids {
+		for _, signedIn := range c.contributors[id].signedInputs {
+			packet.Inputs[idx] = signedIn
+			idx++
+		}
+	}
+
+	return packet, nil
+}
+
+// Status returns the status of all contributors, in the order their
+// contributions are added to the funding transaction.
+func (c *PsbtCoordinator) Status() []ContributorStatus {
+	c.Lock()
+	defer c.Unlock()
+
+	status := make([]ContributorStatus, 0, len(c.ids))
+	for _, id := range c.ids {
+		contrib := c.contributors[id]
+
+		var inputs []wire.OutPoint
+		if contrib.contribution != nil {
+			tx := contrib.contribution.UnsignedTx
+			for _, txIn := range tx.TxIn {
+				inputs = append(inputs, txIn.PreviousOutPoint)
+			}
+		}
+
+		status = append(status, ContributorStatus{
+			ID:     id,
+			State:  contrib.state,
+			Inputs: inputs,
+		})
+	}
+
+	return status
+}
@@ -0,0 +1,175 @@
+package chanfunding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// contributionPsbt creates a PSBT that spends a single input with the given
+// index and sends change back to the contributor.
+func contributionPsbt(t *testing.T, idx uint32) *psbt.Packet {
+	t.Helper()
+
+	prevOut := wire.OutPoint{Index: idx}
+	packet, err := psbt.New(
+		[]*wire.OutPoint{&prevOut}, []*wire.TxOut{{
+			Value:    1000,
+			PkScript: []byte{byte(idx)},
+		}}, 2, 0, []uint32{wire.MaxTxInSequenceNum},
+	)
+	require.NoError(t, err)
+
+	packet.Inputs[0].WitnessUtxo = &wire.TxOut{
+		Value:    100_000,
+		PkScript: []byte{0x00, byte(idx)},
+	}
+
+	return packet
+}
+
+// signContribution finalizes the inputs of the given contribution in the
+// given funded PSBT.
+func signContribution(t *testing.T, funded,
+	contribution *psbt.Packet) *psbt.Packet {
+
+	t.Helper()
+
+	signed := &psbt.Packet{
+		UnsignedTx: funded.UnsignedTx.Copy(),
+		Inputs:     append([]psbt.PInput{}, funded.Inputs...),
+		Outputs:    funded.Outputs,
+	}
+	for _, txIn := range contribution.UnsignedTx.TxIn {
+		for i, fundedIn := range signed.UnsignedTx.TxIn {
+			if fundedIn.PreviousOutPoint != txIn.PreviousOutPoint {
+				continue
+			}
+			signed.Inputs[i].FinalScriptWitness = []byte{0x01}
+		}
+	}
+
+	return signed
+}
+
+// TestPsbtCoordinator tests that the coordinator assembles the funding
+// transaction from the inputs of all contributors and collects their
+// signatures.
+func TestPsbtCoordinator(t *testing.T) {
+	t.Parallel()
+
+	base, err := psbt.New(
+		nil, []*wire.TxOut{{Value: 150_000, PkScript: []byte{0xff}}},
+		2, 0, nil,
+	)
+	require.NoError(t, err)
+
+	_, err = NewPsbtCoordinator(base, []string{"a", "a"}, time.Time{})
+	require.ErrorContains(t, err, "duplicate contributor")
+
+	c, err := NewPsbtCoordinator(base, []string{"a", "b"}, time.Time{})
+	require.NoError(t, err)
+
+	contribA := contributionPsbt(t, 1)
+	contribB := contributionPsbt(t, 2)
+
+	// Unknown contributors and contributions without UTXO information
+	// are rejected.
+	_, err = c.AddContribution("c", contribA)
+	require.ErrorIs(t, err, ErrUnknownContributor)
+
+	noUtxo := contributionPsbt(t, 1)
+	noUtxo.Inputs[0].WitnessUtxo = nil
+	_, err = c.AddContribution("a", noUtxo)
+	require.ErrorContains(t, err, "input sum")
+
+	// The funded PSBT is only available once all contributors added
+	// their inputs, and no input can be added twice.
+	allFunded, err := c.AddContribution("a", contribA)
+	require.NoError(t, err)
+	require.False(t, allFunded)
+
+	_, err = c.FundedPsbt()
+	require.ErrorContains(t, err, "didn't add its inputs")
+
+	_, err = c.AddContribution("b", contributionPsbt(t, 1))
+	require.ErrorContains(t, err, "already added by a")
+
+	allFunded, err = c.AddContribution("b", contribB)
+	require.NoError(t, err)
+	require.True(t, allFunded)
+
+	funded, err := c.FundedPsbt()
+	require.NoError(t, err)
+	require.Len(t, funded.UnsignedTx.TxIn, 2)
+	require.Len(t, funded.Inputs, 2)
+	require.Len(t, funded.UnsignedTx.TxOut, 3)
+	require.Len(t, funded.Outputs, 3)
+
+	txIns := funded.UnsignedTx.TxIn
+	require.Equal(t, uint32(1), txIns[0].PreviousOutPoint.Index)
+	require.Equal(t, uint32(2), txIns[1].PreviousOutPoint.Index)
+
+	// Signatures must be added to the funded PSBT and finalize all inputs
+	// of the contributor.
+	_, err = c.AddSignatures("a", contribA)
+	require.ErrorContains(t, err, "doesn't match")
+
+	_, err = c.AddSignatures("a", funded)
+	require.ErrorContains(t, err, "not finalized")
+
+	allSigned, err := c.AddSignatures(
+		"a", signContribution(t, funded, contribA),
+	)
+	require.NoError(t, err)
+	require.False(t, allSigned)
+
+	_, err = c.SignedPsbt()
+	require.Error(t, err)
+
+	status := c.Status()
+	require.Equal(t, ContributorSigned, status[0].State)
+	require.Equal(t, ContributorFunded, status[1].State)
+	require.Equal(t, []wire.OutPoint{{Index: 2}}, status[1].Inputs)
+
+	allSigned, err = c.AddSignatures(
+		"b", signContribution(t, funded, contribB),
+	)
+	require.NoError(t, err)
+	require.True(t, allSigned)
+
+	signed, err := c.SignedPsbt()
+	require.NoError(t, err)
+	for _, in := range signed.Inputs {
+		require.NotEmpty(t, in.FinalScriptWitness)
+	}
+
+	// A fully signed funding transaction doesn't expire.
+	require.False(t, c.Expire())
+}
+
+// TestPsbtCoordinatorExpire tests that no contributions are accepted once the
+// coordinator expired.
+func TestPsbtCoordinatorExpire(t *testing.T) {
+	t.Parallel()
+
+	base, err := psbt.New(
+		nil, []*wire.TxOut{{Value: 150_000, PkScript: []byte{0xff}}},
+		2, 0, nil,
+	)
+	require.NoError(t, err)
+
+	c, err := NewPsbtCoordinator(base, []string{"a"}, time.Time{})
+	require.NoError(t, err)
+
+	require.True(t, c.Expire())
+
+	_, err = c.AddContribution("a", contributionPsbt(t, 1))
+	require.ErrorIs(t, err, ErrContributionsExpired)
+
+	_, err = c.FundedPsbt()
+	require.ErrorIs(t, err, ErrContributionsExpired)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -344,6 +345,11 @@ type PaymentDescriptor struct {
 	// NOTE: Populate only in fail payment descriptor entry types.
 	FailReason []byte
 
+	// FailAttrData stores the attribution data of the failure, if any.
+	//
+	// NOTE: Populate only in fail payment descriptor entry types.
+	FailAttrData []byte
+
 	// FailCode stores the code why a particular payment was canceled.
 	//
 	// NOTE: Populated only in payment descriptor with MalformedFail type.
@@ -380,6 +386,27 @@ type PaymentDescriptor struct {
 	BlindingPoint lnwire.BlindingPointRecord
 }
 
+// wireFailAttrData returns the attribution data of a fail message as it's
+// stored in a payment descriptor.
+func wireFailAttrData(msg *lnwire.UpdateFailHTLC) []byte {
+	var attrData []byte
+	msg.AttrData.WhenSomeV(func(data []byte) {
+		attrData = data
+	})
+
+	return attrData
+}
+
+// failAttrDataRecord returns the attribution data record of a fail message
+// for the attribution data of a payment descriptor.
+func failAttrDataRecord(attrData []byte) lnwire.AttrDataRecord {
+	if len(attrData) == 0 {
+		return lnwire.AttrDataRecord{}
+	}
+
+	return lnwire.NewAttrDataRecord(attrData)
+}
+
 // PayDescsFromRemoteLogUpdates converts a slice of LogUpdates received from the
 // remote peer into PaymentDescriptors to inform a link's forwarding decisions.
 //
@@ -437,9 +464,10 @@ func PayDescsFromRemoteLogUpdates(chanID lnwire.ShortChannelID, height uint64,
 
 		case *lnwire.UpdateFailHTLC:
 			pd = PaymentDescriptor{
-				ParentIndex: wireMsg.ID,
-				EntryType:   Fail,
-				FailReason:  wireMsg.Reason[:],
+				ParentIndex:  wireMsg.ID,
+				EntryType:    Fail,
+				FailReason:   wireMsg.Reason[:],
+				FailAttrData: wireFailAttrData(wireMsg),
 				DestRef: &channeldb.SettleFailRef{
 					Source: chanID,
 					Height: height,
@@ -1268,6 +1296,11 @@ type LightningChannel struct {
 
 	isClosed bool
 
+	// rbfCoopClosed is true if the channel was closed by a round of the
+	// RBF co-op close protocol, which allows later rounds to sign a
+	// replacement closing transaction.
+	rbfCoopClosed bool
+
 	// sigPool is a pool of workers that are capable of signing and
 	// validating signatures in parallel. This is utilized as an
 	// optimization to void serially signing or validating the HTLC
@@ -1609,6 +1642,7 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 			LogIndex:                 logUpdate.LogIndex,
 			EntryType:                Fail,
 			FailReason:               wireMsg.Reason[:],
+			FailAttrData:             wireFailAttrData(wireMsg),
 			removeCommitHeightRemote: commitHeight,
 		}
 
@@ -1695,6 +1729,7 @@ func (lc *LightningChannel) localLogUpdateToPayDesc(logUpdate *channeldb.LogUpda
 			LogIndex:                 logUpdate.LogIndex,
 			EntryType:                Fail,
 			FailReason:               wireMsg.Reason[:],
+			FailAttrData:             wireFailAttrData(wireMsg),
 			removeCommitHeightRemote: commitHeight,
 		}, nil
 
@@ -1793,6 +1828,7 @@ func (lc *LightningChannel) remoteLogUpdateToPayDesc(logUpdate *channeldb.LogUpd
 			LogIndex:                logUpdate.LogIndex,
 			EntryType:               Fail,
 			FailReason:              wireMsg.Reason[:],
+			FailAttrData:            wireFailAttrData(wireMsg),
 			removeCommitHeightLocal: commitHeight,
 		}, nil
 
@@ -3643,9 +3679,10 @@ func (lc *LightningChannel) createCommitDiff(
 
 		case Fail:
 			logUpdate.UpdateMsg = &lnwire.UpdateFailHTLC{
-				ChanID: chanID,
-				ID:     pd.ParentIndex,
-				Reason: pd.FailReason,
+				ChanID:   chanID,
+				ID:       pd.ParentIndex,
+				Reason:   pd.FailReason,
+				AttrData: failAttrDataRecord(pd.FailAttrData),
 			}
 
 		case MalformedFail:
@@ -3767,9 +3804,10 @@ func (lc *LightningChannel) getUnsignedAckedUpdates() []channeldb.LogUpdate {
 
 		case Fail:
 			logUpdate.UpdateMsg = &lnwire.UpdateFailHTLC{
-				ChanID: chanID,
-				ID:     pd.ParentIndex,
-				Reason: pd.FailReason,
+				ChanID:   chanID,
+				ID:       pd.ParentIndex,
+				Reason:   pd.FailReason,
+				AttrData: failAttrDataRecord(pd.FailAttrData),
 			}
 
 		case MalformedFail:
@@ -5769,9 +5807,10 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) (
 
 		case Fail:
 			logUpdate.UpdateMsg = &lnwire.UpdateFailHTLC{
-				ChanID: chanID,
-				ID:     pd.ParentIndex,
-				Reason: pd.FailReason,
+				ChanID:   chanID,
+				ID:       pd.ParentIndex,
+				Reason:   pd.FailReason,
+				AttrData: failAttrDataRecord(pd.FailAttrData),
 			}
 			settleFailUpdates = append(settleFailUpdates, logUpdate)
 
@@ -6307,9 +6346,11 @@ func (lc *LightningChannel) ReceiveHTLCSettle(preimage [32]byte, htlcIndex uint6
 //     the HTLC was failed locally before committing a circuit to the circuit
 //     map.
 //
+// The attribution data of the failure may be nil if we don't add any.
+//
 // NOTE: It is okay for sourceRef, destRef, and closeKey to be nil when unit
 // testing the wallet.
-func (lc *LightningChannel) FailHTLC(htlcIndex uint64, reason []byte,
+func (lc *LightningChannel) FailHTLC(htlcIndex uint64, reason, attrData []byte,
 	sourceRef *channeldb.AddRef, destRef *channeldb.SettleFailRef,
 	closeKey *models.CircuitKey) error {
 
@@ -6334,6 +6375,7 @@ func (lc *LightningChannel) FailHTLC(htlcIndex uint64, reason []byte,
 		LogIndex:         lc.localUpdateLog.logIndex,
 		EntryType:        Fail,
 		FailReason:       reason,
+		FailAttrData:     attrData,
 		SourceRef:        sourceRef,
 		DestRef:          destRef,
 		ClosedCircuitKey: closeKey,
@@ -6401,9 +6443,10 @@ func (lc *LightningChannel) MalformedFailHTLC(htlcIndex uint64,
 // ReceiveFailHTLC attempts to cancel a targeted HTLC by its log index,
 // inserting an entry which will remove the target log entry within the next
 // commitment update. This method should be called in response to the upstream
-// party cancelling an outgoing HTLC.
-func (lc *LightningChannel) ReceiveFailHTLC(htlcIndex uint64, reason []byte,
-) error {
+// party cancelling an outgoing HTLC. The attribution data of the failure is
+// nil if the upstream party didn't send any.
+func (lc *LightningChannel) ReceiveFailHTLC(htlcIndex uint64, reason,
+	attrData []byte) error {
 
 	lc.Lock()
 	defer lc.Unlock()
@@ -6420,12 +6463,13 @@ func (lc *LightningChannel) ReceiveFailHTLC(htlcIndex uint64, reason []byte,
 	}
 
 	pd := &PaymentDescriptor{
-		Amount:      htlc.Amount,
-		RHash:       htlc.RHash,
-		ParentIndex: htlc.HtlcIndex,
-		LogIndex:    lc.remoteUpdateLog.logIndex,
-		EntryType:   Fail,
-		FailReason:  reason,
+		Amount:       htlc.Amount,
+		RHash:        htlc.RHash,
+		ParentIndex:  htlc.HtlcIndex,
+		LogIndex:     lc.remoteUpdateLog.logIndex,
+		EntryType:    Fail,
+		FailReason:   reason,
+		FailAttrData: attrData,
 	}
 
 	lc.remoteUpdateLog.appendUpdate(pd)
@@ -7758,6 +7802,14 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 // close process.
 type chanCloseOpt struct {
 	musigSession *MusigSession
+
+	// customFeePayer, if set, overrides the party that pays the fee of the
+	// co-op close transaction. It is true if the local party pays the fee.
+	customFeePayer fn.Option[bool]
+
+	// rbfSequence, if set, marks the close as an RBF co-op close and is
+	// the sequence used for the input spending the funding output.
+	rbfSequence fn.Option[uint32]
 }
 
 // ChanCloseOpt is a closure type that cen be used to modify the set of default
@@ -7778,6 +7830,26 @@ func WithCoopCloseMusigSession(session *MusigSession) ChanCloseOpt {
 	}
 }
 
+// WithCustomCoopFeePayer can be used to override the party that pays the fee
+// of the co-op close transaction, which by default is the initiator of the
+// channel. If localPays is true, the fee is paid from our balance, otherwise
+// from the balance of the remote party.
+func WithCustomCoopFeePayer(localPays bool) ChanCloseOpt {
+	return func(opts *chanCloseOpt) {
+		opts.customFeePayer = fn.Some(localPays)
+	}
+}
+
+// WithRbfCoopClose signals that the co-op close transaction may replace a
+// previous one that was already signed for this channel, as done by the RBF
+// co-op close protocol. The passed sequence is used for the input spending
+// the funding output.
+func WithRbfCoopClose(sequence uint32) ChanCloseOpt {
+	return func(opts *chanCloseOpt) {
+		opts.rbfSequence = fn.Some(sequence)
+	}
+}
+
 // CreateCloseProposal is used by both parties in a cooperative channel close
 // workflow to generate proposed close transactions and signatures. This method
 // should only be executed once all pending HTLCs (if any) on the channel have
@@ -7796,22 +7868,24 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	lc.Lock()
 	defer lc.Unlock()
 
-	// If we're already closing the channel, then ignore this request.
-	if lc.isClosed {
-		// TODO(roasbeef): check to ensure no pending payments
-		return nil, nil, 0, ErrChanClosing
-	}
-
 	opts := defaultCloseOpts()
 	for _, optFunc := range closeOpts {
 		optFunc(opts)
 	}
 
+	// If we're already closing the channel, then ignore this request,
+	// unless we're replacing the closing transaction of an RBF co-op close.
+	if lc.isClosed && !lc.canReplaceCoopClose(opts) {
+		// TODO(roasbeef): check to ensure no pending payments
+		return nil, nil, 0, ErrChanClosing
+	}
+
 	// Get the final balances after subtracting the proposed fee, taking
 	// care not to persist the adjusted balance, as the feeRate may change
 	// during the channel closing process.
 	ourBalance, theirBalance, err := CoopCloseBalance(
 		lc.channelState.ChanType, lc.channelState.IsInitiator,
+		opts.customFeePayer.UnwrapOr(lc.channelState.IsInitiator),
 		proposedFee, lc.channelState.LocalCommitment,
 	)
 	if err != nil {
@@ -7825,6 +7899,12 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 		closeTxOpts = append(closeTxOpts, WithRBFCloseTx())
 	}
 
+	// The sequence of an RBF co-op close takes precedence over the
+	// default one.
+	opts.rbfSequence.WhenSome(func(sequence uint32) {
+		closeTxOpts = append(closeTxOpts, withCloseTxSequence(sequence))
+	})
+
 	closeTx := CreateCooperativeCloseTx(
 		fundingTxIn(lc.channelState), lc.channelState.LocalChanCfg.DustLimit,
 		lc.channelState.RemoteChanCfg.DustLimit, ourBalance, theirBalance,
@@ -7864,6 +7944,15 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	return sig, &closeTXID, ourBalance, nil
 }
 
+// canReplaceCoopClose returns true if the channel was closed by a round of the
+// RBF co-op close protocol and the passed options belong to a later round,
+// whose closing transaction replaces the previous one.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) canReplaceCoopClose(opts *chanCloseOpt) bool {
+	return lc.rbfCoopClosed && opts.rbfSequence.IsSome()
+}
+
 // CompleteCooperativeClose completes the cooperative closure of the target
 // active lightning channel. A fully signed closure transaction as well as the
 // signature itself are returned. Additionally, we also return our final
@@ -7880,20 +7969,22 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	lc.Lock()
 	defer lc.Unlock()
 
-	// If the channel is already closing, then ignore this request.
-	if lc.isClosed {
-		// TODO(roasbeef): check to ensure no pending payments
-		return nil, 0, ErrChanClosing
-	}
-
 	opts := defaultCloseOpts()
 	for _, optFunc := range closeOpts {
 		optFunc(opts)
 	}
 
+	// If the channel is already closing, then ignore this request, unless
+	// we're replacing the closing transaction of an RBF co-op close.
+	if lc.isClosed && !lc.canReplaceCoopClose(opts) {
+		// TODO(roasbeef): check to ensure no pending payments
+		return nil, 0, ErrChanClosing
+	}
+
 	// Get the final balances after subtracting the proposed fee.
 	ourBalance, theirBalance, err := CoopCloseBalance(
 		lc.channelState.ChanType, lc.channelState.IsInitiator,
+		opts.customFeePayer.UnwrapOr(lc.channelState.IsInitiator),
 		proposedFee, lc.channelState.LocalCommitment,
 	)
 	if err != nil {
@@ -7907,6 +7998,12 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 		closeTxOpts = append(closeTxOpts, WithRBFCloseTx())
 	}
 
+	// The sequence of an RBF co-op close takes precedence over the
+	// default one.
+	opts.rbfSequence.WhenSome(func(sequence uint32) {
+		closeTxOpts = append(closeTxOpts, withCloseTxSequence(sequence))
+	})
+
 	// Create the transaction used to return the current settled balance
 	// on this active channel back to both parties. In this current model,
 	// the initiator pays full fees for the cooperative close transaction.
@@ -7986,6 +8083,7 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	// channel now as closed as the closure transaction should get into the
 	// chain in a timely manner and possibly be re-broadcast by the wallet.
 	lc.isClosed = true
+	lc.rbfCoopClosed = opts.rbfSequence.IsSome()
 
 	return closeTx, ourBalance, nil
 }
@@ -8561,6 +8659,10 @@ type closeTxOpts struct {
 	// enableRBF indicates whether the cooperative close tx should signal
 	// RBF or not.
 	enableRBF bool
+
+	// sequence, if set, is the sequence of the funding input. It takes
+	// precedence over enableRBF.
+	sequence fn.Option[uint32]
 }
 
 // defaultCloseTxOpts returns a closeTxOpts struct with default values.
@@ -8581,6 +8683,14 @@ func WithRBFCloseTx() CloseTxOpt {
 	}
 }
 
+// withCloseTxSequence sets the sequence of the funding input of the
+// cooperative close tx.
+func withCloseTxSequence(sequence uint32) CloseTxOpt {
+	return func(o *closeTxOpts) {
+		o.sequence = fn.Some(sequence)
+	}
+}
+
 // CreateCooperativeCloseTx creates a transaction which if signed by both
 // parties, then broadcast cooperatively closes an active channel. The creation
 // of the closure transaction is modified by a boolean indicating if the party
@@ -8602,6 +8712,9 @@ func CreateCooperativeCloseTx(fundingTxIn wire.TxIn,
 	if opts.enableRBF {
 		fundingTxIn.Sequence = mempool.MaxRBFSequence
 	}
+	opts.sequence.WhenSome(func(sequence uint32) {
+		fundingTxIn.Sequence = sequence
+	})
 
 	// Construct the transaction to perform a cooperative closure of the
 	// channel. In the event that one side doesn't have any settled funds
@@ -8981,6 +9094,9 @@ func (lc *LightningChannel) unsignedLocalUpdates(remoteMessageIndex,
 					ChanID: chanID,
 					ID:     pd.ParentIndex,
 					Reason: pd.FailReason,
+					AttrData: failAttrDataRecord(
+						pd.FailAttrData,
+					),
 				}
 			case MalformedFail:
 				logUpdate.UpdateMsg = &lnwire.UpdateFailMalformedHTLC{
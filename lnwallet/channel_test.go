@@ -16,6 +16,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
@@ -441,9 +442,9 @@ func TestChannelZeroAddLocalHeight(t *testing.T) {
 
 	// Now Bob should fail the htlc back to Alice.
 	// <----fail-----
-	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	require.NoError(t, err)
-	err = aliceChannel.ReceiveFailHTLC(0, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(0, []byte("bad"), nil)
 	require.NoError(t, err)
 
 	// Bob should send a commitment signature to Alice.
@@ -799,6 +800,67 @@ func testCoopClose(t *testing.T, testCase *coopCloseTestCase) {
 	}
 }
 
+// TestRbfCooperativeChannelClosure checks that the party closing the channel
+// can pay the co-op close fee even if it isn't the initiator of the channel,
+// and that the closing transaction can be replaced by a later round with a
+// higher fee.
+func TestRbfCooperativeChannelClosure(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceDeliveryScript := bobsPrivKey[:]
+	bobDeliveryScript := testHdSeed[:]
+
+	// closeRound has Bob, who isn't the initiator of the channel, pay the
+	// given fee for a closing transaction that Alice signs.
+	closeRound := func(fee btcutil.Amount) *wire.MsgTx {
+		bobSig, _, bobBalance, err := bobChannel.CreateCloseProposal(
+			fee, bobDeliveryScript, aliceDeliveryScript,
+			WithCustomCoopFeePayer(true),
+			WithRbfCoopClose(mempool.MaxRBFSequence),
+		)
+		require.NoError(t, err)
+
+		expBalanceBob := bobChannel.channelState.LocalCommitment.
+			LocalBalance.ToSatoshis() - fee
+		require.Equal(t, expBalanceBob, bobBalance)
+
+		aliceSig, _, _, err := aliceChannel.CreateCloseProposal(
+			fee, aliceDeliveryScript, bobDeliveryScript,
+			WithCustomCoopFeePayer(false),
+			WithRbfCoopClose(mempool.MaxRBFSequence),
+		)
+		require.NoError(t, err)
+
+		closeTx, _, err := aliceChannel.CompleteCooperativeClose(
+			aliceSig, bobSig, aliceDeliveryScript,
+			bobDeliveryScript, fee, WithCustomCoopFeePayer(false),
+			WithRbfCoopClose(mempool.MaxRBFSequence),
+		)
+		require.NoError(t, err)
+		require.Equal(
+			t, uint32(mempool.MaxRBFSequence),
+			closeTx.TxIn[0].Sequence,
+		)
+
+		return closeTx
+	}
+
+	firstTx := closeRound(1_000)
+	replacementTx := closeRound(2_000)
+	require.NotEqual(t, firstTx.TxHash(), replacementTx.TxHash())
+
+	// A legacy co-op close can't be signed once the channel was closed.
+	_, _, _, err = aliceChannel.CreateCloseProposal(
+		3_000, aliceDeliveryScript, bobDeliveryScript,
+	)
+	require.ErrorIs(t, err, ErrChanClosing)
+}
+
 // TestForceClose checks that the resulting ForceCloseSummary is correct when a
 // peer is ForceClosing the channel. Will check outputs both above and below
 // the dust limit. Additionally, we'll ensure that the node which executed the
@@ -2060,9 +2122,11 @@ func TestCancelHTLC(t *testing.T) {
 
 	// Now, with the HTLC committed on both sides, trigger a cancellation
 	// from Bob to Alice, removing the HTLC.
-	err = bobChannel.FailHTLC(bobHtlcIndex, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(
+		bobHtlcIndex, []byte("failreason"), nil, nil, nil, nil,
+	)
 	require.NoError(t, err, "unable to cancel HTLC")
-	err = aliceChannel.ReceiveFailHTLC(aliceHtlcIndex, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(aliceHtlcIndex, []byte("bad"), nil)
 	require.NoError(t, err, "unable to recv htlc cancel")
 
 	// Now trigger another state transition, the HTLC should now be removed
@@ -4904,9 +4968,9 @@ func TestChanAvailableBandwidth(t *testing.T) {
 	}
 
 	htlcIndex := uint64((numHtlcs * 2) - 1)
-	err = bobChannel.FailHTLC(htlcIndex, []byte("f"), nil, nil, nil)
+	err = bobChannel.FailHTLC(htlcIndex, []byte("f"), nil, nil, nil, nil)
 	require.NoError(t, err, "unable to cancel HTLC")
-	err = aliceChannel.ReceiveFailHTLC(htlcIndex, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(htlcIndex, []byte("bad"), nil)
 	require.NoError(t, err, "unable to recv htlc cancel")
 
 	// We must do a state transition before the balance is available
@@ -5373,9 +5437,11 @@ func TestLockedInHtlcForwardingSkipAfterRestart(t *testing.T) {
 
 	// With both nodes restarted, Bob will now attempt to cancel one of
 	// Alice's HTLC's.
-	err = bobChannel.FailHTLC(htlc.ID, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(
+		htlc.ID, []byte("failreason"), nil, nil, nil, nil,
+	)
 	require.NoError(t, err, "unable to cancel HTLC")
-	err = aliceChannel.ReceiveFailHTLC(htlc.ID, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(htlc.ID, []byte("bad"), nil)
 	require.NoError(t, err, "unable to recv htlc cancel")
 
 	// We'll now initiate another state transition, but this time Bob will
@@ -5426,9 +5492,11 @@ func TestLockedInHtlcForwardingSkipAfterRestart(t *testing.T) {
 
 	// Failing the HTLC here will cause the update to be included in Alice's
 	// remote log, but it should not be committed by this transition.
-	err = bobChannel.FailHTLC(htlc2.ID, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(
+		htlc2.ID, []byte("failreason"), nil, nil, nil, nil,
+	)
 	require.NoError(t, err, "unable to cancel HTLC")
-	err = aliceChannel.ReceiveFailHTLC(htlc2.ID, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(htlc2.ID, []byte("bad"), nil)
 	require.NoError(t, err, "unable to recv htlc cancel")
 
 	bobRevocation, _, finalHtlcs, err := bobChannel.
@@ -5472,9 +5540,11 @@ func TestLockedInHtlcForwardingSkipAfterRestart(t *testing.T) {
 
 	// Re-add the Fail to both Alice and Bob's channels, as the non-committed
 	// update will not have survived the restart.
-	err = bobChannel.FailHTLC(htlc2.ID, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(
+		htlc2.ID, []byte("failreason"), nil, nil, nil, nil,
+	)
 	require.NoError(t, err, "unable to cancel HTLC")
-	err = aliceChannel.ReceiveFailHTLC(htlc2.ID, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(htlc2.ID, []byte("bad"), nil)
 	require.NoError(t, err, "unable to recv htlc cancel")
 
 	// Have Alice initiate a state transition, which does not include the
@@ -5924,9 +5994,13 @@ func TestDesyncHTLCs(t *testing.T) {
 	}
 
 	// Now let Bob fail this HTLC.
-	err = bobChannel.FailHTLC(bobIndex, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(
+		bobIndex, []byte("failreason"), nil, nil, nil, nil,
+	)
 	require.NoError(t, err, "unable to cancel HTLC")
-	if err := aliceChannel.ReceiveFailHTLC(aliceIndex, []byte("bad")); err != nil {
+	if err := aliceChannel.ReceiveFailHTLC(
+		aliceIndex, []byte("bad"), nil,
+	); err != nil {
 		t.Fatalf("unable to recv htlc cancel: %v", err)
 	}
 
@@ -6021,10 +6095,12 @@ func TestMaxAcceptedHTLCs(t *testing.T) {
 
 	// Bob will fail the htlc specified by htlcID and then force a state
 	// transition.
-	err = bobChannel.FailHTLC(htlcID, []byte{}, nil, nil, nil)
+	err = bobChannel.FailHTLC(htlcID, []byte{}, nil, nil, nil, nil)
 	require.NoError(t, err, "unable to fail htlc")
 
-	if err := aliceChannel.ReceiveFailHTLC(htlcID, []byte{}); err != nil {
+	if err := aliceChannel.ReceiveFailHTLC(
+		htlcID, []byte{}, nil,
+	); err != nil {
 		t.Fatalf("unable to receive fail htlc: %v", err)
 	}
 
@@ -6142,10 +6218,12 @@ func TestMaxAsynchronousHtlcs(t *testing.T) {
 	}
 
 	// Fail back an HTLC and sign a commitment as in steps 1 & 2.
-	err = bobChannel.FailHTLC(htlcID, []byte{}, nil, nil, nil)
+	err = bobChannel.FailHTLC(htlcID, []byte{}, nil, nil, nil, nil)
 	require.NoError(t, err, "unable to fail htlc")
 
-	if err := aliceChannel.ReceiveFailHTLC(htlcID, []byte{}); err != nil {
+	if err := aliceChannel.ReceiveFailHTLC(
+		htlcID, []byte{}, nil,
+	); err != nil {
 		t.Fatalf("unable to receive fail htlc: %v", err)
 	}
 
@@ -7000,10 +7078,10 @@ func TestChannelRestoreUpdateLogsFailedHTLC(t *testing.T) {
 	restoreAndAssert(t, aliceChannel, 1, 0, 0, 0)
 
 	// Now we make Bob fail this HTLC.
-	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	require.NoError(t, err, "unable to cancel HTLC")
 
-	err = aliceChannel.ReceiveFailHTLC(0, []byte("failreason"))
+	err = aliceChannel.ReceiveFailHTLC(0, []byte("failreason"), nil)
 	require.NoError(t, err, "unable to recv htlc cancel")
 
 	// This Fail update should have been added to Alice's remote update log.
@@ -7064,6 +7142,50 @@ func TestChannelRestoreUpdateLogsFailedHTLC(t *testing.T) {
 	restoreAndAssert(t, aliceChannel, 0, 0, 0, 0)
 }
 
+// TestFailHTLCAttrData tests that the attribution data of a failure is kept
+// when the failure is restored from disk and sent again.
+func TestFailHTLCAttrData(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	htlcAmount := lnwire.NewMSatFromSatoshis(20000)
+	htlc, _ := createHTLC(0, htlcAmount)
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// Bob fails the HTLC with attribution data and signs a commitment
+	// that includes the failure, which persists it.
+	attrData := bytes.Repeat([]byte{1}, 920)
+	err = bobChannel.FailHTLC(
+		0, []byte("failreason"), attrData, nil, nil, nil,
+	)
+	require.NoError(t, err)
+	_, err = bobChannel.SignNextCommitment()
+	require.NoError(t, err)
+
+	bobChannel, err = restartChannel(bobChannel)
+	require.NoError(t, err)
+
+	// The restored failure must still have its attribution data, so it's
+	// included when the failure is sent again.
+	var failDesc *PaymentDescriptor
+	for e := bobChannel.localUpdateLog.Front(); e != nil; e = e.Next() {
+		pd := e.Value.(*PaymentDescriptor)
+		if pd.EntryType == Fail {
+			failDesc = pd
+		}
+	}
+	require.NotNil(t, failDesc)
+	require.Equal(t, attrData, failDesc.FailAttrData)
+}
+
 // TestDuplicateFailRejection tests that if either party attempts to fail an
 // HTLC twice, then we'll reject the second fail attempt.
 func TestDuplicateFailRejection(t *testing.T) {
@@ -7090,19 +7212,23 @@ func TestDuplicateFailRejection(t *testing.T) {
 
 	// With the HTLC locked in, we'll now have Bob fail the HTLC back to
 	// Alice.
-	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	require.NoError(t, err, "unable to cancel HTLC")
-	if err := aliceChannel.ReceiveFailHTLC(0, []byte("bad")); err != nil {
+	if err := aliceChannel.ReceiveFailHTLC(
+		0, []byte("bad"), nil,
+	); err != nil {
 		t.Fatalf("unable to recv htlc cancel: %v", err)
 	}
 
 	// If we attempt to fail it AGAIN, then both sides should reject this
 	// second failure attempt.
-	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	if err == nil {
 		t.Fatalf("duplicate HTLC failure attempt should have failed")
 	}
-	if err := aliceChannel.ReceiveFailHTLC(0, []byte("bad")); err == nil {
+	if err := aliceChannel.ReceiveFailHTLC(
+		0, []byte("bad"), nil,
+	); err == nil {
 		t.Fatalf("duplicate HTLC failure attempt should have failed")
 	}
 
@@ -7119,14 +7245,16 @@ func TestDuplicateFailRejection(t *testing.T) {
 	require.NoError(t, err, "unable to restart channel")
 
 	// If we try to fail the same HTLC again, then we should get an error.
-	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	if err == nil {
 		t.Fatalf("duplicate HTLC failure attempt should have failed")
 	}
 
 	// Alice on the other hand should accept the failure again, as she
 	// dropped all items in the logs which weren't committed.
-	if err := aliceChannel.ReceiveFailHTLC(0, []byte("bad")); err != nil {
+	if err := aliceChannel.ReceiveFailHTLC(
+		0, []byte("bad"), nil,
+	); err != nil {
 		t.Fatalf("unable to recv htlc cancel: %v", err)
 	}
 }
@@ -7399,9 +7527,9 @@ func TestChannelRestoreCommitHeight(t *testing.T) {
 	bobChannel = restoreAndAssertCommitHeights(t, bobChannel, true, 1, 2, 2)
 
 	// Bob now fails back the htlc that was just locked in.
-	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	require.NoError(t, err, "unable to cancel HTLC")
-	err = aliceChannel.ReceiveFailHTLC(0, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(0, []byte("bad"), nil)
 	require.NoError(t, err, "unable to recv htlc cancel")
 
 	// Now Bob signs for the fail update.
@@ -9270,9 +9398,9 @@ func TestChannelUnsignedAckedFailure(t *testing.T) {
 
 	// Now Bob should fail the htlc back to Alice.
 	// <----fail-----
-	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = bobChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	require.NoError(t, err)
-	err = aliceChannel.ReceiveFailHTLC(0, []byte("bad"))
+	err = aliceChannel.ReceiveFailHTLC(0, []byte("bad"), nil)
 	require.NoError(t, err)
 
 	// Bob should send a commitment signature to Alice.
@@ -9380,9 +9508,9 @@ func TestChannelLocalUnsignedUpdatesFailure(t *testing.T) {
 
 	// Now Alice should fail the htlc back to Bob.
 	// -----fail--->
-	err = aliceChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil)
+	err = aliceChannel.FailHTLC(0, []byte("failreason"), nil, nil, nil, nil)
 	require.NoError(t, err)
-	err = bobChannel.ReceiveFailHTLC(0, []byte("bad"))
+	err = bobChannel.ReceiveFailHTLC(0, []byte("bad"), nil)
 	require.NoError(t, err)
 
 	// Alice should send a commitment signature to Bob.
@@ -10795,10 +10923,10 @@ func TestAsynchronousSendingWithFeeBuffer(t *testing.T) {
 	//	<----rev-------	|---------------
 	//	<----sig-------	|---------------
 	//	---------------	|-----rev------>
-	err = aliceChannel.FailHTLC(0, []byte{}, nil, nil, nil)
+	err = aliceChannel.FailHTLC(0, []byte{}, nil, nil, nil, nil)
 	require.NoError(t, err)
 
-	err = bobChannel.ReceiveFailHTLC(0, []byte{})
+	err = bobChannel.ReceiveFailHTLC(0, []byte{}, nil)
 	require.NoError(t, err)
 
 	err = ForceStateTransition(aliceChannel, bobChannel)
@@ -960,10 +960,13 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 }
 
 // CoopCloseBalance returns the final balances that should be used to create
-// the cooperative close tx, given the channel type and transaction fee.
-func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
-	coopCloseFee btcutil.Amount, localCommit channeldb.ChannelCommitment) (
-	btcutil.Amount, btcutil.Amount, error) {
+// the cooperative close tx, given the channel type and transaction fee. The
+// fee is paid by the local party if localPaysFee is true, and by the remote
+// party otherwise.
+func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator,
+	localPaysFee bool, coopCloseFee btcutil.Amount,
+	localCommit channeldb.ChannelCommitment) (btcutil.Amount,
+	btcutil.Amount, error) {
 
 	// Get both parties' balances from the latest commitment.
 	ourBalance := localCommit.LocalBalance.ToSatoshis()
@@ -979,21 +982,25 @@ func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
 		initiatorDelta += 2 * anchorSize
 	}
 
-	// The initiator will pay the full coop close fee, subtract that value
-	// from their balance.
-	initiatorDelta -= coopCloseFee
-
 	if isInitiator {
 		ourBalance += initiatorDelta
 	} else {
 		theirBalance += initiatorDelta
 	}
 
-	// During fee negotiation it should always be verified that the
-	// initiator can pay the proposed fee, but we do a sanity check just to
-	// be sure here.
+	// The fee payer will pay the full coop close fee, subtract that value
+	// from their balance.
+	if localPaysFee {
+		ourBalance -= coopCloseFee
+	} else {
+		theirBalance -= coopCloseFee
+	}
+
+	// During fee negotiation it should always be verified that the fee
+	// payer can pay the proposed fee, but we do a sanity check just to be
+	// sure here.
 	if ourBalance < 0 || theirBalance < 0 {
-		return 0, 0, fmt.Errorf("initiator cannot afford proposed " +
+		return 0, 0, fmt.Errorf("fee payer cannot afford proposed " +
 			"coop close fee")
 	}
 
@@ -0,0 +1,397 @@
+package hwisigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+var (
+	// ErrRequestDenied is returned if the operator denied a signing
+	// request.
+	ErrRequestDenied = errors.New("signing request denied")
+
+	// ErrUnknownRequest is returned if a signing request to approve or
+	// deny doesn't exist (anymore).
+	ErrUnknownRequest = errors.New("unknown signing request")
+)
+
+// Config holds the configuration of the hardware wallet signer.
+type Config struct {
+	// Path is the path to the HWI executable.
+	Path string
+
+	// DeviceType is the optional type of the device, for example "trezor"
+	// or "ledger".
+	DeviceType string
+
+	// Fingerprint is the hex encoded master key fingerprint of the device.
+	// Only inputs that are derived from this master key are sent to the
+	// device.
+	Fingerprint string
+
+	// ChainParams are the parameters of the chain the device signs for.
+	ChainParams *chaincfg.Params
+
+	// Timeout is the maximum time a single call to the device may take,
+	// including the time the user needs to confirm the transaction on the
+	// device.
+	Timeout time.Duration
+
+	// SkipApproval disables the approval of signing requests through RPC.
+	// If set, signing requests are forwarded to the device right away.
+	SkipApproval bool
+}
+
+// Request is a pending request to sign a PSBT with the hardware wallet that
+// waits for the approval of the operator.
+type Request struct {
+	// ID is the unique ID of the request.
+	ID uint64
+
+	// Psbt is the serialized PSBT that is going to be signed.
+	Psbt []byte
+
+	// Inputs are the indices of the inputs that are going to be signed by
+	// the device.
+	Inputs []uint32
+
+	// CreatedAt is the time the request was created.
+	CreatedAt time.Time
+
+	// decision is used to deliver the decision of the operator.
+	decision chan bool
+}
+
+// hwiResponse is the JSON object HWI prints as the result of a command.
+type hwiResponse struct {
+	Psbt  string `json:"psbt"`
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// Signer delegates the signing of wallet inputs to a hardware wallet by
+// calling the HWI command line tool. Keys of the custom lnd key family
+// purpose are never sent to the device, so channel related signing always
+// stays with lnd.
+type Signer struct {
+	cfg *Config
+
+	fingerprint uint32
+
+	// runCmd runs HWI with the given arguments and returns its output.
+	runCmd func(ctx context.Context, args ...string) ([]byte, error)
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]*Request
+}
+
+// New creates a new hardware wallet signer from the given config.
+func New(cfg *Config) (*Signer, error) {
+	fingerprint, err := hex.DecodeString(cfg.Fingerprint)
+	if err != nil || len(fingerprint) != 4 {
+		return nil, fmt.Errorf("invalid master key fingerprint %q",
+			cfg.Fingerprint)
+	}
+
+	s := &Signer{
+		cfg:         cfg,
+		fingerprint: binary.LittleEndian.Uint32(fingerprint),
+		pending:     make(map[uint64]*Request),
+	}
+	s.runCmd = s.execHWI
+
+	return s, nil
+}
+
+// chainName returns the name HWI uses for the chain of the given parameters.
+func chainName(params *chaincfg.Params) string {
+	switch params.Name {
+	case chaincfg.MainNetParams.Name:
+		return "main"
+
+	case chaincfg.TestNet3Params.Name:
+		return "test"
+
+	case chaincfg.SigNetParams.Name:
+		return "signet"
+
+	default:
+		return "regtest"
+	}
+}
+
+// execHWI runs the HWI executable with the given arguments.
+func (s *Signer) execHWI(ctx context.Context, args ...string) ([]byte,
+	error) {
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.cfg.Path, args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running HWI: %w: %s", err,
+			stderr.String())
+	}
+
+	return out, nil
+}
+
+// Inputs returns the indices of the inputs of the packet that aren't signed
+// yet and are derived from the master key of the device.
+func (s *Signer) Inputs(packet *psbt.Packet) []uint32 {
+	var inputs []uint32
+	for idx := range packet.Inputs {
+		in := &packet.Inputs[idx]
+
+		if len(in.FinalScriptWitness) > 0 ||
+			len(in.FinalScriptSig) > 0 {
+
+			continue
+		}
+
+		if s.ownsInput(in) {
+			inputs = append(inputs, uint32(idx))
+		}
+	}
+
+	return inputs
+}
+
+// ownsInput returns true if the input is derived from the master key of the
+// device and is not a key of lnd's custom key family purpose.
+func (s *Signer) ownsInput(in *psbt.PInput) bool {
+	var paths [][]uint32
+	for _, derivation := range in.Bip32Derivation {
+		if derivation.MasterKeyFingerprint == s.fingerprint {
+			paths = append(paths, derivation.Bip32Path)
+		}
+	}
+	for _, derivation := range in.TaprootBip32Derivation {
+		if derivation.MasterKeyFingerprint == s.fingerprint {
+			paths = append(paths, derivation.Bip32Path)
+		}
+	}
+
+	const channelPurpose = hdkeychain.HardenedKeyStart +
+		keychain.BIP0043Purpose
+
+	for _, path := range paths {
+		if len(path) > 0 && path[0] == channelPurpose {
+			return false
+		}
+	}
+
+	return len(paths) > 0
+}
+
+// SignPsbt signs all inputs of the packet that belong to the device and
+// returns their indices. If approval of signing requests is required, this
+// blocks until the operator approved or denied the request, or the context
+// is canceled.
+func (s *Signer) SignPsbt(ctx context.Context, packet *psbt.Packet) ([]uint32,
+	error) {
+
+	inputs := s.Inputs(packet)
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	if !s.cfg.SkipApproval {
+		err := s.waitForApproval(ctx, buf.Bytes(), inputs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	args := []string{
+		"--fingerprint", s.cfg.Fingerprint,
+		"--chain", chainName(s.cfg.ChainParams),
+	}
+	if s.cfg.DeviceType != "" {
+		args = append(args, "--device-type", s.cfg.DeviceType)
+	}
+	args = append(
+		args, "signtx", base64.StdEncoding.EncodeToString(buf.Bytes()),
+	)
+
+	log.Infof("Sending %d input(s) of PSBT to hardware wallet %s for "+
+		"signing", len(inputs), s.cfg.Fingerprint)
+
+	out, err := s.runCmd(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp hwiResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding HWI response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("hardware wallet error (code %d): %s",
+			resp.Code, resp.Error)
+	}
+
+	signed, err := psbt.NewFromRawBytes(
+		bytes.NewReader([]byte(resp.Psbt)), true,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signed PSBT: %w", err)
+	}
+
+	if signed.UnsignedTx.TxHash() != packet.UnsignedTx.TxHash() {
+		return nil, errors.New("hardware wallet returned a different " +
+			"transaction")
+	}
+
+	return mergeSignatures(packet, signed, inputs), nil
+}
+
+// mergeSignatures copies the signatures the device added to the given inputs
+// of the signed packet into the packet and returns the indices of the inputs
+// that received a signature.
+func mergeSignatures(packet, signed *psbt.Packet, inputs []uint32) []uint32 {
+	var signedInputs []uint32
+	for _, idx := range inputs {
+		in := &packet.Inputs[idx]
+		signedIn := &signed.Inputs[idx]
+		added := false
+
+		for _, sig := range signedIn.PartialSigs {
+			if hasPartialSig(in, sig.PubKey) {
+				continue
+			}
+
+			in.PartialSigs = append(in.PartialSigs, sig)
+			added = true
+		}
+
+		if len(in.TaprootKeySpendSig) == 0 &&
+			len(signedIn.TaprootKeySpendSig) > 0 {
+
+			in.TaprootKeySpendSig = signedIn.TaprootKeySpendSig
+			added = true
+		}
+
+		scriptSigs := signedIn.TaprootScriptSpendSig
+		if len(scriptSigs) > len(in.TaprootScriptSpendSig) {
+			in.TaprootScriptSpendSig = scriptSigs
+			added = true
+		}
+
+		if added {
+			signedInputs = append(signedInputs, idx)
+		}
+	}
+
+	return signedInputs
+}
+
+// hasPartialSig returns true if the input already has a partial signature of
+// the given public key.
+func hasPartialSig(in *psbt.PInput, pubKey []byte) bool {
+	for _, sig := range in.PartialSigs {
+		if bytes.Equal(sig.PubKey, pubKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// waitForApproval registers a signing request and blocks until the operator
+// decided about it.
+func (s *Signer) waitForApproval(ctx context.Context, rawPsbt []byte,
+	inputs []uint32) error {
+
+	s.mu.Lock()
+	s.nextID++
+	req := &Request{
+		ID:        s.nextID,
+		Psbt:      rawPsbt,
+		Inputs:    inputs,
+		CreatedAt: time.Now(),
+		decision:  make(chan bool, 1),
+	}
+	s.pending[req.ID] = req
+	s.mu.Unlock()
+
+	log.Infof("Hardware wallet signing request %d is waiting for "+
+		"approval", req.ID)
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, req.ID)
+		s.mu.Unlock()
+	}()
+
+	select {
+	case approved := <-req.decision:
+		if !approved {
+			return ErrRequestDenied
+		}
+
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PendingRequests returns all signing requests that wait for the approval of
+// the operator.
+func (s *Signer) PendingRequests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]*Request, 0, len(s.pending))
+	for _, req := range s.pending {
+		requests = append(requests, req)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].ID < requests[j].ID
+	})
+
+	return requests
+}
+
+// Decide approves or denies the pending signing request with the given ID.
+func (s *Signer) Decide(id uint64, approve bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, ok := s.pending[id]
+	if !ok {
+		return ErrUnknownRequest
+	}
+
+	// The request is removed right away so it can't be decided twice.
+	delete(s.pending, id)
+	req.decision <- approve
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package hwisigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testFingerprint = "d34db33f"
+
+	// testFingerprintLE is the test fingerprint as it is stored in a
+	// PSBT.
+	testFingerprintLE = 0x3fb34dd3
+)
+
+var (
+	testPrivKey1, testPubKey1 = testKey(1)
+	_, testPubKey2            = testKey(2)
+
+	testSig = append(
+		ecdsa.Sign(testPrivKey1, chainhash.HashB([]byte{1})).
+			Serialize(),
+		byte(txscript.SigHashAll),
+	)
+)
+
+// testKey returns a private key and its serialized public key.
+func testKey(b byte) (*btcec.PrivateKey, []byte) {
+	privKey, pubKey := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{b}, 32))
+
+	return privKey, pubKey.SerializeCompressed()
+}
+
+// testPacket creates a packet with a wallet input of the device, an input of
+// lnd's channel key family derived from the same master key and an input of
+// another master key.
+func testPacket(t *testing.T) *psbt.Packet {
+	t.Helper()
+
+	tx := wire.NewMsgTx(2)
+	for i := 0; i < 3; i++ {
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{byte(i)},
+				Index: uint32(i),
+			},
+		})
+	}
+	tx.AddTxOut(&wire.TxOut{
+		Value:    10_000,
+		PkScript: append([]byte{0x00, 0x14}, make([]byte, 20)...),
+	})
+
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	require.NoError(t, err)
+
+	const h = hdkeychain.HardenedKeyStart
+	packet.Inputs[0].Bip32Derivation = []*psbt.Bip32Derivation{{
+		PubKey:               testPubKey1,
+		MasterKeyFingerprint: testFingerprintLE,
+		Bip32Path:            []uint32{h + 84, h + 1, h, 0, 0},
+	}}
+	packet.Inputs[1].Bip32Derivation = []*psbt.Bip32Derivation{{
+		PubKey:               testPubKey2,
+		MasterKeyFingerprint: testFingerprintLE,
+		Bip32Path:            []uint32{h + 1017, h + 1, h, 0, 0},
+	}}
+	packet.Inputs[2].Bip32Derivation = []*psbt.Bip32Derivation{{
+		PubKey:               testPubKey2,
+		MasterKeyFingerprint: 1,
+		Bip32Path:            []uint32{h + 84, h + 1, h, 0, 0},
+	}}
+
+	return packet
+}
+
+// newTestSigner creates a signer that uses a fake device which signs the
+// first input of the given packet.
+func newTestSigner(t *testing.T, skipApproval bool) (*Signer,
+	*[]string) {
+
+	t.Helper()
+
+	signer, err := New(&Config{
+		Fingerprint:  testFingerprint,
+		ChainParams:  &chaincfg.RegressionNetParams,
+		Timeout:      time.Second,
+		SkipApproval: skipApproval,
+	})
+	require.NoError(t, err)
+
+	var calledArgs []string
+	signer.runCmd = func(_ context.Context, args ...string) ([]byte,
+		error) {
+
+		calledArgs = args
+
+		raw, err := base64.StdEncoding.DecodeString(
+			args[len(args)-1],
+		)
+		require.NoError(t, err)
+
+		packet, err := psbt.NewFromRawBytes(
+			bytes.NewReader(raw), false,
+		)
+		require.NoError(t, err)
+
+		packet.Inputs[0].PartialSigs = []*psbt.PartialSig{{
+			PubKey:    testPubKey1,
+			Signature: testSig,
+		}}
+
+		b64, err := packet.B64Encode()
+		require.NoError(t, err)
+
+		return json.Marshal(&hwiResponse{Psbt: b64})
+	}
+
+	return signer, &calledArgs
+}
+
+// TestInputs tests that only wallet inputs of the device's master key are
+// selected for signing.
+func TestInputs(t *testing.T) {
+	t.Parallel()
+
+	signer, _ := newTestSigner(t, true)
+	packet := testPacket(t)
+
+	require.Equal(t, []uint32{0}, signer.Inputs(packet))
+
+	packet.Inputs[0].FinalScriptWitness = []byte{0x01}
+	require.Empty(t, signer.Inputs(packet))
+}
+
+// TestSignPsbt tests that the signatures of the device are merged into the
+// packet.
+func TestSignPsbt(t *testing.T) {
+	t.Parallel()
+
+	signer, args := newTestSigner(t, true)
+	packet := testPacket(t)
+
+	signed, err := signer.SignPsbt(context.Background(), packet)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{0}, signed)
+	require.Equal(t, testSig, packet.Inputs[0].PartialSigs[0].Signature)
+	require.Empty(t, packet.Inputs[1].PartialSigs)
+
+	require.Equal(t, []string{
+		"--fingerprint", testFingerprint, "--chain", "regtest",
+		"signtx",
+	}, (*args)[:len(*args)-1])
+}
+
+// TestApproval tests that signing requests wait for the decision of the
+// operator.
+func TestApproval(t *testing.T) {
+	t.Parallel()
+
+	signer, _ := newTestSigner(t, false)
+
+	sign := func() chan error {
+		errChan := make(chan error, 1)
+		go func() {
+			_, err := signer.SignPsbt(
+				context.Background(), testPacket(t),
+			)
+			errChan <- err
+		}()
+
+		return errChan
+	}
+
+	waitForRequest := func() *Request {
+		var requests []*Request
+		require.Eventually(t, func() bool {
+			requests = signer.PendingRequests()
+			return len(requests) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		return requests[0]
+	}
+
+	// A denied request must not be forwarded to the device.
+	errChan := sign()
+	req := waitForRequest()
+	require.Equal(t, []uint32{0}, req.Inputs)
+	require.NoError(t, signer.Decide(req.ID, false))
+	require.ErrorIs(t, <-errChan, ErrRequestDenied)
+
+	// A request can only be decided once.
+	require.ErrorIs(t, signer.Decide(req.ID, true), ErrUnknownRequest)
+
+	errChan = sign()
+	req = waitForRequest()
+	require.NoError(t, signer.Decide(req.ID, true))
+	require.NoError(t, <-errChan)
+	require.Empty(t, signer.PendingRequests())
+}
@@ -320,6 +320,12 @@ type WalletController interface {
 		dryRun bool) (*waddrmgr.AccountProperties, []btcutil.Address,
 		[]btcutil.Address, error)
 
+	// CreateAccount creates a new account that is derived from the
+	// wallet's seed, in the key scope of the given address type. The
+	// account's funds are kept apart from those of all other accounts.
+	CreateAccount(name string,
+		addrType AddressType) (*waddrmgr.AccountProperties, error)
+
 	// ImportPublicKey imports a single derived public key into the wallet.
 	// The address type can usually be inferred from the key's version, but
 	// in the case of legacy versions (xpub, tpub), an address type must be
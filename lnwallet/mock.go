@@ -130,6 +130,13 @@ func (w *mockWalletController) ImportAccount(string, *hdkeychain.ExtendedKey,
 	return nil, nil, nil, nil
 }
 
+// CreateAccount currently returns a dummy value.
+func (w *mockWalletController) CreateAccount(string,
+	AddressType) (*waddrmgr.AccountProperties, error) {
+
+	return nil, nil
+}
+
 // ImportPublicKey currently returns a dummy value.
 func (w *mockWalletController) ImportPublicKey(*btcec.PublicKey,
 	waddrmgr.AddressType) error {
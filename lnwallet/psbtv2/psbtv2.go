@@ -0,0 +1,609 @@
+package psbtv2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// Version0 is the original PSBT version defined in BIP 174, which
+	// carries the unsigned transaction in its global map.
+	Version0 uint32 = 0
+
+	// Version2 is the PSBT version defined in BIP 370, which carries the
+	// fields of the unsigned transaction in its input and output maps.
+	Version2 uint32 = 2
+
+	// lockTimeThreshold is the value below which a lock time is
+	// interpreted as a block height rather than a unix timestamp.
+	lockTimeThreshold = 500_000_000
+)
+
+// The key types of BIP 370 that are used to describe the unsigned
+// transaction of a version 2 PSBT.
+const (
+	globalUnsignedTx         = 0x00
+	globalTxVersion          = 0x02
+	globalFallbackLockTime   = 0x03
+	globalInputCount         = 0x04
+	globalOutputCount        = 0x05
+	globalTxModifiable       = 0x06
+	globalVersion            = 0xfb
+	inPreviousTxid           = 0x0e
+	inOutputIndex            = 0x0f
+	inSequence               = 0x10
+	inRequiredTimeLockTime   = 0x11
+	inRequiredHeightLockTime = 0x12
+	outAmount                = 0x03
+	outScript                = 0x04
+)
+
+var (
+	// psbtMagic is the separator that starts every serialized PSBT.
+	psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+	// ErrUnsupportedVersion is returned if a PSBT is of a version other
+	// than 0 or 2.
+	ErrUnsupportedVersion = errors.New("unsupported PSBT version")
+
+	// ErrMissingField is returned if a version 2 PSBT lacks a field that
+	// is required to construct its unsigned transaction.
+	ErrMissingField = errors.New("PSBT is missing required field")
+)
+
+// kvPair is a single key-value pair of a PSBT map.
+type kvPair struct {
+	key   []byte
+	value []byte
+}
+
+// keyType returns the type of the key-value pair.
+func (kv kvPair) keyType() byte {
+	return kv.key[0]
+}
+
+// rawPacket is a PSBT that was split into its maps, without interpreting any
+// of their fields.
+type rawPacket struct {
+	global  []kvPair
+	inputs  [][]kvPair
+	outputs [][]kvPair
+}
+
+// Version returns the version of the serialized PSBT.
+func Version(raw []byte) (uint32, error) {
+	r := bytes.NewReader(raw)
+	if err := readMagic(r); err != nil {
+		return 0, err
+	}
+
+	global, err := readMap(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return mapVersion(global)
+}
+
+// Parse parses a serialized PSBT of version 0 or 2 into a packet and returns
+// it together with the version it was serialized in. Version 2 PSBTs are
+// converted, so the packet always carries an unsigned transaction.
+func Parse(raw []byte) (*psbt.Packet, uint32, error) {
+	version, err := Version(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch version {
+	case Version0:
+		packet, err := psbt.NewFromRawBytes(
+			bytes.NewReader(raw), false,
+		)
+
+		return packet, Version0, err
+
+	case Version2:
+		v0, err := convertToV0(raw)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		packet, err := psbt.NewFromRawBytes(bytes.NewReader(v0), false)
+
+		return packet, Version2, err
+
+	default:
+		return nil, 0, fmt.Errorf("%w: %d", ErrUnsupportedVersion,
+			version)
+	}
+}
+
+// Serialize serializes the packet as a PSBT of the given version.
+func Serialize(packet *psbt.Packet, version uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	switch version {
+	case Version0:
+		return buf.Bytes(), nil
+
+	case Version2:
+		return convertToV2(buf.Bytes(), packet.UnsignedTx)
+
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion,
+			version)
+	}
+}
+
+// convertToV0 converts a serialized version 2 PSBT into version 0 by moving
+// the transaction fields of its maps into an unsigned transaction.
+func convertToV0(raw []byte) ([]byte, error) {
+	r := bytes.NewReader(raw)
+	if err := readMagic(r); err != nil {
+		return nil, err
+	}
+
+	global, err := readMap(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := wire.NewMsgTx(2)
+	var (
+		numInputs, numOutputs           uint64
+		haveInputCount, haveOutputCount bool
+		fallbackLockTime                uint32
+		otherGlobal                     []kvPair
+	)
+	for _, kv := range global {
+		switch kv.keyType() {
+		case globalTxVersion:
+			v, err := uint32Value(kv)
+			if err != nil {
+				return nil, err
+			}
+			tx.Version = int32(v)
+
+		case globalFallbackLockTime:
+			fallbackLockTime, err = uint32Value(kv)
+			if err != nil {
+				return nil, err
+			}
+
+		case globalInputCount:
+			numInputs, err = varIntValue(kv)
+			if err != nil {
+				return nil, err
+			}
+			haveInputCount = true
+
+		case globalOutputCount:
+			numOutputs, err = varIntValue(kv)
+			if err != nil {
+				return nil, err
+			}
+			haveOutputCount = true
+
+		// The version is implied by the unsigned transaction and
+		// nothing can be modified anymore once it's fixed.
+		case globalVersion, globalTxModifiable:
+
+		case globalUnsignedTx:
+			return nil, fmt.Errorf("version 2 PSBT must not " +
+				"contain an unsigned transaction")
+
+		default:
+			otherGlobal = append(otherGlobal, kv)
+		}
+	}
+	if !haveInputCount || !haveOutputCount {
+		return nil, fmt.Errorf("%w: input or output count",
+			ErrMissingField)
+	}
+
+	// Every map takes at least the byte of its separator, which bounds
+	// the number of maps we need to allocate.
+	if numInputs+numOutputs > uint64(r.Len()) {
+		return nil, fmt.Errorf("input and output count exceed PSBT " +
+			"size")
+	}
+
+	var (
+		inputs                       = make([][]kvPair, numInputs)
+		heightLocks, timeLocks       []uint32
+		onlyHeightLock, onlyTimeLock bool
+	)
+	for i := range inputs {
+		inMap, err := readMap(r)
+		if err != nil {
+			return nil, err
+		}
+
+		txIn := &wire.TxIn{Sequence: wire.MaxTxInSequenceNum}
+		var (
+			haveTxid, haveIndex        bool
+			heightLock, timeLock       uint32
+			hasHeightLock, hasTimeLock bool
+		)
+		for _, kv := range inMap {
+			switch kv.keyType() {
+			case inPreviousTxid:
+				if len(kv.value) != chainhash.HashSize {
+					return nil, fmt.Errorf("invalid "+
+						"previous txid of input %d", i)
+				}
+				copy(txIn.PreviousOutPoint.Hash[:], kv.value)
+				haveTxid = true
+
+			case inOutputIndex:
+				txIn.PreviousOutPoint.Index, err = uint32Value(
+					kv,
+				)
+				if err != nil {
+					return nil, err
+				}
+				haveIndex = true
+
+			case inSequence:
+				txIn.Sequence, err = uint32Value(kv)
+				if err != nil {
+					return nil, err
+				}
+
+			case inRequiredTimeLockTime:
+				timeLock, err = uint32Value(kv)
+				if err != nil {
+					return nil, err
+				}
+				if timeLock < lockTimeThreshold {
+					return nil, fmt.Errorf("invalid "+
+						"time lock of input %d", i)
+				}
+				hasTimeLock = true
+
+			case inRequiredHeightLockTime:
+				heightLock, err = uint32Value(kv)
+				if err != nil {
+					return nil, err
+				}
+				if heightLock == 0 ||
+					heightLock >= lockTimeThreshold {
+
+					return nil, fmt.Errorf("invalid "+
+						"height lock of input %d", i)
+				}
+				hasHeightLock = true
+
+			default:
+				inputs[i] = append(inputs[i], kv)
+			}
+		}
+		if !haveTxid || !haveIndex {
+			return nil, fmt.Errorf("%w: outpoint of input %d",
+				ErrMissingField, i)
+		}
+		tx.AddTxIn(txIn)
+
+		if hasHeightLock {
+			heightLocks = append(heightLocks, heightLock)
+		}
+		if hasTimeLock {
+			timeLocks = append(timeLocks, timeLock)
+		}
+		switch {
+		case hasHeightLock && !hasTimeLock:
+			onlyHeightLock = true
+
+		case hasTimeLock && !hasHeightLock:
+			onlyTimeLock = true
+		}
+	}
+
+	// Determine the lock time as defined in BIP 370. Inputs that support
+	// both kinds of lock times let us pick either, in which case a height
+	// based lock time is preferred.
+	switch {
+	case onlyHeightLock && onlyTimeLock:
+		return nil, fmt.Errorf("inputs require both height and time " +
+			"based lock times")
+
+	case len(heightLocks) == 0 && len(timeLocks) == 0:
+		tx.LockTime = fallbackLockTime
+
+	case onlyTimeLock:
+		tx.LockTime = maxUint32(timeLocks)
+
+	default:
+		tx.LockTime = maxUint32(heightLocks)
+	}
+
+	outputs := make([][]kvPair, numOutputs)
+	for i := range outputs {
+		outMap, err := readMap(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			txOut                  wire.TxOut
+			haveAmount, haveScript bool
+		)
+		for _, kv := range outMap {
+			switch kv.keyType() {
+			case outAmount:
+				if len(kv.value) != 8 {
+					return nil, fmt.Errorf("invalid "+
+						"amount of output %d", i)
+				}
+				txOut.Value = int64(
+					binary.LittleEndian.Uint64(kv.value),
+				)
+				haveAmount = true
+
+			case outScript:
+				txOut.PkScript = kv.value
+				haveScript = true
+
+			default:
+				outputs[i] = append(outputs[i], kv)
+			}
+		}
+		if !haveAmount || !haveScript {
+			return nil, fmt.Errorf("%w: amount or script of "+
+				"output %d", ErrMissingField, i)
+		}
+		tx.AddTxOut(&txOut)
+	}
+
+	var txBuf bytes.Buffer
+	if err := tx.SerializeNoWitness(&txBuf); err != nil {
+		return nil, err
+	}
+
+	global = append([]kvPair{{
+		key:   []byte{globalUnsignedTx},
+		value: txBuf.Bytes(),
+	}}, otherGlobal...)
+
+	return writePacket(&rawPacket{
+		global:  global,
+		inputs:  inputs,
+		outputs: outputs,
+	})
+}
+
+// convertToV2 converts a serialized version 0 PSBT into version 2 by moving
+// the fields of its unsigned transaction into its maps.
+func convertToV2(raw []byte, tx *wire.MsgTx) ([]byte, error) {
+	packet, err := readPacket(raw, len(tx.TxIn), len(tx.TxOut))
+	if err != nil {
+		return nil, err
+	}
+
+	global := []kvPair{
+		uint32Pair(globalTxVersion, uint32(tx.Version)),
+		uint32Pair(globalFallbackLockTime, tx.LockTime),
+		varIntPair(globalInputCount, uint64(len(tx.TxIn))),
+		varIntPair(globalOutputCount, uint64(len(tx.TxOut))),
+	}
+	for _, kv := range packet.global {
+		switch kv.keyType() {
+		case globalUnsignedTx, globalVersion:
+			continue
+		}
+		global = append(global, kv)
+	}
+	global = append(global, uint32Pair(globalVersion, Version2))
+	packet.global = global
+
+	for i, txIn := range tx.TxIn {
+		outPoint := txIn.PreviousOutPoint
+		packet.inputs[i] = append([]kvPair{
+			{
+				key:   []byte{inPreviousTxid},
+				value: outPoint.Hash[:],
+			},
+			uint32Pair(inOutputIndex, outPoint.Index),
+			uint32Pair(inSequence, txIn.Sequence),
+		}, packet.inputs[i]...)
+	}
+
+	for i, txOut := range tx.TxOut {
+		var amount [8]byte
+		binary.LittleEndian.PutUint64(amount[:], uint64(txOut.Value))
+
+		packet.outputs[i] = append([]kvPair{
+			{key: []byte{outAmount}, value: amount[:]},
+			{key: []byte{outScript}, value: txOut.PkScript},
+		}, packet.outputs[i]...)
+	}
+
+	return writePacket(packet)
+}
+
+// mapVersion returns the PSBT version stored in the given global map.
+func mapVersion(global []kvPair) (uint32, error) {
+	for _, kv := range global {
+		if kv.keyType() == globalVersion && len(kv.key) == 1 {
+			return uint32Value(kv)
+		}
+	}
+
+	return Version0, nil
+}
+
+// readPacket splits a serialized PSBT with the given number of inputs and
+// outputs into its maps.
+func readPacket(raw []byte, numInputs, numOutputs int) (*rawPacket, error) {
+	r := bytes.NewReader(raw)
+	if err := readMagic(r); err != nil {
+		return nil, err
+	}
+
+	global, err := readMap(r)
+	if err != nil {
+		return nil, err
+	}
+
+	packet := &rawPacket{
+		global:  global,
+		inputs:  make([][]kvPair, numInputs),
+		outputs: make([][]kvPair, numOutputs),
+	}
+	for i := range packet.inputs {
+		packet.inputs[i], err = readMap(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range packet.outputs {
+		packet.outputs[i], err = readMap(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return packet, nil
+}
+
+// writePacket serializes the maps of a PSBT.
+func writePacket(packet *rawPacket) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(psbtMagic)
+
+	maps := append([][]kvPair{packet.global}, packet.inputs...)
+	maps = append(maps, packet.outputs...)
+	for _, m := range maps {
+		if err := writeMap(&buf, m); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readMagic reads the magic bytes that start every PSBT.
+func readMagic(r io.Reader) error {
+	var magic [5]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return err
+	}
+	if !bytes.Equal(magic[:], psbtMagic) {
+		return psbt.ErrInvalidMagicBytes
+	}
+
+	return nil
+}
+
+// readMap reads the key-value pairs of a single PSBT map up to and including
+// its separator.
+func readMap(r io.Reader) ([]kvPair, error) {
+	var pairs []kvPair
+	for {
+		keyLen, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return nil, psbt.ErrInvalidPsbtFormat
+		}
+		if keyLen == 0 {
+			return pairs, nil
+		}
+		if keyLen > psbt.MaxPsbtKeyLength {
+			return nil, psbt.ErrInvalidKeyData
+		}
+
+		key := make([]byte, keyLen)
+		if _, err := io.ReadFull(r, key); err != nil {
+			return nil, err
+		}
+
+		value, err := wire.ReadVarBytes(
+			r, 0, psbt.MaxPsbtValueLength, "PSBT value",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, kvPair{key: key, value: value})
+	}
+}
+
+// writeMap writes the key-value pairs of a single PSBT map followed by its
+// separator.
+func writeMap(w io.Writer, pairs []kvPair) error {
+	for _, kv := range pairs {
+		if err := wire.WriteVarBytes(w, 0, kv.key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, kv.value); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write([]byte{0x00})
+
+	return err
+}
+
+// uint32Value decodes the value of a key-value pair as a little endian 32-bit
+// integer.
+func uint32Value(kv kvPair) (uint32, error) {
+	if len(kv.value) != 4 {
+		return 0, fmt.Errorf("invalid value of PSBT key type %#x",
+			kv.keyType())
+	}
+
+	return binary.LittleEndian.Uint32(kv.value), nil
+}
+
+// varIntValue decodes the value of a key-value pair as a compact size
+// integer.
+func varIntValue(kv kvPair) (uint64, error) {
+	r := bytes.NewReader(kv.value)
+	v, err := wire.ReadVarInt(r, 0)
+	if err != nil || r.Len() != 0 {
+		return 0, fmt.Errorf("invalid value of PSBT key type %#x",
+			kv.keyType())
+	}
+
+	return v, nil
+}
+
+// uint32Pair returns a key-value pair of the given type with a little endian
+// 32-bit integer value.
+func uint32Pair(keyType byte, v uint32) kvPair {
+	var value [4]byte
+	binary.LittleEndian.PutUint32(value[:], v)
+
+	return kvPair{key: []byte{keyType}, value: value[:]}
+}
+
+// varIntPair returns a key-value pair of the given type with a compact size
+// integer value.
+func varIntPair(keyType byte, v uint64) kvPair {
+	var value bytes.Buffer
+	_ = wire.WriteVarInt(&value, 0, v)
+
+	return kvPair{key: []byte{keyType}, value: value.Bytes()}
+}
+
+// maxUint32 returns the largest of the given values.
+func maxUint32(values []uint32) uint32 {
+	var result uint32
+	for _, v := range values {
+		if v > result {
+			result = v
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,282 @@
+package psbtv2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// testPacket returns a version 0 packet with two inputs and two outputs that
+// carries input, output and global fields besides the unsigned transaction.
+func testPacket(t *testing.T) *psbt.Packet {
+	t.Helper()
+
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = 800_000
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{1, 2, 3},
+			Index: 1,
+		},
+		Sequence: wire.MaxTxInSequenceNum - 1,
+	})
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{4, 5, 6},
+			Index: 7,
+		},
+		Sequence: wire.MaxTxInSequenceNum,
+	})
+	tx.AddTxOut(&wire.TxOut{
+		Value:    100_000,
+		PkScript: append([]byte{0x00, 0x14}, make([]byte, 20)...),
+	})
+	tx.AddTxOut(&wire.TxOut{
+		Value:    50_000,
+		PkScript: append([]byte{0x51, 0x20}, make([]byte, 32)...),
+	})
+
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	require.NoError(t, err)
+
+	packet.Inputs[0].WitnessUtxo = &wire.TxOut{
+		Value: 200_000,
+		PkScript: append(
+			[]byte{0x00, 0x14}, bytes.Repeat([]byte{1}, 20)...,
+		),
+	}
+	packet.Inputs[1].SighashType = 1
+	packet.Outputs[1].TaprootInternalKey = bytes.Repeat([]byte{2}, 32)
+	packet.Unknowns = []*psbt.Unknown{{
+		Key:   []byte{0xfc, 0x01, 0x02},
+		Value: []byte{0x03},
+	}}
+
+	return packet
+}
+
+// TestRoundTrip tests that a packet keeps all of its fields when it is
+// serialized as a version 2 PSBT and parsed again.
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	packet := testPacket(t)
+
+	v0, err := Serialize(packet, Version0)
+	require.NoError(t, err)
+
+	version, err := Version(v0)
+	require.NoError(t, err)
+	require.Equal(t, Version0, version)
+
+	v2, err := Serialize(packet, Version2)
+	require.NoError(t, err)
+
+	version, err = Version(v2)
+	require.NoError(t, err)
+	require.Equal(t, Version2, version)
+
+	// The version 2 PSBT must not be accepted as version 0, as it lacks
+	// the unsigned transaction.
+	_, err = psbt.NewFromRawBytes(bytes.NewReader(v2), false)
+	require.Error(t, err)
+
+	parsed, version, err := Parse(v2)
+	require.NoError(t, err)
+	require.Equal(t, Version2, version)
+	require.Equal(t, packet.UnsignedTx.TxHash(), parsed.UnsignedTx.TxHash())
+	require.Equal(t, packet.Inputs, parsed.Inputs)
+	require.Equal(t, packet.Outputs, parsed.Outputs)
+	require.Equal(t, packet.Unknowns, parsed.Unknowns)
+
+	// Serializing the parsed packet as version 0 results in the original
+	// version 0 PSBT.
+	reserialized, err := Serialize(parsed, Version0)
+	require.NoError(t, err)
+	require.Equal(t, v0, reserialized)
+
+	_, err = Serialize(packet, 1)
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+// setInputField replaces or adds a field of the given input map.
+func setInputField(packet *rawPacket, input int, kv kvPair) {
+	for i, existing := range packet.inputs[input] {
+		if existing.keyType() == kv.keyType() {
+			packet.inputs[input][i] = kv
+			return
+		}
+	}
+
+	packet.inputs[input] = append(packet.inputs[input], kv)
+}
+
+// TestLockTime tests that the lock time of the unsigned transaction is
+// determined from the required lock times of the inputs as defined in BIP 370.
+func TestLockTime(t *testing.T) {
+	t.Parallel()
+
+	const (
+		fallback = 800_000
+		time1    = 1_700_000_000
+		time2    = 1_700_000_100
+	)
+
+	testCases := []struct {
+		name     string
+		locks    [2][]kvPair
+		lockTime uint32
+		err      bool
+	}{{
+		name:     "fallback",
+		lockTime: fallback,
+	}, {
+		name: "max height",
+		locks: [2][]kvPair{{
+			uint32Pair(inRequiredHeightLockTime, 100),
+		}, {
+			uint32Pair(inRequiredHeightLockTime, 200),
+		}},
+		lockTime: 200,
+	}, {
+		name: "max time",
+		locks: [2][]kvPair{{
+			uint32Pair(inRequiredTimeLockTime, time2),
+		}, {
+			uint32Pair(inRequiredTimeLockTime, time1),
+		}},
+		lockTime: time2,
+	}, {
+		name: "height preferred if both supported",
+		locks: [2][]kvPair{{
+			uint32Pair(inRequiredTimeLockTime, time1),
+			uint32Pair(inRequiredHeightLockTime, 100),
+		}, {
+			uint32Pair(inRequiredTimeLockTime, time2),
+			uint32Pair(inRequiredHeightLockTime, 50),
+		}},
+		lockTime: 100,
+	}, {
+		name: "time required by one input",
+		locks: [2][]kvPair{{
+			uint32Pair(inRequiredTimeLockTime, time1),
+			uint32Pair(inRequiredHeightLockTime, 100),
+		}, {
+			uint32Pair(inRequiredTimeLockTime, time2),
+		}},
+		lockTime: time2,
+	}, {
+		name: "conflicting lock times",
+		locks: [2][]kvPair{{
+			uint32Pair(inRequiredHeightLockTime, 100),
+		}, {
+			uint32Pair(inRequiredTimeLockTime, time1),
+		}},
+		err: true,
+	}, {
+		name: "height lock out of range",
+		locks: [2][]kvPair{{
+			uint32Pair(inRequiredHeightLockTime, time1),
+		}},
+		err: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			packet := testPacket(t)
+			v2, err := Serialize(packet, Version2)
+			require.NoError(t, err)
+
+			raw, err := readPacket(v2, 2, 2)
+			require.NoError(t, err)
+
+			for input, locks := range tc.locks {
+				for _, kv := range locks {
+					setInputField(raw, input, kv)
+				}
+			}
+
+			v2, err = writePacket(raw)
+			require.NoError(t, err)
+
+			parsed, _, err := Parse(v2)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(
+				t, tc.lockTime, parsed.UnsignedTx.LockTime,
+			)
+		})
+	}
+}
+
+// TestMissingFields tests that version 2 PSBTs lacking the fields of the
+// unsigned transaction are rejected.
+func TestMissingFields(t *testing.T) {
+	t.Parallel()
+
+	v2, err := Serialize(testPacket(t), Version2)
+	require.NoError(t, err)
+
+	removeField := func(maps [][]kvPair, keyType byte) {
+		for i := range maps {
+			maps[i] = withoutField(maps[i], keyType)
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(*rawPacket)
+	}{{
+		name: "input count",
+		modify: func(p *rawPacket) {
+			p.global = withoutField(p.global, globalInputCount)
+		},
+	}, {
+		name: "previous txid",
+		modify: func(p *rawPacket) {
+			removeField(p.inputs, inPreviousTxid)
+		},
+	}, {
+		name: "output script",
+		modify: func(p *rawPacket) {
+			removeField(p.outputs, outScript)
+		},
+	}}
+
+	for _, tc := range testCases {
+		raw, err := readPacket(v2, 2, 2)
+		require.NoError(t, err)
+
+		tc.modify(raw)
+
+		modified, err := writePacket(raw)
+		require.NoError(t, err)
+
+		_, _, err = Parse(modified)
+		require.ErrorIs(t, err, ErrMissingField, tc.name)
+	}
+}
+
+// withoutField returns the map without the fields of the given type.
+func withoutField(m []kvPair, keyType byte) []kvPair {
+	var kept []kvPair
+	for _, kv := range m {
+		if kv.keyType() != keyType {
+			kept = append(kept, kv)
+		}
+	}
+
+	return kept
+}
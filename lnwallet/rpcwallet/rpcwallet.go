@@ -32,6 +32,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -45,6 +46,12 @@ var (
 	// supported in remote signing mode.
 	ErrRemoteSigningPrivateKeyNotAvailable = errors.New("deriving " +
 		"private key is not supported by RPC based key ring")
+
+	// ErrRemoteSignerDeclined is the error that is returned if the remote
+	// signer refused to approve an operation, for example because of its
+	// signing policy.
+	ErrRemoteSignerDeclined = errors.New("remote signer declined to " +
+		"approve the operation")
 )
 
 // RPCKeyRing is an implementation of the SecretKeyRing interface that uses a
@@ -68,6 +75,7 @@ type RPCKeyRing struct {
 
 var _ keychain.SecretKeyRing = (*RPCKeyRing)(nil)
 var _ input.Signer = (*RPCKeyRing)(nil)
+var _ input.BatchSigner = (*RPCKeyRing)(nil)
 var _ keychain.MessageSignerRing = (*RPCKeyRing)(nil)
 var _ lnwallet.WalletController = (*RPCKeyRing)(nil)
 
@@ -210,7 +218,7 @@ func (r *RPCKeyRing) SignPsbt(packet *psbt.Packet) ([]uint32, error) {
 	if err != nil {
 		considerShutdown(err)
 		return nil, fmt.Errorf("error signing PSBT in remote signer "+
-			"instance: %v", err)
+			"instance: %w", signerErr(err))
 	}
 
 	signedPacket, err := psbt.NewFromRawBytes(
@@ -421,7 +429,7 @@ func (r *RPCKeyRing) ECDH(keyDesc keychain.KeyDescriptor,
 	if err != nil {
 		considerShutdown(err)
 		return key, fmt.Errorf("error deriving shared key in remote "+
-			"signer instance: %v", err)
+			"signer instance: %w", signerErr(err))
 	}
 
 	copy(key[:], resp.SharedKey)
@@ -451,7 +459,7 @@ func (r *RPCKeyRing) SignMessage(keyLoc keychain.KeyLocator,
 	if err != nil {
 		considerShutdown(err)
 		return nil, fmt.Errorf("error signing message in remote "+
-			"signer instance: %v", err)
+			"signer instance: %w", signerErr(err))
 	}
 
 	wireSig, err := lnwire.NewSigFromECDSARawSignature(resp.Signature)
@@ -498,7 +506,7 @@ func (r *RPCKeyRing) SignMessageCompact(keyLoc keychain.KeyLocator,
 	if err != nil {
 		considerShutdown(err)
 		return nil, fmt.Errorf("error signing message in remote "+
-			"signer instance: %v", err)
+			"signer instance: %w", signerErr(err))
 	}
 
 	// The signature in the response is zbase32 encoded, so we need to
@@ -533,7 +541,7 @@ func (r *RPCKeyRing) SignMessageSchnorr(keyLoc keychain.KeyLocator,
 	if err != nil {
 		considerShutdown(err)
 		return nil, fmt.Errorf("error signing message in remote "+
-			"signer instance: %w", err)
+			"signer instance: %w", signerErr(err))
 	}
 
 	sigParsed, err := schnorr.ParseSignature(resp.Signature)
@@ -579,6 +587,116 @@ func (r *RPCKeyRing) SignOutputRaw(tx *wire.MsgTx,
 	return r.remoteSign(tx, signDesc, nil)
 }
 
+// SignOutputRawBatch generates a signature for each of the passed
+// transactions according to the SignDescriptor at the same index. All PSBTs
+// are sent to the remote signer in a single round trip and are signed or
+// rejected as a whole.
+//
+// NOTE: The resulting signatures should be void of a sighash byte.
+//
+// NOTE: This method is part of the input.BatchSigner interface.
+//
+// NOTE: This method only signs with BIP1017 (internal) keys!
+func (r *RPCKeyRing) SignOutputRawBatch(txs []*wire.MsgTx,
+	signDescs []*input.SignDescriptor) ([]input.Signature, error) {
+
+	if len(txs) != len(signDescs) {
+		return nil, fmt.Errorf("got %d transactions but %d sign "+
+			"descriptors", len(txs), len(signDescs))
+	}
+
+	req := &walletrpc.SignPsbtBatchRequest{
+		FundedPsbts: make([][]byte, len(txs)),
+	}
+	for i, tx := range txs {
+		packet, err := r.signPacket(tx, signDescs[i], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := packet.Serialize(&buf); err != nil {
+			return nil, fmt.Errorf("error serializing PSBT: %w",
+				err)
+		}
+		req.FundedPsbts[i] = buf.Bytes()
+	}
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	signedPsbts, err := r.signPsbtBatch(ctxt, req)
+	if err != nil {
+		considerShutdown(err)
+		return nil, fmt.Errorf("error signing PSBT batch in remote "+
+			"signer instance: %w", signerErr(err))
+	}
+
+	if len(signedPsbts) != len(txs) {
+		return nil, fmt.Errorf("remote signer returned %d PSBTs for "+
+			"batch of %d", len(signedPsbts), len(txs))
+	}
+
+	sigs := make([]input.Signature, len(txs))
+	for i, signedPsbt := range signedPsbts {
+		sigs[i], err = signatureFromPsbt(signedPsbt, signDescs[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sigs, nil
+}
+
+// signPsbtBatch signs all PSBTs of the request with the remote signer and
+// returns the signed PSBTs in the same order. If the remote signer doesn't
+// know the SignPsbtBatch RPC yet, the PSBTs are signed with concurrent
+// SignPsbt calls instead, so the batch still only takes a single round trip.
+func (r *RPCKeyRing) signPsbtBatch(ctx context.Context,
+	req *walletrpc.SignPsbtBatchRequest) ([][]byte, error) {
+
+	resp, err := r.walletClient.SignPsbtBatch(ctx, req)
+	statusErr, isStatusErr := status.FromError(err)
+	switch {
+	case err == nil:
+		signedPsbts := make([][]byte, len(resp.Responses))
+		for i, signResp := range resp.Responses {
+			signedPsbts[i] = signResp.SignedPsbt
+		}
+
+		return signedPsbts, nil
+
+	case !isStatusErr || statusErr.Code() != codes.Unimplemented:
+		return nil, err
+	}
+
+	signedPsbts := make([][]byte, len(req.FundedPsbts))
+	group, ctx := errgroup.WithContext(ctx)
+	for i, fundedPsbt := range req.FundedPsbts {
+		i, fundedPsbt := i, fundedPsbt
+
+		group.Go(func() error {
+			resp, err := r.walletClient.SignPsbt(
+				ctx, &walletrpc.SignPsbtRequest{
+					FundedPsbt: fundedPsbt,
+				},
+			)
+			if err != nil {
+				return err
+			}
+			signedPsbts[i] = resp.SignedPsbt
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return signedPsbts, nil
+}
+
 // ComputeInputScript generates a complete InputIndex for the passed
 // transaction with the signature as defined within the passed
 // SignDescriptor. This method should be capable of generating the
@@ -718,7 +836,7 @@ func (r *RPCKeyRing) MuSig2CreateSession(bipVersion input.MuSig2Version,
 	if err != nil {
 		considerShutdown(err)
 		return nil, fmt.Errorf("error creating MuSig2 session in "+
-			"remote signer instance: %v", err)
+			"remote signer instance: %w", signerErr(err))
 	}
 
 	// De-Serialize all the info back into our native struct.
@@ -772,7 +890,7 @@ func (r *RPCKeyRing) MuSig2RegisterNonces(sessionID input.MuSig2SessionID,
 	if err != nil {
 		considerShutdown(err)
 		return false, fmt.Errorf("error registering MuSig2 nonces in "+
-			"remote signer instance: %v", err)
+			"remote signer instance: %w", signerErr(err))
 	}
 
 	return resp.HaveAllNonces, nil
@@ -803,7 +921,7 @@ func (r *RPCKeyRing) MuSig2Sign(sessionID input.MuSig2SessionID,
 	if err != nil {
 		considerShutdown(err)
 		return nil, fmt.Errorf("error signing MuSig2 session in "+
-			"remote signer instance: %v", err)
+			"remote signer instance: %w", signerErr(err))
 	}
 
 	partialSig, err := input.DeserializePartialSignature(
@@ -847,7 +965,8 @@ func (r *RPCKeyRing) MuSig2CombineSig(sessionID input.MuSig2SessionID,
 	if err != nil {
 		considerShutdown(err)
 		return nil, false, fmt.Errorf("error combining MuSig2 "+
-			"signatures in remote signer instance: %v", err)
+			"signatures in remote signer instance: %w",
+			signerErr(err))
 	}
 
 	// The final signature is only available when we have all the other
@@ -878,7 +997,7 @@ func (r *RPCKeyRing) MuSig2Cleanup(sessionID input.MuSig2SessionID) error {
 	if err != nil {
 		considerShutdown(err)
 		return fmt.Errorf("error cleaning up MuSig2 session in remote "+
-			"signer instance: %v", err)
+			"signer instance: %w", signerErr(err))
 	}
 
 	return nil
@@ -889,6 +1008,58 @@ func (r *RPCKeyRing) MuSig2Cleanup(sessionID input.MuSig2SessionID) error {
 func (r *RPCKeyRing) remoteSign(tx *wire.MsgTx, signDesc *input.SignDescriptor,
 	sigScript []byte) (input.Signature, error) {
 
+	packet, err := r.signPacket(tx, signDesc, sigScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// Okay, let's sign the input by the remote signer now.
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("error serializing PSBT: %w", err)
+	}
+
+	resp, err := r.walletClient.SignPsbt(
+		ctxt, &walletrpc.SignPsbtRequest{FundedPsbt: buf.Bytes()},
+	)
+	if err != nil {
+		considerShutdown(err)
+		return nil, fmt.Errorf("error signing PSBT in remote signer "+
+			"instance: %w", signerErr(err))
+	}
+
+	return signatureFromPsbt(resp.SignedPsbt, signDesc)
+}
+
+// signatureFromPsbt extracts the signature of the input specified in signDesc
+// from the PSBT signed by the remote signer.
+func signatureFromPsbt(signedPsbt []byte,
+	signDesc *input.SignDescriptor) (input.Signature, error) {
+
+	signedPacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(signedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signed PSBT: %w", err)
+	}
+
+	// We expect a signature in the input now.
+	if signDesc.InputIndex >= len(signedPacket.Inputs) {
+		return nil, fmt.Errorf("remote signer returned invalid PSBT")
+	}
+	in := &signedPacket.Inputs[signDesc.InputIndex]
+
+	return extractSignature(in, signDesc.SignMethod)
+}
+
+// signPacket assembles the PSBT the remote signer needs to sign the input
+// specified in signDesc of the given transaction tx.
+func (r *RPCKeyRing) signPacket(tx *wire.MsgTx, signDesc *input.SignDescriptor,
+	sigScript []byte) (*psbt.Packet, error) {
+
 	packet, err := packetFromTx(tx)
 	if err != nil {
 		return nil, fmt.Errorf("error converting TX into PSBT: %w", err)
@@ -1151,38 +1322,7 @@ func (r *RPCKeyRing) remoteSign(tx *wire.MsgTx, signDesc *input.SignDescriptor,
 		}}
 	}
 
-	// Okay, let's sign the input by the remote signer now.
-	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
-	defer cancel()
-
-	var buf bytes.Buffer
-	if err := packet.Serialize(&buf); err != nil {
-		return nil, fmt.Errorf("error serializing PSBT: %w", err)
-	}
-
-	resp, err := r.walletClient.SignPsbt(
-		ctxt, &walletrpc.SignPsbtRequest{FundedPsbt: buf.Bytes()},
-	)
-	if err != nil {
-		considerShutdown(err)
-		return nil, fmt.Errorf("error signing PSBT in remote signer "+
-			"instance: %v", err)
-	}
-
-	signedPacket, err := psbt.NewFromRawBytes(
-		bytes.NewReader(resp.SignedPsbt), false,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing signed PSBT: %w", err)
-	}
-
-	// We expect a signature in the input now.
-	if signDesc.InputIndex >= len(signedPacket.Inputs) {
-		return nil, fmt.Errorf("remote signer returned invalid PSBT")
-	}
-	in = &signedPacket.Inputs[signDesc.InputIndex]
-
-	return extractSignature(in, signDesc.SignMethod)
+	return packet, nil
 }
 
 // extractSignature attempts to extract the signature from the PSBT input,
@@ -1343,6 +1483,20 @@ func packetFromTx(original *wire.MsgTx) (*psbt.Packet, error) {
 	return packet, nil
 }
 
+// signerErr returns the given error of the remote signer. If the remote signer
+// refused to approve the requested operation, the returned error wraps
+// ErrRemoteSignerDeclined together with the reason given by the signer, so
+// callers can tell it apart from availability problems.
+func signerErr(err error) error {
+	statusErr, isStatusErr := status.FromError(err)
+	if isStatusErr && statusErr.Code() == codes.PermissionDenied {
+		return fmt.Errorf("%w: %s", ErrRemoteSignerDeclined,
+			statusErr.Message())
+	}
+
+	return err
+}
+
 // considerShutdown inspects the error and issues a shutdown (through logging
 // a critical error, which will cause the logger to issue a clean shutdown
 // request) if the error looks like a connection or general availability error
@@ -127,8 +127,9 @@ type SigPool struct {
 
 	signer input.Signer
 
-	verifyJobs chan VerifyJob
-	signJobs   chan SignJob
+	verifyJobs  chan VerifyJob
+	signJobs    chan SignJob
+	signBatches chan []SignJob
 
 	wg   sync.WaitGroup
 	quit chan struct{}
@@ -141,11 +142,12 @@ type SigPool struct {
 // physical CPU cores available on the target machine.
 func NewSigPool(numWorkers int, signer input.Signer) *SigPool {
 	return &SigPool{
-		signer:     signer,
-		numWorkers: numWorkers,
-		verifyJobs: make(chan VerifyJob, jobBuffer),
-		signJobs:   make(chan SignJob, jobBuffer),
-		quit:       make(chan struct{}),
+		signer:      signer,
+		numWorkers:  numWorkers,
+		verifyJobs:  make(chan VerifyJob, jobBuffer),
+		signJobs:    make(chan SignJob, jobBuffer),
+		signBatches: make(chan []SignJob),
+		quit:        make(chan struct{}),
 	}
 }
 
@@ -220,6 +222,13 @@ func (s *SigPool) poolWorker() {
 				return
 			}
 
+		// We've just received a batch of signature jobs for a signer
+		// that signs them all at once.
+		case batch := <-s.signBatches:
+			if !s.signBatch(batch) {
+				return
+			}
+
 		// We've just received a new verification job from the outside
 		// world. We'll attempt to construct the sighash, parse the
 		// signature, and finally verify the signature.
@@ -269,11 +278,66 @@ func (s *SigPool) poolWorker() {
 	}
 }
 
+// signBatch signs all jobs of the batch in a single operation of the batch
+// signer and sends the responses back to the caller. False is returned if the
+// sigPool is exiting.
+func (s *SigPool) signBatch(batch []SignJob) bool {
+	batchSigner, ok := s.signer.(input.BatchSigner)
+	if !ok {
+		// SubmitSignBatch only sends batches for batch signers.
+		return true
+	}
+
+	txs := make([]*wire.MsgTx, len(batch))
+	signDescs := make([]*input.SignDescriptor, len(batch))
+	for i := range batch {
+		txs[i] = batch[i].Tx
+		signDescs[i] = &batch[i].SignDesc
+	}
+
+	rawSigs, err := batchSigner.SignOutputRawBatch(txs, signDescs)
+	if err == nil && len(rawSigs) != len(batch) {
+		err = fmt.Errorf("batch signer returned %d signatures for "+
+			"%d jobs", len(rawSigs), len(batch))
+	}
+
+	for i, job := range batch {
+		resp := SignJobResp{Err: err}
+		if err == nil {
+			resp.Sig, resp.Err = lnwire.NewSigFromSignature(
+				rawSigs[i],
+			)
+		}
+
+		select {
+		case job.Resp <- resp:
+		case <-job.Cancel:
+		case <-s.quit:
+			return false
+		}
+	}
+
+	return true
+}
+
 // SubmitSignBatch submits a batch of signature jobs to the sigPool.  The
 // response and cancel channels for each of the SignJob's are expected to be
 // fully populated, as the response for each job will be sent over the
-// response channel within the job itself.
+// response channel within the job itself. If the signer of the pool is an
+// input.BatchSigner, the whole batch is signed in a single operation, which
+// expects all jobs of the batch to share the same cancel channel.
 func (s *SigPool) SubmitSignBatch(signJobs []SignJob) {
+	_, isBatchSigner := s.signer.(input.BatchSigner)
+	if isBatchSigner && len(signJobs) > 1 {
+		select {
+		case s.signBatches <- signJobs:
+		case <-signJobs[0].Cancel:
+		case <-s.quit:
+		}
+
+		return
+	}
+
 	for _, job := range signJobs {
 		select {
 		case s.signJobs <- job:
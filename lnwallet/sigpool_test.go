@@ -0,0 +1,129 @@
+package lnwallet
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBatchSigner is an input.BatchSigner that signs each transaction of a
+// batch with the embedded mock signer.
+type mockBatchSigner struct {
+	*input.MockSigner
+
+	batches atomic.Int32
+	fail    bool
+}
+
+func (m *mockBatchSigner) SignOutputRawBatch(txs []*wire.MsgTx,
+	signDescs []*input.SignDescriptor) ([]input.Signature, error) {
+
+	m.batches.Add(1)
+	if m.fail {
+		return nil, errors.New("batch declined")
+	}
+
+	sigs := make([]input.Signature, len(txs))
+	for i, tx := range txs {
+		sig, err := m.SignOutputRaw(tx, signDescs[i])
+		if err != nil {
+			return nil, err
+		}
+		sigs[i] = sig
+	}
+
+	return sigs, nil
+}
+
+// testSignJobs creates sign jobs for the given number of transactions that
+// spend a p2wkh output of the given key.
+func testSignJobs(t *testing.T, privKey *btcec.PrivateKey,
+	numJobs int) []SignJob {
+
+	t.Helper()
+
+	pkScript, err := input.WitnessPubKeyHash(
+		privKey.PubKey().SerializeCompressed(),
+	)
+	require.NoError(t, err)
+	prevOut := &wire.TxOut{Value: 10_000, PkScript: pkScript}
+
+	cancel := make(chan struct{})
+	jobs := make([]SignJob, numJobs)
+	for i := range jobs {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Index: uint32(i)},
+		})
+		tx.AddTxOut(&wire.TxOut{Value: 9_000, PkScript: pkScript})
+
+		fetcher := txscript.NewCannedPrevOutputFetcher(
+			prevOut.PkScript, prevOut.Value,
+		)
+		jobs[i] = SignJob{
+			SignDesc: input.SignDescriptor{
+				KeyDesc: keychain.KeyDescriptor{
+					PubKey: privKey.PubKey(),
+				},
+				WitnessScript: pkScript,
+				Output:        prevOut,
+				HashType:      txscript.SigHashAll,
+				SigHashes: txscript.NewTxSigHashes(
+					tx, fetcher,
+				),
+				PrevOutputFetcher: fetcher,
+			},
+			Tx:     tx,
+			Cancel: cancel,
+			Resp:   make(chan SignJobResp, 1),
+		}
+	}
+
+	return jobs
+}
+
+// TestSigPoolBatchSigner tests that a batch of sign jobs is signed in a
+// single operation if the signer of the pool is a batch signer.
+func TestSigPoolBatchSigner(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	signer := &mockBatchSigner{
+		MockSigner: input.NewMockSigner(
+			[]*btcec.PrivateKey{privKey}, nil,
+		),
+	}
+	pool := NewSigPool(2, signer)
+	require.NoError(t, pool.Start())
+	t.Cleanup(func() {
+		require.NoError(t, pool.Stop())
+	})
+
+	jobs := testSignJobs(t, privKey, 3)
+	pool.SubmitSignBatch(jobs)
+	for _, job := range jobs {
+		resp := <-job.Resp
+		require.NoError(t, resp.Err)
+		require.NotEqual(t, make([]byte, 64), resp.Sig.RawBytes())
+	}
+	require.EqualValues(t, 1, signer.batches.Load())
+
+	// If the batch is declined, every job receives the error.
+	signer.fail = true
+	jobs = testSignJobs(t, privKey, 2)
+	pool.SubmitSignBatch(jobs)
+	for _, job := range jobs {
+		resp := <-job.Resp
+		require.ErrorContains(t, resp.Err, "batch declined")
+	}
+	require.EqualValues(t, 2, signer.batches.Load())
+}
@@ -577,6 +577,26 @@ func (l *LightningWallet) ListUnspentWitnessFromDefaultAccount(
 	)
 }
 
+// coinConfirmations returns the number of confirmations of the unspent
+// outputs of the default account.
+//
+// NOTE: This method requires the global coin selection lock to be held.
+func (l *LightningWallet) coinConfirmations() (map[wire.OutPoint]int64,
+	error) {
+
+	utxos, err := l.ListUnspentWitnessFromDefaultAccount(0, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	confs := make(map[wire.OutPoint]int64, len(utxos))
+	for _, utxo := range utxos {
+		confs[utxo.OutPoint] = utxo.Confirmations
+	}
+
+	return confs, nil
+}
+
 // LockedOutpoints returns a list of all currently locked outpoint.
 func (l *LightningWallet) LockedOutpoints() []*wire.OutPoint {
 	outPoints := make([]*wire.OutPoint, 0, len(l.lockedOutPoints))
@@ -854,6 +874,24 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	// If no chanFunder was provided, then we'll assume the default
 	// assembler, which is backed by the wallet's internal coin selection.
 	if req.ChanFunder == nil {
+		// An oldest-first strategy needs to know the confirmations of
+		// the coins it arranges.
+		var strategy wallet.CoinSelectionStrategy
+		err := l.WithCoinSelectLock(func() error {
+			var err error
+			strategy, err = chanfunding.WithConfirmations(
+				l.Cfg.CoinSelectionStrategy,
+				l.coinConfirmations,
+			)
+
+			return err
+		})
+		if err != nil {
+			req.err <- err
+			req.resp <- nil
+			return
+		}
+
 		// We use the P2WSH dust limit since it is larger than the
 		// P2WPKH dust limit and to avoid threading through two
 		// different dust limits.
@@ -867,7 +905,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 			DustLimit: DustLimitForSize(
 				input.P2WSHSize,
 			),
-			CoinSelectionStrategy: l.Cfg.CoinSelectionStrategy,
+			CoinSelectionStrategy: strategy,
 		}
 		req.ChanFunder = chanfunding.NewWalletAssembler(cfg)
 	} else {
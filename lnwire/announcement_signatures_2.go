@@ -0,0 +1,89 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// AnnounceSignatures2 is a direct message between two endpoints of a taproot
+// channel and serves as an opt-in mechanism to allow the announcement of the
+// channel to the rest of the network. It contains the MuSig2 partial signature
+// of the sender over the ChannelAnnouncement2 message, created with both its
+// node key and bitcoin key.
+type AnnounceSignatures2 struct {
+	// ChannelID is the unique description of the funding transaction.
+	ChannelID tlv.RecordT[tlv.TlvType0, ChannelID]
+
+	// ShortChannelID is the unique description of the funding transaction.
+	// It is constructed with the most significant 3 bytes as the block
+	// height, the next 3 bytes indicating the transaction index within the
+	// block, and the least significant two bytes indicating the output
+	// index which pays to the channel.
+	ShortChannelID tlv.RecordT[tlv.TlvType2, ShortChannelID]
+
+	// PartialSignature is the MuSig2 partial signature of the sender over
+	// the ChannelAnnouncement2 message.
+	PartialSignature tlv.RecordT[tlv.TlvType4, PartialSig]
+
+	// ExtraOpaqueData is the set of TLV records that were included in the
+	// message but that we don't know how to parse.
+	ExtraOpaqueData ExtraOpaqueData
+}
+
+// A compile time check to ensure AnnounceSignatures2 implements the
+// lnwire.Message interface.
+var _ Message = (*AnnounceSignatures2)(nil)
+
+// Decode deserializes a serialized AnnounceSignatures2 stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures2) Decode(r io.Reader, _ uint32) error {
+	_, extra, err := decodePureTLVMessage(
+		r, &a.ChannelID, &a.ShortChannelID, &a.PartialSignature,
+	)
+	if err != nil {
+		return err
+	}
+
+	a.ExtraOpaqueData = extra
+
+	return nil
+}
+
+// Encode serializes the target AnnounceSignatures2 into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures2) Encode(w *bytes.Buffer, _ uint32) error {
+	return EncodePureTLVMessage(a, w)
+}
+
+// AllRecords returns all the TLV records of the message, including the
+// unknown records kept in ExtraOpaqueData.
+//
+// This is part of the lnwire.PureTLVMessage interface.
+func (a *AnnounceSignatures2) AllRecords() ([]tlv.Record, error) {
+	records := []tlv.Record{
+		a.ChannelID.Record(),
+		a.ShortChannelID.Record(),
+		a.PartialSignature.Record(),
+	}
+
+	extra, err := extraRecords(a.ExtraOpaqueData)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(records, extra...), nil
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures2) MsgType() MessageType {
+	return MsgAnnounceSignatures2
+}
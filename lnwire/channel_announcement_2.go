@@ -0,0 +1,209 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// ChanAnn2MsgName is the name of the ChannelAnnouncement2 message
+	// that is used in the tag of its signature digest.
+	ChanAnn2MsgName = "channel_announcement_2"
+
+	// ChanAnn2SigFieldName is the name of the signature field of the
+	// ChannelAnnouncement2 message.
+	ChanAnn2SigFieldName = "signature"
+
+	// chanAnn2SigType is the TLV type of the signature record of the
+	// ChannelAnnouncement2 message. It falls within the unsigned range of
+	// a pure TLV message.
+	chanAnn2SigType tlv.Type = 160
+)
+
+// ChannelAnnouncement2 message is used to announce the existence of a taproot
+// channel between two peers in the network. Unlike the legacy
+// ChannelAnnouncement, the message is encoded as a pure TLV stream and carries
+// a single MuSig2 signature of the node and bitcoin keys of both peers.
+type ChannelAnnouncement2 struct {
+	// Signature is a Schnorr signature over the serialized TLV stream of
+	// the message, created by the MuSig2 aggregate of the node keys and, if
+	// present, the bitcoin keys of the channel.
+	Signature Sig
+
+	// ChainHash denotes the target chain that this channel was opened
+	// within. This value should be the genesis hash of the target chain.
+	ChainHash tlv.RecordT[tlv.TlvType0, [32]byte]
+
+	// Features is the feature vector that encodes the features supported
+	// by the target channel.
+	Features tlv.RecordT[tlv.TlvType2, FeatureVector]
+
+	// ShortChannelID is the unique description of the funding
+	// transaction.
+	ShortChannelID tlv.RecordT[tlv.TlvType4, ShortChannelID]
+
+	// Capacity is the capacity of the channel in satoshis.
+	Capacity tlv.RecordT[tlv.TlvType6, uint64]
+
+	// NodeID1 is the numerically-lesser public key ID of one of the
+	// channel operators.
+	NodeID1 tlv.RecordT[tlv.TlvType8, [33]byte]
+
+	// NodeID2 is the numerically-greater public key ID of one of the
+	// channel operators.
+	NodeID2 tlv.RecordT[tlv.TlvType10, [33]byte]
+
+	// BitcoinKey1 is the public key of the key used by Node1 in the
+	// construction of the on-chain funding transaction. If this is not
+	// set, then the node key is expected to have been used instead.
+	BitcoinKey1 tlv.OptionalRecordT[tlv.TlvType12, [33]byte]
+
+	// BitcoinKey2 is the public key of the key used by Node2 in the
+	// construction of the on-chain funding transaction. If this is not
+	// set, then the node key is expected to have been used instead.
+	BitcoinKey2 tlv.OptionalRecordT[tlv.TlvType14, [33]byte]
+
+	// MerkleRootHash is the hash used to create the taproot tweak of the
+	// funding output. If this is not set, then a BIP86 tweak is expected
+	// to have been used.
+	MerkleRootHash tlv.OptionalRecordT[tlv.TlvType16, [32]byte]
+
+	// ExtraOpaqueData is the set of TLV records that were included in the
+	// message but that we don't know how to parse. These are kept around
+	// as they are covered by the signature of the message.
+	ExtraOpaqueData ExtraOpaqueData
+}
+
+// NewChannelAnnouncement2 creates a new ChannelAnnouncement2 with an empty
+// feature vector.
+func NewChannelAnnouncement2() *ChannelAnnouncement2 {
+	return &ChannelAnnouncement2{
+		Features: tlv.NewRecordT[tlv.TlvType2](
+			*NewFeatureVector(nil, Features),
+		),
+	}
+}
+
+// A compile time check to ensure ChannelAnnouncement2 implements the
+// lnwire.Message interface.
+var _ Message = (*ChannelAnnouncement2)(nil)
+
+// A compile time check to ensure ChannelAnnouncement2 implements the
+// lnwire.PureTLVMessage interface.
+var _ PureTLVMessage = (*ChannelAnnouncement2)(nil)
+
+// Decode deserializes a serialized ChannelAnnouncement2 stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelAnnouncement2) Decode(r io.Reader, _ uint32) error {
+	var (
+		btcKey1    = tlv.ZeroRecordT[tlv.TlvType12, [33]byte]()
+		btcKey2    = tlv.ZeroRecordT[tlv.TlvType14, [33]byte]()
+		merkleRoot = tlv.ZeroRecordT[tlv.TlvType16, [32]byte]()
+		sig        Sig
+	)
+	typeMap, extra, err := decodePureTLVMessage(
+		r, &c.ChainHash, &c.Features, &c.ShortChannelID, &c.Capacity,
+		&c.NodeID1, &c.NodeID2, &btcKey1, &btcKey2, &merkleRoot,
+		sigRecordProducer(chanAnn2SigType, &sig),
+	)
+	if err != nil {
+		return err
+	}
+
+	// If no features were set, we'll default to an empty feature vector
+	// so that the message is always safe to use.
+	if c.Features.Val.RawFeatureVector == nil {
+		c.Features.Val = *NewFeatureVector(nil, Features)
+	}
+
+	if _, ok := typeMap[c.BitcoinKey1.TlvType()]; ok {
+		c.BitcoinKey1 = tlv.SomeRecordT(btcKey1)
+	}
+	if _, ok := typeMap[c.BitcoinKey2.TlvType()]; ok {
+		c.BitcoinKey2 = tlv.SomeRecordT(btcKey2)
+	}
+	if _, ok := typeMap[c.MerkleRootHash.TlvType()]; ok {
+		c.MerkleRootHash = tlv.SomeRecordT(merkleRoot)
+	}
+
+	// The signature of a pure TLV gossip message is always a Schnorr
+	// signature.
+	sig.ForceSchnorr()
+	c.Signature = sig
+	c.ExtraOpaqueData = extra
+
+	return nil
+}
+
+// Encode serializes the target ChannelAnnouncement2 into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelAnnouncement2) Encode(w *bytes.Buffer, _ uint32) error {
+	return EncodePureTLVMessage(c, w)
+}
+
+// AllRecords returns all the TLV records of the message, including the
+// unknown records kept in ExtraOpaqueData.
+//
+// This is part of the lnwire.PureTLVMessage interface.
+func (c *ChannelAnnouncement2) AllRecords() ([]tlv.Record, error) {
+	// Make sure we never try to encode a nil feature vector.
+	features := c.Features
+	if features.Val.RawFeatureVector == nil {
+		features.Val = *NewFeatureVector(nil, Features)
+	}
+
+	records := []tlv.Record{
+		c.ChainHash.Record(),
+		features.Record(),
+		c.ShortChannelID.Record(),
+		c.Capacity.Record(),
+		c.NodeID1.Record(),
+		c.NodeID2.Record(),
+		tlv.MakePrimitiveRecord(chanAnn2SigType, &c.Signature.bytes),
+	}
+
+	c.BitcoinKey1.WhenSome(func(r tlv.RecordT[tlv.TlvType12, [33]byte]) {
+		records = append(records, r.Record())
+	})
+	c.BitcoinKey2.WhenSome(func(r tlv.RecordT[tlv.TlvType14, [33]byte]) {
+		records = append(records, r.Record())
+	})
+	c.MerkleRootHash.WhenSome(
+		func(r tlv.RecordT[tlv.TlvType16, [32]byte]) {
+			records = append(records, r.Record())
+		},
+	)
+
+	extra, err := extraRecords(c.ExtraOpaqueData)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(records, extra...), nil
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelAnnouncement2) MsgType() MessageType {
+	return MsgChannelAnnouncement2
+}
+
+// DigestToSign computes the tagged hash of the signed fields of the
+// announcement that is to be signed by the aggregate MuSig2 key.
+func (c *ChannelAnnouncement2) DigestToSign() (*chainhash.Hash, error) {
+	data, err := SerialiseFieldsToSign(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return MsgHash(ChanAnn2MsgName, ChanAnn2SigFieldName, data), nil
+}
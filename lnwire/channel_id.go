@@ -7,6 +7,7 @@ import (
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 const (
@@ -36,6 +37,13 @@ func (c ChannelID) String() string {
 	return hex.EncodeToString(c[:])
 }
 
+// Record returns a TLV record that can be used to encode/decode a ChannelID
+// to/from a TLV stream. Note that a zero type is used, as we expect the type
+// to be overwritten when used as part of a tlv.RecordT.
+func (c *ChannelID) Record() tlv.Record {
+	return tlv.MakePrimitiveRecord(0, (*[32]byte)(c))
+}
+
 // NewChanIDFromOutPoint converts a target OutPoint into a ChannelID that is
 // usable within the network. In order to convert the OutPoint into a ChannelID,
 // we XOR the lower 2-bytes of the txid within the OutPoint with the big-endian
@@ -33,6 +33,18 @@ type ChannelReady struct {
 	// to accept a new commitment state transition.
 	NextLocalNonce OptMusig2NonceTLV
 
+	// AnnouncementNodeNonce is an optional field that stores the public
+	// musig2 nonce the sender will use to sign the ChannelAnnouncement2 of
+	// the channel with its node key. This will only be populated if the
+	// taproot gossip feature was negotiated for a public taproot channel.
+	AnnouncementNodeNonce tlv.OptionalRecordT[tlv.TlvType0, Musig2Nonce]
+
+	// AnnouncementBitcoinNonce is an optional field that stores the public
+	// musig2 nonce the sender will use to sign the ChannelAnnouncement2 of
+	// the channel with its funding key. This will only be populated if the
+	// taproot gossip feature was negotiated for a public taproot channel.
+	AnnouncementBitcoinNonce tlv.OptionalRecordT[tlv.TlvType2, Musig2Nonce]
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -78,9 +90,11 @@ func (c *ChannelReady) Decode(r io.Reader, _ uint32) error {
 	var (
 		aliasScid  ShortChannelID
 		localNonce = c.NextLocalNonce.Zero()
+		nodeNonce  = c.AnnouncementNodeNonce.Zero()
+		btcNonce   = c.AnnouncementBitcoinNonce.Zero()
 	)
 	typeMap, err := tlvRecords.ExtractRecords(
-		&aliasScid, &localNonce,
+		&aliasScid, &localNonce, &nodeNonce, &btcNonce,
 	)
 	if err != nil {
 		return err
@@ -94,6 +108,16 @@ func (c *ChannelReady) Decode(r io.Reader, _ uint32) error {
 	if val, ok := typeMap[c.NextLocalNonce.TlvType()]; ok && val == nil {
 		c.NextLocalNonce = tlv.SomeRecordT(localNonce)
 	}
+	if val, ok := typeMap[c.AnnouncementNodeNonce.TlvType()]; ok &&
+		val == nil {
+
+		c.AnnouncementNodeNonce = tlv.SomeRecordT(nodeNonce)
+	}
+	if val, ok := typeMap[c.AnnouncementBitcoinNonce.TlvType()]; ok &&
+		val == nil {
+
+		c.AnnouncementBitcoinNonce = tlv.SomeRecordT(btcNonce)
+	}
 
 	if len(tlvRecords) != 0 {
 		c.ExtraData = tlvRecords
@@ -117,13 +141,23 @@ func (c *ChannelReady) Encode(w *bytes.Buffer, _ uint32) error {
 	}
 
 	// We'll only encode the AliasScid in a TLV segment if it exists.
-	recordProducers := make([]tlv.RecordProducer, 0, 2)
+	recordProducers := make([]tlv.RecordProducer, 0, 4)
 	if c.AliasScid != nil {
 		recordProducers = append(recordProducers, c.AliasScid)
 	}
 	c.NextLocalNonce.WhenSome(func(localNonce Musig2NonceTLV) {
 		recordProducers = append(recordProducers, &localNonce)
 	})
+	c.AnnouncementNodeNonce.WhenSome(
+		func(nonce tlv.RecordT[tlv.TlvType0, Musig2Nonce]) {
+			recordProducers = append(recordProducers, &nonce)
+		},
+	)
+	c.AnnouncementBitcoinNonce.WhenSome(
+		func(nonce tlv.RecordT[tlv.TlvType2, Musig2Nonce]) {
+			recordProducers = append(recordProducers, &nonce)
+		},
+	)
 	err := EncodeMessageExtraData(&c.ExtraData, recordProducers...)
 	if err != nil {
 		return err
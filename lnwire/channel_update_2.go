@@ -0,0 +1,224 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// ChanUpdate2MsgName is the name of the ChannelUpdate2 message that is
+	// used in the tag of its signature digest.
+	ChanUpdate2MsgName = "channel_update_2"
+
+	// ChanUpdate2SigFieldName is the name of the signature field of the
+	// ChannelUpdate2 message.
+	ChanUpdate2SigFieldName = "signature"
+
+	// chanUpdate2SigType is the TLV type of the signature record of the
+	// ChannelUpdate2 message. It falls within the unsigned range of a pure
+	// TLV message.
+	chanUpdate2SigType tlv.Type = 160
+)
+
+// ChanUpdateDisableFlags is a bit vector used to indicate in which direction
+// a channel announced through ChannelUpdate2 is disabled.
+type ChanUpdateDisableFlags uint8
+
+const (
+	// ChanUpdateDisableIncoming is the bit that signals that the channel
+	// cannot currently be used to forward HTLCs towards the sender of the
+	// update.
+	ChanUpdateDisableIncoming ChanUpdateDisableFlags = 1 << iota
+
+	// ChanUpdateDisableOutgoing is the bit that signals that the sender of
+	// the update will not currently forward HTLCs over the channel.
+	ChanUpdateDisableOutgoing
+)
+
+// IsEnabled returns true if none of the disable bits are set.
+func (c ChanUpdateDisableFlags) IsEnabled() bool {
+	return c == 0
+}
+
+// IncomingDisabled returns true if the incoming direction is disabled.
+func (c ChanUpdateDisableFlags) IncomingDisabled() bool {
+	return c&ChanUpdateDisableIncoming == ChanUpdateDisableIncoming
+}
+
+// OutgoingDisabled returns true if the outgoing direction is disabled.
+func (c ChanUpdateDisableFlags) OutgoingDisabled() bool {
+	return c&ChanUpdateDisableOutgoing == ChanUpdateDisableOutgoing
+}
+
+// ChannelUpdate2 message is used after a taproot channel has been announced
+// using ChannelAnnouncement2 to communicate the routing policy of one of the
+// directions of the channel. Unlike the legacy ChannelUpdate, the message is
+// encoded as a pure TLV stream, signed with a Schnorr signature and uses the
+// block height rather than a timestamp to order updates.
+type ChannelUpdate2 struct {
+	// Signature is a Schnorr signature of the node key of the sender of
+	// the update over the signed fields of the message.
+	Signature Sig
+
+	// ChainHash denotes the target chain that this channel was opened
+	// within. This value should be the genesis hash of the target chain.
+	ChainHash tlv.RecordT[tlv.TlvType0, [32]byte]
+
+	// ShortChannelID is the unique description of the funding transaction.
+	ShortChannelID tlv.RecordT[tlv.TlvType2, ShortChannelID]
+
+	// BlockHeight allows ordering in the case of multiple announcements.
+	// We should ignore the message if block height is not greater than
+	// the last-received.
+	BlockHeight tlv.RecordT[tlv.TlvType4, uint32]
+
+	// DisabledFlags is an optional bitfield that describes various reasons
+	// that the node is communicating that the channel should be considered
+	// disabled.
+	DisabledFlags tlv.RecordT[tlv.TlvType6, uint8]
+
+	// SecondPeer is set if the update is for the second node of the
+	// channel as listed in the channel announcement. If it is not set,
+	// then the update is for the first node.
+	SecondPeer tlv.OptionalRecordT[tlv.TlvType8, bool]
+
+	// CLTVExpiryDelta is the minimum number of blocks this node requires
+	// to be added to the expiry of HTLCs.
+	CLTVExpiryDelta tlv.RecordT[tlv.TlvType10, uint16]
+
+	// HTLCMinimumMsat is the minimum HTLC value which will be accepted.
+	HTLCMinimumMsat tlv.RecordT[tlv.TlvType12, uint64]
+
+	// HTLCMaximumMsat is the maximum HTLC value which will be accepted.
+	HTLCMaximumMsat tlv.RecordT[tlv.TlvType14, uint64]
+
+	// FeeBaseMsat is the base fee that must be used for incoming HTLCs on
+	// this channel.
+	FeeBaseMsat tlv.RecordT[tlv.TlvType16, uint32]
+
+	// FeeProportionalMillionths is the fee rate that will be charged per
+	// millionth of a satoshi.
+	FeeProportionalMillionths tlv.RecordT[tlv.TlvType18, uint32]
+
+	// ExtraOpaqueData is the set of TLV records that were included in the
+	// message but that we don't know how to parse. These are kept around
+	// as they are covered by the signature of the message.
+	ExtraOpaqueData ExtraOpaqueData
+}
+
+// A compile time check to ensure ChannelUpdate2 implements the lnwire.Message
+// interface.
+var _ Message = (*ChannelUpdate2)(nil)
+
+// A compile time check to ensure ChannelUpdate2 implements the
+// lnwire.PureTLVMessage interface.
+var _ PureTLVMessage = (*ChannelUpdate2)(nil)
+
+// Decode deserializes a serialized ChannelUpdate2 stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelUpdate2) Decode(r io.Reader, _ uint32) error {
+	var (
+		secondPeer = tlv.ZeroRecordT[tlv.TlvType8, bool]()
+		sig        Sig
+	)
+	typeMap, extra, err := decodePureTLVMessage(
+		r, &c.ChainHash, &c.ShortChannelID, &c.BlockHeight,
+		&c.DisabledFlags, &secondPeer, &c.CLTVExpiryDelta,
+		&c.HTLCMinimumMsat, &c.HTLCMaximumMsat, &c.FeeBaseMsat,
+		&c.FeeProportionalMillionths,
+		sigRecordProducer(chanUpdate2SigType, &sig),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := typeMap[c.SecondPeer.TlvType()]; ok {
+		c.SecondPeer = tlv.SomeRecordT(secondPeer)
+	}
+
+	// The signature of a pure TLV gossip message is always a Schnorr
+	// signature.
+	sig.ForceSchnorr()
+	c.Signature = sig
+	c.ExtraOpaqueData = extra
+
+	return nil
+}
+
+// Encode serializes the target ChannelUpdate2 into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelUpdate2) Encode(w *bytes.Buffer, _ uint32) error {
+	return EncodePureTLVMessage(c, w)
+}
+
+// AllRecords returns all the TLV records of the message, including the
+// unknown records kept in ExtraOpaqueData.
+//
+// This is part of the lnwire.PureTLVMessage interface.
+func (c *ChannelUpdate2) AllRecords() ([]tlv.Record, error) {
+	records := []tlv.Record{
+		c.ChainHash.Record(),
+		c.ShortChannelID.Record(),
+		c.BlockHeight.Record(),
+		c.DisabledFlags.Record(),
+		c.CLTVExpiryDelta.Record(),
+		c.HTLCMinimumMsat.Record(),
+		c.HTLCMaximumMsat.Record(),
+		c.FeeBaseMsat.Record(),
+		c.FeeProportionalMillionths.Record(),
+		tlv.MakePrimitiveRecord(chanUpdate2SigType, &c.Signature.bytes),
+	}
+
+	c.SecondPeer.WhenSome(func(r tlv.RecordT[tlv.TlvType8, bool]) {
+		records = append(records, r.Record())
+	})
+
+	extra, err := extraRecords(c.ExtraOpaqueData)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(records, extra...), nil
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelUpdate2) MsgType() MessageType {
+	return MsgChannelUpdate2
+}
+
+// IsNode1 returns true if the update was sent by the first node of the
+// channel.
+func (c *ChannelUpdate2) IsNode1() bool {
+	var isSecond bool
+	c.SecondPeer.WhenSomeV(func(second bool) {
+		isSecond = second
+	})
+
+	return !isSecond
+}
+
+// IsDisabled returns true if any of the disable flags are set.
+func (c *ChannelUpdate2) IsDisabled() bool {
+	return !ChanUpdateDisableFlags(c.DisabledFlags.Val).IsEnabled()
+}
+
+// DigestToSign computes the tagged hash of the signed fields of the update
+// that is to be signed by the node key of the sender.
+func (c *ChannelUpdate2) DigestToSign() (*chainhash.Hash, error) {
+	data, err := SerialiseFieldsToSign(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return MsgHash(ChanUpdate2MsgName, ChanUpdate2SigFieldName, data), nil
+}
@@ -0,0 +1,125 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrDecodePanic is returned when decoding or encoding a message panicked.
+// Any occurrence of it is a bug, as it would allow a peer to crash the node
+// with a crafted message.
+var ErrDecodePanic = errors.New("message codec panicked")
+
+// DecodeReport describes the result of decoding a raw wire message with
+// DecodeMessage.
+type DecodeReport struct {
+	// Type is the message type given by the first two bytes of the raw
+	// message.
+	Type MessageType
+
+	// Msg is the decoded message. It is nil if decoding failed.
+	Msg Message
+
+	// PayloadSize is the size of the raw message, excluding the message
+	// type.
+	PayloadSize int
+
+	// MaxPayloadSize is the largest payload size a message is allowed to
+	// have.
+	MaxPayloadSize int
+
+	// EncodedSize is the payload size of the decoded message once encoded
+	// again. It is zero if decoding failed.
+	EncodedSize int
+
+	// RoundTrip is true if encoding the decoded message again yields the
+	// raw message.
+	RoundTrip bool
+
+	// Err is the error that occurred while decoding the message or
+	// encoding it again, if any.
+	Err error
+}
+
+// ExceedsMaxSize returns true if the raw message is larger than any message
+// is allowed to be.
+func (r *DecodeReport) ExceedsMaxSize() bool {
+	return r.PayloadSize > r.MaxPayloadSize
+}
+
+// recoverCodec turns a panic of the message codec into an error wrapping
+// ErrDecodePanic.
+func recoverCodec(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v", ErrDecodePanic, r)
+	}
+}
+
+// readMessageSafe decodes the given raw message, recovering from panics.
+func readMessageSafe(raw []byte) (msg Message, err error) {
+	defer recoverCodec(&err)
+
+	return ReadMessage(bytes.NewReader(raw), 0)
+}
+
+// writeMessageSafe encodes the given message, recovering from panics.
+func writeMessageSafe(msg Message) (encoded []byte, err error) {
+	defer recoverCodec(&err)
+
+	var b bytes.Buffer
+	if _, err := WriteMessage(&b, msg, 0); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeMessage decodes the given raw wire message, including its two byte
+// message type, and reports the outcome instead of only returning an error.
+// The decoded message is encoded again to check that it survives a round
+// trip. Panics of the message codec are recovered from and reported with an
+// error wrapping ErrDecodePanic, so arbitrary input can be decoded safely.
+func DecodeMessage(raw []byte) *DecodeReport {
+	report := &DecodeReport{
+		MaxPayloadSize: MaxMsgBody,
+	}
+
+	if len(raw) < 2 {
+		report.Err = fmt.Errorf("message too short: %d bytes",
+			len(raw))
+
+		return report
+	}
+	report.Type = MessageType(binary.BigEndian.Uint16(raw[:2]))
+	report.PayloadSize = len(raw) - 2
+
+	// Peers can't send us messages that are larger than the maximum
+	// size, so we don't try to decode them either.
+	if report.ExceedsMaxSize() {
+		report.Err = fmt.Errorf("payload of %d bytes exceeds maximum "+
+			"of %d bytes", report.PayloadSize, MaxMsgBody)
+
+		return report
+	}
+
+	msg, err := readMessageSafe(raw)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	report.Msg = msg
+
+	encoded, err := writeMessageSafe(msg)
+	if err != nil {
+		report.Err = fmt.Errorf("unable to encode decoded message: %w",
+			err)
+
+		return report
+	}
+	report.EncodedSize = len(encoded) - 2
+	report.RoundTrip = bytes.Equal(encoded, raw)
+
+	return report
+}
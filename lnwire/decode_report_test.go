@@ -0,0 +1,43 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeMessage tests that raw messages are decoded and reported on.
+func TestDecodeMessage(t *testing.T) {
+	t.Parallel()
+
+	// Messages that are too short to hold a message type or too large to
+	// be sent by a peer aren't decoded.
+	report := DecodeMessage([]byte{0})
+	require.Error(t, report.Err)
+	require.Nil(t, report.Msg)
+
+	report = DecodeMessage(make([]byte, MaxMsgBody+3))
+	require.Error(t, report.Err)
+	require.True(t, report.ExceedsMaxSize())
+
+	// A valid message is decoded and survives a round trip.
+	var b bytes.Buffer
+	_, err := WriteMessage(&b, &DynAck{ChanID: ChannelID{1}}, 0)
+	require.NoError(t, err)
+
+	report = DecodeMessage(b.Bytes())
+	require.NoError(t, report.Err)
+	require.EqualValues(t, MsgDynAck, report.Type)
+	require.IsType(t, &DynAck{}, report.Msg)
+	require.Equal(t, ChannelID{1}, report.Msg.(*DynAck).ChanID)
+	require.Equal(t, b.Len()-2, report.PayloadSize)
+	require.Equal(t, report.PayloadSize, report.EncodedSize)
+	require.True(t, report.RoundTrip)
+
+	// A truncated message fails to decode without panicking.
+	report = DecodeMessage(b.Bytes()[:10])
+	require.Error(t, report.Err)
+	require.NotErrorIs(t, report.Err, ErrDecodePanic)
+	require.EqualValues(t, MsgDynAck, report.Type)
+}
@@ -59,6 +59,14 @@ type Error struct {
 	// Data is the attached error data that describes the exact failure
 	// which caused the error message to be sent.
 	Data ErrorData
+
+	// Code is the optional machine-readable reason of the error.
+	Code ErrorCodeRecord
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
 }
 
 // NewError creates a new Error message.
@@ -79,7 +87,8 @@ func (c *Error) Error() string {
 		errMsg = string(c.Data)
 	}
 
-	return fmt.Sprintf("chan_id=%v, err=%v", c.ChanID, errMsg)
+	return fmt.Sprintf("chan_id=%v%v, err=%v", c.ChanID,
+		errorCodeSummary(c.Code), errMsg)
 }
 
 // Decode deserializes a serialized Error message stored in the passed
@@ -87,10 +96,18 @@ func (c *Error) Error() string {
 //
 // This is part of the lnwire.Message interface.
 func (c *Error) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.Data,
+		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	decodeErrorCode(&c.ExtraData, &c.Code)
+
+	return nil
 }
 
 // Encode serializes the target Error into the passed io.Writer observing the
@@ -102,7 +119,15 @@ func (c *Error) Encode(w *bytes.Buffer, pver uint32) error {
 		return err
 	}
 
-	return WriteErrorData(w, c.Data)
+	if err := WriteErrorData(w, c.Data); err != nil {
+		return err
+	}
+
+	if err := encodeErrorCode(&c.ExtraData, c.Code); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
 }
 
 // MsgType returns the integer uniquely identifying an Error message on the
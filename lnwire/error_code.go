@@ -0,0 +1,176 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// ErrorCode is a machine-readable reason attached to Error and Warning
+// messages, next to the human-readable error data. It allows the receiver to
+// act on the failure, and operators to aggregate failures, without parsing
+// the error data.
+type ErrorCode uint16
+
+const (
+	// ErrorCodeInternal is used when the channel failed because of an
+	// internal error of the sender.
+	ErrorCodeInternal ErrorCode = 1
+
+	// ErrorCodeSyncFailed is used when the channel state couldn't be
+	// synchronized when reestablishing the channel.
+	ErrorCodeSyncFailed ErrorCode = 2
+
+	// ErrorCodeInvalidUpdate is used when the peer sent an invalid channel
+	// update, for example an HTLC that violates the channel constraints.
+	ErrorCodeInvalidUpdate ErrorCode = 3
+
+	// ErrorCodeInvalidCommitSig is used when the peer sent an invalid
+	// commitment signature.
+	ErrorCodeInvalidCommitSig ErrorCode = 4
+
+	// ErrorCodeInvalidRevocation is used when the peer sent an invalid
+	// revocation.
+	ErrorCodeInvalidRevocation ErrorCode = 5
+
+	// ErrorCodeRecoveryRequired is used when the sender lost channel state
+	// and relies on the peer to force close the channel.
+	ErrorCodeRecoveryRequired ErrorCode = 6
+
+	// ErrorCodeFundingFailed is used when opening the channel failed.
+	ErrorCodeFundingFailed ErrorCode = 7
+
+	// ErrorCodeCloseFailed is used when cooperatively closing the channel
+	// failed.
+	ErrorCodeCloseFailed ErrorCode = 8
+)
+
+// String returns a human readable version of the error code.
+func (e ErrorCode) String() string {
+	switch e {
+	case ErrorCodeInternal:
+		return "internal_error"
+
+	case ErrorCodeSyncFailed:
+		return "sync_failed"
+
+	case ErrorCodeInvalidUpdate:
+		return "invalid_update"
+
+	case ErrorCodeInvalidCommitSig:
+		return "invalid_commit_sig"
+
+	case ErrorCodeInvalidRevocation:
+		return "invalid_revocation"
+
+	case ErrorCodeRecoveryRequired:
+		return "recovery_required"
+
+	case ErrorCodeFundingFailed:
+		return "funding_failed"
+
+	case ErrorCodeCloseFailed:
+		return "close_failed"
+
+	default:
+		return "unknown"
+	}
+}
+
+// Record returns a TLV record that can be used to encode/decode an error code
+// to/from a TLV stream.
+func (e *ErrorCode) Record() tlv.Record {
+	return tlv.MakeStaticRecord(0, e, 2, EErrorCode, DErrorCode)
+}
+
+// EErrorCode is an encoder for ErrorCode. It is exported so other packages
+// can use the encoding scheme.
+func EErrorCode(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*ErrorCode); ok {
+		return tlv.EUint16T(w, uint16(*v), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.ErrorCode")
+}
+
+// DErrorCode is a decoder for ErrorCode. It is exported so other packages can
+// use the decoding scheme.
+func DErrorCode(r io.Reader, val interface{}, buf *[8]byte, l uint64) error {
+	if v, ok := val.(*ErrorCode); ok && l == 2 {
+		var code uint16
+		if err := tlv.DUint16(r, &code, buf, 2); err != nil {
+			return err
+		}
+
+		*v = ErrorCode(code)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.ErrorCode", l, 2)
+}
+
+type (
+	// ErrorCodeTlvType is the TLV type of the error code of Error and
+	// Warning messages. It is odd, so peers that don't understand it
+	// ignore it.
+	ErrorCodeTlvType = tlv.TlvType1
+
+	// ErrorCodeRecord holds the optional error code of Error and Warning
+	// messages.
+	ErrorCodeRecord = tlv.OptionalRecordT[ErrorCodeTlvType, ErrorCode]
+)
+
+// NewErrorCodeRecord returns an error code record holding the given code.
+func NewErrorCodeRecord(code ErrorCode) ErrorCodeRecord {
+	return tlv.SomeRecordT(
+		tlv.NewRecordT[ErrorCodeTlvType, ErrorCode](code),
+	)
+}
+
+// decodeErrorCode extracts the error code from the extra data of an Error or
+// Warning message. The extra data is set to nil if it holds no records.
+func decodeErrorCode(extraData *ExtraOpaqueData, code *ErrorCodeRecord) {
+	// Peers that don't know about error codes may append arbitrary data to
+	// their errors, which we ignore like we did before error codes were
+	// added, instead of failing to decode the error.
+	codeRecord := code.Zero()
+	tlvMap, err := extraData.ExtractRecords(&codeRecord)
+	if err != nil {
+		*extraData = nil
+		return
+	}
+
+	if val, ok := tlvMap[code.TlvType()]; ok && val == nil {
+		*code = tlv.SomeRecordT(codeRecord)
+	}
+
+	// Set extra data to nil if we didn't parse anything out of it so that
+	// we can use assert.Equal in tests.
+	if len(tlvMap) == 0 {
+		*extraData = nil
+	}
+}
+
+// encodeErrorCode packs the error code of an Error or Warning message into
+// its extra data.
+func encodeErrorCode(extraData *ExtraOpaqueData, code ErrorCodeRecord) error {
+	var records []tlv.RecordProducer
+	code.WhenSome(func(r tlv.RecordT[ErrorCodeTlvType, ErrorCode]) {
+		records = append(records, &r)
+	})
+
+	return EncodeMessageExtraData(extraData, records...)
+}
+
+// errorCodeSummary returns the error code formatted for the string
+// representation of Error and Warning messages, or an empty string if no
+// error code is set.
+func errorCodeSummary(code ErrorCodeRecord) string {
+	var summary string
+	code.WhenSome(func(r tlv.RecordT[ErrorCodeTlvType, ErrorCode]) {
+		summary = ", code=" + r.Val.String()
+	})
+
+	return summary
+}
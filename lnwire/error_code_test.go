@@ -0,0 +1,62 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorCode tests that the error code of Error and Warning messages is
+// encoded and decoded, and that errors without a valid TLV stream appended
+// are still decoded.
+func TestErrorCode(t *testing.T) {
+	t.Parallel()
+
+	roundTrip := func(msg Message) Message {
+		var b bytes.Buffer
+		_, err := WriteMessage(&b, msg, 0)
+		require.NoError(t, err)
+
+		decoded, err := ReadMessage(&b, 0)
+		require.NoError(t, err)
+
+		return decoded
+	}
+
+	errMsg := &Error{
+		ChanID: ChannelID{1},
+		Data:   ErrorData("invalid commitment"),
+		Code:   NewErrorCodeRecord(ErrorCodeInvalidCommitSig),
+	}
+	decoded, ok := roundTrip(errMsg).(*Error)
+	require.True(t, ok)
+	require.Equal(t, errMsg, decoded)
+	require.Contains(t, decoded.Error(), "code=invalid_commit_sig")
+
+	warning := &Warning{
+		ChanID: ChannelID{1},
+		Data:   WarningData("funding failed"),
+		Code:   NewErrorCodeRecord(ErrorCodeFundingFailed),
+	}
+	decodedWarning, ok := roundTrip(warning).(*Warning)
+	require.True(t, ok)
+	require.Equal(t, warning, decodedWarning)
+	require.Contains(t, decodedWarning.Warning(), "code=funding_failed")
+
+	// Errors without an error code don't mention it.
+	noCode := &Error{ChanID: ChannelID{1}, Data: ErrorData("failed")}
+	require.NotContains(t, roundTrip(noCode).(*Error).Error(), "code=")
+
+	// Trailing data that isn't a valid TLV stream is ignored.
+	var b bytes.Buffer
+	_, err := WriteMessage(&b, noCode, 0)
+	require.NoError(t, err)
+	b.Write([]byte{0xff})
+
+	decoded, ok = roundTrip(noCode).(*Error)
+	require.True(t, ok)
+	msg, err := ReadMessage(&b, 0)
+	require.NoError(t, err)
+	require.Equal(t, decoded, msg)
+}
@@ -171,6 +171,24 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// OnionMessagesRequired is a required feature bit that signals that
+	// the node forwards onion messages as defined in BOLT 4.
+	OnionMessagesRequired FeatureBit = 38
+
+	// OnionMessagesOptional is an optional feature bit that signals that
+	// the node forwards onion messages as defined in BOLT 4.
+	OnionMessagesOptional FeatureBit = 39
+
+	// ProvideStorageRequired is a required feature bit that signals that
+	// the node offers to store a small encrypted backup blob on behalf of
+	// its channel peers using the peer_storage message.
+	ProvideStorageRequired FeatureBit = 42
+
+	// ProvideStorageOptional is an optional feature bit that signals that
+	// the node offers to store a small encrypted backup blob on behalf of
+	// its channel peers using the peer_storage message.
+	ProvideStorageOptional FeatureBit = 43
+
 	// ExplicitChannelTypeRequired is a required bit that denotes that a
 	// connection established with this node is to use explicit channel
 	// commitment types for negotiation instead of the existing implicit
@@ -223,6 +241,26 @@ const (
 	// able and willing to accept keysend payments.
 	KeysendOptional = 55
 
+	// TrampolineRoutingRequired is a required bit that indicates that the
+	// node is able to relay payments using trampoline onions.
+	TrampolineRoutingRequired FeatureBit = 56
+
+	// TrampolineRoutingOptional is an optional bit that indicates that the
+	// node is able to relay payments using trampoline onions.
+	TrampolineRoutingOptional FeatureBit = 57
+
+	// RbfCoopCloseRequired is a required feature bit that signals that
+	// the node supports the RBF cooperative close protocol, which lets
+	// the closing party bump the fee of a broadcast closing transaction
+	// using the closing_complete and closing_sig messages.
+	RbfCoopCloseRequired FeatureBit = 60
+
+	// RbfCoopCloseOptional is an optional feature bit that signals that
+	// the node supports the RBF cooperative close protocol, which lets
+	// the closing party bump the fee of a broadcast closing transaction
+	// using the closing_complete and closing_sig messages.
+	RbfCoopCloseOptional FeatureBit = 61
+
 	// ScriptEnforcedLeaseOptional is an optional feature bit that signals
 	// that the node requires channels having zero-fee second-level HTLC
 	// transactions, which also imply anchor commitments, along with an
@@ -265,6 +303,20 @@ const (
 	// finalized.
 	SimpleTaprootChannelsOptionalStaging = 181
 
+	// TaprootGossipRequiredStaging is a required feature bit that
+	// indicates that the node understands and requires the pure TLV,
+	// Schnorr signed gossip messages used to announce taproot channels.
+	// This is a feature bit used in the wild while the gossip protocol
+	// is still being finalized.
+	TaprootGossipRequiredStaging = 182
+
+	// TaprootGossipOptionalStaging is an optional feature bit that
+	// indicates that the node understands the pure TLV, Schnorr signed
+	// gossip messages used to announce taproot channels. This is a
+	// feature bit used in the wild while the gossip protocol is still
+	// being finalized.
+	TaprootGossipOptionalStaging = 183
+
 	// MaxBolt11Feature is the maximum feature bit value allowed in bolt 11
 	// invoices.
 	//
@@ -311,12 +363,20 @@ var Features = map[FeatureBit]string{
 	WumboChannelsOptional:                "wumbo-channels",
 	AMPRequired:                          "amp",
 	AMPOptional:                          "amp",
+	OnionMessagesRequired:                "onion-messages",
+	OnionMessagesOptional:                "onion-messages",
+	ProvideStorageRequired:               "provide-storage",
+	ProvideStorageOptional:               "provide-storage",
 	PaymentMetadataOptional:              "payment-metadata",
 	PaymentMetadataRequired:              "payment-metadata",
 	ExplicitChannelTypeOptional:          "explicit-commitment-type",
 	ExplicitChannelTypeRequired:          "explicit-commitment-type",
 	KeysendOptional:                      "keysend",
 	KeysendRequired:                      "keysend",
+	TrampolineRoutingOptional:            "trampoline-routing",
+	RbfCoopCloseRequired:                 "rbf-coop-close",
+	RbfCoopCloseOptional:                 "rbf-coop-close",
+	TrampolineRoutingRequired:            "trampoline-routing",
 	ScriptEnforcedLeaseRequired:          "script-enforced-lease",
 	ScriptEnforcedLeaseOptional:          "script-enforced-lease",
 	ScidAliasRequired:                    "scid-alias",
@@ -331,6 +391,8 @@ var Features = map[FeatureBit]string{
 	SimpleTaprootChannelsOptionalFinal:   "simple-taproot-chans",
 	SimpleTaprootChannelsRequiredStaging: "simple-taproot-chans-x",
 	SimpleTaprootChannelsOptionalStaging: "simple-taproot-chans-x",
+	TaprootGossipRequiredStaging:         "taproot-gossip-x",
+	TaprootGossipOptionalStaging:         "taproot-gossip-x",
 }
 
 // RawFeatureVector represents a set of feature bits as defined in BOLT-09.  A
@@ -7,6 +7,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,6 +57,81 @@ func harness(t *testing.T, data []byte) {
 	require.Equal(t, msg, newMsg)
 }
 
+// seedChanID is the channel ID of the messages in the seed corpora.
+var seedChanID = ChannelID{1, 2, 3, 4}
+
+// encodeSeed encodes the given message, including its message type, for a
+// seed corpus.
+func encodeSeed(f *testing.F, msg Message) []byte {
+	f.Helper()
+
+	var b bytes.Buffer
+	_, err := WriteMessage(&b, msg, 0)
+	require.NoError(f, err)
+
+	return b.Bytes()
+}
+
+// addSeedMessages adds the given messages without their message type to the
+// seed corpus of a fuzz test that prefixes the fuzzed data with the type.
+func addSeedMessages(f *testing.F, msgs ...Message) {
+	f.Helper()
+
+	for _, msg := range msgs {
+		f.Add(encodeSeed(f, msg)[2:])
+	}
+}
+
+// dynProposeSeeds returns valid DynPropose messages for the seed corpora.
+func dynProposeSeeds() []Message {
+	fundingKey, _ := btcec.PrivKeyFromBytes([]byte{1})
+
+	return []Message{
+		&DynPropose{ChanID: seedChanID},
+		&DynPropose{
+			ChanID:           seedChanID,
+			Initiator:        true,
+			DustLimit:        fn.Some(btcutil.Amount(354)),
+			MaxValueInFlight: fn.Some(MilliSatoshi(1_000_000)),
+			ChannelReserve:   fn.Some(btcutil.Amount(10_000)),
+			CsvDelay:         fn.Some(uint16(144)),
+			MaxAcceptedHTLCs: fn.Some(uint16(483)),
+			FundingKey:       fn.Some(*fundingKey.PubKey()),
+			ChannelType: fn.Some(ChannelType(*NewRawFeatureVector(
+				SimpleTaprootChannelsRequiredStaging,
+			))),
+		},
+	}
+}
+
+// dynRejectSeeds returns valid DynReject messages for the seed corpora.
+func dynRejectSeeds() []Message {
+	return []Message{
+		&DynReject{
+			ChanID:           seedChanID,
+			UpdateRejections: *NewRawFeatureVector(),
+		},
+		&DynReject{
+			ChanID:           seedChanID,
+			UpdateRejections: *NewRawFeatureVector(0, 2, 6),
+		},
+	}
+}
+
+// dynAckSeeds returns valid DynAck messages for the seed corpora.
+func dynAckSeeds() []Message {
+	return []Message{
+		&DynAck{ChanID: seedChanID},
+	}
+}
+
+// kickoffSigSeeds returns valid KickoffSig messages for the seed corpora.
+func kickoffSigSeeds() []Message {
+	return []Message{
+		&KickoffSig{ChanID: seedChanID},
+	}
+}
+
 func FuzzAcceptChannel(f *testing.F) {
 	f.Fuzz(func(t *testing.T, data []byte) {
 		data = prefixWithMsgType(data, MsgAcceptChannel)
@@ -191,6 +269,39 @@ func FuzzWarning(f *testing.F) {
 	})
 }
 
+func FuzzPeerStorage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgPeerStorage.
+		data = prefixWithMsgType(data, MsgPeerStorage)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
+func FuzzPeerStorageRetrieval(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgPeerStorageRetrieval.
+		data = prefixWithMsgType(data, MsgPeerStorageRetrieval)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
+func FuzzOnionMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgOnionMessage.
+		data = prefixWithMsgType(data, MsgOnionMessage)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
 func FuzzFundingCreated(f *testing.F) {
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Prefix with MsgFundingCreated.
@@ -578,6 +689,8 @@ func FuzzUpdateFulfillHTLC(f *testing.F) {
 }
 
 func FuzzDynPropose(f *testing.F) {
+	addSeedMessages(f, dynProposeSeeds()...)
+
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Prefix with DynPropose.
 		data = prefixWithMsgType(data, MsgDynPropose)
@@ -589,6 +702,8 @@ func FuzzDynPropose(f *testing.F) {
 }
 
 func FuzzDynReject(f *testing.F) {
+	addSeedMessages(f, dynRejectSeeds()...)
+
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Prefix with DynReject.
 		data = prefixWithMsgType(data, MsgDynReject)
@@ -600,6 +715,8 @@ func FuzzDynReject(f *testing.F) {
 }
 
 func FuzzDynAck(f *testing.F) {
+	addSeedMessages(f, dynAckSeeds()...)
+
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Prefix with DynReject.
 		data = prefixWithMsgType(data, MsgDynAck)
@@ -611,6 +728,8 @@ func FuzzDynAck(f *testing.F) {
 }
 
 func FuzzKickoffSig(f *testing.F) {
+	addSeedMessages(f, kickoffSigSeeds()...)
+
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Prefix with KickoffSig
 		data = prefixWithMsgType(data, MsgKickoffSig)
@@ -900,3 +1019,63 @@ func FuzzClosingComplete(f *testing.F) {
 		harness(t, data)
 	})
 }
+
+func FuzzChannelAnnouncement2(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with ChannelAnnouncement2.
+		data = prefixWithMsgType(data, MsgChannelAnnouncement2)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
+func FuzzChannelUpdate2(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with ChannelUpdate2.
+		data = prefixWithMsgType(data, MsgChannelUpdate2)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
+func FuzzAnnounceSignatures2(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with AnnounceSignatures2.
+		data = prefixWithMsgType(data, MsgAnnounceSignatures2)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
+// FuzzDecodeMessage makes sure that decoding arbitrary messages of any type
+// never makes the message codec panic.
+func FuzzDecodeMessage(f *testing.F) {
+	var seeds []Message
+	seeds = append(seeds, dynProposeSeeds()...)
+	seeds = append(seeds, dynRejectSeeds()...)
+	seeds = append(seeds, dynAckSeeds()...)
+	seeds = append(seeds, kickoffSigSeeds()...)
+	for _, msg := range seeds {
+		f.Add(encodeSeed(f, msg))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		report := DecodeMessage(data)
+		require.NotErrorIs(t, report.Err, ErrDecodePanic)
+
+		// A message that was decoded must also encode again, and not
+		// grow beyond the maximum message size in doing so.
+		if report.Msg != nil {
+			require.NoError(t, report.Err)
+			require.LessOrEqual(
+				t, report.EncodedSize, report.MaxPayloadSize,
+			)
+		}
+	})
+}
@@ -147,6 +147,49 @@ func randRawKey() ([33]byte, error) {
 	return n, nil
 }
 
+// randSchnorrSig returns a random 64-byte signature that is flagged as a
+// schnorr signature.
+func randSchnorrSig(t *testing.T, r *rand.Rand) Sig {
+	var sig Sig
+	_, err := r.Read(sig.bytes[:])
+	require.NoError(t, err)
+
+	sig.ForceSchnorr()
+
+	return sig
+}
+
+// randTLVStream returns a serialized TLV stream of up to three random records
+// with odd types starting from the given minimum type.
+func randTLVStream(r *rand.Rand, minType uint64) (ExtraOpaqueData, error) {
+	records := make(map[uint64][]byte)
+	for i := 0; i < r.Intn(3)+1; i++ {
+		typ := minType + uint64(r.Intn(1000))
+		if typ%2 == 0 {
+			typ++
+		}
+
+		val := make([]byte, r.Intn(32))
+		if _, err := r.Read(val); err != nil {
+			return nil, err
+		}
+
+		records[typ] = val
+	}
+
+	stream, err := tlv.NewStream(tlv.MapToRecords(records)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
 func randDeliveryAddress(r *rand.Rand) (DeliveryAddress, error) {
 	// Generate size minimum one. Empty scripts should be tested specifically.
 	size := r.Intn(deliveryAddressMaxSize) + 1
@@ -682,6 +725,13 @@ func TestLightningWireProtocol(t *testing.T) {
 				req.NextLocalNonce = someLocalNonce[NonceRecordTypeT](r)
 			}
 
+			if r.Int31()%2 == 0 {
+				req.AnnouncementNodeNonce =
+					someLocalNonce[tlv.TlvType0](r)
+				req.AnnouncementBitcoinNonce =
+					someLocalNonce[tlv.TlvType2](r)
+			}
+
 			v[0] = reflect.ValueOf(*req)
 		},
 		MsgShutdown: func(v []reflect.Value, r *rand.Rand) {
@@ -837,6 +887,27 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(da)
 		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			pathKey, err := randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			onion := make([]byte, r.Intn(1000)+1)
+			if _, err := r.Read(onion); err != nil {
+				t.Fatalf("unable to generate onion: %v", err)
+				return
+			}
+
+			req := OnionMessage{
+				PathKey:   pathKey,
+				OnionBlob: onion,
+				ExtraData: make([]byte, 0),
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgKickoffSig: func(v []reflect.Value, r *rand.Rand) {
 			ks := KickoffSig{
 				ExtraData: make([]byte, 0),
@@ -1337,6 +1408,119 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgChannelAnnouncement2: func(v []reflect.Value, r *rand.Rand) {
+			req := ChannelAnnouncement2{
+				Signature: randSchnorrSig(t, r),
+			}
+
+			req.ShortChannelID.Val = NewShortChanIDFromInt(
+				uint64(r.Int63()),
+			)
+			req.Capacity.Val = rand.Uint64()
+
+			features := randRawFeatureVector(r)
+			req.Features.Val = *NewFeatureVector(features, Features)
+
+			_, err := r.Read(req.ChainHash.Val[:])
+			require.NoError(t, err)
+
+			req.NodeID1.Val, err = randRawKey()
+			require.NoError(t, err)
+
+			req.NodeID2.Val, err = randRawKey()
+			require.NoError(t, err)
+
+			// Sometimes set the bitcoin keys.
+			if r.Intn(2) == 0 {
+				btcKey1 := tlv.ZeroRecordT[
+					tlv.TlvType12, [33]byte,
+				]()
+				btcKey1.Val, err = randRawKey()
+				require.NoError(t, err)
+				req.BitcoinKey1 = tlv.SomeRecordT(btcKey1)
+
+				btcKey2 := tlv.ZeroRecordT[
+					tlv.TlvType14, [33]byte,
+				]()
+				btcKey2.Val, err = randRawKey()
+				require.NoError(t, err)
+				req.BitcoinKey2 = tlv.SomeRecordT(btcKey2)
+			}
+
+			// Sometimes set the merkle root hash.
+			if r.Intn(2) == 0 {
+				hash := tlv.ZeroRecordT[
+					tlv.TlvType16, [32]byte,
+				]()
+				_, err := r.Read(hash.Val[:])
+				require.NoError(t, err)
+
+				req.MerkleRootHash = tlv.SomeRecordT(hash)
+			}
+
+			// Sometimes add some unknown odd records that are
+			// covered by the signature.
+			if r.Intn(2) == 0 {
+				extra, err := randTLVStream(r, 1001)
+				require.NoError(t, err)
+
+				req.ExtraOpaqueData = extra
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgChannelUpdate2: func(v []reflect.Value, r *rand.Rand) {
+			req := ChannelUpdate2{
+				Signature: randSchnorrSig(t, r),
+			}
+
+			req.ShortChannelID.Val = NewShortChanIDFromInt(
+				uint64(r.Int63()),
+			)
+			req.BlockHeight.Val = r.Uint32()
+			req.DisabledFlags.Val = uint8(r.Int31n(4))
+			req.CLTVExpiryDelta.Val = uint16(r.Int31())
+			req.HTLCMinimumMsat.Val = r.Uint64()
+			req.HTLCMaximumMsat.Val = r.Uint64()
+			req.FeeBaseMsat.Val = r.Uint32()
+			req.FeeProportionalMillionths.Val = r.Uint32()
+
+			_, err := r.Read(req.ChainHash.Val[:])
+			require.NoError(t, err)
+
+			if r.Intn(2) == 0 {
+				secondPeer := tlv.ZeroRecordT[
+					tlv.TlvType8, bool,
+				]()
+				secondPeer.Val = r.Intn(2) == 0
+				req.SecondPeer = tlv.SomeRecordT(secondPeer)
+			}
+
+			if r.Intn(2) == 0 {
+				extra, err := randTLVStream(r, 1001)
+				require.NoError(t, err)
+
+				req.ExtraOpaqueData = extra
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgAnnounceSignatures2: func(v []reflect.Value, r *rand.Rand) {
+			var req AnnounceSignatures2
+
+			req.ShortChannelID.Val = NewShortChanIDFromInt(
+				uint64(r.Int63()),
+			)
+
+			_, err := r.Read(req.ChannelID.Val[:])
+			require.NoError(t, err)
+
+			partialSig, err := randPartialSig(r)
+			require.NoError(t, err)
+			req.PartialSignature.Val = *partialSig
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgUpdateAddHTLC: func(v []reflect.Value, r *rand.Rand) {
 			req := &UpdateAddHTLC{
 				ID:     r.Uint64(),
@@ -1373,6 +1557,70 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(*req)
 		},
+		MsgUpdateFailHTLC: func(v []reflect.Value, r *rand.Rand) {
+			req := UpdateFailHTLC{
+				ID:     r.Uint64(),
+				Reason: make(OpaqueReason, 1+r.Intn(1000)),
+			}
+
+			_, err := r.Read(req.ChanID[:])
+			require.NoError(t, err)
+
+			_, err = r.Read(req.Reason)
+			require.NoError(t, err)
+
+			// Attach attribution data 50% of the time, since not
+			// all nodes add it to their failures.
+			if r.Intn(2) == 0 {
+				attrData := make([]byte, 920)
+				_, err = r.Read(attrData)
+				require.NoError(t, err)
+
+				req.AttrData = NewAttrDataRecord(attrData)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgError: func(v []reflect.Value, r *rand.Rand) {
+			req := Error{
+				Data: make(ErrorData, 1+r.Intn(100)),
+			}
+
+			_, err := r.Read(req.ChanID[:])
+			require.NoError(t, err)
+
+			_, err = r.Read(req.Data)
+			require.NoError(t, err)
+
+			// Attach an error code 50% of the time, as peers that
+			// don't know about error codes don't send them.
+			if r.Intn(2) == 0 {
+				req.Code = NewErrorCodeRecord(
+					ErrorCode(r.Uint32()),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgWarning: func(v []reflect.Value, r *rand.Rand) {
+			req := Warning{
+				Data: make(WarningData, 1+r.Intn(100)),
+			}
+
+			_, err := r.Read(req.ChanID[:])
+			require.NoError(t, err)
+
+			_, err = r.Read(req.Data)
+			require.NoError(t, err)
+
+			if r.Intn(2) == 0 {
+				req.Code = NewErrorCodeRecord(
+					ErrorCode(r.Uint32()),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 	}
 
 	// With the above types defined, we'll now generate a slice of
@@ -1396,6 +1644,18 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgPeerStorage,
+			scenario: func(m PeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgPeerStorageRetrieval,
+			scenario: func(m PeerStorageRetrieval) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgError,
 			scenario: func(m Error) bool {
@@ -1474,6 +1734,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgKickoffSig,
 			scenario: func(m KickoffSig) bool {
@@ -1595,6 +1861,24 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgChannelAnnouncement2,
+			scenario: func(m ChannelAnnouncement2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgChannelUpdate2,
+			scenario: func(m ChannelUpdate2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgAnnounceSignatures2,
+			scenario: func(m AnnounceSignatures2) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		var config *quick.Config
@@ -23,6 +23,8 @@ type MessageType uint16
 // Lightning protocol.
 const (
 	MsgWarning                 MessageType = 1
+	MsgPeerStorage                         = 7
+	MsgPeerStorageRetrieval                = 9
 	MsgInit                                = 16
 	MsgError                               = 17
 	MsgPing                                = 18
@@ -51,11 +53,15 @@ const (
 	MsgNodeAnnouncement                    = 257
 	MsgChannelUpdate                       = 258
 	MsgAnnounceSignatures                  = 259
+	MsgAnnounceSignatures2                 = 260
 	MsgQueryShortChanIDs                   = 261
 	MsgReplyShortChanIDsEnd                = 262
 	MsgQueryChannelRange                   = 263
 	MsgReplyChannelRange                   = 264
 	MsgGossipTimestampRange                = 265
+	MsgChannelAnnouncement2                = 267
+	MsgChannelUpdate2                      = 271
+	MsgOnionMessage                        = 513
 	MsgKickoffSig                          = 777
 )
 
@@ -84,6 +90,10 @@ func (t MessageType) String() string {
 	switch t {
 	case MsgWarning:
 		return "Warning"
+	case MsgPeerStorage:
+		return "PeerStorage"
+	case MsgPeerStorageRetrieval:
+		return "PeerStorageRetrieval"
 	case MsgInit:
 		return "Init"
 	case MsgOpenChannel:
@@ -152,6 +162,14 @@ func (t MessageType) String() string {
 		return "ClosingComplete"
 	case MsgClosingSig:
 		return "ClosingSig"
+	case MsgAnnounceSignatures2:
+		return "AnnounceSignatures2"
+	case MsgChannelAnnouncement2:
+		return "ChannelAnnouncement2"
+	case MsgChannelUpdate2:
+		return "ChannelUpdate2"
+	case MsgOnionMessage:
+		return "OnionMessage"
 	default:
 		return "<unknown>"
 	}
@@ -211,6 +229,10 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 	switch msgType {
 	case MsgWarning:
 		msg = &Warning{}
+	case MsgPeerStorage:
+		msg = &PeerStorage{}
+	case MsgPeerStorageRetrieval:
+		msg = &PeerStorageRetrieval{}
 	case MsgInit:
 		msg = &Init{}
 	case MsgOpenChannel:
@@ -279,6 +301,24 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &ClosingComplete{}
 	case MsgClosingSig:
 		msg = &ClosingSig{}
+	case MsgAnnounceSignatures2:
+		msg = &AnnounceSignatures2{}
+	case MsgChannelAnnouncement2:
+		msg = &ChannelAnnouncement2{}
+	case MsgChannelUpdate2:
+		msg = &ChannelUpdate2{}
+	case MsgOnionMessage:
+		// Onion messages used to be unknown to lnd, so they could be
+		// overridden to be handled by an external application. Keep
+		// honoring such an override.
+		if IsCustomOverride(msgType) {
+			msg = &Custom{
+				Type: msgType,
+			}
+			break
+		}
+
+		msg = &OnionMessage{}
 	default:
 		// If the message is not within our custom range and has not
 		// specifically been overridden, return an unknown message.
@@ -0,0 +1,80 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// OnionMessagePacket is the serialized onion packet carried by an onion
+// message. Unlike the onion packets used for payments, the packet does not
+// have a fixed size, so it is prefixed with its length on the wire.
+type OnionMessagePacket []byte
+
+// OnionMessage is used to relay a message that is not tied to a payment or a
+// channel along a blinded path. Each hop peels a layer of the enclosed onion
+// and either forwards the remainder to the next node of the path or consumes
+// the message if it is the final recipient.
+type OnionMessage struct {
+	// PathKey is the ephemeral key that the receiving node uses to derive
+	// the shared secret needed to decrypt the data that the creator of
+	// the blinded path left for it.
+	PathKey *btcec.PublicKey
+
+	// OnionBlob is the serialized onion packet that is to be processed by
+	// the receiving node.
+	OnionBlob OnionMessagePacket
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewOnionMessage creates a new OnionMessage with the given path key and onion
+// packet.
+func NewOnionMessage(pathKey *btcec.PublicKey,
+	onion []byte) *OnionMessage {
+
+	return &OnionMessage{
+		PathKey:   pathKey,
+		OnionBlob: onion,
+	}
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Decode deserializes a serialized OnionMessage stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, _ uint32) error {
+	return ReadElements(r, &o.PathKey, &o.OnionBlob, &o.ExtraData)
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WritePublicKey(w, o.PathKey); err != nil {
+		return err
+	}
+
+	if err := WriteOnionMessagePacket(w, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
@@ -0,0 +1,105 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// MaxPeerStorageBytes is the maximum size of a blob that can be sent within a
+// peer_storage or peer_storage_retrieval message. It is the maximum message
+// payload minus the two bytes used to encode the length of the blob.
+const MaxPeerStorageBytes = MaxMsgBody - 2
+
+// PeerStorageBlob is an opaque blob of data that a node asks its peer to store
+// on its behalf. The blob is expected to be encrypted by its owner, so the
+// storing node is unable to interpret its contents.
+type PeerStorageBlob []byte
+
+// PeerStorage is sent by a node to ask its peer to store the enclosed blob on
+// its behalf. Each new PeerStorage message replaces the blob that was
+// previously stored for the sender.
+type PeerStorage struct {
+	// Blob is the encrypted data that the sender would like the receiver
+	// to store.
+	Blob PeerStorageBlob
+}
+
+// NewPeerStorage creates a new PeerStorage message with the given blob.
+func NewPeerStorage(blob []byte) *PeerStorage {
+	return &PeerStorage{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure PeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*PeerStorage)(nil)
+
+// Decode deserializes a serialized PeerStorage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Decode(r io.Reader, _ uint32) error {
+	return ReadElement(r, &p.Blob)
+}
+
+// Encode serializes the target PeerStorage into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Encode(w *bytes.Buffer, _ uint32) error {
+	return WritePeerStorageBlob(w, p.Blob)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MsgType() MessageType {
+	return MsgPeerStorage
+}
+
+// PeerStorageRetrieval is sent by a node that stores a blob on behalf of its
+// peer to hand back the latest stored blob, usually right after the
+// connection has been established.
+type PeerStorageRetrieval struct {
+	// Blob is the latest blob that the sender has stored on behalf of the
+	// receiver.
+	Blob PeerStorageBlob
+}
+
+// NewPeerStorageRetrieval creates a new PeerStorageRetrieval message with the
+// given blob.
+func NewPeerStorageRetrieval(blob []byte) *PeerStorageRetrieval {
+	return &PeerStorageRetrieval{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure PeerStorageRetrieval implements the
+// lnwire.Message interface.
+var _ Message = (*PeerStorageRetrieval)(nil)
+
+// Decode deserializes a serialized PeerStorageRetrieval message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Decode(r io.Reader, _ uint32) error {
+	return ReadElement(r, &p.Blob)
+}
+
+// Encode serializes the target PeerStorageRetrieval into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Encode(w *bytes.Buffer, _ uint32) error {
+	return WritePeerStorageBlob(w, p.Blob)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) MsgType() MessageType {
+	return MsgPeerStorageRetrieval
+}
@@ -0,0 +1,192 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// pureTLVUnsignedRangeStart is the first TLV type of the inclusive
+	// range of types that are not covered by the signature of a pure TLV
+	// gossip message. Signatures themselves are carried in this range.
+	pureTLVUnsignedRangeStart = 160
+
+	// pureTLVUnsignedRangeEnd is the last TLV type of the inclusive range
+	// of types that are not covered by the signature of a pure TLV gossip
+	// message.
+	pureTLVUnsignedRangeEnd = 239
+)
+
+// PureTLVMessage is an interface implemented by the gossip messages that are
+// encoded solely as a TLV stream, without any fixed fields preceding it. Such
+// messages are signed over the serialization of all records that fall outside
+// of the unsigned type range, including any records that we don't know of.
+type PureTLVMessage interface {
+	Message
+
+	// AllRecords returns all the TLV records of the message, including
+	// the ones that we don't know how to parse.
+	AllRecords() ([]tlv.Record, error)
+}
+
+// InUnsignedRange returns true if the given TLV type falls within the range
+// of types that are not covered by the signature of a pure TLV message.
+func InUnsignedRange(t tlv.Type) bool {
+	return t >= pureTLVUnsignedRangeStart && t <= pureTLVUnsignedRangeEnd
+}
+
+// EncodePureTLVMessage serializes all the records of the given message as a
+// single, canonically sorted TLV stream into the passed buffer.
+func EncodePureTLVMessage(msg PureTLVMessage, w *bytes.Buffer) error {
+	records, err := msg.AllRecords()
+	if err != nil {
+		return err
+	}
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// SerialiseFieldsToSign returns the serialization of all the records of the
+// given pure TLV message that are covered by its signature.
+func SerialiseFieldsToSign(msg PureTLVMessage) ([]byte, error) {
+	records, err := msg.AllRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	var signed []tlv.Record
+	for _, record := range records {
+		if InUnsignedRange(record.Type()) {
+			continue
+		}
+
+		signed = append(signed, record)
+	}
+	tlv.SortRecords(signed)
+
+	stream, err := tlv.NewStream(signed...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// MsgHash computes the tagged hash that is signed for a field of a pure TLV
+// gossip message. The tag is the concatenation of "lightning", the message
+// name and the field name.
+func MsgHash(msgName, fieldName string, msg []byte) *chainhash.Hash {
+	tag := []byte("lightning" + msgName + fieldName)
+
+	return chainhash.TaggedHash(tag, msg)
+}
+
+// decodePureTLVMessage reads the remaining bytes of r as a TLV stream and
+// decodes the given records from it. The set of parsed types is returned
+// along with the records that were not recognized, so that they can be
+// re-serialized as part of the signed data later on.
+func decodePureTLVMessage(r io.Reader,
+	records ...tlv.RecordProducer) (tlv.TypeMap, ExtraOpaqueData, error) {
+
+	rawStream, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream := ExtraOpaqueData(rawStream)
+	typeMap, err := stream.ExtractRecords(records...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Any type that has a non-nil value in the type map was not known to
+	// us, so we'll keep it around as extra data.
+	unknown := make(map[uint64][]byte)
+	for typ, val := range typeMap {
+		if val == nil {
+			continue
+		}
+
+		unknown[uint64(typ)] = val
+	}
+
+	if len(unknown) == 0 {
+		return typeMap, nil, nil
+	}
+
+	// MapToRecords already returns the records in sorted order, so we can
+	// encode them directly.
+	unknownStream, err := tlv.NewStream(tlv.MapToRecords(unknown)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var extra bytes.Buffer
+	if err := unknownStream.Encode(&extra); err != nil {
+		return nil, nil, err
+	}
+
+	return typeMap, extra.Bytes(), nil
+}
+
+// extraRecords parses the given extra opaque data into a set of records that
+// re-serialize the unknown TLV values as is.
+func extraRecords(extra ExtraOpaqueData) ([]tlv.Record, error) {
+	if len(extra) == 0 {
+		return nil, nil
+	}
+
+	typeMap, err := extra.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	unknown := make(map[uint64][]byte, len(typeMap))
+	for typ, val := range typeMap {
+		// Records that are part of the known range of the message
+		// would have been parsed into the message itself, so this
+		// should never happen.
+		if val == nil {
+			return nil, fmt.Errorf("unexpected empty extra record "+
+				"of type %d", typ)
+		}
+
+		unknown[uint64(typ)] = val
+	}
+
+	return tlv.MapToRecords(unknown), nil
+}
+
+// sigRecordProducer returns a record producer that can be used to decode a
+// signature carried in the given TLV type.
+func sigRecordProducer(typ tlv.Type, sig *Sig) tlv.RecordProducer {
+	return &sigRecord{typ: typ, sig: sig}
+}
+
+// sigRecord wraps a signature along with the TLV type it is encoded in.
+type sigRecord struct {
+	typ tlv.Type
+	sig *Sig
+}
+
+// Record returns the TLV record of the signature.
+//
+// NOTE: This is part of the tlv.RecordProducer interface.
+func (s *sigRecord) Record() tlv.Record {
+	return tlv.MakePrimitiveRecord(s.typ, &s.sig.bytes)
+}
@@ -3,8 +3,29 @@ package lnwire
 import (
 	"bytes"
 	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+type (
+	// AttrDataTlvType is the TLV type of the attribution data of an
+	// UpdateFailHTLC message.
+	AttrDataTlvType = tlv.TlvType1
+
+	// AttrDataRecord holds the optional attribution data of an
+	// UpdateFailHTLC message, which allows the sender of the HTLC to pin
+	// a failure to a specific hop of the route.
+	AttrDataRecord = tlv.OptionalRecordT[AttrDataTlvType, []byte]
 )
 
+// NewAttrDataRecord returns an attribution data record holding the given
+// attribution data.
+func NewAttrDataRecord(attrData []byte) AttrDataRecord {
+	return tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[AttrDataTlvType](attrData),
+	)
+}
+
 // OpaqueReason is an opaque encrypted byte slice that encodes the exact
 // failure reason and additional some supplemental data. The contents of this
 // slice can only be decrypted by the sender of the original HTLC.
@@ -28,6 +49,12 @@ type UpdateFailHTLC struct {
 	// HTLC message.
 	Reason OpaqueReason
 
+	// AttrData is the optional attribution data of the failure. Each hop
+	// on the way back to the sender adds its hold time and HMACs over the
+	// failure to it, so the sender can identify the hop that corrupted a
+	// failure it can't decrypt.
+	AttrData AttrDataRecord
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -43,12 +70,32 @@ var _ Message = (*UpdateFailHTLC)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateFailHTLC) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	if err := ReadElements(r,
 		&c.ChanID,
 		&c.ID,
 		&c.Reason,
 		&c.ExtraData,
-	)
+	); err != nil {
+		return err
+	}
+
+	attrData := c.AttrData.Zero()
+	tlvMap, err := c.ExtraData.ExtractRecords(&attrData)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := tlvMap[c.AttrData.TlvType()]; ok && val == nil {
+		c.AttrData = tlv.SomeRecordT(attrData)
+	}
+
+	// Set extra data to nil if we didn't parse anything out of it so that
+	// we can use assert.Equal in tests.
+	if len(tlvMap) == 0 {
+		c.ExtraData = nil
+	}
+
+	return nil
 }
 
 // Encode serializes the target UpdateFailHTLC into the passed io.Writer observing
@@ -68,6 +115,17 @@ func (c *UpdateFailHTLC) Encode(w *bytes.Buffer, pver uint32) error {
 		return err
 	}
 
+	// Only include the attribution data in extra data if present.
+	var records []tlv.RecordProducer
+	c.AttrData.WhenSome(func(a tlv.RecordT[AttrDataTlvType, []byte]) {
+		records = append(records, &a)
+	})
+
+	err := EncodeMessageExtraData(&c.ExtraData, records...)
+	if err != nil {
+		return err
+	}
+
 	return WriteBytes(w, c.ExtraData)
 }
 
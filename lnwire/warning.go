@@ -23,6 +23,14 @@ type Warning struct {
 	// Data is the attached warning data that describes the exact failure
 	// which caused the warning message to be sent.
 	Data WarningData
+
+	// Code is the optional machine-readable reason of the warning.
+	Code ErrorCodeRecord
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
 }
 
 // A compile time check to ensure Warning implements the lnwire.Message
@@ -41,7 +49,8 @@ func (c *Warning) Warning() string {
 		errMsg = string(c.Data)
 	}
 
-	return fmt.Sprintf("chan_id=%v, err=%v", c.ChanID, errMsg)
+	return fmt.Sprintf("chan_id=%v%v, err=%v", c.ChanID,
+		errorCodeSummary(c.Code), errMsg)
 }
 
 // Decode deserializes a serialized Warning message stored in the passed
@@ -49,10 +58,18 @@ func (c *Warning) Warning() string {
 //
 // This is part of the lnwire.Message interface.
 func (c *Warning) Decode(r io.Reader, _ uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.Data,
+		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	decodeErrorCode(&c.ExtraData, &c.Code)
+
+	return nil
 }
 
 // Encode serializes the target Warning into the passed io.Writer observing the
@@ -64,7 +81,15 @@ func (c *Warning) Encode(w *bytes.Buffer, _ uint32) error {
 		return err
 	}
 
-	return WriteWarningData(w, c.Data)
+	if err := WriteWarningData(w, c.Data); err != nil {
+		return err
+	}
+
+	if err := encodeErrorCode(&c.ExtraData, c.Code); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
 }
 
 // MsgType returns the integer uniquely identifying an Warning message on the
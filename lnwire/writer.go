@@ -245,6 +245,17 @@ func WriteWarningData(buf *bytes.Buffer, data WarningData) error {
 	return writeDataWithLength(buf, data)
 }
 
+// WritePeerStorageBlob appends the blob to the provided buffer.
+func WritePeerStorageBlob(buf *bytes.Buffer, blob PeerStorageBlob) error {
+	return writeDataWithLength(buf, blob)
+}
+
+// WriteOnionMessagePacket appends the onion message packet to the provided
+// buffer.
+func WriteOnionMessagePacket(buf *bytes.Buffer, p OnionMessagePacket) error {
+	return writeDataWithLength(buf, p)
+}
+
 // WriteErrorData appends the data to the provided buffer.
 func WriteErrorData(buf *bytes.Buffer, data ErrorData) error {
 	return writeDataWithLength(buf, data)
@@ -5,6 +5,8 @@ import (
 	"github.com/btcsuite/btclog"
 	"github.com/lightninglabs/neutrino"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/anchorreserve"
+	"github.com/lightningnetwork/lnd/asyncpay"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/chainntnfs"
@@ -14,30 +16,42 @@ import (
 	"github.com/lightningnetwork/lnd/chanfitness"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/cltvguard"
 	"github.com/lightningnetwork/lnd/cluster"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/fault"
 	"github.com/lightningnetwork/lnd/funding"
 	"github.com/lightningnetwork/lnd/healthcheck"
+	"github.com/lightningnetwork/lnd/holdfee"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/jamdetect"
+	"github.com/lightningnetwork/lnd/jitchannel"
+	"github.com/lightningnetwork/lnd/liquidityalert"
 	"github.com/lightningnetwork/lnd/lnrpc/autopilotrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/devrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/lsprpc"
 	"github.com/lightningnetwork/lnd/lnrpc/neutrinorpc"
 	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/verrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lnurl"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwallet/hwisigner"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
+	"github.com/lightningnetwork/lnd/lsps"
 	"github.com/lightningnetwork/lnd/monitoring"
+	"github.com/lightningnetwork/lnd/monitoring/tracing"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/onionmessage"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/routing"
@@ -162,6 +176,7 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(root, "WTCL", interceptor, wtclient.UseLogger)
 	AddSubLogger(root, "PRNF", interceptor, peernotifier.UseLogger)
 	AddSubLogger(root, "CHFD", interceptor, chanfunding.UseLogger)
+	AddSubLogger(root, "HWIS", interceptor, hwisigner.UseLogger)
 	AddSubLogger(root, "PEER", interceptor, peer.UseLogger)
 	AddSubLogger(root, "CHCL", interceptor, chancloser.UseLogger)
 
@@ -179,6 +194,19 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(root, btcwallet.Subsystem, interceptor, btcwallet.UseLogger)
 	AddSubLogger(root, rpcwallet.Subsystem, interceptor, rpcwallet.UseLogger)
 	AddSubLogger(root, peersrpc.Subsystem, interceptor, peersrpc.UseLogger)
+	AddSubLogger(root, lsprpc.Subsystem, interceptor, lsprpc.UseLogger)
+	AddSubLogger(root, fault.Subsystem, interceptor, fault.UseLogger)
+	AddSubLogger(root, tracing.Subsystem, interceptor, tracing.UseLogger)
+	AddSubLogger(root, lnurl.Subsystem, interceptor, lnurl.UseLogger)
+	AddSubLogger(root, anchorreserve.Subsystem, interceptor, anchorreserve.UseLogger)
+	AddSubLogger(root, liquidityalert.Subsystem, interceptor, liquidityalert.UseLogger)
+	AddSubLogger(root, cltvguard.Subsystem, interceptor, cltvguard.UseLogger)
+	AddSubLogger(root, holdfee.Subsystem, interceptor, holdfee.UseLogger)
+	AddSubLogger(root, jamdetect.Subsystem, interceptor, jamdetect.UseLogger)
+	AddSubLogger(root, lsps.Subsystem, interceptor, lsps.UseLogger)
+	AddSubLogger(root, jitchannel.Subsystem, interceptor, jitchannel.UseLogger)
+	AddSubLogger(root, onionmessage.Subsystem, interceptor, onionmessage.UseLogger)
+	AddSubLogger(root, asyncpay.Subsystem, interceptor, asyncpay.UseLogger)
 }
 
 // AddSubLogger is a helper method to conveniently create and register the
@@ -0,0 +1,278 @@
+// Package lsps implements the client side of the LSP specifications: the
+// JSON-RPC transport over custom peer messages (LSPS0), ordering channels from
+// an LSP (LSPS1) and buying just-in-time channels (LSPS2).
+package lsps
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// MessageType is the custom peer message type LSPS0 uses to transport
+	// JSON-RPC requests and responses.
+	MessageType lnwire.MessageType = 37913
+
+	// DefaultRequestTimeout is the default time we wait for the response
+	// of an LSP to a request.
+	DefaultRequestTimeout = 30 * time.Second
+
+	// jsonRPCVersion is the JSON-RPC version of all requests and
+	// responses.
+	jsonRPCVersion = "2.0"
+
+	// requestIDLen is the number of random bytes of a request id.
+	requestIDLen = 16
+)
+
+var (
+	// ErrClientShuttingDown is returned when a request is aborted because
+	// the client is shutting down.
+	ErrClientShuttingDown = errors.New("lsps client shutting down")
+
+	// ErrRequestTimeout is returned when an LSP doesn't respond to a
+	// request in time.
+	ErrRequestTimeout = errors.New("lsps request timed out")
+)
+
+// request is a JSON-RPC request sent to an LSP.
+type request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      string      `json:"id"`
+}
+
+// response is a JSON-RPC response of an LSP.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      string          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is an error an LSP responded with.
+type RPCError struct {
+	// Code is the JSON-RPC error code.
+	Code int `json:"code"`
+
+	// Message is a short description of the error.
+	Message string `json:"message"`
+
+	// Data holds additional information about the error, if any.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Error returns a human-readable representation of the error.
+//
+// NOTE: This is part of the error interface.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("lsp error %d: %s", e.Code, e.Message)
+}
+
+// pendingKey identifies a request that waits for its response.
+type pendingKey struct {
+	peer route.Vertex
+	id   string
+}
+
+// Config holds the dependencies of the LSP client.
+type Config struct {
+	// SendCustomMessage sends a custom message to a connected peer.
+	SendCustomMessage func(peer [33]byte, msgType lnwire.MessageType,
+		data []byte) error
+
+	// Acceptor is the channel acceptor that the client adds its acceptor
+	// for just-in-time channels to while it expects any.
+	Acceptor chanacceptor.MultiplexAcceptor
+
+	// Clock is used to check the expiry of just-in-time channel offers.
+	Clock clock.Clock
+
+	// RequestTimeout is the time we wait for the response of an LSP to a
+	// request.
+	RequestTimeout time.Duration
+}
+
+// Client is the client side of the LSP specifications. It sends JSON-RPC
+// requests to LSPs over custom peer messages, and accepts the just-in-time
+// channels bought from them.
+//
+// NOTE: This struct is safe for concurrent access.
+type Client struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// pending holds the channels the responses to the outstanding
+	// requests are delivered on.
+	pending map[pendingKey]chan *response
+
+	// jitChannels holds the just-in-time channels we bought and expect
+	// the LSPs to open.
+	jitChannels map[lnwire.ShortChannelID]*JITChannel
+
+	// acceptorID is the id of our acceptor in the channel acceptor, if
+	// it's added.
+	acceptorID     uint64
+	acceptorActive bool
+
+	mu   sync.Mutex
+	quit chan struct{}
+}
+
+// NewClient creates a new LSP client.
+func NewClient(cfg *Config) *Client {
+	return &Client{
+		cfg:         cfg,
+		pending:     make(map[pendingKey]chan *response),
+		jitChannels: make(map[lnwire.ShortChannelID]*JITChannel),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start starts the LSP client.
+func (c *Client) Start() error {
+	c.started.Do(func() {
+		log.Info("LSP client starting")
+	})
+
+	return nil
+}
+
+// Stop aborts all outstanding requests and removes our channel acceptor.
+func (c *Client) Stop() error {
+	c.stopped.Do(func() {
+		log.Info("LSP client shutting down...")
+		defer log.Debug("LSP client shutdown complete")
+
+		close(c.quit)
+
+		c.mu.Lock()
+		c.removeAcceptor()
+		c.mu.Unlock()
+	})
+
+	return nil
+}
+
+// HandleMessage processes a custom message of the LSPS0 message type that the
+// given peer sent us. Responses are delivered to the requests waiting for
+// them, anything else is ignored.
+func (c *Client) HandleMessage(peer route.Vertex, data []byte) {
+	var resp response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		log.Debugf("Ignoring invalid LSPS message from %v: %v", peer,
+			err)
+
+		return
+	}
+
+	key := pendingKey{peer: peer, id: resp.ID}
+
+	c.mu.Lock()
+	respChan, ok := c.pending[key]
+	delete(c.pending, key)
+	c.mu.Unlock()
+
+	if !ok {
+		log.Debugf("Ignoring LSPS message from %v without matching "+
+			"request", peer)
+
+		return
+	}
+
+	// The channel is buffered, so this never blocks.
+	respChan <- &resp
+}
+
+// call sends a request to an LSP and waits for its response. The result of
+// the response is decoded into result.
+func (c *Client) call(ctx context.Context, lsp route.Vertex, method string,
+	params, result interface{}) error {
+
+	var idBytes [requestIDLen]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return err
+	}
+	id := hex.EncodeToString(idBytes[:])
+
+	// Requests must always have params, even if they're empty.
+	if params == nil {
+		params = struct{}{}
+	}
+
+	data, err := json.Marshal(&request{
+		JSONRPC: jsonRPCVersion,
+		Method:  method,
+		Params:  params,
+		ID:      id,
+	})
+	if err != nil {
+		return err
+	}
+
+	key := pendingKey{peer: lsp, id: id}
+	respChan := make(chan *response, 1)
+
+	c.mu.Lock()
+	c.pending[key] = respChan
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+	}()
+
+	log.Debugf("Sending %v request %v to LSP %v", method, id, lsp)
+
+	err = c.cfg.SendCustomMessage(lsp, MessageType, data)
+	if err != nil {
+		return fmt.Errorf("unable to send %v request: %w", method,
+			err)
+	}
+
+	timeout := time.NewTimer(c.cfg.RequestTimeout)
+	defer timeout.Stop()
+
+	var resp *response
+	select {
+	case resp = <-respChan:
+
+	case <-timeout.C:
+		return ErrRequestTimeout
+
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-c.quit:
+		return ErrClientShuttingDown
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("invalid %v response: %w", method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package lsps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLSP answers the requests of a client with the results or errors of its
+// handlers.
+type mockLSP struct {
+	t      *testing.T
+	client *Client
+
+	// handlers returns the response to a request by method.
+	handlers map[string]func(params json.RawMessage) (interface{},
+		*RPCError)
+}
+
+// sendCustomMessage is passed to the client to send messages to the LSP.
+func (m *mockLSP) sendCustomMessage(peer [33]byte, msgType lnwire.MessageType,
+	data []byte) error {
+
+	require.Equal(m.t, MessageType, msgType)
+
+	var req struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+		ID      string          `json:"id"`
+	}
+	require.NoError(m.t, json.Unmarshal(data, &req))
+	require.Equal(m.t, jsonRPCVersion, req.JSONRPC)
+
+	// Params must always be an object.
+	require.Equal(m.t, byte('{'), req.Params[0])
+
+	handler, ok := m.handlers[req.Method]
+	if !ok {
+		// Don't respond, so the request times out.
+		return nil
+	}
+
+	result, rpcErr := handler(req.Params)
+
+	resp := map[string]interface{}{
+		"jsonrpc": jsonRPCVersion,
+		"id":      req.ID,
+	}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+
+	respData, err := json.Marshal(resp)
+	require.NoError(m.t, err)
+
+	// Responses arrive asynchronously, like they do from a peer.
+	go m.client.HandleMessage(peer, respData)
+
+	return nil
+}
+
+// newTestClient creates a client that talks to a mock LSP.
+func newTestClient(t *testing.T, testClock clock.Clock,
+	acceptor chanacceptor.MultiplexAcceptor) (*Client, *mockLSP) {
+
+	lsp := &mockLSP{
+		t: t,
+		handlers: make(
+			map[string]func(json.RawMessage) (interface{},
+				*RPCError),
+		),
+	}
+
+	client := NewClient(&Config{
+		SendCustomMessage: lsp.sendCustomMessage,
+		Acceptor:          acceptor,
+		Clock:             testClock,
+		RequestTimeout:    time.Second,
+	})
+	lsp.client = client
+
+	require.NoError(t, client.Start())
+	t.Cleanup(func() {
+		require.NoError(t, client.Stop())
+	})
+
+	return client, lsp
+}
+
+// newTestLSPKey returns a new random public key for an LSP.
+func newTestLSPKey(t *testing.T) *btcec.PublicKey {
+	t.Helper()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return priv.PubKey()
+}
+
+// TestClientCall tests that requests are matched with their responses, and
+// that errors and timeouts are reported.
+func TestClientCall(t *testing.T) {
+	t.Parallel()
+
+	client, lsp := newTestClient(t, clock.NewDefaultClock(), nil)
+	lspKey := route.NewVertex(newTestLSPKey(t))
+	ctx := context.Background()
+
+	lsp.handlers["lsps0.list_protocols"] = func(
+		json.RawMessage) (interface{}, *RPCError) {
+
+		return map[string]interface{}{
+			"protocols": []int{1, 2},
+		}, nil
+	}
+
+	protocols, err := client.ListProtocols(ctx, lspKey)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{1, 2}, protocols)
+
+	lsp.handlers["lsps1.get_order"] = func(
+		json.RawMessage) (interface{}, *RPCError) {
+
+		return nil, &RPCError{Code: 101, Message: "not found"}
+	}
+
+	_, err = client.LSPS1GetOrder(ctx, lspKey, "order")
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, 101, rpcErr.Code)
+
+	// Requests the LSP doesn't answer time out.
+	_, err = client.LSPS1GetInfo(ctx, lspKey)
+	require.ErrorIs(t, err, ErrRequestTimeout)
+	require.Empty(t, client.pending)
+
+	// Responses from other peers or without a request are ignored.
+	client.HandleMessage(lspKey, []byte(`{"jsonrpc":"2.0","id":"x"}`))
+	client.HandleMessage(lspKey, []byte(`invalid`))
+}
+
+// TestLSPS1CreateOrder tests that channel orders are encoded as the LSPS1
+// specification defines them.
+func TestLSPS1CreateOrder(t *testing.T) {
+	t.Parallel()
+
+	client, lsp := newTestClient(t, clock.NewDefaultClock(), nil)
+	lspKey := route.NewVertex(newTestLSPKey(t))
+
+	lsp.handlers["lsps1.create_order"] = func(
+		params json.RawMessage) (interface{}, *RPCError) {
+
+		var req map[string]interface{}
+		require.NoError(t, json.Unmarshal(params, &req))
+
+		// Amounts are encoded as strings.
+		require.Equal(t, "500000", req["lsp_balance_sat"])
+		require.Equal(t, "0", req["client_balance_sat"])
+
+		return map[string]interface{}{
+			"order_id":           "order1",
+			"lsp_balance_sat":    "500000",
+			"client_balance_sat": "0",
+			"order_state":        "CREATED",
+			"created_at":         "2024-01-01T00:00:00Z",
+			"payment": map[string]interface{}{
+				"bolt11": map[string]interface{}{
+					"state":      "EXPECT_PAYMENT",
+					"expires_at": "2024-01-01T01:00:00Z",
+					"invoice":    "lnbc",
+
+					"fee_total_sat":   "2500",
+					"order_total_sat": "2500",
+				},
+			},
+		}, nil
+	}
+
+	order, err := client.LSPS1CreateOrder(
+		context.Background(), lspKey, &OrderRequest{
+			LSPBalance:          500_000,
+			ChannelExpiryBlocks: 144,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "order1", order.OrderID)
+	require.Equal(t, OrderStateCreated, order.State)
+	require.Nil(t, order.Channel)
+	require.NotNil(t, order.Payment.Bolt11)
+	require.EqualValues(t, 2500, order.Payment.Bolt11.FeeTotal)
+	require.Equal(t, "lnbc", order.Payment.Bolt11.Invoice)
+}
+
+// TestOpeningFee tests the calculation of the opening fee of just-in-time
+// channels.
+func TestOpeningFee(t *testing.T) {
+	t.Parallel()
+
+	params := &OpeningFeeParams{
+		MinFee:       2_000_000,
+		Proportional: 10_000,
+	}
+
+	// Below the minimum fee, the minimum fee is charged.
+	fee, err := params.OpeningFee(100_000_000)
+	require.NoError(t, err)
+	require.EqualValues(t, 2_000_000, fee)
+
+	// The proportional fee is rounded up.
+	fee, err = params.OpeningFee(300_000_001)
+	require.NoError(t, err)
+	require.EqualValues(t, 3_000_001, fee)
+
+	_, err = params.OpeningFee(lnwire.MilliSatoshi(1 << 63))
+	require.Error(t, err)
+}
+
+// TestParseSCID tests parsing short channel ids in the format of the LSP
+// specifications.
+func TestParseSCID(t *testing.T) {
+	t.Parallel()
+
+	scid, err := parseSCID("29451x4815x1")
+	require.NoError(t, err)
+	require.Equal(t, lnwire.ShortChannelID{
+		BlockHeight: 29451,
+		TxIndex:     4815,
+		TxPosition:  1,
+	}, scid)
+
+	for _, invalid := range []string{"", "1x2", "1x2x3x4", "ax2x3"} {
+		_, err := parseSCID(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+// TestLSPS2Buy tests that a bought just-in-time channel is accepted as
+// zero-conf channel from its LSP until its fee params expire.
+func TestLSPS2Buy(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	testClock := clock.NewTestClock(now)
+	acceptor := chanacceptor.NewChainedAcceptor()
+
+	client, lsp := newTestClient(t, testClock, acceptor)
+
+	lspPub := newTestLSPKey(t)
+	otherPub := newTestLSPKey(t)
+	lspKey := route.NewVertex(lspPub)
+	ctx := context.Background()
+
+	lsp.handlers["lsps2.buy"] = func(
+		params json.RawMessage) (interface{}, *RPCError) {
+
+		var req map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(params, &req))
+		require.Equal(t, `"42000000"`, string(req["payment_size_msat"]))
+
+		return map[string]interface{}{
+			"jit_channel_scid":      "800000x10x1",
+			"lsp_cltv_expiry_delta": 144,
+			"client_trusts_lsp":     false,
+		}, nil
+	}
+
+	params := &OpeningFeeParams{
+		MinFee:         1_000_000,
+		Proportional:   1000,
+		ValidUntil:     now.Add(time.Hour),
+		MinPaymentSize: 10_000_000,
+		MaxPaymentSize: 100_000_000,
+		Promise:        "promise",
+	}
+
+	// Payment sizes outside of the range of the params are rejected.
+	tooSmall := lnwire.MilliSatoshi(1000)
+	_, err := client.LSPS2Buy(ctx, lspKey, params, &tooSmall)
+	require.ErrorIs(t, err, ErrPaymentSizeOutOfRange)
+
+	paymentSize := lnwire.MilliSatoshi(42_000_000)
+	jitChannel, err := client.LSPS2Buy(ctx, lspKey, params, &paymentSize)
+	require.NoError(t, err)
+	require.EqualValues(t, 800_000, jitChannel.SCID.BlockHeight)
+
+	hint, err := jitChannel.HopHint()
+	require.NoError(t, err)
+	require.Equal(t, jitChannel.SCID.ToUint64(), hint.ChannelID)
+	require.EqualValues(t, 144, hint.CLTVExpiryDelta)
+	require.True(t, hint.NodeID.IsEqual(lspPub))
+
+	require.Len(t, client.JITChannels(), 1)
+
+	zeroConfType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.ZeroConfRequired,
+	))
+	newRequest := func(node *btcec.PublicKey,
+		zeroConf bool) *chanacceptor.ChannelAcceptRequest {
+
+		req := &chanacceptor.ChannelAcceptRequest{
+			Node:        node,
+			OpenChanMsg: &lnwire.OpenChannel{},
+		}
+		if zeroConf {
+			req.OpenChanMsg.ChannelType = &zeroConfType
+		}
+
+		return req
+	}
+
+	// Zero-conf channels from other peers get no opinion, which doesn't
+	// allow them to be zero-conf.
+	resp := acceptor.Accept(newRequest(otherPub, true))
+	require.False(t, resp.RejectChannel())
+	require.False(t, resp.ZeroConf)
+
+	// Regular channels from the LSP get no opinion either.
+	resp = acceptor.Accept(newRequest(lspPub, false))
+	require.False(t, resp.RejectChannel())
+	require.False(t, resp.ZeroConf)
+	require.Len(t, client.JITChannels(), 1)
+
+	// The zero-conf channel from the LSP is accepted once.
+	resp = acceptor.Accept(newRequest(lspPub, true))
+	require.False(t, resp.RejectChannel())
+	require.True(t, resp.ZeroConf)
+	require.Empty(t, client.JITChannels())
+
+	resp = acceptor.Accept(newRequest(lspPub, true))
+	require.False(t, resp.ZeroConf)
+
+	// Expired fee params can't be used to buy a channel, and channels
+	// bought with them are no longer expected.
+	_, err = client.LSPS2Buy(ctx, lspKey, params, &paymentSize)
+	require.NoError(t, err)
+
+	testClock.SetTime(now.Add(time.Hour))
+	require.Empty(t, client.JITChannels())
+
+	_, err = client.LSPS2Buy(ctx, lspKey, params, &paymentSize)
+	require.ErrorIs(t, err, ErrFeeParamsExpired)
+}
@@ -0,0 +1,26 @@
+package lsps
+
+import (
+	"context"
+
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// listProtocolsResult is the result of an lsps0.list_protocols request.
+type listProtocolsResult struct {
+	Protocols []uint16 `json:"protocols"`
+}
+
+// ListProtocols returns the LSP specifications the given LSP supports, such
+// as 1 for LSPS1 and 2 for LSPS2.
+func (c *Client) ListProtocols(ctx context.Context,
+	lsp route.Vertex) ([]uint16, error) {
+
+	var result listProtocolsResult
+	err := c.call(ctx, lsp, "lsps0.list_protocols", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Protocols, nil
+}
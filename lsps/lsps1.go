@@ -0,0 +1,257 @@
+package lsps
+
+import (
+	"context"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// OrderState is the state of a channel order.
+type OrderState string
+
+const (
+	// OrderStateCreated is the state of an order that waits for its
+	// payment or for the channel to be opened.
+	OrderStateCreated OrderState = "CREATED"
+
+	// OrderStateCompleted is the state of an order whose channel is open.
+	OrderStateCompleted OrderState = "COMPLETED"
+
+	// OrderStateFailed is the state of an order that failed, in which
+	// case the payment is refunded.
+	OrderStateFailed OrderState = "FAILED"
+)
+
+// PaymentState is the state of the payment of a channel order.
+type PaymentState string
+
+const (
+	// PaymentStateExpectPayment is the state of a payment the LSP waits
+	// for.
+	PaymentStateExpectPayment PaymentState = "EXPECT_PAYMENT"
+
+	// PaymentStateHold is the state of a payment the LSP received but
+	// didn't settle yet.
+	PaymentStateHold PaymentState = "HOLD"
+
+	// PaymentStatePaid is the state of a payment the LSP settled.
+	PaymentStatePaid PaymentState = "PAID"
+
+	// PaymentStateRefunded is the state of a payment the LSP refunded.
+	PaymentStateRefunded PaymentState = "REFUNDED"
+)
+
+// LSPS1Info describes the channels an LSP sells, as returned by lsps1.get_info.
+type LSPS1Info struct {
+	// MinRequiredChannelConfirmations is the minimum number of
+	// confirmations the LSP waits for before the channel can be used.
+	MinRequiredChannelConfirmations uint16 `json:"min_required_channel_confirmations"` //nolint:lll
+
+	// MinFundingConfirmsWithinBlocks is the minimum number of blocks the
+	// funding transaction can be requested to confirm in.
+	MinFundingConfirmsWithinBlocks uint16 `json:"min_funding_confirms_within_blocks"` //nolint:lll
+
+	// SupportsZeroChannelReserve is true if the LSP opens channels
+	// without a channel reserve for the client.
+	SupportsZeroChannelReserve bool `json:"supports_zero_channel_reserve"`
+
+	// MaxChannelExpiryBlocks is the maximum number of blocks the LSP
+	// keeps a channel open for.
+	MaxChannelExpiryBlocks uint32 `json:"max_channel_expiry_blocks"`
+
+	// MinInitialClientBalance is the minimum balance the client can buy
+	// on its side of the channel.
+	MinInitialClientBalance btcutil.Amount `json:"min_initial_client_balance_sat,string"` //nolint:lll
+
+	// MaxInitialClientBalance is the maximum balance the client can buy
+	// on its side of the channel.
+	MaxInitialClientBalance btcutil.Amount `json:"max_initial_client_balance_sat,string"` //nolint:lll
+
+	// MinInitialLSPBalance is the minimum balance the client can request
+	// on the LSP side of the channel.
+	MinInitialLSPBalance btcutil.Amount `json:"min_initial_lsp_balance_sat,string"` //nolint:lll
+
+	// MaxInitialLSPBalance is the maximum balance the client can request
+	// on the LSP side of the channel.
+	MaxInitialLSPBalance btcutil.Amount `json:"max_initial_lsp_balance_sat,string"` //nolint:lll
+
+	// MinChannelBalance is the minimum capacity of a channel.
+	MinChannelBalance btcutil.Amount `json:"min_channel_balance_sat,string"`
+
+	// MaxChannelBalance is the maximum capacity of a channel.
+	MaxChannelBalance btcutil.Amount `json:"max_channel_balance_sat,string"`
+}
+
+// OrderRequest describes the channel a client orders from an LSP.
+type OrderRequest struct {
+	// LSPBalance is the balance on the LSP side of the channel.
+	LSPBalance btcutil.Amount `json:"lsp_balance_sat,string"`
+
+	// ClientBalance is the balance on the client side of the channel,
+	// which the client pays for on top of the fees.
+	ClientBalance btcutil.Amount `json:"client_balance_sat,string"`
+
+	// RequiredChannelConfirmations is the number of confirmations the
+	// LSP waits for before the channel can be used.
+	RequiredChannelConfirmations uint16 `json:"required_channel_confirmations"` //nolint:lll
+
+	// FundingConfirmsWithinBlocks is the number of blocks the funding
+	// transaction should confirm in.
+	FundingConfirmsWithinBlocks uint16 `json:"funding_confirms_within_blocks"` //nolint:lll
+
+	// ChannelExpiryBlocks is the number of blocks the LSP keeps the
+	// channel open for at least.
+	ChannelExpiryBlocks uint32 `json:"channel_expiry_blocks"`
+
+	// Token is an optional token the LSP handed out, for example for a
+	// discount.
+	Token string `json:"token,omitempty"`
+
+	// RefundOnchainAddress is the address the LSP refunds on-chain
+	// payments to if the order fails.
+	RefundOnchainAddress string `json:"refund_onchain_address,omitempty"`
+
+	// AnnounceChannel is true if the channel is announced to the network.
+	AnnounceChannel bool `json:"announce_channel"`
+}
+
+// Bolt11Payment describes how to pay for an order with a lightning payment.
+type Bolt11Payment struct {
+	// State is the state of the payment.
+	State PaymentState `json:"state"`
+
+	// ExpiresAt is the time the invoice expires.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// FeeTotal is the fee the LSP charges for the channel.
+	FeeTotal btcutil.Amount `json:"fee_total_sat,string"`
+
+	// OrderTotal is the amount to pay, which is the fee plus the client
+	// balance.
+	OrderTotal btcutil.Amount `json:"order_total_sat,string"`
+
+	// Invoice is the invoice to pay.
+	Invoice string `json:"invoice"`
+}
+
+// OnchainPayment describes how to pay for an order on-chain.
+type OnchainPayment struct {
+	// State is the state of the payment.
+	State PaymentState `json:"state"`
+
+	// ExpiresAt is the time after which the address is no longer watched.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// FeeTotal is the fee the LSP charges for the channel.
+	FeeTotal btcutil.Amount `json:"fee_total_sat,string"`
+
+	// OrderTotal is the amount to pay, which is the fee plus the client
+	// balance.
+	OrderTotal btcutil.Amount `json:"order_total_sat,string"`
+
+	// Address is the address to pay to.
+	Address string `json:"address"`
+
+	// MinOnchainPaymentConfirmations is the number of confirmations the
+	// LSP waits for before it considers the payment paid.
+	MinOnchainPaymentConfirmations *uint16 `json:"min_onchain_payment_confirmations,omitempty"` //nolint:lll
+
+	// MinFeeFor0Conf is the minimum fee rate in sat/vB of a payment the
+	// LSP accepts without confirmation.
+	MinFeeFor0Conf uint64 `json:"min_fee_for_0conf,omitempty"`
+}
+
+// OrderPayment describes the ways to pay for an order.
+type OrderPayment struct {
+	// Bolt11 describes how to pay with a lightning payment, if the LSP
+	// supports it.
+	Bolt11 *Bolt11Payment `json:"bolt11,omitempty"`
+
+	// Onchain describes how to pay on-chain, if the LSP supports it.
+	Onchain *OnchainPayment `json:"onchain,omitempty"`
+}
+
+// OrderChannel describes the channel of an order once it's funded.
+type OrderChannel struct {
+	// FundedAt is the time the funding transaction was published.
+	FundedAt time.Time `json:"funded_at"`
+
+	// FundingOutpoint is the funding outpoint of the channel.
+	FundingOutpoint string `json:"funding_outpoint"`
+
+	// ExpiresAt is the earliest time the LSP may close the channel.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Order is a channel order as the LSP tracks it.
+type Order struct {
+	OrderRequest
+
+	// OrderID identifies the order.
+	OrderID string `json:"order_id"`
+
+	// CreatedAt is the time the order was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// State is the state of the order.
+	State OrderState `json:"order_state"`
+
+	// Payment describes how to pay for the order.
+	Payment OrderPayment `json:"payment"`
+
+	// Channel describes the channel once it's funded.
+	Channel *OrderChannel `json:"channel,omitempty"`
+}
+
+// getOrderParams are the params of an lsps1.get_order request.
+type getOrderParams struct {
+	OrderID string `json:"order_id"`
+}
+
+// LSPS1GetInfo returns the channels the given LSP sells.
+func (c *Client) LSPS1GetInfo(ctx context.Context,
+	lsp route.Vertex) (*LSPS1Info, error) {
+
+	var info LSPS1Info
+	if err := c.call(ctx, lsp, "lsps1.get_info", nil, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// LSPS1CreateOrder orders a channel from the given LSP. The returned order
+// describes how to pay for the channel.
+func (c *Client) LSPS1CreateOrder(ctx context.Context, lsp route.Vertex,
+	req *OrderRequest) (*Order, error) {
+
+	var order Order
+	err := c.call(ctx, lsp, "lsps1.create_order", req, &order)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Created order %v with LSP %v for a channel with %v on "+
+		"the LSP side and %v on our side", order.OrderID, lsp,
+		req.LSPBalance, req.ClientBalance)
+
+	return &order, nil
+}
+
+// LSPS1GetOrder returns the current state of an order.
+func (c *Client) LSPS1GetOrder(ctx context.Context, lsp route.Vertex,
+	orderID string) (*Order, error) {
+
+	var order Order
+	err := c.call(
+		ctx, lsp, "lsps1.get_order", &getOrderParams{OrderID: orderID},
+		&order,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
@@ -0,0 +1,383 @@
+package lsps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+var (
+	// ErrFeeParamsExpired is returned when buying a just-in-time channel
+	// with opening fee params that are no longer valid.
+	ErrFeeParamsExpired = errors.New("opening fee params expired")
+
+	// ErrPaymentSizeOutOfRange is returned when buying a just-in-time
+	// channel for a payment size the opening fee params don't allow.
+	ErrPaymentSizeOutOfRange = errors.New("payment size out of range")
+)
+
+// OpeningFeeParams are the fees and limits an LSP offers just-in-time channels
+// for. The promise commits the LSP to them until they're no longer valid.
+type OpeningFeeParams struct {
+	// MinFee is the minimum fee the LSP charges for opening a channel.
+	MinFee lnwire.MilliSatoshi `json:"min_fee_msat,string"`
+
+	// Proportional is the fee the LSP charges in parts per million of the
+	// payment size.
+	Proportional uint32 `json:"proportional"`
+
+	// ValidUntil is the time after which the params are no longer valid.
+	ValidUntil time.Time `json:"valid_until"`
+
+	// MinLifetime is the number of blocks the LSP keeps the channel open
+	// for at least.
+	MinLifetime uint32 `json:"min_lifetime"`
+
+	// MaxClientToSelfDelay is the maximum to_self_delay the LSP accepts
+	// for its funds in the channel.
+	MaxClientToSelfDelay uint32 `json:"max_client_to_self_delay"`
+
+	// MinPaymentSize is the minimum payment size the LSP opens a channel
+	// for.
+	MinPaymentSize lnwire.MilliSatoshi `json:"min_payment_size_msat,string"`
+
+	// MaxPaymentSize is the maximum payment size the LSP opens a channel
+	// for.
+	MaxPaymentSize lnwire.MilliSatoshi `json:"max_payment_size_msat,string"`
+
+	// Promise is the LSP's commitment to the params, which the client
+	// passes back unchanged when buying a channel.
+	Promise string `json:"promise"`
+}
+
+// OpeningFee returns the fee the LSP charges for opening a channel for a
+// payment of the given size.
+func (p *OpeningFeeParams) OpeningFee(
+	paymentSize lnwire.MilliSatoshi) (lnwire.MilliSatoshi, error) {
+
+	if p.Proportional != 0 &&
+		uint64(paymentSize) > (math.MaxUint64-999_999)/
+			uint64(p.Proportional) {
+
+		return 0, fmt.Errorf("opening fee for payment size %v "+
+			"overflows", paymentSize)
+	}
+
+	// The proportional fee is rounded up.
+	fee := (uint64(paymentSize)*uint64(p.Proportional) + 999_999) /
+		1_000_000
+
+	if fee < uint64(p.MinFee) {
+		return p.MinFee, nil
+	}
+
+	return lnwire.MilliSatoshi(fee), nil
+}
+
+// LSPS2Info holds the opening fee params an LSP currently offers just-in-time
+// channels for, as returned by lsps2.get_info.
+type LSPS2Info struct {
+	// OpeningFeeParamsMenu are the offered params, ordered by increasing
+	// fees.
+	OpeningFeeParamsMenu []*OpeningFeeParams `json:"opening_fee_params_menu"` //nolint:lll
+}
+
+// lsps2GetInfoParams are the params of an lsps2.get_info request.
+type lsps2GetInfoParams struct {
+	Token string `json:"token,omitempty"`
+}
+
+// lsps2BuyParams are the params of an lsps2.buy request.
+type lsps2BuyParams struct {
+	OpeningFeeParams *OpeningFeeParams    `json:"opening_fee_params"`
+	PaymentSize      *lnwire.MilliSatoshi `json:"payment_size_msat,omitempty,string"` //nolint:lll
+}
+
+// lsps2BuyResult is the result of an lsps2.buy request.
+type lsps2BuyResult struct {
+	JITChannelSCID     string `json:"jit_channel_scid"`
+	LSPCltvExpiryDelta uint16 `json:"lsp_cltv_expiry_delta"`
+	ClientTrustsLSP    bool   `json:"client_trusts_lsp"`
+}
+
+// JITChannel is a just-in-time channel bought from an LSP. The LSP opens the
+// channel once it receives a payment for the invoice that routes through the
+// channel's short channel id.
+type JITChannel struct {
+	// LSP is the node that opens the channel.
+	LSP route.Vertex
+
+	// SCID is the short channel id the invoice's route hint uses for the
+	// channel.
+	SCID lnwire.ShortChannelID
+
+	// LSPCltvExpiryDelta is the CLTV delta the LSP requires for the route
+	// hint.
+	LSPCltvExpiryDelta uint16
+
+	// ClientTrustsLSP is true if the LSP expects the client to release the
+	// preimage before the funding transaction is published.
+	ClientTrustsLSP bool
+
+	// OpeningFeeParams are the params the channel was bought with.
+	OpeningFeeParams *OpeningFeeParams
+
+	// PaymentSize is the size of the payment the channel was bought for,
+	// if the size was fixed.
+	PaymentSize *lnwire.MilliSatoshi
+}
+
+// HopHint returns the route hint hop an invoice needs to include, so that the
+// payer routes the payment through the LSP and the channel it opens.
+func (j *JITChannel) HopHint() (zpay32.HopHint, error) {
+	lspKey, err := btcec.ParsePubKey(j.LSP[:])
+	if err != nil {
+		return zpay32.HopHint{}, err
+	}
+
+	return zpay32.HopHint{
+		NodeID:          lspKey,
+		ChannelID:       j.SCID.ToUint64(),
+		CLTVExpiryDelta: j.LSPCltvExpiryDelta,
+	}, nil
+}
+
+// LSPS2GetInfo returns the opening fee params the given LSP currently offers
+// just-in-time channels for. The token is optional.
+func (c *Client) LSPS2GetInfo(ctx context.Context, lsp route.Vertex,
+	token string) (*LSPS2Info, error) {
+
+	var info LSPS2Info
+	err := c.call(
+		ctx, lsp, "lsps2.get_info", &lsps2GetInfoParams{Token: token},
+		&info,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// LSPS2Buy buys a just-in-time channel from the given LSP with opening fee
+// params it offered. The payment size is optional, without it the invoice may
+// be paid with any amount the params allow. Until the params expire, the
+// client accepts a zero-conf channel from the LSP for the bought channel.
+func (c *Client) LSPS2Buy(ctx context.Context, lsp route.Vertex,
+	params *OpeningFeeParams,
+	paymentSize *lnwire.MilliSatoshi) (*JITChannel, error) {
+
+	if !c.cfg.Clock.Now().Before(params.ValidUntil) {
+		return nil, ErrFeeParamsExpired
+	}
+
+	if paymentSize != nil {
+		if *paymentSize < params.MinPaymentSize ||
+			*paymentSize > params.MaxPaymentSize {
+
+			return nil, fmt.Errorf("%w: %v not in [%v, %v]",
+				ErrPaymentSizeOutOfRange, *paymentSize,
+				params.MinPaymentSize, params.MaxPaymentSize)
+		}
+
+		fee, err := params.OpeningFee(*paymentSize)
+		if err != nil {
+			return nil, err
+		}
+
+		// The LSP deducts the fee from the payment, so a payment
+		// that doesn't cover it can't open the channel.
+		if fee >= *paymentSize {
+			return nil, fmt.Errorf("opening fee %v exceeds "+
+				"payment size %v", fee, *paymentSize)
+		}
+	}
+
+	var result lsps2BuyResult
+	err := c.call(ctx, lsp, "lsps2.buy", &lsps2BuyParams{
+		OpeningFeeParams: params,
+		PaymentSize:      paymentSize,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	scid, err := parseSCID(result.JITChannelSCID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jit channel scid: %w", err)
+	}
+
+	jitChannel := &JITChannel{
+		LSP:                lsp,
+		SCID:               scid,
+		LSPCltvExpiryDelta: result.LSPCltvExpiryDelta,
+		ClientTrustsLSP:    result.ClientTrustsLSP,
+		OpeningFeeParams:   params,
+		PaymentSize:        paymentSize,
+	}
+
+	c.mu.Lock()
+	c.jitChannels[scid] = jitChannel
+	c.updateAcceptor()
+	c.mu.Unlock()
+
+	log.Infof("Bought JIT channel %v from LSP %v", scid, lsp)
+
+	return jitChannel, nil
+}
+
+// JITChannels returns the just-in-time channels we bought and still expect the
+// LSPs to open.
+func (c *Client) JITChannels() []*JITChannel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneJITChannels()
+	c.updateAcceptor()
+
+	channels := make([]*JITChannel, 0, len(c.jitChannels))
+	for _, jitChannel := range c.jitChannels {
+		channels = append(channels, jitChannel)
+	}
+
+	return channels
+}
+
+// Accept accepts zero-conf channels from LSPs we bought a just-in-time channel
+// from. Other channels are accepted without an opinion, so that the other
+// acceptors decide about them, and zero-conf channels from other peers are
+// still rejected unless another acceptor allows them.
+//
+// NOTE: This is part of the chanacceptor.ChannelAcceptor interface.
+func (c *Client) Accept(
+	req *chanacceptor.ChannelAcceptRequest) *chanacceptor.ChannelAcceptResponse { //nolint:lll
+
+	noOpinion := chanacceptor.NewChannelAcceptResponse(
+		true, nil, nil, 0, 0, 0, 0, 0, 0, false,
+	)
+
+	var zeroConf bool
+	if chanType := req.OpenChanMsg.ChannelType; chanType != nil {
+		chanFeatures := lnwire.RawFeatureVector(*chanType)
+		zeroConf = chanFeatures.IsSet(lnwire.ZeroConfRequired)
+	}
+	if !zeroConf {
+		return noOpinion
+	}
+
+	lsp := route.NewVertex(req.Node)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneJITChannels()
+
+	for scid, jitChannel := range c.jitChannels {
+		if jitChannel.LSP != lsp {
+			continue
+		}
+
+		log.Infof("Accepting zero-conf channel %x from LSP %v for "+
+			"JIT channel %v", req.OpenChanMsg.PendingChannelID,
+			lsp, scid)
+
+		// Our acceptor stays added until the next update, as it
+		// can't be removed while the channel acceptor is calling it.
+		delete(c.jitChannels, scid)
+
+		return chanacceptor.NewChannelAcceptResponse(
+			true, nil, nil, 0, 0, 0, 0, 0, 0, true,
+		)
+	}
+
+	return noOpinion
+}
+
+// pruneJITChannels removes the just-in-time channels whose opening fee params
+// expired, as the LSP no longer opens them.
+//
+// NOTE: The mutex must be held when calling this method.
+func (c *Client) pruneJITChannels() {
+	now := c.cfg.Clock.Now()
+	for scid, jitChannel := range c.jitChannels {
+		if now.Before(jitChannel.OpeningFeeParams.ValidUntil) {
+			continue
+		}
+
+		log.Debugf("JIT channel %v from LSP %v expired", scid,
+			jitChannel.LSP)
+
+		delete(c.jitChannels, scid)
+	}
+}
+
+// updateAcceptor adds our acceptor to the channel acceptor while we expect
+// just-in-time channels, and removes it once we don't.
+//
+// NOTE: The mutex must be held when calling this method.
+func (c *Client) updateAcceptor() {
+	if c.cfg.Acceptor == nil {
+		return
+	}
+
+	switch {
+	case len(c.jitChannels) > 0 && !c.acceptorActive:
+		c.acceptorID = c.cfg.Acceptor.AddAcceptor(c)
+		c.acceptorActive = true
+
+	case len(c.jitChannels) == 0:
+		c.removeAcceptor()
+	}
+}
+
+// removeAcceptor removes our acceptor from the channel acceptor, if it's
+// added.
+//
+// NOTE: The mutex must be held when calling this method.
+func (c *Client) removeAcceptor() {
+	if c.cfg.Acceptor == nil || !c.acceptorActive {
+		return
+	}
+
+	c.cfg.Acceptor.RemoveAcceptor(c.acceptorID)
+	c.acceptorActive = false
+}
+
+// parseSCID parses a short channel id in the BxTxO format the LSP
+// specifications use.
+func parseSCID(s string) (lnwire.ShortChannelID, error) {
+	parts := strings.Split(s, "x")
+	if len(parts) != 3 {
+		return lnwire.ShortChannelID{}, fmt.Errorf("expected "+
+			"format BxTxO, got %q", s)
+	}
+
+	blockHeight, err := strconv.ParseUint(parts[0], 10, 24)
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+	txIndex, err := strconv.ParseUint(parts[1], 10, 24)
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+	txPosition, err := strconv.ParseUint(parts[2], 10, 16)
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+
+	return lnwire.ShortChannelID{
+		BlockHeight: uint32(blockHeight),
+		TxIndex:     uint32(txIndex),
+		TxPosition:  uint16(txPosition),
+	}, nil
+}
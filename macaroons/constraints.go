@@ -3,6 +3,8 @@ package macaroons
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -21,6 +23,47 @@ const (
 	// in the serialized macaroon. We choose a single space as the delimiter
 	// between the because that is also used by the macaroon bakery library.
 	CondLndCustom = "lnd-custom"
+
+	// CondAccount is the first party caveat condition name that binds a
+	// macaroon to a single wallet account. The caveat is encoded as the
+	// string "account <account-name>" in the serialized macaroon.
+	CondAccount = "account"
+)
+
+var (
+	// ErrAccountMismatch is returned if a request made with a macaroon
+	// that is bound to an account names a different account.
+	ErrAccountMismatch = errors.New("macaroon is bound to a different " +
+		"account")
+
+	// accountAwareMethods are the RPC methods that restrict their effects
+	// to the account a macaroon is bound to. Macaroons that are bound to
+	// an account can't be used for any other method, as it would act on
+	// the funds of the whole wallet.
+	accountAwareMethods = map[string]struct{}{
+		"/lnrpc.Lightning/WalletBalance":          {},
+		"/lnrpc.Lightning/SubscribeWalletBalance": {},
+		"/lnrpc.Lightning/ListUnspent":            {},
+		"/lnrpc.Lightning/NewAddress":             {},
+		"/lnrpc.Lightning/AddInvoice":             {},
+		"/lnrpc.Lightning/LookupInvoice":          {},
+		"/lnrpc.Lightning/ListInvoices":           {},
+		"/lnrpc.Lightning/SubscribeInvoices":      {},
+		"/lnrpc.Lightning/SendPayment":            {},
+		"/lnrpc.Lightning/SendPaymentSync":        {},
+		"/lnrpc.Lightning/SendToRoute":            {},
+		"/lnrpc.Lightning/SendToRouteSync":        {},
+		"/lnrpc.Lightning/ListPayments":           {},
+		"/routerrpc.Router/SendPaymentV2":         {},
+		"/routerrpc.Router/SendToRouteV2":         {},
+		"/routerrpc.Router/SendTrampolinePayment": {},
+		"/walletrpc.WalletKit/ListUnspent":        {},
+		"/walletrpc.WalletKit/NextAddr":           {},
+		"/walletrpc.WalletKit/ListAccounts":       {},
+		"/walletrpc.WalletKit/ListAddresses":      {},
+		"/walletrpc.WalletKit/FundPsbt":           {},
+		"/walletrpc.WalletKit/FinalizePsbt":       {},
+	}
 )
 
 // CustomCaveatAcceptor is an interface that contains a single method for
@@ -122,6 +165,109 @@ func IPLockChecker() (string, checkers.Func) {
 	}
 }
 
+// AccountConstraint binds a macaroon to the wallet account with the given
+// name. Requests made with the macaroon can only use this account, and only
+// see the invoices and payments that were attributed to it.
+func AccountConstraint(account string) func(*macaroon.Macaroon) error {
+	return func(mac *macaroon.Macaroon) error {
+		if account == "" {
+			return fmt.Errorf("account cannot be empty")
+		}
+
+		caveat := checkers.Condition(CondAccount, account)
+		return mac.AddFirstPartyCaveat([]byte(caveat))
+	}
+}
+
+// AccountChecker accepts a macaroon that is bound to a non-empty account only
+// for the RPC methods that are account aware. The account itself is enforced
+// by these methods, which fetch it with AccountFromContext. It is of the
+// `Checker` type.
+func AccountChecker() (string, checkers.Func) {
+	return CondAccount, func(ctx context.Context, _, arg string) error {
+		if arg == "" {
+			return fmt.Errorf("expected account, got empty string")
+		}
+
+		method := MethodFromContext(ctx)
+		if _, ok := accountAwareMethods[method]; !ok {
+			return fmt.Errorf("macaroon is bound to account %v "+
+				"and can't be used for %v", arg, method)
+		}
+
+		return nil
+	}
+}
+
+// AccountFromMacaroon returns the account the given macaroon is bound to, or
+// an empty string if it isn't bound to an account. A macaroon that is bound
+// to more than one account is rejected.
+func AccountFromMacaroon(mac *macaroon.Macaroon) (string, error) {
+	if mac == nil {
+		return "", nil
+	}
+
+	var account string
+	caveatPrefix := []byte(CondAccount + " ")
+	for _, caveat := range mac.Caveats() {
+		if !bytes.HasPrefix(caveat.Id, caveatPrefix) {
+			continue
+		}
+
+		name := string(caveat.Id[len(caveatPrefix):])
+		if account != "" && account != name {
+			return "", fmt.Errorf("macaroon is bound to multiple " +
+				"accounts")
+		}
+		account = name
+	}
+
+	return account, nil
+}
+
+// AccountFromContext returns the account the macaroon of the incoming gRPC
+// request is bound to, or an empty string if the request has no macaroon or
+// it isn't bound to an account.
+func AccountFromContext(ctx context.Context) (string, error) {
+	macHex, err := RawMacaroonFromContext(ctx)
+	if err != nil {
+		return "", nil
+	}
+
+	macBytes, err := hex.DecodeString(macHex)
+	if err != nil {
+		return "", err
+	}
+
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return "", err
+	}
+
+	return AccountFromMacaroon(mac)
+}
+
+// EnforceAccount returns the account a request is allowed to use. If the
+// macaroon of the request isn't bound to an account, the requested account
+// is returned unchanged. Otherwise the bound account is returned, and
+// ErrAccountMismatch if a different account was requested.
+func EnforceAccount(ctx context.Context, requested string) (string, error) {
+	account, err := AccountFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case account == "":
+		return requested, nil
+
+	case requested != "" && requested != account:
+		return "", ErrAccountMismatch
+	}
+
+	return account, nil
+}
+
 // CustomConstraint returns a function that adds a custom caveat condition to
 // a macaroon.
 func CustomConstraint(name, condition string) func(*macaroon.Macaroon) error {
@@ -1,6 +1,8 @@
 package macaroons_test
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"testing"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
 	macaroon "gopkg.in/macaroon.v2"
 )
 
@@ -157,3 +160,67 @@ func TestCustomConstraint(t *testing.T) {
 	)
 	require.Equal(t, customCaveatCondition, "")
 }
+
+// TestAccountConstraint tests that a macaroon can be bound to an account and
+// that the account is enforced for requests made with it.
+func TestAccountConstraint(t *testing.T) {
+	t.Parallel()
+
+	require.Error(t, macaroons.AccountConstraint("")(
+		createDummyMacaroon(t),
+	))
+
+	// A macaroon without the caveat isn't bound to an account.
+	testMacaroon := createDummyMacaroon(t)
+	account, err := macaroons.AccountFromMacaroon(testMacaroon)
+	require.NoError(t, err)
+	require.Empty(t, account)
+
+	ctx := contextWithMacaroon(t, testMacaroon)
+	account, err = macaroons.EnforceAccount(ctx, "other")
+	require.NoError(t, err)
+	require.Equal(t, "other", account)
+
+	// Once the caveat is added, only the bound account can be used.
+	constraintFunc := macaroons.AccountConstraint("alice")
+	require.NoError(t, constraintFunc(testMacaroon))
+	require.Equal(
+		t, []byte("account alice"), testMacaroon.Caveats()[0].Id,
+	)
+
+	ctx = contextWithMacaroon(t, testMacaroon)
+	account, err = macaroons.EnforceAccount(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, "alice", account)
+
+	account, err = macaroons.EnforceAccount(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "alice", account)
+
+	_, err = macaroons.EnforceAccount(ctx, "bob")
+	require.ErrorIs(t, err, macaroons.ErrAccountMismatch)
+
+	// Adding the same account again is fine, a different one isn't.
+	require.NoError(t, constraintFunc(testMacaroon))
+	_, err = macaroons.AccountFromMacaroon(testMacaroon)
+	require.NoError(t, err)
+
+	require.NoError(t, macaroons.AccountConstraint("bob")(testMacaroon))
+	_, err = macaroons.AccountFromMacaroon(testMacaroon)
+	require.Error(t, err)
+}
+
+// contextWithMacaroon returns an incoming gRPC context that carries the given
+// macaroon.
+func contextWithMacaroon(t *testing.T,
+	mac *macaroon.Macaroon) context.Context {
+
+	macBytes, err := mac.MarshalBinary()
+	require.NoError(t, err)
+
+	md := metadata.New(map[string]string{
+		"macaroon": hex.EncodeToString(macBytes),
+	})
+
+	return metadata.NewIncomingContext(context.Background(), md)
+}
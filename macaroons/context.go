@@ -9,6 +9,10 @@ var (
 	// RootKeyIDContextKey is the key to get rootKeyID from context.
 	RootKeyIDContextKey = contextKey{"rootkeyid"}
 
+	// MethodContextKey is the key to get the full URI of the called RPC
+	// method from the context of a macaroon check.
+	MethodContextKey = contextKey{"method"}
+
 	// ErrContextRootKeyID is used when the supplied context doesn't have
 	// a root key ID.
 	ErrContextRootKeyID = fmt.Errorf("failed to read root key ID " +
@@ -42,3 +46,19 @@ func RootKeyIDFromContext(ctx context.Context) ([]byte, error) {
 
 	return id, nil
 }
+
+// ContextWithMethod passes the full URI of the called RPC method to context,
+// so that caveat checkers can restrict a macaroon to certain methods.
+func ContextWithMethod(ctx context.Context,
+	fullMethod string) context.Context {
+
+	return context.WithValue(ctx, MethodContextKey, fullMethod)
+}
+
+// MethodFromContext retrieves the full URI of the called RPC method from
+// context using the key MethodContextKey. An empty string is returned if the
+// context doesn't carry a method.
+func MethodFromContext(ctx context.Context) string {
+	method, _ := ctx.Value(MethodContextKey).(string)
+	return method
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 	"gopkg.in/macaroon-bakery.v2/bakery/checkers"
 	macaroon "gopkg.in/macaroon.v2"
@@ -233,7 +234,8 @@ func (svc *Service) CheckMacAuth(ctx context.Context, macBytes []byte,
 	}
 
 	// Check the method being called against the permitted operation, the
-	// expiration time and IP address and return the result.
+	// expiration time, IP address and account and return the result.
+	ctx = ContextWithMethod(ctx, fullMethod)
 	authChecker := svc.Checker.Auth(macaroon.Slice{mac})
 	_, err = authChecker.Allow(ctx, requiredPermissions...)
 
@@ -365,6 +367,64 @@ func RawMacaroonFromContext(ctx context.Context) (string, error) {
 	return md["macaroon"][0], nil
 }
 
+// RootKeyIDFromMacaroon returns the ID of the root key the given macaroon was
+// baked with.
+func RootKeyIDFromMacaroon(mac *macaroon.Macaroon) ([]byte, error) {
+	id := mac.Id()
+	if len(id) < 1 || id[0] != byte(bakery.Version3) {
+		return nil, ErrInvalidID
+	}
+
+	// The rest of the ID is the protobuf encoded MacaroonId message, whose
+	// storage ID field holds the root key ID.
+	const storageIDField = 2
+	for b := id[1:]; len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, ErrInvalidID
+		}
+		b = b[n:]
+
+		if num == storageIDField && typ == protowire.BytesType {
+			rootKeyID, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, ErrInvalidID
+			}
+
+			return rootKeyID, nil
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, ErrInvalidID
+		}
+		b = b[n:]
+	}
+
+	return nil, ErrMissingRootKeyID
+}
+
+// RootKeyIDFromRequest returns the root key ID of the macaroon of the incoming
+// gRPC request, or nil if the request has no macaroon.
+func RootKeyIDFromRequest(ctx context.Context) ([]byte, error) {
+	macHex, err := RawMacaroonFromContext(ctx)
+	if err != nil {
+		return nil, nil
+	}
+
+	macBytes, err := hex.DecodeString(macHex)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return nil, err
+	}
+
+	return RootKeyIDFromMacaroon(mac)
+}
+
 // SafeCopyMacaroon creates a copy of a macaroon that is safe to be used and
 // modified. This is necessary because the macaroon library's own Clone() method
 // is unsafe for certain edge cases, resulting in both the cloned and the
@@ -152,6 +152,80 @@ func TestValidateMacaroon(t *testing.T) {
 	require.NoError(t, err, "Error validating the macaroon")
 }
 
+// TestValidateAccountMacaroon tests that a macaroon that is bound to an
+// account is only accepted for the RPC methods that are account aware.
+func TestValidateAccountMacaroon(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestRootKeyStorage(t)
+	rootKeyStore, err := macaroons.NewRootKeyStorage(db)
+	require.NoError(t, err)
+	service, err := macaroons.NewService(
+		rootKeyStore, "lnd", false, macaroons.AccountChecker,
+	)
+	require.NoError(t, err, "Error creating new service")
+	defer service.Close()
+
+	err = service.CreateUnlock(&defaultPw)
+	require.NoError(t, err, "Error unlocking root key storage")
+
+	mac, err := service.NewMacaroon(
+		context.TODO(), macaroons.DefaultRootKeyID, testOperation,
+	)
+	require.NoError(t, err, "Error creating macaroon from service")
+
+	accountMac, err := macaroons.AddConstraints(
+		mac.M(), macaroons.AccountConstraint("alice"),
+	)
+	require.NoError(t, err)
+	ctx := contextWithMacaroon(t, accountMac)
+
+	testCases := []struct {
+		method  string
+		allowed bool
+	}{
+		{method: "/lnrpc.Lightning/WalletBalance", allowed: true},
+		{
+			method:  "/lnrpc.Lightning/SubscribeWalletBalance",
+			allowed: true,
+		},
+		{method: "/lnrpc.Lightning/AddInvoice", allowed: true},
+		{method: "/routerrpc.Router/SendPaymentV2", allowed: true},
+		{method: "/walletrpc.WalletKit/FundPsbt", allowed: true},
+
+		// These methods act on the funds of the whole wallet.
+		{method: "/lnrpc.Lightning/OpenChannel"},
+		{method: "/lnrpc.Lightning/BatchOpenChannel"},
+		{method: "/lnrpc.Lightning/CloseChannel"},
+		{method: "/lnrpc.Lightning/GetTransactions"},
+		{method: "/walletrpc.WalletKit/SendOutputs"},
+		{method: "/walletrpc.WalletKit/BumpFee"},
+		{method: "/walletrpc.WalletKit/LeaseOutput"},
+		{method: "/walletrpc.WalletKit/PublishTransaction"},
+	}
+
+	for _, tc := range testCases {
+		err := service.ValidateMacaroon(
+			ctx, []bakery.Op{testOperation}, tc.method,
+		)
+		if tc.allowed {
+			require.NoError(t, err, tc.method)
+		} else {
+			require.ErrorContains(
+				t, err, "bound to account", tc.method,
+			)
+		}
+
+		// Without the account caveat, the macaroon is accepted for
+		// every method.
+		err = service.ValidateMacaroon(
+			contextWithMacaroon(t, mac.M()),
+			[]bakery.Op{testOperation}, tc.method,
+		)
+		require.NoError(t, err, tc.method)
+	}
+}
+
 // TestListMacaroonIDs checks that ListMacaroonIDs returns the expected result.
 func TestListMacaroonIDs(t *testing.T) {
 	t.Parallel()
@@ -185,6 +259,45 @@ func TestListMacaroonIDs(t *testing.T) {
 	require.Equal(t, expectedIDs, ids, "root key IDs mismatch")
 }
 
+// TestRootKeyIDFromRequest tests that the root key ID a macaroon was baked
+// with is extracted from an incoming context.
+func TestRootKeyIDFromRequest(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestRootKeyStorage(t)
+	rootKeyStore, err := macaroons.NewRootKeyStorage(db)
+	require.NoError(t, err)
+	service, err := macaroons.NewService(
+		rootKeyStore, "lnd", false, macaroons.IPLockChecker,
+	)
+	require.NoError(t, err, "Error creating new service")
+	defer service.Close()
+
+	err = service.CreateUnlock(&defaultPw)
+	require.NoError(t, err, "Error unlocking root key storage")
+
+	// Without a macaroon, there's no root key ID.
+	rootKeyID, err := macaroons.RootKeyIDFromRequest(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, rootKeyID)
+
+	mac, err := service.NewMacaroon(
+		context.TODO(), []byte("1234"), testOperation,
+	)
+	require.NoError(t, err, "Error creating macaroon from service")
+	macBinary, err := mac.M().MarshalBinary()
+	require.NoError(t, err, "Error serializing macaroon")
+
+	md := metadata.New(map[string]string{
+		"macaroon": hex.EncodeToString(macBinary),
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	rootKeyID, err = macaroons.RootKeyIDFromRequest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1234"), rootKeyID)
+}
+
 // TestDeleteMacaroonID removes the specific root key ID.
 func TestDeleteMacaroonID(t *testing.T) {
 	t.Parallel()
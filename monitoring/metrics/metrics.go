@@ -0,0 +1,75 @@
+// Package metrics holds the Prometheus metrics of the lnd subsystems. The
+// metrics are only collected if lnd is built with the monitoring tag,
+// otherwise all functions of this package are no-ops. Subsystems can
+// therefore record metrics unconditionally.
+package metrics
+
+const (
+	// namespace is the namespace of all lnd metrics.
+	namespace = "lnd"
+
+	// outcomeLabel is the label used by all metrics that distinguish
+	// between successful and failed operations.
+	outcomeLabel = "outcome"
+
+	// queueLabel is the label used by the mailbox and peer send queue
+	// metrics to distinguish between their queues.
+	queueLabel = "queue"
+
+	// kindLabel is the label used by the liquidity alert metrics to
+	// distinguish between the kinds of alerts.
+	kindLabel = "kind"
+
+	// reasonLabel is the label used by the suppressed channel update
+	// metric to distinguish between the reasons an update was suppressed.
+	reasonLabel = "reason"
+)
+
+const (
+	// OutcomeSuccess is the outcome of a successful operation.
+	OutcomeSuccess = "success"
+
+	// OutcomeFailure is the outcome of a failed operation.
+	OutcomeFailure = "failure"
+)
+
+const (
+	// QueueAdd is the mailbox queue holding add packets.
+	QueueAdd = "add"
+
+	// QueueSettleFail is the mailbox queue holding settle and fail
+	// packets.
+	QueueSettleFail = "settle_fail"
+
+	// QueuePriority is the peer send queue holding high-priority
+	// messages, such as channel updates and HTLC messages.
+	QueuePriority = "priority"
+
+	// QueueLazy is the peer send queue holding low-priority messages,
+	// such as gossip messages.
+	QueueLazy = "lazy"
+)
+
+const (
+	// SuppressedRateLimited is the reason of remote channel updates that
+	// were dropped because they exceeded the accepted rate.
+	SuppressedRateLimited = "rate_limited"
+
+	// SuppressedThrottled is the reason of our own channel updates that
+	// were held back because they exceeded the local update rate.
+	SuppressedThrottled = "throttled"
+
+	// SuppressedSuperseded is the reason of held back channel updates that
+	// were replaced by a newer update before they were broadcast.
+	SuppressedSuperseded = "superseded"
+)
+
+// Outcome returns the outcome label value of an operation that returned the
+// given error.
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeFailure
+	}
+
+	return OutcomeSuccess
+}
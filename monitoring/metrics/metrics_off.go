@@ -0,0 +1,59 @@
+//go:build !monitoring
+// +build !monitoring
+
+package metrics
+
+import "time"
+
+// ObserveForward records the duration of an HTLC forward with the given
+// outcome. Monitoring is currently disabled.
+func ObserveForward(string, time.Duration) {}
+
+// ObserveMailboxDepth records the depth of a mailbox queue. Monitoring is
+// currently disabled.
+func ObserveMailboxDepth(string, int) {}
+
+// ObservePathfinding records the duration of a path finding attempt with the
+// given outcome. Monitoring is currently disabled.
+func ObservePathfinding(string, time.Duration) {}
+
+// IncPeerReconnects records a reconnection attempt to a persistent peer.
+// Monitoring is currently disabled.
+func IncPeerReconnects() {}
+
+// ObservePeerSendQueueDepth records the depth of a peer send queue. Monitoring
+// is currently disabled.
+func ObservePeerSendQueueDepth(string, int) {}
+
+// ObservePeerCoalescedMsgs records the number of messages flushed to a peer
+// in a single write. Monitoring is currently disabled.
+func ObservePeerCoalescedMsgs(int) {}
+
+// IncSweepBroadcasts records the broadcast of a sweep transaction with the
+// given outcome. Monitoring is currently disabled.
+func IncSweepBroadcasts(string) {}
+
+// SetAnchorReserve records the outcome of an anchor reserve check. Monitoring
+// is currently disabled.
+func SetAnchorReserve(int64, int64, int64) {}
+
+// IncAnchorReserveAlerts records an alert about an insufficient anchor
+// reserve. Monitoring is currently disabled.
+func IncAnchorReserveAlerts() {}
+
+// SetLiquidityActiveAlerts records the number of active liquidity alerts of
+// the given kind. Monitoring is currently disabled.
+func SetLiquidityActiveAlerts(string, int) {}
+
+// IncLiquidityAlerts records a raised liquidity alert of the given kind.
+// Monitoring is currently disabled.
+func IncLiquidityAlerts(string) {}
+
+// IncSuppressedChannelUpdates records a channel update that was suppressed for
+// the given reason. Monitoring is currently disabled.
+func IncSuppressedChannelUpdates(string) {}
+
+// IncUrgentChannelUpdates records one of our own channel updates that was
+// broadcast right away because it disabled or enabled a channel. Monitoring is
+// currently disabled.
+func IncUrgentChannelUpdates() {}
@@ -0,0 +1,277 @@
+//go:build monitoring
+// +build monitoring
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// forwardDuration is the time between the switch receiving an HTLC
+	// to forward and the settle or fail of that HTLC.
+	forwardDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "htlcswitch",
+			Name:      "forward_duration_seconds",
+			Help: "Time from receiving an HTLC to forward until " +
+				"it was settled or failed.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+		}, []string{outcomeLabel},
+	)
+
+	// mailboxDepth is the number of packets pending in a mailbox queue
+	// after a new packet was added.
+	mailboxDepth = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "htlcswitch",
+			Name:      "mailbox_depth",
+			Help: "Number of packets pending in a link mailbox " +
+				"queue when a new packet is added.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{queueLabel},
+	)
+
+	// pathfindingDuration is the time spent finding a path for a
+	// payment attempt.
+	pathfindingDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "routing",
+			Name:      "pathfinding_duration_seconds",
+			Help:      "Time spent finding a path for a payment.",
+			Buckets: prometheus.ExponentialBuckets(
+				0.001, 2, 15,
+			),
+		}, []string{outcomeLabel},
+	)
+
+	// peerReconnects is the number of reconnection attempts to
+	// persistent peers.
+	peerReconnects = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "peer",
+			Name:      "reconnects_total",
+			Help: "Number of reconnection attempts to persistent " +
+				"peers.",
+		},
+	)
+
+	// peerSendQueueDepth is the number of messages pending in a peer send
+	// queue after a new message was added.
+	peerSendQueueDepth = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "peer",
+			Name:      "send_queue_depth",
+			Help: "Number of messages pending in a peer send " +
+				"queue when a new message is added.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{queueLabel},
+	)
+
+	// peerCoalescedMsgs is the number of messages flushed to a peer
+	// together.
+	peerCoalescedMsgs = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "peer",
+			Name:      "coalesced_messages",
+			Help: "Number of messages flushed to a peer in a " +
+				"single write.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 6),
+		},
+	)
+
+	// sweepBroadcasts is the number of sweep transactions broadcast.
+	sweepBroadcasts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "sweep",
+			Name:      "broadcasts_total",
+			Help:      "Number of sweep transactions broadcast.",
+		}, []string{outcomeLabel},
+	)
+
+	// anchorReserveRequired is the value the wallet policy keeps in the
+	// wallet for the anchor channels.
+	anchorReserveRequired = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "anchorreserve",
+			Name:      "required_sat",
+			Help: "Value the wallet keeps in reserve for the " +
+				"anchor channels.",
+		},
+	)
+
+	// anchorReserveFeeBumpCost is the fee needed to get the most
+	// expensive anchor channel commitment confirmed.
+	anchorReserveFeeBumpCost = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "anchorreserve",
+			Name:      "fee_bump_cost_sat",
+			Help: "Fee needed to get the most expensive anchor " +
+				"channel commitment confirmed.",
+		},
+	)
+
+	// anchorReserveBalance is the balance available for fee bumps.
+	anchorReserveBalance = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "anchorreserve",
+			Name:      "balance_sat",
+			Help:      "Wallet balance available for fee bumps.",
+		},
+	)
+
+	// anchorReserveAlerts is the number of alerts about an insufficient
+	// anchor reserve.
+	anchorReserveAlerts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "anchorreserve",
+			Name:      "alerts_total",
+			Help: "Number of times the wallet was found unable to " +
+				"cover a force close fee bump.",
+		},
+	)
+
+	// liquidityActiveAlerts is the number of active liquidity alerts.
+	liquidityActiveAlerts = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "liquidity",
+			Name:      "active_alerts",
+			Help:      "Number of active liquidity alerts.",
+		}, []string{kindLabel},
+	)
+
+	// liquidityAlerts is the number of raised liquidity alerts.
+	liquidityAlerts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "liquidity",
+			Name:      "alerts_total",
+			Help:      "Number of raised liquidity alerts.",
+		}, []string{kindLabel},
+	)
+
+	// suppressedChannelUpdates is the number of suppressed channel
+	// updates.
+	suppressedChannelUpdates = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "gossip",
+			Name:      "suppressed_channel_updates_total",
+			Help: "Number of channel updates that were dropped " +
+				"or held back because of rate limits.",
+		}, []string{reasonLabel},
+	)
+
+	// urgentChannelUpdates is the number of our own channel updates that
+	// bypassed the local update rate.
+	urgentChannelUpdates = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "gossip",
+			Name:      "urgent_channel_updates_total",
+			Help: "Number of our own channel updates that were " +
+				"broadcast right away because they disabled " +
+				"or enabled a channel.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		forwardDuration, mailboxDepth, pathfindingDuration,
+		peerReconnects, peerSendQueueDepth, peerCoalescedMsgs,
+		sweepBroadcasts, anchorReserveRequired,
+		anchorReserveFeeBumpCost, anchorReserveBalance,
+		anchorReserveAlerts, liquidityActiveAlerts, liquidityAlerts,
+		suppressedChannelUpdates, urgentChannelUpdates,
+	)
+}
+
+// ObserveForward records the duration of an HTLC forward with the given
+// outcome.
+func ObserveForward(outcome string, d time.Duration) {
+	forwardDuration.WithLabelValues(outcome).Observe(d.Seconds())
+}
+
+// ObserveMailboxDepth records the depth of a mailbox queue.
+func ObserveMailboxDepth(queue string, depth int) {
+	mailboxDepth.WithLabelValues(queue).Observe(float64(depth))
+}
+
+// ObservePathfinding records the duration of a path finding attempt with the
+// given outcome.
+func ObservePathfinding(outcome string, d time.Duration) {
+	pathfindingDuration.WithLabelValues(outcome).Observe(d.Seconds())
+}
+
+// IncPeerReconnects records a reconnection attempt to a persistent peer.
+func IncPeerReconnects() {
+	peerReconnects.Inc()
+}
+
+// ObservePeerSendQueueDepth records the depth of a peer send queue.
+func ObservePeerSendQueueDepth(queue string, depth int) {
+	peerSendQueueDepth.WithLabelValues(queue).Observe(float64(depth))
+}
+
+// ObservePeerCoalescedMsgs records the number of messages flushed to a peer
+// in a single write.
+func ObservePeerCoalescedMsgs(num int) {
+	peerCoalescedMsgs.Observe(float64(num))
+}
+
+// IncSweepBroadcasts records the broadcast of a sweep transaction with the
+// given outcome.
+func IncSweepBroadcasts(outcome string) {
+	sweepBroadcasts.WithLabelValues(outcome).Inc()
+}
+
+// SetAnchorReserve records the outcome of an anchor reserve check.
+func SetAnchorReserve(required, feeBumpCost, balance int64) {
+	anchorReserveRequired.Set(float64(required))
+	anchorReserveFeeBumpCost.Set(float64(feeBumpCost))
+	anchorReserveBalance.Set(float64(balance))
+}
+
+// IncAnchorReserveAlerts records an alert about an insufficient anchor
+// reserve.
+func IncAnchorReserveAlerts() {
+	anchorReserveAlerts.Inc()
+}
+
+// SetLiquidityActiveAlerts records the number of active liquidity alerts of
+// the given kind.
+func SetLiquidityActiveAlerts(kind string, num int) {
+	liquidityActiveAlerts.WithLabelValues(kind).Set(float64(num))
+}
+
+// IncLiquidityAlerts records a raised liquidity alert of the given kind.
+func IncLiquidityAlerts(kind string) {
+	liquidityAlerts.WithLabelValues(kind).Inc()
+}
+
+// IncSuppressedChannelUpdates records a channel update that was suppressed for
+// the given reason.
+func IncSuppressedChannelUpdates(reason string) {
+	suppressedChannelUpdates.WithLabelValues(reason).Inc()
+}
+
+// IncUrgentChannelUpdates records one of our own channel updates that was
+// broadcast right away because it disabled or enabled a channel.
+func IncUrgentChannelUpdates() {
+	urgentChannelUpdates.Inc()
+}
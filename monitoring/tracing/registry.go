@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// htlcSpans maps the payment hash of locally initiated HTLCs to the span of
+// the payment attempt they belong to. Subsystems that only see the HTLC, like
+// the peer writing it to the wire, use it to attach their spans to the
+// payment's trace.
+var htlcSpans = struct {
+	sync.Mutex
+	spans map[lntypes.Hash]trace.SpanContext
+}{
+	spans: make(map[lntypes.Hash]trace.SpanContext),
+}
+
+// TrackHTLC associates the HTLC with the given payment hash with the span in
+// the context until UntrackHTLC is called. Contexts without a sampled span are
+// ignored.
+func TrackHTLC(ctx context.Context, hash lntypes.Hash) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsSampled() {
+		return
+	}
+
+	htlcSpans.Lock()
+	htlcSpans.spans[hash] = spanCtx
+	htlcSpans.Unlock()
+}
+
+// UntrackHTLC removes the association of the given payment hash with a span.
+func UntrackHTLC(hash lntypes.Hash) {
+	htlcSpans.Lock()
+	delete(htlcSpans.spans, hash)
+	htlcSpans.Unlock()
+}
+
+// HTLCContext returns a context carrying the span the HTLC with the given
+// payment hash is tracked with. The boolean is false if the HTLC isn't
+// tracked.
+func HTLCContext(hash lntypes.Hash) (context.Context, bool) {
+	htlcSpans.Lock()
+	spanCtx, ok := htlcSpans.spans[hash]
+	htlcSpans.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return trace.ContextWithSpanContext(context.Background(), spanCtx), true
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestTrackHTLC checks that tracked HTLCs resolve to the span they were
+// tracked with, and that HTLCs without a sampled span aren't tracked.
+func TestTrackHTLC(t *testing.T) {
+	t.Parallel()
+
+	hash := lntypes.Hash{1}
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{2},
+		SpanID:     trace.SpanID{3},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	// A context without a span isn't tracked.
+	TrackHTLC(context.Background(), hash)
+	_, ok := HTLCContext(hash)
+	require.False(t, ok)
+
+	TrackHTLC(ctx, hash)
+	htlcCtx, ok := HTLCContext(hash)
+	require.True(t, ok)
+	require.Equal(t, spanCtx, trace.SpanContextFromContext(htlcCtx))
+	require.Equal(t, spanCtx.TraceID().String(), TraceID(htlcCtx))
+
+	UntrackHTLC(hash)
+	_, ok = HTLCContext(hash)
+	require.False(t, ok)
+}
@@ -0,0 +1,130 @@
+// Package tracing exports OpenTelemetry traces of RPC calls and of the stages
+// of the payment pipeline. Until Start is called, the global tracer provider
+// is a no-op, so spans can be created unconditionally.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+const (
+	// instrumentationName is the name of the tracer used for all spans
+	// created by lnd.
+	instrumentationName = "github.com/lightningnetwork/lnd"
+
+	// serviceName is the service name traces are exported under.
+	serviceName = "lnd"
+)
+
+// Config describes the OTLP collector traces are exported to.
+type Config struct {
+	// Endpoint is the host:port of the OTLP gRPC collector.
+	Endpoint string
+
+	// Insecure disables TLS for the connection to the collector.
+	Insecure bool
+
+	// SampleRatio is the fraction of traces that are sampled.
+	SampleRatio float64
+}
+
+// Start sets up the OTLP exporter described by the given config and installs
+// it as the global tracer provider. The returned function flushes all pending
+// spans and shuts the exporter down.
+func Start(ctx context.Context, cfg *Config) (func(context.Context) error,
+	error) {
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(cfg.SampleRatio),
+		)),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL, semconv.ServiceNameKey.String(
+				serviceName,
+			),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	log.Infof("Exporting traces to %v (sample ratio %v)", cfg.Endpoint,
+		cfg.SampleRatio)
+
+	return provider.Shutdown, nil
+}
+
+// GetInterceptors returns the gRPC interceptors that start a span for every
+// RPC call. Spans started further down the call inherit the trace of the RPC.
+func GetInterceptors() (grpc.UnaryServerInterceptor,
+	grpc.StreamServerInterceptor) {
+
+	return otelgrpc.UnaryServerInterceptor(),
+		otelgrpc.StreamServerInterceptor()
+}
+
+// StartSpan starts a new span with the given name as a child of the span
+// found in the context, if any.
+func StartSpan(ctx context.Context, name string,
+	attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+
+	return otel.Tracer(instrumentationName).Start(
+		ctx, name, trace.WithAttributes(attrs...),
+	)
+}
+
+// EndSpan records the given error, if any, on the span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// TraceID returns the ID of the trace the span in the context belongs to, or
+// an empty string if the context doesn't carry a sampled span.
+func TraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsSampled() {
+		return ""
+	}
+
+	return spanCtx.TraceID().String()
+}
+
+// Detach returns a background context that carries the span of the given
+// context. This allows work that outlives a call, such as a payment that
+// isn't canceled with its RPC stream, to be attributed to the call's trace.
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(
+		context.Background(), trace.SpanContextFromContext(ctx),
+	)
+}
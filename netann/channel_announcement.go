@@ -2,9 +2,11 @@ package netann
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 // CreateChanAnnouncement is a helper function which creates all channel
@@ -84,3 +86,87 @@ func CreateChanAnnouncement(chanProof *models.ChannelAuthProof,
 
 	return chanAnn, edge1Ann, edge2Ann, nil
 }
+
+// CreateChanAnnouncement2 is a helper function which re-creates the
+// ChannelAnnouncement2 of a taproot channel along with its channel updates
+// from the database structs of the channel. The proof must hold the Schnorr
+// signature of the announcement.
+func CreateChanAnnouncement2(chanProof *models.ChannelAuthProof,
+	chanInfo *models.ChannelEdgeInfo,
+	e1, e2 *models.ChannelEdgePolicy) (*lnwire.ChannelAnnouncement2,
+	*lnwire.ChannelUpdate, *lnwire.ChannelUpdate, error) {
+
+	if !chanProof.IsTaproot() {
+		return nil, nil, nil, fmt.Errorf("proof of channel %v isn't a "+
+			"taproot proof", chanInfo.ChannelID)
+	}
+
+	chanAnn := lnwire.NewChannelAnnouncement2()
+	chanAnn.ChainHash.Val = chanInfo.ChainHash
+	chanAnn.ShortChannelID.Val = lnwire.NewShortChanIDFromInt(
+		chanInfo.ChannelID,
+	)
+	chanAnn.Capacity.Val = uint64(chanInfo.Capacity)
+	chanAnn.NodeID1.Val = chanInfo.NodeKey1Bytes
+	chanAnn.NodeID2.Val = chanInfo.NodeKey2Bytes
+	chanAnn.BitcoinKey1 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType12](
+			chanInfo.BitcoinKey1Bytes,
+		),
+	)
+	chanAnn.BitcoinKey2 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType14](
+			chanInfo.BitcoinKey2Bytes,
+		),
+	)
+	chanAnn.ExtraOpaqueData = chanInfo.ExtraOpaqueData
+
+	features := lnwire.NewRawFeatureVector()
+	err := features.Decode(bytes.NewReader(chanInfo.Features))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	chanAnn.Features.Val = *lnwire.NewFeatureVector(
+		features, lnwire.Features,
+	)
+
+	chanAnn.Signature, err = lnwire.NewSigFromSchnorrRawSignature(
+		chanProof.SchnorrSigBytes,
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The channel updates are created in the same way as for any other
+	// channel.
+	var edge1Ann, edge2Ann *lnwire.ChannelUpdate
+	if e1 != nil {
+		edge1Ann, err = ChannelUpdateFromEdge(chanInfo, e1)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if e2 != nil {
+		edge2Ann, err = ChannelUpdateFromEdge(chanInfo, e2)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return chanAnn, edge1Ann, edge2Ann, nil
+}
+
+// CreateChanAnnouncementMsg re-creates the channel announcement of the channel
+// along with its channel updates, using a ChannelAnnouncement2 if the channel
+// was announced with a taproot proof.
+func CreateChanAnnouncementMsg(chanProof *models.ChannelAuthProof,
+	chanInfo *models.ChannelEdgeInfo,
+	e1, e2 *models.ChannelEdgePolicy) (lnwire.Message,
+	*lnwire.ChannelUpdate, *lnwire.ChannelUpdate, error) {
+
+	if chanProof.IsTaproot() {
+		return CreateChanAnnouncement2(chanProof, chanInfo, e1, e2)
+	}
+
+	return CreateChanAnnouncement(chanProof, chanInfo, e1, e2)
+}
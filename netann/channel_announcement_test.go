@@ -9,6 +9,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -64,3 +65,77 @@ func TestCreateChanAnnouncement(t *testing.T) {
 
 	assert.Equal(t, chanAnn, expChanAnn)
 }
+
+// TestCreateChanAnnouncement2 tests that the ChannelAnnouncement2 of a taproot
+// channel is re-created from the database structs of the channel.
+func TestCreateChanAnnouncement2(t *testing.T) {
+	t.Parallel()
+
+	features := lnwire.NewRawFeatureVector(
+		lnwire.SimpleTaprootChannelsRequiredStaging,
+	)
+	var featuresBuf bytes.Buffer
+	require.NoError(t, features.Encode(&featuresBuf))
+
+	sigBytes := bytes.Repeat([]byte{0x2}, 64)
+	sig, err := lnwire.NewSigFromSchnorrRawSignature(sigBytes)
+	require.NoError(t, err)
+
+	expChanAnn := lnwire.NewChannelAnnouncement2()
+	expChanAnn.Signature = sig
+	expChanAnn.ChainHash.Val = chainhash.Hash{0x1}
+	expChanAnn.Features.Val = *lnwire.NewFeatureVector(
+		features, lnwire.Features,
+	)
+	expChanAnn.ShortChannelID.Val = lnwire.ShortChannelID{BlockHeight: 1}
+	expChanAnn.Capacity.Val = btcutil.SatoshiPerBitcoin
+	expChanAnn.NodeID1.Val = [33]byte{0x1}
+	expChanAnn.NodeID2.Val = [33]byte{0x2}
+	expChanAnn.BitcoinKey1 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType12]([33]byte{0x3}),
+	)
+	expChanAnn.BitcoinKey2 = tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[tlv.TlvType14]([33]byte{0x4}),
+	)
+	expChanAnn.ExtraOpaqueData = []byte{0x21, 0x1, 0x1}
+
+	chanInfo := &models.ChannelEdgeInfo{
+		ChainHash:        expChanAnn.ChainHash.Val,
+		ChannelID:        expChanAnn.ShortChannelID.Val.ToUint64(),
+		ChannelPoint:     wire.OutPoint{Index: 1},
+		Capacity:         btcutil.SatoshiPerBitcoin,
+		NodeKey1Bytes:    expChanAnn.NodeID1.Val,
+		NodeKey2Bytes:    expChanAnn.NodeID2.Val,
+		BitcoinKey1Bytes: [33]byte{0x3},
+		BitcoinKey2Bytes: [33]byte{0x4},
+		Features:         featuresBuf.Bytes(),
+		ExtraOpaqueData:  expChanAnn.ExtraOpaqueData,
+	}
+
+	// A legacy proof can't be used to create a ChannelAnnouncement2.
+	_, _, _, err = CreateChanAnnouncement2(
+		&models.ChannelAuthProof{NodeSig1Bytes: sigBytes}, chanInfo,
+		nil, nil,
+	)
+	require.Error(t, err)
+
+	chanProof := &models.ChannelAuthProof{SchnorrSigBytes: sigBytes}
+	chanAnn, _, _, err := CreateChanAnnouncement2(
+		chanProof, chanInfo, nil, nil,
+	)
+	require.NoError(t, err, "unable to create channel announcement")
+
+	// Both announcements must serialize to the same bytes, otherwise the
+	// signature wouldn't cover the re-created announcement.
+	var expBuf, buf bytes.Buffer
+	require.NoError(t, expChanAnn.Encode(&expBuf, 0))
+	require.NoError(t, chanAnn.Encode(&buf, 0))
+	require.Equal(t, expBuf.Bytes(), buf.Bytes())
+
+	// The generic helper picks the announcement type from the proof.
+	msg, _, _, err := CreateChanAnnouncementMsg(
+		chanProof, chanInfo, nil, nil,
+	)
+	require.NoError(t, err)
+	require.IsType(t, &lnwire.ChannelAnnouncement2{}, msg)
+}
@@ -79,6 +79,38 @@ func SignChannelUpdate(signer lnwallet.MessageSigner, keyLoc keychain.KeyLocator
 	return nil
 }
 
+// SignChannelUpdate2 signs the passed taproot channel update with the node key
+// described by the given key locator, and sets the resulting Schnorr signature
+// on the update.
+//
+// NOTE: This method modifies the given update.
+func SignChannelUpdate2(signer keychain.MessageSignerRing,
+	keyLoc keychain.KeyLocator, update *lnwire.ChannelUpdate2) error {
+
+	data, err := lnwire.SerialiseFieldsToSign(update)
+	if err != nil {
+		return fmt.Errorf("unable to get data to sign: %w", err)
+	}
+
+	// The signature commits to the tagged hash of the signed fields of the
+	// update, so we let the signer compute the digest using the tag.
+	tag := []byte(
+		"lightning" + lnwire.ChanUpdate2MsgName +
+			lnwire.ChanUpdate2SigFieldName,
+	)
+	sig, err := signer.SignMessageSchnorr(keyLoc, data, false, nil, tag)
+	if err != nil {
+		return err
+	}
+
+	update.Signature, err = lnwire.NewSigFromSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ExtractChannelUpdate attempts to retrieve a lnwire.ChannelUpdate message from
 // an edge's info and a set of routing policies.
 //
@@ -8,10 +8,12 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/stretchr/testify/require"
 )
 
 type mockSigner struct {
@@ -192,3 +194,28 @@ func TestUpdateDisableFlag(t *testing.T) {
 		})
 	}
 }
+
+// TestSignChannelUpdate2 asserts that a taproot channel update signed using
+// SignChannelUpdate2 passes the router's signature verification, and that any
+// change to the signed fields invalidates the signature.
+func TestSignChannelUpdate2(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &mock.SecretKeyRing{RootKey: privKey}
+
+	update := &lnwire.ChannelUpdate2{}
+	update.BlockHeight.Val = 100
+	update.CLTVExpiryDelta.Val = 40
+	update.HTLCMaximumMsat.Val = 1000
+
+	err := netann.SignChannelUpdate2(keyRing, testKeyLoc, update)
+	require.NoError(t, err)
+
+	err = routing.VerifyChannelUpdate2Signature(update, pubKey)
+	require.NoError(t, err)
+
+	// Bumping the block height must invalidate the signature.
+	update.BlockHeight.Val++
+	err = routing.VerifyChannelUpdate2Signature(update, pubKey)
+	require.Error(t, err)
+}
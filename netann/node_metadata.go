@@ -0,0 +1,172 @@
+package netann
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// NodeMetadataContactType is the odd TLV type of the operator contact
+	// record in a node announcement.
+	NodeMetadataContactType tlv.Type = 65537
+
+	// NodeMetadataOrgNameType is the odd TLV type of the organization
+	// name record in a node announcement.
+	NodeMetadataOrgNameType tlv.Type = 65539
+
+	// NodeMetadataURLType is the odd TLV type of the canonical URL record
+	// in a node announcement.
+	NodeMetadataURLType tlv.Type = 65541
+
+	// MaxNodeMetadataLen is the maximum length in bytes of a single node
+	// metadata record. It keeps the announcement well below the maximum
+	// size of the extra data we store for a node.
+	MaxNodeMetadataLen = 256
+)
+
+// NodeMetadata is the operator information a node can attach to its node
+// announcement as odd TLV records. Nodes that don't know the records ignore
+// them, but still relay them as they are covered by the signature.
+type NodeMetadata struct {
+	// Contact is the contact of the node operator, for example an email
+	// address or a Nostr public key.
+	Contact string
+
+	// OrgName is the name of the organization running the node.
+	OrgName string
+
+	// URL is the canonical URL of the node or its operator.
+	URL string
+}
+
+// records returns the metadata fields keyed by their TLV type.
+func (m *NodeMetadata) records() map[tlv.Type]string {
+	return map[tlv.Type]string{
+		NodeMetadataContactType: m.Contact,
+		NodeMetadataOrgNameType: m.OrgName,
+		NodeMetadataURLType:     m.URL,
+	}
+}
+
+// Validate checks that every field is valid UTF-8 and not too long.
+func (m *NodeMetadata) Validate() error {
+	for typ, value := range m.records() {
+		if len(value) > MaxNodeMetadataLen {
+			return fmt.Errorf("node metadata record %d exceeds %d "+
+				"bytes", typ, MaxNodeMetadataLen)
+		}
+		if !utf8.ValidString(value) {
+			return fmt.Errorf("node metadata record %d is not "+
+				"valid UTF-8", typ)
+		}
+	}
+
+	return nil
+}
+
+// ParseNodeMetadata parses the node metadata records from the extra data of a
+// node announcement. Records that aren't present are left empty.
+func ParseNodeMetadata(extraData []byte) (*NodeMetadata, error) {
+	typeMap, err := decodeTypeMap(extraData)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &NodeMetadata{}
+	for typ, field := range map[tlv.Type]*string{
+		NodeMetadataContactType: &metadata.Contact,
+		NodeMetadataOrgNameType: &metadata.OrgName,
+		NodeMetadataURLType:     &metadata.URL,
+	} {
+		value, ok := typeMap[typ]
+		if !ok {
+			continue
+		}
+		if !utf8.Valid(value) {
+			return nil, fmt.Errorf("node metadata record %d is "+
+				"not valid UTF-8", typ)
+		}
+
+		*field = string(value)
+	}
+
+	return metadata, nil
+}
+
+// SetNodeMetadata returns the given extra data of a node announcement with
+// its node metadata records replaced by the given metadata. Empty fields are
+// removed, all other records are kept.
+func SetNodeMetadata(extraData []byte,
+	metadata *NodeMetadata) (lnwire.ExtraOpaqueData, error) {
+
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+
+	typeMap, err := decodeTypeMap(extraData)
+	if err != nil {
+		return nil, err
+	}
+
+	for typ, value := range metadata.records() {
+		if value == "" {
+			delete(typeMap, typ)
+			continue
+		}
+
+		typeMap[typ] = []byte(value)
+	}
+
+	records := make([]tlv.Record, 0, len(typeMap))
+	for typ, value := range typeMap {
+		value := value
+		records = append(records, tlv.MakePrimitiveRecord(typ, &value))
+	}
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeTypeMap decodes the extra data of a node announcement into its raw
+// TLV records.
+func decodeTypeMap(extraData []byte) (tlv.TypeMap, error) {
+	stream, err := tlv.NewStream()
+	if err != nil {
+		return nil, err
+	}
+
+	// The extra data may come from a remote node, so we use the P2P
+	// decoding variant.
+	typeMap, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(extraData),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return typeMap, nil
+}
+
+// NodeAnnSetExtraOpaqueData is a functional option that sets the extra data
+// of the given node announcement.
+func NodeAnnSetExtraOpaqueData(
+	extraData lnwire.ExtraOpaqueData) func(*lnwire.NodeAnnouncement) {
+
+	return func(nodeAnn *lnwire.NodeAnnouncement) {
+		nodeAnn.ExtraOpaqueData = extraData
+	}
+}
@@ -0,0 +1,71 @@
+package netann
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNodeMetadata tests that node metadata records are set in and parsed from
+// the extra data of a node announcement without touching other records.
+func TestNodeMetadata(t *testing.T) {
+	t.Parallel()
+
+	// Start with extra data that holds an unrelated record.
+	otherValue := []byte{1, 2, 3}
+	var extraData lnwire.ExtraOpaqueData
+	err := extraData.PackRecords(&recordProducer{
+		tlv.MakePrimitiveRecord(tlv.Type(101), &otherValue),
+	})
+	require.NoError(t, err)
+
+	metadata, err := ParseNodeMetadata(extraData)
+	require.NoError(t, err)
+	require.Equal(t, &NodeMetadata{}, metadata)
+
+	expected := &NodeMetadata{
+		Contact: "ops@example.com",
+		OrgName: "Example Inc.",
+		URL:     "https://example.com",
+	}
+	extraData, err = SetNodeMetadata(extraData, expected)
+	require.NoError(t, err)
+
+	metadata, err = ParseNodeMetadata(extraData)
+	require.NoError(t, err)
+	require.Equal(t, expected, metadata)
+
+	// Clearing a field removes its record only.
+	expected.OrgName = ""
+	extraData, err = SetNodeMetadata(extraData, expected)
+	require.NoError(t, err)
+
+	metadata, err = ParseNodeMetadata(extraData)
+	require.NoError(t, err)
+	require.Equal(t, expected, metadata)
+
+	typeMap, err := decodeTypeMap(extraData)
+	require.NoError(t, err)
+	require.Equal(t, otherValue, typeMap[tlv.Type(101)])
+	require.NotContains(t, typeMap, NodeMetadataOrgNameType)
+
+	// Records that are too long are rejected.
+	_, err = SetNodeMetadata(extraData, &NodeMetadata{
+		URL: strings.Repeat("a", MaxNodeMetadataLen+1),
+	})
+	require.Error(t, err)
+}
+
+// recordProducer is a simple helper struct that implements the
+// tlv.RecordProducer interface.
+type recordProducer struct {
+	record tlv.Record
+}
+
+// Record returns the underlying record.
+func (r *recordProducer) Record() tlv.Record {
+	return r.record
+}
@@ -0,0 +1,99 @@
+package lnd
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// newNodeCapabilities extracts the capabilities of a node from the features
+// it advertises.
+//
+// NOTE: There's no feature bit for offers yet, so support for them isn't
+// reported.
+func newNodeCapabilities(
+	features *lnwire.FeatureVector) *lnrpc.NodeCapabilities {
+
+	return &lnrpc.NodeCapabilities{
+		OnionMessages: features.HasFeature(
+			lnwire.OnionMessagesOptional,
+		),
+		RouteBlinding: features.HasFeature(
+			lnwire.RouteBlindingOptional,
+		),
+		TaprootChannels: features.HasFeature(
+			lnwire.SimpleTaprootChannelsOptionalFinal,
+		) || features.HasFeature(
+			lnwire.SimpleTaprootChannelsOptionalStaging,
+		),
+		Trampoline: features.HasFeature(
+			lnwire.TrampolineRoutingOptional,
+		),
+	}
+}
+
+// newNodeRelayStats computes the relay statistics of a node from a mission
+// control snapshot. These summarize how reliably the node relayed our payment
+// attempts, based on the results mission control recorded for the pairs the
+// node is the source of.
+func newNodeRelayStats(node route.Vertex,
+	snapshot *routing.MissionControlSnapshot) *lnrpc.NodeRelayStats {
+
+	var (
+		stats                    = &lnrpc.NodeRelayStats{}
+		lastSuccess, lastFailure time.Time
+		maxSuccessAmt            lnwire.MilliSatoshi
+		latestSuccesses, results int
+	)
+	for _, pair := range snapshot.Pairs {
+		if pair.Pair.From != node {
+			continue
+		}
+
+		hasSuccess := !pair.SuccessTime.IsZero()
+		hasFailure := !pair.FailTime.IsZero()
+
+		if hasSuccess {
+			stats.SuccessPairs++
+			if pair.SuccessTime.After(lastSuccess) {
+				lastSuccess = pair.SuccessTime
+			}
+			if pair.SuccessAmt > maxSuccessAmt {
+				maxSuccessAmt = pair.SuccessAmt
+			}
+		}
+
+		if hasFailure {
+			stats.FailurePairs++
+			if pair.FailTime.After(lastFailure) {
+				lastFailure = pair.FailTime
+			}
+		}
+
+		if !hasSuccess && !hasFailure {
+			continue
+		}
+
+		results++
+		if hasSuccess && pair.SuccessTime.After(pair.FailTime) {
+			latestSuccesses++
+		}
+	}
+
+	if !lastSuccess.IsZero() {
+		stats.LastSuccess = lastSuccess.Unix()
+	}
+	if !lastFailure.IsZero() {
+		stats.LastFailure = lastFailure.Unix()
+	}
+	stats.MaxSuccessAmtMsat = int64(maxSuccessAmt)
+
+	if results > 0 {
+		stats.SuccessRatio = float64(latestSuccesses) / float64(results)
+	}
+
+	return stats
+}
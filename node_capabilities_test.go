@@ -0,0 +1,96 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewNodeCapabilities tests that the capabilities of a node are derived
+// from the features it advertises.
+func TestNewNodeCapabilities(t *testing.T) {
+	t.Parallel()
+
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.OnionMessagesOptional,
+			lnwire.SimpleTaprootChannelsOptionalStaging,
+		), lnwire.Features,
+	)
+
+	require.Equal(t, &lnrpc.NodeCapabilities{
+		OnionMessages:   true,
+		TaprootChannels: true,
+	}, newNodeCapabilities(features))
+
+	features = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.RouteBlindingRequired,
+			lnwire.TrampolineRoutingOptional,
+		), lnwire.Features,
+	)
+
+	require.Equal(t, &lnrpc.NodeCapabilities{
+		RouteBlinding: true,
+		Trampoline:    true,
+	}, newNodeCapabilities(features))
+}
+
+// TestNewNodeRelayStats tests that the relay statistics of a node only take
+// the pairs the node is the source of into account.
+func TestNewNodeRelayStats(t *testing.T) {
+	t.Parallel()
+
+	var node, peer1, peer2, peer3 route.Vertex
+	node[0], peer1[0], peer2[0], peer3[0] = 1, 2, 3, 4
+
+	now := time.Unix(1_000_000, 0)
+
+	snapshot := &routing.MissionControlSnapshot{
+		Pairs: []routing.MissionControlPairSnapshot{
+			{
+				Pair: routing.NewDirectedNodePair(node, peer1),
+				TimedPairResult: routing.TimedPairResult{
+					SuccessTime: now,
+					SuccessAmt:  5000,
+					FailTime:    now.Add(-time.Hour),
+					FailAmt:     9000,
+				},
+			},
+			{
+				Pair: routing.NewDirectedNodePair(node, peer2),
+				TimedPairResult: routing.TimedPairResult{
+					FailTime: now.Add(time.Minute),
+					FailAmt:  1000,
+				},
+			},
+			{
+				// Pairs of other nodes are ignored.
+				Pair: routing.NewDirectedNodePair(peer3, node),
+				TimedPairResult: routing.TimedPairResult{
+					SuccessTime: now.Add(time.Hour),
+					SuccessAmt:  10000,
+				},
+			},
+		},
+	}
+
+	stats := newNodeRelayStats(node, snapshot)
+	require.Equal(t, &lnrpc.NodeRelayStats{
+		SuccessPairs:      1,
+		FailurePairs:      2,
+		LastSuccess:       now.Unix(),
+		LastFailure:       now.Add(time.Minute).Unix(),
+		MaxSuccessAmtMsat: 5000,
+		SuccessRatio:      0.5,
+	}, stats)
+
+	// A node without any results has empty statistics.
+	stats = newNodeRelayStats(peer1, snapshot)
+	require.Equal(t, &lnrpc.NodeRelayStats{}, stats)
+}
@@ -0,0 +1,142 @@
+package onionmessage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrNoHops is returned when a blinded path without any hops is encoded or
+// decoded.
+var ErrNoHops = errors.New("blinded path has no hops")
+
+// BuildBlindedPath creates a blinded path for onion messages that runs through
+// the given nodes and ends at the last one of them. The first node is the
+// introduction node of the path. The path ID is handed to the final node so
+// that it can recognize the paths it created.
+func BuildBlindedPath(sessionKey *btcec.PrivateKey, nodes []*btcec.PublicKey,
+	pathID []byte) (*sphinx.BlindedPath, error) {
+
+	if len(nodes) == 0 {
+		return nil, ErrNoHops
+	}
+
+	hops := make([]*sphinx.HopInfo, len(nodes))
+	for i, node := range nodes {
+		routeData := &RouteData{}
+		if i == len(nodes)-1 {
+			routeData.PathID = pathID
+		} else {
+			routeData.NextNodeID = nodes[i+1]
+		}
+
+		plainText, err := EncodeRouteData(routeData)
+		if err != nil {
+			return nil, err
+		}
+
+		hops[i] = &sphinx.HopInfo{
+			NodePub:   node,
+			PlainText: plainText,
+		}
+	}
+
+	return sphinx.BuildBlindedPath(sessionKey, hops)
+}
+
+// EncodeBlindedPath serializes a blinded path using the blinded_path encoding
+// of the specification.
+func EncodeBlindedPath(path *sphinx.BlindedPath) ([]byte, error) {
+	if len(path.BlindedHops) == 0 {
+		return nil, ErrNoHops
+	}
+	if len(path.BlindedHops) > 255 {
+		return nil, fmt.Errorf("blinded path has %d hops, at most 255 "+
+			"are allowed", len(path.BlindedHops))
+	}
+
+	var b bytes.Buffer
+	err := lnwire.WritePublicKey(&b, path.IntroductionPoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := lnwire.WritePublicKey(&b, path.BlindingPoint); err != nil {
+		return nil, err
+	}
+	err = lnwire.WriteUint8(&b, uint8(len(path.BlindedHops)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hop := range path.BlindedHops {
+		err := lnwire.WritePublicKey(&b, hop.BlindedNodePub)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(hop.CipherText) > 0xffff {
+			return nil, fmt.Errorf("encrypted data of %d bytes "+
+				"exceeds maximum", len(hop.CipherText))
+		}
+		err = lnwire.WriteUint16(&b, uint16(len(hop.CipherText)))
+		if err != nil {
+			return nil, err
+		}
+		if err := lnwire.WriteBytes(&b, hop.CipherText); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeBlindedPath parses a blinded path that was serialized with
+// EncodeBlindedPath.
+func DecodeBlindedPath(b []byte) (*sphinx.BlindedPath, error) {
+	var (
+		r       = bytes.NewReader(b)
+		path    sphinx.BlindedPath
+		numHops uint8
+	)
+
+	err := lnwire.ReadElements(
+		r, &path.IntroductionPoint, &path.BlindingPoint, &numHops,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if numHops == 0 {
+		return nil, ErrNoHops
+	}
+
+	path.BlindedHops = make([]*sphinx.BlindedHopInfo, numHops)
+	for i := range path.BlindedHops {
+		var (
+			hop     sphinx.BlindedHopInfo
+			dataLen uint16
+		)
+		err := lnwire.ReadElements(r, &hop.BlindedNodePub, &dataLen)
+		if err != nil {
+			return nil, err
+		}
+
+		hop.CipherText = make([]byte, dataLen)
+		if _, err := io.ReadFull(r, hop.CipherText); err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &hop
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after blinded path",
+			r.Len())
+	}
+
+	return &path, nil
+}
@@ -0,0 +1,91 @@
+package onionmessage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// maxHeldMessages is the maximum number of onion messages that we
+	// hold for a single offline peer. Once it is reached, the oldest
+	// message is dropped for every new one.
+	maxHeldMessages = 16
+
+	// heldMessageExpiry is the time after which a held onion message is
+	// dropped if the peer didn't come back online.
+	heldMessageExpiry = 24 * time.Hour
+)
+
+// heldMessage is an onion message that waits for its next hop to come online.
+type heldMessage struct {
+	msg      *lnwire.OnionMessage
+	received time.Time
+}
+
+// mailbox holds onion messages for peers that are offline.
+type mailbox struct {
+	clock clock.Clock
+
+	mtx  sync.Mutex
+	msgs map[route.Vertex][]heldMessage
+}
+
+// newMailbox creates an empty mailbox.
+func newMailbox(clock clock.Clock) *mailbox {
+	return &mailbox{
+		clock: clock,
+		msgs:  make(map[route.Vertex][]heldMessage),
+	}
+}
+
+// hold adds the message to the ones held for the given peer, dropping the
+// expired ones.
+func (m *mailbox) hold(peer route.Vertex, msg *lnwire.OnionMessage) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := m.clock.Now()
+	msgs := make([]heldMessage, 0, len(m.msgs[peer])+1)
+	for _, h := range m.msgs[peer] {
+		if now.Sub(h.received) > heldMessageExpiry {
+			continue
+		}
+
+		msgs = append(msgs, h)
+	}
+
+	msgs = append(msgs, heldMessage{
+		msg:      msg,
+		received: now,
+	})
+	if len(msgs) > maxHeldMessages {
+		msgs = msgs[len(msgs)-maxHeldMessages:]
+	}
+
+	m.msgs[peer] = msgs
+}
+
+// take removes all messages held for the given peer and returns the ones that
+// haven't expired yet.
+func (m *mailbox) take(peer route.Vertex) []*lnwire.OnionMessage {
+	m.mtx.Lock()
+	held := m.msgs[peer]
+	delete(m.msgs, peer)
+	m.mtx.Unlock()
+
+	now := m.clock.Now()
+	msgs := make([]*lnwire.OnionMessage, 0, len(held))
+	for _, h := range held {
+		if now.Sub(h.received) > heldMessageExpiry {
+			continue
+		}
+
+		msgs = append(msgs, h.msg)
+	}
+
+	return msgs
+}
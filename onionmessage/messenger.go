@@ -0,0 +1,452 @@
+package onionmessage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrInvalidPacketSize is returned when an onion message carries a
+	// packet that doesn't have the size of a regular onion packet. Larger
+	// packets are allowed by the specification but are not supported.
+	ErrInvalidPacketSize = errors.New("unsupported onion packet size")
+
+	// ErrMissingEncryptedData is returned when the payload of a hop does
+	// not contain the data that tells the hop how to handle the message.
+	ErrMissingEncryptedData = errors.New("missing encrypted data")
+
+	// ErrUnexpectedFields is returned when a message that needs to be
+	// forwarded carries fields that only the final hop may receive.
+	ErrUnexpectedFields = errors.New("unexpected fields for " +
+		"forwarded message")
+
+	// ErrNoNextHop is returned when a message needs to be forwarded but
+	// the blinded path does not tell us where to.
+	ErrNoNextHop = errors.New("no next hop for forwarded message")
+
+	// ErrNextHopIsSelf is returned when a message would be forwarded to
+	// ourselves.
+	ErrNextHopIsSelf = errors.New("next hop is our own node")
+
+	// ErrNotFinalHop is returned when the last layer of the onion was
+	// reached but the blinded path points to a further hop.
+	ErrNotFinalHop = errors.New("final hop has forwarding instructions")
+
+	// ErrPeerOffline is returned by SendToPeer if the peer is not
+	// connected.
+	ErrPeerOffline = errors.New("peer is offline")
+
+	// ErrHandlerExists is returned when a second handler is registered
+	// for the same content type.
+	ErrHandlerExists = errors.New("handler already registered")
+)
+
+// Message is an onion message that was delivered to us.
+type Message struct {
+	// PathID is the path ID that we put in the blinded path the message
+	// was sent over. Handlers should check it to make sure the message
+	// arrived over a path they created, if they rely on that.
+	PathID []byte
+
+	// ReplyPath is the blinded path that the sender included for
+	// replies, if any.
+	ReplyPath *sphinx.BlindedPath
+
+	// Contents holds all content records of the message, keyed by type.
+	Contents map[tlv.Type][]byte
+}
+
+// Handler processes a message that was delivered to us and that carries the
+// content type the handler was registered for.
+type Handler func(msg *Message) error
+
+// Config holds the dependencies of the Messenger.
+type Config struct {
+	// NodeKey is our node key that is used to decrypt the onion messages
+	// that are sent to us.
+	NodeKey sphinx.SingleKeyECDH
+
+	// ChainParams are the parameters of the chain we are running on.
+	ChainParams *chaincfg.Params
+
+	// SendToPeer sends an onion message to the given peer. It must not
+	// block on the delivery of the message and must return ErrPeerOffline
+	// if the peer is not connected.
+	SendToPeer func(peer route.Vertex, msg *lnwire.OnionMessage) error
+
+	// HoldForPeer returns true if the messages we forward to the given
+	// peer should be held while it is offline, so that they can be
+	// delivered once it is back online. If it is nil, messages for
+	// offline peers are dropped.
+	HoldForPeer func(peer route.Vertex) bool
+
+	// Clock is used to expire the messages held for offline peers.
+	Clock clock.Clock
+
+	// FetchChannelPeer returns the peer that we have the given channel
+	// with. It is used for blinded paths that identify the next hop by
+	// channel instead of by node key.
+	FetchChannelPeer func(scid lnwire.ShortChannelID) (route.Vertex,
+		error)
+
+	// NotifyMessage is called with every message that is delivered to
+	// us, regardless of whether a handler is registered for its contents.
+	// It may be nil.
+	NotifyMessage func(msg *Message)
+}
+
+// Messenger forwards onion messages that pass through our node, delivers the
+// ones that are meant for us to the registered handlers and sends out our own
+// messages.
+type Messenger struct {
+	cfg *Config
+
+	ourKey route.Vertex
+
+	router *sphinx.Router
+
+	mailbox *mailbox
+
+	handlersMtx sync.RWMutex
+	handlers    map[tlv.Type]Handler
+}
+
+// New creates a new Messenger from the given config.
+func New(cfg *Config) *Messenger {
+	return &Messenger{
+		cfg:    cfg,
+		ourKey: route.NewVertex(cfg.NodeKey.PubKey()),
+		router: sphinx.NewRouter(
+			cfg.NodeKey, cfg.ChainParams, &noopReplayLog{},
+		),
+		mailbox:  newMailbox(cfg.Clock),
+		handlers: make(map[tlv.Type]Handler),
+	}
+}
+
+// RegisterHandler registers a handler for messages that carry a record of the
+// given content type.
+func (m *Messenger) RegisterHandler(typ tlv.Type, handler Handler) error {
+	if typ < MinContentType {
+		return fmt.Errorf("%w: %d", ErrInvalidContentType, typ)
+	}
+
+	m.handlersMtx.Lock()
+	defer m.handlersMtx.Unlock()
+
+	if _, ok := m.handlers[typ]; ok {
+		return fmt.Errorf("%w: %d", ErrHandlerExists, typ)
+	}
+	m.handlers[typ] = handler
+
+	return nil
+}
+
+// HandleOnionMessage processes an onion message that we received from the
+// given peer. Depending on our position in the blinded path, the message is
+// either forwarded to the next hop or delivered to our handlers.
+func (m *Messenger) HandleOnionMessage(peer route.Vertex,
+	msg *lnwire.OnionMessage) error {
+
+	if len(msg.OnionBlob) != lnwire.OnionPacketSize {
+		return fmt.Errorf("%w: %d", ErrInvalidPacketSize,
+			len(msg.OnionBlob))
+	}
+
+	var onionPkt sphinx.OnionPacket
+	err := onionPkt.Decode(bytes.NewReader(msg.OnionBlob))
+	if err != nil {
+		return fmt.Errorf("unable to decode onion packet: %w", err)
+	}
+
+	processed, err := m.router.ProcessOnionPacket(
+		&onionPkt, nil, 0, sphinx.WithBlindingPoint(msg.PathKey),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to process onion packet: %w", err)
+	}
+
+	payload, err := DecodePayload(processed.Payload.Payload)
+	if err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+	if len(payload.EncryptedData) == 0 {
+		return ErrMissingEncryptedData
+	}
+
+	plainText, err := m.router.DecryptBlindedHopData(
+		msg.PathKey, payload.EncryptedData,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to decrypt route data: %w", err)
+	}
+
+	routeData, err := DecodeRouteData(plainText)
+	if err != nil {
+		return fmt.Errorf("unable to decode route data: %w", err)
+	}
+
+	switch processed.Action {
+	case sphinx.MoreHops:
+		return m.forward(
+			peer, msg.PathKey, payload, routeData,
+			processed.NextPacket,
+		)
+
+	case sphinx.ExitNode:
+		return m.deliver(payload, routeData)
+
+	default:
+		return fmt.Errorf("unexpected onion action: %v",
+			processed.Action)
+	}
+}
+
+// forward sends the peeled onion on to the next hop of the blinded path.
+func (m *Messenger) forward(peer route.Vertex, pathKey *btcec.PublicKey,
+	payload *Payload, routeData *RouteData,
+	nextPacket *sphinx.OnionPacket) error {
+
+	if len(payload.ReplyPath) > 0 || len(payload.Contents) > 0 {
+		return ErrUnexpectedFields
+	}
+
+	var nextNode route.Vertex
+	switch {
+	case routeData.NextNodeID != nil:
+		nextNode = route.NewVertex(routeData.NextNodeID)
+
+	case routeData.ShortChannelID != nil:
+		var err error
+		nextNode, err = m.cfg.FetchChannelPeer(
+			*routeData.ShortChannelID,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to find peer for channel "+
+				"%v: %w", routeData.ShortChannelID, err)
+		}
+
+	default:
+		return ErrNoNextHop
+	}
+
+	if nextNode == m.ourKey {
+		return ErrNextHopIsSelf
+	}
+
+	nextPathKey := routeData.NextPathKeyOverride
+	if nextPathKey == nil {
+		var err error
+		nextPathKey, err = m.router.NextEphemeral(pathKey)
+		if err != nil {
+			return fmt.Errorf("unable to derive next path key: %w",
+				err)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := nextPacket.Encode(&b); err != nil {
+		return err
+	}
+
+	log.Tracef("Forwarding onion message from %v to %v", peer, nextNode)
+
+	nextMsg := lnwire.NewOnionMessage(nextPathKey, b.Bytes())
+	err := m.cfg.SendToPeer(nextNode, nextMsg)
+	if errors.Is(err, ErrPeerOffline) && m.cfg.HoldForPeer != nil &&
+		m.cfg.HoldForPeer(nextNode) {
+
+		log.Debugf("Holding onion message for offline peer %v",
+			nextNode)
+
+		m.mailbox.hold(nextNode, nextMsg)
+
+		return nil
+	}
+
+	return err
+}
+
+// PeerOnline delivers the messages that we held for the given peer while it
+// was offline. It must be called once the peer is connected again.
+func (m *Messenger) PeerOnline(peer route.Vertex) {
+	msgs := m.mailbox.take(peer)
+	if len(msgs) == 0 {
+		return
+	}
+
+	log.Debugf("Delivering %d held onion messages to %v", len(msgs),
+		peer)
+
+	for _, msg := range msgs {
+		if err := m.cfg.SendToPeer(peer, msg); err != nil {
+			log.Debugf("Unable to deliver held onion message to "+
+				"%v: %v", peer, err)
+
+			return
+		}
+	}
+}
+
+// deliver hands a message that reached us to the handlers of its contents.
+func (m *Messenger) deliver(payload *Payload, routeData *RouteData) error {
+	if !routeData.IsFinal() {
+		return ErrNotFinalHop
+	}
+
+	msg := &Message{
+		PathID:   routeData.PathID,
+		Contents: payload.Contents,
+	}
+	if len(payload.ReplyPath) > 0 {
+		replyPath, err := DecodeBlindedPath(payload.ReplyPath)
+		if err != nil {
+			return fmt.Errorf("unable to decode reply path: %w",
+				err)
+		}
+		msg.ReplyPath = replyPath
+	}
+
+	if m.cfg.NotifyMessage != nil {
+		m.cfg.NotifyMessage(msg)
+	}
+
+	m.handlersMtx.RLock()
+	defer m.handlersMtx.RUnlock()
+
+	var handled bool
+	for typ := range msg.Contents {
+		handler, ok := m.handlers[typ]
+		if !ok {
+			continue
+		}
+
+		handled = true
+		if err := handler(msg); err != nil {
+			return fmt.Errorf("unable to handle content type %d: "+
+				"%w", typ, err)
+		}
+	}
+
+	if !handled {
+		log.Debugf("Dropping onion message without known contents")
+	}
+
+	return nil
+}
+
+// SendMessage sends an onion message with the given contents over the given
+// blinded path. The reply path is optional and is handed to the recipient so
+// that it can respond.
+func (m *Messenger) SendMessage(path, replyPath *sphinx.BlindedPath,
+	contents map[tlv.Type][]byte) error {
+
+	firstHop, msg, err := CreateOnionMessage(path, replyPath, contents)
+	if err != nil {
+		return err
+	}
+
+	if firstHop == m.ourKey {
+		return ErrNextHopIsSelf
+	}
+
+	return m.cfg.SendToPeer(firstHop, msg)
+}
+
+// CreateOnionMessage builds an onion message that travels along the given
+// blinded path and delivers the contents and the optional reply path to the
+// final hop. It returns the introduction node of the path, which is the node
+// the message has to be sent to, along with the message itself.
+func CreateOnionMessage(path, replyPath *sphinx.BlindedPath,
+	contents map[tlv.Type][]byte) (route.Vertex, *lnwire.OnionMessage,
+	error) {
+
+	numHops := len(path.BlindedHops)
+	switch {
+	case numHops == 0:
+		return route.Vertex{}, nil, ErrNoHops
+
+	case numHops > sphinx.NumMaxHops:
+		return route.Vertex{}, nil, fmt.Errorf("blinded path has %d "+
+			"hops, at most %d are supported", numHops,
+			sphinx.NumMaxHops)
+	}
+
+	var encodedReplyPath []byte
+	if replyPath != nil {
+		var err error
+		encodedReplyPath, err = EncodeBlindedPath(replyPath)
+		if err != nil {
+			return route.Vertex{}, nil, err
+		}
+	}
+
+	var paymentPath sphinx.PaymentPath
+	for i, hop := range path.BlindedHops {
+		payload := &Payload{
+			EncryptedData: hop.CipherText,
+		}
+		if i == numHops-1 {
+			payload.ReplyPath = encodedReplyPath
+			payload.Contents = contents
+		}
+
+		encoded, err := EncodePayload(payload)
+		if err != nil {
+			return route.Vertex{}, nil, err
+		}
+
+		hopPayload, err := sphinx.NewTLVHopPayload(encoded)
+		if err != nil {
+			return route.Vertex{}, nil, err
+		}
+
+		paymentPath[i] = sphinx.OnionHop{
+			NodePub:    *hop.BlindedNodePub,
+			HopPayload: hopPayload,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return route.Vertex{}, nil, err
+	}
+
+	onionPkt, err := sphinx.NewOnionPacket(
+		&paymentPath, sessionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("unable to create "+
+			"onion packet: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := onionPkt.Encode(&b); err != nil {
+		return route.Vertex{}, nil, err
+	}
+
+	return route.NewVertex(path.IntroductionPoint),
+		lnwire.NewOnionMessage(path.BlindingPoint, b.Bytes()), nil
+}
+
+// NewPathID returns a random path ID that can be used to recognize the
+// blinded paths that we create.
+func NewPathID() ([]byte, error) {
+	var pathID [32]byte
+	if _, err := rand.Read(pathID[:]); err != nil {
+		return nil, err
+	}
+
+	return pathID[:], nil
+}
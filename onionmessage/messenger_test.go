@@ -0,0 +1,351 @@
+package onionmessage
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// testNetwork is a set of messengers that deliver onion messages to each
+// other directly.
+type testNetwork struct {
+	t          *testing.T
+	clock      *clock.TestClock
+	messengers map[route.Vertex]*Messenger
+	keys       []*btcec.PublicKey
+
+	offlineMtx sync.Mutex
+	offline    map[route.Vertex]bool
+}
+
+// newTestNetwork creates a network of the given number of messengers.
+func newTestNetwork(t *testing.T, numNodes int) *testNetwork {
+	n := &testNetwork{
+		t:          t,
+		clock:      clock.NewTestClock(time.Unix(1, 0)),
+		messengers: make(map[route.Vertex]*Messenger),
+		offline:    make(map[route.Vertex]bool),
+	}
+
+	for i := 0; i < numNodes; i++ {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		node := route.NewVertex(priv.PubKey())
+		m := New(&Config{
+			NodeKey:     &sphinx.PrivKeyECDH{PrivKey: priv},
+			ChainParams: &chaincfg.RegressionNetParams,
+			SendToPeer:  n.sendToPeer(node),
+			HoldForPeer: func(route.Vertex) bool {
+				return true
+			},
+			Clock: n.clock,
+		})
+
+		n.messengers[node] = m
+		n.keys = append(n.keys, priv.PubKey())
+	}
+
+	return n
+}
+
+// sendToPeer returns a SendToPeer function for the given node that passes the
+// message straight to the target messenger.
+func (n *testNetwork) sendToPeer(from route.Vertex) func(route.Vertex,
+	*lnwire.OnionMessage) error {
+
+	return func(to route.Vertex, msg *lnwire.OnionMessage) error {
+		n.offlineMtx.Lock()
+		offline := n.offline[to]
+		n.offlineMtx.Unlock()
+		if offline {
+			return ErrPeerOffline
+		}
+
+		// Send the message over the wire encoding to make sure it
+		// survives it.
+		var b bytes.Buffer
+		_, err := lnwire.WriteMessage(&b, msg, 0)
+		require.NoError(n.t, err)
+
+		decoded, err := lnwire.ReadMessage(&b, 0)
+		require.NoError(n.t, err)
+
+		return n.messengers[to].HandleOnionMessage(
+			from, decoded.(*lnwire.OnionMessage),
+		)
+	}
+}
+
+// setOffline marks the node with the given index as offline or online.
+func (n *testNetwork) setOffline(i int, offline bool) {
+	n.offlineMtx.Lock()
+	defer n.offlineMtx.Unlock()
+
+	n.offline[route.NewVertex(n.keys[i])] = offline
+}
+
+// messenger returns the messenger of the node with the given index.
+func (n *testNetwork) messenger(i int) *Messenger {
+	return n.messengers[route.NewVertex(n.keys[i])]
+}
+
+// TestOnionMessageDelivery asserts that a message is forwarded along a
+// blinded path and delivered to the handler of the recipient together with
+// the path ID and the reply path.
+func TestOnionMessageDelivery(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, 4)
+
+	const contentType tlv.Type = 65
+	var delivered *Message
+	err := n.messenger(3).RegisterHandler(contentType,
+		func(msg *Message) error {
+			delivered = msg
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	pathID, err := NewPathID()
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	path, err := BuildBlindedPath(sessionKey, n.keys[1:], pathID)
+	require.NoError(t, err)
+
+	replySessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	replyPath, err := BuildBlindedPath(
+		replySessionKey, []*btcec.PublicKey{n.keys[1], n.keys[0]}, nil,
+	)
+	require.NoError(t, err)
+
+	contents := map[tlv.Type][]byte{
+		contentType: []byte("hello"),
+		101:         []byte("ignored"),
+	}
+	err = n.messenger(0).SendMessage(path, replyPath, contents)
+	require.NoError(t, err)
+
+	require.NotNil(t, delivered)
+	require.Equal(t, pathID, delivered.PathID)
+	require.Equal(t, contents, delivered.Contents)
+	require.Equal(t, replyPath, delivered.ReplyPath)
+
+	// The recipient replies over the reply path, which leads the message
+	// back to the original sender.
+	var reply *Message
+	err = n.messenger(0).RegisterHandler(contentType,
+		func(msg *Message) error {
+			reply = msg
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	err = n.messenger(3).SendMessage(
+		delivered.ReplyPath, nil, map[tlv.Type][]byte{
+			contentType: []byte("hi"),
+		},
+	)
+	require.NoError(t, err)
+
+	require.NotNil(t, reply)
+	require.Equal(t, []byte("hi"), reply.Contents[contentType])
+	require.Nil(t, reply.ReplyPath)
+}
+
+// TestOnionMessageHoldForOfflinePeer asserts that messages for an offline peer
+// are held and delivered once it is back online, unless they expired.
+func TestOnionMessageHoldForOfflinePeer(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, 3)
+
+	const contentType tlv.Type = 65
+	var delivered [][]byte
+	err := n.messenger(2).RegisterHandler(contentType,
+		func(msg *Message) error {
+			delivered = append(delivered, msg.Contents[contentType])
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	sendMsg := func(content byte) {
+		sessionKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		path, err := BuildBlindedPath(sessionKey, n.keys[1:], nil)
+		require.NoError(t, err)
+
+		err = n.messenger(0).SendMessage(path, nil, map[tlv.Type][]byte{
+			contentType: {content},
+		})
+		require.NoError(t, err)
+	}
+
+	// The recipient is offline, so the message is held by its peer.
+	n.setOffline(2, true)
+	sendMsg(1)
+	require.Empty(t, delivered)
+
+	// Once it is back online, the message is delivered.
+	n.setOffline(2, false)
+	n.messenger(1).PeerOnline(route.NewVertex(n.keys[2]))
+	require.Equal(t, [][]byte{{1}}, delivered)
+
+	// Nothing is delivered twice.
+	n.messenger(1).PeerOnline(route.NewVertex(n.keys[2]))
+	require.Len(t, delivered, 1)
+
+	// Messages that were held for too long are dropped.
+	n.setOffline(2, true)
+	sendMsg(2)
+	n.clock.SetTime(n.clock.Now().Add(heldMessageExpiry + time.Second))
+	sendMsg(3)
+
+	n.setOffline(2, false)
+	n.messenger(1).PeerOnline(route.NewVertex(n.keys[2]))
+	require.Equal(t, [][]byte{{1}, {3}}, delivered)
+}
+
+// TestOnionMessageForwardContents asserts that a forwarding node refuses to
+// forward a message that carries contents for it.
+func TestOnionMessageForwardContents(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, 3)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	path, err := BuildBlindedPath(sessionKey, n.keys[1:], nil)
+	require.NoError(t, err)
+
+	// Build an onion that carries contents for the intermediate hop.
+	var paymentPath sphinx.PaymentPath
+	for i, hop := range path.BlindedHops {
+		encoded, err := EncodePayload(&Payload{
+			EncryptedData: hop.CipherText,
+			Contents: map[tlv.Type][]byte{
+				65: []byte("hello"),
+			},
+		})
+		require.NoError(t, err)
+
+		hopPayload, err := sphinx.NewTLVHopPayload(encoded)
+		require.NoError(t, err)
+
+		paymentPath[i] = sphinx.OnionHop{
+			NodePub:    *hop.BlindedNodePub,
+			HopPayload: hopPayload,
+		}
+	}
+
+	onionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	onionPkt, err := sphinx.NewOnionPacket(
+		&paymentPath, onionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, onionPkt.Encode(&b))
+
+	err = n.messenger(1).HandleOnionMessage(
+		route.NewVertex(n.keys[0]),
+		lnwire.NewOnionMessage(path.BlindingPoint, b.Bytes()),
+	)
+	require.ErrorIs(t, err, ErrUnexpectedFields)
+}
+
+// TestOnionMessageInvalidSize asserts that packets that don't have the regular
+// onion packet size are rejected.
+func TestOnionMessageInvalidSize(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, 1)
+
+	err := n.messenger(0).HandleOnionMessage(
+		route.Vertex{}, lnwire.NewOnionMessage(n.keys[0], []byte{0}),
+	)
+	require.ErrorIs(t, err, ErrInvalidPacketSize)
+}
+
+// TestDecodePayloadUnknownRequired asserts that unknown even routing types are
+// rejected while unknown content types are returned as contents.
+func TestDecodePayloadUnknownRequired(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := EncodePayload(&Payload{
+		EncryptedData: []byte{1, 2, 3},
+		Contents: map[tlv.Type][]byte{
+			64: {4},
+		},
+	})
+	require.NoError(t, err)
+
+	payload, err := DecodePayload(encoded)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, payload.EncryptedData)
+	require.Equal(t, map[tlv.Type][]byte{64: {4}}, payload.Contents)
+
+	// Type 6 is even and unknown.
+	_, err = DecodePayload([]byte{6, 1, 0})
+	require.ErrorIs(t, err, ErrUnknownRequiredType)
+
+	// Type 7 is odd and may be ignored.
+	_, err = DecodePayload([]byte{7, 1, 0})
+	require.NoError(t, err)
+
+	_, err = EncodePayload(&Payload{
+		Contents: map[tlv.Type][]byte{
+			10: {4},
+		},
+	})
+	require.ErrorIs(t, err, ErrInvalidContentType)
+}
+
+// TestOnionMessageNotify asserts that every delivered message is passed to
+// NotifyMessage, including the ones no handler is registered for.
+func TestOnionMessageNotify(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, 2)
+
+	var notified []*Message
+	n.messenger(1).cfg.NotifyMessage = func(msg *Message) {
+		notified = append(notified, msg)
+	}
+
+	pathID, err := NewPathID()
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	path, err := BuildBlindedPath(sessionKey, n.keys[1:], pathID)
+	require.NoError(t, err)
+
+	contents := map[tlv.Type][]byte{
+		101: []byte("unhandled"),
+	}
+	err = n.messenger(0).SendMessage(path, nil, contents)
+	require.NoError(t, err)
+
+	require.Len(t, notified, 1)
+	require.Equal(t, pathID, notified[0].PathID)
+	require.Nil(t, notified[0].ReplyPath)
+	require.Equal(t, contents, notified[0].Contents)
+}
@@ -0,0 +1,268 @@
+package onionmessage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// replyPathType is the type of the onionmsg_tlv record that holds a
+	// blinded path the recipient can use to reply to the message.
+	replyPathType tlv.Type = 2
+
+	// encryptedDataType is the type of the onionmsg_tlv record that holds
+	// the data the creator of the blinded path encrypted for the hop.
+	encryptedDataType tlv.Type = 4
+
+	// MinContentType is the lowest onionmsg_tlv type that can be used for
+	// the contents of a message. All lower types are reserved for the
+	// fields that control how the message is routed.
+	MinContentType tlv.Type = 64
+
+	// routePaddingType is the type of the encrypted_data_tlv record that
+	// is used to pad all hops of a blinded path to the same size.
+	routePaddingType tlv.Type = 1
+
+	// routeSCIDType is the type of the encrypted_data_tlv record that
+	// identifies the next hop by the channel we have with it.
+	routeSCIDType tlv.Type = 2
+
+	// routeNextNodeType is the type of the encrypted_data_tlv record that
+	// identifies the next hop by its node key.
+	routeNextNodeType tlv.Type = 4
+
+	// routePathIDType is the type of the encrypted_data_tlv record that
+	// the recipient uses to recognize blinded paths it created.
+	routePathIDType tlv.Type = 6
+
+	// routePathKeyOverrideType is the type of the encrypted_data_tlv
+	// record that replaces the path key for the next hop.
+	routePathKeyOverrideType tlv.Type = 8
+)
+
+var (
+	// ErrUnknownRequiredType is returned when a payload contains an even
+	// type that is not known to us.
+	ErrUnknownRequiredType = errors.New("unknown required type")
+
+	// ErrInvalidContentType is returned when the contents of a message
+	// use a type that is reserved for routing fields.
+	ErrInvalidContentType = errors.New("content type below minimum")
+)
+
+// Payload is the onionmsg_tlv payload that the sender of an onion message
+// left for a single hop.
+type Payload struct {
+	// ReplyPath is an optional blinded path that the recipient can use to
+	// reply to the message. It is only set for the final hop.
+	ReplyPath []byte
+
+	// EncryptedData is the data that the creator of the blinded path
+	// encrypted for this hop. It tells the hop where to forward the
+	// message to.
+	EncryptedData []byte
+
+	// Contents holds the records of the message itself, keyed by their
+	// type. It is only set for the final hop.
+	Contents map[tlv.Type][]byte
+}
+
+// EncodePayload serializes the given payload as a TLV stream.
+func EncodePayload(p *Payload) ([]byte, error) {
+	var records []tlv.Record
+	if len(p.ReplyPath) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			replyPathType, &p.ReplyPath,
+		))
+	}
+	if len(p.EncryptedData) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			encryptedDataType, &p.EncryptedData,
+		))
+	}
+
+	for typ, val := range p.Contents {
+		if typ < MinContentType {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidContentType,
+				typ)
+		}
+
+		val := val
+		records = append(records, tlv.MakePrimitiveRecord(typ, &val))
+	}
+
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodePayload parses the onionmsg_tlv payload of a hop. All unknown records
+// that use a content type are returned as the contents of the message.
+func DecodePayload(b []byte) (*Payload, error) {
+	var p Payload
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(replyPathType, &p.ReplyPath),
+		tlv.MakePrimitiveRecord(encryptedDataType, &p.EncryptedData),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(b),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for typ, val := range parsedTypes {
+		// Known records are recorded with a nil value.
+		if val == nil {
+			continue
+		}
+
+		if typ >= MinContentType {
+			if p.Contents == nil {
+				p.Contents = make(map[tlv.Type][]byte)
+			}
+			p.Contents[typ] = val
+
+			continue
+		}
+
+		if typ%2 == 0 {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownRequiredType,
+				typ)
+		}
+	}
+
+	return &p, nil
+}
+
+// RouteData is the encrypted_data_tlv payload that the creator of a blinded
+// path encrypted for a single hop.
+type RouteData struct {
+	// ShortChannelID identifies the next hop by the channel that the
+	// forwarding node has with it. It is only used if NextNodeID is not
+	// set.
+	ShortChannelID *lnwire.ShortChannelID
+
+	// NextNodeID is the node the message should be forwarded to. It is
+	// not set for the final hop.
+	NextNodeID *btcec.PublicKey
+
+	// PathID is set by the recipient for the final hop, so that it can
+	// tell whether a message reached it over a path it created.
+	PathID []byte
+
+	// NextPathKeyOverride replaces the path key that is sent to the next
+	// hop. It is used to join two blinded paths.
+	NextPathKeyOverride *btcec.PublicKey
+}
+
+// IsFinal returns true if the route data does not point to a next hop.
+func (r *RouteData) IsFinal() bool {
+	return r.NextNodeID == nil && r.ShortChannelID == nil
+}
+
+// EncodeRouteData serializes the given route data as a TLV stream.
+func EncodeRouteData(r *RouteData) ([]byte, error) {
+	var records []tlv.Record
+	if r.ShortChannelID != nil {
+		scid := r.ShortChannelID.ToUint64()
+		records = append(records, tlv.MakePrimitiveRecord(
+			routeSCIDType, &scid,
+		))
+	}
+	if r.NextNodeID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			routeNextNodeType, &r.NextNodeID,
+		))
+	}
+	if len(r.PathID) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			routePathIDType, &r.PathID,
+		))
+	}
+	if r.NextPathKeyOverride != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			routePathKeyOverrideType, &r.NextPathKeyOverride,
+		))
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeRouteData parses the decrypted encrypted_data_tlv payload of a hop.
+func DecodeRouteData(b []byte) (*RouteData, error) {
+	var (
+		r        RouteData
+		padding  []byte
+		scid     uint64
+		nextNode *btcec.PublicKey
+		override *btcec.PublicKey
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(routePaddingType, &padding),
+		tlv.MakePrimitiveRecord(routeSCIDType, &scid),
+		tlv.MakePrimitiveRecord(routeNextNodeType, &nextNode),
+		tlv.MakePrimitiveRecord(routePathIDType, &r.PathID),
+		tlv.MakePrimitiveRecord(routePathKeyOverrideType, &override),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(b),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for typ, val := range parsedTypes {
+		if val != nil && typ%2 == 0 {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownRequiredType,
+				typ)
+		}
+	}
+
+	if _, ok := parsedTypes[routeSCIDType]; ok {
+		chanID := lnwire.NewShortChanIDFromInt(scid)
+		r.ShortChannelID = &chanID
+	}
+	if _, ok := parsedTypes[routeNextNodeType]; ok {
+		r.NextNodeID = nextNode
+	}
+	if _, ok := parsedTypes[routePathKeyOverrideType]; ok {
+		r.NextPathKeyOverride = override
+	}
+
+	return &r, nil
+}
@@ -0,0 +1,332 @@
+package lnd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// The names of the checks of a channel opening, in the order they are
+	// reported.
+	checkNodeReady       = "node_ready"
+	checkParameters      = "parameters"
+	checkPeerConnected   = "peer_connected"
+	checkChannelType     = "channel_type"
+	checkUpfrontShutdown = "upfront_shutdown"
+	checkChannelSize     = "channel_size"
+	checkWalletBalance   = "wallet_balance"
+	checkPendingChannels = "pending_channels"
+	checkAcceptorPolicy  = "acceptor_policy"
+)
+
+// openChannelReport collects the results of checking an OpenChannel request
+// without opening the channel.
+type openChannelReport struct {
+	*lnrpc.CheckOpenChannelResponse
+}
+
+// newOpenChannelReport creates an empty report.
+func newOpenChannelReport() *openChannelReport {
+	return &openChannelReport{
+		CheckOpenChannelResponse: &lnrpc.CheckOpenChannelResponse{},
+	}
+}
+
+// add records the result of a check.
+func (r *openChannelReport) add(name string,
+	status lnrpc.OpenChannelCheck_CheckStatus, format string,
+	args ...interface{}) {
+
+	r.Checks = append(r.Checks, &lnrpc.OpenChannelCheck{
+		Name:   name,
+		Status: status,
+		Detail: fmt.Sprintf(format, args...),
+	})
+}
+
+// pass records a check that passed.
+func (r *openChannelReport) pass(name, format string, args ...interface{}) {
+	r.add(name, lnrpc.OpenChannelCheck_PASS, format, args...)
+}
+
+// warn records a check that found a reason the channel opening may fail.
+func (r *openChannelReport) warn(name, format string, args ...interface{}) {
+	r.add(name, lnrpc.OpenChannelCheck_WARN, format, args...)
+}
+
+// fail records a check that found a reason the channel opening will fail.
+func (r *openChannelReport) fail(name, format string, args ...interface{}) {
+	r.add(name, lnrpc.OpenChannelCheck_FAIL, format, args...)
+}
+
+// skip records the given checks as skipped for the given reason.
+func (r *openChannelReport) skip(reason string, names ...string) {
+	for _, name := range names {
+		r.add(name, lnrpc.OpenChannelCheck_SKIP, "%s", reason)
+	}
+}
+
+// finish sets whether the channel can be opened from the results of the
+// checks and returns the response.
+func (r *openChannelReport) finish() *lnrpc.CheckOpenChannelResponse {
+	r.CanOpen = true
+	for _, check := range r.Checks {
+		if check.Status == lnrpc.OpenChannelCheck_FAIL {
+			r.CanOpen = false
+		}
+	}
+
+	return r.CheckOpenChannelResponse
+}
+
+// marshalCommitmentType converts the commitment type of the wallet to its RPC
+// representation.
+func marshalCommitmentType(t lnwallet.CommitmentType) lnrpc.CommitmentType {
+	switch t {
+	case lnwallet.CommitmentTypeLegacy:
+		return lnrpc.CommitmentType_LEGACY
+
+	case lnwallet.CommitmentTypeTweakless:
+		return lnrpc.CommitmentType_STATIC_REMOTE_KEY
+
+	case lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx:
+		return lnrpc.CommitmentType_ANCHORS
+
+	case lnwallet.CommitmentTypeScriptEnforcedLease:
+		return lnrpc.CommitmentType_SCRIPT_ENFORCED_LEASE
+
+	case lnwallet.CommitmentTypeSimpleTaproot:
+		return lnrpc.CommitmentType_SIMPLE_TAPROOT
+
+	default:
+		return lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE
+	}
+}
+
+// hasChannelTypeBit returns true if the requested channel type has the given
+// feature bit set.
+func hasChannelTypeBit(req *funding.InitFundingMsg,
+	bit lnwire.FeatureBit) bool {
+
+	if req.ChannelType == nil {
+		return false
+	}
+
+	chanType := lnwire.RawFeatureVector(*req.ChannelType)
+
+	return chanType.IsSet(bit)
+}
+
+// checkChannelFeatures checks that the features of the peer support the
+// requested channel and returns the commitment type the channel would use.
+func (r *openChannelReport) checkChannelFeatures(req *funding.InitFundingMsg,
+	local, remote *lnwire.FeatureVector) lnwallet.CommitmentType {
+
+	commitType, err := funding.NegotiateCommitmentType(
+		req.ChannelType, local, remote,
+	)
+	switch {
+	case err != nil:
+		r.fail(checkChannelType, "requested "+
+			"channel type is not supported by the peer: %v", err)
+
+	case hasChannelTypeBit(req, lnwire.ScidAliasRequired) && !req.Private:
+		r.fail(checkChannelType, "scid alias channels must be private")
+
+	default:
+		r.CommitmentType = marshalCommitmentType(commitType)
+		r.pass(checkChannelType, "channel "+
+			"would use commitment type %v", commitType)
+	}
+
+	// The peer must support upfront shutdown if a close address is set.
+	upfrontShutdown := remote.HasFeature(
+		lnwire.UpfrontShutdownScriptOptional,
+	)
+	switch {
+	case len(req.ShutdownScript) == 0:
+		r.pass(checkUpfrontShutdown, "no close address requested")
+
+	case !upfrontShutdown:
+		r.fail(checkUpfrontShutdown, "peer "+
+			"doesn't support upfront shutdown, remove the close "+
+			"address")
+
+	default:
+		r.pass(checkUpfrontShutdown, "peer supports upfront shutdown")
+	}
+
+	// Peers reject channels above the soft limit unless they signal wumbo
+	// support, in which case they use the wumbo limit by default.
+	amt := req.LocalFundingAmt
+	if req.FundUpToMaxAmt > 0 {
+		amt = req.FundUpToMaxAmt
+	}
+	wumbo := remote.HasFeature(lnwire.WumboChannelsOptional)
+	switch {
+	case !wumbo && amt > funding.MaxBtcFundingAmount:
+		// With fundmax, the channel size is only capped at the maximum
+		// and may still be accepted.
+		status := lnrpc.OpenChannelCheck_FAIL
+		if req.FundUpToMaxAmt > 0 {
+			status = lnrpc.OpenChannelCheck_WARN
+		}
+		r.add(checkChannelSize, status, "peer doesn't support wumbo "+
+			"channels and rejects channels above %v",
+			funding.MaxBtcFundingAmount)
+
+	case wumbo && amt > funding.MaxBtcFundingAmountWumbo:
+		r.warn(checkChannelSize, "channel size "+
+			"exceeds the default maximum of %v of the peer",
+			funding.MaxBtcFundingAmountWumbo)
+
+	default:
+		r.pass(checkChannelSize, "channel size "+
+			"is within the default limits of the peer")
+	}
+
+	return commitType
+}
+
+// fundingInputWeight adds the input spending the coin to the weight
+// estimate.
+func fundingInputWeight(estimator *input.TxWeightEstimator,
+	coin *lnwallet.Utxo) {
+
+	switch coin.AddressType {
+	case lnwallet.NestedWitnessPubKey:
+		estimator.AddNestedP2WKHInput()
+
+	case lnwallet.TaprootPubkey:
+		estimator.AddTaprootKeySpendInput(txscript.SigHashDefault)
+
+	default:
+		estimator.AddP2WKHInput()
+	}
+}
+
+// estimateFundingFee estimates the fee of the funding transaction of a
+// channel with the given amount. Coins are selected largest first until they
+// cover the amount, the fee and the reserve. If fundMax is set, all coins are
+// spent. It returns the estimated fee, or the fee of spending all coins if
+// they are not sufficient.
+func estimateFundingFee(coins []*lnwallet.Utxo, amt, reserve btcutil.Amount,
+	feeRate chainfee.SatPerKWeight, taproot, fundMax bool) btcutil.Amount {
+
+	sorted := make([]*lnwallet.Utxo, len(coins))
+	copy(sorted, coins)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+
+	var estimator input.TxWeightEstimator
+	if taproot {
+		estimator.AddP2TROutput()
+	} else {
+		estimator.AddP2WSHOutput()
+	}
+
+	// The change output is always a taproot output.
+	estimator.AddP2TROutput()
+
+	var (
+		total btcutil.Amount
+		fee   btcutil.Amount
+	)
+	for _, coin := range sorted {
+		fundingInputWeight(&estimator, coin)
+		total += coin.Value
+
+		fee = feeRate.FeeForWeight(estimator.Weight())
+		if !fundMax && total >= amt+fee+reserve {
+			break
+		}
+	}
+
+	return fee
+}
+
+// checkWalletBalance checks that the coins are sufficient to fund the
+// channel, pay the funding fee and keep the wallet reserve. The reserve only
+// applies to public channels we contribute funds to.
+func (r *openChannelReport) checkWalletBalance(req *funding.InitFundingMsg,
+	coins []*lnwallet.Utxo, reserve btcutil.Amount, taproot bool) {
+
+	var available btcutil.Amount
+	for _, coin := range coins {
+		available += coin.Value
+	}
+
+	fundMax := req.FundUpToMaxAmt > 0
+	amt := req.LocalFundingAmt
+	if fundMax {
+		amt = req.MinFundAmt
+	}
+
+	fee := estimateFundingFee(
+		coins, amt, reserve, req.FundingFeePerKw, taproot, fundMax,
+	)
+	required := amt + fee + reserve
+
+	r.FundingFeeSat = int64(fee)
+	r.WalletReserveSat = int64(reserve)
+	r.RequiredBalanceSat = int64(required)
+	r.AvailableBalanceSat = int64(available)
+
+	if available < required {
+		r.fail(checkWalletBalance, "insufficient "+
+			"funds: %v available, %v required (funding amount %v, "+
+			"estimated fee %v, wallet reserve %v)", available,
+			required, amt, fee, reserve)
+
+		return
+	}
+
+	r.pass(checkWalletBalance, "%v available, %v "+
+		"required (funding amount %v, estimated fee %v, wallet "+
+		"reserve %v)", available, required, amt, fee, reserve)
+}
+
+// checkPendingChannels checks the number of channels we opened with the peer
+// that are still pending. Peers reject more pending channels than they are
+// configured to allow, which we can only compare against the default.
+func (r *openChannelReport) checkPendingChannels(numPending int) {
+	if numPending >= lncfg.DefaultMaxPendingChannels {
+		r.warn(checkPendingChannels, "%d "+
+			"channel(s) with the peer are pending, the peer "+
+			"rejects new channels if it allows at most %d "+
+			"pending channels, which is the default", numPending,
+			lncfg.DefaultMaxPendingChannels)
+
+		return
+	}
+
+	r.pass(checkPendingChannels, "no channels with the peer are pending")
+}
+
+// checkAcceptorPolicy checks the parts of the request that depend on the
+// channel acceptor of the peer. The policy of the peer can't be queried, so
+// these are reported as warnings.
+func (r *openChannelReport) checkAcceptorPolicy(req *funding.InitFundingMsg) {
+	switch {
+	case hasChannelTypeBit(req, lnwire.ZeroConfRequired):
+		r.warn(checkAcceptorPolicy, "zero-conf "+
+			"channels are only accepted if the peer explicitly "+
+			"trusts us through its channel acceptor")
+
+	default:
+		r.pass(checkAcceptorPolicy, "no known "+
+			"restrictions, the channel acceptor of the peer may "+
+			"still reject the channel")
+	}
+}
@@ -0,0 +1,206 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// checkStatus returns the status of the check with the given name.
+func checkStatus(t *testing.T, report *openChannelReport,
+	name string) lnrpc.OpenChannelCheck_CheckStatus {
+
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check.Status
+		}
+	}
+
+	t.Fatalf("check %v not found", name)
+
+	return 0
+}
+
+// TestCheckChannelFeatures tests that the requested channel is checked
+// against the features of the peer.
+func TestCheckChannelFeatures(t *testing.T) {
+	t.Parallel()
+
+	features := func(bits ...lnwire.FeatureBit) *lnwire.FeatureVector {
+		return lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(bits...), lnwire.Features,
+		)
+	}
+	local := features(
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+		lnwire.ExplicitChannelTypeOptional,
+		lnwire.UpfrontShutdownScriptOptional,
+		lnwire.WumboChannelsOptional,
+	)
+	anchorType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+
+	// A peer supporting everything passes all checks.
+	report := newOpenChannelReport()
+	commitType := report.checkChannelFeatures(&funding.InitFundingMsg{
+		LocalFundingAmt: funding.MaxBtcFundingAmount + 1,
+		ChannelType:     &anchorType,
+		ShutdownScript:  []byte{1},
+	}, local, local)
+	require.True(t, commitType.HasAnchors())
+	require.Equal(
+		t, lnrpc.CommitmentType_ANCHORS, report.CommitmentType,
+	)
+	for _, check := range report.Checks {
+		require.Equal(
+			t, lnrpc.OpenChannelCheck_PASS, check.Status,
+			check.Name,
+		)
+	}
+	require.True(t, report.finish().CanOpen)
+
+	// A legacy peer supports neither the channel type, nor the close
+	// address, nor the channel size.
+	legacy := features(lnwire.StaticRemoteKeyOptional)
+	report = newOpenChannelReport()
+	report.checkChannelFeatures(&funding.InitFundingMsg{
+		LocalFundingAmt: funding.MaxBtcFundingAmount + 1,
+		ChannelType:     &anchorType,
+		ShutdownScript:  []byte{1},
+	}, local, legacy)
+	require.Equal(
+		t, lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE,
+		report.CommitmentType,
+	)
+	require.Equal(
+		t, lnrpc.OpenChannelCheck_FAIL,
+		checkStatus(t, report, checkChannelType),
+	)
+	require.Equal(
+		t, lnrpc.OpenChannelCheck_FAIL,
+		checkStatus(t, report, checkUpfrontShutdown),
+	)
+	require.Equal(
+		t, lnrpc.OpenChannelCheck_FAIL,
+		checkStatus(t, report, checkChannelSize),
+	)
+	require.False(t, report.finish().CanOpen)
+
+	// With fundmax, the channel is capped at the maximum, so a large
+	// maximum is only a warning.
+	report = newOpenChannelReport()
+	report.checkChannelFeatures(&funding.InitFundingMsg{
+		FundUpToMaxAmt: funding.MaxBtcFundingAmount + 1,
+	}, local, legacy)
+	require.Equal(
+		t, lnrpc.OpenChannelCheck_WARN,
+		checkStatus(t, report, checkChannelSize),
+	)
+	require.True(t, report.finish().CanOpen)
+}
+
+// TestCheckWalletBalance tests that the wallet balance must cover the funding
+// amount, the funding fee and the wallet reserve.
+func TestCheckWalletBalance(t *testing.T) {
+	t.Parallel()
+
+	feeRate := chainfee.SatPerKWeight(1000)
+	coins := []*lnwallet.Utxo{
+		{AddressType: lnwallet.WitnessPubKey, Value: 100_000},
+		{AddressType: lnwallet.TaprootPubkey, Value: 500_000},
+	}
+
+	// The largest coin covers the channel, so only its fee is estimated.
+	req := &funding.InitFundingMsg{
+		LocalFundingAmt: 400_000,
+		FundingFeePerKw: feeRate,
+	}
+	report := newOpenChannelReport()
+	report.checkWalletBalance(req, coins, 10_000, false)
+	require.Equal(
+		t, lnrpc.OpenChannelCheck_PASS,
+		checkStatus(t, report, checkWalletBalance),
+	)
+
+	oneInputFee := estimateFundingFee(
+		coins[1:], 0, 0, feeRate, false, false,
+	)
+	require.EqualValues(t, oneInputFee, report.FundingFeeSat)
+	require.EqualValues(t, 10_000, report.WalletReserveSat)
+	require.EqualValues(t, 600_000, report.AvailableBalanceSat)
+	require.EqualValues(
+		t, 400_000+10_000+oneInputFee, report.RequiredBalanceSat,
+	)
+
+	// The reserve can make an otherwise sufficient balance insufficient.
+	req.LocalFundingAmt = 590_000
+	report = newOpenChannelReport()
+	report.checkWalletBalance(req, coins, 10_000, false)
+	require.Equal(
+		t, lnrpc.OpenChannelCheck_FAIL,
+		checkStatus(t, report, checkWalletBalance),
+	)
+	require.Greater(t, report.FundingFeeSat, int64(oneInputFee))
+	require.False(t, report.finish().CanOpen)
+
+	// With fundmax, all coins are spent and only the minimum amount is
+	// required.
+	req = &funding.InitFundingMsg{
+		FundUpToMaxAmt:  btcutil.Amount(1_000_000),
+		MinFundAmt:      funding.MinChanFundingSize,
+		FundingFeePerKw: feeRate,
+	}
+	report = newOpenChannelReport()
+	report.checkWalletBalance(req, coins, 0, true)
+	require.Equal(
+		t, lnrpc.OpenChannelCheck_PASS,
+		checkStatus(t, report, checkWalletBalance),
+	)
+	require.EqualValues(
+		t, estimateFundingFee(coins, 0, 0, feeRate, true, true),
+		report.FundingFeeSat,
+	)
+}
+
+// TestCheckPeerPolicy tests the checks of the pending channel limit and the
+// channel acceptor of the peer.
+func TestCheckPeerPolicy(t *testing.T) {
+	t.Parallel()
+
+	report := newOpenChannelReport()
+	report.checkPendingChannels(0)
+	report.checkAcceptorPolicy(&funding.InitFundingMsg{})
+	for _, check := range report.Checks {
+		require.Equal(
+			t, lnrpc.OpenChannelCheck_PASS, check.Status,
+			check.Name,
+		)
+	}
+
+	zeroConf := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.ZeroConfRequired,
+	))
+	report = newOpenChannelReport()
+	report.checkPendingChannels(1)
+	report.checkAcceptorPolicy(&funding.InitFundingMsg{
+		ChannelType: &zeroConf,
+	})
+	for _, check := range report.Checks {
+		require.Equal(
+			t, lnrpc.OpenChannelCheck_WARN, check.Status,
+			check.Name,
+		)
+	}
+
+	// Warnings don't prevent opening the channel.
+	require.True(t, report.finish().CanOpen)
+}
@@ -30,24 +30,29 @@ import (
 	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/holdfee"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/input"
-	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
+	"github.com/lightningnetwork/lnd/monitoring/tracing"
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -87,6 +92,33 @@ const (
 	// torTimeoutMultiplier is the scaling factor we use on network timeouts
 	// for Tor peers.
 	torTimeoutMultiplier = 3
+
+	// maxCoalescedMsgs is the maximum number of messages that are flushed
+	// to the wire together.
+	maxCoalescedMsgs = 32
+
+	// maxCoalescedBytes is the size of the buffered messages after which
+	// no further messages are coalesced before they are flushed.
+	maxCoalescedBytes = 16 * 1024
+
+	// peerStorageQuota is the maximum size of the blob that we are willing
+	// to store on behalf of a single peer. Larger blobs are ignored.
+	peerStorageQuota = 16 * 1024
+
+	// peerStorageWriteInterval is the minimum time between two writes of
+	// the blob that we store on behalf of a single peer. Blobs received
+	// more frequently are coalesced, and only the latest one is written
+	// once the interval has passed.
+	peerStorageWriteInterval = 5 * time.Second
+
+	// onionMessageRate is the number of onion messages per second that we
+	// process from a single peer. Onion messages beyond this rate are
+	// dropped.
+	onionMessageRate = 10
+
+	// onionMessageBurst is the number of onion messages that we process
+	// from a single peer in a burst before onionMessageRate applies.
+	onionMessageBurst = 50
 )
 
 var (
@@ -105,6 +137,16 @@ type outgoingMsg struct {
 	errChan  chan error // MUST be buffered.
 }
 
+// bufferedMsg is an outgoingMsg that has been buffered on the connection and
+// is waiting to be flushed to the wire.
+type bufferedMsg struct {
+	outgoingMsg
+
+	// endSpan must be called with the result of the write once the
+	// message was flushed.
+	endSpan func(error)
+}
+
 // newChannelMsg packages a channeldb.OpenChannel with a channel that allows
 // the receiver of the request to report when the channel creation process has
 // completed.
@@ -183,6 +225,18 @@ type Config struct {
 	// an htlc where we don't offer it anymore.
 	OutgoingCltvRejectDelta uint32
 
+	// ExtraCltvDelta is an optional function that returns the number of
+	// blocks added to the CLTV deltas the links require for forwards.
+	ExtraCltvDelta func() uint32
+
+	// HoldFeePolicy is an optional policy that requires a surcharge for
+	// forwards that may lock up liquidity for long.
+	HoldFeePolicy *holdfee.Policy
+
+	// InSafeMode is an optional function that returns true while the links
+	// don't accept any new HTLCs.
+	InSafeMode func() bool
+
 	// ChanActiveTimeout specifies the duration the peer will wait to request
 	// a channel reenable, beginning from the time the peer was started.
 	ChanActiveTimeout time.Duration
@@ -271,7 +325,7 @@ type Config struct {
 
 	// Invoices is passed to the ChannelLink on creation and handles all
 	// invoice-related logic.
-	Invoices *invoices.InvoiceRegistry
+	Invoices htlcswitch.InvoiceDatabase
 
 	// ChannelNotifier is used by the link to notify other sub-systems about
 	// channel-related events and by the Brontide to subscribe to
@@ -332,6 +386,29 @@ type Config struct {
 	// closure initiated by the remote peer.
 	CoopCloseTargetConfs uint32
 
+	// CoopCloseRbfBumpInterval is the interval at which the fee of an
+	// unconfirmed RBF coop close transaction that we initiated is bumped.
+	// If zero, the fee is only bumped manually.
+	CoopCloseRbfBumpInterval time.Duration
+
+	// CoopCloseRbfFeeRateIncrease is the percentage by which the fee rate
+	// of an RBF coop close transaction is increased on each scheduled fee
+	// bump.
+	CoopCloseRbfFeeRateIncrease uint32
+
+	// CoopCloseRbfMaxFeeRate, if non-zero, is the fee rate up to which an
+	// RBF coop close transaction is bumped on schedule.
+	CoopCloseRbfMaxFeeRate chainfee.SatPerKWeight
+
+	// FlappingPeer is true if the peer repeatedly disconnected from us
+	// recently. New forwards through its channels are paused once they're
+	// loaded, so their outstanding HTLCs can be resolved.
+	FlappingPeer bool
+
+	// FlapRecoveryPeriod is the duration a flapping peer must stay
+	// connected before forwards through its channels are resumed.
+	FlapRecoveryPeriod time.Duration
+
 	// ServerPubKey is the serialized, compressed public key of our lnd node.
 	// It is used to determine which policy (channel edge) to pass to the
 	// ChannelLink.
@@ -358,6 +435,27 @@ type Config struct {
 	// from the peer.
 	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
 
+	// StorePeerStorage persists the blob that the remote peer asked us to
+	// store on its behalf, replacing any previously stored blob.
+	StorePeerStorage func(peer route.Vertex, blob []byte) error
+
+	// FetchPeerStorage returns the latest blob that we store on behalf of
+	// the remote peer.
+	FetchPeerStorage func(peer route.Vertex) ([]byte, error)
+
+	// FetchOwnPeerStorage returns our own encrypted backup blob that we
+	// ask the remote peer to store on our behalf.
+	FetchOwnPeerStorage func(peer *btcec.PublicKey) ([]byte, error)
+
+	// HandlePeerStorageRetrieval is called whenever the remote peer hands
+	// back the blob that it stores on our behalf.
+	HandlePeerStorageRetrieval func(peer route.Vertex, blob []byte) error
+
+	// HandleOnionMessage is called whenever the peer sends us an onion
+	// message. If it is nil, onion messages are ignored.
+	HandleOnionMessage func(peer route.Vertex,
+		msg *lnwire.OnionMessage) error
+
 	// GetAliases is passed to created links so the Switch and link can be
 	// aware of the channel's aliases.
 	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
@@ -381,6 +479,15 @@ type Config struct {
 	// invalid.
 	DisallowRouteBlinding bool
 
+	// FeaturePolicy enforces the feature bits the peer must or must not
+	// set in its init message. If nil, only the features we require
+	// ourselves are enforced.
+	FeaturePolicy *feature.Policy
+
+	// InitRecords are the custom TLV records included in the init message
+	// we send to the peer. If nil, no custom records are sent.
+	InitRecords *InitRecords
+
 	// Quit is the server's quit channel. If this is closed, we halt operation.
 	Quit chan struct{}
 }
@@ -498,10 +605,48 @@ type Brontide struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// rbfBumpReqs is a channel that requests to bump the fee of an RBF
+	// coop close transaction are sent over.
+	rbfBumpReqs chan *rbfBumpReq
+
+	// rbfClosesConfirmed is a channel that the IDs of channels whose RBF
+	// coop close transaction confirmed are sent over, so their state
+	// machines can be removed from activeChanCloses.
+	rbfClosesConfirmed chan lnwire.ChannelID
+
+	// pausedForwards holds the channels of a flapping peer whose new
+	// outgoing forwards are paused until the peer stays connected for the
+	// FlapRecoveryPeriod.
+	pausedForwards map[lnwire.ChannelID]wire.OutPoint
+
 	// remoteFeatures is the feature vector received from the peer during
 	// the connection handshake.
 	remoteFeatures *lnwire.FeatureVector
 
+	// remoteInitRecords are the TLV records of the init message received
+	// from the peer, by type.
+	remoteInitRecords map[uint64][]byte
+
+	// peerStorageMtx guards the fields used to throttle the writes of the
+	// blob that we store on behalf of the peer.
+	peerStorageMtx sync.Mutex
+
+	// lastPeerStorageWrite is the time that we last wrote the blob that
+	// we store on behalf of the peer.
+	lastPeerStorageWrite time.Time
+
+	// pendingPeerStorage is the latest blob received from the peer that
+	// is waiting for the write interval to pass.
+	pendingPeerStorage []byte
+
+	// peerStorageTimer is the timer of the scheduled write of the pending
+	// blob. It is nil if no write is scheduled.
+	peerStorageTimer *time.Timer
+
+	// onionMsgLimiter limits the rate at which we process onion messages
+	// from the peer.
+	onionMsgLimiter *rate.Limiter
+
 	// resentChanSyncMsg is a set that keeps track of which channels we
 	// have re-sent channel reestablishment messages for. This is done to
 	// avoid getting into loop where both peers will respond to the other
@@ -550,10 +695,16 @@ func NewBrontide(cfg Config) *Brontide {
 		localCloseChanReqs: make(chan *htlcswitch.ChanClose),
 		linkFailures:       make(chan linkFailureReport),
 		chanCloseMsgs:      make(chan *closeMsg),
+		rbfBumpReqs:        make(chan *rbfBumpReq),
+		rbfClosesConfirmed: make(chan lnwire.ChannelID),
+		pausedForwards:     make(map[lnwire.ChannelID]wire.OutPoint),
 		resentChanSyncMsg:  make(map[lnwire.ChannelID]struct{}),
 		startReady:         make(chan struct{}),
 		quit:               make(chan struct{}),
 		log:                build.NewPrefixLog(logPrefix, peerLog),
+		onionMsgLimiter: rate.NewLimiter(
+			onionMessageRate, onionMessageBurst,
+		),
 	}
 
 	if cfg.Conn != nil && cfg.Conn.RemoteAddr() != nil {
@@ -784,6 +935,10 @@ func (p *Brontide) Start() error {
 	// announcements through their timestamps.
 	go p.maybeSendNodeAnn(activeChans)
 
+	// If we store a blob on behalf of the remote peer, we'll hand it back
+	// to them, and ask them to store our own backup blob in return.
+	go p.maybeExchangePeerStorage(activeChans)
+
 	return nil
 }
 
@@ -1112,12 +1267,87 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 				"switch: %v", chanPoint, err)
 		}
 
+		// If the peer is flapping, we'll stop forwarding new HTLCs
+		// through the channel so its outstanding HTLCs can be
+		// resolved while the peer is still connected. Channels that
+		// are shutting down already drain on their own.
+		if p.cfg.FlappingPeer && shutdownMsg.IsNone() {
+			p.pauseForwards(chanPoint, chanID)
+		}
+
 		p.activeChannels.Store(chanID, lnChan)
 	}
 
 	return msgs, nil
 }
 
+// pauseForwards disallows new outgoing HTLCs through the link of the given
+// channel until the flapping peer stays connected for the FlapRecoveryPeriod.
+//
+// NOTE: This MUST be called before the channelManager is started.
+func (p *Brontide) pauseForwards(chanPoint wire.OutPoint,
+	chanID lnwire.ChannelID) {
+
+	link := p.fetchLinkFromKeyAndCid(chanID)
+	if link == nil {
+		p.log.Warnf("Unable to pause forwards, link for ChannelPoint(%v) "+
+			"not found", chanPoint)
+
+		return
+	}
+
+	if !link.DisableAdds(htlcswitch.Outgoing) {
+		p.log.Warnf("Outgoing link adds already disabled: %v", chanID)
+
+		return
+	}
+
+	p.log.Infof("Peer is flapping, pausing forwards through "+
+		"ChannelPoint(%v)", chanPoint)
+
+	p.pausedForwards[chanID] = chanPoint
+
+	p.cfg.ChannelNotifier.NotifyChannelDrainingEvent(
+		chanPoint, channelnotifier.DrainReasonFlapping,
+	)
+	link.OnFlushedOnce(func() {
+		p.cfg.ChannelNotifier.NotifyChannelDrainedEvent(chanPoint)
+	})
+}
+
+// resumeForwards allows new outgoing HTLCs through the links of the channels
+// whose forwards were paused because the peer was flapping.
+func (p *Brontide) resumeForwards() {
+	for chanID, chanPoint := range p.pausedForwards {
+		// Channels that started to close in the meantime must keep
+		// on draining.
+		if _, ok := p.activeChanCloses[chanID]; ok {
+			continue
+		}
+
+		link := p.fetchLinkFromKeyAndCid(chanID)
+		if link == nil {
+			continue
+		}
+
+		if !link.EnableAdds(htlcswitch.Outgoing) {
+			p.log.Warnf("Outgoing link adds already enabled: %v",
+				chanID)
+
+			continue
+		}
+
+		p.log.Infof("Peer stopped flapping, resuming forwards "+
+			"through ChannelPoint(%v)", chanPoint)
+
+		p.cfg.ChannelNotifier.NotifyChannelForwardsResumedEvent(
+			chanPoint,
+		)
+	}
+
+	p.pausedForwards = make(map[lnwire.ChannelID]wire.OutPoint)
+}
+
 // addLink creates and adds a new ChannelLink from the specified channel.
 func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 	lnChan *lnwallet.LightningChannel,
@@ -1182,6 +1412,9 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		MinUpdateTimeout:        htlcswitch.DefaultMinLinkFeeUpdateTimeout,
 		MaxUpdateTimeout:        htlcswitch.DefaultMaxLinkFeeUpdateTimeout,
 		OutgoingCltvRejectDelta: p.cfg.OutgoingCltvRejectDelta,
+		ExtraCltvDelta:          p.cfg.ExtraCltvDelta,
+		HoldFeePolicy:           p.cfg.HoldFeePolicy,
+		InSafeMode:              p.cfg.InSafeMode,
 		TowerClient:             p.cfg.TowerClient,
 		MaxOutgoingCltvExpiry:   p.cfg.MaxOutgoingCltvExpiry,
 		MaxFeeAllocation:        p.cfg.MaxChannelFeeAllocation,
@@ -1239,6 +1472,65 @@ func (p *Brontide) maybeSendNodeAnn(channels []*channeldb.OpenChannel) {
 	}
 }
 
+// maybeExchangePeerStorage sends the remote peer the latest blob that we store
+// on its behalf, if any. If the remote peer offers to store data for us and we
+// have at least one channel with them, we'll also send them our own backup
+// blob.
+func (p *Brontide) maybeExchangePeerStorage(
+	channels []*channeldb.OpenChannel) {
+
+	if p.cfg.FetchPeerStorage != nil {
+		blob, err := p.cfg.FetchPeerStorage(p.cfg.PubKeyBytes)
+		switch {
+		case errors.Is(err, channeldb.ErrNoPeerBucket),
+			errors.Is(err, channeldb.ErrNoPeerStorage):
+
+		case err != nil:
+			p.log.Errorf("Unable to fetch peer storage: %v", err)
+
+		default:
+			retrieval := lnwire.NewPeerStorageRetrieval(blob)
+			err := p.SendMessageLazy(false, retrieval)
+			if err != nil {
+				p.log.Debugf("Unable to send peer storage "+
+					"retrieval: %v", err)
+			}
+		}
+	}
+
+	if len(channels) == 0 {
+		return
+	}
+
+	if err := p.SendPeerStorage(); err != nil {
+		p.log.Warnf("Unable to send peer storage: %v", err)
+	}
+}
+
+// SendPeerStorage sends the remote peer our latest backup blob to store on our
+// behalf, if the remote peer offers to store data for us. It should be called
+// whenever the set of channels that we have with the peer changes.
+func (p *Brontide) SendPeerStorage() error {
+	if p.cfg.FetchOwnPeerStorage == nil ||
+		!p.remoteFeatures.HasFeature(lnwire.ProvideStorageOptional) {
+
+		return nil
+	}
+
+	blob, err := p.cfg.FetchOwnPeerStorage(p.IdentityKey())
+	if err != nil {
+		return fmt.Errorf("unable to fetch own peer storage: %w", err)
+	}
+
+	if len(blob) > lnwire.MaxPeerStorageBytes {
+		return fmt.Errorf("own peer storage blob of %d bytes exceeds "+
+			"the maximum of %d bytes", len(blob),
+			lnwire.MaxPeerStorageBytes)
+	}
+
+	return p.SendMessageLazy(false, lnwire.NewPeerStorage(blob))
+}
+
 // WaitForDisconnect waits until the peer has disconnected. A peer may be
 // disconnected if the local or remote side terminates the connection, or an
 // irrecoverable protocol error has been encountered. This method will only
@@ -1641,9 +1933,33 @@ func newChanMsgStream(p *Brontide, cid lnwire.ChannelID) *msgStream {
 // channel announcements.
 func newDiscMsgStream(p *Brontide) *msgStream {
 	apply := func(msg lnwire.Message) {
-		// TODO(yy): `ProcessRemoteAnnouncement` returns an error chan
-		// and we need to process it.
-		p.cfg.AuthGossiper.ProcessRemoteAnnouncement(msg, p)
+		errChan := p.cfg.AuthGossiper.ProcessRemoteAnnouncement(msg, p)
+
+		// Applying a gossip filter sends the backlog within the new
+		// horizon, whose result we wait for in the background to not
+		// hold up the stream. The gossiper may hold back other
+		// messages until their dependencies arrive, so their result
+		// isn't guaranteed to ever be delivered.
+		//
+		// TODO(yy): process the results of all messages.
+		if _, ok := msg.(*lnwire.GossipTimestampRange); !ok {
+			return
+		}
+
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+
+			select {
+			case err := <-errChan:
+				if err != nil {
+					p.log.Errorf("Unable to apply gossip "+
+						"filter: %v", err)
+				}
+
+			case <-p.quit:
+			}
+		}()
 	}
 
 	return newMsgStream(
@@ -1758,7 +2074,8 @@ out:
 			*lnwire.AcceptChannel,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
-			*lnwire.ChannelReady:
+			*lnwire.ChannelReady,
+			*lnwire.AnnounceSignatures2:
 
 			p.cfg.FundingManager.ProcessFundingMsg(msg, p)
 
@@ -1775,6 +2092,20 @@ out:
 				break out
 			}
 
+		case *lnwire.ClosingComplete:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+
+		case *lnwire.ClosingSig:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+
 		case *lnwire.Warning:
 			targetChan = msg.ChanID
 			isLinkUpdate = p.handleWarningOrError(targetChan, msg)
@@ -1821,6 +2152,7 @@ out:
 
 		case *lnwire.ChannelUpdate,
 			*lnwire.ChannelAnnouncement,
+			*lnwire.ChannelAnnouncement2,
 			*lnwire.NodeAnnouncement,
 			*lnwire.AnnounceSignatures,
 			*lnwire.GossipTimestampRange,
@@ -1838,6 +2170,22 @@ out:
 				p.log.Errorf("%v", err)
 			}
 
+		case *lnwire.PeerStorage:
+			err := p.handlePeerStorage(msg)
+			if err != nil {
+				p.storeError(err)
+				p.log.Errorf("%v", err)
+			}
+
+		case *lnwire.PeerStorageRetrieval:
+			err := p.handlePeerStorageRetrieval(msg)
+			if err != nil {
+				p.log.Errorf("%v", err)
+			}
+
+		case *lnwire.OnionMessage:
+			p.handleOnionMessage(msg)
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -1873,6 +2221,119 @@ func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
 	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
 }
 
+// handleOnionMessage passes the given onion message on to the handler, unless
+// onion messages are disabled or the peer exceeds its rate limit. Failures to
+// process the message are only logged, since the message may have been
+// created by a node other than the peer.
+func (p *Brontide) handleOnionMessage(msg *lnwire.OnionMessage) {
+	if p.cfg.HandleOnionMessage == nil {
+		p.log.Debugf("Ignoring onion message, onion messages are " +
+			"disabled")
+
+		return
+	}
+
+	if !p.onionMsgLimiter.Allow() {
+		p.log.Debugf("Dropping onion message, rate limit exceeded")
+
+		return
+	}
+
+	err := p.cfg.HandleOnionMessage(p.cfg.PubKeyBytes, msg)
+	if err != nil {
+		p.log.Debugf("Unable to handle onion message: %v", err)
+	}
+}
+
+// handlePeerStorage persists the blob that the remote peer asked us to store on
+// its behalf. We only store blobs for peers that we have at least one active
+// channel with, and only if they fit within our per-peer quota.
+func (p *Brontide) handlePeerStorage(msg *lnwire.PeerStorage) error {
+	// If we don't offer to store data for our peers, we'll ignore the
+	// message.
+	if !p.cfg.Features.HasFeature(lnwire.ProvideStorageOptional) ||
+		p.cfg.StorePeerStorage == nil {
+
+		p.log.Debugf("Ignoring peer storage of %d bytes, storage not "+
+			"offered", len(msg.Blob))
+		return nil
+	}
+
+	// To make sure we can't be used as free storage, we only store data
+	// on behalf of peers that we have channels with.
+	if !p.hasActiveChannels() {
+		p.log.Debugf("Ignoring peer storage of %d bytes, no active "+
+			"channels with peer", len(msg.Blob))
+		return nil
+	}
+
+	if len(msg.Blob) > peerStorageQuota {
+		p.log.Warnf("Ignoring peer storage of %d bytes, exceeds quota "+
+			"of %d bytes", len(msg.Blob), peerStorageQuota)
+		return nil
+	}
+
+	p.peerStorageMtx.Lock()
+	defer p.peerStorageMtx.Unlock()
+
+	// If a write is already scheduled, we only replace the blob that it
+	// will write, as only the latest blob of the peer is of interest.
+	if p.peerStorageTimer != nil {
+		p.pendingPeerStorage = msg.Blob
+		return nil
+	}
+
+	// To make sure the peer can't exhaust our disk I/O, we'll delay the
+	// write if we wrote its blob too recently.
+	wait := peerStorageWriteInterval - time.Since(p.lastPeerStorageWrite)
+	if wait > 0 {
+		p.log.Debugf("Delaying peer storage write by %v", wait)
+
+		p.pendingPeerStorage = msg.Blob
+		p.peerStorageTimer = time.AfterFunc(wait, p.flushPeerStorage)
+
+		return nil
+	}
+
+	p.lastPeerStorageWrite = time.Now()
+
+	err := p.cfg.StorePeerStorage(p.cfg.PubKeyBytes, msg.Blob)
+	if err != nil {
+		return fmt.Errorf("unable to store peer storage: %w", err)
+	}
+
+	return nil
+}
+
+// flushPeerStorage writes the pending blob of the peer that was delayed to
+// respect the peer storage write interval.
+func (p *Brontide) flushPeerStorage() {
+	p.peerStorageMtx.Lock()
+	defer p.peerStorageMtx.Unlock()
+
+	blob := p.pendingPeerStorage
+	p.pendingPeerStorage = nil
+	p.peerStorageTimer = nil
+	p.lastPeerStorageWrite = time.Now()
+
+	err := p.cfg.StorePeerStorage(p.cfg.PubKeyBytes, blob)
+	if err != nil {
+		p.log.Errorf("Unable to store peer storage: %v", err)
+	}
+}
+
+// handlePeerStorageRetrieval hands the blob that the remote peer stores on our
+// behalf over to the configured handler.
+func (p *Brontide) handlePeerStorageRetrieval(
+	msg *lnwire.PeerStorageRetrieval) error {
+
+	if p.cfg.HandlePeerStorageRetrieval == nil {
+		return fmt.Errorf("no handler for peer storage retrieval")
+	}
+
+	return p.cfg.HandlePeerStorageRetrieval(p.cfg.PubKeyBytes, msg.Blob)
+}
+
 // isLoadedFromDisk returns true if the provided channel ID is loaded from
 // disk.
 //
@@ -1926,11 +2387,9 @@ func (p *Brontide) hasChannel(chanID lnwire.ChannelID) bool {
 	return ok
 }
 
-// storeError stores an error in our peer's buffer of recent errors with the
-// current timestamp. Errors are only stored if we have at least one active
-// channel with the peer to mitigate a dos vector where a peer costlessly
-// connects to us and spams us with errors.
-func (p *Brontide) storeError(err error) {
+// hasActiveChannels returns true if we have at least one active, non-pending
+// channel with the peer.
+func (p *Brontide) hasActiveChannels() bool {
 	var haveChannels bool
 
 	p.activeChannels.Range(func(_ lnwire.ChannelID,
@@ -1948,9 +2407,17 @@ func (p *Brontide) storeError(err error) {
 		return false
 	})
 
+	return haveChannels
+}
+
+// storeError stores an error in our peer's buffer of recent errors with the
+// current timestamp. Errors are only stored if we have at least one active
+// channel with the peer to mitigate a dos vector where a peer costlessly
+// connects to us and spams us with errors.
+func (p *Brontide) storeError(err error) {
 	// If we do not have any active channels with the peer, we do not store
 	// errors as a dos mitigation.
-	if !haveChannels {
+	if !p.hasActiveChannels() {
 		p.log.Trace("no channels with peer, not storing err")
 		return
 	}
@@ -1973,6 +2440,8 @@ func (p *Brontide) handleWarningOrError(chanID lnwire.ChannelID,
 		p.storeError(errMsg)
 	}
 
+	p.notifyRemoteFailure(chanID, msg)
+
 	switch {
 	// Connection wide messages should be forwarded to all channel links
 	// with this peer.
@@ -1998,6 +2467,43 @@ func (p *Brontide) handleWarningOrError(chanID lnwire.ChannelID,
 	}
 }
 
+// notifyRemoteFailure notifies subscribers of channel events about a warning
+// or error msg the peer sent for one of our active channels.
+func (p *Brontide) notifyRemoteFailure(chanID lnwire.ChannelID,
+	msg lnwire.Message) {
+
+	lnChan, ok := p.activeChannels.Load(chanID)
+	if !ok || lnChan == nil {
+		return
+	}
+
+	event := channelnotifier.ChannelFailureEvent{
+		Remote: true,
+	}
+	setCode := func(code lnwire.ErrorCode) {
+		event.Code = fn.Some(code)
+	}
+
+	switch msg := msg.(type) {
+	case *lnwire.Warning:
+		event.Warning = true
+		event.Message = msg.Warning()
+		msg.Code.WhenSomeV(setCode)
+
+	case *lnwire.Error:
+		event.Message = msg.Error()
+		msg.Code.WhenSomeV(setCode)
+
+	default:
+		return
+	}
+
+	chanPoint := lnChan.ChannelPoint()
+	event.ChannelPoint = &chanPoint
+
+	p.cfg.ChannelNotifier.NotifyChannelFailureEvent(event)
+}
+
 // messageSummary returns a human-readable string that summarizes a
 // incoming/outgoing message. Not all messages will have a summary, only those
 // which have additional data that can be informative at a glance.
@@ -2038,6 +2544,13 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, fee_sat=%v", msg.ChannelID,
 			msg.FeeSatoshis)
 
+	case *lnwire.ClosingComplete:
+		return fmt.Sprintf("chan_id=%v, fee_sat=%v, sequence=%v",
+			msg.ChannelID, msg.FeeSatoshis, msg.Sequence)
+
+	case *lnwire.ClosingSig:
+		return fmt.Sprintf("chan_id=%v", msg.ChannelID)
+
 	case *lnwire.UpdateAddHTLC:
 		var blindingPoint []byte
 		msg.BlindingPoint.WhenSome(
@@ -2140,6 +2653,12 @@ func messageSummary(msg lnwire.Message) string {
 
 	case *lnwire.Custom:
 		return fmt.Sprintf("type=%d", msg.Type)
+
+	case *lnwire.PeerStorage:
+		return fmt.Sprintf("blob_len=%d", len(msg.Blob))
+
+	case *lnwire.PeerStorageRetrieval:
+		return fmt.Sprintf("blob_len=%d", len(msg.Blob))
 	}
 
 	return fmt.Sprintf("unknown msg type=%T", msg)
@@ -2190,10 +2709,10 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 }
 
 // writeMessage writes and flushes the target lnwire.Message to the remote peer.
-// If the passed message is nil, this method will only try to flush an existing
-// message buffered on the connection. It is safe to call this method again
-// with a nil message iff a timeout error is returned. This will continue to
-// flush the pending message to the wire.
+// If the passed message is nil, this method will only try to flush the
+// messages already buffered on the connection. It is safe to call this method
+// again with a nil message iff a timeout error is returned. This will continue
+// to flush the pending messages to the wire.
 //
 // NOTE:
 // Besides its usage in Start, this function should not be used elsewhere
@@ -2201,46 +2720,43 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 // time, panics can occur because WriteMessage and Flush don't use any locking
 // internally.
 func (p *Brontide) writeMessage(msg lnwire.Message) error {
-	// Only log the message on the first attempt.
-	if msg != nil {
-		p.logWireMessage(msg, false)
+	// If the current message has already been serialized, encrypted, and
+	// buffered on the underlying connection we will skip straight to
+	// flushing it to the wire.
+	if msg == nil {
+		return p.flushMessages()
 	}
 
-	noiseConn := p.cfg.Conn
+	_, endSpan, err := p.bufferMessage(msg)
+	if err != nil {
+		endSpan(err)
+		return err
+	}
 
-	flushMsg := func() error {
-		// Ensure the write deadline is set before we attempt to send
-		// the message.
-		writeDeadline := time.Now().Add(
-			p.scaleTimeout(writeMessageTimeout),
-		)
-		err := noiseConn.SetWriteDeadline(writeDeadline)
-		if err != nil {
-			return err
-		}
+	err = p.flushMessages()
+	endSpan(err)
 
-		// Flush the pending message to the wire. If an error is
-		// encountered, e.g. write timeout, the number of bytes written
-		// so far will be returned.
-		n, err := noiseConn.Flush()
+	return err
+}
 
-		// Record the number of bytes written on the wire, if any.
-		if n > 0 {
-			atomic.AddUint64(&p.bytesSent, uint64(n))
-		}
+// bufferMessage serializes and encrypts the target lnwire.Message and buffers
+// the ciphertext on the connection without flushing it to the wire. The size
+// of the serialized message is returned, together with a function that must
+// be called with the result of the write once the message was flushed.
+//
+// NOTE: This method has the same concurrency restrictions as writeMessage.
+func (p *Brontide) bufferMessage(msg lnwire.Message) (int, func(error),
+	error) {
 
-		return err
-	}
+	p.logWireMessage(msg, false)
 
-	// If the current message has already been serialized, encrypted, and
-	// buffered on the underlying connection we will skip straight to
-	// flushing it to the wire.
-	if msg == nil {
-		return flushMsg()
-	}
+	// HTLC adds of our own payments are traced from here until they are
+	// flushed to the wire.
+	endSpan := p.traceHTLCWrite(msg)
 
-	// Otherwise, this is a new message. We'll acquire a write buffer to
-	// serialize the message and buffer the ciphertext on the connection.
+	// We'll acquire a write buffer to serialize the message and buffer the
+	// ciphertext on the connection.
+	var size int
 	err := p.cfg.WritePool.Submit(func(buf *bytes.Buffer) error {
 		// Using a buffer allocated by the write pool, encode the
 		// message directly into the buffer.
@@ -2248,18 +2764,107 @@ func (p *Brontide) writeMessage(msg lnwire.Message) error {
 		if writeErr != nil {
 			return writeErr
 		}
+		size = buf.Len()
 
 		// Finally, write the message itself in a single swoop. This
 		// will buffer the ciphertext on the underlying connection. We
 		// will defer flushing the message until the write pool has been
 		// released.
-		return noiseConn.WriteMessage(buf.Bytes())
+		return p.cfg.Conn.WriteMessage(buf.Bytes())
 	})
+
+	return size, endSpan, err
+}
+
+// flushMessages flushes all messages buffered on the connection to the wire.
+// It is safe to call this method again iff a timeout error is returned.
+func (p *Brontide) flushMessages() error {
+	noiseConn := p.cfg.Conn
+
+	// Ensure the write deadline is set before we attempt to send the
+	// messages.
+	writeDeadline := time.Now().Add(p.scaleTimeout(writeMessageTimeout))
+	err := noiseConn.SetWriteDeadline(writeDeadline)
 	if err != nil {
 		return err
 	}
 
-	return flushMsg()
+	// Flush the pending messages to the wire. If an error is encountered,
+	// e.g. write timeout, the number of bytes written so far will be
+	// returned.
+	n, err := noiseConn.Flush()
+
+	// Record the number of bytes written on the wire, if any.
+	if n > 0 {
+		atomic.AddUint64(&p.bytesSent, uint64(n))
+	}
+
+	return err
+}
+
+// coalesceMessages buffers the given message on the connection, followed by
+// any further messages that are already waiting in the send queue. This
+// allows small messages to be flushed to the wire together. Once the buffered
+// messages grow too large, no more messages are added, so a message of an
+// HTLC that arrives in the meantime doesn't need to wait for a large batch of
+// gossip messages to be written.
+//
+// NOTE: This method MUST only be called by the writeHandler.
+func (p *Brontide) coalesceMessages(outMsg outgoingMsg) ([]bufferedMsg,
+	error) {
+
+	var (
+		batch []bufferedMsg
+		size  int
+	)
+	for {
+		n, endSpan, err := p.bufferMessage(outMsg.msg)
+		batch = append(batch, bufferedMsg{
+			outgoingMsg: outMsg,
+			endSpan:     endSpan,
+		})
+		if err != nil {
+			return batch, err
+		}
+
+		size += n
+		if len(batch) >= maxCoalescedMsgs || size >= maxCoalescedBytes {
+			return batch, nil
+		}
+
+		// Only pick up messages that are already queued, we never
+		// delay the messages we already have.
+		select {
+		case outMsg = <-p.sendQueue:
+		default:
+			return batch, nil
+		}
+	}
+}
+
+// traceHTLCWrite starts a span for writing the given message if it adds an
+// HTLC of one of our own payments, so that the write is traced as part of the
+// payment. The returned function ends the span with the result of the write.
+func (p *Brontide) traceHTLCWrite(msg lnwire.Message) func(error) {
+	htlc, ok := msg.(*lnwire.UpdateAddHTLC)
+	if !ok {
+		return func(error) {}
+	}
+
+	ctx, ok := tracing.HTLCContext(htlc.PaymentHash)
+	if !ok {
+		return func(error) {}
+	}
+
+	_, span := tracing.StartSpan(
+		ctx, "peer.WriteMessage",
+		attribute.String("peer", p.String()),
+		attribute.Int64("htlc_id", int64(htlc.ID)),
+	)
+
+	return func(err error) {
+		tracing.EndSpan(span, err)
+	}
 }
 
 // writeHandler is a goroutine dedicated to reading messages off of an incoming
@@ -2284,15 +2889,23 @@ out:
 		select {
 		case outMsg := <-p.sendQueue:
 			// Record the time at which we first attempt to send the
-			// message.
+			// messages.
 			startTime := time.Now()
 
+			// Buffer the message together with any other messages
+			// that are ready to be sent, so they can be flushed to
+			// the socket at once.
+			batch, err := p.coalesceMessages(outMsg)
+			metrics.ObservePeerCoalescedMsgs(len(batch))
+
 		retry:
-			// Write out the message to the socket. If a timeout
+			// Write out the messages to the socket. If a timeout
 			// error is encountered, we will catch this and retry
 			// after backing off in case the remote peer is just
 			// slow to process messages from the wire.
-			err := p.writeMessage(outMsg.msg)
+			if err == nil {
+				err = p.flushMessages()
+			}
 			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
 				p.log.Debugf("Write timeout detected for "+
 					"peer, first write for message "+
@@ -2300,13 +2913,12 @@ out:
 					time.Since(startTime))
 
 				// If we received a timeout error, this implies
-				// that the message was buffered on the
+				// that the messages were buffered on the
 				// connection successfully and that a flush was
-				// attempted. We'll set the message to nil so
-				// that on a subsequent pass we only try to
-				// flush the buffered message, and forgo
-				// reserializing or reencrypting it.
-				outMsg.msg = nil
+				// attempted. On a subsequent pass we only try
+				// to flush the buffered messages, and forgo
+				// reserializing or reencrypting them.
+				err = nil
 
 				goto retry
 			}
@@ -2323,8 +2935,12 @@ out:
 
 			// If the peer requested a synchronous write, respond
 			// with the error.
-			if outMsg.errChan != nil {
-				outMsg.errChan <- err
+			for _, msg := range batch {
+				msg.endSpan(err)
+
+				if msg.errChan != nil {
+					msg.errChan <- err
+				}
 			}
 
 			if err != nil {
@@ -2365,6 +2981,24 @@ func (p *Brontide) queueHandler() {
 	// been queued. This predominately includes messages from the gossiper.
 	lazyMsgs := list.New()
 
+	// addMsg adds a new message to the queue matching its priority and
+	// records the resulting queue depth.
+	addMsg := func(msg outgoingMsg) {
+		if msg.priority {
+			priorityMsgs.PushBack(msg)
+			metrics.ObservePeerSendQueueDepth(
+				metrics.QueuePriority, priorityMsgs.Len(),
+			)
+
+			return
+		}
+
+		lazyMsgs.PushBack(msg)
+		metrics.ObservePeerSendQueueDepth(
+			metrics.QueueLazy, lazyMsgs.Len(),
+		)
+	}
+
 	for {
 		// Examine the front of the priority queue, if it is empty check
 		// the low priority queue.
@@ -2389,11 +3023,7 @@ func (p *Brontide) queueHandler() {
 					lazyMsgs.Remove(elem)
 				}
 			case msg := <-p.outgoingQueue:
-				if msg.priority {
-					priorityMsgs.PushBack(msg)
-				} else {
-					lazyMsgs.PushBack(msg)
-				}
+				addMsg(msg)
 			case <-p.quit:
 				return
 			}
@@ -2403,11 +3033,7 @@ func (p *Brontide) queueHandler() {
 			// into the queue from outside sub-systems.
 			select {
 			case msg := <-p.outgoingQueue:
-				if msg.priority {
-					priorityMsgs.PushBack(msg)
-				} else {
-					lazyMsgs.PushBack(msg)
-				}
+				addMsg(msg)
 			case <-p.quit:
 				return
 			}
@@ -2517,6 +3143,14 @@ func (p *Brontide) channelManager() {
 	// updates and broadcast them with the "disabled" flag unset.
 	reenableTimeout := time.After(p.cfg.ChanActiveTimeout)
 
+	// resumeForwardsTimeout will fire once the flapping peer stayed
+	// connected for the FlapRecoveryPeriod, after which we'll resume the
+	// forwards through its channels.
+	var resumeForwardsTimeout <-chan time.Time
+	if len(p.pausedForwards) > 0 {
+		resumeForwardsTimeout = time.After(p.cfg.FlapRecoveryPeriod)
+	}
+
 out:
 	for {
 		select {
@@ -2558,6 +3192,16 @@ out:
 		case closeMsg := <-p.chanCloseMsgs:
 			p.handleCloseMsg(closeMsg)
 
+		// We've received a request to bump the fee of an RBF coop
+		// close transaction, either on schedule or by the user.
+		case req := <-p.rbfBumpReqs:
+			req.err <- p.handleRbfBumpReq(req)
+
+		// The RBF coop close transaction of a channel confirmed, so we
+		// no longer need its state machine.
+		case chanID := <-p.rbfClosesConfirmed:
+			delete(p.activeChanCloses, chanID)
+
 		// The channel reannounce delay has elapsed, broadcast the
 		// reenabled channel updates to the network. This should only
 		// fire once, so we set the reenableTimeout channel to nil to
@@ -2586,6 +3230,13 @@ out:
 				p.channelEventClient.Cancel()
 			}
 
+		// The flapping peer stayed connected for the recovery period,
+		// so we'll resume the forwards through its channels. This only
+		// fires once, so we nil the channel afterwards.
+		case <-resumeForwardsTimeout:
+			p.resumeForwards()
+			resumeForwardsTimeout = nil
+
 		case <-p.quit:
 			// As, we've been signalled to exit, we'll reset all
 			// our active channel back to their default state.
@@ -2738,6 +3389,12 @@ func (p *Brontide) fetchActiveChanCloser(chanID lnwire.ChannelID) (
 
 	p.activeChanCloses[chanID] = chanCloser
 
+	// The remote party initiated the cooperative close.
+	p.recordCloseReason(
+		channel.State().FundingOutpoint, channeldb.CloseInitiatorRemote,
+		channeldb.CloseReasonPeerRequest, "",
+	)
+
 	return chanCloser, nil
 }
 
@@ -3029,8 +3686,12 @@ func (p *Brontide) createChanCloser(channel *lnwallet.LightningChannel,
 			Disconnect: func() error {
 				return p.cfg.DisconnectPeer(p.IdentityKey())
 			},
-			ChainParams: &p.cfg.Wallet.Cfg.NetParams,
-			Quit:        p.quit,
+			ChainParams:        &p.cfg.Wallet.Cfg.NetParams,
+			Quit:               p.quit,
+			PeerAlias:          p.alias(),
+			RbfCoopClose:       p.rbfCoopClose(channel),
+			RbfFeeRateIncrease: p.cfg.CoopCloseRbfFeeRateIncrease,
+			RbfMaxFeeRate:      p.cfg.CoopCloseRbfMaxFeeRate,
 		},
 		deliveryScript,
 		fee,
@@ -3161,6 +3822,27 @@ type linkFailureReport struct {
 	linkErr     htlcswitch.LinkFailureError
 }
 
+// recordCloseReason records who initiated the close of the given channel and
+// why. The reason is informational only, so failing to record it doesn't stop
+// the close.
+func (p *Brontide) recordCloseReason(chanPoint wire.OutPoint,
+	initiator channeldb.CloseInitiator, reason channeldb.CloseReason,
+	details string) {
+
+	err := p.cfg.ChannelDB.PutCloseReason(
+		&chanPoint, &channeldb.ChannelCloseReason{
+			Initiator: initiator,
+			Reason:    reason,
+			Details:   details,
+			Timestamp: time.Now(),
+		},
+	)
+	if err != nil {
+		p.log.Errorf("Unable to record close reason of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+	}
+}
+
 // handleLinkFailure processes a link failure report when a link in the switch
 // fails. It facilitates the removal of all channel state within the peer,
 // force closing the channel depending on severity, and sending the error
@@ -3185,6 +3867,12 @@ func (p *Brontide) handleLinkFailure(failure linkFailureReport) {
 	if failure.linkErr.FailureAction == htlcswitch.LinkFailureForceClose {
 		p.log.Warnf("Force closing link(%v)", failure.shortChanID)
 
+		p.recordCloseReason(
+			failure.chanPoint, channeldb.CloseInitiatorLocal,
+			channeldb.CloseReasonPeerBehavior,
+			failure.linkErr.Error(),
+		)
+
 		closeTx, err := p.cfg.ChainArb.ForceCloseContract(
 			failure.chanPoint,
 		)
@@ -3209,6 +3897,15 @@ func (p *Brontide) handleLinkFailure(failure linkFailureReport) {
 		}
 	}
 
+	p.cfg.ChannelNotifier.NotifyChannelFailureEvent(
+		channelnotifier.ChannelFailureEvent{
+			ChannelPoint: &failure.chanPoint,
+			Warning:      failure.linkErr.Warning,
+			Code:         failure.linkErr.WireCode(),
+			Message:      failure.linkErr.Error(),
+		},
+	)
+
 	// Send an error to the peer, why we failed the channel.
 	if failure.linkErr.ShouldSendToPeer() {
 		// If SendData is set, send it to the peer. If not, we'll use
@@ -3220,16 +3917,25 @@ func (p *Brontide) handleLinkFailure(failure linkFailureReport) {
 			data = failure.linkErr.SendData
 		}
 
+		// Attach the machine-readable reason of the failure, so the
+		// peer doesn't need to parse the error data.
+		var code lnwire.ErrorCodeRecord
+		failure.linkErr.WireCode().WhenSome(func(c lnwire.ErrorCode) {
+			code = lnwire.NewErrorCodeRecord(c)
+		})
+
 		var networkMsg lnwire.Message
 		if failure.linkErr.Warning {
 			networkMsg = &lnwire.Warning{
 				ChanID: failure.chanID,
 				Data:   data,
+				Code:   code,
 			}
 		} else {
 			networkMsg = &lnwire.Error{
 				ChanID: failure.chanID,
 				Data:   data,
+				Code:   code,
 			}
 		}
 
@@ -3280,9 +3986,12 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 	chanPoint := chanCloser.Channel().ChannelPoint()
 	p.WipeChannel(&chanPoint)
 
-	// Also clear the activeChanCloses map of this channel.
+	// Also clear the activeChanCloses map of this channel, unless the
+	// closing transaction may still be replaced by a fee bump.
 	cid := lnwire.NewChanIDFromOutPoint(chanPoint)
-	delete(p.activeChanCloses, cid)
+	if !chanCloser.RbfCoopClose() {
+		delete(p.activeChanCloses, cid)
+	}
 
 	// Next, we'll launch a goroutine which will request to be notified by
 	// the ChainNotifier once the closure transaction obtains a single
@@ -3315,6 +4024,18 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 		}
 	}
 
+	// The closing transaction of an RBF coop close may be replaced, so we
+	// wait for the funding output to be spent by any of its versions.
+	if chanCloser.RbfCoopClose() {
+		go p.monitorRbfCoopClose(
+			chanPoint, chanCloser.Channel().FundingTxOut().PkScript,
+			chanCloser.NegotiationHeight(), chanCloser.IsCloser(),
+			closeReq, errChan,
+		)
+
+		return
+	}
+
 	go WaitForChanToClose(chanCloser.NegotiationHeight(), notifier, errChan,
 		&chanPoint, &closingTxid, closingTx.TxOut[0].PkScript, func() {
 			// Respond to the local subsystem which requested the
@@ -3328,6 +4049,89 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 		})
 }
 
+// monitorRbfCoopClose waits until the RBF coop close transaction of a channel,
+// or any of its replacements, confirms by watching for the spend of the
+// funding output. If we initiated the close, the fee of the closing
+// transaction is bumped on schedule in the meantime.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (p *Brontide) monitorRbfCoopClose(chanPoint wire.OutPoint,
+	fundingPkScript []byte, heightHint uint32, isCloser bool,
+	closeReq *htlcswitch.ChanClose, errChan chan error) {
+
+	spendNtfn, err := p.cfg.ChainNotifier.RegisterSpendNtfn(
+		&chanPoint, fundingPkScript, heightHint,
+	)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	defer spendNtfn.Cancel()
+
+	var bumpTicks <-chan time.Time
+	if isCloser && p.cfg.CoopCloseRbfBumpInterval > 0 {
+		bumpTicker := ticker.New(p.cfg.CoopCloseRbfBumpInterval)
+		bumpTicker.Resume()
+		defer bumpTicker.Stop()
+
+		bumpTicks = bumpTicker.Ticks()
+	}
+
+	// Fee bumps require the connection to the peer, but we keep on waiting
+	// for the channel to close after the peer disconnected.
+	quit := p.quit
+	for {
+		select {
+		case <-bumpTicks:
+			err := p.BumpCoopCloseFee(chanPoint, 0)
+			switch {
+			case errors.Is(err, chancloser.ErrMaxRbfFeeReached):
+				p.log.Infof("Coop close tx of ChannelPoint(%v) "+
+					"pays the max fee, no longer bumping",
+					chanPoint)
+
+				bumpTicks = nil
+
+			case err != nil:
+				p.log.Warnf("Unable to bump fee of coop close "+
+					"tx of ChannelPoint(%v): %v", chanPoint,
+					err)
+			}
+
+		case spend, ok := <-spendNtfn.Spend:
+			// In the case that the ChainNotifier is shutting down,
+			// the notification channel will be closed.
+			if !ok {
+				return
+			}
+
+			closingTxid := spend.SpenderTxHash
+			peerLog.Infof("ChannelPoint(%v) is now closed by %v at "+
+				"height %v", chanPoint, closingTxid,
+				spend.SpendingHeight)
+
+			if closeReq != nil {
+				closeReq.Updates <- &ChannelCloseUpdate{
+					ClosingTxid: closingTxid[:],
+					Success:     true,
+				}
+			}
+
+			chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+			select {
+			case p.rbfClosesConfirmed <- chanID:
+			case <-quit:
+			}
+
+			return
+
+		case <-quit:
+			bumpTicks = nil
+			quit = nil
+		}
+	}
+}
+
 // WaitForChanToClose uses the passed notifier to wait until the channel has
 // been detected as closed on chain and then concludes by executing the
 // following actions: the channel point will be sent over the settleChan, and
@@ -3418,9 +4222,34 @@ func (p *Brontide) handleInitMsg(msg *lnwire.Init) error {
 		return fmt.Errorf("data loss protection required")
 	}
 
+	// Finally, we'll make sure they comply with the feature bits the
+	// operator requires or forbids.
+	err = p.cfg.FeaturePolicy.Check(p.cfg.PubKeyBytes, p.remoteFeatures)
+	if err != nil {
+		return fmt.Errorf("invalid remote features: %w", err)
+	}
+
+	// The TLV records of the init message are only made available to
+	// applications, so a malformed stream isn't a reason to disconnect.
+	p.remoteInitRecords, err = ParseInitRecords(msg.ExtraData)
+	if err != nil {
+		p.log.Warnf("Unable to parse init records: %v", err)
+	}
+
 	return nil
 }
 
+// RemoteInitRecords returns the TLV records of the init message received from
+// the peer, by type.
+func (p *Brontide) RemoteInitRecords() map[uint64][]byte {
+	records := make(map[uint64][]byte, len(p.remoteInitRecords))
+	for typ, value := range p.remoteInitRecords {
+		records[typ] = append([]byte(nil), value...)
+	}
+
+	return records
+}
+
 // LocalFeatures returns the set of global features that has been advertised by
 // the local node. This allows sub-systems that use this interface to gate their
 // behavior off the set of negotiated feature bits.
@@ -3478,6 +4307,13 @@ func (p *Brontide) sendInitMsg(legacyChan bool) error {
 		features.RawFeatureVector,
 	)
 
+	// Include the custom records registered by applications.
+	extraData, err := p.cfg.InitRecords.ExtraData()
+	if err != nil {
+		return fmt.Errorf("unable to encode init records: %w", err)
+	}
+	msg.ExtraData = extraData
+
 	return p.writeMessage(msg)
 }
 
@@ -3596,6 +4432,24 @@ func (p *Brontide) PubKey() [33]byte {
 	return p.cfg.PubKeyBytes
 }
 
+// alias returns the alias the remote peer announced, or an empty string if it
+// isn't known.
+func (p *Brontide) alias() string {
+	if p.cfg.ChannelGraph == nil {
+		return ""
+	}
+
+	node, err := p.cfg.ChannelGraph.FetchLightningNode(
+		nil, route.Vertex(p.PubKey()),
+	)
+	if err != nil {
+		p.log.Debugf("Unable to fetch alias: %v", err)
+		return ""
+	}
+
+	return node.Alias
+}
+
 // IdentityKey returns the public key of the remote peer.
 //
 // NOTE: Part of the lnpeer.Peer interface.
@@ -3733,15 +4587,30 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		errMsg := &lnwire.Error{
 			ChanID: msg.cid,
 			Data:   lnwire.ErrorData(err.Error()),
+			Code: lnwire.NewErrorCodeRecord(
+				lnwire.ErrorCodeCloseFailed,
+			),
 		}
 		p.queueMsg(errMsg, nil)
 		return
 	}
 
+	// The closing transaction of an RBF coop close may already have been
+	// broadcast, in which case the message belongs to a fee bump.
+	_, err = chanCloser.ClosingTx()
+	replacing := chanCloser.RbfCoopClose() && err == nil
+
 	handleErr := func(err error) {
 		err = fmt.Errorf("unable to process close msg: %w", err)
 		p.log.Error(err)
 
+		// If a fee bump of an RBF coop close failed, the closing
+		// transaction broadcast before remains valid, so we keep on
+		// waiting for it to confirm.
+		if replacing {
+			return
+		}
+
 		// As the negotiations failed, we'll reset the channel state machine to
 		// ensure we act to on-chain events as normal.
 		chanCloser.Channel().ResetState()
@@ -3770,6 +4639,20 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 			return
 		}
 
+		// Let the operators know the link of a channel the remote peer
+		// is shutting down will now resolve its outstanding HTLCs.
+		if link != nil && !chanCloser.LocallyInitiated() {
+			chanPoint := chanCloser.Channel().ChannelPoint()
+			p.cfg.ChannelNotifier.NotifyChannelDrainingEvent(
+				chanPoint, channelnotifier.DrainReasonShutdown,
+			)
+			link.OnFlushedOnce(func() {
+				p.cfg.ChannelNotifier.NotifyChannelDrainedEvent(
+					chanPoint,
+				)
+			})
+		}
+
 		oShutdown.WhenSome(func(msg lnwire.Shutdown) {
 			// If the link is nil it means we can immediately queue
 			// the Shutdown message since we don't have to wait for
@@ -3795,6 +4678,11 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		})
 
 		beginNegotiation := func() {
+			if chanCloser.RbfCoopClose() {
+				p.beginRbfNegotiation(chanCloser, handleErr)
+				return
+			}
+
 			oClosingSigned, err := chanCloser.BeginNegotiation()
 			if err != nil {
 				handleErr(err)
@@ -3830,6 +4718,23 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 			p.queueMsg(&msg, nil)
 		})
 
+	case *lnwire.ClosingComplete:
+		oClosingSig, err := chanCloser.ReceiveClosingComplete(*typed)
+		if err != nil {
+			handleErr(err)
+			return
+		}
+
+		oClosingSig.WhenSome(func(msg lnwire.ClosingSig) {
+			p.queueMsg(&msg, nil)
+		})
+
+	case *lnwire.ClosingSig:
+		if err := chanCloser.ReceiveClosingSig(*typed); err != nil {
+			handleErr(err)
+			return
+		}
+
 	default:
 		panic("impossible closeMsg type")
 	}
@@ -3840,12 +4745,117 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		return
 	}
 
+	// A replacement of an RBF coop close transaction needs no further
+	// action, as we're already waiting for the channel to close.
+	if replacing {
+		p.log.Infof("Replaced coop close tx of ChannelPoint(%v)",
+			chanCloser.Channel().ChannelPoint())
+
+		return
+	}
+
 	// Otherwise, we've agreed on a closing fee! In this case, we'll wrap up
 	// the channel closure by notifying relevant sub-systems and launching a
 	// goroutine to wait for close tx conf.
 	p.finalizeChanClosure(chanCloser)
 }
 
+// beginRbfNegotiation begins the RBF coop close negotiation once the channel
+// is flushed. If the closee already received the proposal of the closer, the
+// closing transaction is broadcast right away, so the closure is finalized.
+func (p *Brontide) beginRbfNegotiation(chanCloser *chancloser.ChanCloser,
+	handleErr func(error)) {
+
+	oMsg, err := chanCloser.BeginRbfNegotiation()
+	if err != nil {
+		handleErr(err)
+		return
+	}
+
+	oMsg.WhenSome(func(msg lnwire.Message) {
+		p.queueMsg(msg, nil)
+	})
+
+	if _, err := chanCloser.ClosingTx(); err == nil {
+		p.finalizeChanClosure(chanCloser)
+	}
+}
+
+// rbfCoopClose returns true if the channel is to be closed using the RBF coop
+// close protocol, which requires both us and the peer to signal support for
+// it. Taproot channels are closed with the legacy fee negotiation, as their
+// musig2 closing nonces are tied to it.
+func (p *Brontide) rbfCoopClose(channel *lnwallet.LightningChannel) bool {
+	if channel.ChanType().IsTaproot() || p.cfg.Features == nil ||
+		p.remoteFeatures == nil {
+
+		return false
+	}
+
+	return p.cfg.Features.HasFeature(lnwire.RbfCoopCloseOptional) &&
+		p.remoteFeatures.HasFeature(lnwire.RbfCoopCloseOptional)
+}
+
+// rbfBumpReq is a request to bump the fee of the RBF coop close transaction of
+// a channel.
+type rbfBumpReq struct {
+	// chanID is the ID of the channel being closed.
+	chanID lnwire.ChannelID
+
+	// feeRate is the fee rate of the replacement closing transaction. If
+	// zero, the fee rate is increased according to the fee escalation
+	// schedule.
+	feeRate chainfee.SatPerKWeight
+
+	// err is used to report the result of the request.
+	err chan error
+}
+
+// BumpCoopCloseFee bumps the fee of the RBF coop close transaction of the
+// given channel, which must have been initiated by us. If the fee rate is zero,
+// it is increased according to the fee escalation schedule.
+func (p *Brontide) BumpCoopCloseFee(chanPoint wire.OutPoint,
+	feeRate chainfee.SatPerKWeight) error {
+
+	req := &rbfBumpReq{
+		chanID:  lnwire.NewChanIDFromOutPoint(chanPoint),
+		feeRate: feeRate,
+		err:     make(chan error, 1),
+	}
+
+	select {
+	case p.rbfBumpReqs <- req:
+	case <-p.quit:
+		return lnpeer.ErrPeerExiting
+	}
+
+	select {
+	case err := <-req.err:
+		return err
+	case <-p.quit:
+		return lnpeer.ErrPeerExiting
+	}
+}
+
+// handleRbfBumpReq proposes a replacement for the RBF coop close transaction
+// of the requested channel to the peer.
+func (p *Brontide) handleRbfBumpReq(req *rbfBumpReq) error {
+	chanCloser, ok := p.activeChanCloses[req.chanID]
+	if !ok {
+		return fmt.Errorf("no cooperative close of ChannelID(%v) in "+
+			"progress", req.chanID)
+	}
+
+	closingComplete, err := chanCloser.BumpFee(req.feeRate)
+	if err != nil {
+		return err
+	}
+
+	p.queueMsg(closingComplete, nil)
+
+	return nil
+}
+
 // HandleLocalCloseChanReqs accepts a *htlcswitch.ChanClose and passes it onto
 // the channelManager goroutine, which will shut down the link and possibly
 // close the channel.
@@ -3,6 +3,7 @@ package peer
 import (
 	"bytes"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/htlcswitch"
@@ -19,6 +21,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1412,3 +1415,312 @@ func TestRemovePendingChannel(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+// TestHandlePeerStorage asserts that we only store a peer's blob if we offer
+// storage, have an active channel with the peer and the blob fits within our
+// quota.
+func TestHandlePeerStorage(t *testing.T) {
+	t.Parallel()
+
+	offered := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.ProvideStorageOptional),
+		lnwire.Features,
+	)
+	notOffered := lnwire.NewFeatureVector(nil, lnwire.Features)
+
+	testCases := []struct {
+		name          string
+		features      *lnwire.FeatureVector
+		activeChannel bool
+		blobSize      int
+		expectStored  bool
+	}{
+		{
+			name:          "blob stored",
+			features:      offered,
+			activeChannel: true,
+			blobSize:      100,
+			expectStored:  true,
+		},
+		{
+			name:          "storage not offered",
+			features:      notOffered,
+			activeChannel: true,
+			blobSize:      100,
+			expectStored:  false,
+		},
+		{
+			name:          "no active channel",
+			features:      offered,
+			activeChannel: false,
+			blobSize:      100,
+			expectStored:  false,
+		},
+		{
+			name:          "blob exceeds quota",
+			features:      offered,
+			activeChannel: true,
+			blobSize:      peerStorageQuota + 1,
+			expectStored:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stored []byte
+			peer := NewBrontide(Config{
+				Features: tc.features,
+				StorePeerStorage: func(_ route.Vertex,
+					blob []byte) error {
+
+					stored = blob
+					return nil
+				},
+			})
+
+			// Pending channels don't count as active channels.
+			peer.activeChannels.Store(lnwire.ChannelID{0}, nil)
+			if tc.activeChannel {
+				peer.activeChannels.Store(
+					lnwire.ChannelID{1},
+					&lnwallet.LightningChannel{},
+				)
+			}
+
+			blob := make([]byte, tc.blobSize)
+			msg := lnwire.NewPeerStorage(blob)
+			require.NoError(t, peer.handlePeerStorage(msg))
+
+			if tc.expectStored {
+				require.Equal(t, blob, stored)
+			} else {
+				require.Nil(t, stored)
+			}
+		})
+	}
+}
+
+// TestHandlePeerStorageThrottle asserts that blobs received within the peer
+// storage write interval are coalesced into a single delayed write of the
+// latest blob.
+func TestHandlePeerStorageThrottle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		writes [][]byte
+	)
+	peer := NewBrontide(Config{
+		Features: lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(
+				lnwire.ProvideStorageOptional,
+			),
+			lnwire.Features,
+		),
+		StorePeerStorage: func(_ route.Vertex, blob []byte) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			writes = append(writes, blob)
+			return nil
+		},
+	})
+	peer.activeChannels.Store(
+		lnwire.ChannelID{1}, &lnwallet.LightningChannel{},
+	)
+
+	numWrites := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(writes)
+	}
+
+	// The first blob is written right away.
+	msg := lnwire.NewPeerStorage([]byte{1})
+	require.NoError(t, peer.handlePeerStorage(msg))
+	require.Equal(t, 1, numWrites())
+
+	// Pretend that the interval has almost passed, so that the delayed
+	// write happens shortly.
+	peer.peerStorageMtx.Lock()
+	peer.lastPeerStorageWrite = time.Now().Add(
+		-peerStorageWriteInterval + 100*time.Millisecond,
+	)
+	peer.peerStorageMtx.Unlock()
+
+	// The next blobs are delayed, and only the latest one is written.
+	msg = lnwire.NewPeerStorage([]byte{2})
+	require.NoError(t, peer.handlePeerStorage(msg))
+	msg = lnwire.NewPeerStorage([]byte{3})
+	require.NoError(t, peer.handlePeerStorage(msg))
+	require.Equal(t, 1, numWrites())
+
+	require.Eventually(t, func() bool {
+		return numWrites() == 2
+	}, wait.DefaultTimeout, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, []byte{3}, writes[1])
+	mu.Unlock()
+}
+
+// TestPauseForwards tests that the forwards through the channels of a flapping
+// peer are paused and resumed, and that the draining events are sent.
+func TestPauseForwards(t *testing.T) {
+	t.Parallel()
+
+	harness, err := createTestPeerWithChannel(t, noUpdate)
+	require.NoError(t, err, "unable to create test channels")
+
+	chanPoint := harness.channel.ChannelPoint()
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+
+	mockLink := newMockUpdateHandler(chanID)
+	harness.mockSwitch.links = append(harness.mockSwitch.links, mockLink)
+
+	// We use a peer whose channelManager isn't running, so we can drive
+	// the pausing and resuming of forwards ourselves.
+	cfg := harness.peer.cfg
+	cfg.FlappingPeer = true
+	p := NewBrontide(cfg)
+
+	sub, err := cfg.ChannelNotifier.SubscribeChannelEvents()
+	require.NoError(t, err)
+	t.Cleanup(sub.Cancel)
+
+	expectEvent := func(expected interface{}) {
+		t.Helper()
+
+		select {
+		case event := <-sub.Updates():
+			require.Equal(t, expected, event)
+		case <-time.After(timeout):
+			t.Fatalf("did not receive %T", expected)
+		}
+	}
+
+	// Pausing the forwards disables the outgoing adds of the link, and
+	// notifies that the channel is draining. As the mock link is clean,
+	// the channel is drained right away.
+	p.pauseForwards(chanPoint, chanID)
+	require.True(t, mockLink.IsFlushing(htlcswitch.Outgoing))
+	require.False(t, mockLink.IsFlushing(htlcswitch.Incoming))
+
+	expectEvent(channelnotifier.ChannelDrainingEvent{
+		ChannelPoint: &chanPoint,
+		Reason:       channelnotifier.DrainReasonFlapping,
+	})
+	expectEvent(channelnotifier.ChannelDrainedEvent{
+		ChannelPoint: &chanPoint,
+	})
+
+	// A channel that started to close keeps on draining.
+	p.activeChanCloses[chanID] = nil
+	p.resumeForwards()
+	require.True(t, mockLink.IsFlushing(htlcswitch.Outgoing))
+	require.Empty(t, p.pausedForwards)
+
+	// Otherwise, resuming the forwards enables the outgoing adds again.
+	delete(p.activeChanCloses, chanID)
+	require.True(t, mockLink.EnableAdds(htlcswitch.Outgoing))
+	p.pauseForwards(chanPoint, chanID)
+	expectEvent(channelnotifier.ChannelDrainingEvent{
+		ChannelPoint: &chanPoint,
+		Reason:       channelnotifier.DrainReasonFlapping,
+	})
+	expectEvent(channelnotifier.ChannelDrainedEvent{
+		ChannelPoint: &chanPoint,
+	})
+
+	p.resumeForwards()
+	require.False(t, mockLink.IsFlushing(htlcswitch.Outgoing))
+	require.Empty(t, p.pausedForwards)
+
+	expectEvent(channelnotifier.ChannelForwardsResumedEvent{
+		ChannelPoint: &chanPoint,
+	})
+}
+
+// TestCoalesceMessages tests that small messages waiting in the send queue are
+// buffered together and flushed to the wire in a single write, while large
+// messages are flushed on their own.
+func TestCoalesceMessages(t *testing.T) {
+	t.Parallel()
+
+	params := createTestPeer(t)
+	p := params.peer
+
+	mockConn := newMockConn(t, 4)
+	p.cfg.Conn = mockConn
+
+	newPing := func(padding int) *lnwire.Ping {
+		return &lnwire.Ping{
+			NumPongBytes: 1,
+			PaddingBytes: make([]byte, padding),
+		}
+	}
+	serialize := func(msg lnwire.Message) []byte {
+		var b bytes.Buffer
+		_, err := lnwire.WriteMessage(&b, msg, 0)
+		require.NoError(t, err)
+
+		return b.Bytes()
+	}
+
+	// Queue up two more small messages behind the first one, which should
+	// all be written together.
+	small := []*lnwire.Ping{newPing(1), newPing(2), newPing(3)}
+	p.sendQueue = make(chan outgoingMsg, 2)
+	p.sendQueue <- outgoingMsg{msg: small[1]}
+	p.sendQueue <- outgoingMsg{msg: small[2]}
+
+	batch, err := p.coalesceMessages(outgoingMsg{msg: small[0]})
+	require.NoError(t, err)
+	require.Len(t, batch, len(small))
+	require.NoError(t, p.flushMessages())
+	require.Equal(t, 1, mockConn.flushes)
+
+	for _, msg := range small {
+		mockConn.assertWrite(serialize(msg))
+	}
+
+	// A large message isn't coalesced with the messages queued behind it.
+	large := newPing(maxCoalescedBytes)
+	p.sendQueue <- outgoingMsg{msg: small[0]}
+
+	batch, err = p.coalesceMessages(outgoingMsg{msg: large})
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	mockConn.assertWrite(serialize(large))
+	require.Len(t, p.sendQueue, 1)
+}
+
+// TestHandleOnionMessageRateLimit asserts that onion messages beyond the burst
+// of a peer are dropped.
+func TestHandleOnionMessageRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var handled int
+	peer := NewBrontide(Config{
+		HandleOnionMessage: func(route.Vertex,
+			*lnwire.OnionMessage) error {
+
+			handled++
+			return nil
+		},
+	})
+
+	for i := 0; i < onionMessageBurst*2; i++ {
+		peer.handleOnionMessage(&lnwire.OnionMessage{})
+	}
+
+	// The limiter may have refilled a few tokens while the messages were
+	// handled.
+	require.GreaterOrEqual(t, handled, onionMessageBurst)
+	require.Less(t, handled, onionMessageBurst*2)
+}
@@ -0,0 +1,168 @@
+package peer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// MaxInitRecordsSize is the maximum size in bytes of the encoded TLV
+	// stream of all registered init records. It keeps the init message
+	// well below the maximum message size.
+	MaxInitRecordsSize = 4096
+)
+
+var (
+	// ErrInitRecordType is returned when registering an init record with
+	// a type outside of the custom range, or with an even type.
+	ErrInitRecordType = fmt.Errorf("init record type must be odd and at "+
+		"least %d", record.CustomTypeStart)
+
+	// ErrInitRecordsTooLarge is returned when registering an init record
+	// would make the encoded init records exceed MaxInitRecordsSize.
+	ErrInitRecordsTooLarge = fmt.Errorf("init records exceed %d bytes",
+		MaxInitRecordsSize)
+
+	// ErrInitRecordNotFound is returned when unregistering an init record
+	// that isn't registered.
+	ErrInitRecordNotFound = errors.New("init record not registered")
+)
+
+// InitRecords holds the custom TLV records applications registered to be
+// included in the init message we send to peers. This allows applications to
+// negotiate their own protocols with peers, for example to identify an LSP,
+// without changes to the peer. Records only apply to connections established
+// after they were registered.
+//
+// Only odd types in the custom range can be registered: peers must fail the
+// connection on unknown even types, so registering them would disconnect
+// every peer that doesn't know the record.
+type InitRecords struct {
+	mu      sync.RWMutex
+	records map[uint64][]byte
+}
+
+// NewInitRecords creates an empty set of init records.
+func NewInitRecords() *InitRecords {
+	return &InitRecords{
+		records: make(map[uint64][]byte),
+	}
+}
+
+// encodeInitRecords encodes the records as a TLV stream.
+func encodeInitRecords(records map[uint64][]byte) ([]byte, error) {
+	stream, err := tlv.NewStream(tlv.MapToRecords(records)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Register adds the record with the given type and value to our init
+// message, replacing the value of an already registered record of the type.
+func (r *InitRecords) Register(typ uint64, value []byte) error {
+	if typ < record.CustomTypeStart || typ%2 == 0 {
+		return ErrInitRecordType
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records := make(map[uint64][]byte, len(r.records)+1)
+	for t, v := range r.records {
+		records[t] = v
+	}
+	records[typ] = append([]byte(nil), value...)
+
+	encoded, err := encodeInitRecords(records)
+	if err != nil {
+		return err
+	}
+	if len(encoded) > MaxInitRecordsSize {
+		return ErrInitRecordsTooLarge
+	}
+
+	r.records = records
+
+	return nil
+}
+
+// Unregister removes the record with the given type from our init message.
+func (r *InitRecords) Unregister(typ uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[typ]; !ok {
+		return ErrInitRecordNotFound
+	}
+
+	delete(r.records, typ)
+
+	return nil
+}
+
+// Records returns a copy of the registered records by type.
+func (r *InitRecords) Records() map[uint64][]byte {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make(map[uint64][]byte, len(r.records))
+	for typ, value := range r.records {
+		records[typ] = append([]byte(nil), value...)
+	}
+
+	return records
+}
+
+// ExtraData returns the registered records encoded as the extra data of an
+// init message. A nil set of init records results in empty extra data.
+func (r *InitRecords) ExtraData() (lnwire.ExtraOpaqueData, error) {
+	records := r.Records()
+	if len(records) == 0 {
+		return make([]byte, 0), nil
+	}
+
+	return encodeInitRecords(records)
+}
+
+// ParseInitRecords parses the extra data of a peer's init message into its
+// records by type. Records of all types are returned, including those defined
+// by the protocol.
+func ParseInitRecords(extraData lnwire.ExtraOpaqueData) (map[uint64][]byte,
+	error) {
+
+	stream, err := tlv.NewStream()
+	if err != nil {
+		return nil, err
+	}
+
+	typeMap, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(extraData),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[uint64][]byte, len(typeMap))
+	for typ, value := range typeMap {
+		records[uint64(typ)] = value
+	}
+
+	return records, nil
+}
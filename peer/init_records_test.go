@@ -0,0 +1,95 @@
+package peer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInitRecords tests registering custom init records and parsing them from
+// the extra data of an init message.
+func TestInitRecords(t *testing.T) {
+	t.Parallel()
+
+	records := NewInitRecords()
+
+	// Without records, the extra data is empty.
+	extraData, err := records.ExtraData()
+	require.NoError(t, err)
+	require.Empty(t, extraData)
+
+	// Only odd types in the custom range can be registered.
+	require.ErrorIs(t, records.Register(1, nil), ErrInitRecordType)
+	require.ErrorIs(t, records.Register(65536, nil), ErrInitRecordType)
+
+	require.NoError(t, records.Register(65537, []byte{1}))
+	require.NoError(t, records.Register(65539, []byte{2, 3}))
+
+	// Registering a type again replaces its value.
+	require.NoError(t, records.Register(65537, []byte{4}))
+
+	expected := map[uint64][]byte{
+		65537: {4},
+		65539: {2, 3},
+	}
+	require.Equal(t, expected, records.Records())
+
+	// The records must not exceed the maximum size together.
+	err = records.Register(65541, make([]byte, MaxInitRecordsSize))
+	require.ErrorIs(t, err, ErrInitRecordsTooLarge)
+	require.Equal(t, expected, records.Records())
+
+	// The encoded records are parsed back into the same records.
+	extraData, err = records.ExtraData()
+	require.NoError(t, err)
+	parsed, err := ParseInitRecords(extraData)
+	require.NoError(t, err)
+	require.Equal(t, expected, parsed)
+
+	require.NoError(t, records.Unregister(65537))
+	require.ErrorIs(t, records.Unregister(65537), ErrInitRecordNotFound)
+	require.Equal(t, map[uint64][]byte{65539: {2, 3}}, records.Records())
+
+	// A malformed TLV stream can't be parsed.
+	_, err = ParseInitRecords(lnwire.ExtraOpaqueData{0x01, 0x05})
+	require.Error(t, err)
+
+	// A nil set of records has no records.
+	var nilRecords *InitRecords
+	require.Nil(t, nilRecords.Records())
+}
+
+// TestSendInitRecords tests that the registered init records are sent in the
+// extra data of our init message.
+func TestSendInitRecords(t *testing.T) {
+	t.Parallel()
+
+	params := createTestPeer(t)
+
+	var (
+		p         = params.peer
+		mockConn  = params.mockConn
+		writePool = p.cfg.WritePool
+	)
+
+	p.cfg.InitRecords = NewInitRecords()
+	require.NoError(t, p.cfg.InitRecords.Register(65537, []byte{1, 2, 3}))
+
+	expectedInit := lnwire.NewInitMessage(
+		p.cfg.LegacyFeatures.RawFeatureVector,
+		p.cfg.Features.RawFeatureVector,
+	)
+	expectedInit.ExtraData = []byte{
+		0xfe, 0x00, 0x01, 0x00, 0x01, 0x03, 1, 2, 3,
+	}
+
+	var b bytes.Buffer
+	_, err := lnwire.WriteMessage(&b, expectedInit, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, p.sendInitMsg(false))
+	mockConn.assertWrite(b.Bytes())
+	require.NoError(t, writePool.Stop())
+}
@@ -422,6 +422,9 @@ type mockMessageConn struct {
 	// trigger on this counter if a data race exists.
 	writeRaceDetectingCounter int
 
+	// flushes is the number of times the connection was flushed.
+	flushes int
+
 	// readRaceDetectingCounter is incremented on any function call
 	// associated with reading from the connection. The race detector will
 	// trigger on this counter if a data race exists.
@@ -482,6 +485,7 @@ func (m *mockMessageConn) SetWriteDeadline(time.Time) error {
 // Flush mocks a message conn flush.
 func (m *mockMessageConn) Flush() (int, error) {
 	m.writeRaceDetectingCounter++
+	m.flushes++
 	return 0, nil
 }
 
@@ -489,6 +493,11 @@ func (m *mockMessageConn) Flush() (int, error) {
 // the bytes sent into the mock's writtenMessages channel.
 func (m *mockMessageConn) WriteMessage(msg []byte) error {
 	m.writeRaceDetectingCounter++
+
+	// The passed bytes are only valid until the write buffer is released,
+	// so we'll copy them like the real connection does by encrypting them.
+	msg = append([]byte(nil), msg...)
+
 	select {
 	case m.writtenMessages <- msg:
 	case <-time.After(timeout):
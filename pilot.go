@@ -10,6 +10,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/funding"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -124,8 +125,28 @@ func (c *chanController) OpenChannel(target *btcec.PublicKey,
 	}
 }
 
+// CloseChannel cooperatively closes the target channel at the fee rate
+// estimated for the confirmation target of the autopilot. This function should
+// un-block once the closing transaction has been broadcast.
 func (c *chanController) CloseChannel(chanPoint *wire.OutPoint) error {
-	return nil
+	feePerKw, err := c.server.cc.FeeEstimator.EstimateFeePerKW(
+		c.confTarget,
+	)
+	if err != nil {
+		return err
+	}
+
+	updateChan, errChan := c.server.htlcSwitch.CloseLink(
+		chanPoint, contractcourt.CloseRegular, feePerKw, 0, nil,
+	)
+	select {
+	case err := <-errChan:
+		return err
+	case <-updateChan:
+		return nil
+	case <-c.server.quit:
+		return nil
+	}
 }
 
 // A compile time assertion to ensure chanController meets the
@@ -142,9 +163,10 @@ func initAutoPilot(svr *server, cfg *lncfg.AutoPilot,
 
 	atplLog.Infof("Instantiating autopilot with active=%v, "+
 		"max_channels=%d, allocation=%f, min_chan_size=%d, "+
-		"max_chan_size=%d, private=%t, min_confs=%d, conf_target=%d",
-		cfg.Active, cfg.MaxChannels, cfg.Allocation, cfg.MinChannelSize,
-		cfg.MaxChannelSize, cfg.Private, cfg.MinConfs, cfg.ConfTarget)
+		"max_chan_size=%d, private=%t, min_confs=%d, conf_target=%d, "+
+		"max_pending_closes=%d", cfg.Active, cfg.MaxChannels,
+		cfg.Allocation, cfg.MinChannelSize, cfg.MaxChannelSize,
+		cfg.Private, cfg.MinConfs, cfg.ConfTarget, cfg.MaxPendingCloses)
 
 	// Set up the constraints the autopilot heuristics must adhere to.
 	atplConstraints := autopilot.NewConstraints(
@@ -185,8 +207,9 @@ func initAutoPilot(svr *server, cfg *lncfg.AutoPilot,
 				cfg.MinConfs, lnwallet.DefaultAccountName,
 			)
 		},
-		Graph:       autopilot.ChannelGraphFromDatabase(svr.graphDB),
-		Constraints: atplConstraints,
+		Graph:            autopilot.ChannelGraphFromDatabase(svr.graphDB),
+		Constraints:      atplConstraints,
+		MaxPendingCloses: cfg.MaxPendingCloses,
 		ConnectToPeer: func(target *btcec.PublicKey, addrs []net.Addr) (bool, error) {
 			// First, we'll check if we're already connected to the
 			// target peer. If we are, we can exit early. Otherwise,
@@ -274,6 +297,7 @@ func initAutoPilot(svr *server, cfg *lncfg.AutoPilot,
 					Node: autopilot.NewNodeID(
 						channel.IdentityPub,
 					),
+					ChanPoint: channel.FundingOutpoint,
 				}
 			}
 
@@ -289,9 +313,10 @@ func initAutoPilot(svr *server, cfg *lncfg.AutoPilot,
 
 			localCommit := channel.LocalCommitment
 			return &autopilot.LocalChannel{
-				ChanID:  channel.ShortChanID(),
-				Balance: localCommit.LocalBalance.ToSatoshis(),
-				Node:    autopilot.NewNodeID(channel.IdentityPub),
+				ChanID:    channel.ShortChanID(),
+				Balance:   localCommit.LocalBalance.ToSatoshis(),
+				Node:      autopilot.NewNodeID(channel.IdentityPub),
+				ChanPoint: chanPoint,
 			}, nil
 		},
 		SubscribeTransactions: svr.cc.Wallet.SubscribeTransactions,
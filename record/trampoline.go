@@ -0,0 +1,28 @@
+package record
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// OutgoingNodeIDOnionType is the type used in a trampoline onion to
+	// reference the public key of the next trampoline node.
+	OutgoingNodeIDOnionType tlv.Type = 66098
+
+	// TrampolineOnionType is the type used in the onion payload of the
+	// final hop of an outer route to carry the trampoline onion.
+	TrampolineOnionType tlv.Type = 66100
+)
+
+// NewOutgoingNodeIDRecord creates a tlv.Record that encodes the
+// outgoing_node_id (type 66098) for a trampoline onion payload.
+func NewOutgoingNodeIDRecord(nodeID **btcec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(OutgoingNodeIDOnionType, nodeID)
+}
+
+// NewTrampolineOnionRecord creates a tlv.Record that encodes the
+// trampoline_onion (type 66100) for an onion payload.
+func NewTrampolineOnionRecord(onion *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(TrampolineOnionType, onion)
+}
@@ -0,0 +1,106 @@
+package lnd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lncfg"
+)
+
+const (
+	// remoteDBCheckName is the name of the remote database health check.
+	remoteDBCheckName = "remote database"
+
+	// pgReplicationLagQuery returns the largest replay lag in seconds of
+	// the replicas of a postgres primary. The lag is NULL for database
+	// users that may not read the replication statistics, in which case
+	// no lag is reported.
+	pgReplicationLagQuery = "SELECT COALESCE(MAX(EXTRACT(EPOCH FROM " +
+		"replay_lag)), 0) FROM pg_stat_replication"
+)
+
+// remoteDBProbeBucket is the top level bucket read to probe the latency of the
+// database. It doesn't need to exist, as reading it makes a round trip to the
+// database in either case.
+var remoteDBProbeBucket = []byte("remote-db-health-probe")
+
+// remoteDBHealthCheck returns a health check that probes the latency of the
+// given database backend and, if a postgres connection is given, the
+// replication lag of its replicas.
+func remoteDBHealthCheck(cfg *lncfg.RemoteDBCheckConfig, db kvdb.Backend,
+	pgDB *sql.DB) func() error {
+
+	return func() error {
+		start := time.Now()
+		err := kvdb.View(db, func(tx kvdb.RTx) error {
+			_ = tx.ReadBucket(remoteDBProbeBucket)
+			return nil
+		}, func() {})
+		if err != nil {
+			return fmt.Errorf("unable to probe database: %w", err)
+		}
+
+		latency := time.Since(start)
+		if cfg.MaxLatency > 0 && latency > cfg.MaxLatency {
+			return fmt.Errorf("database latency of %v exceeds "+
+				"maximum of %v", latency, cfg.MaxLatency)
+		}
+
+		if pgDB == nil || cfg.MaxReplicationLag == 0 {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(
+			context.Background(), cfg.Timeout,
+		)
+		defer cancel()
+
+		var lagSeconds float64
+		err = pgDB.QueryRowContext(ctx, pgReplicationLagQuery).Scan(
+			&lagSeconds,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to query replication lag: %w",
+				err)
+		}
+
+		lag := time.Duration(lagSeconds * float64(time.Second))
+		if lag > cfg.MaxReplicationLag {
+			return fmt.Errorf("database replication lag of %v "+
+				"exceeds maximum of %v", lag,
+				cfg.MaxReplicationLag)
+		}
+
+		return nil
+	}
+}
+
+// newRemoteDBHealthCheck returns the health check of the remote database
+// backend, or nil if the check is disabled or the backend isn't remote.
+func newRemoteDBHealthCheck(cfg *Config, dbs *DatabaseInstances) func() error {
+	checkCfg := cfg.HealthChecks.RemoteDB
+	if checkCfg.Attempts == 0 {
+		return nil
+	}
+
+	var pgDB *sql.DB
+	switch cfg.DB.Backend {
+	case lncfg.PostgresBackend:
+		if dbs.NativeSQLStore != nil {
+			pgDB = dbs.NativeSQLStore.DB
+		}
+
+	case lncfg.EtcdBackend:
+
+	default:
+		srvrLog.Infof("Disabling remote database health check for "+
+			"%v backend", cfg.DB.Backend)
+
+		return nil
+	}
+
+	return remoteDBHealthCheck(checkCfg, dbs.ChanStateDB.Backend, pgDB)
+}
@@ -0,0 +1,43 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRemoteDBHealthCheck tests that the remote database health check fails
+// once probing the database takes longer than allowed.
+func TestRemoteDBHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	backend, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "health")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	cfg := &lncfg.RemoteDBCheckConfig{
+		MaxLatency: time.Minute,
+		CheckConfig: &lncfg.CheckConfig{
+			Timeout: time.Second,
+		},
+	}
+	check := remoteDBHealthCheck(cfg, backend, nil)
+	require.NoError(t, check())
+
+	// Without a postgres connection, the replication lag isn't checked.
+	cfg.MaxReplicationLag = time.Nanosecond
+	require.NoError(t, check())
+
+	// A probe can't be faster than a nanosecond.
+	cfg.MaxLatency = time.Nanosecond
+	require.ErrorContains(t, check(), "database latency")
+
+	// Once the database is closed, the probe fails.
+	cfg.MaxLatency = 0
+	require.NoError(t, check())
+	require.NoError(t, backend.Close())
+	require.ErrorContains(t, check(), "unable to probe database")
+}
@@ -5,11 +5,15 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 // ValidateChannelAnn validates the channel announcement message and checks
@@ -188,3 +192,171 @@ func ValidateChannelUpdateFields(capacity btcutil.Amount,
 
 	return nil
 }
+
+// ValidateChannelAnn2 validates the taproot channel announcement message. It
+// checks that the attached Schnorr signature is a valid signature of the MuSig2
+// aggregate of the node keys and (if present) the bitcoin keys over the
+// message digest, and that the given funding pkScript matches the taproot
+// output that is committed to by the announced keys.
+func ValidateChannelAnn2(a *lnwire.ChannelAnnouncement2,
+	fundingPkScript []byte) error {
+
+	nodeKey1, err := btcec.ParsePubKey(a.NodeID1.Val[:])
+	if err != nil {
+		return err
+	}
+	nodeKey2, err := btcec.ParsePubKey(a.NodeID2.Val[:])
+	if err != nil {
+		return err
+	}
+
+	// The signing keys always include both node keys. If the bitcoin keys
+	// are set, then they are used to construct the funding output and
+	// also take part in the signature.
+	signingKeys := []*btcec.PublicKey{nodeKey1, nodeKey2}
+	fundingKey1, fundingKey2 := nodeKey1, nodeKey2
+
+	var btcKeyErr error
+	a.BitcoinKey1.WhenSomeV(func(key [33]byte) {
+		fundingKey1, btcKeyErr = btcec.ParsePubKey(key[:])
+	})
+	if btcKeyErr != nil {
+		return btcKeyErr
+	}
+	a.BitcoinKey2.WhenSomeV(func(key [33]byte) {
+		fundingKey2, btcKeyErr = btcec.ParsePubKey(key[:])
+	})
+	if btcKeyErr != nil {
+		return btcKeyErr
+	}
+
+	// Either both or none of the bitcoin keys must be present.
+	switch {
+	case a.BitcoinKey1.IsSome() && a.BitcoinKey2.IsSome():
+		signingKeys = append(signingKeys, fundingKey1, fundingKey2)
+
+	case a.BitcoinKey1.IsSome() || a.BitcoinKey2.IsSome():
+		return errors.New("only one bitcoin key set in channel " +
+			"announcement")
+	}
+
+	// Before verifying the signature, make sure that the funding output
+	// actually commits to the announced keys.
+	expectedPkScript, err := ChanAnn2FundingPkScript(
+		fundingKey1, fundingKey2, a.MerkleRootHash,
+	)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expectedPkScript, fundingPkScript) {
+		return fmt.Errorf("funding pkScript %x doesn't match keys "+
+			"of channel announcement, expected %x",
+			fundingPkScript, expectedPkScript)
+	}
+
+	// The keys are sorted before they are aggregated, so the signers
+	// don't need to agree on an order.
+	aggKey, _, _, err := musig2.AggregateKeys(signingKeys, true)
+	if err != nil {
+		return fmt.Errorf("unable to aggregate keys: %w", err)
+	}
+
+	digest, err := a.DigestToSign()
+	if err != nil {
+		return err
+	}
+
+	sig, err := schnorr.ParseSignature(a.Signature.RawBytes())
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(digest[:], aggKey.FinalKey) {
+		return errors.New("can't verify channel announcement " +
+			"signature")
+	}
+
+	return nil
+}
+
+// ChanAnn2FundingPkScript derives the pkScript of the taproot funding output
+// of a channel from the two funding keys. If a merkle root is given, then it
+// is used to tweak the aggregate key, otherwise a BIP86 tweak is applied.
+func ChanAnn2FundingPkScript(key1, key2 *btcec.PublicKey,
+	merkleRoot tlv.OptionalRecordT[tlv.TlvType16, [32]byte]) ([]byte,
+	error) {
+
+	tweak := musig2.WithBIP86KeyTweak()
+	merkleRoot.WhenSomeV(func(root [32]byte) {
+		tweak = musig2.WithTaprootKeyTweak(root[:])
+	})
+
+	aggKey, _, _, err := musig2.AggregateKeys(
+		[]*btcec.PublicKey{key1, key2}, true, tweak,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to combine keys: %w", err)
+	}
+
+	return input.PayToTaprootScript(aggKey.FinalKey)
+}
+
+// ValidateChannelUpdate2Ann validates the taproot channel update message by
+// checking (1) that the included Schnorr signature covers the update and has
+// been created by the node's private key, and (2) that the update's fields are
+// sane.
+func ValidateChannelUpdate2Ann(pubKey *btcec.PublicKey,
+	capacity btcutil.Amount, a *lnwire.ChannelUpdate2) error {
+
+	if err := ValidateChannelUpdate2Fields(capacity, a); err != nil {
+		return err
+	}
+
+	return VerifyChannelUpdate2Signature(a, pubKey)
+}
+
+// VerifyChannelUpdate2Signature verifies that the channel update message was
+// signed by the party with the given node public key.
+func VerifyChannelUpdate2Signature(msg *lnwire.ChannelUpdate2,
+	pubKey *btcec.PublicKey) error {
+
+	digest, err := msg.DigestToSign()
+	if err != nil {
+		return fmt.Errorf("unable to reconstruct message data: %w", err)
+	}
+
+	sig, err := schnorr.ParseSignature(msg.Signature.RawBytes())
+	if err != nil {
+		return err
+	}
+
+	if !sig.Verify(digest[:], pubKey) {
+		return fmt.Errorf("invalid signature for channel update %v",
+			spew.Sdump(msg))
+	}
+
+	return nil
+}
+
+// ValidateChannelUpdate2Fields validates the HTLC limits of a taproot channel
+// update.
+func ValidateChannelUpdate2Fields(capacity btcutil.Amount,
+	msg *lnwire.ChannelUpdate2) error {
+
+	maxHtlc := lnwire.MilliSatoshi(msg.HTLCMaximumMsat.Val)
+	minHtlc := lnwire.MilliSatoshi(msg.HTLCMinimumMsat.Val)
+	if maxHtlc == 0 || maxHtlc < minHtlc {
+		return errors.Errorf("invalid max htlc for channel "+
+			"update %v", spew.Sdump(msg))
+	}
+
+	// For light clients, the capacity will not be set so we'll skip
+	// checking whether the MaxHTLC value respects the channel's
+	// capacity.
+	capacityMsat := lnwire.NewMSatFromSatoshis(capacity)
+	if capacityMsat != 0 && maxHtlc > capacityMsat {
+		return errors.Errorf("max_htlc (%v) for channel update "+
+			"greater than capacity (%v)", maxHtlc, capacityMsat)
+	}
+
+	return nil
+}
@@ -80,6 +80,42 @@ func (b *BlindedPayment) Validate() error {
 	return nil
 }
 
+// targetNode returns the vertex that path finding should treat as the target
+// of a payment to the blinded path. For a blinded path that only contains the
+// introduction node, this is the unblinded introduction node, otherwise it is
+// the blinded node id of the final hop in the path.
+func (b *BlindedPayment) targetNode() route.Vertex {
+	hops := b.BlindedPath.BlindedHops
+	if len(hops) == 1 {
+		return route.NewVertex(b.BlindedPath.IntroductionPoint)
+	}
+
+	return route.NewVertex(hops[len(hops)-1].BlindedNodePub)
+}
+
+// finalCltvDelta returns the cltv delta that needs to be added for the final
+// hop of a route to the blinded path. If the path only contains the
+// introduction node, no route hints are produced for it, so the blinded
+// path's delta must be accounted for as the final cltv delta. Otherwise, the
+// delta is fully accounted for in the route hints and zero is returned.
+func (b *BlindedPayment) finalCltvDelta() uint16 {
+	if len(b.BlindedPath.BlindedHops) == 1 {
+		return b.CltvExpiryDelta
+	}
+
+	return 0
+}
+
+// aggregateFee returns the fee that the blinded portion of the route charges
+// for delivering amt to the receiver, based on the aggregated relay
+// parameters of the path.
+func (b *BlindedPayment) aggregateFee(
+	amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	return lnwire.MilliSatoshi(b.BaseFee) +
+		amt*lnwire.MilliSatoshi(b.ProportionalFeeRate)/1_000_000
+}
+
 // toRouteHints produces a set of chained route hints that represent a blinded
 // path. In the case of a single hop blinded route (which is paying directly
 // to the introduction point), no hints will be returned. In this case callers
@@ -0,0 +1,262 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+var (
+	// ErrNoKnownRouteHints is returned when removing the known route hints
+	// of a destination that has none.
+	ErrNoKnownRouteHints = errors.New("no known route hints for " +
+		"destination")
+)
+
+// HintScope defines which payments may use a known route hint.
+type HintScope uint8
+
+const (
+	// HintScopeDestination only allows payments to the destination the
+	// hints were registered for to use them.
+	HintScopeDestination HintScope = iota
+
+	// HintScopeAll allows any payment to route through the channels
+	// described by the hints. This should only be used for hints from a
+	// trusted source, as the hints of one destination then affect the
+	// routes of payments to other destinations.
+	HintScopeAll
+)
+
+// String returns a human-readable representation of the scope.
+func (s HintScope) String() string {
+	switch s {
+	case HintScopeDestination:
+		return "destination"
+
+	case HintScopeAll:
+		return "all"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// KnownRouteHint is a set of route hints for a private destination that was
+// registered out of band, for example for the clients of an LSP.
+type KnownRouteHint struct {
+	// Destination is the node the hints lead to.
+	Destination route.Vertex
+
+	// Hints are the route hints to the destination, in the same format
+	// as the route hints of an invoice.
+	Hints [][]zpay32.HopHint
+
+	// Scope defines which payments may use the hints.
+	Scope HintScope
+
+	// Expiry is the time after which the hints are no longer used.
+	Expiry time.Time
+}
+
+// KnownRouteHints holds route hints for private destinations that were
+// registered out of band. Path finding for our outgoing payments uses them in
+// addition to the route hints of the payment itself, which avoids failed first
+// attempts to private destinations whose invoices lack the hints.
+//
+// NOTE: This struct is safe for concurrent access.
+type KnownRouteHints struct {
+	clock clock.Clock
+
+	// hints holds the registered hints by destination.
+	hints map[route.Vertex]*KnownRouteHint
+
+	mu sync.Mutex
+}
+
+// NewKnownRouteHints creates a new, empty set of known route hints.
+func NewKnownRouteHints(clock clock.Clock) *KnownRouteHints {
+	return &KnownRouteHints{
+		clock: clock,
+		hints: make(map[route.Vertex]*KnownRouteHint),
+	}
+}
+
+// Add registers route hints for a destination that are used for the given time
+// to live. Any hints that were registered for the destination before are
+// replaced.
+func (k *KnownRouteHints) Add(dest route.Vertex, hints [][]zpay32.HopHint,
+	ttl time.Duration, scope HintScope) error {
+
+	if ttl <= 0 {
+		return fmt.Errorf("route hint ttl must be positive, got %v",
+			ttl)
+	}
+
+	if scope != HintScopeDestination && scope != HintScopeAll {
+		return fmt.Errorf("unknown route hint scope: %v", scope)
+	}
+
+	if len(hints) == 0 {
+		return errors.New("no route hints given")
+	}
+
+	for _, hint := range hints {
+		if len(hint) == 0 {
+			return errors.New("empty route hint")
+		}
+
+		for _, hop := range hint {
+			if hop.NodeID == nil {
+				return errors.New("route hint hop without " +
+					"node id")
+			}
+		}
+	}
+
+	// Make sure the hints can be converted to edges, so that invalid
+	// hints are rejected right away instead of failing payments.
+	if _, err := RouteHintsToEdges(hints, dest); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.hints[dest] = &KnownRouteHint{
+		Destination: dest,
+		Hints:       copyHopHints(hints),
+		Scope:       scope,
+		Expiry:      k.clock.Now().Add(ttl),
+	}
+
+	log.Debugf("Registered %d known route hints for %v with scope %v, "+
+		"ttl %v", len(hints), dest, scope, ttl)
+
+	return nil
+}
+
+// Remove removes the route hints registered for a destination.
+func (k *KnownRouteHints) Remove(dest route.Vertex) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.hints[dest]; !ok {
+		return ErrNoKnownRouteHints
+	}
+
+	delete(k.hints, dest)
+
+	return nil
+}
+
+// List returns the route hints that haven't expired yet, ordered by their
+// destination.
+func (k *KnownRouteHints) List() []*KnownRouteHint {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.pruneExpired()
+
+	hints := make([]*KnownRouteHint, 0, len(k.hints))
+	for _, hint := range k.hints {
+		hintCopy := *hint
+		hintCopy.Hints = copyHopHints(hint.Hints)
+		hints = append(hints, &hintCopy)
+	}
+
+	sort.Slice(hints, func(i, j int) bool {
+		return hints[i].Destination.String() <
+			hints[j].Destination.String()
+	})
+
+	return hints
+}
+
+// edges returns the edges of the known route hints that a payment to the
+// given target may use.
+func (k *KnownRouteHints) edges(target route.Vertex) (RouteHints, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.pruneExpired()
+
+	edges := make(RouteHints)
+	for dest, hint := range k.hints {
+		if dest != target && hint.Scope != HintScopeAll {
+			continue
+		}
+
+		hintEdges, err := RouteHintsToEdges(hint.Hints, dest)
+		if err != nil {
+			return nil, err
+		}
+
+		edges = mergeAdditionalEdges(edges, hintEdges)
+	}
+
+	return edges, nil
+}
+
+// pruneExpired removes the hints whose time to live elapsed.
+//
+// NOTE: The mutex must be held when calling this method.
+func (k *KnownRouteHints) pruneExpired() {
+	now := k.clock.Now()
+	for dest, hint := range k.hints {
+		if now.Before(hint.Expiry) {
+			continue
+		}
+
+		log.Debugf("Known route hints for %v expired", dest)
+		delete(k.hints, dest)
+	}
+}
+
+// mergeAdditionalEdges adds the edges of extra to edges, skipping the ones for
+// channels that edges already has an edge for from the same node. The merged
+// edges are returned.
+func mergeAdditionalEdges(edges, extra RouteHints) RouteHints {
+	if edges == nil {
+		edges = make(RouteHints)
+	}
+
+	for from, extraEdges := range extra {
+		known := make(map[uint64]struct{})
+		for _, edge := range edges[from] {
+			known[edge.EdgePolicy().ChannelID] = struct{}{}
+		}
+
+		for _, edge := range extraEdges {
+			chanID := edge.EdgePolicy().ChannelID
+			if _, ok := known[chanID]; ok {
+				continue
+			}
+
+			known[chanID] = struct{}{}
+			edges[from] = append(edges[from], edge)
+		}
+	}
+
+	return edges
+}
+
+// copyHopHints returns a deep copy of the given route hints.
+func copyHopHints(hints [][]zpay32.HopHint) [][]zpay32.HopHint {
+	hintsCopy := make([][]zpay32.HopHint, 0, len(hints))
+	for _, hint := range hints {
+		hops := make([]zpay32.HopHint, 0, len(hint))
+		for _, hop := range hint {
+			hops = append(hops, hop.Copy())
+		}
+		hintsCopy = append(hintsCopy, hops)
+	}
+
+	return hintsCopy
+}
@@ -0,0 +1,118 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// newKnownHintTestKey creates a new random public key.
+func newKnownHintTestKey(t *testing.T) *btcec.PublicKey {
+	t.Helper()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return priv.PubKey()
+}
+
+// TestKnownRouteHints tests that known route hints are only used for the
+// payments their scope allows, and that they expire.
+func TestKnownRouteHints(t *testing.T) {
+	t.Parallel()
+
+	const ttl = time.Hour
+
+	var (
+		lsp       = newKnownHintTestKey(t)
+		partner   = newKnownHintTestKey(t)
+		client    = route.NewVertex(newKnownHintTestKey(t))
+		trusted   = route.NewVertex(newKnownHintTestKey(t))
+		otherDest = route.NewVertex(newKnownHintTestKey(t))
+	)
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	known := NewKnownRouteHints(testClock)
+
+	// Invalid hints are rejected.
+	hints := [][]zpay32.HopHint{{{NodeID: lsp, ChannelID: 1}}}
+	require.Error(t, known.Add(client, hints, 0, HintScopeDestination))
+	require.Error(t, known.Add(client, nil, ttl, HintScopeDestination))
+	require.Error(t, known.Add(
+		client, [][]zpay32.HopHint{{{ChannelID: 1}}}, ttl,
+		HintScopeDestination,
+	))
+
+	require.NoError(t, known.Add(client, hints, ttl, HintScopeDestination))
+	require.NoError(t, known.Add(
+		trusted, [][]zpay32.HopHint{{{NodeID: partner, ChannelID: 2}}},
+		ttl, HintScopeAll,
+	))
+
+	// Payments to the client use both its own hints and the trusted
+	// hints, payments to other destinations only the trusted hints.
+	edges, err := known.edges(client)
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+	require.Len(t, edges[route.NewVertex(lsp)], 1)
+	require.Len(t, edges[route.NewVertex(partner)], 1)
+
+	edges, err = known.edges(otherDest)
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	require.Len(t, edges[route.NewVertex(partner)], 1)
+
+	require.Len(t, known.List(), 2)
+
+	// Removing hints of a destination without hints fails.
+	require.ErrorIs(t, known.Remove(otherDest), ErrNoKnownRouteHints)
+	require.NoError(t, known.Remove(trusted))
+
+	edges, err = known.edges(otherDest)
+	require.NoError(t, err)
+	require.Empty(t, edges)
+
+	// Once the ttl elapsed, the hints are no longer used.
+	testClock.SetTime(testClock.Now().Add(ttl))
+
+	edges, err = known.edges(client)
+	require.NoError(t, err)
+	require.Empty(t, edges)
+	require.Empty(t, known.List())
+}
+
+// TestMergeAdditionalEdges tests that merging edges skips the channels that
+// already have an edge from the same node.
+func TestMergeAdditionalEdges(t *testing.T) {
+	t.Parallel()
+
+	var (
+		node   = newKnownHintTestKey(t)
+		target = route.NewVertex(newKnownHintTestKey(t))
+	)
+
+	edges, err := RouteHintsToEdges(
+		[][]zpay32.HopHint{{{NodeID: node, ChannelID: 1}}}, target,
+	)
+	require.NoError(t, err)
+
+	extra, err := RouteHintsToEdges([][]zpay32.HopHint{
+		{{NodeID: node, ChannelID: 1, FeeBaseMSat: 100}},
+		{{NodeID: node, ChannelID: 2}},
+	}, target)
+	require.NoError(t, err)
+
+	merged := mergeAdditionalEdges(edges, extra)
+
+	from := route.NewVertex(node)
+	require.Len(t, merged[from], 2)
+
+	// The edge that was there first takes precedence.
+	require.Zero(t, merged[from][0].EdgePolicy().FeeBaseMSat)
+	require.EqualValues(t, 2, merged[from][1].EdgePolicy().ChannelID)
+}
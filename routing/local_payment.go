@@ -0,0 +1,137 @@
+package routing
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// LocalPaymentSettler settles payments to our own node without sending an
+// HTLC over the network.
+type LocalPaymentSettler interface {
+	// SettleLocalPayment attempts to settle the invoice with the given
+	// hash using a payment that is identified by the given circuit key.
+	// The preimage of the invoice is returned on success.
+	SettleLocalPayment(rHash lntypes.Hash, amtPaid lnwire.MilliSatoshi,
+		expiry uint32, currentHeight int32,
+		circuitKey models.CircuitKey,
+		payload invoices.Payload) (lntypes.Preimage, error)
+}
+
+// newLocalRoute returns the route of a payment to our own node that is settled
+// locally. The route consists of a single hop from our node to itself over
+// the zero channel ID, which is never used by a real channel. The final hop
+// fields are populated as they would be for any other payment, so that the
+// invoice registry can validate them.
+func newLocalRoute(payment *LightningPayment, amt lnwire.MilliSatoshi,
+	height uint32, finalCltvDelta uint16) (*route.Route, error) {
+
+	var mpp *record.MPP
+	if payment.PaymentAddr != nil {
+		mpp = record.NewMPP(payment.Amount, *payment.PaymentAddr)
+	}
+
+	timeLock := height + uint32(finalCltvDelta)
+
+	hop := &route.Hop{
+		PubKeyBytes:      payment.Target,
+		AmtToForward:     amt,
+		OutgoingTimeLock: timeLock,
+		CustomRecords:    payment.DestCustomRecords,
+		MPP:              mpp,
+		Metadata:         payment.Metadata,
+	}
+
+	return route.NewRouteFromHops(
+		amt, timeLock, payment.Target, []*route.Hop{hop},
+	)
+}
+
+// isLocalRoute returns true if the given route was created by newLocalRoute.
+func isLocalRoute(rt *route.Route) bool {
+	return len(rt.Hops) == 1 && rt.Hops[0].ChannelID == 0 &&
+		rt.Hops[0].PubKeyBytes == rt.SourcePubKey
+}
+
+// localPayload exposes the final hop of a local route as the payload of the
+// payment to the invoice registry.
+type localPayload struct {
+	hop *route.Hop
+}
+
+// A compile time check to ensure localPayload implements invoices.Payload.
+var _ invoices.Payload = (*localPayload)(nil)
+
+// MultiPath returns the MPP record of the payment.
+func (l *localPayload) MultiPath() *record.MPP {
+	return l.hop.MPP
+}
+
+// AMPRecord returns the AMP record of the payment.
+func (l *localPayload) AMPRecord() *record.AMP {
+	return l.hop.AMP
+}
+
+// CustomRecords returns the custom records of the payment.
+func (l *localPayload) CustomRecords() record.CustomSet {
+	return l.hop.CustomRecords
+}
+
+// Metadata returns the metadata of the payment.
+func (l *localPayload) Metadata() []byte {
+	return l.hop.Metadata
+}
+
+// settleLocally settles an attempt of a payment to our own node with the
+// invoice registry and records its outcome with the control tower. Settling
+// is idempotent, so an attempt that is resumed after a restart is simply
+// settled again. If the invoice can't be settled, the payment is failed, as
+// another attempt wouldn't fare any better.
+func (p *paymentLifecycle) settleLocally(attempt *channeldb.HTLCAttempt,
+	hash lntypes.Hash) (*attemptResult, error) {
+
+	hop := attempt.Route.FinalHop()
+
+	// The attempt ID is unique, so together with the zero channel ID it
+	// identifies the payment to the invoice registry.
+	circuitKey := models.CircuitKey{
+		ChanID: lnwire.NewShortChanIDFromInt(hop.ChannelID),
+		HtlcID: attempt.AttemptID,
+	}
+
+	preimage, err := p.router.cfg.LocalPaymentSettler.SettleLocalPayment(
+		hash, hop.AmtToForward, attempt.Route.TotalTimeLock,
+		p.currentHeight, circuitKey, &localPayload{hop: hop},
+	)
+	if err != nil {
+		reason := channeldb.FailureReasonPaymentDetails
+
+		return p.failPaymentAndAttempt(attempt.AttemptID, &reason, err)
+	}
+
+	log.Debugf("Payment %v settled locally with pid=%v", p.identifier,
+		attempt.AttemptID)
+
+	htlcAttempt, err := p.router.cfg.Control.SettleAttempt(
+		p.identifier, attempt.AttemptID,
+		&channeldb.HTLCSettleInfo{
+			Preimage:   preimage,
+			SettleTime: p.router.cfg.Clock.Now(),
+		},
+	)
+	if err != nil {
+		log.Errorf("Error settling attempt %v for payment %v with "+
+			"preimage %v: %v", attempt.AttemptID, p.identifier,
+			preimage, err)
+
+		return nil, err
+	}
+
+	return &attemptResult{
+		attempt: htlcAttempt,
+	}, nil
+}
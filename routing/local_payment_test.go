@@ -0,0 +1,141 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// makeLocalAttempt returns an attempt of a payment to self that is settled
+// locally.
+func makeLocalAttempt(t *testing.T, amt lnwire.MilliSatoshi,
+	hash lntypes.Hash) *channeldb.HTLCAttempt {
+
+	payAddr := [32]byte{1}
+	payment := &LightningPayment{
+		Target:      route.Vertex{2},
+		Amount:      amt,
+		PaymentAddr: &payAddr,
+		Metadata:    []byte{3},
+	}
+
+	rt, err := newLocalRoute(payment, amt, 100, 40)
+	require.NoError(t, err)
+
+	return channeldb.NewHtlcAttempt(
+		7, priv1, *rt, time.Unix(0, 0), &hash,
+	)
+}
+
+// TestNewLocalRoute checks that the route of a locally settled payment carries
+// the final hop fields of the payment and is recognized as local.
+func TestNewLocalRoute(t *testing.T) {
+	t.Parallel()
+
+	amt := lnwire.MilliSatoshi(10_000)
+	attempt := makeLocalAttempt(t, amt, lntypes.Hash{1})
+	rt := &attempt.Route
+
+	require.True(t, isLocalRoute(rt))
+	require.Equal(t, amt, rt.TotalAmount)
+	require.Equal(t, amt, rt.ReceiverAmt())
+	require.Zero(t, rt.TotalFees())
+	require.EqualValues(t, 140, rt.TotalTimeLock)
+
+	hop := rt.FinalHop()
+	require.Equal(t, rt.SourcePubKey, hop.PubKeyBytes)
+	require.Equal(t, record.NewMPP(amt, [32]byte{1}), hop.MPP)
+	require.Equal(t, []byte{3}, hop.Metadata)
+
+	// Routes through the network are never local.
+	require.False(t, isLocalRoute(createDummyRoute(t, amt)))
+}
+
+// TestCollectResultLocalSettle checks that the result of a local attempt is
+// obtained from the local payment settler rather than the switch.
+func TestCollectResultLocalSettle(t *testing.T) {
+	t.Parallel()
+
+	p, m := newTestPaymentLifecycle(t)
+
+	settler := &mockLocalPaymentSettler{}
+	p.router.cfg.LocalPaymentSettler = settler
+	t.Cleanup(func() { settler.AssertExpectations(t) })
+
+	preimage := lntypes.Preimage{1}
+	attempt := makeLocalAttempt(t, 10_000, p.identifier)
+
+	m.shardTracker.On("GetHash",
+		attempt.AttemptID,
+	).Return(p.identifier, nil).Once()
+
+	// The settler is called with the amount and expiry of the route and a
+	// circuit key that's unique to the attempt.
+	circuitKey := models.CircuitKey{HtlcID: attempt.AttemptID}
+	settler.On("SettleLocalPayment",
+		p.identifier, lnwire.MilliSatoshi(10_000),
+		attempt.Route.TotalTimeLock, p.currentHeight, circuitKey,
+		mock.Anything,
+	).Return(preimage, nil).Once()
+
+	settled := makeSettledAttempt(t, 10_000, preimage)
+	m.control.On("SettleAttempt",
+		p.identifier, attempt.AttemptID, mock.Anything,
+	).Return(settled, nil).Once()
+
+	m.clock.On("Now").Return(time.Now())
+
+	result, err := p.collectResult(attempt)
+	require.NoError(t, err)
+	require.Equal(t, preimage, result.attempt.Settle.Preimage)
+}
+
+// TestCollectResultLocalSettleFail checks that the payment is failed when the
+// invoice can't be settled locally.
+func TestCollectResultLocalSettleFail(t *testing.T) {
+	t.Parallel()
+
+	p, m := newTestPaymentLifecycle(t)
+
+	settler := &mockLocalPaymentSettler{}
+	p.router.cfg.LocalPaymentSettler = settler
+	t.Cleanup(func() { settler.AssertExpectations(t) })
+
+	attempt := makeLocalAttempt(t, 10_000, p.identifier)
+
+	m.shardTracker.On("GetHash",
+		attempt.AttemptID,
+	).Return(p.identifier, nil).Once()
+
+	settler.On("SettleLocalPayment",
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything,
+	).Return(lntypes.Preimage{}, errDummy).Once()
+
+	// The payment is failed with the control tower, as another attempt
+	// wouldn't be settled either.
+	m.control.On("FailPayment",
+		p.identifier, channeldb.FailureReasonPaymentDetails,
+	).Return(nil).Once()
+
+	m.shardTracker.On("CancelShard", attempt.AttemptID).Return(nil).Once()
+
+	failed := makeFailedAttempt(t, 10_000)
+	m.control.On("FailAttempt",
+		p.identifier, attempt.AttemptID, mock.Anything,
+	).Return(failed, nil).Once()
+
+	m.clock.On("Now").Return(time.Now())
+
+	result, err := p.collectResult(attempt)
+	require.NoError(t, err)
+	require.ErrorIs(t, result.err, errDummy)
+}
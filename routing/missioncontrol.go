@@ -336,19 +336,71 @@ func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
 	m.Lock()
 	defer m.Unlock()
 
+	return m.getProbability(m.estimator, fromNode, toNode, amt, capacity)
+}
+
+// GetProbabilityWithEstimator returns the success probability of a payment
+// from fromNode along edge as given by the passed estimator rather than the
+// active one. This allows the outputs of different probability models to be
+// compared against the same payment history.
+func (m *MissionControl) GetProbabilityWithEstimator(estimator Estimator,
+	fromNode, toNode route.Vertex, amt lnwire.MilliSatoshi,
+	capacity btcutil.Amount) float64 {
+
+	m.Lock()
+	defer m.Unlock()
+
+	return m.getProbability(estimator, fromNode, toNode, amt, capacity)
+}
+
+// getProbability estimates the success probability of a payment from fromNode
+// along edge using the given estimator. The caller must hold the mission
+// control lock.
+func (m *MissionControl) getProbability(estimator Estimator, fromNode,
+	toNode route.Vertex, amt lnwire.MilliSatoshi,
+	capacity btcutil.Amount) float64 {
+
 	now := m.now()
 	results, _ := m.state.getLastPairResult(fromNode)
 
 	// Use a distinct probability estimation function for local channels.
 	if fromNode == m.selfNode {
-		return m.estimator.LocalPairProbability(now, results, toNode)
+		return estimator.LocalPairProbability(now, results, toNode)
 	}
 
-	return m.estimator.PairProbability(
+	return estimator.PairProbability(
 		now, results, toNode, amt, capacity,
 	)
 }
 
+// GetEndorsementHint returns true if toNode is likely to give endorsed
+// treatment to HTLCs that it receives from fromNode. Nodes endorse HTLCs from
+// peers that built up a good reputation with them, so we consider this to be
+// the case if a payment succeeded across the pair within the given window and
+// the pair hasn't failed since.
+func (m *MissionControl) GetEndorsementHint(fromNode, toNode route.Vertex,
+	window time.Duration) bool {
+
+	m.Lock()
+	defer m.Unlock()
+
+	results, ok := m.state.getLastPairResult(fromNode)
+	if !ok {
+		return false
+	}
+
+	result, ok := results[toNode]
+	if !ok || result.SuccessTime.IsZero() {
+		return false
+	}
+
+	if m.now().Sub(result.SuccessTime) > window {
+		return false
+	}
+
+	return !result.FailTime.After(result.SuccessTime)
+}
+
 // GetHistorySnapshot takes a snapshot from the current mission control state
 // and actual probability estimates.
 func (m *MissionControl) GetHistorySnapshot() *MissionControlSnapshot {
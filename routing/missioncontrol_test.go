@@ -211,6 +211,73 @@ func TestMissionControl(t *testing.T) {
 	ctx.reportSuccess()
 }
 
+// TestMissionControlEndorsementHint tests that mission control only hints that
+// a pair is likely to give endorsed treatment after a recent success.
+func TestMissionControlEndorsementHint(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	const window = time.Hour
+
+	expectHint := func(expected bool) {
+		t.Helper()
+
+		hint := ctx.mc.GetEndorsementHint(
+			mcTestNode1, mcTestNode2, window,
+		)
+		require.Equal(t, expected, hint)
+	}
+
+	// Without any history, we don't expect endorsed treatment.
+	expectHint(false)
+
+	// After a success, the pair is expected to endorse our HTLCs.
+	ctx.reportSuccess()
+	expectHint(true)
+
+	// Once the success falls outside of the window, the hint is gone.
+	ctx.now = ctx.now.Add(window + time.Second)
+	expectHint(false)
+
+	// A new success followed by a failure also removes the hint.
+	ctx.reportSuccess()
+	expectHint(true)
+
+	ctx.now = ctx.now.Add(time.Second)
+	ctx.reportFailure(0, lnwire.NewTemporaryChannelFailure(nil))
+	expectHint(false)
+}
+
+// TestMissionControlEstimatorComparison tests that probabilities can be
+// queried with an estimator other than the active one, using the same payment
+// history.
+func TestMissionControlEstimatorComparison(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	bimodal, err := NewBimodalEstimator(DefaultBimodalConfig())
+	require.NoError(t, err)
+
+	getProbability := func(estimator Estimator) float64 {
+		return ctx.mc.GetProbabilityWithEstimator(
+			estimator, mcTestNode1, mcTestNode2, 1000,
+			testCapacity,
+		)
+	}
+
+	// Querying with the active estimator yields the same result as the
+	// regular probability query.
+	active := ctx.mc.GetConfig().Estimator
+	require.Equal(t, testAprioriHopProbability, getProbability(active))
+
+	// Without any history, the bimodal model considers a small amount
+	// much more likely to succeed than the apriori model does.
+	require.Greater(t, getProbability(bimodal), testAprioriHopProbability)
+
+	// After a failure, both models take the shared history into account.
+	ctx.reportFailure(1000, lnwire.NewTemporaryChannelFailure(nil))
+	require.Zero(t, getProbability(active))
+	require.Zero(t, getProbability(bimodal))
+}
+
 // TestMissionControlChannelUpdate tests that the first channel update is not
 // penalizing the channel yet.
 func TestMissionControlChannelUpdate(t *testing.T) {
@@ -3,6 +3,7 @@ package routing
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -10,6 +11,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
@@ -148,6 +150,12 @@ func (m *mockMissionControlOld) GetProbability(fromNode, toNode route.Vertex,
 	return 0
 }
 
+func (m *mockMissionControlOld) GetEndorsementHint(fromNode,
+	toNode route.Vertex, window time.Duration) bool {
+
+	return false
+}
+
 type mockPaymentSessionOld struct {
 	routes []*route.Route
 
@@ -666,6 +674,13 @@ func (m *mockMissionControl) GetProbability(fromNode, toNode route.Vertex,
 	return args.Get(0).(float64)
 }
 
+func (m *mockMissionControl) GetEndorsementHint(fromNode, toNode route.Vertex,
+	window time.Duration) bool {
+
+	args := m.Called(fromNode, toNode, window)
+	return args.Bool(0)
+}
+
 type mockPaymentSession struct {
 	mock.Mock
 }
@@ -946,3 +961,21 @@ func (m *mockShard) AMP() *record.AMP {
 
 	return r.(*record.AMP)
 }
+
+type mockLocalPaymentSettler struct {
+	mock.Mock
+}
+
+var _ LocalPaymentSettler = (*mockLocalPaymentSettler)(nil)
+
+func (m *mockLocalPaymentSettler) SettleLocalPayment(rHash lntypes.Hash,
+	amtPaid lnwire.MilliSatoshi, expiry uint32, currentHeight int32,
+	circuitKey models.CircuitKey,
+	payload invoices.Payload) (lntypes.Preimage, error) {
+
+	args := m.Called(
+		rHash, amtPaid, expiry, currentHeight, circuitKey, payload,
+	)
+
+	return args.Get(0).(lntypes.Preimage), args.Error(1)
+}
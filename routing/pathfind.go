@@ -78,6 +78,29 @@ var (
 	// returned from findPath.
 	DefaultMinRouteProbability = float64(0.01)
 
+	// DefaultEndorsementWindow is the default period over which a
+	// successful payment across a node pair is considered to have built up
+	// enough reputation for our HTLCs to receive endorsed treatment.
+	DefaultEndorsementWindow = 14 * 24 * time.Hour
+
+	// DefaultUnendorsedPenalty is the default fixed virtual cost in path
+	// finding that is added to every hop that is unlikely to give our HTLCs
+	// endorsed treatment when endorsement aware path finding is enabled.
+	DefaultUnendorsedPenalty = lnwire.NewMSatFromSatoshis(10)
+
+	// DefaultBlindedFeeTolerancePPM is the default maximum aggregate fee,
+	// expressed in parts per million of the amount delivered to the
+	// receiver, that we're willing to pay to the blinded portion of a
+	// route. Blinded paths only advertise aggregated relay parameters,
+	// which can round up the fee we pay compared to the sum of the fees of
+	// the individual blinded hops.
+	DefaultBlindedFeeTolerancePPM = int64(50_000)
+
+	// DefaultParallelPathSearches is the default number of path searches
+	// for different shard amounts that are run concurrently when a payment
+	// needs to be split. A value of one searches sequentially.
+	DefaultParallelPathSearches = 1
+
 	// DefaultAprioriHopProbability is the default a priori probability for
 	// a hop.
 	DefaultAprioriHopProbability = float64(0.6)
@@ -433,6 +456,12 @@ type RestrictParams struct {
 	// BlindedPayment is necessary to determine the hop size of the
 	// last/exit hop.
 	BlindedPayment *BlindedPayment
+
+	// EndorsementSource is an optional callback that is expected to return
+	// true if toNode is likely to give endorsed treatment to HTLCs that it
+	// receives from fromNode. If it is nil, path finding doesn't take
+	// endorsement into account.
+	EndorsementSource func(fromNode, toNode route.Vertex) bool
 }
 
 // PathFindingConfig defines global parameters that control the trade-off in
@@ -452,6 +481,43 @@ type PathFindingConfig struct {
 	// MinProbability defines the minimum success probability of the
 	// returned route.
 	MinProbability float64
+
+	// EndorsementAware indicates whether path finding should prefer hops
+	// that are likely to give our HTLCs endorsed treatment, based on the
+	// reputation we built up with them through past payments.
+	EndorsementAware bool
+
+	// EndorsementWindow is the period over which a successful payment
+	// across a node pair is considered to have built up reputation.
+	EndorsementWindow time.Duration
+
+	// UnendorsedPenalty is the fixed virtual cost that is added to the
+	// weight of every hop that is unlikely to give our HTLCs endorsed
+	// treatment.
+	UnendorsedPenalty lnwire.MilliSatoshi
+
+	// BlindedFeeTolerancePPM is the maximum aggregate fee, expressed in
+	// parts per million of the amount delivered to the receiver, that we
+	// are willing to pay to the blinded portion of a route. Blinded paths
+	// that charge more are skipped. A value of zero disables the check.
+	BlindedFeeTolerancePPM int64
+
+	// ParallelPathSearches is the maximum number of path searches for
+	// successively smaller shard amounts that are run concurrently when no
+	// route can be found for the full amount of a multi-part payment.
+	// Values of one or below search sequentially.
+	ParallelPathSearches int
+
+	// ShadowRouteMaxCltv is the maximum number of blocks of random padding
+	// that is added to the final CLTV delta of a payment's routes, so that
+	// the receiver can't infer its distance to the sender. A value of zero
+	// disables the padding.
+	ShadowRouteMaxCltv uint16
+
+	// AmountRandomization is the strategy used to pick the amount of the
+	// next shard when no route can be found for the current amount of a
+	// payment.
+	AmountRandomization AmountRandomization
 }
 
 // getOutgoingBalance returns the maximum available balance in any of the
@@ -828,6 +894,16 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// the HTLC that is handed out to fromVertex.
 		weight := edgeWeight(netAmountToReceive, fee, timeLockDelta)
 
+		// If path finding is endorsement aware, we add a virtual cost
+		// to hops that are unlikely to give our HTLC endorsed
+		// treatment. The final hop is exempt as the receiver doesn't
+		// forward the HTLC any further.
+		if r.EndorsementSource != nil && toNodeDist.node != target &&
+			!r.EndorsementSource(fromVertex, toNodeDist.node) {
+
+			weight += int64(cfg.UnendorsedPenalty)
+		}
+
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
 		// plus the weight of this edge.
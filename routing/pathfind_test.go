@@ -168,8 +168,8 @@ func makeTestGraph(t *testing.T, useCache bool) (*channeldb.ChannelGraph,
 	opts := channeldb.DefaultOptions()
 	graph, err := channeldb.NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
-		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		useCache, false,
+		opts.BatchCommitInterval, opts.MaxBatchSize,
+		opts.PreAllocCacheNumNodes, useCache, false,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -817,6 +817,9 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "equal cost route selection",
 		fn:   runEqualCostRouteSelection,
+	}, {
+		name: "endorsement aware routing",
+		fn:   runEndorsementAwareRouting,
 	}, {
 		name: "no cycle",
 		fn:   runNoCycle,
@@ -2978,6 +2981,63 @@ func testProbabilityRouting(t *testing.T, useCache bool,
 	}
 }
 
+// runEndorsementAwareRouting asserts that endorsement aware path finding adds a
+// penalty to hops that are unlikely to give our HTLCs endorsed treatment.
+func runEndorsementAwareRouting(t *testing.T, useCache bool) {
+	// Set up a test graph with two possible paths to the target: a cheap
+	// three hop path (via channels 10 and 11) and a more expensive two hop
+	// path (via channel 20).
+	testChannels := []*testChannel{
+		symmetricTestChannel(
+			"roasbeef", "a1", 100000, &testChannelPolicy{},
+		),
+		symmetricTestChannel(
+			"roasbeef", "b", 100000, &testChannelPolicy{},
+		),
+		symmetricTestChannel("a1", "a2", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: lnwire.NewMSatFromSatoshis(5),
+			MinHTLC:     1,
+		}, 10),
+		symmetricTestChannel("a2", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: lnwire.NewMSatFromSatoshis(8),
+			MinHTLC:     1,
+		}, 11),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: lnwire.NewMSatFromSatoshis(25),
+			MinHTLC:     1,
+		}, 20),
+	}
+
+	ctx := newPathFindingTestContext(t, useCache, testChannels, "roasbeef")
+
+	alias := ctx.testGraphInstance.aliasMap
+	target := alias["target"]
+	amt := lnwire.NewMSatFromSatoshis(100)
+
+	// Without endorsement hints, the cheaper three hop route is expected.
+	path, err := ctx.findPath(target, amt)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, path[1].policy.ChannelID)
+
+	// Only a2 is unlikely to endorse HTLCs that it receives from a1. The
+	// penalty for this single hop makes the two hop route the better one.
+	ctx.restrictParams.EndorsementSource = func(fromNode,
+		toNode route.Vertex) bool {
+
+		return fromNode != alias["a1"] || toNode != alias["a2"]
+	}
+	ctx.pathFindingConfig.UnendorsedPenalty = lnwire.NewMSatFromSatoshis(
+		20,
+	)
+
+	path, err = ctx.findPath(target, amt)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, path[1].policy.ChannelID)
+}
+
 // runEqualCostRouteSelection asserts that route probability will be used as a
 // tie breaker in case the path finding probabilities are equal.
 func runEqualCostRouteSelection(t *testing.T, useCache bool) {
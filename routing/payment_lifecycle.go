@@ -14,8 +14,10 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/tracing"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/routing/shards"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ErrPaymentLifecycleExiting is used when waiting for htlc attempt result, but
@@ -47,6 +49,10 @@ type paymentLifecycle struct {
 	// except in unit test, where we use a much simpler resultCollector to
 	// decouple the test flow for the payment lifecycle.
 	resultCollector func(attempt *channeldb.HTLCAttempt)
+
+	// traceCtx carries the span of the payment. The spans of the stages
+	// of the payment are created as its children.
+	traceCtx context.Context
 }
 
 // newPaymentLifecycle initiates a new payment lifecycle and returns it.
@@ -64,6 +70,7 @@ func newPaymentLifecycle(r *ChannelRouter, feeLimit lnwire.MilliSatoshi,
 		currentHeight:   currentHeight,
 		quit:            make(chan struct{}),
 		resultCollected: make(chan error, 1),
+		traceCtx:        context.Background(),
 	}
 
 	// Mount the result collector.
@@ -359,10 +366,12 @@ func (p *paymentLifecycle) requestRoute(
 	remainingFees := p.calcFeeBudget(ps.FeesPaid)
 
 	// Query our payment session to construct a route.
+	_, span := tracing.StartSpan(p.traceCtx, "routing.FindRoute")
 	rt, err := p.paySession.RequestRoute(
 		ps.RemainingAmt, remainingFees,
 		uint32(ps.NumAttemptsInFlight), uint32(p.currentHeight),
 	)
+	tracing.EndSpan(span, err)
 
 	// Exit early if there's no error.
 	if err == nil {
@@ -469,6 +478,12 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 		return p.failAttempt(attempt.AttemptID, err)
 	}
 
+	// Payments that are settled locally never reach the switch, so we
+	// settle them with the invoice registry instead.
+	if isLocalRoute(&attempt.Route) {
+		return p.settleLocally(attempt, hash)
+	}
+
 	// Regenerate the circuit for this attempt.
 	_, circuit, err := generateSphinxPacket(
 		&attempt.Route, hash[:], attempt.SessionKey(),
@@ -494,6 +509,7 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 	// switch.
 	errorDecryptor := &htlcswitch.SphinxErrorDecrypter{
 		OnionErrorDecrypter: sphinx.NewOnionErrorDecrypter(circuit),
+		Circuit:             circuit,
 	}
 
 	// Now ask the switch to return the result of the payment when
@@ -522,19 +538,30 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 		ok     bool
 	)
 
+	_, span := tracing.StartSpan(
+		p.traceCtx, "htlcswitch.AwaitResult",
+		attribute.Int64("attempt_id", int64(attempt.AttemptID)),
+	)
+
 	select {
 	case result, ok = <-resultChan:
 		if !ok {
+			tracing.EndSpan(span, htlcswitch.ErrSwitchExiting)
 			return nil, htlcswitch.ErrSwitchExiting
 		}
 
 	case <-p.quit:
+		tracing.EndSpan(span, ErrPaymentLifecycleExiting)
 		return nil, ErrPaymentLifecycleExiting
 
 	case <-p.router.quit:
+		tracing.EndSpan(span, ErrRouterShuttingDown)
 		return nil, ErrRouterShuttingDown
 	}
 
+	tracing.EndSpan(span, result.Error)
+	tracing.UntrackHTLC(hash)
+
 	// In case of a payment failure, fail the attempt with the control
 	// tower and return.
 	if result.Error != nil {
@@ -553,6 +580,12 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 		log.Errorf("Error reporting payment success to mc: %v", err)
 	}
 
+	// Remember the path of the successful attempt for future payments to
+	// the same destination.
+	if p.router.cfg.RouteCache != nil {
+		p.router.cfg.RouteCache.AddRoute(&attempt.Route)
+	}
+
 	// In case of success we atomically store settle result to the DB move
 	// the shard to the settled state.
 	htlcAttempt, err := p.router.cfg.Control.SettleAttempt(
@@ -666,6 +699,14 @@ func (p *paymentLifecycle) sendAttempt(
 
 	rt := attempt.Route
 
+	// Payments that are settled locally aren't sent to the switch. They
+	// are settled once their result is collected.
+	if isLocalRoute(&rt) {
+		return &attemptResult{
+			attempt: attempt,
+		}, nil
+	}
+
 	// Construct the first hop.
 	firstHop := lnwire.NewShortChanIDFromInt(rt.Hops[0].ChannelID)
 
@@ -694,15 +735,26 @@ func (p *paymentLifecycle) sendAttempt(
 
 	copy(htlcAdd.OnionBlob[:], onionBlob)
 
+	// Track the HTLC with the payment's span, so that writing it to the
+	// first hop is traced as part of the payment.
+	tracing.TrackHTLC(p.traceCtx, *attempt.Hash)
+
 	// Send it to the Switch. When this method returns we assume
 	// the Switch successfully has persisted the payment attempt,
 	// such that we can resume waiting for the result after a
 	// restart.
+	_, span := tracing.StartSpan(
+		p.traceCtx, "htlcswitch.SendHTLC",
+		attribute.Int64("attempt_id", int64(attempt.AttemptID)),
+	)
 	err = p.router.cfg.Payer.SendHTLC(firstHop, attempt.AttemptID, htlcAdd)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		log.Errorf("Failed sending attempt %d for payment %v to "+
 			"switch: %v", attempt.AttemptID, p.identifier, err)
 
+		tracing.UntrackHTLC(*attempt.Hash)
+
 		return p.handleSwitchErr(attempt, err)
 	}
 
@@ -752,6 +804,12 @@ func (p *paymentLifecycle) handleSwitchErr(attempt *channeldb.HTLCAttempt,
 	internalErrorReason := channeldb.FailureReasonError
 	attemptID := attempt.AttemptID
 
+	// A failed attempt may have been built from a cached path, which we
+	// shouldn't try again.
+	if p.router.cfg.RouteCache != nil {
+		p.router.cfg.RouteCache.RemoveRoute(&attempt.Route)
+	}
+
 	// reportAndFail is a helper closure that reports the failure to the
 	// mission control, which helps us to decide whether we want to retry
 	// the payment or not. If a non nil reason is returned from mission
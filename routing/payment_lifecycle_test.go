@@ -35,6 +35,7 @@ func createTestPaymentLifecycle() *paymentLifecycle {
 	return &paymentLifecycle{
 		router:     rt,
 		identifier: paymentHash,
+		traceCtx:   context.Background(),
 	}
 }
 
@@ -2,6 +2,8 @@ package routing
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btclog"
@@ -10,6 +12,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
 	"github.com/lightningnetwork/lnd/routing/route"
 )
 
@@ -181,6 +184,10 @@ type paymentSession struct {
 
 	missionControl MissionController
 
+	// routeCache is an optional cache of recently successful routes that
+	// is consulted before running path finding.
+	routeCache *RouteCache
+
 	// minShardAmt is the amount beyond which we won't try to further split
 	// the payment if no route is found. If the maximum number of htlcs
 	// specified in the payment is one, under no circumstances splitting
@@ -239,6 +246,18 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 	finalCltvDelta := p.payment.FinalCLTVDelta
 	finalCltvDelta += BlockPadding
 
+	// A payment that is settled locally doesn't need a path, as it never
+	// leaves our node.
+	if p.payment.LocalSettle {
+		return newLocalRoute(p.payment, maxAmt, height, finalCltvDelta)
+	}
+
+	// Pad the final cltv delta with a random number of blocks, so that
+	// the route looks longer to the receiver than it is.
+	finalCltvDelta += shadowCltvPadding(
+		p.shadowRouteMaxCltv(), p.payment.CltvLimit, finalCltvDelta,
+	)
+
 	// We need to subtract the final delta before passing it into path
 	// finding. The optimal path is independent of the final cltv delta and
 	// the path finding algorithm is unaware of this value.
@@ -262,6 +281,19 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		Metadata:           p.payment.Metadata,
 	}
 
+	// If enabled, we'll let mission control tell path finding which hops
+	// are likely to give our HTLCs endorsed treatment.
+	if p.pathFindingConfig.EndorsementAware {
+		window := p.pathFindingConfig.EndorsementWindow
+		restrictions.EndorsementSource = func(fromNode,
+			toNode route.Vertex) bool {
+
+			return p.missionControl.GetEndorsementHint(
+				fromNode, toNode, window,
+			)
+		}
+	}
+
 	finalHtlcExpiry := int32(height) + int32(finalCltvDelta)
 
 	// Before we enter the loop below, we'll make sure to respect the max
@@ -276,130 +308,488 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		maxAmt = *p.payment.MaxShardAmt
 	}
 
+	// When no route is found for the full amount, the amount is halved
+	// until a route is found. If the payment can be split, several of
+	// these path searches may run concurrently to cut down the latency of
+	// finding a shard that fits.
+	batchSize := 1
+	if p.pathFindingConfig.ParallelPathSearches > 1 &&
+		p.canSplit(activeShards, false) {
+
+		batchSize = p.pathFindingConfig.ParallelPathSearches
+	}
+
 	for {
-		// Get a routing graph.
-		routingGraph, cleanup, err := p.getRoutingGraph()
-		if err != nil {
-			return nil, err
+		// Collect the amounts of this batch of path searches, halving
+		// the amount for each subsequent search. The first amount is
+		// always searched, but further ones must respect the minimum
+		// shard amount.
+		amts := []lnwire.MilliSatoshi{maxAmt}
+		for len(amts) < batchSize {
+			nextAmt := splitAmount(
+				amts[len(amts)-1], p.amountRandomization(),
+			)
+			if nextAmt < p.minShardAmt {
+				break
+			}
+
+			amts = append(amts, nextAmt)
 		}
 
-		// We'll also obtain a set of bandwidthHints from the lower
-		// layer for each of our outbound channels. This will allow the
-		// path finding to skip any links that aren't active or just
-		// don't have enough bandwidth to carry the payment. New
-		// bandwidth hints are queried for every new path finding
-		// attempt, because concurrent payments may change balances.
-		bandwidthHints, err := p.getBandwidthHints(routingGraph)
-		if err != nil {
-			return nil, err
+		results := p.searchRoutes(
+			amts, restrictions, height, finalCltvDelta,
+			finalHtlcExpiry,
+		)
+
+		// The results are processed in order of decreasing amount, so
+		// that we pick the route for the largest amount, exactly as if
+		// the searches were run sequentially.
+		for i, result := range results {
+			switch {
+			case result.err == errNoPathFound:
+				// Try the next smaller amount of this batch, if
+				// there is one and we're allowed to split.
+				if i < len(results)-1 {
+					continue
+				}
+
+			// If there isn't enough local bandwidth, there is no
+			// point in splitting. It won't be possible to create a
+			// complete set in any case, but the sent out partial
+			// payments would be held by the receiver until the mpp
+			// timeout.
+			case result.err == errInsufficientBalance:
+				p.log.Debug("not splitting because local " +
+					"balance is insufficient")
+
+				return nil, result.err
+
+			case result.err != nil:
+				return nil, result.err
+
+			default:
+				return result.route, nil
+			}
+		}
+
+		// No route was found for any amount of this batch, so we'll
+		// split the payment further if possible.
+		if !p.canSplit(activeShards, true) {
+			return nil, errNoPathFound
+		}
+
+		// This is where the magic happens. If we can't find a route,
+		// try it for about half the amount.
+		maxAmt = splitAmount(
+			amts[len(amts)-1], p.amountRandomization(),
+		)
+
+		// Put a lower bound on the minimum shard size.
+		if maxAmt < p.minShardAmt {
+			p.log.Debugf("not splitting because minimum shard "+
+				"amount %v has been reached", p.minShardAmt)
+
+			return nil, errNoPathFound
+		}
+	}
+}
+
+// shadowRouteMaxCltv returns the maximum shadow route padding of the payment,
+// which is the router's default unless the payment overrides it.
+func (p *paymentSession) shadowRouteMaxCltv() uint16 {
+	if p.payment.ShadowRouteMaxCltv != nil {
+		return *p.payment.ShadowRouteMaxCltv
+	}
+
+	return p.pathFindingConfig.ShadowRouteMaxCltv
+}
+
+// amountRandomization returns the strategy used to pick the amounts of the
+// payment's shards, which is the router's default unless the payment
+// overrides it.
+func (p *paymentSession) amountRandomization() AmountRandomization {
+	if p.payment.AmountRandomization != AmountRandomizationDefault {
+		return p.payment.AmountRandomization
+	}
+
+	return p.pathFindingConfig.AmountRandomization
+}
+
+// canSplit returns true if the payment may be split into smaller shards when
+// no route can be found for the current amount. If logReason is set, the
+// reason for not splitting is logged.
+func (p *paymentSession) canSplit(activeShards uint32, logReason bool) bool {
+	logDebug := func(format string, params ...interface{}) {
+		if logReason {
+			p.log.Debugf(format, params...)
 		}
+	}
+
+	// Don't split if this is a legacy payment without mpp record.
+	if p.payment.PaymentAddr == nil {
+		logDebug("not splitting because payment address is " +
+			"unspecified")
+
+		return false
+	}
+
+	if p.payment.DestFeatures == nil {
+		logDebug("Not splitting because destination DestFeatures is " +
+			"nil")
 
-		p.log.Debugf("pathfinding for amt=%v", maxAmt)
+		return false
+	}
 
-		sourceVertex := routingGraph.sourceNode()
+	destFeatures := p.payment.DestFeatures
+	if !destFeatures.HasFeature(lnwire.MPPOptional) &&
+		!destFeatures.HasFeature(lnwire.AMPOptional) {
 
-		// Find a route for the current amount.
-		path, _, err := p.pathFinder(
-			&graphParams{
-				additionalEdges: p.additionalEdges,
-				bandwidthHints:  bandwidthHints,
-				graph:           routingGraph,
-			},
-			restrictions, &p.pathFindingConfig,
-			sourceVertex, p.payment.Target,
-			maxAmt, p.payment.TimePref, finalHtlcExpiry,
+		logDebug("not splitting because destination doesn't declare " +
+			"MPP or AMP")
+
+		return false
+	}
+
+	// No splitting if this is the last shard.
+	isLastShard := activeShards+1 >= p.payment.MaxParts
+	if isLastShard {
+		logDebug("not splitting because shard limit %v has been "+
+			"reached", p.payment.MaxParts)
+
+		return false
+	}
+
+	return true
+}
+
+// routeSearchResult holds the outcome of a single path search.
+type routeSearchResult struct {
+	route *route.Route
+	err   error
+}
+
+// searchRoutes runs a path search for each of the given amounts and returns
+// the results in the same order. If more than one amount is given, the
+// searches are run concurrently, each using its own routing graph and
+// bandwidth hints, while sharing the view of mission control.
+func (p *paymentSession) searchRoutes(amts []lnwire.MilliSatoshi,
+	restrictions *RestrictParams, height uint32, finalCltvDelta uint16,
+	finalHtlcExpiry int32) []routeSearchResult {
+
+	results := make([]routeSearchResult, len(amts))
+
+	// Avoid spawning a goroutine for the common sequential case.
+	if len(amts) == 1 {
+		rt, err := p.searchRoute(
+			amts[0], restrictions, height, finalCltvDelta,
+			finalHtlcExpiry,
 		)
+		results[0] = routeSearchResult{route: rt, err: err}
 
-		// Close routing graph.
-		cleanup()
+		return results
+	}
 
-		switch {
-		case err == errNoPathFound:
-			// Don't split if this is a legacy payment without mpp
-			// record.
-			if p.payment.PaymentAddr == nil {
-				p.log.Debugf("not splitting because payment " +
-					"address is unspecified")
+	var wg sync.WaitGroup
+	for i, amt := range amts {
+		wg.Add(1)
+		go func(i int, amt lnwire.MilliSatoshi) {
+			defer wg.Done()
+
+			rt, err := p.searchRoute(
+				amt, restrictions, height, finalCltvDelta,
+				finalHtlcExpiry,
+			)
+			results[i] = routeSearchResult{route: rt, err: err}
+		}(i, amt)
+	}
+	wg.Wait()
 
-				return nil, errNoPathFound
-			}
+	return results
+}
 
-			if p.payment.DestFeatures == nil {
-				p.log.Debug("Not splitting because " +
-					"destination DestFeatures is nil")
-				return nil, errNoPathFound
-			}
+// searchRoute runs a single path search for the given amount on a fresh
+// routing graph.
+func (p *paymentSession) searchRoute(maxAmt lnwire.MilliSatoshi,
+	restrictions *RestrictParams, height uint32, finalCltvDelta uint16,
+	finalHtlcExpiry int32) (*route.Route, error) {
 
-			destFeatures := p.payment.DestFeatures
-			if !destFeatures.HasFeature(lnwire.MPPOptional) &&
-				!destFeatures.HasFeature(lnwire.AMPOptional) {
+	// Get a routing graph.
+	routingGraph, cleanup, err := p.getRoutingGraph()
+	if err != nil {
+		return nil, err
+	}
 
-				p.log.Debug("not splitting because " +
-					"destination doesn't declare MPP or AMP")
+	// Close routing graph once we're done.
+	defer cleanup()
 
-				return nil, errNoPathFound
-			}
+	// We'll also obtain a set of bandwidthHints from the lower layer for
+	// each of our outbound channels. This will allow the path finding to
+	// skip any links that aren't active or just don't have enough
+	// bandwidth to carry the payment. New bandwidth hints are queried for
+	// every new path finding attempt, because concurrent payments may
+	// change balances.
+	bandwidthHints, err := p.getBandwidthHints(routingGraph)
+	if err != nil {
+		return nil, err
+	}
 
-			// No splitting if this is the last shard.
-			isLastShard := activeShards+1 >= p.payment.MaxParts
-			if isLastShard {
-				p.log.Debugf("not splitting because shard "+
-					"limit %v has been reached",
-					p.payment.MaxParts)
+	p.log.Debugf("pathfinding for amt=%v", maxAmt)
 
-				return nil, errNoPathFound
-			}
+	sourceVertex := routingGraph.sourceNode()
 
-			// This is where the magic happens. If we can't find a
-			// route, try it for half the amount.
-			maxAmt /= 2
+	g := &graphParams{
+		additionalEdges: p.additionalEdges,
+		bandwidthHints:  bandwidthHints,
+		graph:           routingGraph,
+	}
 
-			// Put a lower bound on the minimum shard size.
-			if maxAmt < p.minShardAmt {
-				p.log.Debugf("not splitting because minimum "+
-					"shard amount %v has been reached",
-					p.minShardAmt)
+	// Find a route for the current amount, either to the target node or
+	// to one of the blinded paths of the payment.
+	if len(p.payment.BlindedPayments) > 0 {
+		return p.findBlindedRoute(
+			g, restrictions, sourceVertex, maxAmt, height,
+			finalCltvDelta,
+		)
+	}
 
-				return nil, errNoPathFound
-			}
+	return p.findRoute(
+		g, restrictions, sourceVertex, maxAmt, height, finalCltvDelta,
+		finalHtlcExpiry,
+	)
+}
+
+// findRoute runs path finding towards the target node of the payment and turns
+// the resulting path into a route by applying the time-lock and fee
+// requirements.
+func (p *paymentSession) findRoute(g *graphParams, r *RestrictParams,
+	source route.Vertex, amt lnwire.MilliSatoshi, height uint32,
+	finalCltvDelta uint16, finalHtlcExpiry int32) (*route.Route, error) {
+
+	// Try a recently successful path to the target first, so that repeated
+	// payments don't require a full path search.
+	rt := p.cachedRoute(g, r, source, amt, height, finalCltvDelta)
+	if rt != nil {
+		return rt, nil
+	}
+
+	start := time.Now()
+	path, _, err := p.pathFinder(
+		g, r, &p.pathFindingConfig, source, p.payment.Target, amt,
+		p.payment.TimePref, finalHtlcExpiry,
+	)
+	metrics.ObservePathfinding(metrics.Outcome(err), time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+
+	return newRoute(
+		source, path, height,
+		finalHopParams{
+			amt:         amt,
+			totalAmt:    p.payment.Amount,
+			cltvDelta:   finalCltvDelta,
+			records:     p.payment.DestCustomRecords,
+			paymentAddr: p.payment.PaymentAddr,
+			metadata:    p.payment.Metadata,
+		}, nil,
+	)
+}
+
+// cachedRoute attempts to build a route for the given amount along a path to
+// the target that recently succeeded. The route is built with the current
+// channel policies and is only returned if it satisfies the restrictions of
+// the payment. Otherwise nil is returned and path finding should be used.
+func (p *paymentSession) cachedRoute(g *graphParams, r *RestrictParams,
+	source route.Vertex, amt lnwire.MilliSatoshi, height uint32,
+	finalCltvDelta uint16) *route.Route {
+
+	if p.routeCache == nil {
+		return nil
+	}
+
+	hops, ok := p.routeCache.LookupPath(p.payment.Target, amt)
+	if !ok {
+		return nil
+	}
+
+	// The cached path must respect a last hop restriction.
+	if r.LastHop != nil {
+		if len(hops) < 2 || hops[len(hops)-2] != *r.LastHop {
+			return nil
+		}
+	}
+
+	var outgoingChans map[uint64]struct{}
+	if len(r.OutgoingChannelIDs) > 0 {
+		outgoingChans = make(map[uint64]struct{})
+		for _, chanID := range r.OutgoingChannelIDs {
+			outgoingChans[chanID] = struct{}{}
+		}
+	}
+
+	unifiers, senderAmt, err := getRouteUnifiers(
+		source, hops, false, amt, outgoingChans, g.graph,
+		g.bandwidthHints,
+	)
+	if err != nil {
+		p.log.Debugf("Unable to use cached path: %v", err)
+		return nil
+	}
+
+	pathEdges, _, err := getPathEdges(
+		source, senderAmt, unifiers, g.bandwidthHints, hops,
+	)
+	if err != nil {
+		p.log.Debugf("Unable to use cached path: %v", err)
+		return nil
+	}
+
+	rt, err := newRoute(
+		source, pathEdges, height,
+		finalHopParams{
+			amt:         amt,
+			totalAmt:    p.payment.Amount,
+			cltvDelta:   finalCltvDelta,
+			records:     p.payment.DestCustomRecords,
+			paymentAddr: p.payment.PaymentAddr,
+			metadata:    p.payment.Metadata,
+		}, nil,
+	)
+	if err != nil {
+		p.log.Debugf("Unable to use cached path: %v", err)
+		return nil
+	}
+
+	// The fees and the time lock of the cached path may have grown since
+	// it was cached, so make sure it is still within our limits. The cltv
+	// limit excludes the final cltv delta.
+	if rt.TotalFees() > r.FeeLimit {
+		return nil
+	}
+
+	cltvDelta := rt.TotalTimeLock - height - uint32(finalCltvDelta)
+	if cltvDelta > r.CltvLimit {
+		return nil
+	}
+
+	p.log.Debugf("Using cached path for amt=%v", amt)
+
+	return rt
+}
+
+// findBlindedRoute runs path finding towards each of the blinded paths of the
+// payment and returns the route with the lowest expected cost. Blinded paths
+// that charge more than the configured fee tolerance are skipped, so that
+// the shards of a payment can be spread over several blinded paths.
+func (p *paymentSession) findBlindedRoute(g *graphParams, r *RestrictParams,
+	source route.Vertex, amt lnwire.MilliSatoshi, height uint32,
+	finalCltvDelta uint16) (*route.Route, error) {
+
+	var (
+		bestRoute *route.Route
+		bestDist  int64
+
+		cfg       = &p.pathFindingConfig
+		tolerance = lnwire.MilliSatoshi(cfg.BlindedFeeTolerancePPM)
+		maxFee    = amt * tolerance / 1_000_000
+
+		// The attempt cost is used to compare the routes to the
+		// different blinded paths in the same way that path finding
+		// compares routes to a single target.
+		attemptCost = float64(
+			cfg.AttemptCost +
+				amt*lnwire.MilliSatoshi(cfg.AttemptCostPPM)/
+					1_000_000,
+		)
+	)
+
+	for _, blindedPmt := range p.payment.BlindedPayments {
+		introNode := route.NewVertex(
+			blindedPmt.BlindedPath.IntroductionPoint,
+		)
+
+		// We can't use a blinded path that has our own node as the
+		// introduction point.
+		if introNode == source {
+			p.log.Debugf("Skipping blinded path via own node")
+
+			continue
+		}
+
+		fee := blindedPmt.aggregateFee(amt)
+		if tolerance > 0 && fee > maxFee {
+			p.log.Debugf("Skipping blinded path via %v: fee %v "+
+				"exceeds tolerance of %v", introNode, fee,
+				maxFee)
 
-			// Go pathfinding.
 			continue
+		}
 
-		// If there isn't enough local bandwidth, there is no point in
-		// splitting. It won't be possible to create a complete set in
-		// any case, but the sent out partial payments would be held by
-		// the receiver until the mpp timeout.
-		case err == errInsufficientBalance:
-			p.log.Debug("not splitting because local balance " +
-				"is insufficient")
+		// A blinded path that consists of only the introduction node
+		// carries its own final cltv delta, which we need to account
+		// for in our limit and final expiry.
+		cltvDelta := finalCltvDelta + blindedPmt.finalCltvDelta()
+		if uint32(cltvDelta) >= p.payment.CltvLimit {
+			p.log.Debugf("Skipping blinded path via %v: cltv "+
+				"delta %v exceeds limit", introNode, cltvDelta)
 
-			return nil, err
+			continue
+		}
+
+		// The final hop of a blinded route is identified by its
+		// encrypted data rather than a payment address, so we don't
+		// attach the mpp record or metadata.
+		restrictions := *r
+		restrictions.BlindedPayment = blindedPmt
+		restrictions.PaymentAddr = nil
+		restrictions.Metadata = nil
+		restrictions.CltvLimit = p.payment.CltvLimit - uint32(cltvDelta)
+
+		blindedGraph := *g
+		blindedGraph.additionalEdges = blindedPmt.toRouteHints()
+
+		path, probability, err := p.pathFinder(
+			&blindedGraph, &restrictions, cfg, source,
+			blindedPmt.targetNode(), amt, p.payment.TimePref,
+			int32(height)+int32(cltvDelta),
+		)
+
+		switch {
+		// If we can't reach this blinded path, we'll try the others.
+		case err == errNoPathFound:
+			continue
 
 		case err != nil:
 			return nil, err
 		}
 
-		// With the next candidate path found, we'll attempt to turn
-		// this into a route by applying the time-lock and fee
-		// requirements.
-		route, err := newRoute(
-			sourceVertex, path, height,
+		rt, err := newRoute(
+			source, path, height,
 			finalHopParams{
-				amt:         maxAmt,
-				totalAmt:    p.payment.Amount,
-				cltvDelta:   finalCltvDelta,
-				records:     p.payment.DestCustomRecords,
-				paymentAddr: p.payment.PaymentAddr,
-				metadata:    p.payment.Metadata,
-			}, nil,
+				amt:       amt,
+				totalAmt:  p.payment.Amount,
+				cltvDelta: cltvDelta,
+				records:   p.payment.DestCustomRecords,
+			}, blindedPmt.BlindedPath,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		return route, err
+		dist := getProbabilityBasedDist(
+			int64(rt.TotalFees()), probability, attemptCost,
+		)
+		if bestRoute == nil || dist < bestDist {
+			bestRoute = rt
+			bestDist = dist
+		}
+	}
+
+	if bestRoute == nil {
+		return nil, errNoPathFound
 	}
+
+	return bestRoute, nil
 }
 
 // UpdateAdditionalEdge updates the channel edge policy for a private edge. It
@@ -42,6 +42,15 @@ type SessionSource struct {
 	// PathFindingConfig defines global parameters that control the
 	// trade-off in path finding between fees and probability.
 	PathFindingConfig PathFindingConfig
+
+	// RouteCache is an optional cache of recently successful routes that
+	// payment sessions try before running path finding.
+	RouteCache *RouteCache
+
+	// KnownRouteHints is an optional set of route hints for private
+	// destinations that were registered out of band. Payment sessions use
+	// them in addition to the route hints of the payment.
+	KnownRouteHints *KnownRouteHints
 }
 
 // getRoutingGraph returns a routing graph and a clean-up function for
@@ -79,6 +88,20 @@ func (m *SessionSource) NewPaymentSession(p *LightningPayment) (
 	if err != nil {
 		return nil, err
 	}
+	session.routeCache = m.RouteCache
+
+	if m.KnownRouteHints != nil {
+		knownEdges, err := m.KnownRouteHints.edges(p.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		// The route hints of the payment itself take precedence over
+		// known hints for the same channels.
+		session.additionalEdges = mergeAdditionalEdges(
+			session.additionalEdges, knownEdges,
+		)
+	}
 
 	return session, nil
 }
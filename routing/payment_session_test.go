@@ -1,9 +1,12 @@
 package routing
 
 import (
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -252,6 +255,261 @@ func TestRequestRoute(t *testing.T) {
 	}
 }
 
+// TestRequestRouteParallel tests that the path searches for successively
+// smaller shard amounts can run concurrently and yield the same route as the
+// sequential search.
+func TestRequestRouteParallel(t *testing.T) {
+	const height = 10
+
+	testCases := []struct {
+		name             string
+		parallelism      int
+		expectedSearches []lnwire.MilliSatoshi
+	}{
+		{
+			name:        "sequential",
+			parallelism: 1,
+			expectedSearches: []lnwire.MilliSatoshi{
+				1600, 800, 400, 200,
+			},
+		},
+		{
+			// The first batch searches 1600, 800 and 400 msat.
+			// The second batch is cut short by the minimum shard
+			// amount after 200 and 100 msat.
+			name:        "parallel",
+			parallelism: 3,
+			expectedSearches: []lnwire.MilliSatoshi{
+				1600, 800, 400, 200, 100,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			testRequestRouteParallel(
+				t, tc.parallelism, tc.expectedSearches, height,
+			)
+		})
+	}
+}
+
+func testRequestRouteParallel(t *testing.T, parallelism int,
+	expectedSearches []lnwire.MilliSatoshi, height uint32) {
+
+	payment := &LightningPayment{
+		CltvLimit:      300,
+		FinalCLTVDelta: 40,
+		Amount:         1600,
+		FeeLimit:       1000,
+		MaxParts:       16,
+		PaymentAddr:    &[32]byte{1},
+		DestFeatures: lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(
+				lnwire.TLVOnionPayloadOptional,
+				lnwire.PaymentAddrOptional,
+				lnwire.MPPOptional,
+			), lnwire.Features,
+		),
+	}
+
+	var paymentHash [32]byte
+	require.NoError(t, payment.SetPaymentHash(paymentHash))
+
+	session, err := newPaymentSession(
+		payment,
+		func(routingGraph) (bandwidthHints, error) {
+			return &mockBandwidthHints{}, nil
+		},
+		func() (routingGraph, func(), error) {
+			return &sessionGraph{}, func() {}, nil
+		},
+		&MissionControl{},
+		PathFindingConfig{ParallelPathSearches: parallelism},
+	)
+	require.NoError(t, err)
+
+	session.minShardAmt = 100
+
+	var (
+		mu       sync.Mutex
+		searches []lnwire.MilliSatoshi
+	)
+
+	// Override pathfinder with a mock that only finds a path for amounts
+	// up to 250 msat.
+	session.pathFinder = func(_ *graphParams, _ *RestrictParams,
+		_ *PathFindingConfig, _, _ route.Vertex,
+		amt lnwire.MilliSatoshi, _ float64, _ int32) ([]*unifiedEdge,
+		float64, error) {
+
+		mu.Lock()
+		searches = append(searches, amt)
+		mu.Unlock()
+
+		if amt > 250 {
+			return nil, 0, errNoPathFound
+		}
+
+		path := []*unifiedEdge{
+			{
+				policy: &models.CachedEdgePolicy{
+					ToNodePubKey: func() route.Vertex {
+						return route.Vertex{}
+					},
+					ToNodeFeatures: payment.DestFeatures,
+				},
+			},
+		}
+
+		return path, 1.0, nil
+	}
+
+	rt, err := session.RequestRoute(
+		payment.Amount, payment.FeeLimit, 0, height,
+	)
+	require.NoError(t, err)
+
+	// The route for the largest amount that a path was found for is
+	// expected, regardless of how many searches ran concurrently.
+	require.EqualValues(t, 200, rt.ReceiverAmt())
+	require.ElementsMatch(t, expectedSearches, searches)
+}
+
+// TestRequestRouteBlinded tests that a payment session picks the most
+// promising of the blinded paths of a payment and skips blinded paths that
+// charge more than the configured fee tolerance.
+func TestRequestRouteBlinded(t *testing.T) {
+	const (
+		height     = 10
+		cltvLimit  = uint32(300)
+		blindDelta = uint16(40)
+	)
+
+	newBlindedPayment := func(baseFee uint32) *BlindedPayment {
+		introKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		blindingKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return &BlindedPayment{
+			BlindedPath: &sphinx.BlindedPath{
+				IntroductionPoint: introKey.PubKey(),
+				BlindingPoint:     blindingKey.PubKey(),
+				BlindedHops: []*sphinx.BlindedHopInfo{
+					{
+						BlindedNodePub: introKey.PubKey(),
+						CipherText:     []byte{1, 2, 3},
+					},
+				},
+			},
+			BaseFee:         baseFee,
+			CltvExpiryDelta: blindDelta,
+			HtlcMaximum:     100_000,
+		}
+	}
+
+	unreliable := newBlindedPayment(0)
+	reliable := newBlindedPayment(500)
+
+	payment := &LightningPayment{
+		CltvLimit:       cltvLimit,
+		Amount:          10_000,
+		FeeLimit:        1000,
+		BlindedPayments: []*BlindedPayment{unreliable, reliable},
+	}
+	require.NoError(t, payment.SetPaymentHash(lntypes.Hash{}))
+
+	newSession := func(tolerancePPM int64) *paymentSession {
+		session, err := newPaymentSession(
+			payment,
+			func(routingGraph) (bandwidthHints, error) {
+				return &mockBandwidthHints{}, nil
+			},
+			func() (routingGraph, func(), error) {
+				return &sessionGraph{}, func() {}, nil
+			},
+			&MissionControl{},
+			PathFindingConfig{
+				AttemptCost:            100,
+				BlindedFeeTolerancePPM: tolerancePPM,
+			},
+		)
+		require.NoError(t, err)
+
+		// Override pathfinder with a mock that returns a direct path
+		// to the target, which is the introduction node of the
+		// blinded path.
+		session.pathFinder = func(_ *graphParams, r *RestrictParams,
+			_ *PathFindingConfig, _, target route.Vertex,
+			_ lnwire.MilliSatoshi, _ float64, _ int32) (
+			[]*unifiedEdge, float64, error) {
+
+			// The cltv limit must exclude both the block padding
+			// and the delta of the blinded path.
+			require.Equal(
+				t, cltvLimit-uint32(BlockPadding+blindDelta),
+				r.CltvLimit,
+			)
+			require.NotNil(t, r.BlindedPayment)
+			require.Nil(t, r.PaymentAddr)
+
+			probability := 0.1
+			if r.BlindedPayment == reliable {
+				probability = 0.9
+			}
+
+			path := []*unifiedEdge{
+				{
+					policy: &models.CachedEdgePolicy{
+						ToNodePubKey: func() route.Vertex {
+							return target
+						},
+						ToNodeFeatures: lnwire.NewFeatureVector(
+							nil, nil,
+						),
+					},
+				},
+			}
+
+			return path, probability, nil
+		}
+
+		return session
+	}
+
+	// Without a fee tolerance, we expect the most reliable blinded path
+	// to be used.
+	rt, err := newSession(0).RequestRoute(
+		payment.Amount, payment.FeeLimit, 0, height,
+	)
+	require.NoError(t, err)
+
+	introVertex := route.NewVertex(reliable.BlindedPath.IntroductionPoint)
+	require.Equal(t, introVertex, rt.Hops[0].PubKeyBytes)
+	require.Equal(
+		t, reliable.BlindedPath.BlindingPoint, rt.Hops[0].BlindingPoint,
+	)
+	require.Equal(t, payment.Amount, rt.Hops[0].TotalAmtMsat)
+	require.Nil(t, rt.Hops[0].MPP)
+	require.EqualValues(
+		t, height+BlockPadding+blindDelta, rt.TotalTimeLock,
+	)
+
+	// With a tolerance of 1%, the base fee of the reliable path is too
+	// high, so we expect the other path to be used.
+	rt, err = newSession(10_000).RequestRoute(
+		payment.Amount, payment.FeeLimit, 0, height,
+	)
+	require.NoError(t, err)
+
+	introVertex = route.NewVertex(unreliable.BlindedPath.IntroductionPoint)
+	require.Equal(t, introVertex, rt.Hops[0].PubKeyBytes)
+}
+
 type sessionGraph struct {
 	routingGraph
 }
@@ -491,28 +491,15 @@ func (i *interpretedResult) processPaymentOutcomeIntermediate(
 			i.successPairRange(route, 0, introIdx-1)
 		}
 
-		// If the hop after the introduction node that sent us an
-		// error is the final recipient, then we finally fail the
-		// payment because the receiver has generated a blinded route
-		// that they're unable to use. We have this special case so
-		// that we don't penalize the introduction node, and there is
-		// no point in retrying the payment while LND only supports
-		// one blinded route per payment.
-		//
-		// Note that if LND is extended to support multiple blinded
-		// routes, this will terminate the payment without re-trying
-		// the other routes.
-		if introIdx == len(route.Hops)-1 {
-			i.finalFailureReason = &reasonError
-		} else {
-			// If there are other hops between the recipient and
-			// introduction node, then we just penalize the last
-			// hop in the blinded route to minimize the storage of
-			// results for ephemeral keys.
-			i.failPairBalance(
-				route, len(route.Hops)-1,
-			)
-		}
+		// We penalize the last hop in the blinded route to minimize
+		// the storage of results for ephemeral keys. If the hop after
+		// the introduction node is the final recipient, the receiver
+		// has generated a blinded route that they're unable to use.
+		// We don't fail the payment in that case, as the receiver may
+		// have provided other blinded paths that can still be tried.
+		// If there are none, path finding won't find a route anymore
+		// and the payment fails with a no route failure.
+		i.failPairBalance(route, len(route.Hops)-1)
 
 	// In all other cases, we penalize the reporting node. These are all
 	// failures that should not happen.
@@ -525,8 +525,8 @@ var resultTestCases = []resultTestCase{
 			pairResults: map[DirectedNodePair]pairResult{
 				getTestPair(0, 1): successPairResult(100),
 				getTestPair(1, 2): successPairResult(99),
+				getTestPair(2, 3): failPairResult(95),
 			},
-			finalFailureReason: &reasonError,
 		},
 	},
 	// Test the case where a node before the introduction node returns a
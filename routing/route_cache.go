@@ -0,0 +1,194 @@
+package routing
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// routeCacheKey identifies the routes in the route cache. Routes are cached
+// per destination and amount band, so that a route that succeeded for an
+// amount is only reused for amounts of the same order of magnitude.
+type routeCacheKey struct {
+	dest route.Vertex
+	band int
+}
+
+// newRouteCacheKey returns the route cache key for the given destination and
+// amount.
+func newRouteCacheKey(dest route.Vertex,
+	amt lnwire.MilliSatoshi) routeCacheKey {
+
+	// The amount band is the position of the most significant bit of the
+	// amount, which groups amounts by powers of two.
+	return routeCacheKey{
+		dest: dest,
+		band: bits.Len64(uint64(amt)),
+	}
+}
+
+// routeCacheEntry is a path that recently succeeded.
+type routeCacheEntry struct {
+	// hops are the nodes of the path, excluding our own node.
+	hops []route.Vertex
+
+	// chanIDs are the channels that the path used.
+	chanIDs []uint64
+
+	// expiry is the time after which the entry can no longer be used.
+	expiry time.Time
+}
+
+// RouteCache remembers the paths of recently successful payments per
+// destination and amount band, so that repeated payments to the same
+// destinations don't require a full path search. Cached paths expire after a
+// configurable time and are invalidated once any of their channels changes.
+//
+// NOTE: This struct is safe for concurrent access.
+type RouteCache struct {
+	ttl   time.Duration
+	clock clock.Clock
+
+	// entries holds the cached paths.
+	entries map[routeCacheKey]*routeCacheEntry
+
+	// chanIndex maps a channel to the keys of all cached paths that use
+	// it, so that entries can be invalidated on channel updates.
+	chanIndex map[uint64]map[routeCacheKey]struct{}
+
+	mu sync.Mutex
+}
+
+// NewRouteCache creates a new route cache whose entries expire after the
+// given time to live.
+func NewRouteCache(ttl time.Duration, clock clock.Clock) *RouteCache {
+	return &RouteCache{
+		ttl:       ttl,
+		clock:     clock,
+		entries:   make(map[routeCacheKey]*routeCacheEntry),
+		chanIndex: make(map[uint64]map[routeCacheKey]struct{}),
+	}
+}
+
+// AddRoute adds the path of a successful route to the cache. Routes to blinded
+// paths are not cached, as the path towards the introduction node is not
+// useful on its own.
+func (c *RouteCache) AddRoute(rt *route.Route) {
+	if len(rt.Hops) == 0 {
+		return
+	}
+
+	hops := make([]route.Vertex, len(rt.Hops))
+	chanIDs := make([]uint64, len(rt.Hops))
+	for i, hop := range rt.Hops {
+		if hop.BlindingPoint != nil || len(hop.EncryptedData) > 0 {
+			return
+		}
+
+		hops[i] = hop.PubKeyBytes
+		chanIDs[i] = hop.ChannelID
+	}
+
+	key := newRouteCacheKey(hops[len(hops)-1], rt.ReceiverAmt())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Replace any existing entry for the key, so that the index doesn't
+	// keep stale references to it.
+	c.remove(key)
+
+	c.entries[key] = &routeCacheEntry{
+		hops:    hops,
+		chanIDs: chanIDs,
+		expiry:  c.clock.Now().Add(c.ttl),
+	}
+	for _, chanID := range chanIDs {
+		keys, ok := c.chanIndex[chanID]
+		if !ok {
+			keys = make(map[routeCacheKey]struct{})
+			c.chanIndex[chanID] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	log.Debugf("Cached route to %v via %v hops", key.dest, len(hops))
+}
+
+// LookupPath returns the nodes of a cached path to the destination for an
+// amount of the given amount band, if there is one that hasn't expired yet.
+func (c *RouteCache) LookupPath(dest route.Vertex,
+	amt lnwire.MilliSatoshi) ([]route.Vertex, bool) {
+
+	key := newRouteCacheKey(dest, amt)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !c.clock.Now().Before(entry.expiry) {
+		c.remove(key)
+		return nil, false
+	}
+
+	return entry.hops, true
+}
+
+// RemoveRoute removes the cached path that the given route was built from, for
+// example after it failed.
+func (c *RouteCache) RemoveRoute(rt *route.Route) {
+	if len(rt.Hops) == 0 {
+		return
+	}
+
+	key := newRouteCacheKey(
+		rt.Hops[len(rt.Hops)-1].PubKeyBytes, rt.ReceiverAmt(),
+	)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+}
+
+// InvalidateChannels removes all cached paths that use any of the given
+// channels. It is called when the policy of a channel changes or the channel
+// is closed.
+func (c *RouteCache) InvalidateChannels(chanIDs ...uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, chanID := range chanIDs {
+		for key := range c.chanIndex[chanID] {
+			c.remove(key)
+		}
+	}
+}
+
+// remove deletes the entry for the given key from the cache and the channel
+// index. The caller must hold the mutex.
+func (c *RouteCache) remove(key routeCacheKey) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	for _, chanID := range entry.chanIDs {
+		keys := c.chanIndex[chanID]
+		delete(keys, key)
+
+		if len(keys) == 0 {
+			delete(c.chanIndex, chanID)
+		}
+	}
+
+	delete(c.entries, key)
+}
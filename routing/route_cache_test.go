@@ -0,0 +1,98 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRouteCache tests that the route cache returns cached paths per
+// destination and amount band, and that entries expire and are invalidated.
+func TestRouteCache(t *testing.T) {
+	t.Parallel()
+
+	const ttl = time.Minute
+
+	var (
+		node1 = route.Vertex{1}
+		node2 = route.Vertex{2}
+		dest  = route.Vertex{3}
+	)
+
+	newRoute := func(amt lnwire.MilliSatoshi,
+		chanIDs ...uint64) *route.Route {
+
+		return &route.Route{
+			Hops: []*route.Hop{
+				{ChannelID: chanIDs[0], PubKeyBytes: node1},
+				{ChannelID: chanIDs[1], PubKeyBytes: node2},
+				{
+					ChannelID:    chanIDs[2],
+					PubKeyBytes:  dest,
+					AmtToForward: amt,
+				},
+			},
+		}
+	}
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	cache := NewRouteCache(ttl, testClock)
+
+	// Nothing is cached initially.
+	_, ok := cache.LookupPath(dest, 1000)
+	require.False(t, ok)
+
+	// After adding a route, its path is returned for amounts of the same
+	// band, but not for amounts of a different order of magnitude.
+	cache.AddRoute(newRoute(1000, 1, 2, 3))
+
+	hops, ok := cache.LookupPath(dest, 1020)
+	require.True(t, ok)
+	require.Equal(t, []route.Vertex{node1, node2, dest}, hops)
+
+	_, ok = cache.LookupPath(dest, 100_000)
+	require.False(t, ok)
+
+	_, ok = cache.LookupPath(node2, 1000)
+	require.False(t, ok)
+
+	// An update for an unrelated channel leaves the entry in place, but an
+	// update for one of its channels removes it.
+	cache.InvalidateChannels(4)
+	_, ok = cache.LookupPath(dest, 1000)
+	require.True(t, ok)
+
+	cache.InvalidateChannels(2)
+	_, ok = cache.LookupPath(dest, 1000)
+	require.False(t, ok)
+	require.Empty(t, cache.chanIndex)
+
+	// Entries expire after the time to live.
+	cache.AddRoute(newRoute(1000, 1, 2, 3))
+	testClock.SetTime(testClock.Now().Add(ttl))
+	_, ok = cache.LookupPath(dest, 1000)
+	require.False(t, ok)
+	require.Empty(t, cache.entries)
+
+	// A failed route is removed from the cache.
+	rt := newRoute(1000, 1, 2, 3)
+	cache.AddRoute(rt)
+	cache.RemoveRoute(rt)
+	_, ok = cache.LookupPath(dest, 1000)
+	require.False(t, ok)
+
+	// Routes to blinded paths aren't cached.
+	blindingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	rt = newRoute(1000, 1, 2, 3)
+	rt.Hops[1].BlindingPoint = blindingKey.PubKey()
+	cache.AddRoute(rt)
+	_, ok = cache.LookupPath(dest, 1000)
+	require.False(t, ok)
+}
@@ -0,0 +1,204 @@
+package routing
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// HopFees describes the fees charged by a node that forwards a payment along a
+// route.
+type HopFees struct {
+	// Node is the forwarding node.
+	Node route.Vertex
+
+	// IncomingChannel is the channel over which the payment arrives at the
+	// node.
+	IncomingChannel uint64
+
+	// OutgoingChannel is the channel over which the node forwards the
+	// payment.
+	OutgoingChannel uint64
+
+	// AmtToForward is the amount the node forwards over the outgoing
+	// channel.
+	AmtToForward lnwire.MilliSatoshi
+
+	// OutboundFee is the fee the policy of the node for the outgoing
+	// channel charges for forwarding the amount.
+	OutboundFee lnwire.MilliSatoshi
+
+	// InboundFee is the fee the policy of the node for the incoming
+	// channel charges on top of the outbound fee. A negative inbound fee
+	// is a discount, which can't exceed the outbound fee.
+	InboundFee int64
+
+	// Fee is the total fee paid to the node along the route.
+	Fee lnwire.MilliSatoshi
+}
+
+// RouteFees breaks down the fees of a route by the nodes that charge them.
+type RouteFees struct {
+	// Route is the route the fees are paid along.
+	Route *route.Route
+
+	// Hops are the fees charged by each forwarding node of the route, in
+	// the order of the route.
+	Hops []HopFees
+
+	// TotalFees is the total fee paid along the route.
+	TotalFees lnwire.MilliSatoshi
+
+	// OwnOutboundFee is the fee our own policy for the first channel of
+	// the route charges when others forward the amount of the payment over
+	// it. We don't pay it when sending the payment ourselves.
+	OwnOutboundFee lnwire.MilliSatoshi
+
+	// OwnInboundFee is the inbound fee our own policy for the first
+	// channel of the route charges for the amount of the payment when
+	// others forward it to us over that channel.
+	OwnInboundFee int64
+}
+
+// policyFetcher returns the policy the given node set for the given channel.
+type policyFetcher func(chanID uint64,
+	node route.Vertex) (*models.ChannelEdgePolicy, error)
+
+// RouteFees breaks down the fees of the given route by the nodes that charge
+// them, based on the policies in the channel graph.
+func (r *ChannelRouter) RouteFees(rt *route.Route) (*RouteFees, error) {
+	fetchPolicy := func(chanID uint64,
+		node route.Vertex) (*models.ChannelEdgePolicy, error) {
+
+		info, policy1, policy2, err := r.cfg.Graph.FetchChannelEdgesByID(
+			chanID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch channel %v: %w",
+				chanID, err)
+		}
+
+		var policy *models.ChannelEdgePolicy
+		switch node {
+		case info.NodeKey1Bytes:
+			policy = policy1
+
+		case info.NodeKey2Bytes:
+			policy = policy2
+
+		default:
+			return nil, fmt.Errorf("node %v is not an endpoint of "+
+				"channel %v", node, chanID)
+		}
+
+		if policy == nil {
+			return nil, fmt.Errorf("no policy of node %v for "+
+				"channel %v", node, chanID)
+		}
+
+		return policy, nil
+	}
+
+	return calcRouteFees(rt, fetchPolicy)
+}
+
+// calcRouteFees breaks down the fees of the given route by the nodes that
+// charge them, using the given function to look up their policies.
+func calcRouteFees(rt *route.Route, fetchPolicy policyFetcher) (*RouteFees,
+	error) {
+
+	if len(rt.Hops) == 0 {
+		return nil, fmt.Errorf("route has no hops")
+	}
+
+	// The policies of the channels within a blinded path aren't known.
+	for _, hop := range rt.Hops {
+		if hop.EncryptedData != nil {
+			return nil, fmt.Errorf("fees of blinded routes can't " +
+				"be broken down")
+		}
+	}
+
+	routeFees := &RouteFees{
+		Route:     rt,
+		Hops:      make([]HopFees, 0, len(rt.Hops)-1),
+		TotalFees: rt.TotalFees(),
+	}
+
+	// Every hop but the last one is a node that forwards the amount of its
+	// hop over the channel of the next hop.
+	for i := 0; i < len(rt.Hops)-1; i++ {
+		incoming, outgoing := rt.Hops[i], rt.Hops[i+1]
+
+		outPolicy, err := fetchPolicy(
+			outgoing.ChannelID, incoming.PubKeyBytes,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		inboundFee, err := policyInboundFee(
+			fetchPolicy, incoming.ChannelID, incoming.PubKeyBytes,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// The inbound fee is charged on the amount including the
+		// outbound fee, and the total fee of a node can't be negative.
+		outboundFee := outPolicy.ComputeFee(incoming.AmtToForward)
+		inFee := inboundFee.CalcFee(incoming.AmtToForward + outboundFee)
+		inFee = max(inFee, -int64(outboundFee))
+
+		routeFees.Hops = append(routeFees.Hops, HopFees{
+			Node:            incoming.PubKeyBytes,
+			IncomingChannel: incoming.ChannelID,
+			OutgoingChannel: outgoing.ChannelID,
+			AmtToForward:    incoming.AmtToForward,
+			OutboundFee:     outboundFee,
+			InboundFee:      inFee,
+			Fee:             rt.HopFee(i),
+		})
+	}
+
+	// Finally, we'll look at our own policy for the first channel, to show
+	// the fees we charge others for the same amount.
+	firstChan := rt.Hops[0].ChannelID
+	ownPolicy, err := fetchPolicy(firstChan, rt.SourcePubKey)
+	if err != nil {
+		return nil, err
+	}
+	ownInboundFee, err := policyInboundFee(
+		fetchPolicy, firstChan, rt.SourcePubKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routeFees.OwnOutboundFee = ownPolicy.ComputeFee(rt.TotalAmount)
+	routeFees.OwnInboundFee = ownInboundFee.CalcFee(rt.TotalAmount)
+
+	return routeFees, nil
+}
+
+// policyInboundFee returns the inbound fee the given node set for the given
+// channel.
+func policyInboundFee(fetchPolicy policyFetcher, chanID uint64,
+	node route.Vertex) (models.InboundFee, error) {
+
+	policy, err := fetchPolicy(chanID, node)
+	if err != nil {
+		return models.InboundFee{}, err
+	}
+
+	var wireFee lnwire.Fee
+	_, err = policy.ExtraOpaqueData.ExtractRecords(&wireFee)
+	if err != nil {
+		return models.InboundFee{}, fmt.Errorf("unable to parse "+
+			"inbound fee of channel %v: %w", chanID, err)
+	}
+
+	return models.NewInboundFeeFromWire(wireFee), nil
+}
@@ -0,0 +1,126 @@
+package routing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// policyKey identifies the policy a node set for a channel.
+type policyKey struct {
+	chanID uint64
+	node   route.Vertex
+}
+
+// newFeePolicy creates a policy with the given outbound and inbound fees.
+func newFeePolicy(t *testing.T, base, rate lnwire.MilliSatoshi,
+	inboundBase int32) *models.ChannelEdgePolicy {
+
+	policy := &models.ChannelEdgePolicy{
+		FeeBaseMSat:               base,
+		FeeProportionalMillionths: rate,
+	}
+
+	wireFee := lnwire.Fee{BaseFee: inboundBase}
+	require.NoError(t, policy.ExtraOpaqueData.PackRecords(&wireFee))
+
+	return policy
+}
+
+// TestCalcRouteFees tests that the fees of a route are broken down by the
+// nodes that charge them.
+func TestCalcRouteFees(t *testing.T) {
+	t.Parallel()
+
+	var source, alice, bob, dave route.Vertex
+	source[0], alice[0], bob[0], dave[0] = 1, 2, 3, 4
+
+	policies := map[policyKey]*models.ChannelEdgePolicy{
+		// Our own policy for the first channel.
+		{1, source}: newFeePolicy(t, 10, 1000, 5),
+
+		// Alice charges an inbound fee on top of her outbound fee.
+		{1, alice}: newFeePolicy(t, 0, 0, 500),
+		{2, alice}: newFeePolicy(t, 1000, 0, 0),
+
+		// Bob's inbound discount exceeds his outbound fee, so he
+		// forwards for free.
+		{2, bob}: newFeePolicy(t, 0, 0, -2000),
+		{3, bob}: newFeePolicy(t, 1000, 0, 0),
+	}
+	fetchPolicy := func(chanID uint64,
+		node route.Vertex) (*models.ChannelEdgePolicy, error) {
+
+		policy, ok := policies[policyKey{chanID, node}]
+		if !ok {
+			return nil, fmt.Errorf("no policy")
+		}
+
+		return policy, nil
+	}
+
+	rt := &route.Route{
+		TotalAmount:  101_500,
+		SourcePubKey: source,
+		Hops: []*route.Hop{
+			{
+				PubKeyBytes:  alice,
+				ChannelID:    1,
+				AmtToForward: 100_000,
+			},
+			{
+				PubKeyBytes:  bob,
+				ChannelID:    2,
+				AmtToForward: 100_000,
+			},
+			{
+				PubKeyBytes:  dave,
+				ChannelID:    3,
+				AmtToForward: 100_000,
+			},
+		},
+	}
+
+	fees, err := calcRouteFees(rt, fetchPolicy)
+	require.NoError(t, err)
+
+	require.Equal(t, []HopFees{
+		{
+			Node:            alice,
+			IncomingChannel: 1,
+			OutgoingChannel: 2,
+			AmtToForward:    100_000,
+			OutboundFee:     1000,
+			InboundFee:      500,
+			Fee:             1500,
+		},
+		{
+			Node:            bob,
+			IncomingChannel: 2,
+			OutgoingChannel: 3,
+			AmtToForward:    100_000,
+			OutboundFee:     1000,
+			InboundFee:      -1000,
+			Fee:             0,
+		},
+	}, fees.Hops)
+	require.EqualValues(t, 1500, fees.TotalFees)
+
+	// Our own fees are those we'd charge for the total amount.
+	require.EqualValues(t, 111, fees.OwnOutboundFee)
+	require.EqualValues(t, 5, fees.OwnInboundFee)
+
+	// Without the policy of a hop, the fees can't be broken down.
+	delete(policies, policyKey{3, bob})
+	_, err = calcRouteFees(rt, fetchPolicy)
+	require.Error(t, err)
+
+	// Neither can those of blinded routes.
+	rt.Hops[2].EncryptedData = []byte{1}
+	_, err = calcRouteFees(rt, fetchPolicy)
+	require.ErrorContains(t, err, "blinded")
+}
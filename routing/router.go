@@ -33,6 +33,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chanvalidate"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring/tracing"
 	"github.com/lightningnetwork/lnd/multimutex"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing/chainview"
@@ -40,6 +41,8 @@ import (
 	"github.com/lightningnetwork/lnd/routing/shards"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/zpay32"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -94,6 +97,10 @@ var (
 	// shutting down.
 	ErrRouterShuttingDown = fmt.Errorf("router shutting down")
 
+	// ErrPaymentNotActive is returned when a payment is canceled that
+	// doesn't have a running payment lifecycle.
+	ErrPaymentNotActive = errors.New("payment not active")
+
 	// ErrSelfIntro is a failure returned when the source node of a
 	// route request is also the introduction node. This is not yet
 	// supported because LND does not support blinded forwardingg.
@@ -273,6 +280,12 @@ type MissionController interface {
 	// payment from fromNode along edge.
 	GetProbability(fromNode, toNode route.Vertex,
 		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
+
+	// GetEndorsementHint returns true if toNode is likely to give endorsed
+	// treatment to HTLCs that it receives from fromNode, based on the
+	// payments that succeeded across the pair within the given window.
+	GetEndorsementHint(fromNode, toNode route.Vertex,
+		window time.Duration) bool
 }
 
 // FeeSchema is the set fee configuration for a Lightning Node on the network.
@@ -411,6 +424,16 @@ type Config struct {
 	// IsAlias returns whether a passed ShortChannelID is an alias. This is
 	// only used for our local channels.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// RouteCache is an optional cache of recently successful routes. If
+	// set, the paths of successful payments are added to it and cached
+	// paths are invalidated when their channels change.
+	RouteCache *RouteCache
+
+	// LocalPaymentSettler is used to settle payments to our own node
+	// without sending them over the network. If nil, such payments are
+	// rejected and payments to self require a circular route.
+	LocalPaymentSettler LocalPaymentSettler
 }
 
 // EdgeLocator is a struct used to identify a specific edge.
@@ -495,6 +518,10 @@ type ChannelRouter struct {
 	// announcements over a window of defaultStatInterval.
 	stats *routerStats
 
+	// activePayments maps the identifier of every payment that currently
+	// has a running payment lifecycle to the function that cancels it.
+	activePayments lnutils.SyncMap[lntypes.Hash, context.CancelFunc]
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -1103,7 +1130,23 @@ func (r *ChannelRouter) handleNetworkUpdate(vb *ValidationBarrier,
 	update *routingMsg) {
 
 	defer r.wg.Done()
-	defer vb.CompleteJob()
+
+	// Only channel announcements are validated against the chain, which is
+	// what the number of concurrent jobs is limited for. Node
+	// announcements and channel updates just wait for the graph store to
+	// commit them, so we release their slot right away. This allows many
+	// of them to be buffered and committed in the same batch, rather than
+	// one small batch per commit interval.
+	var jobCompleted sync.Once
+	completeJob := func() {
+		jobCompleted.Do(vb.CompleteJob)
+	}
+	defer completeJob()
+
+	switch update.msg.(type) {
+	case *channeldb.LightningNode, *models.ChannelEdgePolicy:
+		completeJob()
+	}
 
 	// If this message has an existing dependency, then we'll wait until
 	// that has been fully validated before we proceed.
@@ -1455,6 +1498,13 @@ func (r *ChannelRouter) updateGraphWithClosedChannels(
 		return err
 	}
 
+	// Drop any cached paths that used one of the closed channels.
+	if r.cfg.RouteCache != nil {
+		for _, edge := range chansClosed {
+			r.cfg.RouteCache.InvalidateChannels(edge.ChannelID)
+		}
+	}
+
 	// Notify all currently registered clients of the newly closed channels.
 	closeSummaries := createCloseSummaries(blockHeight, chansClosed...)
 	r.notifyTopologyChange(&TopologyChange{
@@ -1521,7 +1571,7 @@ func (r *ChannelRouter) addZombieEdge(chanID uint64) error {
 //
 // TODO(roasbeef: export and use elsewhere?
 func makeFundingScript(bitcoinKey1, bitcoinKey2 []byte,
-	chanFeatures []byte) ([]byte, error) {
+	chanFeatures []byte, taproot bool) ([]byte, error) {
 
 	legacyFundingScript := func() ([]byte, error) {
 		witnessScript, err := input.GenMultiSigScript(
@@ -1538,6 +1588,32 @@ func makeFundingScript(bitcoinKey1, bitcoinKey2 []byte,
 		return pkScript, nil
 	}
 
+	taprootFundingScript := func() ([]byte, error) {
+		pubKey1, err := btcec.ParsePubKey(bitcoinKey1)
+		if err != nil {
+			return nil, err
+		}
+		pubKey2, err := btcec.ParsePubKey(bitcoinKey2)
+		if err != nil {
+			return nil, err
+		}
+
+		fundingScript, _, err := input.GenTaprootFundingScript(
+			pubKey1, pubKey2, 0,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return fundingScript, nil
+	}
+
+	// Channels announced with a ChannelAnnouncement2 are always taproot
+	// channels, regardless of their feature bits.
+	if taproot {
+		return taprootFundingScript()
+	}
+
 	if len(chanFeatures) == 0 {
 		return legacyFundingScript()
 	}
@@ -1558,23 +1634,7 @@ func makeFundingScript(bitcoinKey1, bitcoinKey2 []byte,
 		lnwire.SimpleTaprootChannelsOptionalStaging,
 	) {
 
-		pubKey1, err := btcec.ParsePubKey(bitcoinKey1)
-		if err != nil {
-			return nil, err
-		}
-		pubKey2, err := btcec.ParsePubKey(bitcoinKey2)
-		if err != nil {
-			return nil, err
-		}
-
-		fundingScript, _, err := input.GenTaprootFundingScript(
-			pubKey1, pubKey2, 0,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		return fundingScript, nil
+		return taprootFundingScript()
 	}
 
 	return legacyFundingScript()
@@ -1692,9 +1752,11 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 		// Recreate witness output to be sure that declared in channel
 		// edge bitcoin keys and channel value corresponds to the
 		// reality.
+		taprootProof := msg.AuthProof != nil &&
+			msg.AuthProof.IsTaproot()
 		fundingPkScript, err := makeFundingScript(
 			msg.BitcoinKey1Bytes[:], msg.BitcoinKey2Bytes[:],
-			msg.Features,
+			msg.Features, taprootProof,
 		)
 		if err != nil {
 			return err
@@ -1742,9 +1804,19 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 				msg.ChannelID, fundingPoint, err)
 		}
 
+		// The capacity is part of the signed ChannelAnnouncement2, so
+		// it must match the value of the funding output.
+		capacity := btcutil.Amount(chanUtxo.Value)
+		if taprootProof && msg.Capacity != capacity {
+			return newErrf(ErrInvalidFundingOutput, "announced "+
+				"capacity %v of chan_id=%v doesn't match "+
+				"funding output value %v", msg.Capacity,
+				msg.ChannelID, capacity)
+		}
+
 		// TODO(roasbeef): this is a hack, needs to be removed
 		// after commitment fees are dynamic.
-		msg.Capacity = btcutil.Amount(chanUtxo.Value)
+		msg.Capacity = capacity
 		msg.ChannelPoint = *fundingPoint
 		if err := r.cfg.Graph.AddChannelEdge(msg, op...); err != nil {
 			return errors.Errorf("unable to add edge: %v", err)
@@ -1861,6 +1933,12 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 			newLogClosure(func() string { return spew.Sdump(msg) }))
 		r.stats.incNumChannelUpdates()
 
+		// Paths that were cached with the previous policy of this
+		// channel may no longer be the best choice.
+		if r.cfg.RouteCache != nil {
+			r.cfg.RouteCache.InvalidateChannels(msg.ChannelID)
+		}
+
 	default:
 		return errors.Errorf("wrong routing update message type")
 	}
@@ -2034,9 +2112,7 @@ func NewRouteRequest(source route.Vertex, target *route.Vertex,
 		// delta). In the case of a multi-hop route, we set our final
 		// cltv to zero, since it's going to be accounted for in the
 		// delta for our hints.
-		if len(blindedPayment.BlindedPath.BlindedHops) == 1 {
-			requestExpiry = blindedPayment.CltvExpiryDelta
-		}
+		requestExpiry = blindedPayment.finalCltvDelta()
 
 		requestHints = blindedPayment.toRouteHints()
 	}
@@ -2074,16 +2150,8 @@ func getTargetNode(target *route.Vertex, blindedPayment *BlindedPayment) (
 	case blinded:
 		// If we're dealing with an edge-case blinded path that just
 		// has an introduction node (first hop expected to be the intro
-		// hop), then we return the unblinded introduction node as our
-		// target.
-		hops := blindedPayment.BlindedPath.BlindedHops
-		if len(hops) == 1 {
-			return route.NewVertex(
-				blindedPayment.BlindedPath.IntroductionPoint,
-			), nil
-		}
-
-		return route.NewVertex(hops[len(hops)-1].BlindedNodePub), nil
+		// hop), then the unblinded introduction node is our target.
+		return blindedPayment.targetNode(), nil
 
 	case targetSet:
 		return *target, nil
@@ -2174,6 +2242,140 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 	return route, probability, nil
 }
 
+// RouteDiversity describes how the routes that are returned by FindRoutes must
+// differ from each other.
+type RouteDiversity uint8
+
+const (
+	// DiversityEdgeDisjoint requires the routes to not share any directed
+	// node pair.
+	DiversityEdgeDisjoint RouteDiversity = iota
+
+	// DiversityNodeDisjoint requires the routes to not share any node
+	// other than the source and the target.
+	DiversityNodeDisjoint
+)
+
+// String returns a human readable representation of the route diversity.
+func (d RouteDiversity) String() string {
+	switch d {
+	case DiversityEdgeDisjoint:
+		return "edge-disjoint"
+
+	case DiversityNodeDisjoint:
+		return "node-disjoint"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(d))
+	}
+}
+
+// FindRoutes attempts to query the ChannelRouter for up to numRoutes routes to
+// the target that satisfy the given diversity constraint. The routes are found
+// one after another, excluding the pairs or nodes of the previously found
+// routes from the search. Along with the routes, their success probabilities
+// are returned. An error is only returned if not a single route can be found.
+func (r *ChannelRouter) FindRoutes(req *RouteRequest, numRoutes int,
+	diversity RouteDiversity) ([]*route.Route, []float64, error) {
+
+	if numRoutes < 1 {
+		return nil, nil, fmt.Errorf("invalid number of routes: %v",
+			numRoutes)
+	}
+
+	if diversity != DiversityEdgeDisjoint &&
+		diversity != DiversityNodeDisjoint {
+
+		return nil, nil, fmt.Errorf("unknown route diversity: %v",
+			diversity)
+	}
+
+	var (
+		routes        []*route.Route
+		probabilities []float64
+
+		excludedPairs = make(map[DirectedNodePair]struct{})
+		excludedNodes = make(map[route.Vertex]struct{})
+	)
+
+	for len(routes) < numRoutes {
+		// Copy the restrictions of the request, so that we can exclude
+		// the parts of the routes that were found so far without
+		// modifying the caller's request.
+		restrictions := *req.Restrictions
+		probabilitySource := req.Restrictions.ProbabilitySource
+		restrictions.ProbabilitySource = func(fromNode,
+			toNode route.Vertex, amt lnwire.MilliSatoshi,
+			capacity btcutil.Amount) float64 {
+
+			pair := NewDirectedNodePair(fromNode, toNode)
+			if _, ok := excludedPairs[pair]; ok {
+				return 0
+			}
+
+			if _, ok := excludedNodes[toNode]; ok {
+				return 0
+			}
+
+			return probabilitySource(
+				fromNode, toNode, amt, capacity,
+			)
+		}
+
+		routeReq := *req
+		routeReq.Restrictions = &restrictions
+
+		rt, probability, err := r.FindRoute(&routeReq)
+
+		_, isNoRouteErr := err.(noRouteError)
+		switch {
+		// If we already found some routes, running out of diverse
+		// routes isn't an error.
+		case isNoRouteErr && len(routes) > 0:
+			return routes, probabilities, nil
+
+		case err != nil:
+			return nil, nil, err
+		}
+
+		routes = append(routes, rt)
+		probabilities = append(probabilities, probability)
+
+		// Exclude the parts of the route that the next routes may not
+		// share with it.
+		fromNode := rt.SourcePubKey
+		for i, hop := range rt.Hops {
+			switch diversity {
+			case DiversityEdgeDisjoint:
+				pair := NewDirectedNodePair(
+					fromNode, hop.PubKeyBytes,
+				)
+				excludedPairs[pair] = struct{}{}
+
+			case DiversityNodeDisjoint:
+				if i < len(rt.Hops)-1 {
+					excludedNodes[hop.PubKeyBytes] =
+						struct{}{}
+				}
+			}
+
+			fromNode = hop.PubKeyBytes
+		}
+
+		// A direct route can't be made node-disjoint by excluding
+		// intermediate nodes, so exclude the pair instead to avoid
+		// returning the same route again.
+		if diversity == DiversityNodeDisjoint && len(rt.Hops) == 1 {
+			pair := NewDirectedNodePair(
+				rt.SourcePubKey, rt.Hops[0].PubKeyBytes,
+			)
+			excludedPairs[pair] = struct{}{}
+		}
+	}
+
+	return routes, probabilities, nil
+}
+
 // generateNewSessionKey generates a new ephemeral private key to be used for a
 // payment attempt.
 func generateNewSessionKey() (*btcec.PrivateKey, error) {
@@ -2349,6 +2551,36 @@ type LightningPayment struct {
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// BlindedPayments is an optional set of blinded paths that lead to
+	// the receiver. If set, every shard of the payment is sent through
+	// one of these paths and the Target, RouteHints and PaymentAddr
+	// fields are not used for path finding.
+	BlindedPayments []*BlindedPayment
+
+	// LocalSettle indicates that this is a payment to our own node that
+	// should be settled directly with our invoice registry, rather than
+	// over a circular route through the network.
+	LocalSettle bool
+
+	// ShadowRouteMaxCltv overrides the maximum number of blocks of random
+	// padding that the router adds to the final CLTV delta of this
+	// payment's routes.
+	//
+	// NOTE: This field is _optional_.
+	ShadowRouteMaxCltv *uint16
+
+	// AmountRandomization overrides the strategy the router uses to pick
+	// the amounts of this payment's shards. The router's default is used
+	// if it's AmountRandomizationDefault.
+	AmountRandomization AmountRandomization
+
+	// Trampoline, if set, routes the payment through the given trampoline
+	// node. PreparePayment rewrites the payment into a payment to the
+	// trampoline node, which relays it to the target.
+	//
+	// NOTE: This field is _optional_.
+	Trampoline *TrampolineHop
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -2440,6 +2672,24 @@ func (r *ChannelRouter) SendPaymentAsync(ctx context.Context,
 	}()
 }
 
+// CancelPayment cancels the in-flight payment with the given identifier. No new
+// HTLC attempts are made for the payment once it is canceled and the payment
+// is marked as failed. HTLC attempts that are already in flight can't be
+// recalled, so the payment lifecycle keeps waiting for their results, after
+// which the final status of the payment is reported to its subscribers.
+func (r *ChannelRouter) CancelPayment(identifier lntypes.Hash) error {
+	cancelPayment, ok := r.activePayments.Load(identifier)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrPaymentNotActive, identifier)
+	}
+
+	log.Infof("Canceling payment %v", identifier)
+
+	cancelPayment()
+
+	return nil
+}
+
 // spewPayment returns a log closures that provides a spewed string
 // representation of the passed payment.
 func spewPayment(payment *LightningPayment) logClosure {
@@ -2466,6 +2716,39 @@ func spewPayment(payment *LightningPayment) logClosure {
 func (r *ChannelRouter) PreparePayment(payment *LightningPayment) (
 	PaymentSession, shards.ShardTracker, error) {
 
+	// Payments can only be settled locally if they are made to ourselves.
+	if payment.LocalSettle {
+		switch {
+		case r.cfg.LocalPaymentSettler == nil:
+			return nil, nil, errors.New("local settlement of " +
+				"payments not supported")
+
+		case payment.Target != r.selfNode.PubKeyBytes:
+			return nil, nil, errors.New("only payments to self " +
+				"can be settled locally")
+		}
+	}
+
+	if payment.ShadowRouteMaxCltv != nil &&
+		*payment.ShadowRouteMaxCltv > MaxShadowRouteMaxCltv {
+
+		return nil, nil, fmt.Errorf("shadow route max cltv %v exceeds "+
+			"maximum of %v", *payment.ShadowRouteMaxCltv,
+			MaxShadowRouteMaxCltv)
+	}
+
+	if payment.Trampoline != nil {
+		_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = payment.routeThroughTrampoline(uint32(currentHeight))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Before starting the HTLC routing attempt, we'll create a fresh
 	// payment session which will report our errors back to mission
 	// control.
@@ -2715,6 +2998,15 @@ func (r *ChannelRouter) sendPayment(ctx context.Context,
 	// deadline.
 	defer cancel()
 
+	// Make the payment cancelable through CancelPayment for as long as its
+	// lifecycle is running.
+	ctx, cancelPayment := context.WithCancel(ctx)
+	r.activePayments.Store(identifier, cancelPayment)
+	defer func() {
+		r.activePayments.Delete(identifier)
+		cancelPayment()
+	}()
+
 	// We'll also fetch the current block height, so we can properly
 	// calculate the required HTLC time locks within the route.
 	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
@@ -2729,7 +3021,23 @@ func (r *ChannelRouter) sendPayment(ctx context.Context,
 		currentHeight,
 	)
 
-	return p.resumePayment(ctx)
+	// Trace the payment as a child of the span of the call that made it,
+	// if any. The trace ID is logged so the payment can be looked up in
+	// the collector.
+	var span trace.Span
+	p.traceCtx, span = tracing.StartSpan(
+		ctx, "routing.SendPayment",
+		attribute.String("payment_hash", identifier.String()),
+	)
+	if traceID := tracing.TraceID(p.traceCtx); traceID != "" {
+		log.Infof("Tracing payment %v with trace_id=%v", identifier,
+			traceID)
+	}
+
+	preimage, rt, err := p.resumePayment(ctx)
+	tracing.EndSpan(span, err)
+
+	return preimage, rt, err
 }
 
 // extractChannelUpdate examines the error and extracts the channel update.
@@ -3173,7 +3481,7 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 func getRouteUnifiers(source route.Vertex, hops []route.Vertex,
 	useMinAmt bool, runningAmt lnwire.MilliSatoshi,
 	outgoingChans map[uint64]struct{}, graph routingGraph,
-	bandwidthHints *bandwidthManager) ([]*edgeUnifier, lnwire.MilliSatoshi,
+	bandwidthHints bandwidthHints) ([]*edgeUnifier, lnwire.MilliSatoshi,
 	error) {
 
 	// Allocate a list that will contain the edge unifiers for this route.
@@ -3252,7 +3560,7 @@ func getRouteUnifiers(source route.Vertex, hops []route.Vertex,
 // getPathEdges returns the edges that make up the path and the total amount,
 // including fees, to send the payment.
 func getPathEdges(source route.Vertex, receiverAmt lnwire.MilliSatoshi,
-	unifiers []*edgeUnifier, bandwidthHints *bandwidthManager,
+	unifiers []*edgeUnifier, bandwidthHints bandwidthHints,
 	hops []route.Vertex) ([]*unifiedEdge,
 	lnwire.MilliSatoshi, error) {
 
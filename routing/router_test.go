@@ -2,6 +2,7 @@ package routing
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image/color"
 	"math"
@@ -22,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/input"
 	lnmock "github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntest/wait"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -297,6 +299,73 @@ func TestFindRoutesWithFeeLimit(t *testing.T) {
 	)
 }
 
+// TestFindRoutesDiversity tests that multiple routes can be queried at once
+// and that they satisfy the requested diversity constraint.
+func TestFindRoutesDiversity(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxFromFile(t, startingBlockHeight, basicGraphFilePath)
+
+	target := ctx.aliases["sophon"]
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	restrictions := &RestrictParams{
+		FeeLimit:          lnwire.NewMSatFromSatoshis(1000),
+		ProbabilitySource: noProbabilitySource,
+		CltvLimit:         math.MaxUint32,
+	}
+
+	req, err := NewRouteRequest(
+		ctx.router.selfNode.PubKeyBytes, &target, paymentAmt, 0,
+		restrictions, nil, nil, nil, MinCLTVDelta,
+	)
+	require.NoError(t, err, "invalid route request")
+
+	for _, diversity := range []RouteDiversity{
+		DiversityEdgeDisjoint, DiversityNodeDisjoint,
+	} {
+		routes, probabilities, err := ctx.router.FindRoutes(
+			req, 5, diversity,
+		)
+		require.NoError(t, err, diversity)
+
+		// There are multiple routes from roasbeef to sophon, but
+		// fewer than requested, in which case all diverse routes are
+		// returned.
+		require.Greater(t, len(routes), 1, diversity)
+		require.Less(t, len(routes), 5, diversity)
+		require.Len(t, probabilities, len(routes), diversity)
+
+		pairs := make(map[DirectedNodePair]struct{})
+		nodes := make(map[route.Vertex]struct{})
+		for _, rt := range routes {
+			fromNode := rt.SourcePubKey
+			for i, hop := range rt.Hops {
+				pair := NewDirectedNodePair(
+					fromNode, hop.PubKeyBytes,
+				)
+				require.NotContains(t, pairs, pair, diversity)
+				pairs[pair] = struct{}{}
+
+				if diversity == DiversityNodeDisjoint &&
+					i < len(rt.Hops)-1 {
+
+					require.NotContains(
+						t, nodes, hop.PubKeyBytes,
+					)
+					nodes[hop.PubKeyBytes] = struct{}{}
+				}
+
+				fromNode = hop.PubKeyBytes
+			}
+		}
+	}
+
+	// Asking for no routes at all is invalid.
+	_, _, err = ctx.router.FindRoutes(req, 0, DiversityEdgeDisjoint)
+	require.Error(t, err)
+}
+
 // TestSendPaymentRouteFailureFallback tests that when sending a payment, if
 // one of the target routes is seen as unavailable, then the next route in the
 // queue is used instead. This process should continue until either a payment
@@ -1349,6 +1418,77 @@ func TestIgnoreChannelEdgePolicyForUnknownChannel(t *testing.T) {
 	}
 }
 
+// TestAddTaprootProofEdge tests that an edge with the proof of a
+// ChannelAnnouncement2 is validated against a taproot funding output and the
+// announced capacity.
+func TestAddTaprootProofEdge(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxFromFile(t, startingBlockHeight, basicGraphFilePath)
+
+	const chanValue = btcutil.Amount(100_000)
+	fundingScript, fundingOut, err := input.GenTaprootFundingScript(
+		bitcoinKey1, bitcoinKey2, int64(chanValue),
+	)
+	require.NoError(t, err)
+
+	fundingTx := wire.NewMsgTx(2)
+	fundingTx.TxOut = append(fundingTx.TxOut, fundingOut)
+	ctx.chain.addUtxo(wire.OutPoint{Hash: fundingTx.TxHash()}, fundingOut)
+
+	chanID := lnwire.ShortChannelID{BlockHeight: 102}
+	ctx.chain.addBlock(&wire.MsgBlock{
+		Transactions: []*wire.MsgTx{fundingTx},
+	}, chanID.BlockHeight, chanID.BlockHeight)
+
+	var pub1, pub2 [33]byte
+	copy(pub1[:], priv1.PubKey().SerializeCompressed())
+	copy(pub2[:], priv2.PubKey().SerializeCompressed())
+
+	newEdge := func(capacity btcutil.Amount) *models.ChannelEdgeInfo {
+		edge := &models.ChannelEdgeInfo{
+			ChannelID: chanID.ToUint64(),
+			Capacity:  capacity,
+			AuthProof: &models.ChannelAuthProof{
+				SchnorrSigBytes: testSig.Serialize()[:64],
+			},
+		}
+		copy(edge.NodeKey1Bytes[:], pub1[:])
+		copy(edge.NodeKey2Bytes[:], pub2[:])
+		copy(
+			edge.BitcoinKey1Bytes[:],
+			bitcoinKey1.SerializeCompressed(),
+		)
+		copy(
+			edge.BitcoinKey2Bytes[:],
+			bitcoinKey2.SerializeCompressed(),
+		)
+
+		return edge
+	}
+
+	// The capacity is covered by the signature of the announcement, so it
+	// must match the value of the funding output.
+	err = ctx.router.AddEdge(newEdge(chanValue - 1))
+	require.True(t, IsError(err, ErrInvalidFundingOutput), err)
+
+	// Even though the edge doesn't signal the taproot channel feature
+	// bit, its taproot funding output is found.
+	require.NoError(t, ctx.router.AddEdge(newEdge(chanValue)))
+
+	edge, _, _, err := ctx.router.GetChannelByID(chanID)
+	require.NoError(t, err)
+	require.True(t, edge.AuthProof.IsTaproot())
+	require.Equal(t, chanValue, edge.Capacity)
+
+	pkScript, err := makeFundingScript(
+		edge.BitcoinKey1Bytes[:], edge.BitcoinKey2Bytes[:], nil, true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, fundingScript, pkScript)
+}
+
 // TestAddEdgeUnknownVertexes tests that if an edge is added that contains two
 // vertexes which we don't know of, the edge should be available for use
 // regardless. This is due to the fact that we don't actually need node
@@ -3996,3 +4136,26 @@ func TestNewRouteRequest(t *testing.T) {
 		})
 	}
 }
+
+// TestCancelPayment tests that only payments with a running lifecycle can be
+// canceled and that canceling them cancels the context of their lifecycle.
+func TestCancelPayment(t *testing.T) {
+	t.Parallel()
+
+	router := &ChannelRouter{}
+	paymentHash := lntypes.Hash{1, 2, 3}
+
+	// A payment without a running lifecycle can't be canceled.
+	err := router.CancelPayment(paymentHash)
+	require.ErrorIs(t, err, ErrPaymentNotActive)
+
+	// Register the cancel function of a payment lifecycle, like sending
+	// the payment does, and cancel the payment.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router.activePayments.Store(paymentHash, cancel)
+
+	require.NoError(t, router.CancelPayment(paymentHash))
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
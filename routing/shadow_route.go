@@ -0,0 +1,124 @@
+package routing
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultShadowRouteMaxCltv is the default maximum number of blocks
+	// of shadow route padding that is added to the final CLTV delta of a
+	// payment. Shadow route padding is disabled by default.
+	DefaultShadowRouteMaxCltv = uint16(0)
+
+	// MaxShadowRouteMaxCltv is the largest maximum shadow route padding
+	// that can be configured. Padding the final CLTV delta with more than
+	// a day worth of blocks locks up liquidity along the route for longer
+	// than is needed to blend in with longer routes.
+	MaxShadowRouteMaxCltv = uint16(144)
+
+	// DefaultAmountRandomization is the name of the default strategy used
+	// to pick the amounts of the shards of a payment.
+	DefaultAmountRandomization = "none"
+)
+
+// AmountRandomization is the strategy used to pick the amount of the next
+// shard when no route can be found for the current amount of a payment.
+type AmountRandomization uint8
+
+const (
+	// AmountRandomizationDefault uses the strategy that is configured for
+	// the router. It is only meaningful as a per-payment override.
+	AmountRandomizationDefault AmountRandomization = iota
+
+	// AmountRandomizationNone halves the amount of the next shard. The
+	// resulting shard amounts are exact fractions of the payment amount,
+	// which makes it easier for intermediate nodes to relate shards of
+	// the same payment to each other.
+	AmountRandomizationNone
+
+	// AmountRandomizationShard picks the amount of the next shard at
+	// random between three eighths and five eighths of the current
+	// amount.
+	AmountRandomizationShard
+)
+
+// String returns a human-readable representation of the strategy.
+func (a AmountRandomization) String() string {
+	switch a {
+	case AmountRandomizationDefault:
+		return "default"
+
+	case AmountRandomizationNone:
+		return "none"
+
+	case AmountRandomizationShard:
+		return "shard"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(a))
+	}
+}
+
+// ParseAmountRandomization parses the name of an amount randomization
+// strategy as it's used in the configuration.
+func ParseAmountRandomization(name string) (AmountRandomization, error) {
+	switch name {
+	case "none":
+		return AmountRandomizationNone, nil
+
+	case "shard":
+		return AmountRandomizationShard, nil
+
+	default:
+		return 0, fmt.Errorf("unknown amount randomization "+
+			"strategy: %v", name)
+	}
+}
+
+// shadowCltvPadding returns a random number of blocks, up to the given
+// maximum, that is added to the final CLTV delta of a route. The padding makes
+// the route look longer to the receiver than it is, so that it can't infer
+// its distance to the sender from the CLTV of the HTLC. At most half of the
+// CLTV budget that is left after the final CLTV delta is used, so that there
+// is still room for finding a path.
+func shadowCltvPadding(maxPadding uint16, cltvLimit uint32,
+	finalCltvDelta uint16) uint16 {
+
+	if cltvLimit <= uint32(finalCltvDelta) {
+		return 0
+	}
+
+	budget := (cltvLimit - uint32(finalCltvDelta)) / 2
+	if uint32(maxPadding) > budget {
+		maxPadding = uint16(budget)
+	}
+
+	if maxPadding == 0 {
+		return 0
+	}
+
+	return uint16(rand.Intn(int(maxPadding) + 1))
+}
+
+// splitAmount returns the amount of the next shard to try when no route could
+// be found for the given amount, following the given strategy.
+func splitAmount(amt lnwire.MilliSatoshi,
+	strategy AmountRandomization) lnwire.MilliSatoshi {
+
+	if strategy != AmountRandomizationShard {
+		return amt / 2
+	}
+
+	// Pick a random amount in [3/8, 5/8] of the current amount, which is
+	// centered around the amount we'd use without randomization.
+	minAmt := amt / 8 * 3
+	jitter := amt / 4
+	if jitter == 0 {
+		return amt / 2
+	}
+
+	return minAmt + lnwire.MilliSatoshi(rand.Int63n(int64(jitter)+1))
+}
@@ -0,0 +1,215 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShadowCltvPadding tests that the shadow route padding stays within the
+// configured maximum and the CLTV budget of the payment.
+func TestShadowCltvPadding(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		maxPadding     uint16
+		cltvLimit      uint32
+		finalCltvDelta uint16
+		expectedMax    uint16
+	}{
+		{
+			name:           "disabled",
+			maxPadding:     0,
+			cltvLimit:      1000,
+			finalCltvDelta: 40,
+			expectedMax:    0,
+		},
+		{
+			name:           "within budget",
+			maxPadding:     50,
+			cltvLimit:      1000,
+			finalCltvDelta: 40,
+			expectedMax:    50,
+		},
+		{
+			name:           "limited by budget",
+			maxPadding:     144,
+			cltvLimit:      140,
+			finalCltvDelta: 40,
+			expectedMax:    50,
+		},
+		{
+			name:           "no budget",
+			maxPadding:     144,
+			cltvLimit:      40,
+			finalCltvDelta: 40,
+			expectedMax:    0,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			for i := 0; i < 100; i++ {
+				padding := shadowCltvPadding(
+					tc.maxPadding, tc.cltvLimit,
+					tc.finalCltvDelta,
+				)
+				require.LessOrEqual(t, padding, tc.expectedMax)
+			}
+		})
+	}
+}
+
+// TestSplitAmount tests that the amount of the next shard is picked according
+// to the amount randomization strategy.
+func TestSplitAmount(t *testing.T) {
+	t.Parallel()
+
+	const amt = lnwire.MilliSatoshi(800_000)
+
+	require.Equal(t, amt/2, splitAmount(amt, AmountRandomizationNone))
+	require.Equal(t, amt/2, splitAmount(amt, AmountRandomizationDefault))
+
+	// Amounts that are too small to randomize are halved.
+	require.EqualValues(t, 1, splitAmount(3, AmountRandomizationShard))
+
+	seen := make(map[lnwire.MilliSatoshi]struct{})
+	for i := 0; i < 100; i++ {
+		next := splitAmount(amt, AmountRandomizationShard)
+		require.GreaterOrEqual(t, next, amt/8*3)
+		require.LessOrEqual(t, next, amt/8*5)
+
+		seen[next] = struct{}{}
+	}
+
+	// With this many draws from 200_001 possible amounts, getting the
+	// same amount every time means the amount isn't randomized.
+	require.Greater(t, len(seen), 1)
+}
+
+// TestParseAmountRandomization tests that the configured names of the amount
+// randomization strategies are parsed.
+func TestParseAmountRandomization(t *testing.T) {
+	t.Parallel()
+
+	for _, strategy := range []AmountRandomization{
+		AmountRandomizationNone, AmountRandomizationShard,
+	} {
+		parsed, err := ParseAmountRandomization(strategy.String())
+		require.NoError(t, err)
+		require.Equal(t, strategy, parsed)
+	}
+
+	_, err := ParseAmountRandomization("default")
+	require.Error(t, err)
+}
+
+// TestRequestRouteShadowPadding tests that a payment session pads the final
+// CLTV delta of its routes, and that a payment can override the configured
+// maximum padding.
+func TestRequestRouteShadowPadding(t *testing.T) {
+	t.Parallel()
+
+	const (
+		height         = 10
+		finalCltvDelta = 40
+		configMax      = 20
+	)
+
+	noPadding := uint16(0)
+
+	path := []*unifiedEdge{
+		{
+			policy: &models.CachedEdgePolicy{
+				ToNodePubKey: func() route.Vertex {
+					return route.Vertex{}
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		override    *uint16
+		expectedMax uint16
+	}{
+		{
+			name:        "config",
+			expectedMax: configMax,
+		},
+		{
+			name:        "override",
+			override:    &noPadding,
+			expectedMax: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			payment := &LightningPayment{
+				CltvLimit:          1000,
+				FinalCLTVDelta:     finalCltvDelta,
+				Amount:             1000,
+				FeeLimit:           1000,
+				ShadowRouteMaxCltv: tc.override,
+			}
+
+			var paymentHash [32]byte
+			require.NoError(t, payment.SetPaymentHash(paymentHash))
+
+			session, err := newPaymentSession(
+				payment,
+				func(routingGraph) (bandwidthHints, error) {
+					return &mockBandwidthHints{}, nil
+				},
+				func() (routingGraph, func(), error) {
+					return &sessionGraph{}, func() {}, nil
+				},
+				&MissionControl{},
+				PathFindingConfig{
+					ShadowRouteMaxCltv: configMax,
+				},
+			)
+			require.NoError(t, err)
+
+			session.pathFinder = func(_ *graphParams,
+				_ *RestrictParams, _ *PathFindingConfig, _,
+				_ route.Vertex, _ lnwire.MilliSatoshi,
+				_ float64, _ int32) ([]*unifiedEdge, float64,
+				error) {
+
+				return path, 1.0, nil
+			}
+
+			minLock := uint32(height + finalCltvDelta)
+			minLock += uint32(BlockPadding)
+			for i := 0; i < 20; i++ {
+				rt, err := session.RequestRoute(
+					payment.Amount, payment.FeeLimit, 0,
+					height,
+				)
+				require.NoError(t, err)
+
+				require.GreaterOrEqual(
+					t, rt.TotalTimeLock, minLock,
+				)
+				require.LessOrEqual(
+					t, rt.TotalTimeLock,
+					minLock+uint32(tc.expectedMax),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package routing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/trampoline"
+)
+
+// ErrTrampolineUnsupported is returned when a payment that should be routed
+// through a trampoline node uses options that can't be passed on to the
+// trampoline node.
+var ErrTrampolineUnsupported = errors.New("payment option not supported " +
+	"for trampoline payments")
+
+// TrampolineHop is a trampoline node that a payment is sent through. The
+// trampoline node finds the route to the target of the payment itself.
+type TrampolineHop struct {
+	// NodeID is the public key of the trampoline node.
+	NodeID route.Vertex
+
+	// Policy is the fee policy that the trampoline node charges for
+	// relaying the payment.
+	Policy trampoline.FeePolicy
+}
+
+// routeThroughTrampoline rewrites the payment so that it is sent to its
+// trampoline node, which relays it to the original target. The trampoline
+// onion that tells the trampoline node where to relay the payment is added to
+// the final hop payload. The trampoline fee is taken out of the fee limit of
+// the payment and is part of the payment amount that is recorded.
+func (l *LightningPayment) routeThroughTrampoline(currentHeight uint32) error {
+	hop := l.Trampoline
+
+	switch {
+	case l.amp != nil:
+		return fmt.Errorf("%w: amp", ErrTrampolineUnsupported)
+
+	case l.paymentHash == nil:
+		return errors.New("trampoline payment without payment hash")
+
+	case len(l.RouteHints) > 0 || len(l.BlindedPayments) > 0:
+		return fmt.Errorf("%w: route hints", ErrTrampolineUnsupported)
+
+	case l.LastHop != nil:
+		return fmt.Errorf("%w: last hop", ErrTrampolineUnsupported)
+
+	case len(l.DestCustomRecords) > 0:
+		return fmt.Errorf("%w: custom records",
+			ErrTrampolineUnsupported)
+
+	case len(l.Metadata) > 0:
+		return fmt.Errorf("%w: metadata", ErrTrampolineUnsupported)
+
+	case l.LocalSettle:
+		return fmt.Errorf("%w: local settle", ErrTrampolineUnsupported)
+
+	case hop.NodeID == l.Target:
+		return errors.New("trampoline node is the payment target")
+	}
+
+	fee := hop.Policy.Fee(l.Amount)
+	if fee > l.FeeLimit {
+		return fmt.Errorf("trampoline fee %v exceeds fee limit %v", fee,
+			l.FeeLimit)
+	}
+
+	// The final CLTV is padded the same way as the final hop of a regular
+	// route, so that the recipient accepts the payment even if a block is
+	// mined while it is in flight.
+	finalCltv := currentHeight + uint32(l.FinalCLTVDelta) +
+		uint32(BlockPadding)
+
+	outerCltvDelta := uint32(l.FinalCLTVDelta) + uint32(BlockPadding) +
+		hop.Policy.CltvDelta
+	if outerCltvDelta > math.MaxUint16 {
+		return fmt.Errorf("trampoline cltv delta %v too large",
+			hop.Policy.CltvDelta)
+	}
+
+	target, err := btcec.ParsePubKey(l.Target[:])
+	if err != nil {
+		return err
+	}
+	trampolineKey, err := btcec.ParsePubKey(hop.NodeID[:])
+	if err != nil {
+		return err
+	}
+
+	trampolinePayload, err := (&trampoline.Payload{
+		AmtToForward:   l.Amount,
+		OutgoingCltv:   finalCltv,
+		OutgoingNodeID: target,
+	}).Encode()
+	if err != nil {
+		return err
+	}
+
+	finalPayload := &trampoline.Payload{
+		AmtToForward: l.Amount,
+		OutgoingCltv: finalCltv,
+	}
+	if l.PaymentAddr != nil {
+		finalPayload.MPP = record.NewMPP(l.Amount, *l.PaymentAddr)
+	}
+	targetPayload, err := finalPayload.Encode()
+	if err != nil {
+		return err
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	onion, err := trampoline.NewOnionPacket(
+		[]trampoline.OnionHop{
+			{NodeID: trampolineKey, Payload: trampolinePayload},
+			{NodeID: target, Payload: targetPayload},
+		},
+		sessionKey, l.paymentHash[:], trampoline.DefaultRoutingInfoSize,
+	)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := onion.Encode(&b); err != nil {
+		return err
+	}
+
+	log.Debugf("Routing payment %v to %v through trampoline node %v",
+		l.paymentHash, l.Target, hop.NodeID)
+
+	// The payment is now made to the trampoline node. The features, the
+	// payment address and the shard options of the original target don't
+	// apply to it, so the payment is sent in a single shard.
+	l.Target = hop.NodeID
+	l.Amount += fee
+	l.FeeLimit -= fee
+	l.FinalCLTVDelta = uint16(outerCltvDelta)
+	l.DestCustomRecords = record.CustomSet{
+		uint64(record.TrampolineOnionType): b.Bytes(),
+	}
+	l.DestFeatures = nil
+	l.PaymentAddr = nil
+	l.MaxParts = 1
+	l.MaxShardAmt = nil
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/trampoline"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRouteThroughTrampoline asserts that a payment is rewritten into a
+// payment to its trampoline node that carries a trampoline onion, which leads
+// the payment to the original target.
+func TestRouteThroughTrampoline(t *testing.T) {
+	t.Parallel()
+
+	trampolineKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	targetKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	hash := lntypes.Hash{1}
+	payAddr := [32]byte{2}
+	payment := &LightningPayment{
+		Target:         route.NewVertex(targetKey.PubKey()),
+		Amount:         100_000,
+		FeeLimit:       5000,
+		FinalCLTVDelta: 40,
+		PaymentAddr:    &payAddr,
+		DestFeatures:   lnwire.EmptyFeatureVector(),
+		Trampoline: &TrampolineHop{
+			NodeID: route.NewVertex(trampolineKey.PubKey()),
+			Policy: trampoline.FeePolicy{
+				BaseFee:   1000,
+				FeeRate:   1000,
+				CltvDelta: 100,
+			},
+		},
+	}
+	require.NoError(t, payment.SetPaymentHash(hash))
+
+	require.NoError(t, payment.routeThroughTrampoline(500))
+
+	require.Equal(
+		t, route.NewVertex(trampolineKey.PubKey()), payment.Target,
+	)
+	require.EqualValues(t, 101_100, payment.Amount)
+	require.EqualValues(t, 3900, payment.FeeLimit)
+	require.EqualValues(t, 40+BlockPadding+100, payment.FinalCLTVDelta)
+	require.Nil(t, payment.PaymentAddr)
+	require.Nil(t, payment.DestFeatures)
+	require.EqualValues(t, 1, payment.MaxParts)
+	require.Equal(t, hash, lntypes.Hash(payment.Identifier()))
+
+	onionBytes := payment.DestCustomRecords[uint64(
+		record.TrampolineOnionType,
+	)]
+	onion, err := trampoline.DecodeOnionPacket(onionBytes)
+	require.NoError(t, err)
+
+	// The trampoline node is told to relay the payment to the target.
+	processed, err := trampoline.ProcessOnionPacket(
+		&sphinx.PrivKeyECDH{PrivKey: trampolineKey}, onion, hash[:],
+	)
+	require.NoError(t, err)
+	require.False(t, processed.IsFinal())
+
+	payload, err := trampoline.DecodePayload(processed.Payload, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 100_000, payload.AmtToForward)
+	require.EqualValues(t, 500+40+BlockPadding, payload.OutgoingCltv)
+	require.True(t, payload.OutgoingNodeID.IsEqual(targetKey.PubKey()))
+
+	// The target finds the payment address of the invoice in its payload.
+	processed, err = trampoline.ProcessOnionPacket(
+		&sphinx.PrivKeyECDH{PrivKey: targetKey}, processed.NextPacket,
+		hash[:],
+	)
+	require.NoError(t, err)
+	require.True(t, processed.IsFinal())
+
+	payload, err = trampoline.DecodePayload(processed.Payload, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 100_000, payload.AmtToForward)
+	require.NotNil(t, payload.MPP)
+	require.Equal(t, payAddr, payload.MPP.PaymentAddr())
+}
+
+// TestRouteThroughTrampolineUnsupported asserts that payments that can't be
+// routed through a trampoline node are rejected.
+func TestRouteThroughTrampolineUnsupported(t *testing.T) {
+	t.Parallel()
+
+	trampolineKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	targetKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	newPayment := func() *LightningPayment {
+		payment := &LightningPayment{
+			Target:   route.NewVertex(targetKey.PubKey()),
+			Amount:   100_000,
+			FeeLimit: 5000,
+			Trampoline: &TrampolineHop{
+				NodeID: route.NewVertex(
+					trampolineKey.PubKey(),
+				),
+				Policy: trampoline.FeePolicy{BaseFee: 1000},
+			},
+		}
+		require.NoError(t, payment.SetPaymentHash(lntypes.Hash{1}))
+
+		return payment
+	}
+
+	payment := newPayment()
+	payment.DestCustomRecords = record.CustomSet{65536: {1}}
+	err = payment.routeThroughTrampoline(500)
+	require.ErrorIs(t, err, ErrTrampolineUnsupported)
+
+	payment = newPayment()
+	payment.LastHop = &payment.Target
+	err = payment.routeThroughTrampoline(500)
+	require.ErrorIs(t, err, ErrTrampolineUnsupported)
+
+	// The trampoline fee must fit into the fee limit.
+	payment = newPayment()
+	payment.FeeLimit = 999
+	require.Error(t, payment.routeThroughTrampoline(500))
+}
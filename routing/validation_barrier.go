@@ -126,6 +126,20 @@ func (v *ValidationBarrier) InitJobDependencies(job interface{}) {
 			v.nodeAnnDependencies[route.Vertex(msg.NodeID1)] = signals
 			v.nodeAnnDependencies[route.Vertex(msg.NodeID2)] = signals
 		}
+	case *lnwire.ChannelAnnouncement2:
+		shortID := msg.ShortChannelID.Val
+		if _, ok := v.chanAnnFinSignal[shortID]; !ok {
+			signals := &validationSignals{
+				allow: make(chan struct{}),
+				deny:  make(chan struct{}),
+			}
+
+			v.chanAnnFinSignal[shortID] = signals
+			v.chanEdgeDependencies[shortID] = signals
+
+			v.nodeAnnDependencies[route.Vertex(msg.NodeID1.Val)] = signals
+			v.nodeAnnDependencies[route.Vertex(msg.NodeID2.Val)] = signals
+		}
 	case *models.ChannelEdgeInfo:
 
 		shortID := lnwire.NewShortChanIDFromInt(msg.ChannelID)
@@ -220,6 +234,7 @@ func (v *ValidationBarrier) WaitForDependants(job interface{}) error {
 		// TODO(roasbeef): need to wait on chan ann?
 	case *models.ChannelEdgeInfo:
 	case *lnwire.ChannelAnnouncement:
+	case *lnwire.ChannelAnnouncement2:
 	}
 
 	// Release the lock once the above read is finished.
@@ -287,6 +302,19 @@ func (v *ValidationBarrier) SignalDependants(job interface{}, allow bool) {
 		}
 
 		delete(v.chanEdgeDependencies, msg.ShortChannelID)
+	case *lnwire.ChannelAnnouncement2:
+		shortID := msg.ShortChannelID.Val
+		finSignals, ok := v.chanAnnFinSignal[shortID]
+		if ok {
+			if allow {
+				close(finSignals.allow)
+			} else {
+				close(finSignals.deny)
+			}
+			delete(v.chanAnnFinSignal, shortID)
+		}
+
+		delete(v.chanEdgeDependencies, shortID)
 
 	// For all other job types, we'll delete the tracking entries from the
 	// map, as if we reach this point, then all dependants have already
@@ -0,0 +1,43 @@
+package rpcperms
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckDeprecated tests that only calls to deprecated methods are marked.
+func TestCheckDeprecated(t *testing.T) {
+	t.Parallel()
+
+	r := NewInterceptorChain(btclog.Disabled, false, false, nil)
+
+	const method = "/lnrpc.Lightning/SendPayment"
+	md, ok := r.checkDeprecated(method)
+	require.True(t, ok)
+	require.Equal(t, []string{method}, md.Get(lnrpc.DeprecatedMetadataKey))
+
+	// The second call is marked as well, only the warning is skipped.
+	_, ok = r.checkDeprecated(method)
+	require.True(t, ok)
+
+	_, ok = r.checkDeprecated("/lnrpc.Lightning/GetInfo")
+	require.False(t, ok)
+
+	// The deprecated fields of methods are listed as well.
+	var found bool
+	for _, d := range lnrpc.Deprecations("/lnrpc.Lightning/") {
+		if d.Method != "/lnrpc.Lightning/GetInfo" {
+			continue
+		}
+
+		found = true
+		require.False(t, d.Deprecated)
+		require.Contains(
+			t, d.ResponseFields, "lnrpc.GetInfoResponse.testnet",
+		)
+	}
+	require.True(t, found)
+}
@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/btcsuite/btclog"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/monitoring"
+	"github.com/lightningnetwork/lnd/monitoring/tracing"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
 
@@ -75,6 +78,17 @@ var (
 	ErrRPCStarting = fmt.Errorf("the RPC server is in the process of " +
 		"starting up, but not yet ready to accept calls")
 
+	// ErrReadOnly is returned if lnd runs in read-only mode and a call is
+	// made that could modify the node's state.
+	ErrReadOnly = fmt.Errorf("lnd is running in read-only mode, calls " +
+		"that modify the node's state are not allowed")
+
+	// ErrReadOnlyStream is returned if lnd runs in read-only mode and a
+	// streaming call is made whose events are sent by a subsystem that
+	// isn't started in read-only mode.
+	ErrReadOnlyStream = fmt.Errorf("lnd is running in read-only mode, " +
+		"the streaming call is not available")
+
 	// macaroonWhitelist defines methods that we don't require macaroons to
 	// access. We also allow these methods to be called even if not all
 	// mandatory middlewares are registered yet. If the wallet is locked
@@ -91,11 +105,66 @@ var (
 
 		// The State service must be available at all times, even
 		// before we can check macaroons, so we whitelist it.
-		"/lnrpc.State/SubscribeState": {},
-		"/lnrpc.State/GetState":       {},
+		"/lnrpc.State/SubscribeState":   {},
+		"/lnrpc.State/GetState":         {},
+		"/lnrpc.State/GetStartupStages": {},
+	}
+
+	// readOnlyWhitelist defines methods that don't only require read
+	// permissions, but that we still allow in read-only mode because they
+	// don't modify the node's state.
+	readOnlyWhitelist = map[string]struct{}{
+		"/lnrpc.WalletUnlocker/GenSeed":      {},
+		"/lnrpc.WalletUnlocker/UnlockWallet": {},
+		"/lnrpc.State/SubscribeState":        {},
+		"/lnrpc.State/GetState":              {},
+		"/lnrpc.State/GetStartupStages":      {},
+		"/lnrpc.Lightning/StopDaemon":        {},
+	}
+
+	// readOnlyUnavailableStreams maps the streaming calls that only require
+	// read permissions, but that can't be served in read-only mode, to the
+	// reason why. The subsystems sending their events are only started
+	// with the server, which isn't started in read-only mode. All other
+	// streaming calls, like the ones that are served by the wallet, are
+	// allowed.
+	readOnlyUnavailableStreams = map[string]string{
+		"/lnrpc.Lightning/SubscribeWalletBalance": "the chain " +
+			"notifier isn't started",
+		"/lnrpc.Lightning/SubscribePeerEvents": "no peers are " +
+			"connected",
+		"/lnrpc.Lightning/SubscribeChannelEvents": "the channel " +
+			"notifier isn't started",
+		"/lnrpc.Lightning/SubscribeInvoices": "the invoice registry " +
+			"isn't started",
+		"/lnrpc.Lightning/SubscribeChannelGraph": "the graph builder " +
+			"isn't started",
+		"/lnrpc.Lightning/SubscribeChannelBackups": "the channel " +
+			"backup swapper isn't started",
+		"/lnrpc.Lightning/SubscribeCustomMessages": "no peers are " +
+			"connected",
+		"/routerrpc.Router/SubscribeHtlcEvents": "the htlc notifier " +
+			"isn't started",
+		"/invoicesrpc.Invoices/SubscribeSingleInvoice": "the invoice " +
+			"registry isn't started",
 	}
 )
 
+// startupStage describes the timing of a stage of the startup of lnd.
+type startupStage struct {
+	// Name is the name of the stage.
+	Name string
+
+	// Started is the time the stage started.
+	Started time.Time
+
+	// Duration is the time the stage took, once it finished.
+	Duration time.Duration
+
+	// Finished is true if the stage finished.
+	Finished bool
+}
+
 // InterceptorChain is a struct that can be added to the running GRPC server,
 // intercepting API calls. This is useful for logging, enforcing permissions,
 // supporting middleware etc. The following diagram shows the order of each
@@ -154,9 +223,17 @@ type InterceptorChain struct {
 	// State service when the state changes.
 	ntfnServer *subscribe.Server
 
+	// stages are the stages of the startup of lnd, in the order they
+	// started.
+	stages []*startupStage
+
 	// noMacaroons should be set true if we don't want to check macaroons.
 	noMacaroons bool
 
+	// readOnly should be set true if lnd runs in read-only mode, in which
+	// case only calls that don't modify the node's state are allowed.
+	readOnly bool
+
 	// svc is the macaroon service used to enforce permissions in case
 	// macaroons are used.
 	svc *macaroons.Service
@@ -189,6 +266,10 @@ type InterceptorChain struct {
 	// middleware crashes.
 	mandatoryMiddleware []string
 
+	// deprecatedCalls holds the deprecated RPC methods that were called
+	// already, so a warning is only logged on the first call.
+	deprecatedCalls sync.Map
+
 	quit chan struct{}
 	sync.RWMutex
 }
@@ -198,13 +279,14 @@ type InterceptorChain struct {
 var _ lnrpc.StateServer = (*InterceptorChain)(nil)
 
 // NewInterceptorChain creates a new InterceptorChain.
-func NewInterceptorChain(log btclog.Logger, noMacaroons bool,
+func NewInterceptorChain(log btclog.Logger, noMacaroons, readOnly bool,
 	mandatoryMiddleware []string) *InterceptorChain {
 
 	return &InterceptorChain{
 		state:                     waitingToStart,
 		ntfnServer:                subscribe.NewServer(),
 		noMacaroons:               noMacaroons,
+		readOnly:                  readOnly,
 		permissionMap:             make(map[string][]bakery.Op),
 		rpcsLog:                   log,
 		registeredMiddlewareNames: make(map[string]int),
@@ -283,6 +365,35 @@ func (r *InterceptorChain) SetServerActive() {
 	_ = r.ntfnServer.SendUpdate(r.state)
 }
 
+// StartStage records the start of the startup stage with the given name. The
+// returned function must be called once the stage finished. Stages may run
+// concurrently.
+func (r *InterceptorChain) StartStage(name string) func() {
+	stage := &startupStage{
+		Name:    name,
+		Started: time.Now(),
+	}
+
+	r.Lock()
+	r.stages = append(r.stages, stage)
+	r.Unlock()
+
+	r.rpcsLog.Debugf("Startup stage %v started", name)
+
+	var finished sync.Once
+	return func() {
+		finished.Do(func() {
+			r.Lock()
+			stage.Duration = time.Since(stage.Started)
+			stage.Finished = true
+			r.Unlock()
+
+			r.rpcsLog.Infof("Startup stage %v finished (took %v)",
+				name, stage.Duration)
+		})
+	}
+}
+
 // rpcStateToWalletState converts rpcState to lnrpc.WalletState. Returns
 // WAITING_TO_START and an error on conversion error.
 func rpcStateToWalletState(state rpcState) (lnrpc.WalletState, error) {
@@ -393,6 +504,35 @@ func (r *InterceptorChain) GetState(_ context.Context,
 	}, nil
 }
 
+// GetStartupStages returns the timings of the stages of the startup of lnd, in
+// the order they started.
+func (r *InterceptorChain) GetStartupStages(_ context.Context,
+	_ *lnrpc.GetStartupStagesRequest) (*lnrpc.GetStartupStagesResponse,
+	error) {
+
+	r.RLock()
+	defer r.RUnlock()
+
+	stages := make([]*lnrpc.StartupStage, 0, len(r.stages))
+	for _, stage := range r.stages {
+		duration := stage.Duration
+		if !stage.Finished {
+			duration = time.Since(stage.Started)
+		}
+
+		stages = append(stages, &lnrpc.StartupStage{
+			Name:        stage.Name,
+			StartTimeNs: stage.Started.UnixNano(),
+			DurationNs:  duration.Nanoseconds(),
+			Finished:    stage.Finished,
+		})
+	}
+
+	return &lnrpc.GetStartupStagesResponse{
+		Stages: stages,
+	}, nil
+}
+
 // AddMacaroonService adds a macaroon service to the interceptor. After this is
 // done every RPC call made will have to pass a valid macaroon to be accepted.
 func (r *InterceptorChain) AddMacaroonService(svc *macaroons.Service) {
@@ -531,14 +671,26 @@ func (r *InterceptorChain) CustomCaveatSupported(customCaveatName string) error
 }
 
 // CreateServerOpts creates the GRPC server options that can be added to a GRPC
-// server in order to add this InterceptorChain.
-func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
+// server in order to add this InterceptorChain. If enableTracing is set, every
+// call is additionally wrapped in an OpenTelemetry span. The given limits are
+// enforced on all calls, nil means no limits.
+func (r *InterceptorChain) CreateServerOpts(enableTracing bool,
+	limits *Limits) []grpc.ServerOption {
+
 	var unaryInterceptors []grpc.UnaryServerInterceptor
 	var strmInterceptors []grpc.StreamServerInterceptor
 
-	// The first interceptors we'll add to the chain is our logging
-	// interceptors, so we can automatically log all errors that happen
-	// during RPC calls.
+	// If tracing is enabled, the tracing interceptors come first, so that
+	// the span of a call covers all other interceptors and every span
+	// created while handling the call becomes its child.
+	if enableTracing {
+		unaryTracing, strmTracing := tracing.GetInterceptors()
+		unaryInterceptors = append(unaryInterceptors, unaryTracing)
+		strmInterceptors = append(strmInterceptors, strmTracing)
+	}
+
+	// Next we'll add our logging interceptors, so we can automatically log
+	// all errors that happen during RPC calls.
 	unaryInterceptors = append(
 		unaryInterceptors, errorLogUnaryServerInterceptor(r.rpcsLog),
 	)
@@ -573,6 +725,26 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 		strmInterceptors, r.middlewareStreamServerInterceptor(),
 	)
 
+	// The limits are enforced last, so they apply to the request as
+	// modified by the middleware.
+	if limits == nil {
+		limits = &Limits{}
+	}
+	unaryInterceptors = append(
+		unaryInterceptors, limitsUnaryServerInterceptor(limits),
+	)
+	strmInterceptors = append(
+		strmInterceptors, limitsStreamServerInterceptor(limits),
+	)
+
+	// Let clients know when they call deprecated methods.
+	unaryInterceptors = append(
+		unaryInterceptors, r.deprecationUnaryServerInterceptor(),
+	)
+	strmInterceptors = append(
+		strmInterceptors, r.deprecationStreamServerInterceptor(),
+	)
+
 	// Get interceptors for Prometheus to gather gRPC performance metrics.
 	// If monitoring is disabled, GetPromInterceptors() will return empty
 	// slices.
@@ -595,6 +767,52 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 	return serverOpts
 }
 
+// checkDeprecated returns the header to set on the response if the method is
+// deprecated, and logs a warning on its first call.
+func (r *InterceptorChain) checkDeprecated(method string) (metadata.MD, bool) {
+	if !lnrpc.IsDeprecatedMethod(method) {
+		return nil, false
+	}
+
+	if _, called := r.deprecatedCalls.LoadOrStore(method, true); !called {
+		r.rpcsLog.Warnf("Deprecated RPC %v was called, it will be "+
+			"removed in a future release", method)
+	}
+
+	return metadata.Pairs(lnrpc.DeprecatedMetadataKey, method), true
+}
+
+// deprecationUnaryServerInterceptor is a GRPC interceptor that marks the
+// responses of deprecated methods with a header.
+func (r *InterceptorChain) deprecationUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if md, ok := r.checkDeprecated(info.FullMethod); ok {
+			// Failing to set the header must not fail the call.
+			_ = grpc.SetHeader(ctx, md)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// deprecationStreamServerInterceptor is a GRPC interceptor that marks the
+// streams of deprecated methods with a header.
+func (r *InterceptorChain) deprecationStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		if md, ok := r.checkDeprecated(info.FullMethod); ok {
+			// Failing to set the header must not fail the call.
+			_ = ss.SetHeader(md)
+		}
+
+		return handler(srv, ss)
+	}
+}
+
 // errorLogUnaryServerInterceptor is a simple UnaryServerInterceptor that will
 // automatically log any errors that occur when serving a client's unary
 // request.
@@ -766,6 +984,44 @@ func (r *InterceptorChain) checkRPCState(srv interface{}) error {
 	return nil
 }
 
+// checkReadOnly checks whether a call to the given method is allowed in
+// read-only mode. Only calls that require nothing but read permissions are
+// allowed, as well as the few calls in the read-only whitelist. Streaming
+// calls whose events are sent by a subsystem that isn't started in read-only
+// mode are rejected with the reason why.
+func (r *InterceptorChain) checkReadOnly(fullMethod string) error {
+
+	if !r.readOnly {
+		return nil
+	}
+
+	if _, ok := readOnlyWhitelist[fullMethod]; ok {
+		return nil
+	}
+
+	r.RLock()
+	uriPermissions, ok := r.permissionMap[fullMethod]
+	r.RUnlock()
+
+	// We don't know whether a method without permissions modifies the
+	// node's state, so we err on the side of caution.
+	if !ok {
+		return ErrReadOnly
+	}
+
+	for _, op := range uriPermissions {
+		if op.Action != "read" {
+			return ErrReadOnly
+		}
+	}
+
+	if reason, ok := readOnlyUnavailableStreams[fullMethod]; ok {
+		return fmt.Errorf("%w: %s", ErrReadOnlyStream, reason)
+	}
+
+	return nil
+}
+
 // rpcStateUnaryServerInterceptor is a GRPC interceptor that checks whether
 // calls to the given gGRPC server is allowed in the current rpc state.
 func (r *InterceptorChain) rpcStateUnaryServerInterceptor() grpc.UnaryServerInterceptor {
@@ -778,6 +1034,10 @@ func (r *InterceptorChain) rpcStateUnaryServerInterceptor() grpc.UnaryServerInte
 			return nil, err
 		}
 
+		if err := r.checkReadOnly(info.FullMethod); err != nil {
+			return nil, err
+		}
+
 		return handler(ctx, req)
 	}
 }
@@ -794,6 +1054,10 @@ func (r *InterceptorChain) rpcStateStreamServerInterceptor() grpc.StreamServerIn
 			return err
 		}
 
+		if err := r.checkReadOnly(info.FullMethod); err != nil {
+			return err
+		}
+
 		return handler(srv, ss)
 	}
 }
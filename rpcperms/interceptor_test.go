@@ -0,0 +1,134 @@
+package rpcperms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+// TestCheckReadOnly makes sure that only calls that don't modify the node's
+// state are allowed in read-only mode.
+func TestCheckReadOnly(t *testing.T) {
+	t.Parallel()
+
+	const (
+		readMethod   = "/lnrpc.Lightning/ListChannels"
+		writeMethod  = "/lnrpc.Lightning/OpenChannel"
+		mixedMethod  = "/lnrpc.Lightning/SendCoins"
+		streamMethod = "/lnrpc.Lightning/SubscribeChannelEvents"
+		walletStream = "/lnrpc.Lightning/SubscribeTransactions"
+	)
+
+	newChain := func(readOnly bool) *InterceptorChain {
+		r := NewInterceptorChain(btclog.Disabled, false, readOnly, nil)
+
+		require.NoError(t, r.AddPermission(readMethod, []bakery.Op{{
+			Entity: "offchain",
+			Action: "read",
+		}}))
+		require.NoError(t, r.AddPermission(streamMethod, []bakery.Op{{
+			Entity: "offchain",
+			Action: "read",
+		}}))
+		require.NoError(t, r.AddPermission(walletStream, []bakery.Op{{
+			Entity: "onchain",
+			Action: "read",
+		}}))
+		require.NoError(t, r.AddPermission(writeMethod, []bakery.Op{{
+			Entity: "onchain",
+			Action: "write",
+		}}))
+		require.NoError(t, r.AddPermission(mixedMethod, []bakery.Op{{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "onchain",
+			Action: "write",
+		}}))
+
+		return r
+	}
+
+	// Without read-only mode, all calls are allowed.
+	r := newChain(false)
+	require.NoError(t, r.checkReadOnly(readMethod))
+	require.NoError(t, r.checkReadOnly(writeMethod))
+	require.NoError(t, r.checkReadOnly(mixedMethod))
+	require.NoError(t, r.checkReadOnly("/lnrpc.Unknown/Method"))
+	require.NoError(t, r.checkReadOnly(streamMethod))
+
+	// In read-only mode, only calls that require nothing but read
+	// permissions and whitelisted calls are allowed.
+	r = newChain(true)
+	require.NoError(t, r.checkReadOnly(readMethod))
+	require.ErrorIs(t, r.checkReadOnly(writeMethod), ErrReadOnly)
+	require.ErrorIs(t, r.checkReadOnly(mixedMethod), ErrReadOnly)
+	require.ErrorIs(
+		t, r.checkReadOnly("/lnrpc.Unknown/Method"), ErrReadOnly,
+	)
+	require.ErrorIs(
+		t, r.checkReadOnly("/lnrpc.WalletUnlocker/InitWallet"),
+		ErrReadOnly,
+	)
+	require.NoError(
+		t, r.checkReadOnly("/lnrpc.WalletUnlocker/UnlockWallet"),
+	)
+	require.NoError(t, r.checkReadOnly("/lnrpc.State/GetState"))
+	require.NoError(t, r.checkReadOnly("/lnrpc.State/SubscribeState"))
+
+	// Streaming calls are served if the subsystem sending their events
+	// runs in read-only mode. Otherwise, they're rejected with the reason
+	// why.
+	require.NoError(t, r.checkReadOnly(walletStream))
+
+	err := r.checkReadOnly(streamMethod)
+	require.ErrorIs(t, err, ErrReadOnlyStream)
+	require.ErrorContains(t, err, "channel notifier isn't started")
+}
+
+// TestStartupStages makes sure that the timings of concurrent startup stages
+// are reported in the order the stages started.
+func TestStartupStages(t *testing.T) {
+	t.Parallel()
+
+	r := NewInterceptorChain(btclog.Disabled, false, false, nil)
+
+	finishDB := r.StartStage("databases")
+	finishWallet := r.StartStage("wallet")
+
+	getStages := func() []*lnrpc.StartupStage {
+		resp, err := r.GetStartupStages(
+			context.Background(), &lnrpc.GetStartupStagesRequest{},
+		)
+		require.NoError(t, err)
+
+		return resp.Stages
+	}
+
+	stages := getStages()
+	require.Len(t, stages, 2)
+	require.Equal(t, "databases", stages[0].Name)
+	require.Equal(t, "wallet", stages[1].Name)
+	require.False(t, stages[0].Finished)
+	require.False(t, stages[1].Finished)
+	require.LessOrEqual(t, stages[0].StartTimeNs, stages[1].StartTimeNs)
+
+	// Finishing a stage fixes its duration, also if it's finished again.
+	finishWallet()
+	stages = getStages()
+	require.False(t, stages[0].Finished)
+	require.True(t, stages[1].Finished)
+
+	walletDuration := stages[1].DurationNs
+	finishWallet()
+	finishDB()
+
+	stages = getStages()
+	require.True(t, stages[0].Finished)
+	require.Equal(t, walletDuration, stages[1].DurationNs)
+	require.GreaterOrEqual(t, stages[0].DurationNs, stages[1].DurationNs)
+}
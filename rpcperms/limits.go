@@ -0,0 +1,205 @@
+package rpcperms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultListResults is the number of results the listing RPCs return
+	// if the request doesn't set a maximum, for RPCs that have a default.
+	defaultListResults = 100
+)
+
+// Limits are the limits enforced on the requests and responses of RPC calls,
+// which protect the memory of the node from clients requesting huge
+// responses.
+type Limits struct {
+	// MaxListResults is the maximum number of results a listing RPC
+	// returns at once. Requests for more results, or for all of them, are
+	// limited to this number, and the client can page through the
+	// remaining results with the returned index offsets. Zero disables the
+	// limit.
+	MaxListResults uint64
+
+	// RejectUnbounded, if set, rejects listing requests for more than
+	// MaxListResults results instead of limiting them.
+	RejectUnbounded bool
+
+	// MaxResponseSize is the maximum size in bytes of a response message.
+	// Calls with larger responses fail with a ResourceExhausted error.
+	// Zero disables the limit.
+	MaxResponseSize uint64
+}
+
+// limitListRequest limits the number of results requested by a listing RPC
+// request to the maximum. Other requests are left untouched. An error is
+// returned if the request is over the limit and such requests are rejected.
+func (l *Limits) limitListRequest(req interface{}) error {
+	if l.MaxListResults == 0 {
+		return nil
+	}
+
+	// limit returns the number of results to request, where requested is
+	// the number of results the request asked for with zero meaning the
+	// default, which is unbounded if def is zero.
+	limit := func(field string, requested, def uint64) (uint64, error) {
+		effective := requested
+		if effective == 0 {
+			effective = def
+		}
+		if effective != 0 && effective <= l.MaxListResults {
+			return requested, nil
+		}
+
+		if !l.RejectUnbounded {
+			return l.MaxListResults, nil
+		}
+
+		desc := fmt.Sprintf("at most %d results can be requested at "+
+			"once, use the index offset to page through the "+
+			"results", l.MaxListResults)
+		badRequest := &errdetails.BadRequest{}
+		badRequest.FieldViolations = append(
+			badRequest.FieldViolations,
+			&errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: desc,
+			},
+		)
+		st, err := status.New(codes.InvalidArgument, desc).WithDetails(
+			badRequest,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, st.Err()
+	}
+
+	var err error
+	switch r := req.(type) {
+	case *lnrpc.ListInvoiceRequest:
+		r.NumMaxInvoices, err = limit(
+			"num_max_invoices", r.NumMaxInvoices,
+			defaultListResults,
+		)
+
+	case *lnrpc.ListPaymentsRequest:
+		r.MaxPayments, err = limit("max_payments", r.MaxPayments, 0)
+
+	case *lnrpc.ForwardingHistoryRequest:
+		var numEvents uint64
+		numEvents, err = limit(
+			"num_max_events", uint64(r.NumMaxEvents),
+			defaultListResults,
+		)
+		r.NumMaxEvents = uint32(numEvents)
+	}
+
+	return err
+}
+
+// checkResponseSize returns a ResourceExhausted error if the response is
+// larger than the maximum.
+func (l *Limits) checkResponseSize(method string, resp interface{}) error {
+	if l.MaxResponseSize == 0 {
+		return nil
+	}
+
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	size := uint64(proto.Size(msg))
+	if size <= l.MaxResponseSize {
+		return nil
+	}
+
+	desc := fmt.Sprintf("response of %d bytes exceeds the maximum "+
+		"response size of %d bytes", size, l.MaxResponseSize)
+	st, err := status.New(codes.ResourceExhausted, desc).WithDetails(
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     method,
+				Description: desc,
+			}},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return st.Err()
+}
+
+// limitsUnaryServerInterceptor is a GRPC interceptor that enforces the limits
+// on unary RPC calls.
+func limitsUnaryServerInterceptor(limits *Limits) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if err := limits.limitListRequest(req); err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		err = limits.checkResponseSize(info.FullMethod, resp)
+		if err != nil {
+			return nil, err
+		}
+
+		return resp, nil
+	}
+}
+
+// limitedServerStream is a server stream that enforces the maximum response
+// size on every message sent.
+type limitedServerStream struct {
+	grpc.ServerStream
+
+	method string
+	limits *Limits
+}
+
+// SendMsg sends the message if it doesn't exceed the maximum response size.
+func (s *limitedServerStream) SendMsg(m interface{}) error {
+	if err := s.limits.checkResponseSize(s.method, m); err != nil {
+		return err
+	}
+
+	return s.ServerStream.SendMsg(m)
+}
+
+// limitsStreamServerInterceptor is a GRPC interceptor that enforces the
+// maximum response size on the messages of streaming RPC calls.
+func limitsStreamServerInterceptor(
+	limits *Limits) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		if limits.MaxResponseSize == 0 {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &limitedServerStream{
+			ServerStream: ss,
+			method:       info.FullMethod,
+			limits:       limits,
+		})
+	}
+}
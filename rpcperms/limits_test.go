@@ -0,0 +1,97 @@
+package rpcperms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestLimitListRequest tests that listing requests are limited or rejected.
+func TestLimitListRequest(t *testing.T) {
+	t.Parallel()
+
+	limits := &Limits{MaxListResults: 50}
+
+	// Requests within the limit are left untouched.
+	invoiceReq := &lnrpc.ListInvoiceRequest{NumMaxInvoices: 10}
+	require.NoError(t, limits.limitListRequest(invoiceReq))
+	require.EqualValues(t, 10, invoiceReq.NumMaxInvoices)
+
+	// The default number of invoices exceeds the limit.
+	invoiceReq = &lnrpc.ListInvoiceRequest{}
+	require.NoError(t, limits.limitListRequest(invoiceReq))
+	require.EqualValues(t, 50, invoiceReq.NumMaxInvoices)
+
+	// Unbounded payment requests are limited.
+	paymentReq := &lnrpc.ListPaymentsRequest{}
+	require.NoError(t, limits.limitListRequest(paymentReq))
+	require.EqualValues(t, 50, paymentReq.MaxPayments)
+
+	forwardReq := &lnrpc.ForwardingHistoryRequest{NumMaxEvents: 1000}
+	require.NoError(t, limits.limitListRequest(forwardReq))
+	require.EqualValues(t, 50, forwardReq.NumMaxEvents)
+
+	// If unbounded requests are rejected, the error names the field.
+	limits.RejectUnbounded = true
+	paymentReq = &lnrpc.ListPaymentsRequest{}
+	err := limits.limitListRequest(paymentReq)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	badRequest, ok := details[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Equal(
+		t, "max_payments", badRequest.FieldViolations[0].Field,
+	)
+
+	// Without a limit, nothing is changed.
+	limits = &Limits{}
+	paymentReq = &lnrpc.ListPaymentsRequest{}
+	require.NoError(t, limits.limitListRequest(paymentReq))
+	require.Zero(t, paymentReq.MaxPayments)
+}
+
+// TestLimitResponseSize tests that responses over the maximum size fail.
+func TestLimitResponseSize(t *testing.T) {
+	t.Parallel()
+
+	interceptor := limitsUnaryServerInterceptor(&Limits{
+		MaxResponseSize: 100,
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/lnrpc.Lightning/GetInfo"}
+
+	respond := func(alias string) grpc.UnaryHandler {
+		return func(context.Context, interface{}) (interface{},
+			error) {
+
+			return &lnrpc.GetInfoResponse{Alias: alias}, nil
+		}
+	}
+
+	resp, err := interceptor(
+		context.Background(), &lnrpc.GetInfoRequest{}, info,
+		respond("small"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "small", resp.(*lnrpc.GetInfoResponse).Alias)
+
+	large := string(make([]byte, 200))
+	_, err = interceptor(
+		context.Background(), &lnrpc.GetInfoRequest{}, info,
+		respond(large),
+	)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	quotaFailure, ok := details[0].(*errdetails.QuotaFailure)
+	require.True(t, ok)
+	require.Equal(t, info.FullMethod, quotaFailure.Violations[0].Subject)
+}
@@ -3,6 +3,7 @@ package lnd
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -34,6 +35,7 @@ import (
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/davecgh/go-spew/spew"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/lightningnetwork/lnd/anchorproof"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/chainreg"
@@ -43,31 +45,40 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/cltvguard"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/holdfee"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/jamdetect"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/liquidityalert"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnurl"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwallet/psbtv2"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/onionmessage"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/record"
@@ -80,8 +91,10 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/tv42/zbase32"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
@@ -333,6 +346,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/BumpCoopCloseFee": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/AbandonChannel": {{
 			Entity: "offchain",
 			Action: "write",
@@ -341,6 +361,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ListDeprecations": {{
+			Entity: "info",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/GetDebugInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -354,6 +378,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GetEffectiveConfig": {{
+			Entity: "info",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/GetRecoveryInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -366,6 +394,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SubscribeWalletBalance": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/EstimateFee": {{
 			Entity: "onchain",
 			Action: "read",
@@ -390,6 +422,94 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ChannelCloseHistory": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ClosedChannelsPage": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/PendingChannelsPage": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/LiquidityAlerts": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/CltvGuardStatus": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/HoldFeeStatus": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SimulateHoldFee": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListJammingIncidents": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AnchorOwnershipProofs": {{
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "message",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SafeModeStatus": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/GetChainInfo": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/GetLeadershipState": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/GetDBUsage": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ScheduleDBCompaction": {{
+			Entity: "info",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ExitSafeMode": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RegisterFundingContributors": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ContributeFunding": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SignFundingContribution": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/FundingContributionStatus": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/SendPayment": {{
 			Entity: "offchain",
 			Action: "write",
@@ -430,6 +550,31 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/AddressReuseReport": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ExportLedger": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/CheckOpenChannel": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/DiagnoseConnection": {{
+			Entity: "peers",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DescribeGraph": {{
 			Entity: "info",
 			Action: "read",
@@ -446,6 +591,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GetNodeMetadata": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/GetNodeCapabilities": {{
+			Entity: "info",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/QueryRoutes": {{
 			Entity: "info",
 			Action: "read",
@@ -478,6 +631,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/ReloadConfig": {{
+			Entity: "info",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DecodePayReq": {{
 			Entity: "offchain",
 			Action: "read",
@@ -564,6 +721,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SendOnionMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeOnionMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/LookupHtlcResolution": {{
 			Entity: "offchain",
 			Action: "read",
@@ -726,6 +891,8 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			return info.NodeKey1Bytes, info.NodeKey2Bytes, nil
 		},
 		FindRoute:              s.chanRouter.FindRoute,
+		FindRoutes:             s.chanRouter.FindRoutes,
+		RouteFees:              s.chanRouter.RouteFees,
 		MissionControl:         s.missionControl,
 		ActiveNetParams:        r.cfg.ActiveNetParams.Params,
 		Tower:                  s.controlTower,
@@ -739,8 +906,33 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		SetChannelDisabled: func(outpoint wire.OutPoint) error {
 			return s.chanStatusMgr.RequestDisable(outpoint, true)
 		},
-		SetChannelAuto:     s.chanStatusMgr.RequestAuto,
-		UseStatusInitiated: subServerCgs.RouterRPC.UseStatusInitiated,
+		SetChannelAuto:      s.chanStatusMgr.RequestAuto,
+		CancelPayment:       s.chanRouter.CancelPayment,
+		FreezeChannel:       s.chanFreezer.Freeze,
+		UnfreezeChannel:     s.chanFreezer.Unfreeze,
+		AttributePayment:    s.accountStore.AttributePayment,
+		UseStatusInitiated:  subServerCgs.RouterRPC.UseStatusInitiated,
+		KnownRouteHints:     s.knownRouteHints,
+		JITChannels:         s.jitChannels,
+		QueryNetworkResults: s.htlcSwitch.QueryNetworkResults,
+		PruneNetworkResults: s.htlcSwitch.PruneNetworkResults,
+	}
+	if s.asyncPayments != nil {
+		routerBackend.HoldPayment = func(path []byte,
+			release func()) error {
+
+			recipientPath, err := onionmessage.DecodeBlindedPath(
+				path,
+			)
+			if err != nil {
+				return fmt.Errorf("invalid recipient path: %w",
+					err)
+			}
+
+			return s.asyncPayments.HoldPayment(
+				recipientPath, release,
+			)
+		}
 	}
 
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
@@ -771,7 +963,10 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		s.sweeper, tower, s.towerClientMgr, r.cfg.net.ResolveTCPAddr,
 		genInvoiceFeatures, genAmpInvoiceFeatures,
 		s.getNodeAnnouncement, s.updateAndBrodcastSelfNode, parseAddr,
-		rpcsLog, s.aliasMgr.GetPeerAlias,
+		rpcsLog, s.aliasMgr.GetPeerAlias, s.clock, s.faultInjector,
+		s.preimageDeriver, s.attributeInvoiceCreator,
+		s.featurePolicy.Rejections, s.lspClient, s.initRecords,
+		s.peerInitRecords, s.miscDB.FetchPeerStorageBlobs,
 	)
 	if err != nil {
 		return err
@@ -950,6 +1145,15 @@ func (r *rpcServer) RegisterWithRestProxy(restCtx context.Context,
 		}
 	}
 
+	// Serve LNURL-pay requests and Lightning Address callbacks directly
+	// from the REST proxy if enabled.
+	if r.cfg.LNURL.Active {
+		if err := r.registerLNURLHandlers(restMux); err != nil {
+			return fmt.Errorf("unable to register LNURL "+
+				"handlers: %w", err)
+		}
+	}
+
 	// Before listening on any of the interfaces, we also want to give the
 	// external subservers a chance to register their own REST proxy stub
 	// with our mux instance.
@@ -963,6 +1167,113 @@ func (r *rpcServer) RegisterWithRestProxy(restCtx context.Context,
 	return nil
 }
 
+// registerLNURLHandlers registers the LNURL-pay and Lightning Address
+// handlers of the configured users with the given REST proxy mux. The REST
+// proxy is started before the wallet is unlocked, so invoices can only be
+// created once the RPC server is started.
+func (r *rpcServer) registerLNURLHandlers(restMux *proxy.ServeMux) error {
+	cfgUsers, err := r.cfg.LNURL.ParseUsers()
+	if err != nil {
+		return err
+	}
+
+	users := make(map[string]*lnurl.User, len(cfgUsers))
+	for _, user := range cfgUsers {
+		users[user.Name] = &lnurl.User{
+			Name: user.Name,
+			MinSendable: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(user.MinSat),
+			),
+			MaxSendable: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(user.MaxSat),
+			),
+			Description: user.Description,
+		}
+	}
+
+	lnurlCfg := &lnurl.Config{
+		Domain:        r.cfg.LNURL.Domain,
+		Users:         users,
+		AddInvoice:    r.addLNURLInvoice,
+		CallbackRate:  rate.Limit(r.cfg.LNURL.CallbackRate),
+		CallbackBurst: r.cfg.LNURL.CallbackBurst,
+	}
+	if r.cfg.LNURL.Zaps {
+		lnurlCfg.NostrPubKey = r.lnurlNostrPubKey
+		lnurlCfg.AddZapRequest = r.addLNURLZapRequest
+	}
+	lnurlServer := lnurl.NewServer(lnurlCfg)
+
+	err = restMux.HandlePath(
+		http.MethodGet, lnurl.PayRequestPattern,
+		func(w http.ResponseWriter, req *http.Request,
+			params map[string]string) {
+
+			lnurlServer.HandlePayRequest(
+				w, req, params[lnurl.UsernameParam],
+			)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return restMux.HandlePath(
+		http.MethodGet, lnurl.CallbackPattern,
+		func(w http.ResponseWriter, req *http.Request,
+			params map[string]string) {
+
+			lnurlServer.HandleCallback(
+				w, req, params[lnurl.UsernameParam],
+			)
+		},
+	)
+}
+
+// addLNURLInvoice adds an invoice requested through an LNURL-pay callback and
+// returns its payment request.
+func (r *rpcServer) addLNURLInvoice(ctx context.Context,
+	amt lnwire.MilliSatoshi, descHash [sha256.Size]byte) (lntypes.Hash,
+	string, error) {
+
+	if atomic.LoadInt32(&r.started) == 0 {
+		return lntypes.Hash{}, "", errors.New("rpc server not started")
+	}
+
+	// Small nodes often only have private channels, so we include hop
+	// hints to make the invoices payable.
+	hash, dbInvoice, err := invoicesrpc.AddInvoice(
+		ctx, r.addInvoiceConfig(), &invoicesrpc.AddInvoiceData{
+			Value:           amt,
+			DescriptionHash: descHash[:],
+			Private:         true,
+		},
+	)
+	if err != nil {
+		return lntypes.Hash{}, "", err
+	}
+
+	return *hash, string(dbInvoice.PaymentRequest), nil
+}
+
+// lnurlNostrPubKey returns the public key zap receipts are signed with.
+func (r *rpcServer) lnurlNostrPubKey() (string, error) {
+	if atomic.LoadInt32(&r.started) == 0 {
+		return "", errors.New("rpc server not started")
+	}
+
+	return r.server.zapPublisher.PubKey(), nil
+}
+
+// addLNURLZapRequest stores the zap request an invoice was created for.
+func (r *rpcServer) addLNURLZapRequest(hash lntypes.Hash, raw []byte) error {
+	if atomic.LoadInt32(&r.started) == 0 {
+		return errors.New("rpc server not started")
+	}
+
+	return r.server.zapPublisher.AddZapRequest(hash, raw)
+}
+
 // Stop signals any active goroutines for a graceful closure.
 func (r *rpcServer) Stop() error {
 	if atomic.AddInt32(&r.shutdown, 1) != 1 {
@@ -1084,6 +1395,14 @@ func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
 		return nil, err
 	}
 
+	pkScripts := make([][]byte, len(outputs))
+	for i, output := range outputs {
+		pkScripts[i] = output.PkScript
+	}
+	if err := r.checkAddressReuse(pkScripts...); err != nil {
+		return nil, err
+	}
+
 	// We first do a dry run, to sanity check we won't spend our wallet
 	// balance below the reserved amount.
 	authoredTx, err := r.server.cc.Wallet.CreateSimpleTx(
@@ -1118,6 +1437,43 @@ func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
 	return &txHash, nil
 }
 
+// checkAddressReuse applies the configured address reuse policy to a
+// transaction paying to the given output scripts. Depending on the policy, a
+// warning is logged or an error is returned if a wallet transaction already
+// paid to one of them.
+func (r *rpcServer) checkAddressReuse(pkScripts ...[]byte) error {
+	if r.cfg.AddressReusePolicy == "allow" {
+		return nil
+	}
+
+	txns, err := r.server.cc.Wallet.ListTransactionDetails(
+		0, btcwallet.UnconfirmedHeight, "",
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, pkScript := range lnwallet.PreviouslyPaid(txns, pkScripts) {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			pkScript, r.cfg.ActiveNetParams.Params,
+		)
+		if err != nil || len(addrs) == 0 {
+			return fmt.Errorf("unable to decode output script "+
+				"%x: %v", pkScript, err)
+		}
+
+		if r.cfg.AddressReusePolicy == "refuse" {
+			return fmt.Errorf("address %v was already used by a "+
+				"wallet transaction", addrs[0])
+		}
+
+		rpcsLog.Warnf("Paying to address %v, which was already used "+
+			"by a wallet transaction", addrs[0])
+	}
+
+	return nil
+}
+
 // ListUnspent returns useful information about each unspent output owned by
 // the wallet, as reported by the underlying `ListUnspentWitness`; the
 // information returned is: outpoint, amount in satoshis, address, address
@@ -1134,6 +1490,11 @@ func (r *rpcServer) ListUnspent(ctx context.Context,
 		return nil, err
 	}
 
+	account, err := macaroons.EnforceAccount(ctx, in.Account)
+	if err != nil {
+		return nil, err
+	}
+
 	// With our arguments validated, we'll query the internal wallet for
 	// the set of UTXOs that match our query.
 	//
@@ -1143,7 +1504,7 @@ func (r *rpcServer) ListUnspent(ctx context.Context,
 	var utxos []*lnwallet.Utxo
 	err = r.server.cc.Wallet.WithCoinSelectLock(func() error {
 		utxos, err = r.server.cc.Wallet.ListUnspentWitness(
-			minConfs, maxConfs, in.Account,
+			minConfs, maxConfs, account,
 		)
 		return err
 	})
@@ -1272,11 +1633,32 @@ func maybeUseDefaultConf(satPerByte int64, satPerVByte uint64,
 	return defaultNumBlocksEstimate
 }
 
+// rejectAccountSpend returns an error if the macaroon of the request is bound
+// to an account. SendCoins and SendMany select coins from the default account
+// only, so such macaroons have to fund their transactions with the PSBT
+// methods of the wallet kit, which are restricted to their account.
+func rejectAccountSpend(ctx context.Context) error {
+	account, err := macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if account != "" {
+		return fmt.Errorf("macaroon is bound to account %v, use "+
+			"FundPsbt to spend from it", account)
+	}
+
+	return nil
+}
+
 // SendCoins executes a request to send coins to a particular address. Unlike
 // SendMany, this RPC call only allows creating a single output at a time.
 func (r *rpcServer) SendCoins(ctx context.Context,
 	in *lnrpc.SendCoinsRequest) (*lnrpc.SendCoinsResponse, error) {
 
+	if err := rejectAccountSpend(ctx); err != nil {
+		return nil, err
+	}
+
 	// Keep the old behavior prior to 0.18.0 - when the user doesn't set
 	// fee rate or conf target, the default conf target of 6 is used.
 	targetConf := maybeUseDefaultConf(
@@ -1359,6 +1741,14 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 				"active")
 		}
 
+		targetScript, err := txscript.PayToAddrScript(targetAddr)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.checkAddressReuse(targetScript); err != nil {
+			return nil, err
+		}
+
 		_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
 		if err != nil {
 			return nil, err
@@ -1504,6 +1894,10 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 func (r *rpcServer) SendMany(ctx context.Context,
 	in *lnrpc.SendManyRequest) (*lnrpc.SendManyResponse, error) {
 
+	if err := rejectAccountSpend(ctx); err != nil {
+		return nil, err
+	}
+
 	// Keep the old behavior prior to 0.18.0 - when the user doesn't set
 	// fee rate or conf target, the default conf target of 6 is used.
 	targetConf := maybeUseDefaultConf(
@@ -1574,18 +1968,19 @@ func (r *rpcServer) SendMany(ctx context.Context,
 func (r *rpcServer) NewAddress(ctx context.Context,
 	in *lnrpc.NewAddressRequest) (*lnrpc.NewAddressResponse, error) {
 
-	// Always use the default wallet account unless one was specified.
-	account := lnwallet.DefaultAccountName
-	if in.Account != "" {
-		account = in.Account
+	// Always use the default wallet account unless one was specified, or
+	// the macaroon is bound to one.
+	account, err := macaroons.EnforceAccount(ctx, in.Account)
+	if err != nil {
+		return nil, err
+	}
+	if account == "" {
+		account = lnwallet.DefaultAccountName
 	}
 
 	// Translate the gRPC proto address type to the wallet controller's
 	// available address types.
-	var (
-		addr btcutil.Address
-		err  error
-	)
+	var addr btcutil.Address
 	switch in.Type {
 	case lnrpc.AddressType_WITNESS_PUBKEY_HASH:
 		addr, err = r.server.cc.Wallet.NewAddress(
@@ -1955,11 +2350,11 @@ func newPsbtAssembler(req *lnrpc.OpenChannelRequest,
 	}
 
 	// The base PSBT is optional. But if it's set, it has to be a valid,
-	// binary serialized PSBT.
+	// binary serialized PSBT of version 0 or 2. We hand the funding PSBT
+	// back to the user in the same version.
+	psbtVersion := psbtv2.Version0
 	if len(psbtShim.BasePsbt) > 0 {
-		packet, err = psbt.NewFromRawBytes(
-			bytes.NewReader(psbtShim.BasePsbt), false,
-		)
+		packet, psbtVersion, err = psbtv2.Parse(psbtShim.BasePsbt)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing base PSBT: %w",
 				err)
@@ -1969,8 +2364,8 @@ func newPsbtAssembler(req *lnrpc.OpenChannelRequest,
 	// With all the parts assembled, we can now make the canned assembler
 	// to pass into the wallet.
 	return chanfunding.NewPsbtAssembler(
-		btcutil.Amount(req.LocalFundingAmount), packet, netParams,
-		!psbtShim.NoPublish,
+		btcutil.Amount(req.LocalFundingAmount), packet, psbtVersion,
+		netParams, !psbtShim.NoPublish,
 	), nil
 }
 
@@ -2256,8 +2651,9 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 
 	case lnrpc.CommitmentType_SIMPLE_TAPROOT:
 		// If the taproot channel type is being set, then the channel
-		// MUST be private (unadvertised) for now.
-		if !in.Private {
+		// MUST be private (unadvertised), unless taproot gossip is
+		// enabled.
+		if !in.Private && !r.cfg.ProtocolOptions.TaprootGossip {
 			return nil, fmt.Errorf("taproot channels must be " +
 				"private")
 		}
@@ -2606,6 +3002,15 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	}
 
 	force := in.Force
+
+	// In safe mode, we only allow force closes, as they resolve the
+	// channel without the cooperation of the peer.
+	if !force {
+		if err := r.server.safeMode.Err(); err != nil {
+			return err
+		}
+	}
+
 	index := in.ChannelPoint.OutputIndex
 	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
 	if err != nil {
@@ -2651,6 +3056,22 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		return err
 	}
 
+	// Record that the channel is being closed on request of the user. If
+	// the close attempt fails, a later close records no other reason, as
+	// it was the user who gave up on the channel first.
+	err = r.server.chanStateDB.PutCloseReason(
+		chanPoint, &channeldb.ChannelCloseReason{
+			Initiator: channeldb.CloseInitiatorLocal,
+			Reason:    channeldb.CloseReasonUserRequest,
+			Details:   fmt.Sprintf("force=%v", force),
+			Timestamp: time.Now(),
+		},
+	)
+	if err != nil {
+		rpcsLog.Errorf("Unable to record close reason of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+	}
+
 	// If a force closure was requested, then we'll handle all the details
 	// around the creation and broadcast of the unilateral closure
 	// transaction here rather than going to the switch as we don't require
@@ -3038,9 +3459,17 @@ func (r *rpcServer) AbandonChannel(_ context.Context,
 // GetInfo returns general information concerning the lightning node including
 // its identity pubkey, alias, the chains it is connected to, and information
 // concerning the number of open+pending channels.
-func (r *rpcServer) GetInfo(_ context.Context,
+func (r *rpcServer) GetInfo(ctx context.Context,
 	_ *lnrpc.GetInfoRequest) (*lnrpc.GetInfoResponse, error) {
 
+	// Clients can detect API changes by the API version. It is returned
+	// as a header as well, so REST clients can read it without parsing
+	// the response. Setting it fails if GetInfo isn't called through
+	// gRPC, which is fine.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		lnrpc.APIVersionMetadataKey, strconv.Itoa(lnrpc.APIVersion),
+	))
+
 	serverPeers := r.server.Peers()
 
 	openChannels, err := r.server.chanStateDB.FetchAllOpenChannels()
@@ -3155,6 +3584,21 @@ func (r *rpcServer) GetInfo(_ context.Context,
 		Features:                  features,
 		RequireHtlcInterceptor:    r.cfg.RequireInterceptor,
 		StoreFinalHtlcResolutions: r.cfg.StoreFinalHtlcResolutions,
+		ApiVersion:                lnrpc.APIVersion,
+	}, nil
+}
+
+// ListDeprecations returns the deprecated RPC methods and the deprecated
+// request and response fields of all methods, as declared in the proto
+// definitions. If a prefix is given, only methods whose full name starts with
+// it are returned. This allows clients to detect their use of deprecated parts
+// of the API before they are removed.
+func (r *rpcServer) ListDeprecations(_ context.Context,
+	req *lnrpc.ListDeprecationsRequest) (*lnrpc.ListDeprecationsResponse,
+	error) {
+
+	return &lnrpc.ListDeprecationsResponse{
+		Methods: lnrpc.Deprecations(req.MethodPrefix),
 	}, nil
 }
 
@@ -3183,6 +3627,32 @@ func (r *rpcServer) GetDebugInfo(_ context.Context,
 	}, nil
 }
 
+// GetEffectiveConfig returns the configuration the daemon is running with,
+// after defaults were applied and the config was validated, as a flat map of
+// options with secrets redacted. Unlike GetDebugInfo, it doesn't read the log
+// file.
+func (r *rpcServer) GetEffectiveConfig(_ context.Context,
+	_ *lnrpc.GetEffectiveConfigRequest) (*lnrpc.GetEffectiveConfigResponse,
+	error) {
+
+	flatConfig, deprecated, err := configToFlatMap(*r.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error converting config to flat map: "+
+			"%w", err)
+	}
+
+	deprecatedKeys := make([]string, 0, len(deprecated))
+	for key := range deprecated {
+		deprecatedKeys = append(deprecatedKeys, key)
+	}
+	sort.Strings(deprecatedKeys)
+
+	return &lnrpc.GetEffectiveConfigResponse{
+		Config:     flatConfig,
+		Deprecated: deprecatedKeys,
+	}, nil
+}
+
 // GetRecoveryInfo returns a boolean indicating whether the wallet is started
 // in recovery mode, whether the recovery is finished, and the progress made
 // so far.
@@ -3401,9 +3871,16 @@ func (r *rpcServer) SubscribePeerEvents(req *lnrpc.PeerEventSubscription,
 func (r *rpcServer) WalletBalance(ctx context.Context,
 	in *lnrpc.WalletBalanceRequest) (*lnrpc.WalletBalanceResponse, error) {
 
-	// Retrieve all existing wallet accounts. We'll compute the confirmed
+	// A macaroon that is bound to an account only sees the balance of
+	// that account.
+	accountFilter, err := macaroons.EnforceAccount(ctx, in.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve all existing wallet accounts. We'll compute the confirmed
 	// and unconfirmed balance for each and tally them up.
-	accounts, err := r.server.cc.Wallet.ListAccounts(in.Account, nil)
+	accounts, err := r.server.cc.Wallet.ListAccounts(accountFilter, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -3522,6 +3999,84 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 	}, nil
 }
 
+// SubscribeWalletBalance sends the on-chain balance of the wallet, broken down
+// by account into confirmed, unconfirmed, leased and anchor reserve amounts,
+// and then sends an update whenever it changes. The balance is recomputed on
+// every wallet transaction and block, and periodically to catch expiring
+// leases. A macaroon that is bound to an account only sees the balance of
+// that account.
+func (r *rpcServer) SubscribeWalletBalance(
+	req *lnrpc.SubscribeWalletBalanceRequest,
+	updateStream lnrpc.Lightning_SubscribeWalletBalanceServer) error {
+
+	ctx := updateStream.Context()
+	accountFilter, err := macaroons.EnforceAccount(ctx, req.Account)
+	if err != nil {
+		return err
+	}
+
+	txClient, err := r.server.cc.Wallet.SubscribeTransactions()
+	if err != nil {
+		return err
+	}
+	defer txClient.Cancel()
+
+	blockEpochs, err := r.server.cc.ChainNotifier.RegisterBlockEpochNtfn(
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	defer blockEpochs.Cancel()
+
+	ticker := time.NewTicker(walletBalancePollInterval)
+	defer ticker.Stop()
+
+	rpcsLog.Infof("New wallet balance subscription")
+
+	var lastUpdate *WalletBalanceUpdate
+	for {
+		update, err := walletBalanceUpdate(
+			r.server.cc.Wallet, accountFilter, req.MinConfs,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !update.equal(lastUpdate) {
+			err := updateStream.Send(marshalWalletBalanceUpdate(
+				update,
+			))
+			if err != nil {
+				return err
+			}
+			lastUpdate = update
+		}
+
+		select {
+		case <-txClient.ConfirmedTransactions():
+		case <-txClient.UnconfirmedTransactions():
+		case <-ticker.C:
+
+		case _, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return errors.New("block epoch subscription " +
+					"closed")
+			}
+
+		case <-ctx.Done():
+			rpcsLog.Infof("Canceling wallet balance subscription")
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
 // ChannelBalance returns the total available channel flow across all open
 // channels in satoshis.
 func (r *rpcServer) ChannelBalance(ctx context.Context,
@@ -3704,6 +4259,15 @@ func (r *rpcServer) fetchPendingForceCloseChannels() (pendingForceClose,
 		return nil, 0, err
 	}
 
+	return r.rpcPendingForceCloseChannels(channels, currentHeight)
+}
+
+// rpcPendingForceCloseChannels converts the given close summaries of channels
+// pending close into the force closed channels of the PendingChannels RPC.
+func (r *rpcServer) rpcPendingForceCloseChannels(
+	channels []*channeldb.ChannelCloseSummary,
+	currentHeight int32) (pendingForceClose, int64, error) {
+
 	result := make(pendingForceClose, 0)
 	limboBalance := int64(0)
 
@@ -4181,216 +4745,695 @@ func (r *rpcServer) ClosedChannels(ctx context.Context,
 	in *lnrpc.ClosedChannelsRequest) (*lnrpc.ClosedChannelsResponse,
 	error) {
 
-	// Show all channels when no filter flags are set.
-	filterResults := in.Cooperative || in.LocalForce ||
-		in.RemoteForce || in.Breach || in.FundingCanceled ||
-		in.Abandoned
+	resp, _, err := r.closedChannelsPage(in, &channelPageRequest{})
 
-	resp := &lnrpc.ClosedChannelsResponse{}
+	return resp, err
+}
 
-	dbChannels, err := r.server.chanStateDB.FetchClosedChannels(false)
-	if err != nil {
-		return nil, err
-	}
+// channelPageRequest describes a page of the channels returned by the
+// ClosedChannelsPage and PendingChannelsPage RPCs.
+type channelPageRequest struct {
+	// Cursor is the cursor returned with the previous page. If empty, the
+	// first page is returned.
+	Cursor []byte
 
-	// In order to make the response easier to parse for clients, we'll
-	// sort the set of closed channels by their closing height before
-	// serializing the proto response.
-	sort.Slice(dbChannels, func(i, j int) bool {
-		return dbChannels[i].CloseHeight < dbChannels[j].CloseHeight
-	})
+	// MaxChannels is the maximum number of channels to return. If zero,
+	// all matching channels are returned.
+	MaxChannels uint32
 
-	for _, dbChannel := range dbChannels {
-		if dbChannel.IsPending {
-			continue
-		}
+	// StartHeight is the lowest close height of the channels to return.
+	StartHeight uint32
 
-		switch dbChannel.CloseType {
-		case channeldb.CooperativeClose:
-			if filterResults && !in.Cooperative {
-				continue
-			}
-		case channeldb.LocalForceClose:
-			if filterResults && !in.LocalForce {
-				continue
-			}
-		case channeldb.RemoteForceClose:
-			if filterResults && !in.RemoteForce {
-				continue
-			}
-		case channeldb.BreachClose:
-			if filterResults && !in.Breach {
-				continue
-			}
-		case channeldb.FundingCanceled:
-			if filterResults && !in.FundingCanceled {
-				continue
-			}
-		case channeldb.Abandoned:
-			if filterResults && !in.Abandoned {
-				continue
-			}
-		}
+	// EndHeight is the highest close height of the channels to return. If
+	// zero, there is no upper bound.
+	EndHeight uint32
 
-		channel, err := r.createRPCClosedChannel(dbChannel)
+	// Peer restricts the page to the channels with the given peer.
+	Peer *btcec.PublicKey
+}
+
+// unmarshalChannelPage converts the RPC page request. A nil page requests all
+// channels.
+func unmarshalChannelPage(in *lnrpc.ChannelPage) (*channelPageRequest, error) {
+	if in == nil {
+		return &channelPageRequest{}, nil
+	}
+
+	if in.EndHeight != 0 && in.EndHeight < in.StartHeight {
+		return nil, fmt.Errorf("end height %d below start height %d",
+			in.EndHeight, in.StartHeight)
+	}
+
+	page := &channelPageRequest{
+		Cursor:      in.Cursor,
+		MaxChannels: in.MaxChannels,
+		StartHeight: in.StartHeight,
+		EndHeight:   in.EndHeight,
+	}
+	if len(in.Peer) != 0 {
+		peer, err := btcec.ParsePubKey(in.Peer)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid peer: %w", err)
 		}
-
-		resp.Channels = append(resp.Channels, channel)
+		page.Peer = peer
 	}
 
-	return resp, nil
+	return page, nil
 }
 
-// LookupHtlcResolution retrieves a final htlc resolution from the database. If
-// the htlc has no final resolution yet, a NotFound grpc status code is
-// returned.
-func (r *rpcServer) LookupHtlcResolution(
-	_ context.Context, in *lnrpc.LookupHtlcResolutionRequest) (
-	*lnrpc.LookupHtlcResolutionResponse, error) {
+// dbQuery returns the database query for the page.
+func (p *channelPageRequest) dbQuery() *channeldb.ClosedChannelQuery {
+	return &channeldb.ClosedChannelQuery{
+		Cursor:         p.Cursor,
+		MaxChannels:    p.MaxChannels,
+		MinCloseHeight: p.StartHeight,
+		MaxCloseHeight: p.EndHeight,
+		RemotePub:      p.Peer,
+	}
+}
 
-	if !r.cfg.StoreFinalHtlcResolutions {
-		return nil, status.Error(codes.Unavailable, "cannot lookup "+
-			"with flag --store-final-htlc-resolutions=false")
+// matchesPeer returns true if the channel with the given hex encoded remote
+// node public key matches the peer filter of the page.
+func (p *channelPageRequest) matchesPeer(remoteNodePub string) bool {
+	if p.Peer == nil {
+		return true
 	}
 
-	chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+	return remoteNodePub == hex.EncodeToString(p.Peer.SerializeCompressed())
+}
 
-	info, err := r.server.chanStateDB.LookupFinalHtlc(chanID, in.HtlcIndex)
-	switch {
-	case errors.Is(err, channeldb.ErrHtlcUnknown):
-		return nil, status.Error(codes.NotFound, err.Error())
+// closeTypeFilter returns the close types to return for the given request. If
+// no close type is set, channels are returned regardless of their close type.
+func closeTypeFilter(in *lnrpc.ClosedChannelsRequest) []channeldb.ClosureType {
+	var closeTypes []channeldb.ClosureType
+	addType := func(set bool, closeType channeldb.ClosureType) {
+		if set {
+			closeTypes = append(closeTypes, closeType)
+		}
+	}
 
-	case err != nil:
+	addType(in.Cooperative, channeldb.CooperativeClose)
+	addType(in.LocalForce, channeldb.LocalForceClose)
+	addType(in.RemoteForce, channeldb.RemoteForceClose)
+	addType(in.Breach, channeldb.BreachClose)
+	addType(in.FundingCanceled, channeldb.FundingCanceled)
+	addType(in.Abandoned, channeldb.Abandoned)
+
+	return closeTypes
+}
+
+// ClosedChannelsPage returns a page of the channels that have been closed,
+// ordered by their close height. Besides the close type filters of the
+// request, the channels can be filtered by their close height and peer. The
+// returned cursor is passed with the request of the next page, and is empty
+// once there are no more channels.
+func (r *rpcServer) ClosedChannelsPage(_ context.Context,
+	in *lnrpc.ClosedChannelsPageRequest) (*lnrpc.ClosedChannelsPageResponse,
+	error) {
+
+	page, err := unmarshalChannelPage(in.Page)
+	if err != nil {
 		return nil, err
 	}
 
-	return &lnrpc.LookupHtlcResolutionResponse{
-		Settled:  info.Settled,
-		Offchain: info.Offchain,
+	filter := in.Filter
+	if filter == nil {
+		filter = &lnrpc.ClosedChannelsRequest{}
+	}
+
+	resp, nextCursor, err := r.closedChannelsPage(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ClosedChannelsPageResponse{
+		Channels:   resp.Channels,
+		NextCursor: nextCursor,
 	}, nil
 }
 
-// ListChannels returns a description of all the open channels that this node
-// is a participant in.
-func (r *rpcServer) ListChannels(ctx context.Context,
-	in *lnrpc.ListChannelsRequest) (*lnrpc.ListChannelsResponse, error) {
+// closedChannelsPage returns the closed channels of the given page, along
+// with the cursor of the next page.
+func (r *rpcServer) closedChannelsPage(in *lnrpc.ClosedChannelsRequest,
+	page *channelPageRequest) (*lnrpc.ClosedChannelsResponse, []byte,
+	error) {
 
-	if in.ActiveOnly && in.InactiveOnly {
-		return nil, fmt.Errorf("either `active_only` or " +
-			"`inactive_only` can be set, but not both")
-	}
+	query := page.dbQuery()
+	query.ExcludePending = true
+	query.CloseTypes = closeTypeFilter(in)
 
-	if in.PublicOnly && in.PrivateOnly {
-		return nil, fmt.Errorf("either `public_only` or " +
-			"`private_only` can be set, but not both")
+	dbPage, err := r.server.chanStateDB.FetchClosedChannelsPage(query)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if len(in.Peer) > 0 && len(in.Peer) != 33 {
-		_, err := route.NewVertexFromBytes(in.Peer)
-		return nil, fmt.Errorf("invalid `peer` key: %w", err)
+	resp := &lnrpc.ClosedChannelsResponse{}
+	for _, dbChannel := range dbPage.Channels {
+		channel, err := r.createRPCClosedChannel(dbChannel)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp.Channels = append(resp.Channels, channel)
 	}
 
-	resp := &lnrpc.ListChannelsResponse{}
+	return resp, dbPage.NextCursor, nil
+}
 
-	dbChannels, err := r.server.chanStateDB.FetchAllOpenChannels()
+// PendingChannelsPage returns a page of the channels that are pending open or
+// close. The force closed channels that are still being resolved are paged by
+// their close height, and can be filtered by their close height and peer.
+// Channels pending open or waiting for their closing transaction to confirm
+// are read from the open channels, and are only returned with the first page,
+// filtered by peer. The returned cursor is passed with the request of the
+// next page, and is empty once there are no more channels.
+func (r *rpcServer) PendingChannelsPage(_ context.Context,
+	in *lnrpc.PendingChannelsPageRequest) (
+	*lnrpc.PendingChannelsPageResponse, error) {
+
+	page, err := unmarshalChannelPage(in.Page)
 	if err != nil {
 		return nil, err
 	}
 
-	rpcsLog.Debugf("[listchannels] fetched %v channels from DB",
-		len(dbChannels))
+	resp, nextCursor, err := r.pendingChannelsPage(in.IncludeRawTx, page)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, dbChannel := range dbChannels {
-		nodePub := dbChannel.IdentityPub
-		nodePubBytes := nodePub.SerializeCompressed()
-		chanPoint := dbChannel.FundingOutpoint
+	return &lnrpc.PendingChannelsPageResponse{
+		Channels:   resp,
+		NextCursor: nextCursor,
+	}, nil
+}
 
-		// If the caller requested channels for a target node, skip any
-		// that don't match the provided pubkey.
-		if len(in.Peer) > 0 && !bytes.Equal(nodePubBytes, in.Peer) {
-			continue
-		}
+// pendingChannelsPage returns the pending channels of the given page, along
+// with the cursor of the next page.
+func (r *rpcServer) pendingChannelsPage(includeRawTx bool,
+	page *channelPageRequest) (*lnrpc.PendingChannelsResponse, []byte,
+	error) {
 
-		var peerOnline bool
-		if _, err := r.server.FindPeer(nodePub); err == nil {
-			peerOnline = true
-		}
+	_, currentHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, nil, err
+	}
 
-		channelID := lnwire.NewChanIDFromOutPoint(chanPoint)
-		var linkActive bool
-		if link, err := r.server.htlcSwitch.GetLink(channelID); err == nil {
-			// A channel is only considered active if it is known
-			// by the switch *and* able to forward
-			// incoming/outgoing payments.
-			linkActive = link.EligibleToForward()
+	query := page.dbQuery()
+	query.PendingOnly = true
+
+	dbPage, err := r.server.chanStateDB.FetchClosedChannelsPage(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := &lnrpc.PendingChannelsResponse{}
+	resp.PendingForceClosingChannels, resp.TotalLimboBalance, err =
+		r.rpcPendingForceCloseChannels(dbPage.Channels, currentHeight)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(page.Cursor) != 0 {
+		return resp, dbPage.NextCursor, nil
+	}
+
+	pendingOpenChannels, err := r.fetchPendingOpenChannels()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pendingOpen := range pendingOpenChannels {
+		if !page.matchesPeer(pendingOpen.Channel.RemoteNodePub) {
+			continue
 		}
 
-		// Next, we'll determine whether we should add this channel to
-		// our list depending on the type of channels requested to us.
-		isActive := peerOnline && linkActive
-		channel, err := createRPCOpenChannel(
-			r, dbChannel, isActive, in.PeerAliasLookup,
+		resp.PendingOpenChannels = append(
+			resp.PendingOpenChannels, pendingOpen,
 		)
-		if err != nil {
-			return nil, err
-		}
+	}
 
-		// We'll only skip returning this channel if we were requested
-		// for a specific kind and this channel doesn't satisfy it.
-		switch {
-		case in.ActiveOnly && !isActive:
-			continue
-		case in.InactiveOnly && isActive:
-			continue
-		case in.PublicOnly && channel.Private:
-			continue
-		case in.PrivateOnly && !channel.Private:
+	waitingCloseChannels, _, err := r.fetchWaitingCloseChannels(
+		includeRawTx,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, waitingClose := range waitingCloseChannels {
+		if !page.matchesPeer(waitingClose.Channel.RemoteNodePub) {
 			continue
 		}
 
-		resp.Channels = append(resp.Channels, channel)
+		resp.WaitingCloseChannels = append(
+			resp.WaitingCloseChannels, waitingClose,
+		)
+		resp.TotalLimboBalance += waitingClose.LimboBalance
 	}
 
-	return resp, nil
+	return resp, dbPage.NextCursor, nil
 }
 
-// rpcCommitmentType takes the channel type and converts it to an rpc commitment
-// type value.
-func rpcCommitmentType(chanType channeldb.ChannelType) lnrpc.CommitmentType {
-	// Extract the commitment type from the channel type flags. We must
-	// first check whether it has anchors, since in that case it would also
-	// be tweakless.
-	switch {
-	case chanType.IsTaproot():
-		return lnrpc.CommitmentType_SIMPLE_TAPROOT
+// anchorProofSigner signs anchor ownership proofs with the node key and the
+// funding key of a channel.
+type anchorProofSigner struct {
+	nodeSigner *netann.NodeSigner
+	msgSigner  lnwallet.MessageSigner
+	fundingKey keychain.KeyLocator
+}
 
-	case chanType.HasLeaseExpiration():
-		return lnrpc.CommitmentType_SCRIPT_ENFORCED_LEASE
+// SignNodeMessage returns the compact signature of the node key over the
+// message.
+func (s *anchorProofSigner) SignNodeMessage(msg []byte) ([]byte, error) {
+	return s.nodeSigner.SignMessageCompact(msg, true)
+}
 
-	case chanType.HasAnchors():
-		return lnrpc.CommitmentType_ANCHORS
+// SignFundingMessage returns the signature of the funding key over the
+// message.
+func (s *anchorProofSigner) SignFundingMessage(
+	msg []byte) (*ecdsa.Signature, error) {
 
-	case chanType.IsTweakless():
-		return lnrpc.CommitmentType_STATIC_REMOTE_KEY
-	default:
+	return s.msgSigner.SignMessage(s.fundingKey, msg, true)
+}
 
-		return lnrpc.CommitmentType_LEGACY
+// AnchorOwnershipProofs returns signed proofs that tie the anchor outputs of
+// our channels to our node, so auditors can attribute the anchors and their
+// sweeps on-chain without access to the node database. If channel points are
+// given, only proofs for those channels are returned. Closed channels are
+// only included if requested. Taproot channels aren't supported, as their
+// anchor scripts change with every commitment.
+func (r *rpcServer) AnchorOwnershipProofs(_ context.Context,
+	in *lnrpc.AnchorOwnershipProofsRequest) (
+	*lnrpc.AnchorOwnershipProofsResponse, error) {
+
+	wanted := make(map[wire.OutPoint]struct{}, len(in.ChannelPoints))
+	for _, chanPoint := range in.ChannelPoints {
+		outPoint, err := wire.NewOutPointFromString(chanPoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel point %v: %w",
+				chanPoint, err)
+		}
+		wanted[*outPoint] = struct{}{}
 	}
-}
+	isWanted := func(chanPoint wire.OutPoint) bool {
+		if len(wanted) == 0 {
+			return true
+		}
+		_, ok := wanted[chanPoint]
 
-// createChannelConstraint creates a *lnrpc.ChannelConstraints using the
-// *Channeldb.ChannelConfig.
-func createChannelConstraint(
-	chanCfg *channeldb.ChannelConfig) *lnrpc.ChannelConstraints {
-	return &lnrpc.ChannelConstraints{
-		CsvDelay:          uint32(chanCfg.CsvDelay),
-		ChanReserveSat:    uint64(chanCfg.ChanReserve),
-		DustLimitSat:      uint64(chanCfg.DustLimit),
-		MaxPendingAmtMsat: uint64(chanCfg.MaxPendingAmount),
-		MinHtlcMsat:       uint64(chanCfg.MinHTLC),
-		MaxAcceptedHtlcs:  uint32(chanCfg.MaxAcceptedHtlcs),
+		return ok
+	}
+
+	nodeKey := r.server.identityECDH.PubKey()
+	newProof := func(channel *channeldb.OpenChannel,
+		closingTxid *chainhash.Hash) (*anchorproof.Proof, error) {
+
+		fundingKey := channel.LocalChanCfg.MultiSigKey
+		signer := &anchorProofSigner{
+			nodeSigner: r.server.nodeSigner,
+			msgSigner:  r.server.cc.MsgSigner,
+			fundingKey: fundingKey.KeyLocator,
+		}
+
+		return anchorproof.New(
+			signer, nodeKey, channel.FundingOutpoint,
+			fundingKey.PubKey, closingTxid,
+		)
+	}
+	hasAnchorProof := func(chanType channeldb.ChannelType) bool {
+		return chanType.HasAnchors() && !chanType.IsTaproot()
+	}
+
+	openChannels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var proofs []*anchorproof.Proof
+	for _, channel := range openChannels {
+		if !hasAnchorProof(channel.ChanType) ||
+			!isWanted(channel.FundingOutpoint) {
+
+			continue
+		}
+
+		proof, err := newProof(channel, nil)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+
+	if !in.IncludeClosed {
+		return marshalAnchorProofs(proofs), nil
+	}
+
+	closedChannels, err := r.server.chanStateDB.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, summary := range closedChannels {
+		if !isWanted(summary.ChanPoint) {
+			continue
+		}
+
+		// The funding key is only known from the historical channel
+		// state, which channels closed by old versions don't have.
+		channel, err := r.server.chanStateDB.FetchHistoricalChannel(
+			&summary.ChanPoint,
+		)
+		switch {
+		case errors.Is(err, channeldb.ErrNoHistoricalBucket),
+			errors.Is(err, channeldb.ErrChannelNotFound):
+
+			continue
+
+		case err != nil:
+			return nil, err
+		}
+
+		if !hasAnchorProof(channel.ChanType) {
+			continue
+		}
+
+		closingTxid := summary.ClosingTXID
+		proof, err := newProof(channel, &closingTxid)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+
+	return marshalAnchorProofs(proofs), nil
+}
+
+// marshalAnchorProofs converts the anchor ownership proofs to their RPC
+// representation.
+func marshalAnchorProofs(
+	proofs []*anchorproof.Proof) *lnrpc.AnchorOwnershipProofsResponse {
+
+	resp := &lnrpc.AnchorOwnershipProofsResponse{
+		Proofs: make([]*lnrpc.AnchorOwnershipProof, 0, len(proofs)),
+	}
+	for _, proof := range proofs {
+		fundingKey := proof.FundingKey.SerializeCompressed()
+		rpcProof := &lnrpc.AnchorOwnershipProof{
+			NodeKey:          proof.NodeKey.SerializeCompressed(),
+			ChannelPoint:     proof.ChannelPoint.String(),
+			FundingKey:       fundingKey,
+			AnchorScript:     proof.AnchorScript,
+			Message:          string(proof.Message()),
+			NodeSignature:    proof.NodeSignature,
+			FundingSignature: proof.FundingSignature,
+		}
+		if proof.ClosingTxid != nil {
+			rpcProof.ClosingTxid = proof.ClosingTxid.String()
+		}
+
+		resp.Proofs = append(resp.Proofs, rpcProof)
+	}
+
+	return resp
+}
+
+// ChannelCloseHistory returns who initiated the close and why for all
+// channels that were closed, ordered by their close height. Channels that are
+// still pending close are included if requested.
+func (r *rpcServer) ChannelCloseHistory(_ context.Context,
+	in *lnrpc.ChannelCloseHistoryRequest) (
+	*lnrpc.ChannelCloseHistoryResponse, error) {
+
+	dbChannels, err := r.server.chanStateDB.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(dbChannels, func(i, j int) bool {
+		return dbChannels[i].CloseHeight < dbChannels[j].CloseHeight
+	})
+
+	resp := &lnrpc.ChannelCloseHistoryResponse{}
+	for _, dbChannel := range dbChannels {
+		if dbChannel.IsPending && !in.IncludePending {
+			continue
+		}
+
+		channel, err := r.createRPCClosedChannel(dbChannel)
+		if err != nil {
+			return nil, err
+		}
+
+		reason, err := r.fetchCloseReason(dbChannel)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &lnrpc.ChannelCloseHistoryEntry{
+			Channel:        channel,
+			CloseInitiator: rpcCloseInitiator(reason.Initiator),
+			Reason:         rpcCloseReason(reason.Reason),
+			Details:        reason.Details,
+		}
+		if !reason.Timestamp.IsZero() {
+			entry.Timestamp = reason.Timestamp.Unix()
+		}
+
+		resp.Channels = append(resp.Channels, entry)
+	}
+
+	return resp, nil
+}
+
+// rpcCloseInitiator converts the initiator of a close to its rpc counterpart.
+func rpcCloseInitiator(initiator channeldb.CloseInitiator) lnrpc.Initiator {
+	switch initiator {
+	case channeldb.CloseInitiatorLocal:
+		return lnrpc.Initiator_INITIATOR_LOCAL
+
+	case channeldb.CloseInitiatorRemote:
+		return lnrpc.Initiator_INITIATOR_REMOTE
+
+	default:
+		return lnrpc.Initiator_INITIATOR_UNKNOWN
+	}
+}
+
+// rpcCloseReason converts the reason of a close to its rpc counterpart.
+func rpcCloseReason(reason channeldb.CloseReason) lnrpc.CloseReason {
+	switch reason {
+	case channeldb.CloseReasonUserRequest:
+		return lnrpc.CloseReason_CLOSE_REASON_USER_REQUEST
+
+	case channeldb.CloseReasonPeerRequest:
+		return lnrpc.CloseReason_CLOSE_REASON_PEER_REQUEST
+
+	case channeldb.CloseReasonRemoteForceClose:
+		return lnrpc.CloseReason_CLOSE_REASON_REMOTE_FORCE_CLOSE
+
+	case channeldb.CloseReasonBreach:
+		return lnrpc.CloseReason_CLOSE_REASON_BREACH
+
+	case channeldb.CloseReasonHtlcExpiry:
+		return lnrpc.CloseReason_CLOSE_REASON_HTLC_EXPIRY
+
+	case channeldb.CloseReasonPeerBehavior:
+		return lnrpc.CloseReason_CLOSE_REASON_PEER_BEHAVIOR
+
+	case channeldb.CloseReasonFundingCanceled:
+		return lnrpc.CloseReason_CLOSE_REASON_FUNDING_CANCELED
+
+	default:
+		return lnrpc.CloseReason_CLOSE_REASON_UNKNOWN
+	}
+}
+
+// fetchCloseReason returns the recorded close reason of the given closed
+// channel. If no reason was recorded, the reason is derived from the close
+// type as far as possible.
+func (r *rpcServer) fetchCloseReason(
+	summary *channeldb.ChannelCloseSummary) (*channeldb.ChannelCloseReason,
+	error) {
+
+	reason, err := r.server.chanStateDB.FetchCloseReason(&summary.ChanPoint)
+	switch {
+	case err == nil:
+		return reason, nil
+
+	case !errors.Is(err, channeldb.ErrNoCloseReason):
+		return nil, err
+	}
+
+	reason = &channeldb.ChannelCloseReason{}
+	switch summary.CloseType {
+	case channeldb.LocalForceClose:
+		reason.Initiator = channeldb.CloseInitiatorLocal
+
+	case channeldb.RemoteForceClose:
+		reason.Initiator = channeldb.CloseInitiatorRemote
+		reason.Reason = channeldb.CloseReasonRemoteForceClose
+
+	case channeldb.BreachClose:
+		reason.Initiator = channeldb.CloseInitiatorRemote
+		reason.Reason = channeldb.CloseReasonBreach
+
+	case channeldb.FundingCanceled:
+		reason.Initiator = channeldb.CloseInitiatorLocal
+		reason.Reason = channeldb.CloseReasonFundingCanceled
+
+	case channeldb.Abandoned:
+		reason.Initiator = channeldb.CloseInitiatorLocal
+		reason.Reason = channeldb.CloseReasonUserRequest
+	}
+
+	return reason, nil
+}
+
+// LookupHtlcResolution retrieves a final htlc resolution from the database. If
+// the htlc has no final resolution yet, a NotFound grpc status code is
+// returned.
+func (r *rpcServer) LookupHtlcResolution(
+	_ context.Context, in *lnrpc.LookupHtlcResolutionRequest) (
+	*lnrpc.LookupHtlcResolutionResponse, error) {
+
+	if !r.cfg.StoreFinalHtlcResolutions {
+		return nil, status.Error(codes.Unavailable, "cannot lookup "+
+			"with flag --store-final-htlc-resolutions=false")
+	}
+
+	chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+
+	info, err := r.server.chanStateDB.LookupFinalHtlc(chanID, in.HtlcIndex)
+	switch {
+	case errors.Is(err, channeldb.ErrHtlcUnknown):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.LookupHtlcResolutionResponse{
+		Settled:  info.Settled,
+		Offchain: info.Offchain,
+	}, nil
+}
+
+// ListChannels returns a description of all the open channels that this node
+// is a participant in.
+func (r *rpcServer) ListChannels(ctx context.Context,
+	in *lnrpc.ListChannelsRequest) (*lnrpc.ListChannelsResponse, error) {
+
+	if in.ActiveOnly && in.InactiveOnly {
+		return nil, fmt.Errorf("either `active_only` or " +
+			"`inactive_only` can be set, but not both")
+	}
+
+	if in.PublicOnly && in.PrivateOnly {
+		return nil, fmt.Errorf("either `public_only` or " +
+			"`private_only` can be set, but not both")
+	}
+
+	if len(in.Peer) > 0 && len(in.Peer) != 33 {
+		_, err := route.NewVertexFromBytes(in.Peer)
+		return nil, fmt.Errorf("invalid `peer` key: %w", err)
+	}
+
+	resp := &lnrpc.ListChannelsResponse{}
+
+	dbChannels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[listchannels] fetched %v channels from DB",
+		len(dbChannels))
+
+	for _, dbChannel := range dbChannels {
+		nodePub := dbChannel.IdentityPub
+		nodePubBytes := nodePub.SerializeCompressed()
+		chanPoint := dbChannel.FundingOutpoint
+
+		// If the caller requested channels for a target node, skip any
+		// that don't match the provided pubkey.
+		if len(in.Peer) > 0 && !bytes.Equal(nodePubBytes, in.Peer) {
+			continue
+		}
+
+		var peerOnline bool
+		if _, err := r.server.FindPeer(nodePub); err == nil {
+			peerOnline = true
+		}
+
+		channelID := lnwire.NewChanIDFromOutPoint(chanPoint)
+		var linkActive bool
+		if link, err := r.server.htlcSwitch.GetLink(channelID); err == nil {
+			// A channel is only considered active if it is known
+			// by the switch *and* able to forward
+			// incoming/outgoing payments.
+			linkActive = link.EligibleToForward()
+		}
+
+		// Next, we'll determine whether we should add this channel to
+		// our list depending on the type of channels requested to us.
+		isActive := peerOnline && linkActive
+		channel, err := createRPCOpenChannel(
+			r, dbChannel, isActive, in.PeerAliasLookup,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// We'll only skip returning this channel if we were requested
+		// for a specific kind and this channel doesn't satisfy it.
+		switch {
+		case in.ActiveOnly && !isActive:
+			continue
+		case in.InactiveOnly && isActive:
+			continue
+		case in.PublicOnly && channel.Private:
+			continue
+		case in.PrivateOnly && !channel.Private:
+			continue
+		}
+
+		resp.Channels = append(resp.Channels, channel)
+	}
+
+	return resp, nil
+}
+
+// rpcCommitmentType takes the channel type and converts it to an rpc commitment
+// type value.
+func rpcCommitmentType(chanType channeldb.ChannelType) lnrpc.CommitmentType {
+	// Extract the commitment type from the channel type flags. We must
+	// first check whether it has anchors, since in that case it would also
+	// be tweakless.
+	switch {
+	case chanType.IsTaproot():
+		return lnrpc.CommitmentType_SIMPLE_TAPROOT
+
+	case chanType.HasLeaseExpiration():
+		return lnrpc.CommitmentType_SCRIPT_ENFORCED_LEASE
+
+	case chanType.HasAnchors():
+		return lnrpc.CommitmentType_ANCHORS
+
+	case chanType.IsTweakless():
+		return lnrpc.CommitmentType_STATIC_REMOTE_KEY
+	default:
+
+		return lnrpc.CommitmentType_LEGACY
+	}
+}
+
+// createChannelConstraint creates a *lnrpc.ChannelConstraints using the
+// *Channeldb.ChannelConfig.
+func createChannelConstraint(
+	chanCfg *channeldb.ChannelConfig) *lnrpc.ChannelConstraints {
+	return &lnrpc.ChannelConstraints{
+		CsvDelay:          uint32(chanCfg.CsvDelay),
+		ChanReserveSat:    uint64(chanCfg.ChanReserve),
+		DustLimitSat:      uint64(chanCfg.DustLimit),
+		MaxPendingAmtMsat: uint64(chanCfg.MaxPendingAmount),
+		MinHtlcMsat:       uint64(chanCfg.MinHTLC),
+		MaxAcceptedHtlcs:  uint32(chanCfg.MaxAcceptedHtlcs),
 	}
 }
 
@@ -4661,6 +5704,23 @@ func (r *rpcServer) createRPCClosedChannel(
 		return nil, err
 	}
 
+	// Fall back to the recorded close reason if the channel status
+	// doesn't tell us who initiated the close.
+	if closeInitiator == lnrpc.Initiator_INITIATOR_UNKNOWN {
+		reason, err := r.fetchCloseReason(dbChannel)
+		if err != nil {
+			return nil, err
+		}
+
+		switch reason.Initiator {
+		case channeldb.CloseInitiatorLocal:
+			closeInitiator = lnrpc.Initiator_INITIATOR_LOCAL
+
+		case channeldb.CloseInitiatorRemote:
+			closeInitiator = lnrpc.Initiator_INITIATOR_REMOTE
+		}
+	}
+
 	// Convert the close type to rpc type.
 	switch dbChannel.CloseType {
 	case channeldb.CooperativeClose:
@@ -4975,6 +6035,52 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 
 				continue
 
+			case channelnotifier.ChannelDrainingEvent:
+				draining := marshalChannelDraining(event)
+				channel := &lnrpc.ChannelEventUpdate_DrainingChannel{
+					DrainingChannel: draining,
+				}
+				update = &lnrpc.ChannelEventUpdate{
+					Type:    lnrpc.ChannelEventUpdate_DRAINING_CHANNEL,
+					Channel: channel,
+				}
+
+			case channelnotifier.ChannelDrainedEvent:
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_DRAINED_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_DrainedChannel{
+						DrainedChannel: &lnrpc.ChannelPoint{
+							FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+								FundingTxidBytes: event.ChannelPoint.Hash[:],
+							},
+							OutputIndex: event.ChannelPoint.Index,
+						},
+					},
+				}
+
+			case channelnotifier.ChannelForwardsResumedEvent:
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_FORWARDS_RESUMED_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_ForwardsResumedChannel{
+						ForwardsResumedChannel: &lnrpc.ChannelPoint{
+							FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+								FundingTxidBytes: event.ChannelPoint.Hash[:],
+							},
+							OutputIndex: event.ChannelPoint.Index,
+						},
+					},
+				}
+
+			case channelnotifier.ChannelFailureEvent:
+				failure := marshalChannelFailure(event)
+				channel := &lnrpc.ChannelEventUpdate_ChannelFailure{
+					ChannelFailure: failure,
+				}
+				update = &lnrpc.ChannelEventUpdate{
+					Type:    lnrpc.ChannelEventUpdate_CHANNEL_FAILURE,
+					Channel: channel,
+				}
+
 			case channelnotifier.FullyResolvedChannelEvent:
 				update = &lnrpc.ChannelEventUpdate{
 					Type: lnrpc.ChannelEventUpdate_FULLY_RESOLVED_CHANNEL,
@@ -5005,9 +6111,60 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 			}
 			return updateStream.Context().Err()
 
-		case <-r.quit:
-			return nil
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// marshalChannelDraining converts a channel draining event to its rpc
+// counterpart.
+func marshalChannelDraining(
+	event channelnotifier.ChannelDrainingEvent) *lnrpc.ChannelDraining {
+
+	draining := &lnrpc.ChannelDraining{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: event.ChannelPoint.Hash[:],
+			},
+			OutputIndex: event.ChannelPoint.Index,
+		},
+	}
+
+	switch event.Reason {
+	case channelnotifier.DrainReasonShutdown:
+		draining.Reason = lnrpc.DrainReason_DRAIN_REASON_SHUTDOWN
+
+	case channelnotifier.DrainReasonFlapping:
+		draining.Reason = lnrpc.DrainReason_DRAIN_REASON_FLAPPING
+	}
+
+	return draining
+}
+
+// marshalChannelFailure converts a channel failure event to its rpc
+// counterpart. Error codes unknown to the RPC are reported as unknown.
+func marshalChannelFailure(
+	event channelnotifier.ChannelFailureEvent) *lnrpc.ChannelFailure {
+
+	code := lnrpc.ChannelErrorCode_CHANNEL_ERROR_UNKNOWN
+	event.Code.WhenSome(func(c lnwire.ErrorCode) {
+		if _, ok := lnrpc.ChannelErrorCode_name[int32(c)]; ok {
+			code = lnrpc.ChannelErrorCode(c)
 		}
+	})
+
+	return &lnrpc.ChannelFailure{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: event.ChannelPoint.Hash[:],
+			},
+			OutputIndex: event.ChannelPoint.Index,
+		},
+		Remote:  event.Remote,
+		Warning: event.Warning,
+		Code:    code,
+		Message: event.Message,
 	}
 }
 
@@ -5025,6 +6182,10 @@ type paymentStream struct {
 type rpcPaymentRequest struct {
 	*lnrpc.SendRequest
 	route *route.Route
+
+	// account is the account the payment is attributed to, if the
+	// macaroon of the request is bound to one.
+	account string
 }
 
 // SendPayment dispatches a bi-directional streaming RPC for sending payments
@@ -5034,6 +6195,11 @@ type rpcPaymentRequest struct {
 func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error {
 	var lock sync.Mutex
 
+	account, err := macaroons.AccountFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
 	return r.sendPayment(&paymentStream{
 		recv: func() (*rpcPaymentRequest, error) {
 			req, err := stream.Recv()
@@ -5043,6 +6209,7 @@ func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error
 
 			return &rpcPaymentRequest{
 				SendRequest: req,
+				account:     account,
 			}, nil
 		},
 		send: func(r *lnrpc.SendResponse) error {
@@ -5062,6 +6229,11 @@ func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error
 func (r *rpcServer) SendToRoute(stream lnrpc.Lightning_SendToRouteServer) error {
 	var lock sync.Mutex
 
+	account, err := macaroons.AccountFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
 	return r.sendPayment(&paymentStream{
 		recv: func() (*rpcPaymentRequest, error) {
 			req, err := stream.Recv()
@@ -5069,7 +6241,13 @@ func (r *rpcServer) SendToRoute(stream lnrpc.Lightning_SendToRouteServer) error
 				return nil, err
 			}
 
-			return r.unmarshallSendToRouteRequest(req)
+			payReq, err := r.unmarshallSendToRouteRequest(req)
+			if err != nil {
+				return nil, err
+			}
+			payReq.account = account
+
+			return payReq, nil
 		},
 		send: func(r *lnrpc.SendResponse) error {
 			// Calling stream.Send concurrently is not safe.
@@ -5124,6 +6302,13 @@ type rpcPaymentIntent struct {
 
 	destCustomRecords record.CustomSet
 
+	// localSettle indicates that the payment is made to ourselves and
+	// should be settled without a circular route.
+	localSettle bool
+
+	// account is the account the payment is attributed to, if any.
+	account string
+
 	route *route.Route
 }
 
@@ -5132,7 +6317,9 @@ type rpcPaymentIntent struct {
 // three ways a client can specify their payment details: a payment request,
 // via manual details, or via a complete route.
 func (r *rpcServer) extractPaymentIntent(rpcPayReq *rpcPaymentRequest) (rpcPaymentIntent, error) {
-	payIntent := rpcPaymentIntent{}
+	payIntent := rpcPaymentIntent{
+		account: rpcPayReq.account,
+	}
 
 	// If a route was specified, then we can use that directly.
 	if rpcPayReq.route != nil {
@@ -5190,16 +6377,10 @@ func (r *rpcServer) extractPaymentIntent(rpcPayReq *rpcPaymentRequest) (rpcPayme
 	}
 	payIntent.destCustomRecords = customRecords
 
-	validateDest := func(dest route.Vertex) error {
-		if rpcPayReq.AllowSelfPayment {
-			return nil
-		}
-
-		if dest == r.selfNode {
-			return errors.New("self-payments not allowed")
-		}
-
-		return nil
+	// Payments to self are settled directly with our invoice registry,
+	// unless a circular route through the network was requested.
+	settleLocally := func(dest route.Vertex) bool {
+		return !rpcPayReq.AllowSelfPayment && dest == r.selfNode
 	}
 
 	// If the payment request field isn't blank, then the details of the
@@ -5256,9 +6437,7 @@ func (r *rpcServer) extractPaymentIntent(rpcPayReq *rpcPaymentRequest) (rpcPayme
 		payIntent.paymentAddr = payReq.PaymentAddr
 		payIntent.metadata = payReq.Metadata
 
-		if err := validateDest(payIntent.dest); err != nil {
-			return payIntent, err
-		}
+		payIntent.localSettle = settleLocally(payIntent.dest)
 
 		// Do bounds checking with the block padding.
 		err = routing.ValidateCLTVLimit(
@@ -5289,9 +6468,7 @@ func (r *rpcServer) extractPaymentIntent(rpcPayReq *rpcPaymentRequest) (rpcPayme
 	}
 	copy(payIntent.dest[:], pubBytes)
 
-	if err := validateDest(payIntent.dest); err != nil {
-		return payIntent, err
-	}
+	payIntent.localSettle = settleLocally(payIntent.dest)
 
 	// Payment address may not be needed by legacy invoices.
 	if len(rpcPayReq.PaymentAddr) != 0 && len(rpcPayReq.PaymentAddr) != 32 {
@@ -5383,6 +6560,17 @@ type paymentIntentResponse struct {
 func (r *rpcServer) dispatchPaymentIntent(
 	payIntent *rpcPaymentIntent) (*paymentIntentResponse, error) {
 
+	// Attribute the payment to its account before it is sent, so it
+	// shows up for the account even if lnd is shut down mid-payment.
+	if payIntent.account != "" {
+		err := r.server.accountStore.AttributePayment(
+			payIntent.rHash, payIntent.account,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Construct a payment request to send to the channel router. If the
 	// payment is successful, the route chosen will be returned. Otherwise,
 	// we'll get a non-nil error.
@@ -5410,6 +6598,7 @@ func (r *rpcServer) dispatchPaymentIntent(
 			DestFeatures:       payIntent.destFeatures,
 			PaymentAddr:        payIntent.paymentAddr,
 			Metadata:           payIntent.metadata,
+			LocalSettle:        payIntent.localSettle,
 
 			// Don't enable multi-part payments on the main rpc.
 			// Users need to use routerrpc for that.
@@ -5677,8 +6866,14 @@ sendLoop:
 func (r *rpcServer) SendPaymentSync(ctx context.Context,
 	nextPayment *lnrpc.SendRequest) (*lnrpc.SendResponse, error) {
 
+	account, err := macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return r.sendPaymentSync(&rpcPaymentRequest{
 		SendRequest: nextPayment,
+		account:     account,
 	})
 }
 
@@ -5698,6 +6893,11 @@ func (r *rpcServer) SendToRouteSync(ctx context.Context,
 		return nil, err
 	}
 
+	paymentRequest.account, err = macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return r.sendPaymentSync(paymentRequest)
 }
 
@@ -5746,12 +6946,8 @@ func (r *rpcServer) sendPaymentSync(
 	}, nil
 }
 
-// AddInvoice attempts to add a new invoice to the invoice database. Any
-// duplicated invoices are rejected, therefore all invoices *must* have a
-// unique payment preimage.
-func (r *rpcServer) AddInvoice(ctx context.Context,
-	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
-
+// addInvoiceConfig returns the config invoices are added with.
+func (r *rpcServer) addInvoiceConfig() *invoicesrpc.AddInvoiceConfig {
 	defaultDelta := r.cfg.Bitcoin.TimeLockDelta
 
 	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
@@ -5760,6 +6956,7 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		ChainParams:       r.cfg.ActiveNetParams.Params,
 		NodeSigner:        r.server.nodeSigner,
 		DefaultCLTVExpiry: defaultDelta,
+		MinCLTVExpiry:     r.cfg.minTimeLockDelta(),
 		ChanDB:            r.server.chanStateDB,
 		Graph:             r.server.graphDB,
 		GenInvoiceFeatures: func() *lnwire.FeatureVector {
@@ -5768,9 +6965,30 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
 			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
 		},
-		GetAlias: r.server.aliasMgr.GetPeerAlias,
+		GetAlias:         r.server.aliasMgr.GetPeerAlias,
+		AttributeCreator: r.server.attributeInvoiceCreator,
+	}
+
+	if r.server.preimageDeriver != nil {
+		addInvoiceCfg.GeneratePreimage = func() (lntypes.Preimage,
+			error) {
+
+			preimage, _, err := r.server.preimageDeriver.Next()
+			return preimage, err
+		}
 	}
 
+	return addInvoiceCfg
+}
+
+// AddInvoice attempts to add a new invoice to the invoice database. Any
+// duplicated invoices are rejected, therefore all invoices *must* have a
+// unique payment preimage.
+func (r *rpcServer) AddInvoice(ctx context.Context,
+	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+
+	addInvoiceCfg := r.addInvoiceConfig()
+
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
 	if err != nil {
 		return nil, err
@@ -5801,6 +7019,11 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		addInvoiceData.Preimage = &preimage
 	}
 
+	account, err := macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	hash, dbInvoice, err := invoicesrpc.AddInvoice(
 		ctx, addInvoiceCfg, addInvoiceData,
 	)
@@ -5808,6 +7031,13 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		return nil, err
 	}
 
+	if account != "" {
+		err := r.server.accountStore.AttributeInvoice(*hash, account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &lnrpc.AddInvoiceResponse{
 		AddIndex:       dbInvoice.AddIndex,
 		PaymentRequest: string(dbInvoice.PaymentRequest),
@@ -5846,6 +7076,18 @@ func (r *rpcServer) LookupInvoice(ctx context.Context,
 	}
 	copy(payHash[:], rHash)
 
+	// Invoices of other accounts are reported as not found, so a macaroon
+	// that is bound to an account can't learn about them.
+	visible, err := r.invoiceVisible(ctx, payHash)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, status.Error(
+			codes.NotFound, invoices.ErrInvoiceNotFound.Error(),
+		)
+	}
+
 	rpcsLog.Tracef("[lookupinvoice] searching for invoice %x", payHash[:])
 
 	invoice, err := r.server.invoices.LookupInvoice(ctx, payHash)
@@ -5909,20 +7151,35 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 	}
 
 	// Before returning the response, we'll need to convert each invoice
-	// into it's proto representation.
+	// into it's proto representation. Invoices of other accounts are
+	// skipped if the macaroon is bound to an account.
 	resp := &lnrpc.ListInvoiceResponse{
-		Invoices:         make([]*lnrpc.Invoice, len(invoiceSlice.Invoices)),
+		Invoices:         make([]*lnrpc.Invoice, 0, len(invoiceSlice.Invoices)),
 		FirstIndexOffset: invoiceSlice.FirstIndexOffset,
 		LastIndexOffset:  invoiceSlice.LastIndexOffset,
 	}
-	for i, invoice := range invoiceSlice.Invoices {
+	for _, invoice := range invoiceSlice.Invoices {
 		invoice := invoice
-		resp.Invoices[i], err = invoicesrpc.CreateRPCInvoice(
+		rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
 			&invoice, r.cfg.ActiveNetParams.Params,
 		)
 		if err != nil {
 			return nil, err
 		}
+
+		hash, err := lntypes.MakeHash(rpcInvoice.RHash)
+		if err != nil {
+			return nil, err
+		}
+		visible, err := r.invoiceVisible(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+
+		resp.Invoices = append(resp.Invoices, rpcInvoice)
 	}
 
 	return resp, nil
@@ -5944,29 +7201,19 @@ func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
 	for {
 		select {
 		case newInvoice := <-invoiceClient.NewInvoices:
-			rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
-				newInvoice, r.cfg.ActiveNetParams.Params,
-			)
+			err := r.sendVisibleInvoice(updateStream, newInvoice)
 			if err != nil {
 				return err
 			}
 
-			if err := updateStream.Send(rpcInvoice); err != nil {
-				return err
-			}
-
 		case settledInvoice := <-invoiceClient.SettledInvoices:
-			rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
-				settledInvoice, r.cfg.ActiveNetParams.Params,
+			err := r.sendVisibleInvoice(
+				updateStream, settledInvoice,
 			)
 			if err != nil {
 				return err
 			}
 
-			if err := updateStream.Send(rpcInvoice); err != nil {
-				return err
-			}
-
 		// The response stream's context for whatever reason has been
 		// closed. If context is closed by an exceeded deadline we will
 		// return an error.
@@ -5982,6 +7229,53 @@ func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
 	}
 }
 
+// invoiceVisible returns true if the invoice with the given payment hash can be
+// seen with the macaroon of the request. A macaroon that is bound to an account
+// only sees the invoices that were attributed to the account.
+func (r *rpcServer) invoiceVisible(ctx context.Context,
+	hash lntypes.Hash) (bool, error) {
+
+	account, err := macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if account == "" {
+		return true, nil
+	}
+
+	invoiceAccount, err := r.server.accountStore.InvoiceAccount(hash)
+	if err != nil {
+		return false, err
+	}
+
+	return invoiceAccount == account, nil
+}
+
+// sendVisibleInvoice sends the given invoice on the stream if it can be seen
+// with the macaroon of the stream.
+func (r *rpcServer) sendVisibleInvoice(
+	updateStream lnrpc.Lightning_SubscribeInvoicesServer,
+	invoice *invoices.Invoice) error {
+
+	rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
+		invoice, r.cfg.ActiveNetParams.Params,
+	)
+	if err != nil {
+		return err
+	}
+
+	hash, err := lntypes.MakeHash(rpcInvoice.RHash)
+	if err != nil {
+		return err
+	}
+	visible, err := r.invoiceVisible(updateStream.Context(), hash)
+	if err != nil || !visible {
+		return err
+	}
+
+	return updateStream.Send(rpcInvoice)
+}
+
 // SubscribeTransactions creates a uni-directional stream (server -> client) in
 // which any newly discovered transactions relevant to the wallet are sent
 // over.
@@ -6030,24 +7324,312 @@ func (r *rpcServer) SubscribeTransactions(req *lnrpc.GetTransactionsRequest,
 func (r *rpcServer) GetTransactions(ctx context.Context,
 	req *lnrpc.GetTransactionsRequest) (*lnrpc.TransactionDetails, error) {
 
-	// To remain backwards compatible with the old api, default to the
-	// special case end height which will return transactions from the start
-	// height until the chain tip, including unconfirmed transactions.
-	var endHeight = btcwallet.UnconfirmedHeight
+	// To remain backwards compatible with the old api, default to the
+	// special case end height which will return transactions from the start
+	// height until the chain tip, including unconfirmed transactions.
+	var endHeight = btcwallet.UnconfirmedHeight
+
+	// If the user has provided an end height, we overwrite our default.
+	if req.EndHeight != 0 {
+		endHeight = req.EndHeight
+	}
+
+	transactions, err := r.server.cc.Wallet.ListTransactionDetails(
+		req.StartHeight, endHeight, req.Account,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return lnrpc.RPCTransactionDetails(transactions), nil
+}
+
+// AddressReuseReport returns the output scripts that more than one wallet
+// transaction paid to, in the order they were first paid to. This includes
+// addresses of the wallet that received funds more than once as well as
+// foreign addresses the wallet paid to more than once. If an account is given,
+// only its transactions are considered.
+func (r *rpcServer) AddressReuseReport(_ context.Context,
+	req *lnrpc.AddressReuseReportRequest) (
+	*lnrpc.AddressReuseReportResponse, error) {
+
+	txns, err := r.server.cc.Wallet.ListTransactionDetails(
+		0, btcwallet.UnconfirmedHeight, req.Account,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The wallet returns the transactions ordered by block height,
+	// followed by the unconfirmed ones.
+	return lnrpc.RPCAddressReuse(lnwallet.FindAddressReuse(txns)), nil
+}
+
+// ExportLedger exports the on-chain and off-chain activity of the node as one
+// ledger in CSV or JSON: the transactions of the wallet, settled invoices,
+// succeeded payments and the fees earned by forwards. All entries share the
+// same value, fee and timestamp semantics, see LedgerEntry. Large ranges can
+// be exported in pages by passing the returned NextOffset as the offset of the
+// next request with the same time range.
+func (r *rpcServer) ExportLedger(ctx context.Context,
+	req *lnrpc.ExportLedgerRequest) (*lnrpc.ExportLedgerResponse, error) {
+
+	src := &ledgerSources{
+		transactions: func() ([]*lnwallet.TransactionDetail, error) {
+			return r.server.cc.Wallet.ListTransactionDetails(
+				0, btcwallet.UnconfirmedHeight, "",
+			)
+		},
+		invoices: r.server.invoicesDB.QueryInvoices,
+		payments: r.server.miscDB.QueryPayments,
+		forwards: r.server.miscDB.ForwardingLog().Query,
+	}
+
+	return exportLedger(ctx, src, req)
+}
+
+// CheckOpenChannel checks an OpenChannel request without opening the channel.
+// It reports everything that is known to make the channel opening fail: the
+// state of the node, invalid parameters, a disconnected peer, features the
+// peer doesn't support, insufficient wallet funds including the funding fee
+// and the anchor reserve, and pending channels or channel acceptor policies
+// the peer may reject the channel for.
+func (r *rpcServer) CheckOpenChannel(_ context.Context,
+	in *lnrpc.OpenChannelRequest) (*lnrpc.CheckOpenChannelResponse, error) {
+
+	report := newOpenChannelReport()
+
+	peerChecks := []string{
+		checkPeerConnected, checkChannelType, checkUpfrontShutdown,
+		checkChannelSize, checkWalletBalance, checkPendingChannels,
+		checkAcceptorPolicy,
+	}
+
+	err := r.canOpenChannel()
+	if err == nil {
+		err = r.server.safeMode.Err()
+	}
+	if err != nil {
+		report.fail(checkNodeReady, "%v", err)
+	} else {
+		report.pass(checkNodeReady, "node is ready to open channels")
+	}
+
+	req, err := r.parseOpenChannelReq(in, true)
+	if err != nil {
+		report.fail(checkParameters, "%v", err)
+		report.skip("invalid parameters", peerChecks...)
+
+		return report.finish(), nil
+	}
+	report.pass(checkParameters, "parameters are valid")
+
+	peer, err := r.server.FindPeer(req.TargetPubkey)
+	if err != nil {
+		report.fail(checkPeerConnected, "peer "+
+			"%x is not online, connect to it first",
+			req.TargetPubkey.SerializeCompressed())
+		report.skip("peer not connected", peerChecks[1:]...)
+
+		return report.finish(), nil
+	}
+	report.pass(checkPeerConnected, "peer is connected")
+
+	commitType := report.checkChannelFeatures(
+		req, peer.LocalFeatures(), peer.RemoteFeatures(),
+	)
+
+	// Channels funded through a shim are funded outside of the wallet.
+	if in.FundingShim != nil {
+		report.skip(
+			"channel is funded through a funding shim",
+			checkWalletBalance,
+		)
+	} else {
+		coins, err := r.server.cc.Wallet.
+			ListUnspentWitnessFromDefaultAccount(
+				req.MinConfs, math.MaxInt32,
+			)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only the selected outpoints are spent if there are any.
+		if len(req.Outpoints) > 0 {
+			selected := fn.NewSet(req.Outpoints...)
+			coins = fn.Filter(func(coin *lnwallet.Utxo) bool {
+				return selected.Contains(coin.OutPoint)
+			}, coins)
+		}
+
+		// The wallet must keep the reserve for all public anchor
+		// channels, including the new one.
+		var reserve btcutil.Amount
+		if !req.Private {
+			numAnchors, err := r.server.cc.Wallet.
+				CurrentNumAnchorChans()
+			if err != nil {
+				return nil, err
+			}
+			if commitType.HasAnchors() {
+				numAnchors++
+			}
+
+			reserve = r.server.cc.Wallet.RequiredReserve(
+				uint32(numAnchors),
+			)
+		}
+
+		report.checkWalletBalance(
+			req, coins, reserve, commitType.IsTaproot(),
+		)
+	}
+
+	pending, err := r.server.chanStateDB.FetchPendingChannels()
+	if err != nil {
+		return nil, err
+	}
+	pendingWithPeer := fn.Filter(func(c *channeldb.OpenChannel) bool {
+		return c.IsInitiator && c.IdentityPub.IsEqual(req.TargetPubkey)
+	}, pending)
+	report.checkPendingChannels(len(pendingWithPeer))
+
+	report.checkAcceptorPolicy(req)
+
+	return report.finish(), nil
+}
+
+// DiagnoseConnection attempts to connect to a node through the given address,
+// or through each address the node advertises, and reports the stage each
+// attempt failed in: resolving the address, the TCP connection, the acts of
+// the noise handshake or the exchange of init messages. The connections are
+// closed after the diagnosis. If we are already connected to the node, only
+// the TCP connection is attempted so the existing connection isn't replaced.
+func (r *rpcServer) DiagnoseConnection(_ context.Context,
+	req *lnrpc.DiagnoseConnectionRequest) (
+	*lnrpc.DiagnoseConnectionResponse, error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	pubKeyBytes, err := hex.DecodeString(req.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.IsEqual(r.server.identityECDH.PubKey()) {
+		return nil, fmt.Errorf("cannot diagnose connection to self")
+	}
+
+	addrs := []string{req.Address}
+	if req.Address == "" {
+		vertex := route.NewVertex(pubKey)
+		node, err := r.server.graphDB.FetchLightningNode(nil, vertex)
+		switch {
+		case errors.Is(err, channeldb.ErrGraphNodeNotFound):
+			return nil, fmt.Errorf("node not found in graph, an " +
+				"address must be given")
+
+		case err != nil:
+			return nil, err
+		}
+
+		addrs = fn.Map(func(addr net.Addr) string {
+			return addr.String()
+		}, node.Addresses)
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("node advertises no " +
+				"addresses, an address must be given")
+		}
+	}
+
+	timeout := r.cfg.ConnectionTimeout
+	if req.TimeoutSecs != 0 {
+		timeout = time.Duration(req.TimeoutSecs) * time.Second
+	}
+
+	_, err = r.server.FindPeer(pubKey)
+	connected := err == nil
+
+	legacyFeatures := r.server.featureMgr.Get(feature.SetLegacyGlobal)
+	initFeatures := r.server.featureMgr.Get(feature.SetInit)
+	initMsg := lnwire.NewInitMessage(
+		legacyFeatures.RawFeatureVector, initFeatures.RawFeatureVector,
+	)
+	peerNet := isolatedNet(r.cfg.net, torClassPeers)
+
+	resolve := func(addr string) (net.Addr, error) {
+		return parseAddr(addr, r.cfg.net)
+	}
+	diagnose := func(addr net.Addr) *lnrpc.AddressDiagnosis {
+		return diagnoseAddress(
+			r.server.identityECDH, &lnwire.NetAddress{
+				IdentityKey: pubKey,
+				Address:     addr,
+				ChainNet:    r.cfg.ActiveNetParams.Net,
+			}, initMsg, timeout, peerNet.Dial, !connected,
+		)
+	}
+
+	return &lnrpc.DiagnoseConnectionResponse{
+		PubKey:    req.PubKey,
+		Connected: connected,
+		Addresses: diagnoseAddresses(
+			addrs, resolve, diagnose, r.cfg.Tor.Active,
+		),
+	}, nil
+}
+
+// BumpCoopCloseFee bumps the fee of the pending RBF cooperative close
+// transaction of the given channel, which must have been closed by us. If the
+// fee rate is 0, the fee rate of the closing transaction is increased by the
+// configured percentage.
+func (r *rpcServer) BumpCoopCloseFee(_ context.Context,
+	in *lnrpc.BumpCoopCloseFeeRequest) (*lnrpc.BumpCoopCloseFeeResponse,
+	error) {
+
+	if in.GetChannelPoint() == nil {
+		return nil, status.Error(codes.InvalidArgument,
+			"must specify channel point")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	if in.SatPerVbyte > math.MaxUint64/1000 {
+		return nil, status.Error(codes.InvalidArgument,
+			"sat_per_vbyte too large")
+	}
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	switch {
+	case errors.Is(err, channeldb.ErrChannelNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
 
-	// If the user has provided an end height, we overwrite our default.
-	if req.EndHeight != 0 {
-		endHeight = req.EndHeight
+	case err != nil:
+		return nil, err
 	}
 
-	transactions, err := r.server.cc.Wallet.ListTransactionDetails(
-		req.StartHeight, endHeight, req.Account,
-	)
+	peer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	feeRate := chainfee.SatPerKVByte(in.SatPerVbyte * 1000).FeePerKWeight()
+
+	err = peer.BumpCoopCloseFee(*chanPoint, feeRate)
 	if err != nil {
 		return nil, err
 	}
 
-	return lnrpc.RPCTransactionDetails(transactions), nil
+	return &lnrpc.BumpCoopCloseFeeResponse{}, nil
 }
 
 // DescribeGraph returns a description of the latest graph state from the PoV
@@ -6255,90 +7837,619 @@ func (r *rpcServer) GetNodeMetrics(ctx context.Context,
 		return nil, nil
 	}
 
-	resp := &lnrpc.NodeMetricsResponse{
-		BetweennessCentrality: make(map[string]*lnrpc.FloatMetric),
+	resp := &lnrpc.NodeMetricsResponse{
+		BetweennessCentrality: make(map[string]*lnrpc.FloatMetric),
+	}
+
+	// Obtain the pointer to the global singleton channel graph, this will
+	// provide a consistent view of the graph due to bolt db's
+	// transactional model.
+	graph := r.server.graphDB
+
+	// Calculate betweenness centrality if requested. Note that depending on the
+	// graph size, this may take up to a few minutes.
+	channelGraph := autopilot.ChannelGraphFromDatabase(graph)
+	centralityMetric, err := autopilot.NewBetweennessCentralityMetric(
+		runtime.NumCPU(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := centralityMetric.Refresh(channelGraph); err != nil {
+		return nil, err
+	}
+
+	// Fill normalized and non normalized centrality.
+	centrality := centralityMetric.GetMetric(true)
+	for nodeID, val := range centrality {
+		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])] =
+			&lnrpc.FloatMetric{
+				NormalizedValue: val,
+			}
+	}
+
+	centrality = centralityMetric.GetMetric(false)
+	for nodeID, val := range centrality {
+		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])].Value = val
+	}
+
+	return resp, nil
+}
+
+// GetChanInfo returns the latest authenticated network announcement for the
+// given channel identified by either its channel ID or a channel outpoint. Both
+// uniquely identify the location of transaction's funding output within the
+// blockchain. The former is an 8-byte integer, while the latter is a string
+// formatted as funding_txid:output_index.
+func (r *rpcServer) GetChanInfo(_ context.Context,
+	in *lnrpc.ChanInfoRequest) (*lnrpc.ChannelEdge, error) {
+
+	graph := r.server.graphDB
+
+	var (
+		edgeInfo     *models.ChannelEdgeInfo
+		edge1, edge2 *models.ChannelEdgePolicy
+		err          error
+	)
+
+	switch {
+	case in.ChanId != 0:
+		edgeInfo, edge1, edge2, err = graph.FetchChannelEdgesByID(
+			in.ChanId,
+		)
+
+	case in.ChanPoint != "":
+		var chanPoint *wire.OutPoint
+		chanPoint, err = wire.NewOutPointFromString(in.ChanPoint)
+		if err != nil {
+			return nil, err
+		}
+		edgeInfo, edge1, edge2, err = graph.FetchChannelEdgesByOutpoint(
+			chanPoint,
+		)
+
+	default:
+		return nil, fmt.Errorf("specify either chan_id or chan_point")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the database's edge format into the network/RPC edge format
+	// which couples the edge itself along with the directional node
+	// routing policies of each node involved within the channel.
+	channelEdge := marshalDBEdge(edgeInfo, edge1, edge2)
+
+	return channelEdge, nil
+}
+
+// GetNodeMetadata returns the operator information records the node with the
+// given public key advertises in its node announcement. The raw records are
+// also part of the custom records returned by GetNodeInfo.
+func (r *rpcServer) GetNodeMetadata(_ context.Context,
+	in *lnrpc.NodeMetadataRequest) (*lnrpc.NodeMetadata, error) {
+
+	pubKey, err := route.NewVertexFromStr(in.PubKey)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	node, err := r.server.graphDB.FetchLightningNode(nil, pubKey)
+	switch {
+	case err == channeldb.ErrGraphNodeNotFound:
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	metadata, err := netann.ParseNodeMetadata(node.ExtraOpaqueData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.NodeMetadata{
+		Contact: metadata.Contact,
+		OrgName: metadata.OrgName,
+		Url:     metadata.URL,
+	}, nil
+}
+
+// GetNodeCapabilities returns the capabilities the node with the given public
+// key advertises, such as support for onion messages and route blinding. If
+// requested, the relay statistics we measured locally for the node are
+// included as well, which helps when selecting introduction nodes for reply
+// paths.
+func (r *rpcServer) GetNodeCapabilities(_ context.Context,
+	in *lnrpc.NodeCapabilitiesRequest) (*lnrpc.NodeCapabilities, error) {
+
+	pubKey, err := route.NewVertexFromStr(in.PubKey)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	node, err := r.server.graphDB.FetchLightningNode(nil, pubKey)
+	switch {
+	case err == channeldb.ErrGraphNodeNotFound:
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	capabilities := newNodeCapabilities(node.Features)
+	if in.IncludeRelayStats {
+		capabilities.RelayStats = newNodeRelayStats(
+			pubKey, r.server.missionControl.GetHistorySnapshot(),
+		)
+	}
+
+	return capabilities, nil
+}
+
+// LiquidityAlerts returns the liquidity alerts that are currently raised by
+// the liquidity monitor.
+func (r *rpcServer) LiquidityAlerts(_ context.Context,
+	_ *lnrpc.LiquidityAlertsRequest) (*lnrpc.LiquidityAlertsResponse,
+	error) {
+
+	if r.server.liquidityMonitor == nil {
+		return nil, status.Error(codes.FailedPrecondition,
+			"liquidity alerts not active, enable them with "+
+				"liquidityalert.active")
+	}
+
+	alerts := r.server.liquidityMonitor.Alerts()
+
+	resp := &lnrpc.LiquidityAlertsResponse{
+		Alerts: make([]*lnrpc.LiquidityAlert, len(alerts)),
+	}
+	for i, alert := range alerts {
+		resp.Alerts[i] = marshalLiquidityAlert(alert)
+	}
+
+	return resp, nil
+}
+
+// marshalLiquidityAlert converts a liquidity alert to its rpc counterpart.
+//
+//nolint:lll
+func marshalLiquidityAlert(alert *liquidityalert.Alert) *lnrpc.LiquidityAlert {
+	rpcAlert := &lnrpc.LiquidityAlert{
+		ChanPoint:    alert.ChanPoint,
+		Peer:         alert.Peer,
+		OutboundSat:  int64(alert.Outbound),
+		InboundSat:   int64(alert.Inbound),
+		ThresholdSat: int64(alert.Threshold),
+		Since:        alert.Since.Unix(),
+	}
+
+	switch alert.Kind {
+	case liquidityalert.KindLowOutbound:
+		rpcAlert.Kind = lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_LOW_OUTBOUND
+
+	case liquidityalert.KindLowInbound:
+		rpcAlert.Kind = lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_LOW_INBOUND
+
+	case liquidityalert.KindOneSided:
+		rpcAlert.Kind = lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_ONE_SIDED
+	}
+
+	switch alert.Scope {
+	case liquidityalert.ScopeChannel:
+		rpcAlert.Scope = lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_CHANNEL
+
+	case liquidityalert.ScopePeer:
+		rpcAlert.Scope = lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_PEER
+
+	case liquidityalert.ScopeNode:
+		rpcAlert.Scope = lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_NODE
+	}
+
+	return rpcAlert
+}
+
+// CltvGuardStatus returns the state of the CLTV guard, including the chain
+// conditions that currently engage it and the extra CLTV delta it requires
+// for forwards.
+func (r *rpcServer) CltvGuardStatus(_ context.Context,
+	_ *lnrpc.CltvGuardStatusRequest) (*lnrpc.CltvGuardStatusResponse,
+	error) {
+
+	if r.server.cltvGuard == nil {
+		return nil, status.Error(codes.FailedPrecondition,
+			"CLTV guard not active, enable it with "+
+				"cltvguard.active")
+	}
+
+	return marshalCltvGuardStatus(r.server.cltvGuard.Status()), nil
+}
+
+// marshalCltvGuardStatus converts the state of the CLTV guard to its rpc
+// counterpart.
+//
+//nolint:lll
+func marshalCltvGuardStatus(
+	guardStatus *cltvguard.Status) *lnrpc.CltvGuardStatusResponse {
+
+	resp := &lnrpc.CltvGuardStatusResponse{
+		Engaged:      guardStatus.Engaged,
+		ExtraDelta:   guardStatus.ExtraDelta,
+		BestHeight:   guardStatus.BestHeight,
+		TipAgeSec:    int64(guardStatus.TipAge / time.Second),
+		RecentBlocks: uint32(guardStatus.RecentBlocks),
+	}
+	if !guardStatus.Since.IsZero() {
+		resp.Since = guardStatus.Since.Unix()
+	}
+
+	for _, condition := range guardStatus.Conditions {
+		switch condition {
+		case cltvguard.ConditionBlockStorm:
+			resp.Conditions = append(
+				resp.Conditions,
+				lnrpc.CltvGuardCondition_CLTV_GUARD_CONDITION_BLOCK_STORM,
+			)
+
+		case cltvguard.ConditionStaleTip:
+			resp.Conditions = append(
+				resp.Conditions,
+				lnrpc.CltvGuardCondition_CLTV_GUARD_CONDITION_STALE_TIP,
+			)
+		}
+	}
+
+	return resp
+}
+
+// HoldFeeStatus returns the statistics of the hold fee policy, including its
+// most recent decisions. In dry-run mode, they show the effect the policy
+// would have on forwards if it was enforced.
+func (r *rpcServer) HoldFeeStatus(_ context.Context,
+	_ *lnrpc.HoldFeeStatusRequest) (*lnrpc.HoldFeeStatusResponse, error) {
+
+	if r.server.holdFeePolicy == nil {
+		return nil, status.Error(codes.FailedPrecondition, "hold fee "+
+			"policy not active, enable it with holdfee.active")
+	}
+
+	stats := r.server.holdFeePolicy.Stats()
+	resp := &lnrpc.HoldFeeStatusResponse{
+		DryRun:                 stats.DryRun,
+		Checked:                stats.Checked,
+		Surcharged:             stats.Surcharged,
+		InsufficientFee:        stats.InsufficientFee,
+		Refused:                stats.Refused,
+		CollectedSurchargeMsat: uint64(stats.CollectedSurcharge),
+		RecentDecisions: make(
+			[]*lnrpc.HoldFeeDecision, 0, len(stats.Recent),
+		),
+	}
+	for _, decision := range stats.Recent {
+		resp.RecentDecisions = append(
+			resp.RecentDecisions, marshalHoldFeeDecision(decision),
+		)
+	}
+
+	return resp, nil
+}
+
+// SimulateHoldFee evaluates the given hypothetical forwards against the
+// configured hold fee policy without recording the decisions. It works while
+// the policy isn't active, so its parameters can be tuned before enabling
+// it.
+func (r *rpcServer) SimulateHoldFee(_ context.Context,
+	req *lnrpc.SimulateHoldFeeRequest) (*lnrpc.SimulateHoldFeeResponse,
+	error) {
+
+	htlcs, err := unmarshalHoldFeeHtlcs(req.Htlcs)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	policy := r.server.holdFeePolicy
+	if policy == nil {
+		policy = newHoldFeePolicy(
+			r.cfg.HoldFee, clock.NewDefaultClock(),
+		)
+	}
+
+	resp := &lnrpc.SimulateHoldFeeResponse{
+		Decisions: make([]*lnrpc.HoldFeeDecision, 0, len(htlcs)),
+	}
+	for _, htlc := range htlcs {
+		resp.Decisions = append(
+			resp.Decisions,
+			marshalHoldFeeDecision(policy.Evaluate(htlc)),
+		)
+	}
+
+	return resp, nil
+}
+
+// unmarshalHoldFeeHtlcs converts the hypothetical forwards of a
+// SimulateHoldFee request, checking that their htlc counts fit the protocol
+// limits.
+func unmarshalHoldFeeHtlcs(rpcHtlcs []*lnrpc.HoldFeeHtlc) ([]holdfee.HTLC,
+	error) {
+
+	htlcs := make([]holdfee.HTLC, 0, len(rpcHtlcs))
+	for i, rpcHtlc := range rpcHtlcs {
+		if rpcHtlc.PendingHtlcs > math.MaxUint16 ||
+			rpcHtlc.MaxHtlcs > math.MaxUint16 {
+
+			return nil, fmt.Errorf("htlc %d: htlc counts must not "+
+				"exceed %d", i, math.MaxUint16)
+		}
+
+		htlcs = append(htlcs, holdfee.HTLC{
+			Amount:       lnwire.MilliSatoshi(rpcHtlc.AmtMsat),
+			Fee:          lnwire.MilliSatoshi(rpcHtlc.FeeMsat),
+			CltvDistance: rpcHtlc.CltvDistance,
+			PendingHTLCs: uint16(rpcHtlc.PendingHtlcs),
+			MaxHTLCs:     uint16(rpcHtlc.MaxHtlcs),
+		})
+	}
+
+	return htlcs, nil
+}
+
+// marshalHoldFeeDecision converts a decision of the hold fee policy to its RPC
+// representation.
+//
+//nolint:lll
+func marshalHoldFeeDecision(decision holdfee.Decision) *lnrpc.HoldFeeDecision {
+	rpcDecision := &lnrpc.HoldFeeDecision{
+		Timestamp:     decision.Time.Unix(),
+		AmtMsat:       uint64(decision.Amount),
+		CltvDistance:  decision.CltvDistance,
+		PendingHtlcs:  uint32(decision.PendingHTLCs),
+		RatePpm:       decision.Rate,
+		SurchargeMsat: uint64(decision.Surcharge),
+		Enforced:      decision.Enforced,
+	}
+
+	switch decision.Outcome {
+	case holdfee.OutcomeAccepted:
+		rpcDecision.Outcome = lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_ACCEPTED
+
+	case holdfee.OutcomeInsufficientFee:
+		rpcDecision.Outcome = lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_INSUFFICIENT_FEE
+
+	case holdfee.OutcomeRefused:
+		rpcDecision.Outcome = lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_REFUSED
+	}
+
+	return rpcDecision
+}
+
+// ListJammingIncidents returns the most recent suspected channel jamming
+// incidents, newest first, including whether forwards from their incoming
+// channels are currently refused.
+func (r *rpcServer) ListJammingIncidents(_ context.Context,
+	_ *lnrpc.ListJammingIncidentsRequest) (
+	*lnrpc.ListJammingIncidentsResponse, error) {
+
+	if r.server.jamDetector == nil {
+		return nil, status.Error(codes.FailedPrecondition, "jamming "+
+			"detection not active, enable it with jamdetect.active")
+	}
+
+	incidents := r.server.jamDetector.Incidents()
+	resp := &lnrpc.ListJammingIncidentsResponse{
+		Incidents: make([]*lnrpc.JammingIncident, 0, len(incidents)),
+	}
+	for _, incident := range incidents {
+		resp.Incidents = append(
+			resp.Incidents, marshalJammingIncident(incident),
+		)
+	}
+
+	return resp, nil
+}
+
+// marshalJammingIncident converts a jamming incident to its rpc counterpart.
+//
+//nolint:lll
+func marshalJammingIncident(incident jamdetect.Incident) *lnrpc.JammingIncident {
+	rpcIncident := &lnrpc.JammingIncident{
+		Id:             incident.ID,
+		IncomingChanId: incident.IncomingChannel.ToUint64(),
+		OutgoingChanIds: make(
+			[]uint64, 0, len(incident.OutgoingChannels),
+		),
+		NumHtlcs:   uint32(incident.HTLCs),
+		AmtMsat:    uint64(incident.Amount),
+		DetectedAt: incident.DetectedAt.Unix(),
+		LastSeen:   incident.LastSeen.Unix(),
+		Resolved:   incident.Resolved,
+	}
+	for _, outgoing := range incident.OutgoingChannels {
+		rpcIncident.OutgoingChanIds = append(
+			rpcIncident.OutgoingChanIds, outgoing.ToUint64(),
+		)
+	}
+	if !incident.MitigatedUntil.IsZero() {
+		rpcIncident.MitigatedUntil = incident.MitigatedUntil.Unix()
+	}
+
+	switch incident.Type {
+	case jamdetect.IncidentSlotJamming:
+		rpcIncident.Type = lnrpc.JammingIncidentType_JAMMING_INCIDENT_TYPE_SLOT_JAMMING
+
+	case jamdetect.IncidentLiquidityJamming:
+		rpcIncident.Type = lnrpc.JammingIncidentType_JAMMING_INCIDENT_TYPE_LIQUIDITY_JAMMING
+	}
+
+	return rpcIncident
+}
+
+// SafeModeStatus returns whether the node is in safe mode, and the failures of
+// its subsystems that put it into safe mode.
+func (r *rpcServer) SafeModeStatus(_ context.Context,
+	_ *lnrpc.SafeModeStatusRequest) (*lnrpc.SafeModeStatusResponse, error) {
+
+	return marshalSafeModeStatus(r.server.safeMode.Status()), nil
+}
+
+// marshalSafeModeStatus converts the safe mode status to its rpc counterpart.
+func marshalSafeModeStatus(
+	safeModeStatus *SafeModeStatus) *lnrpc.SafeModeStatusResponse {
+
+	resp := &lnrpc.SafeModeStatusResponse{
+		Active: safeModeStatus.Active,
+		Reasons: make(
+			[]*lnrpc.SafeModeReason, len(safeModeStatus.Reasons),
+		),
+	}
+	for i, reason := range safeModeStatus.Reasons {
+		resp.Reasons[i] = &lnrpc.SafeModeReason{
+			Subsystem: reason.Subsystem,
+			Reason:    reason.Reason,
+			Since:     reason.Since.Unix(),
+		}
+	}
+
+	return resp
+}
+
+// ExitSafeMode takes the node out of safe mode, so that it accepts new HTLCs
+// and channel operations again. Subsystems that still fail put the node back
+// into safe mode once their failure is detected again.
+func (r *rpcServer) ExitSafeMode(_ context.Context,
+	_ *lnrpc.ExitSafeModeRequest) (*lnrpc.ExitSafeModeResponse, error) {
+
+	err := r.server.safeMode.Exit()
+	switch {
+	case errors.Is(err, errNotInSafeMode):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+
+	case err != nil:
+		return nil, err
 	}
 
-	// Obtain the pointer to the global singleton channel graph, this will
-	// provide a consistent view of the graph due to bolt db's
-	// transactional model.
-	graph := r.server.graphDB
+	return &lnrpc.ExitSafeModeResponse{}, nil
+}
 
-	// Calculate betweenness centrality if requested. Note that depending on the
-	// graph size, this may take up to a few minutes.
-	channelGraph := autopilot.ChannelGraphFromDatabase(graph)
-	centralityMetric, err := autopilot.NewBetweennessCentralityMetric(
-		runtime.NumCPU(),
-	)
+// GetChainInfo returns how far the chain backend and the node have synced the
+// chain, including the block lag of the node behind the backend and the age of
+// its best block.
+func (r *rpcServer) GetChainInfo(_ context.Context,
+	_ *lnrpc.GetChainInfoRequest) (*lnrpc.GetChainInfoResponse, error) {
+
+	if r.server.cc.BackendInfo == nil {
+		return nil, fmt.Errorf("chain backend doesn't report " +
+			"its sync state")
+	}
+
+	backend, err := r.server.cc.BackendInfo()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to query chain backend: %w", err)
 	}
-	if err := centralityMetric.Refresh(channelGraph); err != nil {
-		return nil, err
+
+	bestHash, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get best block info: %w", err)
 	}
 
-	// Fill normalized and non normalized centrality.
-	centrality := centralityMetric.GetMetric(true)
-	for nodeID, val := range centrality {
-		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])] =
-			&lnrpc.FloatMetric{
-				NormalizedValue: val,
-			}
+	isSynced, bestHeaderTimestamp, err := r.server.cc.Wallet.IsSynced()
+	if err != nil {
+		return nil, fmt.Errorf("unable to sync PoV of the wallet "+
+			"with current best block in the main chain: %v", err)
 	}
 
-	centrality = centralityMetric.GetMetric(false)
-	for nodeID, val := range centrality {
-		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])].Value = val
+	return newChainInfo(
+		backend, bestHash.String(), uint32(bestHeight),
+		time.Unix(bestHeaderTimestamp, 0), time.Now(), isSynced,
+	), nil
+}
+
+// GetLeadershipState returns the leader election state of the node when it
+// runs as part of a cluster.
+func (r *rpcServer) GetLeadershipState(ctx context.Context,
+	_ *lnrpc.GetLeadershipStateRequest) (*lnrpc.GetLeadershipStateResponse,
+	error) {
+
+	state, err := r.server.LeadershipState(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return resp, nil
+	return &lnrpc.GetLeadershipStateResponse{
+		Id:           state.ID,
+		Leader:       state.Leader,
+		IsLeader:     state.IsLeader,
+		FencingToken: state.FencingToken,
+	}, nil
 }
 
-// GetChanInfo returns the latest authenticated network announcement for the
-// given channel identified by either its channel ID or a channel outpoint. Both
-// uniquely identify the location of transaction's funding output within the
-// blockchain. The former is an 8-byte integer, while the latter is a string
-// formatted as funding_txid:output_index.
-func (r *rpcServer) GetChanInfo(_ context.Context,
-	in *lnrpc.ChanInfoRequest) (*lnrpc.ChannelEdge, error) {
+// GetDBUsage returns the disk usage of the bolt database files of the node,
+// broken down by top level bucket.
+func (r *rpcServer) GetDBUsage(_ context.Context,
+	_ *lnrpc.GetDBUsageRequest) (*lnrpc.GetDBUsageResponse, error) {
 
-	graph := r.server.graphDB
+	usage, err := r.server.BoltDBUsage()
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
 
-	var (
-		edgeInfo     *models.ChannelEdgeInfo
-		edge1, edge2 *models.ChannelEdgePolicy
-		err          error
-	)
+	return marshalDBUsage(usage), nil
+}
 
-	switch {
-	case in.ChanId != 0:
-		edgeInfo, edge1, edge2, err = graph.FetchChannelEdgesByID(
-			in.ChanId,
-		)
+// marshalDBUsage converts the disk usage of the bolt database files to its rpc
+// counterpart, sorted by the name of the database.
+func marshalDBUsage(
+	usage map[string]*lncfg.BoltDBUsage) *lnrpc.GetDBUsageResponse {
+
+	resp := &lnrpc.GetDBUsageResponse{
+		Databases: make([]*lnrpc.DBUsage, 0, len(usage)),
+	}
+	for name, dbUsage := range usage {
+		rpcUsage := &lnrpc.DBUsage{
+			Name:                name,
+			FilePath:            dbUsage.FilePath,
+			FileSize:            dbUsage.FileSize,
+			CompactionScheduled: dbUsage.CompactionScheduled,
+			Buckets: make(
+				[]*lnrpc.BucketUsage, 0, len(dbUsage.Buckets),
+			),
+		}
+		if !dbUsage.LastCompacted.IsZero() {
+			rpcUsage.LastCompacted = dbUsage.LastCompacted.Unix()
+		}
 
-	case in.ChanPoint != "":
-		var chanPoint *wire.OutPoint
-		chanPoint, err = wire.NewOutPointFromString(in.ChanPoint)
-		if err != nil {
-			return nil, err
+		for _, bucket := range dbUsage.Buckets {
+			rpcUsage.Buckets = append(rpcUsage.Buckets,
+				&lnrpc.BucketUsage{
+					Name:       bucket.Name,
+					NumKeys:    bucket.NumKeys,
+					NumBuckets: bucket.NumBuckets,
+					KeyBytes:   bucket.KeyBytes,
+					ValueBytes: bucket.ValueBytes,
+				},
+			)
 		}
-		edgeInfo, edge1, edge2, err = graph.FetchChannelEdgesByOutpoint(
-			chanPoint,
-		)
 
-	default:
-		return nil, fmt.Errorf("specify either chan_id or chan_point")
+		resp.Databases = append(resp.Databases, rpcUsage)
 	}
+
+	sort.Slice(resp.Databases, func(i, j int) bool {
+		return resp.Databases[i].Name < resp.Databases[j].Name
+	})
+
+	return resp
+}
+
+// ScheduleDBCompaction requests that the given bolt database files, or all of
+// them if none are given, are compacted on the next restart of the node.
+func (r *rpcServer) ScheduleDBCompaction(_ context.Context,
+	req *lnrpc.ScheduleDBCompactionRequest) (
+	*lnrpc.ScheduleDBCompactionResponse, error) {
+
+	err := r.server.ScheduleBoltCompaction(req.Names...)
 	if err != nil {
-		return nil, err
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
 	}
 
-	// Convert the database's edge format into the network/RPC edge format
-	// which couples the edge itself along with the directional node
-	// routing policies of each node involved within the channel.
-	channelEdge := marshalDBEdge(edgeInfo, edge1, edge2)
-
-	return channelEdge, nil
+	return &lnrpc.ScheduleDBCompactionResponse{}, nil
 }
 
 // GetNodeInfo returns the latest advertised and aggregate authenticated
@@ -6824,9 +8935,27 @@ func (r *rpcServer) ListPayments(ctx context.Context,
 		TotalNumPayments: paymentsQuerySlice.TotalCount,
 	}
 
+	// Payments of other accounts are skipped if the macaroon is bound to
+	// an account.
+	account, err := macaroons.AccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, payment := range paymentsQuerySlice.Payments {
 		payment := payment
 
+		if account != "" {
+			paymentAccount, err := r.server.accountStore.
+				PaymentAccount(payment.Info.PaymentIdentifier)
+			if err != nil {
+				return nil, err
+			}
+			if paymentAccount != account {
+				continue
+			}
+		}
+
 		rpcPayment, err := r.routerBackend.MarshallPayment(payment)
 		if err != nil {
 			return nil, err
@@ -6932,6 +9061,24 @@ func (r *rpcServer) DebugLevel(ctx context.Context,
 	return &lnrpc.DebugLevelResponse{}, nil
 }
 
+// ReloadConfig reloads the config file and applies the changed options that
+// can be changed without a restart, just like on SIGHUP.
+func (r *rpcServer) ReloadConfig(_ context.Context,
+	_ *lnrpc.ReloadConfigRequest) (*lnrpc.ReloadConfigResponse, error) {
+
+	rpcsLog.Infof("[reloadconfig] reloading config")
+
+	report, err := r.server.ReloadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ReloadConfigResponse{
+		Applied:  report.Applied,
+		Rejected: report.Rejected,
+	}, nil
+}
+
 // DecodePayReq takes an encoded payment request string and attempts to decode
 // it, returning a full description of the conditions encoded within the
 // payment request.
@@ -7231,10 +9378,10 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 
 	// We'll also ensure that the user isn't setting a CLTV delta that
 	// won't give outgoing HTLCs enough time to fully resolve if needed.
-	if req.TimeLockDelta < minTimeLockDelta {
+	if req.TimeLockDelta < r.cfg.minTimeLockDelta() {
 		return nil, fmt.Errorf("time lock delta of %v is too small, "+
 			"minimum supported is %v", req.TimeLockDelta,
-			minTimeLockDelta)
+			r.cfg.minTimeLockDelta())
 	} else if req.TimeLockDelta > uint32(MaxTimeLockDelta) {
 		return nil, fmt.Errorf("time lock delta of %v is too big, "+
 			"maximum supported is %v", req.TimeLockDelta,
@@ -7777,6 +9924,14 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 				continue
 			case channelnotifier.InactiveLinkEvent:
 				continue
+			case channelnotifier.ChannelDrainingEvent:
+				continue
+			case channelnotifier.ChannelDrainedEvent:
+				continue
+			case channelnotifier.ChannelForwardsResumedEvent:
+				continue
+			case channelnotifier.ChannelFailureEvent:
+				continue
 			}
 
 			// Now that we know the channel state has changed,
@@ -8124,9 +10279,7 @@ func (r *rpcServer) FundingStateStep(ctx context.Context,
 			in.GetPsbtVerify().PendingChanId)
 
 		copy(pendingChanID[:], in.GetPsbtVerify().PendingChanId)
-		packet, err := psbt.NewFromRawBytes(
-			bytes.NewReader(in.GetPsbtVerify().FundedPsbt), false,
-		)
+		packet, _, err := psbtv2.Parse(in.GetPsbtVerify().FundedPsbt)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing psbt: %w", err)
 		}
@@ -8162,10 +10315,7 @@ func (r *rpcServer) FundingStateStep(ctx context.Context,
 				"and final raw TX at the same time")
 
 		case len(msg.SignedPsbt) > 0:
-			packet, err = psbt.NewFromRawBytes(
-				bytes.NewReader(in.GetPsbtFinalize().SignedPsbt),
-				false,
-			)
+			packet, _, err = psbtv2.Parse(msg.SignedPsbt)
 			if err != nil {
 				return nil, fmt.Errorf("error parsing psbt: %w",
 					err)
@@ -8199,6 +10349,164 @@ func (r *rpcServer) FundingStateStep(ctx context.Context,
 	return &lnrpc.FundingStateStepResp{}, nil
 }
 
+// parseFundingContribution parses the pending channel ID and the PSBT of a
+// funding contribution.
+func parseFundingContribution(pendingChanID, rawPsbt []byte) ([32]byte,
+	*psbt.Packet, error) {
+
+	pid, err := parseFundingPendingChanID(pendingChanID)
+	if err != nil {
+		return pid, nil, err
+	}
+
+	packet, _, err := psbtv2.Parse(rawPsbt)
+	if err != nil {
+		return pid, nil, status.Errorf(codes.InvalidArgument, "error "+
+			"parsing psbt: %v", err)
+	}
+
+	return pid, packet, nil
+}
+
+// parseFundingPendingChanID parses the pending channel ID of a funding
+// contribution.
+func parseFundingPendingChanID(pendingChanID []byte) ([32]byte, error) {
+	var pid [32]byte
+	if len(pendingChanID) != len(pid) {
+		return pid, status.Errorf(codes.InvalidArgument, "pending "+
+			"channel ID must be %d bytes", len(pid))
+	}
+	copy(pid[:], pendingChanID)
+
+	return pid, nil
+}
+
+// fundingContributionErr converts an error of the funding contributors into
+// a gRPC status error.
+func fundingContributionErr(err error) error {
+	switch {
+	case errors.Is(err, errNoFundingContributors):
+		return status.Error(codes.NotFound, err.Error())
+
+	case errors.Is(err, chanfunding.ErrUnknownContributor):
+		return status.Error(codes.InvalidArgument, err.Error())
+
+	case errors.Is(err, chanfunding.ErrContributionsExpired):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+
+	default:
+		return err
+	}
+}
+
+// RegisterFundingContributors registers the contributors whose inputs fund
+// the channel with the given pending channel ID, which must already have a
+// PSBT funding intent. The base PSBT must contain the channel funding output.
+// If not all contributors signed their inputs within the timeout, the funding
+// intent is canceled.
+func (r *rpcServer) RegisterFundingContributors(_ context.Context,
+	in *lnrpc.RegisterFundingContributorsRequest) (
+	*lnrpc.RegisterFundingContributorsResponse, error) {
+
+	pid, packet, err := parseFundingContribution(
+		in.PendingChanId, in.BasePsbt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.TimeoutSecs == 0 {
+		return nil, status.Error(codes.InvalidArgument, "timeout must "+
+			"be positive")
+	}
+	timeout := time.Duration(in.TimeoutSecs) * time.Second
+
+	rpcsLog.Debugf("Registering %d funding contributors for "+
+		"pending_id=%x", len(in.ContributorIds), pid[:])
+
+	err = r.server.fundingContributors.Register(
+		pid, packet, in.ContributorIds, timeout,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RegisterFundingContributorsResponse{}, nil
+}
+
+// ContributeFunding adds the inputs and optional change outputs of the given
+// PSBT to the funding transaction of the channel with the given pending
+// channel ID on behalf of the given contributor. Once all contributors added
+// their inputs, the funded PSBT is verified and can be fetched with
+// FundingContributionStatus for signing.
+func (r *rpcServer) ContributeFunding(_ context.Context,
+	in *lnrpc.ContributeFundingRequest) (*lnrpc.ContributeFundingResponse,
+	error) {
+
+	pid, packet, err := parseFundingContribution(in.PendingChanId, in.Psbt)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("Adding funding contribution of %v for pending_id=%x",
+		in.ContributorId, pid[:])
+
+	err = r.server.fundingContributors.Contribute(
+		pid, in.ContributorId, packet,
+	)
+	if err != nil {
+		return nil, fundingContributionErr(err)
+	}
+
+	return &lnrpc.ContributeFundingResponse{}, nil
+}
+
+// SignFundingContribution adds the signed inputs of the given contributor
+// from the given funded PSBT to the funding transaction of the channel with
+// the given pending channel ID. Once all contributors signed their inputs,
+// the funding of the channel is finalized.
+func (r *rpcServer) SignFundingContribution(_ context.Context,
+	in *lnrpc.SignFundingContributionRequest) (
+	*lnrpc.SignFundingContributionResponse, error) {
+
+	pid, packet, err := parseFundingContribution(
+		in.PendingChanId, in.SignedPsbt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("Adding signatures of %v for pending_id=%x",
+		in.ContributorId, pid[:])
+
+	err = r.server.fundingContributors.Sign(pid, in.ContributorId, packet)
+	if err != nil {
+		return nil, fundingContributionErr(err)
+	}
+
+	return &lnrpc.SignFundingContributionResponse{}, nil
+}
+
+// FundingContributionStatus returns the state of the contributors to the
+// funding transaction of the channel with the given pending channel ID, and
+// the funded PSBT once all of them added their inputs.
+func (r *rpcServer) FundingContributionStatus(_ context.Context,
+	in *lnrpc.FundingContributionStatusRequest) (
+	*lnrpc.FundingContributionStatusResponse, error) {
+
+	pid, err := parseFundingPendingChanID(in.PendingChanId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.server.fundingContributors.Status(pid)
+	if err != nil {
+		return nil, fundingContributionErr(err)
+	}
+
+	return resp, nil
+}
+
 // RegisterRPCMiddleware adds a new gRPC middleware to the interceptor chain. A
 // gRPC middleware is software component external to lnd that aims to add
 // additional business logic to lnd by observing/intercepting/validating
@@ -8392,6 +10700,100 @@ func (r *rpcServer) SubscribeCustomMessages(req *lnrpc.SubscribeCustomMessagesRe
 	}
 }
 
+// SendOnionMessage sends an onion message over a blinded path.
+func (r *rpcServer) SendOnionMessage(_ context.Context,
+	req *lnrpc.SendOnionMessageRequest) (*lnrpc.SendOnionMessageResponse,
+	error) {
+
+	path, err := onionmessage.DecodeBlindedPath(req.BlindedPath)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid "+
+			"blinded path: %v", err)
+	}
+
+	contents := make(map[tlv.Type][]byte, len(req.Contents))
+	for typ, value := range req.Contents {
+		if tlv.Type(typ) < onionmessage.MinContentType {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"content type %d is below %d", typ,
+				onionmessage.MinContentType)
+		}
+
+		contents[tlv.Type(typ)] = value
+	}
+
+	pathID, err := r.server.SendOnionMessage(
+		path, contents, req.IncludeReplyPath,
+	)
+	switch {
+	case errors.Is(err, ErrOnionMessagesDisabled):
+		return nil, status.Error(codes.Unimplemented, err.Error())
+
+	case errors.Is(err, onionmessage.ErrPeerOffline):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.SendOnionMessageResponse{
+		ReplyPathId: pathID,
+	}, nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of the onion messages that
+// are delivered to our node.
+func (r *rpcServer) SubscribeOnionMessages(
+	_ *lnrpc.SubscribeOnionMessagesRequest,
+	server lnrpc.Lightning_SubscribeOnionMessagesServer) error {
+
+	client, err := r.server.SubscribeOnionMessages()
+	if errors.Is(err, ErrOnionMessagesDisabled) {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			msg := update.(*onionmessage.Message)
+
+			var replyPath []byte
+			if msg.ReplyPath != nil {
+				replyPath, err = onionmessage.EncodeBlindedPath(
+					msg.ReplyPath,
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			contents := make(map[uint64][]byte, len(msg.Contents))
+			for typ, value := range msg.Contents {
+				contents[uint64(typ)] = value
+			}
+
+			err := server.Send(&lnrpc.OnionMessage{
+				PathId:    msg.PathID,
+				ReplyPath: replyPath,
+				Contents:  contents,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // ListAliases returns the set of all aliases we have ever allocated along with
 // their base SCID's and possibly a separate confirmed SCID in the case of
 // zero-conf.
@@ -1,9 +1,30 @@
 package lnd
 
 import (
+	"context"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/cltvguard"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/holdfee"
+	"github.com/lightningnetwork/lnd/jamdetect"
+	"github.com/lightningnetwork/lnd/liquidityalert"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGetAllPermissions(t *testing.T) {
@@ -12,3 +33,494 @@ func TestGetAllPermissions(t *testing.T) {
 	// Currently there are there are 16 entity:action pairs in use.
 	assert.Equal(t, len(perms), 16)
 }
+
+// TestRPCCloseReason tests that the recorded close reasons and initiators are
+// converted to their rpc counterparts.
+func TestRPCCloseReason(t *testing.T) {
+	reason := channeldb.CloseReasonUnknown
+	for ; reason <= channeldb.CloseReasonFundingCanceled; reason++ {
+		name := strings.ReplaceAll(reason.String(), " ", "_")
+		require.Equal(
+			t, "CLOSE_REASON_"+strings.ToUpper(name),
+			rpcCloseReason(reason).String(),
+		)
+	}
+
+	require.Equal(
+		t, lnrpc.Initiator_INITIATOR_UNKNOWN,
+		rpcCloseInitiator(channeldb.CloseInitiatorUnknown),
+	)
+	require.Equal(
+		t, lnrpc.Initiator_INITIATOR_LOCAL,
+		rpcCloseInitiator(channeldb.CloseInitiatorLocal),
+	)
+	require.Equal(
+		t, lnrpc.Initiator_INITIATOR_REMOTE,
+		rpcCloseInitiator(channeldb.CloseInitiatorRemote),
+	)
+}
+
+// TestBumpCoopCloseFeeValidation tests that invalid BumpCoopCloseFee requests
+// are rejected before the channel is looked up.
+func TestBumpCoopCloseFeeValidation(t *testing.T) {
+	newChanPoint := func(txid []byte) *lnrpc.ChannelPoint {
+		return &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: txid,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name string
+		req  *lnrpc.BumpCoopCloseFeeRequest
+	}{{
+		name: "no channel point",
+		req:  &lnrpc.BumpCoopCloseFeeRequest{},
+	}, {
+		name: "invalid funding txid",
+		req: &lnrpc.BumpCoopCloseFeeRequest{
+			ChannelPoint: newChanPoint([]byte{1}),
+		},
+	}, {
+		name: "fee rate overflow",
+		req: &lnrpc.BumpCoopCloseFeeRequest{
+			ChannelPoint: newChanPoint(make([]byte, 32)),
+			SatPerVbyte:  math.MaxUint64,
+		},
+	}}
+
+	r := &rpcServer{}
+	for _, tc := range testCases {
+		_, err := r.BumpCoopCloseFee(context.Background(), tc.req)
+		require.Equal(
+			t, codes.InvalidArgument, status.Code(err), tc.name,
+		)
+	}
+}
+
+// TestMarshalLiquidityAlert tests that liquidity alerts are converted to their
+// rpc counterparts.
+//
+//nolint:lll
+func TestMarshalLiquidityAlert(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+
+	rpcAlert := marshalLiquidityAlert(&liquidityalert.Alert{
+		Kind:      liquidityalert.KindLowInbound,
+		Scope:     liquidityalert.ScopePeer,
+		Peer:      "02aa",
+		Outbound:  900_000,
+		Inbound:   100_000,
+		Threshold: 200_000,
+		Since:     since,
+	})
+	require.Equal(t, (&lnrpc.LiquidityAlert{
+		Kind:         lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_LOW_INBOUND,
+		Scope:        lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_PEER,
+		Peer:         "02aa",
+		OutboundSat:  900_000,
+		InboundSat:   100_000,
+		ThresholdSat: 200_000,
+		Since:        since.Unix(),
+	}).String(), rpcAlert.String())
+
+	kinds := map[liquidityalert.Kind]lnrpc.LiquidityAlertKind{
+		liquidityalert.KindLowOutbound: lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_LOW_OUTBOUND,
+		liquidityalert.KindLowInbound:  lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_LOW_INBOUND,
+		liquidityalert.KindOneSided:    lnrpc.LiquidityAlertKind_LIQUIDITY_ALERT_KIND_ONE_SIDED,
+	}
+	for kind, rpcKind := range kinds {
+		alert := &liquidityalert.Alert{Kind: kind}
+		require.Equal(t, rpcKind, marshalLiquidityAlert(alert).Kind)
+	}
+
+	scopes := map[liquidityalert.Scope]lnrpc.LiquidityAlertScope{
+		liquidityalert.ScopeChannel: lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_CHANNEL,
+		liquidityalert.ScopePeer:    lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_PEER,
+		liquidityalert.ScopeNode:    lnrpc.LiquidityAlertScope_LIQUIDITY_ALERT_SCOPE_NODE,
+	}
+	for scope, rpcScope := range scopes {
+		alert := &liquidityalert.Alert{Scope: scope}
+		require.Equal(t, rpcScope, marshalLiquidityAlert(alert).Scope)
+	}
+}
+
+// TestMarshalCltvGuardStatus tests that the state of the CLTV guard is
+// converted to its rpc counterpart.
+//
+//nolint:lll
+func TestMarshalCltvGuardStatus(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+
+	resp := marshalCltvGuardStatus(&cltvguard.Status{
+		Engaged: true,
+		Conditions: []cltvguard.Condition{
+			cltvguard.ConditionBlockStorm,
+			cltvguard.ConditionStaleTip,
+		},
+		ExtraDelta:   24,
+		Since:        since,
+		BestHeight:   800_000,
+		TipAge:       2 * time.Hour,
+		RecentBlocks: 7,
+	})
+	require.Equal(t, (&lnrpc.CltvGuardStatusResponse{
+		Engaged: true,
+		Conditions: []lnrpc.CltvGuardCondition{
+			lnrpc.CltvGuardCondition_CLTV_GUARD_CONDITION_BLOCK_STORM,
+			lnrpc.CltvGuardCondition_CLTV_GUARD_CONDITION_STALE_TIP,
+		},
+		ExtraDelta:   24,
+		Since:        since.Unix(),
+		BestHeight:   800_000,
+		TipAgeSec:    7200,
+		RecentBlocks: 7,
+	}).String(), resp.String())
+
+	// A guard that isn't engaged has no engagement time.
+	resp = marshalCltvGuardStatus(&cltvguard.Status{BestHeight: 1})
+	require.False(t, resp.Engaged)
+	require.Zero(t, resp.Since)
+	require.Empty(t, resp.Conditions)
+}
+
+// TestHoldFeeRPCs tests that hypothetical forwards are evaluated against the
+// configured hold fee policy and that the statistics of the active policy are
+// returned.
+func TestHoldFeeRPCs(t *testing.T) {
+	cfg := &lncfg.HoldFee{
+		FreeBlocks:      100,
+		RatePerBlock:    10,
+		QueueMultiplier: 1,
+		MaxRate:         2000,
+	}
+	r := &rpcServer{
+		server: &server{},
+		cfg:    &Config{HoldFee: cfg},
+	}
+	ctx := context.Background()
+
+	// The simulation works while the policy isn't active.
+	resp, err := r.SimulateHoldFee(ctx, &lnrpc.SimulateHoldFeeRequest{
+		Htlcs: []*lnrpc.HoldFeeHtlc{{
+			AmtMsat:      1_000_000,
+			CltvDistance: 80,
+		}, {
+			AmtMsat:      1_000_000,
+			FeeMsat:      500,
+			CltvDistance: 200,
+		}, {
+			AmtMsat:      1_000_000,
+			FeeMsat:      2000,
+			CltvDistance: 200,
+			PendingHtlcs: 5,
+			MaxHtlcs:     5,
+		}, {
+			AmtMsat:      1_000_000,
+			FeeMsat:      1_000_000,
+			CltvDistance: 301,
+		}},
+	})
+	require.NoError(t, err)
+
+	outcomes := make([]lnrpc.HoldFeeOutcome, 0, len(resp.Decisions))
+	rates := make([]uint64, 0, len(resp.Decisions))
+	for _, decision := range resp.Decisions {
+		outcomes = append(outcomes, decision.Outcome)
+		rates = append(rates, decision.RatePpm)
+		require.True(t, decision.Enforced)
+	}
+	require.Equal(t, []lnrpc.HoldFeeOutcome{
+		lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_ACCEPTED,
+		lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_INSUFFICIENT_FEE,
+		lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_ACCEPTED,
+		lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_REFUSED,
+	}, outcomes)
+	require.Equal(t, []uint64{0, 1000, 2000, 2010}, rates)
+	require.EqualValues(t, 1000, resp.Decisions[1].SurchargeMsat)
+
+	// Htlc counts beyond the protocol limits are rejected.
+	_, err = r.SimulateHoldFee(ctx, &lnrpc.SimulateHoldFeeRequest{
+		Htlcs: []*lnrpc.HoldFeeHtlc{{
+			PendingHtlcs: math.MaxUint16 + 1,
+		}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The status requires the policy to be active.
+	_, err = r.HoldFeeStatus(ctx, &lnrpc.HoldFeeStatusRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	now := time.Unix(1700000000, 0)
+	cfg.DryRun = true
+	r.server.holdFeePolicy = newHoldFeePolicy(cfg, clock.NewTestClock(now))
+	r.server.holdFeePolicy.Check(holdfee.HTLC{
+		Amount:       1_000_000,
+		Fee:          500,
+		CltvDistance: 200,
+	})
+
+	feeStatus, err := r.HoldFeeStatus(ctx, &lnrpc.HoldFeeStatusRequest{})
+	require.NoError(t, err)
+	require.Equal(t, (&lnrpc.HoldFeeStatusResponse{
+		DryRun:          true,
+		Checked:         1,
+		Surcharged:      1,
+		InsufficientFee: 1,
+		RecentDecisions: []*lnrpc.HoldFeeDecision{{
+			Timestamp:     now.Unix(),
+			AmtMsat:       1_000_000,
+			CltvDistance:  200,
+			RatePpm:       1000,
+			SurchargeMsat: 1000,
+			Outcome:       lnrpc.HoldFeeOutcome_HOLD_FEE_OUTCOME_INSUFFICIENT_FEE, //nolint:lll
+		}},
+	}).String(), feeStatus.String())
+}
+
+// TestMarshalJammingIncident tests that a jamming incident is converted to its
+// rpc counterpart.
+//
+//nolint:lll
+func TestMarshalJammingIncident(t *testing.T) {
+	detectedAt := time.Unix(1700000000, 0)
+	incident := jamdetect.Incident{
+		ID:              3,
+		Type:            jamdetect.IncidentSlotJamming,
+		IncomingChannel: lnwire.NewShortChanIDFromInt(10),
+		OutgoingChannels: []lnwire.ShortChannelID{
+			lnwire.NewShortChanIDFromInt(11),
+			lnwire.NewShortChanIDFromInt(12),
+		},
+		HTLCs:          40,
+		Amount:         400_000,
+		DetectedAt:     detectedAt,
+		LastSeen:       detectedAt.Add(time.Minute),
+		MitigatedUntil: detectedAt.Add(time.Hour),
+	}
+	require.Equal(t, (&lnrpc.JammingIncident{
+		Id:              3,
+		Type:            lnrpc.JammingIncidentType_JAMMING_INCIDENT_TYPE_SLOT_JAMMING,
+		IncomingChanId:  10,
+		OutgoingChanIds: []uint64{11, 12},
+		NumHtlcs:        40,
+		AmtMsat:         400_000,
+		DetectedAt:      detectedAt.Unix(),
+		LastSeen:        detectedAt.Add(time.Minute).Unix(),
+		MitigatedUntil:  detectedAt.Add(time.Hour).Unix(),
+	}).String(), marshalJammingIncident(incident).String())
+
+	// A resolved incident without mitigation has no mitigation time.
+	incident.Type = jamdetect.IncidentLiquidityJamming
+	incident.Resolved = true
+	incident.MitigatedUntil = time.Time{}
+
+	rpcIncident := marshalJammingIncident(incident)
+	require.Equal(
+		t, lnrpc.JammingIncidentType_JAMMING_INCIDENT_TYPE_LIQUIDITY_JAMMING,
+		rpcIncident.Type,
+	)
+	require.True(t, rpcIncident.Resolved)
+	require.Zero(t, rpcIncident.MitigatedUntil)
+
+	// The RPC requires the detector to be active.
+	r := &rpcServer{server: &server{}}
+	_, err := r.ListJammingIncidents(
+		context.Background(), &lnrpc.ListJammingIncidentsRequest{},
+	)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+// TestMarshalChannelDraining tests that channel draining events are converted
+// to their rpc counterpart, including their reason.
+func TestMarshalChannelDraining(t *testing.T) {
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	event := channelnotifier.ChannelDrainingEvent{
+		ChannelPoint: &chanPoint,
+		Reason:       channelnotifier.DrainReasonFlapping,
+	}
+	require.Equal(t, (&lnrpc.ChannelDraining{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: chanPoint.Hash[:],
+			},
+			OutputIndex: 2,
+		},
+		Reason: lnrpc.DrainReason_DRAIN_REASON_FLAPPING,
+	}).String(), marshalChannelDraining(event).String())
+
+	event.Reason = channelnotifier.DrainReasonShutdown
+	require.Equal(
+		t, lnrpc.DrainReason_DRAIN_REASON_SHUTDOWN,
+		marshalChannelDraining(event).Reason,
+	)
+}
+
+// TestMarshalChannelFailure tests that channel failure events are converted
+// to their rpc counterpart, including their error code.
+func TestMarshalChannelFailure(t *testing.T) {
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	event := channelnotifier.ChannelFailureEvent{
+		ChannelPoint: &chanPoint,
+		Remote:       true,
+		Code:         fn.Some(lnwire.ErrorCodeInvalidCommitSig),
+		Message:      "invalid commit sig",
+	}
+	code := lnrpc.ChannelErrorCode_CHANNEL_ERROR_INVALID_COMMIT_SIG
+	require.Equal(t, (&lnrpc.ChannelFailure{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: chanPoint.Hash[:],
+			},
+			OutputIndex: 2,
+		},
+		Remote:  true,
+		Code:    code,
+		Message: "invalid commit sig",
+	}).String(), marshalChannelFailure(event).String())
+
+	// Failures without a code, or with a code unknown to the RPC, are
+	// reported as unknown.
+	event.Code = fn.None[lnwire.ErrorCode]()
+	require.Equal(
+		t, lnrpc.ChannelErrorCode_CHANNEL_ERROR_UNKNOWN,
+		marshalChannelFailure(event).Code,
+	)
+
+	event.Code = fn.Some(lnwire.ErrorCode(1000))
+	require.Equal(
+		t, lnrpc.ChannelErrorCode_CHANNEL_ERROR_UNKNOWN,
+		marshalChannelFailure(event).Code,
+	)
+}
+
+// TestMarshalDBUsage tests that the disk usage of the bolt database files is
+// converted to its rpc counterpart, sorted by the name of the database.
+func TestMarshalDBUsage(t *testing.T) {
+	compacted := time.Unix(1700000000, 0)
+	usage := map[string]*lncfg.BoltDBUsage{
+		"macaroons": {
+			FilePath: "macaroons.db",
+			FileSize: 100,
+		},
+		"channeldb": {
+			FilePath:            "channel.db",
+			FileSize:            1000,
+			LastCompacted:       compacted,
+			CompactionScheduled: true,
+			Buckets: []*lncfg.BucketUsage{{
+				Name:       "open-chan-bucket",
+				NumKeys:    3,
+				NumBuckets: 1,
+				KeyBytes:   30,
+				ValueBytes: 300,
+			}},
+		},
+	}
+
+	require.Equal(t, (&lnrpc.GetDBUsageResponse{
+		Databases: []*lnrpc.DBUsage{{
+			Name:                "channeldb",
+			FilePath:            "channel.db",
+			FileSize:            1000,
+			LastCompacted:       compacted.Unix(),
+			CompactionScheduled: true,
+			Buckets: []*lnrpc.BucketUsage{{
+				Name:       "open-chan-bucket",
+				NumKeys:    3,
+				NumBuckets: 1,
+				KeyBytes:   30,
+				ValueBytes: 300,
+			}},
+		}, {
+			Name:     "macaroons",
+			FilePath: "macaroons.db",
+			FileSize: 100,
+			Buckets:  []*lnrpc.BucketUsage{},
+		}},
+	}).String(), marshalDBUsage(usage).String())
+}
+
+// TestListDeprecations tests that the deprecations declared in the proto
+// definitions are listed and filtered by method prefix.
+func TestListDeprecations(t *testing.T) {
+	const prefix = "/lnrpc.Lightning/"
+
+	r := &rpcServer{}
+
+	resp, err := r.ListDeprecations(
+		context.Background(), &lnrpc.ListDeprecationsRequest{
+			MethodPrefix: prefix,
+		},
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Methods)
+
+	methods := make(map[string]*lnrpc.MethodDeprecation)
+	for _, method := range resp.Methods {
+		require.True(t, strings.HasPrefix(method.Method, prefix))
+		methods[method.Method] = method
+	}
+
+	sendPayment := methods["/lnrpc.Lightning/SendPayment"]
+	require.NotNil(t, sendPayment)
+	require.True(t, sendPayment.Deprecated)
+
+	getInfo := methods["/lnrpc.Lightning/GetInfo"]
+	require.NotNil(t, getInfo)
+	require.False(t, getInfo.Deprecated)
+	require.Contains(
+		t, getInfo.ResponseFields, "lnrpc.GetInfoResponse.testnet",
+	)
+
+	// Methods without deprecations aren't listed.
+	require.NotContains(t, methods, "/lnrpc.Lightning/ListDeprecations")
+
+	resp, err = r.ListDeprecations(
+		context.Background(), &lnrpc.ListDeprecationsRequest{
+			MethodPrefix: "/unknown.Service/",
+		},
+	)
+	require.NoError(t, err)
+	require.Empty(t, resp.Methods)
+}
+
+// TestUnmarshalChannelPage tests that page requests of the channel paging RPCs
+// are validated and converted.
+func TestUnmarshalChannelPage(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	peer := priv.PubKey()
+
+	page, err := unmarshalChannelPage(nil)
+	require.NoError(t, err)
+	require.Equal(t, &channelPageRequest{}, page)
+
+	page, err = unmarshalChannelPage(&lnrpc.ChannelPage{
+		Cursor:      []byte{1, 2},
+		MaxChannels: 10,
+		StartHeight: 100,
+		EndHeight:   200,
+		Peer:        peer.SerializeCompressed(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, page.Cursor)
+	require.EqualValues(t, 10, page.MaxChannels)
+	require.EqualValues(t, 100, page.StartHeight)
+	require.EqualValues(t, 200, page.EndHeight)
+	require.True(t, peer.IsEqual(page.Peer))
+
+	_, err = unmarshalChannelPage(&lnrpc.ChannelPage{
+		StartHeight: 200,
+		EndHeight:   100,
+	})
+	require.ErrorContains(t, err, "below start height")
+
+	_, err = unmarshalChannelPage(&lnrpc.ChannelPage{
+		Peer: []byte{1, 2, 3},
+	})
+	require.ErrorContains(t, err, "invalid peer")
+}
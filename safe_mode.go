@@ -0,0 +1,234 @@
+package lnd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/healthcheck"
+	"github.com/lightningnetwork/lnd/lncfg"
+)
+
+// errNotInSafeMode is returned when exiting safe mode while the node isn't in
+// safe mode.
+var errNotInSafeMode = errors.New("node is not in safe mode")
+
+// SafeModeReason describes a critical failure of a subsystem that put the node
+// into safe mode.
+type SafeModeReason struct {
+	// Subsystem is the subsystem that failed.
+	Subsystem string `json:"subsystem"`
+
+	// Reason describes the failure.
+	Reason string `json:"reason"`
+
+	// Since is the time the subsystem failed.
+	Since time.Time `json:"since"`
+}
+
+// SafeModeStatus describes whether the node is in safe mode and why.
+type SafeModeStatus struct {
+	// Active is true if the node is in safe mode.
+	Active bool `json:"active"`
+
+	// Reasons are the failures that put the node into safe mode, ordered
+	// by the time they occurred.
+	Reasons []SafeModeReason `json:"reasons"`
+}
+
+// safeMode keeps the node online after critical failures of its subsystems,
+// such as the loss of the chain backend or database errors, instead of
+// shutting it down. In safe mode, the node neither forwards nor initiates new
+// HTLCs, and doesn't open or cooperatively close channels, but it keeps
+// resolving existing state. The node exits safe mode once all failed
+// subsystems recovered, or on request of the user.
+type safeMode struct {
+	// enter stops the switch and the links from accepting new HTLCs.
+	enter func(reason string)
+
+	// exit allows the switch and the links to accept new HTLCs again.
+	exit func()
+
+	clock clock.Clock
+
+	// reasons are the failures that put the node into safe mode, by the
+	// failed subsystem.
+	reasons map[string]*SafeModeReason
+
+	mu sync.Mutex
+}
+
+// newSafeMode creates the safe mode of the node, which is entered and exited
+// with the given functions.
+func newSafeMode(enter func(reason string), exit func(),
+	clock clock.Clock) *safeMode {
+
+	return &safeMode{
+		enter:   enter,
+		exit:    exit,
+		clock:   clock,
+		reasons: make(map[string]*SafeModeReason),
+	}
+}
+
+// Enter puts the node into safe mode because the given subsystem failed. A
+// repeated failure of the same subsystem keeps the time of its first failure.
+func (m *safeMode) Enter(subsystem, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.reasons[subsystem]; ok {
+		return
+	}
+
+	m.reasons[subsystem] = &SafeModeReason{
+		Subsystem: subsystem,
+		Reason:    reason,
+		Since:     m.clock.Now(),
+	}
+
+	srvrLog.Warnf("Entering safe mode, %v failed: %v", subsystem, reason)
+	m.enter(fmt.Sprintf("%v failed: %v", subsystem, reason))
+}
+
+// Resolve records that the given subsystem recovered. The node exits safe mode
+// once no failed subsystems are left.
+func (m *safeMode) Resolve(subsystem string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.reasons[subsystem]; !ok {
+		return
+	}
+
+	delete(m.reasons, subsystem)
+	srvrLog.Infof("Subsystem %v recovered", subsystem)
+
+	if len(m.reasons) == 0 {
+		srvrLog.Infof("Exiting safe mode, all subsystems recovered")
+		m.exit()
+	}
+}
+
+// Exit takes the node out of safe mode, regardless of whether the failed
+// subsystems recovered. Subsystems that still fail put the node back into
+// safe mode once their failure is detected again.
+func (m *safeMode) Exit() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.reasons) == 0 {
+		return errNotInSafeMode
+	}
+
+	m.reasons = make(map[string]*SafeModeReason)
+	srvrLog.Infof("Exiting safe mode on request")
+	m.exit()
+
+	return nil
+}
+
+// Active returns true if the node is in safe mode.
+func (m *safeMode) Active() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.reasons) > 0
+}
+
+// Status returns whether the node is in safe mode and why.
+func (m *safeMode) Status() *SafeModeStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := &SafeModeStatus{
+		Active:  len(m.reasons) > 0,
+		Reasons: make([]SafeModeReason, 0, len(m.reasons)),
+	}
+	for _, reason := range m.reasons {
+		status.Reasons = append(status.Reasons, *reason)
+	}
+	sort.Slice(status.Reasons, func(i, j int) bool {
+		return status.Reasons[i].Since.Before(status.Reasons[j].Since)
+	})
+
+	return status
+}
+
+// Err returns an error describing why the node is in safe mode, or nil if it
+// isn't.
+func (m *safeMode) Err() error {
+	status := m.Status()
+	if !status.Active {
+		return nil
+	}
+
+	failed := make([]string, 0, len(status.Reasons))
+	for _, reason := range status.Reasons {
+		failed = append(failed, reason.Subsystem)
+	}
+
+	return fmt.Errorf("node is in safe mode, failed subsystems: %v",
+		strings.Join(failed, ", "))
+}
+
+// safeModeAcceptor rejects all channel open requests while the node is in safe
+// mode, and defers to the wrapped acceptor otherwise.
+type safeModeAcceptor struct {
+	safeMode *safeMode
+	acceptor chanacceptor.ChannelAcceptor
+}
+
+// Accept rejects the request in safe mode.
+//
+// NOTE: Part of the chanacceptor.ChannelAcceptor interface.
+func (a *safeModeAcceptor) Accept(req *chanacceptor.ChannelAcceptRequest) (
+	resp *chanacceptor.ChannelAcceptResponse) {
+
+	if err := a.safeMode.Err(); err != nil {
+		return chanacceptor.NewChannelAcceptResponse(
+			false, err, nil, 0, 0, 0, 0, 0, 0, false,
+		)
+	}
+
+	return a.acceptor.Accept(req)
+}
+
+// A compile-time constraint to ensure safeModeAcceptor implements the
+// chanacceptor.ChannelAcceptor interface.
+var _ chanacceptor.ChannelAcceptor = (*safeModeAcceptor)(nil)
+
+// newSafeModeObservation creates a health check of the given subsystem that
+// puts the node into safe mode once it fails, rather than shutting it down.
+// As an observation stops once it failed, it adds a new one to the safe mode
+// monitor to detect when the subsystem recovers.
+func (s *server) newSafeModeObservation(subsystem string, check func() error,
+	cfg *lncfg.CheckConfig) *healthcheck.Observation {
+
+	onSuccess := func() {
+		s.safeMode.Resolve(subsystem)
+	}
+	onFailure := func() {
+		s.safeMode.Enter(subsystem, fmt.Sprintf("health check failed "+
+			"after %v calls", cfg.Attempts))
+
+		err := s.safeModeMonitor.AddCheck(
+			s.newSafeModeObservation(subsystem, check, cfg),
+		)
+		if err != nil {
+			srvrLog.Errorf("Unable to add %v health check: %v",
+				subsystem, err)
+		}
+	}
+
+	return healthcheck.NewObservation(
+		subsystem, check, cfg.Interval, cfg.Timeout, cfg.Backoff,
+		cfg.Attempts, healthcheck.WithSuccessCallback(onSuccess),
+		healthcheck.WithFailureCallback(onFailure),
+	)
+}
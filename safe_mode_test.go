@@ -0,0 +1,130 @@
+package lnd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// acceptAll is a channel acceptor that accepts all requests.
+type acceptAll struct{}
+
+// Accept accepts the request.
+func (acceptAll) Accept(_ *chanacceptor.ChannelAcceptRequest) (
+	resp *chanacceptor.ChannelAcceptResponse) {
+
+	return &chanacceptor.ChannelAcceptResponse{}
+}
+
+// TestSafeMode tests that the node enters safe mode once a subsystem fails,
+// and exits it once all failed subsystems recovered or on request.
+func TestSafeMode(t *testing.T) {
+	t.Parallel()
+
+	var active bool
+	testClock := clock.NewTestClock(time.Unix(1_000_000, 0))
+	safeMode := newSafeMode(
+		func(string) { active = true }, func() { active = false },
+		testClock,
+	)
+	acceptor := &safeModeAcceptor{
+		safeMode: safeMode,
+		acceptor: acceptAll{},
+	}
+
+	require.False(t, safeMode.Active())
+	require.NoError(t, safeMode.Err())
+	require.ErrorIs(t, safeMode.Exit(), errNotInSafeMode)
+	require.False(t, acceptor.Accept(nil).RejectChannel())
+
+	// A failed subsystem puts the node into safe mode.
+	safeMode.Enter("chain backend", "unreachable")
+	require.True(t, active)
+	require.True(t, safeMode.Active())
+	require.ErrorContains(t, safeMode.Err(), "chain backend")
+
+	resp := acceptor.Accept(nil)
+	require.True(t, resp.RejectChannel())
+	require.ErrorContains(t, resp, "safe mode")
+
+	// A repeated failure keeps the time of the first failure.
+	firstFailure := testClock.Now()
+	testClock.SetTime(firstFailure.Add(time.Minute))
+	safeMode.Enter("chain backend", "still unreachable")
+	safeMode.Enter("remote database", "too slow")
+
+	status := safeMode.Status()
+	require.True(t, status.Active)
+	require.Equal(t, []SafeModeReason{{
+		Subsystem: "chain backend",
+		Reason:    "unreachable",
+		Since:     firstFailure,
+	}, {
+		Subsystem: "remote database",
+		Reason:    "too slow",
+		Since:     firstFailure.Add(time.Minute),
+	}}, status.Reasons)
+
+	// The node stays in safe mode until all subsystems recovered.
+	safeMode.Resolve("chain backend")
+	require.True(t, active)
+	require.Len(t, safeMode.Status().Reasons, 1)
+
+	safeMode.Resolve("remote database")
+	require.False(t, active)
+	require.False(t, safeMode.Status().Active)
+	require.Empty(t, safeMode.Status().Reasons)
+
+	// The node exits safe mode on request even if subsystems still fail.
+	safeMode.Enter("chain backend", "unreachable")
+	require.True(t, active)
+	require.NoError(t, safeMode.Exit())
+	require.False(t, active)
+	require.NoError(t, safeMode.Err())
+}
+
+// TestSafeModeRPCs tests that the safe mode status is reported and safe mode
+// is exited through the safe mode RPCs.
+func TestSafeModeRPCs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	testClock := clock.NewTestClock(time.Unix(1_000_000, 0))
+	r := &rpcServer{
+		server: &server{
+			safeMode: newSafeMode(
+				func(string) {}, func() {}, testClock,
+			),
+		},
+	}
+
+	resp, err := r.SafeModeStatus(ctx, &lnrpc.SafeModeStatusRequest{})
+	require.NoError(t, err)
+	require.False(t, resp.Active)
+	require.Empty(t, resp.Reasons)
+
+	_, err = r.ExitSafeMode(ctx, &lnrpc.ExitSafeModeRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	r.server.safeMode.Enter("chain backend", "unreachable")
+
+	resp, err = r.SafeModeStatus(ctx, &lnrpc.SafeModeStatusRequest{})
+	require.NoError(t, err)
+	require.True(t, resp.Active)
+	require.Equal(t, (&lnrpc.SafeModeReason{
+		Subsystem: "chain backend",
+		Reason:    "unreachable",
+		Since:     testClock.Now().Unix(),
+	}).String(), resp.Reasons[0].String())
+
+	_, err = r.ExitSafeMode(ctx, &lnrpc.ExitSafeModeRequest{})
+	require.NoError(t, err)
+	require.False(t, r.server.safeMode.Active())
+}
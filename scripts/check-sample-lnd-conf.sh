@@ -48,7 +48,7 @@ rm $FILE_TMP
 
 # OPTIONS_NO_CONF is a list of all options without any expected entries in 
 # sample-lnd.conf. There's no validation needed for these options. 
-OPTIONS_NO_CONF="help lnddir configfile version end"
+OPTIONS_NO_CONF="help lnddir configfile version check-config end"
 
 
 # OPTIONS_NO_LND_DEFAULT_VALUE_CHECK is a list of options with default values
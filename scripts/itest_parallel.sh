@@ -6,32 +6,21 @@ TRANCHES=$2
 TEST_FLAGS=$3
 ITEST_FLAGS=$4
 
-# Create a variable to hold the final exit code.
-exit_code=0
-
-# Run commands using xargs in parallel and capture their PIDs
-pids=()
-for ((i=0; i<PROCESSES; i++)); do 
-    scripts/itest_part.sh $i $TRANCHES $TEST_FLAGS $ITEST_FLAGS &
-    pids+=($!)
-done
-
-
-# Wait for the processes created by xargs to finish.
-for pid in "${pids[@]}"; do
-    wait $pid
-
-    # Once finished, grab its exit code.
-    current_exit_code=$?
-
-    # Overwrite the exit code if current itest doesn't return 0.
-    if [ $current_exit_code -ne 0 ]; then
-        # Only write the exit code of the first failing itest.
-        if [ $exit_code -eq 0 ]; then
-            exit_code=$current_exit_code
-        fi
-    fi
-done
-
-# Exit with the exit code of the first failing itest or 0.
-exit $exit_code
+# Every tranche runs in its own process with its own miner and port range. If
+# there are more tranches than processes, the remaining tranches are started
+# once a previous one finishes. If there are more processes than tranches,
+# the extra processes run the tranches again, starting from the first one,
+# which is used for flake hunting.
+JOBS=$TRANCHES
+if [ "$PROCESSES" -gt "$JOBS" ]; then
+    JOBS=$PROCESSES
+fi
+
+# Run the tranches using xargs with at most $PROCESSES at the same time. Since
+# itest_part.sh exits with code 255 on failure, xargs stops starting new
+# tranches once one of them fails.
+seq 0 $((JOBS - 1)) | xargs -P "$PROCESSES" -I{} \
+    scripts/itest_part.sh {} $TRANCHES $TEST_FLAGS $ITEST_FLAGS
+
+# Exit with a non-zero exit code if any of the tranches failed.
+exit $?
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
 	prand "math/rand"
 	"net"
@@ -17,14 +18,19 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/connmgr"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/go-errors/errors"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/accounts"
 	"github.com/lightningnetwork/lnd/aliasmgr"
+	"github.com/lightningnetwork/lnd/anchorreserve"
+	"github.com/lightningnetwork/lnd/asyncpay"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/brontide"
 	"github.com/lightningnetwork/lnd/chainreg"
@@ -35,41 +41,59 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/cltvguard"
+	"github.com/lightningnetwork/lnd/cluster"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/fault"
 	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/funding"
 	"github.com/lightningnetwork/lnd/healthcheck"
+	"github.com/lightningnetwork/lnd/holdfee"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/jamdetect"
+	"github.com/lightningnetwork/lnd/jitchannel"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/liquidityalert"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnurl"
+	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lsps"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
 	"github.com/lightningnetwork/lnd/nat"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/onionmessage"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/localchans"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/trampoline"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
@@ -103,6 +127,11 @@ const (
 	// multiAddrConnectionStagger is the number of seconds to wait between
 	// attempting to a peer with each of its advertised addresses.
 	multiAddrConnectionStagger = 10 * time.Second
+
+	// trampolinePaymentTimeout is the time we try to reach the next
+	// trampoline node when relaying a trampoline payment, before the
+	// incoming htlc is failed.
+	trampolinePaymentTimeout = time.Minute
 )
 
 var (
@@ -119,6 +148,11 @@ var (
 	// gracefully exiting.
 	ErrServerShuttingDown = errors.New("server is shutting down")
 
+	// ErrOnionMessagesDisabled is returned when onion messages are sent or
+	// subscribed to while protocol.onion-messages is not set.
+	ErrOnionMessagesDisabled = errors.New("onion messages are not " +
+		"enabled")
+
 	// MaxFundingAmount is a soft-limit of the maximum channel size
 	// currently accepted within the Lightning Protocol. This is
 	// defined in BOLT-0002, and serves as an initial precautionary limit
@@ -157,6 +191,14 @@ type server struct {
 
 	cfg *Config
 
+	// clock is the time source shared by all time based subsystems of the
+	// node.
+	clock clock.Clock
+
+	// faultInjector is used to inject faults into some subsystems in dev
+	// builds. It is nil otherwise.
+	faultInjector *fault.Injector
+
 	// identityECDH is an ECDH capable wrapper for the private key used
 	// to authenticate any incoming connections.
 	identityECDH keychain.SingleKeyECDH
@@ -170,6 +212,9 @@ type server struct {
 
 	chanStatusMgr *netann.ChanStatusManager
 
+	// chanFreezer puts channels into maintenance mode.
+	chanFreezer *channelFreezer
+
 	// listenAddrs is the list of addresses the server is currently
 	// listening on.
 	listenAddrs []net.Addr
@@ -215,6 +260,11 @@ type server struct {
 	// as a key for this map.
 	peerErrors map[string]*queue.CircularBuffer
 
+	// peerDisconnects tracks the recent disconnect times of peers, which
+	// are used to detect flapping peers. The string of the peer's
+	// compressed pubkey is used as a key for this map.
+	peerDisconnects map[string][]time.Time
+
 	// ignorePeerTermination tracks peers for which the server has initiated
 	// a disconnect. Adding a peer to this map causes the peer termination
 	// watcher to short circuit in the event that peers are purposefully
@@ -248,10 +298,76 @@ type server struct {
 	// channel DB that haven't been separated out yet.
 	miscDB *channeldb.DB
 
+	// boltDBs holds the bolt database files keyed by their namespace name.
+	// This is empty if a different database backend is used.
+	boltDBs map[string]*lncfg.BoltDB
+
+	// leaderElector is the leader elector of the cluster this node is part
+	// of. It is nil if leader election is disabled.
+	leaderElector cluster.LeaderElector
+
+	// configReloader reloads the config and applies the options that can
+	// be changed without a restart.
+	configReloader *configReloader
+
 	invoicesDB invoices.InvoiceDB
 
 	aliasMgr *aliasmgr.Manager
 
+	// accountStore attributes invoices and payments to the wallet
+	// accounts whose macaroons created them.
+	accountStore *accounts.Store
+
+	// preimageDeriver derives the preimages of new invoices from the
+	// seed. It is nil unless deterministic preimages are enabled.
+	preimageDeriver *invoices.PreimageDeriver
+
+	// holdInvoiceRules settles or cancels accepted hold invoices
+	// automatically. It is nil unless the hold invoice rules are active.
+	holdInvoiceRules *invoices.HoldInvoiceRules
+
+	// zapPublisher publishes the Nostr zap receipts of settled LNURL zap
+	// invoices. It is nil unless zaps are enabled.
+	zapPublisher *lnurl.ZapPublisher
+
+	// anchorReserve checks whether the wallet can cover the fee bumps of
+	// the anchor channels. It is nil unless the monitor is active.
+	anchorReserve *anchorreserve.Monitor
+
+	// liquidityMonitor alerts when the liquidity of the channels drops
+	// below the configured thresholds. It is nil unless the monitor is
+	// active.
+	liquidityMonitor *liquidityalert.Monitor
+
+	// cltvGuard raises the CLTV deltas required for forwards while the
+	// chain is irregular. It is nil unless the guard is active.
+	cltvGuard *cltvguard.Guard
+
+	// holdFeePolicy requires a surcharge for forwards that may lock up
+	// liquidity for long. It is nil unless the policy is active.
+	holdFeePolicy *holdfee.Policy
+
+	// jamDetector detects channel jamming patterns in the forwarded htlcs.
+	// It is nil unless jamming detection is active.
+	jamDetector *jamdetect.Detector
+
+	// lspClient talks to LSPs over custom peer messages, and accepts the
+	// just-in-time channels bought from them.
+	lspClient *lsps.Client
+
+	// jitChannels opens channels to registered clients just in time for
+	// the htlcs forwarded to them. It is nil unless just-in-time channels
+	// are active.
+	jitChannels *jitchannel.Manager
+
+	// featurePolicy enforces the feature bits peers must or must not set
+	// in their init message, and remembers the peers it rejected.
+	featurePolicy *feature.Policy
+
+	// initRecords are the custom TLV records applications registered to
+	// be included in the init message we send to peers.
+	initRecords *peer.InitRecords
+
 	htlcSwitch *htlcswitch.Switch
 
 	interceptableSwitch *htlcswitch.InterceptableSwitch
@@ -270,6 +386,8 @@ type server struct {
 
 	missionControl *routing.MissionControl
 
+	knownRouteHints *routing.KnownRouteHints
+
 	chanRouter *routing.ChannelRouter
 
 	controlTower routing.ControlTower
@@ -325,8 +443,37 @@ type server struct {
 	// livenessMonitor monitors that lnd has access to critical resources.
 	livenessMonitor *healthcheck.Monitor
 
+	// safeModeMonitor runs the health checks that put the node into safe
+	// mode once they fail. It is nil unless any such check is enabled.
+	safeModeMonitor *healthcheck.Monitor
+
+	// safeMode keeps the node online after critical failures of its
+	// subsystems, without accepting new HTLCs and channel operations.
+	safeMode *safeMode
+
+	// fundingContributors assembles channel funding transactions from
+	// the inputs of several contributors.
+	fundingContributors *fundingContributors
+
 	customMessageServer *subscribe.Server
 
+	// onionMessenger forwards and receives onion messages. It is nil if
+	// onion messages are disabled.
+	onionMessenger *onionmessage.Messenger
+
+	// onionMessageServer notifies the subscribers of the onion messages
+	// that are delivered to us. It is nil if onion messages are disabled.
+	onionMessageServer *subscribe.Server
+
+	// asyncPayments takes part in the async payments protocol. It is nil
+	// unless async payments are enabled.
+	asyncPayments *asyncpay.Manager
+
+	// trampolineRelay relays trampoline payments and passes all other
+	// exit hop htlcs on to the invoice registry. It is nil unless
+	// trampoline routing is enabled.
+	trampolineRelay *htlcswitch.TrampolineRelay
+
 	// txPublisher is a publisher with fee-bumping capability.
 	txPublisher *sweep.TxPublisher
 
@@ -462,6 +609,32 @@ func parseAddr(address string, netCfg tor.Net) (net.Addr, error) {
 	return netCfg.ResolveTCPAddr("tcp", hostPort)
 }
 
+const (
+	// torClassPeers is the Tor isolation class of the connections to our
+	// peers.
+	torClassPeers = "peers"
+
+	// torClassWatchtowers is the Tor isolation class of the connections
+	// to our watchtowers.
+	torClassWatchtowers = "watchtowers"
+
+	// torClassFeeEstimator is the Tor isolation class of the connections
+	// to the web fee estimator.
+	torClassFeeEstimator = "feeestimator"
+)
+
+// isolatedNet returns the network the connections of the subsystem with the
+// given Tor isolation class are dialed through. The class only affects which
+// circuits are used if the subsystem isolation policy is configured.
+func isolatedNet(netCfg tor.Net, class string) tor.Net {
+	proxyNet, ok := netCfg.(*tor.ProxyNet)
+	if !ok {
+		return netCfg
+	}
+
+	return proxyNet.WithIsolationClass(class)
+}
+
 // noiseDial is a factory function which creates a connmgr compliant dialing
 // function by returning a closure which includes the server's identity key.
 func noiseDial(idKey keychain.SingleKeyECDH,
@@ -479,7 +652,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	dbs *DatabaseInstances, cc *chainreg.ChainControl,
 	nodeKeyDesc *keychain.KeyDescriptor,
 	chansToRestore walletunlocker.ChannelsToRecover,
-	chanPredicate chanacceptor.ChannelAcceptor,
+	chanPredicate chanacceptor.MultiplexAcceptor,
 	torController *tor.Controller, tlsManager *TLSManager) (*server,
 	error) {
 
@@ -510,6 +683,17 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	var serializedPubKey [33]byte
 	copy(serializedPubKey[:], nodeKeyDesc.PubKey.SerializeCompressed())
 
+	// In dev builds, faults can be injected into some of the subsystems
+	// through the dev RPC to reproduce edge cases. The chain notifier is
+	// wrapped before it's handed to any of them.
+	var faultInjector *fault.Injector
+	if lncfg.IsDevBuild() {
+		faultInjector = fault.NewInjector()
+		cc.ChainNotifier = fault.WrapChainNotifier(
+			cc.ChainNotifier, faultInjector,
+		)
+	}
+
 	// Initialize the sphinx router.
 	replayLog := htlcswitch.NewDecayedLog(
 		dbs.DecayedLogDB, cc.ChainNotifier,
@@ -549,29 +733,55 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoAnySegwit:              cfg.ProtocolOptions.NoAnySegwit(),
 		CustomFeatures:           cfg.ProtocolOptions.CustomFeatures(),
 		NoTaprootChans:           !cfg.ProtocolOptions.TaprootChans,
+		NoTaprootGossip:          !cfg.ProtocolOptions.TaprootGossip,
+		NoPeerStorage:            !cfg.ProtocolOptions.PeerStorage,
+		NoRbfCoopClose:           !cfg.ProtocolOptions.RbfCoopClose,
+		NoOnionMessages:          !cfg.ProtocolOptions.OnionMessages,
+		NoTrampolineRouting:      !cfg.ProtocolOptions.TrampolineRouting,
 		NoRouteBlinding:          cfg.ProtocolOptions.NoRouteBlinding(),
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// All time based subsystems share a single clock. In dev builds, it
+	// can be replaced by a clock that can be moved forward through the
+	// dev RPC to simulate the passing of time.
+	nodeClock := clock.NewDefaultClock()
+	if cfg.Dev.GetTimeWarp() {
+		srvrLog.Warnf("Using warpable clock, time can be moved " +
+			"forward through the dev RPC")
+
+		nodeClock = lnutils.NewWarpClock()
+	}
+
+	// The htlc deltas may be scaled down in the time-compressed CLTV dev
+	// mode, so we use the same set of deltas for the invoice registry, the
+	// switch and the chain arbitrator to keep them consistent.
+	cltvDeltas := cfg.cltvDeltas()
+	finalCltvRejectDelta := int32(cltvDeltas.FinalCltvRejectDelta)
+
 	registryConfig := invoices.RegistryConfig{
-		FinalCltvRejectDelta:        lncfg.DefaultFinalCltvRejectDelta,
+		FinalCltvRejectDelta:        finalCltvRejectDelta,
 		HtlcHoldDuration:            invoices.DefaultHtlcHoldDuration,
-		Clock:                       clock.NewDefaultClock(),
+		Clock:                       nodeClock,
 		AcceptKeySend:               cfg.AcceptKeySend,
 		AcceptAMP:                   cfg.AcceptAMP,
 		GcCanceledInvoicesOnStartup: cfg.GcCanceledInvoicesOnStartup,
 		GcCanceledInvoicesOnTheFly:  cfg.GcCanceledInvoicesOnTheFly,
 		KeysendHoldTime:             cfg.KeysendHoldTime,
+		ReceiveQuota:                cfg.Invoices.ReceiveQuota(),
 	}
 
 	s := &server{
 		cfg:            cfg,
+		clock:          nodeClock,
+		faultInjector:  faultInjector,
 		graphDB:        dbs.GraphDB.ChannelGraph(),
 		chanStateDB:    dbs.ChanStateDB.ChannelStateDB(),
 		addrSource:     dbs.ChanStateDB,
 		miscDB:         dbs.ChanStateDB,
+		boltDBs:        dbs.BoltDBs,
 		invoicesDB:     dbs.InvoiceDB,
 		cc:             cc,
 		sigPool:        lnwallet.NewSigPool(cfg.Workers.Sig, cc.Signer),
@@ -601,6 +811,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		persistentPeerAddrs:     make(map[string][]*lnwire.NetAddress),
 		persistentRetryCancels:  make(map[string]chan struct{}),
 		peerErrors:              make(map[string]*queue.CircularBuffer),
+		peerDisconnects:         make(map[string][]time.Time),
 		ignorePeerTermination:   make(map[*peer.Brontide]struct{}),
 		scheduledPeerConnection: make(map[string]func()),
 		pongBuf:                 make([]byte, lnwire.MaxPongBytes),
@@ -619,21 +830,101 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		quit:       make(chan struct{}),
 	}
 
+	if cfg.ProtocolOptions.OnionMessages {
+		s.onionMessageServer = subscribe.NewServer()
+
+		msgCfg := &onionmessage.Config{
+			NodeKey:          nodeKeyECDH,
+			ChainParams:      cfg.ActiveNetParams.Params,
+			SendToPeer:       s.sendOnionMessage,
+			Clock:            nodeClock,
+			FetchChannelPeer: s.fetchChannelPeer,
+			NotifyMessage:    s.notifyOnionMessage,
+		}
+
+		// As part of the async payments protocol, we hold the onion
+		// messages for our offline channel peers.
+		if cfg.ProtocolOptions.AsyncPayments {
+			msgCfg.HoldForPeer = s.hasOpenChannels
+		}
+
+		s.onionMessenger = onionmessage.New(msgCfg)
+	}
+
+	if cfg.ProtocolOptions.AsyncPayments {
+		s.asyncPayments = asyncpay.NewManager(&asyncpay.Config{
+			Messenger:      s.onionMessenger,
+			BuildReplyPath: s.buildOnionReplyPath,
+			HoldTTL:        asyncpay.DefaultHoldTTL,
+			Clock:          nodeClock,
+		})
+	}
+
 	currentHash, currentHeight, err := s.cc.ChainIO.GetBestBlock()
 	if err != nil {
 		return nil, err
 	}
 
 	expiryWatcher := invoices.NewInvoiceExpiryWatcher(
-		clock.NewDefaultClock(), cfg.Invoices.HoldExpiryDelta,
+		s.clock, cfg.Invoices.HoldExpiryDelta,
 		uint32(currentHeight), currentHash, cc.ChainNotifier,
 	)
+
+	s.accountStore, err = accounts.NewStore(dbs.ChanStateDB)
+	if err != nil {
+		return nil, err
+	}
+	registryConfig.InvoiceCreator = s.accountStore.InvoiceCreator
+
 	s.invoices = invoices.NewRegistry(
 		dbs.InvoiceDB, expiryWatcher, &registryConfig,
 	)
 
+	if cfg.ProtocolOptions.TrampolineRouting {
+		trampolineCfg := cfg.Trampoline
+		s.trampolineRelay = htlcswitch.NewTrampolineRelay(
+			&htlcswitch.TrampolineRelayConfig{
+				Registry: s.invoices,
+				NodeKey:  nodeKeyECDH,
+				Policy: trampoline.FeePolicy{
+					BaseFee: lnwire.MilliSatoshi(
+						trampolineCfg.BaseFee,
+					),
+					FeeRate: lnwire.MilliSatoshi(
+						trampolineCfg.FeeRate,
+					),
+					CltvDelta: trampolineCfg.CltvDelta,
+				},
+				DB:           dbs.ChanStateDB,
+				SendPayment:  s.sendTrampolinePayment,
+				TrackPayment: s.trackTrampolinePayment,
+			},
+		)
+	}
+
 	s.htlcNotifier = htlcswitch.NewHtlcNotifier(time.Now)
 
+	var jammingMitigated func(lnwire.ShortChannelID) bool
+	if cfg.JamDetect.Active {
+		jamCfg := cfg.JamDetect
+		s.jamDetector = jamdetect.New(&jamdetect.Config{
+			SubscribeHtlcEvents: s.htlcNotifier.SubscribeHtlcEvents,
+			Clock:               nodeClock,
+			Ticker:              ticker.New(jamCfg.Interval),
+			MinHoldTime:         jamCfg.MinHoldTime,
+			SlotThreshold:       jamCfg.SlotThreshold,
+			SmallAmount: lnwire.MilliSatoshi(
+				jamCfg.SmallAmount,
+			),
+			LiquidityThreshold: lnwire.MilliSatoshi(
+				jamCfg.LiquidityThreshold,
+			),
+			Mitigate:       jamCfg.Mitigate,
+			MitigationTime: jamCfg.MitigationTime,
+		})
+		jammingMitigated = s.jamDetector.Mitigated
+	}
+
 	thresholdSats := btcutil.Amount(cfg.DustThreshold)
 	thresholdMSats := lnwire.NewMSatFromSatoshis(thresholdSats)
 
@@ -642,6 +933,55 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	if cfg.Invoices.DeterministicPreimages {
+		s.preimageDeriver, err = invoices.NewPreimageDeriver(
+			cc.KeyRing, dbs.ChanStateDB,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.HoldInvoiceRules.Active {
+		s.holdInvoiceRules = newHoldInvoiceRules(
+			cfg, s.invoices, dbs.InvoiceDB, s.preimageDeriver,
+			nodeClock,
+		)
+	}
+
+	if cfg.LNURL.Active && cfg.LNURL.Zaps {
+		zapStore, err := lnurl.NewZapStore(dbs.ChanStateDB)
+		if err != nil {
+			return nil, err
+		}
+
+		// The receipts are signed through the key ring rather than
+		// with a derived private key, so they can also be signed by a
+		// remote signer.
+		zapKey, err := cc.KeyRing.DeriveKey(lnurl.NostrKeyLoc)
+		if err != nil {
+			return nil, err
+		}
+		signZapEvent := func(serialized []byte) (*schnorr.Signature,
+			error) {
+
+			return cc.KeyRing.SignMessageSchnorr(
+				lnurl.NostrKeyLoc, serialized, false, nil, nil,
+			)
+		}
+
+		zapCfg := &lnurl.ZapPublisherConfig{
+			Registry:       s.invoices,
+			Store:          zapStore,
+			PubKey:         zapKey.PubKey,
+			SignEvent:      signZapEvent,
+			Relays:         cfg.LNURL.NostrRelays,
+			PublishTimeout: lnurl.DefaultPublishTimeout,
+			GCTicker:       ticker.New(lnurl.DefaultGCInterval),
+		}
+		s.zapPublisher = lnurl.NewZapPublisher(zapCfg)
+	}
+
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
 		DB:                   dbs.ChanStateDB,
 		FetchAllOpenChannels: s.chanStateDB.FetchAllOpenChannels,
@@ -672,22 +1012,66 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		AckEventTicker:         ticker.New(htlcswitch.DefaultAckInterval),
 		AllowCircularRoute:     cfg.AllowCircularRoute,
 		RejectHTLC:             cfg.RejectHTLC,
-		Clock:                  clock.NewDefaultClock(),
+		Clock:                  s.clock,
 		MailboxDeliveryTimeout: cfg.Htlcswitch.MailboxDeliveryTimeout,
 		DustThreshold:          thresholdMSats,
 		SignAliasUpdate:        s.signAliasUpdate,
 		IsAlias:                aliasmgr.IsAlias,
+		FaultInjector:          faultInjector,
+		JammingMitigated:       jammingMitigated,
+		FetchHtlcAttemptIDs:    s.miscDB.FetchHtlcAttemptIDs,
+		NetworkResultRetention: cfg.Htlcswitch.NetworkResultRetention,
+		NetworkResultPruneTicker: ticker.New(
+			cfg.Htlcswitch.NetworkResultPruneInterval,
+		),
 	}, uint32(currentHeight))
 	if err != nil {
 		return nil, err
 	}
+	s.safeMode = newSafeMode(
+		s.htlcSwitch.EnterSafeMode, s.htlcSwitch.ExitSafeMode,
+		nodeClock,
+	)
+	s.fundingContributors = newFundingContributors(
+		func(pid [32]byte, packet *psbt.Packet) error {
+			return cc.Wallet.PsbtFundingVerify(pid, packet, false)
+		},
+		func(pid [32]byte, packet *psbt.Packet) error {
+			return cc.Wallet.PsbtFundingFinalize(pid, packet, nil)
+		},
+		cc.Wallet.CancelFundingIntent, nodeClock,
+	)
+	// The interceptable switch hands the forwards to just-in-time channels
+	// to their manager, so it must not be a typed nil.
+	var jitChannelHandler htlcswitch.JITChannelHandler
+	if cfg.JITChannel.Active {
+		jitCfg := cfg.JITChannel
+		s.jitChannels = jitchannel.NewManager(&jitchannel.Config{
+			RequestAlias:     s.aliasMgr.RequestAlias,
+			NotifyWhenOnline: s.NotifyWhenOnline,
+			OpenChannel:      s.openJITChannel,
+			AddAlias:         s.addJITChannelAlias,
+			Pricing: jitchannel.StaticPricing(
+				btcutil.Amount(jitCfg.MinChanSize),
+				btcutil.Amount(jitCfg.MaxChanSize),
+				lnwire.MilliSatoshi(jitCfg.BaseFee),
+				jitCfg.FeeRate,
+			),
+			Clock:           nodeClock,
+			RegistrationTTL: jitCfg.RegistrationTTL,
+			OpenTimeout:     jitCfg.OpenTimeout,
+		})
+		jitChannelHandler = s.jitChannels
+	}
+
 	s.interceptableSwitch, err = htlcswitch.NewInterceptableSwitch(
 		&htlcswitch.InterceptableSwitchConfig{
 			Switch:             s.htlcSwitch,
-			CltvRejectDelta:    lncfg.DefaultFinalCltvRejectDelta,
-			CltvInterceptDelta: lncfg.DefaultCltvInterceptDelta,
+			CltvRejectDelta:    cltvDeltas.FinalCltvRejectDelta,
+			CltvInterceptDelta: cltvDeltas.CltvInterceptDelta,
 			RequireInterceptor: s.cfg.RequireInterceptor,
 			Notifier:           s.cc.ChainNotifier,
+			JITChannels:        jitChannelHandler,
 		},
 	)
 	if err != nil {
@@ -718,6 +1102,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	}
 	s.chanStatusMgr = chanStatusMgr
 
+	s.chanFreezer = newChannelFreezer(
+		s.htlcSwitch.FreezeLink, s.htlcSwitch.UnfreezeLink,
+		chanStatusMgr,
+	)
+
 	// If enabled, use either UPnP or NAT-PMP to automatically configure
 	// port forwarding for users behind a NAT.
 	if cfg.NAT {
@@ -834,6 +1223,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	}
 	copy(selfNode.PubKeyBytes[:], nodeKeyDesc.PubKey.SerializeCompressed())
 
+	// Attach the configured operator information as odd TLV records.
+	selfNode.ExtraOpaqueData, err = netann.SetNodeMetadata(
+		nil, &netann.NodeMetadata{
+			Contact: cfg.NodeMetadata.Contact,
+			OrgName: cfg.NodeMetadata.OrgName,
+			URL:     cfg.NodeMetadata.URL,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node metadata: %w", err)
+	}
+
 	// Based on the disk representation of the node announcement generated
 	// above, we'll generate a node announcement that can go out on the
 	// network so we can properly sign it.
@@ -943,24 +1344,58 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		float64(routingConfig.AttemptCostPPM)/10000,
 		routingConfig.MinRouteProbability)
 
+	if routingConfig.ShadowRouteMaxCltv > routing.MaxShadowRouteMaxCltv {
+		return nil, fmt.Errorf("shadow route max cltv %v exceeds "+
+			"maximum of %v", routingConfig.ShadowRouteMaxCltv,
+			routing.MaxShadowRouteMaxCltv)
+	}
+
+	amtRandomization, err := routing.ParseAmountRandomization(
+		routingConfig.AmountRandomization,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	pathFindingConfig := routing.PathFindingConfig{
 		AttemptCost: lnwire.NewMSatFromSatoshis(
 			routingConfig.AttemptCost,
 		),
-		AttemptCostPPM: routingConfig.AttemptCostPPM,
-		MinProbability: routingConfig.MinRouteProbability,
+		AttemptCostPPM:    routingConfig.AttemptCostPPM,
+		MinProbability:    routingConfig.MinRouteProbability,
+		EndorsementAware:  routingConfig.EndorsementAware,
+		EndorsementWindow: routingConfig.EndorsementWindow,
+		UnendorsedPenalty: routing.DefaultUnendorsedPenalty,
+
+		BlindedFeeTolerancePPM: routingConfig.BlindedFeeTolerancePPM,
+		ParallelPathSearches:   routingConfig.ParallelPathSearches,
+		ShadowRouteMaxCltv:     routingConfig.ShadowRouteMaxCltv,
+		AmountRandomization:    amtRandomization,
 	}
 
 	sourceNode, err := chanGraph.SourceNode()
 	if err != nil {
 		return nil, fmt.Errorf("error getting source node: %w", err)
 	}
+
+	// The route cache is only used if a time to live is configured.
+	var routeCache *routing.RouteCache
+	if routingConfig.RouteCacheTTL > 0 {
+		routeCache = routing.NewRouteCache(
+			routingConfig.RouteCacheTTL, s.clock,
+		)
+	}
+
+	s.knownRouteHints = routing.NewKnownRouteHints(s.clock)
+
 	paymentSessionSource := &routing.SessionSource{
 		Graph:             chanGraph,
 		SourceNode:        sourceNode,
 		MissionControl:    s.missionControl,
 		GetLink:           s.htlcSwitch.GetLinkByShortID,
 		PathFindingConfig: pathFindingConfig,
+		RouteCache:        routeCache,
+		KnownRouteHints:   s.knownRouteHints,
 	}
 
 	paymentControl := channeldb.NewPaymentControl(dbs.ChanStateDB)
@@ -985,9 +1420,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		AssumeChannelValid:  cfg.Routing.AssumeChannelValid,
 		NextPaymentID:       sequencer.NextID,
 		PathFindingConfig:   pathFindingConfig,
-		Clock:               clock.NewDefaultClock(),
+		Clock:               s.clock,
 		StrictZombiePruning: strictPruning,
 		IsAlias:             aliasmgr.IsAlias,
+		RouteCache:          routeCache,
+		LocalPaymentSettler: s.invoices,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %w", err)
@@ -1034,12 +1471,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PinnedSyncers:           cfg.Gossip.PinnedSyncers,
 		MaxChannelUpdateBurst:   cfg.Gossip.MaxChannelUpdateBurst,
 		ChannelUpdateInterval:   cfg.Gossip.ChannelUpdateInterval,
+		NumSigWorkers:           cfg.Workers.GossipSig,
 		IsAlias:                 aliasmgr.IsAlias,
 		SignAliasUpdate:         s.signAliasUpdate,
 		FindBaseByAlias:         s.aliasMgr.FindBaseSCID,
 		GetAlias:                s.aliasMgr.GetPeerAlias,
 		FindChannel:             s.findChannel,
 		IsStillZombieChannel:    s.chanRouter.IsZombieChannel,
+		FaultInjector:           faultInjector,
+
+		MaxLocalChannelUpdateBurst: cfg.Gossip.MaxLocalChannelUpdateBurst, //nolint:lll
+		LocalChannelUpdateInterval: cfg.Gossip.LocalChannelUpdateInterval, //nolint:lll
+		ThrottleStatusUpdates:      cfg.Gossip.ThrottleStatusUpdates,
 	}, nodeKeyDesc)
 
 	s.localChanMgr = &localchans.Manager{
@@ -1067,13 +1510,28 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	aggregator := sweep.NewBudgetAggregator(
 		cc.FeeEstimator, sweep.DefaultMaxInputsPerTx,
+		cfg.Sweeper.DeadlineBatchDelta,
 	)
 
+	// Let the fee bumper take the mempool into account if requested and
+	// supported by the fee estimator.
+	mempoolFees := fn.None[chainfee.MempoolFeeSource]()
+	if cfg.Sweeper.MempoolAware {
+		source, ok := cc.FeeEstimator.(chainfee.MempoolFeeSource)
+		if ok {
+			mempoolFees = fn.Some(source)
+		} else {
+			srvrLog.Warnf("Fee estimator doesn't provide mempool " +
+				"fee rates, ignoring sweeper.mempoolaware")
+		}
+	}
+
 	s.txPublisher = sweep.NewTxPublisher(sweep.TxPublisherConfig{
-		Signer:    cc.Wallet.Cfg.Signer,
-		Wallet:    cc.Wallet,
-		Estimator: cc.FeeEstimator,
-		Notifier:  cc.ChainNotifier,
+		Signer:      cc.Wallet.Cfg.Signer,
+		Wallet:      cc.Wallet,
+		Estimator:   cc.FeeEstimator,
+		Notifier:    cc.ChainNotifier,
+		MempoolFees: mempoolFees,
 	})
 
 	s.sweeper = sweep.New(&sweep.UtxoSweeperConfig{
@@ -1138,8 +1596,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	//nolint:lll
 	s.chainArb = contractcourt.NewChainArbitrator(contractcourt.ChainArbitratorConfig{
 		ChainHash:              *s.cfg.ActiveNetParams.GenesisHash,
-		IncomingBroadcastDelta: lncfg.DefaultIncomingBroadcastDelta,
-		OutgoingBroadcastDelta: lncfg.DefaultOutgoingBroadcastDelta,
+		IncomingBroadcastDelta: cltvDeltas.IncomingBroadcastDelta,
+		OutgoingBroadcastDelta: cltvDeltas.OutgoingBroadcastDelta,
 		NewSweepAddr:           newSweepPkScriptGen(cc.Wallet),
 		PublishTx:              cc.Wallet.PublishTransaction,
 		DeliverResolutionMsg: func(msgs ...contractcourt.ResolutionMsg) error {
@@ -1225,7 +1683,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		OnionProcessor:                s.sphinx,
 		PaymentsExpirationGracePeriod: cfg.PaymentsExpirationGracePeriod,
 		IsForwardedHTLC:               s.htlcSwitch.IsForwardedHTLC,
-		Clock:                         clock.NewDefaultClock(),
+		Clock:                         s.clock,
 		SubscribeBreachComplete:       s.breachArbitrator.SubscribeBreachComplete,
 		PutFinalHtlcOutcome:           s.chanStateDB.PutOnchainFinalHtlcOutcome,
 		HtlcNotifier:                  s.htlcNotifier,
@@ -1246,12 +1704,58 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 			return &pc.Incoming
 		},
+		FetchNodeAlias: s.fetchNodeAlias,
 	}, dbs.ChanStateDB)
 
+	if cfg.AnchorReserve.Active {
+		s.anchorReserve = newAnchorReserveMonitor(cfg, cc)
+	}
+
+	s.lspClient = lsps.NewClient(&lsps.Config{
+		SendCustomMessage: s.SendCustomMessage,
+		Acceptor:          chanPredicate,
+		Clock:             nodeClock,
+		RequestTimeout:    lsps.DefaultRequestTimeout,
+	})
+
+	if cfg.LiquidityAlert.Active {
+		s.liquidityMonitor = newLiquidityMonitor(
+			cfg.LiquidityAlert, s.chanStateDB, nodeClock,
+		)
+	}
+
+	if cfg.CltvGuard.Active {
+		guardCfg := cfg.CltvGuard
+		notifier := cc.ChainNotifier
+		s.cltvGuard = cltvguard.New(&cltvguard.Config{
+			RegisterBlockEpochNtfn: notifier.RegisterBlockEpochNtfn,
+			Clock:                  nodeClock,
+			Ticker:                 ticker.New(guardCfg.Interval),
+			StormBlocks:            guardCfg.StormBlocks,
+			StormWindow:            guardCfg.StormWindow,
+			MaxTipAge:              guardCfg.MaxTipAge,
+			ExtraDelta:             guardCfg.ExtraDelta,
+			HoldTime:               guardCfg.HoldTime,
+		})
+	}
+
+	if cfg.HoldFee.Active {
+		s.holdFeePolicy = newHoldFeePolicy(cfg.HoldFee, nodeClock)
+	}
+
+	requiredBits, forbiddenBits, err := cfg.FeaturePolicy.Bits()
+	if err != nil {
+		return nil, err
+	}
+	s.featurePolicy = feature.NewPolicy(
+		requiredBits, forbiddenBits, nodeClock,
+	)
+	s.initRecords = peer.NewInitRecords()
+
 	// Select the configuration and funding parameters for Bitcoin.
 	chainCfg := cfg.Bitcoin
-	minRemoteDelay := funding.MinBtcRemoteDelay
-	maxRemoteDelay := funding.MaxBtcRemoteDelay
+	minRemoteDelay := cfg.minRemoteDelay()
+	maxRemoteDelay := cfg.maxRemoteDelay()
 
 	var chanIDSeed [32]byte
 	if _, err := rand.Read(chanIDSeed[:]); err != nil {
@@ -1320,6 +1824,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			devCfg, reservationTimeout, zombieSweeperInterval)
 	}
 
+	// In safe mode, we don't accept any channel open requests.
+	openChanPredicate := &safeModeAcceptor{
+		safeMode: s.safeMode,
+		acceptor: chanPredicate,
+	}
+
 	//nolint:lll
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
 		Dev:                devCfg,
@@ -1328,6 +1838,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		IDKeyLoc:           nodeKeyDesc.KeyLocator,
 		Wallet:             cc.Wallet,
 		PublishTransaction: cc.Wallet.PublishTransaction,
+		FetchNodeAlias:     s.fetchNodeAlias,
 		UpdateLabel: func(hash chainhash.Hash, label string) error {
 			return cc.Wallet.LabelTransaction(hash, label, true)
 		},
@@ -1446,6 +1957,26 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			cid := lnwire.NewChanIDFromOutPoint(chanPoint)
 			return s.htlcSwitch.UpdateShortChanID(cid)
 		},
+		ZeroConfMempoolActivation: cfg.ProtocolOptions.
+			ZeroConfMempool(),
+		Mempool: cc.MempoolNotifier,
+		BlockOutgoingAdds: func(chanPoint wire.OutPoint,
+			block bool) error {
+
+			cid := lnwire.NewChanIDFromOutPoint(chanPoint)
+			link, err := s.htlcSwitch.GetLink(cid)
+			if err != nil {
+				return err
+			}
+
+			if block {
+				link.DisableAdds(htlcswitch.Outgoing)
+			} else {
+				link.EnableAdds(htlcswitch.Outgoing)
+			}
+
+			return nil
+		},
 		RequiredRemoteChanReserve: func(chanAmt,
 			dustLimit btcutil.Amount) btcutil.Amount {
 
@@ -1485,7 +2016,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RejectPush:                    cfg.RejectPush,
 		MaxLocalCSVDelay:              chainCfg.MaxLocalDelay,
 		NotifyOpenChannelEvent:        s.channelNotifier.NotifyOpenChannelEvent,
-		OpenChannelPredicate:          chanPredicate,
+		OpenChannelPredicate:          openChanPredicate,
 		NotifyPendingOpenChannelEvent: s.channelNotifier.NotifyPendingOpenChannelEvent,
 		EnableUpfrontShutdown:         cfg.EnableUpfrontShutdown,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
@@ -1504,7 +2035,22 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		chanNotifier: s.channelNotifier,
 		addrs:        dbs.ChanStateDB,
 	}
-	backupFile := chanbackup.NewMultiFile(cfg.BackupFilePath)
+	var backupFile chanbackup.Swapper = chanbackup.NewMultiFile(
+		cfg.BackupFilePath,
+	)
+
+	// If the backup escrow is active, every new backup is pushed to the
+	// escrow endpoint once it is stored on disk.
+	if cfg.BackupEscrow.Active {
+		backupFile = chanbackup.NewEscrowSwapper(
+			backupFile, s.identityECDH.PubKey(),
+			&chanbackup.EscrowEndpoint{
+				URL:     cfg.BackupEscrow.URL,
+				Timeout: cfg.BackupEscrow.Timeout,
+			},
+		)
+	}
+
 	startingChans, err := chanbackup.FetchStaticChanBackups(
 		s.chanStateDB, s.addrSource,
 	)
@@ -1531,7 +2077,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			return s.peerNotifier.SubscribePeerEvents()
 		},
 		GetOpenChannels: s.chanStateDB.FetchAllOpenChannels,
-		Clock:           clock.NewDefaultClock(),
+		Clock:           s.clock,
 		ReadFlapCount:   s.miscDB.ReadFlapCount,
 		WriteFlapCount:  s.miscDB.WriteFlapCounts,
 		FlapCountTicker: ticker.New(chanfitness.FlapCountFlushRate),
@@ -1553,6 +2099,10 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			return nil, err
 		}
 
+		// The connections to our towers are dialed in their own Tor
+		// isolation class.
+		towerNet := isolatedNet(cfg.net, torClassWatchtowers)
+
 		// authDial is the wrapper around the btrontide.Dial for the
 		// watchtower.
 		authDial := func(localKey keychain.SingleKeyECDH,
@@ -1616,7 +2166,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			Signer:             cc.Wallet.Cfg.Signer,
 			NewAddress:         newSweepPkScriptGen(cc.Wallet),
 			SecretKeyRing:      s.cc.KeyRing,
-			Dial:               cfg.net.Dial,
+			Dial:               towerNet.Dial,
 			AuthDial:           authDial,
 			DB:                 dbs.TowerClientDB,
 			ChainHash:          *s.cfg.ActiveNetParams.GenesisHash,
@@ -1657,7 +2207,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	}
 
 	// Create liveness monitor.
-	s.createLivenessMonitor(cfg, cc)
+	s.createLivenessMonitor(cfg, cc, dbs)
 
 	// Create the connection manager which will be responsible for
 	// maintaining persistent outbound connections and also accepting new
@@ -1668,7 +2218,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RetryDuration:  time.Second * 5,
 		TargetOutbound: 100,
 		Dial: noiseDial(
-			nodeKeyECDH, s.cfg.net, s.cfg.ConnectionTimeout,
+			nodeKeyECDH, isolatedNet(s.cfg.net, torClassPeers),
+			s.cfg.ConnectionTimeout,
 		),
 		OnConnection: s.OutboundPeerConnected,
 	})
@@ -1701,10 +2252,17 @@ func (s *server) signAliasUpdate(u *lnwire.ChannelUpdate) (*ecdsa.Signature,
 //   - diskCheck
 //   - tlsHealthCheck
 //   - torController, only created when tor is enabled.
+//   - remoteDBCheck, only created when the database backend is remote.
+//
+// The chain backend and remote database checks can put the node into safe
+// mode instead of shutting it down, in which case they are run by the safe
+// mode monitor.
 //
 // If a health check has been disabled by setting attempts to 0, our monitor
 // will not run it.
-func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
+func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl,
+	dbs *DatabaseInstances) {
+
 	chainBackendAttempts := cfg.HealthChecks.ChainCheck.Attempts
 	if cfg.Bitcoin.Node == "nochainbackend" {
 		srvrLog.Info("Disabling chain backend checks for " +
@@ -1713,6 +2271,19 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 		chainBackendAttempts = 0
 	}
 
+	// Checks whose failure should put the node into safe mode rather than
+	// shutting it down are run by a separate monitor.
+	var safeModeChecks []*healthcheck.Observation
+	if cfg.SafeMode.ChainBackend && chainBackendAttempts > 0 {
+		chainCheck := s.newSafeModeObservation(
+			"chain backend", cc.HealthCheck,
+			cfg.HealthChecks.ChainCheck,
+		)
+		safeModeChecks = append(safeModeChecks, chainCheck)
+
+		chainBackendAttempts = 0
+	}
+
 	chainHealthCheck := healthcheck.NewObservation(
 		"chain backend",
 		cc.HealthCheck,
@@ -1778,18 +2349,24 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 
 	// If Tor is enabled, add the healthcheck for tor connection.
 	if s.torController != nil {
+		torCheckCfg := cfg.HealthChecks.TorConnection
 		torConnectionCheck := healthcheck.NewObservation(
 			"tor connection",
 			func() error {
-				return healthcheck.CheckTorServiceStatus(
+				err := healthcheck.CheckTorServiceStatus(
 					s.torController,
 					s.createNewHiddenService,
 				)
+				if err != nil || !torCheckCfg.DaemonStatus {
+					return err
+				}
+
+				return healthcheck.CheckTorDaemonStatus(
+					s.torController,
+				)
 			},
-			cfg.HealthChecks.TorConnection.Interval,
-			cfg.HealthChecks.TorConnection.Timeout,
-			cfg.HealthChecks.TorConnection.Backoff,
-			cfg.HealthChecks.TorConnection.Attempts,
+			torCheckCfg.Interval, torCheckCfg.Timeout,
+			torCheckCfg.Backoff, torCheckCfg.Attempts,
 		)
 		checks = append(checks, torConnectionCheck)
 	}
@@ -1820,6 +2397,27 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 		checks = append(checks, remoteSignerConnectionCheck)
 	}
 
+	// If the remote database health check is enabled, it puts the node
+	// into safe mode unless it should shut lnd down.
+	remoteDBCheck := newRemoteDBHealthCheck(cfg, dbs)
+	if remoteDBCheck != nil {
+		checkCfg := cfg.HealthChecks.RemoteDB
+		if checkCfg.Shutdown {
+			checks = append(checks, healthcheck.NewObservation(
+				remoteDBCheckName, remoteDBCheck,
+				checkCfg.Interval, checkCfg.Timeout,
+				checkCfg.Backoff, checkCfg.Attempts,
+			))
+		} else {
+			safeModeChecks = append(
+				safeModeChecks, s.newSafeModeObservation(
+					remoteDBCheckName, remoteDBCheck,
+					checkCfg.CheckConfig,
+				),
+			)
+		}
+	}
+
 	// If we have not disabled all of our health checks, we create a
 	// liveness monitor with our configured checks.
 	s.livenessMonitor = healthcheck.NewMonitor(
@@ -1828,6 +2426,17 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 			Shutdown: srvrLog.Criticalf,
 		},
 	)
+
+	// The failed checks of the safe mode monitor have already put the node
+	// into safe mode, so there's nothing left to do.
+	if len(safeModeChecks) > 0 {
+		s.safeModeMonitor = healthcheck.NewMonitor(
+			&healthcheck.Config{
+				Checks:   safeModeChecks,
+				Shutdown: srvrLog.Debugf,
+			},
+		)
+	}
 }
 
 // Started returns true if the server has been started, and false otherwise.
@@ -1875,6 +2484,21 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.customMessageServer.Stop)
 
+		if err := s.lspClient.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.lspClient.Stop)
+
+		lspsSub, err := s.customMessageServer.Subscribe()
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		s.wg.Add(1)
+		go s.handleLSPSMessages(lspsSub)
+
 		if s.hostAnn != nil {
 			if err := s.hostAnn.Start(); err != nil {
 				startErr = err
@@ -1891,6 +2515,14 @@ func (s *server) Start() error {
 			cleanup = cleanup.add(s.livenessMonitor.Stop)
 		}
 
+		if s.safeModeMonitor != nil {
+			if err := s.safeModeMonitor.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.safeModeMonitor.Stop)
+		}
+
 		// Start the notification server. This is used so channel
 		// management goroutines can be notified when a funding
 		// transaction reaches a sufficient number of confirmations, or
@@ -2007,6 +2639,63 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.chainArb.Stop)
 
+		if s.anchorReserve != nil {
+			if err := s.anchorReserve.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.anchorReserve.Stop)
+		}
+
+		if s.liquidityMonitor != nil {
+			if err := s.liquidityMonitor.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.liquidityMonitor.Stop)
+		}
+
+		if s.cltvGuard != nil {
+			if err := s.cltvGuard.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.cltvGuard.Stop)
+		}
+
+		if s.jamDetector != nil {
+			if err := s.jamDetector.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.jamDetector.Stop)
+		}
+
+		if s.jitChannels != nil {
+			if err := s.jitChannels.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.jitChannels.Stop)
+		}
+
+		if s.onionMessageServer != nil {
+			err := s.onionMessageServer.Start()
+			if err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.onionMessageServer.Stop)
+		}
+
+		if s.asyncPayments != nil {
+			if err := s.asyncPayments.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.asyncPayments.Stop)
+		}
+
 		if err := s.authGossiper.Start(); err != nil {
 			startErr = err
 			return
@@ -2025,17 +2714,43 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.invoices.Stop)
 
-		if err := s.sphinx.Start(); err != nil {
-			startErr = err
-			return
+		// The trampoline relay resumes its payments through the router,
+		// so it must be started after it.
+		if s.trampolineRelay != nil {
+			if err := s.trampolineRelay.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.trampolineRelay.Stop)
 		}
-		cleanup = cleanup.add(s.sphinx.Stop)
 
-		if err := s.chanStatusMgr.Start(); err != nil {
-			startErr = err
-			return
+		if s.holdInvoiceRules != nil {
+			if err := s.holdInvoiceRules.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.holdInvoiceRules.Stop)
 		}
-		cleanup = cleanup.add(s.chanStatusMgr.Stop)
+
+		if s.zapPublisher != nil {
+			if err := s.zapPublisher.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.zapPublisher.Stop)
+		}
+
+		if err := s.sphinx.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.sphinx.Stop)
+
+		if err := s.chanStatusMgr.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.chanStatusMgr.Stop)
 
 		if err := s.chanEventStore.Start(); err != nil {
 			startErr = err
@@ -2090,6 +2805,17 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.chanSubSwapper.Stop)
 
+		// Keep the backup blobs that our peers store on our behalf up
+		// to date as our channels with them change.
+		chanSub, err := s.channelNotifier.SubscribeChannelEvents()
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		s.wg.Add(1)
+		go s.updatePeerStorage(chanSub)
+
 		if s.torController != nil {
 			if err := s.createNewHiddenService(); err != nil {
 				startErr = err
@@ -2103,6 +2829,13 @@ func (s *server) Start() error {
 			go s.watchExternalIP()
 		}
 
+		// If revocation log compaction is enabled, compact the logs
+		// of the existing channels in the background.
+		if s.cfg.DB.CompactRevLog {
+			s.wg.Add(1)
+			go s.compactRevocationLogs()
+		}
+
 		// Start connmgr last to prevent connections before init.
 		s.connMgr.Start()
 		cleanup = cleanup.add(func() error {
@@ -2213,10 +2946,17 @@ func (s *server) Start() error {
 			)
 		}
 		if s.cfg.Bitcoin.SigNet {
-			setSeedList(
-				s.cfg.Bitcoin.DNSSeeds,
-				chainreg.BitcoinSignetGenesis,
-			)
+			// A custom signet may use its own genesis block, so we
+			// key its seeds by the active chain hash. If it shares
+			// the default genesis block, the default seeds would
+			// point to nodes of the global signet instead, so we
+			// don't use them for a custom challenge.
+			genesisHash := *s.cfg.ActiveNetParams.GenesisHash
+			if s.cfg.Bitcoin.SigNetChallenge != "" {
+				delete(chainreg.ChainDNSSeeds, genesisHash)
+			}
+
+			setSeedList(s.cfg.Bitcoin.DNSSeeds, genesisHash)
 		}
 
 		// If network bootstrapping hasn't been disabled, then we'll
@@ -2261,6 +3001,7 @@ func (s *server) Stop() error {
 		s.connMgr.Stop()
 
 		// Shutdown the wallet, funding manager, and the rpc server.
+		s.chanFreezer.Stop()
 		if err := s.chanStatusMgr.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanStatusMgr: %v", err)
 		}
@@ -2270,12 +3011,75 @@ func (s *server) Stop() error {
 		if err := s.sphinx.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop sphinx: %v", err)
 		}
+		if s.zapPublisher != nil {
+			if err := s.zapPublisher.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop zapPublisher: "+
+					"%v", err)
+			}
+		}
+		if s.holdInvoiceRules != nil {
+			if err := s.holdInvoiceRules.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop "+
+					"holdInvoiceRules: %v", err)
+			}
+		}
 		if err := s.invoices.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop invoices: %v", err)
 		}
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
+		if s.anchorReserve != nil {
+			if err := s.anchorReserve.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop anchorReserve: "+
+					"%v", err)
+			}
+		}
+		if s.liquidityMonitor != nil {
+			if err := s.liquidityMonitor.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop "+
+					"liquidityMonitor: %v", err)
+			}
+		}
+		if err := s.lspClient.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop lspClient: %v", err)
+		}
+		if s.cltvGuard != nil {
+			if err := s.cltvGuard.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop cltvGuard: %v",
+					err)
+			}
+		}
+		if s.jamDetector != nil {
+			if err := s.jamDetector.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop jamDetector: %v",
+					err)
+			}
+		}
+		if s.jitChannels != nil {
+			if err := s.jitChannels.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop jitChannels: %v",
+					err)
+			}
+		}
+		if s.asyncPayments != nil {
+			if err := s.asyncPayments.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop asyncPayments: "+
+					"%v", err)
+			}
+		}
+		if s.onionMessageServer != nil {
+			if err := s.onionMessageServer.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop "+
+					"onionMessageServer: %v", err)
+			}
+		}
+		if s.trampolineRelay != nil {
+			if err := s.trampolineRelay.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop "+
+					"trampolineRelay: %v", err)
+			}
+		}
 		if err := s.chainArb.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chainArb: %v", err)
 		}
@@ -2357,6 +3161,13 @@ func (s *server) Stop() error {
 			}
 		}
 
+		if s.safeModeMonitor != nil {
+			if err := s.safeModeMonitor.Stop(); err != nil {
+				srvrLog.Warnf("unable to shutdown safe mode "+
+					"monitor: %v", err)
+			}
+		}
+
 		// Wait for all lingering goroutines to quit.
 		srvrLog.Debug("Waiting for server to shutdown...")
 		s.wg.Wait()
@@ -2376,6 +3187,84 @@ func (s *server) Stopped() bool {
 	return atomic.LoadInt32(&s.stopping) != 0
 }
 
+// LeadershipState returns the leader election state of this node.
+func (s *server) LeadershipState(
+	ctx context.Context) (*cluster.LeadershipState, error) {
+
+	if s.leaderElector == nil {
+		return nil, fmt.Errorf("leader election is disabled")
+	}
+
+	return cluster.GetLeadershipState(
+		ctx, s.leaderElector, s.cfg.Cluster.ID,
+	)
+}
+
+// BoltDBUsage returns the disk usage of all bolt database files, keyed by
+// their namespace name.
+func (s *server) BoltDBUsage() (map[string]*lncfg.BoltDBUsage, error) {
+	if len(s.boltDBs) == 0 {
+		return nil, fmt.Errorf("database backend %v doesn't support "+
+			"usage reporting", s.cfg.DB.Backend)
+	}
+
+	usage := make(map[string]*lncfg.BoltDBUsage, len(s.boltDBs))
+	for name, db := range s.boltDBs {
+		dbUsage, err := lncfg.GetBoltDBUsage(db)
+		if err != nil {
+			return nil, err
+		}
+
+		usage[name] = dbUsage
+	}
+
+	return usage, nil
+}
+
+// ScheduleBoltCompaction requests that the bolt database files with the given
+// namespace names are compacted on the next restart. All bolt database files
+// are compacted if no names are given.
+func (s *server) ScheduleBoltCompaction(names ...string) error {
+	if len(s.boltDBs) == 0 {
+		return fmt.Errorf("database backend %v doesn't support "+
+			"compaction", s.cfg.DB.Backend)
+	}
+
+	if len(names) == 0 {
+		for name := range s.boltDBs {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		db, ok := s.boltDBs[name]
+		if !ok {
+			return fmt.Errorf("unknown database %v", name)
+		}
+
+		err := lncfg.ScheduleBoltCompaction(db.FilePath)
+		if err != nil {
+			return err
+		}
+
+		srvrLog.Infof("Scheduled compaction of %v on next restart",
+			db.FilePath)
+	}
+
+	return nil
+}
+
+// ReloadConfig reloads the config and applies the options that can be changed
+// without a restart, just like on SIGHUP. The returned report lists which of
+// the changed options were applied and which were rejected.
+func (s *server) ReloadConfig() (*ConfigReloadReport, error) {
+	if s.configReloader == nil {
+		return nil, fmt.Errorf("config reload not available")
+	}
+
+	return s.configReloader.Reload()
+}
+
 // configurePortForwarding attempts to set up port forwarding for the different
 // ports that the server will be listening on.
 //
@@ -2417,6 +3306,24 @@ func (s *server) removePortForwarding() {
 	}
 }
 
+// compactRevocationLogs compacts the revocation logs of all existing
+// channels, so that their HTLC entries are shared between the logs.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) compactRevocationLogs() {
+	defer s.wg.Done()
+
+	srvrLog.Infof("Compacting revocation logs")
+
+	compacted, err := s.chanStateDB.CompactRevocationLogs(s.quit)
+	if err != nil {
+		srvrLog.Errorf("Unable to compact revocation logs: %v", err)
+		return
+	}
+
+	srvrLog.Infof("Compacted %d revocation logs", compacted)
+}
+
 // watchExternalIP continuously checks for an updated external IP address every
 // 15 minutes. Once a new IP address has been detected, it will automatically
 // handle port forwarding rules and send updated node announcements to the
@@ -2553,6 +3460,197 @@ out:
 	}
 }
 
+// newAnchorReserveMonitor creates the anchor reserve monitor from the config.
+// If consolidation is enabled, the confirmed UTXOs of the default account are
+// swept into a fresh wallet address.
+func newAnchorReserveMonitor(cfg *Config,
+	cc *chainreg.ChainControl) *anchorreserve.Monitor {
+
+	arCfg := cfg.AnchorReserve
+	wallet := cc.Wallet
+
+	monitorCfg := &anchorreserve.Config{
+		FetchChannels:   wallet.Cfg.Database.FetchAllOpenChannels,
+		NumAnchorChans:  wallet.CurrentNumAnchorChans,
+		RequiredReserve: wallet.RequiredReserve,
+		ListUnspent: func() ([]*lnwallet.Utxo, error) {
+			return wallet.ListUnspentWitnessFromDefaultAccount(
+				0, math.MaxInt32,
+			)
+		},
+		FeeEstimator: cc.FeeEstimator,
+		ConfTarget:   arCfg.ConfTarget,
+		Ticker:       ticker.New(arCfg.Interval),
+		MaxConsolidationFeeRate: chainfee.SatPerKVByte(
+			arCfg.MaxConsolidationFeeRate * 1000,
+		).FeePerKWeight(),
+		WebhookURL:     arCfg.WebhookURL,
+		WebhookTimeout: arCfg.WebhookTimeout,
+	}
+
+	if arCfg.Consolidate {
+		monitorCfg.Consolidate = func(
+			feeRate chainfee.SatPerKWeight) (chainhash.Hash, error) {
+
+			_, bestHeight, err := cc.ChainIO.GetBestBlock()
+			if err != nil {
+				return chainhash.Hash{}, err
+			}
+
+			addr, err := wallet.NewAddress(
+				lnwallet.TaprootPubkey, false,
+				lnwallet.DefaultAccountName,
+			)
+			if err != nil {
+				return chainhash.Hash{}, err
+			}
+
+			sweepTxPkg, err := sweep.CraftSweepAllTx(
+				feeRate, feeRate, uint32(bestHeight), nil, addr,
+				wallet, wallet, wallet.WalletController,
+				cc.Signer, 1,
+			)
+			if err != nil {
+				return chainhash.Hash{}, err
+			}
+
+			err = wallet.PublishTransaction(
+				sweepTxPkg.SweepTx, labels.MakeLabel(
+					labels.LabelTypeSweepTransaction, nil,
+				),
+			)
+			if err != nil {
+				sweepTxPkg.CancelSweepAttempt()
+				return chainhash.Hash{}, err
+			}
+
+			return sweepTxPkg.SweepTx.TxHash(), nil
+		}
+	}
+
+	return anchorreserve.NewMonitor(monitorCfg)
+}
+
+// newLiquidityMonitor creates the liquidity monitor from the config, checking
+// the balances of the open channels in the given database.
+func newLiquidityMonitor(cfg *lncfg.LiquidityAlert,
+	chanStateDB *channeldb.ChannelStateDB,
+	nodeClock clock.Clock) *liquidityalert.Monitor {
+
+	fetchChannels := func() ([]liquidityalert.Channel, error) {
+		dbChannels, err := chanStateDB.FetchAllOpenChannels()
+		if err != nil {
+			return nil, err
+		}
+
+		channels := make([]liquidityalert.Channel, 0, len(dbChannels))
+		for _, c := range dbChannels {
+			local := c.LocalCommitment.LocalBalance.ToSatoshis()
+			remote := c.LocalCommitment.RemoteBalance.ToSatoshis()
+			channels = append(channels, liquidityalert.Channel{
+				ChanPoint:     c.FundingOutpoint,
+				Peer:          route.NewVertex(c.IdentityPub),
+				Capacity:      c.Capacity,
+				LocalBalance:  local,
+				RemoteBalance: remote,
+			})
+		}
+
+		return channels, nil
+	}
+
+	thresholds := liquidityalert.Thresholds{
+		MinChanOutboundRatio: cfg.MinChanOutboundRatio,
+		MinChanInboundRatio:  cfg.MinChanInboundRatio,
+		MinPeerOutbound:      btcutil.Amount(cfg.MinPeerOutbound),
+		MinPeerInbound:       btcutil.Amount(cfg.MinPeerInbound),
+		MinNodeOutbound:      btcutil.Amount(cfg.MinNodeOutbound),
+		MinNodeInbound:       btcutil.Amount(cfg.MinNodeInbound),
+		OneSidedRatio:        cfg.OneSidedRatio,
+		OneSidedDuration:     cfg.OneSidedDuration,
+	}
+
+	return liquidityalert.NewMonitor(&liquidityalert.Config{
+		FetchChannels:  fetchChannels,
+		Thresholds:     thresholds,
+		Clock:          nodeClock,
+		Ticker:         ticker.New(cfg.Interval),
+		WebhookURL:     cfg.WebhookURL,
+		WebhookTimeout: cfg.WebhookTimeout,
+	})
+}
+
+// newHoldInvoiceRules creates the hold invoice rules configured in the given
+// config. The auto-settle rule can only settle invoices whose preimage was
+// derived deterministically, as lnd doesn't know the preimage of any other
+// hold invoice.
+func newHoldInvoiceRules(cfg *Config, registry *invoices.InvoiceRegistry,
+	invoiceDB invoices.InvoiceDB, deriver *invoices.PreimageDeriver,
+	nodeClock clock.Clock) *invoices.HoldInvoiceRules {
+
+	rulesCfg := cfg.HoldInvoiceRules
+
+	var rules []invoices.HoldRule
+	if rulesCfg.MinAmtMsat > 0 {
+		rules = append(rules, &invoices.MinAmountRule{
+			MinAmt: lnwire.MilliSatoshi(rulesCfg.MinAmtMsat),
+		})
+	}
+
+	if rulesCfg.MaxAutoSettleAmtMsat > 0 {
+		if deriver == nil {
+			srvrLog.Warnf("Hold invoice auto-settle rule requires " +
+				"invoices.deterministic-preimages, ignoring it")
+		} else {
+			rules = append(rules, &invoices.AutoSettleRule{
+				MaxAmt: lnwire.MilliSatoshi(
+					rulesCfg.MaxAutoSettleAmtMsat,
+				),
+				LookupPreimage: func(hash lntypes.Hash) (
+					lntypes.Preimage, error) {
+
+					preimage, _, err := deriver.Find(
+						hash, 0,
+					)
+
+					return preimage, err
+				},
+			})
+		}
+	}
+
+	if rulesCfg.WebhookURL != "" {
+		rules = append(rules, &invoices.WebhookRule{
+			URL:     rulesCfg.WebhookURL,
+			Timeout: rulesCfg.WebhookTimeout,
+		})
+	}
+
+	return invoices.NewHoldInvoiceRules(&invoices.HoldInvoiceRulesConfig{
+		Registry:      registry,
+		InvoiceDB:     invoiceDB,
+		ChainParams:   cfg.ActiveNetParams.Params,
+		Rules:         rules,
+		AcceptTimeout: rulesCfg.AcceptTimeout,
+		Clock:         nodeClock,
+	})
+}
+
+// attributeInvoiceCreator attributes the invoice with the given payment hash
+// to the root key ID of the macaroon of the request in the given context, so
+// the receive quota of its creator can be enforced. Requests without a
+// macaroon leave the invoice unattributed.
+func (s *server) attributeInvoiceCreator(ctx context.Context,
+	hash lntypes.Hash) error {
+
+	rootKeyID, err := macaroons.RootKeyIDFromRequest(ctx)
+	if err != nil || rootKeyID == nil {
+		return err
+	}
+
+	return s.accountStore.AttributeInvoiceCreator(hash, string(rootKeyID))
+}
+
 // initNetworkBootstrappers initializes a set of network peer bootstrappers
 // based on the server, and currently active bootstrap mechanisms as defined
 // within the current configuration.
@@ -3052,6 +4150,7 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 	selfNode.Alias = newNodeAnn.Alias.String()
 	selfNode.Features = s.featureMgr.Get(feature.SetNodeAnn)
 	selfNode.Color = newNodeAnn.RGBColor
+	selfNode.ExtraOpaqueData = newNodeAnn.ExtraOpaqueData
 	selfNode.AuthSigBytes = newNodeAnn.Signature.ToSignatureBytes()
 
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
@@ -3329,6 +4428,15 @@ func (s *server) BroadcastMessage(skips map[route.Vertex]struct{},
 			defer s.wg.Done()
 			defer wg.Done()
 
+			// Peers that don't understand taproot gossip must not
+			// receive any of its messages.
+			msgs := discovery.FilterTaprootGossip(
+				p.RemoteFeatures(), msgs,
+			)
+			if len(msgs) == 0 {
+				return
+			}
+
 			p.SendMessageLazy(false, msgs...)
 		}(sPeer)
 	}
@@ -3789,6 +4897,493 @@ func (s *server) SubscribeCustomMessages() (*subscribe.Client, error) {
 	return s.customMessageServer.Subscribe()
 }
 
+// handleLSPSMessages forwards the LSPS messages our peers send us to the LSP
+// client.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) handleLSPSMessages(sub *subscribe.Client) {
+	defer s.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		select {
+		case update := <-sub.Updates():
+			msg, ok := update.(*CustomMessage)
+			if !ok || msg.Msg.Type != lsps.MessageType {
+				continue
+			}
+
+			s.lspClient.HandleMessage(msg.Peer, msg.Msg.Data)
+
+		case <-sub.Quit():
+			return
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// openJITChannel opens a private zero-conf channel to the client of a
+// just-in-time channel on the given terms. It returns the funding outpoint
+// once the channel can forward htlcs.
+func (s *server) openJITChannel(client *btcec.PublicKey,
+	terms *jitchannel.Terms, cancel <-chan struct{}) (*wire.OutPoint,
+	error) {
+
+	baseFee := uint64(terms.BaseFee)
+	feeRate := terms.FeeRate
+	chanType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+		lnwire.ZeroConfRequired,
+		lnwire.ScidAliasRequired,
+	))
+
+	updates, errChan := s.OpenChannel(&funding.InitFundingMsg{
+		TargetPubkey:    client,
+		ChainHash:       *s.cfg.ActiveNetParams.GenesisHash,
+		LocalFundingAmt: terms.Capacity,
+		BaseFee:         &baseFee,
+		FeeRate:         &feeRate,
+		Private:         true,
+		MinConfs:        1,
+		ChannelType:     &chanType,
+	})
+
+	for {
+		select {
+		// A zero-conf channel is open once the channel_ready messages
+		// were exchanged, which doesn't wait for any confirmation.
+		case update := <-updates:
+			chanPoint := update.GetChanOpen().GetChannelPoint()
+			if chanPoint == nil {
+				continue
+			}
+
+			txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+			if err != nil {
+				return nil, err
+			}
+
+			return &wire.OutPoint{
+				Hash:  *txid,
+				Index: chanPoint.OutputIndex,
+			}, nil
+
+		case err := <-errChan:
+			return nil, err
+
+		case <-cancel:
+			return nil, fmt.Errorf("channel open to %x cancelled",
+				client.SerializeCompressed())
+
+		case <-s.quit:
+			return nil, ErrServerShuttingDown
+		}
+	}
+}
+
+// addJITChannelAlias adds the short channel id of a just-in-time channel as
+// alias to the channel that was opened for it, so that the switch forwards
+// the htlcs sent to the short channel id into the channel.
+func (s *server) addJITChannelAlias(chanID lnwire.ChannelID,
+	alias lnwire.ShortChannelID) error {
+
+	channel, err := s.chanStateDB.FetchChannelByID(nil, chanID)
+	if err != nil {
+		return err
+	}
+
+	err = s.aliasMgr.AddLocalAlias(alias, channel.ShortChannelID, true)
+	if err != nil {
+		return err
+	}
+
+	return s.htlcSwitch.AddAliasForLink(chanID, alias)
+}
+
+// fetchOwnPeerStorage returns our own backup blob that we ask the target peer
+// to store on our behalf. The blob is an encrypted multi channel backup of all
+// the channels that we have open with the peer.
+func (s *server) fetchOwnPeerStorage(peer *btcec.PublicKey) ([]byte, error) {
+	openChans, err := s.chanStateDB.FetchOpenChannels(peer)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeAddrs, err := s.addrSource.AddrsForNode(peer)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]chanbackup.Single, 0, len(openChans))
+	for _, openChan := range openChans {
+		backups = append(
+			backups, chanbackup.NewSingle(openChan, nodeAddrs),
+		)
+	}
+
+	multi := chanbackup.Multi{
+		Version:       chanbackup.DefaultMultiVersion,
+		StaticBackups: backups,
+	}
+
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, s.cc.KeyRing); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// updatePeerStorage sends a connected peer our latest backup blob whenever a
+// channel with the peer is opened or closed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) updatePeerStorage(sub *subscribe.Client) {
+	defer s.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		var remotePub *btcec.PublicKey
+		select {
+		case update := <-sub.Updates():
+			switch event := update.(type) {
+			case channelnotifier.OpenChannelEvent:
+				remotePub = event.Channel.IdentityPub
+
+			case channelnotifier.ClosedChannelEvent:
+				remotePub = event.CloseSummary.RemotePub
+
+			default:
+				continue
+			}
+
+		case <-sub.Quit():
+			return
+
+		case <-s.quit:
+			return
+		}
+
+		peer, err := s.FindPeer(remotePub)
+		if err != nil {
+			continue
+		}
+
+		if err := peer.SendPeerStorage(); err != nil {
+			srvrLog.Warnf("Unable to send peer storage to %x: %v",
+				remotePub.SerializeCompressed(), err)
+		}
+	}
+}
+
+// sendTrampolinePayment makes the payment to the next trampoline node that
+// relays a trampoline payment we received. It blocks until the payment
+// succeeded or failed.
+func (s *server) sendTrampolinePayment(
+	fwd *htlcswitch.TrampolineForward) (lntypes.Preimage, error) {
+
+	_, height, err := s.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return lntypes.Preimage{}, err
+	}
+	currentHeight := uint32(height)
+
+	if fwd.OutgoingCltv <= currentHeight ||
+		fwd.CltvLimit <= fwd.OutgoingCltv {
+
+		return lntypes.Preimage{}, fmt.Errorf("trampoline payment "+
+			"expiry %v too soon at height %v", fwd.OutgoingCltv,
+			currentHeight)
+	}
+
+	finalCltvDelta := fwd.OutgoingCltv - currentHeight
+	if finalCltvDelta > math.MaxUint16 {
+		return lntypes.Preimage{}, fmt.Errorf("trampoline payment "+
+			"expiry %v too far in the future", fwd.OutgoingCltv)
+	}
+
+	payment := &routing.LightningPayment{
+		Target:            fwd.NextNode,
+		Amount:            fwd.Amount,
+		FeeLimit:          fwd.FeeLimit,
+		CltvLimit:         fwd.CltvLimit - currentHeight,
+		FinalCLTVDelta:    uint16(finalCltvDelta),
+		PayAttemptTimeout: trampolinePaymentTimeout,
+		DestCustomRecords: record.CustomSet{
+			uint64(record.TrampolineOnionType): fwd.Onion,
+		},
+		MaxParts: 1,
+	}
+	if err := payment.SetPaymentHash(fwd.PaymentHash); err != nil {
+		return lntypes.Preimage{}, err
+	}
+
+	preimage, _, err := s.chanRouter.SendPayment(payment)
+	if err != nil {
+		return lntypes.Preimage{}, err
+	}
+
+	return preimage, nil
+}
+
+// trackTrampolinePayment waits for the outcome of a payment to the next
+// trampoline node that was already made, using the control tower.
+func (s *server) trackTrampolinePayment(
+	hash lntypes.Hash) (lntypes.Preimage, error) {
+
+	sub, err := s.controlTower.SubscribePayment(hash)
+	if err != nil {
+		return lntypes.Preimage{}, err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case update, ok := <-sub.Updates():
+			if !ok {
+				return lntypes.Preimage{}, fmt.Errorf(
+					"payment %v subscription closed", hash,
+				)
+			}
+
+			payment, ok := update.(*channeldb.MPPayment)
+			if !ok || !payment.Terminated() {
+				continue
+			}
+
+			settle, reason := payment.TerminalInfo()
+			if settle != nil {
+				return settle.Settle.Preimage, nil
+			}
+
+			return lntypes.Preimage{}, fmt.Errorf("trampoline "+
+				"payment %v failed: %v", hash, reason)
+
+		case <-s.quit:
+			return lntypes.Preimage{}, ErrServerShuttingDown
+		}
+	}
+}
+
+// sendOnionMessage sends an onion message to the given peer. Messages are only
+// sent to connected peers that signal support for onion messages.
+func (s *server) sendOnionMessage(peerKey route.Vertex,
+	msg *lnwire.OnionMessage) error {
+
+	p, err := s.FindPeerByPubStr(string(peerKey[:]))
+	if errors.Is(err, ErrPeerNotConnected) {
+		return fmt.Errorf("unable to send onion message to %v: %w",
+			peerKey, onionmessage.ErrPeerOffline)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to send onion message to %v: %w",
+			peerKey, err)
+	}
+
+	if !p.RemoteFeatures().HasFeature(lnwire.OnionMessagesOptional) {
+		return fmt.Errorf("peer %v does not support onion messages",
+			peerKey)
+	}
+
+	return p.SendMessageLazy(false, msg)
+}
+
+// hasOpenChannels returns true if we have at least one open channel with the
+// given peer.
+func (s *server) hasOpenChannels(peerKey route.Vertex) bool {
+	pubKey, err := btcec.ParsePubKey(peerKey[:])
+	if err != nil {
+		return false
+	}
+
+	openChans, err := s.chanStateDB.FetchOpenChannels(pubKey)
+	if err != nil {
+		srvrLog.Errorf("Unable to fetch channels with %v: %v",
+			peerKey, err)
+
+		return false
+	}
+
+	return len(openChans) > 0
+}
+
+// notifyOnionMessage passes an onion message that was delivered to us on to
+// the subscribers.
+func (s *server) notifyOnionMessage(msg *onionmessage.Message) {
+	if err := s.onionMessageServer.SendUpdate(msg); err != nil {
+		srvrLog.Errorf("Unable to notify onion message: %v", err)
+	}
+}
+
+// SendOnionMessage sends an onion message with the given contents over the
+// blinded path. If includeReplyPath is set, a reply path to our node is
+// included in the message and its path ID is returned.
+func (s *server) SendOnionMessage(path *sphinx.BlindedPath,
+	contents map[tlv.Type][]byte, includeReplyPath bool) ([]byte, error) {
+
+	if s.onionMessenger == nil {
+		return nil, ErrOnionMessagesDisabled
+	}
+
+	var (
+		pathID    []byte
+		replyPath *sphinx.BlindedPath
+	)
+	if includeReplyPath {
+		var err error
+		pathID, err = onionmessage.NewPathID()
+		if err != nil {
+			return nil, err
+		}
+
+		replyPath, err = s.buildOnionReplyPath(pathID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build reply path: %w",
+				err)
+		}
+	}
+
+	err := s.onionMessenger.SendMessage(path, replyPath, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return pathID, nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of the onion messages that
+// are delivered to us.
+func (s *server) SubscribeOnionMessages() (*subscribe.Client, error) {
+	if s.onionMessageServer == nil {
+		return nil, ErrOnionMessagesDisabled
+	}
+
+	return s.onionMessageServer.Subscribe()
+}
+
+// buildOnionReplyPath builds a blinded path to our node for the replies to our
+// onion messages. The path is introduced by one of our peers that supports
+// onion messages, so that it doesn't reveal our node.
+func (s *server) buildOnionReplyPath(pathID []byte) (*sphinx.BlindedPath,
+	error) {
+
+	var intro *btcec.PublicKey
+	for _, p := range s.Peers() {
+		features := p.RemoteFeatures()
+		if features == nil ||
+			!features.HasFeature(lnwire.OnionMessagesOptional) {
+
+			continue
+		}
+
+		intro = p.IdentityKey()
+		break
+	}
+	if intro == nil {
+		return nil, errors.New("no connected peer supports onion " +
+			"messages")
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return onionmessage.BuildBlindedPath(
+		sessionKey,
+		[]*btcec.PublicKey{intro, s.identityECDH.PubKey()}, pathID,
+	)
+}
+
+// fetchChannelPeer returns the node at the other end of the given channel of
+// ours.
+func (s *server) fetchChannelPeer(scid lnwire.ShortChannelID) (route.Vertex,
+	error) {
+
+	info, _, _, err := s.graphDB.FetchChannelEdgesByID(scid.ToUint64())
+	if err != nil {
+		return route.Vertex{}, err
+	}
+
+	ourKey := route.NewVertex(s.identityECDH.PubKey())
+	switch ourKey {
+	case info.NodeKey1Bytes:
+		return info.NodeKey2Bytes, nil
+
+	case info.NodeKey2Bytes:
+		return info.NodeKey1Bytes, nil
+
+	default:
+		return route.Vertex{}, fmt.Errorf("channel %v is not ours",
+			scid)
+	}
+}
+
+// handlePeerStorageRetrieval is called when a peer hands back the blob that it
+// stores on our behalf. The channels of the backup that are neither open nor
+// closed in our database are restored, so that we can recover the funds of
+// channels lost with our local state.
+func (s *server) handlePeerStorageRetrieval(peer route.Vertex,
+	blob []byte) error {
+
+	var multi chanbackup.Multi
+	err := multi.UnpackFromReader(bytes.NewReader(blob), s.cc.KeyRing)
+	if err != nil {
+		return fmt.Errorf("unable to unpack peer storage from %v: %w",
+			peer, err)
+	}
+
+	srvrLog.Infof("Peer %v returned our channel backup of %d channel(s)",
+		peer, len(multi.StaticBackups))
+
+	// The blob may be outdated, so we'll skip the channels that we've
+	// closed since, as restoring them would make us try to recover funds
+	// that were already swept.
+	lost := make([]chanbackup.Single, 0, len(multi.StaticBackups))
+	for _, backup := range multi.StaticBackups {
+		_, err := s.chanStateDB.FetchClosedChannel(
+			&backup.FundingOutpoint,
+		)
+		switch {
+		case errors.Is(err, channeldb.ErrClosedChannelNotFound):
+			lost = append(lost, backup)
+
+		case err != nil:
+			return err
+		}
+	}
+
+	if len(lost) == 0 {
+		return nil
+	}
+
+	// Channels that are still open are skipped by the recovery, which
+	// only restores the channels missing from our database. Restoring a
+	// channel reconnects to the peer to start the data loss protection
+	// protocol, so we can't block the read handler of the peer on it.
+	chanRestorer := &chanDBRestorer{
+		db:         s.chanStateDB,
+		secretKeys: s.cc.KeyRing,
+		chainArb:   s.chainArb,
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := chanbackup.Recover(lost, chanRestorer, s)
+		if err != nil {
+			srvrLog.Errorf("Unable to recover channels from peer "+
+				"storage of %v: %v", peer, err)
+		}
+	}()
+
+	return nil
+}
+
 // peerConnected is a function that handles initialization a newly connected
 // peer by adding it to the server's global list of all active peers, and
 // starting all the goroutines the peer needs to function properly. The inbound
@@ -3838,6 +5433,29 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		towerClient = s.towerClientMgr
 	}
 
+	// The max fee rate of automatic fee bumps of RBF coop closes is
+	// configured in sat/vbyte.
+	rbfMaxFeeRate := chainfee.SatPerKVByte(
+		s.cfg.CoopCloseRbf.MaxFeeRate * 1000,
+	).FeePerKWeight()
+
+	// While the CLTV guard is engaged, the links require larger CLTV
+	// deltas for forwards.
+	var extraCltvDelta func() uint32
+	if s.cltvGuard != nil {
+		extraCltvDelta = s.cltvGuard.ExtraDelta
+	}
+
+	cltvDeltas := s.cfg.cltvDeltas()
+
+	// The links hand their exit hop htlcs to the trampoline relay if we
+	// relay trampoline payments, which passes all others on to the invoice
+	// registry.
+	var invoiceDB htlcswitch.InvoiceDatabase = s.invoices
+	if s.trampolineRelay != nil {
+		invoiceDB = s.trampolineRelay
+	}
+
 	// Now that we've established a connection, create a peer, and it to the
 	// set of currently active peers. Configure the peer with the incoming
 	// and outgoing broadcast deltas to prevent htlcs from being accepted or
@@ -3851,7 +5469,10 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		Inbound:                 inbound,
 		Features:                initFeatures,
 		LegacyFeatures:          legacyFeatures,
-		OutgoingCltvRejectDelta: lncfg.DefaultOutgoingCltvRejectDelta,
+		OutgoingCltvRejectDelta: cltvDeltas.OutgoingCltvRejectDelta,
+		ExtraCltvDelta:          extraCltvDelta,
+		HoldFeePolicy:           s.holdFeePolicy,
+		InSafeMode:              s.htlcSwitch.InSafeMode,
 		ChanActiveTimeout:       s.cfg.ChanEnableTimeout,
 		ErrorBuffer:             errBuffer,
 		WritePool:               s.writePool,
@@ -3873,7 +5494,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		RoutingPolicy:           s.cc.RoutingPolicy,
 		Sphinx:                  s.sphinx,
 		WitnessBeacon:           s.witnessBeacon,
-		Invoices:                s.invoices,
+		Invoices:                invoiceDB,
 		ChannelNotifier:         s.channelNotifier,
 		HtlcNotifier:            s.htlcNotifier,
 		TowerClient:             towerClient,
@@ -3897,6 +5518,14 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		MaxOutgoingCltvExpiry:   s.cfg.MaxOutgoingCltvExpiry,
 		MaxChannelFeeAllocation: s.cfg.MaxChannelFeeAllocation,
 		CoopCloseTargetConfs:    s.cfg.CoopCloseTargetConfs,
+
+		CoopCloseRbfBumpInterval:    s.cfg.CoopCloseRbf.BumpInterval,
+		CoopCloseRbfFeeRateIncrease: s.cfg.CoopCloseRbf.FeeRateIncrease,
+		CoopCloseRbfMaxFeeRate:      rbfMaxFeeRate,
+
+		FlappingPeer:       s.isFlappingPeer(pkStr),
+		FlapRecoveryPeriod: s.cfg.Htlcswitch.FlapDrainWindow,
+
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
 		ChannelCommitInterval:  s.cfg.ChannelCommitInterval,
@@ -3907,7 +5536,19 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		RequestAlias:           s.aliasMgr.RequestAlias,
 		AddLocalAlias:          s.aliasMgr.AddLocalAlias,
 		DisallowRouteBlinding:  s.cfg.ProtocolOptions.NoRouteBlinding(),
-		Quit:                   s.quit,
+		FeaturePolicy:          s.featurePolicy,
+		InitRecords:            s.initRecords,
+
+		StorePeerStorage:           s.miscDB.WritePeerStorage,
+		FetchPeerStorage:           s.miscDB.ReadPeerStorage,
+		FetchOwnPeerStorage:        s.fetchOwnPeerStorage,
+		HandlePeerStorageRetrieval: s.handlePeerStorageRetrieval,
+
+		Quit: s.quit,
+	}
+
+	if s.onionMessenger != nil {
+		pCfg.HandleOnionMessage = s.onionMessenger.HandleOnionMessage
 	}
 
 	copy(pCfg.PubKeyBytes[:], peerAddr.IdentityKey.SerializeCompressed())
@@ -3932,6 +5573,61 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 	go s.peerInitializer(p)
 }
 
+// recentDisconnects returns the disconnect times that happened within the
+// given window before now.
+func recentDisconnects(disconnects []time.Time, now time.Time,
+	window time.Duration) []time.Time {
+
+	recent := disconnects[:0]
+	for _, disconnect := range disconnects {
+		if now.Sub(disconnect) < window {
+			recent = append(recent, disconnect)
+		}
+	}
+
+	return recent
+}
+
+// recordPeerDisconnect records a disconnect of the peer with the given
+// compressed pubkey, pruning its disconnects that are outside the flap drain
+// window.
+//
+// NOTE: This MUST be called with the server's mutex held.
+func (s *server) recordPeerDisconnect(pubStr string) {
+	if s.cfg.Htlcswitch.FlapDrainThreshold == 0 {
+		return
+	}
+
+	now := time.Now()
+	s.peerDisconnects[pubStr] = append(recentDisconnects(
+		s.peerDisconnects[pubStr], now, s.cfg.Htlcswitch.FlapDrainWindow,
+	), now)
+}
+
+// isFlappingPeer returns true if the peer with the given compressed pubkey
+// disconnected at least flapdrainthreshold times within the flap drain window.
+//
+// NOTE: This MUST be called with the server's mutex held.
+func (s *server) isFlappingPeer(pubStr string) bool {
+	threshold := s.cfg.Htlcswitch.FlapDrainThreshold
+	if threshold == 0 {
+		return false
+	}
+
+	recent := recentDisconnects(
+		s.peerDisconnects[pubStr], time.Now(),
+		s.cfg.Htlcswitch.FlapDrainWindow,
+	)
+	if len(recent) == 0 {
+		delete(s.peerDisconnects, pubStr)
+		return false
+	}
+
+	s.peerDisconnects[pubStr] = recent
+
+	return uint32(len(recent)) >= threshold
+}
+
 // addPeer adds the passed peer to the server's global state of all active
 // peers.
 func (s *server) addPeer(p *peer.Brontide) {
@@ -4014,6 +5710,12 @@ func (s *server) peerInitializer(p *peer.Brontide) {
 	// was successful, and to begin watching the peer's wait group.
 	close(ready)
 
+	// Deliver the onion messages that we held for the peer while it was
+	// offline.
+	if s.onionMessenger != nil {
+		s.onionMessenger.PeerOnline(p.PubKey())
+	}
+
 	pubStr := string(p.IdentityKey().SerializeCompressed())
 
 	s.mu.Lock()
@@ -4115,6 +5817,10 @@ func (s *server) peerTerminationWatcher(p *peer.Brontide, ready chan struct{}) {
 		return
 	}
 
+	// Record the disconnect so we can tell whether the peer is flapping
+	// once it reconnects.
+	s.recordPeerDisconnect(pubStr)
+
 	// First, cleanup any remaining state the server has regarding the peer
 	// in question.
 	s.removePeer(p)
@@ -4224,6 +5930,7 @@ func (s *server) peerTerminationWatcher(p *peer.Brontide, ready chan struct{}) {
 		srvrLog.Debugf("Attempting to re-establish persistent "+
 			"connection to peer %x",
 			p.IdentityKey().SerializeCompressed())
+		metrics.IncPeerReconnects()
 
 		s.connectToPersistentPeer(pubStr)
 	}()
@@ -4458,8 +6165,9 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 func (s *server) connectToPeer(addr *lnwire.NetAddress,
 	errChan chan<- error, timeout time.Duration) {
 
+	peerNet := isolatedNet(s.cfg.net, torClassPeers)
 	conn, err := brontide.Dial(
-		s.identityECDH, addr, timeout, s.cfg.net.Dial,
+		s.identityECDH, addr, timeout, peerNet.Dial,
 	)
 	if err != nil {
 		srvrLog.Errorf("Unable to connect to %v: %v", addr, err)
@@ -4478,6 +6186,17 @@ func (s *server) connectToPeer(addr *lnwire.NetAddress,
 	s.OutboundPeerConnected(nil, conn)
 }
 
+// peerInitRecords returns the TLV records of the init message received from the
+// connected peer with the given public key.
+func (s *server) peerInitRecords(pubKey [33]byte) (map[uint64][]byte, error) {
+	peer, err := s.FindPeerByPubStr(string(pubKey[:]))
+	if err != nil {
+		return nil, err
+	}
+
+	return peer.RemoteInitRecords(), nil
+}
+
 // DisconnectPeer sends the request to server to close the connection with peer
 // identified by public key.
 //
@@ -4527,6 +6246,12 @@ func (s *server) OpenChannel(
 	req.Updates = make(chan *lnrpc.OpenStatusUpdate, 2)
 	req.Err = make(chan error, 1)
 
+	// We don't open any channels in safe mode.
+	if err := s.safeMode.Err(); err != nil {
+		req.Err <- err
+		return req.Updates, req.Err
+	}
+
 	// First attempt to locate the target peer to open a channel with, if
 	// we're unable to locate the peer then this request will fail.
 	pubKeyBytes := req.TargetPubkey.SerializeCompressed()
@@ -4640,6 +6365,21 @@ func (s *server) fetchNodeAdvertisedAddrs(pub *btcec.PublicKey) ([]net.Addr, err
 	return node.Addresses, nil
 }
 
+// fetchNodeAlias returns the alias the given node announced.
+func (s *server) fetchNodeAlias(pub *btcec.PublicKey) (string, error) {
+	vertex, err := route.NewVertexFromBytes(pub.SerializeCompressed())
+	if err != nil {
+		return "", err
+	}
+
+	node, err := s.graphDB.FetchLightningNode(nil, vertex)
+	if err != nil {
+		return "", err
+	}
+
+	return node.Alias, nil
+}
+
 // fetchLastChanUpdate returns a function which is able to retrieve our latest
 // channel update for a target channel.
 func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
@@ -4720,6 +6460,21 @@ func (s *server) SendCustomMessage(peerPub [33]byte, msgType lnwire.MessageType,
 	return peer.SendMessageLazy(true, msg)
 }
 
+// SendCustomMessageReliably queues a custom message for the peer with the
+// specified pubkey that must be delivered. The message is persisted before it
+// is sent, so that it'll still be sent if the peer is offline or we restart
+// before it was delivered.
+func (s *server) SendCustomMessageReliably(peerPub [33]byte,
+	msgType lnwire.MessageType, data []byte) error {
+
+	msg, err := lnwire.NewCustom(msgType, data)
+	if err != nil {
+		return err
+	}
+
+	return s.authGossiper.SendCustomMessageReliably(peerPub, msg)
+}
+
 // newSweepPkScriptGen creates closure that generates a new public key script
 // which should be used to sweep any funds into the on-chain wallet.
 // Specifically, the script generated is a version 0, pay-to-witness-pubkey-hash
@@ -4753,3 +6508,17 @@ func shouldPeerBootstrap(cfg *Config) bool {
 	// covering the bootstrapping process.
 	return !cfg.NoNetBootstrap && !isDevNetwork
 }
+
+// newHoldFeePolicy creates a hold fee policy with the configured parameters.
+func newHoldFeePolicy(cfg *lncfg.HoldFee,
+	nodeClock clock.Clock) *holdfee.Policy {
+
+	return holdfee.New(&holdfee.Config{
+		Clock:           nodeClock,
+		FreeBlocks:      cfg.FreeBlocks,
+		RatePerBlock:    cfg.RatePerBlock,
+		QueueMultiplier: cfg.QueueMultiplier,
+		MaxRate:         cfg.MaxRate,
+		DryRun:          cfg.DryRun,
+	})
+}
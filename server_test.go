@@ -2,8 +2,10 @@ package lnd
 
 import (
 	"testing"
+	"time"
 
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
 )
 
 // TestShouldPeerBootstrap tests that we properly skip network bootstrap for
@@ -80,3 +82,41 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		}
 	}
 }
+
+// TestFlappingPeer tests that a peer is considered flapping once it
+// disconnected flapdrainthreshold times within the flap drain window.
+func TestFlappingPeer(t *testing.T) {
+	t.Parallel()
+
+	const pubStr = "peer"
+
+	s := &server{
+		cfg: &Config{
+			Htlcswitch: &lncfg.Htlcswitch{
+				FlapDrainThreshold: 2,
+				FlapDrainWindow:    time.Hour,
+			},
+		},
+		peerDisconnects: make(map[string][]time.Time),
+	}
+
+	// A single disconnect doesn't make the peer flapping.
+	s.recordPeerDisconnect(pubStr)
+	require.False(t, s.isFlappingPeer(pubStr))
+
+	s.recordPeerDisconnect(pubStr)
+	require.True(t, s.isFlappingPeer(pubStr))
+
+	// Once the disconnects are outside the window, the peer is no longer
+	// flapping and its disconnects are pruned.
+	old := time.Now().Add(-2 * time.Hour)
+	s.peerDisconnects[pubStr] = []time.Time{old, old}
+	require.False(t, s.isFlappingPeer(pubStr))
+	require.NotContains(t, s.peerDisconnects, pubStr)
+
+	// Disconnects aren't tracked if flap detection is disabled.
+	s.cfg.Htlcswitch.FlapDrainThreshold = 0
+	s.recordPeerDisconnect(pubStr)
+	require.False(t, s.isFlappingPeer(pubStr))
+	require.Empty(t, s.peerDisconnects)
+}
@@ -106,6 +106,10 @@ type Interceptor struct {
 	// gracefully, similar to when receiving SIGINT.
 	shutdownRequestChannel chan struct{}
 
+	// reloadChannel is used to receive SIGHUP signals, which request the
+	// daemon to reload its configuration.
+	reloadChannel chan os.Signal
+
 	// quit is closed when instructing the main interrupt handler to exit.
 	// Note that to avoid losing notifications, only shutdown func may
 	// close this channel.
@@ -126,6 +130,7 @@ func Intercept() (Interceptor, error) {
 		interruptChannel:       make(chan os.Signal, 1),
 		shutdownChannel:        make(chan struct{}),
 		shutdownRequestChannel: make(chan struct{}),
+		reloadChannel:          make(chan os.Signal, 1),
 		quit:                   make(chan struct{}),
 	}
 
@@ -136,6 +141,7 @@ func Intercept() (Interceptor, error) {
 		syscall.SIGQUIT,
 	}
 	signal.Notify(channels.interruptChannel, signalsToCatch...)
+	signal.Notify(channels.reloadChannel, syscall.SIGHUP)
 	go channels.mainInterruptHandler()
 
 	return channels, nil
@@ -185,6 +191,7 @@ func (c *Interceptor) mainInterruptHandler() {
 			log.Infof("Gracefully shutting down.")
 			close(c.shutdownChannel)
 			signal.Stop(c.interruptChannel)
+			signal.Stop(c.reloadChannel)
 			return
 		}
 	}
@@ -227,3 +234,9 @@ func (c *Interceptor) RequestShutdown() {
 func (c *Interceptor) ShutdownChannel() <-chan struct{} {
 	return c.shutdownChannel
 }
+
+// ReloadChannel returns the channel that receives a value every time the
+// daemon is asked to reload its configuration with SIGHUP.
+func (c *Interceptor) ReloadChannel() <-chan os.Signal {
+	return c.reloadChannel
+}
@@ -1,6 +1,7 @@
 package lnd
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"reflect"
@@ -10,6 +11,9 @@ import (
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fault"
+	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -17,6 +21,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/devrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/lsprpc"
 	"github.com/lightningnetwork/lnd/lnrpc/neutrinorpc"
 	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
@@ -24,10 +29,15 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/watchtowerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/wtclientrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/hwisigner"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lsps"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
@@ -91,6 +101,11 @@ type subRPCServerConfigs struct {
 	// developers manipulate LND state that is normally not possible.
 	// Should only be used for development purposes.
 	DevRPC *devrpc.Config `group:"devrpc" namespace:"devrpc"`
+
+	// LspRPC is a sub-RPC server that exposes functionality allowing
+	// wallets built on lnd to order channels and just-in-time channels
+	// from LSPs.
+	LspRPC *lsprpc.Config `group:"lsprpc" namespace:"lsprpc"`
 }
 
 // PopulateDependencies attempts to iterate through all the sub-server configs
@@ -122,7 +137,34 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 		modifiers ...netann.NodeAnnModifier) error,
 	parseAddr func(addr string) (net.Addr, error),
 	rpcLogger btclog.Logger,
-	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)) error {
+	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error),
+	nodeClock clock.Clock, faultInjector *fault.Injector,
+	preimageDeriver *invoices.PreimageDeriver,
+	attributeInvoiceCreator func(context.Context,
+		lntypes.Hash) error,
+	featurePolicyRejections func() []feature.PolicyRejection,
+	lspClient *lsps.Client, initRecords *peer.InitRecords,
+	peerInitRecords func([33]byte) (map[uint64][]byte, error),
+	peerStorageBlobs func() (map[route.Vertex][]byte, error)) error {
+
+	// The hardware wallet signer is only created if it is enabled, so
+	// the wallet kit can tell whether to forward inputs to a device.
+	var hardwareSigner *hwisigner.Signer
+	if cfg.HWI.Enable {
+		var err error
+		hardwareSigner, err = hwisigner.New(&hwisigner.Config{
+			Path:         cfg.HWI.Path,
+			DeviceType:   cfg.HWI.DeviceType,
+			Fingerprint:  cfg.HWI.Fingerprint,
+			ChainParams:  activeNetParams,
+			Timeout:      cfg.HWI.Timeout,
+			SkipApproval: cfg.HWI.SkipApproval,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create hardware wallet "+
+				"signer: %w", err)
+		}
+	}
 
 	// First, we'll use reflect to obtain a version of the config struct
 	// that allows us to programmatically inspect its fields.
@@ -202,6 +244,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 					cc.Wallet.Cfg.CoinSelectionStrategy,
 				),
 			)
+			subCfgValue.FieldByName("HardwareSigner").Set(
+				reflect.ValueOf(hardwareSigner),
+			)
 
 		case *autopilotrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -251,6 +296,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("DefaultCLTVExpiry").Set(
 				reflect.ValueOf(defaultDelta),
 			)
+			subCfgValue.FieldByName("MinCLTVExpiry").Set(
+				reflect.ValueOf(cfg.minTimeLockDelta()),
+			)
 			subCfgValue.FieldByName("GraphDB").Set(
 				reflect.ValueOf(graphDB),
 			)
@@ -266,6 +314,12 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("GetAlias").Set(
 				reflect.ValueOf(getAlias),
 			)
+			subCfgValue.FieldByName("PreimageDeriver").Set(
+				reflect.ValueOf(preimageDeriver),
+			)
+			subCfgValue.FieldByName("AttributeInvoiceCreator").Set(
+				reflect.ValueOf(attributeInvoiceCreator),
+			)
 
 		case *neutrinorpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -317,6 +371,18 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(graphDB),
 			)
 
+			subCfgValue.FieldByName("ChanStateDB").Set(
+				reflect.ValueOf(chanStateDB),
+			)
+
+			subCfgValue.FieldByName("Clock").Set(
+				reflect.ValueOf(nodeClock),
+			)
+
+			subCfgValue.FieldByName("FaultInjector").Set(
+				reflect.ValueOf(faultInjector),
+			)
+
 		case *peersrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
 
@@ -332,6 +398,29 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(updateNodeAnnouncement),
 			)
 
+			subCfgValue.FieldByName("FeaturePolicyRejections").Set(
+				reflect.ValueOf(featurePolicyRejections),
+			)
+
+			subCfgValue.FieldByName("InitRecords").Set(
+				reflect.ValueOf(initRecords),
+			)
+
+			subCfgValue.FieldByName("PeerInitRecords").Set(
+				reflect.ValueOf(peerInitRecords),
+			)
+
+			subCfgValue.FieldByName("PeerStorageBlobs").Set(
+				reflect.ValueOf(peerStorageBlobs),
+			)
+
+		case *lsprpc.Config:
+			subCfgValue := extractReflectValue(subCfg)
+
+			subCfgValue.FieldByName("LSPClient").Set(
+				reflect.ValueOf(lspClient),
+			)
+
 		default:
 			return fmt.Errorf("unknown field: %v, %T", fieldName,
 				cfg)
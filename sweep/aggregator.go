@@ -31,6 +31,12 @@ type BudgetAggregator struct {
 	// maxInputs specifies the maximum number of inputs allowed in a single
 	// sweep tx.
 	maxInputs uint32
+
+	// deadlineBatchDelta is the max number of blocks by which the
+	// deadlines of uncontested inputs may differ to still be grouped into
+	// the same cluster, which then uses the earliest of their deadlines.
+	// If zero, only inputs sharing the same deadline are grouped.
+	deadlineBatchDelta int32
 }
 
 // Compile-time constraint to ensure BudgetAggregator implements UtxoAggregator.
@@ -38,11 +44,12 @@ var _ UtxoAggregator = (*BudgetAggregator)(nil)
 
 // NewBudgetAggregator creates a new instance of a BudgetAggregator.
 func NewBudgetAggregator(estimator chainfee.Estimator,
-	maxInputs uint32) *BudgetAggregator {
+	maxInputs, deadlineBatchDelta uint32) *BudgetAggregator {
 
 	return &BudgetAggregator{
-		estimator: estimator,
-		maxInputs: maxInputs,
+		estimator:          estimator,
+		maxInputs:          maxInputs,
+		deadlineBatchDelta: int32(deadlineBatchDelta),
 	}
 }
 
@@ -53,6 +60,8 @@ type clusterGroup map[int32][]SweeperInput
 // 1. filter out inputs whose budget cannot cover min relay fee.
 // 2. filter a list of exclusive inputs.
 // 3. group the inputs into clusters based on their deadline height.
+// 3a. merge the uncontested inputs of clusters whose deadlines are within the
+// deadline batch delta into the cluster with the earliest deadline.
 // 4. sort the inputs in each cluster by their budget.
 // 5. optionally split a cluster if it exceeds the max input limit.
 // 6. create input sets from each of the clusters.
@@ -96,6 +105,12 @@ func (b *BudgetAggregator) ClusterInputs(inputs InputsMap) []InputSet {
 		clusters[height] = cluster
 	}
 
+	// Batch inputs from different closed channels whose deadlines are
+	// close enough to each other.
+	if b.deadlineBatchDelta > 0 {
+		clusters = b.mergeClusters(clusters)
+	}
+
 	// Now that we have the clusters, we can create the input sets.
 	//
 	// NOTE: cannot pre-allocate the slice since we don't know the number
@@ -126,6 +141,95 @@ func (b *BudgetAggregator) ClusterInputs(inputs InputsMap) []InputSet {
 	return inputSets
 }
 
+// mergeClusters merges the clusters whose deadlines lie within the deadline
+// batch delta of each other. Starting from the earliest deadline, the inputs
+// of all clusters whose deadline is at most deadlineBatchDelta blocks later are
+// moved into the cluster of the earliest deadline, so they are swept in a
+// single transaction with a shared change output. As an input is never moved
+// to a later deadline, its deadline is still met.
+//
+// Contested inputs, which the remote party can spend as well, are not moved
+// and stay in the cluster of their own deadline. If the remote party spends
+// such an input first, the sweeping transaction becomes invalid, which would
+// delay the sweeping of all inputs batched with it.
+func (b *BudgetAggregator) mergeClusters(clusters clusterGroup) clusterGroup {
+	heights := make([]int32, 0, len(clusters))
+	for height := range clusters {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	merged := make(clusterGroup, len(clusters))
+
+	// batchHeight is the deadline of the cluster the inputs are currently
+	// merged into.
+	batchHeight := heights[0]
+	for _, height := range heights {
+		// Start a new batch if the deadline is too far from the
+		// current one.
+		if height-batchHeight > b.deadlineBatchDelta {
+			batchHeight = height
+		}
+
+		for _, inp := range clusters[height] {
+			if height == batchHeight || isContested(inp) {
+				merged[height] = append(merged[height], inp)
+
+				continue
+			}
+
+			log.Tracef("Batching input %v with deadline=%v into "+
+				"cluster with deadline=%v", inp.OutPoint(),
+				height, batchHeight)
+
+			merged[batchHeight] = append(merged[batchHeight], inp)
+		}
+	}
+
+	return merged
+}
+
+// isContested returns true if the remote party or anyone else can spend the
+// output of the given input as well.
+func isContested(inp SweeperInput) bool {
+	switch inp.WitnessType() {
+	// The HTLC outputs on the commitment transactions can be claimed by
+	// the remote party using either the preimage or the timeout path.
+	case input.HtlcOfferedTimeoutSecondLevelInputConfirmed,
+		input.HtlcAcceptedSuccessSecondLevelInputConfirmed,
+		input.HtlcOfferedRemoteTimeout,
+		input.HtlcAcceptedRemoteSuccess,
+		input.TaprootHtlcLocalOfferedTimeout,
+		input.TaprootHtlcAcceptedLocalSuccess,
+		input.TaprootHtlcOfferedRemoteTimeout,
+		input.TaprootHtlcAcceptedRemoteSuccess:
+
+		return true
+
+	// The outputs of a revoked commitment can also be spent by the
+	// remote party once their timeouts have passed.
+	case input.CommitmentRevoke,
+		input.HtlcOfferedRevoke,
+		input.HtlcAcceptedRevoke,
+		input.HtlcSecondLevelRevoke,
+		input.TaprootCommitmentRevoke,
+		input.TaprootHtlcOfferedRevoke,
+		input.TaprootHtlcAcceptedRevoke,
+		input.TaprootHtlcSecondLevelRevoke:
+
+		return true
+
+	// Anchor outputs can be spent by anyone after 16 blocks.
+	case input.CommitmentAnchor, input.TaprootAnchorSweepSpend:
+		return true
+
+	default:
+		return false
+	}
+}
+
 // createInputSet takes a set of inputs which share the same deadline height
 // and turns them into a list of `InputSet`, each set is then used to create a
 // sweep transaction.
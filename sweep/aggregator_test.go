@@ -150,7 +150,7 @@ func TestBudgetAggregatorFilterInputs(t *testing.T) {
 
 	// Init the budget aggregator with the mocked estimator and zero max
 	// num of inputs.
-	b := NewBudgetAggregator(estimator, 0)
+	b := NewBudgetAggregator(estimator, 0, 0)
 
 	// Call the method under test.
 	result := b.filterInputs(inputs)
@@ -214,7 +214,7 @@ func TestBudgetAggregatorSortInputs(t *testing.T) {
 	}
 
 	// Init the budget aggregator with zero max num of inputs.
-	b := NewBudgetAggregator(nil, 0)
+	b := NewBudgetAggregator(nil, 0, 0)
 
 	// Call the method under test.
 	result := b.sortInputs(inputs)
@@ -271,15 +271,15 @@ func TestBudgetAggregatorCreateInputSets(t *testing.T) {
 	pi4 := SweeperInput{
 		Input: mockInput4,
 		params: Params{
-			// This input has a deadline height that is different
-			// from the other inputs. When grouped with other
+			// This input has a deadline height that is earlier
+			// than the other inputs. When grouped with other
 			// inputs, it will cause an error to be returned.
-			DeadlineHeight: fn.Some(testHeight + 1),
+			DeadlineHeight: fn.Some(testHeight - 1),
 		},
 	}
 
 	// Create a budget aggregator with max number of inputs set to 2.
-	b := NewBudgetAggregator(nil, 2)
+	b := NewBudgetAggregator(nil, 2, 0)
 
 	// Create test cases.
 	testCases := []struct {
@@ -540,7 +540,7 @@ func TestBudgetInputSetClusterInputs(t *testing.T) {
 	}
 
 	// Create a budget aggregator with a max number of inputs set to 100.
-	b := NewBudgetAggregator(estimator, DefaultMaxInputsPerTx)
+	b := NewBudgetAggregator(estimator, DefaultMaxInputsPerTx, 0)
 
 	// Call the method under test.
 	result := b.ClusterInputs(inputs)
@@ -640,3 +640,55 @@ func TestSplitOnLocktime(t *testing.T) {
 	require.Len(t, result[uint32(0)], 2)
 	require.Equal(t, expectedResult, result)
 }
+
+// TestMergeClusters checks that the uncontested inputs of clusters whose
+// deadlines are within the deadline batch delta are merged into the cluster
+// with the earliest deadline, while contested inputs keep their deadline.
+func TestMergeClusters(t *testing.T) {
+	t.Parallel()
+
+	newInput := func(wt input.WitnessType, deadline int32) SweeperInput {
+		inp := createTestInput(1000, wt)
+
+		return SweeperInput{
+			Input:          &inp,
+			DeadlineHeight: deadline,
+		}
+	}
+
+	var (
+		deadline1 = testHeight + 10
+		deadline2 = testHeight + 12
+		deadline3 = testHeight + 14
+		deadline4 = testHeight + 30
+	)
+
+	// The inputs of deadline2 and deadline3 are within the delta of
+	// deadline1, while deadline4 starts a new batch.
+	toLocal1 := newInput(input.CommitmentTimeLock, deadline1)
+	toLocal2 := newInput(input.CommitmentTimeLock, deadline2)
+	htlc2 := newInput(input.HtlcOfferedRemoteTimeout, deadline2)
+	toRemote3 := newInput(input.CommitmentToRemoteConfirmed, deadline3)
+	toLocal4 := newInput(input.CommitmentTimeLock, deadline4)
+
+	clusters := clusterGroup{
+		deadline1: {toLocal1},
+		deadline2: {toLocal2, htlc2},
+		deadline3: {toRemote3},
+		deadline4: {toLocal4},
+	}
+
+	b := NewBudgetAggregator(nil, DefaultMaxInputsPerTx, 5)
+	merged := b.mergeClusters(clusters)
+
+	require.Equal(t, clusterGroup{
+		deadline1: {toLocal1, toLocal2, toRemote3},
+		deadline2: {htlc2},
+		deadline4: {toLocal4},
+	}, merged)
+
+	// The merged inputs can be swept in a set with the earliest deadline.
+	set, err := NewBudgetInputSet(merged[deadline1], deadline1)
+	require.NoError(t, err)
+	require.Len(t, set.Inputs(), 3)
+}
@@ -0,0 +1,181 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// BatchPreview describes a sweeping transaction the UtxoSweeper plans to
+// create for a batch of its pending inputs.
+type BatchPreview struct {
+	// Inputs are the outpoints of the pending inputs swept by the batch.
+	// Wallet inputs that may be added to cover the fees of the batch are
+	// not included.
+	Inputs []wire.OutPoint
+
+	// DeadlineHeight is the height by which the batch must be confirmed.
+	DeadlineHeight int32
+
+	// Budget is the total budget of the inputs in the batch.
+	Budget btcutil.Amount
+
+	// FeeRate is the estimated fee rate needed to confirm the batch by its
+	// deadline.
+	FeeRate chainfee.SatPerKWeight
+
+	// Weight is the estimated weight of the sweeping transaction.
+	Weight lntypes.WeightUnit
+
+	// UnbatchedWeight is the estimated total weight of the transactions
+	// needed to sweep each input of the batch on its own.
+	UnbatchedWeight lntypes.WeightUnit
+
+	// EstimatedSavings is the fee saved at FeeRate by sweeping the inputs
+	// in a single transaction that shares one change output.
+	EstimatedSavings btcutil.Amount
+}
+
+// previewBatchesReq is an internal message we'll use to represent an external
+// caller's intent to preview the batches the UtxoSweeper would sweep its
+// pending inputs in.
+type previewBatchesReq struct {
+	respChan chan []*BatchPreview
+	errChan  chan error
+}
+
+// PreviewBatches returns the batches the UtxoSweeper would create for its
+// pending inputs if it swept them now, along with the fees saved by batching
+// them. Inputs that are already published or not yet mature are not included.
+func (s *UtxoSweeper) PreviewBatches() ([]*BatchPreview, error) {
+	respChan := make(chan []*BatchPreview, 1)
+	errChan := make(chan error, 1)
+	select {
+	case s.previewBatchesReqs <- &previewBatchesReq{
+		respChan: respChan,
+		errChan:  errChan,
+	}:
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	select {
+	case previews := <-respChan:
+		return previews, nil
+	case err := <-errChan:
+		return nil, err
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+}
+
+// handlePreviewBatchesReq handles a request to preview the batches of the
+// pending inputs.
+func (s *UtxoSweeper) handlePreviewBatchesReq(req *previewBatchesReq) {
+	previews, err := s.previewBatches()
+	if err != nil {
+		req.errChan <- err
+		return
+	}
+
+	req.respChan <- previews
+}
+
+// previewBatches clusters the inputs that are ready to be swept the same way
+// the next sweep would, and estimates the cost of each resulting batch.
+func (s *UtxoSweeper) previewBatches() ([]*BatchPreview, error) {
+	inputs := s.updateSweeperInputs()
+	sets := s.cfg.Aggregator.ClusterInputs(inputs)
+
+	maxFeeRate := s.cfg.MaxFeeRate.FeePerKWeight()
+
+	previews := make([]*BatchPreview, 0, len(sets))
+	for _, set := range sets {
+		// The fee estimator requires a conf target of at least one
+		// block, which we also use for sets past their deadline.
+		confTarget := calcCurrentConfTarget(
+			s.currentHeight, set.DeadlineHeight(),
+		)
+		confTarget = max(confTarget, 1)
+
+		feeRate, err := s.cfg.FeeEstimator.EstimateFeePerKW(confTarget)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate fee rate "+
+				"for conf target %v: %w", confTarget, err)
+		}
+
+		preview, err := newBatchPreview(set, min(feeRate, maxFeeRate))
+		if err != nil {
+			return nil, err
+		}
+
+		previews = append(previews, preview)
+	}
+
+	return previews, nil
+}
+
+// newBatchPreview creates the preview of sweeping the given input set at the
+// given fee rate.
+func newBatchPreview(set InputSet,
+	feeRate chainfee.SatPerKWeight) (*BatchPreview, error) {
+
+	inputs := set.Inputs()
+
+	weight, err := estimateSweepWeight(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	var unbatchedWeight lntypes.WeightUnit
+	for _, inp := range inputs {
+		inputWeight, err := estimateSweepWeight([]input.Input{inp})
+		if err != nil {
+			return nil, err
+		}
+
+		unbatchedWeight += inputWeight
+	}
+
+	savings := feeRate.FeeForWeight(unbatchedWeight) -
+		feeRate.FeeForWeight(weight)
+
+	return &BatchPreview{
+		Inputs: fn.Map(func(inp input.Input) wire.OutPoint {
+			return inp.OutPoint()
+		}, inputs),
+		DeadlineHeight:   set.DeadlineHeight(),
+		Budget:           set.Budget(),
+		FeeRate:          feeRate,
+		Weight:           weight,
+		UnbatchedWeight:  unbatchedWeight,
+		EstimatedSavings: savings,
+	}, nil
+}
+
+// estimateSweepWeight returns the estimated weight of a transaction sweeping
+// the given inputs to a P2TR change output of the wallet.
+func estimateSweepWeight(inputs []input.Input) (lntypes.WeightUnit, error) {
+	estimator := newWeightEstimator(0, 0)
+	estimator.addP2TROutput()
+
+	for _, inp := range inputs {
+		if err := estimator.add(inp); err != nil {
+			return 0, fmt.Errorf("unable to estimate weight of "+
+				"input %v: %w", inp.OutPoint(), err)
+		}
+
+		// If this input comes with a committed output, add that as
+		// well.
+		if inp.RequiredTxOut() != nil {
+			estimator.addOutput(inp.RequiredTxOut())
+		}
+	}
+
+	return estimator.weight(), nil
+}
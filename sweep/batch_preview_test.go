@@ -0,0 +1,57 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewBatchPreview checks that the preview of a batch reports the fee saved
+// by sweeping its inputs in a single transaction.
+func TestNewBatchPreview(t *testing.T) {
+	t.Parallel()
+
+	inputs := make([]SweeperInput, 0, 3)
+	for i := 0; i < 3; i++ {
+		inputs = append(inputs, SweeperInput{
+			Input: createP2WKHInput(10_000),
+			params: Params{
+				Budget: 1_000,
+			},
+		})
+	}
+
+	set, err := NewBudgetInputSet(inputs, testHeight)
+	require.NoError(t, err)
+
+	const feeRate = chainfee.SatPerKWeight(2_500)
+	preview, err := newBatchPreview(set, feeRate)
+	require.NoError(t, err)
+
+	require.Equal(t, []wire.OutPoint{
+		inputs[0].OutPoint(), inputs[1].OutPoint(),
+		inputs[2].OutPoint(),
+	}, preview.Inputs)
+	require.Equal(t, testHeight, preview.DeadlineHeight)
+	require.EqualValues(t, 3_000, preview.Budget)
+	require.Equal(t, feeRate, preview.FeeRate)
+
+	// Sweeping each input on its own requires two more transactions, so
+	// the batch saves their overhead and change outputs.
+	var estimator input.TxWeightEstimator
+	estimator.AddP2TROutput()
+	estimator.AddWitnessInput(input.P2WKHWitnessSize)
+	singleWeight := estimator.Weight()
+
+	require.Equal(t, 3*singleWeight, preview.UnbatchedWeight)
+	require.Less(t, preview.Weight, preview.UnbatchedWeight)
+	require.Equal(
+		t, feeRate.FeeForWeight(preview.UnbatchedWeight)-
+			feeRate.FeeForWeight(preview.Weight),
+		preview.EstimatedSavings,
+	)
+	require.Positive(t, preview.EstimatedSavings)
+}
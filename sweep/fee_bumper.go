@@ -21,6 +21,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/monitoring/metrics"
 )
 
 var (
@@ -249,6 +250,11 @@ type TxPublisherConfig struct {
 
 	// Notifier is used to monitor the confirmation status of the tx.
 	Notifier chainntnfs.ChainNotifier
+
+	// MempoolFees is an optional source of the fee rates in the mempool
+	// of the chain backend. If set, the fee rate of a tx is only increased
+	// when it's at risk of missing its deadline given the current mempool.
+	MempoolFees fn.Option[chainfee.MempoolFeeSource]
 }
 
 // TxPublisher is an implementation of the Bumper interface. It utilizes the
@@ -382,13 +388,25 @@ func (t *TxPublisher) initializeFeeFunction(
 		"maxFeeRateAllowed=%v", confTarget, req.Budget,
 		maxFeeRateAllowed)
 
-	// Initialize the fee function and return it.
+	// Initialize the fee function.
 	//
 	// TODO(yy): return based on differet req.Strategy?
-	return NewLinearFeeFunction(
+	f, err := NewLinearFeeFunction(
 		maxFeeRateAllowed, confTarget, t.cfg.Estimator,
 		req.StartingFeeRate,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// If we know the fee rates in the mempool, only increase the fee rate
+	// when the tx is at risk of missing its deadline.
+	var feeFunc FeeFunction = f
+	t.cfg.MempoolFees.WhenSome(func(mempool chainfee.MempoolFeeSource) {
+		feeFunc = NewMempoolAwareFeeFunction(f, mempool)
+	})
+
+	return feeFunc, nil
 }
 
 // createRBFCompliantTx creates a tx that is compliant with RBF rules. It does
@@ -569,6 +587,7 @@ func (t *TxPublisher) broadcast(requestID uint64) (*BumpResult, error) {
 		log.Errorf("Failed to publish tx %v: %v", txid, err)
 		event = TxFailed
 	}
+	metrics.IncSweepBroadcasts(metrics.Outcome(err))
 
 	result := &BumpResult{
 		Event:     event,
@@ -10,6 +10,13 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+const (
+	// minMempoolAwareConfTarget is the conf target at or below which the
+	// MempoolAwareFeeFunction always increases the fee rate, as the
+	// mempool is no reliable indicator that close to the deadline.
+	minMempoolAwareConfTarget = 2
+)
+
 var (
 	// ErrMaxPosition is returned when trying to increase the position of
 	// the fee function while it's already at its max.
@@ -309,3 +316,65 @@ func (l *LinearFeeFunction) estimateFeeRate(
 
 	return estimatedFeeRate, nil
 }
+
+// MempoolAwareFeeFunction wraps a FeeFunction and only increases its fee rate
+// for a new conf target when the transaction is at risk of missing its
+// deadline given the current mempool. This avoids following a temporary fee
+// spike while the transaction is still expected to confirm in time. Once the
+// transaction is at risk, the wrapped fee function catches up to the position
+// of the conf target at once.
+type MempoolAwareFeeFunction struct {
+	FeeFunction
+
+	// mempool provides the fee rates in the mempool of the chain backend.
+	mempool chainfee.MempoolFeeSource
+}
+
+// Compile-time check to ensure MempoolAwareFeeFunction satisfies the
+// FeeFunction.
+var _ FeeFunction = (*MempoolAwareFeeFunction)(nil)
+
+// NewMempoolAwareFeeFunction creates a fee function that only increases the
+// fee rate of the given fee function when the mempool requires it.
+func NewMempoolAwareFeeFunction(f FeeFunction,
+	mempool chainfee.MempoolFeeSource) *MempoolAwareFeeFunction {
+
+	return &MempoolAwareFeeFunction{
+		FeeFunction: f,
+		mempool:     mempool,
+	}
+}
+
+// IncreaseFeeRate increases the fee rate of the wrapped fee function to the
+// position of the given conf target, unless the current fee rate is enough to
+// be mined within the first half of the remaining blocks. The margin accounts
+// for transactions arriving later and for slow blocks. Close to the deadline
+// the fee rate is always increased.
+//
+// NOTE: part of the FeeFunction interface.
+func (m *MempoolAwareFeeFunction) IncreaseFeeRate(confTarget uint32) (bool,
+	error) {
+
+	if confTarget <= minMempoolAwareConfTarget {
+		return m.FeeFunction.IncreaseFeeRate(confTarget)
+	}
+
+	distribution, err := m.mempool.MempoolFeeDistribution()
+	if err != nil {
+		log.Warnf("Unable to get mempool fee distribution, increasing "+
+			"fee rate: %v", err)
+
+		return m.FeeFunction.IncreaseFeeRate(confTarget)
+	}
+
+	requiredFeeRate := distribution.FeeRateForBlocks(confTarget / 2)
+	if m.FeeRate() >= requiredFeeRate {
+		log.Debugf("Skipped increase feerate: feerate=%v is enough to "+
+			"confirm within %v blocks (requires %v)", m.FeeRate(),
+			confTarget/2, requiredFeeRate)
+
+		return false, nil
+	}
+
+	return m.FeeFunction.IncreaseFeeRate(confTarget)
+}
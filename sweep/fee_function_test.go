@@ -1,8 +1,10 @@
 package sweep
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/stretchr/testify/require"
@@ -392,3 +394,72 @@ func TestLinearFeeFunctionIncreaseFeeRate(t *testing.T) {
 	rt.ErrorIs(err, ErrMaxPosition)
 	rt.False(increased)
 }
+
+// TestMempoolAwareFeeFunctionIncreaseFeeRate checks that the fee rate is only
+// increased when the mempool indicates the deadline is at risk, and that it
+// then catches up with the linear fee function.
+func TestMempoolAwareFeeFunctionIncreaseFeeRate(t *testing.T) {
+	t.Parallel()
+
+	rt := require.New(t)
+
+	estimator := &chainfee.MockEstimator{}
+	defer estimator.AssertExpectations(t)
+
+	mempool := &chainfee.MockMempoolFeeSource{}
+	defer mempool.AssertExpectations(t)
+
+	// Create a linear fee function with a delta of 100.
+	maxFeeRate := chainfee.SatPerKWeight(1100)
+	startingFeeRate := chainfee.SatPerKWeight(300)
+	confTarget := uint32(9)
+
+	linear, err := NewLinearFeeFunction(
+		maxFeeRate, confTarget, estimator, fn.Some(startingFeeRate),
+	)
+	rt.NoError(err)
+
+	f := NewMempoolAwareFeeFunction(linear, mempool)
+
+	// The mempool is empty, so the starting fee rate is enough.
+	calm := chainfee.NewMempoolFeeDistribution(nil)
+	mempool.On("MempoolFeeDistribution").Return(calm, nil).Twice()
+
+	for _, target := range []uint32{8, 7} {
+		increased, err := f.IncreaseFeeRate(target)
+		rt.NoError(err)
+		rt.False(increased)
+		rt.Equal(startingFeeRate, f.FeeRate())
+	}
+
+	// A fee spike fills the next blocks with transactions paying more
+	// than our fee rate, so the fee function catches up to the position
+	// of the conf target.
+	spike := chainfee.NewMempoolFeeDistribution([]chainfee.MempoolTx{{
+		Fee:   btcutil.Amount(50_000_000),
+		VSize: 5_000_000,
+	}})
+	mempool.On("MempoolFeeDistribution").Return(spike, nil).Once()
+
+	increased, err := f.IncreaseFeeRate(6)
+	rt.NoError(err)
+	rt.True(increased)
+	rt.Equal(startingFeeRate+300, f.FeeRate())
+
+	// If the mempool can't be queried, the fee rate is increased.
+	mempool.On("MempoolFeeDistribution").Return(
+		nil, errors.New("backend down"),
+	).Once()
+
+	increased, err = f.IncreaseFeeRate(5)
+	rt.NoError(err)
+	rt.True(increased)
+	rt.Equal(startingFeeRate+400, f.FeeRate())
+
+	// Close to the deadline, the mempool isn't queried and the fee rate is
+	// always increased.
+	increased, err = f.IncreaseFeeRate(2)
+	rt.NoError(err)
+	rt.True(increased)
+	rt.Equal(startingFeeRate+700, f.FeeRate())
+}
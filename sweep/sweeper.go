@@ -289,6 +289,11 @@ type UtxoSweeper struct {
 	// UtxoSweeper is attempting to sweep.
 	pendingSweepsReqs chan *pendingSweepsReq
 
+	// previewBatchesReqs is a channel that will be sent requests by
+	// external callers in order to preview the batches the pending inputs
+	// would be swept in.
+	previewBatchesReqs chan *previewBatchesReq
+
 	// updateReqs is a channel that will be sent requests by external
 	// callers who wish to bump the fee rate of a given input.
 	updateReqs chan *updateReq
@@ -387,14 +392,15 @@ type sweepInputMessage struct {
 // New returns a new Sweeper instance.
 func New(cfg *UtxoSweeperConfig) *UtxoSweeper {
 	return &UtxoSweeper{
-		cfg:               cfg,
-		newInputs:         make(chan *sweepInputMessage),
-		spendChan:         make(chan *chainntnfs.SpendDetail),
-		updateReqs:        make(chan *updateReq),
-		pendingSweepsReqs: make(chan *pendingSweepsReq),
-		quit:              make(chan struct{}),
-		inputs:            make(InputsMap),
-		bumpResultChan:    make(chan *BumpResult, 100),
+		cfg:                cfg,
+		newInputs:          make(chan *sweepInputMessage),
+		spendChan:          make(chan *chainntnfs.SpendDetail),
+		updateReqs:         make(chan *updateReq),
+		pendingSweepsReqs:  make(chan *pendingSweepsReq),
+		previewBatchesReqs: make(chan *previewBatchesReq),
+		quit:               make(chan struct{}),
+		inputs:             make(InputsMap),
+		bumpResultChan:     make(chan *BumpResult, 100),
 	}
 }
 
@@ -443,6 +449,9 @@ func (s *UtxoSweeper) Start() error {
 			case req := <-s.pendingSweepsReqs:
 				req.errChan <- ErrSweeperShuttingDown
 
+			case req := <-s.previewBatchesReqs:
+				req.errChan <- ErrSweeperShuttingDown
+
 			case req := <-s.updateReqs:
 				req.responseChan <- &updateResp{
 					err: ErrSweeperShuttingDown,
@@ -659,6 +668,11 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 		case req := <-s.pendingSweepsReqs:
 			s.handlePendingSweepsReq(req)
 
+		// A new external request has been received to preview the
+		// batches of the pending inputs.
+		case req := <-s.previewBatchesReqs:
+			s.handlePreviewBatchesReq(req)
+
 		// A new external request has been received to bump the fee rate
 		// of a given input.
 		case req := <-s.updateReqs:
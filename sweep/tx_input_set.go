@@ -117,7 +117,10 @@ type BudgetInputSet struct {
 var _ InputSet = (*BudgetInputSet)(nil)
 
 // validateInputs is used when creating new BudgetInputSet to ensure there are
-// no duplicate inputs and they all share the same deadline heights, if set.
+// no duplicate inputs and the deadline heights they set, if any, are not
+// earlier than the deadline height of the set. An input can be swept in a set
+// with an earlier deadline when it's batched with the inputs of another closed
+// channel.
 func validateInputs(inputs []SweeperInput, deadlineHeight int32) error {
 	// Sanity check the input slice to ensure it's non-empty.
 	if len(inputs) == 0 {
@@ -125,7 +128,7 @@ func validateInputs(inputs []SweeperInput, deadlineHeight int32) error {
 	}
 
 	// inputDeadline tracks the input's deadline height. It will be updated
-	// if the input has a different deadline than the specified
+	// if the input has an earlier deadline than the specified
 	// deadlineHeight.
 	inputDeadline := deadlineHeight
 
@@ -136,13 +139,14 @@ func validateInputs(inputs []SweeperInput, deadlineHeight int32) error {
 			// If the input has a deadline height, we'll check if
 			// it's the same as the specified.
 			inp.params.DeadlineHeight.WhenSome(func(h int32) {
-				// Exit early if the deadlines matched.
-				if h == deadlineHeight {
+				// Exit early if the deadline of the set
+				// doesn't exceed the input's deadline.
+				if h >= deadlineHeight {
 					return
 				}
 
 				// Update the deadline height if it's
-				// different.
+				// earlier.
 				inputDeadline = h
 			})
 
@@ -150,8 +154,8 @@ func validateInputs(inputs []SweeperInput, deadlineHeight int32) error {
 		}, inputs)...,
 	)
 
-	// Make sure the inputs share the same deadline height when there is
-	// one.
+	// Make sure the inputs can be confirmed by their deadline heights when
+	// there is one.
 	if inputDeadline != deadlineHeight {
 		return fmt.Errorf("input deadline height not matched: want "+
 			"%d, got %d", deadlineHeight, inputDeadline)
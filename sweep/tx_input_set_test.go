@@ -85,6 +85,12 @@ func TestNewBudgetInputSet(t *testing.T) {
 	set, err = NewBudgetInputSet([]SweeperInput{input0, input3}, testHeight)
 	rt.NoError(err)
 	rt.NotNil(set)
+
+	// Pass a slice of inputs that only have deadline heights that are
+	// earlier than the specified one.
+	set, err = NewBudgetInputSet([]SweeperInput{input1, input2}, 1)
+	rt.NoError(err)
+	rt.NotNil(set)
 }
 
 // TestBudgetInputSetAddInput checks that `addInput` correctly updates the
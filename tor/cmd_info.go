@@ -19,6 +19,14 @@ var (
 	// onion services found for the current control connection while we
 	// expect one.
 	ErrNoServiceFound = errors.New("no active service found")
+
+	// ErrNotBootstrapped is used when the Tor daemon hasn't finished
+	// bootstrapping its connection to the Tor network.
+	ErrNotBootstrapped = errors.New("tor daemon not bootstrapped")
+
+	// ErrNetworkDown is used when the Tor daemon doesn't consider the Tor
+	// network reachable.
+	ErrNetworkDown = errors.New("tor network unreachable")
 )
 
 // CheckOnionService checks that the onion service created by the controller
@@ -75,3 +83,52 @@ func (c *Controller) CheckOnionService() error {
 
 	return nil
 }
+
+// CheckDaemonStatus checks that the Tor daemon has finished bootstrapping and
+// considers the Tor network reachable. It queries the Tor daemon using the
+// endpoints "status/bootstrap-phase" and "network-liveness".
+func (c *Controller) CheckDaemonStatus() error {
+	// The reply to the bootstrap phase should have the following format,
+	//      status/bootstrap-phase=NOTICE BOOTSTRAP PROGRESS=100 ...
+	// so we're interested in the PROGRESS field.
+	resp, err := c.getInfo("status/bootstrap-phase")
+	if err != nil {
+		return err
+	}
+
+	progress, ok := resp["PROGRESS"]
+	if !ok {
+		return errors.New("bootstrap progress not found in reply")
+	}
+	if progress != "100" {
+		return fmt.Errorf("%w: progress is %v%%", ErrNotBootstrapped,
+			progress)
+	}
+
+	// The reply to the network liveness is either "up" or "down".
+	resp, err = c.getInfo("network-liveness")
+	if err != nil {
+		return err
+	}
+
+	// The lines of the reply are joined, so the value is followed by the
+	// final OK of the reply.
+	liveness := resp["network-liveness"]
+	if !strings.HasPrefix(liveness, "up") {
+		return fmt.Errorf("%w: network liveness is %v", ErrNetworkDown,
+			strings.TrimSuffix(liveness, "OK"))
+	}
+
+	return nil
+}
+
+// getInfo queries the Tor daemon for the given GETINFO key and returns the
+// parsed reply.
+func (c *Controller) getInfo(key string) (map[string]string, error) {
+	_, reply, err := c.sendCommand("GETINFO " + key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", err, reply)
+	}
+
+	return parseTorReply(reply), nil
+}
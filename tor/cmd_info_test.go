@@ -1,8 +1,10 @@
 package tor
 
 import (
+	"bufio"
 	"errors"
 	"io"
+	"net/textproto"
 	"syscall"
 	"testing"
 
@@ -108,3 +110,82 @@ func TestCheckOnionServiceFailOnClosedConnection(t *testing.T) {
 	require.Truef(t, eof || reset,
 		"must of EOF or RESET error, instead got: %v", err)
 }
+
+// TestCheckDaemonStatus tests that the status of the Tor daemon is reported
+// from its bootstrap phase and network liveness.
+func TestCheckDaemonStatus(t *testing.T) {
+	t.Parallel()
+
+	const (
+		bootstrapped = "250-status/bootstrap-phase=NOTICE BOOTSTRAP " +
+			"PROGRESS=100 TAG=done SUMMARY=\"Done\"\n250 OK\n"
+		bootstrapping = "250-status/bootstrap-phase=NOTICE BOOTSTRAP " +
+			"PROGRESS=45 TAG=requesting_descriptors " +
+			"SUMMARY=\"Asking for relay descriptors\"\n250 OK\n"
+		networkUp   = "250-network-liveness=up\n250 OK\n"
+		networkDown = "250-network-liveness=down\n250 OK\n"
+	)
+
+	testCases := []struct {
+		name        string
+		serverResps []string
+		expectedErr error
+	}{
+		{
+			name:        "healthy",
+			serverResps: []string{bootstrapped, networkUp},
+		},
+		{
+			name:        "not bootstrapped",
+			serverResps: []string{bootstrapping},
+			expectedErr: ErrNotBootstrapped,
+		},
+		{
+			name:        "network down",
+			serverResps: []string{bootstrapped, networkDown},
+			expectedErr: ErrNetworkDown,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create mock server and client connection.
+			proxy := createTestProxy(t)
+			t.Cleanup(proxy.cleanUp)
+
+			c := &Controller{conn: proxy.clientConn}
+
+			// Let the server mock the reply to each query once
+			// it's received.
+			go func() {
+				server := textproto.NewReader(
+					bufio.NewReader(proxy.serverConn),
+				)
+				for _, resp := range tc.serverResps {
+					_, err := server.ReadLine()
+					if err != nil {
+						return
+					}
+
+					_, err = proxy.serverConn.Write(
+						[]byte(resp),
+					)
+					if err != nil {
+						return
+					}
+				}
+			}()
+
+			err := c.CheckDaemonStatus()
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
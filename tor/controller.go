@@ -36,6 +36,10 @@ const (
 	// cookieLen is the length of the authentication cookie.
 	cookieLen = 32
 
+	// unixSocketPrefix is the prefix of a control address that points to
+	// the UNIX domain socket the Tor server is listening on.
+	unixSocketPrefix = "unix://"
+
 	// ProtocolInfoVersion is the `protocolinfo` version currently supported
 	// by the Tor server.
 	ProtocolInfoVersion = 1
@@ -116,7 +120,8 @@ type Controller struct {
 	// text-based messages within the connection.
 	conn *textproto.Conn
 
-	// controlAddr is the host:port the Tor server is listening locally for
+	// controlAddr is the host:port, or the path of the UNIX domain socket
+	// prefixed with unix://, the Tor server is listening locally for
 	// controller connections on.
 	controlAddr string
 
@@ -138,7 +143,8 @@ type Controller struct {
 }
 
 // NewController returns a new Tor controller that will be able to interact with
-// a Tor server.
+// a Tor server. The control address is either a host:port or the path of a UNIX
+// domain socket prefixed with unix://.
 func NewController(controlAddr string, targetIPAddress string,
 	password string) *Controller {
 
@@ -159,7 +165,7 @@ func (c *Controller) Start() error {
 
 	log.Info("Starting tor controller")
 
-	conn, err := textproto.Dial("tcp", c.controlAddr)
+	conn, err := c.dial()
 	if err != nil {
 		return fmt.Errorf("unable to connect to Tor server: %w", err)
 	}
@@ -222,7 +228,7 @@ func (c *Controller) Reconnect() error {
 	}
 
 	// Make a new connection and authenticate.
-	conn, err := textproto.Dial("tcp", c.controlAddr)
+	conn, err := c.dial()
 	if err != nil {
 		return fmt.Errorf("unable to connect to Tor server: %w", err)
 	}
@@ -243,6 +249,17 @@ func (c *Controller) Reconnect() error {
 	return nil
 }
 
+// dial opens a connection to the control port of the Tor server, over TCP or
+// over a UNIX domain socket depending on the control address.
+func (c *Controller) dial() (*textproto.Conn, error) {
+	if strings.HasPrefix(c.controlAddr, unixSocketPrefix) {
+		path := strings.TrimPrefix(c.controlAddr, unixSocketPrefix)
+		return textproto.Dial("unix", path)
+	}
+
+	return textproto.Dial("tcp", c.controlAddr)
+}
+
 // sendCommand sends a command to the Tor server and returns its response, as a
 // single space-delimited string, and code.
 func (c *Controller) sendCommand(command string) (int, string, error) {
@@ -563,8 +580,27 @@ func (c *Controller) authenticateViaSafeCookie(info protocolInfo) error {
 	)
 	computedServerHash := computeHMAC256(serverKey, hmacMessage)
 	if !hmac.Equal(computedServerHash, decodedServerHash) {
-		return fmt.Errorf("expected server hash %x, got %x",
-			decodedServerHash, computedServerHash)
+		// The Tor server writes a new cookie each time it starts, so
+		// if it restarted while we connected we might have read the
+		// cookie of its previous run. We'll read the cookie once more
+		// before giving up, so a rotated cookie doesn't require a
+		// restart.
+		rotatedCookie, err := c.getAuthCookie(info)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve authentication "+
+				"cookie: %v", err)
+		}
+
+		hmacMessage = bytes.Join([][]byte{
+			rotatedCookie, clientNonce, decodedServerNonce,
+		}, []byte{})
+		computedServerHash = computeHMAC256(serverKey, hmacMessage)
+		if !hmac.Equal(computedServerHash, decodedServerHash) {
+			return fmt.Errorf("expected server hash %x, got %x",
+				decodedServerHash, computedServerHash)
+		}
+
+		log.Info("Authenticating with rotated Tor cookie")
 	}
 
 	// If the MAC check was successful, we'll proceed with the last step of
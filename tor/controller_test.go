@@ -1,8 +1,13 @@
 package tor
 
 import (
+	"encoding/hex"
+	"fmt"
 	"net"
 	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -360,6 +365,108 @@ func TestReconnectSucceed(t *testing.T) {
 	require.NotEqual(t, proxy.clientConn, c.conn)
 }
 
+// TestStartUnixSocketRotatedCookie tests that the controller connects to a Tor
+// server listening on a UNIX domain socket, and that it authenticates with the
+// SAFECOOKIE method if the cookie is rotated after it was read.
+func TestStartUnixSocketRotatedCookie(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "control.sock")
+	cookiePath := filepath.Join(dir, "control_auth_cookie")
+
+	oldCookie := make([]byte, cookieLen)
+	newCookie := make([]byte, cookieLen)
+	newCookie[0] = 1
+	require.NoError(t, os.WriteFile(cookiePath, oldCookie, 0600))
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, listener.Close())
+	})
+
+	// The mocked Tor server rotates its cookie once the controller asked
+	// for the authentication challenge, so the controller has read the
+	// old cookie by then.
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- func() error {
+			conn, err := listener.Accept()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			server := textproto.NewConn(conn)
+
+			if _, err := server.ReadLine(); err != nil {
+				return err
+			}
+			err = server.PrintfLine("250-PROTOCOLINFO 1\r\n"+
+				"250-AUTH METHODS=COOKIE,SAFECOOKIE "+
+				"COOKIEFILE=\"%s\"\r\n"+
+				"250-VERSION Tor=\"0.4.8.9\"\r\n250 OK",
+				cookiePath)
+			if err != nil {
+				return err
+			}
+
+			line, err := server.ReadLine()
+			if err != nil {
+				return err
+			}
+			clientNonce, err := hex.DecodeString(
+				strings.TrimPrefix(
+					line, "AUTHCHALLENGE SAFECOOKIE ",
+				),
+			)
+			if err != nil {
+				return err
+			}
+
+			err = os.WriteFile(cookiePath, newCookie, 0600)
+			if err != nil {
+				return err
+			}
+
+			serverNonce := make([]byte, nonceLen)
+			msg := append(
+				append(newCookie, clientNonce...),
+				serverNonce...,
+			)
+			err = server.PrintfLine("250 AUTHCHALLENGE "+
+				"SERVERHASH=%x SERVERNONCE=%x",
+				computeHMAC256(serverKey, msg), serverNonce)
+			if err != nil {
+				return err
+			}
+
+			line, err = server.ReadLine()
+			if err != nil {
+				return err
+			}
+			expected := fmt.Sprintf("AUTHENTICATE %x",
+				computeHMAC256(controllerKey, msg))
+			if line != expected {
+				return server.PrintfLine("515 Authentication " +
+					"failed")
+			}
+
+			return server.PrintfLine("250 OK")
+		}()
+	}()
+
+	c := NewController("unix://"+socketPath, "", "")
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, c.conn.Close())
+	})
+
+	require.NoError(t, <-serverErr)
+	require.Equal(t, "0.4.8.9", c.version)
+}
+
 // TestParseTorReply tests that Tor replies are parsed correctly.
 func TestParseTorReply(t *testing.T) {
 	testCase := []struct {
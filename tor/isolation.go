@@ -0,0 +1,130 @@
+package tor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/proxy"
+)
+
+// IsolationPolicy determines which of the connections made through Tor's SOCKS
+// proxy are isolated from each other onto distinct circuits. Tor only lets
+// streams share a circuit if they were opened with the same SOCKS credentials,
+// so the policy determines the credentials used for each connection.
+type IsolationPolicy uint8
+
+const (
+	// IsolationNone doesn't use SOCKS credentials, so all connections may
+	// share circuits.
+	IsolationNone IsolationPolicy = iota
+
+	// IsolationPerConnection uses random credentials for each connection,
+	// so every connection uses a fresh circuit.
+	IsolationPerConnection
+
+	// IsolationPerDestination uses random credentials for each destination
+	// address, which are reused for all connections to it. Connections to
+	// the same peer may share a circuit, while connections to different
+	// peers don't.
+	IsolationPerDestination
+
+	// IsolationPerClass uses the credentials of the isolation class of the
+	// dialer, so the connections of different subsystems don't share
+	// circuits.
+	IsolationPerClass
+)
+
+// String returns a human-readable name of the isolation policy.
+func (p IsolationPolicy) String() string {
+	switch p {
+	case IsolationNone:
+		return "none"
+
+	case IsolationPerConnection:
+		return "connection"
+
+	case IsolationPerDestination:
+		return "destination"
+
+	case IsolationPerClass:
+		return "class"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(p))
+	}
+}
+
+var (
+	// isolationKey is the key the credentials of destinations and
+	// isolation classes are derived from. It's generated once per process,
+	// so the credentials are stable while running but can't be linked
+	// across restarts.
+	isolationKey [32]byte
+
+	// isolationKeyOnce guards the generation of the isolation key.
+	isolationKeyOnce sync.Once
+
+	// isolationKeyErr is the error encountered while generating the
+	// isolation key, if any.
+	isolationKeyErr error
+)
+
+// isolationAuth returns the SOCKS credentials to use for a connection to the
+// given address under the isolation policy, or nil if no credentials should
+// be used. The class is the isolation class of the dialer.
+func isolationAuth(policy IsolationPolicy, class,
+	address string) (*proxy.Auth, error) {
+
+	switch policy {
+	case IsolationNone:
+		return nil, nil
+
+	// Tor will create a new circuit for each set of credentials, so we'll
+	// populate them with random data.
+	case IsolationPerConnection:
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, err
+		}
+
+		return &proxy.Auth{
+			User:     hex.EncodeToString(b[:8]),
+			Password: hex.EncodeToString(b[8:]),
+		}, nil
+
+	case IsolationPerDestination:
+		return derivedAuth("destination", address)
+
+	case IsolationPerClass:
+		return derivedAuth("class", class)
+
+	default:
+		return nil, fmt.Errorf("unknown isolation policy: %v", policy)
+	}
+}
+
+// derivedAuth derives the SOCKS credentials for the given identifier within
+// its scope from the isolation key.
+func derivedAuth(scope, id string) (*proxy.Auth, error) {
+	isolationKeyOnce.Do(func() {
+		_, isolationKeyErr = rand.Read(isolationKey[:])
+	})
+	if isolationKeyErr != nil {
+		return nil, isolationKeyErr
+	}
+
+	mac := hmac.New(sha256.New, isolationKey[:])
+	mac.Write([]byte(scope))
+	mac.Write([]byte{0})
+	mac.Write([]byte(id))
+	sum := mac.Sum(nil)
+
+	return &proxy.Auth{
+		User:     hex.EncodeToString(sum[:8]),
+		Password: hex.EncodeToString(sum[8:16]),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package tor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+)
+
+// TestIsolationAuth tests that the SOCKS credentials of each isolation policy
+// are shared by exactly the connections the policy groups together.
+func TestIsolationAuth(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr1 = "abcdefghijklmnop.onion:9735"
+		addr2 = "qrstuvwxyzabcdef.onion:9735"
+	)
+
+	auth := func(policy IsolationPolicy, class,
+		address string) *proxy.Auth {
+
+		t.Helper()
+
+		auth, err := isolationAuth(policy, class, address)
+		require.NoError(t, err)
+
+		return auth
+	}
+
+	// Without isolation, no credentials are used.
+	require.Nil(t, auth(IsolationNone, "peers", addr1))
+
+	// Every connection gets its own credentials, even to the same
+	// destination.
+	require.NotEqual(
+		t, auth(IsolationPerConnection, "", addr1),
+		auth(IsolationPerConnection, "", addr1),
+	)
+
+	// Connections to the same destination share their credentials, no
+	// matter their class, while different destinations don't.
+	require.Equal(
+		t, auth(IsolationPerDestination, "peers", addr1),
+		auth(IsolationPerDestination, "watchtower", addr1),
+	)
+	require.NotEqual(
+		t, auth(IsolationPerDestination, "", addr1),
+		auth(IsolationPerDestination, "", addr2),
+	)
+
+	// Connections of the same class share their credentials, no matter
+	// their destination, while different classes don't.
+	require.Equal(
+		t, auth(IsolationPerClass, "peers", addr1),
+		auth(IsolationPerClass, "peers", addr2),
+	)
+	require.NotEqual(
+		t, auth(IsolationPerClass, "peers", addr1),
+		auth(IsolationPerClass, "watchtower", addr1),
+	)
+
+	// A class doesn't share its credentials with a destination of the
+	// same name.
+	require.NotEqual(
+		t, auth(IsolationPerClass, addr1, addr1),
+		auth(IsolationPerDestination, addr1, addr1),
+	)
+
+	_, err := isolationAuth(IsolationPolicy(100), "", addr1)
+	require.Error(t, err)
+}
+
+// TestProxyNetWithIsolationClass tests that a ProxyNet with an isolation class
+// is a copy that leaves the original untouched.
+func TestProxyNetWithIsolationClass(t *testing.T) {
+	t.Parallel()
+
+	p := &ProxyNet{
+		SOCKS:           "localhost:9050",
+		IsolationPolicy: IsolationPerClass,
+	}
+
+	isolated := p.WithIsolationClass("peers")
+	require.Equal(t, "peers", isolated.IsolationClass)
+	require.Equal(t, p.SOCKS, isolated.SOCKS)
+	require.Equal(t, p.IsolationPolicy, isolated.IsolationPolicy)
+	require.Empty(t, p.IsolationClass)
+}
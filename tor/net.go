@@ -86,9 +86,19 @@ type ProxyNet struct {
 	// StreamIsolation is a bool that determines if we should force the
 	// creation of a new circuit for this connection. If true, then this
 	// means that our traffic may be harder to correlate as each connection
-	// will now use a distinct circuit.
+	// will now use a distinct circuit. It takes precedence over the
+	// IsolationPolicy.
 	StreamIsolation bool
 
+	// IsolationPolicy determines which connections are isolated from each
+	// other onto distinct circuits.
+	IsolationPolicy IsolationPolicy
+
+	// IsolationClass is the isolation class of the connections dialed
+	// through this ProxyNet, which is used by the IsolationPerClass
+	// policy.
+	IsolationClass string
+
 	// SkipProxyForClearNetTargets allows the proxy network to use direct
 	// connections to non-onion service targets. If enabled, the node IP
 	// address will be revealed while communicating with such targets.
@@ -105,12 +115,31 @@ func (p *ProxyNet) Dial(network, address string,
 	default:
 		return nil, errors.New("cannot dial non-tcp network via Tor")
 	}
-	return Dial(
-		address, p.SOCKS, p.StreamIsolation,
-		p.SkipProxyForClearNetTargets, timeout,
+
+	policy := p.IsolationPolicy
+	if p.StreamIsolation {
+		policy = IsolationPerConnection
+	}
+
+	auth, err := isolationAuth(policy, p.IsolationClass, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return dial(
+		address, p.SOCKS, auth, p.SkipProxyForClearNetTargets, timeout,
 	)
 }
 
+// WithIsolationClass returns a copy of the ProxyNet that dials its connections
+// in the given isolation class.
+func (p *ProxyNet) WithIsolationClass(class string) *ProxyNet {
+	isolated := *p
+	isolated.IsolationClass = class
+
+	return &isolated
+}
+
 // LookupHost uses the Tor LookupHost function in order to resolve hosts over
 // Tor.
 func (p *ProxyNet) LookupHost(host string) ([]string, error) {
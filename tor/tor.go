@@ -2,8 +2,6 @@ package tor
 
 import (
 	"bytes"
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"net"
 	"strconv"
@@ -69,9 +67,30 @@ func Dial(address, socksAddr string, streamIsolation bool,
 	skipProxyForClearNetTargets bool,
 	timeout time.Duration) (net.Conn, error) {
 
+	policy := IsolationNone
+	if streamIsolation {
+		policy = IsolationPerConnection
+	}
+
+	auth, err := isolationAuth(policy, "", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return dial(
+		address, socksAddr, auth, skipProxyForClearNetTargets, timeout,
+	)
+}
+
+// dial establishes a connection to the address via the provided Tor SOCKS
+// proxy using the given credentials, and wraps it to expose the actual remote
+// address.
+func dial(address, socksAddr string, auth *proxy.Auth,
+	skipProxyForClearNetTargets bool,
+	timeout time.Duration) (net.Conn, error) {
+
 	conn, err := dialProxy(
-		address, socksAddr, streamIsolation,
-		skipProxyForClearNetTargets, timeout,
+		address, socksAddr, auth, skipProxyForClearNetTargets, timeout,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("dial proxy failed: %w", err)
@@ -94,33 +113,17 @@ func Dial(address, socksAddr string, streamIsolation bool,
 // dialProxy establishes a connection to the address via the provided TOR SOCKS
 // proxy. Only TCP traffic may be routed via Tor.
 //
-// streamIsolation determines if we should force stream isolation for this new
-// connection. If enabled, new connections will use a fresh circuit, rather than
-// possibly re-using an existing circuit.
+// auth holds the SOCKS credentials of the connection, which Tor uses to isolate
+// streams onto distinct circuits. If nil, no credentials are used and the
+// connection may re-use an existing circuit.
 //
 // skipProxyForClearNetTargets argument allows the dialer to directly connect
 // to the provided address if it does not represent an union service, skipping
 // the SOCKS proxy.
-func dialProxy(address, socksAddr string, streamIsolation bool,
+func dialProxy(address, socksAddr string, auth *proxy.Auth,
 	skipProxyForClearNetTargets bool,
 	timeout time.Duration) (net.Conn, error) {
 
-	// If we were requested to force stream isolation for this connection,
-	// we'll populate the authentication credentials with random data as
-	// Tor will create a new circuit for each set of credentials.
-	var auth *proxy.Auth
-	if streamIsolation {
-		var b [16]byte
-		if _, err := rand.Read(b[:]); err != nil {
-			return nil, err
-		}
-
-		auth = &proxy.Auth{
-			User:     hex.EncodeToString(b[:8]),
-			Password: hex.EncodeToString(b[8:]),
-		}
-	}
-
 	clearDialer := &net.Dialer{Timeout: timeout}
 	if skipProxyForClearNetTargets {
 		host, _, err := net.SplitHostPort(address)
@@ -164,10 +167,20 @@ func LookupSRV(service, proto, name, socksAddr,
 	dnsServer string, streamIsolation bool, skipProxyForClearNetTargets bool,
 	timeout time.Duration) (string, []*net.SRV, error) {
 
+	policy := IsolationNone
+	if streamIsolation {
+		policy = IsolationPerConnection
+	}
+
+	auth, err := isolationAuth(policy, "", dnsServer)
+	if err != nil {
+		return "", nil, err
+	}
+
 	// Connect to the DNS server we'll be using to query SRV records.
 	conn, err := dialProxy(
-		dnsServer, socksAddr, streamIsolation,
-		skipProxyForClearNetTargets, timeout,
+		dnsServer, socksAddr, auth, skipProxyForClearNetTargets,
+		timeout,
 	)
 	if err != nil {
 		return "", nil, err
@@ -0,0 +1,49 @@
+package trampoline
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// FeePolicy is the policy that a trampoline node charges for relaying a
+// payment to the next trampoline node.
+type FeePolicy struct {
+	// BaseFee is the flat fee charged for every relayed payment.
+	BaseFee lnwire.MilliSatoshi
+
+	// FeeRate is the proportional fee charged in parts per million of the
+	// forwarded amount.
+	FeeRate lnwire.MilliSatoshi
+
+	// CltvDelta is the minimum difference between the incoming and the
+	// outgoing CLTV that the trampoline node requires.
+	CltvDelta uint32
+}
+
+// Fee returns the fee that the trampoline node charges for forwarding the
+// given amount.
+func (f *FeePolicy) Fee(amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+	return f.BaseFee + amt*f.FeeRate/1_000_000
+}
+
+// CheckForward verifies that the incoming amount and CLTV of a trampoline
+// payment leave enough room for the fee and CLTV delta of the policy. The
+// remaining budget can then be used to route to the next trampoline node.
+func (f *FeePolicy) CheckForward(incomingAmt lnwire.MilliSatoshi,
+	incomingCltv uint32, payload *Payload) error {
+
+	requiredAmt := payload.AmtToForward + f.Fee(payload.AmtToForward)
+	if incomingAmt < requiredAmt {
+		return fmt.Errorf("incoming amount %v below required %v for "+
+			"trampoline forward", incomingAmt, requiredAmt)
+	}
+
+	requiredCltv := payload.OutgoingCltv + f.CltvDelta
+	if incomingCltv < requiredCltv {
+		return fmt.Errorf("incoming cltv %v below required %v for "+
+			"trampoline forward", incomingCltv, requiredCltv)
+	}
+
+	return nil
+}
@@ -0,0 +1,444 @@
+package trampoline
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	// DefaultRoutingInfoSize is the size of the routing info of the
+	// trampoline onions that we create. It is considerably smaller than
+	// the routing info of a regular onion, as a trampoline onion needs to
+	// fit into the final hop payload of the outer onion.
+	DefaultRoutingInfoSize = 400
+
+	// onionVersion is the version of the trampoline onion packets that
+	// we create and understand.
+	onionVersion = 0
+
+	// hmacSize is the size of the HMACs used to verify the integrity of
+	// the onion.
+	hmacSize = sphinx.HMACSize
+
+	// keyLen is the size of the keys that are derived from the shared
+	// secret of a hop.
+	keyLen = 32
+
+	// onionOverhead is the number of bytes that an encoded trampoline
+	// onion occupies on top of its routing info: the version byte, the
+	// ephemeral public key and the HMAC.
+	onionOverhead = 1 + btcec.PubKeyBytesLenCompressed + hmacSize
+)
+
+var (
+	// ErrInvalidOnionVersion is returned when a trampoline onion with an
+	// unknown version is processed.
+	ErrInvalidOnionVersion = errors.New("invalid trampoline onion version")
+
+	// ErrInvalidOnionHMAC is returned when the HMAC of a trampoline onion
+	// doesn't match the one that we calculate.
+	ErrInvalidOnionHMAC = errors.New("invalid trampoline onion hmac")
+
+	// ErrOnionTooLarge is returned when the payloads of a route don't fit
+	// into the routing info of a trampoline onion.
+	ErrOnionTooLarge = errors.New("trampoline hop payloads exceed " +
+		"routing info size")
+
+	// zeroHMAC is the HMAC that signals the final hop of the onion.
+	zeroHMAC [hmacSize]byte
+)
+
+// OnionHop is a single hop of a trampoline route, consisting of the public key
+// of the trampoline node and the payload that is destined for it.
+type OnionHop struct {
+	// NodeID is the public key of the trampoline node.
+	NodeID *btcec.PublicKey
+
+	// Payload is the TLV encoded payload that is destined for the node.
+	Payload []byte
+}
+
+// OnionPacket is a trampoline onion packet. It uses the same construction as
+// a regular sphinx onion, but its routing info has a variable size.
+type OnionPacket struct {
+	// Version is the version of the packet.
+	Version byte
+
+	// EphemeralKey is the ephemeral public key that the processing node
+	// uses to derive its shared secret.
+	EphemeralKey *btcec.PublicKey
+
+	// RoutingInfo is the encrypted routing info of the packet.
+	RoutingInfo []byte
+
+	// HeaderMAC is the HMAC over the routing info and the associated data
+	// of the packet.
+	HeaderMAC [hmacSize]byte
+}
+
+// NewOnionPacket creates a trampoline onion packet with a routing info of
+// the given size that routes the given payloads to the trampoline hops. The
+// associated data is committed to in the HMAC of each layer and must be
+// provided again when the packet is processed.
+func NewOnionPacket(hops []OnionHop, sessionKey *btcec.PrivateKey,
+	assocData []byte, routingInfoSize int) (*OnionPacket, error) {
+
+	if len(hops) == 0 {
+		return nil, fmt.Errorf("route of length zero passed in")
+	}
+
+	payloads := make([]sphinx.HopPayload, len(hops))
+	nodeKeys := make([]*btcec.PublicKey, len(hops))
+
+	var totalSize int
+	for i, hop := range hops {
+		payload, err := sphinx.NewTLVHopPayload(hop.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		payloads[i] = payload
+		nodeKeys[i] = hop.NodeID
+		totalSize += payload.NumBytes()
+	}
+
+	if totalSize > routingInfoSize {
+		return nil, ErrOnionTooLarge
+	}
+
+	sharedSecrets, err := senderSharedSecrets(nodeKeys, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the filler that ensures the HMACs of the routing info
+	// remain valid as each hop strips off its payload.
+	filler := generateFiller(payloads, sharedSecrets, routingInfoSize)
+
+	// Fill the routing info with pseudo-random bytes derived from the
+	// session key, so that the packet doesn't leak the route length.
+	var sessionKeyBytes sphinx.Hash256
+	copy(sessionKeyBytes[:], sessionKey.Serialize())
+	mixHeader := generateCipherStream(
+		generateKey("pad", &sessionKeyBytes), routingInfoSize,
+	)
+
+	var (
+		nextHmac   [hmacSize]byte
+		payloadBuf bytes.Buffer
+	)
+	for i := len(hops) - 1; i >= 0; i-- {
+		rhoKey := generateKey("rho", &sharedSecrets[i])
+		muKey := generateKey("mu", &sharedSecrets[i])
+
+		// Shift the routing info to make room for the payload of this
+		// hop, which is followed by the HMAC for the next hop.
+		payloads[i].HMAC = nextHmac
+		shiftSize := payloads[i].NumBytes()
+		copy(
+			mixHeader[shiftSize:],
+			mixHeader[:len(mixHeader)-shiftSize],
+		)
+
+		payloadBuf.Reset()
+		if err := payloads[i].Encode(&payloadBuf); err != nil {
+			return nil, err
+		}
+		copy(mixHeader, payloadBuf.Bytes())
+
+		streamBytes := generateCipherStream(rhoKey, routingInfoSize)
+		xor(mixHeader, mixHeader, streamBytes)
+
+		if i == len(hops)-1 {
+			copy(mixHeader[len(mixHeader)-len(filler):], filler)
+		}
+
+		nextHmac = calcMac(muKey, append(mixHeader, assocData...))
+	}
+
+	return &OnionPacket{
+		Version:      onionVersion,
+		EphemeralKey: sessionKey.PubKey(),
+		RoutingInfo:  mixHeader,
+		HeaderMAC:    nextHmac,
+	}, nil
+}
+
+// Encode serializes the onion packet into the passed writer.
+func (o *OnionPacket) Encode(w io.Writer) error {
+	if _, err := w.Write([]byte{o.Version}); err != nil {
+		return err
+	}
+
+	_, err := w.Write(o.EphemeralKey.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(o.RoutingInfo); err != nil {
+		return err
+	}
+
+	_, err = w.Write(o.HeaderMAC[:])
+
+	return err
+}
+
+// DecodeOnionPacket deserializes an encoded trampoline onion packet. The size
+// of the routing info is derived from the length of the encoded packet.
+func DecodeOnionPacket(b []byte) (*OnionPacket, error) {
+	if len(b) <= onionOverhead {
+		return nil, fmt.Errorf("trampoline onion of %d bytes too "+
+			"short", len(b))
+	}
+
+	if b[0] != onionVersion {
+		return nil, ErrInvalidOnionVersion
+	}
+
+	keyEnd := 1 + btcec.PubKeyBytesLenCompressed
+	ephemeralKey, err := btcec.ParsePubKey(b[1:keyEnd])
+	if err != nil {
+		return nil, err
+	}
+
+	macStart := len(b) - hmacSize
+	routingInfo := make([]byte, macStart-keyEnd)
+	copy(routingInfo, b[keyEnd:macStart])
+
+	pkt := &OnionPacket{
+		Version:      b[0],
+		EphemeralKey: ephemeralKey,
+		RoutingInfo:  routingInfo,
+	}
+	copy(pkt.HeaderMAC[:], b[macStart:])
+
+	return pkt, nil
+}
+
+// ProcessedPacket is the result of peeling a layer off a trampoline onion.
+type ProcessedPacket struct {
+	// Payload is the TLV encoded payload destined for the processing
+	// node.
+	Payload []byte
+
+	// NextPacket is the onion packet that must be forwarded to the next
+	// trampoline node. It is nil if the processing node is the final hop.
+	NextPacket *OnionPacket
+}
+
+// IsFinal returns true if the processing node is the final hop of the
+// trampoline route.
+func (p *ProcessedPacket) IsFinal() bool {
+	return p.NextPacket == nil
+}
+
+// ProcessOnionPacket peels a layer off the trampoline onion using the node
+// key of the processing node and returns the payload destined for the node
+// along with the packet for the next trampoline node.
+func ProcessOnionPacket(nodeKey sphinx.SingleKeyECDH, pkt *OnionPacket,
+	assocData []byte) (*ProcessedPacket, error) {
+
+	if pkt.Version != onionVersion {
+		return nil, ErrInvalidOnionVersion
+	}
+
+	sharedSecret, err := nodeKey.ECDH(pkt.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	ss := sphinx.Hash256(sharedSecret)
+
+	// Verify the integrity of the routing info before we decrypt it.
+	message := append(append([]byte{}, pkt.RoutingInfo...), assocData...)
+	mac := calcMac(generateKey("mu", &ss), message)
+	if !hmac.Equal(mac[:], pkt.HeaderMAC[:]) {
+		return nil, ErrInvalidOnionHMAC
+	}
+
+	// Decrypt the routing info, extended with zero bytes so that the
+	// routing info of the next packet keeps the same size.
+	size := len(pkt.RoutingInfo)
+	hopInfo := make([]byte, 2*size)
+	copy(hopInfo, pkt.RoutingInfo)
+	streamBytes := generateCipherStream(generateKey("rho", &ss), 2*size)
+	xor(hopInfo, hopInfo, streamBytes)
+
+	var payload sphinx.HopPayload
+	if err := payload.Decode(bytes.NewReader(hopInfo)); err != nil {
+		return nil, err
+	}
+
+	processed := &ProcessedPacket{
+		Payload: payload.Payload,
+	}
+
+	// A zero HMAC signals that we're the final trampoline hop.
+	if payload.HMAC == zeroHMAC {
+		return processed, nil
+	}
+
+	nextRoutingInfo := make([]byte, size)
+	copy(nextRoutingInfo, hopInfo[payload.NumBytes():])
+
+	processed.NextPacket = &OnionPacket{
+		Version: pkt.Version,
+		EphemeralKey: blindGroupElement(
+			pkt.EphemeralKey,
+			computeBlindingFactor(pkt.EphemeralKey, &ss),
+		),
+		RoutingInfo: nextRoutingInfo,
+		HeaderMAC:   payload.HMAC,
+	}
+
+	return processed, nil
+}
+
+// senderSharedSecrets derives the shared secrets with each of the hops of the
+// route from the session key of the onion.
+func senderSharedSecrets(nodeKeys []*btcec.PublicKey,
+	sessionKey *btcec.PrivateKey) ([]sphinx.Hash256, error) {
+
+	secrets := make([]sphinx.Hash256, len(nodeKeys))
+
+	// The ephemeral private key is blinded after every hop, so that each
+	// hop sees a different ephemeral public key.
+	var ephemeralKey btcec.ModNScalar
+	ephemeralKey.Set(&sessionKey.Key)
+
+	for i, nodeKey := range nodeKeys {
+		ecdh := &sphinx.PrivKeyECDH{
+			PrivKey: btcec.PrivKeyFromScalar(&ephemeralKey),
+		}
+
+		secret, err := ecdh.ECDH(nodeKey)
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = secret
+
+		blindingFactor := computeBlindingFactor(
+			ecdh.PubKey(), &secrets[i],
+		)
+		ephemeralKey.Mul(&blindingFactor)
+	}
+
+	return secrets, nil
+}
+
+// generateFiller generates the filler bytes that are placed at the end of the
+// routing info of the final hop, so that the HMACs of all hops remain valid
+// while each hop strips off its own payload.
+func generateFiller(payloads []sphinx.HopPayload,
+	sharedSecrets []sphinx.Hash256, routingInfoSize int) []byte {
+
+	numHops := len(payloads)
+
+	var fillerSize int
+	for _, payload := range payloads[:numHops-1] {
+		fillerSize += payload.NumBytes()
+	}
+	filler := make([]byte, fillerSize)
+
+	fillerStart := routingInfoSize
+	for i := 0; i < numHops-1; i++ {
+		fillerEnd := routingInfoSize + payloads[i].NumBytes()
+
+		streamBytes := generateCipherStream(
+			generateKey("rho", &sharedSecrets[i]),
+			2*routingInfoSize,
+		)
+		xor(filler, filler, streamBytes[fillerStart:fillerEnd])
+
+		fillerStart -= payloads[i].NumBytes()
+	}
+
+	return filler
+}
+
+// generateKey derives a key of the given type from a shared secret.
+func generateKey(keyType string, sharedSecret *sphinx.Hash256) [keyLen]byte {
+	mac := hmac.New(sha256.New, []byte(keyType))
+	mac.Write(sharedSecret[:])
+
+	var key [keyLen]byte
+	copy(key[:], mac.Sum(nil))
+
+	return key
+}
+
+// generateCipherStream generates a stream of pseudo-random bytes using
+// chacha20 with an all-zero nonce.
+func generateCipherStream(key [keyLen]byte, numBytes int) []byte {
+	var nonce [chacha20.NonceSize]byte
+	cipher, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	if err != nil {
+		// This can only happen if the key or nonce have an invalid
+		// size, which they never have.
+		panic(err)
+	}
+
+	stream := make([]byte, numBytes)
+	cipher.XORKeyStream(stream, stream)
+
+	return stream
+}
+
+// calcMac calculates the HMAC-SHA256 of the message using the given key.
+func calcMac(key [keyLen]byte, msg []byte) [hmacSize]byte {
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(msg)
+
+	var result [hmacSize]byte
+	copy(result[:], mac.Sum(nil))
+
+	return result
+}
+
+// computeBlindingFactor computes the factor that is used to blind the
+// ephemeral key for the next hop: sha256(ephemeralKey || sharedSecret).
+func computeBlindingFactor(ephemeralKey *btcec.PublicKey,
+	sharedSecret *sphinx.Hash256) btcec.ModNScalar {
+
+	h := sha256.New()
+	h.Write(ephemeralKey.SerializeCompressed())
+	h.Write(sharedSecret[:])
+
+	var factor btcec.ModNScalar
+	factor.SetByteSlice(h.Sum(nil))
+
+	return factor
+}
+
+// blindGroupElement multiplies the given point by the blinding factor.
+func blindGroupElement(point *btcec.PublicKey,
+	factor btcec.ModNScalar) *btcec.PublicKey {
+
+	var pointJ, result btcec.JacobianPoint
+	point.AsJacobian(&pointJ)
+	btcec.ScalarMultNonConst(&factor, &pointJ, &result)
+	result.ToAffine()
+
+	return btcec.NewPublicKey(&result.X, &result.Y)
+}
+
+// xor computes the byte wise XOR of a and b and stores the result in dst.
+// Only the first min(len(a), len(b)) bytes are processed.
+func xor(dst, a, b []byte) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+
+	for i := 0; i < n; i++ {
+		dst[i] = a[i] ^ b[i]
+	}
+}
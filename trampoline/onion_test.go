@@ -0,0 +1,186 @@
+package trampoline
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnionRoundTrip asserts that a trampoline onion can be built, encoded and
+// peeled by every trampoline hop, each of which recovers its own payload.
+func TestOnionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 3
+
+	var (
+		nodeKeys = make([]*btcec.PrivateKey, numHops)
+		payloads = make([]*Payload, numHops)
+		hops     = make([]OnionHop, numHops)
+	)
+	for i := 0; i < numHops; i++ {
+		key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		nodeKeys[i] = key
+	}
+
+	for i := 0; i < numHops; i++ {
+		payload := &Payload{
+			AmtToForward: lnwire.MilliSatoshi(10_000 - i*100),
+			OutgoingCltv: uint32(800 - i*40),
+		}
+		if i < numHops-1 {
+			payload.OutgoingNodeID = nodeKeys[i+1].PubKey()
+		} else {
+			payload.MPP = record.NewMPP(
+				payload.AmtToForward, [32]byte{1, 2, 3},
+			)
+		}
+		payloads[i] = payload
+
+		encoded, err := payload.Encode()
+		require.NoError(t, err)
+
+		hops[i] = OnionHop{
+			NodeID:  nodeKeys[i].PubKey(),
+			Payload: encoded,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	assocData := bytes.Repeat([]byte{0x42}, 32)
+	pkt, err := NewOnionPacket(
+		hops, sessionKey, assocData, DefaultRoutingInfoSize,
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < numHops; i++ {
+		// Each hop receives the packet in its encoded form.
+		var b bytes.Buffer
+		require.NoError(t, pkt.Encode(&b))
+		require.Len(t, b.Bytes(), onionOverhead+DefaultRoutingInfoSize)
+
+		pkt, err = DecodeOnionPacket(b.Bytes())
+		require.NoError(t, err)
+
+		processed, err := ProcessOnionPacket(
+			&sphinx.PrivKeyECDH{PrivKey: nodeKeys[i]}, pkt,
+			assocData,
+		)
+		require.NoError(t, err)
+
+		isFinal := i == numHops-1
+		require.Equal(t, isFinal, processed.IsFinal())
+
+		payload, err := DecodePayload(processed.Payload, isFinal)
+		require.NoError(t, err)
+		require.Equal(t, payloads[i], payload)
+
+		pkt = processed.NextPacket
+	}
+}
+
+// TestOnionInvalidHMAC asserts that a trampoline onion that was tampered with
+// or is processed with the wrong associated data is rejected.
+func TestOnionInvalidHMAC(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	payload := &Payload{AmtToForward: 1000, OutgoingCltv: 100}
+	encoded, err := payload.Encode()
+	require.NoError(t, err)
+
+	hops := []OnionHop{{NodeID: nodeKey.PubKey(), Payload: encoded}}
+	pkt, err := NewOnionPacket(hops, sessionKey, nil, 100)
+	require.NoError(t, err)
+
+	ecdh := &sphinx.PrivKeyECDH{PrivKey: nodeKey}
+
+	_, err = ProcessOnionPacket(ecdh, pkt, []byte{1})
+	require.ErrorIs(t, err, ErrInvalidOnionHMAC)
+
+	pkt.RoutingInfo[0] ^= 0x01
+	_, err = ProcessOnionPacket(ecdh, pkt, nil)
+	require.ErrorIs(t, err, ErrInvalidOnionHMAC)
+}
+
+// TestOnionTooLarge asserts that payloads that don't fit into the routing info
+// are rejected.
+func TestOnionTooLarge(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	hops := []OnionHop{{
+		NodeID:  nodeKey.PubKey(),
+		Payload: make([]byte, 100),
+	}}
+	_, err = NewOnionPacket(hops, sessionKey, nil, 100)
+	require.ErrorIs(t, err, ErrOnionTooLarge)
+}
+
+// TestOnionMatchesSphinx asserts that a trampoline onion with the routing info
+// size of a regular onion is identical to the onion created by the sphinx
+// package, which shows that both use the same construction.
+func TestOnionMatchesSphinx(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numHops         = 4
+		routingInfoSize = 1300
+	)
+
+	var (
+		path sphinx.PaymentPath
+		hops = make([]OnionHop, numHops)
+	)
+	for i := 0; i < numHops; i++ {
+		key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		payload := bytes.Repeat([]byte{byte(i + 1)}, 20+i*10)
+		hopPayload, err := sphinx.NewTLVHopPayload(payload)
+		require.NoError(t, err)
+
+		path[i] = sphinx.OnionHop{
+			NodePub:    *key.PubKey(),
+			HopPayload: hopPayload,
+		}
+		hops[i] = OnionHop{NodeID: key.PubKey(), Payload: payload}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	assocData := bytes.Repeat([]byte{0x11}, 32)
+
+	sphinxPkt, err := sphinx.NewOnionPacket(
+		&path, sessionKey, assocData, sphinx.DeterministicPacketFiller,
+	)
+	require.NoError(t, err)
+
+	pkt, err := NewOnionPacket(hops, sessionKey, assocData, routingInfoSize)
+	require.NoError(t, err)
+
+	var sphinxBytes, trampolineBytes bytes.Buffer
+	require.NoError(t, sphinxPkt.Encode(&sphinxBytes))
+	require.NoError(t, pkt.Encode(&trampolineBytes))
+
+	require.Equal(t, sphinxBytes.Bytes(), trampolineBytes.Bytes())
+}
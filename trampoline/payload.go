@@ -0,0 +1,142 @@
+package trampoline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrMissingOutgoingNodeID is returned when an intermediate trampoline
+	// payload doesn't specify the next trampoline node.
+	ErrMissingOutgoingNodeID = errors.New("intermediate trampoline " +
+		"payload missing outgoing node id")
+
+	// ErrUnexpectedOutgoingNodeID is returned when the final trampoline
+	// payload specifies a next trampoline node.
+	ErrUnexpectedOutgoingNodeID = errors.New("final trampoline payload " +
+		"contains outgoing node id")
+)
+
+// Payload is the payload that a trampoline node finds in its layer of the
+// trampoline onion.
+type Payload struct {
+	// AmtToForward is the amount that the trampoline node should deliver
+	// to the next trampoline node, or the amount that the recipient should
+	// receive if this is the final payload.
+	AmtToForward lnwire.MilliSatoshi
+
+	// OutgoingCltv is the CLTV value that the next trampoline node must
+	// receive, or the final CLTV of the payment if this is the final
+	// payload.
+	OutgoingCltv uint32
+
+	// OutgoingNodeID is the public key of the next trampoline node. It is
+	// nil for the final payload.
+	OutgoingNodeID *btcec.PublicKey
+
+	// MPP holds the payment address and total amount of the payment. It
+	// is only set in the final payload.
+	MPP *record.MPP
+}
+
+// IsFinal returns true if the payload is destined for the recipient of the
+// payment.
+func (p *Payload) IsFinal() bool {
+	return p.OutgoingNodeID == nil
+}
+
+// Encode serializes the payload into a TLV stream.
+func (p *Payload) Encode() ([]byte, error) {
+	amt := uint64(p.AmtToForward)
+	records := []tlv.Record{
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&p.OutgoingCltv),
+	}
+
+	if p.MPP != nil {
+		records = append(records, p.MPP.Record())
+	}
+
+	if p.OutgoingNodeID != nil {
+		records = append(
+			records, record.NewOutgoingNodeIDRecord(
+				&p.OutgoingNodeID,
+			),
+		)
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodePayload parses a trampoline payload from the given TLV stream. The
+// isFinal flag indicates whether the payload was found in the final layer of
+// the trampoline onion.
+func DecodePayload(b []byte, isFinal bool) (*Payload, error) {
+	var (
+		amt            uint64
+		cltv           uint32
+		outgoingNodeID *btcec.PublicKey
+		mpp            = &record.MPP{}
+	)
+
+	stream, err := tlv.NewStream(
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&cltv),
+		mpp.Record(),
+		record.NewOutgoingNodeIDRecord(&outgoingNodeID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(b),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parsedTypes[record.AmtOnionType]; !ok {
+		return nil, fmt.Errorf("trampoline payload missing amount")
+	}
+
+	if _, ok := parsedTypes[record.LockTimeOnionType]; !ok {
+		return nil, fmt.Errorf("trampoline payload missing cltv")
+	}
+
+	_, hasNodeID := parsedTypes[record.OutgoingNodeIDOnionType]
+	switch {
+	case isFinal && hasNodeID:
+		return nil, ErrUnexpectedOutgoingNodeID
+
+	case !isFinal && !hasNodeID:
+		return nil, ErrMissingOutgoingNodeID
+	}
+
+	if _, ok := parsedTypes[record.MPPOnionType]; !ok {
+		mpp = nil
+	}
+
+	return &Payload{
+		AmtToForward:   lnwire.MilliSatoshi(amt),
+		OutgoingCltv:   cltv,
+		OutgoingNodeID: outgoingNodeID,
+		MPP:            mpp,
+	}, nil
+}
@@ -0,0 +1,248 @@
+package lnd
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	base "github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+// walletBalancePollInterval is the interval at which the balance is
+// recomputed for balance subscriptions, to catch changes that don't come with
+// a transaction or block, like expiring leases.
+const walletBalancePollInterval = time.Minute
+
+// balanceWallet is the part of the wallet that is needed to compute the
+// balance breakdown of the wallet accounts.
+type balanceWallet interface {
+	// ListAccounts lists all wallet accounts matching the given filters.
+	ListAccounts(name string,
+		keyScope *waddrmgr.KeyScope) ([]*waddrmgr.AccountProperties,
+		error)
+
+	// ConfirmedBalance returns the balance of an account with at least
+	// the given number of confirmations.
+	ConfirmedBalance(confs int32, accountFilter string) (btcutil.Amount,
+		error)
+
+	// ListUnspentWitness returns the unspent witness outputs of an
+	// account.
+	ListUnspentWitness(minConfs, maxConfs int32,
+		accountFilter string) ([]*lnwallet.Utxo, error)
+
+	// ListLeasedOutputs returns all currently leased outputs.
+	ListLeasedOutputs() ([]*base.ListLeasedOutputResult, error)
+
+	// RequiredReserve returns the reserve the wallet keeps for fee
+	// bumping the given number of anchor channels.
+	RequiredReserve(numAnchorChans uint32) btcutil.Amount
+
+	// CurrentNumAnchorChans returns the number of anchor channels the
+	// wallet needs a reserve for.
+	CurrentNumAnchorChans() (int, error)
+}
+
+// AccountBalance is the on-chain balance of a single wallet account.
+type AccountBalance struct {
+	// Account is the name of the account.
+	Account string
+
+	// ConfirmedBalance is the balance of the outputs with at least the
+	// requested number of confirmations.
+	ConfirmedBalance btcutil.Amount
+
+	// UnconfirmedBalance is the balance of the outputs with fewer
+	// confirmations.
+	UnconfirmedBalance btcutil.Amount
+
+	// LockedBalance is the part of the balance that is leased, for
+	// example by a pending channel funding or PSBT.
+	LockedBalance btcutil.Amount
+
+	// ReservedBalanceAnchorChan is the part of the balance that is kept
+	// as reserve to fee bump the anchor channels. It is only set for the
+	// default account, as the reserve is taken from there.
+	ReservedBalanceAnchorChan btcutil.Amount
+}
+
+// WalletBalanceUpdate is the on-chain balance of the wallet broken down by
+// account.
+type WalletBalanceUpdate struct {
+	// Accounts are the balances of the individual accounts, sorted by
+	// name.
+	Accounts []*AccountBalance
+
+	// ConfirmedBalance is the confirmed balance of all accounts.
+	ConfirmedBalance btcutil.Amount
+
+	// UnconfirmedBalance is the unconfirmed balance of all accounts.
+	UnconfirmedBalance btcutil.Amount
+
+	// LockedBalance is the leased balance of all accounts.
+	LockedBalance btcutil.Amount
+
+	// ReservedBalanceAnchorChan is the reserve kept to fee bump the
+	// anchor channels.
+	ReservedBalanceAnchorChan btcutil.Amount
+}
+
+// TotalBalance returns the confirmed and unconfirmed balance of all accounts.
+func (u *WalletBalanceUpdate) TotalBalance() btcutil.Amount {
+	return u.ConfirmedBalance + u.UnconfirmedBalance
+}
+
+// equal returns true if both updates report the same balances.
+func (u *WalletBalanceUpdate) equal(other *WalletBalanceUpdate) bool {
+	if other == nil || len(u.Accounts) != len(other.Accounts) {
+		return false
+	}
+
+	for i, account := range u.Accounts {
+		if *account != *other.Accounts[i] {
+			return false
+		}
+	}
+
+	return u.ConfirmedBalance == other.ConfirmedBalance &&
+		u.UnconfirmedBalance == other.UnconfirmedBalance &&
+		u.LockedBalance == other.LockedBalance &&
+		u.ReservedBalanceAnchorChan == other.ReservedBalanceAnchorChan
+}
+
+// walletBalanceUpdate computes the balance breakdown of the accounts matching
+// the account filter. An empty filter includes all accounts.
+func walletBalanceUpdate(wallet balanceWallet, accountFilter string,
+	minConfs int32) (*WalletBalanceUpdate, error) {
+
+	if minConfs <= 0 {
+		minConfs = 1
+	}
+
+	accounts, err := wallet.ListAccounts(accountFilter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := wallet.ListLeasedOutputs()
+	if err != nil {
+		return nil, err
+	}
+	leased := make(map[wire.OutPoint]struct{}, len(leases))
+	for _, lease := range leases {
+		leased[lease.Outpoint] = struct{}{}
+	}
+
+	numAnchorChans, err := wallet.CurrentNumAnchorChans()
+	if err != nil {
+		return nil, err
+	}
+	reserve := wallet.RequiredReserve(uint32(numAnchorChans))
+
+	update := &WalletBalanceUpdate{}
+	seen := make(map[string]struct{}, len(accounts))
+	for _, account := range accounts {
+		// The default and imported accounts exist in multiple key
+		// scopes, but their balance is computed across all of them.
+		// The accounts of the channel keys never have a balance.
+		name := account.AccountName
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		if account.KeyScope.Purpose == keychain.BIP0043Purpose {
+			continue
+		}
+		if name == waddrmgr.ImportedAddrAccountName &&
+			account.ImportedKeyCount == 0 {
+
+			continue
+		}
+		seen[name] = struct{}{}
+
+		total, err := wallet.ConfirmedBalance(0, name)
+		if err != nil {
+			return nil, err
+		}
+		confirmed, err := wallet.ConfirmedBalance(minConfs, name)
+		if err != nil {
+			return nil, err
+		}
+
+		balance := &AccountBalance{
+			Account:            name,
+			ConfirmedBalance:   confirmed,
+			UnconfirmedBalance: total - confirmed,
+		}
+
+		if len(leased) > 0 {
+			utxos, err := wallet.ListUnspentWitness(
+				0, math.MaxInt32, name,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, utxo := range utxos {
+				if _, ok := leased[utxo.OutPoint]; ok {
+					balance.LockedBalance += utxo.Value
+				}
+			}
+		}
+
+		if name == lnwallet.DefaultAccountName {
+			balance.ReservedBalanceAnchorChan = reserve
+			update.ReservedBalanceAnchorChan = reserve
+		}
+
+		update.Accounts = append(update.Accounts, balance)
+		update.ConfirmedBalance += balance.ConfirmedBalance
+		update.UnconfirmedBalance += balance.UnconfirmedBalance
+		update.LockedBalance += balance.LockedBalance
+	}
+
+	sort.Slice(update.Accounts, func(i, j int) bool {
+		return update.Accounts[i].Account < update.Accounts[j].Account
+	})
+
+	return update, nil
+}
+
+// marshalWalletBalanceUpdate converts a balance update to its RPC
+// representation.
+func marshalWalletBalanceUpdate(
+	update *WalletBalanceUpdate) *lnrpc.WalletBalanceUpdate {
+
+	reserve := update.ReservedBalanceAnchorChan
+	rpcUpdate := &lnrpc.WalletBalanceUpdate{
+		Accounts: make(
+			[]*lnrpc.AccountBalanceDetail, 0, len(update.Accounts),
+		),
+		TotalBalance:              int64(update.TotalBalance()),
+		ConfirmedBalance:          int64(update.ConfirmedBalance),
+		UnconfirmedBalance:        int64(update.UnconfirmedBalance),
+		LockedBalance:             int64(update.LockedBalance),
+		ReservedBalanceAnchorChan: int64(reserve),
+	}
+
+	for _, a := range update.Accounts {
+		rpcUpdate.Accounts = append(
+			rpcUpdate.Accounts, &lnrpc.AccountBalanceDetail{
+				Account:            a.Account,
+				ConfirmedBalance:   int64(a.ConfirmedBalance),
+				UnconfirmedBalance: int64(a.UnconfirmedBalance),
+				LockedBalance:      int64(a.LockedBalance),
+				ReservedBalanceAnchorChan: int64(
+					a.ReservedBalanceAnchorChan,
+				),
+			},
+		)
+	}
+
+	return rpcUpdate
+}
@@ -0,0 +1,211 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	base "github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBalanceWallet is a balanceWallet with fixed balances per account.
+type mockBalanceWallet struct {
+	accounts    []*waddrmgr.AccountProperties
+	total       map[string]btcutil.Amount
+	confirmed   map[string]btcutil.Amount
+	utxos       map[string][]*lnwallet.Utxo
+	leases      []*base.ListLeasedOutputResult
+	anchorChans int
+}
+
+func (m *mockBalanceWallet) ListAccounts(name string,
+	_ *waddrmgr.KeyScope) ([]*waddrmgr.AccountProperties, error) {
+
+	var accounts []*waddrmgr.AccountProperties
+	for _, account := range m.accounts {
+		if name == "" || account.AccountName == name {
+			accounts = append(accounts, account)
+		}
+	}
+
+	return accounts, nil
+}
+
+func (m *mockBalanceWallet) ConfirmedBalance(confs int32,
+	account string) (btcutil.Amount, error) {
+
+	if confs == 0 {
+		return m.total[account], nil
+	}
+
+	return m.confirmed[account], nil
+}
+
+func (m *mockBalanceWallet) ListUnspentWitness(_, _ int32,
+	account string) ([]*lnwallet.Utxo, error) {
+
+	return m.utxos[account], nil
+}
+
+func (m *mockBalanceWallet) ListLeasedOutputs() (
+	[]*base.ListLeasedOutputResult, error) {
+
+	return m.leases, nil
+}
+
+func (m *mockBalanceWallet) RequiredReserve(
+	numAnchorChans uint32) btcutil.Amount {
+
+	return btcutil.Amount(numAnchorChans) * 10_000
+}
+
+func (m *mockBalanceWallet) CurrentNumAnchorChans() (int, error) {
+	return m.anchorChans, nil
+}
+
+// TestWalletBalanceUpdate tests that the balance is broken down correctly by
+// account.
+func TestWalletBalanceUpdate(t *testing.T) {
+	t.Parallel()
+
+	leasedOutpoint := wire.OutPoint{Hash: chainhash.Hash{1}}
+	channelScope := waddrmgr.KeyScope{
+		Purpose: keychain.BIP0043Purpose,
+	}
+
+	wallet := &mockBalanceWallet{
+		accounts: []*waddrmgr.AccountProperties{{
+			AccountName: lnwallet.DefaultAccountName,
+			KeyScope:    waddrmgr.KeyScopeBIP0084,
+		}, {
+			AccountName: lnwallet.DefaultAccountName,
+			KeyScope:    waddrmgr.KeyScopeBIP0086,
+		}, {
+			AccountName: "savings",
+			KeyScope:    waddrmgr.KeyScopeBIP0084,
+		}, {
+			AccountName: waddrmgr.ImportedAddrAccountName,
+			KeyScope:    waddrmgr.KeyScopeBIP0084,
+		}, {
+			AccountName: "1",
+			KeyScope:    channelScope,
+		}},
+		total: map[string]btcutil.Amount{
+			lnwallet.DefaultAccountName: 150_000,
+			"savings":                   70_000,
+		},
+		confirmed: map[string]btcutil.Amount{
+			lnwallet.DefaultAccountName: 100_000,
+			"savings":                   70_000,
+		},
+		utxos: map[string][]*lnwallet.Utxo{
+			"savings": {{
+				OutPoint: leasedOutpoint,
+				Value:    20_000,
+			}, {
+				OutPoint: wire.OutPoint{Index: 1},
+				Value:    50_000,
+			}},
+		},
+		leases: []*base.ListLeasedOutputResult{{
+			LockedOutput: &wtxmgr.LockedOutput{
+				Outpoint: leasedOutpoint,
+			},
+		}},
+		anchorChans: 2,
+	}
+
+	update, err := walletBalanceUpdate(wallet, "", 1)
+	require.NoError(t, err)
+
+	require.Equal(t, []*AccountBalance{{
+		Account:                   lnwallet.DefaultAccountName,
+		ConfirmedBalance:          100_000,
+		UnconfirmedBalance:        50_000,
+		ReservedBalanceAnchorChan: 20_000,
+	}, {
+		Account:          "savings",
+		ConfirmedBalance: 70_000,
+		LockedBalance:    20_000,
+	}}, update.Accounts)
+	require.Equal(t, btcutil.Amount(170_000), update.ConfirmedBalance)
+	require.Equal(t, btcutil.Amount(50_000), update.UnconfirmedBalance)
+	require.Equal(t, btcutil.Amount(220_000), update.TotalBalance())
+	require.Equal(t, btcutil.Amount(20_000), update.LockedBalance)
+	require.Equal(
+		t, btcutil.Amount(20_000), update.ReservedBalanceAnchorChan,
+	)
+
+	// An update with the same balances is equal, while a changed balance
+	// is not.
+	same, err := walletBalanceUpdate(wallet, "", 1)
+	require.NoError(t, err)
+	require.True(t, update.equal(same))
+
+	wallet.total["savings"] = 80_000
+	changed, err := walletBalanceUpdate(wallet, "", 1)
+	require.NoError(t, err)
+	require.False(t, update.equal(changed))
+	require.False(t, update.equal(nil))
+
+	// A filtered update only contains the filtered account and no anchor
+	// reserve, as it is taken from the default account.
+	filtered, err := walletBalanceUpdate(wallet, "savings", 1)
+	require.NoError(t, err)
+	require.Len(t, filtered.Accounts, 1)
+	require.Equal(t, "savings", filtered.Accounts[0].Account)
+	require.Zero(t, filtered.ReservedBalanceAnchorChan)
+}
+
+// TestMarshalWalletBalanceUpdate tests that a balance update is converted to
+// its RPC representation.
+func TestMarshalWalletBalanceUpdate(t *testing.T) {
+	t.Parallel()
+
+	update := &WalletBalanceUpdate{
+		Accounts: []*AccountBalance{{
+			Account:                   lnwallet.DefaultAccountName,
+			ConfirmedBalance:          100_000,
+			UnconfirmedBalance:        50_000,
+			ReservedBalanceAnchorChan: 20_000,
+		}, {
+			Account:          "savings",
+			ConfirmedBalance: 70_000,
+			LockedBalance:    20_000,
+		}},
+		ConfirmedBalance:          170_000,
+		UnconfirmedBalance:        50_000,
+		LockedBalance:             20_000,
+		ReservedBalanceAnchorChan: 20_000,
+	}
+
+	require.Equal(t, &lnrpc.WalletBalanceUpdate{
+		Accounts: []*lnrpc.AccountBalanceDetail{{
+			Account:                   lnwallet.DefaultAccountName,
+			ConfirmedBalance:          100_000,
+			UnconfirmedBalance:        50_000,
+			ReservedBalanceAnchorChan: 20_000,
+		}, {
+			Account:          "savings",
+			ConfirmedBalance: 70_000,
+			LockedBalance:    20_000,
+		}},
+		TotalBalance:              220_000,
+		ConfirmedBalance:          170_000,
+		UnconfirmedBalance:        50_000,
+		LockedBalance:             20_000,
+		ReservedBalanceAnchorChan: 20_000,
+	}, marshalWalletBalanceUpdate(update))
+
+	// An empty wallet has no accounts, but still a non-nil list.
+	empty := marshalWalletBalanceUpdate(&WalletBalanceUpdate{})
+	require.NotNil(t, empty.Accounts)
+	require.Empty(t, empty.Accounts)
+}
@@ -2774,6 +2774,78 @@ var clientTests = []clientTest{
 			h.server.waitForUpdates(hints[numUpdates-1:], waitTime)
 		},
 	},
+	{
+		// Show that the backups stored with a decommissioned tower are
+		// uploaded to the new tower when migrating to it.
+		name: "migrate tower",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 8
+				chanIDInt  = 0
+			)
+
+			// Back up the states of the channel to the old tower.
+			hints := h.advanceChannelN(chanIDInt, numUpdates)
+			h.backupStates(chanIDInt, 0, numUpdates, nil)
+			h.server.waitForUpdates(hints, waitTime)
+
+			// No migration was started yet.
+			_, err := h.clientMgr.MigrationStatus()
+			require.ErrorIs(h.t, err, wtclient.ErrNoTowerMigration)
+
+			// Migrating a tower to itself is refused.
+			err = h.clientMgr.MigrateTower(
+				h.server.addr.IdentityKey, h.server.addr,
+			)
+			require.Error(h.t, err)
+
+			// Migrate to a new tower and assert that it receives
+			// all the states backed up to the old tower.
+			server2 := newServerHarness(
+				h.t, h.net, towerAddr2Str, nil,
+			)
+			server2.start()
+
+			err = h.clientMgr.MigrateTower(
+				h.server.addr.IdentityKey, server2.addr,
+			)
+			require.NoError(h.t, err)
+
+			server2.waitForUpdates(hints, waitTime)
+
+			// The migration completes once all states are acked
+			// by the new tower.
+			err = wait.Predicate(func() bool {
+				status, err := h.clientMgr.MigrationStatus()
+				require.NoError(h.t, err)
+				require.NoError(h.t, status.Err)
+				require.Equal(
+					h.t, numUpdates, status.NumBackups,
+				)
+				require.Zero(h.t, status.NumSkipped)
+
+				return status.Complete()
+			}, waitTime)
+			require.NoError(h.t, err)
+
+			// The old tower was deactivated.
+			resp, err := h.clientMgr.LookupTower(
+				h.server.addr.IdentityKey,
+			)
+			require.NoError(h.t, err)
+			tower, ok := resp[blob.TypeAltruistTaprootCommit]
+			require.True(h.t, ok)
+			require.False(h.t, tower.ActiveSessionCandidate)
+		},
+	},
 	{
 		name: "terminate session",
 		cfg: harnessCfg{
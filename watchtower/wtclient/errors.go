@@ -39,4 +39,12 @@ var (
 	// create a new session with a tower with a session key that has already
 	// been used in the past.
 	ErrSessionKeyAlreadyUsed = errors.New("session key already used")
+
+	// ErrMigrationInProgress signals that a tower migration can't be
+	// started while the backups of another one are still being queued.
+	ErrMigrationInProgress = errors.New("tower migration in progress")
+
+	// ErrNoTowerMigration signals that no tower migration was started
+	// since startup.
+	ErrNoTowerMigration = errors.New("no tower migration started")
 )
@@ -74,6 +74,15 @@ type ClientManager interface {
 	// successful unless the justice transaction would create dust outputs
 	// when trying to abide by the negotiated policy.
 	BackupState(chanID *lnwire.ChannelID, stateNum uint64) error
+
+	// MigrateTower migrates the backups stored with a decommissioned tower
+	// to a new one by uploading the backed up states again.
+	MigrateTower(oldKey *btcec.PublicKey,
+		newTower *lnwire.NetAddress) error
+
+	// MigrationStatus returns the progress of the last tower migration
+	// started since startup.
+	MigrationStatus() (*TowerMigration, error)
 }
 
 // Config provides the client with access to the resources it requires to
@@ -174,6 +183,10 @@ type Manager struct {
 
 	closableSessionQueue *sessionCloseMinHeap
 
+	// migration is the last tower migration started since startup.
+	migration   *towerMigration
+	migrationMu sync.Mutex
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
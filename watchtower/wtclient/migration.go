@@ -0,0 +1,310 @@
+package wtclient
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+)
+
+// TowerMigration describes the progress of migrating the backups stored with
+// a decommissioned tower to a new one.
+type TowerMigration struct {
+	// OldTower is the identity key of the decommissioned tower.
+	OldTower *btcec.PublicKey
+
+	// NewTower is the identity key of the tower the backups are migrated
+	// to.
+	NewTower *btcec.PublicKey
+
+	// StartTime is the time the migration was started.
+	StartTime time.Time
+
+	// NumBackups is the number of backups of open channels that are
+	// migrated.
+	NumBackups int
+
+	// NumSkipped is the number of backups of closed channels, which are
+	// not migrated as they no longer need to be watched.
+	NumSkipped int
+
+	// NumQueued is the number of backups queued for upload so far.
+	NumQueued int
+
+	// NumBackedUp is the number of migrated backups that were
+	// acknowledged by a tower other than the old one.
+	NumBackedUp int
+
+	// Err is the error that aborted the queueing of the backups, if any.
+	Err error
+}
+
+// Complete returns true once all migrated backups were acknowledged by a tower
+// other than the old one, so the old tower can be removed without leaving a
+// protection gap.
+func (t *TowerMigration) Complete() bool {
+	return t.Err == nil && t.NumBackedUp == t.NumBackups
+}
+
+// towerMigration is the state of a migration started with MigrateTower.
+type towerMigration struct {
+	// status is the progress of the migration. Its NumBackedUp is
+	// computed on request.
+	status TowerMigration
+
+	// oldTowerID is the database ID of the decommissioned tower.
+	oldTowerID wtdb.TowerID
+
+	// backups are the migrated backups by the blob type of the client
+	// they are queued with.
+	backups map[blob.Type][]wtdb.BackupID
+
+	// done is closed once all backups are queued or the queueing failed.
+	done chan struct{}
+}
+
+// MigrateTower migrates the backups stored with a decommissioned tower to a
+// new one. The new tower is added and the old tower is deactivated, so that
+// its sessions aren't used anymore. Then the revoked states of all open
+// channels that were backed up to the old tower are queued again, which
+// re-encrypts them for new sessions and uploads them. The progress can be
+// followed with MigrationStatus. Once the migration is complete, the old tower
+// can be removed.
+//
+// NOTE: If other towers are active, some of the backups may be uploaded to
+// them instead of the new tower.
+func (m *Manager) MigrateTower(oldKey *btcec.PublicKey,
+	newTower *lnwire.NetAddress) error {
+
+	if bytes.Equal(oldKey.SerializeCompressed(),
+		newTower.IdentityKey.SerializeCompressed()) {
+
+		return fmt.Errorf("cannot migrate a tower to itself")
+	}
+
+	m.migrationMu.Lock()
+	defer m.migrationMu.Unlock()
+
+	if m.migration != nil {
+		select {
+		case <-m.migration.done:
+		default:
+			return ErrMigrationInProgress
+		}
+	}
+
+	oldTower, err := m.cfg.DB.LoadTower(oldKey)
+	if err != nil {
+		return err
+	}
+
+	// Gather both the acked and the committed updates of the old tower,
+	// as the committed ones won't be acked by it anymore.
+	backups := make(map[blob.Type]map[wtdb.BackupID]struct{})
+	addBackup := func(s *wtdb.ClientSession, id wtdb.BackupID) {
+		blobType := s.Policy.BlobType
+		if _, ok := backups[blobType]; !ok {
+			backups[blobType] = make(map[wtdb.BackupID]struct{})
+		}
+		backups[blobType][id] = struct{}{}
+	}
+
+	_, err = m.cfg.DB.ListClientSessions(
+		&oldTower.ID,
+		wtdb.WithPerAckedRanges(func(s *wtdb.ClientSession,
+			chanID lnwire.ChannelID, ranges map[uint64]uint64) {
+
+			for start, end := range ranges {
+				for height := start; height <= end; height++ {
+					addBackup(s, wtdb.BackupID{
+						ChanID:       chanID,
+						CommitHeight: height,
+					})
+				}
+			}
+		}),
+		wtdb.WithPerCommittedUpdate(func(s *wtdb.ClientSession,
+			update *wtdb.CommittedUpdate) {
+
+			addBackup(s, update.BackupID)
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to list sessions of tower %x: %w",
+			oldKey.SerializeCompressed(), err)
+	}
+
+	if err := m.AddTower(newTower); err != nil {
+		return fmt.Errorf("unable to add new tower: %w", err)
+	}
+
+	if err := m.DeactivateTower(oldKey); err != nil {
+		return fmt.Errorf("unable to deactivate old tower: %w", err)
+	}
+
+	migration := &towerMigration{
+		status: TowerMigration{
+			OldTower:  oldKey,
+			NewTower:  newTower.IdentityKey,
+			StartTime: time.Now(),
+		},
+		oldTowerID: oldTower.ID,
+		backups:    make(map[blob.Type][]wtdb.BackupID),
+		done:       make(chan struct{}),
+	}
+
+	// The states of closed channels don't need to be watched anymore.
+	// Their registration is removed once they are closed.
+	m.backupMu.Lock()
+	for blobType, ids := range backups {
+		for id := range ids {
+			if _, ok := m.chanInfos[id.ChanID]; !ok {
+				migration.status.NumSkipped++
+				continue
+			}
+
+			migration.backups[blobType] = append(
+				migration.backups[blobType], id,
+			)
+			migration.status.NumBackups++
+		}
+	}
+	m.backupMu.Unlock()
+
+	for _, ids := range migration.backups {
+		sort.Slice(ids, func(i, j int) bool {
+			if ids[i].ChanID != ids[j].ChanID {
+				return bytes.Compare(
+					ids[i].ChanID[:], ids[j].ChanID[:],
+				) < 0
+			}
+
+			return ids[i].CommitHeight < ids[j].CommitHeight
+		})
+	}
+
+	log.Infof("Migrating %d backups from tower %x to tower %x, skipping "+
+		"%d backups of closed channels", migration.status.NumBackups,
+		oldKey.SerializeCompressed(),
+		newTower.IdentityKey.SerializeCompressed(),
+		migration.status.NumSkipped)
+
+	m.migration = migration
+
+	m.wg.Add(1)
+	go m.queueMigratedBackups(migration)
+
+	return nil
+}
+
+// queueMigratedBackups queues the backups of a migration for upload.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) queueMigratedBackups(migration *towerMigration) {
+	defer m.wg.Done()
+	defer close(migration.done)
+
+	fail := func(err error) {
+		log.Errorf("Unable to migrate tower backups: %v", err)
+
+		m.migrationMu.Lock()
+		migration.status.Err = err
+		m.migrationMu.Unlock()
+	}
+
+	for blobType, ids := range migration.backups {
+		m.clientsMu.Lock()
+		client, ok := m.clients[blobType]
+		m.clientsMu.Unlock()
+		if !ok {
+			fail(fmt.Errorf("no client registered for blob type %s",
+				blobType))
+
+			return
+		}
+
+		for _, id := range ids {
+			select {
+			case <-m.quit:
+				fail(ErrClientExiting)
+				return
+			default:
+			}
+
+			err := client.backupState(&id.ChanID, id.CommitHeight)
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			m.migrationMu.Lock()
+			migration.status.NumQueued++
+			m.migrationMu.Unlock()
+		}
+	}
+
+	log.Infof("Queued all %d backups migrated from tower %x",
+		migration.status.NumBackups,
+		migration.status.OldTower.SerializeCompressed())
+}
+
+// MigrationStatus returns the progress of the last tower migration started
+// since startup.
+func (m *Manager) MigrationStatus() (*TowerMigration, error) {
+	m.migrationMu.Lock()
+	migration := m.migration
+	if migration == nil {
+		m.migrationMu.Unlock()
+
+		return nil, ErrNoTowerMigration
+	}
+	status := migration.status
+	m.migrationMu.Unlock()
+
+	// A migrated backup is complete once it was acked by a session of
+	// any tower other than the old one.
+	acked := make(map[lnwire.ChannelID][]map[uint64]uint64)
+	_, err := m.cfg.DB.ListClientSessions(
+		nil,
+		wtdb.WithPreEvalFilterFn(func(s *wtdb.ClientSession) bool {
+			return s.TowerID != migration.oldTowerID
+		}),
+		wtdb.WithPerAckedRanges(func(_ *wtdb.ClientSession,
+			chanID lnwire.ChannelID, ranges map[uint64]uint64) {
+
+			acked[chanID] = append(acked[chanID], ranges)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	isAcked := func(id wtdb.BackupID) bool {
+		for _, ranges := range acked[id.ChanID] {
+			for start, end := range ranges {
+				if id.CommitHeight >= start &&
+					id.CommitHeight <= end {
+
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	for _, ids := range migration.backups {
+		for _, id := range ids {
+			if isAcked(id) {
+				status.NumBackedUp++
+			}
+		}
+	}
+
+	return &status, nil
+}
@@ -2452,6 +2452,12 @@ type PerRogueUpdateCountCB func(*ClientSession, uint16)
 // called for each of a session's acked updates.
 type PerAckedUpdateCB func(*ClientSession, uint16, BackupID)
 
+// PerAckedRangesCB describes the signature of a callback function that can be
+// called for each of a session's channels with the ranges of commit heights
+// acked for the channel, mapped from the start to the end of each range.
+type PerAckedRangesCB func(*ClientSession, lnwire.ChannelID,
+	map[uint64]uint64)
+
 // PerCommittedUpdateCB describes the signature of a callback function that can
 // be called for each of a session's committed updates (updates that the client
 // has not yet received an ACK for).
@@ -2479,6 +2485,11 @@ type ClientSessionListCfg struct {
 	// for that channel.
 	PerMaxHeight PerMaxHeightCB
 
+	// PerAckedRanges will, if set, be called for each of the session's
+	// channels to communicate the ranges of commit heights acked for that
+	// channel.
+	PerAckedRanges PerAckedRangesCB
+
 	// PerCommittedUpdate will, if set, be called for each of the session's
 	// committed (un-acked) updates.
 	PerCommittedUpdate PerCommittedUpdateCB
@@ -2511,6 +2522,15 @@ func WithPerMaxHeight(cb PerMaxHeightCB) ClientSessionListOption {
 	}
 }
 
+// WithPerAckedRanges constructs a functional option that will set a call-back
+// function to be called for each of a session's channels to communicate the
+// ranges of commit heights that the session has stored for the channel.
+func WithPerAckedRanges(cb PerAckedRangesCB) ClientSessionListOption {
+	return func(cfg *ClientSessionListCfg) {
+		cfg.PerAckedRanges = cb
+	}
+}
+
 // WithPerNumAckedUpdates constructs a functional option that will set a
 // call-back function to be called for each of a session's channels to
 // communicate the number of updates that the session has stored for the
@@ -2600,7 +2620,8 @@ func (c *ClientDB) getClientSession(sessionsBkt, chanIDIndexBkt kvdb.RBucket,
 	// provided.
 	err = c.filterClientSessionAcks(
 		sessionBkt, chanIDIndexBkt, session, cfg.PerMaxHeight,
-		cfg.PerNumAckedUpdates, cfg.PerRogueUpdateCount,
+		cfg.PerNumAckedUpdates, cfg.PerAckedRanges,
+		cfg.PerRogueUpdateCount,
 	)
 	if err != nil {
 		return nil, err
@@ -2659,6 +2680,7 @@ func getClientSessionCommits(sessionBkt kvdb.RBucket, s *ClientSession,
 func (c *ClientDB) filterClientSessionAcks(sessionBkt,
 	chanIDIndexBkt kvdb.RBucket, s *ClientSession, perMaxCb PerMaxHeightCB,
 	perNumAckedUpdates PerNumAckedUpdatesCB,
+	perAckedRanges PerAckedRangesCB,
 	perRogueUpdateCount PerRogueUpdateCountCB) error {
 
 	if perRogueUpdateCount != nil {
@@ -2677,7 +2699,9 @@ func (c *ClientDB) filterClientSessionAcks(sessionBkt,
 		perRogueUpdateCount(s, uint16(count))
 	}
 
-	if perMaxCb == nil && perNumAckedUpdates == nil {
+	if perMaxCb == nil && perNumAckedUpdates == nil &&
+		perAckedRanges == nil {
+
 		return nil
 	}
 
@@ -2708,6 +2732,10 @@ func (c *ClientDB) filterClientSessionAcks(sessionBkt,
 		if perNumAckedUpdates != nil {
 			perNumAckedUpdates(s, chanID, uint16(index.NumInSet()))
 		}
+
+		if perAckedRanges != nil {
+			perAckedRanges(s, chanID, index.GetAllRanges())
+		}
 		return nil
 	})
 }
@@ -947,6 +947,19 @@ func testMaxCommitmentHeights(h *clientDBHarness) {
 	info.MaxHeight.WhenSome(func(u uint64) {
 		require.EqualValues(t, maxUpdates-1, u)
 	})
+
+	// The acked heights are reported as a single range.
+	ackedRanges := make(map[lnwire.ChannelID]map[uint64]uint64)
+	h.listSessions(&tower.ID, wtdb.WithPerAckedRanges(
+		func(_ *wtdb.ClientSession, id lnwire.ChannelID,
+			ranges map[uint64]uint64) {
+
+			ackedRanges[id] = ranges
+		},
+	))
+	require.Equal(t, map[lnwire.ChannelID]map[uint64]uint64{
+		chanID1: {0: maxUpdates - 1},
+	}, ackedRanges)
 }
 
 // testMarkChannelClosed asserts the behaviour of MarkChannelClosed.
@@ -0,0 +1,229 @@
+package zpay32
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// relayInfoSize is the number of bytes that the relay info of a
+	// blinded payment path occupies:
+	//   - fee_base_msat: 4 bytes
+	//   - fee_proportional_millionths: 4 bytes
+	//   - cltv_expiry_delta: 2 bytes
+	//   - htlc_minimum_msat: 8 bytes
+	//   - htlc_maximum_msat: 8 bytes
+	relayInfoSize = 26
+
+	// maxNumHopsPerPath is the maximum number of blinded hops that we
+	// allow in a single encoded blinded path.
+	maxNumHopsPerPath = 7
+)
+
+// BlindedPaymentPath holds all the information a payer needs to know about a
+// blinded path to a receiver of a payment.
+type BlindedPaymentPath struct {
+	// FeeBaseMsat is the total base fee for the path in milli-satoshis.
+	FeeBaseMsat uint32
+
+	// FeeRate is the total fee rate for the path in parts per million.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the total CLTV delta to apply to the path. This
+	// includes the final CLTV delta of the receiver.
+	CltvExpiryDelta uint16
+
+	// HTLCMinMsat is the minimum number of milli-satoshis that any hop in
+	// the path will route.
+	HTLCMinMsat uint64
+
+	// HTLCMaxMsat is the maximum number of milli-satoshis that a hop in
+	// the path will route.
+	HTLCMaxMsat uint64
+
+	// Features is the feature bit vector for the path.
+	Features *lnwire.FeatureVector
+
+	// BlindedPath is the unblinded introduction point and the list of
+	// blinded hops that make up the path.
+	*sphinx.BlindedPath
+}
+
+// Encode serialises the blinded payment path to the given writer.
+func (p *BlindedPaymentPath) Encode(w io.Writer) error {
+	var relayInfo [relayInfoSize]byte
+	binary.BigEndian.PutUint32(relayInfo[0:4], p.FeeBaseMsat)
+	binary.BigEndian.PutUint32(relayInfo[4:8], p.FeeRate)
+	binary.BigEndian.PutUint16(relayInfo[8:10], p.CltvExpiryDelta)
+	binary.BigEndian.PutUint64(relayInfo[10:18], p.HTLCMinMsat)
+	binary.BigEndian.PutUint64(relayInfo[18:26], p.HTLCMaxMsat)
+
+	if _, err := w.Write(relayInfo[:]); err != nil {
+		return err
+	}
+
+	features := lnwire.EmptyFeatureVector()
+	if p.Features != nil {
+		features = p.Features
+	}
+
+	if err := features.Encode(w); err != nil {
+		return err
+	}
+
+	return encodeBlindedPath(w, p.BlindedPath)
+}
+
+// DecodeBlindedPaymentPath deserialises a blinded payment path from the given
+// reader.
+func DecodeBlindedPaymentPath(r io.Reader) (*BlindedPaymentPath, error) {
+	var relayInfo [relayInfoSize]byte
+	if _, err := io.ReadFull(r, relayInfo[:]); err != nil {
+		return nil, err
+	}
+
+	features := lnwire.NewRawFeatureVector()
+	if err := features.Decode(r); err != nil {
+		return nil, err
+	}
+
+	path, err := decodeBlindedPath(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlindedPaymentPath{
+		FeeBaseMsat:     binary.BigEndian.Uint32(relayInfo[0:4]),
+		FeeRate:         binary.BigEndian.Uint32(relayInfo[4:8]),
+		CltvExpiryDelta: binary.BigEndian.Uint16(relayInfo[8:10]),
+		HTLCMinMsat:     binary.BigEndian.Uint64(relayInfo[10:18]),
+		HTLCMaxMsat:     binary.BigEndian.Uint64(relayInfo[18:26]),
+		Features: lnwire.NewFeatureVector(
+			features, lnwire.Features,
+		),
+		BlindedPath: path,
+	}, nil
+}
+
+// encodeBlindedPath serialises the introduction point, blinding point and
+// blinded hops of a sphinx.BlindedPath to the given writer.
+func encodeBlindedPath(w io.Writer, path *sphinx.BlindedPath) error {
+	if path == nil || path.IntroductionPoint == nil ||
+		path.BlindingPoint == nil {
+
+		return fmt.Errorf("incomplete blinded path")
+	}
+
+	numHops := len(path.BlindedHops)
+	if numHops == 0 || numHops > maxNumHopsPerPath {
+		return fmt.Errorf("blinded path must have between 1 and %d "+
+			"hops, got %d", maxNumHopsPerPath, numHops)
+	}
+
+	_, err := w.Write(path.IntroductionPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(path.BlindingPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{uint8(numHops)}); err != nil {
+		return err
+	}
+
+	for _, hop := range path.BlindedHops {
+		_, err := w.Write(hop.BlindedNodePub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		cipherLen := len(hop.CipherText)
+		if cipherLen > 0xffff {
+			return fmt.Errorf("cipher text of %d bytes too large",
+				cipherLen)
+		}
+
+		var lenBytes [2]byte
+		binary.BigEndian.PutUint16(lenBytes[:], uint16(cipherLen))
+		if _, err := w.Write(lenBytes[:]); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(hop.CipherText); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeBlindedPath deserialises a sphinx.BlindedPath from the given reader.
+func decodeBlindedPath(r io.Reader) (*sphinx.BlindedPath, error) {
+	introPoint, err := readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	blindingPoint, err := readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var numHops [1]byte
+	if _, err := io.ReadFull(r, numHops[:]); err != nil {
+		return nil, err
+	}
+
+	if numHops[0] == 0 || numHops[0] > maxNumHopsPerPath {
+		return nil, fmt.Errorf("blinded path must have between 1 and "+
+			"%d hops, got %d", maxNumHopsPerPath, numHops[0])
+	}
+
+	hops := make([]*sphinx.BlindedHopInfo, numHops[0])
+	for i := range hops {
+		nodePub, err := readPubKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var lenBytes [2]byte
+		if _, err := io.ReadFull(r, lenBytes[:]); err != nil {
+			return nil, err
+		}
+
+		cipherLen := binary.BigEndian.Uint16(lenBytes[:])
+		cipherText := make([]byte, cipherLen)
+		if _, err := io.ReadFull(r, cipherText); err != nil {
+			return nil, err
+		}
+
+		hops[i] = &sphinx.BlindedHopInfo{
+			BlindedNodePub: nodePub,
+			CipherText:     cipherText,
+		}
+	}
+
+	return &sphinx.BlindedPath{
+		IntroductionPoint: introPoint,
+		BlindingPoint:     blindingPoint,
+		BlindedHops:       hops,
+	}, nil
+}
+
+// readPubKey reads a 33-byte compressed public key from the given reader.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var keyBytes [33]byte
+	if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(keyBytes[:])
+}
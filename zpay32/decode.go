@@ -290,6 +290,18 @@ func parseTaggedFields(invoice *Invoice, fields []byte, net *chaincfg.Params) er
 			}
 
 			invoice.RouteHints = append(invoice.RouteHints, routeHint)
+		case fieldTypeB:
+			// A `b` field can be included in an invoice multiple
+			// times, so we won't skip it if we have already seen
+			// one.
+			blindedPath, err := parseBlindedPaymentPath(base32Data)
+			if err != nil {
+				return err
+			}
+
+			invoice.BlindedPaymentPaths = append(
+				invoice.BlindedPaymentPaths, blindedPath,
+			)
 		case fieldType9:
 			if invoice.Features != nil {
 				// We skip the field if we have already seen a
@@ -495,6 +507,17 @@ func parseRouteHint(data []byte) ([]HopHint, error) {
 	return routeHint, nil
 }
 
+// parseBlindedPaymentPath converts the data (encoded in base32) into a single
+// blinded payment path.
+func parseBlindedPaymentPath(data []byte) (*BlindedPaymentPath, error) {
+	base256Data, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeBlindedPaymentPath(bytes.NewReader(base256Data))
+}
+
 // parseFeatures decodes any feature bits directly from the base32
 // representation.
 func parseFeatures(data []byte) (*lnwire.FeatureVector, error) {
@@ -76,6 +76,9 @@ const (
 	// probing the recipient.
 	fieldTypeS = 16
 
+	// fieldTypeB contains one or more blinded payment paths.
+	fieldTypeB = 20
+
 	// maxInvoiceLength is the maximum total length an invoice can have.
 	// This is chosen to be the maximum number of bytes that can fit into a
 	// single QR code: https://en.wikipedia.org/wiki/QR_code#Storage
@@ -183,6 +186,13 @@ type Invoice struct {
 	// NOTE: This is optional.
 	RouteHints [][]HopHint
 
+	// BlindedPaymentPaths is a set of blinded payment paths that can be
+	// used to reach the receiver without revealing its identity. This
+	// field is mutually exclusive with RouteHints.
+	//
+	// NOTE: This is optional.
+	BlindedPaymentPaths []*BlindedPaymentPath
+
 	// Features represents an optional field used to signal optional or
 	// required support for features by the receiver.
 	Features *lnwire.FeatureVector
@@ -263,6 +273,15 @@ func RouteHint(routeHint []HopHint) func(*Invoice) {
 	}
 }
 
+// WithBlindedPaymentPath is a functional option that allows callers of
+// NewInvoice to add a blinded payment path that can be used to reach the
+// receiver.
+func WithBlindedPaymentPath(p *BlindedPaymentPath) func(*Invoice) {
+	return func(i *Invoice) {
+		i.BlindedPaymentPaths = append(i.BlindedPaymentPaths, p)
+	}
+}
+
 // Features is a functional option that allows callers of NewInvoice to set the
 // desired feature bits that are advertised on the invoice. If this option is
 // not used, an empty feature vector will automatically be populated.
@@ -380,6 +399,15 @@ func validateInvoice(invoice *Invoice) error {
 			len(invoice.Destination.SerializeCompressed()))
 	}
 
+	// Route hints and blinded paths are two ways of reaching the receiver
+	// that can't be combined.
+	if len(invoice.RouteHints) != 0 &&
+		len(invoice.BlindedPaymentPaths) != 0 {
+
+		return fmt.Errorf("cannot have both route hints and blinded " +
+			"payment paths")
+	}
+
 	// Ensure that all invoices have feature vectors.
 	if invoice.Features == nil {
 		return fmt.Errorf("missing feature vector")
@@ -11,6 +11,7 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/bech32"
 	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/stretchr/testify/require"
 )
@@ -799,3 +800,74 @@ func TestParseTaggedFields(t *testing.T) {
 		})
 	}
 }
+
+// TestBlindedPaymentPaths asserts that blinded payment paths survive an
+// encoding round trip of an invoice and that they can't be combined with
+// route hints.
+func TestBlindedPaymentPaths(t *testing.T) {
+	t.Parallel()
+
+	newPath := func(numHops int) *BlindedPaymentPath {
+		hops := make([]*sphinx.BlindedHopInfo, numHops)
+		for i := range hops {
+			hops[i] = &sphinx.BlindedHopInfo{
+				BlindedNodePub: testHopHintPubkey2,
+				CipherText:     []byte{byte(i), 1, 2, 3},
+			}
+		}
+
+		return &BlindedPaymentPath{
+			FeeBaseMsat:     1000,
+			FeeRate:         500,
+			CltvExpiryDelta: 144,
+			HTLCMinMsat:     1,
+			HTLCMaxMsat:     5_000_000,
+			Features: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.TLVOnionPayloadOptional,
+				), lnwire.Features,
+			),
+			BlindedPath: &sphinx.BlindedPath{
+				IntroductionPoint: testHopHintPubkey1,
+				BlindingPoint:     testPubKey,
+				BlindedHops:       hops,
+			},
+		}
+	}
+
+	paths := []*BlindedPaymentPath{newPath(1), newPath(3)}
+
+	invoice, err := NewInvoice(
+		&chaincfg.MainNetParams, testPaymentHash, time.Unix(1, 0),
+		Amount(testMillisat10mBTC), Description(testCupOfCoffee),
+		PaymentAddr(testPaymentAddr),
+		WithBlindedPaymentPath(paths[0]),
+		WithBlindedPaymentPath(paths[1]),
+	)
+	require.NoError(t, err)
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	require.NoError(t, err)
+
+	decoded, err := Decode(encoded, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.Len(t, decoded.BlindedPaymentPaths, len(paths))
+
+	for i, path := range decoded.BlindedPaymentPaths {
+		require.Equal(t, paths[i].FeeBaseMsat, path.FeeBaseMsat)
+		require.Equal(t, paths[i].FeeRate, path.FeeRate)
+		require.Equal(t, paths[i].CltvExpiryDelta, path.CltvExpiryDelta)
+		require.Equal(t, paths[i].HTLCMinMsat, path.HTLCMinMsat)
+		require.Equal(t, paths[i].HTLCMaxMsat, path.HTLCMaxMsat)
+		require.Equal(t, paths[i].Features, path.Features)
+		require.Equal(t, paths[i].BlindedPath, path.BlindedPath)
+	}
+
+	// An invoice can't contain both route hints and blinded paths.
+	_, err = NewInvoice(
+		&chaincfg.MainNetParams, testPaymentHash, time.Unix(1, 0),
+		Description(testCupOfCoffee), RouteHint(testSingleHop),
+		WithBlindedPaymentPath(paths[0]),
+	)
+	require.Error(t, err)
+}
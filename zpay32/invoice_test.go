@@ -909,6 +909,49 @@ func TestInvoiceChecksumMalleability(t *testing.T) {
 	}
 }
 
+// TestCustomSigNetInvoice tests that invoices of a custom signet network use
+// the signet specific human-readable part and can be decoded again with the
+// same network parameters.
+func TestCustomSigNetInvoice(t *testing.T) {
+	t.Parallel()
+
+	challenge, _ := hex.DecodeString("51")
+	chain := chaincfg.CustomSignetParams(challenge, nil)
+
+	invoice, err := NewInvoice(
+		&chain, testPaymentHash, time.Unix(1496314658, 0),
+		Amount(testMillisat25mBTC), Description(testCupOfCoffee),
+		PaymentAddr(testPaymentAddr),
+	)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "lntbs25m") {
+		t.Fatalf("expected signet hrp, got invoice %v", encoded)
+	}
+
+	decoded, err := Decode(encoded, &chain)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+	if decoded.Net != &chain {
+		t.Fatalf("expected custom signet params, got %v",
+			decoded.Net.Name)
+	}
+
+	// A testnet3 node must not accept the invoice, even though signet and
+	// testnet3 share the same segwit hrp.
+	_, err = Decode(encoded, &chaincfg.TestNet3Params)
+	if err == nil {
+		t.Fatalf("expected testnet3 to reject signet invoice")
+	}
+}
+
 func compareInvoices(expected, actual *Invoice) error {
 	if !reflect.DeepEqual(expected.Net, actual.Net) {
 		return fmt.Errorf("expected net %v, got %v",